@@ -21,6 +21,7 @@ import (
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/bccsp/pkcs11"
 	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/secrets"
 	"github.com/pkg/errors"
 )
 
@@ -44,7 +45,11 @@ func (f *PKCS11Factory) Get(config *FactoryOpts) (bccsp.BCCSP, error) {
 		return nil, errors.New("Invalid config. It must not be nil.")
 	}
 
-	p11Opts := config.Pkcs11Opts
+	p11Opts := *config.Pkcs11Opts
+	// the PIN can reference a file or an environment variable
+	if err := secrets.ResolveInPlace(&p11Opts.Pin); err != nil {
+		return nil, errors.WithMessage(err, "failed resolving the PKCS11 PIN")
+	}
 
 	//TODO: PKCS11 does not need a keystore, but we have not migrated all of PKCS11 BCCSP to PKCS11 yet
 	var ks bccsp.KeyStore
@@ -60,5 +65,5 @@ func (f *PKCS11Factory) Get(config *FactoryOpts) (bccsp.BCCSP, error) {
 		// Default to DummyKeystore
 		ks = sw.NewDummyKeyStore()
 	}
-	return pkcs11.New(*p11Opts, ks)
+	return pkcs11.New(p11Opts, ks)
 }
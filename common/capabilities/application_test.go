@@ -51,6 +51,31 @@ func TestApplicationV13(t *testing.T) {
 	assert.True(t, ap.V1_2Validation())
 	assert.True(t, ap.V1_3Validation())
 	assert.True(t, ap.KeyLevelEndorsement())
+	assert.False(t, ap.ConfigurableRWSetHashing())
+	assert.False(t, ap.TxDeduplication())
+	assert.False(t, ap.ChaincodeMigration())
+	assert.False(t, ap.EphemeralCollections())
+	assert.False(t, ap.ValueCommitments())
+	assert.True(t, ap.ACLs())
+	assert.True(t, ap.CollectionUpgrade())
+	assert.True(t, ap.PrivateChannelData())
+}
+
+func TestApplicationV142(t *testing.T) {
+	ap := NewApplicationProvider(map[string]*cb.Capability{
+		ApplicationV1_4_2: {},
+	})
+	assert.NoError(t, ap.Supported())
+	assert.True(t, ap.ForbidDuplicateTXIdInBlock())
+	assert.True(t, ap.V1_1Validation())
+	assert.True(t, ap.V1_2Validation())
+	assert.True(t, ap.V1_3Validation())
+	assert.True(t, ap.KeyLevelEndorsement())
+	assert.True(t, ap.ConfigurableRWSetHashing())
+	assert.True(t, ap.TxDeduplication())
+	assert.True(t, ap.ChaincodeMigration())
+	assert.True(t, ap.EphemeralCollections())
+	assert.True(t, ap.ValueCommitments())
 	assert.True(t, ap.ACLs())
 	assert.True(t, ap.CollectionUpgrade())
 	assert.True(t, ap.PrivateChannelData())
@@ -68,6 +93,7 @@ func TestHasCapability(t *testing.T) {
 	assert.True(t, ap.HasCapability(ApplicationV1_1))
 	assert.True(t, ap.HasCapability(ApplicationV1_2))
 	assert.True(t, ap.HasCapability(ApplicationV1_3))
+	assert.True(t, ap.HasCapability(ApplicationV1_4_2))
 	assert.True(t, ap.HasCapability(ApplicationPvtDataExperimental))
 	assert.True(t, ap.HasCapability(ApplicationResourcesTreeExperimental))
 	assert.False(t, ap.HasCapability("default"))
@@ -7,10 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package capabilities
 
 import (
+	"fmt"
+
 	"github.com/hyperledger/fabric/common/flogging"
 	cb "github.com/hyperledger/fabric/protos/common"
-
-	"github.com/pkg/errors"
 )
 
 const pkgLogID = "common/capabilities"
@@ -48,7 +48,20 @@ func (r *registry) Supported() error {
 			continue
 		}
 
-		return errors.Errorf("%s capability %s is required but not supported", r.provider.Type(), capabilityName)
+		return &UnsupportedError{Type: r.provider.Type(), Capability: capabilityName}
 	}
 	return nil
 }
+
+// UnsupportedError is returned when a config requires a capability which this binary does not support.
+// A binary which encounters it must stop processing the channel, as its view of the channel would diverge.
+type UnsupportedError struct {
+	// Type is the type of the capability, such as Application
+	Type string
+	// Capability is the name of the unsupported capability
+	Capability string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s capability %s is required but not supported", e.Type, e.Capability)
+}
@@ -34,6 +34,9 @@ func TestNotSatisfied(t *testing.T) {
 		NewOrdererProvider(capsMap).registry,
 		NewApplicationProvider(capsMap).registry,
 	} {
-		assert.Error(t, provider.Supported())
+		err := provider.Supported()
+		assert.Error(t, err)
+		assert.Equal(t, &UnsupportedError{Type: provider.provider.Type(), Capability: "FakeCapability"}, err)
+		assert.Equal(t, provider.provider.Type()+" capability FakeCapability is required but not supported", err.Error())
 	}
 }
@@ -36,4 +36,16 @@ func TestChannelV13(t *testing.T) {
 	})
 	assert.NoError(t, op.Supported())
 	assert.True(t, op.MSPVersion() == msp.MSPv1_3)
+	assert.False(t, op.EndorsementTimeExpiration())
+	assert.False(t, op.PublishedCRLs())
+}
+
+func TestChannelV142(t *testing.T) {
+	op := NewChannelProvider(map[string]*cb.Capability{
+		ChannelV1_4_2: {},
+	})
+	assert.NoError(t, op.Supported())
+	assert.True(t, op.MSPVersion() == msp.MSPv1_4_2)
+	assert.True(t, op.EndorsementTimeExpiration())
+	assert.True(t, op.PublishedCRLs())
 }
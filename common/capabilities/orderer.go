@@ -15,12 +15,20 @@ const (
 
 	// OrdererV1_1 is the capabilties string for standard new non-backwards compatible fabric v1.1 orderer capabilities.
 	OrdererV1_1 = "V1_1"
+
+	// OrdererV1_4 is the capabilties string for standard new non-backwards compatible fabric v1.4 orderer capabilities.
+	OrdererV1_4 = "V1_4"
+
+	// OrdererV1_4_2 is the capabilties string for standard new non-backwards compatible fabric v1.4.2 orderer capabilities.
+	OrdererV1_4_2 = "V1_4_2"
 )
 
 // OrdererProvider provides capabilities information for orderer level config.
 type OrdererProvider struct {
 	*registry
 	v11BugFixes bool
+	v14         bool
+	v142        bool
 }
 
 // NewOrdererProvider creates an orderer capabilities provider.
@@ -28,6 +36,8 @@ func NewOrdererProvider(capabilities map[string]*cb.Capability) *OrdererProvider
 	cp := &OrdererProvider{}
 	cp.registry = newRegistry(cp, capabilities)
 	_, cp.v11BugFixes = capabilities[OrdererV1_1]
+	_, cp.v14 = capabilities[OrdererV1_4]
+	_, cp.v142 = capabilities[OrdererV1_4_2]
 	return cp
 }
 
@@ -40,6 +50,10 @@ func (cp *OrdererProvider) Type() string {
 func (cp *OrdererProvider) HasCapability(capability string) bool {
 	switch capability {
 	// Add new capability names here
+	case OrdererV1_4_2:
+		return true
+	case OrdererV1_4:
+		return true
 	case OrdererV1_1:
 		return true
 	default:
@@ -50,17 +64,41 @@ func (cp *OrdererProvider) HasCapability(capability string) bool {
 // PredictableChannelTemplate specifies whether the v1.0 undesirable behavior of setting the /Channel
 // group's mod_policy to "" and copying versions from the channel config should be fixed or not.
 func (cp *OrdererProvider) PredictableChannelTemplate() bool {
-	return cp.v11BugFixes
+	return cp.v11BugFixes || cp.v14 || cp.v142
 }
 
 // Resubmission specifies whether the v1.0 non-deterministic commitment of tx should be fixed by re-submitting
 // the re-validated tx.
 func (cp *OrdererProvider) Resubmission() bool {
-	return cp.v11BugFixes
+	return cp.v11BugFixes || cp.v14 || cp.v142
 }
 
 // ExpirationCheck specifies whether the orderer checks for identity expiration checks
 // when validating messages
 func (cp *OrdererProvider) ExpirationCheck() bool {
-	return cp.v11BugFixes
+	return cp.v11BugFixes || cp.v14 || cp.v142
+}
+
+// UnsatisfiablePoliciesCheck specifies whether the orderer rejects the config updates
+// introducing policies which can never be satisfied
+func (cp *OrdererProvider) UnsatisfiablePoliciesCheck() bool {
+	return cp.v14 || cp.v142
+}
+
+// DeliverIdentityBinding specifies whether the orderer requires the signed deliver requests
+// to carry the hash of the TLS client certificate of their connection
+func (cp *OrdererProvider) DeliverIdentityBinding() bool {
+	return cp.v142
+}
+
+// TransactionSizeLimit specifies whether the orderer enforces the max transaction bytes
+// of the batch size, rather than only limiting the messages to the absolute max bytes
+func (cp *OrdererProvider) TransactionSizeLimit() bool {
+	return cp.v142
+}
+
+// KafkaIPv6Brokers specifies whether the Kafka brokers of the orderer config may be given
+// as bracketed IPv6 addresses
+func (cp *OrdererProvider) KafkaIPv6Brokers() bool {
+	return cp.v142
 }
@@ -20,6 +20,10 @@ func TestOrdererV10(t *testing.T) {
 	assert.False(t, op.PredictableChannelTemplate())
 	assert.False(t, op.Resubmission())
 	assert.False(t, op.ExpirationCheck())
+	assert.False(t, op.UnsatisfiablePoliciesCheck())
+	assert.False(t, op.DeliverIdentityBinding())
+	assert.False(t, op.TransactionSizeLimit())
+	assert.False(t, op.KafkaIPv6Brokers())
 }
 
 func TestOrdererV11(t *testing.T) {
@@ -30,4 +34,36 @@ func TestOrdererV11(t *testing.T) {
 	assert.True(t, op.PredictableChannelTemplate())
 	assert.True(t, op.Resubmission())
 	assert.True(t, op.ExpirationCheck())
+	assert.False(t, op.UnsatisfiablePoliciesCheck())
+	assert.False(t, op.DeliverIdentityBinding())
+	assert.False(t, op.TransactionSizeLimit())
+	assert.False(t, op.KafkaIPv6Brokers())
+}
+
+func TestOrdererV14(t *testing.T) {
+	op := NewOrdererProvider(map[string]*cb.Capability{
+		OrdererV1_4: {},
+	})
+	assert.NoError(t, op.Supported())
+	assert.True(t, op.PredictableChannelTemplate())
+	assert.True(t, op.Resubmission())
+	assert.True(t, op.ExpirationCheck())
+	assert.True(t, op.UnsatisfiablePoliciesCheck())
+	assert.False(t, op.DeliverIdentityBinding())
+	assert.False(t, op.TransactionSizeLimit())
+	assert.False(t, op.KafkaIPv6Brokers())
+}
+
+func TestOrdererV142(t *testing.T) {
+	op := NewOrdererProvider(map[string]*cb.Capability{
+		OrdererV1_4_2: {},
+	})
+	assert.NoError(t, op.Supported())
+	assert.True(t, op.PredictableChannelTemplate())
+	assert.True(t, op.Resubmission())
+	assert.True(t, op.ExpirationCheck())
+	assert.True(t, op.UnsatisfiablePoliciesCheck())
+	assert.True(t, op.DeliverIdentityBinding())
+	assert.True(t, op.TransactionSizeLimit())
+	assert.True(t, op.KafkaIPv6Brokers())
 }
@@ -8,9 +8,12 @@ package cauthdsl
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/msp"
 	cb "github.com/hyperledger/fabric/protos/common"
 	mb "github.com/hyperledger/fabric/protos/msp"
@@ -44,29 +47,49 @@ func deduplicate(sds []*cb.SignedData, deserializer msp.IdentityDeserializer) []
 // compile recursively builds a go evaluatable function corresponding to the policy specified, remember to call deduplicate on identities before
 // passing them to this function for evaluation
 func compile(policy *cb.SignaturePolicy, identities []*mb.MSPPrincipal, deserializer msp.IdentityDeserializer) (func([]*cb.SignedData, []bool) bool, error) {
+	evaluator, err := compileTraceable(policy, identities, deserializer)
+	if err != nil {
+		return nil, err
+	}
+	return func(signedData []*cb.SignedData, used []bool) bool {
+		return evaluator(signedData, used, nil)
+	}, nil
+}
+
+// traceableEvaluator evaluates signatures against a policy, recording the
+// evaluation of every element of the policy as a child of the trace if not nil
+type traceableEvaluator func(signedData []*cb.SignedData, used []bool, trace *policies.Trace) bool
+
+// compileTraceable is compile, building an evaluation function which may trace the evaluation
+func compileTraceable(policy *cb.SignaturePolicy, identities []*mb.MSPPrincipal, deserializer msp.IdentityDeserializer) (traceableEvaluator, error) {
 	if policy == nil {
 		return nil, fmt.Errorf("Empty policy element")
 	}
 
 	switch t := policy.Type.(type) {
 	case *cb.SignaturePolicy_NOutOf_:
-		policies := make([]func([]*cb.SignedData, []bool) bool, len(t.NOutOf.Rules))
+		compiledRules := make([]traceableEvaluator, len(t.NOutOf.Rules))
 		for i, policy := range t.NOutOf.Rules {
-			compiledPolicy, err := compile(policy, identities, deserializer)
+			compiledPolicy, err := compileTraceable(policy, identities, deserializer)
 			if err != nil {
 				return nil, err
 			}
-			policies[i] = compiledPolicy
+			compiledRules[i] = compiledPolicy
 
 		}
-		return func(signedData []*cb.SignedData, used []bool) bool {
+		return func(signedData []*cb.SignedData, used []bool, trace *policies.Trace) bool {
 			grepKey := time.Now().UnixNano()
 			cauthdslLogger.Debugf("%p gate %d evaluation starts", signedData, grepKey)
+			var gateTrace *policies.Trace
+			if trace != nil {
+				gateTrace = &policies.Trace{Name: fmt.Sprintf("%d out of %d rules", t.NOutOf.N, len(t.NOutOf.Rules))}
+				trace.Children = append(trace.Children, gateTrace)
+			}
 			verified := int32(0)
 			_used := make([]bool, len(used))
-			for _, policy := range policies {
+			for _, policy := range compiledRules {
 				copy(_used, used)
-				if policy(signedData, _used) {
+				if policy(signedData, _used, gateTrace) {
 					verified++
 					copy(used, _used)
 				}
@@ -77,6 +100,10 @@ func compile(policy *cb.SignaturePolicy, identities []*mb.MSPPrincipal, deserial
 			} else {
 				cauthdslLogger.Debugf("%p gate %d evaluation fails", signedData, grepKey)
 			}
+			if gateTrace != nil {
+				gateTrace.Satisfied = verified >= t.NOutOf.N
+				gateTrace.Details = []string{fmt.Sprintf("%d rules satisfied", verified)}
+			}
 
 			return verified >= t.NOutOf.N
 		}, nil
@@ -85,11 +112,22 @@ func compile(policy *cb.SignaturePolicy, identities []*mb.MSPPrincipal, deserial
 			return nil, fmt.Errorf("identity index out of range, requested %v, but identies length is %d", t.SignedBy, len(identities))
 		}
 		signedByID := identities[t.SignedBy]
-		return func(signedData []*cb.SignedData, used []bool) bool {
+		return func(signedData []*cb.SignedData, used []bool, trace *policies.Trace) bool {
 			cauthdslLogger.Debugf("%p signed by %d principal evaluation starts (used %v)", signedData, t.SignedBy, used)
+			var principalTrace *policies.Trace
+			if trace != nil {
+				principalTrace = &policies.Trace{Name: "signed by " + principalString(signedByID)}
+				trace.Children = append(trace.Children, principalTrace)
+			}
+			detail := func(format string, args ...interface{}) {
+				if principalTrace != nil {
+					principalTrace.Details = append(principalTrace.Details, fmt.Sprintf(format, args...))
+				}
+			}
 			for i, sd := range signedData {
 				if used[i] {
 					cauthdslLogger.Debugf("%p skipping identity %d because it has already been used", signedData, i)
+					detail("identity %d already counted for another rule", i)
 					continue
 				}
 				if cauthdslLogger.IsEnabledFor(zapcore.DebugLevel) {
@@ -99,27 +137,59 @@ func compile(policy *cb.SignaturePolicy, identities []*mb.MSPPrincipal, deserial
 				identity, err := deserializer.DeserializeIdentity(sd.Identity)
 				if err != nil {
 					cauthdslLogger.Errorf("Principal deserialization failure (%s) for identity %x", err, sd.Identity)
+					detail("identity %d could not be deserialized: %s", i, err)
 					continue
 				}
 				err = identity.SatisfiesPrincipal(signedByID)
 				if err != nil {
 					cauthdslLogger.Debugf("%p identity %d does not satisfy principal: %s", signedData, i, err)
+					detail("identity %d of MSP %s does not satisfy the principal: %s", i, identity.GetIdentifier().Mspid, err)
 					continue
 				}
 				cauthdslLogger.Debugf("%p principal matched by identity %d", signedData, i)
 				err = identity.Verify(sd.Data, sd.Signature)
 				if err != nil {
 					cauthdslLogger.Debugf("%p signature for identity %d is invalid: %s", signedData, i, err)
+					detail("identity %d of MSP %s has an invalid signature: %s", i, identity.GetIdentifier().Mspid, err)
 					continue
 				}
 				cauthdslLogger.Debugf("%p principal evaluation succeeds for identity %d", signedData, i)
+				detail("identity %d of MSP %s satisfies the principal", i, identity.GetIdentifier().Mspid)
+				if principalTrace != nil {
+					principalTrace.Satisfied = true
+				}
 				used[i] = true
 				return true
 			}
 			cauthdslLogger.Debugf("%p principal evaluation fails", signedData)
+			if len(signedData) == 0 {
+				detail("no signatures")
+			}
 			return false
 		}, nil
 	default:
 		return nil, fmt.Errorf("Unknown type: %T:%v", t, t)
 	}
 }
+
+// principalString describes a principal in the terms of the policy language where possible
+func principalString(principal *mb.MSPPrincipal) string {
+	switch principal.PrincipalClassification {
+	case mb.MSPPrincipal_ROLE:
+		role := &mb.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, role); err == nil {
+			return fmt.Sprintf("'%s.%s'", role.MspIdentifier, strings.ToLower(role.Role.String()))
+		}
+	case mb.MSPPrincipal_ORGANIZATION_UNIT:
+		ou := &mb.OrganizationUnit{}
+		if err := proto.Unmarshal(principal.Principal, ou); err == nil {
+			return fmt.Sprintf("organizational unit %s of MSP %s", ou.OrganizationalUnitIdentifier, ou.MspIdentifier)
+		}
+	case mb.MSPPrincipal_IDENTITY:
+		identity := &mb.SerializedIdentity{}
+		if err := proto.Unmarshal(principal.Principal, identity); err == nil && identity.Mspid != "" {
+			return fmt.Sprintf("an identity of MSP %s", identity.Mspid)
+		}
+	}
+	return fmt.Sprintf("%s principal", principal.PrincipalClassification)
+}
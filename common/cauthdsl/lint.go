@@ -0,0 +1,58 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cauthdsl
+
+import (
+	"fmt"
+	"strings"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric/protos/msp"
+)
+
+// Lint returns an error describing why the given policy can never be satisfied,
+// or nil if it can be.  checkPrincipal returns an error if no identity can
+// satisfy the given principal, such as a principal of an undefined MSP.
+func Lint(policy *cb.SignaturePolicyEnvelope, checkPrincipal func(principal *mb.MSPPrincipal) error) error {
+	if policy == nil || policy.Rule == nil {
+		return fmt.Errorf("policy has no rule")
+	}
+	return lintRule(policy.Rule, policy.Identities, checkPrincipal)
+}
+
+func lintRule(rule *cb.SignaturePolicy, identities []*mb.MSPPrincipal, checkPrincipal func(principal *mb.MSPPrincipal) error) error {
+	switch t := rule.Type.(type) {
+	case *cb.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || t.SignedBy >= int32(len(identities)) {
+			return fmt.Errorf("identity index %d out of range, the policy has %d identities", t.SignedBy, len(identities))
+		}
+		if err := checkPrincipal(identities[t.SignedBy]); err != nil {
+			return fmt.Errorf("identity %d cannot be satisfied: %s", t.SignedBy, err)
+		}
+		return nil
+	case *cb.SignaturePolicy_NOutOf_:
+		satisfiable := int32(0)
+		var reasons []string
+		for i, r := range t.NOutOf.Rules {
+			if err := lintRule(r, identities, checkPrincipal); err != nil {
+				reasons = append(reasons, fmt.Sprintf("rule %d: %s", i, err))
+				continue
+			}
+			satisfiable++
+		}
+		if satisfiable >= t.NOutOf.N {
+			return nil
+		}
+		var details string
+		if len(reasons) > 0 {
+			details = " (" + strings.Join(reasons, "; ") + ")"
+		}
+		return fmt.Errorf("%d out of %d rules are required, but %d can be satisfied%s", t.NOutOf.N, len(t.NOutOf.Rules), satisfiable, details)
+	default:
+		return fmt.Errorf("unknown rule type: %T", t)
+	}
+}
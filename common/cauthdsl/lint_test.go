@@ -0,0 +1,73 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cauthdsl
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	// principals of MSP C can never be satisfied
+	checkPrincipal := func(principal *msp.MSPPrincipal) error {
+		role := &msp.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, role); err != nil {
+			return err
+		}
+		if role.MspIdentifier == "C" {
+			return fmt.Errorf("MSP C is not defined")
+		}
+		return nil
+	}
+
+	for _, test := range []struct {
+		policy      string
+		expectedErr string
+	}{
+		{policy: "OR('A.member', 'C.member')"},
+		{policy: "OutOf(2, 'A.member', 'B.member', 'C.member')"},
+		{
+			policy:      "AND('A.member', 'C.member')",
+			expectedErr: "2 out of 2 rules are required, but 1 can be satisfied (rule 1: identity 1 cannot be satisfied: MSP C is not defined)",
+		},
+		{
+			policy: "OR(AND('A.member', 'C.member'), 'C.admin')",
+			expectedErr: "1 out of 2 rules are required, but 0 can be satisfied " +
+				"(rule 0: 2 out of 2 rules are required, but 1 can be satisfied (rule 1: identity 1 cannot be satisfied: MSP C is not defined); " +
+				"rule 1: identity 2 cannot be satisfied: MSP C is not defined)",
+		},
+	} {
+		t.Run(test.policy, func(t *testing.T) {
+			policy, err := FromString(test.policy)
+			assert.NoError(t, err)
+			err = Lint(policy, checkPrincipal)
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.expectedErr)
+			}
+		})
+	}
+
+	t.Run("EmptyGate", func(t *testing.T) {
+		assert.NoError(t, Lint(AcceptAllPolicy, checkPrincipal))
+		assert.EqualError(t, Lint(RejectAllPolicy, checkPrincipal), "1 out of 0 rules are required, but 0 can be satisfied")
+	})
+
+	t.Run("MalformedPolicy", func(t *testing.T) {
+		assert.EqualError(t, Lint(&cb.SignaturePolicyEnvelope{}, checkPrincipal), "policy has no rule")
+		policy := &cb.SignaturePolicyEnvelope{Rule: NOutOf(1, []*cb.SignaturePolicy{SignedBy(1)})}
+		assert.EqualError(t, Lint(policy, checkPrincipal), "1 out of 1 rules are required, but 0 can be satisfied (rule 0: identity index 1 out of range, the policy has 0 identities)")
+		policy = &cb.SignaturePolicyEnvelope{Rule: &cb.SignaturePolicy{}}
+		assert.EqualError(t, Lint(policy, checkPrincipal), "unknown rule type: <nil>")
+	})
+}
@@ -39,7 +39,7 @@ func (pr *provider) NewPolicy(data []byte) (policies.Policy, proto.Message, erro
 		return nil, nil, fmt.Errorf("This evaluator only understands messages of version 0, but version was %d", sigPolicy.Version)
 	}
 
-	compiled, err := compile(sigPolicy.Rule, sigPolicy.Identities, pr.deserializer)
+	compiled, err := compileTraceable(sigPolicy.Rule, sigPolicy.Identities, pr.deserializer)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -52,7 +52,7 @@ func (pr *provider) NewPolicy(data []byte) (policies.Policy, proto.Message, erro
 }
 
 type policy struct {
-	evaluator    func([]*cb.SignedData, []bool) bool
+	evaluator    traceableEvaluator
 	deserializer msp.IdentityDeserializer
 }
 
@@ -62,9 +62,22 @@ func (p *policy) Evaluate(signatureSet []*cb.SignedData) error {
 		return fmt.Errorf("No such policy")
 	}
 
-	ok := p.evaluator(deduplicate(signatureSet, p.deserializer), make([]bool, len(signatureSet)))
+	ok := p.evaluator(deduplicate(signatureSet, p.deserializer), make([]bool, len(signatureSet)), nil)
 	if !ok {
 		return errors.New("signature set did not satisfy policy")
 	}
 	return nil
 }
+
+// Trace evaluates the signature set as Evaluate does, recording the
+// evaluation of every rule of the policy in the returned trace
+func (p *policy) Trace(signatureSet []*cb.SignedData) *policies.Trace {
+	trace := &policies.Trace{Name: "signature policy"}
+	if p == nil {
+		trace.Details = []string{"No such policy"}
+		return trace
+	}
+
+	trace.Satisfied = p.evaluator(deduplicate(signatureSet, p.deserializer), make([]bool, len(signatureSet)), trace)
+	return trace
+}
@@ -118,3 +118,32 @@ func TestNewPolicyErrorCase(t *testing.T) {
 	err4 := pol4.Evaluate([]*cb.SignedData{})
 	assert.EqualError(t, err4, "No such policy")
 }
+
+func TestTrace(t *testing.T) {
+	provider := NewPolicyProvider(&mockDeserializer{})
+	pol, _, err := provider.NewPolicy(marshalOrPanic(Envelope(And(SignedBy(0), SignedBy(1)), signers)))
+	assert.NoError(t, err)
+
+	signedData, _ := toSignedData([][]byte{nil, nil}, signers, [][]byte{validSignature, invalidSignature})
+	trace := policies.Explain(pol, signedData)
+	assert.False(t, trace.Satisfied)
+	assert.Equal(t, "signature policy", trace.Name)
+	assert.Len(t, trace.Children, 1)
+
+	gate := trace.Children[0]
+	assert.Equal(t, "2 out of 2 rules", gate.Name)
+	assert.Equal(t, []string{"1 rules satisfied"}, gate.Details)
+	assert.Len(t, gate.Children, 2)
+	assert.True(t, gate.Children[0].Satisfied)
+	assert.Contains(t, gate.Children[0].Details, "identity 0 of MSP Mock satisfies the principal")
+	assert.False(t, gate.Children[1].Satisfied)
+	assert.Contains(t, gate.Children[1].Details, "identity 0 already counted for another rule")
+	assert.Contains(t, gate.Children[1].Details, "identity 1 of MSP Mock has an invalid signature: Invalid signature")
+
+	assert.Error(t, pol.Evaluate(signedData))
+
+	var nilPolicy *policy
+	trace = nilPolicy.Trace(signedData)
+	assert.False(t, trace.Satisfied)
+	assert.Equal(t, []string{"No such policy"}, trace.Details)
+}
@@ -36,6 +36,16 @@ const (
 	GateOutOf = "OutOf"
 )
 
+// Weight is the function assigning a weight to a rule of an OutOf gate
+const Weight = "Weight"
+
+const (
+	// MaxNestingDepth is the maximum number of nested gates of a policy
+	MaxNestingDepth = 32
+	// MaxWeightedRules is the maximum number of rules of an OutOf gate with weighted rules
+	MaxWeightedRules = 10
+)
+
 // Role values for principals
 const (
 	RoleAdmin  = "admin"
@@ -53,6 +63,40 @@ var (
 	regexErr = regexp.MustCompile("^No parameter '([^']+)' found[.]$")
 )
 
+// weightedRule is a rule of an OutOf gate which counts as many times as its weight
+// towards the threshold of the gate
+type weightedRule struct {
+	weight int
+	rule   string
+}
+
+// ruleString returns the representation of a rule passed to a gate, quoting principals
+func ruleString(arg interface{}) (string, error) {
+	t, ok := arg.(string)
+	if !ok {
+		return "", fmt.Errorf("Unexpected type %s", reflect.TypeOf(arg))
+	}
+	if regex.MatchString(t) {
+		return "'" + t + "'", nil
+	}
+	return t, nil
+}
+
+func weight(args ...interface{}) (interface{}, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("Expected two arguments to %s. Given %d", Weight, len(args))
+	}
+	w, ok := args[0].(float64)
+	if !ok || w != float64(int(w)) || w < 1 {
+		return nil, fmt.Errorf("Expected a positive integer weight, got %v", args[0])
+	}
+	rule, err := ruleString(args[1])
+	if err != nil {
+		return nil, err
+	}
+	return weightedRule{weight: int(w), rule: rule}, nil
+}
+
 // a stub function - it returns the same string as it's passed.
 // This will be evaluated by second/third passes to convert to a proto policy
 func outof(args ...interface{}) (interface{}, error) {
@@ -74,27 +118,103 @@ func outof(args ...interface{}) (interface{}, error) {
 	}
 
 	for _, arg := range args[1:] {
-		toret += ", "
-		switch t := arg.(type) {
-		case string:
-			if regex.MatchString(t) {
-				toret += "'" + t + "'"
-			} else {
-				toret += t
-			}
-		default:
-			return nil, fmt.Errorf("Unexpected type %s", reflect.TypeOf(arg))
+		if _, ok := arg.(weightedRule); ok {
+			return weightedOutOf(arg0, args[1:])
+		}
+	}
+
+	for _, arg := range args[1:] {
+		rule, err := ruleString(arg)
+		if err != nil {
+			return nil, err
 		}
+		toret += ", " + rule
 	}
 	return toret + ")", nil
 }
 
+// weightedOutOf expands an OutOf gate with weighted rules to an Or of the
+// minimal combinations of rules whose total weight reaches the threshold,
+// so that the policy can be expressed with unweighted gates
+func weightedOutOf(threshold interface{}, args []interface{}) (interface{}, error) {
+	n, ok := threshold.(float64)
+	if !ok || n != float64(int(n)) || n < 1 {
+		return nil, fmt.Errorf("Expected a positive integer threshold for weighted rules, got %v", threshold)
+	}
+	if len(args) > MaxWeightedRules {
+		return nil, fmt.Errorf("At most %d weighted rules are supported, got %d", MaxWeightedRules, len(args))
+	}
+
+	rules := make([]weightedRule, len(args))
+	total := 0
+	for i, arg := range args {
+		if wr, ok := arg.(weightedRule); ok {
+			rules[i] = wr
+		} else {
+			rule, err := ruleString(arg)
+			if err != nil {
+				return nil, err
+			}
+			rules[i] = weightedRule{weight: 1, rule: rule}
+		}
+		total += rules[i].weight
+	}
+	t := int(n)
+	if t > total {
+		return nil, fmt.Errorf("Invalid weighted predicate, threshold %d exceeds total weight %d", t, total)
+	}
+
+	var combinations []string
+	for set := 1; set < 1<<uint(len(rules)); set++ {
+		sum, lightest := 0, total
+		var members []string
+		for i, wr := range rules {
+			if set&(1<<uint(i)) == 0 {
+				continue
+			}
+			sum += wr.weight
+			if wr.weight < lightest {
+				lightest = wr.weight
+			}
+			members = append(members, wr.rule)
+		}
+		// a combination is minimal if it falls below the threshold without any of its rules
+		if sum < t || sum-lightest >= t {
+			continue
+		}
+		if len(members) == 1 {
+			combinations = append(combinations, members[0])
+			continue
+		}
+		combinations = append(combinations, fmt.Sprintf("outof(%d, %s)", len(members), strings.Join(members, ", ")))
+	}
+	if len(combinations) == 1 && strings.HasPrefix(combinations[0], "outof(") {
+		return combinations[0], nil
+	}
+	return "outof(1, " + strings.Join(combinations, ", ") + ")", nil
+}
+
+func checkUnweighted(gate string, args []interface{}) error {
+	for _, arg := range args {
+		if _, ok := arg.(weightedRule); ok {
+			return fmt.Errorf("Weighted rules are only supported by %s, not by %s", GateOutOf, gate)
+		}
+	}
+	return nil
+}
+
 func and(args ...interface{}) (interface{}, error) {
+	if err := checkUnweighted(GateAnd, args); err != nil {
+		return nil, err
+	}
 	args = append([]interface{}{len(args)}, args...)
 	return outof(args...)
 }
 
 func or(args ...interface{}) (interface{}, error) {
+	if err := checkUnweighted(GateOr, args); err != nil {
+		return nil, err
+	}
 	args = append([]interface{}{1}, args...)
 	return outof(args...)
 }
@@ -228,10 +348,18 @@ func newContext() *context {
 // implements that policy. The supported language is as follows:
 //
 // GATE(P[, P])
+// OutOf(N, P[, P])
 //
 // where:
 //	- GATE is either "and" or "or"
-//	- P is either a principal or another nested call to GATE
+//	- N is the number of P which must be satisfied
+//	- P is either a principal, another nested call to GATE or OutOf,
+//	  or, within OutOf only, Weight(W, P), which makes P count W times
+//	  towards N
+//
+// Policies may nest at most MaxNestingDepth gates, OutOf gates with
+// weighted rules are expanded to an Or of the minimal combinations of
+// rules reaching N, and may have at most MaxWeightedRules rules.
 //
 // A principal is defined as:
 //
@@ -254,6 +382,9 @@ func FromString(policy string) (*common.SignaturePolicyEnvelope, error) {
 			GateOutOf:                  outof,
 			strings.ToLower(GateOutOf): outof,
 			strings.ToUpper(GateOutOf): outof,
+			Weight:                     weight,
+			strings.ToLower(Weight):    weight,
+			strings.ToUpper(Weight):    weight,
 		},
 	)
 	if err != nil {
@@ -330,6 +461,9 @@ func FromString(policy string) (*common.SignaturePolicyEnvelope, error) {
 	if !ok {
 		return nil, fmt.Errorf("invalid policy string '%s'", policy)
 	}
+	if depth := nestingDepth(rule); depth > MaxNestingDepth {
+		return nil, fmt.Errorf("policy nests %d gates, at most %d are supported", depth, MaxNestingDepth)
+	}
 
 	p := &common.SignaturePolicyEnvelope{
 		Identities: ctx.principals,
@@ -339,3 +473,18 @@ func FromString(policy string) (*common.SignaturePolicyEnvelope, error) {
 
 	return p, nil
 }
+
+// nestingDepth returns the number of nested gates of the given rule
+func nestingDepth(rule *common.SignaturePolicy) int {
+	gate := rule.GetNOutOf()
+	if gate == nil {
+		return 0
+	}
+	depth := 0
+	for _, r := range gate.Rules {
+		if d := nestingDepth(r); d > depth {
+			depth = d
+		}
+	}
+	return depth + 1
+}
@@ -7,7 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package cauthdsl
 
 import (
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/hyperledger/fabric/protos/common"
@@ -305,3 +307,71 @@ func TestBadStringBeforeFAB11404_ThisCanDeleteAfterFAB11404HasMerged(t *testing.
 	assert.Nil(t, p3)
 	assert.EqualError(t, err3, `invalid policy string ''\'1\'''`)
 }
+
+func TestWeightedOutOf(t *testing.T) {
+	p1, err := FromString("OutOf(3, Weight(2, 'A.member'), 'B.member', 'C.member')")
+	assert.NoError(t, err)
+	p2, err := FromString("Or(And('A.member', 'B.member'), And('A.member', 'C.member'))")
+	assert.NoError(t, err)
+	assert.Equal(t, p2, p1)
+
+	p1, err = FromString("OutOf(2, weight(2, 'A.member'), 'B.member')")
+	assert.NoError(t, err)
+	p2, err = FromString("OutOf(1, 'A.member')")
+	assert.NoError(t, err)
+	assert.Equal(t, p2, p1)
+
+	p1, err = FromString("OutOf(2, WEIGHT(2, And('A.member', 'B.member')), 'C.member', 'D.member')")
+	assert.NoError(t, err)
+	p2, err = FromString("Or(And('A.member', 'B.member'), And('C.member', 'D.member'))")
+	assert.NoError(t, err)
+	assert.Equal(t, p2, p1)
+}
+
+func TestWeightedOutOfErrorCase(t *testing.T) {
+	_, err := FromString("And(Weight(2, 'A.member'), 'B.member')")
+	assert.EqualError(t, err, "Weighted rules are only supported by OutOf, not by And")
+
+	_, err = FromString("Or(Weight(2, 'A.member'), 'B.member')")
+	assert.EqualError(t, err, "Weighted rules are only supported by OutOf, not by Or")
+
+	_, err = FromString("OutOf(4, Weight(2, 'A.member'), 'B.member')")
+	assert.EqualError(t, err, "Invalid weighted predicate, threshold 4 exceeds total weight 3")
+
+	_, err = FromString("OutOf(0, Weight(2, 'A.member'), 'B.member')")
+	assert.EqualError(t, err, "Expected a positive integer threshold for weighted rules, got 0")
+
+	_, err = FromString("OutOf(1, Weight(0, 'A.member'), 'B.member')")
+	assert.EqualError(t, err, "Expected a positive integer weight, got 0")
+
+	_, err = FromString("OutOf(1, Weight(1.5, 'A.member'), 'B.member')")
+	assert.EqualError(t, err, "Expected a positive integer weight, got 1.5")
+
+	_, err = FromString("OutOf(1, Weight(2, 'A.member', 'B.member'))")
+	assert.EqualError(t, err, "Expected two arguments to Weight. Given 3")
+
+	rules := make([]string, MaxWeightedRules+1)
+	for i := range rules {
+		rules[i] = fmt.Sprintf("'Org%d.member'", i)
+	}
+	rules[0] = "Weight(2, " + rules[0] + ")"
+	_, err = FromString("OutOf(2, " + strings.Join(rules, ", ") + ")")
+	assert.EqualError(t, err, fmt.Sprintf("At most %d weighted rules are supported, got %d", MaxWeightedRules, MaxWeightedRules+1))
+}
+
+func TestNestingDepth(t *testing.T) {
+	nested := func(depth int) string {
+		policy := "'A.member'"
+		for i := 0; i < depth; i++ {
+			policy = fmt.Sprintf("Or(%s, 'B%d.member')", policy, i)
+		}
+		return policy
+	}
+
+	p, err := FromString(nested(MaxNestingDepth))
+	assert.NoError(t, err)
+	assert.Len(t, p.Identities, MaxNestingDepth+1)
+
+	_, err = FromString(nested(MaxNestingDepth + 1))
+	assert.EqualError(t, err, fmt.Sprintf("policy nests %d gates, at most %d are supported", MaxNestingDepth+1, MaxNestingDepth))
+}
@@ -0,0 +1,221 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package certmonitor
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/msp"
+	cb "github.com/hyperledger/fabric/protos/common"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+)
+
+// Certificate is a certificate watched by the monitor
+type Certificate struct {
+	// Channel is the channel whose config holds the certificate, empty for
+	// the certificates of the node itself
+	Channel      string
+	MSPID        string
+	Role         string
+	Subject      string
+	SerialNumber string
+	NotAfter     time.Time
+}
+
+// Source returns the certificates to watch
+type Source func() ([]Certificate, error)
+
+// LocalMSP returns the source of the certificates of the local MSP in the
+// directory, its signing certificate included. The directory is read again on
+// every scan, an idemix MSP holding no certificate.
+func LocalMSP(dir, mspID, mspType string) Source {
+	return func() ([]Certificate, error) {
+		mspConfig, err := msp.GetVerifyingMspConfig(dir, mspID, mspType)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("failed loading local MSP from %s", dir))
+		}
+		certs, err := MSPCertificates(mspConfig)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed reading local MSP")
+		}
+		if msp.ProviderType(mspConfig.Type) != msp.FABRIC {
+			return certs, nil
+		}
+		signcertsDir := filepath.Join(dir, "signcerts")
+		files, err := ioutil.ReadDir(signcertsDir)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed reading signing certificates directory %s", signcertsDir)
+		}
+		for _, f := range files {
+			if f.IsDir() {
+				continue
+			}
+			signcerts, err := PEMFiles(mspID, "signer", filepath.Join(signcertsDir, f.Name()))()
+			if err != nil {
+				return nil, err
+			}
+			certs = append(certs, signcerts...)
+		}
+		return certs, nil
+	}
+}
+
+// PEMFiles returns the source of the certificates PEM encoded in the files,
+// such as the TLS certificates of the node, which are read again on every scan
+func PEMFiles(mspID, role string, files ...string) Source {
+	return func() ([]Certificate, error) {
+		var certs []Certificate
+		for _, file := range files {
+			pemBytes, err := ioutil.ReadFile(file)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed reading %s certificate", role)
+			}
+			for block, rest := pem.Decode(pemBytes); block != nil; block, rest = pem.Decode(rest) {
+				if block.Type != "CERTIFICATE" {
+					continue
+				}
+				cert, err := x509.ParseCertificate(block.Bytes)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed parsing %s certificate %s", role, file)
+				}
+				certs = append(certs, newCertificate("", mspID, role, cert))
+			}
+		}
+		return certs, nil
+	}
+}
+
+// Channels returns the source of the certificates of the MSPs of the config
+// of the channels, those of the channels without config being skipped
+func Channels(channelIDs func() []string, config func(channelID string) *cb.Config) Source {
+	return func() ([]Certificate, error) {
+		var certs []Certificate
+		for _, channelID := range channelIDs() {
+			channelConfig := config(channelID)
+			if channelConfig == nil {
+				continue
+			}
+			channelCerts, err := ChannelCertificates(channelID, channelConfig)
+			if err != nil {
+				return nil, errors.WithMessage(err, fmt.Sprintf("failed reading the config of channel %s", channelID))
+			}
+			certs = append(certs, channelCerts...)
+		}
+		return certs, nil
+	}
+}
+
+// ChannelCertificates returns the certificates of the MSPs of the orderer and
+// application organizations of the channel config. The MSP of an organization
+// of both the orderer and the application is listed once.
+func ChannelCertificates(channelID string, config *cb.Config) ([]Certificate, error) {
+	if config.ChannelGroup == nil {
+		return nil, errors.New("config has no channel group")
+	}
+	var certs []Certificate
+	listed := map[string]bool{}
+	for _, groupKey := range []string{channelconfig.OrdererGroupKey, channelconfig.ApplicationGroupKey} {
+		group := config.ChannelGroup.Groups[groupKey]
+		if group == nil {
+			continue
+		}
+		orgNames := make([]string, 0, len(group.Groups))
+		for orgName := range group.Groups {
+			orgNames = append(orgNames, orgName)
+		}
+		sort.Strings(orgNames)
+		for _, orgName := range orgNames {
+			value, ok := group.Groups[orgName].Values[channelconfig.MSPKey]
+			if !ok {
+				continue
+			}
+			mspConfig := &mspprotos.MSPConfig{}
+			if err := proto.Unmarshal(value.Value, mspConfig); err != nil {
+				return nil, errors.Wrapf(err, "failed reading the MSP of organization %s", orgName)
+			}
+			orgCerts, err := MSPCertificates(mspConfig)
+			if err != nil {
+				return nil, errors.WithMessage(err, fmt.Sprintf("failed reading the MSP of organization %s", orgName))
+			}
+			for _, c := range orgCerts {
+				key := c.MSPID + "/" + c.Role + "/" + c.SerialNumber + "/" + c.Subject
+				if listed[key] {
+					continue
+				}
+				listed[key] = true
+				c.Channel = channelID
+				certs = append(certs, c)
+			}
+		}
+	}
+	return certs, nil
+}
+
+// MSPCertificates returns the certificates of a fabric MSP config, the signing
+// certificate included if the config has one. An idemix MSP holds none.
+func MSPCertificates(mspConfig *mspprotos.MSPConfig) ([]Certificate, error) {
+	if msp.ProviderType(mspConfig.Type) != msp.FABRIC {
+		return nil, nil
+	}
+	fabricConfig := &mspprotos.FabricMSPConfig{}
+	if err := proto.Unmarshal(mspConfig.Config, fabricConfig); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshaling fabric MSP config")
+	}
+
+	roles := []struct {
+		name  string
+		certs [][]byte
+	}{
+		{"root_cert", fabricConfig.RootCerts},
+		{"intermediate_cert", fabricConfig.IntermediateCerts},
+		{"admin", fabricConfig.Admins},
+		{"tls_root_cert", fabricConfig.TlsRootCerts},
+		{"tls_intermediate_cert", fabricConfig.TlsIntermediateCerts},
+	}
+	if fabricConfig.SigningIdentity != nil && len(fabricConfig.SigningIdentity.PublicSigner) != 0 {
+		roles = append(roles, struct {
+			name  string
+			certs [][]byte
+		}{"signer", [][]byte{fabricConfig.SigningIdentity.PublicSigner}})
+	}
+
+	var certs []Certificate
+	for _, role := range roles {
+		for _, pemBytes := range role.certs {
+			block, _ := pem.Decode(pemBytes)
+			if block == nil {
+				return nil, errors.Errorf("%s is not PEM encoded", role.name)
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed parsing %s", role.name)
+			}
+			certs = append(certs, newCertificate("", fabricConfig.Name, role.name, cert))
+		}
+	}
+	return certs, nil
+}
+
+func newCertificate(channelID, mspID, role string, cert *x509.Certificate) Certificate {
+	return Certificate{
+		Channel:      channelID,
+		MSPID:        mspID,
+		Role:         role,
+		Subject:      cert.Subject.String(),
+		SerialNumber: cert.SerialNumber.Text(16),
+		NotAfter:     cert.NotAfter.UTC(),
+	}
+}
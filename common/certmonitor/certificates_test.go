@@ -0,0 +1,129 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package certmonitor
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/tools/configtxgen/configtxgentest"
+	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
+	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	"github.com/hyperledger/fabric/core/config/configtest"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCertificate writes a self signed certificate with the serial number and
+// expiration to the file, PEM encoded
+func writeCertificate(t *testing.T, file string, serial int64, notAfter time.Time) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "node"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	defer f.Close()
+	require.NoError(t, pem.Encode(f, &pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestLocalMSP(t *testing.T) {
+	dir, err := configtest.GetDevMspDir()
+	require.NoError(t, err)
+
+	certs, err := LocalMSP(dir, "SampleOrg", "bccsp")()
+	require.NoError(t, err)
+	roles := map[string]int{}
+	for _, c := range certs {
+		assert.Equal(t, "SampleOrg", c.MSPID)
+		assert.Empty(t, c.Channel)
+		assert.NotEmpty(t, c.SerialNumber)
+		roles[c.Role]++
+	}
+	assert.Equal(t, map[string]int{"root_cert": 1, "admin": 1, "tls_root_cert": 1, "tls_intermediate_cert": 1, "signer": 1}, roles)
+
+	_, err = LocalMSP(filepath.Join(dir, "missing"), "SampleOrg", "bccsp")()
+	assert.Error(t, err)
+}
+
+func TestPEMFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certmonitor")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	notAfter := time.Now().Add(time.Hour).Truncate(time.Second).UTC()
+	file := filepath.Join(dir, "chain.pem")
+	writeCertificate(t, file, 10, notAfter)
+	writeCertificate(t, file, 11, notAfter.Add(time.Hour))
+
+	certs, err := PEMFiles("SampleOrg", "tls_server", file)()
+	require.NoError(t, err)
+	assert.Equal(t, []Certificate{
+		{MSPID: "SampleOrg", Role: "tls_server", Subject: "CN=node", SerialNumber: "a", NotAfter: notAfter},
+		{MSPID: "SampleOrg", Role: "tls_server", Subject: "CN=node", SerialNumber: "b", NotAfter: notAfter.Add(time.Hour)},
+	}, certs)
+
+	_, err = PEMFiles("SampleOrg", "tls_server", filepath.Join(dir, "missing.pem"))()
+	assert.Error(t, err)
+
+	require.NoError(t, ioutil.WriteFile(file, []byte("-----BEGIN CERTIFICATE-----\nAAAA\n-----END CERTIFICATE-----\n"), 0600))
+	_, err = PEMFiles("SampleOrg", "tls_server", file)()
+	assert.Error(t, err)
+}
+
+func TestChannelCertificates(t *testing.T) {
+	conf := configtxgentest.Load(genesisconfig.SampleSingleMSPSoloProfile)
+	conf.Consortiums = nil
+	conf.Application = configtxgentest.Load(genesisconfig.SampleSingleMSPChannelProfile).Application
+	cg, err := encoder.NewChannelGroup(conf)
+	require.NoError(t, err)
+	config := &cb.Config{ChannelGroup: cg}
+
+	// the SampleOrg MSP of both the orderer and the application is listed once
+	certs, err := ChannelCertificates("mychannel", config)
+	require.NoError(t, err)
+	var roles []string
+	for _, c := range certs {
+		assert.Equal(t, "mychannel", c.Channel)
+		assert.Equal(t, "SampleOrg", c.MSPID)
+		roles = append(roles, c.Role)
+	}
+	assert.ElementsMatch(t, []string{"root_cert", "admin", "tls_root_cert", "tls_intermediate_cert"}, roles)
+
+	source := Channels(
+		func() []string { return []string{"mychannel", "removed"} },
+		func(channelID string) *cb.Config {
+			if channelID == "mychannel" {
+				return config
+			}
+			return nil
+		},
+	)
+	sourced, err := source()
+	require.NoError(t, err)
+	assert.Equal(t, certs, sourced)
+
+	_, err = ChannelCertificates("mychannel", &cb.Config{})
+	assert.EqualError(t, err, "config has no channel group")
+}
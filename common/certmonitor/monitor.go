@@ -0,0 +1,204 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package certmonitor watches the expiration of the certificates a node
+// depends on: those of its local MSP, its TLS certificates and those of the
+// MSPs of the channels it belongs to. The certificates are scanned
+// periodically, those about to expire are logged as warnings and the time
+// left before the expiration of each certificate is exported as a metric.
+package certmonitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("certmonitor")
+
+// Options contains the configuration of a monitor
+type Options struct {
+	// Interval is the interval between two scans of the certificates
+	Interval time.Duration
+	// WarnBefore is how long before their expiration the certificates are
+	// reported
+	WarnBefore time.Duration
+}
+
+// Monitor scans the certificates of its sources, and reports those which
+// expire within the warning period
+type Monitor struct {
+	options Options
+	sources []Source
+	scope   metrics.Scope
+	now     func() time.Time
+
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewMonitor returns a monitor of the certificates of the sources, which
+// exports its metrics to the scope
+func NewMonitor(options Options, scope metrics.Scope, sources ...Source) *Monitor {
+	return &Monitor{
+		options: options,
+		sources: sources,
+		scope:   scope,
+		now:     time.Now,
+		done:    make(chan struct{}),
+	}
+}
+
+// Start scans the certificates right away, then every interval until the
+// monitor is stopped
+func (m *Monitor) Start() {
+	go func() {
+		ticker := time.NewTicker(m.options.Interval)
+		defer ticker.Stop()
+		for {
+			m.Scan()
+			select {
+			case <-ticker.C:
+			case <-m.done:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic scans
+func (m *Monitor) Stop() {
+	m.stopOnce.Do(func() { close(m.done) })
+}
+
+// Scan scans the certificates of the sources once. It logs a warning for,
+// and counts, the certificates which expire within the warning period, and
+// updates the time left before the expiration of every certificate. The
+// sources which fail are logged and skipped.
+func (m *Monitor) Scan() {
+	now := m.now()
+	var expiring, expired int
+	for _, source := range m.sources {
+		certs, err := source()
+		if err != nil {
+			logger.Warningf("Failed reading certificates to monitor: %s", err)
+			continue
+		}
+		for _, c := range certs {
+			left := c.NotAfter.Sub(now)
+			m.scope.Tagged(map[string]string{
+				"channel":       c.Channel,
+				"msp_id":        c.MSPID,
+				"role":          c.Role,
+				"subject":       c.Subject,
+				"serial_number": c.SerialNumber,
+			}).Gauge("seconds_to_expiration").Update(left.Seconds())
+
+			switch {
+			case left <= 0:
+				expired++
+				logger.Errorf("Certificate %s expired on %s", describe(c), c.NotAfter.Format(time.RFC3339))
+			case left <= m.options.WarnBefore:
+				expiring++
+				logger.Warningf("Certificate %s expires in %s, on %s", describe(c), left.Truncate(time.Minute), c.NotAfter.Format(time.RFC3339))
+			}
+		}
+	}
+	m.scope.Gauge("expiring").Update(float64(expiring))
+	m.scope.Gauge("expired").Update(float64(expired))
+}
+
+// Expiring reads the certificates of the sources and returns those which
+// expire within the given duration, or within the warning period if it is not
+// positive, the expired ones included, the earliest first
+func (m *Monitor) Expiring(within time.Duration) ([]Certificate, error) {
+	if within <= 0 {
+		within = m.options.WarnBefore
+	}
+	deadline := m.now().Add(within)
+	expiring := []Certificate{}
+	for _, source := range m.sources {
+		certs, err := source()
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range certs {
+			if !c.NotAfter.After(deadline) {
+				expiring = append(expiring, c)
+			}
+		}
+	}
+	sort.SliceStable(expiring, func(i, j int) bool {
+		return expiring[i].NotAfter.Before(expiring[j].NotAfter)
+	})
+	return expiring, nil
+}
+
+// expiringCertificate is the JSON encoding of the certificates returned by
+// ServeHTTP
+type expiringCertificate struct {
+	Channel      string    `json:"channel,omitempty"`
+	MSPID        string    `json:"msp_id"`
+	Role         string    `json:"role"`
+	Subject      string    `json:"subject"`
+	SerialNumber string    `json:"serial_number"`
+	NotAfter     time.Time `json:"not_after"`
+	Expired      bool      `json:"expired"`
+}
+
+// ServeHTTP serves the certificates which expire within the duration of the
+// "within" query parameter, or within the warning period if it is unset,
+// encoded as JSON
+func (m *Monitor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var within time.Duration
+	if param := r.URL.Query().Get("within"); param != "" {
+		var err error
+		if within, err = time.ParseDuration(param); err != nil {
+			http.Error(w, "invalid within duration: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	certs, err := m.Expiring(within)
+	if err != nil {
+		http.Error(w, errors.WithMessage(err, "failed listing expiring certificates").Error(), http.StatusInternalServerError)
+		return
+	}
+
+	now := m.now()
+	expiring := []expiringCertificate{}
+	for _, c := range certs {
+		expiring = append(expiring, expiringCertificate{
+			Channel:      c.Channel,
+			MSPID:        c.MSPID,
+			Role:         c.Role,
+			Subject:      c.Subject,
+			SerialNumber: c.SerialNumber,
+			NotAfter:     c.NotAfter,
+			Expired:      !c.NotAfter.After(now),
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(expiring)
+}
+
+func describe(c Certificate) string {
+	if c.Channel == "" {
+		return fmt.Sprintf("%s [%s] of the node, of MSP %s", c.Role, c.Subject, c.MSPID)
+	}
+	return fmt.Sprintf("%s [%s] of MSP %s on channel %s", c.Role, c.Subject, c.MSPID, c.Channel)
+}
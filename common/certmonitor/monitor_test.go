@@ -0,0 +1,163 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package certmonitor
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingGauge struct {
+	sync.Mutex
+	value float64
+}
+
+func (g *recordingGauge) Update(value float64) {
+	g.Lock()
+	defer g.Unlock()
+	g.value = value
+}
+
+func (g *recordingGauge) get() float64 {
+	g.Lock()
+	defer g.Unlock()
+	return g.value
+}
+
+// recordingScope records the gauges by name, prefixed by the serial number
+// they are tagged with if any
+type recordingScope struct {
+	metrics.Scope
+	prefix string
+	gauges map[string]*recordingGauge
+}
+
+func newRecordingScope() *recordingScope {
+	return &recordingScope{
+		Scope:  metrics.NewNoOpScope(),
+		gauges: make(map[string]*recordingGauge),
+	}
+}
+
+func (s *recordingScope) Gauge(name string) metrics.Gauge {
+	g := &recordingGauge{}
+	s.gauges[s.prefix+name] = g
+	return g
+}
+
+func (s *recordingScope) Tagged(tags map[string]string) metrics.Scope {
+	return &recordingScope{Scope: s.Scope, prefix: tags["serial_number"] + "/", gauges: s.gauges}
+}
+
+func TestMonitor(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	certs := []Certificate{
+		{MSPID: "SampleOrg", Role: "signer", SerialNumber: "1", NotAfter: now.Add(48 * time.Hour)},
+		{MSPID: "SampleOrg", Role: "tls_server", SerialNumber: "2", NotAfter: now.Add(-time.Hour)},
+		{Channel: "mychannel", MSPID: "Org1", Role: "root_cert", SerialNumber: "3", NotAfter: now.Add(365 * 24 * time.Hour)},
+	}
+	sources := []Source{
+		func() ([]Certificate, error) { return certs[:2], nil },
+		func() ([]Certificate, error) { return certs[2:], nil },
+	}
+
+	scope := newRecordingScope()
+	m := NewMonitor(Options{Interval: time.Hour, WarnBefore: 7 * 24 * time.Hour}, scope, sources...)
+	m.now = func() time.Time { return now }
+
+	m.Scan()
+	assert.Equal(t, float64(1), scope.gauges["expiring"].get())
+	assert.Equal(t, float64(1), scope.gauges["expired"].get())
+	assert.Equal(t, (48 * time.Hour).Seconds(), scope.gauges["1/seconds_to_expiration"].get())
+	assert.Equal(t, -time.Hour.Seconds(), scope.gauges["2/seconds_to_expiration"].get())
+	assert.Equal(t, (365 * 24 * time.Hour).Seconds(), scope.gauges["3/seconds_to_expiration"].get())
+
+	expiring, err := m.Expiring(0)
+	require.NoError(t, err)
+	assert.Equal(t, []Certificate{certs[1], certs[0]}, expiring)
+	expiring, err = m.Expiring(2 * 365 * 24 * time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []Certificate{certs[1], certs[0], certs[2]}, expiring)
+
+	// a failing source is skipped by the scans but fails the listing
+	m.sources = append(m.sources, func() ([]Certificate, error) { return nil, errors.New("unreadable") })
+	m.Scan()
+	assert.Equal(t, float64(1), scope.gauges["expiring"].get())
+	_, err = m.Expiring(0)
+	assert.EqualError(t, err, "unreadable")
+}
+
+func TestMonitorStart(t *testing.T) {
+	var lock sync.Mutex
+	scans := 0
+	m := NewMonitor(Options{Interval: 10 * time.Millisecond}, metrics.NewNoOpScope(), func() ([]Certificate, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		scans++
+		return nil, nil
+	})
+	m.Start()
+	time.Sleep(100 * time.Millisecond)
+	m.Stop()
+	m.Stop()
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.True(t, scans > 1, "certificates weren't scanned periodically")
+}
+
+func TestMonitorServeHTTP(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewMonitor(Options{WarnBefore: 24 * time.Hour}, metrics.NewNoOpScope(), func() ([]Certificate, error) {
+		return []Certificate{
+			{MSPID: "SampleOrg", Role: "signer", Subject: "CN=peer0", SerialNumber: "1", NotAfter: now.Add(time.Hour)},
+			{Channel: "mychannel", MSPID: "Org1", Role: "admin", Subject: "CN=admin", SerialNumber: "2", NotAfter: now.Add(-time.Hour)},
+			{Channel: "mychannel", MSPID: "Org1", Role: "root_cert", Subject: "CN=ca", SerialNumber: "3", NotAfter: now.Add(48 * time.Hour)},
+		}, nil
+	})
+	m.now = func() time.Time { return now }
+	server := httptest.NewServer(m)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "?within=72h")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var expiring []expiringCertificate
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&expiring))
+	assert.Equal(t, []expiringCertificate{
+		{Channel: "mychannel", MSPID: "Org1", Role: "admin", Subject: "CN=admin", SerialNumber: "2", NotAfter: now.Add(-time.Hour), Expired: true},
+		{MSPID: "SampleOrg", Role: "signer", Subject: "CN=peer0", SerialNumber: "1", NotAfter: now.Add(time.Hour)},
+		{Channel: "mychannel", MSPID: "Org1", Role: "root_cert", Subject: "CN=ca", SerialNumber: "3", NotAfter: now.Add(48 * time.Hour)},
+	}, expiring)
+
+	resp, err = http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	expiring = nil
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&expiring))
+	assert.Len(t, expiring, 2)
+
+	resp, err = http.Get(server.URL + "?within=soon")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Post(server.URL, "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
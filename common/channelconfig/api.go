@@ -44,6 +44,14 @@ type Application interface {
 
 	// Capabilities defines the capabilities for the application portion of a channel
 	Capabilities() ApplicationCapabilities
+
+	// RWSetHashingAlgorithm returns the algorithm used to hash the values
+	// of the write-sets, including the private data
+	RWSetHashingAlgorithm() func(input []byte) []byte
+
+	// TxDedupWindow returns the number of blocks within which the transactions carrying
+	// the same dedup key for the same chaincode are duplicates, 0 if they are not deduplicated
+	TxDedupWindow() uint64
 }
 
 // Channel gives read only access to the channel configuration
@@ -59,6 +67,11 @@ type Channel interface {
 	// OrdererAddresses returns the list of valid orderer addresses to connect to to invoke Broadcast/Deliver
 	OrdererAddresses() []string
 
+	// EndorsementMaxSkew returns the maximum difference allowed between the timestamp of a
+	// transaction and the time at which it is ordered, when the expiration of its identities
+	// is checked against the endorsement time
+	EndorsementMaxSkew() time.Duration
+
 	// Capabilities defines the capabilities for a channel
 	Capabilities() ChannelCapabilities
 }
@@ -115,6 +128,14 @@ type ChannelCapabilities interface {
 	// MSPVersion specifies the version of the MSP this channel must understand, including the MSP types
 	// and MSP principal types.
 	MSPVersion() msp.MSPVersion
+
+	// EndorsementTimeExpiration specifies whether the expiration of the identities which created and
+	// endorsed a transaction is checked against the timestamp of the transaction, when it was endorsed
+	EndorsementTimeExpiration() bool
+
+	// PublishedCRLs specifies whether the CRLs published through crlscc are validated, committed
+	// and applied to the MSPs of the channel
+	PublishedCRLs() bool
 }
 
 // ApplicationCapabilities defines the capabilities for the application portion of a channel
@@ -160,6 +181,18 @@ type ApplicationCapabilities interface {
 	// KeyLevelEndorsement returns true if this channel supports endorsement
 	// policies expressible at a ledger key granularity, as described in FAB-8812
 	KeyLevelEndorsement() bool
+
+	// ChaincodeMigration returns true if this channel supports recording the
+	// migrations of the state of the chaincodes by their Migrate function
+	ChaincodeMigration() bool
+
+	// EphemeralCollections returns true if this channel supports the collections
+	// whose private data is never persisted by the peers
+	EphemeralCollections() bool
+
+	// ValueCommitments returns true if this channel supports the collections
+	// committing to their private values with Pedersen commitments
+	ValueCommitments() bool
 }
 
 // OrdererCapabilities defines the capabilities for the orderer portion of a channel
@@ -178,6 +211,22 @@ type OrdererCapabilities interface {
 	// ExpirationCheck specifies whether the orderer checks for identity expiration checks
 	// when validating messages
 	ExpirationCheck() bool
+
+	// UnsatisfiablePoliciesCheck specifies whether the orderer rejects the config updates
+	// introducing policies which can never be satisfied
+	UnsatisfiablePoliciesCheck() bool
+
+	// DeliverIdentityBinding specifies whether the orderer requires the signed deliver requests
+	// to carry the hash of the TLS client certificate of their connection
+	DeliverIdentityBinding() bool
+
+	// TransactionSizeLimit specifies whether the orderer enforces the max transaction bytes
+	// of the batch size
+	TransactionSizeLimit() bool
+
+	// KafkaIPv6Brokers specifies whether the Kafka brokers of the orderer config may be given
+	// as bracketed IPv6 addresses
+	KafkaIPv6Brokers() bool
 }
 
 // PolicyMapper is an interface for
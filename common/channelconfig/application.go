@@ -7,9 +7,13 @@ SPDX-License-Identifier: Apache-2.0
 package channelconfig
 
 import (
+	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/common/capabilities"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/util"
 	cb "github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
 
 	"github.com/pkg/errors"
 )
@@ -20,18 +24,30 @@ const (
 
 	// ACLsKey is the name of the ACLs config
 	ACLsKey = "ACLs"
+
+	// RWSetHashingAlgorithmKey is the name of the write-set hashing algorithm config
+	RWSetHashingAlgorithmKey = "RWSetHashingAlgorithm"
+
+	// TxDedupWindowKey is the name of the transaction dedup window config
+	TxDedupWindowKey = "TxDedupWindow"
+
+	// BLAKE2b256 is the name of the BLAKE2b write-set hashing algorithm with 256 bits digests
+	BLAKE2b256 = "BLAKE2B_256"
 )
 
 // ApplicationProtos is used as the source of the ApplicationConfig
 type ApplicationProtos struct {
-	ACLs         *pb.ACLs
-	Capabilities *cb.Capabilities
+	ACLs                  *pb.ACLs
+	Capabilities          *cb.Capabilities
+	RWSetHashingAlgorithm *cb.HashingAlgorithm
+	TxDedupWindow         *pb.TxDedupWindow
 }
 
 // ApplicationConfig implements the Application interface
 type ApplicationConfig struct {
-	applicationOrgs map[string]ApplicationOrg
-	protos          *ApplicationProtos
+	applicationOrgs       map[string]ApplicationOrg
+	protos                *ApplicationProtos
+	rwsetHashingAlgorithm func(input []byte) []byte
 }
 
 // NewApplicationConfig creates config from an Application config group
@@ -51,7 +67,23 @@ func NewApplicationConfig(appGroup *cb.ConfigGroup, mspConfig *MSPConfigHandler)
 		}
 	}
 
+	if !capabilities.NewApplicationProvider(ac.protos.Capabilities.Capabilities).ConfigurableRWSetHashing() {
+		if _, ok := appGroup.Values[RWSetHashingAlgorithmKey]; ok {
+			return nil, errors.New("RWSetHashingAlgorithm may not be specified without the required capability")
+		}
+	}
+
+	if !capabilities.NewApplicationProvider(ac.protos.Capabilities.Capabilities).TxDeduplication() {
+		if _, ok := appGroup.Values[TxDedupWindowKey]; ok {
+			return nil, errors.New("TxDedupWindow may not be specified without the required capability")
+		}
+	}
+
 	var err error
+	if ac.rwsetHashingAlgorithm, err = RWSetHashFunc(ac.protos.RWSetHashingAlgorithm.Name); err != nil {
+		return nil, err
+	}
+
 	for orgName, orgGroup := range appGroup.Groups {
 		ac.applicationOrgs[orgName], err = NewApplicationOrgConfig(orgName, orgGroup, mspConfig)
 		if err != nil {
@@ -62,6 +94,91 @@ func NewApplicationConfig(appGroup *cb.ConfigGroup, mspConfig *MSPConfigHandler)
 	return ac, nil
 }
 
+// RWSetHashFunc returns the function implementing the named write-set hashing
+// algorithm, SHA256 being the default
+func RWSetHashFunc(name string) (func(input []byte) []byte, error) {
+	switch name {
+	case "", bccsp.SHA256:
+		return util.ComputeSHA256, nil
+	case bccsp.SHA3_256:
+		return util.ComputeSHA3256, nil
+	case BLAKE2b256:
+		return util.ComputeBLAKE2b256, nil
+	default:
+		return nil, errors.Errorf("unknown write-set hashing algorithm type: %s", name)
+	}
+}
+
+// applicationProtosFromConfigBlock returns the values of the application group of
+// the config carried by the given config block, nil if the channel has none
+func applicationProtosFromConfigBlock(block *cb.Block) (*ApplicationProtos, error) {
+	env, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to extract the config envelope from the block")
+	}
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal payload from envelope")
+	}
+	configEnvelope, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal config envelope from payload")
+	}
+	if configEnvelope.Config == nil || configEnvelope.Config.ChannelGroup == nil {
+		return nil, errors.New("config envelope has no channel group")
+	}
+
+	appGroup, ok := configEnvelope.Config.ChannelGroup.Groups[ApplicationGroupKey]
+	if !ok {
+		return nil, nil
+	}
+	protos := &ApplicationProtos{}
+	if err := DeserializeProtoValuesFromGroup(appGroup, protos); err != nil {
+		return nil, errors.Wrap(err, "failed to deserialize values")
+	}
+	return protos, nil
+}
+
+// RWSetHashFuncFromConfigBlock returns the function hashing the values of the
+// write-sets of the blocks created under the config carried by the given config block
+func RWSetHashFuncFromConfigBlock(block *cb.Block) (func(input []byte) []byte, error) {
+	protos, err := applicationProtosFromConfigBlock(block)
+	if err != nil {
+		return nil, err
+	}
+	if protos == nil || !capabilities.NewApplicationProvider(protos.Capabilities.GetCapabilities()).ConfigurableRWSetHashing() {
+		return util.ComputeSHA256, nil
+	}
+	return RWSetHashFunc(protos.RWSetHashingAlgorithm.GetName())
+}
+
+// TxDedupWindowFromConfigBlock returns the dedup window of the transactions of the
+// blocks created under the config carried by the given config block, 0 if the
+// transactions are not deduplicated
+func TxDedupWindowFromConfigBlock(block *cb.Block) (uint64, error) {
+	protos, err := applicationProtosFromConfigBlock(block)
+	if err != nil {
+		return 0, err
+	}
+	if protos == nil || !capabilities.NewApplicationProvider(protos.Capabilities.GetCapabilities()).TxDeduplication() {
+		return 0, nil
+	}
+	return protos.TxDedupWindow.GetBlocks(), nil
+}
+
+// EphemeralCollectionsFromConfigBlock returns true if the config carried by the
+// given config block enables the ephemeral collections for the blocks created under it
+func EphemeralCollectionsFromConfigBlock(block *cb.Block) (bool, error) {
+	protos, err := applicationProtosFromConfigBlock(block)
+	if err != nil {
+		return false, err
+	}
+	if protos == nil {
+		return false, nil
+	}
+	return capabilities.NewApplicationProvider(protos.Capabilities.GetCapabilities()).EphemeralCollections(), nil
+}
+
 // Organizations returns a map of org ID to ApplicationOrg
 func (ac *ApplicationConfig) Organizations() map[string]ApplicationOrg {
 	return ac.applicationOrgs
@@ -72,6 +189,18 @@ func (ac *ApplicationConfig) Capabilities() ApplicationCapabilities {
 	return capabilities.NewApplicationProvider(ac.protos.Capabilities.Capabilities)
 }
 
+// RWSetHashingAlgorithm returns the function used to hash the values of the
+// write-sets, SHA256 unless the RWSetHashingAlgorithm config specifies otherwise
+func (ac *ApplicationConfig) RWSetHashingAlgorithm() func(input []byte) []byte {
+	return ac.rwsetHashingAlgorithm
+}
+
+// TxDedupWindow returns the number of blocks within which the transactions carrying
+// the same dedup key for the same chaincode are duplicates, 0 if they are not deduplicated
+func (ac *ApplicationConfig) TxDedupWindow() uint64 {
+	return ac.protos.TxDedupWindow.GetBlocks()
+}
+
 // APIPolicyMapper returns a PolicyMapper that maps API names to policies
 func (ac *ApplicationConfig) APIPolicyMapper() PolicyMapper {
 	pm := newAPIsProvider(ac.protos.ACLs.Acls)
@@ -9,7 +9,9 @@ package channelconfig
 import (
 	"testing"
 
+	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/common/capabilities"
+	"github.com/hyperledger/fabric/common/util"
 	cb "github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/utils"
 
@@ -53,3 +55,240 @@ func TestACL(t *testing.T) {
 		g.Expect(err).To(MatchError("ACLs may not be specified without the required capability"))
 	})
 }
+
+func TestRWSetHashingAlgorithm(t *testing.T) {
+	g := NewGomegaWithT(t)
+	cgt := &cb.ConfigGroup{
+		Values: map[string]*cb.ConfigValue{
+			RWSetHashingAlgorithmKey: {
+				Value: utils.MarshalOrPanic(
+					RWSetHashingAlgorithmValue(bccsp.SHA3_256).Value(),
+				),
+			},
+			CapabilitiesKey: {
+				Value: utils.MarshalOrPanic(
+					CapabilitiesValue(map[string]bool{
+						capabilities.ApplicationV1_4_2: true,
+					}).Value(),
+				),
+			},
+		},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		cg := proto.Clone(cgt).(*cb.ConfigGroup)
+		ac, err := NewApplicationConfig(cg, nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(ac.RWSetHashingAlgorithm()([]byte("value"))).To(Equal(util.ComputeSHA3256([]byte("value"))))
+	})
+
+	t.Run("BLAKE2b", func(t *testing.T) {
+		cg := proto.Clone(cgt).(*cb.ConfigGroup)
+		cg.Values[RWSetHashingAlgorithmKey].Value = utils.MarshalOrPanic(RWSetHashingAlgorithmValue(BLAKE2b256).Value())
+		ac, err := NewApplicationConfig(cg, nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(ac.RWSetHashingAlgorithm()([]byte("value"))).To(Equal(util.ComputeBLAKE2b256([]byte("value"))))
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		cg := proto.Clone(cgt).(*cb.ConfigGroup)
+		delete(cg.Values, RWSetHashingAlgorithmKey)
+		ac, err := NewApplicationConfig(cg, nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(ac.RWSetHashingAlgorithm()([]byte("value"))).To(Equal(util.ComputeSHA256([]byte("value"))))
+	})
+
+	t.Run("Unknown algorithm", func(t *testing.T) {
+		cg := proto.Clone(cgt).(*cb.ConfigGroup)
+		cg.Values[RWSetHashingAlgorithmKey].Value = utils.MarshalOrPanic(RWSetHashingAlgorithmValue("MD5").Value())
+		_, err := NewApplicationConfig(cg, nil)
+		g.Expect(err).To(MatchError("unknown write-set hashing algorithm type: MD5"))
+	})
+
+	t.Run("MissingCapability", func(t *testing.T) {
+		cg := proto.Clone(cgt).(*cb.ConfigGroup)
+		delete(cg.Values, CapabilitiesKey)
+		_, err := NewApplicationConfig(cg, nil)
+		g.Expect(err).To(MatchError("RWSetHashingAlgorithm may not be specified without the required capability"))
+	})
+
+	t.Run("V1_3Capability", func(t *testing.T) {
+		cg := proto.Clone(cgt).(*cb.ConfigGroup)
+		cg.Values[CapabilitiesKey].Value = utils.MarshalOrPanic(CapabilitiesValue(map[string]bool{
+			capabilities.ApplicationV1_3: true,
+		}).Value())
+		_, err := NewApplicationConfig(cg, nil)
+		g.Expect(err).To(MatchError("RWSetHashingAlgorithm may not be specified without the required capability"))
+	})
+}
+
+func TestRWSetHashFuncFromConfigBlock(t *testing.T) {
+	g := NewGomegaWithT(t)
+	configBlock := func(appGroup *cb.ConfigGroup) *cb.Block {
+		config := &cb.Config{ChannelGroup: &cb.ConfigGroup{Groups: map[string]*cb.ConfigGroup{}}}
+		if appGroup != nil {
+			config.ChannelGroup.Groups[ApplicationGroupKey] = appGroup
+		}
+		payload := &cb.Payload{
+			Header: &cb.Header{},
+			Data:   utils.MarshalOrPanic(&cb.ConfigEnvelope{Config: config}),
+		}
+		env := &cb.Envelope{Payload: utils.MarshalOrPanic(payload)}
+		return &cb.Block{Data: &cb.BlockData{Data: [][]byte{utils.MarshalOrPanic(env)}}}
+	}
+	appGroup := func(caps map[string]bool, algorithm string) *cb.ConfigGroup {
+		return &cb.ConfigGroup{
+			Values: map[string]*cb.ConfigValue{
+				CapabilitiesKey:          {Value: utils.MarshalOrPanic(CapabilitiesValue(caps).Value())},
+				RWSetHashingAlgorithmKey: {Value: utils.MarshalOrPanic(RWSetHashingAlgorithmValue(algorithm).Value())},
+			},
+		}
+	}
+	value := []byte("value")
+
+	hashFunc, err := RWSetHashFuncFromConfigBlock(configBlock(appGroup(map[string]bool{capabilities.ApplicationV1_4_2: true}, BLAKE2b256)))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(hashFunc(value)).To(Equal(util.ComputeBLAKE2b256(value)))
+
+	hashFunc, err = RWSetHashFuncFromConfigBlock(configBlock(appGroup(map[string]bool{capabilities.ApplicationV1_3: true}, BLAKE2b256)))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(hashFunc(value)).To(Equal(util.ComputeSHA256(value)))
+
+	hashFunc, err = RWSetHashFuncFromConfigBlock(configBlock(nil))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(hashFunc(value)).To(Equal(util.ComputeSHA256(value)))
+
+	_, err = RWSetHashFuncFromConfigBlock(configBlock(appGroup(map[string]bool{capabilities.ApplicationV1_4_2: true}, "MD5")))
+	g.Expect(err).To(MatchError("unknown write-set hashing algorithm type: MD5"))
+
+	_, err = RWSetHashFuncFromConfigBlock(&cb.Block{Data: &cb.BlockData{}})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestTxDedupWindowFromConfigBlock(t *testing.T) {
+	g := NewGomegaWithT(t)
+	configBlock := func(appGroup *cb.ConfigGroup) *cb.Block {
+		config := &cb.Config{ChannelGroup: &cb.ConfigGroup{Groups: map[string]*cb.ConfigGroup{}}}
+		if appGroup != nil {
+			config.ChannelGroup.Groups[ApplicationGroupKey] = appGroup
+		}
+		payload := &cb.Payload{
+			Header: &cb.Header{},
+			Data:   utils.MarshalOrPanic(&cb.ConfigEnvelope{Config: config}),
+		}
+		env := &cb.Envelope{Payload: utils.MarshalOrPanic(payload)}
+		return &cb.Block{Data: &cb.BlockData{Data: [][]byte{utils.MarshalOrPanic(env)}}}
+	}
+	appGroup := func(caps map[string]bool, blocks uint64) *cb.ConfigGroup {
+		return &cb.ConfigGroup{
+			Values: map[string]*cb.ConfigValue{
+				CapabilitiesKey:  {Value: utils.MarshalOrPanic(CapabilitiesValue(caps).Value())},
+				TxDedupWindowKey: {Value: utils.MarshalOrPanic(TxDedupWindowValue(blocks).Value())},
+			},
+		}
+	}
+
+	window, err := TxDedupWindowFromConfigBlock(configBlock(appGroup(map[string]bool{capabilities.ApplicationV1_4_2: true}, 10)))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(window).To(Equal(uint64(10)))
+
+	window, err = TxDedupWindowFromConfigBlock(configBlock(appGroup(map[string]bool{capabilities.ApplicationV1_3: true}, 10)))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(window).To(Equal(uint64(0)))
+
+	window, err = TxDedupWindowFromConfigBlock(configBlock(nil))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(window).To(Equal(uint64(0)))
+
+	_, err = TxDedupWindowFromConfigBlock(&cb.Block{Data: &cb.BlockData{}})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestEphemeralCollectionsFromConfigBlock(t *testing.T) {
+	g := NewGomegaWithT(t)
+	configBlock := func(appGroup *cb.ConfigGroup) *cb.Block {
+		config := &cb.Config{ChannelGroup: &cb.ConfigGroup{Groups: map[string]*cb.ConfigGroup{}}}
+		if appGroup != nil {
+			config.ChannelGroup.Groups[ApplicationGroupKey] = appGroup
+		}
+		payload := &cb.Payload{
+			Header: &cb.Header{},
+			Data:   utils.MarshalOrPanic(&cb.ConfigEnvelope{Config: config}),
+		}
+		env := &cb.Envelope{Payload: utils.MarshalOrPanic(payload)}
+		return &cb.Block{Data: &cb.BlockData{Data: [][]byte{utils.MarshalOrPanic(env)}}}
+	}
+	appGroup := func(caps map[string]bool) *cb.ConfigGroup {
+		return &cb.ConfigGroup{
+			Values: map[string]*cb.ConfigValue{
+				CapabilitiesKey: {Value: utils.MarshalOrPanic(CapabilitiesValue(caps).Value())},
+			},
+		}
+	}
+
+	enabled, err := EphemeralCollectionsFromConfigBlock(configBlock(appGroup(map[string]bool{capabilities.ApplicationV1_4_2: true})))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(enabled).To(BeTrue())
+
+	enabled, err = EphemeralCollectionsFromConfigBlock(configBlock(appGroup(map[string]bool{capabilities.ApplicationV1_3: true})))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(enabled).To(BeFalse())
+
+	enabled, err = EphemeralCollectionsFromConfigBlock(configBlock(nil))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(enabled).To(BeFalse())
+
+	_, err = EphemeralCollectionsFromConfigBlock(&cb.Block{Data: &cb.BlockData{}})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestTxDedupWindow(t *testing.T) {
+	g := NewGomegaWithT(t)
+	cgt := &cb.ConfigGroup{
+		Values: map[string]*cb.ConfigValue{
+			TxDedupWindowKey: {
+				Value: utils.MarshalOrPanic(
+					TxDedupWindowValue(100).Value(),
+				),
+			},
+			CapabilitiesKey: {
+				Value: utils.MarshalOrPanic(
+					CapabilitiesValue(map[string]bool{
+						capabilities.ApplicationV1_4_2: true,
+					}).Value(),
+				),
+			},
+		},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		cg := proto.Clone(cgt).(*cb.ConfigGroup)
+		ac, err := NewApplicationConfig(cg, nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(ac.TxDedupWindow()).To(Equal(uint64(100)))
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		cg := proto.Clone(cgt).(*cb.ConfigGroup)
+		delete(cg.Values, TxDedupWindowKey)
+		ac, err := NewApplicationConfig(cg, nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(ac.TxDedupWindow()).To(Equal(uint64(0)))
+	})
+
+	t.Run("MissingCapability", func(t *testing.T) {
+		cg := proto.Clone(cgt).(*cb.ConfigGroup)
+		delete(cg.Values, CapabilitiesKey)
+		_, err := NewApplicationConfig(cg, nil)
+		g.Expect(err).To(MatchError("TxDedupWindow may not be specified without the required capability"))
+	})
+
+	t.Run("V1_3Capability", func(t *testing.T) {
+		cg := proto.Clone(cgt).(*cb.ConfigGroup)
+		cg.Values[CapabilitiesKey].Value = utils.MarshalOrPanic(CapabilitiesValue(map[string]bool{
+			capabilities.ApplicationV1_3: true,
+		}).Value())
+		_, err := NewApplicationConfig(cg, nil)
+		g.Expect(err).To(MatchError("TxDedupWindow may not be specified without the required capability"))
+	})
+}
@@ -201,6 +201,7 @@ func NewBundle(channelID string, config *cb.Config) (*Bundle, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "initializing policymanager failed")
 	}
+	policyManager.CacheEvaluations(config.Sequence, policies.DefaultEvaluationCacheSize)
 
 	configtxManager, err := configtx.NewValidatorImpl(channelID, config, RootGroupKey, policyManager)
 	if err != nil {
@@ -9,6 +9,7 @@ package channelconfig
 import (
 	"fmt"
 	"math"
+	"time"
 
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/common/capabilities"
@@ -33,6 +34,9 @@ const (
 	// OrdererAddressesKey is the cb.ConfigItem type key name for the OrdererAddresses message
 	OrdererAddressesKey = "OrdererAddresses"
 
+	// EndorsementMaxSkewKey is the cb.ConfigItem type key name for the EndorsementMaxSkew message
+	EndorsementMaxSkewKey = "EndorsementMaxSkew"
+
 	// GroupKey is the name of the channel group
 	ChannelGroupKey = "Channel"
 
@@ -62,14 +66,21 @@ type ChannelProtos struct {
 	OrdererAddresses          *cb.OrdererAddresses
 	Consortium                *cb.Consortium
 	Capabilities              *cb.Capabilities
+	EndorsementMaxSkew        *cb.EndorsementMaxSkew
 }
 
+// DefaultEndorsementMaxSkew is the maximum difference allowed between the timestamp of a
+// transaction and the time at which it is ordered, unless the channel configures it
+const DefaultEndorsementMaxSkew = 15 * time.Minute
+
 // ChannelConfig stores the channel configuration
 type ChannelConfig struct {
 	protos *ChannelProtos
 
 	hashingAlgorithm func(input []byte) []byte
 
+	endorsementMaxSkew time.Duration
+
 	mspManager msp.MSPManager
 
 	appConfig         *ApplicationConfig
@@ -153,6 +164,13 @@ func (cc *ChannelConfig) OrdererAddresses() []string {
 	return cc.protos.OrdererAddresses.Addresses
 }
 
+// EndorsementMaxSkew returns the maximum difference allowed between the timestamp of a
+// transaction and the time at which it is ordered, when the expiration of its identities
+// is checked against the endorsement time
+func (cc *ChannelConfig) EndorsementMaxSkew() time.Duration {
+	return cc.endorsementMaxSkew
+}
+
 // ConsortiumName returns the name of the consortium this channel was created under
 func (cc *ChannelConfig) ConsortiumName() string {
 	return cc.protos.Consortium.Name
@@ -169,6 +187,7 @@ func (cc *ChannelConfig) Validate() error {
 		cc.validateHashingAlgorithm,
 		cc.validateBlockDataHashingStructure,
 		cc.validateOrdererAddresses,
+		cc.validateEndorsementMaxSkew,
 	} {
 		if err := validator(); err != nil {
 			return err
@@ -191,6 +210,25 @@ func (cc *ChannelConfig) validateHashingAlgorithm() error {
 	return nil
 }
 
+func (cc *ChannelConfig) validateEndorsementMaxSkew() error {
+	if cc.protos.EndorsementMaxSkew.Duration == "" {
+		cc.endorsementMaxSkew = DefaultEndorsementMaxSkew
+		return nil
+	}
+	if !capabilities.NewChannelProvider(cc.protos.Capabilities.GetCapabilities()).EndorsementTimeExpiration() {
+		return errors.New("EndorsementMaxSkew may not be specified without the required capability")
+	}
+	var err error
+	cc.endorsementMaxSkew, err = time.ParseDuration(cc.protos.EndorsementMaxSkew.Duration)
+	if err != nil {
+		return fmt.Errorf("Attempted to set the endorsement max skew to a invalid value: %s", err)
+	}
+	if cc.endorsementMaxSkew <= 0 {
+		return fmt.Errorf("Attempted to set the endorsement max skew to a non-positive value: %s", cc.endorsementMaxSkew)
+	}
+	return nil
+}
+
 func (cc *ChannelConfig) validateBlockDataHashingStructure() error {
 	if cc.protos.BlockDataHashingStructure.Width != math.MaxUint32 {
 		return fmt.Errorf("BlockDataHashStructure width only supported at MaxUint32 in this version")
@@ -10,8 +10,10 @@ import (
 	"math"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/common/capabilities"
 	"github.com/hyperledger/fabric/common/util"
 	cb "github.com/hyperledger/fabric/protos/common"
 
@@ -77,6 +79,26 @@ func TestOrdererAddresses(t *testing.T) {
 	assert.Equal(t, "127.0.0.1:7050", cc.OrdererAddresses()[0], "Unexpected orderer address returned")
 }
 
+func TestEndorsementMaxSkew(t *testing.T) {
+	cc := &ChannelConfig{protos: &ChannelProtos{EndorsementMaxSkew: &cb.EndorsementMaxSkew{}}}
+	assert.NoError(t, cc.validateEndorsementMaxSkew(), "Unset endorsement max skew")
+	assert.Equal(t, DefaultEndorsementMaxSkew, cc.EndorsementMaxSkew())
+
+	cc = &ChannelConfig{protos: &ChannelProtos{EndorsementMaxSkew: &cb.EndorsementMaxSkew{Duration: "5m"}}}
+	assert.EqualError(t, cc.validateEndorsementMaxSkew(), "EndorsementMaxSkew may not be specified without the required capability")
+
+	v142 := &cb.Capabilities{Capabilities: map[string]*cb.Capability{capabilities.ChannelV1_4_2: {}}}
+	cc = &ChannelConfig{protos: &ChannelProtos{Capabilities: v142, EndorsementMaxSkew: &cb.EndorsementMaxSkew{Duration: "5m"}}}
+	assert.NoError(t, cc.validateEndorsementMaxSkew(), "Valid endorsement max skew")
+	assert.Equal(t, 5*time.Minute, cc.EndorsementMaxSkew())
+
+	cc = &ChannelConfig{protos: &ChannelProtos{Capabilities: v142, EndorsementMaxSkew: &cb.EndorsementMaxSkew{Duration: "0s"}}}
+	assert.Error(t, cc.validateEndorsementMaxSkew(), "Zero endorsement max skew")
+
+	cc = &ChannelConfig{protos: &ChannelProtos{Capabilities: v142, EndorsementMaxSkew: &cb.EndorsementMaxSkew{Duration: "soon"}}}
+	assert.Error(t, cc.validateEndorsementMaxSkew(), "Invalid endorsement max skew")
+}
+
 func TestConsortiumName(t *testing.T) {
 	cc := &ChannelConfig{protos: &ChannelProtos{Consortium: &cb.Consortium{Name: "TestConsortium"}}}
 	assert.Equal(t, "TestConsortium", cc.ConsortiumName(), "Unexpected consortium name returned")
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package channelconfig
 
 import (
+	"sort"
+
 	"github.com/hyperledger/fabric/common/policies"
 )
 
@@ -44,4 +46,14 @@ func LogSanityChecks(res Resources) {
 			}
 		}
 	}
+
+	unsatisfiable := UnsatisfiablePolicies(res)
+	paths := make([]string, 0, len(unsatisfiable))
+	for path := range unsatisfiable {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		logger.Warningf("Current configuration has policy '%s' which can never be satisfied: %s", path, unsatisfiable[path])
+	}
 }
@@ -96,7 +96,11 @@ func (bh *MSPConfigHandler) CreateMSPManager() (msp.MSPManager, error) {
 		i++
 	}
 
-	manager := msp.NewMSPManager()
-	err := manager.Setup(mspList)
+	// add a cache layer on top
+	manager, err := cache.NewManager(msp.NewMSPManager())
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating the MSP manager cache failed")
+	}
+	err = manager.Setup(mspList)
 	return manager, err
 }
@@ -8,6 +8,7 @@ package channelconfig
 
 import (
 	"fmt"
+	"net"
 	"regexp"
 	"strconv"
 	"strings"
@@ -153,6 +154,11 @@ func (oc *OrdererConfig) validateBatchSize() error {
 	if oc.protos.BatchSize.PreferredMaxBytes > oc.protos.BatchSize.AbsoluteMaxBytes {
 		return fmt.Errorf("Attempted to set the batch size preferred max bytes (%v) greater than the absolute max bytes (%v).", oc.protos.BatchSize.PreferredMaxBytes, oc.protos.BatchSize.AbsoluteMaxBytes)
 	}
+	// the max transaction bytes are only enforced, hence validated, with the required capability
+	transactionSizeLimit := capabilities.NewOrdererProvider(oc.protos.Capabilities.GetCapabilities()).TransactionSizeLimit()
+	if transactionSizeLimit && oc.protos.BatchSize.MaxTransactionBytes > oc.protos.BatchSize.AbsoluteMaxBytes {
+		return fmt.Errorf("Attempted to set the batch size max transaction bytes (%v) greater than the absolute max bytes (%v).", oc.protos.BatchSize.MaxTransactionBytes, oc.protos.BatchSize.AbsoluteMaxBytes)
+	}
 	return nil
 }
 
@@ -169,30 +175,35 @@ func (oc *OrdererConfig) validateBatchTimeout() error {
 }
 
 func (oc *OrdererConfig) validateKafkaBrokers() error {
+	// the IPv6 brokers are only understood by the orderers with the required capability
+	ipv6Brokers := capabilities.NewOrdererProvider(oc.protos.Capabilities.GetCapabilities()).KafkaIPv6Brokers()
 	for _, broker := range oc.protos.KafkaBrokers.Brokers {
-		if !brokerEntrySeemsValid(broker) {
+		if !brokerEntrySeemsValid(broker, ipv6Brokers) {
 			return fmt.Errorf("Invalid broker entry: %s", broker)
 		}
 	}
 	return nil
 }
 
-// This does just a barebones sanity check.
-func brokerEntrySeemsValid(broker string) bool {
-	if !strings.Contains(broker, ":") {
+// This does just a barebones sanity check. Bracketed IPv6 addresses are only
+// valid if ipv6Brokers is set.
+func brokerEntrySeemsValid(broker string, ipv6Brokers bool) bool {
+	host, port, err := net.SplitHostPort(broker)
+	if err != nil {
 		return false
 	}
 
-	parts := strings.Split(broker, ":")
-	if len(parts) > 2 {
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
 		return false
 	}
 
-	host := parts[0]
-	port := parts[1]
-
-	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
-		return false
+	// IPv6 addresses are enclosed in brackets, e.g., [2001:db8::1]:9092
+	if strings.HasPrefix(broker, "[") {
+		if !ipv6Brokers {
+			return false
+		}
+		ip := net.ParseIP(host)
+		return ip != nil && ip.To4() == nil
 	}
 
 	// Valid hostnames may contain only the ASCII letters 'a' through 'z' (in a
@@ -9,6 +9,8 @@ package channelconfig
 import (
 	"testing"
 
+	"github.com/hyperledger/fabric/common/capabilities"
+	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 
 	"github.com/stretchr/testify/assert"
@@ -30,6 +32,16 @@ func TestBatchSize(t *testing.T) {
 
 	oc = &OrdererConfig{protos: &OrdererProtos{BatchSize: &ab.BatchSize{MaxMessageCount: validMaxMessageCount, AbsoluteMaxBytes: validAbsoluteMaxBytes, PreferredMaxBytes: validAbsoluteMaxBytes + 1}}}
 	assert.Error(t, oc.validateBatchSize(), "PreferredMaxBytes larger to AbsoluteMaxBytes")
+
+	v142 := &cb.Capabilities{Capabilities: map[string]*cb.Capability{capabilities.OrdererV1_4_2: {}}}
+	oc = &OrdererConfig{protos: &OrdererProtos{Capabilities: v142, BatchSize: &ab.BatchSize{MaxMessageCount: validMaxMessageCount, AbsoluteMaxBytes: validAbsoluteMaxBytes, PreferredMaxBytes: validPreferredMaxBytes, MaxTransactionBytes: validAbsoluteMaxBytes}}}
+	assert.NoError(t, oc.validateBatchSize(), "MaxTransactionBytes equal to AbsoluteMaxBytes")
+
+	oc = &OrdererConfig{protos: &OrdererProtos{Capabilities: v142, BatchSize: &ab.BatchSize{MaxMessageCount: validMaxMessageCount, AbsoluteMaxBytes: validAbsoluteMaxBytes, PreferredMaxBytes: validPreferredMaxBytes, MaxTransactionBytes: validAbsoluteMaxBytes + 1}}}
+	assert.Error(t, oc.validateBatchSize(), "MaxTransactionBytes larger to AbsoluteMaxBytes")
+
+	oc = &OrdererConfig{protos: &OrdererProtos{BatchSize: &ab.BatchSize{MaxMessageCount: validMaxMessageCount, AbsoluteMaxBytes: validAbsoluteMaxBytes, PreferredMaxBytes: validPreferredMaxBytes, MaxTransactionBytes: validAbsoluteMaxBytes + 1}}}
+	assert.NoError(t, oc.validateBatchSize(), "MaxTransactionBytes ignored without the V1_4_2 capability")
 }
 
 func TestBatchTimeout(t *testing.T) {
@@ -49,4 +61,16 @@ func TestKafkaBrokers(t *testing.T) {
 
 	oc = &OrdererConfig{protos: &OrdererProtos{KafkaBrokers: &ab.KafkaBrokers{Brokers: []string{"127.0.0.1", "foo.bar", "127.0.0.1:-1", "localhost:65536", "foo.bar.:9092", ".127.0.0.1:9092", "-foo.bar:9092"}}}}
 	assert.Error(t, oc.validateKafkaBrokers(), "Invalid kafka brokers")
+
+	v142 := &cb.Capabilities{Capabilities: map[string]*cb.Capability{capabilities.OrdererV1_4_2: {}}}
+	oc = &OrdererConfig{protos: &OrdererProtos{Capabilities: v142, KafkaBrokers: &ab.KafkaBrokers{Brokers: []string{"[::1]:9092", "[2001:db8::1]:9092"}}}}
+	assert.NoError(t, oc.validateKafkaBrokers(), "Valid IPv6 kafka brokers")
+
+	oc = &OrdererConfig{protos: &OrdererProtos{KafkaBrokers: &ab.KafkaBrokers{Brokers: []string{"[::1]:9092"}}}}
+	assert.Error(t, oc.validateKafkaBrokers(), "IPv6 kafka brokers without the V1_4_2 capability")
+
+	for _, broker := range []string{"::1:9092", "[::1]", "[::1]:-1", "[foo.bar]:9092"} {
+		oc = &OrdererConfig{protos: &OrdererProtos{Capabilities: v142, KafkaBrokers: &ab.KafkaBrokers{Brokers: []string{broker}}}}
+		assert.Error(t, oc.validateKafkaBrokers(), "Invalid kafka broker %s", broker)
+	}
 }
@@ -0,0 +1,136 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channelconfig
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/msp"
+	cb "github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+)
+
+// UnsatisfiablePolicies returns, by path, why each of the signature policies
+// of the configuration of the given resources can never be satisfied by the
+// identities of the MSPs of the configuration
+func UnsatisfiablePolicies(res Resources) map[string]error {
+	validator, mspManager := res.ConfigtxValidator(), res.MSPManager()
+	if validator == nil || mspManager == nil {
+		return nil
+	}
+	config := validator.ConfigProto()
+	if config == nil || config.ChannelGroup == nil {
+		return nil
+	}
+	msps, err := mspManager.GetMSPs()
+	if err != nil {
+		logger.Warningf("Failed retrieving the MSPs of the channel, their policies are not checked: %s", err)
+		return nil
+	}
+	nodeOUs := make(map[string]bool)
+	collectNodeOUs(config.ChannelGroup, nodeOUs)
+	checkPrincipal := func(principal *mb.MSPPrincipal) error {
+		return checkPrincipal(principal, msps, nodeOUs)
+	}
+
+	unsatisfiable := make(map[string]error)
+	lintGroupPolicies(config.ChannelGroup, "/"+RootGroupKey, checkPrincipal, unsatisfiable)
+	return unsatisfiable
+}
+
+// ValidateNewPolicies returns an error if a signature policy of the configuration
+// of next can never be satisfied, unless it could already never be satisfied in
+// the configuration of current
+func ValidateNewPolicies(current, next Resources) error {
+	existing := UnsatisfiablePolicies(current)
+	var problems []string
+	for path, err := range UnsatisfiablePolicies(next) {
+		if _, ok := existing[path]; ok {
+			continue
+		}
+		problems = append(problems, path+": "+err.Error())
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return errors.Errorf("policies can never be satisfied: %s", strings.Join(problems, ", "))
+}
+
+func lintGroupPolicies(group *cb.ConfigGroup, path string, checkPrincipal func(*mb.MSPPrincipal) error, unsatisfiable map[string]error) {
+	for name, policy := range group.Policies {
+		if policy.Policy == nil || policy.Policy.Type != int32(cb.Policy_SIGNATURE) {
+			continue
+		}
+		envelope := &cb.SignaturePolicyEnvelope{}
+		// a policy rejecting all signatures is unsatisfiable on purpose
+		if err := proto.Unmarshal(policy.Policy.Value, envelope); err != nil || proto.Equal(envelope, cauthdsl.RejectAllPolicy) {
+			continue
+		}
+		if err := cauthdsl.Lint(envelope, checkPrincipal); err != nil {
+			unsatisfiable[path+"/"+name] = err
+		}
+	}
+	for name, subGroup := range group.Groups {
+		lintGroupPolicies(subGroup, path+"/"+name, checkPrincipal, unsatisfiable)
+	}
+}
+
+// collectNodeOUs records, by MSP ID, whether the Fabric MSPs of the group and its sub-groups enable NodeOUs
+func collectNodeOUs(group *cb.ConfigGroup, nodeOUs map[string]bool) {
+	if value, ok := group.Values[MSPKey]; ok {
+		mspConfig := &mb.MSPConfig{}
+		fabricConfig := &mb.FabricMSPConfig{}
+		if proto.Unmarshal(value.Value, mspConfig) == nil && mspConfig.Type == int32(msp.FABRIC) &&
+			proto.Unmarshal(mspConfig.Config, fabricConfig) == nil {
+			nodeOUs[fabricConfig.Name] = fabricConfig.FabricNodeOus.GetEnable()
+		}
+	}
+	for _, subGroup := range group.Groups {
+		collectNodeOUs(subGroup, nodeOUs)
+	}
+}
+
+// checkPrincipal returns an error if the given principal can't be satisfied by the identities of the given MSPs
+func checkPrincipal(principal *mb.MSPPrincipal, msps map[string]msp.MSP, nodeOUs map[string]bool) error {
+	var mspID string
+	switch principal.PrincipalClassification {
+	case mb.MSPPrincipal_ROLE:
+		role := &mb.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, role); err != nil {
+			return errors.Wrap(err, "malformed role principal")
+		}
+		mspID = role.MspIdentifier
+		if role.Role == mb.MSPRole_CLIENT || role.Role == mb.MSPRole_PEER {
+			if enabled, fabric := nodeOUs[mspID]; fabric && !enabled {
+				return errors.Errorf("MSP %s does not enable NodeOUs, so it has no identity of role %s", mspID, role.Role)
+			}
+		}
+	case mb.MSPPrincipal_ORGANIZATION_UNIT:
+		ou := &mb.OrganizationUnit{}
+		if err := proto.Unmarshal(principal.Principal, ou); err != nil {
+			return errors.Wrap(err, "malformed organization unit principal")
+		}
+		mspID = ou.MspIdentifier
+	case mb.MSPPrincipal_IDENTITY:
+		identity := &mb.SerializedIdentity{}
+		if err := proto.Unmarshal(principal.Principal, identity); err != nil {
+			return errors.Wrap(err, "malformed identity principal")
+		}
+		mspID = identity.Mspid
+	default:
+		return nil
+	}
+	if _, ok := msps[mspID]; !ok {
+		return errors.Errorf("MSP %s is not defined", mspID)
+	}
+	return nil
+}
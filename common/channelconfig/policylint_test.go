@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channelconfig_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/tools/configtxgen/configtxgentest"
+	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
+	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnsatisfiablePolicies(t *testing.T) {
+	channelGroup, err := encoder.NewChannelGroup(configtxgentest.Load(genesisconfig.SampleSingleMSPSoloProfile))
+	require.NoError(t, err)
+	config := &cb.Config{ChannelGroup: channelGroup}
+
+	current, err := channelconfig.NewBundle("foo", config)
+	require.NoError(t, err)
+	assert.Empty(t, channelconfig.UnsatisfiablePolicies(current))
+
+	// the sample MSP does not enable NodeOUs, hence it can't tell peers apart
+	next := proto.Clone(config).(*cb.Config)
+	addSignaturePolicy(t, next.ChannelGroup.Groups[channelconfig.OrdererGroupKey], "Peers", "OR('SampleOrg.peer')")
+	addSignaturePolicy(t, next.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Groups["SampleOrg"], "Others", "OR('SampleOrg.admin', 'OtherOrg.member')")
+	addSignaturePolicy(t, next.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Groups["SampleOrg"], "Other", "OR('OtherOrg.member')")
+	next.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Policies["Frozen"] = &cb.ConfigPolicy{
+		Policy: &cb.Policy{Type: int32(cb.Policy_SIGNATURE), Value: cauthdsl.MarshaledRejectAllPolicy},
+	}
+	nextBundle, err := channelconfig.NewBundle("foo", next)
+	require.NoError(t, err)
+
+	unsatisfiable := channelconfig.UnsatisfiablePolicies(nextBundle)
+	assert.Len(t, unsatisfiable, 2)
+	assert.EqualError(t, unsatisfiable["/Channel/Orderer/Peers"],
+		"1 out of 1 rules are required, but 0 can be satisfied (rule 0: identity 0 cannot be satisfied: MSP SampleOrg does not enable NodeOUs, so it has no identity of role PEER)")
+	assert.EqualError(t, unsatisfiable["/Channel/Orderer/SampleOrg/Other"],
+		"1 out of 1 rules are required, but 0 can be satisfied (rule 0: identity 0 cannot be satisfied: MSP OtherOrg is not defined)")
+
+	err = channelconfig.ValidateNewPolicies(current, nextBundle)
+	assert.EqualError(t, err, "policies can never be satisfied: "+
+		"/Channel/Orderer/Peers: "+unsatisfiable["/Channel/Orderer/Peers"].Error()+", "+
+		"/Channel/Orderer/SampleOrg/Other: "+unsatisfiable["/Channel/Orderer/SampleOrg/Other"].Error())
+
+	// policies which could already never be satisfied are not reported again
+	assert.NoError(t, channelconfig.ValidateNewPolicies(nextBundle, nextBundle))
+	assert.NoError(t, channelconfig.ValidateNewPolicies(current, current))
+}
+
+func addSignaturePolicy(t *testing.T, group *cb.ConfigGroup, name string, rule string) {
+	policy, err := cauthdsl.FromString(rule)
+	require.NoError(t, err)
+	group.Policies[name] = &cb.ConfigPolicy{
+		ModPolicy: channelconfig.AdminsPolicyKey,
+		Policy:    &cb.Policy{Type: int32(cb.Policy_SIGNATURE), Value: utils.MarshalOrPanic(policy)},
+	}
+}
@@ -102,6 +102,17 @@ func OrdererAddressesValue(addresses []string) *StandardConfigValue {
 	}
 }
 
+// EndorsementMaxSkewValue returns the config definition for the maximum difference allowed between
+// the timestamp of a transaction and the time at which it is ordered. It is a value for the /Channel group.
+func EndorsementMaxSkewValue(maxSkew string) *StandardConfigValue {
+	return &StandardConfigValue{
+		key: EndorsementMaxSkewKey,
+		value: &cb.EndorsementMaxSkew{
+			Duration: maxSkew,
+		},
+	}
+}
+
 // ConsensusTypeValue returns the config definition for the orderer consensus type.
 // It is a value for the /Channel/Orderer group.
 func ConsensusTypeValue(consensusType string, consensusMetadata []byte) *StandardConfigValue {
@@ -116,13 +127,14 @@ func ConsensusTypeValue(consensusType string, consensusMetadata []byte) *Standar
 
 // BatchSizeValue returns the config definition for the orderer batch size.
 // It is a value for the /Channel/Orderer group.
-func BatchSizeValue(maxMessages, absoluteMaxBytes, preferredMaxBytes uint32) *StandardConfigValue {
+func BatchSizeValue(maxMessages, absoluteMaxBytes, preferredMaxBytes, maxTransactionBytes uint32) *StandardConfigValue {
 	return &StandardConfigValue{
 		key: BatchSizeKey,
 		value: &ab.BatchSize{
-			MaxMessageCount:   maxMessages,
-			AbsoluteMaxBytes:  absoluteMaxBytes,
-			PreferredMaxBytes: preferredMaxBytes,
+			MaxMessageCount:     maxMessages,
+			AbsoluteMaxBytes:    absoluteMaxBytes,
+			PreferredMaxBytes:   preferredMaxBytes,
+			MaxTransactionBytes: maxTransactionBytes,
 		},
 	}
 }
@@ -223,3 +235,25 @@ func ACLValues(acls map[string]string) *StandardConfigValue {
 		value: a,
 	}
 }
+
+// RWSetHashingAlgorithmValue returns the config definition for the algorithm used
+// to hash the values of the write-sets. It is a value for the /Channel/Application/.
+func RWSetHashingAlgorithmValue(name string) *StandardConfigValue {
+	return &StandardConfigValue{
+		key: RWSetHashingAlgorithmKey,
+		value: &cb.HashingAlgorithm{
+			Name: name,
+		},
+	}
+}
+
+// TxDedupWindowValue returns the config definition for the number of blocks within which
+// the transactions carrying the same dedup key are duplicates. It is a value for the /Channel/Application/.
+func TxDedupWindowValue(blocks uint64) *StandardConfigValue {
+	return &StandardConfigValue{
+		key: TxDedupWindowKey,
+		value: &pb.TxDedupWindow{
+			Blocks: blocks,
+		},
+	}
+}
@@ -0,0 +1,167 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package checkpoint
+
+import (
+	"bytes"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/util"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// Verify checks that the checkpoint embedded in block directly follows the
+// checkpoint embedded in previous, which is trusted by the caller, and that it
+// is signed according to policy. previous may be the genesis block, which is
+// the root of every chain of checkpoints.
+//
+// The orderers embed a checkpoint in every config block, so the config in
+// effect when block was signed is the one referenced by the previous
+// checkpoint, and policy must be the block validation policy of that config.
+// Once Verify succeeds, the header of block is as trustworthy as the header of
+// previous, and so is the last config block it references.
+func Verify(block, previous *cb.Block, policy policies.Policy) (*cb.Checkpoint, error) {
+	if block == nil || block.Header == nil || previous == nil || previous.Header == nil {
+		return nil, errors.New("missing block header")
+	}
+
+	previousLastConfig := uint64(0)
+	if previous.Header.Number != 0 {
+		previousCheckpoint, err := utils.GetCheckpointFromBlock(previous)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed extracting the previous checkpoint")
+		}
+		if previousCheckpoint == nil {
+			return nil, errors.Errorf("block %d does not carry a checkpoint", previous.Header.Number)
+		}
+		previousLastConfig = previousCheckpoint.LastConfig
+	}
+
+	checkpoint, err := utils.GetCheckpointFromBlock(block)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint == nil {
+		return nil, errors.Errorf("block %d does not carry a checkpoint", block.Header.Number)
+	}
+
+	if checkpoint.PreviousCheckpoint != previous.Header.Number {
+		return nil, errors.Errorf("checkpoint of block %d follows block %d, not block %d",
+			block.Header.Number, checkpoint.PreviousCheckpoint, previous.Header.Number)
+	}
+	if previous.Header.Number >= block.Header.Number {
+		return nil, errors.Errorf("checkpoint of block %d can't follow block %d", block.Header.Number, previous.Header.Number)
+	}
+	if !bytes.Equal(checkpoint.PreviousCheckpointHash, previous.Header.Hash()) {
+		return nil, errors.Errorf("checkpoint of block %d does not match the header hash of block %d", block.Header.Number, previous.Header.Number)
+	}
+	if checkpoint.LastConfig != previousLastConfig && checkpoint.LastConfig != block.Header.Number {
+		return nil, errors.Errorf("checkpoint of block %d references config block %d, which is not covered by a checkpoint",
+			block.Header.Number, checkpoint.LastConfig)
+	}
+
+	metadata, err := utils.GetMetadataFromBlock(block, cb.BlockMetadataIndex_CHECKPOINT)
+	if err != nil {
+		return nil, err
+	}
+	if err := evaluateSignatures(metadata, block.Header, policy); err != nil {
+		return nil, err
+	}
+
+	return checkpoint, nil
+}
+
+// SignState returns the metadata of a checkpoint of the state as of block,
+// whose hash is stateHash, signed by signer over the checkpoint and the header
+// of block as the checkpoints embedded in the blocks are. The state hash does
+// not depend on the peer, so the peers holding the same state may append their
+// signatures to the metadata.
+func SignState(block *cb.Block, stateHash []byte, signer crypto.LocalSigner) (*cb.Metadata, error) {
+	if block == nil || block.Header == nil {
+		return nil, errors.New("missing block header")
+	}
+	lastConfig, err := utils.GetLastConfigIndexFromBlock(block)
+	if err != nil {
+		return nil, err
+	}
+	value, err := proto.Marshal(&cb.Checkpoint{LastConfig: lastConfig, StateHash: stateHash})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshaling the state checkpoint")
+	}
+	sigHeader, err := signer.NewSignatureHeader()
+	if err != nil {
+		return nil, err
+	}
+	sigHeaderBytes, err := proto.Marshal(sigHeader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshaling the signature header")
+	}
+	signature, err := signer.Sign(util.ConcatenateBytes(value, sigHeaderBytes, block.Header.Bytes()))
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed signing the state checkpoint")
+	}
+	return &cb.Metadata{
+		Value:      value,
+		Signatures: []*cb.MetadataSignature{{SignatureHeader: sigHeaderBytes, Signature: signature}},
+	}, nil
+}
+
+// VerifyState checks that metadata, as returned by SignState, commits to the
+// state hash as of block, which is trusted by the caller, and that it is signed
+// according to policy.
+func VerifyState(block *cb.Block, metadata *cb.Metadata, stateHash []byte, policy policies.Policy) (*cb.Checkpoint, error) {
+	if block == nil || block.Header == nil {
+		return nil, errors.New("missing block header")
+	}
+	if metadata == nil {
+		return nil, errors.Errorf("missing state checkpoint of block %d", block.Header.Number)
+	}
+	checkpoint := &cb.Checkpoint{}
+	if err := proto.Unmarshal(metadata.Value, checkpoint); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshaling the state checkpoint")
+	}
+	if len(stateHash) == 0 || !bytes.Equal(checkpoint.StateHash, stateHash) {
+		return nil, errors.Errorf("state checkpoint of block %d does not match the state hash", block.Header.Number)
+	}
+	lastConfig, err := utils.GetLastConfigIndexFromBlock(block)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint.LastConfig != lastConfig {
+		return nil, errors.Errorf("state checkpoint of block %d references config block %d, not %d",
+			block.Header.Number, checkpoint.LastConfig, lastConfig)
+	}
+	if err := evaluateSignatures(metadata, block.Header, policy); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+// evaluateSignatures checks that the signatures of metadata over its value and
+// header satisfy policy
+func evaluateSignatures(metadata *cb.Metadata, header *cb.BlockHeader, policy policies.Policy) error {
+	signatureSet := []*cb.SignedData{}
+	for _, metadataSignature := range metadata.Signatures {
+		shdr, err := utils.GetSignatureHeader(metadataSignature.SignatureHeader)
+		if err != nil {
+			return errors.WithMessage(err, "failed unmarshaling the signature header of the checkpoint")
+		}
+		signatureSet = append(signatureSet, &cb.SignedData{
+			Identity:  shdr.Creator,
+			Data:      util.ConcatenateBytes(metadata.Value, metadataSignature.SignatureHeader, header.Bytes()),
+			Signature: metadataSignature.Signature,
+		})
+	}
+	if err := policy.Evaluate(signatureSet); err != nil {
+		return errors.WithMessage(err, "checkpoint signatures do not satisfy the policy")
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package checkpoint
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	mockcrypto "github.com/hyperledger/fabric/common/mocks/crypto"
+	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
+	"github.com/hyperledger/fabric/common/util"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// hashPolicy accepts the signatures which are the hash of the signed data
+type hashPolicy struct{}
+
+func (hashPolicy) Evaluate(signatureSet []*cb.SignedData) error {
+	if len(signatureSet) == 0 {
+		return errors.New("no signatures")
+	}
+	for _, sd := range signatureSet {
+		digest := sha256.Sum256(sd.Data)
+		if !bytes.Equal(digest[:], sd.Signature) || !bytes.Equal(sd.Identity, []byte("orderer")) {
+			return errors.New("bad signature")
+		}
+	}
+	return nil
+}
+
+func withCheckpoint(number uint64, checkpoint *cb.Checkpoint) *cb.Block {
+	block := cb.NewBlock(number, []byte("previous hash"))
+	sigHeader := utils.MarshalOrPanic(&cb.SignatureHeader{Creator: []byte("orderer")})
+	value := utils.MarshalOrPanic(checkpoint)
+	digest := sha256.Sum256(util.ConcatenateBytes(value, sigHeader, block.Header.Bytes()))
+	block.Metadata.Metadata[cb.BlockMetadataIndex_CHECKPOINT] = utils.MarshalOrPanic(&cb.Metadata{
+		Value: value,
+		Signatures: []*cb.MetadataSignature{
+			{SignatureHeader: sigHeader, Signature: digest[:]},
+		},
+	})
+	return block
+}
+
+func TestVerify(t *testing.T) {
+	genesis := cb.NewBlock(0, nil)
+
+	first := withCheckpoint(10, &cb.Checkpoint{PreviousCheckpoint: 0, PreviousCheckpointHash: genesis.Header.Hash()})
+	checkpoint, err := Verify(first, genesis, hashPolicy{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), checkpoint.LastConfig)
+
+	// config block 15 carries its own checkpoint
+	config := withCheckpoint(15, &cb.Checkpoint{LastConfig: 15, PreviousCheckpoint: 10, PreviousCheckpointHash: first.Header.Hash()})
+	checkpoint, err = Verify(config, first, hashPolicy{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(15), checkpoint.LastConfig)
+
+	second := withCheckpoint(20, &cb.Checkpoint{LastConfig: 15, PreviousCheckpoint: 15, PreviousCheckpointHash: config.Header.Hash()})
+	_, err = Verify(second, config, hashPolicy{})
+	assert.NoError(t, err)
+}
+
+func TestVerifyBadCheckpoints(t *testing.T) {
+	genesis := cb.NewBlock(0, nil)
+	first := withCheckpoint(10, &cb.Checkpoint{PreviousCheckpoint: 0, PreviousCheckpointHash: genesis.Header.Hash()})
+
+	_, err := Verify(nil, genesis, hashPolicy{})
+	assert.EqualError(t, err, "missing block header")
+
+	_, err = Verify(cb.NewBlock(10, nil), genesis, hashPolicy{})
+	assert.EqualError(t, err, "block 10 does not carry a checkpoint")
+
+	_, err = Verify(first, cb.NewBlock(5, nil), hashPolicy{})
+	assert.EqualError(t, err, "block 5 does not carry a checkpoint")
+
+	skipped := withCheckpoint(20, &cb.Checkpoint{PreviousCheckpoint: 10, PreviousCheckpointHash: first.Header.Hash()})
+	_, err = Verify(skipped, genesis, hashPolicy{})
+	assert.EqualError(t, err, "checkpoint of block 20 follows block 10, not block 0")
+
+	backwards := withCheckpoint(10, &cb.Checkpoint{PreviousCheckpoint: 10, PreviousCheckpointHash: first.Header.Hash()})
+	_, err = Verify(backwards, first, hashPolicy{})
+	assert.EqualError(t, err, "checkpoint of block 10 can't follow block 10")
+
+	forged := withCheckpoint(20, &cb.Checkpoint{PreviousCheckpoint: 10, PreviousCheckpointHash: []byte("forged")})
+	_, err = Verify(forged, first, hashPolicy{})
+	assert.EqualError(t, err, "checkpoint of block 20 does not match the header hash of block 10")
+
+	// config block 15 was written without a checkpoint
+	uncovered := withCheckpoint(20, &cb.Checkpoint{LastConfig: 15, PreviousCheckpoint: 10, PreviousCheckpointHash: first.Header.Hash()})
+	_, err = Verify(uncovered, first, hashPolicy{})
+	assert.EqualError(t, err, "checkpoint of block 20 references config block 15, which is not covered by a checkpoint")
+
+	// the header was altered after the checkpoint was signed
+	altered := withCheckpoint(20, &cb.Checkpoint{PreviousCheckpoint: 10, PreviousCheckpointHash: first.Header.Hash()})
+	altered.Header.DataHash = []byte("altered")
+	_, err = Verify(altered, first, hashPolicy{})
+	assert.EqualError(t, err, "checkpoint signatures do not satisfy the policy: bad signature")
+
+	_, err = Verify(first, genesis, &mockpolicies.Policy{Err: errors.New("unauthorized")})
+	assert.EqualError(t, err, "checkpoint signatures do not satisfy the policy: unauthorized")
+}
+
+// echoPolicy accepts the signatures of mockcrypto.LocalSigner, which are the
+// signed data
+type echoPolicy struct{}
+
+func (echoPolicy) Evaluate(signatureSet []*cb.SignedData) error {
+	if len(signatureSet) == 0 {
+		return errors.New("no signatures")
+	}
+	for _, sd := range signatureSet {
+		if !bytes.Equal(sd.Data, sd.Signature) {
+			return errors.New("bad signature")
+		}
+	}
+	return nil
+}
+
+func TestSignState(t *testing.T) {
+	block := cb.NewBlock(20, []byte("previous hash"))
+	block.Metadata.Metadata[cb.BlockMetadataIndex_LAST_CONFIG] = utils.MarshalOrPanic(&cb.Metadata{
+		Value: utils.MarshalOrPanic(&cb.LastConfig{Index: 15}),
+	})
+	stateHash := []byte("state hash")
+
+	metadata, err := SignState(block, stateHash, mockcrypto.FakeLocalSigner)
+	assert.NoError(t, err)
+	checkpoint, err := VerifyState(block, metadata, stateHash, echoPolicy{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(15), checkpoint.LastConfig)
+	assert.Equal(t, stateHash, checkpoint.StateHash)
+
+	_, err = VerifyState(block, nil, stateHash, echoPolicy{})
+	assert.EqualError(t, err, "missing state checkpoint of block 20")
+
+	_, err = VerifyState(block, metadata, []byte("other state"), echoPolicy{})
+	assert.EqualError(t, err, "state checkpoint of block 20 does not match the state hash")
+
+	other := cb.NewBlock(20, []byte("other previous hash"))
+	other.Metadata = block.Metadata
+	_, err = VerifyState(other, metadata, stateHash, echoPolicy{})
+	assert.EqualError(t, err, "checkpoint signatures do not satisfy the policy: bad signature")
+
+	_, err = VerifyState(block, metadata, stateHash, &mockpolicies.Policy{Err: errors.New("unauthorized")})
+	assert.EqualError(t, err, "checkpoint signatures do not satisfy the policy: unauthorized")
+
+	_, err = SignState(nil, stateHash, mockcrypto.FakeLocalSigner)
+	assert.EqualError(t, err, "missing block header")
+}
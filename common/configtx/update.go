@@ -95,7 +95,7 @@ func (vi *ValidatorImpl) verifyDeltaSet(deltaSet map[string]comparable, signedDa
 		}
 
 		// Ensure the policy is satisfied
-		if err := policy.Evaluate(signedData); err != nil {
+		if err := policies.Evaluate(policy, signedData); err != nil {
 			return errors.Wrapf(err, "policy for %s not satisfied", key)
 		}
 	}
@@ -11,11 +11,20 @@ import (
 	"testing"
 
 	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
+	"github.com/hyperledger/fabric/common/policies"
 	cb "github.com/hyperledger/fabric/protos/common"
 
 	"github.com/stretchr/testify/assert"
 )
 
+type traceablePolicy struct {
+	*mockpolicies.Policy
+}
+
+func (tp *traceablePolicy) Trace(signedData []*cb.SignedData) *policies.Trace {
+	return &policies.Trace{Name: "foo policy", Details: []string{"no signatures"}}
+}
+
 func TestReadSetNotPresent(t *testing.T) {
 	vi := &ValidatorImpl{
 		configMap: make(map[string]comparable),
@@ -112,6 +121,21 @@ func TestVerifyDeltaSet(t *testing.T) {
 		assert.Error(t, vi.verifyDeltaSet(deltaSet, nil), "Policy evaluation should have failed")
 	})
 
+	t.Run("Policy evaluation explained", func(t *testing.T) {
+		deltaSet := make(map[string]comparable)
+
+		deltaSet["foo"] = comparable{ConfigValue: &cb.ConfigValue{Version: 1, ModPolicy: "foo"}}
+		// the existing item has no mod policy
+		vi.pm.(*mockpolicies.Manager).PolicyMap = map[string]policies.Policy{
+			"": &traceablePolicy{&mockpolicies.Policy{Err: fmt.Errorf("Err")}},
+		}
+		defer func() { vi.pm.(*mockpolicies.Manager).PolicyMap = nil }()
+
+		err := vi.verifyDeltaSet(deltaSet, nil)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not satisfied: Err: foo policy: not satisfied (no signatures)")
+	})
+
 	t.Run("Empty delta set", func(t *testing.T) {
 		err := (&ValidatorImpl{}).verifyDeltaSet(map[string]comparable{}, nil)
 		assert.Error(t, err, "Empty delta set should be rejected")
@@ -0,0 +1,164 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package pedersen implements the Pedersen commitments to the private values of
+// the collections selecting them, over the P-256 curve.
+//
+// The commitment to a value is mG + rH, where G is the base point of the curve
+// and H a generator whose discrete logarithm relative to G is unknown. The value
+// is encoded into the scalar m as the big-endian integer of its length followed
+// by its bytes, so that the values of different lengths don't collide. Values of
+// at most MaxIntegerSize bytes are thus committed as m = l*256^l + v, where v is
+// the unsigned integer of the l bytes of the value, so that range proofs apply
+// to v, and longer values as the integer of the length byte 32 followed by the
+// first 31 bytes of their SHA256 hash. The blinding factor r is a random
+// scalar chosen by the client, which supplies it to all the endorsers in the
+// transient field of the proposal under TransientKey, and keeps it in order to
+// open the commitment and prove properties of the value in zero knowledge.
+package pedersen
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// CommitmentSize is the size of a commitment, a compressed point of the curve
+	CommitmentSize = 33
+	// MaxIntegerSize is the maximum size of the values committed as integers
+	MaxIntegerSize = 31
+	// BlindingFactorSize is the size of a blinding factor, a big-endian scalar
+	BlindingFactorSize = 32
+
+	// hashedValue is the length byte of the encoding of the hashed values
+	hashedValue = MaxIntegerSize + 1
+
+	generatorDomain = "fabric-pedersen-generator-H"
+	transientPrefix = "pedersen-blinding-factor"
+)
+
+var (
+	curve  = elliptic.P256()
+	hx, hy = generatorH()
+)
+
+// generatorH derives H by hashing a fixed domain with a counter into the
+// abscissa of a point of the curve, so that no one knows its discrete logarithm
+func generatorH() (*big.Int, *big.Int) {
+	for counter := byte(0); ; counter++ {
+		x := sha256.Sum256(append([]byte(generatorDomain), counter))
+		if x, y := unmarshalCompressed(append([]byte{2}, x[:]...)); x != nil {
+			return x, y
+		}
+	}
+}
+
+// Generators returns the generators G and H of the commitments, as compressed points
+func Generators() (g []byte, h []byte) {
+	return marshalCompressed(curve.Params().Gx, curve.Params().Gy), marshalCompressed(hx, hy)
+}
+
+// marshalCompressed returns the SEC 1 compressed form of the given point
+func marshalCompressed(x, y *big.Int) []byte {
+	compressed := make([]byte, CommitmentSize)
+	compressed[0] = byte(2 + y.Bit(0))
+	xBytes := x.Bytes()
+	copy(compressed[CommitmentSize-len(xBytes):], xBytes)
+	return compressed
+}
+
+// unmarshalCompressed returns the point of the given SEC 1 compressed form, or
+// nil if it isn't the compressed form of a point of the curve
+func unmarshalCompressed(compressed []byte) (x, y *big.Int) {
+	if len(compressed) != CommitmentSize || (compressed[0] != 2 && compressed[0] != 3) {
+		return nil, nil
+	}
+	p := curve.Params().P
+	x = new(big.Int).SetBytes(compressed[1:])
+	if x.Cmp(p) >= 0 {
+		return nil, nil
+	}
+	// y^2 = x^3 - 3x + b
+	y2 := new(big.Int).Exp(x, big.NewInt(3), p)
+	y2.Sub(y2, new(big.Int).Mul(x, big.NewInt(3)))
+	y2.Add(y2, curve.Params().B)
+	y2.Mod(y2, p)
+	y = new(big.Int).ModSqrt(y2, p)
+	if y == nil {
+		return nil, nil
+	}
+	if y.Bit(0) != uint(compressed[0]&1) {
+		y.Sub(p, y)
+	}
+	return x, y
+}
+
+// TransientKey returns the key of the transient field of the proposal under
+// which the client supplies the blinding factor of the commitment to the value
+// of the given key of the given collection of the given chaincode
+func TransientKey(chaincodeName, collection, key string) string {
+	return transientPrefix + "/" + chaincodeName + "/" + collection + "/" + key
+}
+
+// Opening returns the scalars m and r of the commitment to the given value with
+// the given blinding factor
+func Opening(value []byte, blindingFactor []byte) (m *big.Int, r *big.Int, err error) {
+	n := curve.Params().N
+	if len(blindingFactor) != BlindingFactorSize {
+		return nil, nil, errors.Errorf("blinding factor of %d bytes instead of %d", len(blindingFactor), BlindingFactorSize)
+	}
+	r = new(big.Int).SetBytes(blindingFactor)
+	if r.Sign() == 0 || r.Cmp(n) >= 0 {
+		return nil, nil, errors.New("blinding factor isn't a non-zero scalar of the P-256 curve")
+	}
+	if len(value) <= MaxIntegerSize {
+		m = new(big.Int).SetBytes(append([]byte{byte(len(value))}, value...))
+	} else {
+		digest := sha256.Sum256(value)
+		m = new(big.Int).SetBytes(append([]byte{hashedValue}, digest[:MaxIntegerSize]...))
+	}
+	return m, r, nil
+}
+
+// CommitScalars returns the commitment mG + rH
+func CommitScalars(m *big.Int, r *big.Int) []byte {
+	n := curve.Params().N
+	mx, my := curve.ScalarBaseMult(new(big.Int).Mod(m, n).Bytes())
+	rx, ry := curve.ScalarMult(hx, hy, new(big.Int).Mod(r, n).Bytes())
+	x, y := curve.Add(mx, my, rx, ry)
+	return marshalCompressed(x, y)
+}
+
+// Commit returns the commitment to the given value with the given blinding factor
+func Commit(value []byte, blindingFactor []byte) ([]byte, error) {
+	m, r, err := Opening(value, blindingFactor)
+	if err != nil {
+		return nil, err
+	}
+	return CommitScalars(m, r), nil
+}
+
+// Verify returns whether the given commitment is the commitment to the given
+// value with the given blinding factor
+func Verify(commitment []byte, value []byte, blindingFactor []byte) bool {
+	expected, err := Commit(value, blindingFactor)
+	return err == nil && bytes.Equal(expected, commitment)
+}
+
+// CheckFormat returns an error if the given commitment isn't a compressed point of the curve
+func CheckFormat(commitment []byte) error {
+	if len(commitment) != CommitmentSize {
+		return errors.Errorf("commitment of %d bytes instead of %d", len(commitment), CommitmentSize)
+	}
+	if x, _ := unmarshalCompressed(commitment); x == nil {
+		return errors.New("commitment isn't a compressed point of the P-256 curve")
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pedersen
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	r1 = bytes.Repeat([]byte{1}, BlindingFactorSize)
+	r2 = bytes.Repeat([]byte{2}, BlindingFactorSize)
+)
+
+func TestCommit(t *testing.T) {
+	c1, err := Commit([]byte("value"), r1)
+	assert.NoError(t, err)
+	assert.Len(t, c1, CommitmentSize)
+	assert.NoError(t, CheckFormat(c1))
+	assert.True(t, Verify(c1, []byte("value"), r1))
+	assert.False(t, Verify(c1, []byte("other value"), r1))
+	assert.False(t, Verify(c1, []byte("value"), r2))
+
+	// the commitments to the same value with different blinding factors differ
+	c2, err := Commit([]byte("value"), r2)
+	assert.NoError(t, err)
+	assert.NotEqual(t, c1, c2)
+	long := bytes.Repeat([]byte{1}, MaxIntegerSize+1)
+	c3, err := Commit(long, r1)
+	assert.NoError(t, err)
+	assert.True(t, Verify(c3, long, r1))
+}
+
+func TestOpening(t *testing.T) {
+	// the value is encoded after its length
+	m, r, err := Opening([]byte{1, 0}, r1)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(2<<16+256), m)
+	assert.Equal(t, new(big.Int).SetBytes(r1), r)
+	c, _ := Commit([]byte{1, 0}, r1)
+	assert.Equal(t, c, CommitScalars(m, r))
+
+	// the commitments are additively homomorphic, which the proofs of the
+	// properties of the committed values rely on
+	m2, s2, err := Opening([]byte{2}, r2)
+	assert.NoError(t, err)
+	sum := CommitScalars(new(big.Int).Add(m, m2), new(big.Int).Add(r, s2))
+	assert.Equal(t, big.NewInt(1<<8+2), m2)
+	assert.Equal(t, CommitScalars(big.NewInt(2<<16+256+1<<8+2), new(big.Int).Add(r, s2)), sum)
+	assert.NotEqual(t, CommitScalars(big.NewInt(2<<16+256+1<<8+1), new(big.Int).Add(r, s2)), sum)
+
+	_, _, err = Opening([]byte("value"), r1[1:])
+	assert.EqualError(t, err, "blinding factor of 31 bytes instead of 32")
+	_, _, err = Opening([]byte("value"), make([]byte, BlindingFactorSize))
+	assert.EqualError(t, err, "blinding factor isn't a non-zero scalar of the P-256 curve")
+	_, _, err = Opening([]byte("value"), bytes.Repeat([]byte{0xff}, BlindingFactorSize))
+	assert.EqualError(t, err, "blinding factor isn't a non-zero scalar of the P-256 curve")
+	assert.False(t, Verify(c, []byte{1, 0}, nil))
+}
+
+func TestOpeningIsInjective(t *testing.T) {
+	long := bytes.Repeat([]byte{1}, MaxIntegerSize+1)
+	digest := sha256.Sum256(long)
+	values := [][]byte{
+		nil,
+		{0},
+		{0, 0},
+		[]byte("a"),
+		[]byte("\x00a"),
+		[]byte("\x01a"),
+		bytes.Repeat([]byte{0xff}, MaxIntegerSize),
+		append([]byte{0}, bytes.Repeat([]byte{0xff}, MaxIntegerSize-1)...),
+		digest[:MaxIntegerSize],
+		long,
+	}
+	scalars := map[string][]byte{}
+	for _, value := range values {
+		m, _, err := Opening(value, r1)
+		assert.NoError(t, err)
+		assert.True(t, m.Cmp(curve.Params().N) < 0)
+		other, found := scalars[m.String()]
+		assert.False(t, found, "%x and %x are committed as the same scalar", value, other)
+		scalars[m.String()] = value
+	}
+
+	c1, _ := Commit([]byte("a"), r1)
+	c2, _ := Commit([]byte("\x00a"), r1)
+	assert.NotEqual(t, c1, c2)
+	assert.False(t, Verify(c1, []byte("\x00a"), r1))
+}
+
+func TestTransientKey(t *testing.T) {
+	assert.Equal(t, "pedersen-blinding-factor/mycc/coll/key", TransientKey("mycc", "coll", "key"))
+}
+
+func TestGenerators(t *testing.T) {
+	g, h := Generators()
+	assert.NoError(t, CheckFormat(g))
+	assert.NoError(t, CheckFormat(h))
+	assert.NotEqual(t, g, h)
+	assert.Equal(t, g, CommitScalars(big.NewInt(1), big.NewInt(0)))
+	assert.Equal(t, h, CommitScalars(big.NewInt(0), big.NewInt(1)))
+}
+
+func TestCompressedPoints(t *testing.T) {
+	params := curve.Params()
+	x, y := unmarshalCompressed(marshalCompressed(params.Gx, params.Gy))
+	assert.Equal(t, params.Gx, x)
+	assert.Equal(t, params.Gy, y)
+
+	// both parities of the ordinate round trip
+	negGy := new(big.Int).Sub(params.P, params.Gy)
+	compressed := marshalCompressed(params.Gx, negGy)
+	assert.NotEqual(t, marshalCompressed(params.Gx, params.Gy)[0], compressed[0])
+	x, y = unmarshalCompressed(compressed)
+	assert.Equal(t, params.Gx, x)
+	assert.Equal(t, negGy, y)
+
+	x, y = unmarshalCompressed(marshalCompressed(hx, hy))
+	assert.Equal(t, hx, x)
+	assert.Equal(t, hy, y)
+	assert.True(t, curve.IsOnCurve(hx, hy))
+
+	// the abscissas must be reduced
+	x, _ = unmarshalCompressed(append([]byte{2}, params.P.Bytes()...))
+	assert.Nil(t, x)
+}
+
+func TestCheckFormat(t *testing.T) {
+	commitment, _ := Commit([]byte("value"), r1)
+	assert.EqualError(t, CheckFormat(make([]byte, 32)), "commitment of 32 bytes instead of 33")
+	assert.EqualError(t, CheckFormat(append([]byte{2}, bytes.Repeat([]byte{0xff}, 32)...)), "commitment isn't a compressed point of the P-256 curve")
+	assert.EqualError(t, CheckFormat(append([]byte{4}, commitment[1:]...)), "commitment isn't a compressed point of the P-256 curve")
+}
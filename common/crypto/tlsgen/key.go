@@ -17,6 +17,7 @@ import (
 	"encoding/pem"
 	"math/big"
 	"net"
+	"strings"
 	"time"
 )
 
@@ -78,7 +79,8 @@ func newCertKeyPair(isCA bool, isServer bool, host string, certSigner crypto.Sig
 	if isServer {
 		template.NotAfter = tenYearsFromNow
 		template.ExtKeyUsage = append(template.ExtKeyUsage, x509.ExtKeyUsageServerAuth)
-		if ip := net.ParseIP(host); ip != nil {
+		// IPv6 addresses may be enclosed in brackets, e.g., [::1]
+		if ip := net.ParseIP(strings.Trim(host, "[]")); ip != nil {
 			template.IPAddresses = append(template.IPAddresses, ip)
 		} else {
 			template.DNSNames = append(template.DNSNames, host)
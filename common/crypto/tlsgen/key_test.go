@@ -10,6 +10,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
+	"net"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -38,3 +39,17 @@ func TestLoadCert(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, cert)
 }
+
+func TestServerCertIPv6(t *testing.T) {
+	for _, host := range []string{"::1", "[::1]"} {
+		pair, err := newCertKeyPair(false, true, host, nil, nil)
+		assert.NoError(t, err)
+		block, _ := pem.Decode(pair.Cert)
+		cert, err := x509.ParseCertificate(block.Bytes)
+		assert.NoError(t, err)
+		assert.Empty(t, cert.DNSNames)
+		assert.Len(t, cert.IPAddresses, 1)
+		assert.True(t, net.ParseIP("::1").Equal(cert.IPAddresses[0]))
+		assert.NoError(t, cert.VerifyHostname("[::1]"))
+	}
+}
@@ -0,0 +1,33 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliver
+
+import (
+	"context"
+
+	"github.com/hyperledger/fabric/core/comm"
+	cb "github.com/hyperledger/fabric/protos/common"
+)
+
+// IdentityBinder is implemented by the chains which may require the deliver
+// requests to carry, in their signed channel header, the hash of the TLS client
+// certificate of their connection, so that a signed deliver request can't be
+// replayed over the connection of another client.
+type IdentityBinder interface {
+	// DeliverIdentityBinding returns whether the deliver requests must be bound
+	// to the TLS client certificate of their connection.
+	DeliverIdentityBinding() bool
+}
+
+// CheckIdentityBinding returns an error unless the TlsCertHash of the given
+// channel header, which is covered by the signature of the request, is the hash
+// of the TLS client certificate of the connection of the given context. Unlike
+// the BindingInspector of the Handler, it applies whether or not the server is
+// configured to require mutual TLS.
+func CheckIdentityBinding(ctx context.Context, chdr *cb.ChannelHeader) error {
+	return comm.NewBindingInspector(true, ExtractChannelHeaderCertHash)(ctx, chdr)
+}
@@ -0,0 +1,178 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliver_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/hyperledger/fabric/common/deliver"
+	"github.com/hyperledger/fabric/common/deliver/mock"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// selfSignedCert returns a self-signed TLS client certificate issued to the given common name
+func selfSignedCert(commonName string) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).NotTo(HaveOccurred())
+	return cert
+}
+
+func certHash(cert *x509.Certificate) []byte {
+	hash := sha256.Sum256(cert.Raw)
+	return hash[:]
+}
+
+// tlsContext returns the context of a stream whose client sent the given TLS certificate
+func tlsContext(cert *x509.Certificate) context.Context {
+	state := tls.ConnectionState{}
+	if cert != nil {
+		state.PeerCertificates = []*x509.Certificate{cert}
+	}
+	return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+}
+
+// bindingChain is a chain which may require deliver identity binding
+type bindingChain struct {
+	*mock.Chain
+	binding bool
+}
+
+func (c *bindingChain) DeliverIdentityBinding() bool {
+	return c.binding
+}
+
+var _ = Describe("CheckIdentityBinding", func() {
+	var (
+		aliceCert, bobCert *x509.Certificate
+		chdr               *cb.ChannelHeader
+	)
+
+	BeforeEach(func() {
+		aliceCert = selfSignedCert("alice")
+		bobCert = selfSignedCert("bob")
+		chdr = &cb.ChannelHeader{TlsCertHash: certHash(aliceCert)}
+	})
+
+	It("accepts the TLS certificate whose hash is in the channel header", func() {
+		err := deliver.CheckIdentityBinding(tlsContext(aliceCert), chdr)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("rejects a connection without a TLS certificate", func() {
+		err := deliver.CheckIdentityBinding(tlsContext(nil), chdr)
+		Expect(err).To(MatchError("client didn't send a TLS certificate"))
+		err = deliver.CheckIdentityBinding(context.Background(), chdr)
+		Expect(err).To(MatchError("client didn't send a TLS certificate"))
+	})
+
+	It("rejects a channel header without a TLS cert hash", func() {
+		err := deliver.CheckIdentityBinding(tlsContext(aliceCert), &cb.ChannelHeader{})
+		Expect(err).To(MatchError("client didn't include its TLS cert hash"))
+	})
+
+	It("rejects the TLS certificate of another client", func() {
+		err := deliver.CheckIdentityBinding(tlsContext(bobCert), chdr)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix("claimed TLS cert hash is"))
+	})
+
+	Describe("when delivering", func() {
+		var (
+			chain              *bindingChain
+			fakeResponseSender *mock.ResponseSender
+			handler            *deliver.Handler
+			server             *deliver.Server
+		)
+
+		BeforeEach(func() {
+			fakeChain := &mock.Chain{}
+			fakeChain.ErroredReturns(make(chan struct{}))
+			fakeBlockIterator := &mock.BlockIterator{}
+			fakeBlockIterator.NextReturns(&cb.Block{Header: &cb.BlockHeader{Number: 0}}, cb.Status_SUCCESS)
+			fakeBlockReader := &mock.BlockReader{}
+			fakeBlockReader.HeightReturns(1)
+			fakeBlockReader.IteratorReturns(fakeBlockIterator, 0)
+			fakeChain.ReaderReturns(fakeBlockReader)
+			chain = &bindingChain{Chain: fakeChain, binding: true}
+
+			fakeChainManager := &mock.ChainManager{}
+			fakeChainManager.GetChainReturns(chain, true)
+			handler = &deliver.Handler{
+				ChainManager:     fakeChainManager,
+				TimeWindow:       time.Second,
+				BindingInspector: &mock.Inspector{},
+			}
+
+			env, err := utils.CreateSignedEnvelopeWithTLSBinding(cb.HeaderType_DELIVER_SEEK_INFO, "chain-id", nil, &ab.SeekInfo{Start: seekOldest, Stop: seekOldest}, 0, 0, certHash(aliceCert))
+			Expect(err).NotTo(HaveOccurred())
+
+			fakeReceiver := &mock.Receiver{}
+			fakeReceiver.RecvReturnsOnCall(0, env, nil)
+			fakeReceiver.RecvReturnsOnCall(1, nil, io.EOF)
+			fakeResponseSender = &mock.ResponseSender{}
+			server = &deliver.Server{
+				Receiver:       fakeReceiver,
+				PolicyChecker:  &mock.PolicyChecker{},
+				ResponseSender: fakeResponseSender,
+			}
+		})
+
+		It("delivers the blocks over the connection the request is bound to", func() {
+			err := handler.Handle(tlsContext(aliceCert), server)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(1))
+			Expect(fakeResponseSender.SendStatusResponseArgsForCall(0)).To(Equal(cb.Status_SUCCESS))
+		})
+
+		It("rejects the request over a connection of another client", func() {
+			err := handler.Handle(tlsContext(bobCert), server)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(0))
+			Expect(fakeResponseSender.SendStatusResponseArgsForCall(0)).To(Equal(cb.Status_FORBIDDEN))
+		})
+
+		Context("when the chain doesn't require the binding", func() {
+			BeforeEach(func() {
+				chain.binding = false
+			})
+
+			It("delivers the blocks over any connection", func() {
+				err := handler.Handle(tlsContext(nil), server)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(1))
+			})
+		})
+	})
+})
@@ -0,0 +1,44 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliver
+
+import (
+	"github.com/golang/protobuf/proto"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/pkg/errors"
+)
+
+// CheckpointAfter returns the opaque checkpoint token which resumes a deliver
+// on the given channel right after the block with the given number.
+func CheckpointAfter(channelID string, blockNumber uint64) []byte {
+	token, err := proto.Marshal(&ab.DeliverCheckpoint{
+		ChannelId:   channelID,
+		BlockNumber: blockNumber + 1,
+	})
+	if err != nil {
+		// marshaling a message made only of scalar fields cannot fail
+		panic(err)
+	}
+	return token
+}
+
+// resolveCheckpoint converts a checkpoint seek position into the specified
+// seek position it records, after checking it was issued for the given channel.
+func resolveCheckpoint(checkpoint *ab.SeekCheckpoint, channelID string) (*ab.SeekPosition, error) {
+	cp := &ab.DeliverCheckpoint{}
+	if err := proto.Unmarshal(checkpoint.Token, cp); err != nil {
+		return nil, errors.Wrap(err, "malformed checkpoint token")
+	}
+	if cp.ChannelId != channelID {
+		return nil, errors.Errorf("checkpoint token was issued for channel %s", cp.ChannelId)
+	}
+	return &ab.SeekPosition{
+		Type: &ab.SeekPosition_Specified{
+			Specified: &ab.SeekSpecified{Number: cp.BlockNumber},
+		},
+	}, nil
+}
@@ -10,6 +10,7 @@ import (
 	"context"
 	"io"
 	"math"
+	"sync"
 	"time"
 
 	"github.com/golang/protobuf/proto"
@@ -17,6 +18,7 @@ import (
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/replay"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/comm"
 	cb "github.com/hyperledger/fabric/protos/common"
@@ -89,6 +91,37 @@ type Handler struct {
 	ChainManager     ChainManager
 	TimeWindow       time.Duration
 	BindingInspector Inspector
+	// ReplayCache, when set, rejects the seek requests which were already received
+	ReplayCache *replay.Cache
+	// StreamQuota, when set, bounds the number of deliver requests of each
+	// channel served concurrently
+	StreamQuota StreamQuota
+	// Closing, when set, is closed when the server shuts down. The deliver
+	// requests being served are then ended, and the new ones rejected, with
+	// SERVICE_UNAVAILABLE so that the clients retry against another server
+	Closing <-chan struct{}
+	// MaxStreamsPerIdentity, when not zero, bounds the number of deliver
+	// requests of each client identity served concurrently, so that a client
+	// opening many streams can't make the server buffer blocks for all of them
+	MaxStreamsPerIdentity int
+	// BlocksInFlight is the number of blocks of a deliver request which may be
+	// read from the ledger ahead of their delivery to the client. It bounds the
+	// blocks buffered for each stream of a slow client, with a default of 1
+	BlocksInFlight int
+
+	streamsLock     sync.Mutex
+	identityStreams map[string]int
+}
+
+//go:generate counterfeiter -o mock/stream_quota.go -fake-name StreamQuota . StreamQuota
+
+// StreamQuota bounds the number of deliver requests of a channel served
+// concurrently.
+type StreamQuota interface {
+	// AcquireDeliverStream reserves a slot for a deliver request of the
+	// channel, which is freed by calling the returned function, or returns
+	// an error if the channel has no slot left
+	AcquireDeliverStream(channelID string) (func(), error)
 }
 
 //go:generate counterfeiter -o mock/receiver.go -fake-name Receiver . Receiver
@@ -103,8 +136,18 @@ type Receiver interface {
 // ResponseSender defines the interface a handler must implement to send
 // responses.
 type ResponseSender interface {
+	// SendStatusResponse sends completion status to the client.
 	SendStatusResponse(status cb.Status) error
-	SendBlockResponse(block *cb.Block) error
+	// SendBlockResponse sends the block and optionally private data to the client.
+	SendBlockResponse(block *cb.Block, channelID string, chain Chain, signedData *cb.SignedData) error
+}
+
+// RequestPreparer is implemented by the response senders which need to
+// inspect each seek request before any block is delivered for it.
+type RequestPreparer interface {
+	// PrepareRequest is called once for every seek request, an error rejects
+	// the request with a BAD_REQUEST status.
+	PrepareRequest(signedData *cb.SignedData) error
 }
 
 // Server is a polymorphic structure to support generalization of this handler
@@ -196,6 +239,9 @@ func (h *Handler) deliverBlocks(ctx context.Context, srv *Server, envelope *cb.E
 	case <-erroredChan:
 		logger.Warningf("[channel: %s] Rejecting deliver request for %s because of consenter error", chdr.ChannelId, addr)
 		return srv.SendStatusResponse(cb.Status_SERVICE_UNAVAILABLE)
+	case <-h.Closing:
+		logger.Debugf("[channel: %s] Rejecting deliver request for %s because the server is shutting down", chdr.ChannelId, addr)
+		return srv.SendStatusResponse(cb.Status_SERVICE_UNAVAILABLE)
 	default:
 
 	}
@@ -211,6 +257,42 @@ func (h *Handler) deliverBlocks(ctx context.Context, srv *Server, envelope *cb.E
 		return srv.SendStatusResponse(cb.Status_FORBIDDEN)
 	}
 
+	if h.ReplayCache != nil {
+		if err := h.ReplayCache.Check(envelope); err != nil {
+			logger.Warningf("[channel: %s] Rejecting replayed deliver request from %s: %s", chdr.ChannelId, addr, err)
+			return srv.SendStatusResponse(cb.Status_BAD_REQUEST)
+		}
+	}
+
+	signedData, err := envelope.AsSignedData()
+	if err != nil {
+		logger.Warningf("[channel: %s] Received a deliver request from %s that could not be converted to signed data: %s", chdr.ChannelId, addr, err)
+		return srv.SendStatusResponse(cb.Status_BAD_REQUEST)
+	}
+
+	if binder, ok := chain.(IdentityBinder); ok && binder.DeliverIdentityBinding() {
+		if err := CheckIdentityBinding(ctx, chdr); err != nil {
+			logger.Warningf("[channel: %s] Rejecting deliver request from %s which isn't bound to the TLS certificate of its connection: %s", chdr.ChannelId, addr, err)
+			return srv.SendStatusResponse(cb.Status_FORBIDDEN)
+		}
+	}
+
+	if h.StreamQuota != nil {
+		release, err := h.StreamQuota.AcquireDeliverStream(chdr.ChannelId)
+		if err != nil {
+			logger.Warningf("[channel: %s] Rejecting deliver request from %s: %s", chdr.ChannelId, addr, err)
+			return srv.SendStatusResponse(cb.Status_SERVICE_UNAVAILABLE)
+		}
+		defer release()
+	}
+
+	releaseIdentity, err := h.acquireIdentityStream(signedData[0].Identity)
+	if err != nil {
+		logger.Warningf("[channel: %s] Rejecting deliver request from %s: %s", chdr.ChannelId, addr, err)
+		return srv.SendStatusResponse(cb.Status_SERVICE_UNAVAILABLE)
+	}
+	defer releaseIdentity()
+
 	seekInfo := &ab.SeekInfo{}
 	if err = proto.Unmarshal(payload.Data, seekInfo); err != nil {
 		logger.Warningf("[channel: %s] Received a signed deliver request from %s with malformed seekInfo payload: %s", chdr.ChannelId, addr, err)
@@ -222,6 +304,25 @@ func (h *Handler) deliverBlocks(ctx context.Context, srv *Server, envelope *cb.E
 		return srv.SendStatusResponse(cb.Status_BAD_REQUEST)
 	}
 
+	if checkpoint := seekInfo.Start.GetCheckpoint(); checkpoint != nil {
+		if seekInfo.Start, err = resolveCheckpoint(checkpoint, chdr.ChannelId); err != nil {
+			logger.Warningf("[channel: %s] Received seekInfo message from %s with invalid checkpoint start: %s", chdr.ChannelId, addr, err)
+			return srv.SendStatusResponse(cb.Status_BAD_REQUEST)
+		}
+	}
+
+	if seekInfo.Stop.GetCheckpoint() != nil {
+		logger.Warningf("[channel: %s] Received seekInfo message from %s with checkpoint stop", chdr.ChannelId, addr)
+		return srv.SendStatusResponse(cb.Status_BAD_REQUEST)
+	}
+
+	if preparer, ok := srv.ResponseSender.(RequestPreparer); ok {
+		if err := preparer.PrepareRequest(signedData[0]); err != nil {
+			logger.Warningf("[channel: %s] Rejecting deliver request from %s: %s", chdr.ChannelId, addr, err)
+			return srv.SendStatusResponse(cb.Status_BAD_REQUEST)
+		}
+	}
+
 	logger.Debugf("[channel: %s] Received seekInfo (%p) %v from %s", chdr.ChannelId, seekInfo, seekInfo, addr)
 
 	cursor, number := chain.Reader().Iterator(seekInfo.Start)
@@ -240,10 +341,23 @@ func (h *Handler) deliverBlocks(ctx context.Context, srv *Server, envelope *cb.E
 		}
 	}
 
+	sender := newBlockSender(h.BlocksInFlight, func(block *cb.Block) error {
+		return srv.SendBlockResponse(block, chdr.ChannelId, chain, signedData[0])
+	})
+	defer sender.flush()
+
+	// the blocks handed over to the sender are sent ahead of the status
+	sendStatusResponse := func(status cb.Status) error {
+		if err := sender.flush(); err != nil {
+			return err
+		}
+		return srv.SendStatusResponse(status)
+	}
+
 	for {
 		if seekInfo.Behavior == ab.SeekInfo_FAIL_IF_NOT_READY {
 			if number > chain.Reader().Height()-1 {
-				return srv.SendStatusResponse(cb.Status_NOT_FOUND)
+				return sendStatusResponse(cb.Status_NOT_FOUND)
 			}
 		}
 
@@ -262,14 +376,17 @@ func (h *Handler) deliverBlocks(ctx context.Context, srv *Server, envelope *cb.E
 			return errors.Wrapf(ctx.Err(), "context finished before block retrieved")
 		case <-erroredChan:
 			logger.Warningf("Aborting deliver for request because of background error")
-			return srv.SendStatusResponse(cb.Status_SERVICE_UNAVAILABLE)
+			return sendStatusResponse(cb.Status_SERVICE_UNAVAILABLE)
+		case <-h.Closing:
+			logger.Debugf("[channel: %s] Ending deliver for %s because the server is shutting down", chdr.ChannelId, addr)
+			return sendStatusResponse(cb.Status_SERVICE_UNAVAILABLE)
 		case <-iterCh:
 			// Iterator has set the block and status vars
 		}
 
 		if status != cb.Status_SUCCESS {
 			logger.Errorf("[channel: %s] Error reading from channel, cause was: %v", chdr.ChannelId, status)
-			return srv.SendStatusResponse(status)
+			return sendStatusResponse(status)
 		}
 
 		// increment block number to support FAIL_IF_NOT_READY deliver behavior
@@ -277,12 +394,12 @@ func (h *Handler) deliverBlocks(ctx context.Context, srv *Server, envelope *cb.E
 
 		if err := accessControl.Evaluate(); err != nil {
 			logger.Warningf("[channel: %s] Client authorization revoked for deliver request from %s: %s", chdr.ChannelId, addr, err)
-			return srv.SendStatusResponse(cb.Status_FORBIDDEN)
+			return sendStatusResponse(cb.Status_FORBIDDEN)
 		}
 
 		logger.Debugf("[channel: %s] Delivering block for (%p) for %s", chdr.ChannelId, seekInfo, addr)
 
-		if err := srv.SendBlockResponse(block); err != nil {
+		if err := sender.send(block); err != nil {
 			logger.Warningf("[channel: %s] Error sending to %s: %s", chdr.ChannelId, addr, err)
 			return err
 		}
@@ -292,7 +409,7 @@ func (h *Handler) deliverBlocks(ctx context.Context, srv *Server, envelope *cb.E
 		}
 	}
 
-	if err := srv.SendStatusResponse(cb.Status_SUCCESS); err != nil {
+	if err := sendStatusResponse(cb.Status_SUCCESS); err != nil {
 		logger.Warningf("[channel: %s] Error sending to %s: %s", chdr.ChannelId, addr, err)
 		return err
 	}
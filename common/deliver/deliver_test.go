@@ -15,6 +15,7 @@ import (
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/hyperledger/fabric/common/deliver"
 	"github.com/hyperledger/fabric/common/deliver/mock"
+	"github.com/hyperledger/fabric/common/replay"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	"github.com/hyperledger/fabric/common/util"
 	cb "github.com/hyperledger/fabric/protos/common"
@@ -184,6 +185,23 @@ var _ = Describe("Deliver", func() {
 			}
 		})
 
+		It("passes the channel, chain and signed data of the request to the response sender", func() {
+			err := handler.Handle(context.Background(), server)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(1))
+			b, channelID, chain, signedData := fakeResponseSender.SendBlockResponseArgsForCall(0)
+			Expect(b).To(Equal(&cb.Block{
+				Header: &cb.BlockHeader{Number: 100},
+			}))
+			Expect(channelID).To(Equal("chain-id"))
+			Expect(chain).To(Equal(fakeChain))
+			Expect(signedData).To(Equal(&cb.SignedData{
+				Data:      envelope.Payload,
+				Signature: envelope.Signature,
+			}))
+		})
+
 		It("validates the channel header with the binding inspector", func() {
 			err := handler.Handle(context.Background(), server)
 			Expect(err).NotTo(HaveOccurred())
@@ -266,7 +284,7 @@ var _ = Describe("Deliver", func() {
 
 				Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(5))
 				for i := 0; i < 5; i++ {
-					b := fakeResponseSender.SendBlockResponseArgsForCall(i)
+					b, _, _, _ := fakeResponseSender.SendBlockResponseArgsForCall(i)
 					Expect(b).To(Equal(&cb.Block{
 						Header: &cb.BlockHeader{Number: 995 + uint64(i)},
 					}))
@@ -289,7 +307,7 @@ var _ = Describe("Deliver", func() {
 				Expect(fakeBlockIterator.NextCallCount()).To(Equal(1))
 
 				Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(1))
-				b := fakeResponseSender.SendBlockResponseArgsForCall(0)
+				b, _, _, _ := fakeResponseSender.SendBlockResponseArgsForCall(0)
 				Expect(b).To(Equal(&cb.Block{
 					Header: &cb.BlockHeader{Number: 100},
 				}))
@@ -320,7 +338,7 @@ var _ = Describe("Deliver", func() {
 				Expect(fakeBlockIterator.NextCallCount()).To(Equal(2))
 				Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(2))
 				for i := 0; i < fakeResponseSender.SendBlockResponseCallCount(); i++ {
-					b := fakeResponseSender.SendBlockResponseArgsForCall(i)
+					b, _, _, _ := fakeResponseSender.SendBlockResponseArgsForCall(i)
 					Expect(b).To(Equal(&cb.Block{
 						Header: &cb.BlockHeader{Number: uint64(i + 1)},
 					}))
@@ -451,6 +469,177 @@ var _ = Describe("Deliver", func() {
 			})
 		})
 
+		Context("when a replay cache is set", func() {
+			BeforeEach(func() {
+				handler.ReplayCache = replay.NewCache(time.Second, time.Second, 10)
+				envelope.Payload = utils.MarshalOrPanic(&cb.Payload{
+					Header: &cb.Header{
+						ChannelHeader:   utils.MarshalOrPanic(channelHeader),
+						SignatureHeader: utils.MarshalOrPanic(&cb.SignatureHeader{Nonce: []byte("nonce")}),
+					},
+					Data: utils.MarshalOrPanic(seekInfo),
+				})
+				fakeReceiver.RecvReturnsOnCall(1, envelope, nil)
+				fakeReceiver.RecvReturnsOnCall(2, nil, io.EOF)
+			})
+
+			It("rejects the replayed requests", func() {
+				err := handler.Handle(context.Background(), server)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(1))
+				Expect(fakeResponseSender.SendStatusResponseCallCount()).To(Equal(2))
+				Expect(fakeResponseSender.SendStatusResponseArgsForCall(0)).To(Equal(cb.Status_SUCCESS))
+				Expect(fakeResponseSender.SendStatusResponseArgsForCall(1)).To(Equal(cb.Status_BAD_REQUEST))
+			})
+		})
+
+		Context("when a stream quota is set", func() {
+			var (
+				fakeStreamQuota *mock.StreamQuota
+				released        int
+			)
+
+			BeforeEach(func() {
+				released = 0
+				fakeStreamQuota = &mock.StreamQuota{}
+				fakeStreamQuota.AcquireDeliverStreamReturns(func() { released++ }, nil)
+				handler.StreamQuota = fakeStreamQuota
+			})
+
+			It("holds a slot of the channel while serving the request", func() {
+				err := handler.Handle(context.Background(), server)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeStreamQuota.AcquireDeliverStreamCallCount()).To(Equal(1))
+				Expect(fakeStreamQuota.AcquireDeliverStreamArgsForCall(0)).To(Equal("chain-id"))
+				Expect(released).To(Equal(1))
+				Expect(fakeResponseSender.SendStatusResponseArgsForCall(0)).To(Equal(cb.Status_SUCCESS))
+			})
+
+			Context("when the channel has no slot left", func() {
+				BeforeEach(func() {
+					fakeStreamQuota.AcquireDeliverStreamReturns(nil, errors.New("quota-exceeded"))
+				})
+
+				It("sends status service unavailable", func() {
+					err := handler.Handle(context.Background(), server)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(0))
+					Expect(fakeResponseSender.SendStatusResponseCallCount()).To(Equal(1))
+					Expect(fakeResponseSender.SendStatusResponseArgsForCall(0)).To(Equal(cb.Status_SERVICE_UNAVAILABLE))
+				})
+			})
+		})
+
+		Context("when the streams per identity are limited", func() {
+			var (
+				sending chan struct{}
+				unblock chan struct{}
+			)
+
+			BeforeEach(func() {
+				handler.MaxStreamsPerIdentity = 1
+				sending = make(chan struct{})
+				unblock = make(chan struct{})
+				fakeResponseSender.SendBlockResponseStub = func(*cb.Block, string, deliver.Chain, *cb.SignedData) error {
+					close(sending)
+					<-unblock
+					return nil
+				}
+			})
+
+			It("rejects the requests of the identity beyond its maximum until a stream ends", func() {
+				errC := make(chan error, 1)
+				go func() { errC <- handler.Handle(context.Background(), server) }()
+				Eventually(sending).Should(BeClosed())
+
+				secondReceiver := &mock.Receiver{}
+				secondReceiver.RecvReturns(envelope, nil)
+				secondReceiver.RecvReturnsOnCall(1, nil, io.EOF)
+				secondSender := &mock.ResponseSender{}
+				second := &deliver.Server{
+					Receiver:       secondReceiver,
+					PolicyChecker:  fakePolicyChecker,
+					ResponseSender: secondSender,
+				}
+				err := handler.Handle(context.Background(), second)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(secondSender.SendBlockResponseCallCount()).To(Equal(0))
+				Expect(secondSender.SendStatusResponseCallCount()).To(Equal(1))
+				Expect(secondSender.SendStatusResponseArgsForCall(0)).To(Equal(cb.Status_SERVICE_UNAVAILABLE))
+
+				close(unblock)
+				Eventually(errC).Should(Receive(BeNil()))
+				Expect(fakeResponseSender.SendStatusResponseArgsForCall(0)).To(Equal(cb.Status_SUCCESS))
+
+				secondReceiver.RecvReturnsOnCall(2, envelope, nil)
+				secondReceiver.RecvReturnsOnCall(3, nil, io.EOF)
+				err = handler.Handle(context.Background(), second)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(secondSender.SendBlockResponseCallCount()).To(Equal(1))
+				Expect(secondSender.SendStatusResponseArgsForCall(1)).To(Equal(cb.Status_SUCCESS))
+			})
+		})
+
+		Context("when several blocks are allowed in flight", func() {
+			BeforeEach(func() {
+				handler.BlocksInFlight = 3
+				seekInfo.Stop.GetSpecified().Number = 109
+				number := uint64(100)
+				fakeBlockIterator.NextStub = func() (*cb.Block, cb.Status) {
+					block := &cb.Block{Header: &cb.BlockHeader{Number: number}}
+					number++
+					return block, cb.Status_SUCCESS
+				}
+			})
+
+			It("sends the blocks in order before the status", func() {
+				err := handler.Handle(context.Background(), server)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(10))
+				for i := 0; i < 10; i++ {
+					b, _, _, _ := fakeResponseSender.SendBlockResponseArgsForCall(i)
+					Expect(b.Header.Number).To(Equal(uint64(100 + i)))
+				}
+				Expect(fakeResponseSender.SendStatusResponseCallCount()).To(Equal(1))
+				Expect(fakeResponseSender.SendStatusResponseArgsForCall(0)).To(Equal(cb.Status_SUCCESS))
+			})
+
+			It("stops reading blocks once the window is full", func() {
+				unblock := make(chan struct{})
+				fakeResponseSender.SendBlockResponseStub = func(*cb.Block, string, deliver.Chain, *cb.SignedData) error {
+					<-unblock
+					return nil
+				}
+				errC := make(chan error, 1)
+				go func() { errC <- handler.Handle(context.Background(), server) }()
+
+				Eventually(fakeBlockIterator.NextCallCount).Should(Equal(3))
+				Consistently(fakeBlockIterator.NextCallCount).Should(Equal(3))
+
+				close(unblock)
+				Eventually(errC).Should(Receive(BeNil()))
+				Expect(fakeBlockIterator.NextCallCount()).To(Equal(10))
+			})
+
+			Context("when sending a block fails", func() {
+				BeforeEach(func() {
+					fakeResponseSender.SendBlockResponseReturns(errors.New("send-fails"))
+				})
+
+				It("returns the error without sending a status", func() {
+					err := handler.Handle(context.Background(), server)
+					Expect(err).To(MatchError("send-fails"))
+
+					Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(1))
+					Expect(fakeResponseSender.SendStatusResponseCallCount()).To(Equal(0))
+				})
+			})
+		})
+
 		Context("when the channel is not found", func() {
 			BeforeEach(func() {
 				fakeChainManager.GetChainReturns(nil, false)
@@ -509,6 +698,53 @@ var _ = Describe("Deliver", func() {
 			})
 		})
 
+		Context("when the server is closing before reading from the chain", func() {
+			BeforeEach(func() {
+				closing := make(chan struct{})
+				close(closing)
+				handler.Closing = closing
+			})
+
+			It("sends status service unavailable", func() {
+				err := handler.Handle(context.Background(), server)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeChain.ReaderCallCount()).To(Equal(0))
+				Expect(fakeResponseSender.SendStatusResponseCallCount()).To(Equal(1))
+				resp := fakeResponseSender.SendStatusResponseArgsForCall(0)
+				Expect(resp).To(Equal(cb.Status_SERVICE_UNAVAILABLE))
+			})
+		})
+
+		Context("when the server is closing while waiting for the next block", func() {
+			var done chan struct{}
+
+			BeforeEach(func() {
+				closing := make(chan struct{})
+				handler.Closing = closing
+				done = make(chan struct{})
+				fakeBlockIterator.NextStub = func() (*cb.Block, cb.Status) {
+					close(closing)
+					<-done
+					return nil, cb.Status_BAD_REQUEST
+				}
+			})
+
+			AfterEach(func() {
+				close(done)
+			})
+
+			It("sends status service unavailable", func() {
+				err := handler.Handle(context.Background(), server)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(0))
+				Expect(fakeResponseSender.SendStatusResponseCallCount()).To(Equal(1))
+				resp := fakeResponseSender.SendStatusResponseArgsForCall(0)
+				Expect(resp).To(Equal(cb.Status_SERVICE_UNAVAILABLE))
+			})
+		})
+
 		Context("when the chain errors while reading from the chain", func() {
 			BeforeEach(func() {
 				fakeChain.ReaderStub = func() blockledger.Reader {
@@ -613,6 +849,84 @@ var _ = Describe("Deliver", func() {
 			})
 		})
 
+		Context("when seek info starts from a checkpoint", func() {
+			var seekFromCheckpoint = func(token []byte) *ab.SeekInfo {
+				return &ab.SeekInfo{
+					Start: &ab.SeekPosition{
+						Type: &ab.SeekPosition_Checkpoint{Checkpoint: &ab.SeekCheckpoint{Token: token}},
+					},
+					Stop: seekNewest,
+				}
+			}
+
+			BeforeEach(func() {
+				fakeBlockReader.HeightReturns(101)
+				seekInfo = seekFromCheckpoint(deliver.CheckpointAfter("chain-id", 99))
+			})
+
+			It("gets a block iterator from the block after the checkpoint", func() {
+				err := handler.Handle(context.Background(), server)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeBlockReader.IteratorCallCount()).To(Equal(1))
+				start := fakeBlockReader.IteratorArgsForCall(0)
+				Expect(proto.Equal(start, &ab.SeekPosition{
+					Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: 100}},
+				})).To(BeTrue())
+			})
+
+			Context("when the checkpoint was issued for another channel", func() {
+				BeforeEach(func() {
+					seekInfo = seekFromCheckpoint(deliver.CheckpointAfter("another-chain-id", 99))
+				})
+
+				It("sends status bad request", func() {
+					err := handler.Handle(context.Background(), server)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeBlockReader.IteratorCallCount()).To(Equal(0))
+					Expect(fakeResponseSender.SendStatusResponseCallCount()).To(Equal(1))
+					resp := fakeResponseSender.SendStatusResponseArgsForCall(0)
+					Expect(resp).To(Equal(cb.Status_BAD_REQUEST))
+				})
+			})
+
+			Context("when the checkpoint token is malformed", func() {
+				BeforeEach(func() {
+					seekInfo = seekFromCheckpoint([]byte("garbage"))
+				})
+
+				It("sends status bad request", func() {
+					err := handler.Handle(context.Background(), server)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeResponseSender.SendStatusResponseCallCount()).To(Equal(1))
+					resp := fakeResponseSender.SendStatusResponseArgsForCall(0)
+					Expect(resp).To(Equal(cb.Status_BAD_REQUEST))
+				})
+			})
+		})
+
+		Context("when seek info stops at a checkpoint", func() {
+			BeforeEach(func() {
+				seekInfo = &ab.SeekInfo{
+					Start: seekOldest,
+					Stop: &ab.SeekPosition{
+						Type: &ab.SeekPosition_Checkpoint{Checkpoint: &ab.SeekCheckpoint{Token: deliver.CheckpointAfter("chain-id", 99)}},
+					},
+				}
+			})
+
+			It("sends status bad request", func() {
+				err := handler.Handle(context.Background(), server)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeResponseSender.SendStatusResponseCallCount()).To(Equal(1))
+				resp := fakeResponseSender.SendStatusResponseArgsForCall(0)
+				Expect(resp).To(Equal(cb.Status_BAD_REQUEST))
+			})
+		})
+
 		Context("when fail if not ready is set and the next block is unavailable", func() {
 			BeforeEach(func() {
 				fakeBlockReader.HeightReturns(1000)
@@ -0,0 +1,122 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliver
+
+import (
+	"sync"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// acquireIdentityStream reserves a slot for a deliver request of the client
+// identity, which is freed by calling the returned function, or returns an
+// error if the identity has reached its maximum of concurrent deliver streams
+func (h *Handler) acquireIdentityStream(identity []byte) (func(), error) {
+	if h.MaxStreamsPerIdentity <= 0 {
+		return func() {}, nil
+	}
+
+	key := string(identity)
+	h.streamsLock.Lock()
+	defer h.streamsLock.Unlock()
+	if h.identityStreams == nil {
+		h.identityStreams = map[string]int{}
+	}
+	if h.identityStreams[key] >= h.MaxStreamsPerIdentity {
+		return nil, errors.Errorf("client identity has reached its maximum of %d concurrent deliver streams", h.MaxStreamsPerIdentity)
+	}
+	h.identityStreams[key]++
+
+	return func() {
+		h.streamsLock.Lock()
+		defer h.streamsLock.Unlock()
+		if h.identityStreams[key]--; h.identityStreams[key] <= 0 {
+			delete(h.identityStreams, key)
+		}
+	}, nil
+}
+
+// blockSender sends the blocks of a deliver request
+type blockSender interface {
+	// send sends the block, or hands it over to be sent. It returns the error
+	// of the sends of the previous blocks, if any
+	send(block *cb.Block) error
+	// flush waits for the blocks handed over to be sent, and returns the
+	// error of their sends if any. No block can be sent once flushed
+	flush() error
+}
+
+// newBlockSender returns a sender of the blocks of a deliver request, which
+// holds at most window blocks read from the ledger but not yet sent. The
+// blocks are sent as they are read with a window of 1 block.
+func newBlockSender(window int, send func(block *cb.Block) error) blockSender {
+	if window <= 1 {
+		return syncSender(send)
+	}
+	// the block being sent and the one waiting to be handed over are held
+	// besides the queued ones
+	s := &windowSender{
+		sendBlock: send,
+		blocks:    make(chan *cb.Block, window-2),
+		failed:    make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// syncSender sends the blocks as they are read
+type syncSender func(block *cb.Block) error
+
+func (s syncSender) send(block *cb.Block) error {
+	return s(block)
+}
+
+func (s syncSender) flush() error {
+	return nil
+}
+
+// windowSender sends the blocks from a goroutine of its own, so that the next
+// blocks are read from the ledger while the client receives the previous
+// ones. Once the window is full, as the client is slower than the ledger, the
+// blocks wait to be handed over and the reading stops.
+type windowSender struct {
+	sendBlock func(block *cb.Block) error
+	blocks    chan *cb.Block
+	failed    chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	err       error
+}
+
+func (s *windowSender) run() {
+	defer close(s.done)
+	for block := range s.blocks {
+		if s.err != nil {
+			continue
+		}
+		if s.err = s.sendBlock(block); s.err != nil {
+			close(s.failed)
+		}
+	}
+}
+
+func (s *windowSender) send(block *cb.Block) error {
+	select {
+	case s.blocks <- block:
+		return nil
+	case <-s.failed:
+		return s.err
+	}
+}
+
+func (s *windowSender) flush() error {
+	s.closeOnce.Do(func() { close(s.blocks) })
+	<-s.done
+	return s.err
+}
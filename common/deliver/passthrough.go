@@ -0,0 +1,77 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliver
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric/protos/common"
+)
+
+// EncodedResponses holds the encoding of the response carrying the newest
+// block sent on every channel, which the deliver streams of the channel
+// share. The clients following a channel wait for the same newest block: the
+// response carrying it is encoded once and passed through to every one of
+// them as is, instead of encoding the whole block again for each client.
+type EncodedResponses struct {
+	mutex     sync.Mutex
+	responses map[string]*encodedResponse
+}
+
+type encodedResponse struct {
+	number   uint64
+	dataHash []byte
+	once     sync.Once
+	bytes    []byte
+	err      error
+}
+
+// NewEncodedResponses returns an empty set of encoded block responses
+func NewEncodedResponses() *EncodedResponses {
+	return &EncodedResponses{responses: make(map[string]*encodedResponse)}
+}
+
+// Encode returns the encoding of the response carrying the block of the
+// channel. The encoding of the response carrying the newest block of the
+// channel is kept and shared, while the responses carrying older blocks,
+// read by the clients catching up, are encoded for every client.
+func (e *EncodedResponses) Encode(channelID string, block *cb.Block, response proto.Message) ([]byte, error) {
+	number := block.Header.Number
+	e.mutex.Lock()
+	r := e.responses[channelID]
+	if r == nil || r.number < number {
+		r = &encodedResponse{number: number, dataHash: block.Header.DataHash}
+		e.responses[channelID] = r
+	}
+	e.mutex.Unlock()
+
+	if r.number != number || !bytes.Equal(r.dataHash, block.Header.DataHash) {
+		return proto.Marshal(response)
+	}
+	r.once.Do(func() {
+		r.bytes, r.err = proto.Marshal(response)
+	})
+	return r.bytes, r.err
+}
+
+// EncodedMessage returns the message along with its encoding, which gRPC
+// sends as is when it is passed to SendMsg instead of encoding the message
+func EncodedMessage(msg proto.Message, encoded []byte) proto.Message {
+	return &encodedMessage{Message: msg, encoded: encoded}
+}
+
+type encodedMessage struct {
+	proto.Message
+	encoded []byte
+}
+
+// Marshal returns the encoding of the message
+func (m *encodedMessage) Marshal() ([]byte, error) {
+	return m.encoded, nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliver_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/deliver"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func blockResponse(number uint64, dataHash string, txs ...[]byte) (*cb.Block, *ab.DeliverResponse) {
+	block := &cb.Block{
+		Header:   &cb.BlockHeader{Number: number, DataHash: []byte(dataHash)},
+		Data:     &cb.BlockData{Data: txs},
+		Metadata: &cb.BlockMetadata{Metadata: [][]byte{{}, {}, {}, {}}},
+	}
+	return block, &ab.DeliverResponse{Type: &ab.DeliverResponse_Block{Block: block}}
+}
+
+var _ = Describe("EncodedResponses", func() {
+	var encodedResponses *deliver.EncodedResponses
+
+	BeforeEach(func() {
+		encodedResponses = deliver.NewEncodedResponses()
+	})
+
+	It("shares the encoding of the response carrying the newest block", func() {
+		block, response := blockResponse(5, "hash", []byte("tx"))
+		encoded, err := encodedResponses.Encode("channel", block, response)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(encoded).To(Equal(utils.MarshalOrPanic(response)))
+
+		again, err := encodedResponses.Encode("channel", block, response)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(&again[0]).To(BeIdenticalTo(&encoded[0]))
+	})
+
+	It("encodes the responses carrying older blocks for every client", func() {
+		newest, newestResponse := blockResponse(5, "hash", []byte("tx"))
+		_, err := encodedResponses.Encode("channel", newest, newestResponse)
+		Expect(err).NotTo(HaveOccurred())
+
+		older, olderResponse := blockResponse(4, "older", []byte("other tx"))
+		first, err := encodedResponses.Encode("channel", older, olderResponse)
+		Expect(err).NotTo(HaveOccurred())
+		second, err := encodedResponses.Encode("channel", older, olderResponse)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(first).To(Equal(utils.MarshalOrPanic(olderResponse)))
+		Expect(&second[0]).NotTo(BeIdenticalTo(&first[0]))
+	})
+
+	It("does not share the encoding of a different block with the same number", func() {
+		block, response := blockResponse(5, "hash", []byte("tx"))
+		_, err := encodedResponses.Encode("channel", block, response)
+		Expect(err).NotTo(HaveOccurred())
+
+		other, otherResponse := blockResponse(5, "other", []byte("other tx"))
+		encoded, err := encodedResponses.Encode("channel", other, otherResponse)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(encoded).To(Equal(utils.MarshalOrPanic(otherResponse)))
+	})
+
+	It("keeps the responses of the channels apart", func() {
+		block, response := blockResponse(5, "hash", []byte("tx"))
+		_, err := encodedResponses.Encode("channel", block, response)
+		Expect(err).NotTo(HaveOccurred())
+
+		other, otherResponse := blockResponse(5, "hash", []byte("other tx"))
+		encoded, err := encodedResponses.Encode("other-channel", other, otherResponse)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(encoded).To(Equal(utils.MarshalOrPanic(otherResponse)))
+	})
+})
+
+var _ = Describe("EncodedMessage", func() {
+	It("marshals as its encoding", func() {
+		_, response := blockResponse(5, "hash", []byte("tx"))
+		msg := deliver.EncodedMessage(response, []byte("encoded"))
+		Expect(proto.Marshal(msg)).To(Equal([]byte("encoded")))
+		Expect(msg.String()).To(Equal(response.String()))
+	})
+})
+
+// BenchmarkEncodedResponses measures sending a large block to many clients
+// following the channel, which encode it once
+func BenchmarkEncodedResponses(b *testing.B) {
+	txs := make([][]byte, 500)
+	for i := range txs {
+		txs[i] = make([]byte, 100*1024)
+	}
+	const clients = 10
+
+	b.Run("encoded per client", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, response := blockResponse(uint64(i), "hash", txs...)
+			for c := 0; c < clients; c++ {
+				if _, err := proto.Marshal(response); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	b.Run("shared", func(b *testing.B) {
+		encodedResponses := deliver.NewEncodedResponses()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			block, response := blockResponse(uint64(i), "hash", txs...)
+			for c := 0; c < clients; c++ {
+				if _, err := encodedResponses.Encode("channel", block, response); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
@@ -55,6 +55,59 @@ func (m *ModuleLevels) ResetLevels() {
 // The logging specification has the following form:
 //   [<module>[,<module>...]=]<level>[:[<module>[,<module>...]=]<level>...]
 func (m *ModuleLevels) ActivateSpec(spec string) error {
+	levelAll, updates, err := parseSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	// Update existing modules iff an unqualified level is set.
+	if levelAll != nil {
+		l := *levelAll
+		m.SetDefaultLevel(l)
+		for module := range m.Levels() {
+			m.SetLevel(module, l)
+		}
+	}
+
+	for module, level := range updates {
+		m.SetLevel(module, level)
+	}
+
+	return nil
+}
+
+// MergeSpec is used to modify module logging levels as ActivateSpec does,
+// except that an unqualified level only updates the modules logging at the
+// current default level. The modules whose level was set apart from the
+// default one keep their level, unless the specification sets it.
+func (m *ModuleLevels) MergeSpec(spec string) error {
+	levelAll, updates, err := parseSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	if levelAll != nil {
+		l := *levelAll
+		m.mutex.Lock()
+		for module, level := range m.levels {
+			if level == m.defaultLevel {
+				m.levels[module] = l
+			}
+		}
+		m.defaultLevel = l
+		m.mutex.Unlock()
+	}
+
+	for module, level := range updates {
+		m.SetLevel(module, level)
+	}
+
+	return nil
+}
+
+// parseSpec returns the unqualified level of the logging specification, nil
+// if there is none, and the levels of the modules it names
+func parseSpec(spec string) (*zapcore.Level, map[string]zapcore.Level, error) {
 	var levelAll *zapcore.Level
 	updates := map[string]zapcore.Level{}
 
@@ -68,7 +121,7 @@ func (m *ModuleLevels) ActivateSpec(spec string) error {
 		case 2: // <module>[,<module>...]=<level>
 			level := NameToLevel(split[1])
 			if split[0] == "" {
-				return errors.Errorf("invalid logging specification '%s': no module specified in segment '%s'", spec, field)
+				return nil, nil, errors.Errorf("invalid logging specification '%s': no module specified in segment '%s'", spec, field)
 			}
 
 			modules := strings.Split(split[0], ",")
@@ -77,24 +130,10 @@ func (m *ModuleLevels) ActivateSpec(spec string) error {
 			}
 
 		default:
-			return errors.Errorf("invalid logging specification '%s': bad segment '%s'", spec, field)
-		}
-	}
-
-	// Update existing modules iff an unqualified level is set.
-	if levelAll != nil {
-		l := *levelAll
-		m.SetDefaultLevel(l)
-		for module := range m.Levels() {
-			m.SetLevel(module, l)
+			return nil, nil, errors.Errorf("invalid logging specification '%s': bad segment '%s'", spec, field)
 		}
 	}
-
-	for module, level := range updates {
-		m.SetLevel(module, level)
-	}
-
-	return nil
+	return levelAll, updates, nil
 }
 
 // SetLevel sets the logging level for a single logging module.
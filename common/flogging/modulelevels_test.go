@@ -205,6 +205,36 @@ func TestModuleLevelsActivateSpec(t *testing.T) {
 	}
 }
 
+func TestModuleLevelsMergeSpec(t *testing.T) {
+	ml := &flogging.ModuleLevels{}
+	ml.SetDefaultLevel(zapcore.InfoLevel)
+	ml.RestoreLevels(map[string]zapcore.Level{
+		"default":    zapcore.InfoLevel,
+		"overridden": zapcore.WarnLevel,
+		"chaincode":  zapcore.DebugLevel,
+	})
+
+	// the modules at the default level follow the new default level
+	err := ml.MergeSpec("module1=error:DEBUG")
+	assert.NoError(t, err)
+	assert.Equal(t, zapcore.DebugLevel, ml.DefaultLevel())
+	assert.Equal(t, map[string]zapcore.Level{
+		"default":    zapcore.DebugLevel,
+		"overridden": zapcore.WarnLevel,
+		"chaincode":  zapcore.DebugLevel,
+		"module1":    zapcore.ErrorLevel,
+	}, ml.Levels())
+
+	err = ml.MergeSpec("overridden=info")
+	assert.NoError(t, err)
+	assert.Equal(t, zapcore.DebugLevel, ml.DefaultLevel())
+	assert.Equal(t, zapcore.InfoLevel, ml.Level("overridden"))
+
+	err = ml.MergeSpec("=INFO:WARN")
+	assert.EqualError(t, err, "invalid logging specification '=INFO:WARN': no module specified in segment '=INFO'")
+	assert.Equal(t, zapcore.DebugLevel, ml.DefaultLevel())
+}
+
 func TestModuleLevelsEnabler(t *testing.T) {
 	ml := &flogging.ModuleLevels{}
 	ml.SetLevel("module-name", zapcore.ErrorLevel)
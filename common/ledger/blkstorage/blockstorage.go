@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package blkstorage
 
 import (
+	"time"
+
 	"github.com/hyperledger/fabric/common/ledger"
 	l "github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/protos/common"
@@ -25,11 +27,18 @@ const (
 	IndexableAttrBlockNumTranNum  = IndexableAttr("BlockNumTranNum")
 	IndexableAttrBlockTxID        = IndexableAttr("BlockTxID")
 	IndexableAttrTxValidationCode = IndexableAttr("TxValidationCode")
+	IndexableAttrTxCreator        = IndexableAttr("TxCreator")
+	IndexableAttrBlockTime        = IndexableAttr("BlockTime")
+	IndexableAttrTxDedupKey       = IndexableAttr("TxDedupKey")
 )
 
 // IndexConfig - a configuration that includes a list of attributes that should be indexed
 type IndexConfig struct {
 	AttrsToIndex []IndexableAttr
+	// AsyncIndexing makes the blocks indexed in the background once they are
+	// added to the store, the lookups of the index waiting for it to catch up
+	// with the blocks they may look for
+	AsyncIndexing bool
 }
 
 var (
@@ -62,5 +71,35 @@ type BlockStore interface {
 	RetrieveTxByBlockNumTranNum(blockNum uint64, tranNum uint64) (*common.Envelope, error)
 	RetrieveBlockByTxID(txID string) (*common.Block, error)
 	RetrieveTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error)
+	// RetrieveTxsByCreator returns, in commit order, up to limit transactions created by the given
+	// serialized identity in the blocks starting at startBlockNum. A limit of 0 returns all of them
+	RetrieveTxsByCreator(creator []byte, startBlockNum uint64, limit int) ([]*l.IndexedTransaction, error)
+	// RetrieveBlocksByTimeRange returns up to limit blocks whose time falls within [start, end).
+	// The time of a block is the timestamp of its first transaction. A limit of 0 returns all of them
+	RetrieveBlocksByTimeRange(start, end time.Time, limit int) ([]*common.Block, error)
+	// RetrieveBlockNumByDedupKey returns the number of the block of the last valid transaction
+	// carrying the given dedup key for the given chaincode
+	RetrieveBlockNumByDedupKey(namespace, dedupKey string) (uint64, error)
 	Shutdown()
 }
+
+// IndexedHeightReporter is implemented by the block stores which may index the
+// blocks asynchronously with their addition, so that the lag of the index is
+// monitored
+type IndexedHeightReporter interface {
+	// GetIndexedHeight returns the number of blocks indexed so far
+	GetIndexedHeight() (uint64, error)
+}
+
+// BootstrapCapable is implemented by the block stores whose blockchain can start
+// at the last block of a snapshot of the state of the ledger instead of at the
+// genesis block
+type BootstrapCapable interface {
+	// Bootstrap adds lastBlock to the empty blockchain, the heights below it
+	// being skipped, and keeps configBlock, the last config block as of
+	// lastBlock, retrievable by its number
+	Bootstrap(lastBlock, configBlock *common.Block) error
+	// GetBootstrapConfigBlock returns the config block kept by Bootstrap, nil if
+	// the blockchain starts at the genesis block
+	GetBootstrapConfigBlock() (*common.Block, error)
+}
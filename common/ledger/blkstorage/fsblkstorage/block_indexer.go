@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// indexQueueSize is the number of blocks added to the store which may wait for
+// being indexed, beyond which the addition of the blocks waits for the index
+const indexQueueSize = 100
+
+// blockIndexer indexes the blocks asynchronously with their addition to the
+// block files. As the checkpoint of the index records the last block indexed,
+// the blocks not indexed yet when the peer stops are indexed by syncIndex at
+// the next start.
+type blockIndexer struct {
+	index index
+	queue chan *blockIdxInfo
+	done  chan struct{}
+
+	cond *sync.Cond
+	// indexedHeight is the number of blocks indexed so far
+	indexedHeight uint64
+	// err is the error which stopped the indexing of the blocks
+	err error
+}
+
+func newBlockIndexer(index index, indexedHeight uint64) *blockIndexer {
+	indexer := &blockIndexer{
+		index:         index,
+		queue:         make(chan *blockIdxInfo, indexQueueSize),
+		done:          make(chan struct{}),
+		cond:          sync.NewCond(&sync.Mutex{}),
+		indexedHeight: indexedHeight,
+	}
+	go indexer.run()
+	return indexer
+}
+
+func (indexer *blockIndexer) run() {
+	defer close(indexer.done)
+	for blockIdxInfo := range indexer.queue {
+		if err := indexer.index.indexBlock(blockIdxInfo); err != nil {
+			logger.Errorf("Failed indexing block [%d], the lookups of the index fail until the peer restarts: %s", blockIdxInfo.blockNum, err)
+			indexer.update(0, errors.WithMessage(err, fmt.Sprintf("failed indexing block [%d]", blockIdxInfo.blockNum)))
+			// the following blocks are not indexed, so that the index has no gap
+			// when it is synced with the block files at the next start
+			for range indexer.queue {
+			}
+			return
+		}
+		indexer.update(blockIdxInfo.blockNum+1, nil)
+	}
+}
+
+func (indexer *blockIndexer) update(indexedHeight uint64, err error) {
+	indexer.cond.L.Lock()
+	defer indexer.cond.L.Unlock()
+	if err != nil {
+		indexer.err = err
+	} else {
+		indexer.indexedHeight = indexedHeight
+	}
+	indexer.cond.Broadcast()
+}
+
+// enqueue schedules the indexing of a block, waiting if too many blocks are
+// already waiting for being indexed
+func (indexer *blockIndexer) enqueue(blockIdxInfo *blockIdxInfo) {
+	indexer.queue <- blockIdxInfo
+}
+
+// waitIndexed waits for the blocks below the given height to be indexed, or
+// returns the error which stopped the indexing before
+func (indexer *blockIndexer) waitIndexed(height uint64) error {
+	indexer.cond.L.Lock()
+	defer indexer.cond.L.Unlock()
+	for indexer.indexedHeight < height {
+		if indexer.err != nil {
+			return indexer.err
+		}
+		indexer.cond.Wait()
+	}
+	return nil
+}
+
+func (indexer *blockIndexer) getIndexedHeight() uint64 {
+	indexer.cond.L.Lock()
+	defer indexer.cond.L.Unlock()
+	return indexer.indexedHeight
+}
+
+// close waits for the blocks already added to be indexed
+func (indexer *blockIndexer) close() {
+	close(indexer.queue)
+	<-indexer.done
+}
@@ -0,0 +1,86 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAsyncIndexingTestEnv(t *testing.T, conf *Conf) *testEnv {
+	indexConfig := &blkstorage.IndexConfig{
+		AttrsToIndex: []blkstorage.IndexableAttr{
+			blkstorage.IndexableAttrBlockHash,
+			blkstorage.IndexableAttrBlockNum,
+			blkstorage.IndexableAttrTxID,
+		},
+		AsyncIndexing: true,
+	}
+	return &testEnv{t, NewProvider(conf, indexConfig).(*FsBlockstoreProvider)}
+}
+
+func TestAsyncIndexing(t *testing.T) {
+	env := newAsyncIndexingTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	allBlocks := testutil.ConstructTestBlocks(t, 15)
+	blocks := allBlocks[:10]
+	blkfileMgrWrapper.addBlocks(blocks)
+
+	// the lookups wait for the index to catch up
+	blkfileMgrWrapper.testGetBlockByHash(blocks)
+	blkfileMgrWrapper.testGetBlockByNumber(blocks, 0)
+	txID, err := extractTxID(blocks[9].Data.Data[0])
+	assert.NoError(t, err)
+	_, err = blkfileMgrWrapper.blockfileMgr.retrieveTransactionByID(txID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), blkfileMgrWrapper.blockfileMgr.getIndexedHeight())
+
+	// the lookup of a block beyond the last one doesn't wait
+	_, err = blkfileMgrWrapper.blockfileMgr.retrieveBlockByNumber(100)
+	assert.Equal(t, blkstorage.ErrNotFoundInIndex, err)
+
+	// the blocks added are indexed before closing
+	blkfileMgrWrapper.addBlocks(allBlocks[10:])
+	blkfileMgrWrapper.close()
+	lastBlockIndexed, err := blkfileMgrWrapper.blockfileMgr.index.getLastBlockIndexed()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(14), lastBlockIndexed)
+}
+
+type failingIndex struct {
+	index
+	failingBlockNum uint64
+	indexed         []uint64
+}
+
+func (i *failingIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
+	if blockIdxInfo.blockNum == i.failingBlockNum {
+		return errors.New("disk full")
+	}
+	i.indexed = append(i.indexed, blockIdxInfo.blockNum)
+	return nil
+}
+
+func TestBlockIndexerFailure(t *testing.T) {
+	index := &failingIndex{failingBlockNum: 3}
+	indexer := newBlockIndexer(index, 1)
+	for blockNum := uint64(1); blockNum < 6; blockNum++ {
+		indexer.enqueue(&blockIdxInfo{blockNum: blockNum})
+	}
+
+	assert.EqualError(t, indexer.waitIndexed(6), "failed indexing block [3]: disk full")
+	assert.NoError(t, indexer.waitIndexed(3))
+	assert.Equal(t, uint64(3), indexer.getIndexedHeight())
+	indexer.close()
+	// the blocks following the failure are not indexed
+	assert.Equal(t, []uint64{1, 2}, index.indexed)
+}
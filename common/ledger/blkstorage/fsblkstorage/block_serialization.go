@@ -17,7 +17,10 @@ limitations under the License.
 package fsblkstorage
 
 import (
+	"encoding/binary"
+
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	ledgerutil "github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -32,12 +35,19 @@ type serializedBlockInfo struct {
 //The order of the transactions must be maintained for history
 type txindexInfo struct {
 	txID        string
+	creator     []byte
+	timestamp   *timestamp.Timestamp
 	loc         *locPointer
 	isDuplicate bool
+	// dedupNs and dedupKey are the chaincode and the dedup key of an endorser transaction carrying one
+	dedupNs  string
+	dedupKey string
 }
 
 func serializeBlock(block *common.Block) ([]byte, *serializedBlockInfo, error) {
-	buf := proto.NewBuffer(nil)
+	// the buffer is allocated at once, large blocks would be copied
+	// repeatedly while growing it otherwise
+	buf := proto.NewBuffer(make([]byte, 0, maxSerializedBlockSize(block)))
 	var err error
 	info := &serializedBlockInfo{}
 	info.blockHeader = block.Header
@@ -54,6 +64,23 @@ func serializeBlock(block *common.Block) ([]byte, *serializedBlockInfo, error) {
 	return buf.Bytes(), info, nil
 }
 
+// maxSerializedBlockSize returns an upper bound of the size of the
+// serialized block
+func maxSerializedBlockSize(block *common.Block) int {
+	size := 3*binary.MaxVarintLen64 + len(block.Header.DataHash) + len(block.Header.PreviousHash)
+	size += binary.MaxVarintLen64
+	for _, txEnvelopeBytes := range block.Data.Data {
+		size += binary.MaxVarintLen64 + len(txEnvelopeBytes)
+	}
+	size += binary.MaxVarintLen64
+	if block.Metadata != nil {
+		for _, b := range block.Metadata.Metadata {
+			size += binary.MaxVarintLen64 + len(b)
+		}
+	}
+	return size
+}
+
 func deserializeBlock(serializedBlockBytes []byte) (*common.Block, error) {
 	block := &common.Block{}
 	var err error
@@ -112,14 +139,14 @@ func addDataBytes(blockData *common.BlockData, buf *proto.Buffer) ([]*txindexInf
 	}
 	for _, txEnvelopeBytes := range blockData.Data {
 		offset := len(buf.Bytes())
-		txid, err := extractTxID(txEnvelopeBytes)
+		idxInfo, err := extractTxIndexInfo(txEnvelopeBytes)
 		if err != nil {
 			return nil, err
 		}
 		if err := buf.EncodeRawBytes(txEnvelopeBytes); err != nil {
 			return nil, err
 		}
-		idxInfo := &txindexInfo{txID: txid, loc: &locPointer{offset, len(buf.Bytes()) - offset}}
+		idxInfo.loc = &locPointer{offset, len(buf.Bytes()) - offset}
 		txOffsets = append(txOffsets, idxInfo)
 	}
 	return txOffsets, nil
@@ -170,16 +197,16 @@ func extractData(buf *ledgerutil.Buffer) (*common.BlockData, []*txindexInfo, err
 	}
 	for i := uint64(0); i < numItems; i++ {
 		var txEnvBytes []byte
-		var txid string
+		var idxInfo *txindexInfo
 		txOffset := buf.GetBytesConsumed()
 		if txEnvBytes, err = buf.DecodeRawBytes(false); err != nil {
 			return nil, nil, err
 		}
-		if txid, err = extractTxID(txEnvBytes); err != nil {
+		if idxInfo, err = extractTxIndexInfo(txEnvBytes); err != nil {
 			return nil, nil, err
 		}
 		data.Data = append(data.Data, txEnvBytes)
-		idxInfo := &txindexInfo{txID: txid, loc: &locPointer{txOffset, buf.GetBytesConsumed() - txOffset}}
+		idxInfo.loc = &locPointer{txOffset, buf.GetBytesConsumed() - txOffset}
 		txOffsets = append(txOffsets, idxInfo)
 	}
 	return data, txOffsets, nil
@@ -203,17 +230,37 @@ func extractMetadata(buf *ledgerutil.Buffer) (*common.BlockMetadata, error) {
 }
 
 func extractTxID(txEnvelopBytes []byte) (string, error) {
-	txEnvelope, err := utils.GetEnvelopeFromBlock(txEnvelopBytes)
+	idxInfo, err := extractTxIndexInfo(txEnvelopBytes)
 	if err != nil {
 		return "", err
 	}
-	txPayload, err := utils.GetPayload(txEnvelope)
+	return idxInfo.txID, nil
+}
+
+// extractTxIndexInfo extracts the txid, creator, timestamp and dedup key of a transaction from its header
+func extractTxIndexInfo(txEnvelopBytes []byte) (*txindexInfo, error) {
+	// only the header of the transaction is unmarshaled, the transaction
+	// itself isn't copied
+	txEnvelope, err := utils.UnmarshalEnvelopeNoCopy(txEnvelopBytes)
+	if err != nil {
+		return nil, err
+	}
+	txHeader, err := utils.UnmarshalPayloadHeader(txEnvelope.Payload)
 	if err != nil {
-		return "", nil
+		return &txindexInfo{}, nil
 	}
-	chdr, err := utils.UnmarshalChannelHeader(txPayload.Header.ChannelHeader)
+	chdr, err := utils.UnmarshalChannelHeader(txHeader.ChannelHeader)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	idxInfo := &txindexInfo{txID: chdr.TxId, timestamp: chdr.Timestamp}
+	if shdr, err := utils.GetSignatureHeader(txHeader.SignatureHeader); err == nil {
+		idxInfo.creator = shdr.Creator
+	}
+	if chdr.Type == int32(common.HeaderType_ENDORSER_TRANSACTION) {
+		if ext, err := utils.GetChaincodeHeaderExtension(txHeader); err == nil && ext.DedupKey != "" && ext.ChaincodeId != nil {
+			idxInfo.dedupNs, idxInfo.dedupKey = ext.ChaincodeId.Name, ext.DedupKey
+		}
 	}
-	return chdr.TxId, nil
+	return idxInfo, nil
 }
@@ -65,3 +65,15 @@ func TestSerializedBlockInfo(t *testing.T) {
 		assert.Equal(t, txEnvBytes, txEnvBytesFromBB)
 	}
 }
+
+func BenchmarkSerializeBlock(b *testing.B) {
+	// a block of 50MB
+	block := testutil.ConstructTestBlock(b, 1, 500, 100*1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := serializeBlock(block); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"math"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/golang/protobuf/proto"
@@ -19,6 +20,7 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	l "github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/peer"
 	putil "github.com/hyperledger/fabric/protos/utils"
@@ -32,7 +34,8 @@ const (
 )
 
 var (
-	blkMgrInfoKey = []byte("blkMgrInfo")
+	blkMgrInfoKey           = []byte("blkMgrInfo")
+	bootstrapConfigBlockKey = []byte("bootstrapConfigBlock")
 )
 
 type blockfileMgr struct {
@@ -44,6 +47,21 @@ type blockfileMgr struct {
 	cpInfoCond        *sync.Cond
 	currentFileWriter *blockfileWriter
 	bcInfo            atomic.Value
+	// indexer is set when the blocks are indexed asynchronously
+	indexer *blockIndexer
+	// bootstrapConfigBlock is set when the blockchain starts at the last block
+	// of a snapshot of the state instead of at the genesis block, or when the
+	// block file holding the last config block has been pruned
+	bootstrapConfigBlock *common.Block
+	// pruningInfo tracks the block files which have been pruned
+	pruningInfo *pruningInfo
+	// prunedLock guards bootstrapConfigBlock and pruningInfo
+	prunedLock sync.RWMutex
+	// nextFileFirstBlockNum caches the first block of the block file following
+	// the oldest one, 0 when it is not known yet
+	nextFileFirstBlockNum uint64
+	// syncer is set when the block files are flushed to the disk by group
+	syncer *groupSyncer
 }
 
 /*
@@ -87,7 +105,7 @@ At start up a new manager:
 		-- If index and file system are not in sync, syncs index from the FS
   *)  Updates blockchain info used by the APIs
 */
-func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig, indexStore *leveldbhelper.DBHandle) *blockfileMgr {
+func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig, indexStore *leveldbhelper.DBHandle, syncer *groupSyncer) *blockfileMgr {
 	logger.Debugf("newBlockfileMgr() initializing file-based block storage for ledger: %s ", id)
 	//Determine the root directory for the blockfile storage, if it does not exist create it
 	rootDir := conf.getLedgerBlockDir(id)
@@ -96,7 +114,7 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 		panic(fmt.Sprintf("Error creating block storage root dir [%s]: %s", rootDir, err))
 	}
 	// Instantiate the manager, i.e. blockFileMgr structure
-	mgr := &blockfileMgr{rootDir: rootDir, conf: conf, db: indexStore}
+	mgr := &blockfileMgr{rootDir: rootDir, conf: conf, db: indexStore, syncer: syncer}
 
 	// cp = checkpointInfo, retrieve from the database the file suffix or number of where blocks were stored.
 	// It also retrieves the current size of that file and the last block number that was written to that file.
@@ -144,6 +162,17 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 	// or announcing the occurrence of an event.
 	mgr.cpInfoCond = sync.NewCond(&sync.Mutex{})
 
+	if mgr.bootstrapConfigBlock, err = mgr.loadBootstrapConfigBlock(); err != nil {
+		panic(fmt.Sprintf("Could not get the bootstrap config block from db: %s", err))
+	}
+	if mgr.pruningInfo, err = mgr.loadPruningInfo(); err != nil {
+		panic(fmt.Sprintf("Could not get the pruning info from db: %s", err))
+	}
+	if mgr.pruningInfo.firstFileNum > 0 {
+		// the files pruned before a crash may not have been removed
+		mgr.removePrunedFiles()
+	}
+
 	// init BlockchainInfo for external API's
 	bcInfo := &common.BlockchainInfo{
 		Height:            0,
@@ -165,6 +194,9 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 			PreviousBlockHash: previousBlockHash}
 	}
 	mgr.bcInfo.Store(bcInfo)
+	if indexConfig.AsyncIndexing {
+		mgr.indexer = newBlockIndexer(mgr.index, bcInfo.Height)
+	}
 	return mgr
 }
 
@@ -189,7 +221,7 @@ func syncCPInfoFromFS(rootDir string, cpInfo *checkpointInfo) {
 		return
 	}
 	//Scan the file system to verify that the checkpoint info stored in db is correct
-	_, endOffsetLastBlock, numBlocks, err := scanForLastCompleteBlock(
+	lastBlockBytes, endOffsetLastBlock, numBlocks, err := scanForLastCompleteBlock(
 		rootDir, cpInfo.latestFileChunkSuffixNum, int64(cpInfo.latestFileChunksize))
 	if err != nil {
 		panic(fmt.Sprintf("Could not open current file for detecting last block in the file: %s", err))
@@ -200,7 +232,12 @@ func syncCPInfoFromFS(rootDir string, cpInfo *checkpointInfo) {
 	}
 	//Updates the checkpoint info for the actual last block number stored and it's end location
 	if cpInfo.isChainEmpty {
-		cpInfo.lastBlockNumber = uint64(numBlocks - 1)
+		// a blockchain bootstrapped from a snapshot does not start at block 0
+		lastBlock, err := deserializeBlock(lastBlockBytes)
+		if err != nil {
+			panic(fmt.Sprintf("Could not deserialize the last block in the file: %s", err))
+		}
+		cpInfo.lastBlockNumber = lastBlock.Header.Number
 	} else {
 		cpInfo.lastBlockNumber += uint64(numBlocks)
 	}
@@ -213,6 +250,9 @@ func deriveBlockfilePath(rootDir string, suffixNum int) string {
 }
 
 func (mgr *blockfileMgr) close() {
+	if mgr.indexer != nil {
+		mgr.indexer.close()
+	}
 	mgr.currentFileWriter.close()
 }
 
@@ -275,11 +315,22 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 		mgr.moveToNextFile()
 		currentOffset = 0
 	}
-	//append blockBytesEncodedLen to the file
-	err = mgr.currentFileWriter.append(blockBytesEncodedLen, false)
-	if err == nil {
-		//append the actual block bytes to the file
-		err = mgr.currentFileWriter.append(blockBytes, true)
+	if mgr.syncer != nil {
+		mgr.syncer.begin()
+	}
+	//append blockBytesEncodedLen and the actual block bytes to the file at once
+	appendBytes := make([]byte, 0, totalBytesToAppend)
+	appendBytes = append(appendBytes, blockBytesEncodedLen...)
+	appendBytes = append(appendBytes, blockBytes...)
+	err = mgr.currentFileWriter.append(appendBytes, mgr.syncer == nil)
+	if mgr.syncer != nil {
+		if err == nil {
+			// the checkpoint info must not get ahead of the block on the disk, the
+			// flush of the file is waited for as if it was done in place
+			err = mgr.syncer.sync(mgr.currentFileWriter.file).wait()
+		} else {
+			mgr.syncer.end()
+		}
 	}
 	if err != nil {
 		truncateErr := mgr.currentFileWriter.truncateFile(mgr.cpInfo.latestFileChunksize)
@@ -313,18 +364,78 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 		txOffset.loc.offset += len(blockBytesEncodedLen)
 	}
 	//save the index in the database
-	if err = mgr.index.indexBlock(&blockIdxInfo{
+	blockIdxInfo := &blockIdxInfo{
 		blockNum: block.Header.Number, blockHash: blockHash,
-		flp: blockFLP, txOffsets: txOffsets, metadata: block.Metadata}); err != nil {
+		flp: blockFLP, txOffsets: txOffsets, metadata: block.Metadata}
+	if mgr.indexer != nil {
+		mgr.indexer.enqueue(blockIdxInfo)
+	} else if err = mgr.index.indexBlock(blockIdxInfo); err != nil {
 		return err
 	}
 
 	//update the checkpoint info (for storage) and the blockchain info (for APIs) in the manager
 	mgr.updateCheckpoint(newCPInfo)
 	mgr.updateBlockchainInfo(blockHash, block)
+
+	// the block is added even if the old block files can't be pruned, they
+	// are pruned along with a later block
+	if err := mgr.prune(block); err != nil {
+		logger.Errorf("Failed pruning the block files: %s", err)
+	}
+	return nil
+}
+
+// bootstrap adds lastBlock, the last block of a snapshot of the state, to the
+// empty blockchain as if the blocks below it had been added, and saves
+// configBlock so that it is retrieved by number though it is not in the block
+// files. The blocks below lastBlock, other than configBlock, are not available.
+func (mgr *blockfileMgr) bootstrap(lastBlock, configBlock *common.Block) error {
+	bcInfo := mgr.getBlockchainInfo()
+	if bcInfo.Height != 0 {
+		return errors.Errorf("the blockchain is not empty, its height is %d", bcInfo.Height)
+	}
+	if configBlock.Header.Number > lastBlock.Header.Number {
+		return errors.Errorf("config block [%d] follows the last block [%d]", configBlock.Header.Number, lastBlock.Header.Number)
+	}
+	configBlockBytes, err := proto.Marshal(configBlock)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling the config block")
+	}
+	if err := mgr.db.Put(bootstrapConfigBlockKey, configBlockBytes, true); err != nil {
+		return errors.WithMessage(err, "error saving the config block to db")
+	}
+	mgr.prunedLock.Lock()
+	mgr.bootstrapConfigBlock = configBlock
+	mgr.prunedLock.Unlock()
+	mgr.bcInfo.Store(&common.BlockchainInfo{
+		Height:           lastBlock.Header.Number,
+		CurrentBlockHash: lastBlock.Header.PreviousHash,
+	})
+	if err := mgr.addBlock(lastBlock); err != nil {
+		mgr.bcInfo.Store(bcInfo)
+		return err
+	}
 	return nil
 }
 
+func (mgr *blockfileMgr) loadBootstrapConfigBlock() (*common.Block, error) {
+	b, err := mgr.db.Get(bootstrapConfigBlockKey)
+	if b == nil || err != nil {
+		return nil, err
+	}
+	block := &common.Block{}
+	if err := proto.Unmarshal(b, block); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling the bootstrap config block")
+	}
+	return block, nil
+}
+
+func (mgr *blockfileMgr) getBootstrapConfigBlock() *common.Block {
+	mgr.prunedLock.RLock()
+	defer mgr.prunedLock.RUnlock()
+	return mgr.bootstrapConfigBlock
+}
+
 func (mgr *blockfileMgr) syncIndex() error {
 	var lastBlockIndexed uint64
 	var indexEmpty bool
@@ -337,8 +448,8 @@ func (mgr *blockfileMgr) syncIndex() error {
 		indexEmpty = true
 	}
 
-	//initialize index to file number:zero, offset:zero and blockNum:0
-	startFileNum := 0
+	//initialize index to the first file not pruned, offset:zero and blockNum:0
+	startFileNum := mgr.pruningInfo.firstFileNum
 	startOffset := 0
 	skipFirstBlock := false
 	//get the last file that blocks were added to using the checkpoint info
@@ -347,6 +458,9 @@ func (mgr *blockfileMgr) syncIndex() error {
 
 	//if the index stored in the db has value, update the index information with those values
 	if !indexEmpty {
+		if err = mgr.backfillIndex(lastBlockIndexed); err != nil {
+			return err
+		}
 		if lastBlockIndexed == mgr.cpInfo.lastBlockNumber {
 			logger.Debug("Both the block files and indices are in sync.")
 			return nil
@@ -395,26 +509,10 @@ func (mgr *blockfileMgr) syncIndex() error {
 		if blockBytes == nil {
 			break
 		}
-		info, err := extractSerializedBlockInfo(blockBytes)
-		if err != nil {
+		if blockIdxInfo, err = constructBlockIdxInfo(blockBytes, blockPlacementInfo); err != nil {
 			return err
 		}
 
-		//The blockStartOffset will get applied to the txOffsets prior to indexing within indexBlock(),
-		//therefore just shift by the difference between blockBytesOffset and blockStartOffset
-		numBytesToShift := int(blockPlacementInfo.blockBytesOffset - blockPlacementInfo.blockStartOffset)
-		for _, offset := range info.txOffsets {
-			offset.loc.offset += numBytesToShift
-		}
-
-		//Update the blockIndexInfo with what was actually stored in file system
-		blockIdxInfo.blockHash = info.blockHeader.Hash()
-		blockIdxInfo.blockNum = info.blockHeader.Number
-		blockIdxInfo.flp = &fileLocPointer{fileSuffixNum: blockPlacementInfo.fileNum,
-			locPointer: locPointer{offset: int(blockPlacementInfo.blockStartOffset)}}
-		blockIdxInfo.txOffsets = info.txOffsets
-		blockIdxInfo.metadata = info.metadata
-
 		logger.Debugf("syncIndex() indexing block [%d]", blockIdxInfo.blockNum)
 		if err = mgr.index.indexBlock(blockIdxInfo); err != nil {
 			return err
@@ -427,6 +525,98 @@ func (mgr *blockfileMgr) syncIndex() error {
 	return nil
 }
 
+// backfillIndex indexes the attributes enabled since the index was built for
+// the blocks up to the last block indexed
+func (mgr *blockfileMgr) backfillIndex(lastBlockIndexed uint64) error {
+	attrs, err := mgr.index.getAttrsToBackfill()
+	if err != nil {
+		return err
+	}
+	if len(attrs) == 0 {
+		return nil
+	}
+	logger.Infof("Backfilling index attributes %s from block [%d] to block [%d]", attrs, mgr.pruningInfo.firstBlockNum, lastBlockIndexed)
+	stream, err := newBlockStream(mgr.rootDir, mgr.pruningInfo.firstFileNum, 0, mgr.cpInfo.latestFileChunkSuffixNum)
+	if err != nil {
+		return err
+	}
+	defer stream.close()
+	for {
+		blockBytes, blockPlacementInfo, err := stream.nextBlockBytesAndPlacementInfo()
+		if err != nil {
+			return err
+		}
+		if blockBytes == nil {
+			break
+		}
+		blockIdxInfo, err := constructBlockIdxInfo(blockBytes, blockPlacementInfo)
+		if err != nil {
+			return err
+		}
+		if blockIdxInfo.blockNum > lastBlockIndexed {
+			break
+		}
+		if err = mgr.index.backfillBlock(blockIdxInfo, attrs); err != nil {
+			return err
+		}
+		if blockIdxInfo.blockNum%10000 == 0 {
+			logger.Infof("Backfilled block number [%d]", blockIdxInfo.blockNum)
+		}
+	}
+	logger.Infof("Finished backfilling index attributes %s", attrs)
+	return mgr.index.markAttrsIndexed()
+}
+
+// constructBlockIdxInfo builds the index information of a block as it is stored in the block files
+func constructBlockIdxInfo(blockBytes []byte, placementInfo *blockPlacementInfo) (*blockIdxInfo, error) {
+	info, err := extractSerializedBlockInfo(blockBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	//The blockStartOffset will get applied to the txOffsets prior to indexing within indexBlock(),
+	//therefore just shift by the difference between blockBytesOffset and blockStartOffset
+	numBytesToShift := int(placementInfo.blockBytesOffset - placementInfo.blockStartOffset)
+	for _, offset := range info.txOffsets {
+		offset.loc.offset += numBytesToShift
+	}
+
+	//Update the blockIndexInfo with what was actually stored in file system
+	return &blockIdxInfo{
+		blockHash: info.blockHeader.Hash(),
+		blockNum:  info.blockHeader.Number,
+		flp: &fileLocPointer{fileSuffixNum: placementInfo.fileNum,
+			locPointer: locPointer{offset: int(placementInfo.blockStartOffset)}},
+		txOffsets: info.txOffsets,
+		metadata:  info.metadata,
+	}, nil
+}
+
+// waitIndexed waits for the blocks below the given height, or all the blocks
+// added so far if they are fewer, to be indexed when they are indexed
+// asynchronously
+func (mgr *blockfileMgr) waitIndexed(height uint64) error {
+	if mgr.indexer == nil {
+		return nil
+	}
+	if bcHeight := mgr.getBlockchainInfo().Height; height > bcHeight {
+		height = bcHeight
+	}
+	return mgr.indexer.waitIndexed(height)
+}
+
+// waitAllIndexed waits for all the blocks added so far to be indexed
+func (mgr *blockfileMgr) waitAllIndexed() error {
+	return mgr.waitIndexed(math.MaxUint64)
+}
+
+func (mgr *blockfileMgr) getIndexedHeight() uint64 {
+	if mgr.indexer == nil {
+		return mgr.getBlockchainInfo().Height
+	}
+	return mgr.indexer.getIndexedHeight()
+}
+
 func (mgr *blockfileMgr) getBlockchainInfo() *common.BlockchainInfo {
 	return mgr.bcInfo.Load().(*common.BlockchainInfo)
 }
@@ -451,6 +641,9 @@ func (mgr *blockfileMgr) updateBlockchainInfo(latestBlockHash []byte, latestBloc
 
 func (mgr *blockfileMgr) retrieveBlockByHash(blockHash []byte) (*common.Block, error) {
 	logger.Debugf("retrieveBlockByHash() - blockHash = [%#v]", blockHash)
+	if err := mgr.waitAllIndexed(); err != nil {
+		return nil, err
+	}
 	loc, err := mgr.index.getBlockLocByHash(blockHash)
 	if err != nil {
 		return nil, err
@@ -465,7 +658,17 @@ func (mgr *blockfileMgr) retrieveBlockByNumber(blockNum uint64) (*common.Block,
 	if blockNum == math.MaxUint64 {
 		blockNum = mgr.getBlockchainInfo().Height - 1
 	}
+	// the config block of a bootstrapped or pruned blockchain may not be in the block files
+	if configBlock := mgr.getBootstrapConfigBlock(); configBlock != nil && blockNum == configBlock.Header.Number {
+		return configBlock, nil
+	}
+	if err := mgr.checkBlockNotPruned(blockNum); err != nil {
+		return nil, err
+	}
 
+	if err := mgr.waitIndexed(blockNum + 1); err != nil {
+		return nil, err
+	}
 	loc, err := mgr.index.getBlockLocByBlockNum(blockNum)
 	if err != nil {
 		return nil, err
@@ -475,6 +678,9 @@ func (mgr *blockfileMgr) retrieveBlockByNumber(blockNum uint64) (*common.Block,
 
 func (mgr *blockfileMgr) retrieveBlockByTxID(txID string) (*common.Block, error) {
 	logger.Debugf("retrieveBlockByTxID() - txID = [%s]", txID)
+	if err := mgr.waitAllIndexed(); err != nil {
+		return nil, err
+	}
 
 	loc, err := mgr.index.getBlockLocByTxID(txID)
 
@@ -486,11 +692,75 @@ func (mgr *blockfileMgr) retrieveBlockByTxID(txID string) (*common.Block, error)
 
 func (mgr *blockfileMgr) retrieveTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error) {
 	logger.Debugf("retrieveTxValidationCodeByTxID() - txID = [%s]", txID)
+	if err := mgr.waitAllIndexed(); err != nil {
+		return peer.TxValidationCode(-1), err
+	}
 	return mgr.index.getTxValidationCodeByTxID(txID)
 }
 
+func (mgr *blockfileMgr) retrieveBlockNumByDedupKey(namespace, dedupKey string) (uint64, error) {
+	logger.Debugf("retrieveBlockNumByDedupKey() - namespace = [%s], dedupKey = [%s]", namespace, dedupKey)
+	if err := mgr.waitAllIndexed(); err != nil {
+		return 0, err
+	}
+	return mgr.index.getBlockNumByDedupKey(namespace, dedupKey)
+}
+
+func (mgr *blockfileMgr) retrieveTxsByCreator(creator []byte, startBlockNum uint64, limit int) ([]*l.IndexedTransaction, error) {
+	logger.Debugf("retrieveTxsByCreator() - startBlockNum = [%d], limit = [%d]", startBlockNum, limit)
+	if err := mgr.waitAllIndexed(); err != nil {
+		return nil, err
+	}
+	locs, err := mgr.index.getTxLocsByCreator(creator, startBlockNum, limit)
+	if err != nil {
+		return nil, err
+	}
+	txs := make([]*l.IndexedTransaction, 0, len(locs))
+	for _, loc := range locs {
+		txEnvelope, err := mgr.fetchTransactionEnvelope(loc.flp)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, &l.IndexedTransaction{
+			BlockNum: loc.blockNum,
+			TxNum:    loc.txNum,
+			Transaction: &peer.ProcessedTransaction{
+				TransactionEnvelope: txEnvelope,
+				ValidationCode:      int32(loc.validationCode),
+			},
+		})
+	}
+	return txs, nil
+}
+
+func (mgr *blockfileMgr) retrieveBlocksByTimeRange(start, end time.Time, limit int) ([]*common.Block, error) {
+	logger.Debugf("retrieveBlocksByTimeRange() - start = [%s], end = [%s], limit = [%d]", start, end, limit)
+	if err := mgr.waitAllIndexed(); err != nil {
+		return nil, err
+	}
+	locs, err := mgr.index.getBlockLocsByTime(start, end, limit)
+	if err != nil {
+		return nil, err
+	}
+	blocks := make([]*common.Block, 0, len(locs))
+	for _, loc := range locs {
+		block, err := mgr.fetchBlock(loc)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
 func (mgr *blockfileMgr) retrieveBlockHeaderByNumber(blockNum uint64) (*common.BlockHeader, error) {
 	logger.Debugf("retrieveBlockHeaderByNumber() - blockNum = [%d]", blockNum)
+	if err := mgr.checkBlockNotPruned(blockNum); err != nil {
+		return nil, err
+	}
+	if err := mgr.waitIndexed(blockNum + 1); err != nil {
+		return nil, err
+	}
 	loc, err := mgr.index.getBlockLocByBlockNum(blockNum)
 	if err != nil {
 		return nil, err
@@ -512,6 +782,9 @@ func (mgr *blockfileMgr) retrieveBlocks(startNum uint64) (*blocksItr, error) {
 
 func (mgr *blockfileMgr) retrieveTransactionByID(txID string) (*common.Envelope, error) {
 	logger.Debugf("retrieveTransactionByID() - txId = [%s]", txID)
+	if err := mgr.waitAllIndexed(); err != nil {
+		return nil, err
+	}
 	loc, err := mgr.index.getTxLoc(txID)
 	if err != nil {
 		return nil, err
@@ -521,6 +794,12 @@ func (mgr *blockfileMgr) retrieveTransactionByID(txID string) (*common.Envelope,
 
 func (mgr *blockfileMgr) retrieveTransactionByBlockNumTranNum(blockNum uint64, tranNum uint64) (*common.Envelope, error) {
 	logger.Debugf("retrieveTransactionByBlockNumTranNum() - blockNum = [%d], tranNum = [%d]", blockNum, tranNum)
+	if err := mgr.checkBlockNotPruned(blockNum); err != nil {
+		return nil, err
+	}
+	if err := mgr.waitIndexed(blockNum + 1); err != nil {
+		return nil, err
+	}
 	loc, err := mgr.index.getTXLocByBlockNumTranNum(blockNum, tranNum)
 	if err != nil {
 		return nil, err
@@ -552,6 +831,9 @@ func (mgr *blockfileMgr) fetchTransactionEnvelope(lp *fileLocPointer) (*common.E
 }
 
 func (mgr *blockfileMgr) fetchBlockBytes(lp *fileLocPointer) ([]byte, error) {
+	if err := mgr.checkFileNotPruned(lp.fileSuffixNum); err != nil {
+		return nil, err
+	}
 	stream, err := newBlockfileStream(mgr.rootDir, lp.fileSuffixNum, int64(lp.offset))
 	if err != nil {
 		return nil, err
@@ -565,6 +847,9 @@ func (mgr *blockfileMgr) fetchBlockBytes(lp *fileLocPointer) ([]byte, error) {
 }
 
 func (mgr *blockfileMgr) fetchRawBytes(lp *fileLocPointer) ([]byte, error) {
+	if err := mgr.checkFileNotPruned(lp.fileSuffixNum); err != nil {
+		return nil, err
+	}
 	filePath := deriveBlockfilePath(mgr.rootDir, lp.fileSuffixNum)
 	reader, err := newBlockfileReader(filePath)
 	if err != nil {
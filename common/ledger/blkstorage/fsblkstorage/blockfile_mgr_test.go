@@ -338,3 +338,42 @@ func TestBlockfileMgrGetBlockByTxID(t *testing.T) {
 		}
 	}
 }
+
+func TestBlockfileMgrBootstrap(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	configBlock, lastBlock := blocks[2], blocks[5]
+
+	assert.EqualError(t, blkfileMgrWrapper.blockfileMgr.bootstrap(configBlock, lastBlock), "config block [5] follows the last block [2]")
+	assert.NoError(t, blkfileMgrWrapper.blockfileMgr.bootstrap(lastBlock, configBlock))
+	bcInfo := blkfileMgrWrapper.blockfileMgr.getBlockchainInfo()
+	assert.Equal(t, &common.BlockchainInfo{
+		Height:            6,
+		CurrentBlockHash:  lastBlock.Header.Hash(),
+		PreviousBlockHash: lastBlock.Header.PreviousHash,
+	}, bcInfo)
+	assert.EqualError(t, blkfileMgrWrapper.blockfileMgr.bootstrap(lastBlock, configBlock), "the blockchain is not empty, its height is 6")
+
+	// the blocks are added after the last block of the snapshot
+	assert.Error(t, blkfileMgrWrapper.blockfileMgr.addBlock(blocks[7]))
+	blkfileMgrWrapper.addBlocks(blocks[6:8])
+	blkfileMgrWrapper.close()
+
+	// the bootstrapped blockchain is restored on restart
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	assert.Equal(t, uint64(8), blkfileMgrWrapper.blockfileMgr.getBlockchainInfo().Height)
+	blkfileMgrWrapper.testGetBlockByNumber(blocks[5:8], 5)
+	block, err := blkfileMgrWrapper.blockfileMgr.retrieveBlockByNumber(2)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(configBlock, block))
+	_, err = blkfileMgrWrapper.blockfileMgr.retrieveBlockByNumber(3)
+	assert.Error(t, err)
+	itr, err := blkfileMgrWrapper.blockfileMgr.retrieveBlocks(3)
+	assert.NoError(t, err)
+	defer itr.Close()
+	_, err = itr.Next()
+	assert.Error(t, err)
+}
@@ -0,0 +1,267 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	putil "github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+var pruningInfoKey = []byte("blkMgrPruningInfo")
+
+// pruningInfo tracks the oldest block file which has not been pruned, and
+// the first block it holds
+type pruningInfo struct {
+	firstFileNum  int
+	firstBlockNum uint64
+}
+
+func (i *pruningInfo) marshal() ([]byte, error) {
+	buffer := proto.NewBuffer([]byte{})
+	if err := buffer.EncodeVarint(uint64(i.firstFileNum)); err != nil {
+		return nil, err
+	}
+	if err := buffer.EncodeVarint(i.firstBlockNum); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (i *pruningInfo) unmarshal(b []byte) error {
+	buffer := proto.NewBuffer(b)
+	val, err := buffer.DecodeVarint()
+	if err != nil {
+		return err
+	}
+	i.firstFileNum = int(val)
+	if i.firstBlockNum, err = buffer.DecodeVarint(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (mgr *blockfileMgr) loadPruningInfo() (*pruningInfo, error) {
+	b, err := mgr.db.Get(pruningInfoKey)
+	if err != nil {
+		return nil, err
+	}
+	i := &pruningInfo{}
+	if b == nil {
+		return i, nil
+	}
+	if err := i.unmarshal(b); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+func (mgr *blockfileMgr) getPruningInfo() *pruningInfo {
+	mgr.prunedLock.RLock()
+	defer mgr.prunedLock.RUnlock()
+	return mgr.pruningInfo
+}
+
+// checkBlockNotPruned returns an error if the block has been pruned
+func (mgr *blockfileMgr) checkBlockNotPruned(blockNum uint64) error {
+	if first := mgr.getPruningInfo().firstBlockNum; blockNum < first {
+		return errors.Errorf("block [%d] has been pruned, the first block available is [%d]", blockNum, first)
+	}
+	return nil
+}
+
+// checkFileNotPruned returns an error if the block file has been pruned
+func (mgr *blockfileMgr) checkFileNotPruned(fileNum int) error {
+	if info := mgr.getPruningInfo(); fileNum < info.firstFileNum {
+		return errors.Errorf("block file [%d] has been pruned, the first block available is [%d]", fileNum, info.firstBlockNum)
+	}
+	return nil
+}
+
+// prune prunes the block files holding only blocks more than
+// conf.retainBlocks blocks below the height of the blockchain, once block
+// has been added. The block file being written to is never pruned, and the
+// last config block is kept in the db when the file holding it is pruned.
+func (mgr *blockfileMgr) prune(block *common.Block) error {
+	if mgr.conf.retainBlocks == 0 || block.Header.Number+1 <= mgr.conf.retainBlocks {
+		return nil
+	}
+	pruneBelow := block.Header.Number + 1 - mgr.conf.retainBlocks
+
+	current := mgr.getPruningInfo()
+	next := &pruningInfo{firstFileNum: current.firstFileNum, firstBlockNum: current.firstBlockNum}
+	for next.firstFileNum < mgr.cpInfo.latestFileChunkSuffixNum {
+		if mgr.nextFileFirstBlockNum == 0 {
+			firstBlockNum, ok, err := firstBlockNumInFile(mgr.rootDir, next.firstFileNum+1)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				break
+			}
+			mgr.nextFileFirstBlockNum = firstBlockNum
+		}
+		if mgr.nextFileFirstBlockNum > pruneBelow {
+			break
+		}
+		next.firstFileNum++
+		next.firstBlockNum = mgr.nextFileFirstBlockNum
+		mgr.nextFileFirstBlockNum = 0
+	}
+	if next.firstFileNum == current.firstFileNum {
+		return nil
+	}
+
+	if err := mgr.retainLastConfigBlock(block, next.firstBlockNum); err != nil {
+		return err
+	}
+	b, err := next.marshal()
+	if err != nil {
+		return err
+	}
+	if err := mgr.db.Put(pruningInfoKey, b, true); err != nil {
+		return errors.WithMessage(err, "error saving the pruning info to db")
+	}
+	mgr.prunedLock.Lock()
+	mgr.pruningInfo = next
+	mgr.prunedLock.Unlock()
+	logger.Infof("Pruned the blocks below block [%d] of ledger [%s]", next.firstBlockNum, filepath.Base(mgr.rootDir))
+	mgr.removePrunedFiles()
+	return nil
+}
+
+// retainLastConfigBlock saves the last config block to the db, so that it
+// is still retrieved by number, when it is below the first block kept
+func (mgr *blockfileMgr) retainLastConfigBlock(block *common.Block, firstBlockNum uint64) error {
+	lastConfig, err := putil.GetLastConfigIndexFromBlock(block)
+	if err != nil {
+		// the blocks of the test ledgers have no last config index
+		logger.Debugf("Block [%d] has no last config index: %s", block.Header.Number, err)
+		return nil
+	}
+	if lastConfig >= firstBlockNum {
+		return nil
+	}
+	if configBlock := mgr.getBootstrapConfigBlock(); configBlock != nil && configBlock.Header.Number == lastConfig {
+		return nil
+	}
+	configBlock, err := mgr.retrieveBlockByNumber(lastConfig)
+	if err != nil {
+		return errors.WithMessage(err, "error retrieving the last config block")
+	}
+	configBlockBytes, err := proto.Marshal(configBlock)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling the config block")
+	}
+	if err := mgr.db.Put(bootstrapConfigBlockKey, configBlockBytes, true); err != nil {
+		return errors.WithMessage(err, "error saving the config block to db")
+	}
+	mgr.prunedLock.Lock()
+	mgr.bootstrapConfigBlock = configBlock
+	mgr.prunedLock.Unlock()
+	return nil
+}
+
+// removePrunedFiles moves the block files which have been pruned to the
+// archive directory, or removes them if there is none. The files which
+// can't be moved are left in place and moved again at the next pruning or
+// at the next start.
+func (mgr *blockfileMgr) removePrunedFiles() {
+	firstFileNum := mgr.getPruningInfo().firstFileNum
+	filesInfo, err := ioutil.ReadDir(mgr.rootDir)
+	if err != nil {
+		logger.Warningf("Failed reading dir %s: %s", mgr.rootDir, err)
+		return
+	}
+	var archiveDir string
+	if mgr.conf.archiveDir != "" {
+		archiveDir = filepath.Join(mgr.conf.archiveDir, filepath.Base(mgr.rootDir))
+		if _, err := util.CreateDirIfMissing(archiveDir); err != nil {
+			logger.Warningf("Failed creating the archive dir %s: %s", archiveDir, err)
+			return
+		}
+	}
+	for _, fileInfo := range filesInfo {
+		name := fileInfo.Name()
+		if fileInfo.IsDir() || !isBlockFileName(name) {
+			continue
+		}
+		fileNum, err := strconv.Atoi(strings.TrimPrefix(name, blockfilePrefix))
+		if err != nil || fileNum >= firstFileNum {
+			continue
+		}
+		filePath := filepath.Join(mgr.rootDir, name)
+		if archiveDir != "" {
+			err = moveFile(filePath, filepath.Join(archiveDir, name))
+		} else {
+			err = os.Remove(filePath)
+		}
+		if err != nil {
+			logger.Warningf("Failed removing the pruned block file %s: %s", filePath, err)
+		}
+	}
+}
+
+// moveFile renames the file, or copies it and removes the original when it
+// can't be renamed, e.g. across file systems
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
+
+// firstBlockNumInFile returns the number of the first block of the block
+// file, and false if the file holds no complete block
+func firstBlockNumInFile(rootDir string, fileNum int) (uint64, bool, error) {
+	stream, err := newBlockfileStream(rootDir, fileNum, 0)
+	if err != nil {
+		return 0, false, err
+	}
+	defer stream.close()
+	blockBytes, err := stream.nextBlockBytes()
+	if err == ErrUnexpectedEndOfBlockfile || (err == nil && blockBytes == nil) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	info, err := extractSerializedBlockInfo(blockBytes)
+	if err != nil {
+		return 0, false, err
+	}
+	return info.blockHeader.Number, true, nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	putil "github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockfileMgrPruning(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 100)
+	size := 0
+	for _, block := range blocks[:10] {
+		by, _, err := serializeBlock(block)
+		assert.NoError(t, err)
+		size += len(by) + len(proto.EncodeVarint(uint64(len(by))))
+	}
+	// block 0 stands for the last config block of every block
+	lastConfig := putil.MarshalOrPanic(&common.Metadata{Value: putil.MarshalOrPanic(&common.LastConfig{Index: 0})})
+	for _, block := range blocks {
+		block.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG] = lastConfig
+	}
+
+	t.Run("archive", func(t *testing.T) {
+		archiveDir := testPath()
+		defer os.RemoveAll(archiveDir)
+		env := newTestEnv(t, NewConf(testPath(), size).WithPruning(30, archiveDir))
+		defer env.Cleanup()
+		w := newTestBlockfileWrapper(env, "testLedger")
+		w.addBlocks(blocks)
+
+		assertPruned := func(w *testBlockfileMgrWrapper) {
+			info := w.blockfileMgr.getPruningInfo()
+			assert.True(t, info.firstFileNum > 0)
+			assert.True(t, info.firstBlockNum > 0 && info.firstBlockNum <= 70)
+			exists, _, err := util.FileExists(deriveBlockfilePath(w.blockfileMgr.rootDir, 0))
+			assert.NoError(t, err)
+			assert.False(t, exists)
+			exists, _, err = util.FileExists(filepath.Join(archiveDir, "testLedger", blockfilePrefix+"000000"))
+			assert.NoError(t, err)
+			assert.True(t, exists)
+
+			// the last config block is still available
+			block, err := w.blockfileMgr.retrieveBlockByNumber(0)
+			assert.NoError(t, err)
+			assert.True(t, proto.Equal(blocks[0], block))
+			w.testGetBlockByNumber(blocks[70:], 70)
+
+			_, err = w.blockfileMgr.retrieveBlockByNumber(1)
+			assert.Contains(t, err.Error(), "block [1] has been pruned")
+			txID, err := extractTxID(blocks[1].Data.Data[0])
+			assert.NoError(t, err)
+			_, err = w.blockfileMgr.retrieveBlockByTxID(txID)
+			assert.Contains(t, err.Error(), "has been pruned")
+			itr, err := w.blockfileMgr.retrieveBlocks(1)
+			assert.NoError(t, err)
+			defer itr.Close()
+			_, err = itr.Next()
+			assert.Contains(t, err.Error(), "block [1] has been pruned")
+		}
+		assertPruned(w)
+		w.close()
+
+		w = newTestBlockfileWrapper(env, "testLedger")
+		defer w.close()
+		assertPruned(w)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		env := newTestEnv(t, NewConf(testPath(), size).WithPruning(30, ""))
+		defer env.Cleanup()
+		w := newTestBlockfileWrapper(env, "testLedger")
+		defer w.close()
+		w.addBlocks(blocks)
+
+		firstFileNum := w.blockfileMgr.getPruningInfo().firstFileNum
+		assert.True(t, firstFileNum > 0)
+		for fileNum := 0; fileNum < firstFileNum; fileNum++ {
+			exists, _, err := util.FileExists(deriveBlockfilePath(w.blockfileMgr.rootDir, fileNum))
+			assert.NoError(t, err)
+			assert.False(t, exists)
+		}
+		w.testGetBlockByNumber(blocks[70:], 70)
+	})
+
+	t.Run("no pruning", func(t *testing.T) {
+		env := newTestEnv(t, NewConf(testPath(), size))
+		defer env.Cleanup()
+		w := newTestBlockfileWrapper(env, "testLedger")
+		defer w.close()
+		w.addBlocks(blocks)
+
+		assert.Equal(t, &pruningInfo{}, w.blockfileMgr.getPruningInfo())
+		w.testGetBlockByNumber(blocks, 0)
+	})
+}
@@ -8,9 +8,14 @@ package fsblkstorage
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
@@ -27,12 +32,25 @@ const (
 	blockNumTranNumIdxKeyPrefix    = 'a'
 	blockTxIDIdxKeyPrefix          = 'b'
 	txValidationResultIdxKeyPrefix = 'v'
+	txCreatorIdxKeyPrefix          = 'c'
+	blockTimeIdxKeyPrefix          = 'm'
+	txDedupKeyIdxKeyPrefix         = 'd'
 	indexCheckpointKeyStr          = "indexCheckpointKey"
+	indexedAttrsKeyStr             = "indexedAttrsKey"
 )
 
 var indexCheckpointKey = []byte(indexCheckpointKeyStr)
+var indexedAttrsKey = []byte(indexedAttrsKeyStr)
 var errIndexEmpty = errors.New("NoBlockIndexed")
 
+// backfillableAttrs are the attributes which, when enabled on an existing index,
+// get indexed for the blocks that were indexed before
+var backfillableAttrs = []blkstorage.IndexableAttr{
+	blkstorage.IndexableAttrTxCreator,
+	blkstorage.IndexableAttrBlockTime,
+	blkstorage.IndexableAttrTxDedupKey,
+}
+
 type index interface {
 	getLastBlockIndexed() (uint64, error)
 	indexBlock(blockIdxInfo *blockIdxInfo) error
@@ -42,6 +60,12 @@ type index interface {
 	getTXLocByBlockNumTranNum(blockNum uint64, tranNum uint64) (*fileLocPointer, error)
 	getBlockLocByTxID(txID string) (*fileLocPointer, error)
 	getTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error)
+	getTxLocsByCreator(creator []byte, startBlockNum uint64, limit int) ([]*creatorTxLoc, error)
+	getBlockLocsByTime(start, end time.Time, limit int) ([]*fileLocPointer, error)
+	getBlockNumByDedupKey(namespace, dedupKey string) (uint64, error)
+	getAttrsToBackfill() ([]blkstorage.IndexableAttr, error)
+	backfillBlock(blockIdxInfo *blockIdxInfo, attrs []blkstorage.IndexableAttr) error
+	markAttrsIndexed() error
 }
 
 type blockIdxInfo struct {
@@ -52,6 +76,14 @@ type blockIdxInfo struct {
 	metadata  *common.BlockMetadata
 }
 
+// creatorTxLoc is an entry of the creator index, locating a transaction along with its validation result
+type creatorTxLoc struct {
+	blockNum       uint64
+	txNum          uint64
+	validationCode peer.TxValidationCode
+	flp            *fileLocPointer
+}
+
 type blockIndex struct {
 	indexItemsMap map[blkstorage.IndexableAttr]bool
 	db            *leveldbhelper.DBHandle
@@ -72,7 +104,17 @@ func newBlockIndex(indexConfig *blkstorage.IndexConfig, db *leveldbhelper.DBHand
 		return nil, errors.Errorf("dependent index [%s] is not enabled for [%s] or [%s]",
 			blkstorage.IndexableAttrTxID, blkstorage.IndexableAttrTxValidationCode, blkstorage.IndexableAttrBlockTxID)
 	}
-	return &blockIndex{indexItemsMap, db}, nil
+	index := &blockIndex{indexItemsMap, db}
+	// An empty index gets built with all the configured attributes. Recording them
+	// allows detecting, on a later start, the attributes enabled in the meantime
+	if _, err := index.getLastBlockIndexed(); err == errIndexEmpty {
+		if err := index.markAttrsIndexed(); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	return index, nil
 }
 
 func (index *blockIndex) getLastBlockIndexed() (uint64, error) {
@@ -167,6 +209,23 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 		}
 	}
 
+	// Index7 - Store transaction location and validation result by creator, used to list the transactions of an identity
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxCreator]; ok {
+		if err := addTxCreatorEntries(batch, blockIdxInfo, txsfltr); err != nil {
+			return err
+		}
+	}
+
+	// Index8 - Store block location by block time, used to find the blocks committed within a time range
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockTime]; ok {
+		addBlockTimeEntry(batch, blockIdxInfo, flpBytes)
+	}
+
+	// Index9 - Store block number by chaincode and dedup key of the valid transactions, used to detect duplicate submissions
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxDedupKey]; ok {
+		addTxDedupKeyEntries(batch, blockIdxInfo, txsfltr)
+	}
+
 	batch.Put(indexCheckpointKey, encodeBlockNum(blockIdxInfo.blockNum))
 	// Setting snyc to true as a precaution, false may be an ok optimization after further testing.
 	if err := index.db.WriteBatch(batch, true); err != nil {
@@ -175,6 +234,102 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 	return nil
 }
 
+// backfillBlock indexes the given attributes for an already indexed block
+func (index *blockIndex) backfillBlock(blockIdxInfo *blockIdxInfo, attrs []blkstorage.IndexableAttr) error {
+	txsfltr := ledgerUtil.TxValidationFlags(blockIdxInfo.metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	batch := leveldbhelper.NewUpdateBatch()
+	flpBytes, err := blockIdxInfo.flp.marshal()
+	if err != nil {
+		return err
+	}
+	for _, attr := range attrs {
+		switch attr {
+		case blkstorage.IndexableAttrTxCreator:
+			if err := addTxCreatorEntries(batch, blockIdxInfo, txsfltr); err != nil {
+				return err
+			}
+		case blkstorage.IndexableAttrBlockTime:
+			addBlockTimeEntry(batch, blockIdxInfo, flpBytes)
+		case blkstorage.IndexableAttrTxDedupKey:
+			addTxDedupKeyEntries(batch, blockIdxInfo, txsfltr)
+		default:
+			return errors.Errorf("attribute [%s] cannot be backfilled", attr)
+		}
+	}
+	// the attributes are marked as indexed, with sync, only after all the blocks are backfilled
+	return index.db.WriteBatch(batch, false)
+}
+
+// getAttrsToBackfill returns the configured attributes which were not indexed for the blocks already in the index
+func (index *blockIndex) getAttrsToBackfill() ([]blkstorage.IndexableAttr, error) {
+	b, err := index.db.Get(indexedAttrsKey)
+	if err != nil {
+		return nil, err
+	}
+	indexed := make(map[blkstorage.IndexableAttr]bool)
+	// indexes built before the indexed attributes were recorded did not index any of the backfillable attributes
+	if b != nil {
+		for _, attr := range strings.Split(string(b), ",") {
+			indexed[blkstorage.IndexableAttr(attr)] = true
+		}
+	}
+	var attrs []blkstorage.IndexableAttr
+	for _, attr := range backfillableAttrs {
+		if index.indexItemsMap[attr] && !indexed[attr] {
+			attrs = append(attrs, attr)
+		}
+	}
+	return attrs, nil
+}
+
+// markAttrsIndexed records the configured attributes as indexed for all the blocks in the index
+func (index *blockIndex) markAttrsIndexed() error {
+	var attrs []string
+	for attr := range index.indexItemsMap {
+		attrs = append(attrs, string(attr))
+	}
+	sort.Strings(attrs)
+	return index.db.Put(indexedAttrsKey, []byte(strings.Join(attrs, ",")), true)
+}
+
+func addTxCreatorEntries(batch *leveldbhelper.UpdateBatch, blockIdxInfo *blockIdxInfo, txsfltr ledgerUtil.TxValidationFlags) error {
+	flp := blockIdxInfo.flp
+	for txIterator, txoffset := range blockIdxInfo.txOffsets {
+		if len(txoffset.creator) == 0 {
+			continue
+		}
+		txFlp := newFileLocationPointer(flp.fileSuffixNum, flp.offset, txoffset.loc)
+		txFlpBytes, err := txFlp.marshal()
+		if err != nil {
+			return err
+		}
+		value := append([]byte{byte(txsfltr.Flag(txIterator))}, txFlpBytes...)
+		batch.Put(constructTxCreatorKey(txoffset.creator, blockIdxInfo.blockNum, uint64(txIterator)), value)
+	}
+	return nil
+}
+
+func addBlockTimeEntry(batch *leveldbhelper.UpdateBatch, blockIdxInfo *blockIdxInfo, flpBytes []byte) {
+	blockTime, ok := blockIdxInfo.blockTime()
+	if !ok {
+		logger.Debugf("Block [%d] has no timestamp. Not indexing in block time index", blockIdxInfo.blockNum)
+		return
+	}
+	batch.Put(constructBlockTimeKey(blockTime, blockIdxInfo.blockNum), flpBytes)
+}
+
+// addTxDedupKeyEntries indexes the dedup keys of the valid transactions of the block. A later
+// valid transaction with the same dedup key, committed once the key left the dedup window of
+// the channel, replaces the entry
+func addTxDedupKeyEntries(batch *leveldbhelper.UpdateBatch, blockIdxInfo *blockIdxInfo, txsfltr ledgerUtil.TxValidationFlags) {
+	for txIterator, txoffset := range blockIdxInfo.txOffsets {
+		if txoffset.dedupKey == "" || !txsfltr.IsValid(txIterator) {
+			continue
+		}
+		batch.Put(constructTxDedupKey(txoffset.dedupNs, txoffset.dedupKey), encodeBlockNum(blockIdxInfo.blockNum))
+	}
+}
+
 func (index *blockIndex) markDuplicateTxids(blockIdxInfo *blockIdxInfo) error {
 	uniqueTxids := make(map[string]bool)
 	for _, txIdxInfo := range blockIdxInfo.txOffsets {
@@ -297,6 +452,72 @@ func (index *blockIndex) getTxValidationCodeByTxID(txID string) (peer.TxValidati
 	return result, nil
 }
 
+func (index *blockIndex) getTxLocsByCreator(creator []byte, startBlockNum uint64, limit int) ([]*creatorTxLoc, error) {
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxCreator]; !ok {
+		return nil, blkstorage.ErrAttrNotIndexed
+	}
+	startKey := append(constructTxCreatorPrefix(creator), util.EncodeOrderPreservingVarUint64(startBlockNum)...)
+	// the order preserving encoding of a number never starts with 0xff
+	endKey := append(constructTxCreatorPrefix(creator), 0xff)
+	itr := index.db.GetIterator(startKey, endKey)
+	defer itr.Release()
+
+	var locs []*creatorTxLoc
+	for (limit == 0 || len(locs) < limit) && itr.Next() {
+		key, value := itr.Key(), itr.Value()
+		if len(value) < 2 {
+			return nil, errors.New("invalid value in creator index")
+		}
+		loc := &creatorTxLoc{validationCode: peer.TxValidationCode(int32(value[0])), flp: &fileLocPointer{}}
+		nums := key[1+sha256.Size:]
+		var n int
+		loc.blockNum, n = util.DecodeOrderPreservingVarUint64(nums)
+		loc.txNum, _ = util.DecodeOrderPreservingVarUint64(nums[n:])
+		if err := loc.flp.unmarshal(value[1:]); err != nil {
+			return nil, err
+		}
+		locs = append(locs, loc)
+	}
+	return locs, nil
+}
+
+func (index *blockIndex) getBlockLocsByTime(start, end time.Time, limit int) ([]*fileLocPointer, error) {
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockTime]; !ok {
+		return nil, blkstorage.ErrAttrNotIndexed
+	}
+	if !start.Before(end) {
+		return nil, nil
+	}
+	itr := index.db.GetIterator(constructBlockTimePrefix(unixNanos(start)), constructBlockTimePrefix(unixNanos(end)))
+	defer itr.Release()
+
+	var locs []*fileLocPointer
+	for (limit == 0 || len(locs) < limit) && itr.Next() {
+		blkLoc := &fileLocPointer{}
+		if err := blkLoc.unmarshal(itr.Value()); err != nil {
+			return nil, err
+		}
+		locs = append(locs, blkLoc)
+	}
+	return locs, nil
+}
+
+// getBlockNumByDedupKey returns the number of the block of the last valid transaction
+// carrying the given dedup key for the given chaincode
+func (index *blockIndex) getBlockNumByDedupKey(namespace, dedupKey string) (uint64, error) {
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxDedupKey]; !ok {
+		return 0, blkstorage.ErrAttrNotIndexed
+	}
+	b, err := index.db.Get(constructTxDedupKey(namespace, dedupKey))
+	if err != nil {
+		return 0, err
+	}
+	if b == nil {
+		return 0, blkstorage.ErrNotFoundInIndex
+	}
+	return decodeBlockNum(b), nil
+}
+
 func constructBlockNumKey(blockNum uint64) []byte {
 	blkNumBytes := util.EncodeOrderPreservingVarUint64(blockNum)
 	return append([]byte{blockNumIdxKeyPrefix}, blkNumBytes...)
@@ -325,6 +546,40 @@ func constructBlockNumTranNumKey(blockNum uint64, txNum uint64) []byte {
 	return append([]byte{blockNumTranNumIdxKeyPrefix}, key...)
 }
 
+func constructTxCreatorPrefix(creator []byte) []byte {
+	creatorHash := sha256.Sum256(creator)
+	return append([]byte{txCreatorIdxKeyPrefix}, creatorHash[:]...)
+}
+
+func constructTxCreatorKey(creator []byte, blockNum uint64, txNum uint64) []byte {
+	key := constructTxCreatorPrefix(creator)
+	key = append(key, util.EncodeOrderPreservingVarUint64(blockNum)...)
+	return append(key, util.EncodeOrderPreservingVarUint64(txNum)...)
+}
+
+// constructTxDedupKey separates the chaincode name, which never contains a 0 byte, from the dedup key
+func constructTxDedupKey(namespace, dedupKey string) []byte {
+	key := append([]byte{txDedupKeyIdxKeyPrefix}, []byte(namespace)...)
+	key = append(key, 0x00)
+	return append(key, []byte(dedupKey)...)
+}
+
+func constructBlockTimePrefix(blockTime uint64) []byte {
+	return append([]byte{blockTimeIdxKeyPrefix}, util.EncodeOrderPreservingVarUint64(blockTime)...)
+}
+
+func constructBlockTimeKey(blockTime uint64, blockNum uint64) []byte {
+	return append(constructBlockTimePrefix(blockTime), util.EncodeOrderPreservingVarUint64(blockNum)...)
+}
+
+// unixNanos returns the nanoseconds elapsed since the epoch, times before the epoch being mapped to zero
+func unixNanos(t time.Time) uint64 {
+	if t.Before(time.Unix(0, 0)) {
+		return 0
+	}
+	return uint64(t.UnixNano())
+}
+
 func encodeBlockNum(blockNum uint64) []byte {
 	return proto.EncodeVarint(blockNum)
 }
@@ -399,6 +654,19 @@ func (flp *fileLocPointer) String() string {
 	return fmt.Sprintf("fileSuffixNum=%d, %s", flp.fileSuffixNum, flp.locPointer.String())
 }
 
+// blockTime returns the time of the block in nanoseconds since the epoch,
+// which is the timestamp of its first transaction
+func (blockIdxInfo *blockIdxInfo) blockTime() (uint64, bool) {
+	if len(blockIdxInfo.txOffsets) == 0 || blockIdxInfo.txOffsets[0].timestamp == nil {
+		return 0, false
+	}
+	t, err := ptypes.Timestamp(blockIdxInfo.txOffsets[0].timestamp)
+	if err != nil {
+		return 0, false
+	}
+	return unixNanos(t), true
+}
+
 func (blockIdxInfo *blockIdxInfo) String() string {
 
 	var buffer bytes.Buffer
@@ -19,9 +19,12 @@ package fsblkstorage
 import (
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	l "github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/peer"
@@ -59,6 +62,30 @@ func (i *noopIndex) getTxValidationCodeByTxID(txID string) (peer.TxValidationCod
 	return peer.TxValidationCode(-1), nil
 }
 
+func (i *noopIndex) getTxLocsByCreator(creator []byte, startBlockNum uint64, limit int) ([]*creatorTxLoc, error) {
+	return nil, nil
+}
+
+func (i *noopIndex) getBlockLocsByTime(start, end time.Time, limit int) ([]*fileLocPointer, error) {
+	return nil, nil
+}
+
+func (i *noopIndex) getBlockNumByDedupKey(namespace, dedupKey string) (uint64, error) {
+	return 0, nil
+}
+
+func (i *noopIndex) getAttrsToBackfill() ([]blkstorage.IndexableAttr, error) {
+	return nil, nil
+}
+
+func (i *noopIndex) backfillBlock(blockIdxInfo *blockIdxInfo, attrs []blkstorage.IndexableAttr) error {
+	return nil
+}
+
+func (i *noopIndex) markAttrsIndexed() error {
+	return nil
+}
+
 func TestBlockIndexSync(t *testing.T) {
 	testBlockIndexSync(t, 10, 5, false)
 	testBlockIndexSync(t, 10, 5, true)
@@ -257,3 +284,206 @@ func containsAttr(indexItems []blkstorage.IndexableAttr, attr blkstorage.Indexab
 	}
 	return false
 }
+
+func TestBlockIndexCreatorAndTime(t *testing.T) {
+	env := newTestEnvSelectiveIndexing(t, NewConf(testPath(), 0), explorerTestAttrs)
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testledger")
+	defer blkfileMgrWrapper.close()
+	blkfileMgrWrapper.addBlocks(constructExplorerTestBlocks(t))
+	blockfileMgr := blkfileMgrWrapper.blockfileMgr
+
+	txs, err := blockfileMgr.retrieveTxsByCreator([]byte("alice"), 0, 0)
+	assert.NoError(t, err)
+	assertTxPositions(t, [][2]uint64{{0, 0}, {1, 1}, {2, 0}}, txs)
+	assert.Equal(t, int32(peer.TxValidationCode_VALID), txs[0].Transaction.ValidationCode)
+	assert.Equal(t, int32(peer.TxValidationCode_MVCC_READ_CONFLICT), txs[2].Transaction.ValidationCode)
+	txid, err := extractTxID(putil.MarshalOrPanic(txs[1].Transaction.TransactionEnvelope))
+	assert.NoError(t, err)
+	assert.Equal(t, "tx-alice-1", txid)
+
+	txs, err = blockfileMgr.retrieveTxsByCreator([]byte("alice"), 0, 2)
+	assert.NoError(t, err)
+	assertTxPositions(t, [][2]uint64{{0, 0}, {1, 1}}, txs)
+
+	txs, err = blockfileMgr.retrieveTxsByCreator([]byte("alice"), 2, 0)
+	assert.NoError(t, err)
+	assertTxPositions(t, [][2]uint64{{2, 0}}, txs)
+
+	txs, err = blockfileMgr.retrieveTxsByCreator([]byte("bob"), 0, 0)
+	assert.NoError(t, err)
+	assertTxPositions(t, [][2]uint64{{1, 0}}, txs)
+
+	txs, err = blockfileMgr.retrieveTxsByCreator([]byte("carol"), 0, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, txs)
+
+	blocks, err := blockfileMgr.retrieveBlocksByTimeRange(explorerTestTime(0), explorerTestTime(2), 0)
+	assert.NoError(t, err)
+	assertBlockNumbers(t, []uint64{0, 1}, blocks)
+
+	blocks, err = blockfileMgr.retrieveBlocksByTimeRange(explorerTestTime(1), explorerTestTime(3), 1)
+	assert.NoError(t, err)
+	assertBlockNumbers(t, []uint64{1}, blocks)
+
+	blocks, err = blockfileMgr.retrieveBlocksByTimeRange(explorerTestTime(2), explorerTestTime(2), 0)
+	assert.NoError(t, err)
+	assert.Empty(t, blocks)
+}
+
+func TestBlockIndexCreatorAndTimeNotIndexed(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testledger")
+	defer blkfileMgrWrapper.close()
+	blkfileMgrWrapper.addBlocks(constructExplorerTestBlocks(t))
+
+	_, err := blkfileMgrWrapper.blockfileMgr.retrieveTxsByCreator([]byte("alice"), 0, 0)
+	assert.Exactly(t, blkstorage.ErrAttrNotIndexed, err)
+	_, err = blkfileMgrWrapper.blockfileMgr.retrieveBlocksByTimeRange(explorerTestTime(0), explorerTestTime(3), 0)
+	assert.Exactly(t, blkstorage.ErrAttrNotIndexed, err)
+}
+
+func TestBlockIndexDedupKey(t *testing.T) {
+	env := newTestEnvSelectiveIndexing(t, NewConf(testPath(), 0), []blkstorage.IndexableAttr{blkstorage.IndexableAttrTxDedupKey})
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testledger")
+	defer blkfileMgrWrapper.close()
+
+	// the transaction with dedup key k1 of block 1 is invalid
+	dedupKeys := [][]string{{"k1"}, {"k1", "k2", ""}, {"k1"}}
+	var blocks []*common.Block
+	var prevHash []byte
+	for blockNum, keys := range dedupKeys {
+		var envs []*common.Envelope
+		for txNum, key := range keys {
+			chdr := putil.MakeChannelHeader(common.HeaderType_ENDORSER_TRANSACTION, 0, "testchain", 0)
+			chdr.TxId = fmt.Sprintf("tx-%d-%d", blockNum, txNum)
+			chdr.Extension = putil.MarshalOrPanic(&peer.ChaincodeHeaderExtension{ChaincodeId: &peer.ChaincodeID{Name: "mycc"}, DedupKey: key})
+			payload := &common.Payload{Header: putil.MakePayloadHeader(chdr, &common.SignatureHeader{})}
+			envs = append(envs, &common.Envelope{Payload: putil.MarshalOrPanic(payload)})
+		}
+		block := testutil.NewBlock(envs, uint64(blockNum), prevHash)
+		prevHash = block.Header.Hash()
+		blocks = append(blocks, block)
+	}
+	util.TxValidationFlags(blocks[1].Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER]).SetFlag(0, peer.TxValidationCode_MVCC_READ_CONFLICT)
+	blkfileMgrWrapper.addBlocks(blocks[:2])
+	blockfileMgr := blkfileMgrWrapper.blockfileMgr
+
+	blockNum, err := blockfileMgr.retrieveBlockNumByDedupKey("mycc", "k1")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), blockNum)
+	blockNum, err = blockfileMgr.retrieveBlockNumByDedupKey("mycc", "k2")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), blockNum)
+
+	blkfileMgrWrapper.addBlocks(blocks[2:])
+	blockNum, err = blockfileMgr.retrieveBlockNumByDedupKey("mycc", "k1")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), blockNum)
+
+	_, err = blockfileMgr.retrieveBlockNumByDedupKey("mycc", "k3")
+	assert.Exactly(t, blkstorage.ErrNotFoundInIndex, err)
+	_, err = blockfileMgr.retrieveBlockNumByDedupKey("othercc", "k1")
+	assert.Exactly(t, blkstorage.ErrNotFoundInIndex, err)
+	_, err = blockfileMgr.retrieveBlockNumByDedupKey("mycc", "")
+	assert.Exactly(t, blkstorage.ErrNotFoundInIndex, err)
+}
+
+func TestBlockIndexDedupKeyNotIndexed(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testledger")
+	defer blkfileMgrWrapper.close()
+
+	_, err := blkfileMgrWrapper.blockfileMgr.retrieveBlockNumByDedupKey("mycc", "k1")
+	assert.Exactly(t, blkstorage.ErrAttrNotIndexed, err)
+}
+
+func TestBlockIndexBackfill(t *testing.T) {
+	testBlockIndexBackfill(t, false)
+	// an index built before the indexed attributes were recorded
+	testBlockIndexBackfill(t, true)
+}
+
+func testBlockIndexBackfill(t *testing.T, unrecordedAttrs bool) {
+	t.Run(fmt.Sprintf("unrecordedAttrs=%t", unrecordedAttrs), func(t *testing.T) {
+		conf := NewConf(testPath(), 0)
+		env := newTestEnv(t, conf)
+		blkfileMgrWrapper := newTestBlockfileWrapper(env, "testledger")
+		blkfileMgrWrapper.addBlocks(constructExplorerTestBlocks(t))
+		if unrecordedAttrs {
+			assert.NoError(t, blkfileMgrWrapper.blockfileMgr.db.Delete(indexedAttrsKey, true))
+		}
+		blkfileMgrWrapper.close()
+		env.provider.Close()
+
+		env = newTestEnvSelectiveIndexing(t, conf, explorerTestAttrs)
+		defer env.Cleanup()
+		blkfileMgrWrapper = newTestBlockfileWrapper(env, "testledger")
+		defer blkfileMgrWrapper.close()
+		blockfileMgr := blkfileMgrWrapper.blockfileMgr
+
+		txs, err := blockfileMgr.retrieveTxsByCreator([]byte("alice"), 0, 0)
+		assert.NoError(t, err)
+		assertTxPositions(t, [][2]uint64{{0, 0}, {1, 1}, {2, 0}}, txs)
+		blocks, err := blockfileMgr.retrieveBlocksByTimeRange(explorerTestTime(0), explorerTestTime(3), 0)
+		assert.NoError(t, err)
+		assertBlockNumbers(t, []uint64{0, 1, 2}, blocks)
+
+		attrs, err := blockfileMgr.index.getAttrsToBackfill()
+		assert.NoError(t, err)
+		assert.Empty(t, attrs)
+	})
+}
+
+var explorerTestAttrs = []blkstorage.IndexableAttr{
+	blkstorage.IndexableAttrBlockNum,
+	blkstorage.IndexableAttrTxID,
+	blkstorage.IndexableAttrTxCreator,
+	blkstorage.IndexableAttrBlockTime,
+}
+
+func explorerTestTime(hours int) time.Time {
+	return time.Date(2018, time.October, 1, hours, 0, 0, 0, time.UTC)
+}
+
+// constructExplorerTestBlocks constructs three blocks, an hour apart, with transactions of alice and bob.
+// The transaction of alice in the last block is invalid
+func constructExplorerTestBlocks(t *testing.T) []*common.Block {
+	txs := [][]string{{"alice"}, {"bob", "alice"}, {"alice"}}
+	var blocks []*common.Block
+	var prevHash []byte
+	for blockNum, creators := range txs {
+		var envs []*common.Envelope
+		for _, creator := range creators {
+			chdr := putil.MakeChannelHeader(common.HeaderType_ENDORSER_TRANSACTION, 0, "testchain", 0)
+			chdr.TxId = fmt.Sprintf("tx-%s-%d", creator, blockNum)
+			chdr.Timestamp = &timestamp.Timestamp{Seconds: explorerTestTime(blockNum).Unix()}
+			payload := &common.Payload{Header: putil.MakePayloadHeader(chdr, &common.SignatureHeader{Creator: []byte(creator)})}
+			envs = append(envs, &common.Envelope{Payload: putil.MarshalOrPanic(payload)})
+		}
+		block := testutil.NewBlock(envs, uint64(blockNum), prevHash)
+		prevHash = block.Header.Hash()
+		blocks = append(blocks, block)
+	}
+	util.TxValidationFlags(blocks[2].Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER]).SetFlag(0, peer.TxValidationCode_MVCC_READ_CONFLICT)
+	return blocks
+}
+
+func assertTxPositions(t *testing.T, expected [][2]uint64, txs []*l.IndexedTransaction) {
+	var positions [][2]uint64
+	for _, tx := range txs {
+		positions = append(positions, [2]uint64{tx.BlockNum, tx.TxNum})
+	}
+	assert.Equal(t, expected, positions)
+}
+
+func assertBlockNumbers(t *testing.T, expected []uint64, blocks []*common.Block) {
+	var blockNums []uint64
+	for _, block := range blocks {
+		blockNums = append(blockNums, block.Header.Number)
+	}
+	assert.Equal(t, expected, blockNums)
+}
@@ -51,6 +51,12 @@ func (itr *blocksItr) waitForBlock(blockNum uint64) uint64 {
 func (itr *blocksItr) initStream() error {
 	var lp *fileLocPointer
 	var err error
+	if err = itr.mgr.checkBlockNotPruned(itr.blockNumToRetrieve); err != nil {
+		return err
+	}
+	if err = itr.mgr.waitIndexed(itr.blockNumToRetrieve + 1); err != nil {
+		return err
+	}
 	if lp, err = itr.mgr.index.getBlockLocByBlockNum(itr.blockNumToRetrieve); err != nil {
 		return err
 	}
@@ -16,7 +16,10 @@ limitations under the License.
 
 package fsblkstorage
 
-import "path/filepath"
+import (
+	"path/filepath"
+	"time"
+)
 
 const (
 	// ChainsDir is the name of the directory containing the channel ledgers.
@@ -30,6 +33,17 @@ const (
 type Conf struct {
 	blockStorageDir  string
 	maxBlockfileSize int
+	// groupSync is true when the block files are flushed to the disk by group,
+	// within groupSyncMaxDelay of the first block appended to the group,
+	// rather than each block on its own
+	groupSync         bool
+	groupSyncMaxDelay time.Duration
+	// retainBlocks is the number of blocks below the height of the blockchain
+	// which are kept in the block files, 0 keeping all the blocks
+	retainBlocks uint64
+	// archiveDir is the directory the pruned block files are moved to, they
+	// are removed when it is empty
+	archiveDir string
 }
 
 // NewConf constructs new `Conf`.
@@ -38,7 +52,29 @@ func NewConf(blockStorageDir string, maxBlockfileSize int) *Conf {
 	if maxBlockfileSize <= 0 {
 		maxBlockfileSize = defaultMaxBlockfileSize
 	}
-	return &Conf{blockStorageDir, maxBlockfileSize}
+	return &Conf{blockStorageDir: blockStorageDir, maxBlockfileSize: maxBlockfileSize}
+}
+
+// NewConfWithGroupSync constructs new `Conf` whose block files are flushed to
+// the disk by group. The blocks appended by the ledgers of the provider within
+// maxDelay of the first block of a group are flushed together, by a single
+// flush per block file, the addition of each block still returning only once
+// the block is flushed.
+func NewConfWithGroupSync(blockStorageDir string, maxBlockfileSize int, maxDelay time.Duration) *Conf {
+	conf := NewConf(blockStorageDir, maxBlockfileSize)
+	conf.groupSync = true
+	conf.groupSyncMaxDelay = maxDelay
+	return conf
+}
+
+// WithPruning sets the conf to prune the block files holding only blocks
+// more than retainBlocks blocks below the height of the blockchain. The
+// pruned block files are moved to a directory of archiveDir named after the
+// ledger, or removed when archiveDir is empty.
+func (conf *Conf) WithPruning(retainBlocks uint64, archiveDir string) *Conf {
+	conf.retainBlocks = retainBlocks
+	conf.archiveDir = archiveDir
+	return conf
 }
 
 func (conf *Conf) getIndexDir() string {
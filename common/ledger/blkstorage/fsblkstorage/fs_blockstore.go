@@ -17,9 +17,12 @@ limitations under the License.
 package fsblkstorage
 
 import (
+	"time"
+
 	"github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	l "github.com/hyperledger/fabric/core/ledger"
 
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/peer"
@@ -34,8 +37,8 @@ type fsBlockStore struct {
 
 // NewFsBlockStore constructs a `FsBlockStore`
 func newFsBlockStore(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
-	dbHandle *leveldbhelper.DBHandle) *fsBlockStore {
-	return &fsBlockStore{id, conf, newBlockfileMgr(id, conf, indexConfig, dbHandle)}
+	dbHandle *leveldbhelper.DBHandle, syncer *groupSyncer) *fsBlockStore {
+	return &fsBlockStore{id, conf, newBlockfileMgr(id, conf, indexConfig, dbHandle, syncer)}
 }
 
 // AddBlock adds a new block
@@ -86,6 +89,37 @@ func (store *fsBlockStore) RetrieveTxValidationCodeByTxID(txID string) (peer.TxV
 	return store.fileMgr.retrieveTxValidationCodeByTxID(txID)
 }
 
+// RetrieveTxsByCreator returns the transactions created by the given serialized identity
+func (store *fsBlockStore) RetrieveTxsByCreator(creator []byte, startBlockNum uint64, limit int) ([]*l.IndexedTransaction, error) {
+	return store.fileMgr.retrieveTxsByCreator(creator, startBlockNum, limit)
+}
+
+// RetrieveBlocksByTimeRange returns the blocks whose time falls within the given range
+func (store *fsBlockStore) RetrieveBlocksByTimeRange(start, end time.Time, limit int) ([]*common.Block, error) {
+	return store.fileMgr.retrieveBlocksByTimeRange(start, end, limit)
+}
+
+// RetrieveBlockNumByDedupKey returns the number of the block of the last valid
+// transaction carrying the given dedup key for the given chaincode
+func (store *fsBlockStore) RetrieveBlockNumByDedupKey(namespace, dedupKey string) (uint64, error) {
+	return store.fileMgr.retrieveBlockNumByDedupKey(namespace, dedupKey)
+}
+
+// GetIndexedHeight implements method in interface `blkstorage.IndexedHeightReporter`
+func (store *fsBlockStore) GetIndexedHeight() (uint64, error) {
+	return store.fileMgr.getIndexedHeight(), nil
+}
+
+// Bootstrap implements method in interface `blkstorage.BootstrapCapable`
+func (store *fsBlockStore) Bootstrap(lastBlock, configBlock *common.Block) error {
+	return store.fileMgr.bootstrap(lastBlock, configBlock)
+}
+
+// GetBootstrapConfigBlock implements method in interface `blkstorage.BootstrapCapable`
+func (store *fsBlockStore) GetBootstrapConfigBlock() (*common.Block, error) {
+	return store.fileMgr.bootstrapConfigBlock, nil
+}
+
 // Shutdown shuts down the block store
 func (store *fsBlockStore) Shutdown() {
 	logger.Debugf("closing fs blockStore:%s", store.id)
@@ -27,12 +27,18 @@ type FsBlockstoreProvider struct {
 	conf            *Conf
 	indexConfig     *blkstorage.IndexConfig
 	leveldbProvider *leveldbhelper.Provider
+	// syncer is set when the block files are flushed to the disk by group
+	syncer *groupSyncer
 }
 
 // NewProvider constructs a filesystem based block store provider
 func NewProvider(conf *Conf, indexConfig *blkstorage.IndexConfig) blkstorage.BlockStoreProvider {
 	p := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: conf.getIndexDir()})
-	return &FsBlockstoreProvider{conf, indexConfig, p}
+	var syncer *groupSyncer
+	if conf.groupSync {
+		syncer = newGroupSyncer(conf.groupSyncMaxDelay)
+	}
+	return &FsBlockstoreProvider{conf, indexConfig, p, syncer}
 }
 
 // CreateBlockStore simply calls OpenBlockStore
@@ -45,7 +51,7 @@ func (p *FsBlockstoreProvider) CreateBlockStore(ledgerid string) (blkstorage.Blo
 // This method should be invoked only once for a particular ledgerid
 func (p *FsBlockstoreProvider) OpenBlockStore(ledgerid string) (blkstorage.BlockStore, error) {
 	indexStoreHandle := p.leveldbProvider.GetDBHandle(ledgerid)
-	return newFsBlockStore(ledgerid, p.conf, p.indexConfig, indexStoreHandle), nil
+	return newFsBlockStore(ledgerid, p.conf, p.indexConfig, indexStoreHandle, p.syncer), nil
 }
 
 // Exists tells whether the BlockStore with given id exists
@@ -61,5 +67,8 @@ func (p *FsBlockstoreProvider) List() ([]string, error) {
 
 // Close closes the FsBlockstoreProvider
 func (p *FsBlockstoreProvider) Close() {
+	if p.syncer != nil {
+		p.syncer.close()
+	}
 	p.leveldbProvider.Close()
 }
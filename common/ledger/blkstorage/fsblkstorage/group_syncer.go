@@ -0,0 +1,170 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// groupSyncer flushes to the disk the block files of the ledgers of a provider
+// by group: the blocks appended within maxDelay of the first block of a group
+// are flushed together, by a single flush per block file, so that the ledgers
+// committing blocks concurrently share the latency of the flushes. A group is
+// flushed without waiting for the delay once no other block is being appended.
+type groupSyncer struct {
+	maxDelay time.Duration
+	// appending is the number of blocks being appended whose flush is not
+	// requested yet
+	appending int32
+	// wake wakes the group up when the append of a block fails
+	wake     chan struct{}
+	requests chan *syncRequest
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// syncRequest is the request of the flush of a block file, completed once the
+// group it belongs to is flushed
+type syncRequest struct {
+	file *os.File
+	err  error
+	done chan struct{}
+}
+
+func newGroupSyncer(maxDelay time.Duration) *groupSyncer {
+	s := &groupSyncer{
+		maxDelay: maxDelay,
+		wake:     make(chan struct{}, 1),
+		requests: make(chan *syncRequest),
+		done:     make(chan struct{}),
+	}
+	s.wg.Add(1)
+	go s.run()
+	return s
+}
+
+// begin tells the syncer that a block is being appended to a block file, whose
+// flush is then requested by sync, or abandoned by end if the append fails
+func (s *groupSyncer) begin() {
+	atomic.AddInt32(&s.appending, 1)
+}
+
+// end tells the syncer that the block being appended won't be flushed
+func (s *groupSyncer) end() {
+	atomic.AddInt32(&s.appending, -1)
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// others returns whether other blocks are being appended, which the current
+// group waits for
+func (s *groupSyncer) others() bool {
+	return atomic.LoadInt32(&s.appending) > 0
+}
+
+// sync requests the flush of the file with the current group, once the block
+// appended since begin was called is written to the file
+func (s *groupSyncer) sync(file *os.File) *syncRequest {
+	atomic.AddInt32(&s.appending, -1)
+	r := &syncRequest{file: file, done: make(chan struct{})}
+	select {
+	case s.requests <- r:
+	case <-s.done:
+		// the provider is closed, the file is flushed on its own
+		r.complete(errors.Wrapf(file.Sync(), "error syncing block file %s", file.Name()))
+	}
+	return r
+}
+
+func (s *groupSyncer) run() {
+	defer s.wg.Done()
+	for {
+		var group []*syncRequest
+		select {
+		case r := <-s.requests:
+			group = append(group, r)
+		case <-s.done:
+			return
+		}
+		if !s.others() {
+			s.flush(group)
+			continue
+		}
+		timer := time.NewTimer(s.maxDelay)
+		closed := false
+	collect:
+		for {
+			select {
+			case r := <-s.requests:
+				group = append(group, r)
+				if !s.others() {
+					timer.Stop()
+					break collect
+				}
+			case <-s.wake:
+				if !s.others() {
+					timer.Stop()
+					break collect
+				}
+			case <-timer.C:
+				break collect
+			case <-s.done:
+				timer.Stop()
+				closed = true
+				break collect
+			}
+		}
+		s.flush(group)
+		if closed {
+			return
+		}
+	}
+}
+
+// flush flushes the files of the group in parallel, once per file
+func (s *groupSyncer) flush(group []*syncRequest) {
+	requestsByFile := map[*os.File][]*syncRequest{}
+	for _, r := range group {
+		requestsByFile[r.file] = append(requestsByFile[r.file], r)
+	}
+	var wg sync.WaitGroup
+	for file, requests := range requestsByFile {
+		wg.Add(1)
+		go func(file *os.File, requests []*syncRequest) {
+			defer wg.Done()
+			err := errors.Wrapf(file.Sync(), "error syncing block file %s", file.Name())
+			for _, r := range requests {
+				r.complete(err)
+			}
+		}(file, requests)
+	}
+	wg.Wait()
+}
+
+// close stops the syncer once the current group is flushed
+func (s *groupSyncer) close() {
+	close(s.done)
+	s.wg.Wait()
+}
+
+func (r *syncRequest) complete(err error) {
+	r.err = err
+	close(r.done)
+}
+
+// wait waits for the flush of the file, and returns its error if any
+func (r *syncRequest) wait() error {
+	<-r.done
+	return r.err
+}
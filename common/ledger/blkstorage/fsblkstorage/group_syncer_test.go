@@ -0,0 +1,115 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupSyncer(t *testing.T) {
+	dir := testPath()
+	defer os.RemoveAll(dir)
+	var files []*os.File
+	for i := 0; i < 3; i++ {
+		file, err := os.Create(filepath.Join(dir, fmt.Sprintf("file%d", i)))
+		require.NoError(t, err)
+		defer file.Close()
+		files = append(files, file)
+	}
+
+	// a block appended alone is flushed without waiting for the delay
+	syncer := newGroupSyncer(time.Hour)
+	syncer.begin()
+	assert.NoError(t, syncer.sync(files[0]).wait())
+
+	// the group waits for the blocks being appended within the delay, and is
+	// flushed once they are all appended
+	for range append(files, files[0]) {
+		syncer.begin()
+	}
+	first := syncer.sync(files[0])
+	select {
+	case <-first.done:
+		t.Fatal("the group was flushed before the other blocks were appended")
+	case <-time.After(10 * time.Millisecond):
+	}
+	var requests []*syncRequest
+	for _, file := range files {
+		requests = append(requests, syncer.sync(file))
+	}
+	for _, r := range append(requests, first) {
+		assert.NoError(t, r.wait())
+	}
+
+	// the blocks whose append fails are not waited for
+	syncer.begin()
+	syncer.begin()
+	first = syncer.sync(files[0])
+	syncer.end()
+	assert.NoError(t, first.wait())
+
+	// the current group is flushed when the syncer is closed
+	syncer.begin()
+	syncer.begin()
+	first = syncer.sync(files[0])
+	select {
+	case <-first.done:
+		t.Fatal("the group was flushed before the end of the delay")
+	case <-time.After(10 * time.Millisecond):
+	}
+	syncer.close()
+	assert.NoError(t, first.wait())
+	// the files are flushed on their own once the syncer is closed
+	syncer.begin()
+	assert.NoError(t, syncer.sync(files[1]).wait())
+
+	// the failed flushes are reported to all their requests
+	syncer = newGroupSyncer(0)
+	defer syncer.close()
+	files[2].Close()
+	syncer.begin()
+	err := syncer.sync(files[2]).wait()
+	assert.Contains(t, err.Error(), "error syncing block file "+files[2].Name())
+}
+
+func TestGroupSync(t *testing.T) {
+	env := newTestEnv(t, NewConfWithGroupSync(testPath(), 0, 5*time.Millisecond))
+	defer env.Cleanup()
+	require.NotNil(t, env.provider.syncer)
+
+	// the ledgers add their blocks concurrently
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	var wrappers []*testBlockfileMgrWrapper
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		blkfileMgrWrapper := newTestBlockfileWrapper(env, fmt.Sprintf("ledger%d", i))
+		defer blkfileMgrWrapper.close()
+		wrappers = append(wrappers, blkfileMgrWrapper)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			blkfileMgrWrapper.addBlocks(blocks)
+		}()
+	}
+	wg.Wait()
+
+	for i, blkfileMgrWrapper := range wrappers {
+		blkfileMgrWrapper.testGetBlockByNumber(blocks, 0)
+		_, _, numBlocks, err := scanForLastCompleteBlock(env.provider.conf.getLedgerBlockDir(fmt.Sprintf("ledger%d", i)), 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 10, numBlocks)
+	}
+}
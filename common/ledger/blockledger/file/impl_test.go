@@ -12,11 +12,13 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
 	cl "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	"github.com/hyperledger/fabric/core/ledger"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/peer"
@@ -107,6 +109,18 @@ func (mbs *mockBlockStore) RetrieveTxValidationCodeByTxID(txID string) (peer.TxV
 	return mbs.txValidationCode, mbs.defaultError
 }
 
+func (mbs *mockBlockStore) RetrieveTxsByCreator(creator []byte, startBlockNum uint64, limit int) ([]*ledger.IndexedTransaction, error) {
+	return nil, mbs.defaultError
+}
+
+func (mbs *mockBlockStore) RetrieveBlocksByTimeRange(start, end time.Time, limit int) ([]*cb.Block, error) {
+	return nil, mbs.defaultError
+}
+
+func (mbs *mockBlockStore) RetrieveBlockNumByDedupKey(namespace, dedupKey string) (uint64, error) {
+	return 0, mbs.defaultError
+}
+
 func (*mockBlockStore) Shutdown() {
 }
 
@@ -50,6 +50,13 @@ type QueryResultsIterator interface {
 	GetBookmarkAndClose() string
 }
 
+// QueryWarner is implemented by the query iterators reporting warnings about
+// the execution of the queries, such as the lack of an index matching the
+// selector of a query
+type QueryWarner interface {
+	GetWarning() string
+}
+
 // QueryResult - a general interface for supporting different types of query results. Actual types differ for different queries
 type QueryResult interface{}
 
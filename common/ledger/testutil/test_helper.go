@@ -75,7 +75,7 @@ func (bg *BlockGenerator) NextTestBlocks(numBlocks int) []*common.Block {
 }
 
 // ConstructTransaction constructs a transaction for testing
-func ConstructTransaction(_ *testing.T, simulationResults []byte, txid string, sign bool) (*common.Envelope, string, error) {
+func ConstructTransaction(_ testing.TB, simulationResults []byte, txid string, sign bool) (*common.Envelope, string, error) {
 	ccid := &pb.ChaincodeID{
 		Name:    "foo",
 		Version: "v1",
@@ -105,7 +105,7 @@ func ConstructBlockWithTxid(t *testing.T, blockNum uint64, previousHash []byte,
 }
 
 // ConstructBlock constructs a single block
-func ConstructBlock(t *testing.T, blockNum uint64, previousHash []byte, simulationResults [][]byte, sign bool) *common.Block {
+func ConstructBlock(t testing.TB, blockNum uint64, previousHash []byte, simulationResults [][]byte, sign bool) *common.Block {
 	envs := []*common.Envelope{}
 	for i := 0; i < len(simulationResults); i++ {
 		env, _, err := ConstructTransaction(t, simulationResults[i], "", sign)
@@ -118,7 +118,7 @@ func ConstructBlock(t *testing.T, blockNum uint64, previousHash []byte, simulati
 }
 
 //ConstructTestBlock constructs a single block with random contents
-func ConstructTestBlock(t *testing.T, blockNum uint64, numTx int, txSize int) *common.Block {
+func ConstructTestBlock(t testing.TB, blockNum uint64, numTx int, txSize int) *common.Block {
 	simulationResults := [][]byte{}
 	for i := 0; i < numTx; i++ {
 		simulationResults = append(simulationResults, ConstructRandomBytes(t, txSize))
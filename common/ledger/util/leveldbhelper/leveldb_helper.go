@@ -143,6 +143,26 @@ func (dbInst *DB) GetIterator(startKey []byte, endKey []byte) iterator.Iterator
 	return dbInst.db.NewIterator(&goleveldbutil.Range{Start: startKey, Limit: endKey}, dbInst.readOpts)
 }
 
+// GetSnapshot returns a snapshot of the current state of the db. The snapshot
+// should be released after the use.
+func (dbInst *DB) GetSnapshot() (*leveldb.Snapshot, error) {
+	snapshot, err := dbInst.db.GetSnapshot()
+	if err != nil {
+		return nil, errors.Wrap(err, "error taking leveldb snapshot")
+	}
+	return snapshot, nil
+}
+
+// ApproximateSize returns the approximate size in bytes on the file system of
+// the keys between the startKey (inclusive) and the endKey (exclusive)
+func (dbInst *DB) ApproximateSize(startKey []byte, endKey []byte) (int64, error) {
+	sizes, err := dbInst.db.SizeOf([]goleveldbutil.Range{{Start: startKey, Limit: endKey}})
+	if err != nil {
+		return 0, errors.Wrap(err, "error computing leveldb size")
+	}
+	return sizes.Sum(), nil
+}
+
 // WriteBatch writes a batch
 func (dbInst *DB) WriteBatch(batch *leveldb.Batch, sync bool) error {
 	wo := dbInst.writeOptsNoSync
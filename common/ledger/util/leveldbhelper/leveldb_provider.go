@@ -20,8 +20,11 @@ import (
 	"bytes"
 	"sync"
 
+	"github.com/pkg/errors"
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	goleveldbutil "github.com/syndtr/goleveldb/leveldb/util"
 )
 
 var dbNameKeySep = []byte{0x00}
@@ -103,16 +106,62 @@ func (h *DBHandle) WriteBatch(batch *UpdateBatch, sync bool) error {
 // The resultset contains all the keys that are present in the db between the startKey (inclusive) and the endKey (exclusive).
 // A nil startKey represents the first available key and a nil endKey represent a logical key after the last available key
 func (h *DBHandle) GetIterator(startKey []byte, endKey []byte) *Iterator {
-	sKey := constructLevelKey(h.dbName, startKey)
-	eKey := constructLevelKey(h.dbName, endKey)
-	if endKey == nil {
-		// replace the last byte 'dbNameKeySep' by 'lastKeyIndicator'
-		eKey[len(eKey)-1] = lastKeyIndicator
-	}
+	sKey, eKey := constructLevelRange(h.dbName, startKey, endKey)
 	logger.Debugf("Getting iterator for range [%#v] - [%#v]", sKey, eKey)
 	return &Iterator{h.db.GetIterator(sKey, eKey)}
 }
 
+// ApproximateSize returns the approximate size in bytes on the file system of
+// the keys of the named db
+func (h *DBHandle) ApproximateSize() (int64, error) {
+	sKey, eKey := constructLevelRange(h.dbName, nil, nil)
+	return h.db.ApproximateSize(sKey, eKey)
+}
+
+// GetSnapshot returns a read-only view of the named db, unaffected by the writes
+// performed after the call. The snapshot should be released after the use.
+func (h *DBHandle) GetSnapshot() (*Snapshot, error) {
+	snapshot, err := h.db.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &Snapshot{h.dbName, snapshot, h.db.readOpts}, nil
+}
+
+// Snapshot is a read-only view of a named db at a point in time
+type Snapshot struct {
+	dbName   string
+	snapshot *leveldb.Snapshot
+	readOpts *opt.ReadOptions
+}
+
+// Get returns the value for the given key in the snapshot
+func (s *Snapshot) Get(key []byte) ([]byte, error) {
+	levelKey := constructLevelKey(s.dbName, key)
+	value, err := s.snapshot.Get(levelKey, s.readOpts)
+	if err == leveldb.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		logger.Errorf("Error retrieving leveldb key [%#v] from snapshot: %s", levelKey, err)
+		return nil, errors.Wrapf(err, "error retrieving leveldb key [%#v] from snapshot", levelKey)
+	}
+	return value, nil
+}
+
+// GetIterator gets an handle to an iterator over the snapshot. The iterator should be released
+// after the use. The range semantics are the same as the ones of function DBHandle.GetIterator
+func (s *Snapshot) GetIterator(startKey []byte, endKey []byte) *Iterator {
+	sKey, eKey := constructLevelRange(s.dbName, startKey, endKey)
+	logger.Debugf("Getting snapshot iterator for range [%#v] - [%#v]", sKey, eKey)
+	return &Iterator{s.snapshot.NewIterator(&goleveldbutil.Range{Start: sKey, Limit: eKey}, s.readOpts)}
+}
+
+// Release releases the snapshot
+func (s *Snapshot) Release() {
+	s.snapshot.Release()
+}
+
 // UpdateBatch encloses the details of multiple `updates`
 type UpdateBatch struct {
 	KVs map[string][]byte
@@ -150,6 +199,16 @@ func constructLevelKey(dbName string, key []byte) []byte {
 	return append(append([]byte(dbName), dbNameKeySep...), key...)
 }
 
+func constructLevelRange(dbName string, startKey []byte, endKey []byte) ([]byte, []byte) {
+	sKey := constructLevelKey(dbName, startKey)
+	eKey := constructLevelKey(dbName, endKey)
+	if endKey == nil {
+		// replace the last byte 'dbNameKeySep' by 'lastKeyIndicator'
+		eKey[len(eKey)-1] = lastKeyIndicator
+	}
+	return sKey, eKey
+}
+
 func retrieveAppKey(levelKey []byte) []byte {
 	return bytes.SplitN(levelKey, dbNameKeySep, 2)[1]
 }
@@ -54,6 +54,68 @@ func TestIterator(t *testing.T) {
 	checkItrResults(t, itr3, createTestKeys(0, 19), createTestValues("db2", 0, 19))
 }
 
+func TestSnapshot(t *testing.T) {
+	env := newTestProviderEnv(t, testDBPath)
+	defer env.cleanup()
+	p := env.provider
+
+	db1 := p.GetDBHandle("db1")
+	db2 := p.GetDBHandle("db2")
+	for i := 0; i < 10; i++ {
+		db1.Put([]byte(createTestKey(i)), []byte(createTestValue("db1", i)), false)
+		db2.Put([]byte(createTestKey(i)), []byte(createTestValue("db2", i)), false)
+	}
+
+	snapshot, err := db1.GetSnapshot()
+	assert.NoError(t, err)
+	defer snapshot.Release()
+
+	// updates after the snapshot are not visible through it
+	db1.Put([]byte(createTestKey(2)), []byte("new-value"), false)
+	db1.Delete([]byte(createTestKey(3)), false)
+	db1.Put([]byte(createTestKey(10)), []byte(createTestValue("db1", 10)), false)
+
+	val, err := snapshot.Get([]byte(createTestKey(2)))
+	assert.NoError(t, err)
+	assert.Equal(t, createTestValue("db1", 2), string(val))
+	val, err = snapshot.Get([]byte(createTestKey(10)))
+	assert.NoError(t, err)
+	assert.Nil(t, val)
+	val, err = db1.Get([]byte(createTestKey(2)))
+	assert.NoError(t, err)
+	assert.Equal(t, "new-value", string(val))
+
+	itr1 := snapshot.GetIterator([]byte(createTestKey(2)), []byte(createTestKey(5)))
+	defer itr1.Release()
+	checkItrResults(t, itr1, createTestKeys(2, 4), createTestValues("db1", 2, 4))
+
+	// the snapshot is restricted to its named db
+	itr2 := snapshot.GetIterator(nil, nil)
+	defer itr2.Release()
+	checkItrResults(t, itr2, createTestKeys(0, 9), createTestValues("db1", 0, 9))
+}
+
+func TestApproximateSize(t *testing.T) {
+	env := newTestProviderEnv(t, testDBPath)
+	defer env.cleanup()
+	value := make([]byte, 1024)
+	for i := 0; i < 100; i++ {
+		env.provider.GetDBHandle("db1").Put([]byte(createTestKey(i)), value, false)
+	}
+	// reopening the db flushes its journal to a table file, whose size is reported
+	env.provider.Close()
+	env.provider = NewProvider(&Conf{testDBPath})
+	db1 := env.provider.GetDBHandle("db1")
+	db2 := env.provider.GetDBHandle("db2")
+
+	size, err := db1.ApproximateSize()
+	assert.NoError(t, err)
+	assert.True(t, size > 0)
+	size, err = db2.ApproximateSize()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), size)
+}
+
 func TestBatchedUpdates(t *testing.T) {
 	env := newTestProviderEnv(t, testDBPath)
 	defer env.cleanup()
@@ -145,9 +145,17 @@ func (g *noOpGauge) Update(v float64) {
 
 }
 
+type noOpHistogram struct {
+}
+
+func (h *noOpHistogram) RecordDuration(v time.Duration) {
+
+}
+
 type noOpScope struct {
-	counter *noOpCounter
-	gauge   *noOpGauge
+	counter   *noOpCounter
+	gauge     *noOpGauge
+	histogram *noOpHistogram
 }
 
 func (s *noOpScope) Counter(name string) Counter {
@@ -158,6 +166,10 @@ func (s *noOpScope) Gauge(name string) Gauge {
 	return s.gauge
 }
 
+func (s *noOpScope) Histogram(name string) Histogram {
+	return s.histogram
+}
+
 func (s *noOpScope) Tagged(tags map[string]string) Scope {
 	return s
 }
@@ -174,10 +186,16 @@ func (s *noOpScope) Start() error {
 	return nil
 }
 
+// NewNoOpScope returns a scope which discards the emitted metrics
+func NewNoOpScope() Scope {
+	return newNoOpScope()
+}
+
 func newNoOpScope() Scope {
 	return &noOpScope{
-		counter: &noOpCounter{},
-		gauge:   &noOpGauge{},
+		counter:   &noOpCounter{},
+		gauge:     &noOpGauge{},
+		histogram: &noOpHistogram{},
 	}
 }
 
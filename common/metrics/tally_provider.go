@@ -29,6 +29,10 @@ var logger = flogging.MustGetLogger("common/metrics/tally")
 
 var scopeRegistryKey = tally.KeyForPrefixedStringMap
 
+// DurationBuckets are the upper bounds of the buckets of the histograms,
+// growing exponentially from 1ms to about 16s.
+var DurationBuckets = tally.MustMakeExponentialDurationBuckets(time.Millisecond, 2, 15)
+
 type counter struct {
 	tallyCounter tally.Counter
 }
@@ -53,6 +57,18 @@ func (g *gauge) Update(v float64) {
 	g.tallyGauge.Update(v)
 }
 
+type histogram struct {
+	tallyHistogram tally.Histogram
+}
+
+func newHistogram(tallyHistogram tally.Histogram) *histogram {
+	return &histogram{tallyHistogram: tallyHistogram}
+}
+
+func (h *histogram) RecordDuration(v time.Duration) {
+	h.tallyHistogram.RecordDuration(v)
+}
+
 type scopeRegistry struct {
 	sync.RWMutex
 	subScopes map[string]*scope
@@ -68,9 +84,11 @@ type scope struct {
 
 	cm sync.RWMutex
 	gm sync.RWMutex
+	hm sync.RWMutex
 
-	counters map[string]*counter
-	gauges   map[string]*gauge
+	counters   map[string]*counter
+	gauges     map[string]*gauge
+	histograms map[string]*histogram
 }
 
 func newRootScope(opts tally.ScopeOptions, interval time.Duration) Scope {
@@ -92,7 +110,8 @@ func newRootScope(opts tally.ScopeOptions, interval time.Duration) Scope {
 		},
 		baseReporter: baseReporter,
 		counters:     make(map[string]*counter),
-		gauges:       make(map[string]*gauge)}
+		gauges:       make(map[string]*gauge),
+		histograms:   make(map[string]*histogram)}
 }
 
 func newStatsdReporter(statsdReporterOpts StatsdReporterOpts) (tally.StatsReporter, error) {
@@ -171,6 +190,23 @@ func (s *scope) Gauge(name string) Gauge {
 	return val
 }
 
+func (s *scope) Histogram(name string) Histogram {
+	s.hm.RLock()
+	val, ok := s.histograms[name]
+	s.hm.RUnlock()
+	if !ok {
+		s.hm.Lock()
+		val, ok = s.histograms[name]
+		if !ok {
+			histogram := s.tallyScope.Histogram(name, DurationBuckets)
+			val = newHistogram(histogram)
+			s.histograms[name] = val
+		}
+		s.hm.Unlock()
+	}
+	return val
+}
+
 func (s *scope) Tagged(tags map[string]string) Scope {
 	originTags := tags
 	tags = mergeRightTags(s.tags, tags)
@@ -201,8 +237,9 @@ func (s *scope) Tagged(tags map[string]string) Scope {
 		tallyScope: s.tallyScope.Tagged(originTags),
 		registry:   s.registry,
 
-		counters: make(map[string]*counter),
-		gauges:   make(map[string]*gauge),
+		counters:   make(map[string]*counter),
+		gauges:     make(map[string]*gauge),
+		histograms: make(map[string]*histogram),
 	}
 
 	s.registry.subScopes[key] = subScope
@@ -237,8 +274,9 @@ func (s *scope) SubScope(prefix string) Scope {
 		tallyScope: s.tallyScope.SubScope(prefix),
 		registry:   s.registry,
 
-		counters: make(map[string]*counter),
-		gauges:   make(map[string]*gauge),
+		counters:   make(map[string]*counter),
+		gauges:     make(map[string]*gauge),
+		histograms: make(map[string]*histogram),
 	}
 
 	s.registry.subScopes[key] = subScope
@@ -11,8 +11,10 @@ import (
 )
 
 type MockApplication struct {
-	CapabilitiesRv channelconfig.ApplicationCapabilities
-	Acls           map[string]string
+	CapabilitiesRv          channelconfig.ApplicationCapabilities
+	Acls                    map[string]string
+	RWSetHashingAlgorithmRv func(input []byte) []byte
+	TxDedupWindowRv         uint64
 }
 
 func (m *MockApplication) Organizations() map[string]channelconfig.ApplicationOrg {
@@ -23,6 +25,14 @@ func (m *MockApplication) Capabilities() channelconfig.ApplicationCapabilities {
 	return m.CapabilitiesRv
 }
 
+func (m *MockApplication) RWSetHashingAlgorithm() func(input []byte) []byte {
+	return m.RWSetHashingAlgorithmRv
+}
+
+func (m *MockApplication) TxDedupWindow() uint64 {
+	return m.TxDedupWindowRv
+}
+
 func (m *MockApplication) PolicyRefForAPI(apiName string) string {
 	if m.Acls == nil {
 		return ""
@@ -46,6 +56,9 @@ type MockApplicationCapabilities struct {
 	MetadataLifecycleRv          bool
 	KeyLevelEndorsementRv        bool
 	V1_3ValidationRv             bool
+	ChaincodeMigrationRv         bool
+	EphemeralCollectionsRv       bool
+	ValueCommitmentsRv           bool
 }
 
 func (mac *MockApplicationCapabilities) Supported() error {
@@ -87,3 +100,15 @@ func (mac *MockApplicationCapabilities) KeyLevelEndorsement() bool {
 func (mac *MockApplicationCapabilities) V1_3Validation() bool {
 	return mac.V1_3ValidationRv
 }
+
+func (mac *MockApplicationCapabilities) ChaincodeMigration() bool {
+	return mac.ChaincodeMigrationRv
+}
+
+func (mac *MockApplicationCapabilities) EphemeralCollections() bool {
+	return mac.EphemeralCollectionsRv
+}
+
+func (mac *MockApplicationCapabilities) ValueCommitments() bool {
+	return mac.ValueCommitmentsRv
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package config
 
 import (
+	"time"
+
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/msp"
@@ -34,6 +36,8 @@ type Channel struct {
 	BlockDataHashingStructureWidthVal uint32
 	// OrdererAddressesVal is returned as the result of OrdererAddresses()
 	OrdererAddressesVal []string
+	// EndorsementMaxSkewVal is returned as the result of EndorsementMaxSkew()
+	EndorsementMaxSkewVal time.Duration
 	// CapabilitiesVal is returned as the result of Capabilities()
 	CapabilitiesVal channelconfig.ChannelCapabilities
 }
@@ -56,6 +60,11 @@ func (scm *Channel) OrdererAddresses() []string {
 	return scm.OrdererAddressesVal
 }
 
+// EndorsementMaxSkew returns the EndorsementMaxSkewVal
+func (scm *Channel) EndorsementMaxSkew() time.Duration {
+	return scm.EndorsementMaxSkewVal
+}
+
 // Capabilities returns CapabilitiesVal
 func (scm *Channel) Capabilities() channelconfig.ChannelCapabilities {
 	return scm.CapabilitiesVal
@@ -68,6 +77,12 @@ type ChannelCapabilities struct {
 
 	// MSPVersionVal is returned by MSPVersion()
 	MSPVersionVal msp.MSPVersion
+
+	// EndorsementTimeExpirationVal is returned by EndorsementTimeExpiration()
+	EndorsementTimeExpirationVal bool
+
+	// PublishedCRLsVal is returned by PublishedCRLs()
+	PublishedCRLsVal bool
 }
 
 // Supported returns SupportedErr
@@ -79,3 +94,13 @@ func (cc *ChannelCapabilities) Supported() error {
 func (cc *ChannelCapabilities) MSPVersion() msp.MSPVersion {
 	return cc.MSPVersionVal
 }
+
+// EndorsementTimeExpiration returns EndorsementTimeExpirationVal
+func (cc *ChannelCapabilities) EndorsementTimeExpiration() bool {
+	return cc.EndorsementTimeExpirationVal
+}
+
+// PublishedCRLs returns PublishedCRLsVal
+func (cc *ChannelCapabilities) PublishedCRLs() bool {
+	return cc.PublishedCRLsVal
+}
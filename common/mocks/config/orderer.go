@@ -86,6 +86,18 @@ type OrdererCapabilities struct {
 
 	// ExpirationVal is returned by ExpirationCheck()
 	ExpirationVal bool
+
+	// UnsatisfiablePoliciesCheckVal is returned by UnsatisfiablePoliciesCheck()
+	UnsatisfiablePoliciesCheckVal bool
+
+	// DeliverIdentityBindingVal is returned by DeliverIdentityBinding()
+	DeliverIdentityBindingVal bool
+
+	// TransactionSizeLimitVal is returned by TransactionSizeLimit()
+	TransactionSizeLimitVal bool
+
+	// KafkaIPv6BrokersVal is returned by KafkaIPv6Brokers()
+	KafkaIPv6BrokersVal bool
 }
 
 // Supported returns SupportedErr
@@ -108,3 +120,23 @@ func (oc *OrdererCapabilities) Resubmission() bool {
 func (oc *OrdererCapabilities) ExpirationCheck() bool {
 	return oc.ExpirationVal
 }
+
+// UnsatisfiablePoliciesCheck returns UnsatisfiablePoliciesCheckVal
+func (oc *OrdererCapabilities) UnsatisfiablePoliciesCheck() bool {
+	return oc.UnsatisfiablePoliciesCheckVal
+}
+
+// DeliverIdentityBinding returns DeliverIdentityBindingVal
+func (oc *OrdererCapabilities) DeliverIdentityBinding() bool {
+	return oc.DeliverIdentityBindingVal
+}
+
+// TransactionSizeLimit returns TransactionSizeLimitVal
+func (oc *OrdererCapabilities) TransactionSizeLimit() bool {
+	return oc.TransactionSizeLimitVal
+}
+
+// KafkaIPv6Brokers returns KafkaIPv6BrokersVal
+func (oc *OrdererCapabilities) KafkaIPv6Brokers() bool {
+	return oc.KafkaIPv6BrokersVal
+}
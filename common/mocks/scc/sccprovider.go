@@ -31,6 +31,8 @@ type MocksccProviderFactory struct {
 	ApplicationConfigBool bool
 	PolicyManagerRv       policies.Manager
 	PolicyManagerBool     bool
+	LedgerHeightRv        uint64
+	LedgerHeightErr       error
 }
 
 func (c *MocksccProviderFactory) NewSystemChaincodeProvider() sysccprovider.SystemChaincodeProvider {
@@ -41,6 +43,8 @@ func (c *MocksccProviderFactory) NewSystemChaincodeProvider() sysccprovider.Syst
 		ApplicationConfigBool: c.ApplicationConfigBool,
 		PolicyManagerBool:     c.PolicyManagerBool,
 		PolicyManagerRv:       c.PolicyManagerRv,
+		LedgerHeightRv:        c.LedgerHeightRv,
+		LedgerHeightErr:       c.LedgerHeightErr,
 	}
 }
 
@@ -52,6 +56,8 @@ type MocksccProviderImpl struct {
 	PolicyManagerRv       policies.Manager
 	PolicyManagerBool     bool
 	SysCCMap              map[string]bool
+	LedgerHeightRv        uint64
+	LedgerHeightErr       error
 }
 
 func (c *MocksccProviderImpl) IsSysCC(name string) bool {
@@ -59,7 +65,7 @@ func (c *MocksccProviderImpl) IsSysCC(name string) bool {
 		return c.SysCCMap[name]
 	}
 
-	return (name == "lscc") || (name == "escc") || (name == "vscc") || (name == "notext")
+	return (name == "lscc") || (name == "escc") || (name == "vscc") || (name == "notext") || (name == "xcc") || (name == "crlscc")
 }
 
 func (c *MocksccProviderImpl) IsSysCCAndNotInvokableCC2CC(name string) bool {
@@ -74,6 +80,17 @@ func (c *MocksccProviderImpl) GetQueryExecutorForLedger(cid string) (ledger.Quer
 	return c.Qe, c.QErr
 }
 
+func (c *MocksccProviderImpl) GetStateAtHeight(cid, namespace, key string, height uint64) ([]byte, error) {
+	if c.QErr != nil || c.Qe == nil {
+		return nil, c.QErr
+	}
+	return c.Qe.GetState(namespace, key)
+}
+
+func (c *MocksccProviderImpl) GetLedgerHeight(cid string) (uint64, error) {
+	return c.LedgerHeightRv, c.LedgerHeightErr
+}
+
 func (c *MocksccProviderImpl) GetApplicationConfig(cid string) (channelconfig.Application, bool) {
 	return c.ApplicationConfigRv, c.ApplicationConfigBool
 }
@@ -0,0 +1,93 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policies
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"sync"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+)
+
+// DefaultEvaluationCacheSize is the number of evaluations the cache of a
+// policy manager holds
+const DefaultEvaluationCacheSize = 1000
+
+// evaluationKey identifies the evaluation of a policy against a signature set
+// under a config sequence
+type evaluationKey struct {
+	policy   string
+	digest   [sha256.Size]byte
+	sequence uint64
+}
+
+// evaluationCache holds the outcomes of the latest evaluations of the policies
+// of a manager and its sub-managers. The oldest evaluations are evicted first
+type evaluationCache struct {
+	sequence uint64
+
+	lock        sync.Mutex
+	size        int
+	evaluations map[evaluationKey]*evaluation
+	keys        []evaluationKey
+	next        int
+}
+
+func newEvaluationCache(sequence uint64, size int) *evaluationCache {
+	return &evaluationCache{
+		sequence:    sequence,
+		size:        size,
+		evaluations: make(map[evaluationKey]*evaluation, size),
+	}
+}
+
+// key returns the key of the evaluation of the policy against the signature set
+func (c *evaluationCache) key(policy string, signatureSet []*cb.SignedData) evaluationKey {
+	h := sha256.New()
+	var length [8]byte
+	for _, sd := range signatureSet {
+		for _, field := range [][]byte{sd.Data, sd.Identity, sd.Signature} {
+			binary.BigEndian.PutUint64(length[:], uint64(len(field)))
+			h.Write(length[:])
+			h.Write(field)
+		}
+	}
+	key := evaluationKey{policy: policy, sequence: c.sequence}
+	h.Sum(key.digest[:0])
+	return key
+}
+
+// evaluation is the outcome of the evaluation of a policy
+type evaluation struct {
+	err error
+}
+
+// get returns the cached evaluation of the key, or nil if there is none
+func (c *evaluationCache) get(key evaluationKey) *evaluation {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.evaluations[key]
+}
+
+func (c *evaluationCache) add(key evaluationKey, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, exists := c.evaluations[key]; exists {
+		return
+	}
+	if len(c.keys) < c.size {
+		c.keys = append(c.keys, key)
+	} else {
+		delete(c.evaluations, c.keys[c.next])
+		c.keys[c.next] = key
+		c.next = (c.next + 1) % c.size
+	}
+	c.evaluations[key] = &evaluation{err: err}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policies
+
+import (
+	"bytes"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingPolicy accepts the signature sets signed by "good" and counts its
+// evaluations
+type countingPolicy struct {
+	evaluations int32
+}
+
+func (cp *countingPolicy) Evaluate(signatureSet []*cb.SignedData) error {
+	atomic.AddInt32(&cp.evaluations, 1)
+	for _, sd := range signatureSet {
+		if bytes.Equal(sd.Signature, []byte("good")) {
+			return nil
+		}
+	}
+	return errors.New("bad signature")
+}
+
+type countingProvider struct {
+	policy *countingPolicy
+}
+
+func (cp *countingProvider) NewPolicy(data []byte) (Policy, proto.Message, error) {
+	return cp.policy, nil, nil
+}
+
+func TestCacheEvaluations(t *testing.T) {
+	policy := &countingPolicy{}
+	config := &cb.ConfigGroup{
+		Groups: map[string]*cb.ConfigGroup{
+			"org": {
+				Policies: map[string]*cb.ConfigPolicy{
+					"Writers": {Policy: &cb.Policy{Type: mockType}},
+				},
+			},
+		},
+		Policies: map[string]*cb.ConfigPolicy{
+			"Writers": {Policy: &cb.Policy{Type: int32(cb.Policy_IMPLICIT_META), Value: utils.MarshalOrPanic(&cb.ImplicitMetaPolicy{
+				Rule:      cb.ImplicitMetaPolicy_ANY,
+				SubPolicy: "Writers",
+			})}},
+		},
+	}
+	m, err := NewManagerImpl("Channel", map[int32]Provider{mockType: &countingProvider{policy: policy}}, config)
+	assert.NoError(t, err)
+	m.CacheEvaluations(1, 2)
+
+	good := []*cb.SignedData{{Data: []byte("data"), Identity: []byte("id"), Signature: []byte("good")}}
+	bad := []*cb.SignedData{{Data: []byte("data"), Identity: []byte("id"), Signature: []byte("bad")}}
+	writers, _ := m.GetPolicy("Writers")
+	orgWriters, _ := m.GetPolicy("org/Writers")
+
+	// The sub-policies of the implicit meta policy are cached too
+	assert.NoError(t, writers.Evaluate(good))
+	assert.NoError(t, writers.Evaluate(good))
+	assert.NoError(t, orgWriters.Evaluate(good))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&policy.evaluations))
+
+	// The failed evaluations are cached as well
+	assert.EqualError(t, orgWriters.Evaluate(bad), "bad signature")
+	assert.EqualError(t, orgWriters.Evaluate(bad), "bad signature")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&policy.evaluations))
+
+	// The oldest evaluations are evicted
+	assert.Error(t, writers.Evaluate(bad))
+	assert.NoError(t, orgWriters.Evaluate(good))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&policy.evaluations))
+
+	// A manager built for the next config sequence starts afresh
+	m, err = NewManagerImpl("Channel", map[int32]Provider{mockType: &countingProvider{policy: policy}}, config)
+	assert.NoError(t, err)
+	m.CacheEvaluations(2, DefaultEvaluationCacheSize)
+	orgWriters, _ = m.GetPolicy("org/Writers")
+	assert.NoError(t, orgWriters.Evaluate(good))
+	assert.Equal(t, int32(4), atomic.LoadInt32(&policy.evaluations))
+}
+
+func TestEvaluationKey(t *testing.T) {
+	cache := newEvaluationCache(1, DefaultEvaluationCacheSize)
+	signatureSet := []*cb.SignedData{{Data: []byte("ab"), Identity: []byte("c"), Signature: []byte("d")}}
+	key := cache.key("/Channel/Writers", signatureSet)
+
+	assert.Equal(t, key, cache.key("/Channel/Writers", signatureSet))
+	assert.NotEqual(t, key, cache.key("/Channel/Readers", signatureSet))
+	assert.NotEqual(t, key, newEvaluationCache(2, DefaultEvaluationCacheSize).key("/Channel/Writers", signatureSet))
+	// The fields are delimited
+	assert.NotEqual(t, key, cache.key("/Channel/Writers", []*cb.SignedData{{Data: []byte("a"), Identity: []byte("bc"), Signature: []byte("d")}}))
+}
@@ -9,6 +9,7 @@ package policies
 import (
 	"bytes"
 	"fmt"
+	"sort"
 
 	cb "github.com/hyperledger/fabric/protos/common"
 	"go.uber.org/zap/zapcore"
@@ -100,3 +101,23 @@ func (imp *implicitMetaPolicy) Evaluate(signatureSet []*cb.SignedData) error {
 	}
 	return fmt.Errorf("Failed to reach implicit threshold of %d sub-policies, required %d remaining", imp.threshold, remaining)
 }
+
+// Trace evaluates every sub-policy, unlike Evaluate which stops once the threshold is
+// reached, and returns their traces along with whether enough of them were satisfied
+func (imp *implicitMetaPolicy) Trace(signatureSet []*cb.SignedData) *Trace {
+	trace := &Trace{Name: fmt.Sprintf("implicit meta policy requiring %d of the %s sub-policies", imp.threshold, imp.subPolicyName)}
+	satisfied := 0
+	for _, policy := range imp.subPolicies {
+		child := Explain(policy, signatureSet)
+		if child.Satisfied {
+			satisfied++
+		}
+		trace.Children = append(trace.Children, child)
+	}
+	sort.Slice(trace.Children, func(i, j int) bool {
+		return trace.Children[i].Name < trace.Children[j].Name
+	})
+	trace.Satisfied = satisfied >= imp.threshold
+	trace.Details = []string{fmt.Sprintf("%d of %d sub-policies satisfied", satisfied, len(imp.subPolicies))}
+	return trace
+}
@@ -153,6 +153,9 @@ type ManagerImpl struct {
 	path     string // The group level path
 	policies map[string]Policy
 	managers map[string]*ManagerImpl
+
+	// evaluations of the policies, shared with the sub-managers
+	cache *evaluationCache
 }
 
 // NewManagerImpl creates a new ManagerImpl with the given CryptoHelper
@@ -218,12 +221,31 @@ func NewManagerImpl(path string, providers map[int32]Provider, root *cb.ConfigGr
 	}, nil
 }
 
+// CacheEvaluations makes the manager and its sub-managers cache the outcomes
+// of the evaluations of their policies under the given config sequence, up to
+// size evaluations. As the manager is replaced on config updates, so are the
+// cached evaluations. It must be called before the policies are evaluated
+func (pm *ManagerImpl) CacheEvaluations(sequence uint64, size int) {
+	pm.setCache(newEvaluationCache(sequence, size))
+}
+
+func (pm *ManagerImpl) setCache(cache *evaluationCache) {
+	pm.cache = cache
+	for _, manager := range pm.managers {
+		manager.setCache(cache)
+	}
+}
+
 type rejectPolicy string
 
 func (rp rejectPolicy) Evaluate(signedData []*cb.SignedData) error {
 	return fmt.Errorf("No such policy: '%s'", rp)
 }
 
+func (rp rejectPolicy) Trace(signedData []*cb.SignedData) *Trace {
+	return &Trace{Name: string(rp), Details: []string{"No such policy"}}
+}
+
 // Manager returns the sub-policy manager for a given path and whether it exists
 func (pm *ManagerImpl) Manager(path []string) (Manager, bool) {
 	logger.Debugf("Manager %s looking up path %v", pm.path, path)
@@ -245,9 +267,26 @@ func (pm *ManagerImpl) Manager(path []string) (Manager, bool) {
 type policyLogger struct {
 	policy     Policy
 	policyName string
+	manager    *ManagerImpl
 }
 
 func (pl *policyLogger) Evaluate(signatureSet []*cb.SignedData) error {
+	cache := pl.manager.cache
+	if cache == nil {
+		return pl.evaluate(signatureSet)
+	}
+
+	key := cache.key(pl.policyName, signatureSet)
+	if cached := cache.get(key); cached != nil {
+		logger.Debugf("Returning the cached evaluation of policy %s", pl.policyName)
+		return cached.err
+	}
+	err := pl.evaluate(signatureSet)
+	cache.add(key, err)
+	return err
+}
+
+func (pl *policyLogger) evaluate(signatureSet []*cb.SignedData) error {
 	if logger.IsEnabledFor(zapcore.DebugLevel) {
 		logger.Debugf("== Evaluating %T Policy %s ==", pl.policy, pl.policyName)
 		defer logger.Debugf("== Done Evaluating %T Policy %s", pl.policy, pl.policyName)
@@ -262,6 +301,13 @@ func (pl *policyLogger) Evaluate(signatureSet []*cb.SignedData) error {
 	return err
 }
 
+// Trace names the trace of the underlying policy after the policy
+func (pl *policyLogger) Trace(signatureSet []*cb.SignedData) *Trace {
+	trace := Explain(pl.policy, signatureSet)
+	trace.Name = pl.policyName + " (" + trace.Name + ")"
+	return trace
+}
+
 // GetPolicy returns a policy and true if it was the policy requested, or false if it is the default reject policy
 func (pm *ManagerImpl) GetPolicy(id string) (Policy, bool) {
 	if id == "" {
@@ -290,5 +336,6 @@ func (pm *ManagerImpl) GetPolicy(id string) (Policy, bool) {
 	return &policyLogger{
 		policy:     policy,
 		policyName: PathSeparator + pm.path + PathSeparator + relpath,
+		manager:    pm,
 	}, true
 }
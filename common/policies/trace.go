@@ -0,0 +1,139 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policies
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+)
+
+// Trace is the result of the evaluation of a policy, or of a rule of a policy,
+// along with the results of the evaluations it involved
+type Trace struct {
+	// Name identifies the evaluated policy or rule
+	Name string
+	// Satisfied is whether the signature set satisfied the policy or rule
+	Satisfied bool
+	// Details explain the result, such as why signatures didn't count
+	Details []string
+	// Children are the traces of the sub-policies or rules evaluated
+	Children []*Trace
+}
+
+// String renders the trace as a tree, indenting the children of each element
+func (t *Trace) String() string {
+	var b bytes.Buffer
+	t.write(&b, 0)
+	return b.String()
+}
+
+func (t *Trace) write(b *bytes.Buffer, depth int) {
+	indent := strings.Repeat("  ", depth)
+	result := "not satisfied"
+	if t.Satisfied {
+		result = "satisfied"
+	}
+	fmt.Fprintf(b, "%s%s: %s\n", indent, t.Name, result)
+	for _, detail := range t.Details {
+		fmt.Fprintf(b, "%s  - %s\n", indent, detail)
+	}
+	for _, child := range t.Children {
+		child.write(b, depth+1)
+	}
+}
+
+// TraceablePolicy is a Policy which can explain its evaluations
+type TraceablePolicy interface {
+	// Trace evaluates the signature set as Evaluate does, and returns the trace of the evaluation
+	Trace(signatureSet []*cb.SignedData) *Trace
+}
+
+// Explain evaluates the signature set against the policy, and returns the trace
+// of the evaluation.  Policies which can't explain their evaluations are traced
+// with the error of their evaluation only.
+func Explain(policy Policy, signatureSet []*cb.SignedData) *Trace {
+	if tp, ok := policy.(TraceablePolicy); ok {
+		return tp.Trace(signatureSet)
+	}
+	trace := &Trace{Name: fmt.Sprintf("%T", policy), Satisfied: true}
+	if err := policy.Evaluate(signatureSet); err != nil {
+		trace.Satisfied = false
+		trace.Details = []string{err.Error()}
+	}
+	return trace
+}
+
+// Summary renders the trace on a single line, keeping only the elements which
+// were not satisfied, as they explain why the evaluation failed
+func (t *Trace) Summary() string {
+	var b bytes.Buffer
+	t.summarize(&b)
+	return b.String()
+}
+
+func (t *Trace) summarize(b *bytes.Buffer) {
+	if t.Satisfied {
+		fmt.Fprintf(b, "%s: satisfied", t.Name)
+	} else {
+		fmt.Fprintf(b, "%s: not satisfied", t.Name)
+	}
+	if len(t.Details) != 0 {
+		fmt.Fprintf(b, " (%s)", strings.Join(t.Details, "; "))
+	}
+	var failed []*Trace
+	for _, child := range t.Children {
+		if !child.Satisfied {
+			failed = append(failed, child)
+		}
+	}
+	if len(failed) == 0 {
+		return
+	}
+	b.WriteString(" [")
+	for i, child := range failed {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		child.summarize(b)
+	}
+	b.WriteString("]")
+}
+
+// EvaluationError is the error of a policy which was not satisfied, along with
+// the trace of the evaluation explaining why
+type EvaluationError struct {
+	err   error
+	Trace *Trace
+}
+
+// Error returns the error of the policy followed by the summary of its trace
+func (e *EvaluationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.err, e.Trace.Summary())
+}
+
+// Cause returns the error of the policy
+func (e *EvaluationError) Cause() error {
+	return e.err
+}
+
+// Evaluate evaluates the signature set against the policy, as policy.Evaluate
+// does.  When the policy is not satisfied, the returned EvaluationError explains
+// the evaluation of the policies which can explain their evaluations, and is the
+// error of the policy otherwise.
+func Evaluate(policy Policy, signatureSet []*cb.SignedData) error {
+	err := policy.Evaluate(signatureSet)
+	if err == nil {
+		return nil
+	}
+	if _, ok := policy.(TraceablePolicy); !ok {
+		return err
+	}
+	return &EvaluationError{err: err, Trace: Explain(policy, signatureSet)}
+}
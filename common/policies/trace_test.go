@@ -0,0 +1,127 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policies
+
+import (
+	"errors"
+	"testing"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type failPolicy struct{}
+
+func (fp failPolicy) Evaluate(signedData []*cb.SignedData) error {
+	return errors.New("no good signatures")
+}
+
+func TestExplainUntraceable(t *testing.T) {
+	trace := Explain(acceptPolicy{}, nil)
+	assert.True(t, trace.Satisfied)
+	assert.Equal(t, "policies.acceptPolicy", trace.Name)
+	assert.Empty(t, trace.Details)
+
+	trace = Explain(failPolicy{}, nil)
+	assert.False(t, trace.Satisfied)
+	assert.Equal(t, []string{"no good signatures"}, trace.Details)
+}
+
+func TestTraceString(t *testing.T) {
+	trace := &Trace{
+		Name:    "root",
+		Details: []string{"1 of 2 satisfied"},
+		Children: []*Trace{
+			{Name: "first", Satisfied: true},
+			{Name: "second", Details: []string{"no signatures"}},
+		},
+	}
+	assert.Equal(t, "root: not satisfied\n"+
+		"  - 1 of 2 satisfied\n"+
+		"  first: satisfied\n"+
+		"  second: not satisfied\n"+
+		"    - no signatures\n", trace.String())
+}
+
+func TestImplicitMetaTrace(t *testing.T) {
+	managers := makeManagers(3, 1)
+	managers["2"].policies[TestPolicyName] = failPolicy{}
+	imp, err := newImplicitMetaPolicy(utils.MarshalOrPanic(&cb.ImplicitMetaPolicy{
+		Rule:      cb.ImplicitMetaPolicy_MAJORITY,
+		SubPolicy: TestPolicyName,
+	}), managers)
+	assert.NoError(t, err)
+
+	trace := Explain(imp, nil)
+	assert.False(t, trace.Satisfied)
+	assert.Equal(t, "implicit meta policy requiring 2 of the TestPolicyName sub-policies", trace.Name)
+	assert.Equal(t, []string{"1 of 3 sub-policies satisfied"}, trace.Details)
+	assert.Len(t, trace.Children, 3)
+	assert.Equal(t, 1, countSatisfied(trace.Children))
+	assert.Contains(t, trace.String(), "no good signatures")
+}
+
+func TestRejectPolicyTrace(t *testing.T) {
+	m := &ManagerImpl{policies: map[string]Policy{}}
+	policy, ok := m.GetPolicy("missing")
+	assert.False(t, ok)
+
+	trace := Explain(policy, nil)
+	assert.False(t, trace.Satisfied)
+	assert.Equal(t, []string{"No such policy"}, trace.Details)
+}
+
+func countSatisfied(traces []*Trace) int {
+	count := 0
+	for _, trace := range traces {
+		if trace.Satisfied {
+			count++
+		}
+	}
+	return count
+}
+
+func TestTraceSummary(t *testing.T) {
+	trace := &Trace{
+		Name:    "root",
+		Details: []string{"1 of 3 satisfied", "2 required"},
+		Children: []*Trace{
+			{Name: "first", Satisfied: true},
+			{Name: "second", Details: []string{"no signatures"}},
+			{Name: "third", Children: []*Trace{{Name: "leaf"}}},
+		},
+	}
+	assert.Equal(t, "root: not satisfied (1 of 3 satisfied; 2 required) [second: not satisfied (no signatures); third: not satisfied [leaf: not satisfied]]", trace.Summary())
+}
+
+func TestEvaluate(t *testing.T) {
+	managers := makeManagers(3, 1)
+	managers["2"].policies[TestPolicyName] = failPolicy{}
+	imp, err := newImplicitMetaPolicy(utils.MarshalOrPanic(&cb.ImplicitMetaPolicy{
+		Rule:      cb.ImplicitMetaPolicy_ALL,
+		SubPolicy: TestPolicyName,
+	}), managers)
+	assert.NoError(t, err)
+
+	err = Evaluate(imp, nil)
+	assert.IsType(t, &EvaluationError{}, err)
+	assert.Equal(t, imp.Evaluate(nil), err.(*EvaluationError).Cause())
+	assert.Equal(t, imp.Evaluate(nil).Error()+": "+Explain(imp, nil).Summary(), err.Error())
+	assert.Contains(t, err.Error(), "no good signatures")
+
+	// policies which can't explain their evaluations return their error
+	assert.Equal(t, errors.New("no good signatures"), Evaluate(failPolicy{}, nil))
+	assert.NoError(t, Evaluate(acceptPolicy{}, nil))
+
+	imp, err = newImplicitMetaPolicy(utils.MarshalOrPanic(&cb.ImplicitMetaPolicy{
+		Rule:      cb.ImplicitMetaPolicy_ANY,
+		SubPolicy: TestPolicyName,
+	}), managers)
+	assert.NoError(t, err)
+	assert.NoError(t, Evaluate(imp, nil))
+}
@@ -0,0 +1,233 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package profiling serves the Go runtime profiles of a node over HTTP, on a
+// listener of its own. Unless it is bound to a loopback address, the listener
+// requires TLS and authenticates the clients by their certificate. The
+// profiles can be turned on and off while the node runs.
+package profiling
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("profiling")
+
+// Options contains the configuration of the profiling server
+type Options struct {
+	// ListenAddress is the address the profiling server listens on
+	ListenAddress string
+	// TLS is the TLS configuration of the listener, built by NewTLSConfig. It
+	// may only be nil if the listen address is a loopback address
+	TLS *tls.Config
+	// Enabled tells whether the profiles are served once the server starts
+	Enabled bool
+	// BlockProfileRate and MutexProfileFraction are set on the runtime while
+	// the profiles are enabled, so that the block and mutex profiles hold
+	// samples. They are reset to 0 when the profiles are disabled
+	BlockProfileRate     int
+	MutexProfileFraction int
+}
+
+// Status is the body of the requests and responses of the /profiling
+// endpoint, which turns the profiles on and off
+type Status struct {
+	Enabled bool `json:"enabled"`
+}
+
+// Server serves the runtime profiles at /debug/pprof/, and their status at
+// /profiling
+type Server struct {
+	options  Options
+	listener net.Listener
+	server   *http.Server
+	mux      *http.ServeMux
+
+	lock    sync.RWMutex
+	enabled bool
+}
+
+// NewTLSConfig returns the TLS configuration of a profiling server, which
+// requires the clients to present a certificate issued by one of the client
+// root CAs
+func NewTLSConfig(certificate, key []byte, clientRootCAs [][]byte) (*tls.Config, error) {
+	cert, err := tls.X509KeyPair(certificate, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed loading profiling TLS key pair")
+	}
+	if len(clientRootCAs) == 0 {
+		return nil, errors.New("profiling requires client root CAs to authenticate the clients")
+	}
+	clientCAs := x509.NewCertPool()
+	for _, clientRootCA := range clientRootCAs {
+		if !clientCAs.AppendCertsFromPEM(clientRootCA) {
+			return nil, errors.New("failed adding profiling client root CA")
+		}
+	}
+	return &tls.Config{
+		Certificates:           []tls.Certificate{cert},
+		ClientAuth:             tls.RequireAndVerifyClientCert,
+		ClientCAs:              clientCAs,
+		SessionTicketsDisabled: true,
+		MinVersion:             tls.VersionTLS12,
+	}, nil
+}
+
+// NewServer binds the listener of a profiling server, which serves once
+// started
+func NewServer(options Options) (*Server, error) {
+	if options.TLS == nil && !isLoopback(options.ListenAddress) {
+		return nil, errors.Errorf("profiling on %s requires TLS unless bound to a loopback address", options.ListenAddress)
+	}
+
+	listener, err := net.Listen("tcp", options.ListenAddress)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed listening on %s", options.ListenAddress)
+	}
+	if options.TLS != nil {
+		listener = tls.NewListener(listener, options.TLS)
+	}
+
+	s := &Server{
+		options:  options,
+		listener: listener,
+		mux:      http.NewServeMux(),
+	}
+	s.mux.HandleFunc("/profiling", s.handleStatus)
+	s.mux.Handle("/debug/pprof/", s.whenEnabled(http.HandlerFunc(pprof.Index)))
+	s.mux.Handle("/debug/pprof/cmdline", s.whenEnabled(http.HandlerFunc(pprof.Cmdline)))
+	s.mux.Handle("/debug/pprof/profile", s.whenEnabled(http.HandlerFunc(pprof.Profile)))
+	s.mux.Handle("/debug/pprof/symbol", s.whenEnabled(http.HandlerFunc(pprof.Symbol)))
+	s.mux.Handle("/debug/pprof/trace", s.whenEnabled(http.HandlerFunc(pprof.Trace)))
+	s.server = &http.Server{Handler: s.mux}
+	s.SetEnabled(options.Enabled)
+	return s, nil
+}
+
+// Addr returns the address the server listens on
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Handle serves the requests for the pattern with the handler on the listener
+// of the server, whether the profiles are enabled or not
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Start serves the requests until the server is stopped
+func (s *Server) Start() error {
+	logger.Infof("Starting profiling server on %s", s.listener.Addr())
+	err := s.server.Serve(s.listener)
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// Stop closes the listener and the connections of the server
+func (s *Server) Stop() error {
+	s.SetEnabled(false)
+	return s.server.Close()
+}
+
+// Enabled tells whether the profiles are served
+func (s *Server) Enabled() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.enabled
+}
+
+// SetEnabled turns the profiles on or off
+func (s *Server) SetEnabled(enabled bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if enabled == s.enabled {
+		return
+	}
+	s.enabled = enabled
+	if enabled {
+		runtime.SetBlockProfileRate(s.options.BlockProfileRate)
+		runtime.SetMutexProfileFraction(s.options.MutexProfileFraction)
+		logger.Info("Profiling enabled")
+	} else {
+		runtime.SetBlockProfileRate(0)
+		runtime.SetMutexProfileFraction(0)
+		logger.Info("Profiling disabled")
+	}
+}
+
+// whenEnabled serves the request with the handler if the profiles are enabled
+func (s *Server) whenEnabled(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.Enabled() {
+			http.Error(w, "profiling is disabled", http.StatusServiceUnavailable)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// handleStatus returns whether the profiles are enabled on GET, and turns them
+// on or off on PUT
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var status Status
+		if err := json.NewDecoder(r.Body).Decode(&status); err != nil {
+			http.Error(w, "invalid profiling status: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		logger.Infof("Profiling turned %s by %s", onOff(status.Enabled), clientName(r))
+		s.SetEnabled(status.Enabled)
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(&Status{Enabled: s.Enabled()})
+}
+
+// isLoopback tells whether the host of the address is a loopback address
+func isLoopback(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// clientName returns the subject of the certificate of the client, or its
+// address if it didn't present one
+func clientName(r *http.Request) string {
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		return r.TLS.PeerCertificates[0].Subject.String()
+	}
+	return r.RemoteAddr
+}
+
+func onOff(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+	return "off"
+}
@@ -0,0 +1,147 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package profiling
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startServer(t *testing.T, options Options) *Server {
+	s, err := NewServer(options)
+	require.NoError(t, err)
+	go s.Start()
+	return s
+}
+
+func get(t *testing.T, client *http.Client, url string) (int, string) {
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func put(t *testing.T, client *http.Client, url string, body string) (int, string) {
+	req, err := http.NewRequest(http.MethodPut, url, strings.NewReader(body))
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(respBody)
+}
+
+func TestServer(t *testing.T) {
+	s := startServer(t, Options{ListenAddress: "127.0.0.1:0", Enabled: true})
+	defer s.Stop()
+	url := fmt.Sprintf("http://%s", s.Addr())
+	client := &http.Client{}
+
+	code, _ := get(t, client, url+"/debug/pprof/heap")
+	assert.Equal(t, http.StatusOK, code)
+	code, body := get(t, client, url+"/profiling")
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"enabled": true}`, body)
+
+	// the profiles are turned off at runtime
+	code, body = put(t, client, url+"/profiling", `{"enabled": false}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"enabled": false}`, body)
+	assert.False(t, s.Enabled())
+	code, body = get(t, client, url+"/debug/pprof/heap")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "profiling is disabled\n", body)
+
+	// and on again
+	code, _ = put(t, client, url+"/profiling", `{"enabled": true}`)
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = get(t, client, url+"/debug/pprof/block")
+	assert.Equal(t, http.StatusOK, code)
+
+	code, _ = put(t, client, url+"/profiling", `enabled`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	resp, err := client.Post(url+"/profiling", "application/json", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestServerHandle(t *testing.T) {
+	s := startServer(t, Options{ListenAddress: "127.0.0.1:0"})
+	defer s.Stop()
+	s.Handle("/status", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	}))
+	url := fmt.Sprintf("http://%s", s.Addr())
+
+	// the handlers are served while the profiles are disabled
+	code, body := get(t, &http.Client{}, url+"/status")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "OK", body)
+}
+
+func TestServerRequiresTLS(t *testing.T) {
+	_, err := NewServer(Options{ListenAddress: "0.0.0.0:0"})
+	assert.EqualError(t, err, "profiling on 0.0.0.0:0 requires TLS unless bound to a loopback address")
+
+	_, err = NewServer(Options{ListenAddress: "localhost:-1"})
+	assert.Error(t, err)
+}
+
+func TestServerTLS(t *testing.T) {
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+	serverPair, err := ca.NewServerCertKeyPair("127.0.0.1")
+	require.NoError(t, err)
+	clientPair, err := ca.NewClientCertKeyPair()
+	require.NoError(t, err)
+
+	_, err = NewTLSConfig(serverPair.Cert, serverPair.Key, nil)
+	assert.EqualError(t, err, "profiling requires client root CAs to authenticate the clients")
+	_, err = NewTLSConfig(serverPair.Cert, serverPair.Key, [][]byte{[]byte("not a certificate")})
+	assert.EqualError(t, err, "failed adding profiling client root CA")
+	_, err = NewTLSConfig(serverPair.Cert, clientPair.Key, [][]byte{ca.CertBytes()})
+	assert.Error(t, err)
+
+	tlsConfig, err := NewTLSConfig(serverPair.Cert, serverPair.Key, [][]byte{ca.CertBytes()})
+	require.NoError(t, err)
+	s := startServer(t, Options{ListenAddress: "0.0.0.0:0", TLS: tlsConfig, Enabled: true})
+	defer s.Stop()
+	_, port, err := net.SplitHostPort(s.Addr().String())
+	require.NoError(t, err)
+	url := fmt.Sprintf("https://127.0.0.1:%s", port)
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AppendCertsFromPEM(ca.CertBytes())
+
+	// the clients must present a certificate
+	anonymous := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: rootCAs}}}
+	_, err = anonymous.Get(url + "/debug/pprof/heap")
+	assert.Error(t, err)
+
+	clientCert, err := tls.X509KeyPair(clientPair.Cert, clientPair.Key)
+	require.NoError(t, err)
+	authenticated := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:      rootCAs,
+		Certificates: []tls.Certificate{clientCert},
+	}}}
+	code, _ := get(t, authenticated, url+"/debug/pprof/heap")
+	assert.Equal(t, http.StatusOK, code)
+}
@@ -0,0 +1,161 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package replay
+
+import (
+	"container/heap"
+	"crypto/sha256"
+	"math"
+	"sync"
+	"time"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// Cache rejects the signed envelopes which are not fresh, or whose nonce was
+// already seen from the same creator. An envelope is fresh if its timestamp is
+// within the window of the current time, so the cache only needs to remember the
+// nonces for the duration of the window. When the cache is full the oldest nonce
+// is dropped, and every envelope not newer than it is rejected from then on.
+// The watermark is clamped to the current time plus the maximum clock skew, so
+// that the envelopes of a client whose clock runs ahead can't advance it past the
+// envelopes of the clients whose clocks are within the skew.
+type Cache struct {
+	window  time.Duration
+	maxSkew time.Duration
+	size    int
+
+	lock      sync.Mutex
+	seen      map[[sha256.Size]byte]struct{}
+	entries   entryHeap
+	watermark time.Time
+}
+
+// NewCache creates a cache accepting the envelopes whose timestamp is within the
+// given window of the current time, and remembering at most size nonces. The
+// watermark never exceeds the current time plus maxSkew.
+func NewCache(window, maxSkew time.Duration, size int) *Cache {
+	return &Cache{
+		window:  window,
+		maxSkew: maxSkew,
+		size:    size,
+		seen:    make(map[[sha256.Size]byte]struct{}),
+	}
+}
+
+// Check returns an error if the given envelope isn't fresh, or if it is a replay
+// of an envelope which was already checked. Otherwise the nonce of the envelope
+// is recorded.
+func (c *Cache) Check(env *cb.Envelope) error {
+	key, timestamp, err := envelopeKey(env)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if math.Abs(float64(now.UnixNano()-timestamp.UnixNano())) > float64(c.window.Nanoseconds()) {
+		return errors.Errorf("envelope timestamp %s is more than %s apart from current server time %s", timestamp, c.window, now)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.expire(now)
+	if !timestamp.After(c.watermark) {
+		return errors.Errorf("envelope timestamp %s is older than the oldest envelope remembered for replay detection", timestamp)
+	}
+	if _, exists := c.seen[key]; exists {
+		return errors.New("envelope is a replay of an already received envelope")
+	}
+
+	c.seen[key] = struct{}{}
+	heap.Push(&c.entries, entry{key: key, timestamp: timestamp})
+	for len(c.entries) > c.size {
+		oldest := heap.Pop(&c.entries).(entry)
+		delete(c.seen, oldest.key)
+		c.watermark = oldest.timestamp
+		if latest := now.Add(c.maxSkew); c.watermark.After(latest) {
+			c.watermark = latest
+		}
+	}
+	return nil
+}
+
+// Forget drops the nonce of the given envelope, so that it may be received again,
+// e.g. when a client retries an envelope which couldn't be processed.
+func (c *Cache) Forget(env *cb.Envelope) {
+	key, _, err := envelopeKey(env)
+	if err != nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// the entry stays in the heap until it expires, which is harmless
+	delete(c.seen, key)
+}
+
+// expire drops the nonces of the envelopes which are no longer fresh
+func (c *Cache) expire(now time.Time) {
+	oldest := now.Add(-c.window)
+	for len(c.entries) > 0 && c.entries[0].timestamp.Before(oldest) {
+		expired := heap.Pop(&c.entries).(entry)
+		delete(c.seen, expired.key)
+	}
+}
+
+// envelopeKey returns the hash of the nonce and creator of the given envelope,
+// and its timestamp
+func envelopeKey(env *cb.Envelope) ([sha256.Size]byte, time.Time, error) {
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return [sha256.Size]byte{}, time.Time{}, err
+	}
+	if payload.Header == nil {
+		return [sha256.Size]byte{}, time.Time{}, errors.New("envelope must have a header")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return [sha256.Size]byte{}, time.Time{}, err
+	}
+	if chdr.Timestamp == nil {
+		return [sha256.Size]byte{}, time.Time{}, errors.New("channel header in envelope must contain timestamp")
+	}
+	shdr, err := utils.GetSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		return [sha256.Size]byte{}, time.Time{}, err
+	}
+	if len(shdr.Nonce) == 0 {
+		return [sha256.Size]byte{}, time.Time{}, errors.New("signature header in envelope must contain a nonce")
+	}
+
+	timestamp := time.Unix(chdr.Timestamp.Seconds, int64(chdr.Timestamp.Nanos)).UTC()
+	return sha256.Sum256(append(append([]byte{}, shdr.Nonce...), shdr.Creator...)), timestamp, nil
+}
+
+type entry struct {
+	key       [sha256.Size]byte
+	timestamp time.Time
+}
+
+// entryHeap orders the entries of the cache by timestamp
+type entryHeap []entry
+
+func (h entryHeap) Len() int            { return len(h) }
+func (h entryHeap) Less(i, j int) bool  { return h[i].timestamp.Before(h[j].timestamp) }
+func (h entryHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *entryHeap) Push(x interface{}) { *h = append(*h, x.(entry)) }
+func (h *entryHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	*h = old[:n-1]
+	return e
+}
@@ -0,0 +1,120 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package replay
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func envelope(t time.Time, nonce, creator string) *cb.Envelope {
+	chdr := &cb.ChannelHeader{ChannelId: "foo"}
+	if !t.IsZero() {
+		chdr.Timestamp = &timestamp.Timestamp{Seconds: t.Unix(), Nanos: int32(t.Nanosecond())}
+	}
+	return &cb.Envelope{
+		Payload: utils.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader:   utils.MarshalOrPanic(chdr),
+				SignatureHeader: utils.MarshalOrPanic(&cb.SignatureHeader{Nonce: []byte(nonce), Creator: []byte(creator)}),
+			},
+		}),
+	}
+}
+
+func TestCheck(t *testing.T) {
+	c := NewCache(time.Minute, time.Minute, 10)
+	now := time.Now()
+
+	assert.NoError(t, c.Check(envelope(now, "nonce1", "alice")))
+	assert.EqualError(t, c.Check(envelope(now, "nonce1", "alice")), "envelope is a replay of an already received envelope")
+	// the same nonce from another creator is another envelope
+	assert.NoError(t, c.Check(envelope(now, "nonce1", "bob")))
+	assert.NoError(t, c.Check(envelope(now.Add(-30*time.Second), "nonce2", "alice")))
+
+	err := c.Check(envelope(now.Add(-2*time.Minute), "nonce3", "alice"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "is more than 1m0s apart from current server time")
+	err = c.Check(envelope(now.Add(2*time.Minute), "nonce3", "alice"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "is more than 1m0s apart from current server time")
+}
+
+func TestCheckMalformed(t *testing.T) {
+	c := NewCache(time.Minute, time.Minute, 10)
+
+	assert.EqualError(t, c.Check(envelope(time.Time{}, "nonce", "alice")), "channel header in envelope must contain timestamp")
+	assert.EqualError(t, c.Check(envelope(time.Now(), "", "alice")), "signature header in envelope must contain a nonce")
+	assert.EqualError(t, c.Check(&cb.Envelope{Payload: utils.MarshalOrPanic(&cb.Payload{})}), "envelope must have a header")
+	assert.Error(t, c.Check(&cb.Envelope{Payload: []byte("garbage")}))
+}
+
+func TestCheckExpiration(t *testing.T) {
+	c := NewCache(time.Minute, time.Minute, 10)
+	env := envelope(time.Now().Add(-time.Minute+100*time.Millisecond), "nonce", "alice")
+
+	assert.NoError(t, c.Check(env))
+	assert.Len(t, c.seen, 1)
+	time.Sleep(200 * time.Millisecond)
+
+	// the nonce is dropped once the envelope is no longer fresh
+	err := c.Check(env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "is more than 1m0s apart from current server time")
+	assert.NoError(t, c.Check(envelope(time.Now(), "other", "alice")))
+	assert.Len(t, c.seen, 1)
+}
+
+func TestCheckFull(t *testing.T) {
+	c := NewCache(time.Minute, time.Minute, 2)
+	now := time.Now()
+
+	assert.NoError(t, c.Check(envelope(now.Add(-3*time.Second), "nonce1", "alice")))
+	assert.NoError(t, c.Check(envelope(now.Add(-time.Second), "nonce2", "alice")))
+	assert.NoError(t, c.Check(envelope(now.Add(-2*time.Second), "nonce3", "alice")))
+	assert.Len(t, c.seen, 2)
+
+	// the oldest nonce was dropped, so the envelopes which aren't newer are rejected
+	err := c.Check(envelope(now.Add(-3*time.Second), "nonce1", "alice"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "is older than the oldest envelope remembered for replay detection")
+	assert.EqualError(t, c.Check(envelope(now.Add(-2*time.Second), "nonce3", "alice")), "envelope is a replay of an already received envelope")
+	assert.NoError(t, c.Check(envelope(now, "nonce4", "alice")))
+}
+
+func TestForget(t *testing.T) {
+	c := NewCache(time.Minute, time.Minute, 10)
+	env := envelope(time.Now(), "nonce", "alice")
+
+	assert.NoError(t, c.Check(env))
+	c.Forget(env)
+	assert.NoError(t, c.Check(env))
+	assert.Error(t, c.Check(env))
+
+	// forgetting a malformed envelope is a no-op
+	c.Forget(&cb.Envelope{Payload: []byte("garbage")})
+}
+
+func TestCheckFullFutureTimestamps(t *testing.T) {
+	c := NewCache(time.Minute, time.Second, 2)
+	now := time.Now()
+
+	// the envelopes of a client whose clock runs ahead fill the cache
+	assert.NoError(t, c.Check(envelope(now.Add(50*time.Second), "nonce1", "alice")))
+	assert.NoError(t, c.Check(envelope(now.Add(51*time.Second), "nonce2", "alice")))
+	assert.NoError(t, c.Check(envelope(now.Add(52*time.Second), "nonce3", "alice")))
+
+	// the watermark is clamped to the maximum clock skew, so the envelopes
+	// of the clients whose clock is on time are still accepted
+	assert.True(t, c.watermark.Before(now.Add(2*time.Second)))
+	assert.NoError(t, c.Check(envelope(now.Add(1500*time.Millisecond), "nonce4", "bob")))
+}
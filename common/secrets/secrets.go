@@ -0,0 +1,67 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package secrets resolves the credentials of the configuration which are
+// given as a reference to a file or to an environment variable instead of
+// their value, so that they can be kept out of the configuration files.
+package secrets
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// FilePrefix introduces a secret read from the file at the path which
+	// follows it, e.g. file:///var/hyperledger/secrets/couchdb
+	FilePrefix = "file://"
+	// EnvPrefix introduces a secret read from the environment variable whose
+	// name follows it, e.g. env://COUCHDB_PASSWORD
+	EnvPrefix = "env://"
+)
+
+// Resolve returns the secret referenced by the value. Values which are not
+// a reference are returned unchanged. The trailing line breaks of the files
+// are removed.
+func Resolve(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, FilePrefix):
+		path := strings.TrimPrefix(value, FilePrefix)
+		if path == "" {
+			return "", errors.Errorf("secret reference %s has no file path", value)
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed reading secret from file %s", path)
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	case strings.HasPrefix(value, EnvPrefix):
+		name := strings.TrimPrefix(value, EnvPrefix)
+		if name == "" {
+			return "", errors.Errorf("secret reference %s has no environment variable name", value)
+		}
+		secret, ok := os.LookupEnv(name)
+		if !ok {
+			return "", errors.Errorf("environment variable %s of secret is not set", name)
+		}
+		return secret, nil
+	default:
+		return value, nil
+	}
+}
+
+// ResolveInPlace replaces the value with the secret it references
+func ResolveInPlace(value *string) error {
+	secret, err := Resolve(*value)
+	if err != nil {
+		return err
+	}
+	*value = secret
+	return nil
+}
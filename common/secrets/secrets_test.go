@@ -0,0 +1,65 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package secrets
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "password")
+	require.NoError(t, ioutil.WriteFile(path, []byte("s3cr3t\n"), 0600))
+
+	os.Setenv("SECRETS_TEST_PASSWORD", "fromenv")
+	defer os.Unsetenv("SECRETS_TEST_PASSWORD")
+
+	secret, err := Resolve("plain")
+	assert.NoError(t, err)
+	assert.Equal(t, "plain", secret)
+
+	secret, err = Resolve("")
+	assert.NoError(t, err)
+	assert.Equal(t, "", secret)
+
+	secret, err = Resolve("file://" + path)
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", secret)
+
+	secret, err = Resolve("env://SECRETS_TEST_PASSWORD")
+	assert.NoError(t, err)
+	assert.Equal(t, "fromenv", secret)
+
+	_, err = Resolve("file://" + filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed reading secret from file")
+
+	_, err = Resolve("file://")
+	assert.EqualError(t, err, "secret reference file:// has no file path")
+
+	_, err = Resolve("env://SECRETS_TEST_MISSING")
+	assert.EqualError(t, err, "environment variable SECRETS_TEST_MISSING of secret is not set")
+
+	_, err = Resolve("env://")
+	assert.EqualError(t, err, "secret reference env:// has no environment variable name")
+
+	value := "env://SECRETS_TEST_PASSWORD"
+	assert.NoError(t, ResolveInPlace(&value))
+	assert.Equal(t, "fromenv", value)
+
+	value = "env://SECRETS_TEST_MISSING"
+	assert.Error(t, ResolveInPlace(&value))
+	assert.Equal(t, "env://SECRETS_TEST_MISSING", value)
+}
@@ -163,6 +163,10 @@ func NewChannelGroup(conf *genesisconfig.Profile) (*cb.ConfigGroup, error) {
 		addValue(channelGroup, channelconfig.CapabilitiesValue(conf.Capabilities), channelconfig.AdminsPolicyKey)
 	}
 
+	if conf.EndorsementMaxSkew != "" {
+		addValue(channelGroup, channelconfig.EndorsementMaxSkewValue(conf.EndorsementMaxSkew), channelconfig.AdminsPolicyKey)
+	}
+
 	var err error
 	channelGroup.Groups[channelconfig.OrdererGroupKey], err = NewOrdererGroup(conf.Orderer)
 	if err != nil {
@@ -208,6 +212,7 @@ func NewOrdererGroup(conf *genesisconfig.Orderer) (*cb.ConfigGroup, error) {
 		conf.BatchSize.MaxMessageCount,
 		conf.BatchSize.AbsoluteMaxBytes,
 		conf.BatchSize.PreferredMaxBytes,
+		conf.BatchSize.MaxTransactionBytes,
 	), channelconfig.AdminsPolicyKey)
 	addValue(ordererGroup, channelconfig.BatchTimeoutValue(conf.BatchTimeout.String()), channelconfig.AdminsPolicyKey)
 	addValue(ordererGroup, channelconfig.ChannelRestrictionsValue(conf.MaxChannels), channelconfig.AdminsPolicyKey)
@@ -291,6 +296,14 @@ func NewApplicationGroup(conf *genesisconfig.Application) (*cb.ConfigGroup, erro
 		addValue(applicationGroup, channelconfig.CapabilitiesValue(conf.Capabilities), channelconfig.AdminsPolicyKey)
 	}
 
+	if conf.RWSetHashingAlgorithm != "" {
+		addValue(applicationGroup, channelconfig.RWSetHashingAlgorithmValue(conf.RWSetHashingAlgorithm), channelconfig.AdminsPolicyKey)
+	}
+
+	if conf.TxDedupWindow != 0 {
+		addValue(applicationGroup, channelconfig.TxDedupWindowValue(conf.TxDedupWindow), channelconfig.AdminsPolicyKey)
+	}
+
 	for _, org := range conf.Organizations {
 		var err error
 		applicationGroup.Groups[org.Name], err = NewApplicationOrgGroup(org)
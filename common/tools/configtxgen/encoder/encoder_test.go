@@ -159,6 +159,22 @@ func TestNewApplicationGroup(t *testing.T) {
 		assert.NotNil(t, group)
 	})
 
+	t.Run("Application with write-set hashing algorithm", func(t *testing.T) {
+		config := configtxgentest.Load(genesisconfig.SampleSingleMSPChannelProfile)
+		config.Application.RWSetHashingAlgorithm = "SHA3_256"
+		group, err := NewApplicationGroup(config.Application)
+		assert.NoError(t, err)
+		assert.Contains(t, group.Values, channelconfig.RWSetHashingAlgorithmKey)
+	})
+
+	t.Run("Application with transaction dedup window", func(t *testing.T) {
+		config := configtxgentest.Load(genesisconfig.SampleSingleMSPChannelProfile)
+		config.Application.TxDedupWindow = 100
+		group, err := NewApplicationGroup(config.Application)
+		assert.NoError(t, err)
+		assert.Contains(t, group.Values, channelconfig.TxDedupWindowKey)
+	})
+
 	t.Run("Application missing policies", func(t *testing.T) {
 		config := configtxgentest.Load(genesisconfig.SampleSingleMSPChannelProfile)
 		config.Application.Policies = nil
@@ -196,6 +212,14 @@ func TestNewChannelGroup(t *testing.T) {
 		assert.NotNil(t, group)
 	})
 
+	t.Run("Add endorsement max skew", func(t *testing.T) {
+		config := configtxgentest.Load(genesisconfig.SampleDevModeSoloProfile)
+		config.EndorsementMaxSkew = "10m"
+		group, err := NewChannelGroup(config)
+		assert.NoError(t, err)
+		assert.Contains(t, group.Values, channelconfig.EndorsementMaxSkewKey)
+	})
+
 	t.Run("Channel missing policies", func(t *testing.T) {
 		config := configtxgentest.Load(genesisconfig.SampleDevModeSoloProfile)
 		config.Policies = nil
@@ -106,12 +106,13 @@ type TopLevel struct {
 // Profile encodes orderer/application configuration combinations for the
 // configtxgen tool.
 type Profile struct {
-	Consortium   string                 `yaml:"Consortium"`
-	Application  *Application           `yaml:"Application"`
-	Orderer      *Orderer               `yaml:"Orderer"`
-	Consortiums  map[string]*Consortium `yaml:"Consortiums"`
-	Capabilities map[string]bool        `yaml:"Capabilities"`
-	Policies     map[string]*Policy     `yaml:"Policies"`
+	Consortium         string                 `yaml:"Consortium"`
+	Application        *Application           `yaml:"Application"`
+	Orderer            *Orderer               `yaml:"Orderer"`
+	Consortiums        map[string]*Consortium `yaml:"Consortiums"`
+	Capabilities       map[string]bool        `yaml:"Capabilities"`
+	Policies           map[string]*Policy     `yaml:"Policies"`
+	EndorsementMaxSkew string                 `yaml:"EndorsementMaxSkew"`
 }
 
 // Policy encodes a channel config policy
@@ -129,11 +130,13 @@ type Consortium struct {
 // Application encodes the application-level configuration needed in config
 // transactions.
 type Application struct {
-	Organizations []*Organization    `yaml:"Organizations"`
-	Capabilities  map[string]bool    `yaml:"Capabilities"`
-	Resources     *Resources         `yaml:"Resources"`
-	Policies      map[string]*Policy `yaml:"Policies"`
-	ACLs          map[string]string  `yaml:"ACLs"`
+	Organizations         []*Organization    `yaml:"Organizations"`
+	Capabilities          map[string]bool    `yaml:"Capabilities"`
+	Resources             *Resources         `yaml:"Resources"`
+	Policies              map[string]*Policy `yaml:"Policies"`
+	ACLs                  map[string]string  `yaml:"ACLs"`
+	RWSetHashingAlgorithm string             `yaml:"RWSetHashingAlgorithm"`
+	TxDedupWindow         uint64             `yaml:"TxDedupWindow"`
 }
 
 // Resources encodes the application-level resources configuration needed to
@@ -186,9 +189,10 @@ type Orderer struct {
 
 // BatchSize contains configuration affecting the size of batches.
 type BatchSize struct {
-	MaxMessageCount   uint32 `yaml:"MaxMessageCount"`
-	AbsoluteMaxBytes  uint32 `yaml:"AbsoluteMaxBytes"`
-	PreferredMaxBytes uint32 `yaml:"PreferredMaxBytes"`
+	MaxMessageCount     uint32 `yaml:"MaxMessageCount"`
+	AbsoluteMaxBytes    uint32 `yaml:"AbsoluteMaxBytes"`
+	PreferredMaxBytes   uint32 `yaml:"PreferredMaxBytes"`
+	MaxTransactionBytes uint32 `yaml:"MaxTransactionBytes"`
 }
 
 // Kafka contains configuration for the Kafka-based orderer.
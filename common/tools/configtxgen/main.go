@@ -13,12 +13,15 @@ import (
 	"os"
 	"strings"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/bccsp/factory"
 	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/configtx"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
 	"github.com/hyperledger/fabric/common/tools/configtxgen/metadata"
+	"github.com/hyperledger/fabric/common/tools/configtxlator/update"
 	"github.com/hyperledger/fabric/common/tools/protolator"
 	cb "github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
@@ -204,8 +207,116 @@ func doPrintOrg(t *genesisconfig.TopLevel, printOrg string) error {
 	return errors.Errorf("organization %s not found", printOrg)
 }
 
+// readChannelConfig returns the channel ID and the config carried by the config block at the given path
+func readChannelConfig(configBlock string) (string, *cb.Config, error) {
+	data, err := ioutil.ReadFile(configBlock)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "could not read config block %s", configBlock)
+	}
+	block, err := utils.UnmarshalBlock(data)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "error unmarshaling to block")
+	}
+	env, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "malformed config block")
+	}
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "malformed config block")
+	}
+	if payload.Header == nil {
+		return "", nil, errors.New("malformed config block: missing header")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "malformed config block")
+	}
+	if chdr.Type != int32(cb.HeaderType_CONFIG) {
+		return "", nil, errors.Errorf("block %d is not a config block", block.Header.Number)
+	}
+	configEnv, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "malformed config block")
+	}
+	if configEnv.Config == nil || configEnv.Config.ChannelGroup == nil {
+		return "", nil, errors.New("config block carries no channel config")
+	}
+	return chdr.ChannelId, configEnv.Config, nil
+}
+
+// doOutputOrgUpdate writes the config update transitioning the config of the channel in the given
+// config block into the config returned by modify, which is passed a copy of the application group
+func doOutputOrgUpdate(configBlock string, channelID string, output string, modify func(app *cb.ConfigGroup) error) error {
+	if configBlock == "" {
+		return errors.New("must specify the current config block of the channel")
+	}
+	blockChannelID, original, err := readChannelConfig(configBlock)
+	if err != nil {
+		return err
+	}
+	if channelID != "" && channelID != blockChannelID {
+		return errors.Errorf("config block is for channel %s, not %s", blockChannelID, channelID)
+	}
+
+	updated := proto.Clone(original).(*cb.Config)
+	app, ok := updated.ChannelGroup.Groups[channelconfig.ApplicationGroupKey]
+	if !ok {
+		return errors.Errorf("channel %s has no application group", blockChannelID)
+	}
+	if err := modify(app); err != nil {
+		return err
+	}
+
+	env, err := update.ComputeEnvelope(blockChannelID, original, updated)
+	if err != nil {
+		return errors.Wrap(err, "error computing config update")
+	}
+	if err := ioutil.WriteFile(output, utils.MarshalOrPanic(env), 0644); err != nil {
+		return errors.Wrap(err, "error writing config update")
+	}
+	return nil
+}
+
+func doOutputAddOrgUpdate(t *genesisconfig.TopLevel, configBlock string, channelID string, orgName string, outputAddOrgUpdate string) error {
+	logger.Info("Generating organization addition update")
+	var org *genesisconfig.Organization
+	for _, iorg := range t.Organizations {
+		if iorg.Name == orgName {
+			org = iorg
+		}
+	}
+	if org == nil {
+		return errors.Errorf("organization %s not found", orgName)
+	}
+	orgGroup, err := encoder.NewApplicationOrgGroup(org)
+	if err != nil {
+		return errors.Wrapf(err, "bad org definition for org %s", org.Name)
+	}
+
+	return doOutputOrgUpdate(configBlock, channelID, outputAddOrgUpdate, func(app *cb.ConfigGroup) error {
+		if _, exists := app.Groups[org.Name]; exists {
+			return errors.Errorf("organization %s is already a member of the channel", org.Name)
+		}
+		app.Groups[org.Name] = orgGroup
+		return nil
+	})
+}
+
+func doOutputRemoveOrgUpdate(configBlock string, channelID string, orgName string, outputRemoveOrgUpdate string) error {
+	logger.Info("Generating organization removal update")
+	return doOutputOrgUpdate(configBlock, channelID, outputRemoveOrgUpdate, func(app *cb.ConfigGroup) error {
+		if _, exists := app.Groups[orgName]; !exists {
+			return errors.Errorf("organization %s is not a member of the channel", orgName)
+		}
+		delete(app.Groups, orgName)
+		return nil
+	})
+}
+
 func main() {
 	var outputBlock, outputChannelCreateTx, profile, configPath, channelID, inspectBlock, inspectChannelCreateTx, outputAnchorPeersUpdate, asOrg, printOrg string
+	var configBlock, org, outputAddOrgUpdate, outputRemoveOrgUpdate string
 
 	flag.StringVar(&outputBlock, "outputBlock", "", "The path to write the genesis block to (if set)")
 	flag.StringVar(&channelID, "channelID", "", "The channel ID to use in the configtx")
@@ -217,6 +328,10 @@ func main() {
 	flag.StringVar(&outputAnchorPeersUpdate, "outputAnchorPeersUpdate", "", "Creates an config update to update an anchor peer (works only with the default channel creation, and only for the first update)")
 	flag.StringVar(&asOrg, "asOrg", "", "Performs the config generation as a particular organization (by name), only including values in the write set that org (likely) has privilege to set")
 	flag.StringVar(&printOrg, "printOrg", "", "Prints the definition of an organization as JSON. (useful for adding an org to a channel manually)")
+	flag.StringVar(&configBlock, "configBlock", "", "The path to the current config block of the channel to generate an organization update for")
+	flag.StringVar(&org, "org", "", "The name of the organization to add to or remove from the channel, added organizations are read from configtx.yaml")
+	flag.StringVar(&outputAddOrgUpdate, "outputAddOrgUpdate", "", "The path to write a config update adding the organization to the application group of the channel to (if set)")
+	flag.StringVar(&outputRemoveOrgUpdate, "outputRemoveOrgUpdate", "", "The path to write a config update removing the organization from the application group of the channel to (if set)")
 
 	version := flag.Bool("version", false, "Show version information")
 
@@ -304,6 +419,18 @@ func main() {
 			logger.Fatalf("Error on printOrg: %s", err)
 		}
 	}
+
+	if outputAddOrgUpdate != "" {
+		if err := doOutputAddOrgUpdate(topLevelConfig, configBlock, channelID, org, outputAddOrgUpdate); err != nil {
+			logger.Fatalf("Error on outputAddOrgUpdate: %s", err)
+		}
+	}
+
+	if outputRemoveOrgUpdate != "" {
+		if err := doOutputRemoveOrgUpdate(configBlock, channelID, org, outputRemoveOrgUpdate); err != nil {
+			logger.Fatalf("Error on outputRemoveOrgUpdate: %s", err)
+		}
+	}
 }
 
 func printVersion() {
@@ -23,10 +23,13 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/bccsp/factory"
 	"github.com/hyperledger/fabric/common/tools/configtxgen/configtxgentest"
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
 	"github.com/hyperledger/fabric/core/config/configtest"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -201,3 +204,72 @@ func TestPrintOrg(t *testing.T) {
 	assert.Error(t, err, "Fake org")
 	assert.Regexp(t, "bad org definition", err.Error())
 }
+
+// writeChannelConfigBlock writes the genesis block of an application channel with SampleOrg as its only member
+func writeChannelConfigBlock(t *testing.T, channelID string) string {
+	blockDest := filepath.Join(tmpDir, "channelConfigBlock")
+	config := configtxgentest.Load(genesisconfig.SampleSingleMSPSoloProfile)
+	config.Consortiums = nil
+	config.Application = configtxgentest.Load(genesisconfig.SampleSingleMSPChannelProfile).Application
+	assert.NoError(t, doOutputBlock(config, channelID, blockDest))
+	return blockDest
+}
+
+// readConfigUpdate returns the config update carried by the envelope at the given path
+func readConfigUpdate(t *testing.T, path string) *cb.ConfigUpdate {
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	env, err := utils.UnmarshalEnvelope(data)
+	assert.NoError(t, err)
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	assert.NoError(t, err)
+	configUpdateEnv := &cb.ConfigUpdateEnvelope{}
+	assert.NoError(t, proto.Unmarshal(payload.Data, configUpdateEnv))
+	configUpdate := &cb.ConfigUpdate{}
+	assert.NoError(t, proto.Unmarshal(configUpdateEnv.ConfigUpdate, configUpdate))
+	return configUpdate
+}
+
+func TestOutputAddOrgUpdate(t *testing.T) {
+	factory.InitFactories(nil)
+	configBlock := writeChannelConfigBlock(t, "foo")
+	updateDest := filepath.Join(tmpDir, "addOrgUpdate")
+	config := configtxgentest.LoadTopLevel()
+	newOrg := *config.Organizations[0]
+	newOrg.Name = "NewOrg"
+	config.Organizations = append(config.Organizations, &newOrg)
+
+	assert.NoError(t, doOutputAddOrgUpdate(config, configBlock, "foo", "NewOrg", updateDest), "Good addOrgUpdate request")
+	configUpdate := readConfigUpdate(t, updateDest)
+	assert.Equal(t, "foo", configUpdate.ChannelId)
+	app := configUpdate.WriteSet.Groups["Application"]
+	assert.Equal(t, configUpdate.ReadSet.Groups["Application"].Version+1, app.Version)
+	assert.Contains(t, app.Groups, "NewOrg")
+	assert.Contains(t, app.Groups["NewOrg"].Values, "MSP")
+	assert.Empty(t, app.Groups[genesisconfig.SampleOrgName].Values, "Existing org is unchanged")
+
+	err := doOutputAddOrgUpdate(config, configBlock, "foo", genesisconfig.SampleOrgName, updateDest)
+	assert.EqualError(t, err, "organization SampleOrg is already a member of the channel")
+	err = doOutputAddOrgUpdate(config, configBlock, "foo", "MissingOrg", updateDest)
+	assert.EqualError(t, err, "organization MissingOrg not found")
+	err = doOutputAddOrgUpdate(config, configBlock, "bar", "NewOrg", updateDest)
+	assert.EqualError(t, err, "config block is for channel foo, not bar")
+	err = doOutputAddOrgUpdate(config, "", "foo", "NewOrg", updateDest)
+	assert.EqualError(t, err, "must specify the current config block of the channel")
+}
+
+func TestOutputRemoveOrgUpdate(t *testing.T) {
+	factory.InitFactories(nil)
+	configBlock := writeChannelConfigBlock(t, "foo")
+	updateDest := filepath.Join(tmpDir, "removeOrgUpdate")
+
+	assert.NoError(t, doOutputRemoveOrgUpdate(configBlock, "", genesisconfig.SampleOrgName, updateDest), "Good removeOrgUpdate request")
+	configUpdate := readConfigUpdate(t, updateDest)
+	assert.Equal(t, "foo", configUpdate.ChannelId)
+	app := configUpdate.WriteSet.Groups["Application"]
+	assert.Equal(t, configUpdate.ReadSet.Groups["Application"].Version+1, app.Version)
+	assert.NotContains(t, app.Groups, genesisconfig.SampleOrgName)
+
+	err := doOutputRemoveOrgUpdate(configBlock, "", "MissingOrg", updateDest)
+	assert.EqualError(t, err, "organization MissingOrg is not a member of the channel")
+}
@@ -19,16 +19,22 @@ package main
 import (
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"os"
-	"reflect"
+	"strconv"
 
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/configtx"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/common/tools/configtxlator/metadata"
 	"github.com/hyperledger/fabric/common/tools/configtxlator/rest"
+	"github.com/hyperledger/fabric/common/tools/configtxlator/translate"
 	"github.com/hyperledger/fabric/common/tools/configtxlator/update"
 	"github.com/hyperledger/fabric/common/tools/protolator"
 	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
 
 	// Import these to register the proto types
 	_ "github.com/hyperledger/fabric/protos/common"
@@ -66,6 +72,13 @@ var (
 	computeUpdateChannelID = computeUpdate.Flag("channel_id", "The name of the channel for this update.").Required().String()
 	computeUpdateDest      = computeUpdate.Flag("output", "A file to write the JSON document to.").Default(os.Stdout.Name()).OpenFile(os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
 
+	explainPolicy          = app.Command("explain_policy", "Evaluates a policy of a marshaled common.Config message against the signatures of a config update and explains the result.")
+	explainPolicyConfig    = explainPolicy.Flag("config", "The config message defining the policy.").File()
+	explainPolicyInput     = explainPolicy.Flag("input", "A signed config update envelope carrying the signatures to evaluate.").File()
+	explainPolicyChannelID = explainPolicy.Flag("channel_id", "The name of the channel of the config.").Required().String()
+	explainPolicyPath      = explainPolicy.Flag("policy", "The fully qualified path of the policy.  For example, '/Channel/Application/Admins'.").Required().String()
+	explainPolicyDest      = explainPolicy.Flag("output", "A file to write the explanation to.").Default(os.Stdout.Name()).OpenFile(os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+
 	version = app.Command("version", "Show version information")
 )
 
@@ -76,7 +89,7 @@ func main() {
 	switch kingpin.MustParse(app.Parse(os.Args[1:])) {
 	// "start" command
 	case start.FullCommand():
-		startServer(fmt.Sprintf("%s:%d", *hostname, *port))
+		startServer(net.JoinHostPort(*hostname, strconv.Itoa(*port)))
 	// "proto_encode" command
 	case protoEncode.FullCommand():
 		defer (*protoEncodeSource).Close()
@@ -100,6 +113,14 @@ func main() {
 		if err != nil {
 			app.Fatalf("Error computing update: %s", err)
 		}
+	case explainPolicy.FullCommand():
+		defer (*explainPolicyConfig).Close()
+		defer (*explainPolicyInput).Close()
+		defer (*explainPolicyDest).Close()
+		err := explainPlcy(*explainPolicyConfig, *explainPolicyInput, *explainPolicyDest, *explainPolicyChannelID, *explainPolicyPath)
+		if err != nil {
+			app.Fatalf("Error explaining policy: %s", err)
+		}
 	// "version" command
 	case version.FullCommand():
 		printVersion()
@@ -119,20 +140,14 @@ func printVersion() {
 }
 
 func encodeProto(msgName string, input, output *os.File) error {
-	msgType := proto.MessageType(msgName)
-	if msgType == nil {
-		return errors.Errorf("message of type %s unknown", msgType)
-	}
-	msg := reflect.New(msgType.Elem()).Interface().(proto.Message)
-
-	err := protolator.DeepUnmarshalJSON(input, msg)
+	in, err := ioutil.ReadAll(input)
 	if err != nil {
-		return errors.Wrapf(err, "error decoding input")
+		return errors.Wrapf(err, "error reading input")
 	}
 
-	out, err := proto.Marshal(msg)
+	out, err := translate.Encode(msgName, in)
 	if err != nil {
-		return errors.Wrapf(err, "error marshaling")
+		return err
 	}
 
 	_, err = output.Write(out)
@@ -144,25 +159,19 @@ func encodeProto(msgName string, input, output *os.File) error {
 }
 
 func decodeProto(msgName string, input, output *os.File) error {
-	msgType := proto.MessageType(msgName)
-	if msgType == nil {
-		return errors.Errorf("message of type %s unknown", msgType)
-	}
-	msg := reflect.New(msgType.Elem()).Interface().(proto.Message)
-
 	in, err := ioutil.ReadAll(input)
 	if err != nil {
 		return errors.Wrapf(err, "error reading input")
 	}
 
-	err = proto.Unmarshal(in, msg)
+	out, err := translate.Decode(msgName, in, protolator.MarshalOptions{Indent: "\t"})
 	if err != nil {
-		return errors.Wrapf(err, "error unmarshaling")
+		return err
 	}
 
-	err = protolator.DeepMarshalJSON(output, msg)
+	_, err = output.Write(out)
 	if err != nil {
-		return errors.Wrapf(err, "error encoding output")
+		return errors.Wrapf(err, "error writing output")
 	}
 
 	return nil
@@ -191,13 +200,11 @@ func computeUpdt(original, updated, output *os.File, channelID string) error {
 		return errors.Wrapf(err, "error unmarshaling updated config")
 	}
 
-	cu, err := update.Compute(origConf, updtConf)
+	cu, err := update.ComputeForChannel(channelID, origConf, updtConf)
 	if err != nil {
 		return errors.Wrapf(err, "error computing config update")
 	}
 
-	cu.ChannelId = channelID
-
 	outBytes, err := proto.Marshal(cu)
 	if err != nil {
 		return errors.Wrapf(err, "error marshaling computed config update")
@@ -210,3 +217,59 @@ func computeUpdt(original, updated, output *os.File, channelID string) error {
 
 	return nil
 }
+
+func explainPlcy(config, input, output *os.File, channelID, policyPath string) error {
+	confIn, err := ioutil.ReadAll(config)
+	if err != nil {
+		return errors.Wrapf(err, "error reading config")
+	}
+
+	conf := &cb.Config{}
+	err = proto.Unmarshal(confIn, conf)
+	if err != nil {
+		return errors.Wrapf(err, "error unmarshaling config")
+	}
+
+	envIn, err := ioutil.ReadAll(input)
+	if err != nil {
+		return errors.Wrapf(err, "error reading config update envelope")
+	}
+
+	env := &cb.Envelope{}
+	err = proto.Unmarshal(envIn, env)
+	if err != nil {
+		return errors.Wrapf(err, "error unmarshaling config update envelope")
+	}
+
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return errors.Wrapf(err, "error unmarshaling config update envelope payload")
+	}
+
+	configUpdateEnv, err := configtx.UnmarshalConfigUpdateEnvelope(payload.Data)
+	if err != nil {
+		return errors.Wrapf(err, "error unmarshaling config update")
+	}
+
+	signedData, err := configUpdateEnv.AsSignedData()
+	if err != nil {
+		return errors.Wrapf(err, "error extracting config update signatures")
+	}
+
+	bundle, err := channelconfig.NewBundle(channelID, conf)
+	if err != nil {
+		return errors.Wrapf(err, "error creating channel config bundle")
+	}
+
+	policy, ok := bundle.PolicyManager().GetPolicy(policyPath)
+	if !ok {
+		return errors.Errorf("policy %s does not exist", policyPath)
+	}
+
+	_, err = fmt.Fprint(output, policies.Explain(policy, signedData))
+	if err != nil {
+		return errors.Wrapf(err, "error writing explanation to output")
+	}
+
+	return nil
+}
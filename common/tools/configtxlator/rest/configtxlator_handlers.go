@@ -69,15 +69,13 @@ func ComputeUpdateFromConfigs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	configUpdate, err := update.Compute(originalConfig, updatedConfig)
+	configUpdate, err := update.ComputeForChannel(r.FormValue("channel"), originalConfig, updatedConfig)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		fmt.Fprintf(w, "Error computing update: %s\n", err)
 		return
 	}
 
-	configUpdate.ChannelId = r.FormValue("channel")
-
 	encoded, err := proto.Marshal(configUpdate)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -17,31 +17,28 @@ limitations under the License.
 package rest
 
 import (
-	"bytes"
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"reflect"
 
+	"github.com/hyperledger/fabric/common/tools/configtxlator/translate"
 	"github.com/hyperledger/fabric/common/tools/protolator"
 
-	"github.com/golang/protobuf/proto"
 	"github.com/gorilla/mux"
 )
 
-func getMsgType(r *http.Request) (proto.Message, error) {
+func getMsgName(r *http.Request) (string, error) {
 	vars := mux.Vars(r)
 	msgName := vars["msgName"] // Will not arrive is unset
 
-	msgType := proto.MessageType(msgName)
-	if msgType == nil {
-		return nil, fmt.Errorf("message name not found")
+	if _, err := translate.NewMessage(msgName); err != nil {
+		return "", fmt.Errorf("message name not found")
 	}
-	return reflect.New(msgType.Elem()).Interface().(proto.Message), nil
+	return msgName, nil
 }
 
 func Decode(w http.ResponseWriter, r *http.Request) {
-	msg, err := getMsgType(r)
+	msgName, err := getMsgName(r)
 	if err != nil {
 		w.WriteHeader(http.StatusNotFound)
 		fmt.Fprintln(w, err)
@@ -55,15 +52,7 @@ func Decode(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = proto.Unmarshal(buf, msg)
-	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprintln(w, err)
-		return
-	}
-
-	var buffer bytes.Buffer
-	err = protolator.DeepMarshalJSON(&buffer, msg)
+	data, err := translate.Decode(msgName, buf, protolator.MarshalOptions{Indent: "\t"})
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		fmt.Fprintln(w, err)
@@ -72,25 +61,25 @@ func Decode(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusOK)
 	w.Header().Set("Content-Type", "application/json")
-	buffer.WriteTo(w)
+	w.Write(data)
 }
 
 func Encode(w http.ResponseWriter, r *http.Request) {
-	msg, err := getMsgType(r)
+	msgName, err := getMsgName(r)
 	if err != nil {
 		w.WriteHeader(http.StatusNotFound)
 		fmt.Fprintln(w, err)
 		return
 	}
 
-	err = protolator.DeepUnmarshalJSON(r.Body, msg)
+	buf, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		fmt.Fprintln(w, err)
 		return
 	}
 
-	data, err := proto.Marshal(msg)
+	data, err := translate.Encode(msgName, buf)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		fmt.Fprintln(w, err)
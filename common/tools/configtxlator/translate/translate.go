@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package translate converts the protobuf messages of Fabric to and from their
+// JSON representation, as the proto_encode and proto_decode commands of
+// configtxlator do, so that tooling can do so in process.
+package translate
+
+import (
+	"bytes"
+	"reflect"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/tools/protolator"
+	"github.com/pkg/errors"
+
+	// Import these to register the proto types
+	_ "github.com/hyperledger/fabric/protos/common"
+	_ "github.com/hyperledger/fabric/protos/msp"
+	_ "github.com/hyperledger/fabric/protos/orderer"
+	_ "github.com/hyperledger/fabric/protos/orderer/etcdraft"
+	_ "github.com/hyperledger/fabric/protos/peer"
+)
+
+// NewMessage returns a new message of the given fully qualified type, such as common.Config
+func NewMessage(msgName string) (proto.Message, error) {
+	msgType := proto.MessageType(msgName)
+	if msgType == nil {
+		return nil, errors.Errorf("message of type %s unknown", msgName)
+	}
+	return reflect.New(msgType.Elem()).Interface().(proto.Message), nil
+}
+
+// Decode returns the JSON representation of the given marshaled message of
+// the given type, marshaled with the given options
+func Decode(msgName string, data []byte, opts protolator.MarshalOptions) ([]byte, error) {
+	msg, err := NewMessage(msgName)
+	if err != nil {
+		return nil, err
+	}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return nil, errors.Wrapf(err, "error unmarshaling")
+	}
+	buf := &bytes.Buffer{}
+	if err := opts.Marshal(buf, msg); err != nil {
+		return nil, errors.Wrapf(err, "error encoding")
+	}
+	return buf.Bytes(), nil
+}
+
+// Encode returns the marshaled message of the given type represented by the
+// given JSON.  Maps are marshaled in the order of their keys, so that the same
+// JSON is always encoded to the same bytes by the same build.
+func Encode(msgName string, jsonData []byte) ([]byte, error) {
+	msg, err := NewMessage(msgName)
+	if err != nil {
+		return nil, err
+	}
+	if err := protolator.DeepUnmarshalJSON(bytes.NewReader(jsonData), msg); err != nil {
+		return nil, errors.Wrapf(err, "error decoding")
+	}
+	data, err := protolator.MostlyDeterministicMarshal(msg)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error marshaling")
+	}
+	return data, nil
+}
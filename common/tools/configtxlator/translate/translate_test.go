@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package translate
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/tools/protolator"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMessage(t *testing.T) {
+	msg, err := NewMessage("common.Config")
+	assert.NoError(t, err)
+	assert.IsType(t, &cb.Config{}, msg)
+
+	_, err = NewMessage("common.NotAMessage")
+	assert.EqualError(t, err, "message of type common.NotAMessage unknown")
+}
+
+func TestDecodeEncode(t *testing.T) {
+	config := &cb.Config{
+		Sequence: 3,
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				"Application": {ModPolicy: "Admins"},
+				"Orderer":     {ModPolicy: "Admins"},
+			},
+			Values: map[string]*cb.ConfigValue{
+				"Consortium": {Value: utils.MarshalOrPanic(&cb.Consortium{Name: "SampleConsortium"})},
+			},
+		},
+	}
+	data := utils.MarshalOrPanic(config)
+
+	jsonData, err := Decode("common.Config", data, protolator.MarshalOptions{})
+	assert.NoError(t, err)
+	assert.Contains(t, string(jsonData), `"Consortium":{"mod_policy":"","value":{"name":"SampleConsortium"},"version":"0"}`)
+
+	encoded, err := Encode("common.Config", jsonData)
+	assert.NoError(t, err)
+	decoded := &cb.Config{}
+	assert.NoError(t, proto.Unmarshal(encoded, decoded))
+	assert.True(t, proto.Equal(config, decoded))
+
+	reencoded, err := Encode("common.Config", jsonData)
+	assert.NoError(t, err)
+	assert.Equal(t, encoded, reencoded)
+
+	t.Run("UnknownType", func(t *testing.T) {
+		_, err := Decode("common.NotAMessage", data, protolator.MarshalOptions{})
+		assert.EqualError(t, err, "message of type common.NotAMessage unknown")
+		_, err = Encode("common.NotAMessage", jsonData)
+		assert.EqualError(t, err, "message of type common.NotAMessage unknown")
+	})
+
+	t.Run("BadInput", func(t *testing.T) {
+		_, err := Decode("common.Config", []byte("garbage"), protolator.MarshalOptions{})
+		assert.Contains(t, err.Error(), "error unmarshaling")
+		_, err = Encode("common.Config", []byte("garbage"))
+		assert.Contains(t, err.Error(), "error decoding")
+	})
+}
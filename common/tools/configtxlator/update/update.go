@@ -21,9 +21,71 @@ import (
 	"fmt"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/tools/protolator"
 	cb "github.com/hyperledger/fabric/protos/common"
+	_ "github.com/hyperledger/fabric/protos/orderer" // register the Orderer config group type
+	_ "github.com/hyperledger/fabric/protos/peer"    // register the Application config group type
+	"github.com/hyperledger/fabric/protos/utils"
 )
 
+// dynamicSubGroup returns the dynamic type of the given sub-group of a group of the
+// given dynamic type, or nil if it is unknown
+func dynamicSubGroup(dynamicGroup proto.Message, groupName string, group *cb.ConfigGroup) proto.Message {
+	dmfp, ok := dynamicGroup.(protolator.DynamicMapFieldProto)
+	if !ok {
+		return nil
+	}
+	dynamicSubGroup, err := dmfp.DynamicMapFieldProto("groups", groupName, group)
+	if err != nil {
+		return nil
+	}
+	return dynamicSubGroup
+}
+
+// valueProto returns a newly allocated message of the type of the given value
+// of a group of the given dynamic type, or nil if it is unknown
+func valueProto(dynamicGroup proto.Message, valueName string, value *cb.ConfigValue) proto.Message {
+	dmfp, ok := dynamicGroup.(protolator.DynamicMapFieldProto)
+	if !ok {
+		return nil
+	}
+	dynamicValue, err := dmfp.DynamicMapFieldProto("values", valueName, value)
+	if err != nil {
+		return nil
+	}
+	vofp, ok := dynamicValue.(protolator.VariablyOpaqueFieldProto)
+	if !ok {
+		return nil
+	}
+	msg, err := vofp.VariablyOpaqueFieldProto("value")
+	if err != nil {
+		return nil
+	}
+	return msg
+}
+
+// valuesEqual reports whether the given values of a group of the given dynamic type are
+// equal. Marshaled values which differ byte-wise are compared as messages when their type
+// is known, as the marshaling of protos containing maps is not deterministic.
+func valuesEqual(dynamicGroup proto.Message, valueName string, original, updated *cb.ConfigValue) bool {
+	if original.ModPolicy != updated.ModPolicy {
+		return false
+	}
+	if bytes.Equal(original.Value, updated.Value) {
+		return true
+	}
+
+	originalMsg := valueProto(dynamicGroup, valueName, original)
+	updatedMsg := valueProto(dynamicGroup, valueName, updated)
+	if originalMsg == nil || updatedMsg == nil {
+		return false
+	}
+	if proto.Unmarshal(original.Value, originalMsg) != nil || proto.Unmarshal(updated.Value, updatedMsg) != nil {
+		return false
+	}
+	return proto.Equal(originalMsg, updatedMsg)
+}
+
 func computePoliciesMapUpdate(original, updated map[string]*cb.ConfigPolicy) (readSet, writeSet, sameSet map[string]*cb.ConfigPolicy, updatedMembers bool) {
 	readSet = make(map[string]*cb.ConfigPolicy)
 	writeSet = make(map[string]*cb.ConfigPolicy)
@@ -69,7 +131,7 @@ func computePoliciesMapUpdate(original, updated map[string]*cb.ConfigPolicy) (re
 	return
 }
 
-func computeValuesMapUpdate(original, updated map[string]*cb.ConfigValue) (readSet, writeSet, sameSet map[string]*cb.ConfigValue, updatedMembers bool) {
+func computeValuesMapUpdate(original, updated map[string]*cb.ConfigValue, dynamicGroup proto.Message) (readSet, writeSet, sameSet map[string]*cb.ConfigValue, updatedMembers bool) {
 	readSet = make(map[string]*cb.ConfigValue)
 	writeSet = make(map[string]*cb.ConfigValue)
 
@@ -84,7 +146,7 @@ func computeValuesMapUpdate(original, updated map[string]*cb.ConfigValue) (readS
 			continue
 		}
 
-		if originalValue.ModPolicy == updatedValue.ModPolicy && bytes.Equal(originalValue.Value, updatedValue.Value) {
+		if valuesEqual(dynamicGroup, valueName, originalValue, updatedValue) {
 			sameSet[valueName] = &cb.ConfigValue{
 				Version: originalValue.Version,
 			}
@@ -114,7 +176,7 @@ func computeValuesMapUpdate(original, updated map[string]*cb.ConfigValue) (readS
 	return
 }
 
-func computeGroupsMapUpdate(original, updated map[string]*cb.ConfigGroup) (readSet, writeSet, sameSet map[string]*cb.ConfigGroup, updatedMembers bool) {
+func computeGroupsMapUpdate(original, updated map[string]*cb.ConfigGroup, dynamicGroup proto.Message) (readSet, writeSet, sameSet map[string]*cb.ConfigGroup, updatedMembers bool) {
 	readSet = make(map[string]*cb.ConfigGroup)
 	writeSet = make(map[string]*cb.ConfigGroup)
 
@@ -129,7 +191,7 @@ func computeGroupsMapUpdate(original, updated map[string]*cb.ConfigGroup) (readS
 			continue
 		}
 
-		groupReadSet, groupWriteSet, groupUpdated := computeGroupUpdate(originalGroup, updatedGroup)
+		groupReadSet, groupWriteSet, groupUpdated := computeGroupUpdate(originalGroup, updatedGroup, dynamicSubGroup(dynamicGroup, groupName, updatedGroup))
 		if !groupUpdated {
 			sameSet[groupName] = groupReadSet
 			continue
@@ -146,7 +208,7 @@ func computeGroupsMapUpdate(original, updated map[string]*cb.ConfigGroup) (readS
 			continue
 		}
 		updatedMembers = true
-		_, groupWriteSet, _ := computeGroupUpdate(cb.NewConfigGroup(), updatedGroup)
+		_, groupWriteSet, _ := computeGroupUpdate(cb.NewConfigGroup(), updatedGroup, dynamicSubGroup(dynamicGroup, groupName, updatedGroup))
 		writeSet[groupName] = &cb.ConfigGroup{
 			Version:   0,
 			ModPolicy: updatedGroup.ModPolicy,
@@ -159,10 +221,12 @@ func computeGroupsMapUpdate(original, updated map[string]*cb.ConfigGroup) (readS
 	return
 }
 
-func computeGroupUpdate(original, updated *cb.ConfigGroup) (readSet, writeSet *cb.ConfigGroup, updatedGroup bool) {
+// computeGroupUpdate computes the read and write sets transitioning the original group into the updated one.
+// The dynamic group, if not nil, decorates the group with the types of its values and sub-groups.
+func computeGroupUpdate(original, updated *cb.ConfigGroup, dynamicGroup proto.Message) (readSet, writeSet *cb.ConfigGroup, updatedGroup bool) {
 	readSetPolicies, writeSetPolicies, sameSetPolicies, policiesMembersUpdated := computePoliciesMapUpdate(original.Policies, updated.Policies)
-	readSetValues, writeSetValues, sameSetValues, valuesMembersUpdated := computeValuesMapUpdate(original.Values, updated.Values)
-	readSetGroups, writeSetGroups, sameSetGroups, groupsMembersUpdated := computeGroupsMapUpdate(original.Groups, updated.Groups)
+	readSetValues, writeSetValues, sameSetValues, valuesMembersUpdated := computeValuesMapUpdate(original.Values, updated.Values, dynamicGroup)
+	readSetGroups, writeSetGroups, sameSetGroups, groupsMembersUpdated := computeGroupsMapUpdate(original.Groups, updated.Groups, dynamicGroup)
 
 	// If the updated group is 'Equal' to the updated group (none of the members nor the mod policy changed)
 	if !(policiesMembersUpdated || valuesMembersUpdated || groupsMembersUpdated || original.ModPolicy != updated.ModPolicy) {
@@ -223,6 +287,8 @@ func computeGroupUpdate(original, updated *cb.ConfigGroup) (readSet, writeSet *c
 		}, true
 }
 
+// Compute computes the minimal config update which transitions the original config into the
+// updated one, covering additions, modifications and removals of groups, values and policies
 func Compute(original, updated *cb.Config) (*cb.ConfigUpdate, error) {
 	if original.ChannelGroup == nil {
 		return nil, fmt.Errorf("no channel group included for original config")
@@ -232,7 +298,7 @@ func Compute(original, updated *cb.Config) (*cb.ConfigUpdate, error) {
 		return nil, fmt.Errorf("no channel group included for updated config")
 	}
 
-	readSet, writeSet, groupUpdated := computeGroupUpdate(original.ChannelGroup, updated.ChannelGroup)
+	readSet, writeSet, groupUpdated := computeGroupUpdate(original.ChannelGroup, updated.ChannelGroup, &cb.DynamicChannelGroup{ConfigGroup: updated.ChannelGroup})
 	if !groupUpdated {
 		return nil, fmt.Errorf("no differences detected between original and updated config")
 	}
@@ -241,3 +307,32 @@ func Compute(original, updated *cb.Config) (*cb.ConfigUpdate, error) {
 		WriteSet: writeSet,
 	}, nil
 }
+
+// ComputeForChannel computes the config update of the given channel which transitions
+// its original config into the updated one
+func ComputeForChannel(channelID string, original, updated *cb.Config) (*cb.ConfigUpdate, error) {
+	configUpdate, err := Compute(original, updated)
+	if err != nil {
+		return nil, err
+	}
+	configUpdate.ChannelId = channelID
+	return configUpdate, nil
+}
+
+// ComputeEnvelope computes the config update of the given channel which transitions its original
+// config into the updated one, and wraps it in an unsigned CONFIG_UPDATE envelope. The signatures
+// the modification policies of the update require are to be added to the ConfigUpdateEnvelope,
+// and the envelope is to be signed by the submitter before being sent to the ordering service.
+func ComputeEnvelope(channelID string, original, updated *cb.Config) (*cb.Envelope, error) {
+	configUpdate, err := ComputeForChannel(channelID, original, updated)
+	if err != nil {
+		return nil, err
+	}
+	configUpdateBytes, err := proto.Marshal(configUpdate)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling config update: %s", err)
+	}
+	return utils.CreateSignedEnvelope(cb.HeaderType_CONFIG_UPDATE, channelID, nil, &cb.ConfigUpdateEnvelope{
+		ConfigUpdate: configUpdateBytes,
+	}, 0, 0)
+}
@@ -19,7 +19,9 @@ package update
 import (
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -599,3 +601,152 @@ func TestTwiceNestedModification(t *testing.T) {
 
 	assert.Equal(t, expectedWriteSet, cu.WriteSet, "Mismatched write set")
 }
+
+// capabilitiesValue returns the given capabilities marshaled one by one, in order,
+// which is a valid encoding of a Capabilities message holding all of them
+func capabilitiesValue(names ...string) []byte {
+	var value []byte
+	for _, name := range names {
+		value = append(value, utils.MarshalOrPanic(&cb.Capabilities{
+			Capabilities: map[string]*cb.Capability{name: {}},
+		})...)
+	}
+	return value
+}
+
+func TestReorderedMapValue(t *testing.T) {
+	newConfig := func(channelCapabilities, appCapabilities []byte) *cb.Config {
+		return &cb.Config{
+			ChannelGroup: &cb.ConfigGroup{
+				Version: 1,
+				Values: map[string]*cb.ConfigValue{
+					"Capabilities": {Version: 2, Value: channelCapabilities},
+				},
+				Groups: map[string]*cb.ConfigGroup{
+					"Application": {
+						Version: 3,
+						Values: map[string]*cb.ConfigValue{
+							"Capabilities": {Version: 4, Value: appCapabilities},
+						},
+					},
+				},
+			},
+		}
+	}
+	original := newConfig(capabilitiesValue("V1_1", "V1_3"), capabilitiesValue("V1_1", "V1_2"))
+
+	t.Run("SameCapabilities", func(t *testing.T) {
+		updated := newConfig(capabilitiesValue("V1_3", "V1_1"), capabilitiesValue("V1_2", "V1_1"))
+		_, err := Compute(original, updated)
+		assert.EqualError(t, err, "no differences detected between original and updated config")
+	})
+
+	t.Run("ChangedCapabilities", func(t *testing.T) {
+		updated := newConfig(capabilitiesValue("V1_3", "V1_1"), capabilitiesValue("V1_3", "V1_1"))
+		cu, err := Compute(original, updated)
+		assert.NoError(t, err)
+
+		expectedWriteSet := &cb.ConfigGroup{
+			Version: 1,
+			Groups: map[string]*cb.ConfigGroup{
+				"Application": {
+					Version: 3,
+					Values: map[string]*cb.ConfigValue{
+						"Capabilities": {Version: 5, Value: updated.ChannelGroup.Groups["Application"].Values["Capabilities"].Value},
+					},
+					Policies: map[string]*cb.ConfigPolicy{},
+					Groups:   map[string]*cb.ConfigGroup{},
+				},
+			},
+			Policies: map[string]*cb.ConfigPolicy{},
+			Values:   map[string]*cb.ConfigValue{},
+		}
+		assert.Equal(t, expectedWriteSet, cu.WriteSet, "Mismatched write set")
+	})
+}
+
+func TestMSPRotationAndConsenterChange(t *testing.T) {
+	newConfig := func(msp, consensusMetadata string) *cb.Config {
+		return &cb.Config{
+			ChannelGroup: &cb.ConfigGroup{
+				Groups: map[string]*cb.ConfigGroup{
+					"Orderer": {
+						Version: 2,
+						Values: map[string]*cb.ConfigValue{
+							"ConsensusType": {Version: 1, Value: []byte(consensusMetadata), ModPolicy: "Admins"},
+							"BatchSize":     {Version: 3, Value: []byte("batchsize"), ModPolicy: "Admins"},
+						},
+						Groups: map[string]*cb.ConfigGroup{
+							"OrdererOrg": {
+								Version: 4,
+								Values: map[string]*cb.ConfigValue{
+									"MSP": {Version: 5, Value: []byte(msp), ModPolicy: "Admins"},
+								},
+								Policies: map[string]*cb.ConfigPolicy{
+									"Admins": {Version: 6, Policy: &cb.Policy{Type: 1}},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	cu, err := ComputeForChannel("mychannel", newConfig("oldcert", "consenters1"), newConfig("newcert", "consenters2"))
+	assert.NoError(t, err)
+	assert.Equal(t, "mychannel", cu.ChannelId)
+
+	// only the rotated MSP and the consenter set are written, at their next version
+	expectedWriteSet := &cb.ConfigGroup{
+		Groups: map[string]*cb.ConfigGroup{
+			"Orderer": {
+				Version: 2,
+				Values: map[string]*cb.ConfigValue{
+					"ConsensusType": {Version: 2, Value: []byte("consenters2"), ModPolicy: "Admins"},
+				},
+				Groups: map[string]*cb.ConfigGroup{
+					"OrdererOrg": {
+						Version: 4,
+						Values: map[string]*cb.ConfigValue{
+							"MSP": {Version: 6, Value: []byte("newcert"), ModPolicy: "Admins"},
+						},
+						Policies: map[string]*cb.ConfigPolicy{},
+						Groups:   map[string]*cb.ConfigGroup{},
+					},
+				},
+				Policies: map[string]*cb.ConfigPolicy{},
+			},
+		},
+		Policies: map[string]*cb.ConfigPolicy{},
+		Values:   map[string]*cb.ConfigValue{},
+	}
+	assert.Equal(t, expectedWriteSet, cu.WriteSet, "Mismatched write set")
+}
+
+func TestComputeEnvelope(t *testing.T) {
+	original := &cb.Config{ChannelGroup: &cb.ConfigGroup{Version: 1}}
+	updated := &cb.Config{ChannelGroup: &cb.ConfigGroup{ModPolicy: "Admins"}}
+
+	env, err := ComputeEnvelope("mychannel", original, updated)
+	assert.NoError(t, err)
+	assert.Nil(t, env.Signature)
+
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	assert.NoError(t, err)
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(cb.HeaderType_CONFIG_UPDATE), chdr.Type)
+	assert.Equal(t, "mychannel", chdr.ChannelId)
+
+	configUpdateEnv := &cb.ConfigUpdateEnvelope{}
+	assert.NoError(t, proto.Unmarshal(payload.Data, configUpdateEnv))
+	configUpdate := &cb.ConfigUpdate{}
+	assert.NoError(t, proto.Unmarshal(configUpdateEnv.ConfigUpdate, configUpdate))
+	assert.Equal(t, "mychannel", configUpdate.ChannelId)
+	assert.Equal(t, uint64(2), configUpdate.WriteSet.Version)
+	assert.Equal(t, "Admins", configUpdate.WriteSet.ModPolicy)
+
+	_, err = ComputeEnvelope("mychannel", original, original)
+	assert.EqualError(t, err, "no differences detected between original and updated config")
+}
@@ -146,6 +146,15 @@ func TestGenerateSignCertificate(t *testing.T) {
 	assert.Contains(t, cert.DNSNames, testName2)
 	assert.Contains(t, cert.IPAddresses, net.ParseIP(testIP).To4())
 
+	// IPv6 sans are set as IP addresses, with or without brackets
+	sans = []string{"2001:db8::1", "[2001:db8::2]"}
+	cert, err = rootCA.SignCertificate(certDir, testName, nil, sans, ecPubKey,
+		x509.KeyUsageDigitalSignature, []x509.ExtKeyUsage{})
+	assert.NoError(t, err, "Failed to generate signed certificate")
+	assert.Empty(t, cert.DNSNames)
+	assert.Contains(t, cert.IPAddresses, net.ParseIP("2001:db8::1"))
+	assert.Contains(t, cert.IPAddresses, net.ParseIP("2001:db8::2"))
+
 	// check to make sure the signed public key was stored
 	pemFile := filepath.Join(certDir, testName+"-cert.pem")
 	assert.Equal(t, true, checkForFile(pemFile),
@@ -167,6 +176,41 @@ func TestGenerateSignCertificate(t *testing.T) {
 
 }
 
+func TestRenewCertificate(t *testing.T) {
+	caDir := filepath.Join(testDir, "ca")
+	certDir := filepath.Join(testDir, "certs")
+	priv, _, err := csp.GeneratePrivateKey(certDir)
+	assert.NoError(t, err, "Failed to generate private key")
+	ecPubKey, err := csp.GetECPublicKey(priv)
+	assert.NoError(t, err, "Failed to get public key")
+
+	rootCA, err := ca.NewCA(caDir, testCAName, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+
+	cert, err := rootCA.SignCertificate(certDir, testName, []string{"PeerOU"}, []string{testName2, testIP}, ecPubKey,
+		x509.KeyUsageDigitalSignature, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth})
+	assert.NoError(t, err, "Failed to generate signed certificate")
+
+	renewed, err := rootCA.RenewCertificate(certDir, testName, cert)
+	assert.NoError(t, err, "Failed to renew certificate")
+	assert.NotEqual(t, cert.SerialNumber, renewed.SerialNumber, "Should have a new serial number")
+	assert.Equal(t, cert.Subject.String(), renewed.Subject.String(), "Should have same subject")
+	assert.Equal(t, cert.PublicKey, renewed.PublicKey, "Should have same public key")
+	assert.Equal(t, cert.KeyUsage, renewed.KeyUsage)
+	assert.Equal(t, cert.ExtKeyUsage, renewed.ExtKeyUsage)
+	assert.Equal(t, cert.DNSNames, renewed.DNSNames)
+	assert.Equal(t, cert.IPAddresses, renewed.IPAddresses)
+	assert.NoError(t, renewed.CheckSignatureFrom(rootCA.SignCert), "Should be signed by the CA")
+
+	loadedCert, err := ca.LoadCertificateECDSA(certDir)
+	assert.NoError(t, err)
+	assert.Equal(t, renewed.SerialNumber, loadedCert.SerialNumber, "Should have replaced the certificate")
+
+	_, err = rootCA.RenewCertificate(certDir, testName, &x509.Certificate{})
+	assert.EqualError(t, err, "certificate public key is not an ECDSA key")
+	cleanup(testDir)
+}
+
 func cleanup(dir string) {
 	os.RemoveAll(dir)
 }
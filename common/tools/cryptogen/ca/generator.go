@@ -12,6 +12,7 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"errors"
 	"io/ioutil"
 	"math/big"
 	"net"
@@ -108,8 +109,9 @@ func (ca *CA) SignCertificate(baseDir, name string, ous, sans []string, pub *ecd
 
 	template.Subject = subject
 	for _, san := range sans {
-		// try to parse as an IP address first
-		ip := net.ParseIP(san)
+		// try to parse as an IP address first, IPv6 addresses may be
+		// enclosed in brackets
+		ip := net.ParseIP(strings.Trim(san, "[]"))
 		if ip != nil {
 			template.IPAddresses = append(template.IPAddresses, ip)
 		} else {
@@ -127,6 +129,26 @@ func (ca *CA) SignCertificate(baseDir, name string, ous, sans []string, pub *ecd
 	return cert, nil
 }
 
+// RenewCertificate reissues the given certificate, signed by the CA, for the
+// same subject, public key, key usages and SANs with a fresh validity period,
+// and saves it in baseDir/name
+func (ca *CA) RenewCertificate(baseDir, name string, cert *x509.Certificate) (*x509.Certificate, error) {
+	pub, ok := cert.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("certificate public key is not an ECDSA key")
+	}
+
+	template := x509Template()
+	template.KeyUsage = cert.KeyUsage
+	template.ExtKeyUsage = cert.ExtKeyUsage
+	template.Subject = cert.Subject
+	template.SubjectKeyId = cert.SubjectKeyId
+	template.DNSNames = cert.DNSNames
+	template.IPAddresses = cert.IPAddresses
+
+	return genCertificateECDSA(baseDir, name, &template, ca.SignCert, pub, ca.Signer)
+}
+
 // default template for X509 subject
 func subjectTemplate() pkix.Name {
 	return pkix.Name{
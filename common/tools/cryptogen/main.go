@@ -12,7 +12,9 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"text/template"
+	"time"
 
 	"github.com/hyperledger/fabric/common/tools/cryptogen/ca"
 	"github.com/hyperledger/fabric/common/tools/cryptogen/csp"
@@ -210,6 +212,11 @@ var (
 	ext           = app.Command("extend", "Extend existing network")
 	inputDir      = ext.Flag("input", "The input directory in which existing network place").Default("crypto-config").String()
 	extConfigFile = ext.Flag("config", "The configuration template to use").File()
+
+	renewal        = app.Command("renew", "Reissue the certificates of an existing network which are about to expire")
+	renewInputDir  = renewal.Flag("input", "The input directory in which existing network place").Default("crypto-config").String()
+	expiringWithin = renewal.Flag("expiring-within", "Reissue the certificates expiring within this duration").Default("720h").Duration()
+	renewAdmins    = renewal.Flag("admins", "Also reissue the signing certificates of the admins, which must then be updated in the channel configurations").Bool()
 )
 
 func main() {
@@ -223,6 +230,9 @@ func main() {
 	case ext.FullCommand():
 		extend()
 
+	case renewal.FullCommand():
+		renew()
+
 		// "showtemplate" command
 	case showtemplate.FullCommand():
 		fmt.Print(defaultConfig)
@@ -370,6 +380,189 @@ func extendOrdererOrg(orgSpec OrgSpec) {
 	}
 }
 
+func renew() {
+	deadline := time.Now().Add(*expiringWithin)
+
+	for _, orgsDir := range []string{"peerOrganizations", "ordererOrganizations"} {
+		orgs, err := ioutil.ReadDir(filepath.Join(*renewInputDir, orgsDir))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			fmt.Printf("Error reading %s:\n%v\n", orgsDir, err)
+			os.Exit(1)
+		}
+		for _, org := range orgs {
+			if org.IsDir() {
+				renewOrg(filepath.Join(*renewInputDir, orgsDir, org.Name()), deadline)
+			}
+		}
+	}
+}
+
+func renewOrg(orgDir string, deadline time.Time) {
+	orgName := filepath.Base(orgDir)
+
+	signCA, err := loadCA(filepath.Join(orgDir, "ca"))
+	if err != nil {
+		fmt.Printf("Error loading signCA for org %s:\n%v\n", orgName, err)
+		os.Exit(1)
+	}
+	tlsCA, err := loadCA(filepath.Join(orgDir, "tlsca"))
+	if err != nil {
+		fmt.Printf("Error loading tlsCA for org %s:\n%v\n", orgName, err)
+		os.Exit(1)
+	}
+
+	// CA certificates are part of the channel MSP definitions, so they can't be reissued in place
+	for _, c := range []*ca.CA{signCA, tlsCA} {
+		if c.SignCert.NotAfter.Before(deadline) {
+			fmt.Printf("Warning: CA certificate %s of org %s expires on %s and must be replaced through a channel configuration update\n",
+				c.Name, orgName, c.SignCert.NotAfter)
+		}
+	}
+
+	admins, err := adminUsers(orgDir)
+	if err != nil {
+		fmt.Printf("Error reading admin certs of org %s:\n%v\n", orgName, err)
+		os.Exit(1)
+	}
+
+	for _, nodesDir := range []string{"peers", "orderers", "users"} {
+		nodes, err := ioutil.ReadDir(filepath.Join(orgDir, nodesDir))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			fmt.Printf("Error reading %s of org %s:\n%v\n", nodesDir, orgName, err)
+			os.Exit(1)
+		}
+		for _, node := range nodes {
+			if !node.IsDir() {
+				continue
+			}
+			nodeDir := filepath.Join(orgDir, nodesDir, node.Name())
+			var renewed bool
+			if nodesDir == "users" && admins[node.Name()] {
+				renewed, err = renewAdmin(orgDir, nodeDir, node.Name(), signCA, tlsCA, deadline)
+			} else {
+				renewed, err = msp.RenewLocalMSP(nodeDir, node.Name(), signCA, tlsCA, deadline)
+			}
+			if err != nil {
+				fmt.Printf("Error renewing local MSP for %s:\n%v\n", node.Name(), err)
+				os.Exit(1)
+			}
+			if renewed {
+				fmt.Println(node.Name())
+			}
+		}
+	}
+
+	err = refreshAdminCerts(orgDir)
+	if err != nil {
+		fmt.Printf("Error refreshing admin certs for org %s:\n%v\n", orgName, err)
+		os.Exit(1)
+	}
+}
+
+// adminUsers returns the names of the users whose certs are the admin certs of
+// the MSP of the org
+func adminUsers(orgDir string) (map[string]bool, error) {
+	admins := make(map[string]bool)
+	adminCerts, err := ioutil.ReadDir(filepath.Join(orgDir, "msp", "admincerts"))
+	if os.IsNotExist(err) {
+		return admins, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, adminCert := range adminCerts {
+		admins[strings.TrimSuffix(adminCert.Name(), "-cert.pem")] = true
+	}
+	return admins, nil
+}
+
+// renewAdmin reissues the TLS certificates of an admin of the org which expire
+// before the deadline.  Its signing certificate is part of the channel MSP
+// definitions of the org, so it is only reissued when requested, and the
+// channel configuration updates it then requires are listed.
+func renewAdmin(orgDir, baseDir, name string, signCA, tlsCA *ca.CA, deadline time.Time) (bool, error) {
+	orgName := filepath.Base(orgDir)
+
+	renewed, err := msp.RenewTLSCerts(baseDir, name, tlsCA, deadline)
+	if err != nil {
+		return false, err
+	}
+	expiry, err := msp.SigningCertExpiry(baseDir, name)
+	if err != nil {
+		return renewed, err
+	}
+	if !expiry.Before(deadline) {
+		return renewed, nil
+	}
+	if !*renewAdmins {
+		fmt.Printf("Warning: signing certificate of admin %s of org %s expires on %s and is only reissued with --admins\n",
+			name, orgName, expiry)
+		return renewed, nil
+	}
+
+	_, err = msp.RenewSigningCert(baseDir, name, signCA, deadline)
+	if err != nil {
+		return renewed, err
+	}
+	adminsPaths := []string{"channel_group.groups.Application.groups.<org>.values.MSP.value.config.admins of every application channel of the org",
+		"channel_group.groups.Consortiums.groups.<consortium>.groups.<org>.values.MSP.value.config.admins of the orderer system channel"}
+	if filepath.Base(filepath.Dir(orgDir)) == "ordererOrganizations" {
+		adminsPaths = []string{"channel_group.groups.Orderer.groups.<org>.values.MSP.value.config.admins of every channel"}
+	}
+	fmt.Printf("Warning: signing certificate of admin %s of org %s was reissued and must replace the former one through channel configuration updates of:\n",
+		name, orgName)
+	for _, path := range adminsPaths {
+		fmt.Printf("  %s\n", path)
+	}
+	return true, nil
+}
+
+// refreshAdminCerts replaces the admin certs of the MSPs of the org with the
+// current signing certs of the corresponding users
+func refreshAdminCerts(orgDir string) error {
+	adminCertsDirs := []string{filepath.Join(orgDir, "msp", "admincerts")}
+	for _, nodesDir := range []string{"peers", "orderers", "users"} {
+		nodes, err := ioutil.ReadDir(filepath.Join(orgDir, nodesDir))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for _, node := range nodes {
+			adminCertsDirs = append(adminCertsDirs, filepath.Join(orgDir, nodesDir, node.Name(), "msp", "admincerts"))
+		}
+	}
+
+	for _, adminCertsDir := range adminCertsDirs {
+		adminCerts, err := ioutil.ReadDir(adminCertsDir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for _, adminCert := range adminCerts {
+			userName := strings.TrimSuffix(adminCert.Name(), "-cert.pem")
+			signCert := filepath.Join(orgDir, "users", userName, "msp", "signcerts", adminCert.Name())
+			if _, err := os.Stat(signCert); os.IsNotExist(err) {
+				continue
+			}
+			err = copyFile(signCert, filepath.Join(adminCertsDir, adminCert.Name()))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func generate() {
 
 	config, err := getConfig()
@@ -698,13 +891,16 @@ func printVersion() {
 }
 
 func getCA(caDir string, spec OrgSpec, name string) *ca.CA {
-	_, signer, _ := csp.LoadPrivateKey(caDir)
-	cert, _ := ca.LoadCertificateECDSA(caDir)
+	loaded, err := loadCA(caDir)
+	if err != nil {
+		fmt.Printf("Error loading CA %s for org %s:\n%v\n", name, spec.Domain, err)
+		os.Exit(1)
+	}
 
 	return &ca.CA{
 		Name:               name,
-		Signer:             signer,
-		SignCert:           cert,
+		Signer:             loaded.Signer,
+		SignCert:           loaded.SignCert,
 		Country:            spec.CA.Country,
 		Province:           spec.CA.Province,
 		Locality:           spec.CA.Locality,
@@ -713,3 +909,27 @@ func getCA(caDir string, spec OrgSpec, name string) *ca.CA {
 		PostalCode:         spec.CA.PostalCode,
 	}
 }
+
+// loadCA loads the signing key pair of an existing CA from caDir
+func loadCA(caDir string) (*ca.CA, error) {
+	_, signer, err := csp.LoadPrivateKey(caDir)
+	if err != nil {
+		return nil, err
+	}
+	if signer == nil {
+		return nil, fmt.Errorf("no private key found in %s", caDir)
+	}
+	cert, err := ca.LoadCertificateECDSA(caDir)
+	if err != nil {
+		return nil, err
+	}
+	if cert == nil {
+		return nil, fmt.Errorf("no certificate found in %s", caDir)
+	}
+
+	return &ca.CA{
+		Name:     cert.Subject.CommonName,
+		Signer:   signer,
+		SignCert: cert,
+	}, nil
+}
@@ -9,9 +9,12 @@ import (
 	"crypto/x509"
 	"encoding/hex"
 	"encoding/pem"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
 
 	"github.com/hyperledger/fabric/bccsp"
@@ -157,6 +160,78 @@ func GenerateLocalMSP(baseDir, name string, sans []string, signCA *ca.CA,
 	return nil
 }
 
+// RenewLocalMSP reissues the signing and TLS certificates of the local MSP in
+// baseDir which expire before the deadline, keeping their keys, and returns
+// whether any of them was reissued
+func RenewLocalMSP(baseDir, name string, signCA *ca.CA, tlsCA *ca.CA, deadline time.Time) (bool, error) {
+	signRenewed, err := RenewSigningCert(baseDir, name, signCA, deadline)
+	if err != nil {
+		return false, err
+	}
+	tlsRenewed, err := RenewTLSCerts(baseDir, name, tlsCA, deadline)
+	return signRenewed || tlsRenewed, err
+}
+
+// SigningCertExpiry returns the expiration date of the signing certificate of
+// the local MSP in baseDir
+func SigningCertExpiry(baseDir, name string) (time.Time, error) {
+	cert, err := readCertificate(filepath.Join(baseDir, "msp", "signcerts", x509Filename(name)))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cert.NotAfter, nil
+}
+
+// RenewSigningCert reissues the signing certificate of the local MSP in baseDir
+// if it expires before the deadline, and returns whether it was reissued
+func RenewSigningCert(baseDir, name string, signCA *ca.CA, deadline time.Time) (bool, error) {
+	signCertsDir := filepath.Join(baseDir, "msp", "signcerts")
+	cert, err := readCertificate(filepath.Join(signCertsDir, x509Filename(name)))
+	if err != nil {
+		return false, err
+	}
+	if !cert.NotAfter.Before(deadline) {
+		return false, nil
+	}
+	_, err = signCA.RenewCertificate(signCertsDir, name, cert)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RenewTLSCerts reissues the TLS certificates of the local MSP in baseDir
+// which expire before the deadline, and returns whether any of them was reissued
+func RenewTLSCerts(baseDir, name string, tlsCA *ca.CA, deadline time.Time) (bool, error) {
+	renewed := false
+
+	tlsDir := filepath.Join(baseDir, "tls")
+	for _, tlsFilePrefix := range []string{"server", "client"} {
+		tlsCertFile := filepath.Join(tlsDir, tlsFilePrefix+".crt")
+		if _, err := os.Stat(tlsCertFile); os.IsNotExist(err) {
+			continue
+		}
+		cert, err := readCertificate(tlsCertFile)
+		if err != nil {
+			return renewed, err
+		}
+		if !cert.NotAfter.Before(deadline) {
+			continue
+		}
+		_, err = tlsCA.RenewCertificate(tlsDir, name, cert)
+		if err != nil {
+			return renewed, err
+		}
+		err = os.Rename(filepath.Join(tlsDir, x509Filename(name)), tlsCertFile)
+		if err != nil {
+			return renewed, err
+		}
+		renewed = true
+	}
+
+	return renewed, nil
+}
+
 func GenerateVerifyingMSP(baseDir string, signCA *ca.CA, tlsCA *ca.CA, nodeOUs bool) error {
 
 	// create folder structure and write artifacts to proper locations
@@ -228,6 +303,18 @@ func x509Filename(name string) string {
 	return name + "-cert.pem"
 }
 
+func readCertificate(path string) (*x509.Certificate, error) {
+	pemBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.Errorf("no PEM data found in %s", path)
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
 func x509Export(path string, cert *x509.Certificate) error {
 	return pemExport(path, "CERTIFICATE", cert.Raw)
 }
@@ -6,10 +6,12 @@ SPDX-License-Identifier: Apache-2.0
 package msp_test
 
 import (
+	"crypto/tls"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v2"
@@ -172,6 +174,114 @@ func TestGenerateVerifyingMSP(t *testing.T) {
 	cleanup(testDir)
 }
 
+func TestRenewLocalMSP(t *testing.T) {
+
+	cleanup(testDir)
+
+	caDir := filepath.Join(testDir, "ca")
+	tlsCADir := filepath.Join(testDir, "tlsca")
+	signCertFile := filepath.Join(testDir, "msp", "signcerts", testName+"-cert.pem")
+	tlsCertFile := filepath.Join(testDir, "tls", "server.crt")
+
+	signCA, err := ca.NewCA(caDir, testCAOrg, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+	tlsCA, err := ca.NewCA(tlsCADir, testCAOrg, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+
+	err = msp.GenerateLocalMSP(testDir, testName, []string{testName}, signCA, tlsCA, msp.PEER, true)
+	assert.NoError(t, err, "Failed to generate local MSP")
+	signCert, err := ioutil.ReadFile(signCertFile)
+	assert.NoError(t, err)
+	tlsCert, err := ioutil.ReadFile(tlsCertFile)
+	assert.NoError(t, err)
+
+	// nothing expires within a day
+	renewed, err := msp.RenewLocalMSP(testDir, testName, signCA, tlsCA, time.Now().Add(24*time.Hour))
+	assert.NoError(t, err)
+	assert.False(t, renewed)
+	renewedSignCert, err := ioutil.ReadFile(signCertFile)
+	assert.NoError(t, err)
+	assert.Equal(t, signCert, renewedSignCert)
+
+	// everything expires within 20 years
+	renewed, err = msp.RenewLocalMSP(testDir, testName, signCA, tlsCA, time.Now().Add(20*365*24*time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, renewed)
+	renewedSignCert, err = ioutil.ReadFile(signCertFile)
+	assert.NoError(t, err)
+	assert.NotEqual(t, signCert, renewedSignCert)
+	renewedTLSCert, err := ioutil.ReadFile(tlsCertFile)
+	assert.NoError(t, err)
+	assert.NotEqual(t, tlsCert, renewedTLSCert)
+	assert.False(t, checkForFile(filepath.Join(testDir, "tls", testName+"-cert.pem")))
+
+	// the renewed certificates still match the keys of the local MSP
+	_, err = tls.LoadX509KeyPair(tlsCertFile, filepath.Join(testDir, "tls", "server.key"))
+	assert.NoError(t, err, "Renewed TLS certificate should match the TLS key")
+	testMSPConfig, err := fabricmsp.GetLocalMspConfig(filepath.Join(testDir, "msp"), nil, testName)
+	assert.NoError(t, err, "Error parsing local MSP config")
+	testMSP, err := fabricmsp.New(&fabricmsp.BCCSPNewOpts{NewBaseOpts: fabricmsp.NewBaseOpts{Version: fabricmsp.MSPv1_0}})
+	assert.NoError(t, err, "Error creating new BCCSP MSP")
+	err = testMSP.Setup(testMSPConfig)
+	assert.NoError(t, err, "Error setting up local MSP")
+
+	_, err = msp.RenewLocalMSP(filepath.Join(testDir, "missing"), testName, signCA, tlsCA, time.Now())
+	assert.Error(t, err, "Should have failed without a local MSP")
+	cleanup(testDir)
+}
+
+func TestRenewSigningAndTLSCerts(t *testing.T) {
+
+	cleanup(testDir)
+	defer cleanup(testDir)
+
+	signCertFile := filepath.Join(testDir, "msp", "signcerts", testName+"-cert.pem")
+	tlsCertFile := filepath.Join(testDir, "tls", "client.crt")
+
+	signCA, err := ca.NewCA(filepath.Join(testDir, "ca"), testCAOrg, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+	tlsCA, err := ca.NewCA(filepath.Join(testDir, "tlsca"), testCAOrg, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+
+	err = msp.GenerateLocalMSP(testDir, testName, nil, signCA, tlsCA, msp.CLIENT, false)
+	assert.NoError(t, err, "Failed to generate local MSP")
+	signCert, err := ioutil.ReadFile(signCertFile)
+	assert.NoError(t, err)
+	tlsCert, err := ioutil.ReadFile(tlsCertFile)
+	assert.NoError(t, err)
+
+	expiry, err := msp.SigningCertExpiry(testDir, testName)
+	assert.NoError(t, err)
+	assert.True(t, expiry.After(time.Now().Add(24*time.Hour)))
+
+	// the TLS certificates are reissued alone
+	deadline := expiry.Add(time.Hour)
+	renewed, err := msp.RenewTLSCerts(testDir, testName, tlsCA, deadline)
+	assert.NoError(t, err)
+	assert.True(t, renewed)
+	renewedTLSCert, err := ioutil.ReadFile(tlsCertFile)
+	assert.NoError(t, err)
+	assert.NotEqual(t, tlsCert, renewedTLSCert)
+	renewedSignCert, err := ioutil.ReadFile(signCertFile)
+	assert.NoError(t, err)
+	assert.Equal(t, signCert, renewedSignCert)
+
+	// then the signing certificate
+	renewed, err = msp.RenewSigningCert(testDir, testName, signCA, deadline)
+	assert.NoError(t, err)
+	assert.True(t, renewed)
+	renewedSignCert, err = ioutil.ReadFile(signCertFile)
+	assert.NoError(t, err)
+	assert.NotEqual(t, signCert, renewedSignCert)
+
+	renewed, err = msp.RenewSigningCert(testDir, testName, signCA, time.Now())
+	assert.NoError(t, err)
+	assert.False(t, renewed)
+
+	_, err = msp.SigningCertExpiry(filepath.Join(testDir, "missing"), testName)
+	assert.Error(t, err, "Should have failed without a local MSP")
+}
+
 func TestExportConfig(t *testing.T) {
 	path := filepath.Join(testDir, "export-test")
 	configFile := filepath.Join(path, "config.yaml")
@@ -0,0 +1,175 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/pkg/errors"
+)
+
+// An archive is a gzipped tar file holding, in this order, every block of the
+// channel followed by the private data of its transactions, and a manifest
+// describing its content:
+//
+//	blocks/<block number>.block                  a marshaled common.Block
+//	pvtdata/<block number>/<tx number>.pvtdata   a marshaled rwset.TxPvtReadWriteSet
+//	manifest.json                                the Manifest
+const (
+	blocksDir    = "blocks/"
+	pvtDataDir   = "pvtdata/"
+	manifestName = "manifest.json"
+)
+
+// Manifest describes the content of an archive
+type Manifest struct {
+	ChannelID string `json:"channel_id"`
+	MSPID     string `json:"msp_id"`
+	// Height is the number of blocks of the archive
+	Height uint64 `json:"height"`
+	// Collections lists the collections of the private data of the channel,
+	// and whether the archive holds their private data
+	Collections []*Collection `json:"collections"`
+}
+
+// Collection tells whether the private data of a collection is included in the
+// archive, and the reason why it is not otherwise
+type Collection struct {
+	Namespace  string `json:"namespace"`
+	Collection string `json:"collection"`
+	Included   bool   `json:"included"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// archiveWriter writes the entries of an archive
+type archiveWriter struct {
+	gz *gzip.Writer
+	tw *tar.Writer
+}
+
+func newArchiveWriter(w io.Writer) *archiveWriter {
+	gz := gzip.NewWriter(w)
+	return &archiveWriter{gz: gz, tw: tar.NewWriter(gz)}
+}
+
+func (a *archiveWriter) writeBlock(block *cb.Block) error {
+	return a.writeProto(fmt.Sprintf("%s%d.block", blocksDir, block.Header.Number), block)
+}
+
+func (a *archiveWriter) writePvtData(blockNum, txNum uint64, pvtData *rwset.TxPvtReadWriteSet) error {
+	return a.writeProto(fmt.Sprintf("%s%d/%d.pvtdata", pvtDataDir, blockNum, txNum), pvtData)
+}
+
+// close writes the manifest and closes the archive
+func (a *archiveWriter) close(manifest *Manifest) error {
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := a.write(manifestName, manifestBytes); err != nil {
+		return err
+	}
+	if err := a.tw.Close(); err != nil {
+		return err
+	}
+	return a.gz.Close()
+}
+
+func (a *archiveWriter) writeProto(name string, msg proto.Message) error {
+	msgBytes, err := proto.Marshal(msg)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %s", name)
+	}
+	return a.write(name, msgBytes)
+}
+
+func (a *archiveWriter) write(name string, content []byte) error {
+	hdr := &tar.Header{Name: name, Mode: 0600, Size: int64(len(content)), Typeflag: tar.TypeReg}
+	if err := a.tw.WriteHeader(hdr); err != nil {
+		return errors.Wrapf(err, "failed to write %s", name)
+	}
+	_, err := a.tw.Write(content)
+	return errors.Wrapf(err, "failed to write %s", name)
+}
+
+// entry is an entry read from an archive, holding either a block, the private
+// data of a transaction of a block, or the manifest
+type entry struct {
+	block    *cb.Block
+	pvtData  *rwset.TxPvtReadWriteSet
+	blockNum uint64
+	txNum    uint64
+	manifest *Manifest
+}
+
+// archiveReader reads the entries of an archive
+type archiveReader struct {
+	tr *tar.Reader
+}
+
+func newArchiveReader(r io.Reader) (*archiveReader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read archive")
+	}
+	return &archiveReader{tr: tar.NewReader(gz)}, nil
+}
+
+// next returns the next entry of the archive, or io.EOF after the last one
+func (a *archiveReader) next() (*entry, error) {
+	hdr, err := a.tr.Next()
+	if err == io.EOF {
+		return nil, err
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read archive")
+	}
+	content, err := ioutil.ReadAll(a.tr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", hdr.Name)
+	}
+
+	switch {
+	case hdr.Name == manifestName:
+		manifest := &Manifest{}
+		if err := json.Unmarshal(content, manifest); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal %s", hdr.Name)
+		}
+		return &entry{manifest: manifest}, nil
+	case strings.HasPrefix(hdr.Name, blocksDir):
+		block := &cb.Block{}
+		if err := proto.Unmarshal(content, block); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal %s", hdr.Name)
+		}
+		return &entry{block: block}, nil
+	case strings.HasPrefix(hdr.Name, pvtDataDir):
+		var blockNum, txNum uint64
+		if _, err := fmt.Sscanf(strings.TrimPrefix(hdr.Name, pvtDataDir), "%d/%d.pvtdata", &blockNum, &txNum); err != nil {
+			return nil, errors.Errorf("invalid private data entry %s", hdr.Name)
+		}
+		pvtData := &rwset.TxPvtReadWriteSet{}
+		if err := proto.Unmarshal(content, pvtData); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal %s", hdr.Name)
+		}
+		return &entry{pvtData: pvtData, blockNum: blockNum, txNum: txNum}, nil
+	default:
+		return nil, errors.Errorf("unexpected entry %s", hdr.Name)
+	}
+}
+
+func collectionKey(ns, coll string) string {
+	return ns + "/" + coll
+}
@@ -0,0 +1,216 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package backup
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/msp"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("fabric-backup")
+
+// Source is the ledger of the channel exported to an archive
+type Source interface {
+	GetBlockchainInfo() (*cb.BlockchainInfo, error)
+	GetPvtDataAndBlockByNum(blockNum uint64, filter ledger.PvtNsCollFilter) (*ledger.BlockAndPvtData, error)
+	NewQueryExecutor() (ledger.QueryExecutor, error)
+}
+
+// Export writes to the archive the blocks of the channel, and the private data
+// of the collections whose access policy lists the organization as a member.
+// The organization must be an application organization of the channel. The
+// membership of the collections is that of their current definitions, as for
+// the reconciliation of the private data by the peers of the organization.
+func Export(src Source, mspID string, w io.Writer) (*Manifest, error) {
+	info, err := src.GetBlockchainInfo()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get blockchain info")
+	}
+	if info.Height == 0 {
+		return nil, errors.New("the ledger is empty")
+	}
+
+	bundle, err := channelConfig(src, info.Height-1)
+	if err != nil {
+		return nil, err
+	}
+	channelID := bundle.ConfigtxValidator().ChainID()
+	if err := checkChannelMember(bundle, mspID); err != nil {
+		return nil, err
+	}
+
+	e := &exporter{
+		channelID:   channelID,
+		mspID:       mspID,
+		collections: privdata.NewSimpleCollectionStore(&collectionSupport{src: src, deserializer: bundle.MSPManager()}),
+		included:    map[string]*Collection{},
+	}
+	manifest := &Manifest{ChannelID: channelID, MSPID: mspID, Height: info.Height}
+	aw := newArchiveWriter(w)
+	for blockNum := uint64(0); blockNum < info.Height; blockNum++ {
+		blockAndPvtData, err := src.GetPvtDataAndBlockByNum(blockNum, nil)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("failed to get block %d", blockNum))
+		}
+		if err := aw.writeBlock(blockAndPvtData.Block); err != nil {
+			return nil, err
+		}
+
+		var txNums []uint64
+		for txNum := range blockAndPvtData.BlockPvtData {
+			txNums = append(txNums, txNum)
+		}
+		sort.Slice(txNums, func(i, j int) bool { return txNums[i] < txNums[j] })
+		for _, txNum := range txNums {
+			pvtData := e.filter(blockAndPvtData.BlockPvtData[txNum].WriteSet)
+			if pvtData == nil {
+				continue
+			}
+			if err := aw.writePvtData(blockNum, txNum, pvtData); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, coll := range e.included {
+		manifest.Collections = append(manifest.Collections, coll)
+	}
+	sort.Slice(manifest.Collections, func(i, j int) bool {
+		return collectionKey(manifest.Collections[i].Namespace, manifest.Collections[i].Collection) <
+			collectionKey(manifest.Collections[j].Namespace, manifest.Collections[j].Collection)
+	})
+	if err := aw.close(manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// channelConfig returns the config of the channel as of the block
+func channelConfig(src Source, blockNum uint64) (*channelconfig.Bundle, error) {
+	noPvtData := ledger.NewPvtNsCollFilter()
+	blockAndPvtData, err := src.GetPvtDataAndBlockByNum(blockNum, noPvtData)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("failed to get block %d", blockNum))
+	}
+	configIndex, err := utils.GetLastConfigIndexFromBlock(blockAndPvtData.Block)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("failed to get the last config index of block %d", blockNum))
+	}
+	blockAndPvtData, err = src.GetPvtDataAndBlockByNum(configIndex, noPvtData)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("failed to get config block %d", configIndex))
+	}
+	env, err := utils.ExtractEnvelope(blockAndPvtData.Block, 0)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("failed to get the config of block %d", configIndex))
+	}
+	bundle, err := channelconfig.NewBundleFromEnvelope(env)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("failed to get the config of block %d", configIndex))
+	}
+	return bundle, nil
+}
+
+func checkChannelMember(bundle *channelconfig.Bundle, mspID string) error {
+	channelID := bundle.ConfigtxValidator().ChainID()
+	ac, ok := bundle.ApplicationConfig()
+	if !ok {
+		return errors.Errorf("channel %s has no application organizations", channelID)
+	}
+	for _, org := range ac.Organizations() {
+		if org.MSPID() == mspID {
+			return nil
+		}
+	}
+	return errors.Errorf("organization %s is not a member of channel %s", mspID, channelID)
+}
+
+// exporter keeps track of the collections whose private data is exported
+type exporter struct {
+	channelID   string
+	mspID       string
+	collections privdata.CollectionStore
+	included    map[string]*Collection
+}
+
+// filter returns the private data of the collections the organization is a
+// member of, or nil if there is none
+func (e *exporter) filter(pvtData *rwset.TxPvtReadWriteSet) *rwset.TxPvtReadWriteSet {
+	if pvtData == nil {
+		return nil
+	}
+	filtered := &rwset.TxPvtReadWriteSet{DataModel: pvtData.DataModel}
+	for _, nsPvtRWSet := range pvtData.NsPvtRwset {
+		var collPvtRWSets []*rwset.CollectionPvtReadWriteSet
+		for _, collPvtRWSet := range nsPvtRWSet.CollectionPvtRwset {
+			if e.collection(nsPvtRWSet.Namespace, collPvtRWSet.CollectionName).Included {
+				collPvtRWSets = append(collPvtRWSets, collPvtRWSet)
+			}
+		}
+		if len(collPvtRWSets) != 0 {
+			filtered.NsPvtRwset = append(filtered.NsPvtRwset, &rwset.NsPvtReadWriteSet{Namespace: nsPvtRWSet.Namespace, CollectionPvtRwset: collPvtRWSets})
+		}
+	}
+	if len(filtered.NsPvtRwset) == 0 {
+		return nil
+	}
+	return filtered
+}
+
+// collection returns whether the private data of the collection is exported,
+// the collections which cannot be retrieved being excluded
+func (e *exporter) collection(ns, coll string) *Collection {
+	key := collectionKey(ns, coll)
+	if c, ok := e.included[key]; ok {
+		return c
+	}
+
+	c := &Collection{Namespace: ns, Collection: coll}
+	policy, err := e.collections.RetrieveCollectionAccessPolicy(cb.CollectionCriteria{Channel: e.channelID, Namespace: ns, Collection: coll})
+	if err != nil {
+		c.Reason = err.Error()
+	} else {
+		c.Reason = fmt.Sprintf("organization %s is not a member of the collection", e.mspID)
+		for _, org := range policy.MemberOrgs() {
+			if org == e.mspID {
+				c.Included, c.Reason = true, ""
+				break
+			}
+		}
+	}
+	if !c.Included {
+		logger.Infof("Excluding the private data of collection %s: %s", key, c.Reason)
+	}
+	e.included[key] = c
+	return c
+}
+
+// collectionSupport reads the definitions of the collections from the state
+// of the exported ledger
+type collectionSupport struct {
+	src          Source
+	deserializer msp.IdentityDeserializer
+}
+
+func (cs *collectionSupport) GetQueryExecutorForLedger(cid string) (ledger.QueryExecutor, error) {
+	return cs.src.NewQueryExecutor()
+}
+
+func (cs *collectionSupport) GetIdentityDeserializer(chainID string) msp.IdentityDeserializer {
+	return cs.deserializer
+}
@@ -0,0 +1,200 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package backup
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func collectionConfig(name string, mspID string) *cb.CollectionConfig {
+	return &cb.CollectionConfig{
+		Payload: &cb.CollectionConfig_StaticCollectionConfig{
+			StaticCollectionConfig: &cb.StaticCollectionConfig{
+				Name: name,
+				MemberOrgsPolicy: &cb.CollectionPolicyConfig{
+					Payload: &cb.CollectionPolicyConfig_SignaturePolicy{
+						SignaturePolicy: cauthdsl.SignedByAnyMember([]string{mspID}),
+					},
+				},
+			},
+		},
+	}
+}
+
+func commitBlock(t *testing.T, lgr ledger.PeerLedger, simRes []*ledger.TxSimulationResults) {
+	var pubSimResBytes [][]byte
+	blockPvtData := map[uint64]*ledger.TxPvtData{}
+	for i, res := range simRes {
+		bytes, err := res.GetPubSimulationBytes()
+		require.NoError(t, err)
+		pubSimResBytes = append(pubSimResBytes, bytes)
+		if res.PvtSimulationResults != nil {
+			blockPvtData[uint64(i)] = &ledger.TxPvtData{SeqInBlock: uint64(i), WriteSet: res.PvtSimulationResults}
+		}
+	}
+	info, err := lgr.GetBlockchainInfo()
+	require.NoError(t, err)
+	block := testutil.ConstructBlock(t, info.Height, info.CurrentBlockHash, pubSimResBytes, false)
+	require.NoError(t, lgr.CommitWithPvtData(&ledger.BlockAndPvtData{Block: block, BlockPvtData: blockPvtData}))
+}
+
+// newTestLedger returns a ledger with a block defining the collections of
+// the chaincode, followed by a block whose transactions write to them
+func newTestLedger(t *testing.T) (ledger.PeerLedger, func()) {
+	dir, err := ioutil.TempDir("", "fabric-backup")
+	require.NoError(t, err)
+	viper.Set("peer.fileSystemPath", dir)
+	ledgermgmt.InitializeTestEnv()
+	cleanup := func() {
+		ledgermgmt.CleanupTestEnv()
+		os.RemoveAll(dir)
+	}
+
+	gb, err := configtxtest.MakeGenesisBlock("testchannel")
+	require.NoError(t, err)
+	lgr, err := ledgermgmt.CreateLedger(gb)
+	require.NoError(t, err)
+
+	collections := &cb.CollectionConfigPackage{Config: []*cb.CollectionConfig{
+		collectionConfig("mine", "SampleOrg"),
+		collectionConfig("theirs", "OtherOrg"),
+	}}
+	simulator, err := lgr.NewTxSimulator(util.GenerateUUID())
+	require.NoError(t, err)
+	require.NoError(t, simulator.SetState("lscc", privdata.BuildCollectionKVSKey("mycc"), utils.MarshalOrPanic(collections)))
+	simulator.Done()
+	simRes, err := simulator.GetTxSimulationResults()
+	require.NoError(t, err)
+	commitBlock(t, lgr, []*ledger.TxSimulationResults{simRes})
+
+	var pvtSimRes []*ledger.TxSimulationResults
+	for _, writes := range [][]string{{"mine", "theirs"}, {"theirs"}} {
+		builder := rwsetutil.NewRWSetBuilder()
+		for _, coll := range writes {
+			builder.AddToPvtAndHashedWriteSet("mycc", coll, "key", []byte("value of "+coll))
+		}
+		simRes, err := builder.GetTxSimulationResults()
+		require.NoError(t, err)
+		pvtSimRes = append(pvtSimRes, simRes)
+	}
+	commitBlock(t, lgr, pvtSimRes)
+	return lgr, cleanup
+}
+
+// fakeTarget records the blocks committed by Import
+type fakeTarget struct {
+	genesisBlock *cb.Block
+	committed    []*ledger.BlockAndPvtData
+}
+
+func (ft *fakeTarget) CommitWithPvtData(blockAndPvtdata *ledger.BlockAndPvtData) error {
+	ft.committed = append(ft.committed, blockAndPvtdata)
+	return nil
+}
+
+func (ft *fakeTarget) create(genesisBlock *cb.Block) (Target, error) {
+	ft.genesisBlock = genesisBlock
+	return ft, nil
+}
+
+func TestExportImport(t *testing.T) {
+	lgr, cleanup := newTestLedger(t)
+	defer cleanup()
+
+	archive := &bytes.Buffer{}
+	manifest, err := Export(lgr, "SampleOrg", archive)
+	require.NoError(t, err)
+	assert.Equal(t, "testchannel", manifest.ChannelID)
+	assert.Equal(t, "SampleOrg", manifest.MSPID)
+	assert.Equal(t, uint64(3), manifest.Height)
+	assert.Equal(t, []*Collection{
+		{Namespace: "mycc", Collection: "mine", Included: true},
+		{Namespace: "mycc", Collection: "theirs", Reason: "organization SampleOrg is not a member of the collection"},
+	}, manifest.Collections)
+
+	target := &fakeTarget{}
+	imported, err := Import(bytes.NewReader(archive.Bytes()), target.create)
+	require.NoError(t, err)
+	assert.Equal(t, manifest, imported)
+	require.NotNil(t, target.genesisBlock)
+	assert.Equal(t, uint64(0), target.genesisBlock.Header.Number)
+	require.Len(t, target.committed, 2)
+	assert.Empty(t, target.committed[0].BlockPvtData)
+
+	// only the private data of the collection of the organization is exported
+	pvtData := target.committed[1].BlockPvtData
+	require.Len(t, pvtData, 1)
+	require.NotNil(t, pvtData[0])
+	assert.True(t, pvtData[0].Has("mycc", "mine"))
+	assert.False(t, pvtData[0].Has("mycc", "theirs"))
+	original, err := lgr.GetPvtDataByNum(2, nil)
+	require.NoError(t, err)
+	assert.Equal(t, original[0].WriteSet.NsPvtRwset[0].CollectionPvtRwset[0], pvtData[0].WriteSet.NsPvtRwset[0].CollectionPvtRwset[0])
+}
+
+func TestExportNotChannelMember(t *testing.T) {
+	lgr, cleanup := newTestLedger(t)
+	defer cleanup()
+
+	_, err := Export(lgr, "OtherOrg", &bytes.Buffer{})
+	assert.EqualError(t, err, "organization OtherOrg is not a member of channel testchannel")
+}
+
+func TestImportInvalidArchive(t *testing.T) {
+	lgr, cleanup := newTestLedger(t)
+	defer cleanup()
+
+	_, err := Import(bytes.NewReader([]byte("not an archive")), (&fakeTarget{}).create)
+	assert.Contains(t, err.Error(), "failed to read archive")
+
+	// the manifest is written last, a truncated archive lacks it
+	truncated := &bytes.Buffer{}
+	aw := newArchiveWriter(truncated)
+	genesisBlock, err := lgr.GetBlockByNumber(0)
+	require.NoError(t, err)
+	require.NoError(t, aw.writeBlock(genesisBlock))
+	require.NoError(t, aw.tw.Close())
+	require.NoError(t, aw.gz.Close())
+	_, err = Import(truncated, (&fakeTarget{}).create)
+	assert.EqualError(t, err, "the archive has no manifest")
+
+	// the blocks must follow each other
+	gapped := &bytes.Buffer{}
+	aw = newArchiveWriter(gapped)
+	block, err := lgr.GetBlockByNumber(2)
+	require.NoError(t, err)
+	require.NoError(t, aw.writeBlock(genesisBlock))
+	require.NoError(t, aw.writeBlock(block))
+	require.NoError(t, aw.close(&Manifest{Height: 2}))
+	_, err = Import(gapped, (&fakeTarget{}).create)
+	assert.EqualError(t, err, "expected block 1 in the archive")
+
+	// the manifest must account for every block
+	mismatched := &bytes.Buffer{}
+	aw = newArchiveWriter(mismatched)
+	require.NoError(t, aw.writeBlock(genesisBlock))
+	require.NoError(t, aw.close(&Manifest{Height: 3}))
+	_, err = Import(mismatched, (&fakeTarget{}).create)
+	assert.EqualError(t, err, "the archive holds 1 blocks, its manifest 3")
+}
@@ -0,0 +1,89 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package backup
+
+import (
+	"io"
+
+	"github.com/hyperledger/fabric/core/ledger"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// Target is the ledger of the channel into which the blocks of an archive are
+// imported
+type Target interface {
+	CommitWithPvtData(blockAndPvtdata *ledger.BlockAndPvtData) error
+}
+
+// Import creates the ledger of the channel from the genesis block of the
+// archive, then commits the other blocks of the archive along with their
+// private data, and returns the manifest of the archive
+func Import(r io.Reader, create func(genesisBlock *cb.Block) (Target, error)) (*Manifest, error) {
+	ar, err := newArchiveReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var target Target
+	var pending *ledger.BlockAndPvtData
+	height := uint64(0)
+	commit := func() error {
+		if pending == nil {
+			return nil
+		}
+		var err error
+		if height == 0 {
+			target, err = create(pending.Block)
+		} else {
+			err = target.CommitWithPvtData(pending)
+		}
+		if err != nil {
+			return errors.WithMessage(err, "failed to commit block")
+		}
+		height++
+		pending = nil
+		return nil
+	}
+
+	for {
+		e, err := ar.next()
+		if err == io.EOF {
+			return nil, errors.New("the archive has no manifest")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case e.block != nil:
+			if err := commit(); err != nil {
+				return nil, err
+			}
+			if e.block.Header == nil || e.block.Header.Number != height {
+				return nil, errors.Errorf("expected block %d in the archive", height)
+			}
+			pending = &ledger.BlockAndPvtData{Block: e.block, BlockPvtData: map[uint64]*ledger.TxPvtData{}}
+		case e.pvtData != nil:
+			if pending == nil || pending.Block.Header.Number != e.blockNum || e.blockNum == 0 {
+				return nil, errors.Errorf("unexpected private data of block %d in the archive", e.blockNum)
+			}
+			pending.BlockPvtData[e.txNum] = &ledger.TxPvtData{SeqInBlock: e.txNum, WriteSet: e.pvtData}
+		case e.manifest != nil:
+			if err := commit(); err != nil {
+				return nil, err
+			}
+			if e.manifest.Height != height {
+				return nil, errors.Errorf("the archive holds %d blocks, its manifest %d", height, e.manifest.Height)
+			}
+			if _, err := ar.next(); err != io.EOF {
+				return nil, errors.New("unexpected entry after the manifest of the archive")
+			}
+			return e.manifest, nil
+		}
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hyperledger/fabric/common/tools/fabric-backup/backup"
+	"github.com/hyperledger/fabric/core/chaincode/platforms"
+	"github.com/hyperledger/fabric/core/chaincode/platforms/car"
+	"github.com/hyperledger/fabric/core/chaincode/platforms/golang"
+	"github.com/hyperledger/fabric/core/chaincode/platforms/java"
+	"github.com/hyperledger/fabric/core/chaincode/platforms/node"
+	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/core/scc/lscc"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/spf13/viper"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+// command line flags
+var (
+	app = kingpin.New("fabric-backup", "Utility for exporting the ledger of a channel along with the private data an organization is entitled to, and for importing it into the peers of the organization")
+
+	fileSystemPath = app.Flag("fileSystemPath", "The file system path of the peer holding the ledgers, peer.fileSystemPath of core.yaml if not set").String()
+
+	exportCmd       = app.Command("export", "Export the ledger of a channel of a stopped peer to an archive")
+	exportChannelID = exportCmd.Flag("channelID", "The channel whose ledger is exported").Short('C').Required().String()
+	exportMSPID     = exportCmd.Flag("mspID", "The MSP ID of the organization the archive is handed to").Required().String()
+	exportOutput    = exportCmd.Flag("output", "The archive file to write").Short('o').Required().String()
+
+	importCmd   = app.Command("import", "Create the ledger of a channel of a stopped peer from an archive")
+	importInput = importCmd.Flag("input", "The archive file to read").Short('i').Required().String()
+)
+
+func main() {
+	command := kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	// the ledger settings are read from core.yaml and the CORE_ environment
+	// variables as for the peer
+	viper.SetEnvPrefix(common.CmdRoot)
+	viper.AutomaticEnv()
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	if err := common.InitConfig(common.CmdRoot); err != nil {
+		app.Fatalf("Error loading core.yaml: %s", err)
+	}
+	if *fileSystemPath != "" {
+		viper.Set("peer.fileSystemPath", *fileSystemPath)
+	}
+
+	// the ledgers are processed as by the peer, which must not be running
+	ledgermgmt.Initialize(&ledgermgmt.Initializer{
+		CustomTxProcessors:            peer.ConfigTxProcessors,
+		PlatformRegistry:              platforms.NewRegistry(&golang.Platform{}, &node.Platform{}, &java.Platform{}, &car.Platform{}),
+		DeployedChaincodeInfoProvider: &lscc.DeployedCCInfoProvider{},
+	})
+	defer ledgermgmt.Close()
+
+	var err error
+	switch command {
+	case exportCmd.FullCommand():
+		err = exportLedger()
+	case importCmd.FullCommand():
+		err = importLedger()
+	}
+	if err != nil {
+		ledgermgmt.Close()
+		app.Fatalf("%s", err)
+	}
+}
+
+func exportLedger() error {
+	lgr, err := ledgermgmt.OpenLedger(*exportChannelID)
+	if err != nil {
+		return fmt.Errorf("error opening the ledger of channel %s: %s", *exportChannelID, err)
+	}
+	defer lgr.Close()
+
+	f, err := os.OpenFile(*exportOutput, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("error creating the archive: %s", err)
+	}
+	defer f.Close()
+	manifest, err := backup.Export(lgr, *exportMSPID, f)
+	if err != nil {
+		os.Remove(*exportOutput)
+		return fmt.Errorf("error exporting the ledger of channel %s: %s", *exportChannelID, err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("error writing the archive: %s", err)
+	}
+
+	fmt.Printf("Exported %d blocks of channel %s for organization %s\n", manifest.Height, manifest.ChannelID, manifest.MSPID)
+	printCollections(manifest)
+	return nil
+}
+
+func importLedger() error {
+	f, err := os.Open(*importInput)
+	if err != nil {
+		return fmt.Errorf("error opening the archive: %s", err)
+	}
+	defer f.Close()
+
+	manifest, err := backup.Import(f, func(genesisBlock *cb.Block) (backup.Target, error) {
+		lgr, err := ledgermgmt.CreateLedger(genesisBlock)
+		if err != nil {
+			return nil, err
+		}
+		return lgr, nil
+	})
+	if err != nil {
+		return fmt.Errorf("error importing the archive: %s", err)
+	}
+
+	fmt.Printf("Imported %d blocks of channel %s for organization %s\n", manifest.Height, manifest.ChannelID, manifest.MSPID)
+	printCollections(manifest)
+	return nil
+}
+
+func printCollections(manifest *backup.Manifest) {
+	for _, coll := range manifest.Collections {
+		if coll.Included {
+			fmt.Printf("  included collection %s/%s\n", coll.Namespace, coll.Collection)
+		} else {
+			fmt.Printf("  excluded collection %s/%s: %s\n", coll.Namespace, coll.Collection, coll.Reason)
+		}
+	}
+}
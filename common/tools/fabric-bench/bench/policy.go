@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bench
+
+import (
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric/protos/msp"
+)
+
+// satisfiedBy returns whether endorsements by peers of the given MSPs satisfy
+// the policy, each endorsement satisfying a single principal. Only the MSP of
+// the principals is checked, the peers being assumed to have their roles.
+func satisfiedBy(policy *cb.SignaturePolicyEnvelope, mspIDs []string) bool {
+	used := make([]bool, len(mspIDs))
+	return satisfies(policy.Rule, policy.Identities, mspIDs, used)
+}
+
+func satisfies(rule *cb.SignaturePolicy, principals []*mb.MSPPrincipal, mspIDs []string, used []bool) bool {
+	switch t := rule.Type.(type) {
+	case *cb.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || int(t.SignedBy) >= len(principals) {
+			return false
+		}
+		mspID := principalMSPID(principals[t.SignedBy])
+		for i, id := range mspIDs {
+			if !used[i] && mspID != "" && id == mspID {
+				used[i] = true
+				return true
+			}
+		}
+		return false
+	case *cb.SignaturePolicy_NOutOf_:
+		verified := int32(0)
+		_used := make([]bool, len(used))
+		for _, subRule := range t.NOutOf.Rules {
+			copy(_used, used)
+			if satisfies(subRule, principals, mspIDs, _used) {
+				verified++
+				copy(used, _used)
+			}
+		}
+		return verified >= t.NOutOf.N
+	}
+	return false
+}
+
+// principalMSPID returns the MSP of the principal, or the empty string if the
+// principal does not designate the members of an MSP
+func principalMSPID(principal *mb.MSPPrincipal) string {
+	switch principal.PrincipalClassification {
+	case mb.MSPPrincipal_ROLE:
+		role := &mb.MSPRole{}
+		if proto.Unmarshal(principal.Principal, role) == nil {
+			return role.MspIdentifier
+		}
+	case mb.MSPPrincipal_ORGANIZATION_UNIT:
+		ou := &mb.OrganizationUnit{}
+		if proto.Unmarshal(principal.Principal, ou) == nil {
+			return ou.MspIdentifier
+		}
+	}
+	return ""
+}
@@ -0,0 +1,154 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bench
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// The phases of a transaction whose latencies are reported
+const (
+	Endorsement = "endorsement"
+	Ordering    = "ordering"
+	Total       = "total"
+)
+
+// Percentiles are the latency percentiles reported for every phase
+var Percentiles = []float64{50, 90, 99}
+
+// Latencies are the latencies measured for a phase of the transactions
+type Latencies struct {
+	Phase  string
+	sorted bool
+	values []time.Duration
+}
+
+func (l *Latencies) add(latency time.Duration) {
+	l.values = append(l.values, latency)
+	l.sorted = false
+}
+
+// Count returns the number of transactions which completed the phase
+func (l *Latencies) Count() int {
+	return len(l.values)
+}
+
+// Mean returns the mean latency of the phase
+func (l *Latencies) Mean() time.Duration {
+	if len(l.values) == 0 {
+		return 0
+	}
+	var sum time.Duration
+	for _, v := range l.values {
+		sum += v
+	}
+	return sum / time.Duration(len(l.values))
+}
+
+// Percentile returns the latency under which the given percentage of the
+// transactions completed the phase, using the nearest rank method
+func (l *Latencies) Percentile(p float64) time.Duration {
+	if len(l.values) == 0 {
+		return 0
+	}
+	if !l.sorted {
+		sort.Slice(l.values, func(i, j int) bool { return l.values[i] < l.values[j] })
+		l.sorted = true
+	}
+	rank := int(math.Ceil(p/100*float64(len(l.values)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(l.values) {
+		rank = len(l.values) - 1
+	}
+	return l.values[rank]
+}
+
+// Report is the outcome of a run of a workload
+type Report struct {
+	// Duration is the time the run took
+	Duration time.Duration
+	// Succeeded is the number of transactions accepted by the ordering service
+	Succeeded int
+	// Failed is the number of transactions which failed a phase
+	Failed int
+	// Errors counts the failures by error message
+	Errors map[string]int
+	// Phases are the latencies of the phases, in the order the transactions go through them
+	Phases []*Latencies
+}
+
+func newReport() *Report {
+	return &Report{
+		Errors: map[string]int{},
+		Phases: []*Latencies{{Phase: Endorsement}, {Phase: Ordering}, {Phase: Total}},
+	}
+}
+
+// Phase returns the latencies of the given phase, or nil if there is no such phase
+func (r *Report) Phase(phase string) *Latencies {
+	for _, l := range r.Phases {
+		if l.Phase == phase {
+			return l
+		}
+	}
+	return nil
+}
+
+// Throughput returns the number of transactions per second accepted by the ordering service
+func (r *Report) Throughput() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.Succeeded) / r.Duration.Seconds()
+}
+
+// Print writes the report in a human readable form
+func (r *Report) Print(out io.Writer) error {
+	fmt.Fprintf(out, "Transactions: %d succeeded, %d failed in %s\n", r.Succeeded, r.Failed, r.Duration)
+	fmt.Fprintf(out, "Throughput: %.2f tx/s\n\n", r.Throughput())
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprint(w, "PHASE\tCOUNT\tMEAN")
+	for _, p := range Percentiles {
+		fmt.Fprintf(w, "\tP%g", p)
+	}
+	fmt.Fprintln(w)
+	for _, l := range r.Phases {
+		fmt.Fprintf(w, "%s\t%d\t%s", l.Phase, l.Count(), round(l.Mean()))
+		for _, p := range Percentiles {
+			fmt.Fprintf(w, "\t%s", round(l.Percentile(p)))
+		}
+		fmt.Fprintln(w)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(r.Errors) > 0 {
+		fmt.Fprintln(out, "\nErrors:")
+		var messages []string
+		for message := range r.Errors {
+			messages = append(messages, message)
+		}
+		sort.Strings(messages)
+		for _, message := range messages {
+			fmt.Fprintf(out, "  %d x %s\n", r.Errors[message], message)
+		}
+	}
+	return nil
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(10 * time.Microsecond)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bench
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatencies(t *testing.T) {
+	l := &Latencies{Phase: Total}
+	assert.Equal(t, time.Duration(0), l.Percentile(50))
+	assert.Equal(t, time.Duration(0), l.Mean())
+
+	for i := 100; i > 0; i-- {
+		l.add(time.Duration(i) * time.Millisecond)
+	}
+	assert.Equal(t, 100, l.Count())
+	assert.Equal(t, 50500*time.Microsecond, l.Mean())
+	assert.Equal(t, 50*time.Millisecond, l.Percentile(50))
+	assert.Equal(t, 90*time.Millisecond, l.Percentile(90))
+	assert.Equal(t, 99*time.Millisecond, l.Percentile(99))
+	assert.Equal(t, 100*time.Millisecond, l.Percentile(100))
+	assert.Equal(t, time.Millisecond, l.Percentile(0))
+}
+
+func TestReportPrint(t *testing.T) {
+	r := newReport()
+	r.Duration = 2 * time.Second
+	r.Succeeded = 10
+	r.Failed = 1
+	r.Errors["error sending transaction: SERVICE_UNAVAILABLE"] = 1
+	for i := 0; i < 10; i++ {
+		r.Phase(Endorsement).add(10 * time.Millisecond)
+		r.Phase(Ordering).add(20 * time.Millisecond)
+		r.Phase(Total).add(30 * time.Millisecond)
+	}
+	assert.Nil(t, r.Phase("commit"))
+	assert.Equal(t, 5.0, r.Throughput())
+
+	var out bytes.Buffer
+	assert.NoError(t, r.Print(&out))
+	assert.Equal(t, `Transactions: 10 succeeded, 1 failed in 2s
+Throughput: 5.00 tx/s
+
+PHASE        COUNT  MEAN  P50   P90   P99
+endorsement  10     10ms  10ms  10ms  10ms
+ordering     10     20ms  20ms  20ms  20ms
+total        10     30ms  30ms  30ms  30ms
+
+Errors:
+  1 x error sending transaction: SERVICE_UNAVAILABLE
+`, out.String())
+}
@@ -0,0 +1,303 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bench
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("bench")
+
+// Workload describes the transactions submitted during a run
+type Workload struct {
+	// ChannelID is the channel the transactions are submitted to
+	ChannelID string
+	// Chaincode is the name of the invoked chaincode
+	Chaincode string
+	// Args are the arguments of the invocation, starting with the function name
+	Args [][]byte
+	// PayloadSize is the size of the random argument appended to the
+	// arguments of every invocation, if not zero
+	PayloadSize int
+	// Transactions is the number of transactions submitted
+	Transactions int
+	// Concurrency is the number of transactions in flight at any time
+	Concurrency int
+	// Endorsers is the number of peers endorsing every transaction, picked
+	// round robin among the endorser clients to satisfy policies requiring
+	// fewer than all of them; zero means all of them
+	Endorsers int
+	// Policy is the endorsement policy every transaction is endorsed for, if
+	// set instead of Endorsers: the peers are picked round robin until their
+	// MSPs satisfy the policy
+	Policy *cb.SignaturePolicyEnvelope
+	// Timeout bounds the time to collect the endorsements of a transaction
+	Timeout time.Duration
+}
+
+// Runner drives a workload against a network
+type Runner struct {
+	Workload Workload
+	// Signer signs the proposals and the transactions
+	Signer msp.SigningIdentity
+	// EndorserClients are the peers the proposals are sent to
+	EndorserClients []pb.EndorserClient
+	// PeerMSPIDs are the MSPs of the endorser clients at the same position,
+	// required by the endorsement policy of the workload
+	PeerMSPIDs []string
+	// NewBroadcastClient opens a connection to the ordering service, one per
+	// concurrent submitter as a broadcast stream handles a transaction at a time
+	NewBroadcastClient func() (common.BroadcastClient, error)
+}
+
+// Run submits the transactions of the workload and reports the latencies
+// of their phases, failing only if the network can't be reached at all
+func (r *Runner) Run() (*Report, error) {
+	w := r.Workload
+	if w.Transactions <= 0 {
+		return nil, errors.New("the number of transactions must be positive")
+	}
+	if w.Concurrency <= 0 {
+		return nil, errors.New("the concurrency must be positive")
+	}
+	if len(r.EndorserClients) == 0 {
+		return nil, errors.New("at least one endorser client is required")
+	}
+	if w.Endorsers < 0 || w.Endorsers > len(r.EndorserClients) {
+		return nil, errors.Errorf("cannot pick %d endorsers among %d peers", w.Endorsers, len(r.EndorserClients))
+	}
+	if w.Policy != nil {
+		if w.Endorsers != 0 {
+			return nil, errors.New("the number of endorsers and the endorsement policy are mutually exclusive")
+		}
+		if len(r.PeerMSPIDs) != len(r.EndorserClients) {
+			return nil, errors.Errorf("the endorsement policy requires the MSP of each of the %d peers, got %d", len(r.EndorserClients), len(r.PeerMSPIDs))
+		}
+		if !satisfiedBy(w.Policy, r.PeerMSPIDs) {
+			return nil, errors.New("the endorsement policy cannot be satisfied by the peers")
+		}
+	}
+	creator, err := r.Signer.Serialize()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error serializing signing identity")
+	}
+
+	concurrency := w.Concurrency
+	if concurrency > w.Transactions {
+		concurrency = w.Transactions
+	}
+	broadcastClients := make([]common.BroadcastClient, concurrency)
+	for i := range broadcastClients {
+		bc, err := r.NewBroadcastClient()
+		if err != nil {
+			for _, opened := range broadcastClients[:i] {
+				opened.Close()
+			}
+			return nil, errors.WithMessage(err, "error connecting to the ordering service")
+		}
+		broadcastClients[i] = bc
+	}
+
+	report := newReport()
+	var mutex sync.Mutex
+	record := func(result *result) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if result.err != nil {
+			report.Failed++
+			report.Errors[result.err.Error()]++
+		} else {
+			report.Succeeded++
+		}
+		for phase, latency := range result.latencies {
+			report.Phase(phase).add(latency)
+		}
+	}
+
+	logger.Infof("Submitting %d transactions to chaincode %s on channel %s with concurrency %d", w.Transactions, w.Chaincode, w.ChannelID, concurrency)
+	txs := make(chan int, w.Transactions)
+	for i := 0; i < w.Transactions; i++ {
+		txs <- i
+	}
+	close(txs)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := range broadcastClients {
+		wg.Add(1)
+		go func(worker int, bc common.BroadcastClient) {
+			defer wg.Done()
+			defer bc.Close()
+			payload := rand.New(rand.NewSource(time.Now().UnixNano() + int64(worker)))
+			for tx := range txs {
+				record(r.submit(tx, creator, bc, payload))
+			}
+		}(i, broadcastClients[i])
+	}
+	wg.Wait()
+	report.Duration = time.Since(start)
+
+	return report, nil
+}
+
+type result struct {
+	latencies map[string]time.Duration
+	err       error
+}
+
+// submit endorses and orders the given transaction of the workload
+func (r *Runner) submit(tx int, creator []byte, bc common.BroadcastClient, payload *rand.Rand) *result {
+	res := &result{latencies: map[string]time.Duration{}}
+	start := time.Now()
+
+	prop, signedProp, err := r.proposal(creator, payload)
+	if err != nil {
+		res.err = err
+		return res
+	}
+
+	endorsers := r.endorsers(tx)
+	responses := make([]*pb.ProposalResponse, len(endorsers))
+	errs := make([]error, len(endorsers))
+	ctx := context.Background()
+	if r.Workload.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Workload.Timeout)
+		defer cancel()
+	}
+	var wg sync.WaitGroup
+	for i, endorser := range endorsers {
+		wg.Add(1)
+		go func(i int, endorser pb.EndorserClient) {
+			defer wg.Done()
+			responses[i], errs[i] = endorser.ProcessProposal(ctx, signedProp)
+		}(i, endorser)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			res.err = errors.WithMessage(err, "error endorsing transaction")
+			return res
+		}
+		if responses[i].Response == nil || responses[i].Response.Status >= shim.ERRORTHRESHOLD {
+			res.err = errors.Errorf("endorsement failed: %s", responses[i].Response.GetMessage())
+			return res
+		}
+	}
+	endorsed := time.Now()
+	res.latencies[Endorsement] = endorsed.Sub(start)
+
+	env, err := putils.CreateSignedTx(prop, r.Signer, responses...)
+	if err != nil {
+		res.err = errors.WithMessage(err, "could not assemble transaction")
+		return res
+	}
+	if err := bc.Send(env); err != nil {
+		res.err = errors.WithMessage(err, "error sending transaction")
+		return res
+	}
+	ordered := time.Now()
+	res.latencies[Ordering] = ordered.Sub(endorsed)
+	res.latencies[Total] = ordered.Sub(start)
+
+	return res
+}
+
+func (r *Runner) proposal(creator []byte, payload *rand.Rand) (*pb.Proposal, *pb.SignedProposal, error) {
+	args := r.Workload.Args
+	if r.Workload.PayloadSize > 0 {
+		data := make([]byte, r.Workload.PayloadSize)
+		payload.Read(data)
+		args = append(append([][]byte{}, args...), data)
+	}
+	invocation := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_GOLANG,
+			ChaincodeId: &pb.ChaincodeID{Name: r.Workload.Chaincode},
+			Input:       &pb.ChaincodeInput{Args: args},
+		},
+	}
+
+	prop, _, err := putils.CreateChaincodeProposal(cb.HeaderType_ENDORSER_TRANSACTION, r.Workload.ChannelID, invocation, creator)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "error creating proposal")
+	}
+	signedProp, err := putils.GetSignedProposal(prop, r.Signer)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "error signing proposal")
+	}
+	return prop, signedProp, nil
+}
+
+// endorsers returns the endorser clients the given transaction is sent to
+func (r *Runner) endorsers(tx int) []pb.EndorserClient {
+	if r.Workload.Policy != nil {
+		return r.policyEndorsers(tx)
+	}
+	count := r.Workload.Endorsers
+	if count == 0 {
+		return r.EndorserClients
+	}
+	endorsers := make([]pb.EndorserClient, count)
+	for i := range endorsers {
+		endorsers[i] = r.EndorserClients[(tx+i)%len(r.EndorserClients)]
+	}
+	return endorsers
+}
+
+// policyEndorsers returns the endorser clients whose endorsements satisfy the
+// endorsement policy of the workload, picked round robin from the given
+// transaction. The peers of MSPs the policy does not mention are never picked,
+// and the peers of MSPs already picked are only picked once the peers of the
+// other MSPs don't satisfy the policy.
+func (r *Runner) policyEndorsers(tx int) []pb.EndorserClient {
+	var policyMSPIDs []string
+	for _, principal := range r.Workload.Policy.Identities {
+		policyMSPIDs = append(policyMSPIDs, principalMSPID(principal))
+	}
+
+	var endorsers []pb.EndorserClient
+	var mspIDs []string
+	picked := make([]bool, len(r.EndorserClients))
+	for _, sameMSP := range []bool{false, true} {
+		for i := range r.EndorserClients {
+			peer := (tx + i) % len(r.EndorserClients)
+			mspID := r.PeerMSPIDs[peer]
+			if picked[peer] || !contains(policyMSPIDs, mspID) || (!sameMSP && contains(mspIDs, mspID)) {
+				continue
+			}
+			picked[peer] = true
+			endorsers = append(endorsers, r.EndorserClients[peer])
+			mspIDs = append(mspIDs, mspID)
+			if satisfiedBy(r.Workload.Policy, mspIDs) {
+				return endorsers
+			}
+		}
+	}
+	return endorsers
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,240 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bench
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/msp/mgmt"
+	msptesttools "github.com/hyperledger/fabric/msp/mgmt/testtools"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+func TestMain(m *testing.M) {
+	err := msptesttools.LoadMSPSetupForTesting()
+	if err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+type countingEndorser struct {
+	sync.Mutex
+	proposals []*pb.SignedProposal
+	response  *pb.ProposalResponse
+	err       error
+}
+
+func (ce *countingEndorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedProposal, opts ...grpc.CallOption) (*pb.ProposalResponse, error) {
+	ce.Lock()
+	defer ce.Unlock()
+	ce.proposals = append(ce.proposals, signedProp)
+	return ce.response, ce.err
+}
+
+type recordingBroadcaster struct {
+	sync.Mutex
+	envelopes []*cb.Envelope
+	err       error
+	closed    int
+}
+
+func (rb *recordingBroadcaster) Send(env *cb.Envelope) error {
+	rb.Lock()
+	defer rb.Unlock()
+	if rb.err != nil {
+		return rb.err
+	}
+	rb.envelopes = append(rb.envelopes, env)
+	return nil
+}
+
+func (rb *recordingBroadcaster) Close() error {
+	rb.Lock()
+	defer rb.Unlock()
+	rb.closed++
+	return nil
+}
+
+func newEndorser(status int32) *countingEndorser {
+	return &countingEndorser{
+		response: &pb.ProposalResponse{
+			Response:    &pb.Response{Status: status, Message: "chaincode message"},
+			Endorsement: &pb.Endorsement{},
+		},
+	}
+}
+
+func newRunner(t *testing.T, w Workload, bc *recordingBroadcaster, endorsers ...pb.EndorserClient) *Runner {
+	signer, err := mgmt.GetLocalMSP().GetDefaultSigningIdentity()
+	assert.NoError(t, err)
+	return &Runner{
+		Workload:           w,
+		Signer:             signer,
+		EndorserClients:    endorsers,
+		NewBroadcastClient: func() (common.BroadcastClient, error) { return bc, nil },
+	}
+}
+
+func TestRun(t *testing.T) {
+	e1, e2, e3 := newEndorser(200), newEndorser(200), newEndorser(200)
+	bc := &recordingBroadcaster{}
+	r := newRunner(t, Workload{
+		ChannelID:    "mychannel",
+		Chaincode:    "mycc",
+		Args:         [][]byte{[]byte("put"), []byte("key")},
+		PayloadSize:  64,
+		Transactions: 30,
+		Concurrency:  4,
+		Endorsers:    2,
+	}, bc, e1, e2, e3)
+
+	report, err := r.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, 30, report.Succeeded)
+	assert.Equal(t, 0, report.Failed)
+	assert.Len(t, bc.envelopes, 30)
+	assert.Equal(t, 4, bc.closed)
+	for _, phase := range []string{Endorsement, Ordering, Total} {
+		assert.Equal(t, 30, report.Phase(phase).Count(), phase)
+	}
+
+	// two of the three peers endorse every transaction, round robin
+	assert.Len(t, e1.proposals, 20)
+	assert.Len(t, e2.proposals, 20)
+	assert.Len(t, e3.proposals, 20)
+
+	prop := &pb.Proposal{}
+	assert.NoError(t, proto.Unmarshal(e1.proposals[0].ProposalBytes, prop))
+	cis, err := putils.GetChaincodeInvocationSpec(prop)
+	assert.NoError(t, err)
+	assert.Equal(t, "mycc", cis.ChaincodeSpec.ChaincodeId.Name)
+	assert.Len(t, cis.ChaincodeSpec.Input.Args, 3)
+	assert.Equal(t, []byte("key"), cis.ChaincodeSpec.Input.Args[1])
+	assert.Len(t, cis.ChaincodeSpec.Input.Args[2], 64)
+	hdr, err := putils.GetHeader(prop.Header)
+	assert.NoError(t, err)
+	chdr, err := putils.UnmarshalChannelHeader(hdr.ChannelHeader)
+	assert.NoError(t, err)
+	assert.Equal(t, "mychannel", chdr.ChannelId)
+}
+
+func TestRunPolicy(t *testing.T) {
+	policy, err := cauthdsl.FromString("AND('Org1MSP.peer', OR('Org2MSP.peer', 'Org3MSP.peer'))")
+	assert.NoError(t, err)
+	e1, e2, e3, e4 := newEndorser(200), newEndorser(200), newEndorser(200), newEndorser(200)
+	bc := &recordingBroadcaster{}
+	r := newRunner(t, Workload{
+		ChannelID:    "mychannel",
+		Chaincode:    "mycc",
+		Transactions: 4,
+		Concurrency:  1,
+		Policy:       policy,
+	}, bc, e1, e2, e3, e4)
+	r.PeerMSPIDs = []string{"Org1MSP", "Org1MSP", "Org2MSP", "Org4MSP"}
+
+	// the endorsers are picked round robin until the policy is satisfied,
+	// skipping the peers of MSPs already picked or not in the policy
+	assert.Equal(t, []pb.EndorserClient{e1, e3}, r.endorsers(0))
+	assert.Equal(t, []pb.EndorserClient{e2, e3}, r.endorsers(1))
+	assert.Equal(t, []pb.EndorserClient{e3, e1}, r.endorsers(2))
+	assert.Equal(t, []pb.EndorserClient{e1, e3}, r.endorsers(3))
+
+	report, err := r.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, 4, report.Succeeded)
+	assert.Len(t, e1.proposals, 3)
+	assert.Len(t, e2.proposals, 1)
+	assert.Len(t, e3.proposals, 4)
+	assert.Empty(t, e4.proposals)
+
+	// peers of the same MSP are picked when the policy requires it
+	policy, err = cauthdsl.FromString("OutOf(2, 'Org1MSP.peer', 'Org1MSP.peer')")
+	assert.NoError(t, err)
+	r.Workload.Policy = policy
+	assert.Equal(t, []pb.EndorserClient{e1, e2}, r.endorsers(0))
+}
+
+func TestSatisfiedBy(t *testing.T) {
+	for _, test := range []struct {
+		policy    string
+		mspIDs    []string
+		satisfied bool
+	}{
+		{"OR('Org1MSP.peer', 'Org2MSP.member')", []string{"Org2MSP"}, true},
+		{"OR('Org1MSP.peer', 'Org2MSP.member')", []string{"Org3MSP"}, false},
+		{"AND('Org1MSP.peer', 'Org2MSP.peer')", []string{"Org1MSP"}, false},
+		{"AND('Org1MSP.peer', 'Org2MSP.peer')", []string{"Org2MSP", "Org1MSP"}, true},
+		{"AND('Org1MSP.peer', 'Org1MSP.peer')", []string{"Org1MSP"}, false},
+		{"AND('Org1MSP.peer', 'Org1MSP.peer')", []string{"Org1MSP", "Org1MSP"}, true},
+		{"OutOf(2, Weight(2, 'Org1MSP.peer'), 'Org2MSP.peer')", []string{"Org2MSP"}, false},
+		{"OutOf(2, Weight(2, 'Org1MSP.peer'), 'Org2MSP.peer')", []string{"Org1MSP"}, true},
+	} {
+		policy, err := cauthdsl.FromString(test.policy)
+		assert.NoError(t, err)
+		assert.Equal(t, test.satisfied, satisfiedBy(policy, test.mspIDs), "%s by %v", test.policy, test.mspIDs)
+	}
+}
+
+func TestRunFailures(t *testing.T) {
+	w := Workload{Chaincode: "mycc", Transactions: 5, Concurrency: 10}
+
+	report, err := newRunner(t, w, &recordingBroadcaster{}, newEndorser(500)).Run()
+	assert.NoError(t, err)
+	assert.Equal(t, 5, report.Failed)
+	assert.Equal(t, map[string]int{"endorsement failed: chaincode message": 5}, report.Errors)
+	assert.Equal(t, 0, report.Phase(Endorsement).Count())
+
+	report, err = newRunner(t, w, &recordingBroadcaster{}, newEndorser(200), &countingEndorser{err: errors.New("unavailable")}).Run()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"error endorsing transaction: unavailable": 5}, report.Errors)
+
+	report, err = newRunner(t, w, &recordingBroadcaster{err: errors.New("SERVICE_UNAVAILABLE")}, newEndorser(200)).Run()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"error sending transaction: SERVICE_UNAVAILABLE": 5}, report.Errors)
+	assert.Equal(t, 5, report.Phase(Endorsement).Count())
+	assert.Equal(t, 0, report.Phase(Ordering).Count())
+}
+
+func TestRunBadWorkload(t *testing.T) {
+	bc := &recordingBroadcaster{}
+	for _, test := range []struct {
+		workload   Workload
+		endorsers  []pb.EndorserClient
+		peerMSPIDs []string
+		err        string
+	}{
+		{Workload{Concurrency: 1}, []pb.EndorserClient{newEndorser(200)}, nil, "the number of transactions must be positive"},
+		{Workload{Transactions: 1}, []pb.EndorserClient{newEndorser(200)}, nil, "the concurrency must be positive"},
+		{Workload{Transactions: 1, Concurrency: 1}, nil, nil, "at least one endorser client is required"},
+		{Workload{Transactions: 1, Concurrency: 1, Endorsers: 2}, []pb.EndorserClient{newEndorser(200)}, nil, "cannot pick 2 endorsers among 1 peers"},
+		{Workload{Transactions: 1, Concurrency: 1, Endorsers: 1, Policy: cauthdsl.AcceptAllPolicy}, []pb.EndorserClient{newEndorser(200)}, []string{"Org1MSP"}, "the number of endorsers and the endorsement policy are mutually exclusive"},
+		{Workload{Transactions: 1, Concurrency: 1, Policy: cauthdsl.SignedByMspPeer("Org1MSP")}, []pb.EndorserClient{newEndorser(200)}, nil, "the endorsement policy requires the MSP of each of the 1 peers, got 0"},
+		{Workload{Transactions: 1, Concurrency: 1, Policy: cauthdsl.SignedByMspPeer("Org2MSP")}, []pb.EndorserClient{newEndorser(200)}, []string{"Org1MSP"}, "the endorsement policy cannot be satisfied by the peers"},
+	} {
+		r := newRunner(t, test.workload, bc, test.endorsers...)
+		r.PeerMSPIDs = test.peerMSPIDs
+		_, err := r.Run()
+		assert.EqualError(t, err, test.err)
+	}
+
+	r := newRunner(t, Workload{Transactions: 1, Concurrency: 1}, bc, newEndorser(200))
+	r.NewBroadcastClient = func() (common.BroadcastClient, error) { return nil, errors.New("connection refused") }
+	_, err := r.Run()
+	assert.EqualError(t, err, "error connecting to the ordering service: connection refused")
+}
@@ -0,0 +1,137 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/tools/fabric-bench/bench"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+// command line flags
+var (
+	app = kingpin.New("fabric-bench", "Utility for measuring the throughput and latency of a Hyperledger Fabric network")
+
+	channelID    = app.Flag("channelID", "The channel the transactions are submitted to").Short('C').Required().String()
+	chaincode    = app.Flag("name", "The name of the invoked chaincode").Short('n').Required().String()
+	args         = app.Flag("arg", "An argument of the invocation, starting with the function name; may be repeated").Short('a').Strings()
+	payloadSize  = app.Flag("payloadSize", "The size in bytes of the random argument appended to every invocation").Default("0").Int()
+	transactions = app.Flag("transactions", "The number of transactions to submit").Short('t').Default("1000").Int()
+	concurrency  = app.Flag("concurrency", "The number of transactions in flight at any time").Default("10").Int()
+	endorsers    = app.Flag("endorsers", "The number of peers endorsing every transaction, picked round robin among the peer addresses; all of them if zero").Default("0").Int()
+	policy       = app.Flag("policy", "The endorsement policy every transaction is endorsed for, picking the peers round robin until their MSPs satisfy it, e.g. \"AND('Org1MSP.peer','Org2MSP.peer')\"").String()
+	timeout      = app.Flag("timeout", "The time to wait for the endorsements of a transaction").Default("30s").Duration()
+
+	peerAddresses    = app.Flag("peerAddresses", "The address of a peer to send proposals to; may be repeated").Required().Strings()
+	tlsRootCertFiles = app.Flag("tlsRootCertFiles", "The TLS root cert file of the peer at the same position in the peer addresses, if TLS is enabled").Strings()
+	peerMSPIDs       = app.Flag("peerMSPIDs", "The MSP ID of the peer at the same position in the peer addresses, required by the endorsement policy").Strings()
+
+	ordererAddress             = app.Flag("orderer", "Ordering service endpoint").Short('o').Required().String()
+	ordererTLS                 = app.Flag("tls", "Use TLS when communicating with the orderer endpoint").Bool()
+	clientAuth                 = app.Flag("clientauth", "Use mutual TLS when communicating with the orderer endpoint").Bool()
+	caFile                     = app.Flag("cafile", "Path to file containing PEM-encoded trusted certificate(s) for the ordering endpoint").String()
+	keyFile                    = app.Flag("keyfile", "Path to file containing PEM-encoded private key to use for mutual TLS communication with the orderer endpoint").String()
+	certFile                   = app.Flag("certfile", "Path to file containing PEM-encoded X509 public key to use for mutual TLS communication with the orderer endpoint").String()
+	ordererTLSHostnameOverride = app.Flag("ordererTLSHostnameOverride", "The hostname override to use when validating the TLS connection to the orderer").String()
+	connTimeout                = app.Flag("connTimeout", "Timeout for client to connect").Default("3s").Duration()
+)
+
+func main() {
+	kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	// the peer settings, such as TLS and the MSP of the client, are read
+	// from core.yaml and the CORE_ environment variables as for the peer CLI
+	viper.SetEnvPrefix(common.CmdRoot)
+	viper.AutomaticEnv()
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	common.InitCmd(nil, nil)
+	setOrdererEnv()
+
+	runner, err := newRunner()
+	if err != nil {
+		app.Fatalf("Error initializing workload: %s", err)
+	}
+
+	report, err := runner.Run()
+	if err != nil {
+		app.Fatalf("Error running workload: %s", err)
+	}
+	if err := report.Print(os.Stdout); err != nil {
+		app.Fatalf("Error writing report: %s", err)
+	}
+}
+
+// setOrdererEnv sets the orderer settings read by the broadcast client from the flags
+func setOrdererEnv() {
+	viper.Set("orderer.address", *ordererAddress)
+	viper.Set("orderer.tls.enabled", *ordererTLS)
+	viper.Set("orderer.tls.clientAuthRequired", *clientAuth)
+	viper.Set("orderer.tls.rootcert.file", *caFile)
+	viper.Set("orderer.tls.clientKey.file", *keyFile)
+	viper.Set("orderer.tls.clientCert.file", *certFile)
+	viper.Set("orderer.tls.serverhostoverride", *ordererTLSHostnameOverride)
+	viper.Set("orderer.client.connTimeout", *connTimeout)
+}
+
+func newRunner() (*bench.Runner, error) {
+	signer, err := common.GetDefaultSignerFnc()
+	if err != nil {
+		return nil, err
+	}
+
+	var endorserClients []pb.EndorserClient
+	for i, address := range *peerAddresses {
+		var tlsRootCertFile string
+		if i < len(*tlsRootCertFiles) {
+			tlsRootCertFile = (*tlsRootCertFiles)[i]
+		}
+		endorserClient, err := common.GetEndorserClientFnc(address, tlsRootCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("error getting endorser client for %s: %s", address, err)
+		}
+		endorserClients = append(endorserClients, endorserClient)
+	}
+
+	var endorsementPolicy *cb.SignaturePolicyEnvelope
+	if *policy != "" {
+		endorsementPolicy, err = cauthdsl.FromString(*policy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid endorsement policy: %s", err)
+		}
+	}
+
+	invocationArgs := make([][]byte, len(*args))
+	for i, arg := range *args {
+		invocationArgs[i] = []byte(arg)
+	}
+
+	return &bench.Runner{
+		Workload: bench.Workload{
+			ChannelID:    *channelID,
+			Chaincode:    *chaincode,
+			Args:         invocationArgs,
+			PayloadSize:  *payloadSize,
+			Transactions: *transactions,
+			Concurrency:  *concurrency,
+			Endorsers:    *endorsers,
+			Policy:       endorsementPolicy,
+			Timeout:      *timeout,
+		},
+		Signer:             signer,
+		EndorserClients:    endorserClients,
+		PeerMSPIDs:         *peerMSPIDs,
+		NewBroadcastClient: common.GetBroadcastClientFnc,
+	}, nil
+}
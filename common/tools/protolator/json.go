@@ -404,21 +404,34 @@ func recursivelyCreateTreeFromMessage(msg proto.Message) (tree map[string]interf
 	return tree, nil
 }
 
-// DeepMarshalJSON marshals msg to w as JSON, but instead of marshaling bytes fields which contain nested
-// marshaled messages as base64 (like the standard proto encoding), these nested messages are remarshaled
-// as the JSON representation of those messages.  This is done so that the JSON representation is as non-binary
-// and human readable as possible.
-func DeepMarshalJSON(w io.Writer, msg proto.Message) error {
+// MarshalOptions configures the JSON documents produced by Marshal.  The fields of the
+// documents are always sorted by name, so that a message is always marshaled to the same document.
+type MarshalOptions struct {
+	// Indent is the string each nesting level of the document is indented with,
+	// if empty the document is compact
+	Indent string
+}
+
+// Marshal marshals msg to w as JSON with the options, as DeepMarshalJSON does
+func (mo MarshalOptions) Marshal(w io.Writer, msg proto.Message) error {
 	root, err := recursivelyCreateTreeFromMessage(msg)
 	if err != nil {
 		return err
 	}
 
 	encoder := json.NewEncoder(w)
-	encoder.SetIndent("", "\t")
+	encoder.SetIndent("", mo.Indent)
 	return encoder.Encode(root)
 }
 
+// DeepMarshalJSON marshals msg to w as JSON, but instead of marshaling bytes fields which contain nested
+// marshaled messages as base64 (like the standard proto encoding), these nested messages are remarshaled
+// as the JSON representation of those messages.  This is done so that the JSON representation is as non-binary
+// and human readable as possible.  The JSON is indented with tabs.
+func DeepMarshalJSON(w io.Writer, msg proto.Message) error {
+	return MarshalOptions{Indent: "\t"}.Marshal(w, msg)
+}
+
 func recursivelyPopulateMessageFromTree(tree map[string]interface{}, msg proto.Message) (err error) {
 	defer func() {
 		// Because this function is recursive, it's difficult to determine which level
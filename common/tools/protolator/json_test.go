@@ -231,6 +231,27 @@ func (tpff testProtoFailFactory) NewProtoField(msg proto.Message, fieldName stri
 	return nil, fmt.Errorf("Intentionally failing")
 }
 
+func TestMarshalOptions(t *testing.T) {
+	msg := &testprotos.SimpleMsg{
+		PlainField: "foo",
+		MapField:   map[string]string{"b": "c", "a": "d"},
+	}
+
+	var compact bytes.Buffer
+	assert.NoError(t, MarshalOptions{}.Marshal(&compact, msg))
+	assert.Equal(t, `{"map_field":{"a":"d","b":"c"},"plain_field":"foo","slice_field":[]}`+"\n", compact.String())
+
+	var indented, deep bytes.Buffer
+	assert.NoError(t, MarshalOptions{Indent: "\t"}.Marshal(&indented, msg))
+	assert.NoError(t, DeepMarshalJSON(&deep, msg))
+	assert.Equal(t, deep.String(), indented.String())
+	assert.Contains(t, indented.String(), "\n\t\"plain_field\": \"foo\"")
+
+	decoded := &testprotos.SimpleMsg{}
+	assert.NoError(t, DeepUnmarshalJSON(&compact, decoded))
+	assert.True(t, proto.Equal(msg, decoded))
+}
+
 func TestFailFactory(t *testing.T) {
 	fieldFactories = []protoFieldFactory{&testProtoFailFactory{}}
 
@@ -0,0 +1,284 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Exporter sends ended spans to a tracing backend
+type Exporter interface {
+	// Export sends a batch of spans
+	Export(spans []*Span) error
+}
+
+// batchProcessor queues the ended spans and exports them in batches
+type batchProcessor struct {
+	exporter      Exporter
+	batchSize     int
+	flushInterval time.Duration
+
+	queue    chan *Span
+	stopOnce sync.Once
+	stopped  chan struct{}
+	done     chan struct{}
+
+	dropLock sync.Mutex
+	dropped  int
+}
+
+func newBatchProcessor(exporter Exporter, conf Config) *batchProcessor {
+	p := &batchProcessor{
+		exporter:      exporter,
+		batchSize:     conf.BatchSize,
+		flushInterval: conf.FlushInterval,
+		queue:         make(chan *Span, conf.QueueSize),
+		stopped:       make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+func (p *batchProcessor) enqueue(span *Span) {
+	select {
+	case <-p.stopped:
+		return
+	default:
+	}
+	select {
+	case p.queue <- span:
+	default:
+		p.dropLock.Lock()
+		p.dropped++
+		p.dropLock.Unlock()
+	}
+}
+
+func (p *batchProcessor) run() {
+	defer close(p.done)
+	ticker := time.NewTicker(p.flushInterval)
+	defer ticker.Stop()
+	batch := make([]*Span, 0, p.batchSize)
+	for {
+		select {
+		case span := <-p.queue:
+			batch = append(batch, span)
+			if len(batch) >= p.batchSize {
+				batch = p.flush(batch)
+			}
+		case <-ticker.C:
+			batch = p.flush(batch)
+		case <-p.stopped:
+			for {
+				select {
+				case span := <-p.queue:
+					batch = append(batch, span)
+					if len(batch) >= p.batchSize {
+						batch = p.flush(batch)
+					}
+				default:
+					p.flush(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+func (p *batchProcessor) flush(batch []*Span) []*Span {
+	p.dropLock.Lock()
+	dropped := p.dropped
+	p.dropped = 0
+	p.dropLock.Unlock()
+	if dropped > 0 {
+		logger.Warningf("Dropped %d spans since the export queue was full", dropped)
+	}
+	if len(batch) == 0 {
+		return batch
+	}
+	if err := p.exporter.Export(batch); err != nil {
+		logger.Warningf("Failed exporting %d spans: %s", len(batch), err)
+	}
+	return make([]*Span, 0, p.batchSize)
+}
+
+// stop exports the queued spans and waits for the export to complete
+func (p *batchProcessor) stop() {
+	p.stopOnce.Do(func() {
+		close(p.stopped)
+	})
+	<-p.done
+}
+
+// logExporter writes the spans to the log of the node
+type logExporter struct{}
+
+func (*logExporter) Export(spans []*Span) error {
+	for _, span := range spans {
+		span.lock.Lock()
+		logger.Infof("Span %s trace=%s span=%s parent=%s duration=%s attributes=%v error=%q",
+			span.name, span.context.TraceID, span.context.SpanID, span.parentID, span.end.Sub(span.start), span.attributes, span.err)
+		span.lock.Unlock()
+	}
+	return nil
+}
+
+// OTLPExporter sends the spans to an OpenTelemetry collector, or any backend
+// supporting the OTLP/HTTP protocol with the JSON encoding, such as Jaeger
+type OTLPExporter struct {
+	endpoint    string
+	serviceName string
+	timeout     time.Duration
+	client      *http.Client
+}
+
+// NewOTLPExporter creates an exporter sending the spans to the supplied URL
+func NewOTLPExporter(endpoint, serviceName string, timeout time.Duration) *OTLPExporter {
+	return &OTLPExporter{
+		endpoint:    endpoint,
+		serviceName: serviceName,
+		timeout:     timeout,
+		client:      &http.Client{},
+	}
+}
+
+// Export sends a batch of spans
+func (e *OTLPExporter) Export(spans []*Span) error {
+	body, err := json.Marshal(e.newRequest(spans))
+	if err != nil {
+		return errors.Wrap(err, "failed marshaling spans")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+	req, err := http.NewRequest(http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrapf(err, "invalid OTLP endpoint %s", e.endpoint)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.Wrapf(err, "failed sending spans to %s", e.endpoint)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("%s rejected the spans with status %s", e.endpoint, resp.Status)
+	}
+	return nil
+}
+
+// The types below are the JSON encoding of the OTLP trace export request
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpStatus struct {
+	// Code is 0 when unset, and 2 when the span failed
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func newOTLPAttribute(key string, value interface{}) otlpAttribute {
+	attr := otlpAttribute{Key: key}
+	switch v := value.(type) {
+	case bool:
+		attr.Value.BoolValue = &v
+	case int64:
+		i := strconv.FormatInt(v, 10)
+		attr.Value.IntValue = &i
+	case float64:
+		attr.Value.DoubleValue = &v
+	case string:
+		attr.Value.StringValue = &v
+	}
+	return attr
+}
+
+func (e *OTLPExporter) newRequest(spans []*Span) *otlpRequest {
+	scopeSpans := otlpScopeSpans{Scope: otlpScope{Name: "github.com/hyperledger/fabric"}}
+	for _, span := range spans {
+		span.lock.Lock()
+		s := otlpSpan{
+			TraceID:           span.context.TraceID.String(),
+			SpanID:            span.context.SpanID.String(),
+			Name:              span.name,
+			Kind:              int(span.kind),
+			StartTimeUnixNano: strconv.FormatInt(span.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.end.UnixNano(), 10),
+		}
+		if span.parentID.IsValid() {
+			s.ParentSpanID = span.parentID.String()
+		}
+		for _, attr := range span.attributes {
+			s.Attributes = append(s.Attributes, newOTLPAttribute(attr.Key, attr.Value))
+		}
+		if span.err != "" {
+			s.Status = otlpStatus{Code: 2, Message: span.err}
+		}
+		span.lock.Unlock()
+		scopeSpans.Spans = append(scopeSpans.Spans, s)
+	}
+	return &otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{
+				Attributes: []otlpAttribute{newOTLPAttribute("service.name", e.serviceName)},
+			},
+			ScopeSpans: []otlpScopeSpans{scopeSpans},
+		}},
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOTLPExporter(t *testing.T) {
+	requests := make(chan map[string]interface{}, 1)
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		request := map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal(body, &request))
+		requests <- request
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	exporter := NewOTLPExporter(server.URL, "peer0", time.Second)
+	tracer := NewTracer(Config{SampleRate: 1}, exporter)
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	_, span := tracer.Start(ctx, "child")
+	span.SetAttribute("tx_id", "tx1")
+	span.SetAttribute("block", 3)
+	span.SetAttribute("valid", true)
+	span.SetError(errors.New("failed"))
+	span.End()
+
+	require.NoError(t, exporter.Export([]*Span{span}))
+	request := <-requests
+	resourceSpans := request["resourceSpans"].([]interface{})[0].(map[string]interface{})
+	resource := resourceSpans["resource"].(map[string]interface{})
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"key":   "service.name",
+		"value": map[string]interface{}{"stringValue": "peer0"},
+	}}, resource["attributes"])
+	spans := resourceSpans["scopeSpans"].([]interface{})[0].(map[string]interface{})["spans"].([]interface{})
+	require.Len(t, spans, 1)
+	exported := spans[0].(map[string]interface{})
+	assert.Equal(t, "child", exported["name"])
+	assert.Equal(t, span.Context().TraceID.String(), exported["traceId"])
+	assert.Equal(t, span.Context().SpanID.String(), exported["spanId"])
+	assert.Equal(t, parent.Context().SpanID.String(), exported["parentSpanId"])
+	assert.Equal(t, float64(SpanKindInternal), exported["kind"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"key": "tx_id", "value": map[string]interface{}{"stringValue": "tx1"}},
+		map[string]interface{}{"key": "block", "value": map[string]interface{}{"intValue": "3"}},
+		map[string]interface{}{"key": "valid", "value": map[string]interface{}{"boolValue": true}},
+	}, exported["attributes"])
+	assert.Equal(t, map[string]interface{}{"code": float64(2), "message": "failed"}, exported["status"])
+
+	status = http.StatusBadRequest
+	err := exporter.Export([]*Span{span})
+	<-requests
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "rejected the spans with status 400")
+
+	server.Close()
+	err = exporter.Export([]*Span{span})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed sending spans to")
+}
+
+func TestBatchProcessor(t *testing.T) {
+	exporter := &recordingExporter{}
+	tracer := NewTracer(Config{SampleRate: 1, BatchSize: 2, FlushInterval: time.Hour}, exporter)
+
+	_, span := tracer.Start(context.Background(), "first")
+	span.End()
+	time.Sleep(100 * time.Millisecond)
+	assert.Len(t, exporter.exported(), 0)
+
+	// a full batch is exported without waiting for the flush interval
+	_, span = tracer.Start(context.Background(), "second")
+	span.End()
+	for deadline := time.Now().Add(time.Second); len(exporter.exported()) < 2 && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Len(t, exporter.exported(), 2)
+
+	_, span = tracer.Start(context.Background(), "third")
+	span.End()
+	tracer.Shutdown()
+	assert.Len(t, exporter.exported(), 3)
+
+	// the spans are exported after the flush interval
+	exporter = &recordingExporter{}
+	tracer = NewTracer(Config{SampleRate: 1, FlushInterval: 50 * time.Millisecond}, exporter)
+	defer tracer.Shutdown()
+	_, span = tracer.Start(context.Background(), "flushed")
+	span.End()
+	for deadline := time.Now().Add(time.Second); len(exporter.exported()) < 1 && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Len(t, exporter.exported(), 1)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tracing
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// traceparentKey is the gRPC metadata key carrying the trace context
+const traceparentKey = "traceparent"
+
+// NewOutgoingContext returns a context whose outgoing gRPC metadata carries
+// the trace context of the span of the supplied context, if any
+func NewOutgoingContext(ctx context.Context) context.Context {
+	span := SpanFromContext(ctx)
+	if span == nil {
+		return ctx
+	}
+	// the trace context of an enclosing span may already be set
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	md.Set(traceparentKey, span.Context().Traceparent())
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// RemoteSpanContext returns the trace context carried by the incoming gRPC
+// metadata of the supplied context, or an invalid span context if there is
+// none
+func RemoteSpanContext(ctx context.Context) SpanContext {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return SpanContext{}
+	}
+	values := md.Get(traceparentKey)
+	if len(values) == 0 {
+		return SpanContext{}
+	}
+	sc, err := ParseTraceparent(values[0])
+	if err != nil {
+		logger.Debugf("Ignoring the trace context of the request: %s", err)
+		return SpanContext{}
+	}
+	return sc
+}
+
+func (t *Tracer) startServerSpan(ctx context.Context, method string) (context.Context, *Span) {
+	ctx, span := t.StartWithParent(ctx, method, SpanKindServer, RemoteSpanContext(ctx))
+	span.SetAttribute("rpc.system", "grpc")
+	span.SetAttribute("rpc.method", method)
+	return ctx, span
+}
+
+func endSpan(span *Span, err error) {
+	if err != nil {
+		span.SetAttribute("rpc.grpc.status_code", status.Code(err).String())
+		span.SetError(err)
+	}
+	span.End()
+}
+
+// UnaryServerInterceptor returns an interceptor recording a span for each
+// unary call, whose parent is the span of the client if it propagated its
+// trace context
+func UnaryServerInterceptor(tracer *Tracer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, span := tracer.startServerSpan(ctx, info.FullMethod)
+		resp, err := handler(ctx, req)
+		endSpan(span, err)
+		return resp, err
+	}
+}
+
+type tracedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tracedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// StreamServerInterceptor returns an interceptor recording a span for each
+// stream, whose parent is the span of the client if it propagated its trace
+// context
+func StreamServerInterceptor(tracer *Tracer) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := tracer.startServerSpan(ss.Context(), info.FullMethod)
+		err := handler(srv, &tracedServerStream{ServerStream: ss, ctx: ctx})
+		endSpan(span, err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor returns an interceptor recording a span for each
+// unary call made within a span, and propagating its trace context to the
+// server
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		parent := SpanFromContext(ctx)
+		if parent == nil {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		ctx, span := parent.tracer.StartWithParent(ctx, method, SpanKindClient, parent.Context())
+		span.SetAttribute("rpc.system", "grpc")
+		span.SetAttribute("rpc.method", method)
+		err := invoker(NewOutgoingContext(ctx), method, req, reply, cc, opts...)
+		endSpan(span, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor returns an interceptor propagating the trace
+// context of the span, if any, of the context of the streams to the server
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(NewOutgoingContext(ctx), desc, cc, method, opts...)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type mockServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *mockServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestServerInterceptors(t *testing.T) {
+	exporter := &recordingExporter{}
+	tracer := NewTracer(Config{SampleRate: 1}, exporter)
+	remote := SpanContext{TraceID: newTraceID(), SpanID: newSpanID(), Sampled: true}
+	incoming := metadata.NewIncomingContext(context.Background(), metadata.Pairs(traceparentKey, remote.Traceparent()))
+
+	var served *Span
+	unary := UnaryServerInterceptor(tracer)
+	_, err := unary(incoming, nil, &grpc.UnaryServerInfo{FullMethod: "/protos.Endorser/ProcessProposal"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		served = SpanFromContext(ctx)
+		return nil, nil
+	})
+	assert.NoError(t, err)
+	require.NotNil(t, served)
+	assert.Equal(t, remote.TraceID, served.Context().TraceID)
+	assert.Equal(t, remote.SpanID, served.parentID)
+	assert.Equal(t, SpanKindServer, served.kind)
+
+	// a request without trace context starts a new trace
+	stream := StreamServerInterceptor(tracer)
+	err = stream(nil, &mockServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/orderer.AtomicBroadcast/Broadcast"}, func(srv interface{}, ss grpc.ServerStream) error {
+		served = SpanFromContext(ss.Context())
+		return errors.New("stream failed")
+	})
+	assert.EqualError(t, err, "stream failed")
+	require.NotNil(t, served)
+	assert.NotEqual(t, remote.TraceID, served.Context().TraceID)
+	assert.False(t, served.parentID.IsValid())
+
+	tracer.Shutdown()
+	spans := exporter.exported()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "/protos.Endorser/ProcessProposal", spans[0].Name())
+	assert.Equal(t, "/orderer.AtomicBroadcast/Broadcast", spans[1].Name())
+	assert.Equal(t, "stream failed", spans[1].err)
+}
+
+func TestClientInterceptors(t *testing.T) {
+	exporter := &recordingExporter{}
+	tracer := NewTracer(Config{SampleRate: 1}, exporter)
+
+	var outgoing metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+	unary := UnaryClientInterceptor()
+
+	// the calls made outside of a span carry no trace context
+	assert.NoError(t, unary(context.Background(), "/protos.Endorser/ProcessProposal", nil, nil, nil, invoker))
+	assert.Empty(t, outgoing.Get(traceparentKey))
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	assert.NoError(t, unary(ctx, "/protos.Endorser/ProcessProposal", nil, nil, nil, invoker))
+	require.Len(t, outgoing.Get(traceparentKey), 1)
+	sc, err := ParseTraceparent(outgoing.Get(traceparentKey)[0])
+	require.NoError(t, err)
+	assert.Equal(t, parent.Context().TraceID, sc.TraceID)
+	assert.NotEqual(t, parent.Context().SpanID, sc.SpanID)
+
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		return nil, nil
+	}
+	// the trace context of nested spans replaces the one of their parent
+	_, child := tracer.Start(NewOutgoingContext(ctx), "child")
+	_, err = StreamClientInterceptor()(ContextWithSpan(NewOutgoingContext(ctx), child), nil, nil, "/orderer.AtomicBroadcast/Broadcast", streamer)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{child.Context().Traceparent()}, outgoing.Get(traceparentKey))
+
+	child.End()
+	parent.End()
+	tracer.Shutdown()
+	spans := exporter.exported()
+	require.Len(t, spans, 3)
+	assert.Equal(t, SpanKindClient, spans[0].kind)
+	assert.Equal(t, sc.SpanID, spans[0].Context().SpanID)
+}
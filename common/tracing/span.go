@@ -0,0 +1,235 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TraceID identifies a trace, which is the tree of the spans of a request
+type TraceID [16]byte
+
+// SpanID identifies a span within a trace
+type SpanID [8]byte
+
+// String returns the hex encoding of the trace ID
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid tells whether the trace ID is not made of zeroes only
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+// String returns the hex encoding of the span ID
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid tells whether the span ID is not made of zeroes only
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// SpanContext is the part of a span which is propagated to the
+// services a request goes through
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+// IsValid tells whether the span context identifies a span
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Traceparent returns the span context in the format of the traceparent
+// header of the W3C Trace Context recommendation
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", sc.TraceID, sc.SpanID, flags)
+}
+
+// ParseTraceparent parses a traceparent header of the W3C Trace Context
+// recommendation
+func ParseTraceparent(traceparent string) (SpanContext, error) {
+	sc := SpanContext{}
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 {
+		return sc, errors.Errorf("invalid traceparent %s", traceparent)
+	}
+	version, err := hex.DecodeString(parts[0])
+	if err != nil || len(version) != 1 || version[0] == 0xff || (version[0] == 0 && len(parts) != 4) {
+		return sc, errors.Errorf("invalid traceparent version in %s", traceparent)
+	}
+	traceID, err := hex.DecodeString(parts[1])
+	if err != nil || len(traceID) != len(sc.TraceID) {
+		return sc, errors.Errorf("invalid trace ID in traceparent %s", traceparent)
+	}
+	spanID, err := hex.DecodeString(parts[2])
+	if err != nil || len(spanID) != len(sc.SpanID) {
+		return sc, errors.Errorf("invalid span ID in traceparent %s", traceparent)
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil || len(flags) != 1 {
+		return sc, errors.Errorf("invalid trace flags in traceparent %s", traceparent)
+	}
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	sc.Sampled = flags[0]&0x01 == 0x01
+	if !sc.IsValid() {
+		return SpanContext{}, errors.Errorf("invalid traceparent %s", traceparent)
+	}
+	return sc, nil
+}
+
+// SpanKind tells the role of a span in a remote call
+type SpanKind int
+
+const (
+	// SpanKindInternal is the kind of the spans of an operation within a service
+	SpanKindInternal SpanKind = iota + 1
+	// SpanKindServer is the kind of the spans of the requests served by a service
+	SpanKindServer
+	// SpanKindClient is the kind of the spans of the requests sent to another service
+	SpanKindClient
+)
+
+// Attribute is a key and value describing a span
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Span is a timed operation of a trace. The methods of a nil span do nothing,
+// so that the code creating spans is unchanged when tracing is disabled.
+type Span struct {
+	tracer   *Tracer
+	name     string
+	kind     SpanKind
+	context  SpanContext
+	parentID SpanID
+	start    time.Time
+
+	lock       sync.Mutex
+	end        time.Time
+	attributes []Attribute
+	err        string
+	ended      bool
+}
+
+// Name returns the name of the span
+func (s *Span) Name() string {
+	if s == nil {
+		return ""
+	}
+	return s.name
+}
+
+// Context returns the span context to propagate to the children of the span
+func (s *Span) Context() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.context
+}
+
+// SetAttribute records a key and value describing the span. The value is a
+// string, a bool, an integer or a float, and other types are recorded as
+// their string representation.
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	switch v := value.(type) {
+	case string, bool, int64, float64:
+	case int:
+		value = int64(v)
+	case uint64:
+		value = int64(v)
+	case int32:
+		value = int64(v)
+	case uint32:
+		value = int64(v)
+	default:
+		value = fmt.Sprint(v)
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.attributes = append(s.attributes, Attribute{Key: key, Value: value})
+}
+
+// SetError marks the span as failed with the supplied error, if any
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.err = err.Error()
+}
+
+// Child starts a span of the same trace whose parent is this span
+func (s *Span) Child(name string) *Span {
+	if s == nil {
+		return nil
+	}
+	return s.tracer.newSpan(name, SpanKindInternal, s.context)
+}
+
+// End ends the span, which is exported if it is sampled. Ending a span more
+// than once has no effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	if s.ended {
+		s.lock.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.lock.Unlock()
+	if s.context.Sampled {
+		s.tracer.export(s)
+	}
+}
+
+// Duration returns the duration of an ended span
+func (s *Span) Duration() time.Duration {
+	if s == nil {
+		return 0
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.end.Sub(s.start)
+}
+
+func newTraceID() TraceID {
+	id := TraceID{}
+	rand.Read(id[:])
+	return id
+}
+
+func newSpanID() SpanID {
+	id := SpanID{}
+	rand.Read(id[:])
+	return id
+}
@@ -0,0 +1,257 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package tracing records the spans of the requests served by a Fabric node
+// and propagates their trace context to the nodes the requests go through,
+// following the W3C Trace Context recommendation, so that the spans of a
+// transaction can be gathered by an OpenTelemetry collector or Jaeger.
+package tracing
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+const (
+	otlpExporterType = "otlp"
+	logExporterType  = "log"
+
+	defaultExporterType  = otlpExporterType
+	defaultOTLPEndpoint  = "http://localhost:4318/v1/traces"
+	defaultSampleRate    = 1.0
+	defaultBatchSize     = 512
+	defaultQueueSize     = 2048
+	defaultFlushInterval = 5 * time.Second
+	defaultExportTimeout = 10 * time.Second
+)
+
+var logger = flogging.MustGetLogger("tracing")
+
+// Config is the configuration of the tracer of a node
+type Config struct {
+	// Enabled turns the recording of the spans on
+	Enabled bool
+	// ServiceName is the name of the node in the exported spans
+	ServiceName string
+	// Exporter is the type of the exporter of the spans, "otlp" or "log"
+	Exporter string
+	// Endpoint is the URL of the OTLP/HTTP endpoint receiving the spans
+	Endpoint string
+	// SampleRate is the ratio of the traces started by the node which are
+	// recorded. The traces started by another node are recorded if it
+	// sampled them.
+	SampleRate float64
+	// BatchSize is the maximum number of spans exported at once
+	BatchSize int
+	// QueueSize is the maximum number of spans waiting to be exported,
+	// the spans ended while the queue is full are dropped
+	QueueSize int
+	// FlushInterval is the maximum time a span waits before being exported
+	FlushInterval time.Duration
+	// Timeout is the timeout of an export request
+	Timeout time.Duration
+}
+
+// NewConfig creates the tracing configuration of the peer from the tracing
+// section of the configuration file
+func NewConfig(serviceName string) Config {
+	conf := Config{
+		Enabled:       viper.GetBool("tracing.enabled"),
+		ServiceName:   serviceName,
+		Exporter:      viper.GetString("tracing.exporter"),
+		Endpoint:      viper.GetString("tracing.otlp.endpoint"),
+		SampleRate:    defaultSampleRate,
+		BatchSize:     viper.GetInt("tracing.batchSize"),
+		FlushInterval: viper.GetDuration("tracing.flushInterval"),
+		Timeout:       viper.GetDuration("tracing.otlp.timeout"),
+	}
+	if viper.IsSet("tracing.sampleRate") {
+		conf.SampleRate = viper.GetFloat64("tracing.sampleRate")
+	}
+	if name := viper.GetString("tracing.serviceName"); name != "" {
+		conf.ServiceName = name
+	}
+	return conf
+}
+
+func (conf Config) withDefaults() Config {
+	if conf.Exporter == "" {
+		conf.Exporter = defaultExporterType
+	}
+	if conf.Endpoint == "" {
+		conf.Endpoint = defaultOTLPEndpoint
+	}
+	if conf.BatchSize <= 0 {
+		conf.BatchSize = defaultBatchSize
+	}
+	if conf.QueueSize <= 0 {
+		conf.QueueSize = defaultQueueSize
+	}
+	if conf.FlushInterval <= 0 {
+		conf.FlushInterval = defaultFlushInterval
+	}
+	if conf.Timeout <= 0 {
+		conf.Timeout = defaultExportTimeout
+	}
+	return conf
+}
+
+// Tracer creates the spans of a node and exports the sampled ones
+type Tracer struct {
+	serviceName string
+	sampleRate  float64
+	processor   *batchProcessor
+}
+
+// NewTracer creates a tracer exporting its spans with the supplied exporter
+func NewTracer(conf Config, exporter Exporter) *Tracer {
+	conf = conf.withDefaults()
+	return &Tracer{
+		serviceName: conf.ServiceName,
+		sampleRate:  conf.SampleRate,
+		processor:   newBatchProcessor(exporter, conf),
+	}
+}
+
+func newExporter(conf Config) (Exporter, error) {
+	switch conf.Exporter {
+	case otlpExporterType:
+		return NewOTLPExporter(conf.Endpoint, conf.ServiceName, conf.Timeout), nil
+	case logExporterType:
+		return &logExporter{}, nil
+	default:
+		return nil, errors.Errorf("unknown tracing exporter type %s", conf.Exporter)
+	}
+}
+
+// Start starts a span of the trace carried by the context, or of a new
+// trace if there is none, and returns a context carrying the span
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, *Span) {
+	return t.StartWithParent(ctx, name, SpanKindInternal, SpanFromContext(ctx).Context())
+}
+
+// StartWithParent starts a span whose parent is identified by the supplied
+// span context, which belongs to another service, and returns a context
+// carrying the span. A new trace is started if the parent is invalid.
+func (t *Tracer) StartWithParent(ctx context.Context, name string, kind SpanKind, parent SpanContext) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+	span := t.newSpan(name, kind, parent)
+	return ContextWithSpan(ctx, span), span
+}
+
+func (t *Tracer) newSpan(name string, kind SpanKind, parent SpanContext) *Span {
+	span := &Span{
+		tracer: t,
+		name:   name,
+		kind:   kind,
+		start:  time.Now(),
+	}
+	if parent.IsValid() {
+		span.context.TraceID = parent.TraceID
+		span.context.Sampled = parent.Sampled
+		span.parentID = parent.SpanID
+	} else {
+		span.context.TraceID = newTraceID()
+		span.context.Sampled = rand.Float64() < t.sampleRate
+	}
+	span.context.SpanID = newSpanID()
+	return span
+}
+
+func (t *Tracer) export(span *Span) {
+	t.processor.enqueue(span)
+}
+
+// Shutdown exports the remaining spans and stops the tracer
+func (t *Tracer) Shutdown() {
+	if t == nil {
+		return
+	}
+	t.processor.stop()
+}
+
+type spanKey struct{}
+
+// ContextWithSpan returns a context carrying the supplied span
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	if span == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// SpanFromContext returns the span carried by the context, or nil
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+var (
+	globalLock   sync.RWMutex
+	globalTracer *Tracer
+)
+
+// Init creates the tracer of the node from the supplied configuration. The
+// spans are not recorded if tracing is disabled.
+func Init(conf Config) error {
+	if !conf.Enabled {
+		return nil
+	}
+	conf = conf.withDefaults()
+	if conf.SampleRate < 0 || conf.SampleRate > 1 {
+		return errors.Errorf("tracing sample rate %v is not between 0 and 1", conf.SampleRate)
+	}
+	exporter, err := newExporter(conf)
+	if err != nil {
+		return err
+	}
+	tracer := NewTracer(conf, exporter)
+
+	globalLock.Lock()
+	defer globalLock.Unlock()
+	if globalTracer != nil {
+		globalTracer.Shutdown()
+	}
+	globalTracer = tracer
+	logger.Infof("Tracing enabled, exporting %v%% of the traces of %s with the %s exporter", conf.SampleRate*100, conf.ServiceName, conf.Exporter)
+	return nil
+}
+
+// Shutdown exports the remaining spans of the tracer of the node and stops it
+func Shutdown() {
+	globalLock.Lock()
+	defer globalLock.Unlock()
+	globalTracer.Shutdown()
+	globalTracer = nil
+}
+
+// GlobalTracer returns the tracer of the node, or nil if tracing is disabled
+func GlobalTracer() *Tracer {
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return globalTracer
+}
+
+// IsEnabled tells whether the node records spans
+func IsEnabled() bool {
+	return GlobalTracer() != nil
+}
+
+// Start starts a span with the tracer of the node, see Tracer.Start
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	return GlobalTracer().Start(ctx, name)
+}
@@ -0,0 +1,191 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tracing
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingExporter struct {
+	lock  sync.Mutex
+	spans []*Span
+}
+
+func (e *recordingExporter) Export(spans []*Span) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *recordingExporter) exported() []*Span {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return append([]*Span(nil), e.spans...)
+}
+
+func TestTraceparent(t *testing.T) {
+	sc, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	require.NoError(t, err)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID.String())
+	assert.Equal(t, "00f067aa0ba902b7", sc.SpanID.String())
+	assert.True(t, sc.Sampled)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", sc.Traceparent())
+
+	sc, err = ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	require.NoError(t, err)
+	assert.False(t, sc.Sampled)
+
+	// future versions may have more fields
+	_, err = ParseTraceparent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra")
+	assert.NoError(t, err)
+
+	for _, traceparent := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"00-4bf92f3577b34da6a3ce929d0e0e47-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba9-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-zz",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+	} {
+		_, err := ParseTraceparent(traceparent)
+		assert.Error(t, err, traceparent)
+	}
+}
+
+func TestTracer(t *testing.T) {
+	exporter := &recordingExporter{}
+	tracer := NewTracer(Config{ServiceName: "peer0", SampleRate: 1, FlushInterval: time.Hour}, exporter)
+
+	ctx, root := tracer.Start(context.Background(), "root")
+	assert.Equal(t, root, SpanFromContext(ctx))
+	assert.True(t, root.Context().IsValid())
+	assert.True(t, root.Context().Sampled)
+
+	_, child := tracer.Start(ctx, "child")
+	assert.Equal(t, root.Context().TraceID, child.Context().TraceID)
+	assert.Equal(t, root.Context().SpanID, child.parentID)
+	assert.NotEqual(t, root.Context().SpanID, child.Context().SpanID)
+	child.SetAttribute("tx_id", "tx1")
+	child.SetAttribute("block", uint64(2))
+	child.SetError(errors.New("failed"))
+	child.End()
+	child.End()
+
+	grandchild := child.Child("grandchild")
+	assert.Equal(t, child.Context().SpanID, grandchild.parentID)
+	grandchild.End()
+	root.End()
+
+	tracer.Shutdown()
+	spans := exporter.exported()
+	require.Len(t, spans, 3)
+	assert.Equal(t, "child", spans[0].Name())
+	assert.Equal(t, []Attribute{{"tx_id", "tx1"}, {"block", int64(2)}}, spans[0].attributes)
+	assert.Equal(t, "failed", spans[0].err)
+	assert.Equal(t, "grandchild", spans[1].Name())
+	assert.Equal(t, "root", spans[2].Name())
+
+	// the spans ended after the shutdown are not exported
+	_, span := tracer.Start(context.Background(), "late")
+	span.End()
+	assert.Len(t, exporter.exported(), 3)
+}
+
+func TestTracerSampling(t *testing.T) {
+	exporter := &recordingExporter{}
+	tracer := NewTracer(Config{SampleRate: 0}, exporter)
+	ctx, root := tracer.Start(context.Background(), "root")
+	assert.False(t, root.Context().Sampled)
+	_, child := tracer.Start(ctx, "child")
+	assert.False(t, child.Context().Sampled)
+	child.End()
+	root.End()
+
+	// a trace sampled by another service is recorded
+	remote := SpanContext{TraceID: newTraceID(), SpanID: newSpanID(), Sampled: true}
+	_, span := tracer.StartWithParent(context.Background(), "server", SpanKindServer, remote)
+	assert.Equal(t, remote.TraceID, span.Context().TraceID)
+	assert.Equal(t, remote.SpanID, span.parentID)
+	assert.True(t, span.Context().Sampled)
+	span.End()
+
+	tracer.Shutdown()
+	spans := exporter.exported()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "server", spans[0].Name())
+}
+
+func TestNilSpan(t *testing.T) {
+	var tracer *Tracer
+	ctx, span := tracer.Start(context.Background(), "disabled")
+	assert.Nil(t, span)
+	assert.Equal(t, context.Background(), ctx)
+	span.SetAttribute("key", "value")
+	span.SetError(errors.New("failed"))
+	assert.Nil(t, span.Child("child"))
+	assert.False(t, span.Context().IsValid())
+	span.End()
+	tracer.Shutdown()
+}
+
+func TestInit(t *testing.T) {
+	defer Shutdown()
+	assert.NoError(t, Init(Config{Enabled: false}))
+	assert.False(t, IsEnabled())
+	_, span := Start(context.Background(), "disabled")
+	assert.Nil(t, span)
+
+	err := Init(Config{Enabled: true, Exporter: "zipkin"})
+	assert.EqualError(t, err, "unknown tracing exporter type zipkin")
+	err = Init(Config{Enabled: true, SampleRate: 2})
+	assert.EqualError(t, err, "tracing sample rate 2 is not between 0 and 1")
+	assert.False(t, IsEnabled())
+
+	assert.NoError(t, Init(Config{Enabled: true, Exporter: "log", SampleRate: 1}))
+	assert.True(t, IsEnabled())
+	_, span = Start(context.Background(), "enabled")
+	assert.NotNil(t, span)
+	span.End()
+}
+
+func TestNewConfig(t *testing.T) {
+	defer viper.Reset()
+	conf := NewConfig("peer0")
+	assert.Equal(t, Config{ServiceName: "peer0", SampleRate: 1}, conf)
+
+	viper.Set("tracing.enabled", true)
+	viper.Set("tracing.serviceName", "peer0.org1")
+	viper.Set("tracing.exporter", "otlp")
+	viper.Set("tracing.otlp.endpoint", "http://collector:4318/v1/traces")
+	viper.Set("tracing.otlp.timeout", "3s")
+	viper.Set("tracing.sampleRate", 0.25)
+	viper.Set("tracing.batchSize", 64)
+	viper.Set("tracing.flushInterval", "1s")
+	conf = NewConfig("peer0")
+	assert.Equal(t, Config{
+		Enabled:       true,
+		ServiceName:   "peer0.org1",
+		Exporter:      "otlp",
+		Endpoint:      "http://collector:4318/v1/traces",
+		SampleRate:    0.25,
+		BatchSize:     64,
+		FlushInterval: time.Second,
+		Timeout:       3 * time.Second,
+	}, conf)
+}
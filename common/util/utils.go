@@ -29,6 +29,7 @@ import (
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/bccsp/factory"
 	"github.com/hyperledger/fabric/common/metadata"
+	"golang.org/x/crypto/blake2b"
 )
 
 type alg struct {
@@ -59,6 +60,12 @@ func ComputeSHA3256(data []byte) (hash []byte) {
 	return
 }
 
+// ComputeBLAKE2b256 returns BLAKE2b-256 on data
+func ComputeBLAKE2b256(data []byte) []byte {
+	hash := blake2b.Sum256(data)
+	return hash[:]
+}
+
 // GenerateBytesUUID returns a UUID based on RFC 4122 returning the generated bytes
 func GenerateBytesUUID() []byte {
 	uuid := make([]byte, 16)
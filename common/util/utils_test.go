@@ -18,6 +18,7 @@ package util
 
 import (
 	"bytes"
+	"encoding/hex"
 	"fmt"
 	"reflect"
 	"strings"
@@ -45,6 +46,11 @@ func TestComputeSHA3256(t *testing.T) {
 	}
 }
 
+func TestComputeBLAKE2b256(t *testing.T) {
+	assert.Equal(t, "bddd813c634239723171ef3fee98579b94964e3bb1cb3e427262c8c068d52319", hex.EncodeToString(ComputeBLAKE2b256([]byte("abc"))))
+	assert.NotEqual(t, ComputeBLAKE2b256([]byte("foobar1")), ComputeBLAKE2b256([]byte("foobar2")))
+}
+
 func TestUUIDGeneration(t *testing.T) {
 	uuid := GenerateUUID()
 	if len(uuid) != 36 {
@@ -8,6 +8,7 @@ package aclmgmt
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/core/aclmgmt/resources"
@@ -16,6 +17,7 @@ import (
 	"github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
 )
 
 const (
@@ -28,7 +30,7 @@ const (
 type defaultACLProvider struct {
 	policyChecker policy.PolicyChecker
 
-	//peer wide policy (currently not used)
+	//peer wide policy, evaluated against the local MSP
 	pResourcePolicyMap map[string]string
 
 	//channel specific policy
@@ -53,9 +55,9 @@ func (d *defaultACLProvider) initialize() {
 	d.cResourcePolicyMap = make(map[string]string)
 
 	//-------------- LSCC --------------
-	//p resources (implemented by the chaincode currently)
-	d.pResourcePolicyMap[resources.Lscc_Install] = ""
-	d.pResourcePolicyMap[resources.Lscc_GetInstalledChaincodes] = ""
+	//p resources
+	d.pResourcePolicyMap[resources.Lscc_Install] = mgmt.Admins
+	d.pResourcePolicyMap[resources.Lscc_GetInstalledChaincodes] = mgmt.Admins
 
 	//c resources
 	d.cResourcePolicyMap[resources.Lscc_Deploy] = ""  //ACL check covered by PROPOSAL
@@ -75,16 +77,27 @@ func (d *defaultACLProvider) initialize() {
 	d.cResourcePolicyMap[resources.Qscc_GetBlockByHash] = CHANNELREADERS
 	d.cResourcePolicyMap[resources.Qscc_GetTransactionByID] = CHANNELREADERS
 	d.cResourcePolicyMap[resources.Qscc_GetBlockByTxID] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Qscc_GetTxValidationCode] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Qscc_GetBlockRange] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Qscc_GetBlockByTxIDRange] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Qscc_GetTransactionsByCreator] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Qscc_GetBlocksByTimeRange] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Qscc_GetPrivateDataAvailability] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Qscc_EvaluatePolicy] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Qscc_EvaluateKeyPolicy] = CHANNELREADERS
 
 	//--------------- CSCC resources -----------
-	//p resources (implemented by the chaincode currently)
-	d.pResourcePolicyMap[resources.Cscc_JoinChain] = ""
-	d.pResourcePolicyMap[resources.Cscc_GetChannels] = ""
+	//p resources
+	d.pResourcePolicyMap[resources.Cscc_JoinChain] = mgmt.Admins
+	d.pResourcePolicyMap[resources.Cscc_GetChannels] = mgmt.Members
 
 	//c resources
 	d.cResourcePolicyMap[resources.Cscc_GetConfigBlock] = CHANNELREADERS
 	d.cResourcePolicyMap[resources.Cscc_GetConfigTree] = CHANNELREADERS
 	d.cResourcePolicyMap[resources.Cscc_SimulateConfigTreeUpdate] = CHANNELWRITERS
+	d.cResourcePolicyMap[resources.Cscc_GetChannelConfig] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Cscc_GetCapabilities] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Cscc_GetExpiringIdentities] = CHANNELREADERS
 
 	//---------------- non-scc resources ------------
 	//Peer resources
@@ -94,6 +107,34 @@ func (d *defaultACLProvider) initialize() {
 	//Event resources
 	d.cResourcePolicyMap[resources.Event_Block] = CHANNELREADERS
 	d.cResourcePolicyMap[resources.Event_FilteredBlock] = CHANNELREADERS
+
+	//Gateway resources
+	d.cResourcePolicyMap[resources.Gateway_CommitStatus] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Gateway_CheckConflicts] = CHANNELREADERS
+
+	d.overridePeerPolicies(viper.GetStringMapString("peer.localACLs"))
+}
+
+//overridePeerPolicies replaces the policies of the peer wide resources with the
+//ones of the peer configuration, which are either Admins or Members of the local MSP
+func (d *defaultACLProvider) overridePeerPolicies(acls map[string]string) {
+	for resName := range d.pResourcePolicyMap {
+		//the keys of the peer configuration are not case sensitive
+		var policy string
+		for key, value := range acls {
+			if strings.EqualFold(key, resName) {
+				policy = value
+			}
+		}
+		switch policy {
+		case "":
+		case mgmt.Admins, mgmt.Members:
+			aclLogger.Infof("Using local MSP policy %s for %s", policy, resName)
+			d.pResourcePolicyMap[resName] = policy
+		default:
+			aclLogger.Warningf("Invalid local MSP policy %s for %s; defaulting to %s", policy, resName, d.pResourcePolicyMap[resName])
+		}
+	}
 }
 
 //this should cover an exhaustive list of everything called from the peer
@@ -107,8 +148,18 @@ func (d *defaultACLProvider) defaultPolicy(resName string, cprovider bool) strin
 	return pol
 }
 
-//CheckACL provides default (v 1.0) behavior by mapping resources to their ACL for a channel
+//CheckACL provides default (v 1.0) behavior by mapping resources to their ACL for a channel,
+//and peer wide resources to their policy of the local MSP
 func (d *defaultACLProvider) CheckACL(resName string, channelID string, idinfo interface{}) error {
+	if policy := d.defaultPolicy(resName, false); policy != "" {
+		signedProp, ok := idinfo.(*pb.SignedProposal)
+		if !ok {
+			aclLogger.Errorf("Unmapped id on checkACL %s", resName)
+			return fmt.Errorf("Unknown id on checkACL %s", resName)
+		}
+		return d.policyChecker.CheckPolicyNoChannel(policy, signedProp)
+	}
+
 	policy := d.defaultPolicy(resName, true)
 	if policy == "" {
 		aclLogger.Errorf("Unmapped policy for %s", resName)
@@ -124,6 +175,10 @@ func (d *defaultACLProvider) CheckACL(resName string, channelID string, idinfo i
 			return err
 		}
 		return d.policyChecker.CheckPolicyBySignedData(channelID, policy, sd)
+	case *common.SignedData:
+		return d.policyChecker.CheckPolicyBySignedData(channelID, policy, []*common.SignedData{idinfo.(*common.SignedData)})
+	case []*common.SignedData:
+		return d.policyChecker.CheckPolicyBySignedData(channelID, policy, idinfo.([]*common.SignedData))
 	default:
 		aclLogger.Errorf("Unmapped id on checkACL %s", resName)
 		return fmt.Errorf("Unknown id on checkACL %s", resName)
@@ -0,0 +1,100 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aclmgmt
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	"github.com/hyperledger/fabric/msp/mgmt"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+//mockPolicyChecker satisfies the local MSP policies of the given identities
+type mockPolicyChecker struct {
+	localPolicies map[string][]string
+}
+
+func (pc *mockPolicyChecker) CheckPolicy(channelID, policyName string, signedProp *peer.SignedProposal) error {
+	return errors.New("unexpected channel check")
+}
+
+func (pc *mockPolicyChecker) CheckPolicyBySignedData(channelID, policyName string, sd []*common.SignedData) error {
+	return errors.New("unexpected channel check")
+}
+
+func (pc *mockPolicyChecker) CheckPolicyNoChannel(policyName string, signedProp *peer.SignedProposal) error {
+	prop, err := utils.GetProposal(signedProp.ProposalBytes)
+	if err != nil {
+		return err
+	}
+	hdr, err := utils.GetHeader(prop.Header)
+	if err != nil {
+		return err
+	}
+	shdr, err := utils.GetSignatureHeader(hdr.SignatureHeader)
+	if err != nil {
+		return err
+	}
+	for _, creator := range pc.localPolicies[policyName] {
+		if creator == string(shdr.Creator) {
+			return nil
+		}
+	}
+	return errors.Errorf("%s does not satisfy %s", shdr.Creator, policyName)
+}
+
+func newTestDefaultACLProvider() *defaultACLProvider {
+	d := NewDefaultACLProvider().(*defaultACLProvider)
+	d.policyChecker = &mockPolicyChecker{
+		localPolicies: map[string][]string{
+			mgmt.Admins:  {"Alice"},
+			mgmt.Members: {"Alice", "Bob"},
+		},
+	}
+	return d
+}
+
+func TestDefaultPeerPolicies(t *testing.T) {
+	d := newTestDefaultACLProvider()
+
+	alice, _ := utils.MockSignedEndorserProposalOrPanic("", &peer.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
+	bob, _ := utils.MockSignedEndorserProposalOrPanic("", &peer.ChaincodeSpec{}, []byte("Bob"), []byte("msg1"))
+
+	for _, resName := range []string{resources.Lscc_Install, resources.Lscc_GetInstalledChaincodes, resources.Cscc_JoinChain} {
+		assert.NoError(t, d.CheckACL(resName, "", alice), resName)
+		assert.EqualError(t, d.CheckACL(resName, "", bob), "Bob does not satisfy Admins", resName)
+	}
+	assert.NoError(t, d.CheckACL(resources.Cscc_GetChannels, "", alice))
+	assert.NoError(t, d.CheckACL(resources.Cscc_GetChannels, "", bob))
+
+	err := d.CheckACL(resources.Lscc_Install, "", &common.SignedData{Identity: []byte("Alice")})
+	assert.EqualError(t, err, "Unknown id on checkACL lscc/Install")
+}
+
+func TestOverridePeerPolicies(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("peer.localACLs", map[string]string{
+		"lscc/getinstalledchaincodes": "Members",
+		"cscc/GetChannels":            "Admins",
+		"cscc/JoinChain":              "Readers",
+	})
+	d := newTestDefaultACLProvider()
+
+	bob, _ := utils.MockSignedEndorserProposalOrPanic("", &peer.ChaincodeSpec{}, []byte("Bob"), []byte("msg1"))
+
+	assert.NoError(t, d.CheckACL(resources.Lscc_GetInstalledChaincodes, "", bob))
+	assert.EqualError(t, d.CheckACL(resources.Cscc_GetChannels, "", bob), "Bob does not satisfy Admins")
+	// invalid and missing policies keep their default
+	assert.EqualError(t, d.CheckACL(resources.Cscc_JoinChain, "", bob), "Bob does not satisfy Admins")
+	assert.EqualError(t, d.CheckACL(resources.Lscc_Install, "", bob), "Bob does not satisfy Admins")
+}
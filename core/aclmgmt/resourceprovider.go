@@ -10,6 +10,7 @@ import (
 	"fmt"
 
 	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -64,7 +65,7 @@ func (pe *policyEvaluatorImpl) Evaluate(polName string, sd []*common.SignedData)
 		return PolicyNotFound(polName)
 	}
 
-	return policy.Evaluate(sd)
+	return policies.Evaluate(policy, sd)
 }
 
 //------ resourcePolicyProvider ----------
@@ -125,6 +126,10 @@ func (rp *aclmgmtPolicyProviderImpl) CheckACL(polName string, idinfo interface{}
 		if err != nil {
 			return err
 		}
+	case *common.SignedData:
+		sd = []*common.SignedData{idinfo.(*common.SignedData)}
+	case []*common.SignedData:
+		sd = idinfo.([]*common.SignedData)
 	default:
 		return InvalidIdInfo(polName)
 	}
@@ -66,6 +66,12 @@ func TestPolicyBase(t *testing.T) {
 	assert.NoError(t, err)
 	err = pprov.CheckACL("pol", env)
 	assert.NoError(t, err)
+
+	err = pprov.CheckACL("pol", &common.SignedData{Data: []byte("msg"), Identity: []byte("Alice"), Signature: []byte("sig")})
+	assert.NoError(t, err)
+
+	err = pprov.CheckACL("pol", []*common.SignedData{{Data: []byte("msg"), Identity: []byte("Alice"), Signature: []byte("sig")}})
+	assert.NoError(t, err)
 }
 
 func TestPolicyBad(t *testing.T) {
@@ -23,11 +23,19 @@ const (
 	Lscc_GetCollectionsConfig      = "lscc/GetCollectionsConfig"
 
 	//Qscc resources
-	Qscc_GetChainInfo       = "qscc/GetChainInfo"
-	Qscc_GetBlockByNumber   = "qscc/GetBlockByNumber"
-	Qscc_GetBlockByHash     = "qscc/GetBlockByHash"
-	Qscc_GetTransactionByID = "qscc/GetTransactionByID"
-	Qscc_GetBlockByTxID     = "qscc/GetBlockByTxID"
+	Qscc_GetChainInfo               = "qscc/GetChainInfo"
+	Qscc_GetBlockByNumber           = "qscc/GetBlockByNumber"
+	Qscc_GetBlockByHash             = "qscc/GetBlockByHash"
+	Qscc_GetTransactionByID         = "qscc/GetTransactionByID"
+	Qscc_GetBlockByTxID             = "qscc/GetBlockByTxID"
+	Qscc_GetTxValidationCode        = "qscc/GetTxValidationCode"
+	Qscc_GetBlockRange              = "qscc/GetBlockRange"
+	Qscc_GetBlockByTxIDRange        = "qscc/GetBlockByTxIDRange"
+	Qscc_GetTransactionsByCreator   = "qscc/GetTransactionsByCreator"
+	Qscc_GetBlocksByTimeRange       = "qscc/GetBlocksByTimeRange"
+	Qscc_GetPrivateDataAvailability = "qscc/GetPrivateDataAvailability"
+	Qscc_EvaluatePolicy             = "qscc/EvaluatePolicy"
+	Qscc_EvaluateKeyPolicy          = "qscc/EvaluateKeyPolicy"
 
 	//Cscc resources
 	Cscc_JoinChain                = "cscc/JoinChain"
@@ -35,6 +43,9 @@ const (
 	Cscc_GetChannels              = "cscc/GetChannels"
 	Cscc_GetConfigTree            = "cscc/GetConfigTree"
 	Cscc_SimulateConfigTreeUpdate = "cscc/SimulateConfigTreeUpdate"
+	Cscc_GetChannelConfig         = "cscc/GetChannelConfig"
+	Cscc_GetCapabilities          = "cscc/GetCapabilities"
+	Cscc_GetExpiringIdentities    = "cscc/GetExpiringIdentities"
 
 	//Peer resources
 	Peer_Propose              = "peer/Propose"
@@ -43,4 +54,8 @@ const (
 	//Events
 	Event_Block         = "event/Block"
 	Event_FilteredBlock = "event/FilteredBlock"
+
+	//Gateway resources
+	Gateway_CommitStatus   = "gateway/CommitStatus"
+	Gateway_CheckConflicts = "gateway/CheckConflicts"
 )
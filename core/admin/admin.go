@@ -9,9 +9,16 @@ package admin
 import (
 	"context"
 	"strings"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hyperledger/fabric/common/certmonitor"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/config/reload"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/pkg/errors"
@@ -32,6 +39,23 @@ type AccessControlEvaluator interface {
 	Evaluate(signatureSet []*common.SignedData) error
 }
 
+// ChaincodeStatusProvider returns the status of the chaincodes running on the
+// peer
+type ChaincodeStatusProvider interface {
+	// ChaincodeStatus returns the status of the chaincodes, restricted to the
+	// instances of the chaincode with the given name if it is set
+	ChaincodeStatus(chaincodeName string) []*pb.ChaincodeStatus
+}
+
+// ExpiringCertificatesProvider returns the certificates the peer depends on
+// which are about to expire
+type ExpiringCertificatesProvider interface {
+	// Expiring returns the certificates which expire within the given
+	// duration, or within the warning period if it is not positive, the
+	// earliest first
+	Expiring(within time.Duration) ([]certmonitor.Certificate, error)
+}
+
 // NewAdminServer creates and returns a Admin service instance.
 func NewAdminServer(ace AccessControlEvaluator) *ServerAdmin {
 	s := &ServerAdmin{
@@ -39,6 +63,9 @@ func NewAdminServer(ace AccessControlEvaluator) *ServerAdmin {
 			ace: ace,
 		},
 		levelsAtStartup: flogging.GetModuleLevels(),
+		reloadConfig:    reload.Reload,
+		indexManager:    ledgerIndexManager,
+		snapshotLister:  ledgerSnapshotLister,
 	}
 	return s
 }
@@ -48,6 +75,47 @@ type ServerAdmin struct {
 	v requestValidator
 
 	levelsAtStartup map[string]zapcore.Level
+
+	reloadConfig func() ([]string, error)
+
+	indexManager func(channelID string) (ledger.StateIndexManager, error)
+
+	snapshotLister func(channelID string) (ledger.SnapshotLister, error)
+
+	// ChaincodeStatusProvider serves the status requests of the chaincodes
+	ChaincodeStatusProvider ChaincodeStatusProvider
+
+	// ExpiringCertificatesProvider serves the requests listing the expiring
+	// certificates
+	ExpiringCertificatesProvider ExpiringCertificatesProvider
+}
+
+// ledgerIndexManager returns the manager of the state indexes of the ledger of
+// the channel
+func ledgerIndexManager(channelID string) (ledger.StateIndexManager, error) {
+	l := peer.GetLedger(channelID)
+	if l == nil {
+		return nil, errors.Errorf("channel %s not found", channelID)
+	}
+	indexManager, ok := ledgermgmt.Unwrap(l).(ledger.StateIndexManager)
+	if !ok {
+		return nil, errors.Errorf("the ledger of channel %s does not support state indexes", channelID)
+	}
+	return indexManager, nil
+}
+
+// ledgerSnapshotLister returns the lister of the state snapshots of the ledger
+// of the channel
+func ledgerSnapshotLister(channelID string) (ledger.SnapshotLister, error) {
+	l := peer.GetLedger(channelID)
+	if l == nil {
+		return nil, errors.Errorf("channel %s not found", channelID)
+	}
+	snapshotLister, ok := ledgermgmt.Unwrap(l).(ledger.SnapshotLister)
+	if !ok {
+		return nil, errors.Errorf("the ledger of channel %s does not support snapshots", channelID)
+	}
+	return snapshotLister, nil
 }
 
 func (s *ServerAdmin) GetStatus(ctx context.Context, env *common.Envelope) (*pb.ServerStatus, error) {
@@ -103,3 +171,178 @@ func (s *ServerAdmin) RevertLogLevels(ctx context.Context, env *common.Envelope)
 	flogging.RestoreLevels(s.levelsAtStartup)
 	return &empty.Empty{}, nil
 }
+
+func (s *ServerAdmin) ReloadConfig(ctx context.Context, env *common.Envelope) (*pb.ReloadConfigResponse, error) {
+	if _, err := s.v.validate(ctx, env); err != nil {
+		return nil, err
+	}
+	changedKeys, err := s.reloadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ReloadConfigResponse{ChangedKeys: changedKeys}, nil
+}
+
+func (s *ServerAdmin) ListStateIndexes(ctx context.Context, env *common.Envelope) (*pb.StateIndexesResponse, error) {
+	request, indexManager, err := s.stateIndexRequest(ctx, env)
+	if err != nil {
+		return nil, err
+	}
+	return listStateIndexes(indexManager, request)
+}
+
+func (s *ServerAdmin) CreateStateIndex(ctx context.Context, env *common.Envelope) (*pb.StateIndexesResponse, error) {
+	request, indexManager, err := s.stateIndexRequest(ctx, env)
+	if err != nil {
+		return nil, err
+	}
+	if len(request.Definition) == 0 {
+		return nil, errors.New("index definition is empty")
+	}
+	if err := indexManager.CreateStateIndex(request.Chaincode, request.Collection, request.Definition); err != nil {
+		return nil, err
+	}
+	logger.Infof("Created a state index for chaincode %s on channel %s", request.Chaincode, request.ChannelId)
+	return listStateIndexes(indexManager, request)
+}
+
+func (s *ServerAdmin) RebuildStateIndexes(ctx context.Context, env *common.Envelope) (*pb.StateIndexesResponse, error) {
+	request, indexManager, err := s.stateIndexRequest(ctx, env)
+	if err != nil {
+		return nil, err
+	}
+	if err := indexManager.RebuildStateIndexes(request.Chaincode, request.Collection); err != nil {
+		return nil, err
+	}
+	logger.Infof("Rebuilt the state indexes of chaincode %s on channel %s", request.Chaincode, request.ChannelId)
+	return listStateIndexes(indexManager, request)
+}
+
+func (s *ServerAdmin) stateIndexRequest(ctx context.Context, env *common.Envelope) (*pb.StateIndexRequest, ledger.StateIndexManager, error) {
+	op, err := s.v.validate(ctx, env)
+	if err != nil {
+		return nil, nil, err
+	}
+	request := op.GetIndexReq()
+	if request == nil {
+		return nil, nil, errors.New("request is nil")
+	}
+	if request.ChannelId == "" || request.Chaincode == "" {
+		return nil, nil, errors.New("channel and chaincode must be specified")
+	}
+	indexManager, err := s.indexManager(request.ChannelId)
+	if err != nil {
+		return nil, nil, err
+	}
+	return request, indexManager, nil
+}
+
+func listStateIndexes(indexManager ledger.StateIndexManager, request *pb.StateIndexRequest) (*pb.StateIndexesResponse, error) {
+	indexes, err := indexManager.ListStateIndexes(request.Chaincode, request.Collection)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.StateIndexesResponse{}
+	for _, index := range indexes {
+		resp.Indexes = append(resp.Indexes, &pb.StateIndex{
+			Collection:     index.Collection,
+			DesignDocument: index.DesignDocument,
+			Name:           index.Name,
+			Definition:     index.Definition,
+		})
+	}
+	return resp, nil
+}
+
+func (s *ServerAdmin) GetChaincodeStatus(ctx context.Context, env *common.Envelope) (*pb.ChaincodeStatusResponse, error) {
+	op, err := s.v.validate(ctx, env)
+	if err != nil {
+		return nil, err
+	}
+	request := op.GetChaincodeStatusReq()
+	if request == nil {
+		return nil, errors.New("request is nil")
+	}
+	if s.ChaincodeStatusProvider == nil {
+		return nil, errors.New("chaincode status is not available")
+	}
+	return &pb.ChaincodeStatusResponse{Chaincodes: s.ChaincodeStatusProvider.ChaincodeStatus(request.Chaincode)}, nil
+}
+
+func (s *ServerAdmin) ListSnapshots(ctx context.Context, env *common.Envelope) (*pb.SnapshotsResponse, error) {
+	op, err := s.v.validate(ctx, env)
+	if err != nil {
+		return nil, err
+	}
+	request := op.GetSnapshotsReq()
+	if request == nil {
+		return nil, errors.New("request is nil")
+	}
+	if request.ChannelId == "" {
+		return nil, errors.New("channel must be specified")
+	}
+	snapshotLister, err := s.snapshotLister(request.ChannelId)
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := snapshotLister.ListSnapshots()
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.SnapshotsResponse{}
+	for _, snapshot := range snapshots {
+		ts, err := ptypes.TimestampProto(snapshot.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		resp.Snapshots = append(resp.Snapshots, &pb.Snapshot{
+			Height:        snapshot.Height,
+			Path:          snapshot.Path,
+			LastBlockHash: snapshot.LastBlockHash,
+			Timestamp:     ts,
+		})
+	}
+	return resp, nil
+}
+
+func (s *ServerAdmin) ListExpiringCertificates(ctx context.Context, env *common.Envelope) (*pb.ExpiringCertificatesResponse, error) {
+	op, err := s.v.validate(ctx, env)
+	if err != nil {
+		return nil, err
+	}
+	request := op.GetExpiringCertsReq()
+	if request == nil {
+		return nil, errors.New("request is nil")
+	}
+	if s.ExpiringCertificatesProvider == nil {
+		return nil, errors.New("certificate monitoring is not enabled")
+	}
+	var within time.Duration
+	if request.Within != nil {
+		if within, err = ptypes.Duration(request.Within); err != nil {
+			return nil, errors.Wrap(err, "invalid duration")
+		}
+	}
+	certs, err := s.ExpiringCertificatesProvider.Expiring(within)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	resp := &pb.ExpiringCertificatesResponse{}
+	for _, c := range certs {
+		notAfter, err := ptypes.TimestampProto(c.NotAfter)
+		if err != nil {
+			return nil, err
+		}
+		resp.Certificates = append(resp.Certificates, &pb.ExpiringCertificate{
+			ChannelId:    c.Channel,
+			MspId:        c.MSPID,
+			Role:         c.Role,
+			Subject:      c.Subject,
+			SerialNumber: c.SerialNumber,
+			NotAfter:     notAfter,
+			Expired:      !c.NotAfter.After(now),
+		})
+	}
+	return resp, nil
+}
@@ -9,11 +9,17 @@ package admin
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/ptypes/duration"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric/common/certmonitor"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/testutil"
 	"github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -58,7 +64,7 @@ func TestForbidden(t *testing.T) {
 	adminServer := NewAdminServer(nil)
 	adminServer.v = &mockValidator{}
 	mv := adminServer.v.(*mockValidator)
-	mv.On("validate").Return(nil, accessDenied).Times(5)
+	mv.On("validate").Return(nil, accessDenied).Times(11)
 
 	ctx := context.Background()
 	status, err := adminServer.GetStatus(ctx, nil)
@@ -78,6 +84,296 @@ func TestForbidden(t *testing.T) {
 
 	_, err = adminServer.StartServer(ctx, nil)
 	assert.Equal(t, accessDenied, err)
+
+	_, err = adminServer.ReloadConfig(ctx, nil)
+	assert.Equal(t, accessDenied, err)
+
+	_, err = adminServer.ListStateIndexes(ctx, nil)
+	assert.Equal(t, accessDenied, err)
+
+	_, err = adminServer.CreateStateIndex(ctx, nil)
+	assert.Equal(t, accessDenied, err)
+
+	_, err = adminServer.RebuildStateIndexes(ctx, nil)
+	assert.Equal(t, accessDenied, err)
+
+	_, err = adminServer.GetChaincodeStatus(ctx, nil)
+	assert.Equal(t, accessDenied, err)
+
+	_, err = adminServer.ListExpiringCertificates(ctx, nil)
+	assert.Equal(t, accessDenied, err)
+}
+
+func TestReloadConfig(t *testing.T) {
+	adminServer := NewAdminServer(nil)
+	adminServer.v = &mockValidator{}
+	mv := adminServer.v.(*mockValidator)
+	mv.On("validate").Return(nil, nil).Twice()
+
+	adminServer.reloadConfig = func() ([]string, error) {
+		return []string{"logging.level"}, nil
+	}
+	response, err := adminServer.ReloadConfig(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"logging.level"}, response.ChangedKeys)
+
+	adminServer.reloadConfig = func() ([]string, error) {
+		return nil, errors.New("no configuration file to reload")
+	}
+	_, err = adminServer.ReloadConfig(context.Background(), nil)
+	assert.EqualError(t, err, "no configuration file to reload")
+}
+
+type mockStateIndexManager struct {
+	indexes []*ledger.StateIndex
+	err     error
+}
+
+func (m *mockStateIndexManager) ListStateIndexes(chaincodeName, collection string) ([]*ledger.StateIndex, error) {
+	return m.indexes, m.err
+}
+
+func (m *mockStateIndexManager) CreateStateIndex(chaincodeName, collection string, definition []byte) error {
+	if m.err == nil {
+		m.indexes = append(m.indexes, &ledger.StateIndex{Collection: collection, DesignDocument: "indexOwnerDoc", Name: "indexOwner", Definition: string(definition)})
+	}
+	return m.err
+}
+
+func (m *mockStateIndexManager) RebuildStateIndexes(chaincodeName, collection string) error {
+	return m.err
+}
+
+func TestStateIndexCalls(t *testing.T) {
+	adminServer := NewAdminServer(nil)
+	adminServer.v = &mockValidator{}
+	mv := adminServer.v.(*mockValidator)
+	indexManager := &mockStateIndexManager{}
+	adminServer.indexManager = func(channelID string) (ledger.StateIndexManager, error) {
+		if channelID != "mychannel" {
+			return nil, errors.Errorf("ledger [%s] not found", channelID)
+		}
+		return indexManager, nil
+	}
+
+	wrapIndexRequest := func(req *pb.StateIndexRequest) *pb.AdminOperation {
+		return &pb.AdminOperation{
+			Content: &pb.AdminOperation_IndexReq{
+				IndexReq: req,
+			},
+		}
+	}
+	ctx := context.Background()
+
+	mv.On("validate").Return(wrapIndexRequest(&pb.StateIndexRequest{ChannelId: "mychannel", Chaincode: "marbles"}), nil).Once()
+	resp, err := adminServer.ListStateIndexes(ctx, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Indexes)
+
+	definition := []byte(`{"index":{"fields":["owner"]},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}`)
+	mv.On("validate").Return(wrapIndexRequest(&pb.StateIndexRequest{ChannelId: "mychannel", Chaincode: "marbles", Collection: "collectionMarbles", Definition: definition}), nil).Once()
+	resp, err = adminServer.CreateStateIndex(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []*pb.StateIndex{{Collection: "collectionMarbles", DesignDocument: "indexOwnerDoc", Name: "indexOwner", Definition: string(definition)}}, resp.Indexes)
+
+	mv.On("validate").Return(wrapIndexRequest(&pb.StateIndexRequest{ChannelId: "mychannel", Chaincode: "marbles"}), nil).Once()
+	resp, err = adminServer.RebuildStateIndexes(ctx, nil)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Indexes, 1)
+
+	mv.On("validate").Return(wrapIndexRequest(&pb.StateIndexRequest{ChannelId: "mychannel", Chaincode: "marbles"}), nil).Once()
+	_, err = adminServer.CreateStateIndex(ctx, nil)
+	assert.EqualError(t, err, "index definition is empty")
+
+	mv.On("validate").Return(wrapIndexRequest(nil), nil).Once()
+	_, err = adminServer.ListStateIndexes(ctx, nil)
+	assert.EqualError(t, err, "request is nil")
+
+	mv.On("validate").Return(wrapIndexRequest(&pb.StateIndexRequest{ChannelId: "mychannel"}), nil).Once()
+	_, err = adminServer.ListStateIndexes(ctx, nil)
+	assert.EqualError(t, err, "channel and chaincode must be specified")
+
+	mv.On("validate").Return(wrapIndexRequest(&pb.StateIndexRequest{ChannelId: "otherchannel", Chaincode: "marbles"}), nil).Once()
+	_, err = adminServer.ListStateIndexes(ctx, nil)
+	assert.EqualError(t, err, "ledger [otherchannel] not found")
+
+	indexManager.err = errors.New("collection [collectionMarbles] is not defined")
+	mv.On("validate").Return(wrapIndexRequest(&pb.StateIndexRequest{ChannelId: "mychannel", Chaincode: "marbles"}), nil).Once()
+	_, err = adminServer.RebuildStateIndexes(ctx, nil)
+	assert.EqualError(t, err, "collection [collectionMarbles] is not defined")
+}
+
+type mockChaincodeStatusProvider map[string][]*pb.ChaincodeStatus
+
+func (m mockChaincodeStatusProvider) ChaincodeStatus(chaincodeName string) []*pb.ChaincodeStatus {
+	return m[chaincodeName]
+}
+
+func TestGetChaincodeStatus(t *testing.T) {
+	adminServer := NewAdminServer(nil)
+	adminServer.v = &mockValidator{}
+	mv := adminServer.v.(*mockValidator)
+
+	wrapStatusRequest := func(req *pb.ChaincodeStatusRequest) *pb.AdminOperation {
+		return &pb.AdminOperation{
+			Content: &pb.AdminOperation_ChaincodeStatusReq{
+				ChaincodeStatusReq: req,
+			},
+		}
+	}
+	ctx := context.Background()
+
+	mv.On("validate").Return(wrapStatusRequest(&pb.ChaincodeStatusRequest{}), nil).Once()
+	_, err := adminServer.GetChaincodeStatus(ctx, nil)
+	assert.EqualError(t, err, "chaincode status is not available")
+
+	marbles := []*pb.ChaincodeStatus{{Name: "marbles:1.0", Responsive: true}}
+	adminServer.ChaincodeStatusProvider = mockChaincodeStatusProvider{
+		"":        append(marbles, &pb.ChaincodeStatus{Name: "mycc:1.0"}),
+		"marbles": marbles,
+	}
+	mv.On("validate").Return(wrapStatusRequest(&pb.ChaincodeStatusRequest{Chaincode: "marbles"}), nil).Once()
+	resp, err := adminServer.GetChaincodeStatus(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, marbles, resp.Chaincodes)
+
+	mv.On("validate").Return(wrapStatusRequest(&pb.ChaincodeStatusRequest{}), nil).Once()
+	resp, err = adminServer.GetChaincodeStatus(ctx, nil)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Chaincodes, 2)
+
+	mv.On("validate").Return(wrapStatusRequest(nil), nil).Once()
+	_, err = adminServer.GetChaincodeStatus(ctx, nil)
+	assert.EqualError(t, err, "request is nil")
+}
+
+type mockExpiringCertificatesProvider struct {
+	certs  []certmonitor.Certificate
+	within time.Duration
+	err    error
+}
+
+func (m *mockExpiringCertificatesProvider) Expiring(within time.Duration) ([]certmonitor.Certificate, error) {
+	m.within = within
+	return m.certs, m.err
+}
+
+func TestListExpiringCertificates(t *testing.T) {
+	adminServer := NewAdminServer(nil)
+	adminServer.v = &mockValidator{}
+	mv := adminServer.v.(*mockValidator)
+
+	wrapCertsRequest := func(req *pb.ExpiringCertificatesRequest) *pb.AdminOperation {
+		return &pb.AdminOperation{
+			Content: &pb.AdminOperation_ExpiringCertsReq{
+				ExpiringCertsReq: req,
+			},
+		}
+	}
+	ctx := context.Background()
+
+	mv.On("validate").Return(wrapCertsRequest(&pb.ExpiringCertificatesRequest{}), nil).Once()
+	_, err := adminServer.ListExpiringCertificates(ctx, nil)
+	assert.EqualError(t, err, "certificate monitoring is not enabled")
+
+	provider := &mockExpiringCertificatesProvider{certs: []certmonitor.Certificate{
+		{MSPID: "SampleOrg", Role: "signer", Subject: "CN=peer0", SerialNumber: "1", NotAfter: time.Unix(1500000000, 0)},
+		{Channel: "mychannel", MSPID: "Org1", Role: "root_cert", Subject: "CN=ca", SerialNumber: "2", NotAfter: time.Now().Add(time.Hour)},
+	}}
+	adminServer.ExpiringCertificatesProvider = provider
+	mv.On("validate").Return(wrapCertsRequest(&pb.ExpiringCertificatesRequest{Within: &duration.Duration{Seconds: 7200}}), nil).Once()
+	resp, err := adminServer.ListExpiringCertificates(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, provider.within)
+	assert.Len(t, resp.Certificates, 2)
+	assert.Equal(t, &pb.ExpiringCertificate{
+		MspId:        "SampleOrg",
+		Role:         "signer",
+		Subject:      "CN=peer0",
+		SerialNumber: "1",
+		NotAfter:     &timestamp.Timestamp{Seconds: 1500000000},
+		Expired:      true,
+	}, resp.Certificates[0])
+	assert.Equal(t, "mychannel", resp.Certificates[1].ChannelId)
+	assert.False(t, resp.Certificates[1].Expired)
+
+	// the warning period of the peer applies without duration
+	mv.On("validate").Return(wrapCertsRequest(&pb.ExpiringCertificatesRequest{}), nil).Once()
+	_, err = adminServer.ListExpiringCertificates(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), provider.within)
+
+	provider.err = errors.New("failed reading the config of channel mychannel")
+	mv.On("validate").Return(wrapCertsRequest(&pb.ExpiringCertificatesRequest{}), nil).Once()
+	_, err = adminServer.ListExpiringCertificates(ctx, nil)
+	assert.EqualError(t, err, "failed reading the config of channel mychannel")
+
+	mv.On("validate").Return(wrapCertsRequest(nil), nil).Once()
+	_, err = adminServer.ListExpiringCertificates(ctx, nil)
+	assert.EqualError(t, err, "request is nil")
+}
+
+type mockSnapshotLister struct {
+	snapshots []*ledger.SnapshotInfo
+	err       error
+}
+
+func (m *mockSnapshotLister) ListSnapshots() ([]*ledger.SnapshotInfo, error) {
+	return m.snapshots, m.err
+}
+
+func TestListSnapshots(t *testing.T) {
+	adminServer := NewAdminServer(nil)
+	adminServer.v = &mockValidator{}
+	mv := adminServer.v.(*mockValidator)
+	snapshotLister := &mockSnapshotLister{snapshots: []*ledger.SnapshotInfo{{
+		Height:        1000,
+		Path:          "/var/hyperledger/production/snapshots/mychannel/1000",
+		LastBlockHash: []byte("hash"),
+		Timestamp:     time.Unix(1500000000, 0),
+	}}}
+	adminServer.snapshotLister = func(channelID string) (ledger.SnapshotLister, error) {
+		if channelID != "mychannel" {
+			return nil, errors.Errorf("ledger [%s] not found", channelID)
+		}
+		return snapshotLister, nil
+	}
+
+	wrapSnapshotsRequest := func(req *pb.SnapshotsRequest) *pb.AdminOperation {
+		return &pb.AdminOperation{
+			Content: &pb.AdminOperation_SnapshotsReq{
+				SnapshotsReq: req,
+			},
+		}
+	}
+	ctx := context.Background()
+
+	mv.On("validate").Return(wrapSnapshotsRequest(&pb.SnapshotsRequest{ChannelId: "mychannel"}), nil).Once()
+	resp, err := adminServer.ListSnapshots(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []*pb.Snapshot{{
+		Height:        1000,
+		Path:          "/var/hyperledger/production/snapshots/mychannel/1000",
+		LastBlockHash: []byte("hash"),
+		Timestamp:     &timestamp.Timestamp{Seconds: 1500000000},
+	}}, resp.Snapshots)
+
+	mv.On("validate").Return(wrapSnapshotsRequest(nil), nil).Once()
+	_, err = adminServer.ListSnapshots(ctx, nil)
+	assert.EqualError(t, err, "request is nil")
+
+	mv.On("validate").Return(wrapSnapshotsRequest(&pb.SnapshotsRequest{}), nil).Once()
+	_, err = adminServer.ListSnapshots(ctx, nil)
+	assert.EqualError(t, err, "channel must be specified")
+
+	mv.On("validate").Return(wrapSnapshotsRequest(&pb.SnapshotsRequest{ChannelId: "otherchannel"}), nil).Once()
+	_, err = adminServer.ListSnapshots(ctx, nil)
+	assert.EqualError(t, err, "ledger [otherchannel] not found")
+
+	snapshotLister.err = errors.New("error reading the snapshots")
+	mv.On("validate").Return(wrapSnapshotsRequest(&pb.SnapshotsRequest{ChannelId: "mychannel"}), nil).Once()
+	_, err = adminServer.ListSnapshots(ctx, nil)
+	assert.EqualError(t, err, "error reading the snapshots")
 }
 
 func TestLoggingCalls(t *testing.T) {
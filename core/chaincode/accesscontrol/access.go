@@ -45,7 +45,9 @@ func NewAuthenticator(ca tlsgen.CA) *Authenticator {
 
 // Generate returns a pair of certificate and private key,
 // and associates the hash of the certificate with the given
-// chaincode name
+// chaincode name. The certificate previously generated for
+// the chaincode name is revoked, and the certificate can be
+// used by a single registration of the chaincode.
 func (ac *Authenticator) Generate(ccName string) (*CertAndPrivKeyPair, error) {
 	cert, err := ac.mapper.genCert(ccName)
 	if err != nil {
@@ -77,8 +79,9 @@ func (ac *Authenticator) authenticate(msg *pb.ChaincodeMessage, stream grpc.Serv
 		logger.Warning(errMsg)
 		return errors.New(errMsg)
 	}
-	// Look it up in the mapper
-	registeredName := ac.mapper.lookup(certHash(hash))
+	// Look it up in the mapper. The certificate is claimed by this registration,
+	// so it can't be used again by an impostor which stole it
+	registeredName := ac.mapper.claim(certHash(hash))
 	if registeredName == "" {
 		errMsg := fmt.Sprintf("Chaincode %s with given certificate hash %v not found in registry", ccName, hash)
 		logger.Warning(errMsg)
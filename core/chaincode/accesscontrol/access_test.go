@@ -301,6 +301,76 @@ func TestAccessControl(t *testing.T) {
 	assertLogContains(t, recorder, "with given certificate hash", "not found in registry")
 }
 
+func TestCertificatePinning(t *testing.T) {
+	oldLogger := logger
+	l, recorder := floggingtest.NewTestLogger(t, floggingtest.AtLevel(zapcore.InfoLevel))
+	logger = l
+	defer func() { logger = oldLogger }()
+
+	chaincodeID := &pb.ChaincodeID{Name: "example02"}
+	payload, err := proto.Marshal(chaincodeID)
+	assert.NoError(t, err)
+	registerMsg := &pb.ChaincodeMessage{
+		Type:    pb.ChaincodeMessage_REGISTER,
+		Payload: payload,
+	}
+	putStateMsg := &pb.ChaincodeMessage{
+		Type: pb.ChaincodeMessage_PUT_STATE,
+	}
+
+	ca, _ := tlsgen.NewCA()
+	srv := newCCServer(t, 7054, "example02", true, ca)
+	auth := NewAuthenticator(ca)
+	pb.RegisterChaincodeSupportServer(srv.grpcSrv, auth.Wrap(srv))
+	go srv.grpcSrv.Serve(srv.l)
+	defer srv.stop()
+
+	clientCert := func(kp *CertAndPrivKeyPair) *tls.Certificate {
+		keyBytes, err := base64.StdEncoding.DecodeString(kp.Key)
+		assert.NoError(t, err)
+		certBytes, err := base64.StdEncoding.DecodeString(kp.Cert)
+		assert.NoError(t, err)
+		cert, err := tls.X509KeyPair(certBytes, keyBytes)
+		assert.NoError(t, err)
+		return &cert
+	}
+
+	// The chaincode is launched, and then restarted before registering
+	kp, err := auth.Generate("example02")
+	assert.NoError(t, err)
+	stolenCert := clientCert(kp)
+	kp, err = auth.Generate("example02")
+	assert.NoError(t, err)
+	cert := clientCert(kp)
+
+	// The certificate of the former launch was revoked by the restart
+	impostor, err := newClient(t, 7054, stolenCert, ca.CertBytes())
+	assert.NoError(t, err)
+	defer impostor.close()
+	impostor.sendMsg(registerMsg)
+	impostor.sendMsg(putStateMsg)
+	assert.Nil(t, impostor.recv())
+	assertLogContains(t, recorder, "with given certificate hash", "not found in registry")
+
+	realCC, err := newClient(t, 7054, cert, ca.CertBytes())
+	assert.NoError(t, err)
+	defer realCC.close()
+	realCC.sendMsg(registerMsg)
+	realCC.sendMsg(putStateMsg)
+	echoMsg := realCC.recv()
+	assert.NotNil(t, echoMsg)
+	assert.Empty(t, recorder.Messages())
+
+	// The certificate was claimed by the registration of the real chaincode
+	impostor, err = newClient(t, 7054, cert, ca.CertBytes())
+	assert.NoError(t, err)
+	defer impostor.close()
+	impostor.sendMsg(registerMsg)
+	impostor.sendMsg(putStateMsg)
+	assert.Nil(t, impostor.recv())
+	assertLogContains(t, recorder, "with given certificate hash", "not found in registry")
+}
+
 func assertLogContains(t *testing.T, r *floggingtest.Recorder, ss ...string) {
 	defer r.Reset()
 	for _, s := range ss {
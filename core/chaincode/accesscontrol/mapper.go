@@ -27,12 +27,16 @@ type certMapper struct {
 	keyGen KeyGenFunc
 	sync.RWMutex
 	m map[certHash]string
+	// issued maps every chaincode name to the hash of the
+	// last certificate generated for it
+	issued map[string]certHash
 }
 
 func newCertMapper(keyGen KeyGenFunc) *certMapper {
 	return &certMapper{
 		keyGen: keyGen,
 		m:      make(map[certHash]string),
+		issued: make(map[string]certHash),
 	}
 }
 
@@ -42,10 +46,26 @@ func (r *certMapper) lookup(h certHash) string {
 	return r.m[h]
 }
 
+// claim returns the chaincode name associated with the hash and removes the
+// association, so that a certificate is used by a single registration
+func (r *certMapper) claim(h certHash) string {
+	r.Lock()
+	defer r.Unlock()
+	name := r.m[h]
+	r.remove(h)
+	return name
+}
+
+// register associates the hash with the name, and revokes the certificate
+// previously generated for the name, which belongs to a former launch of the chaincode
 func (r *certMapper) register(hash certHash, name string) {
 	r.Lock()
 	defer r.Unlock()
+	if previous, exists := r.issued[name]; exists {
+		delete(r.m, previous)
+	}
 	r.m[hash] = name
+	r.issued[name] = hash
 	time.AfterFunc(ttl, func() {
 		r.purge(hash)
 	})
@@ -54,7 +74,18 @@ func (r *certMapper) register(hash certHash, name string) {
 func (r *certMapper) purge(hash certHash) {
 	r.Lock()
 	defer r.Unlock()
+	r.remove(hash)
+}
+
+func (r *certMapper) remove(hash certHash) {
+	name, exists := r.m[hash]
+	if !exists {
+		return
+	}
 	delete(r.m, hash)
+	if r.issued[name] == hash {
+		delete(r.issued, name)
+	}
 }
 
 func (r *certMapper) genCert(name string) (*tlsgen.CertKeyPair, error) {
@@ -31,3 +31,38 @@ func TestPurge(t *testing.T) {
 	time.Sleep(time.Second * 3)
 	assert.Empty(t, m.lookup(certHash(hash)))
 }
+
+func TestRotationAndClaim(t *testing.T) {
+	ca, _ := tlsgen.NewCA()
+	m := newCertMapper(ca.NewClientCertKeyPair)
+	hashOf := func(k *tlsgen.CertKeyPair) certHash {
+		hash, _ := factory.GetDefault().Hash(k.TLSCert.Raw, &bccsp.SHA256Opts{})
+		return certHash(hash)
+	}
+
+	k1, err := m.genCert("A")
+	assert.NoError(t, err)
+	k2, err := m.genCert("B")
+	assert.NoError(t, err)
+	assert.Equal(t, "A", m.lookup(hashOf(k1)))
+	assert.Equal(t, "B", m.lookup(hashOf(k2)))
+
+	// generating a new certificate for A revokes the previous one
+	k3, err := m.genCert("A")
+	assert.NoError(t, err)
+	assert.Empty(t, m.lookup(hashOf(k1)))
+	assert.Equal(t, "A", m.lookup(hashOf(k3)))
+	assert.Equal(t, "B", m.lookup(hashOf(k2)))
+
+	// a certificate can be claimed only once
+	assert.Equal(t, "A", m.claim(hashOf(k3)))
+	assert.Empty(t, m.claim(hashOf(k3)))
+	assert.Empty(t, m.issued["A"])
+	assert.Empty(t, m.claim(hashOf(k1)))
+
+	// purging a revoked certificate leaves the current one untouched
+	k4, err := m.genCert("B")
+	assert.NoError(t, err)
+	m.purge(hashOf(k2))
+	assert.Equal(t, "B", m.lookup(hashOf(k4)))
+}
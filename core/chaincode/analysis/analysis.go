@@ -0,0 +1,247 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package analysis
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+var logger = flogging.MustGetLogger("chaincode.analysis")
+
+// Action is what the peer does with the findings of a check
+type Action string
+
+const (
+	// Ignore disables the check
+	Ignore Action = "ignore"
+	// Warn logs the findings of the check
+	Warn Action = "warn"
+	// Reject rejects the install of the chaincodes the check finds an
+	// issue in
+	Reject Action = "reject"
+)
+
+// Package holds the parsed Go source files of a package of a chaincode
+type Package struct {
+	// Path is the import path of the package
+	Path  string
+	Fset  *token.FileSet
+	Files []*ast.File
+	// Info holds the types inferred from the source files of the package
+	// alone, the types defined by the imported packages are invalid
+	Info *types.Info
+}
+
+// Finding is a potential source of nondeterminism found by a check
+type Finding struct {
+	Check    string
+	Position token.Position
+	Message  string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s (%s)", f.Position, f.Message, f.Check)
+}
+
+// Check looks for potential sources of nondeterminism in a package
+type Check func(pkg *Package) []Finding
+
+var (
+	checksLock sync.RWMutex
+	checks     = map[string]Check{}
+)
+
+// RegisterCheck makes a check available under the given name, which the
+// configuration of the analyzers refers to regardless of its case
+func RegisterCheck(name string, check Check) {
+	checksLock.Lock()
+	defer checksLock.Unlock()
+	name = strings.ToLower(name)
+	if _, exists := checks[name]; exists {
+		logger.Panicf("Check %s is already registered", name)
+	}
+	checks[name] = check
+}
+
+// Analyzer runs checks on the source files of the Go chaincodes before they
+// are installed on the peer
+type Analyzer struct {
+	actions map[string]Action
+}
+
+// NewAnalyzer returns an analyzer running the registered checks with the
+// given names, and acting on their findings as specified
+func NewAnalyzer(actions map[string]Action) (*Analyzer, error) {
+	checksLock.RLock()
+	defer checksLock.RUnlock()
+	a := &Analyzer{actions: map[string]Action{}}
+	for name, action := range actions {
+		name = strings.ToLower(name)
+		if _, exists := checks[name]; !exists {
+			return nil, errors.Errorf("unknown chaincode analysis check %s", name)
+		}
+		switch action {
+		case Ignore:
+		case Warn, Reject:
+			a.actions[name] = action
+		default:
+			return nil, errors.Errorf("invalid action %s for chaincode analysis check %s, expected %s, %s or %s", action, name, Ignore, Warn, Reject)
+		}
+	}
+	return a, nil
+}
+
+// Analyze runs the checks on the Go source files of the chaincode, excluding
+// its vendored packages and tests. The findings of the checks whose action is
+// Warn are logged, and an error listing those of the checks whose action is
+// Reject is returned if they found any.
+func (a *Analyzer) Analyze(cds *pb.ChaincodeDeploymentSpec) error {
+	if a == nil || len(a.actions) == 0 {
+		return nil
+	}
+	if cds.ChaincodeSpec == nil || cds.ChaincodeSpec.Type != pb.ChaincodeSpec_GOLANG || len(cds.CodePackage) == 0 {
+		return nil
+	}
+	name, version := cds.ChaincodeSpec.ChaincodeId.Name, cds.ChaincodeSpec.ChaincodeId.Version
+
+	pkgs, err := parsePackages(cds.ChaincodeSpec.ChaincodeId.Path, cds.CodePackage)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed analyzing chaincode %s:%s", name, version))
+	}
+
+	var checkNames []string
+	for checkName := range a.actions {
+		checkNames = append(checkNames, checkName)
+	}
+	sort.Strings(checkNames)
+
+	checksLock.RLock()
+	defer checksLock.RUnlock()
+	var rejected []string
+	for _, pkg := range pkgs {
+		for _, checkName := range checkNames {
+			for _, finding := range checks[checkName](pkg) {
+				finding.Check = checkName
+				if a.actions[checkName] == Reject {
+					rejected = append(rejected, finding.String())
+					continue
+				}
+				logger.Warningf("Chaincode %s:%s: %s", name, version, finding)
+			}
+		}
+	}
+	if len(rejected) > 0 {
+		return errors.Errorf("chaincode %s:%s rejected by the static analysis: %s", name, version, strings.Join(rejected, "; "))
+	}
+	return nil
+}
+
+// parsePackages parses the Go source files of the chaincode at the given
+// path and of its sub packages in the code package
+func parsePackages(ccPath string, codePackage []byte) ([]*Package, error) {
+	gr, err := gzip.NewReader(bytes.NewReader(codePackage))
+	if err != nil {
+		return nil, errors.Wrap(err, "failure opening codepackage gzip stream")
+	}
+	tr := tar.NewReader(gr)
+
+	prefix := path.Join("src", ccPath) + "/"
+	fset := token.NewFileSet()
+	files := map[string][]*ast.File{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failure reading codepackage tar stream")
+		}
+
+		name := strings.TrimPrefix(header.Name, "/")
+		if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		if strings.Contains("/"+strings.TrimPrefix(name, prefix), "/vendor/") {
+			continue
+		}
+		src, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, errors.Wrap(err, "failure reading codepackage tar stream")
+		}
+		f, err := parser.ParseFile(fset, strings.TrimPrefix(name, "src/"), src, 0)
+		if err != nil {
+			// the chaincode would not build either
+			return nil, errors.Wrapf(err, "failure parsing %s", name)
+		}
+		dir := path.Dir(strings.TrimPrefix(name, "src/"))
+		files[dir] = append(files[dir], f)
+	}
+
+	var dirs []string
+	for dir := range files {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	var pkgs []*Package
+	for _, dir := range dirs {
+		pkg := &Package{
+			Path:  dir,
+			Fset:  fset,
+			Files: files[dir],
+			Info: &types.Info{
+				Types: map[ast.Expr]types.TypeAndValue{},
+				Defs:  map[*ast.Ident]types.Object{},
+				Uses:  map[*ast.Ident]types.Object{},
+			},
+		}
+		// the packages imported are not available, so that the type checker
+		// reports errors on their use which can be ignored
+		conf := &types.Config{
+			Importer: importerFunc(func(path string) (*types.Package, error) {
+				return nil, errors.Errorf("package %s not available", path)
+			}),
+			Error: func(error) {},
+		}
+		conf.Check(dir, fset, pkg.Files, pkg.Info)
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs, nil
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) {
+	return f(path)
+}
+
+// GlobalConfig returns the actions of the checks set in the peer configuration
+func GlobalConfig() map[string]Action {
+	actions := map[string]Action{}
+	for name, action := range viper.GetStringMapString("chaincode.analysis.checks") {
+		actions[strings.ToLower(name)] = Action(strings.ToLower(action))
+	}
+	return actions
+}
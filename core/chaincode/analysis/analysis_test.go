@@ -0,0 +1,190 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package analysis
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+const ccSource = `package main
+
+import (
+	"fmt"
+	mrand "math/rand"
+	"time"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+)
+
+type counts map[string]int
+
+func total(c counts) int {
+	sum := 0
+	for _, n := range c {
+		sum += n
+	}
+	return sum
+}
+
+func put(stub shim.ChaincodeStubInterface) {
+	stub.PutState("key", []byte("value"))
+}
+
+func invoke(stub shim.ChaincodeStubInterface) {
+	for i := range []int{1, 2} {
+		fmt.Println(i)
+	}
+	for k := range map[string]bool{"a": true} {
+		fmt.Println(k)
+	}
+	fmt.Println(time.Now(), time.Duration(0), mrand.Intn(10))
+	go func() {
+		stub.DelState("key")
+	}()
+	go put(stub)
+	go fmt.Println("done")
+}
+`
+
+const helperSource = `package helper
+
+import "crypto/rand"
+
+func Seed(b []byte) {
+	rand.Read(b)
+}
+`
+
+const vendoredSource = `package lib
+
+import "time"
+
+func Now() time.Time {
+	return time.Now()
+}
+`
+
+func codePackage(t *testing.T, files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	for name, src := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0100644, Size: int64(len(src))})
+		assert.NoError(t, err)
+		_, err = tw.Write([]byte(src))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+func testCDS(t *testing.T) *pb.ChaincodeDeploymentSpec {
+	return &pb.ChaincodeDeploymentSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_GOLANG,
+			ChaincodeId: &pb.ChaincodeID{Name: "mycc", Version: "1.0", Path: "github.com/example/mycc"},
+		},
+		CodePackage: codePackage(t, map[string]string{
+			"src/github.com/example/mycc/mycc.go":              ccSource,
+			"src/github.com/example/mycc/mycc_test.go":         vendoredSource,
+			"src/github.com/example/mycc/helper/helper.go":     helperSource,
+			"src/github.com/example/mycc/vendor/lib/lib.go":    vendoredSource,
+			"src/github.com/example/other/other.go":            vendoredSource,
+			"META-INF/statedb/couchdb/indexes/indexOwner.json": "{}",
+		}),
+	}
+}
+
+func findings(t *testing.T, check string) []string {
+	pkgs, err := parsePackages("github.com/example/mycc", testCDS(t).CodePackage)
+	assert.NoError(t, err)
+	var res []string
+	for _, pkg := range pkgs {
+		for _, finding := range checks[check](pkg) {
+			res = append(res, finding.String())
+		}
+	}
+	return res
+}
+
+func TestChecks(t *testing.T) {
+	assert.Equal(t, []string{
+		"github.com/example/mycc/mycc.go:15:2: iteration over a map in random order ()",
+		"github.com/example/mycc/mycc.go:29:2: iteration over a map in random order ()",
+	}, findings(t, "maprange"))
+	assert.Equal(t, []string{
+		"github.com/example/mycc/mycc.go:32:14: read of the local clock with time.Now ()",
+	}, findings(t, "time"))
+	assert.Equal(t, []string{
+		"github.com/example/mycc/mycc.go:32:44: use of math/rand.Intn ()",
+		"github.com/example/mycc/helper/helper.go:6:2: use of crypto/rand.Read ()",
+	}, findings(t, "rand"))
+	assert.Equal(t, []string{
+		"github.com/example/mycc/mycc.go:33:2: goroutine writing to the state ()",
+		"github.com/example/mycc/mycc.go:36:2: goroutine writing to the state ()",
+	}, findings(t, "goroutine"))
+}
+
+func TestAnalyze(t *testing.T) {
+	cds := testCDS(t)
+
+	// nothing is checked by default
+	var a *Analyzer
+	assert.NoError(t, a.Analyze(cds))
+	a, err := NewAnalyzer(nil)
+	assert.NoError(t, err)
+	assert.NoError(t, a.Analyze(cds))
+
+	a, err = NewAnalyzer(map[string]Action{"mapRange": Warn, "time": Ignore, "goroutine": Warn})
+	assert.NoError(t, err)
+	assert.NoError(t, a.Analyze(cds))
+
+	a, err = NewAnalyzer(map[string]Action{"mapRange": Warn, "time": Reject, "RAND": Reject})
+	assert.NoError(t, err)
+	assert.EqualError(t, a.Analyze(cds), "chaincode mycc:1.0 rejected by the static analysis: "+
+		"github.com/example/mycc/mycc.go:32:44: use of math/rand.Intn (rand); "+
+		"github.com/example/mycc/mycc.go:32:14: read of the local clock with time.Now (time); "+
+		"github.com/example/mycc/helper/helper.go:6:2: use of crypto/rand.Read (rand)")
+
+	// only the Go chaincodes are analyzed
+	cds.ChaincodeSpec.Type = pb.ChaincodeSpec_NODE
+	assert.NoError(t, a.Analyze(cds))
+
+	cds = testCDS(t)
+	cds.CodePackage = codePackage(t, map[string]string{"src/github.com/example/mycc/mycc.go": "package main\nfunc"})
+	err = a.Analyze(cds)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed analyzing chaincode mycc:1.0: failure parsing src/github.com/example/mycc/mycc.go")
+
+	cds.CodePackage = []byte("garbage")
+	assert.Error(t, a.Analyze(cds))
+}
+
+func TestNewAnalyzerErrors(t *testing.T) {
+	_, err := NewAnalyzer(map[string]Action{"nonexistent": Warn})
+	assert.EqualError(t, err, "unknown chaincode analysis check nonexistent")
+	_, err = NewAnalyzer(map[string]Action{"time": "fail"})
+	assert.EqualError(t, err, "invalid action fail for chaincode analysis check time, expected ignore, warn or reject")
+
+	assert.Panics(t, func() { RegisterCheck("Time", checkTime) })
+}
+
+func TestGlobalConfig(t *testing.T) {
+	defer viper.Reset()
+	assert.Empty(t, GlobalConfig())
+
+	viper.Set("chaincode.analysis.checks", map[string]interface{}{"mapRange": "WARN", "time": "reject"})
+	assert.Equal(t, map[string]Action{"maprange": Warn, "time": Reject}, GlobalConfig())
+}
@@ -0,0 +1,166 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package analysis
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// The checks built into the peer, which are heuristics: they may miss some
+// sources of nondeterminism and report code which is deterministic
+const (
+	// MapRangeCheck reports the iterations over maps, whose order is random
+	MapRangeCheck = "mapRange"
+	// TimeCheck reports the reads of the local clock
+	TimeCheck = "time"
+	// RandCheck reports the uses of the random number generators
+	RandCheck = "rand"
+	// GoroutineCheck reports the goroutines writing to the state, whose
+	// writes are ordered randomly
+	GoroutineCheck = "goroutine"
+)
+
+func init() {
+	RegisterCheck(MapRangeCheck, checkMapRange)
+	RegisterCheck(TimeCheck, checkTime)
+	RegisterCheck(RandCheck, checkRand)
+	RegisterCheck(GoroutineCheck, checkGoroutine)
+}
+
+func checkMapRange(pkg *Package) []Finding {
+	var findings []Finding
+	for _, f := range pkg.Files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			rs, ok := n.(*ast.RangeStmt)
+			if !ok {
+				return true
+			}
+			if t := pkg.Info.TypeOf(rs.X); t != nil {
+				if _, isMap := t.Underlying().(*types.Map); isMap {
+					findings = append(findings, Finding{Position: pkg.Fset.Position(rs.Pos()), Message: "iteration over a map in random order"})
+				}
+			}
+			return true
+		})
+	}
+	return findings
+}
+
+// clockFuncs are the functions of the time package reading the local clock
+var clockFuncs = map[string]bool{"Now": true, "Since": true, "Until": true}
+
+func checkTime(pkg *Package) []Finding {
+	var findings []Finding
+	inspectPackageSelectors(pkg, "time", func(sel *ast.SelectorExpr) {
+		if clockFuncs[sel.Sel.Name] {
+			findings = append(findings, Finding{Position: pkg.Fset.Position(sel.Pos()), Message: "read of the local clock with time." + sel.Sel.Name})
+		}
+	})
+	return findings
+}
+
+func checkRand(pkg *Package) []Finding {
+	var findings []Finding
+	for _, randPath := range []string{"math/rand", "crypto/rand"} {
+		inspectPackageSelectors(pkg, randPath, func(sel *ast.SelectorExpr) {
+			findings = append(findings, Finding{Position: pkg.Fset.Position(sel.Pos()), Message: "use of " + randPath + "." + sel.Sel.Name})
+		})
+	}
+	return findings
+}
+
+// stateWrites are the methods of the chaincode stub writing to the state or
+// to the read-write set of the transaction
+var stateWrites = map[string]bool{
+	"PutState":                          true,
+	"DelState":                          true,
+	"PutPrivateData":                    true,
+	"DelPrivateData":                    true,
+	"SetStateValidationParameter":       true,
+	"SetPrivateDataValidationParameter": true,
+	"SetEvent":                          true,
+}
+
+func checkGoroutine(pkg *Package) []Finding {
+	// the functions of the package, so that the goroutines running them can
+	// be checked
+	funcs := map[types.Object]*ast.FuncDecl{}
+	for _, f := range pkg.Files {
+		for _, decl := range f.Decls {
+			if fd, ok := decl.(*ast.FuncDecl); ok && fd.Body != nil {
+				if obj := pkg.Info.Defs[fd.Name]; obj != nil {
+					funcs[obj] = fd
+				}
+			}
+		}
+	}
+
+	var findings []Finding
+	for _, f := range pkg.Files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			gs, ok := n.(*ast.GoStmt)
+			if !ok {
+				return true
+			}
+			var body ast.Node
+			switch fun := gs.Call.Fun.(type) {
+			case *ast.FuncLit:
+				body = fun.Body
+			case *ast.Ident:
+				if fd := funcs[pkg.Info.Uses[fun]]; fd != nil {
+					body = fd.Body
+				}
+			case *ast.SelectorExpr:
+				if stateWrites[fun.Sel.Name] {
+					body = gs.Call
+				} else if fd := funcs[pkg.Info.Uses[fun.Sel]]; fd != nil {
+					body = fd.Body
+				}
+			}
+			if body != nil && writesState(body) {
+				findings = append(findings, Finding{Position: pkg.Fset.Position(gs.Pos()), Message: "goroutine writing to the state"})
+			}
+			return true
+		})
+	}
+	return findings
+}
+
+func writesState(n ast.Node) bool {
+	found := false
+	ast.Inspect(n, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && stateWrites[sel.Sel.Name] {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// inspectPackageSelectors calls inspect for each selector of a member of the
+// package with the given import path in the package
+func inspectPackageSelectors(pkg *Package, importPath string, inspect func(sel *ast.SelectorExpr)) {
+	for _, f := range pkg.Files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			// the type checker resolves the names of the imported packages,
+			// even though their content is not available
+			if x, ok := sel.X.(*ast.Ident); ok {
+				if pn, ok := pkg.Info.Uses[x].(*types.PkgName); ok && pn.Imported().Path() == importPath {
+					inspect(sel)
+				}
+			}
+			return true
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/chaincode/platforms"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
@@ -42,6 +43,16 @@ type Lifecycle interface {
 	ChaincodeContainerInfo(chainID string, chaincodeID string) (*ccprovider.ChaincodeContainerInfo, error)
 }
 
+// ContainerQuota bounds the number of chaincode containers launched by the
+// transactions of a channel
+type ContainerQuota interface {
+	// AcquireContainer accounts the container of the chaincode to the channel
+	// launching it, or returns an error if the channel has no slot left
+	AcquireContainer(channelID, cname string) error
+	// ReleaseContainer frees the slot of the container of the chaincode
+	ReleaseContainer(cname string)
+}
+
 // ChaincodeSupport responsible for providing interfacing with chaincodes from the Peer.
 type ChaincodeSupport struct {
 	Keepalive        time.Duration
@@ -54,6 +65,21 @@ type ChaincodeSupport struct {
 	SystemCCProvider sysccprovider.SystemChaincodeProvider
 	Lifecycle        Lifecycle
 	appConfig        ApplicationConfigRetriever
+	// MaxConcurrentRequests is the maximum number of transactions executed
+	// concurrently by each chaincode, zero meaning no limit
+	MaxConcurrentRequests int
+	// Metrics is the scope of the chaincode metrics
+	Metrics metrics.Scope
+	// QueryLimits bounds the resources used by the queries of chaincodes
+	QueryLimits QueryLimits
+	// ContainerQuota, when set, bounds the number of chaincode containers
+	// launched by the transactions of each channel
+	ContainerQuota ContainerQuota
+	// HeartbeatInterval is the interval between the heartbeats sent by the
+	// chaincode containers, zero disabling them
+	HeartbeatInterval time.Duration
+	// Restarter, when set, relaunches the chaincode containers which exit
+	Restarter *Restarter
 }
 
 // NewChaincodeSupport creates a new ChaincodeSupport instance.
@@ -70,7 +96,12 @@ func NewChaincodeSupport(
 	SystemCCProvider sysccprovider.SystemChaincodeProvider,
 	platformRegistry *platforms.Registry,
 	appConfig ApplicationConfigRetriever,
+	metricsScope metrics.Scope,
 ) *ChaincodeSupport {
+	if metricsScope == nil {
+		metricsScope = metrics.NewNoOpScope()
+	}
+
 	cs := &ChaincodeSupport{
 		UserRunsCC:       userRunsCC,
 		Keepalive:        config.Keepalive,
@@ -80,6 +111,11 @@ func NewChaincodeSupport(
 		SystemCCProvider: SystemCCProvider,
 		Lifecycle:        lifecycle,
 		appConfig:        appConfig,
+
+		MaxConcurrentRequests: config.MaxConcurrentRequests,
+		Metrics:               metricsScope,
+		QueryLimits:           config.QueryLimits,
+		HeartbeatInterval:     config.HeartbeatInterval,
 	}
 
 	// Keep TestQueries working
@@ -87,17 +123,27 @@ func NewChaincodeSupport(
 		certGenerator = nil
 	}
 
+	commonEnv := []string{
+		"CORE_CHAINCODE_LOGGING_LEVEL=" + config.LogLevel,
+		"CORE_CHAINCODE_LOGGING_SHIM=" + config.ShimLogLevel,
+		"CORE_CHAINCODE_LOGGING_FORMAT=" + config.LogFormat,
+	}
+	if config.HeartbeatInterval > 0 {
+		commonEnv = append(commonEnv, "CORE_CHAINCODE_HEARTBEAT_INTERVAL="+config.HeartbeatInterval.String())
+	}
+
 	cs.Runtime = &ContainerRuntime{
 		CertGenerator:    certGenerator,
 		Processor:        processor,
 		CACert:           caCert,
 		PeerAddress:      peerAddress,
 		PlatformRegistry: platformRegistry,
-		CommonEnv: []string{
-			"CORE_CHAINCODE_LOGGING_LEVEL=" + config.LogLevel,
-			"CORE_CHAINCODE_LOGGING_SHIM=" + config.ShimLogLevel,
-			"CORE_CHAINCODE_LOGGING_FORMAT=" + config.LogFormat,
-		},
+		CommonEnv:        commonEnv,
+	}
+
+	// the chaincodes run by the user in development mode are not restarted
+	if !userRunsCC {
+		cs.Restarter = NewRestarter(config.RestartPolicy, cs.launch, metricsScope)
 	}
 
 	cs.Launcher = &RuntimeLauncher{
@@ -113,13 +159,36 @@ func NewChaincodeSupport(
 // LaunchForInit bypasses getting the chaincode spec from the LSCC table
 // as in the case of v1.0-v1.2 lifecycle, the chaincode will not yet be
 // defined in the LSCC table
-func (cs *ChaincodeSupport) LaunchInit(ccci *ccprovider.ChaincodeContainerInfo) error {
+func (cs *ChaincodeSupport) LaunchInit(chainID string, ccci *ccprovider.ChaincodeContainerInfo) error {
 	cname := ccci.Name + ":" + ccci.Version
 	if cs.HandlerRegistry.Handler(cname) != nil {
 		return nil
 	}
 
-	return cs.Launcher.Launch(ccci)
+	return cs.launch(chainID, cname, ccci)
+}
+
+// launch launches the chaincode container on behalf of the channel, within
+// the container quota of the channel, unless the circuit breaker of the
+// chaincode is open
+func (cs *ChaincodeSupport) launch(chainID, cname string, ccci *ccprovider.ChaincodeContainerInfo) error {
+	if err := cs.Restarter.Allow(cname); err != nil {
+		return err
+	}
+
+	if cs.ContainerQuota != nil {
+		if err := cs.ContainerQuota.AcquireContainer(chainID, cname); err != nil {
+			return err
+		}
+	}
+	if err := cs.Launcher.Launch(ccci); err != nil {
+		if cs.ContainerQuota != nil {
+			cs.ContainerQuota.ReleaseContainer(cname)
+		}
+		return err
+	}
+	cs.Restarter.Launched(chainID, cname, ccci)
+	return nil
 }
 
 // Launch starts executing chaincode if it is not already running. This method
@@ -143,7 +212,7 @@ func (cs *ChaincodeSupport) Launch(chainID, chaincodeName, chaincodeVersion stri
 		return nil, errors.Wrapf(err, "[channel %s] failed to get chaincode container info for %s", chainID, cname)
 	}
 
-	if err := cs.Launcher.Launch(ccci); err != nil {
+	if err := cs.launch(chainID, cname, ccci); err != nil {
 		return nil, errors.Wrapf(err, "[channel %s] could not launch chaincode %s", chainID, cname)
 	}
 
@@ -157,6 +226,7 @@ func (cs *ChaincodeSupport) Launch(chainID, chaincodeName, chaincodeVersion stri
 
 // Stop stops a chaincode if running.
 func (cs *ChaincodeSupport) Stop(ccci *ccprovider.ChaincodeContainerInfo) error {
+	cs.Restarter.Stopped(ccci.Name + ":" + ccci.Version)
 	return cs.Runtime.Stop(ccci)
 }
 
@@ -173,13 +243,23 @@ func (cs *ChaincodeSupport) HandleChaincodeStream(stream ccintf.ChaincodeStream)
 		SystemCCProvider:           cs.SystemCCProvider,
 		SystemCCVersion:            util.GetSysCCVersion(),
 		InstantiationPolicyChecker: CheckInstantiationPolicyFunc(ccprovider.CheckInstantiationPolicy),
-		QueryResponseBuilder:       &QueryResponseGenerator{MaxResultLimit: 100},
+		QueryResponseBuilder:       &QueryResponseGenerator{MaxResultLimit: 100, Limits: cs.QueryLimits},
 		UUIDGenerator:              UUIDGeneratorFunc(util.GenerateUUID),
 		LedgerGetter:               peer.Default,
 		AppConfig:                  cs.appConfig,
+		MaxConcurrentRequests:      cs.MaxConcurrentRequests,
+		Metrics:                    cs.Metrics,
 	}
 
-	return handler.ProcessStream(stream)
+	err := handler.ProcessStream(stream)
+	// the container stopped, or failed to register
+	if handler.chaincodeID != nil {
+		if cs.ContainerQuota != nil {
+			cs.ContainerQuota.ReleaseContainer(handler.chaincodeID.Name)
+		}
+		cs.Restarter.Exited(handler.chaincodeID.Name)
+	}
+	return err
 }
 
 // Register the bidi stream entry point called by chaincode to register with the Peer.
@@ -187,6 +267,21 @@ func (cs *ChaincodeSupport) Register(stream pb.ChaincodeSupport_RegisterServer)
 	return cs.HandleChaincodeStream(stream)
 }
 
+// ChaincodeStatus returns the status of the chaincodes registered with the
+// peer, restricted to the instances of the chaincode with the given name if
+// it is set
+func (cs *ChaincodeSupport) ChaincodeStatus(chaincodeName string) []*pb.ChaincodeStatus {
+	now := time.Now()
+	statuses := []*pb.ChaincodeStatus{}
+	for _, h := range cs.HandlerRegistry.Handlers() {
+		if chaincodeName != "" && ParseName(h.chaincodeID.Name).ChaincodeName != chaincodeName {
+			continue
+		}
+		statuses = append(statuses, h.RuntimeStatus(now, cs.HeartbeatInterval))
+	}
+	return statuses
+}
+
 // createCCMessage creates a transaction message.
 func createCCMessage(messageType pb.ChaincodeMessage_Type, cid string, txid string, cMsg *pb.ChaincodeInput) (*pb.ChaincodeMessage, error) {
 	payload, err := proto.Marshal(cMsg)
@@ -210,7 +305,7 @@ func (cs *ChaincodeSupport) ExecuteLegacyInit(txParams *ccprovider.TransactionPa
 	ccci := ccprovider.DeploymentSpecToChaincodeContainerInfo(spec)
 	ccci.Version = cccid.Version
 
-	err := cs.LaunchInit(ccci)
+	err := cs.LaunchInit(txParams.ChannelID, ccci)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -262,7 +357,7 @@ func processChaincodeExecutionResult(txid, ccName string, resp *pb.ChaincodeMess
 }
 
 func (cs *ChaincodeSupport) InvokeInit(txParams *ccprovider.TransactionParams, cccid *ccprovider.CCContext, input *pb.ChaincodeInput) (*pb.ChaincodeMessage, error) {
-	h, err := cs.Launch(txParams.ChannelID, cccid.Name, cccid.Version)
+	h, err := cs.tracedLaunch(txParams, cccid)
 	if err != nil {
 		return nil, err
 	}
@@ -273,7 +368,7 @@ func (cs *ChaincodeSupport) InvokeInit(txParams *ccprovider.TransactionParams, c
 // Invoke will invoke chaincode and return the message containing the response.
 // The chaincode will be launched if it is not already running.
 func (cs *ChaincodeSupport) Invoke(txParams *ccprovider.TransactionParams, cccid *ccprovider.CCContext, input *pb.ChaincodeInput) (*pb.ChaincodeMessage, error) {
-	h, err := cs.Launch(txParams.ChannelID, cccid.Name, cccid.Version)
+	h, err := cs.tracedLaunch(txParams, cccid)
 	if err != nil {
 		return nil, err
 	}
@@ -293,6 +388,17 @@ func (cs *ChaincodeSupport) Invoke(txParams *ccprovider.TransactionParams, cccid
 	return cs.execute(cctype, txParams, cccid, input, h)
 }
 
+// tracedLaunch launches the chaincode within a child of the span of the
+// transaction, if it is traced.
+func (cs *ChaincodeSupport) tracedLaunch(txParams *ccprovider.TransactionParams, cccid *ccprovider.CCContext) (*Handler, error) {
+	span := txParams.Span.Child("chaincode.Launch")
+	span.SetAttribute("chaincode", cccid.Name+":"+cccid.Version)
+	h, err := cs.Launch(txParams.ChannelID, cccid.Name, cccid.Version)
+	span.SetError(err)
+	span.End()
+	return h, err
+}
+
 // execute executes a transaction and waits for it to complete until a timeout value.
 func (cs *ChaincodeSupport) execute(cctyp pb.ChaincodeMessage_Type, txParams *ccprovider.TransactionParams, cccid *ccprovider.CCContext, input *pb.ChaincodeInput, h *Handler) (*pb.ChaincodeMessage, error) {
 	input.Decorations = txParams.ProposalDecorations
@@ -301,7 +407,12 @@ func (cs *ChaincodeSupport) execute(cctyp pb.ChaincodeMessage_Type, txParams *cc
 		return nil, errors.WithMessage(err, "failed to create chaincode message")
 	}
 
+	span := txParams.Span.Child("chaincode.Execute")
+	span.SetAttribute("chaincode", cccid.Name+":"+cccid.Version)
+	span.SetAttribute("type", cctyp.String())
 	ccresp, err := h.Execute(txParams, cccid, ccMsg, cs.ExecuteTimeout)
+	span.SetError(err)
+	span.End()
 	if err != nil {
 		return nil, errors.WithMessage(err, fmt.Sprintf("error sending"))
 	}
@@ -24,6 +24,7 @@ import (
 	"github.com/hyperledger/fabric/common/crypto/tlsgen"
 	"github.com/hyperledger/fabric/common/flogging"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/metrics"
 	mc "github.com/hyperledger/fabric/common/mocks/config"
 	mocklgr "github.com/hyperledger/fabric/common/mocks/ledger"
 	mockpeer "github.com/hyperledger/fabric/common/mocks/peer"
@@ -45,6 +46,7 @@ import (
 	cmp "github.com/hyperledger/fabric/core/mocks/peer"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/core/policy"
+	"github.com/hyperledger/fabric/core/quota"
 	"github.com/hyperledger/fabric/core/scc"
 	"github.com/hyperledger/fabric/core/scc/lscc"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
@@ -193,6 +195,7 @@ func initMockPeer(chainIDs ...string) (*ChaincodeSupport, error) {
 		sccp,
 		pr,
 		peer.DefaultSupport,
+		nil,
 	)
 	ipRegistry.ChaincodeSupport = chaincodeSupport
 
@@ -1165,6 +1168,136 @@ func TestStartAndWaitLaunchError(t *testing.T) {
 	assert.EqualError(t, err, "error starting container: Bad lunch; upset stomach")
 }
 
+func TestLaunchContainerQuota(t *testing.T) {
+	handlerRegistry := NewHandlerRegistry(false)
+	fakeRuntime := &mock.Runtime{}
+	fakeRuntime.StartStub = func(ccci *ccprovider.ChaincodeContainerInfo, _ []byte) error {
+		cname := ccci.Name + ":" + ccci.Version
+		handlerRegistry.Register(&Handler{chaincodeID: &pb.ChaincodeID{Name: cname}})
+		handlerRegistry.Ready(cname)
+		return nil
+	}
+	fakePackageProvider := &mock.PackageProvider{}
+	fakePackageProvider.GetChaincodeCodePackageReturns(getTarGZ(t, "src/dummy/dummy.go", []byte("code")), nil)
+	fakeLifecycle := &mock.Lifecycle{}
+	fakeLifecycle.ChaincodeContainerInfoStub = func(_, chaincodeName string) (*ccprovider.ChaincodeContainerInfo, error) {
+		return &ccprovider.ChaincodeContainerInfo{Type: "GOLANG", Name: chaincodeName, Version: "0", ContainerType: "DOCKER"}, nil
+	}
+	quotaManager := quota.NewManager(quota.Config{Default: quota.Limits{MaxChaincodeContainers: 1}}, nil)
+
+	cs := &ChaincodeSupport{
+		HandlerRegistry: handlerRegistry,
+		Lifecycle:       fakeLifecycle,
+		Launcher: &RuntimeLauncher{
+			Runtime:         fakeRuntime,
+			Registry:        handlerRegistry,
+			StartupTimeout:  10 * time.Second,
+			PackageProvider: fakePackageProvider,
+		},
+		ContainerQuota: quotaManager,
+	}
+
+	_, err := cs.Launch("channel1", "cc1", "0")
+	assert.NoError(t, err)
+	_, err = cs.Launch("channel1", "cc2", "0")
+	assert.EqualError(t, err, "[channel channel1] could not launch chaincode cc2:0: channel channel1 has reached its quota of 1 chaincode containers")
+	// the running containers are not accounted again
+	_, err = cs.Launch("channel1", "cc1", "0")
+	assert.NoError(t, err)
+	_, err = cs.Launch("channel2", "cc2", "0")
+	assert.NoError(t, err)
+
+	// the slot of a container which failed to launch is freed
+	fakeRuntime.StartReturns(errors.New("Bad lunch; upset stomach"))
+	fakeRuntime.StartStub = nil
+	_, err = cs.Launch("channel3", "cc3", "0")
+	assert.EqualError(t, err, "[channel channel3] could not launch chaincode cc3:0: error starting container: Bad lunch; upset stomach")
+	_, err = cs.Launch("channel3", "cc4", "0")
+	assert.EqualError(t, err, "[channel channel3] could not launch chaincode cc4:0: error starting container: Bad lunch; upset stomach")
+}
+
+func TestLaunchRestarts(t *testing.T) {
+	handlerRegistry := NewHandlerRegistry(false)
+	fakeRuntime := &mock.Runtime{}
+	fakeRuntime.StartStub = func(ccci *ccprovider.ChaincodeContainerInfo, _ []byte) error {
+		cname := ccci.Name + ":" + ccci.Version
+		handlerRegistry.Register(&Handler{chaincodeID: &pb.ChaincodeID{Name: cname}, TXContexts: NewTransactionContexts()})
+		handlerRegistry.Ready(cname)
+		return nil
+	}
+	fakePackageProvider := &mock.PackageProvider{}
+	fakePackageProvider.GetChaincodeCodePackageReturns(getTarGZ(t, "src/dummy/dummy.go", []byte("code")), nil)
+	fakeLifecycle := &mock.Lifecycle{}
+	fakeLifecycle.ChaincodeContainerInfoStub = func(_, chaincodeName string) (*ccprovider.ChaincodeContainerInfo, error) {
+		return &ccprovider.ChaincodeContainerInfo{Type: "GOLANG", Name: chaincodeName, Version: "0", ContainerType: "DOCKER"}, nil
+	}
+
+	cs := &ChaincodeSupport{
+		HandlerRegistry: handlerRegistry,
+		Lifecycle:       fakeLifecycle,
+		Runtime:         fakeRuntime,
+		Launcher: &RuntimeLauncher{
+			Runtime:         fakeRuntime,
+			Registry:        handlerRegistry,
+			StartupTimeout:  10 * time.Second,
+			PackageProvider: fakePackageProvider,
+		},
+	}
+	cs.Restarter = NewRestarter(RestartPolicy{MaxRestarts: 1, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, StableTime: time.Hour, CircuitBreakTime: time.Hour}, cs.launch, nil)
+
+	_, err := cs.Launch("channel1", "cc1", "0")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, fakeRuntime.StartCallCount())
+
+	// the container which exits is restarted
+	handlerRegistry.Deregister("cc1:0")
+	cs.Restarter.Exited("cc1:0")
+	gt := NewGomegaWithT(t)
+	gt.Eventually(fakeRuntime.StartCallCount).Should(Equal(2))
+	gt.Eventually(func() *Handler { return handlerRegistry.Handler("cc1:0") }).ShouldNot(BeNil())
+
+	// the container which keeps exiting opens its circuit breaker
+	handlerRegistry.Deregister("cc1:0")
+	cs.Restarter.Exited("cc1:0")
+	_, err = cs.Launch("channel1", "cc1", "0")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "[channel channel1] could not launch chaincode cc1:0: chaincode cc1:0 exited 1 times in a row, its launches are suspended until")
+	assert.Equal(t, 2, fakeRuntime.StartCallCount())
+
+	// the chaincodes stopped by the peer are launched again on demand
+	assert.NoError(t, cs.Stop(&ccprovider.ChaincodeContainerInfo{Name: "cc1", Version: "0"}))
+	_, err = cs.Launch("channel1", "cc1", "0")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, fakeRuntime.StartCallCount())
+}
+
+func TestChaincodeStatus(t *testing.T) {
+	handlerRegistry := NewHandlerRegistry(true)
+	now := time.Now()
+	for _, cname := range []string{"cc2:1.0", "cc1:1.0", "cc1:2.0"} {
+		h := &Handler{chaincodeID: &pb.ChaincodeID{Name: cname}, runtimeStatus: newRuntimeStatus(now, metrics.NewNoOpScope())}
+		assert.NoError(t, handlerRegistry.Register(h))
+	}
+	handlerRegistry.Handler("cc1:2.0").runtimeStatus.record(now, &pb.ChaincodeRuntimeStats{Goroutines: 5})
+	handlerRegistry.Handler("cc2:1.0").runtimeStatus.registered = now.Add(-time.Hour)
+
+	cs := &ChaincodeSupport{HandlerRegistry: handlerRegistry, HeartbeatInterval: time.Minute}
+	statuses := cs.ChaincodeStatus("")
+	assert.Len(t, statuses, 3)
+	assert.Equal(t, "cc1:1.0", statuses[0].Name)
+	assert.True(t, statuses[0].Responsive)
+	assert.Equal(t, "cc1:2.0", statuses[1].Name)
+	assert.Equal(t, int32(5), statuses[1].Stats.Goroutines)
+	assert.Equal(t, "cc2:1.0", statuses[2].Name)
+	assert.False(t, statuses[2].Responsive)
+
+	statuses = cs.ChaincodeStatus("cc1")
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, "cc1:1.0", statuses[0].Name)
+	assert.Equal(t, "cc1:2.0", statuses[1].Name)
+	assert.Empty(t, cs.ChaincodeStatus("cc3"))
+}
+
 func TestGetTxContextFromHandler(t *testing.T) {
 	h := Handler{TXContexts: NewTransactionContexts(), SystemCCProvider: &scc.Provider{Peer: peer.Default, PeerSupport: peer.DefaultSupport, Registrar: inproccontroller.NewRegistry()}}
 
@@ -19,6 +19,11 @@ import (
 const (
 	defaultExecutionTimeout = 30 * time.Second
 	minimumStartupTimeout   = 5 * time.Second
+
+	defaultRestartInitialBackoff   = time.Second
+	defaultRestartMaxBackoff       = time.Minute
+	defaultRestartStableTime       = 5 * time.Minute
+	defaultRestartCircuitBreakTime = 5 * time.Minute
 )
 
 type Config struct {
@@ -29,6 +34,17 @@ type Config struct {
 	LogFormat      string
 	LogLevel       string
 	ShimLogLevel   string
+	// MaxConcurrentRequests is the maximum number of transactions executed
+	// concurrently by a chaincode container, zero meaning no limit
+	MaxConcurrentRequests int
+	// QueryLimits bounds the resources used by the queries of chaincodes
+	QueryLimits QueryLimits
+	// HeartbeatInterval is the interval between the heartbeats sent by the
+	// chaincode containers, zero disabling them
+	HeartbeatInterval time.Duration
+	// RestartPolicy configures the restart of the chaincode containers
+	// which exit unexpectedly
+	RestartPolicy RestartPolicy
 }
 
 func GlobalConfig() *Config {
@@ -55,6 +71,26 @@ func (c *Config) load() {
 		c.StartupTimeout = minimumStartupTimeout
 	}
 
+	c.MaxConcurrentRequests = viper.GetInt("chaincode.maxConcurrentRequests")
+	if c.MaxConcurrentRequests < 0 {
+		c.MaxConcurrentRequests = 0
+	}
+
+	c.QueryLimits.ExecutionTimeout = viper.GetDuration("chaincode.queryLimits.executionTimeout")
+	c.QueryLimits.TotalResults = viper.GetInt("chaincode.queryLimits.totalResults")
+	c.QueryLimits.ResponseBytes = viper.GetInt("chaincode.queryLimits.responseBytes")
+
+	c.HeartbeatInterval = viper.GetDuration("chaincode.heartbeat.interval")
+	if c.HeartbeatInterval < 0 {
+		c.HeartbeatInterval = 0
+	}
+
+	c.RestartPolicy.MaxRestarts = viper.GetInt("chaincode.restart.maxRestarts")
+	c.RestartPolicy.InitialBackoff = durationOrDefault("chaincode.restart.initialBackoff", defaultRestartInitialBackoff)
+	c.RestartPolicy.MaxBackoff = durationOrDefault("chaincode.restart.maxBackoff", defaultRestartMaxBackoff)
+	c.RestartPolicy.StableTime = durationOrDefault("chaincode.restart.stableTime", defaultRestartStableTime)
+	c.RestartPolicy.CircuitBreakTime = durationOrDefault("chaincode.restart.circuitBreakTime", defaultRestartCircuitBreakTime)
+
 	c.LogFormat = viper.GetString("chaincode.logging.format")
 	c.LogLevel = getLogLevelFromViper("chaincode.logging.level")
 	c.ShimLogLevel = getLogLevelFromViper("chaincode.logging.shim")
@@ -69,6 +105,15 @@ func toSeconds(s string, def int) time.Duration {
 	return time.Duration(seconds) * time.Second
 }
 
+// durationOrDefault returns the positive duration set in viper for the key, or
+// the default one
+func durationOrDefault(key string, def time.Duration) time.Duration {
+	if d := viper.GetDuration(key); d > 0 {
+		return d
+	}
+	return def
+}
+
 // getLogLevelFromViper gets the chaincode container log levels from viper
 func getLogLevelFromViper(key string) string {
 	levelString := viper.GetString(key)
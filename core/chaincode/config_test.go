@@ -36,6 +36,16 @@ var _ = Describe("Config", func() {
 			viper.Set("chaincode.logging.format", "test-chaincode-logging-format")
 			viper.Set("chaincode.logging.level", "WARNING")
 			viper.Set("chaincode.logging.shim", "WARNING")
+			viper.Set("chaincode.maxConcurrentRequests", "8")
+			viper.Set("chaincode.queryLimits.executionTimeout", "10s")
+			viper.Set("chaincode.queryLimits.totalResults", "1000")
+			viper.Set("chaincode.queryLimits.responseBytes", "1048576")
+			viper.Set("chaincode.heartbeat.interval", "15s")
+			viper.Set("chaincode.restart.maxRestarts", "3")
+			viper.Set("chaincode.restart.initialBackoff", "2s")
+			viper.Set("chaincode.restart.maxBackoff", "30s")
+			viper.Set("chaincode.restart.stableTime", "10m")
+			viper.Set("chaincode.restart.circuitBreakTime", "1h")
 
 			config := chaincode.GlobalConfig()
 			Expect(config.TLSEnabled).To(BeTrue())
@@ -45,6 +55,43 @@ var _ = Describe("Config", func() {
 			Expect(config.LogFormat).To(Equal("test-chaincode-logging-format"))
 			Expect(config.LogLevel).To(Equal("WARNING"))
 			Expect(config.ShimLogLevel).To(Equal("WARNING"))
+			Expect(config.MaxConcurrentRequests).To(Equal(8))
+			Expect(config.QueryLimits).To(Equal(chaincode.QueryLimits{
+				ExecutionTimeout: 10 * time.Second,
+				TotalResults:     1000,
+				ResponseBytes:    1048576,
+			}))
+			Expect(config.HeartbeatInterval).To(Equal(15 * time.Second))
+			Expect(config.RestartPolicy).To(Equal(chaincode.RestartPolicy{
+				MaxRestarts:      3,
+				InitialBackoff:   2 * time.Second,
+				MaxBackoff:       30 * time.Second,
+				StableTime:       10 * time.Minute,
+				CircuitBreakTime: time.Hour,
+			}))
+		})
+
+		Context("when the restart delays are not configured", func() {
+			It("falls back to the default delays", func() {
+				config := chaincode.GlobalConfig()
+				Expect(config.RestartPolicy).To(Equal(chaincode.RestartPolicy{
+					InitialBackoff:   time.Second,
+					MaxBackoff:       time.Minute,
+					StableTime:       5 * time.Minute,
+					CircuitBreakTime: 5 * time.Minute,
+				}))
+			})
+		})
+
+		Context("when a negative concurrent request limit is configured", func() {
+			BeforeEach(func() {
+				viper.Set("chaincode.maxConcurrentRequests", "-1")
+			})
+
+			It("falls back to no limit", func() {
+				config := chaincode.GlobalConfig()
+				Expect(config.MaxConcurrentRequests).To(Equal(0))
+			})
 		})
 
 		Context("when an invalid keepalive is configured", func() {
@@ -120,6 +167,20 @@ func capture() (restore func()) {
 		"chaincode.logging.format": viper.GetString("chaincode.logging.format"),
 		"chaincode.logging.level":  viper.GetString("chaincode.logging.level"),
 		"chaincode.logging.shim":   viper.GetString("chaincode.logging.shim"),
+
+		"chaincode.maxConcurrentRequests": viper.GetString("chaincode.maxConcurrentRequests"),
+
+		"chaincode.queryLimits.executionTimeout": viper.GetString("chaincode.queryLimits.executionTimeout"),
+		"chaincode.queryLimits.totalResults":     viper.GetString("chaincode.queryLimits.totalResults"),
+		"chaincode.queryLimits.responseBytes":    viper.GetString("chaincode.queryLimits.responseBytes"),
+
+		"chaincode.heartbeat.interval": viper.GetString("chaincode.heartbeat.interval"),
+
+		"chaincode.restart.maxRestarts":      viper.GetString("chaincode.restart.maxRestarts"),
+		"chaincode.restart.initialBackoff":   viper.GetString("chaincode.restart.initialBackoff"),
+		"chaincode.restart.maxBackoff":       viper.GetString("chaincode.restart.maxBackoff"),
+		"chaincode.restart.stableTime":       viper.GetString("chaincode.restart.stableTime"),
+		"chaincode.restart.circuitBreakTime": viper.GetString("chaincode.restart.circuitBreakTime"),
 	}
 
 	return func() {
@@ -29,7 +29,8 @@ type Processor interface {
 // CertGenerator generates client certificates for chaincode.
 type CertGenerator interface {
 	// Generate returns a certificate and private key and associates
-	// the hash of the certificates with the given chaincode name
+	// the hash of the certificates with the given chaincode name,
+	// revoking the certificate of the previous launch of the chaincode
 	Generate(ccName string) (*accesscontrol.CertAndPrivKeyPair, error)
 }
 
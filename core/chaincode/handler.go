@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package chaincode
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"strings"
@@ -14,9 +15,12 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/crypto/pedersen"
 	"github.com/hyperledger/fabric/common/flogging"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/core/aclmgmt/resources"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
@@ -24,6 +28,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
 )
 
@@ -145,6 +150,11 @@ type Handler struct {
 	UUIDGenerator UUIDGenerator
 	// AppConfig is used to retrieve the application config for a channel
 	AppConfig ApplicationConfigRetriever
+	// MaxConcurrentRequests is the maximum number of transactions executed
+	// concurrently by the chaincode, zero meaning no limit
+	MaxConcurrentRequests int
+	// Metrics is the scope of the metrics of the chaincode
+	Metrics metrics.Scope
 
 	// state holds the current handler state. It will be created, established, or
 	// ready.
@@ -162,6 +172,11 @@ type Handler struct {
 	chatStream ccintf.ChaincodeStream
 	// errChan is used to communicate errors from the async send to the receive loop
 	errChan chan error
+	// limiter bounds the number of concurrent transactions when
+	// MaxConcurrentRequests is set
+	limiter *requestLimiter
+	// runtimeStatus records the heartbeats of the chaincode once registered
+	runtimeStatus *runtimeStatus
 }
 
 // handleMessage is called by ProcessStream to dispatch messages.
@@ -171,6 +186,10 @@ func (h *Handler) handleMessage(msg *pb.ChaincodeMessage) error {
 	if msg.Type == pb.ChaincodeMessage_KEEPALIVE {
 		return nil
 	}
+	if msg.Type == pb.ChaincodeMessage_HEARTBEAT {
+		h.HandleHeartbeat(msg)
+		return nil
+	}
 
 	switch h.state {
 	case Created:
@@ -221,6 +240,12 @@ func (h *Handler) handleMessageReadyState(msg *pb.ChaincodeMessage) error {
 		go h.HandleTransaction(msg, h.HandleGetStateMetadata)
 	case pb.ChaincodeMessage_PUT_STATE_METADATA:
 		go h.HandleTransaction(msg, h.HandlePutStateMetadata)
+	case pb.ChaincodeMessage_GET_PRIVATE_DATA_HASH:
+		go h.HandleTransaction(msg, h.HandleGetPrivateDataHash)
+	case pb.ChaincodeMessage_GET_STATE_MULTIPLE:
+		go h.HandleTransaction(msg, h.HandleGetStateMultiple)
+	case pb.ChaincodeMessage_VERIFY_PRIVATE_DATA_HASH:
+		go h.HandleTransaction(msg, h.HandleVerifyPrivateDataHash)
 	default:
 		return fmt.Errorf("[%s] Fabric side handler cannot handle message (%s) while in ready state", msg.Txid, msg.Type)
 	}
@@ -470,6 +495,29 @@ func (h *Handler) notifyRegistry(err error) {
 	h.Registry.Ready(h.chaincodeID.Name)
 }
 
+// HandleHeartbeat records the runtime stats sent periodically by the
+// chaincode. The heartbeats received before the registration are ignored.
+func (h *Handler) HandleHeartbeat(msg *pb.ChaincodeMessage) {
+	if h.runtimeStatus == nil {
+		chaincodeLogger.Debugf("Ignoring %s received in state %s", msg.Type, h.state)
+		return
+	}
+	stats := &pb.ChaincodeRuntimeStats{}
+	if err := proto.Unmarshal(msg.Payload, stats); err != nil {
+		chaincodeLogger.Warningf("Error in received %s from chaincode %s, could NOT unmarshal runtime stats: %s", msg.Type, h.chaincodeID.Name, err)
+		return
+	}
+	h.runtimeStatus.record(time.Now(), stats)
+}
+
+// RuntimeStatus returns the status of the chaincode at the given time
+func (h *Handler) RuntimeStatus(now time.Time, heartbeatInterval time.Duration) *pb.ChaincodeStatus {
+	if h.runtimeStatus == nil {
+		return &pb.ChaincodeStatus{Name: h.chaincodeID.Name, Responsive: true}
+	}
+	return h.runtimeStatus.status(h.chaincodeID.Name, now, heartbeatInterval)
+}
+
 // handleRegister is invoked when chaincode tries to register.
 func (h *Handler) HandleRegister(msg *pb.ChaincodeMessage) {
 	chaincodeLogger.Debugf("Received %s in state %s", msg.Type, h.state)
@@ -480,8 +528,15 @@ func (h *Handler) HandleRegister(msg *pb.ChaincodeMessage) {
 		return
 	}
 
+	scope := h.Metrics
+	if scope == nil {
+		scope = metrics.NewNoOpScope()
+	}
+	scope = scope.Tagged(map[string]string{"chaincode": chaincodeID.Name})
+
 	// Now register with the chaincodeSupport
 	h.chaincodeID = chaincodeID
+	h.runtimeStatus = newRuntimeStatus(time.Now(), scope)
 	err = h.Registry.Register(h)
 	if err != nil {
 		h.notifyRegistry(err)
@@ -492,6 +547,10 @@ func (h *Handler) HandleRegister(msg *pb.ChaincodeMessage) {
 	// name in keys
 	h.ccInstance = ParseName(h.chaincodeID.Name)
 
+	if h.MaxConcurrentRequests > 0 {
+		h.limiter = newRequestLimiter(h.MaxConcurrentRequests, scope)
+	}
+
 	chaincodeLogger.Debugf("Got %s for chaincodeID = %s, sending back %s", pb.ChaincodeMessage_REGISTER, chaincodeID, pb.ChaincodeMessage_REGISTERED)
 	if err := h.serialSend(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_REGISTERED}); err != nil {
 		chaincodeLogger.Errorf("error sending %s: %s", pb.ChaincodeMessage_REGISTERED, err)
@@ -571,9 +630,14 @@ func (h *Handler) HandleGetState(msg *pb.ChaincodeMessage, txContext *Transactio
 	chaincodeLogger.Debugf("[%s] getting state for chaincode %s, key %s, channel %s", shorttxid(msg.Txid), chaincodeName, getState.Key, txContext.ChainID)
 
 	var res []byte
-	if isCollectionSet(getState.Collection) {
+	switch {
+	case getState.Unvalidated && !isCollectionSet(getState.Collection):
+		return nil, errors.New("unvalidated reads are only supported on private data")
+	case getState.Unvalidated:
+		res, err = txContext.TXSimulator.GetPrivateDataUnvalidated(chaincodeName, getState.Collection, getState.Key)
+	case isCollectionSet(getState.Collection):
 		res, err = txContext.TXSimulator.GetPrivateData(chaincodeName, getState.Collection, getState.Key)
-	} else {
+	default:
 		res, err = txContext.TXSimulator.GetState(chaincodeName, getState.Key)
 	}
 	if err != nil {
@@ -587,6 +651,115 @@ func (h *Handler) HandleGetState(msg *pb.ChaincodeMessage, txContext *Transactio
 	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: res, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
 }
 
+// Handles query to ledger to get the values of several keys in a single request
+func (h *Handler) HandleGetStateMultiple(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
+	getStateMultiple := &pb.GetStateMultiple{}
+	err := proto.Unmarshal(msg.Payload, getStateMultiple)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal failed")
+	}
+
+	chaincodeName := h.ChaincodeName()
+	chaincodeLogger.Debugf("[%s] getting state for chaincode %s, %d keys, channel %s", shorttxid(msg.Txid), chaincodeName, len(getStateMultiple.Keys), txContext.ChainID)
+
+	var res [][]byte
+	if isCollectionSet(getStateMultiple.Collection) {
+		res, err = txContext.TXSimulator.GetPrivateDataMultipleKeys(chaincodeName, getStateMultiple.Collection, getStateMultiple.Keys)
+	} else {
+		res, err = txContext.TXSimulator.GetStateMultipleKeys(chaincodeName, getStateMultiple.Keys)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	payloadBytes, err := proto.Marshal(&pb.GetStateMultipleResult{Values: res})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal failed")
+	}
+
+	// Send response msg back to chaincode. GetState will not trigger event
+	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: payloadBytes, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
+}
+
+// Handles query to ledger to get the hash of a private data value. Unlike
+// GetState on a collection, this does not require the peer to be a member of
+// the collection, as the hashes of all private data are part of the ledger.
+func (h *Handler) HandleGetPrivateDataHash(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
+	getState := &pb.GetState{}
+	err := proto.Unmarshal(msg.Payload, getState)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal failed")
+	}
+
+	if !isCollectionSet(getState.Collection) {
+		return nil, errors.New("collection must not be empty")
+	}
+
+	chaincodeName := h.ChaincodeName()
+	chaincodeLogger.Debugf("[%s] getting private data hash for chaincode %s, collection %s, key %s, channel %s", shorttxid(msg.Txid), chaincodeName, getState.Collection, getState.Key, txContext.ChainID)
+
+	res, err := txContext.TXSimulator.GetPrivateDataHash(chaincodeName, getState.Collection, getState.Key)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if res == nil {
+		chaincodeLogger.Debugf("[%s] No private data hash associated with key: %s. Sending %s with an empty payload", shorttxid(msg.Txid), getState.Key, pb.ChaincodeMessage_RESPONSE)
+	}
+
+	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: res, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
+}
+
+// Handles the verification of a preimage against the hash of a private data
+// value. The preimage is hashed with the write-set hashing algorithm of the
+// channel, or opens the Pedersen commitment to the value, with the blinding
+// factor supplied by the client in the transient field of the proposal, for the
+// collections committing to their values with Pedersen commitments.
+func (h *Handler) HandleVerifyPrivateDataHash(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
+	verify := &pb.VerifyPrivateDataHash{}
+	err := proto.Unmarshal(msg.Payload, verify)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal failed")
+	}
+
+	if !isCollectionSet(verify.Collection) {
+		return nil, errors.New("collection must not be empty")
+	}
+
+	chaincodeName := h.ChaincodeName()
+	chaincodeLogger.Debugf("[%s] verifying private data hash for chaincode %s, collection %s, key %s, channel %s", shorttxid(msg.Txid), chaincodeName, verify.Collection, verify.Key, txContext.ChainID)
+
+	hash, err := txContext.TXSimulator.GetPrivateDataHash(chaincodeName, verify.Collection, verify.Key)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if hash == nil {
+		return nil, errors.Errorf("key %s does not exist in collection %s", verify.Key, verify.Collection)
+	}
+	if len(hash) == pedersen.CommitmentSize {
+		blindingFactor, err := transientBlindingFactor(txContext, chaincodeName, verify.Collection, verify.Key)
+		if err != nil {
+			return nil, err
+		}
+		if blindingFactor == nil {
+			return nil, errors.Errorf("the blinding factor of the commitment of key %s in collection %s is missing from the transient field", verify.Key, verify.Collection)
+		}
+		if !pedersen.Verify(hash, verify.Preimage, blindingFactor) {
+			return nil, errors.Errorf("the preimage does not match the commitment of key %s in collection %s", verify.Key, verify.Collection)
+		}
+		return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
+	}
+
+	ac, exists := h.AppConfig.GetApplicationConfig(msg.ChannelId)
+	if !exists {
+		return nil, errors.Errorf("application config does not exist for %s", msg.ChannelId)
+	}
+	if !bytes.Equal(ac.RWSetHashingAlgorithm()(verify.Preimage), hash) {
+		return nil, errors.Errorf("the preimage does not match the hash of key %s in collection %s", verify.Key, verify.Collection)
+	}
+
+	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
+}
+
 // Handles query to ledger to get state metadata
 func (h *Handler) HandleGetStateMetadata(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
 	err := h.checkMetadataCap(msg)
@@ -841,6 +1014,20 @@ func isCollectionSet(collection string) bool {
 	return collection != ""
 }
 
+// transientBlindingFactor returns the blinding factor of the Pedersen commitment
+// to the value of a key, supplied by the client in the transient field of the
+// proposal, or nil if there is none
+func transientBlindingFactor(txContext *TransactionContext, chaincodeName, collection, key string) ([]byte, error) {
+	if txContext.Proposal == nil {
+		return nil, nil
+	}
+	cpp, err := utils.GetChaincodeProposalPayload(txContext.Proposal.Payload)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get the transient field of the proposal")
+	}
+	return cpp.TransientMap[pedersen.TransientKey(chaincodeName, collection, key)], nil
+}
+
 func isMetadataSetForPagination(metadata *pb.QueryMetadata) bool {
 	if metadata == nil {
 		return false
@@ -936,7 +1123,16 @@ func (h *Handler) HandlePutState(msg *pb.ChaincodeMessage, txContext *Transactio
 
 	chaincodeName := h.ChaincodeName()
 	if isCollectionSet(putState.Collection) {
-		err = txContext.TXSimulator.SetPrivateData(chaincodeName, putState.Collection, putState.Key, putState.Value)
+		var blindingFactor []byte
+		blindingFactor, err = transientBlindingFactor(txContext, chaincodeName, putState.Collection, putState.Key)
+		if err != nil {
+			return nil, err
+		}
+		if blindingFactor != nil {
+			err = txContext.TXSimulator.SetPrivateDataWithBlindingFactor(chaincodeName, putState.Collection, putState.Key, putState.Value, blindingFactor)
+		} else {
+			err = txContext.TXSimulator.SetPrivateData(chaincodeName, putState.Collection, putState.Key, putState.Value)
+		}
 	} else {
 		err = txContext.TXSimulator.SetState(chaincodeName, putState.Key, putState.Value)
 	}
@@ -1039,6 +1235,7 @@ func (h *Handler) HandleInvokeChaincode(msg *pb.ChaincodeMessage, txContext *Tra
 		Proposal:             txContext.Proposal,
 		TXSimulator:          txContext.TXSimulator,
 		HistoryQueryExecutor: txContext.HistoryQueryExecutor,
+		Context:              txContext.Context,
 	}
 
 	if targetInstance.ChainID != txContext.ChainID {
@@ -1108,6 +1305,16 @@ func (h *Handler) Execute(txParams *ccprovider.TransactionParams, cccid *ccprovi
 	chaincodeLogger.Debugf("Entry")
 	defer chaincodeLogger.Debugf("Exit")
 
+	if h.limiter != nil {
+		start := time.Now()
+		if err := h.limiter.acquire(msg.ChannelId, msg.Txid, timeout); err != nil {
+			return nil, err
+		}
+		defer h.limiter.release(msg.Txid)
+		// the time spent waiting counts towards the execution timeout
+		timeout -= time.Since(start)
+	}
+
 	txctx, err := h.TXContexts.Create(txParams)
 	if err != nil {
 		return nil, err
@@ -1118,15 +1325,34 @@ func (h *Handler) Execute(txParams *ccprovider.TransactionParams, cccid *ccprovi
 		return nil, err
 	}
 
+	// the execution ends at the deadline of the proposal if it comes before
+	// the timeout, and the chaincode is told when it ends
+	deadline := time.Now().Add(timeout)
+	var aborted <-chan struct{}
+	if txParams.Context != nil {
+		if d, ok := txParams.Context.Deadline(); ok && d.Before(deadline) {
+			deadline = d
+		}
+		aborted = txParams.Context.Done()
+	}
+	if ts, err := ptypes.TimestampProto(deadline); err == nil {
+		msg.Deadline = ts
+	}
+
 	h.serialSendAsync(msg)
 
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
 	var ccresp *pb.ChaincodeMessage
 	select {
 	case ccresp = <-txctx.ResponseNotifier:
 		// response is sent to user or calling chaincode. ChaincodeMessage_ERROR
 		// are typically treated as error
-	case <-time.After(timeout):
+	case <-timer.C:
 		err = errors.New("timeout expired while executing transaction")
+	case <-aborted:
+		err = errors.Wrap(txParams.Context.Err(), "execution of transaction aborted")
 	}
 
 	return ccresp, err
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package chaincode
 
 import (
+	"sort"
 	"sync"
 
 	"github.com/pkg/errors"
@@ -121,6 +122,24 @@ func (r *HandlerRegistry) Handler(cname string) *Handler {
 	return h
 }
 
+// Handlers returns the handlers of the registered chaincodes, sorted by the
+// name of the chaincode instance
+func (r *HandlerRegistry) Handlers() []*Handler {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var cnames []string
+	for cname := range r.handlers {
+		cnames = append(cnames, cname)
+	}
+	sort.Strings(cnames)
+	handlers := make([]*Handler, 0, len(cnames))
+	for _, cname := range cnames {
+		handlers = append(handlers, r.handlers[cname])
+	}
+	return handlers
+}
+
 // Register adds a chaincode handler to the registry.
 // An error will be returned if a handler is already registered for the
 // chaincode. An error will also be returned if the chaincode has not already
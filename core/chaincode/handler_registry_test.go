@@ -148,6 +148,26 @@ var _ = Describe("HandlerRegistry", func() {
 		})
 	})
 
+	Describe("Handlers", func() {
+		It("returns the registered handlers sorted by name", func() {
+			other := &chaincode.Handler{}
+			chaincode.SetHandlerChaincodeID(other, &pb.ChaincodeID{Name: "another-chaincode-name"})
+			Expect(hr.Register(handler)).To(Succeed())
+			Expect(hr.Register(other)).To(Succeed())
+
+			handlers := hr.Handlers()
+			Expect(handlers).To(HaveLen(2))
+			Expect(handlers[0]).To(BeIdenticalTo(other))
+			Expect(handlers[1]).To(BeIdenticalTo(handler))
+		})
+
+		Context("when no handler has been registered", func() {
+			It("returns no handler", func() {
+				Expect(hr.Handlers()).To(BeEmpty())
+			})
+		})
+	})
+
 	Describe("Register", func() {
 		Context("when unsolicited registration is disallowed", func() {
 			BeforeEach(func() {
@@ -7,10 +7,13 @@ SPDX-License-Identifier: Apache-2.0
 package chaincode_test
 
 import (
+	"context"
 	"io"
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hyperledger/fabric/common/crypto/pedersen"
 	"github.com/hyperledger/fabric/common/mocks/config"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/aclmgmt/resources"
@@ -20,6 +23,7 @@ import (
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
@@ -486,6 +490,43 @@ var _ = Describe("Handler", func() {
 					Expect(err).To(MatchError("godzilla"))
 				})
 			})
+
+			Context("when the client supplies a blinding factor for the key", func() {
+				BeforeEach(func() {
+					txContext.Proposal = &pb.Proposal{
+						Payload: utils.MarshalOrPanic(&pb.ChaincodeProposalPayload{
+							TransientMap: map[string][]byte{
+								pedersen.TransientKey("cc-instance-name", "collection-name", "put-state-key"): []byte("blinding-factor"),
+							},
+						}),
+					}
+				})
+
+				It("calls SetPrivateDataWithBlindingFactor on the transaction simulator", func() {
+					_, err := handler.HandlePutState(incomingMessage, txContext)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeTxSimulator.SetPrivateDataCallCount()).To(Equal(0))
+					Expect(fakeTxSimulator.SetPrivateDataWithBlindingFactorCallCount()).To(Equal(1))
+					ccname, collection, key, value, blindingFactor := fakeTxSimulator.SetPrivateDataWithBlindingFactorArgsForCall(0)
+					Expect(ccname).To(Equal("cc-instance-name"))
+					Expect(collection).To(Equal("collection-name"))
+					Expect(key).To(Equal("put-state-key"))
+					Expect(value).To(Equal([]byte("put-state-value")))
+					Expect(blindingFactor).To(Equal([]byte("blinding-factor")))
+				})
+			})
+
+			Context("when the proposal payload is malformed", func() {
+				BeforeEach(func() {
+					txContext.Proposal = &pb.Proposal{Payload: []byte("this-is-a-bogus-payload")}
+				})
+
+				It("returns an error", func() {
+					_, err := handler.HandlePutState(incomingMessage, txContext)
+					Expect(err).To(MatchError(ContainSubstring("failed to get the transient field of the proposal")))
+				})
+			})
 		})
 	})
 
@@ -807,6 +848,46 @@ var _ = Describe("Handler", func() {
 			})
 		})
 
+		Context("when the read of a collection is unvalidated", func() {
+			BeforeEach(func() {
+				request.Collection = "collection-name"
+				request.Unvalidated = true
+				payload, err := proto.Marshal(request)
+				Expect(err).NotTo(HaveOccurred())
+				incomingMessage.Payload = payload
+
+				fakeTxSimulator.GetPrivateDataUnvalidatedReturns([]byte("get-private-data-unvalidated-response"), nil)
+			})
+
+			It("calls GetPrivateDataUnvalidated on the transaction simulator", func() {
+				resp, err := handler.HandleGetState(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.Payload).To(Equal([]byte("get-private-data-unvalidated-response")))
+
+				Expect(fakeTxSimulator.GetPrivateDataCallCount()).To(Equal(0))
+				Expect(fakeTxSimulator.GetPrivateDataUnvalidatedCallCount()).To(Equal(1))
+				ccname, collection, key := fakeTxSimulator.GetPrivateDataUnvalidatedArgsForCall(0)
+				Expect(ccname).To(Equal("cc-instance-name"))
+				Expect(collection).To(Equal("collection-name"))
+				Expect(key).To(Equal("get-state-key"))
+			})
+
+			Context("and the collection is not set", func() {
+				BeforeEach(func() {
+					request.Collection = ""
+					payload, err := proto.Marshal(request)
+					Expect(err).NotTo(HaveOccurred())
+					incomingMessage.Payload = payload
+				})
+
+				It("returns an error", func() {
+					_, err := handler.HandleGetState(incomingMessage, txContext)
+					Expect(err).To(MatchError("unvalidated reads are only supported on private data"))
+					Expect(fakeTxSimulator.GetStateCallCount()).To(Equal(0))
+				})
+			})
+		})
+
 		Context("when collection is not set", func() {
 			BeforeEach(func() {
 				fakeTxSimulator.GetStateReturns([]byte("get-state-response"), nil)
@@ -847,6 +928,332 @@ var _ = Describe("Handler", func() {
 		})
 	})
 
+	Describe("HandleGetPrivateDataHash", func() {
+		var (
+			incomingMessage *pb.ChaincodeMessage
+			request         *pb.GetState
+		)
+
+		BeforeEach(func() {
+			request = &pb.GetState{
+				Collection: "collection-name",
+				Key:        "get-state-key",
+			}
+			payload, err := proto.Marshal(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			incomingMessage = &pb.ChaincodeMessage{
+				Type:      pb.ChaincodeMessage_GET_PRIVATE_DATA_HASH,
+				Payload:   payload,
+				Txid:      "tx-id",
+				ChannelId: "channel-id",
+			}
+
+			fakeTxSimulator.GetPrivateDataHashReturns([]byte("get-private-data-hash-response"), nil)
+		})
+
+		It("calls GetPrivateDataHash on the transaction simulator", func() {
+			_, err := handler.HandleGetPrivateDataHash(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeTxSimulator.GetPrivateDataHashCallCount()).To(Equal(1))
+			ccname, collection, key := fakeTxSimulator.GetPrivateDataHashArgsForCall(0)
+			Expect(ccname).To(Equal("cc-instance-name"))
+			Expect(collection).To(Equal("collection-name"))
+			Expect(key).To(Equal("get-state-key"))
+		})
+
+		It("returns the response message from GetPrivateDataHash", func() {
+			resp, err := handler.HandleGetPrivateDataHash(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(Equal(&pb.ChaincodeMessage{
+				Type:      pb.ChaincodeMessage_RESPONSE,
+				Payload:   []byte("get-private-data-hash-response"),
+				Txid:      "tx-id",
+				ChannelId: "channel-id",
+			}))
+		})
+
+		It("does not read the private data itself", func() {
+			_, err := handler.HandleGetPrivateDataHash(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeTxSimulator.GetPrivateDataCallCount()).To(Equal(0))
+		})
+
+		Context("when unmarshalling the request fails", func() {
+			BeforeEach(func() {
+				incomingMessage.Payload = []byte("this-is-a-bogus-payload")
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleGetPrivateDataHash(incomingMessage, txContext)
+				Expect(err).To(MatchError("unmarshal failed: proto: can't skip unknown wire type 4"))
+			})
+		})
+
+		Context("when collection is not set", func() {
+			BeforeEach(func() {
+				request.Collection = ""
+				payload, err := proto.Marshal(request)
+				Expect(err).NotTo(HaveOccurred())
+				incomingMessage.Payload = payload
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleGetPrivateDataHash(incomingMessage, txContext)
+				Expect(err).To(MatchError("collection must not be empty"))
+				Expect(fakeTxSimulator.GetPrivateDataHashCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when GetPrivateDataHash fails", func() {
+			BeforeEach(func() {
+				fakeTxSimulator.GetPrivateDataHashReturns(nil, errors.New("pickles"))
+			})
+
+			It("returns the error from GetPrivateDataHash", func() {
+				_, err := handler.HandleGetPrivateDataHash(incomingMessage, txContext)
+				Expect(err).To(MatchError("pickles"))
+			})
+		})
+	})
+
+	Describe("HandleVerifyPrivateDataHash", func() {
+		var (
+			incomingMessage *pb.ChaincodeMessage
+			request         *pb.VerifyPrivateDataHash
+		)
+
+		BeforeEach(func() {
+			request = &pb.VerifyPrivateDataHash{
+				Collection: "collection-name",
+				Key:        "verify-key",
+				Preimage:   []byte("preimage"),
+			}
+			payload, err := proto.Marshal(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			incomingMessage = &pb.ChaincodeMessage{
+				Type:      pb.ChaincodeMessage_VERIFY_PRIVATE_DATA_HASH,
+				Payload:   payload,
+				Txid:      "tx-id",
+				ChannelId: "channel-id",
+			}
+
+			fakeApplicationConfigRetriever.GetApplicationConfigReturns(&config.MockApplication{
+				RWSetHashingAlgorithmRv: func(input []byte) []byte { return append([]byte("hash-"), input...) },
+			}, true)
+			fakeTxSimulator.GetPrivateDataHashReturns([]byte("hash-preimage"), nil)
+		})
+
+		It("compares the preimage hashed with the algorithm of the channel", func() {
+			resp, err := handler.HandleVerifyPrivateDataHash(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(Equal(&pb.ChaincodeMessage{
+				Type:      pb.ChaincodeMessage_RESPONSE,
+				Txid:      "tx-id",
+				ChannelId: "channel-id",
+			}))
+
+			Expect(fakeTxSimulator.GetPrivateDataHashCallCount()).To(Equal(1))
+			ccname, collection, key := fakeTxSimulator.GetPrivateDataHashArgsForCall(0)
+			Expect(ccname).To(Equal("cc-instance-name"))
+			Expect(collection).To(Equal("collection-name"))
+			Expect(key).To(Equal("verify-key"))
+			Expect(fakeApplicationConfigRetriever.GetApplicationConfigArgsForCall(0)).To(Equal("channel-id"))
+		})
+
+		Context("when the hash of the preimage differs", func() {
+			BeforeEach(func() {
+				fakeTxSimulator.GetPrivateDataHashReturns(util.ComputeSHA256([]byte("preimage")), nil)
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleVerifyPrivateDataHash(incomingMessage, txContext)
+				Expect(err).To(MatchError("the preimage does not match the hash of key verify-key in collection collection-name"))
+			})
+		})
+
+		Context("when the collection commits to its values", func() {
+			var blindingFactor []byte
+
+			BeforeEach(func() {
+				blindingFactor = make([]byte, pedersen.BlindingFactorSize)
+				blindingFactor[0] = 1
+				commitment, err := pedersen.Commit([]byte("preimage"), blindingFactor)
+				Expect(err).NotTo(HaveOccurred())
+				fakeTxSimulator.GetPrivateDataHashReturns(commitment, nil)
+				txContext.Proposal = &pb.Proposal{
+					Payload: utils.MarshalOrPanic(&pb.ChaincodeProposalPayload{
+						TransientMap: map[string][]byte{
+							pedersen.TransientKey("cc-instance-name", "collection-name", "verify-key"): blindingFactor,
+						},
+					}),
+				}
+			})
+
+			It("opens the commitment with the preimage and the blinding factor of the transient field", func() {
+				_, err := handler.HandleVerifyPrivateDataHash(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeApplicationConfigRetriever.GetApplicationConfigCallCount()).To(Equal(0))
+
+				request.Preimage = []byte("other preimage")
+				incomingMessage.Payload, err = proto.Marshal(request)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = handler.HandleVerifyPrivateDataHash(incomingMessage, txContext)
+				Expect(err).To(MatchError("the preimage does not match the commitment of key verify-key in collection collection-name"))
+			})
+
+			Context("when the blinding factor is not supplied", func() {
+				BeforeEach(func() {
+					txContext.Proposal = nil
+				})
+
+				It("returns an error", func() {
+					_, err := handler.HandleVerifyPrivateDataHash(incomingMessage, txContext)
+					Expect(err).To(MatchError("the blinding factor of the commitment of key verify-key in collection collection-name is missing from the transient field"))
+				})
+			})
+		})
+
+		Context("when the key does not exist", func() {
+			BeforeEach(func() {
+				fakeTxSimulator.GetPrivateDataHashReturns(nil, nil)
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleVerifyPrivateDataHash(incomingMessage, txContext)
+				Expect(err).To(MatchError("key verify-key does not exist in collection collection-name"))
+			})
+		})
+
+		Context("when collection is not set", func() {
+			BeforeEach(func() {
+				request.Collection = ""
+				payload, err := proto.Marshal(request)
+				Expect(err).NotTo(HaveOccurred())
+				incomingMessage.Payload = payload
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleVerifyPrivateDataHash(incomingMessage, txContext)
+				Expect(err).To(MatchError("collection must not be empty"))
+				Expect(fakeTxSimulator.GetPrivateDataHashCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the application config does not exist", func() {
+			BeforeEach(func() {
+				fakeApplicationConfigRetriever.GetApplicationConfigReturns(nil, false)
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleVerifyPrivateDataHash(incomingMessage, txContext)
+				Expect(err).To(MatchError("application config does not exist for channel-id"))
+			})
+		})
+	})
+
+	Describe("HandleGetStateMultiple", func() {
+		var (
+			incomingMessage *pb.ChaincodeMessage
+			request         *pb.GetStateMultiple
+		)
+
+		BeforeEach(func() {
+			request = &pb.GetStateMultiple{
+				Keys: []string{"key-1", "key-2", "key-3"},
+			}
+			payload, err := proto.Marshal(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			incomingMessage = &pb.ChaincodeMessage{
+				Type:      pb.ChaincodeMessage_GET_STATE_MULTIPLE,
+				Payload:   payload,
+				Txid:      "tx-id",
+				ChannelId: "channel-id",
+			}
+
+			fakeTxSimulator.GetStateMultipleKeysReturns([][]byte{[]byte("value-1"), nil, []byte("value-3")}, nil)
+			fakeTxSimulator.GetPrivateDataMultipleKeysReturns([][]byte{[]byte("private-value-1"), nil, nil}, nil)
+		})
+
+		It("calls GetStateMultipleKeys on the transaction simulator", func() {
+			_, err := handler.HandleGetStateMultiple(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeTxSimulator.GetStateMultipleKeysCallCount()).To(Equal(1))
+			ccname, keys := fakeTxSimulator.GetStateMultipleKeysArgsForCall(0)
+			Expect(ccname).To(Equal("cc-instance-name"))
+			Expect(keys).To(Equal([]string{"key-1", "key-2", "key-3"}))
+			Expect(fakeTxSimulator.GetStateCallCount()).To(Equal(0))
+		})
+
+		It("returns the values in a single response message", func() {
+			resp, err := handler.HandleGetStateMultiple(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload, err := proto.Marshal(&pb.GetStateMultipleResult{
+				Values: [][]byte{[]byte("value-1"), nil, []byte("value-3")},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(Equal(&pb.ChaincodeMessage{
+				Type:      pb.ChaincodeMessage_RESPONSE,
+				Payload:   payload,
+				Txid:      "tx-id",
+				ChannelId: "channel-id",
+			}))
+		})
+
+		Context("when collection is set", func() {
+			BeforeEach(func() {
+				request.Collection = "collection-name"
+				payload, err := proto.Marshal(request)
+				Expect(err).NotTo(HaveOccurred())
+				incomingMessage.Payload = payload
+			})
+
+			It("calls GetPrivateDataMultipleKeys on the transaction simulator", func() {
+				resp, err := handler.HandleGetStateMultiple(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeTxSimulator.GetPrivateDataMultipleKeysCallCount()).To(Equal(1))
+				ccname, collection, keys := fakeTxSimulator.GetPrivateDataMultipleKeysArgsForCall(0)
+				Expect(ccname).To(Equal("cc-instance-name"))
+				Expect(collection).To(Equal("collection-name"))
+				Expect(keys).To(Equal([]string{"key-1", "key-2", "key-3"}))
+
+				payload, err := proto.Marshal(&pb.GetStateMultipleResult{
+					Values: [][]byte{[]byte("private-value-1"), nil, nil},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.Payload).To(Equal(payload))
+			})
+		})
+
+		Context("when unmarshalling the request fails", func() {
+			BeforeEach(func() {
+				incomingMessage.Payload = []byte("this-is-a-bogus-payload")
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleGetStateMultiple(incomingMessage, txContext)
+				Expect(err).To(MatchError("unmarshal failed: proto: can't skip unknown wire type 4"))
+			})
+		})
+
+		Context("when GetStateMultipleKeys fails", func() {
+			BeforeEach(func() {
+				fakeTxSimulator.GetStateMultipleKeysReturns(nil, errors.New("pickles"))
+			})
+
+			It("returns the error from GetStateMultipleKeys", func() {
+				_, err := handler.HandleGetStateMultiple(incomingMessage, txContext)
+				Expect(err).To(MatchError("pickles"))
+			})
+		})
+	})
+
 	Describe("HandleGetStateMetadata", func() {
 		var (
 			incomingMessage  *pb.ChaincodeMessage
@@ -1838,6 +2245,18 @@ var _ = Describe("Handler", func() {
 				Expect(txParams.HistoryQueryExecutor).To(BeIdenticalTo(newHistoryQueryExecutor)) // same instance, not just equal
 			})
 
+			It("provides the context of the proposal to the execution", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				txContext.Context = ctx
+				_, err := handler.HandleInvokeChaincode(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeInvoker.InvokeCallCount()).To(Equal(1))
+				txParams, _, _ := fakeInvoker.InvokeArgsForCall(0)
+				Expect(txParams.Context).To(Equal(ctx))
+			})
+
 			It("marks the new transaction simulator as done after execute", func() {
 				fakeInvoker.InvokeStub = func(*ccprovider.TransactionParams, *ccprovider.CCContext, *pb.ChaincodeInput) (*pb.ChaincodeMessage, error) {
 					Expect(newTxSimulator.DoneCallCount()).To(Equal(0))
@@ -2085,10 +2504,23 @@ var _ = Describe("Handler", func() {
 			Eventually(fakeChatStream.SendCallCount).Should(Equal(1))
 			Consistently(fakeChatStream.SendCallCount).Should(Equal(1))
 			msg := fakeChatStream.SendArgsForCall(0)
+			Expect(msg.Deadline).NotTo(BeNil())
+			expectedMessage.Deadline = msg.Deadline
 			Expect(msg).To(Equal(&expectedMessage))
 			Expect(msg.Proposal).To(Equal(expectedSignedProp))
 		})
 
+		It("sends the deadline of the execution to the chaincode", func() {
+			close(responseNotifier)
+			start := time.Now()
+			handler.Execute(txParams, cccid, incomingMessage, time.Minute)
+
+			Eventually(fakeChatStream.SendCallCount).Should(Equal(1))
+			deadline, err := ptypes.Timestamp(fakeChatStream.SendArgsForCall(0).Deadline)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadline).To(BeTemporally("~", start.Add(time.Minute), time.Second))
+		})
+
 		It("waits for the chaincode to respond", func() {
 			doneCh := make(chan struct{})
 			go func() {
@@ -2221,6 +2653,54 @@ var _ = Describe("Handler", func() {
 				Expect(txid).To(Equal("tx-id"))
 			})
 		})
+
+		Context("when the proposal has a deadline before the timeout", func() {
+			var cancel context.CancelFunc
+
+			BeforeEach(func() {
+				txParams.Context, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+			})
+
+			AfterEach(func() {
+				cancel()
+			})
+
+			It("sends the deadline of the proposal to the chaincode", func() {
+				close(responseNotifier)
+				handler.Execute(txParams, cccid, incomingMessage, time.Minute)
+
+				Eventually(fakeChatStream.SendCallCount).Should(Equal(1))
+				deadline, err := ptypes.Timestamp(fakeChatStream.SendArgsForCall(0).Deadline)
+				Expect(err).NotTo(HaveOccurred())
+				expectedDeadline, _ := txParams.Context.Deadline()
+				Expect(deadline).To(BeTemporally("==", expectedDeadline))
+			})
+
+			It("stops waiting for the chaincode at the deadline", func() {
+				_, err := handler.Execute(txParams, cccid, incomingMessage, time.Minute)
+				Expect(err).To(HaveOccurred())
+
+				Expect(fakeContextRegistry.DeleteCallCount()).Should(Equal(1))
+			})
+		})
+
+		Context("when the proposal is canceled", func() {
+			It("aborts the execution", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				txParams.Context = ctx
+				errCh := make(chan error, 1)
+				go func() {
+					_, err := handler.Execute(txParams, cccid, incomingMessage, time.Minute)
+					errCh <- err
+				}()
+
+				Eventually(fakeChatStream.SendCallCount).Should(Equal(1))
+				Consistently(errCh).ShouldNot(Receive())
+				cancel()
+				Eventually(errCh).Should(Receive(MatchError("execution of transaction aborted: context canceled")))
+				Expect(fakeContextRegistry.DeleteCallCount()).Should(Equal(1))
+			})
+		})
 	})
 
 	Describe("HandleRegister", func() {
@@ -2355,6 +2835,51 @@ var _ = Describe("Handler", func() {
 		})
 	})
 
+	Describe("HandleHeartbeat", func() {
+		var incomingMessage *pb.ChaincodeMessage
+
+		BeforeEach(func() {
+			payload, err := proto.Marshal(&pb.ChaincodeRuntimeStats{TransactionsInFlight: 2, MemoryBytes: 1024})
+			Expect(err).NotTo(HaveOccurred())
+			incomingMessage = &pb.ChaincodeMessage{
+				Type:    pb.ChaincodeMessage_HEARTBEAT,
+				Payload: payload,
+			}
+
+			chaincode.SetHandlerCCInstance(handler, nil)
+			registerPayload, err := proto.Marshal(&pb.ChaincodeID{Name: "chaincode-id-name"})
+			Expect(err).NotTo(HaveOccurred())
+			handler.HandleRegister(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_REGISTER, Payload: registerPayload})
+		})
+
+		It("records the runtime stats of the chaincode", func() {
+			handler.HandleHeartbeat(incomingMessage)
+
+			status := handler.RuntimeStatus(time.Now(), time.Minute)
+			Expect(status.Name).To(Equal("chaincode-id-name"))
+			Expect(status.Responsive).To(BeTrue())
+			Expect(status.LastHeartbeat).NotTo(BeNil())
+			Expect(proto.Equal(status.Stats, &pb.ChaincodeRuntimeStats{TransactionsInFlight: 2, MemoryBytes: 1024})).To(BeTrue())
+		})
+
+		It("sends no response", func() {
+			sent := fakeChatStream.SendCallCount()
+			handler.HandleHeartbeat(incomingMessage)
+			Consistently(fakeChatStream.SendCallCount).Should(Equal(sent))
+		})
+
+		Context("when unmarshaling the stats fails", func() {
+			BeforeEach(func() {
+				incomingMessage.Payload = []byte("this-is-a-bogus-payload")
+			})
+
+			It("ignores the heartbeat", func() {
+				handler.HandleHeartbeat(incomingMessage)
+				Expect(handler.RuntimeStatus(time.Now(), time.Minute).LastHeartbeat).To(BeNil())
+			})
+		})
+	})
+
 	Describe("ProcessStream", func() {
 		BeforeEach(func() {
 			incomingMessage := &pb.ChaincodeMessage{
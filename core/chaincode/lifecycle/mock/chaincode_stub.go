@@ -2,6 +2,7 @@
 package mock
 
 import (
+	"context"
 	"sync"
 
 	"github.com/golang/protobuf/ptypes/timestamp"
@@ -94,6 +95,19 @@ type ChaincodeStub struct {
 		result1 []byte
 		result2 error
 	}
+	GetStateMultipleKeysStub        func(keys []string) ([][]byte, error)
+	getStateMultipleKeysMutex       sync.RWMutex
+	getStateMultipleKeysArgsForCall []struct {
+		keys []string
+	}
+	getStateMultipleKeysReturns struct {
+		result1 [][]byte
+		result2 error
+	}
+	getStateMultipleKeysReturnsOnCall map[int]struct {
+		result1 [][]byte
+		result2 error
+	}
 	PutStateStub        func(key string, value []byte) error
 	putStateMutex       sync.RWMutex
 	putStateArgsForCall []struct {
@@ -235,6 +249,75 @@ type ChaincodeStub struct {
 		result2 []string
 		result3 error
 	}
+	GetStateInStub        func(namespace string, key string) ([]byte, error)
+	getStateInMutex       sync.RWMutex
+	getStateInArgsForCall []struct {
+		namespace string
+		key       string
+	}
+	getStateInReturns struct {
+		result1 []byte
+		result2 error
+	}
+	getStateInReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
+	PutStateInStub        func(namespace string, key string, value []byte) error
+	putStateInMutex       sync.RWMutex
+	putStateInArgsForCall []struct {
+		namespace string
+		key       string
+		value     []byte
+	}
+	putStateInReturns struct {
+		result1 error
+	}
+	putStateInReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DelStateInStub        func(namespace string, key string) error
+	delStateInMutex       sync.RWMutex
+	delStateInArgsForCall []struct {
+		namespace string
+		key       string
+	}
+	delStateInReturns struct {
+		result1 error
+	}
+	delStateInReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetStateByRangeInStub        func(namespace string, startKey string, endKey string) (shim.StateQueryIteratorInterface, error)
+	getStateByRangeInMutex       sync.RWMutex
+	getStateByRangeInArgsForCall []struct {
+		namespace string
+		startKey  string
+		endKey    string
+	}
+	getStateByRangeInReturns struct {
+		result1 shim.StateQueryIteratorInterface
+		result2 error
+	}
+	getStateByRangeInReturnsOnCall map[int]struct {
+		result1 shim.StateQueryIteratorInterface
+		result2 error
+	}
+	GetStateByPartialCompositeKeyInStub        func(namespace string, objectType string, keys []string) (shim.StateQueryIteratorInterface, error)
+	getStateByPartialCompositeKeyInMutex       sync.RWMutex
+	getStateByPartialCompositeKeyInArgsForCall []struct {
+		namespace  string
+		objectType string
+		keys       []string
+	}
+	getStateByPartialCompositeKeyInReturns struct {
+		result1 shim.StateQueryIteratorInterface
+		result2 error
+	}
+	getStateByPartialCompositeKeyInReturnsOnCall map[int]struct {
+		result1 shim.StateQueryIteratorInterface
+		result2 error
+	}
 	GetQueryResultStub        func(query string) (shim.StateQueryIteratorInterface, error)
 	getQueryResultMutex       sync.RWMutex
 	getQueryResultArgsForCall []struct {
@@ -292,6 +375,61 @@ type ChaincodeStub struct {
 		result1 []byte
 		result2 error
 	}
+	GetPrivateDataUnvalidatedStub        func(collection, key string) ([]byte, error)
+	getPrivateDataUnvalidatedMutex       sync.RWMutex
+	getPrivateDataUnvalidatedArgsForCall []struct {
+		collection string
+		key        string
+	}
+	getPrivateDataUnvalidatedReturns struct {
+		result1 []byte
+		result2 error
+	}
+	getPrivateDataUnvalidatedReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
+	GetPrivateDataMultipleKeysStub        func(collection string, keys []string) ([][]byte, error)
+	getPrivateDataMultipleKeysMutex       sync.RWMutex
+	getPrivateDataMultipleKeysArgsForCall []struct {
+		collection string
+		keys       []string
+	}
+	getPrivateDataMultipleKeysReturns struct {
+		result1 [][]byte
+		result2 error
+	}
+	getPrivateDataMultipleKeysReturnsOnCall map[int]struct {
+		result1 [][]byte
+		result2 error
+	}
+	GetPrivateDataHashStub        func(collection, key string) ([]byte, error)
+	getPrivateDataHashMutex       sync.RWMutex
+	getPrivateDataHashArgsForCall []struct {
+		collection string
+		key        string
+	}
+	getPrivateDataHashReturns struct {
+		result1 []byte
+		result2 error
+	}
+	getPrivateDataHashReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
+	VerifyPrivateDataHashStub        func(collection, key string, preimage []byte) error
+	verifyPrivateDataHashMutex       sync.RWMutex
+	verifyPrivateDataHashArgsForCall []struct {
+		collection string
+		key        string
+		preimage   []byte
+	}
+	verifyPrivateDataHashReturns struct {
+		result1 error
+	}
+	verifyPrivateDataHashReturnsOnCall map[int]struct {
+		result1 error
+	}
 	PutPrivateDataStub        func(collection string, key string, value []byte) error
 	putPrivateDataMutex       sync.RWMutex
 	putPrivateDataArgsForCall []struct {
@@ -452,6 +590,15 @@ type ChaincodeStub struct {
 		result1 *timestamp.Timestamp
 		result2 error
 	}
+	GetContextStub        func() context.Context
+	getContextMutex       sync.RWMutex
+	getContextArgsForCall []struct{}
+	getContextReturns     struct {
+		result1 context.Context
+	}
+	getContextReturnsOnCall map[int]struct {
+		result1 context.Context
+	}
 	SetEventStub        func(name string, payload []byte) error
 	setEventMutex       sync.RWMutex
 	setEventArgsForCall []struct {
@@ -820,6 +967,62 @@ func (fake *ChaincodeStub) GetStateReturnsOnCall(i int, result1 []byte, result2
 	}{result1, result2}
 }
 
+func (fake *ChaincodeStub) GetStateMultipleKeys(keys []string) ([][]byte, error) {
+	var keysCopy []string
+	if keys != nil {
+		keysCopy = make([]string, len(keys))
+		copy(keysCopy, keys)
+	}
+	fake.getStateMultipleKeysMutex.Lock()
+	ret, specificReturn := fake.getStateMultipleKeysReturnsOnCall[len(fake.getStateMultipleKeysArgsForCall)]
+	fake.getStateMultipleKeysArgsForCall = append(fake.getStateMultipleKeysArgsForCall, struct {
+		keys []string
+	}{keysCopy})
+	fake.recordInvocation("GetStateMultipleKeys", []interface{}{keysCopy})
+	fake.getStateMultipleKeysMutex.Unlock()
+	if fake.GetStateMultipleKeysStub != nil {
+		return fake.GetStateMultipleKeysStub(keys)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getStateMultipleKeysReturns.result1, fake.getStateMultipleKeysReturns.result2
+}
+
+func (fake *ChaincodeStub) GetStateMultipleKeysCallCount() int {
+	fake.getStateMultipleKeysMutex.RLock()
+	defer fake.getStateMultipleKeysMutex.RUnlock()
+	return len(fake.getStateMultipleKeysArgsForCall)
+}
+
+func (fake *ChaincodeStub) GetStateMultipleKeysArgsForCall(i int) []string {
+	fake.getStateMultipleKeysMutex.RLock()
+	defer fake.getStateMultipleKeysMutex.RUnlock()
+	return fake.getStateMultipleKeysArgsForCall[i].keys
+}
+
+func (fake *ChaincodeStub) GetStateMultipleKeysReturns(result1 [][]byte, result2 error) {
+	fake.GetStateMultipleKeysStub = nil
+	fake.getStateMultipleKeysReturns = struct {
+		result1 [][]byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) GetStateMultipleKeysReturnsOnCall(i int, result1 [][]byte, result2 error) {
+	fake.GetStateMultipleKeysStub = nil
+	if fake.getStateMultipleKeysReturnsOnCall == nil {
+		fake.getStateMultipleKeysReturnsOnCall = make(map[int]struct {
+			result1 [][]byte
+			result2 error
+		})
+	}
+	fake.getStateMultipleKeysReturnsOnCall[i] = struct {
+		result1 [][]byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ChaincodeStub) PutState(key string, value []byte) error {
 	var valueCopy []byte
 	if value != nil {
@@ -1366,6 +1569,273 @@ func (fake *ChaincodeStub) SplitCompositeKeyReturnsOnCall(i int, result1 string,
 	}{result1, result2, result3}
 }
 
+func (fake *ChaincodeStub) GetStateIn(namespace string, key string) ([]byte, error) {
+	fake.getStateInMutex.Lock()
+	ret, specificReturn := fake.getStateInReturnsOnCall[len(fake.getStateInArgsForCall)]
+	fake.getStateInArgsForCall = append(fake.getStateInArgsForCall, struct {
+		namespace string
+		key       string
+	}{namespace, key})
+	fake.recordInvocation("GetStateIn", []interface{}{namespace, key})
+	fake.getStateInMutex.Unlock()
+	if fake.GetStateInStub != nil {
+		return fake.GetStateInStub(namespace, key)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getStateInReturns.result1, fake.getStateInReturns.result2
+}
+
+func (fake *ChaincodeStub) GetStateInCallCount() int {
+	fake.getStateInMutex.RLock()
+	defer fake.getStateInMutex.RUnlock()
+	return len(fake.getStateInArgsForCall)
+}
+
+func (fake *ChaincodeStub) GetStateInArgsForCall(i int) (string, string) {
+	fake.getStateInMutex.RLock()
+	defer fake.getStateInMutex.RUnlock()
+	return fake.getStateInArgsForCall[i].namespace, fake.getStateInArgsForCall[i].key
+}
+
+func (fake *ChaincodeStub) GetStateInReturns(result1 []byte, result2 error) {
+	fake.GetStateInStub = nil
+	fake.getStateInReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) GetStateInReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.GetStateInStub = nil
+	if fake.getStateInReturnsOnCall == nil {
+		fake.getStateInReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.getStateInReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) PutStateIn(namespace string, key string, value []byte) error {
+	var valueCopy []byte
+	if value != nil {
+		valueCopy = make([]byte, len(value))
+		copy(valueCopy, value)
+	}
+	fake.putStateInMutex.Lock()
+	ret, specificReturn := fake.putStateInReturnsOnCall[len(fake.putStateInArgsForCall)]
+	fake.putStateInArgsForCall = append(fake.putStateInArgsForCall, struct {
+		namespace string
+		key       string
+		value     []byte
+	}{namespace, key, valueCopy})
+	fake.recordInvocation("PutStateIn", []interface{}{namespace, key, valueCopy})
+	fake.putStateInMutex.Unlock()
+	if fake.PutStateInStub != nil {
+		return fake.PutStateInStub(namespace, key, value)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.putStateInReturns.result1
+}
+
+func (fake *ChaincodeStub) PutStateInCallCount() int {
+	fake.putStateInMutex.RLock()
+	defer fake.putStateInMutex.RUnlock()
+	return len(fake.putStateInArgsForCall)
+}
+
+func (fake *ChaincodeStub) PutStateInArgsForCall(i int) (string, string, []byte) {
+	fake.putStateInMutex.RLock()
+	defer fake.putStateInMutex.RUnlock()
+	return fake.putStateInArgsForCall[i].namespace, fake.putStateInArgsForCall[i].key, fake.putStateInArgsForCall[i].value
+}
+
+func (fake *ChaincodeStub) PutStateInReturns(result1 error) {
+	fake.PutStateInStub = nil
+	fake.putStateInReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ChaincodeStub) PutStateInReturnsOnCall(i int, result1 error) {
+	fake.PutStateInStub = nil
+	if fake.putStateInReturnsOnCall == nil {
+		fake.putStateInReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.putStateInReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ChaincodeStub) DelStateIn(namespace string, key string) error {
+	fake.delStateInMutex.Lock()
+	ret, specificReturn := fake.delStateInReturnsOnCall[len(fake.delStateInArgsForCall)]
+	fake.delStateInArgsForCall = append(fake.delStateInArgsForCall, struct {
+		namespace string
+		key       string
+	}{namespace, key})
+	fake.recordInvocation("DelStateIn", []interface{}{namespace, key})
+	fake.delStateInMutex.Unlock()
+	if fake.DelStateInStub != nil {
+		return fake.DelStateInStub(namespace, key)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.delStateInReturns.result1
+}
+
+func (fake *ChaincodeStub) DelStateInCallCount() int {
+	fake.delStateInMutex.RLock()
+	defer fake.delStateInMutex.RUnlock()
+	return len(fake.delStateInArgsForCall)
+}
+
+func (fake *ChaincodeStub) DelStateInArgsForCall(i int) (string, string) {
+	fake.delStateInMutex.RLock()
+	defer fake.delStateInMutex.RUnlock()
+	return fake.delStateInArgsForCall[i].namespace, fake.delStateInArgsForCall[i].key
+}
+
+func (fake *ChaincodeStub) DelStateInReturns(result1 error) {
+	fake.DelStateInStub = nil
+	fake.delStateInReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ChaincodeStub) DelStateInReturnsOnCall(i int, result1 error) {
+	fake.DelStateInStub = nil
+	if fake.delStateInReturnsOnCall == nil {
+		fake.delStateInReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.delStateInReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ChaincodeStub) GetStateByRangeIn(namespace string, startKey string, endKey string) (shim.StateQueryIteratorInterface, error) {
+	fake.getStateByRangeInMutex.Lock()
+	ret, specificReturn := fake.getStateByRangeInReturnsOnCall[len(fake.getStateByRangeInArgsForCall)]
+	fake.getStateByRangeInArgsForCall = append(fake.getStateByRangeInArgsForCall, struct {
+		namespace string
+		startKey  string
+		endKey    string
+	}{namespace, startKey, endKey})
+	fake.recordInvocation("GetStateByRangeIn", []interface{}{namespace, startKey, endKey})
+	fake.getStateByRangeInMutex.Unlock()
+	if fake.GetStateByRangeInStub != nil {
+		return fake.GetStateByRangeInStub(namespace, startKey, endKey)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getStateByRangeInReturns.result1, fake.getStateByRangeInReturns.result2
+}
+
+func (fake *ChaincodeStub) GetStateByRangeInCallCount() int {
+	fake.getStateByRangeInMutex.RLock()
+	defer fake.getStateByRangeInMutex.RUnlock()
+	return len(fake.getStateByRangeInArgsForCall)
+}
+
+func (fake *ChaincodeStub) GetStateByRangeInArgsForCall(i int) (string, string, string) {
+	fake.getStateByRangeInMutex.RLock()
+	defer fake.getStateByRangeInMutex.RUnlock()
+	return fake.getStateByRangeInArgsForCall[i].namespace, fake.getStateByRangeInArgsForCall[i].startKey, fake.getStateByRangeInArgsForCall[i].endKey
+}
+
+func (fake *ChaincodeStub) GetStateByRangeInReturns(result1 shim.StateQueryIteratorInterface, result2 error) {
+	fake.GetStateByRangeInStub = nil
+	fake.getStateByRangeInReturns = struct {
+		result1 shim.StateQueryIteratorInterface
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) GetStateByRangeInReturnsOnCall(i int, result1 shim.StateQueryIteratorInterface, result2 error) {
+	fake.GetStateByRangeInStub = nil
+	if fake.getStateByRangeInReturnsOnCall == nil {
+		fake.getStateByRangeInReturnsOnCall = make(map[int]struct {
+			result1 shim.StateQueryIteratorInterface
+			result2 error
+		})
+	}
+	fake.getStateByRangeInReturnsOnCall[i] = struct {
+		result1 shim.StateQueryIteratorInterface
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) GetStateByPartialCompositeKeyIn(namespace string, objectType string, keys []string) (shim.StateQueryIteratorInterface, error) {
+	var keysCopy []string
+	if keys != nil {
+		keysCopy = make([]string, len(keys))
+		copy(keysCopy, keys)
+	}
+	fake.getStateByPartialCompositeKeyInMutex.Lock()
+	ret, specificReturn := fake.getStateByPartialCompositeKeyInReturnsOnCall[len(fake.getStateByPartialCompositeKeyInArgsForCall)]
+	fake.getStateByPartialCompositeKeyInArgsForCall = append(fake.getStateByPartialCompositeKeyInArgsForCall, struct {
+		namespace  string
+		objectType string
+		keys       []string
+	}{namespace, objectType, keysCopy})
+	fake.recordInvocation("GetStateByPartialCompositeKeyIn", []interface{}{namespace, objectType, keysCopy})
+	fake.getStateByPartialCompositeKeyInMutex.Unlock()
+	if fake.GetStateByPartialCompositeKeyInStub != nil {
+		return fake.GetStateByPartialCompositeKeyInStub(namespace, objectType, keys)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getStateByPartialCompositeKeyInReturns.result1, fake.getStateByPartialCompositeKeyInReturns.result2
+}
+
+func (fake *ChaincodeStub) GetStateByPartialCompositeKeyInCallCount() int {
+	fake.getStateByPartialCompositeKeyInMutex.RLock()
+	defer fake.getStateByPartialCompositeKeyInMutex.RUnlock()
+	return len(fake.getStateByPartialCompositeKeyInArgsForCall)
+}
+
+func (fake *ChaincodeStub) GetStateByPartialCompositeKeyInArgsForCall(i int) (string, string, []string) {
+	fake.getStateByPartialCompositeKeyInMutex.RLock()
+	defer fake.getStateByPartialCompositeKeyInMutex.RUnlock()
+	return fake.getStateByPartialCompositeKeyInArgsForCall[i].namespace, fake.getStateByPartialCompositeKeyInArgsForCall[i].objectType, fake.getStateByPartialCompositeKeyInArgsForCall[i].keys
+}
+
+func (fake *ChaincodeStub) GetStateByPartialCompositeKeyInReturns(result1 shim.StateQueryIteratorInterface, result2 error) {
+	fake.GetStateByPartialCompositeKeyInStub = nil
+	fake.getStateByPartialCompositeKeyInReturns = struct {
+		result1 shim.StateQueryIteratorInterface
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) GetStateByPartialCompositeKeyInReturnsOnCall(i int, result1 shim.StateQueryIteratorInterface, result2 error) {
+	fake.GetStateByPartialCompositeKeyInStub = nil
+	if fake.getStateByPartialCompositeKeyInReturnsOnCall == nil {
+		fake.getStateByPartialCompositeKeyInReturnsOnCall = make(map[int]struct {
+			result1 shim.StateQueryIteratorInterface
+			result2 error
+		})
+	}
+	fake.getStateByPartialCompositeKeyInReturnsOnCall[i] = struct {
+		result1 shim.StateQueryIteratorInterface
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ChaincodeStub) GetQueryResult(query string) (shim.StateQueryIteratorInterface, error) {
 	fake.getQueryResultMutex.Lock()
 	ret, specificReturn := fake.getQueryResultReturnsOnCall[len(fake.getQueryResultArgsForCall)]
@@ -1576,6 +2046,222 @@ func (fake *ChaincodeStub) GetPrivateDataReturnsOnCall(i int, result1 []byte, re
 	}{result1, result2}
 }
 
+func (fake *ChaincodeStub) GetPrivateDataUnvalidated(collection string, key string) ([]byte, error) {
+	fake.getPrivateDataUnvalidatedMutex.Lock()
+	ret, specificReturn := fake.getPrivateDataUnvalidatedReturnsOnCall[len(fake.getPrivateDataUnvalidatedArgsForCall)]
+	fake.getPrivateDataUnvalidatedArgsForCall = append(fake.getPrivateDataUnvalidatedArgsForCall, struct {
+		collection string
+		key        string
+	}{collection, key})
+	fake.recordInvocation("GetPrivateDataUnvalidated", []interface{}{collection, key})
+	fake.getPrivateDataUnvalidatedMutex.Unlock()
+	if fake.GetPrivateDataUnvalidatedStub != nil {
+		return fake.GetPrivateDataUnvalidatedStub(collection, key)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getPrivateDataUnvalidatedReturns.result1, fake.getPrivateDataUnvalidatedReturns.result2
+}
+
+func (fake *ChaincodeStub) GetPrivateDataUnvalidatedCallCount() int {
+	fake.getPrivateDataUnvalidatedMutex.RLock()
+	defer fake.getPrivateDataUnvalidatedMutex.RUnlock()
+	return len(fake.getPrivateDataUnvalidatedArgsForCall)
+}
+
+func (fake *ChaincodeStub) GetPrivateDataUnvalidatedArgsForCall(i int) (string, string) {
+	fake.getPrivateDataUnvalidatedMutex.RLock()
+	defer fake.getPrivateDataUnvalidatedMutex.RUnlock()
+	return fake.getPrivateDataUnvalidatedArgsForCall[i].collection, fake.getPrivateDataUnvalidatedArgsForCall[i].key
+}
+
+func (fake *ChaincodeStub) GetPrivateDataUnvalidatedReturns(result1 []byte, result2 error) {
+	fake.GetPrivateDataUnvalidatedStub = nil
+	fake.getPrivateDataUnvalidatedReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) GetPrivateDataUnvalidatedReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.GetPrivateDataUnvalidatedStub = nil
+	if fake.getPrivateDataUnvalidatedReturnsOnCall == nil {
+		fake.getPrivateDataUnvalidatedReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.getPrivateDataUnvalidatedReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) GetPrivateDataMultipleKeys(collection string, keys []string) ([][]byte, error) {
+	var keysCopy []string
+	if keys != nil {
+		keysCopy = make([]string, len(keys))
+		copy(keysCopy, keys)
+	}
+	fake.getPrivateDataMultipleKeysMutex.Lock()
+	ret, specificReturn := fake.getPrivateDataMultipleKeysReturnsOnCall[len(fake.getPrivateDataMultipleKeysArgsForCall)]
+	fake.getPrivateDataMultipleKeysArgsForCall = append(fake.getPrivateDataMultipleKeysArgsForCall, struct {
+		collection string
+		keys       []string
+	}{collection, keysCopy})
+	fake.recordInvocation("GetPrivateDataMultipleKeys", []interface{}{collection, keysCopy})
+	fake.getPrivateDataMultipleKeysMutex.Unlock()
+	if fake.GetPrivateDataMultipleKeysStub != nil {
+		return fake.GetPrivateDataMultipleKeysStub(collection, keys)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getPrivateDataMultipleKeysReturns.result1, fake.getPrivateDataMultipleKeysReturns.result2
+}
+
+func (fake *ChaincodeStub) GetPrivateDataMultipleKeysCallCount() int {
+	fake.getPrivateDataMultipleKeysMutex.RLock()
+	defer fake.getPrivateDataMultipleKeysMutex.RUnlock()
+	return len(fake.getPrivateDataMultipleKeysArgsForCall)
+}
+
+func (fake *ChaincodeStub) GetPrivateDataMultipleKeysArgsForCall(i int) (string, []string) {
+	fake.getPrivateDataMultipleKeysMutex.RLock()
+	defer fake.getPrivateDataMultipleKeysMutex.RUnlock()
+	return fake.getPrivateDataMultipleKeysArgsForCall[i].collection, fake.getPrivateDataMultipleKeysArgsForCall[i].keys
+}
+
+func (fake *ChaincodeStub) GetPrivateDataMultipleKeysReturns(result1 [][]byte, result2 error) {
+	fake.GetPrivateDataMultipleKeysStub = nil
+	fake.getPrivateDataMultipleKeysReturns = struct {
+		result1 [][]byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) GetPrivateDataMultipleKeysReturnsOnCall(i int, result1 [][]byte, result2 error) {
+	fake.GetPrivateDataMultipleKeysStub = nil
+	if fake.getPrivateDataMultipleKeysReturnsOnCall == nil {
+		fake.getPrivateDataMultipleKeysReturnsOnCall = make(map[int]struct {
+			result1 [][]byte
+			result2 error
+		})
+	}
+	fake.getPrivateDataMultipleKeysReturnsOnCall[i] = struct {
+		result1 [][]byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) GetPrivateDataHash(collection string, key string) ([]byte, error) {
+	fake.getPrivateDataHashMutex.Lock()
+	ret, specificReturn := fake.getPrivateDataHashReturnsOnCall[len(fake.getPrivateDataHashArgsForCall)]
+	fake.getPrivateDataHashArgsForCall = append(fake.getPrivateDataHashArgsForCall, struct {
+		collection string
+		key        string
+	}{collection, key})
+	fake.recordInvocation("GetPrivateDataHash", []interface{}{collection, key})
+	fake.getPrivateDataHashMutex.Unlock()
+	if fake.GetPrivateDataHashStub != nil {
+		return fake.GetPrivateDataHashStub(collection, key)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getPrivateDataHashReturns.result1, fake.getPrivateDataHashReturns.result2
+}
+
+func (fake *ChaincodeStub) GetPrivateDataHashCallCount() int {
+	fake.getPrivateDataHashMutex.RLock()
+	defer fake.getPrivateDataHashMutex.RUnlock()
+	return len(fake.getPrivateDataHashArgsForCall)
+}
+
+func (fake *ChaincodeStub) GetPrivateDataHashArgsForCall(i int) (string, string) {
+	fake.getPrivateDataHashMutex.RLock()
+	defer fake.getPrivateDataHashMutex.RUnlock()
+	return fake.getPrivateDataHashArgsForCall[i].collection, fake.getPrivateDataHashArgsForCall[i].key
+}
+
+func (fake *ChaincodeStub) GetPrivateDataHashReturns(result1 []byte, result2 error) {
+	fake.GetPrivateDataHashStub = nil
+	fake.getPrivateDataHashReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) GetPrivateDataHashReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.GetPrivateDataHashStub = nil
+	if fake.getPrivateDataHashReturnsOnCall == nil {
+		fake.getPrivateDataHashReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.getPrivateDataHashReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) VerifyPrivateDataHash(collection string, key string, preimage []byte) error {
+	var preimageCopy []byte
+	if preimage != nil {
+		preimageCopy = make([]byte, len(preimage))
+		copy(preimageCopy, preimage)
+	}
+	fake.verifyPrivateDataHashMutex.Lock()
+	ret, specificReturn := fake.verifyPrivateDataHashReturnsOnCall[len(fake.verifyPrivateDataHashArgsForCall)]
+	fake.verifyPrivateDataHashArgsForCall = append(fake.verifyPrivateDataHashArgsForCall, struct {
+		collection string
+		key        string
+		preimage   []byte
+	}{collection, key, preimageCopy})
+	fake.recordInvocation("VerifyPrivateDataHash", []interface{}{collection, key, preimageCopy})
+	fake.verifyPrivateDataHashMutex.Unlock()
+	if fake.VerifyPrivateDataHashStub != nil {
+		return fake.VerifyPrivateDataHashStub(collection, key, preimage)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.verifyPrivateDataHashReturns.result1
+}
+
+func (fake *ChaincodeStub) VerifyPrivateDataHashCallCount() int {
+	fake.verifyPrivateDataHashMutex.RLock()
+	defer fake.verifyPrivateDataHashMutex.RUnlock()
+	return len(fake.verifyPrivateDataHashArgsForCall)
+}
+
+func (fake *ChaincodeStub) VerifyPrivateDataHashArgsForCall(i int) (string, string, []byte) {
+	fake.verifyPrivateDataHashMutex.RLock()
+	defer fake.verifyPrivateDataHashMutex.RUnlock()
+	return fake.verifyPrivateDataHashArgsForCall[i].collection, fake.verifyPrivateDataHashArgsForCall[i].key, fake.verifyPrivateDataHashArgsForCall[i].preimage
+}
+
+func (fake *ChaincodeStub) VerifyPrivateDataHashReturns(result1 error) {
+	fake.VerifyPrivateDataHashStub = nil
+	fake.verifyPrivateDataHashReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ChaincodeStub) VerifyPrivateDataHashReturnsOnCall(i int, result1 error) {
+	fake.VerifyPrivateDataHashStub = nil
+	if fake.verifyPrivateDataHashReturnsOnCall == nil {
+		fake.verifyPrivateDataHashReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.verifyPrivateDataHashReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *ChaincodeStub) PutPrivateData(collection string, key string, value []byte) error {
 	var valueCopy []byte
 	if value != nil {
@@ -2205,6 +2891,46 @@ func (fake *ChaincodeStub) GetTxTimestampReturnsOnCall(i int, result1 *timestamp
 	}{result1, result2}
 }
 
+func (fake *ChaincodeStub) GetContext() context.Context {
+	fake.getContextMutex.Lock()
+	ret, specificReturn := fake.getContextReturnsOnCall[len(fake.getContextArgsForCall)]
+	fake.getContextArgsForCall = append(fake.getContextArgsForCall, struct{}{})
+	fake.recordInvocation("GetContext", []interface{}{})
+	fake.getContextMutex.Unlock()
+	if fake.GetContextStub != nil {
+		return fake.GetContextStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.getContextReturns.result1
+}
+
+func (fake *ChaincodeStub) GetContextCallCount() int {
+	fake.getContextMutex.RLock()
+	defer fake.getContextMutex.RUnlock()
+	return len(fake.getContextArgsForCall)
+}
+
+func (fake *ChaincodeStub) GetContextReturns(result1 context.Context) {
+	fake.GetContextStub = nil
+	fake.getContextReturns = struct {
+		result1 context.Context
+	}{result1}
+}
+
+func (fake *ChaincodeStub) GetContextReturnsOnCall(i int, result1 context.Context) {
+	fake.GetContextStub = nil
+	if fake.getContextReturnsOnCall == nil {
+		fake.getContextReturnsOnCall = make(map[int]struct {
+			result1 context.Context
+		})
+	}
+	fake.getContextReturnsOnCall[i] = struct {
+		result1 context.Context
+	}{result1}
+}
+
 func (fake *ChaincodeStub) SetEvent(name string, payload []byte) error {
 	var payloadCopy []byte
 	if payload != nil {
@@ -2278,6 +3004,8 @@ func (fake *ChaincodeStub) Invocations() map[string][][]interface{} {
 	defer fake.invokeChaincodeMutex.RUnlock()
 	fake.getStateMutex.RLock()
 	defer fake.getStateMutex.RUnlock()
+	fake.getStateMultipleKeysMutex.RLock()
+	defer fake.getStateMultipleKeysMutex.RUnlock()
 	fake.putStateMutex.RLock()
 	defer fake.putStateMutex.RUnlock()
 	fake.delStateMutex.RLock()
@@ -2298,6 +3026,16 @@ func (fake *ChaincodeStub) Invocations() map[string][][]interface{} {
 	defer fake.createCompositeKeyMutex.RUnlock()
 	fake.splitCompositeKeyMutex.RLock()
 	defer fake.splitCompositeKeyMutex.RUnlock()
+	fake.getStateInMutex.RLock()
+	defer fake.getStateInMutex.RUnlock()
+	fake.putStateInMutex.RLock()
+	defer fake.putStateInMutex.RUnlock()
+	fake.delStateInMutex.RLock()
+	defer fake.delStateInMutex.RUnlock()
+	fake.getStateByRangeInMutex.RLock()
+	defer fake.getStateByRangeInMutex.RUnlock()
+	fake.getStateByPartialCompositeKeyInMutex.RLock()
+	defer fake.getStateByPartialCompositeKeyInMutex.RUnlock()
 	fake.getQueryResultMutex.RLock()
 	defer fake.getQueryResultMutex.RUnlock()
 	fake.getQueryResultWithPaginationMutex.RLock()
@@ -2306,6 +3044,14 @@ func (fake *ChaincodeStub) Invocations() map[string][][]interface{} {
 	defer fake.getHistoryForKeyMutex.RUnlock()
 	fake.getPrivateDataMutex.RLock()
 	defer fake.getPrivateDataMutex.RUnlock()
+	fake.getPrivateDataUnvalidatedMutex.RLock()
+	defer fake.getPrivateDataUnvalidatedMutex.RUnlock()
+	fake.getPrivateDataMultipleKeysMutex.RLock()
+	defer fake.getPrivateDataMultipleKeysMutex.RUnlock()
+	fake.getPrivateDataHashMutex.RLock()
+	defer fake.getPrivateDataHashMutex.RUnlock()
+	fake.verifyPrivateDataHashMutex.RLock()
+	defer fake.verifyPrivateDataHashMutex.RUnlock()
 	fake.putPrivateDataMutex.RLock()
 	defer fake.putPrivateDataMutex.RUnlock()
 	fake.delPrivateDataMutex.RLock()
@@ -2332,6 +3078,8 @@ func (fake *ChaincodeStub) Invocations() map[string][][]interface{} {
 	defer fake.getSignedProposalMutex.RUnlock()
 	fake.getTxTimestampMutex.RLock()
 	defer fake.getTxTimestampMutex.RUnlock()
+	fake.getContextMutex.RLock()
+	defer fake.getContextMutex.RUnlock()
 	fake.setEventMutex.RLock()
 	defer fake.setEventMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
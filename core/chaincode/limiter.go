@@ -0,0 +1,156 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/pkg/errors"
+)
+
+// requestLimiter bounds the number of transactions a chaincode executes
+// concurrently. The transactions waiting for an execution slot are queued per
+// channel and the channels are served in turn, so that the requests of a
+// channel don't starve the requests of the other channels.
+type requestLimiter struct {
+	limit int
+
+	mutex   sync.Mutex
+	active  int
+	queued  int
+	holders map[string]int
+	queues  map[string][]*waiter
+	// turns holds the channels with waiting transactions,
+	// in the order they are served
+	turns []string
+
+	activeRequests    metrics.Gauge
+	queuedRequests    metrics.Gauge
+	throttledRequests metrics.Counter
+	queueTimeouts     metrics.Counter
+}
+
+type waiter struct {
+	txID    string
+	ready   chan struct{}
+	granted bool
+}
+
+func newRequestLimiter(limit int, scope metrics.Scope) *requestLimiter {
+	return &requestLimiter{
+		limit:             limit,
+		holders:           make(map[string]int),
+		queues:            make(map[string][]*waiter),
+		activeRequests:    scope.Gauge("active_requests"),
+		queuedRequests:    scope.Gauge("queued_requests"),
+		throttledRequests: scope.Counter("throttled_requests"),
+		queueTimeouts:     scope.Counter("queue_timeouts"),
+	}
+}
+
+// acquire waits for an execution slot for the transaction, up to the timeout.
+// A transaction already holding a slot, e.g. when the chaincode invokes itself
+// on another channel, gets a new one without waiting, as waiting for its own
+// completion would deadlock.
+func (l *requestLimiter) acquire(channelID, txID string, timeout time.Duration) error {
+	l.mutex.Lock()
+	if l.active < l.limit || l.holders[txID] > 0 {
+		l.grant(txID)
+		l.updateGauges()
+		l.mutex.Unlock()
+		return nil
+	}
+
+	w := &waiter{txID: txID, ready: make(chan struct{})}
+	if len(l.queues[channelID]) == 0 {
+		l.turns = append(l.turns, channelID)
+	}
+	l.queues[channelID] = append(l.queues[channelID], w)
+	l.queued++
+	l.updateGauges()
+	l.mutex.Unlock()
+	l.throttledRequests.Inc(1)
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-time.After(timeout):
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if w.granted {
+		// the slot was granted while the timeout expired
+		return nil
+	}
+	l.dequeue(channelID, w)
+	l.updateGauges()
+	l.queueTimeouts.Inc(1)
+	return errors.New("timeout expired while waiting for the chaincode to accept the transaction")
+}
+
+// release frees the execution slot of the transaction and hands it over to
+// the next waiting transaction
+func (l *requestLimiter) release(txID string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.active--
+	if l.holders[txID]--; l.holders[txID] <= 0 {
+		delete(l.holders, txID)
+	}
+	for l.active < l.limit && len(l.turns) > 0 {
+		channelID := l.turns[0]
+		l.turns = l.turns[1:]
+		queue := l.queues[channelID]
+		w := queue[0]
+		l.queues[channelID] = queue[1:]
+		if len(queue) > 1 {
+			l.turns = append(l.turns, channelID)
+		} else {
+			delete(l.queues, channelID)
+		}
+		l.queued--
+		l.grant(w.txID)
+		w.granted = true
+		close(w.ready)
+	}
+	l.updateGauges()
+}
+
+func (l *requestLimiter) grant(txID string) {
+	l.active++
+	l.holders[txID]++
+}
+
+func (l *requestLimiter) dequeue(channelID string, w *waiter) {
+	queue := l.queues[channelID]
+	for i := range queue {
+		if queue[i] == w {
+			queue = append(queue[:i], queue[i+1:]...)
+			break
+		}
+	}
+	l.queued--
+	if len(queue) > 0 {
+		l.queues[channelID] = queue
+		return
+	}
+	delete(l.queues, channelID)
+	for i := range l.turns {
+		if l.turns[i] == channelID {
+			l.turns = append(l.turns[:i], l.turns[i+1:]...)
+			break
+		}
+	}
+}
+
+func (l *requestLimiter) updateGauges() {
+	l.activeRequests.Update(float64(l.active))
+	l.queuedRequests.Update(float64(l.queued))
+}
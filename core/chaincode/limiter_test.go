@@ -0,0 +1,166 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingGauge struct {
+	sync.Mutex
+	value float64
+}
+
+func (g *recordingGauge) Update(value float64) {
+	g.Lock()
+	defer g.Unlock()
+	g.value = value
+}
+
+func (g *recordingGauge) get() float64 {
+	g.Lock()
+	defer g.Unlock()
+	return g.value
+}
+
+type recordingCounter struct {
+	sync.Mutex
+	count int64
+}
+
+func (c *recordingCounter) Inc(delta int64) {
+	c.Lock()
+	defer c.Unlock()
+	c.count += delta
+}
+
+func (c *recordingCounter) get() int64 {
+	c.Lock()
+	defer c.Unlock()
+	return c.count
+}
+
+type recordingScope struct {
+	metrics.Scope
+	gauges   map[string]*recordingGauge
+	counters map[string]*recordingCounter
+}
+
+func newRecordingScope() *recordingScope {
+	return &recordingScope{
+		Scope:    metrics.NewNoOpScope(),
+		gauges:   make(map[string]*recordingGauge),
+		counters: make(map[string]*recordingCounter),
+	}
+}
+
+func (s *recordingScope) Gauge(name string) metrics.Gauge {
+	s.gauges[name] = &recordingGauge{}
+	return s.gauges[name]
+}
+
+func (s *recordingScope) Counter(name string) metrics.Counter {
+	s.counters[name] = &recordingCounter{}
+	return s.counters[name]
+}
+
+// Tagged returns the scope itself, the metrics being recorded by name
+func (s *recordingScope) Tagged(tags map[string]string) metrics.Scope {
+	return s
+}
+
+// acquireAsync acquires a slot in a goroutine and returns the channel on
+// which the result is sent, once the goroutine is queued
+func acquireAsync(t *testing.T, l *requestLimiter, channelID, txID string, timeout time.Duration) chan error {
+	l.mutex.Lock()
+	queued := l.queued
+	l.mutex.Unlock()
+	result := make(chan error, 1)
+	go func() {
+		result <- l.acquire(channelID, txID, timeout)
+	}()
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(time.Millisecond) {
+		l.mutex.Lock()
+		current := l.queued
+		l.mutex.Unlock()
+		if current == queued+1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("transaction %s was not queued", txID)
+		}
+	}
+	return result
+}
+
+func TestRequestLimiter(t *testing.T) {
+	scope := newRecordingScope()
+	l := newRequestLimiter(2, scope)
+
+	assert.NoError(t, l.acquire("ch1", "tx1", time.Second))
+	assert.NoError(t, l.acquire("ch1", "tx2", time.Second))
+	assert.Equal(t, float64(2), scope.gauges["active_requests"].get())
+
+	// the transactions waiting for a slot are served in turn per channel
+	ch1tx3 := acquireAsync(t, l, "ch1", "tx3", time.Minute)
+	ch1tx4 := acquireAsync(t, l, "ch1", "tx4", time.Minute)
+	ch2tx5 := acquireAsync(t, l, "ch2", "tx5", time.Minute)
+	assert.Equal(t, float64(3), scope.gauges["queued_requests"].get())
+	assert.Equal(t, int64(3), scope.counters["throttled_requests"].get())
+
+	l.release("tx1")
+	assert.NoError(t, <-ch1tx3)
+	l.release("tx2")
+	assert.NoError(t, <-ch2tx5)
+	select {
+	case <-ch1tx4:
+		t.Fatal("tx4 should wait for a slot")
+	default:
+	}
+	l.release("tx3")
+	assert.NoError(t, <-ch1tx4)
+	assert.Equal(t, float64(0), scope.gauges["queued_requests"].get())
+	assert.Equal(t, float64(2), scope.gauges["active_requests"].get())
+
+	// a transaction holding a slot gets another one without waiting
+	assert.NoError(t, l.acquire("ch2", "tx4", time.Millisecond))
+	assert.Equal(t, float64(3), scope.gauges["active_requests"].get())
+	l.release("tx4")
+	l.release("tx4")
+	l.release("tx5")
+	assert.Equal(t, float64(0), scope.gauges["active_requests"].get())
+	assert.Empty(t, l.holders)
+}
+
+func TestRequestLimiterTimeout(t *testing.T) {
+	scope := newRecordingScope()
+	l := newRequestLimiter(1, scope)
+
+	assert.NoError(t, l.acquire("ch1", "tx1", time.Second))
+	err := l.acquire("ch1", "tx2", 10*time.Millisecond)
+	assert.EqualError(t, err, "timeout expired while waiting for the chaincode to accept the transaction")
+	assert.Equal(t, int64(1), scope.counters["queue_timeouts"].get())
+	assert.Equal(t, float64(0), scope.gauges["queued_requests"].get())
+	assert.Empty(t, l.queues)
+	assert.Empty(t, l.turns)
+
+	// the queue of a channel is kept when one of its waiters times out
+	ch2tx3 := acquireAsync(t, l, "ch2", "tx3", time.Minute)
+	err = l.acquire("ch2", "tx4", 10*time.Millisecond)
+	assert.Error(t, err)
+	assert.Equal(t, []string{"ch2"}, l.turns)
+
+	l.release("tx1")
+	assert.NoError(t, <-ch2tx3)
+	l.release("tx3")
+	assert.Equal(t, float64(0), scope.gauges["active_requests"].get())
+}
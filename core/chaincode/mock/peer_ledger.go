@@ -3,6 +3,7 @@ package mock
 
 import (
 	"sync"
+	"time"
 
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/core/ledger"
@@ -103,6 +104,50 @@ type PeerLedger struct {
 		result1 peer.TxValidationCode
 		result2 error
 	}
+	GetTransactionsByCreatorStub        func(creator []byte, startBlockNum uint64, limit int) ([]*ledger.IndexedTransaction, error)
+	getTransactionsByCreatorMutex       sync.RWMutex
+	getTransactionsByCreatorArgsForCall []struct {
+		creator       []byte
+		startBlockNum uint64
+		limit         int
+	}
+	getTransactionsByCreatorReturns struct {
+		result1 []*ledger.IndexedTransaction
+		result2 error
+	}
+	getTransactionsByCreatorReturnsOnCall map[int]struct {
+		result1 []*ledger.IndexedTransaction
+		result2 error
+	}
+	GetBlocksByTimeRangeStub        func(start time.Time, end time.Time, limit int) ([]*common.Block, error)
+	getBlocksByTimeRangeMutex       sync.RWMutex
+	getBlocksByTimeRangeArgsForCall []struct {
+		start time.Time
+		end   time.Time
+		limit int
+	}
+	getBlocksByTimeRangeReturns struct {
+		result1 []*common.Block
+		result2 error
+	}
+	getBlocksByTimeRangeReturnsOnCall map[int]struct {
+		result1 []*common.Block
+		result2 error
+	}
+	GetBlockNumByDedupKeyStub        func(namespace string, dedupKey string) (uint64, error)
+	getBlockNumByDedupKeyMutex       sync.RWMutex
+	getBlockNumByDedupKeyArgsForCall []struct {
+		namespace string
+		dedupKey  string
+	}
+	getBlockNumByDedupKeyReturns struct {
+		result1 uint64
+		result2 error
+	}
+	getBlockNumByDedupKeyReturnsOnCall map[int]struct {
+		result1 uint64
+		result2 error
+	}
 	NewTxSimulatorStub        func(txid string) (ledger.TxSimulator, error)
 	newTxSimulatorMutex       sync.RWMutex
 	newTxSimulatorArgsForCall []struct {
@@ -619,6 +664,169 @@ func (fake *PeerLedger) GetTxValidationCodeByTxIDReturnsOnCall(i int, result1 pe
 	}{result1, result2}
 }
 
+func (fake *PeerLedger) GetTransactionsByCreator(creator []byte, startBlockNum uint64, limit int) ([]*ledger.IndexedTransaction, error) {
+	var creatorCopy []byte
+	if creator != nil {
+		creatorCopy = make([]byte, len(creator))
+		copy(creatorCopy, creator)
+	}
+	fake.getTransactionsByCreatorMutex.Lock()
+	ret, specificReturn := fake.getTransactionsByCreatorReturnsOnCall[len(fake.getTransactionsByCreatorArgsForCall)]
+	fake.getTransactionsByCreatorArgsForCall = append(fake.getTransactionsByCreatorArgsForCall, struct {
+		creator       []byte
+		startBlockNum uint64
+		limit         int
+	}{creatorCopy, startBlockNum, limit})
+	fake.recordInvocation("GetTransactionsByCreator", []interface{}{creatorCopy, startBlockNum, limit})
+	fake.getTransactionsByCreatorMutex.Unlock()
+	if fake.GetTransactionsByCreatorStub != nil {
+		return fake.GetTransactionsByCreatorStub(creator, startBlockNum, limit)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getTransactionsByCreatorReturns.result1, fake.getTransactionsByCreatorReturns.result2
+}
+
+func (fake *PeerLedger) GetTransactionsByCreatorCallCount() int {
+	fake.getTransactionsByCreatorMutex.RLock()
+	defer fake.getTransactionsByCreatorMutex.RUnlock()
+	return len(fake.getTransactionsByCreatorArgsForCall)
+}
+
+func (fake *PeerLedger) GetTransactionsByCreatorArgsForCall(i int) ([]byte, uint64, int) {
+	fake.getTransactionsByCreatorMutex.RLock()
+	defer fake.getTransactionsByCreatorMutex.RUnlock()
+	return fake.getTransactionsByCreatorArgsForCall[i].creator, fake.getTransactionsByCreatorArgsForCall[i].startBlockNum, fake.getTransactionsByCreatorArgsForCall[i].limit
+}
+
+func (fake *PeerLedger) GetTransactionsByCreatorReturns(result1 []*ledger.IndexedTransaction, result2 error) {
+	fake.GetTransactionsByCreatorStub = nil
+	fake.getTransactionsByCreatorReturns = struct {
+		result1 []*ledger.IndexedTransaction
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PeerLedger) GetTransactionsByCreatorReturnsOnCall(i int, result1 []*ledger.IndexedTransaction, result2 error) {
+	fake.GetTransactionsByCreatorStub = nil
+	if fake.getTransactionsByCreatorReturnsOnCall == nil {
+		fake.getTransactionsByCreatorReturnsOnCall = make(map[int]struct {
+			result1 []*ledger.IndexedTransaction
+			result2 error
+		})
+	}
+	fake.getTransactionsByCreatorReturnsOnCall[i] = struct {
+		result1 []*ledger.IndexedTransaction
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PeerLedger) GetBlocksByTimeRange(start time.Time, end time.Time, limit int) ([]*common.Block, error) {
+	fake.getBlocksByTimeRangeMutex.Lock()
+	ret, specificReturn := fake.getBlocksByTimeRangeReturnsOnCall[len(fake.getBlocksByTimeRangeArgsForCall)]
+	fake.getBlocksByTimeRangeArgsForCall = append(fake.getBlocksByTimeRangeArgsForCall, struct {
+		start time.Time
+		end   time.Time
+		limit int
+	}{start, end, limit})
+	fake.recordInvocation("GetBlocksByTimeRange", []interface{}{start, end, limit})
+	fake.getBlocksByTimeRangeMutex.Unlock()
+	if fake.GetBlocksByTimeRangeStub != nil {
+		return fake.GetBlocksByTimeRangeStub(start, end, limit)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getBlocksByTimeRangeReturns.result1, fake.getBlocksByTimeRangeReturns.result2
+}
+
+func (fake *PeerLedger) GetBlocksByTimeRangeCallCount() int {
+	fake.getBlocksByTimeRangeMutex.RLock()
+	defer fake.getBlocksByTimeRangeMutex.RUnlock()
+	return len(fake.getBlocksByTimeRangeArgsForCall)
+}
+
+func (fake *PeerLedger) GetBlocksByTimeRangeArgsForCall(i int) (time.Time, time.Time, int) {
+	fake.getBlocksByTimeRangeMutex.RLock()
+	defer fake.getBlocksByTimeRangeMutex.RUnlock()
+	return fake.getBlocksByTimeRangeArgsForCall[i].start, fake.getBlocksByTimeRangeArgsForCall[i].end, fake.getBlocksByTimeRangeArgsForCall[i].limit
+}
+
+func (fake *PeerLedger) GetBlocksByTimeRangeReturns(result1 []*common.Block, result2 error) {
+	fake.GetBlocksByTimeRangeStub = nil
+	fake.getBlocksByTimeRangeReturns = struct {
+		result1 []*common.Block
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PeerLedger) GetBlocksByTimeRangeReturnsOnCall(i int, result1 []*common.Block, result2 error) {
+	fake.GetBlocksByTimeRangeStub = nil
+	if fake.getBlocksByTimeRangeReturnsOnCall == nil {
+		fake.getBlocksByTimeRangeReturnsOnCall = make(map[int]struct {
+			result1 []*common.Block
+			result2 error
+		})
+	}
+	fake.getBlocksByTimeRangeReturnsOnCall[i] = struct {
+		result1 []*common.Block
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PeerLedger) GetBlockNumByDedupKey(namespace string, dedupKey string) (uint64, error) {
+	fake.getBlockNumByDedupKeyMutex.Lock()
+	ret, specificReturn := fake.getBlockNumByDedupKeyReturnsOnCall[len(fake.getBlockNumByDedupKeyArgsForCall)]
+	fake.getBlockNumByDedupKeyArgsForCall = append(fake.getBlockNumByDedupKeyArgsForCall, struct {
+		namespace string
+		dedupKey  string
+	}{namespace, dedupKey})
+	fake.recordInvocation("GetBlockNumByDedupKey", []interface{}{namespace, dedupKey})
+	fake.getBlockNumByDedupKeyMutex.Unlock()
+	if fake.GetBlockNumByDedupKeyStub != nil {
+		return fake.GetBlockNumByDedupKeyStub(namespace, dedupKey)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getBlockNumByDedupKeyReturns.result1, fake.getBlockNumByDedupKeyReturns.result2
+}
+
+func (fake *PeerLedger) GetBlockNumByDedupKeyCallCount() int {
+	fake.getBlockNumByDedupKeyMutex.RLock()
+	defer fake.getBlockNumByDedupKeyMutex.RUnlock()
+	return len(fake.getBlockNumByDedupKeyArgsForCall)
+}
+
+func (fake *PeerLedger) GetBlockNumByDedupKeyArgsForCall(i int) (string, string) {
+	fake.getBlockNumByDedupKeyMutex.RLock()
+	defer fake.getBlockNumByDedupKeyMutex.RUnlock()
+	return fake.getBlockNumByDedupKeyArgsForCall[i].namespace, fake.getBlockNumByDedupKeyArgsForCall[i].dedupKey
+}
+
+func (fake *PeerLedger) GetBlockNumByDedupKeyReturns(result1 uint64, result2 error) {
+	fake.GetBlockNumByDedupKeyStub = nil
+	fake.getBlockNumByDedupKeyReturns = struct {
+		result1 uint64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PeerLedger) GetBlockNumByDedupKeyReturnsOnCall(i int, result1 uint64, result2 error) {
+	fake.GetBlockNumByDedupKeyStub = nil
+	if fake.getBlockNumByDedupKeyReturnsOnCall == nil {
+		fake.getBlockNumByDedupKeyReturnsOnCall = make(map[int]struct {
+			result1 uint64
+			result2 error
+		})
+	}
+	fake.getBlockNumByDedupKeyReturnsOnCall[i] = struct {
+		result1 uint64
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *PeerLedger) NewTxSimulator(txid string) (ledger.TxSimulator, error) {
 	fake.newTxSimulatorMutex.Lock()
 	ret, specificReturn := fake.newTxSimulatorReturnsOnCall[len(fake.newTxSimulatorArgsForCall)]
@@ -1208,6 +1416,12 @@ func (fake *PeerLedger) Invocations() map[string][][]interface{} {
 	defer fake.getBlockByTxIDMutex.RUnlock()
 	fake.getTxValidationCodeByTxIDMutex.RLock()
 	defer fake.getTxValidationCodeByTxIDMutex.RUnlock()
+	fake.getTransactionsByCreatorMutex.RLock()
+	defer fake.getTransactionsByCreatorMutex.RUnlock()
+	fake.getBlocksByTimeRangeMutex.RLock()
+	defer fake.getBlocksByTimeRangeMutex.RUnlock()
+	fake.getBlockNumByDedupKeyMutex.RLock()
+	defer fake.getBlockNumByDedupKeyMutex.RUnlock()
 	fake.newTxSimulatorMutex.RLock()
 	defer fake.newTxSimulatorMutex.RUnlock()
 	fake.newQueryExecutorMutex.RLock()
@@ -126,6 +126,21 @@ type TxSimulator struct {
 		result1 []byte
 		result2 error
 	}
+	GetPrivateDataHashStub        func(namespace, collection, key string) ([]byte, error)
+	getPrivateDataHashMutex       sync.RWMutex
+	getPrivateDataHashArgsForCall []struct {
+		namespace  string
+		collection string
+		key        string
+	}
+	getPrivateDataHashReturns struct {
+		result1 []byte
+		result2 error
+	}
+	getPrivateDataHashReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
 	GetPrivateDataMetadataStub        func(namespace, collection, key string) (map[string][]byte, error)
 	getPrivateDataMetadataMutex       sync.RWMutex
 	getPrivateDataMetadataArgsForCall []struct {
@@ -292,6 +307,21 @@ type TxSimulator struct {
 	setPrivateDataReturnsOnCall map[int]struct {
 		result1 error
 	}
+	SetPrivateDataWithBlindingFactorStub        func(namespace, collection, key string, value, blindingFactor []byte) error
+	setPrivateDataWithBlindingFactorMutex       sync.RWMutex
+	setPrivateDataWithBlindingFactorArgsForCall []struct {
+		namespace      string
+		collection     string
+		key            string
+		value          []byte
+		blindingFactor []byte
+	}
+	setPrivateDataWithBlindingFactorReturns struct {
+		result1 error
+	}
+	setPrivateDataWithBlindingFactorReturnsOnCall map[int]struct {
+		result1 error
+	}
 	SetPrivateDataMultipleKeysStub        func(namespace, collection string, kvs map[string][]byte) error
 	setPrivateDataMultipleKeysMutex       sync.RWMutex
 	setPrivateDataMultipleKeysArgsForCall []struct {
@@ -345,6 +375,21 @@ type TxSimulator struct {
 	deletePrivateDataMetadataReturnsOnCall map[int]struct {
 		result1 error
 	}
+	GetPrivateDataUnvalidatedStub        func(namespace, collection, key string) ([]byte, error)
+	getPrivateDataUnvalidatedMutex       sync.RWMutex
+	getPrivateDataUnvalidatedArgsForCall []struct {
+		namespace  string
+		collection string
+		key        string
+	}
+	getPrivateDataUnvalidatedReturns struct {
+		result1 []byte
+		result2 error
+	}
+	getPrivateDataUnvalidatedReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
 	GetTxSimulationResultsStub        func() (*ledger.TxSimulationResults, error)
 	getTxSimulationResultsMutex       sync.RWMutex
 	getTxSimulationResultsArgsForCall []struct{}
@@ -786,6 +831,59 @@ func (fake *TxSimulator) GetPrivateDataReturnsOnCall(i int, result1 []byte, resu
 	}{result1, result2}
 }
 
+func (fake *TxSimulator) GetPrivateDataHash(namespace string, collection string, key string) ([]byte, error) {
+	fake.getPrivateDataHashMutex.Lock()
+	ret, specificReturn := fake.getPrivateDataHashReturnsOnCall[len(fake.getPrivateDataHashArgsForCall)]
+	fake.getPrivateDataHashArgsForCall = append(fake.getPrivateDataHashArgsForCall, struct {
+		namespace  string
+		collection string
+		key        string
+	}{namespace, collection, key})
+	fake.recordInvocation("GetPrivateDataHash", []interface{}{namespace, collection, key})
+	fake.getPrivateDataHashMutex.Unlock()
+	if fake.GetPrivateDataHashStub != nil {
+		return fake.GetPrivateDataHashStub(namespace, collection, key)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getPrivateDataHashReturns.result1, fake.getPrivateDataHashReturns.result2
+}
+
+func (fake *TxSimulator) GetPrivateDataHashCallCount() int {
+	fake.getPrivateDataHashMutex.RLock()
+	defer fake.getPrivateDataHashMutex.RUnlock()
+	return len(fake.getPrivateDataHashArgsForCall)
+}
+
+func (fake *TxSimulator) GetPrivateDataHashArgsForCall(i int) (string, string, string) {
+	fake.getPrivateDataHashMutex.RLock()
+	defer fake.getPrivateDataHashMutex.RUnlock()
+	return fake.getPrivateDataHashArgsForCall[i].namespace, fake.getPrivateDataHashArgsForCall[i].collection, fake.getPrivateDataHashArgsForCall[i].key
+}
+
+func (fake *TxSimulator) GetPrivateDataHashReturns(result1 []byte, result2 error) {
+	fake.GetPrivateDataHashStub = nil
+	fake.getPrivateDataHashReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *TxSimulator) GetPrivateDataHashReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.GetPrivateDataHashStub = nil
+	if fake.getPrivateDataHashReturnsOnCall == nil {
+		fake.getPrivateDataHashReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.getPrivateDataHashReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *TxSimulator) GetPrivateDataMetadata(namespace string, collection string, key string) (map[string][]byte, error) {
 	fake.getPrivateDataMetadataMutex.Lock()
 	ret, specificReturn := fake.getPrivateDataMetadataReturnsOnCall[len(fake.getPrivateDataMetadataArgsForCall)]
@@ -1434,6 +1532,68 @@ func (fake *TxSimulator) SetPrivateDataReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *TxSimulator) SetPrivateDataWithBlindingFactor(namespace string, collection string, key string, value []byte, blindingFactor []byte) error {
+	var valueCopy []byte
+	if value != nil {
+		valueCopy = make([]byte, len(value))
+		copy(valueCopy, value)
+	}
+	var blindingFactorCopy []byte
+	if blindingFactor != nil {
+		blindingFactorCopy = make([]byte, len(blindingFactor))
+		copy(blindingFactorCopy, blindingFactor)
+	}
+	fake.setPrivateDataWithBlindingFactorMutex.Lock()
+	ret, specificReturn := fake.setPrivateDataWithBlindingFactorReturnsOnCall[len(fake.setPrivateDataWithBlindingFactorArgsForCall)]
+	fake.setPrivateDataWithBlindingFactorArgsForCall = append(fake.setPrivateDataWithBlindingFactorArgsForCall, struct {
+		namespace      string
+		collection     string
+		key            string
+		value          []byte
+		blindingFactor []byte
+	}{namespace, collection, key, valueCopy, blindingFactorCopy})
+	fake.recordInvocation("SetPrivateDataWithBlindingFactor", []interface{}{namespace, collection, key, valueCopy, blindingFactorCopy})
+	fake.setPrivateDataWithBlindingFactorMutex.Unlock()
+	if fake.SetPrivateDataWithBlindingFactorStub != nil {
+		return fake.SetPrivateDataWithBlindingFactorStub(namespace, collection, key, value, blindingFactor)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.setPrivateDataWithBlindingFactorReturns.result1
+}
+
+func (fake *TxSimulator) SetPrivateDataWithBlindingFactorCallCount() int {
+	fake.setPrivateDataWithBlindingFactorMutex.RLock()
+	defer fake.setPrivateDataWithBlindingFactorMutex.RUnlock()
+	return len(fake.setPrivateDataWithBlindingFactorArgsForCall)
+}
+
+func (fake *TxSimulator) SetPrivateDataWithBlindingFactorArgsForCall(i int) (string, string, string, []byte, []byte) {
+	fake.setPrivateDataWithBlindingFactorMutex.RLock()
+	defer fake.setPrivateDataWithBlindingFactorMutex.RUnlock()
+	return fake.setPrivateDataWithBlindingFactorArgsForCall[i].namespace, fake.setPrivateDataWithBlindingFactorArgsForCall[i].collection, fake.setPrivateDataWithBlindingFactorArgsForCall[i].key, fake.setPrivateDataWithBlindingFactorArgsForCall[i].value, fake.setPrivateDataWithBlindingFactorArgsForCall[i].blindingFactor
+}
+
+func (fake *TxSimulator) SetPrivateDataWithBlindingFactorReturns(result1 error) {
+	fake.SetPrivateDataWithBlindingFactorStub = nil
+	fake.setPrivateDataWithBlindingFactorReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *TxSimulator) SetPrivateDataWithBlindingFactorReturnsOnCall(i int, result1 error) {
+	fake.SetPrivateDataWithBlindingFactorStub = nil
+	if fake.setPrivateDataWithBlindingFactorReturnsOnCall == nil {
+		fake.setPrivateDataWithBlindingFactorReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setPrivateDataWithBlindingFactorReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *TxSimulator) SetPrivateDataMultipleKeys(namespace string, collection string, kvs map[string][]byte) error {
 	fake.setPrivateDataMultipleKeysMutex.Lock()
 	ret, specificReturn := fake.setPrivateDataMultipleKeysReturnsOnCall[len(fake.setPrivateDataMultipleKeysArgsForCall)]
@@ -1635,6 +1795,59 @@ func (fake *TxSimulator) DeletePrivateDataMetadataReturnsOnCall(i int, result1 e
 	}{result1}
 }
 
+func (fake *TxSimulator) GetPrivateDataUnvalidated(namespace string, collection string, key string) ([]byte, error) {
+	fake.getPrivateDataUnvalidatedMutex.Lock()
+	ret, specificReturn := fake.getPrivateDataUnvalidatedReturnsOnCall[len(fake.getPrivateDataUnvalidatedArgsForCall)]
+	fake.getPrivateDataUnvalidatedArgsForCall = append(fake.getPrivateDataUnvalidatedArgsForCall, struct {
+		namespace  string
+		collection string
+		key        string
+	}{namespace, collection, key})
+	fake.recordInvocation("GetPrivateDataUnvalidated", []interface{}{namespace, collection, key})
+	fake.getPrivateDataUnvalidatedMutex.Unlock()
+	if fake.GetPrivateDataUnvalidatedStub != nil {
+		return fake.GetPrivateDataUnvalidatedStub(namespace, collection, key)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getPrivateDataUnvalidatedReturns.result1, fake.getPrivateDataUnvalidatedReturns.result2
+}
+
+func (fake *TxSimulator) GetPrivateDataUnvalidatedCallCount() int {
+	fake.getPrivateDataUnvalidatedMutex.RLock()
+	defer fake.getPrivateDataUnvalidatedMutex.RUnlock()
+	return len(fake.getPrivateDataUnvalidatedArgsForCall)
+}
+
+func (fake *TxSimulator) GetPrivateDataUnvalidatedArgsForCall(i int) (string, string, string) {
+	fake.getPrivateDataUnvalidatedMutex.RLock()
+	defer fake.getPrivateDataUnvalidatedMutex.RUnlock()
+	return fake.getPrivateDataUnvalidatedArgsForCall[i].namespace, fake.getPrivateDataUnvalidatedArgsForCall[i].collection, fake.getPrivateDataUnvalidatedArgsForCall[i].key
+}
+
+func (fake *TxSimulator) GetPrivateDataUnvalidatedReturns(result1 []byte, result2 error) {
+	fake.GetPrivateDataUnvalidatedStub = nil
+	fake.getPrivateDataUnvalidatedReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *TxSimulator) GetPrivateDataUnvalidatedReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.GetPrivateDataUnvalidatedStub = nil
+	if fake.getPrivateDataUnvalidatedReturnsOnCall == nil {
+		fake.getPrivateDataUnvalidatedReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.getPrivateDataUnvalidatedReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *TxSimulator) GetTxSimulationResults() (*ledger.TxSimulationResults, error) {
 	fake.getTxSimulationResultsMutex.Lock()
 	ret, specificReturn := fake.getTxSimulationResultsReturnsOnCall[len(fake.getTxSimulationResultsArgsForCall)]
@@ -1697,6 +1910,8 @@ func (fake *TxSimulator) Invocations() map[string][][]interface{} {
 	defer fake.executeQueryWithMetadataMutex.RUnlock()
 	fake.getPrivateDataMutex.RLock()
 	defer fake.getPrivateDataMutex.RUnlock()
+	fake.getPrivateDataHashMutex.RLock()
+	defer fake.getPrivateDataHashMutex.RUnlock()
 	fake.getPrivateDataMetadataMutex.RLock()
 	defer fake.getPrivateDataMetadataMutex.RUnlock()
 	fake.getPrivateDataMetadataByHashMutex.RLock()
@@ -1723,6 +1938,8 @@ func (fake *TxSimulator) Invocations() map[string][][]interface{} {
 	defer fake.executeUpdateMutex.RUnlock()
 	fake.setPrivateDataMutex.RLock()
 	defer fake.setPrivateDataMutex.RUnlock()
+	fake.setPrivateDataWithBlindingFactorMutex.RLock()
+	defer fake.setPrivateDataWithBlindingFactorMutex.RUnlock()
 	fake.setPrivateDataMultipleKeysMutex.RLock()
 	defer fake.setPrivateDataMultipleKeysMutex.RUnlock()
 	fake.deletePrivateDataMutex.RLock()
@@ -1731,6 +1948,8 @@ func (fake *TxSimulator) Invocations() map[string][][]interface{} {
 	defer fake.setPrivateDataMetadataMutex.RUnlock()
 	fake.deletePrivateDataMetadataMutex.RLock()
 	defer fake.deletePrivateDataMetadataMutex.RUnlock()
+	fake.getPrivateDataUnvalidatedMutex.RLock()
+	defer fake.getPrivateDataUnvalidatedMutex.RUnlock()
 	fake.getTxSimulationResultsMutex.RLock()
 	defer fake.getTxSimulationResultsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
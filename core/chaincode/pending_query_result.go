@@ -14,6 +14,9 @@ import (
 
 type PendingQueryResult struct {
 	batch []*pb.QueryResultBytes
+	// totalBytes is the size of all the results added, including
+	// those of the batches already cut
+	totalBytes int
 }
 
 func (p *PendingQueryResult) Cut() []*pb.QueryResultBytes {
@@ -29,9 +32,16 @@ func (p *PendingQueryResult) Add(queryResult commonledger.QueryResult) error {
 		return err
 	}
 	p.batch = append(p.batch, &pb.QueryResultBytes{ResultBytes: queryResultBytes})
+	p.totalBytes += len(queryResultBytes)
 	return nil
 }
 
 func (p *PendingQueryResult) Size() int {
 	return len(p.batch)
 }
+
+// TotalBytes returns the size of all the results added since the
+// creation of the PendingQueryResult
+func (p *PendingQueryResult) TotalBytes() int {
+	return p.totalBytes
+}
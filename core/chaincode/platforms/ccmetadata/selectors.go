@@ -0,0 +1,176 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccmetadata
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// selectorOperand describes the operand expected by a selector operator
+type selectorOperand int
+
+const (
+	// anyOperand is any JSON value, e.g. for $eq
+	anyOperand selectorOperand = iota
+	// selectorsOperand is a non empty array of selectors, e.g. for $and
+	selectorsOperand
+	// subSelectorOperand is a selector, e.g. for $elemMatch
+	subSelectorOperand
+	// arrayOperand is an array of values, e.g. for $in
+	arrayOperand
+	// boolOperand is a boolean, e.g. for $exists
+	boolOperand
+	// stringOperand is a string, e.g. for $regex
+	stringOperand
+	// typeOperand is the name of a JSON type, for $type
+	typeOperand
+	// integerOperand is a non negative integer, for $size
+	integerOperand
+	// modOperand is a [divisor, remainder] pair of integers, for $mod
+	modOperand
+)
+
+// selectorOperators are the operators of the CouchDB selector syntax
+var selectorOperators = map[string]selectorOperand{
+	"$and":         selectorsOperand,
+	"$or":          selectorsOperand,
+	"$nor":         selectorsOperand,
+	"$not":         subSelectorOperand,
+	"$elemMatch":   subSelectorOperand,
+	"$allMatch":    subSelectorOperand,
+	"$keyMapMatch": subSelectorOperand,
+	"$all":         arrayOperand,
+	"$in":          arrayOperand,
+	"$nin":         arrayOperand,
+	"$lt":          anyOperand,
+	"$lte":         anyOperand,
+	"$eq":          anyOperand,
+	"$ne":          anyOperand,
+	"$gte":         anyOperand,
+	"$gt":          anyOperand,
+	"$exists":      boolOperand,
+	"$regex":       stringOperand,
+	"$type":        typeOperand,
+	"$size":        integerOperand,
+	"$mod":         modOperand,
+}
+
+var selectorTypes = []string{"null", "boolean", "number", "string", "array", "object"}
+
+// validateSelector checks that the given selector follows the CouchDB selector
+// syntax, the returned errors locate the invalid entry by its path
+func validateSelector(path string, selector map[string]interface{}) error {
+	// iterate in a stable order so that the first invalid entry is always reported
+	keys := make([]string, 0, len(selector))
+	for key := range selector {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := selector[key]
+		keyPath := path + "." + key
+
+		if !strings.HasPrefix(key, "$") {
+			if key == "" {
+				return fmt.Errorf("Invalid selector at %s, field names must not be empty", path)
+			}
+			// a field is either matched against a value, or against a nested selector
+			if subSelector, ok := value.(map[string]interface{}); ok {
+				if err := validateSelector(keyPath, subSelector); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		operand, ok := selectorOperators[key]
+		if !ok {
+			return fmt.Errorf("Invalid selector at %s, unknown operator %s", keyPath, key)
+		}
+		if err := validateOperand(keyPath, operand, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateOperand(path string, operand selectorOperand, value interface{}) error {
+	switch operand {
+
+	case selectorsOperand:
+		selectors, ok := value.([]interface{})
+		if !ok || len(selectors) == 0 {
+			return fmt.Errorf("Invalid selector at %s, expecting a non empty JSON array of selectors", path)
+		}
+		for i, item := range selectors {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			selector, ok := item.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("Invalid selector at %s, expecting a selector", itemPath)
+			}
+			if err := validateSelector(itemPath, selector); err != nil {
+				return err
+			}
+		}
+
+	case subSelectorOperand:
+		selector, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("Invalid selector at %s, expecting a selector", path)
+		}
+		return validateSelector(path, selector)
+
+	case arrayOperand:
+		if _, ok := value.([]interface{}); !ok {
+			return fmt.Errorf("Invalid selector at %s, expecting a JSON array", path)
+		}
+
+	case boolOperand:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("Invalid selector at %s, expecting a boolean", path)
+		}
+
+	case stringOperand:
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("Invalid selector at %s, expecting a string", path)
+		}
+
+	case typeOperand:
+		if typeName, ok := value.(string); !ok || !contains(selectorTypes, typeName) {
+			return fmt.Errorf("Invalid selector at %s, expecting one of %s", path, selectorTypes)
+		}
+
+	case integerOperand:
+		if n, ok := value.(float64); !ok || !isInteger(n) || n < 0 {
+			return fmt.Errorf("Invalid selector at %s, expecting a non negative integer", path)
+		}
+
+	case modOperand:
+		pair, ok := value.([]interface{})
+		if !ok || len(pair) != 2 {
+			return fmt.Errorf("Invalid selector at %s, expecting a [divisor, remainder] JSON array", path)
+		}
+		divisor, ok := pair[0].(float64)
+		if !ok || !isInteger(divisor) || divisor == 0 {
+			return fmt.Errorf("Invalid selector at %s, the divisor must be a non zero integer", path)
+		}
+		if remainder, ok := pair[1].(float64); !ok || !isInteger(remainder) {
+			return fmt.Errorf("Invalid selector at %s, the remainder must be an integer", path)
+		}
+	}
+
+	return nil
+}
+
+func isInteger(n float64) bool {
+	return n == math.Trunc(n)
+}
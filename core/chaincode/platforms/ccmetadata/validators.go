@@ -10,7 +10,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
-	"reflect"
 	"regexp"
 	"strings"
 )
@@ -137,13 +136,13 @@ func selectFileValidator(filePathName string) fileValidator {
 func couchdbIndexFileValidator(fileName string, fileBytes []byte) error {
 
 	// if the content does not validate as JSON, return err to invalidate the file
-	boolIsJSON, indexDefinition := isJSON(fileBytes)
-	if !boolIsJSON {
-		return &InvalidIndexContentError{fmt.Sprintf("Index metadata file [%s] is not a valid JSON", fileName)}
+	indexDefinition, err := parseJSON(fileBytes)
+	if err != nil {
+		return &InvalidIndexContentError{fmt.Sprintf("Index metadata file [%s] is not a valid JSON: %s", fileName, err)}
 	}
 
 	// validate the index definition
-	err := validateIndexJSON(indexDefinition)
+	err = validateIndexJSON(indexDefinition)
 	if err != nil {
 		return &InvalidIndexContentError{fmt.Sprintf("Index metadata file [%s] is not a valid index definition: %s", fileName, err)}
 	}
@@ -154,8 +153,50 @@ func couchdbIndexFileValidator(fileName string, fileBytes []byte) error {
 
 // isJSON tests a string to determine if it can be parsed as valid JSON
 func isJSON(s []byte) (bool, map[string]interface{}) {
+	js, err := parseJSON(s)
+	return err == nil, js
+}
+
+// parseJSON parses a JSON object, the returned errors locate syntax errors by
+// their line and column
+func parseJSON(s []byte) (map[string]interface{}, error) {
 	var js map[string]interface{}
-	return json.Unmarshal([]byte(s), &js) == nil, js
+	err := json.Unmarshal(s, &js)
+	switch err := err.(type) {
+	case nil:
+		if js == nil {
+			return nil, fmt.Errorf("expecting a JSON object")
+		}
+		return js, nil
+	case *json.SyntaxError:
+		// the offset of a syntax error is past the invalid character
+		line, column := position(s, err.Offset-1)
+		return nil, fmt.Errorf("%s at line %d, column %d", err, line, column)
+	case *json.UnmarshalTypeError:
+		return nil, fmt.Errorf("expecting a JSON object, found a JSON %s", err.Value)
+	default:
+		return nil, err
+	}
+}
+
+// position returns the line and column of the given offset
+func position(s []byte, offset int64) (int, int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > int64(len(s)) {
+		offset = int64(len(s))
+	}
+	line, column := 1, 1
+	for _, c := range s[:offset] {
+		if c == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	return line, column
 }
 
 func validateIndexJSON(indexDefinition map[string]interface{}) error {
@@ -171,11 +212,12 @@ func validateIndexJSON(indexDefinition map[string]interface{}) error {
 
 		case "index":
 
-			if reflect.TypeOf(jsonValue).Kind() != reflect.Map {
+			indexMap, ok := jsonValue.(map[string]interface{})
+			if !ok {
 				return fmt.Errorf("Invalid entry, \"index\" must be a JSON")
 			}
 
-			err := processIndexMap(jsonValue.(map[string]interface{}))
+			err := processIndexMap(indexMap)
 			if err != nil {
 				return err
 			}
@@ -185,7 +227,7 @@ func validateIndexJSON(indexDefinition map[string]interface{}) error {
 		case "ddoc":
 
 			//Verify the design doc is a string
-			if reflect.TypeOf(jsonValue).Kind() != reflect.String {
+			if _, ok := jsonValue.(string); !ok {
 				return fmt.Errorf("Invalid entry, \"ddoc\" must be a string")
 			}
 
@@ -194,7 +236,7 @@ func validateIndexJSON(indexDefinition map[string]interface{}) error {
 		case "name":
 
 			//Verify the name is a string
-			if reflect.TypeOf(jsonValue).Kind() != reflect.String {
+			if _, ok := jsonValue.(string); !ok {
 				return fmt.Errorf("Invalid entry, \"name\" must be a string")
 			}
 
@@ -217,7 +259,7 @@ func validateIndexJSON(indexDefinition map[string]interface{}) error {
 	}
 
 	if !indexIncluded {
-		return fmt.Errorf("Index definition must include a \"fields\" definition")
+		return fmt.Errorf("Index definition must include an \"index\" definition")
 	}
 
 	return nil
@@ -228,6 +270,9 @@ func validateIndexJSON(indexDefinition map[string]interface{}) error {
 //the next level of the json query
 func processIndexMap(jsonFragment map[string]interface{}) error {
 
+	//flag to track if the "fields" key is included
+	fieldsIncluded := false
+
 	//iterate the item in the map
 	for jsonKey, jsonValue := range jsonFragment {
 
@@ -239,33 +284,49 @@ func processIndexMap(jsonFragment map[string]interface{}) error {
 
 			case []interface{}:
 
+				if len(jsonValueType) == 0 {
+					return fmt.Errorf("Index must include at least one field")
+				}
+
 				//iterate the index field objects
-				for _, itemValue := range jsonValueType {
+				for i, itemValue := range jsonValueType {
 
-					switch reflect.TypeOf(itemValue).Kind() {
+					switch itemValueType := itemValue.(type) {
 
-					case reflect.String:
+					case string:
 						//String is a valid field descriptor  ex: "color", "size"
 						logger.Debugf("Found index field name: \"%s\"", itemValue)
 
-					case reflect.Map:
+					case map[string]interface{}:
 						//Handle the case where a sort is included  ex: {"size":"asc"}, {"color":"desc"}
-						err := validateFieldMap(itemValue.(map[string]interface{}))
+						err := validateFieldMap(itemValueType)
 						if err != nil {
 							return err
 						}
 
+					default:
+						return fmt.Errorf("Invalid field definition at index.fields[%d], fields must be names or in the form \"fieldname\":\"sort\"", i)
+
 					}
 				}
 
+				fieldsIncluded = true
+
 			default:
 				return fmt.Errorf("Expecting a JSON array of fields")
 			}
 
 		case "partial_filter_selector":
 
-			//TODO - add support for partial filter selector, for now return nil
-			//Take no other action, will be considered valid for now
+			selector, ok := jsonValue.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("Invalid entry, \"partial_filter_selector\" must be a JSON")
+			}
+
+			err := validateSelector("index.partial_filter_selector", selector)
+			if err != nil {
+				return err
+			}
 
 		default:
 
@@ -277,6 +338,10 @@ func processIndexMap(jsonFragment map[string]interface{}) error {
 
 	}
 
+	if !fieldsIncluded {
+		return fmt.Errorf("Index definition must include a \"fields\" definition")
+	}
+
 	return nil
 
 }
@@ -284,6 +349,10 @@ func processIndexMap(jsonFragment map[string]interface{}) error {
 //validateFieldMap validates the list of field objects
 func validateFieldMap(jsonFragment map[string]interface{}) error {
 
+	if len(jsonFragment) != 1 {
+		return fmt.Errorf("Invalid field definition, fields must be in the form \"fieldname\":\"sort\"")
+	}
+
 	//iterate the fields to validate the sort criteria
 	for jsonKey, jsonValue := range jsonFragment {
 
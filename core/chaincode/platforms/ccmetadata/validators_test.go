@@ -292,6 +292,62 @@ func TestIndexValidationInvalidFields(t *testing.T) {
 
 }
 
+func TestIndexValidationPartialFilterSelector(t *testing.T) {
+
+	// Test valid selectors
+	for _, selector := range []string{
+		`{"status":{"$ne":"archived"}}`,
+		`{"$and":[{"size":{"$gt":10}},{"color":{"$in":["red","blue"]}}]}`,
+		`{"owner":{"name":"tom"},"tags":{"$elemMatch":{"$eq":"new"}},"$not":{"size":{"$exists":false}}}`,
+		`{"size":{"$mod":[2,0]},"tags":{"$size":2},"color":{"$type":"string","$regex":"^r"}}`,
+	} {
+		indexDef := []byte(`{"index":{"fields":["size"],"partial_filter_selector":` + selector + `}}`)
+		_, indexDefinition := isJSON(indexDef)
+		err := validateIndexJSON(indexDefinition)
+		assert.NoError(t, err, selector)
+	}
+
+	// Test invalid selectors
+	for selector, expectedErr := range map[string]string{
+		`"archived"`:                          `Invalid entry, "partial_filter_selector" must be a JSON`,
+		`{"status":{"$notequal":"archived"}}`: "Invalid selector at index.partial_filter_selector.status.$notequal, unknown operator $notequal",
+		`{"$and":{"size":1}}`:                 "Invalid selector at index.partial_filter_selector.$and, expecting a non empty JSON array of selectors",
+		`{"$or":[{"size":1},"color"]}`:        "Invalid selector at index.partial_filter_selector.$or[1], expecting a selector",
+		`{"$or":[{"size":{"$in":"red"}}]}`:    "Invalid selector at index.partial_filter_selector.$or[0].size.$in, expecting a JSON array",
+		`{"size":{"$exists":"yes"}}`:          "Invalid selector at index.partial_filter_selector.size.$exists, expecting a boolean",
+		`{"size":{"$type":"integer"}}`:        "Invalid selector at index.partial_filter_selector.size.$type, expecting one of [null boolean number string array object]",
+		`{"tags":{"$size":1.5}}`:              "Invalid selector at index.partial_filter_selector.tags.$size, expecting a non negative integer",
+		`{"size":{"$mod":[0,1]}}`:             "Invalid selector at index.partial_filter_selector.size.$mod, the divisor must be a non zero integer",
+		`{"size":{"$mod":[2]}}`:               "Invalid selector at index.partial_filter_selector.size.$mod, expecting a [divisor, remainder] JSON array",
+		`{"":1}`:                              "Invalid selector at index.partial_filter_selector, field names must not be empty",
+	} {
+		indexDef := []byte(`{"index":{"fields":["size"],"partial_filter_selector":` + selector + `}}`)
+		_, indexDefinition := isJSON(indexDef)
+		err := validateIndexJSON(indexDefinition)
+		assert.EqualError(t, err, expectedErr, selector)
+	}
+}
+
+func TestIndexValidationErrors(t *testing.T) {
+	fileName := "META-INF/statedb/couchdb/indexes/myIndex.json"
+
+	for indexDef, expectedErr := range map[string]string{
+		"{\n  \"index\": {\"fields\": [\"size\",]}\n}": "Index metadata file [" + fileName + "] is not a valid JSON: invalid character ']' looking for beginning of value at line 2, column 31",
+		`["size"]`:                         "Index metadata file [" + fileName + "] is not a valid JSON: expecting a JSON object, found a JSON array",
+		`null`:                             "Index metadata file [" + fileName + "] is not a valid JSON: expecting a JSON object",
+		`{"index":null}`:                   "Index metadata file [" + fileName + "] is not a valid index definition: Invalid entry, \"index\" must be a JSON",
+		`{"index":{"fields":[]}}`:          "Index metadata file [" + fileName + "] is not a valid index definition: Index must include at least one field",
+		`{"index":{"fields":["size", 1]}}`: "Index metadata file [" + fileName + "] is not a valid index definition: Invalid field definition at index.fields[1], fields must be names or in the form \"fieldname\":\"sort\"",
+		`{"index":{"fields":[{"size":"asc","color":"asc"}]}}`: "Index metadata file [" + fileName + "] is not a valid index definition: Invalid field definition, fields must be in the form \"fieldname\":\"sort\"",
+		`{"index":{"partial_filter_selector":{}}}`:            "Index metadata file [" + fileName + "] is not a valid index definition: Index definition must include a \"fields\" definition",
+	} {
+		err := ValidateMetadataFile(fileName, []byte(indexDef))
+		assert.EqualError(t, err, expectedErr, indexDef)
+		_, ok := err.(*InvalidIndexContentError)
+		assert.True(t, ok, "Should have received an InvalidIndexContentError")
+	}
+}
+
 func cleanupDir(dir string) error {
 	// clean up any previous files
 	err := os.RemoveAll(dir)
@@ -7,13 +7,69 @@ SPDX-License-Identifier: Apache-2.0
 package chaincode
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/golang/protobuf/proto"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	pb "github.com/hyperledger/fabric/protos/peer"
 )
 
+// The codes of the errors returned when a query exceeds a limit
+const (
+	QueryTimeoutExceeded      = "QUERY_TIMEOUT_EXCEEDED"
+	QueryResultsExceeded      = "QUERY_RESULTS_EXCEEDED"
+	QueryResponseSizeExceeded = "QUERY_RESPONSE_SIZE_EXCEEDED"
+)
+
+// QueryLimits bounds the resources used by a query of a chaincode.
+// A zero limit means no limit.
+type QueryLimits struct {
+	// ExecutionTimeout is the maximum time a query iterator stays open
+	ExecutionTimeout time.Duration
+	// TotalResults is the maximum number of results returned by a query
+	TotalResults int
+	// ResponseBytes is the maximum size of the results returned by a query
+	ResponseBytes int
+}
+
+// QueryLimitError is returned when a query exceeds one of the QueryLimits
+type QueryLimitError struct {
+	Code    string
+	Message string
+}
+
+func (e *QueryLimitError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func (l QueryLimits) check(txContext *TransactionContext, iterID string, pendingQueryResults *PendingQueryResult, totalReturnCount int32) error {
+	if start := txContext.GetQueryStartTime(iterID); l.ExecutionTimeout > 0 && !start.IsZero() {
+		if elapsed := time.Since(start); elapsed > l.ExecutionTimeout {
+			return &QueryLimitError{
+				Code:    QueryTimeoutExceeded,
+				Message: fmt.Sprintf("query ran for %s, exceeding the limit of %s", elapsed, l.ExecutionTimeout),
+			}
+		}
+	}
+	if l.TotalResults > 0 && int(totalReturnCount) > l.TotalResults {
+		return &QueryLimitError{
+			Code:    QueryResultsExceeded,
+			Message: fmt.Sprintf("query returned more than %d results", l.TotalResults),
+		}
+	}
+	if l.ResponseBytes > 0 && pendingQueryResults.TotalBytes() > l.ResponseBytes {
+		return &QueryLimitError{
+			Code:    QueryResponseSizeExceeded,
+			Message: fmt.Sprintf("query results exceed the limit of %d bytes", l.ResponseBytes),
+		}
+	}
+	return nil
+}
+
 type QueryResponseGenerator struct {
 	MaxResultLimit int
+	Limits         QueryLimits
 }
 
 // BuildQueryResponse takes an iterator and fetch state to construct QueryResponse
@@ -26,7 +82,7 @@ func (q *QueryResponseGenerator) BuildQueryResponse(txContext *TransactionContex
 	for {
 		// if the total count has been reached, return the result and prevent the Next() being called
 		if *totalReturnCount >= totalReturnLimit {
-			return createQueryResponse(txContext, iterID, isPaginated, pendingQueryResults, *totalReturnCount)
+			return createQueryResponse(txContext, iter, iterID, isPaginated, pendingQueryResults, *totalReturnCount)
 		}
 
 		queryResult, err := iter.Next()
@@ -38,7 +94,7 @@ func (q *QueryResponseGenerator) BuildQueryResponse(txContext *TransactionContex
 
 		case queryResult == nil:
 
-			return createQueryResponse(txContext, iterID, isPaginated, pendingQueryResults, *totalReturnCount)
+			return createQueryResponse(txContext, iter, iterID, isPaginated, pendingQueryResults, *totalReturnCount)
 
 		case !isPaginated && pendingQueryResults.Size() == q.MaxResultLimit:
 			// if explicit pagination is not used
@@ -51,7 +107,15 @@ func (q *QueryResponseGenerator) BuildQueryResponse(txContext *TransactionContex
 				return nil, err
 			}
 			*totalReturnCount++
-			return &pb.QueryResponse{Results: batch, HasMore: true, Id: iterID}, nil
+			if err := q.enforceLimits(txContext, iterID, pendingQueryResults, *totalReturnCount); err != nil {
+				return nil, err
+			}
+			warningMetadata, err := createWarningMetadata(iter)
+			if err != nil {
+				txContext.CleanupQueryContext(iterID)
+				return nil, err
+			}
+			return &pb.QueryResponse{Results: batch, HasMore: true, Id: iterID, Metadata: warningMetadata}, nil
 
 		default:
 			if err := pendingQueryResults.Add(queryResult); err != nil {
@@ -59,11 +123,24 @@ func (q *QueryResponseGenerator) BuildQueryResponse(txContext *TransactionContex
 				return nil, err
 			}
 			*totalReturnCount++
+			if err := q.enforceLimits(txContext, iterID, pendingQueryResults, *totalReturnCount); err != nil {
+				return nil, err
+			}
 		}
 	}
 }
 
-func createQueryResponse(txContext *TransactionContext, iterID string, isPaginated bool, pendingQueryResults *PendingQueryResult, totalReturnCount int32) (*pb.QueryResponse, error) {
+// enforceLimits aborts the query if it exceeds one of the limits
+func (q *QueryResponseGenerator) enforceLimits(txContext *TransactionContext, iterID string, pendingQueryResults *PendingQueryResult, totalReturnCount int32) error {
+	err := q.Limits.check(txContext, iterID, pendingQueryResults, totalReturnCount)
+	if err != nil {
+		chaincodeLogger.Warningf("Aborting query %s: %s", iterID, err)
+		txContext.CleanupQueryContext(iterID)
+	}
+	return err
+}
+
+func createQueryResponse(txContext *TransactionContext, iter commonledger.ResultsIterator, iterID string, isPaginated bool, pendingQueryResults *PendingQueryResult, totalReturnCount int32) (*pb.QueryResponse, error) {
 
 	batch := pendingQueryResults.Cut()
 
@@ -71,6 +148,10 @@ func createQueryResponse(txContext *TransactionContext, iterID string, isPaginat
 		// when explicit pagination is enabled, return the batch with the responseMetadata
 		bookmark := txContext.CleanupQueryContextWithBookmark(iterID)
 		responseMetadata := createResponseMetadata(totalReturnCount, bookmark)
+		// let the chaincode know about the queries the state database cannot serve efficiently
+		if queryWarner, ok := iter.(commonledger.QueryWarner); ok {
+			responseMetadata.Warning = queryWarner.GetWarning()
+		}
 		responseMetadataBytes, err := proto.Marshal(responseMetadata)
 		if err != nil {
 			return nil, err
@@ -80,8 +161,23 @@ func createQueryResponse(txContext *TransactionContext, iterID string, isPaginat
 
 	// if explicit pagination is not used, then the end of the resultset has been reached, return the batch
 	txContext.CleanupQueryContext(iterID)
-	return &pb.QueryResponse{Results: batch, HasMore: false, Id: iterID}, nil
+	warningMetadata, err := createWarningMetadata(iter)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.QueryResponse{Results: batch, HasMore: false, Id: iterID, Metadata: warningMetadata}, nil
+
+}
 
+// createWarningMetadata returns the metadata holding the warning of the state
+// database about the execution of a rich query without pagination, which is
+// nil if there is no warning
+func createWarningMetadata(iter commonledger.ResultsIterator) ([]byte, error) {
+	queryWarner, ok := iter.(commonledger.QueryWarner)
+	if !ok || queryWarner.GetWarning() == "" {
+		return nil, nil
+	}
+	return proto.Marshal(&pb.QueryResponseMetadata{Warning: queryWarner.GetWarning()})
 }
 
 func createResponseMetadata(returnCount int32, bookmark string) *pb.QueryResponseMetadata {
@@ -11,10 +11,13 @@ import (
 	"fmt"
 	"math"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/core/chaincode"
 	"github.com/hyperledger/fabric/core/chaincode/mock"
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -161,3 +164,136 @@ func TestBuildQueryResponseErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildQueryResponseLimits(t *testing.T) {
+	queryResult := &queryresult.KV{
+		Key:       "key",
+		Namespace: "namespace",
+		Value:     []byte("value"),
+	}
+	resultBytes, err := proto.Marshal(queryResult)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		limits       chaincode.QueryLimits
+		expectedCode string
+	}{
+		{"results", chaincode.QueryLimits{TotalResults: 5}, chaincode.QueryResultsExceeded},
+		{"bytes", chaincode.QueryLimits{ResponseBytes: 3 * len(resultBytes)}, chaincode.QueryResponseSizeExceeded},
+		{"timeout", chaincode.QueryLimits{ExecutionTimeout: time.Nanosecond}, chaincode.QueryTimeoutExceeded},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			txSimulator := &mock.TxSimulator{}
+			transactionContext := &chaincode.TransactionContext{TXSimulator: txSimulator}
+			resultsIterator := &mock.QueryResultsIterator{}
+			resultsIterator.NextReturns(queryResult, nil)
+			transactionContext.InitializeQueryContext("query-id", resultsIterator)
+			time.Sleep(time.Millisecond)
+
+			responseGenerator := &chaincode.QueryResponseGenerator{
+				MaxResultLimit: 100,
+				Limits:         tc.limits,
+			}
+			resp, err := responseGenerator.BuildQueryResponse(transactionContext, resultsIterator, "query-id", false, totalQueryLimit)
+			assert.Nil(t, resp)
+			if assert.IsType(t, &chaincode.QueryLimitError{}, err) {
+				assert.Equal(t, tc.expectedCode, err.(*chaincode.QueryLimitError).Code)
+			}
+			assert.Equal(t, 1, resultsIterator.CloseCallCount())
+			assert.Nil(t, transactionContext.GetQueryIterator("query-id"))
+		})
+	}
+
+	t.Run("within limits", func(t *testing.T) {
+		txSimulator := &mock.TxSimulator{}
+		transactionContext := &chaincode.TransactionContext{TXSimulator: txSimulator}
+		resultsIterator := &mock.QueryResultsIterator{}
+		for i := 0; i < 5; i++ {
+			resultsIterator.NextReturnsOnCall(i, queryResult, nil)
+		}
+		resultsIterator.NextReturnsOnCall(5, nil, nil)
+		transactionContext.InitializeQueryContext("query-id", resultsIterator)
+
+		responseGenerator := &chaincode.QueryResponseGenerator{
+			MaxResultLimit: 100,
+			Limits: chaincode.QueryLimits{
+				ExecutionTimeout: time.Minute,
+				TotalResults:     5,
+				ResponseBytes:    5 * len(resultBytes),
+			},
+		}
+		resp, err := responseGenerator.BuildQueryResponse(transactionContext, resultsIterator, "query-id", false, totalQueryLimit)
+		assert.NoError(t, err)
+		assert.Len(t, resp.GetResults(), 5)
+	})
+}
+
+type warningResultsIterator struct {
+	*mock.QueryResultsIterator
+	warning string
+}
+
+func (w *warningResultsIterator) GetWarning() string {
+	return w.warning
+}
+
+func TestBuildQueryResponseWarning(t *testing.T) {
+	txSimulator := &mock.TxSimulator{}
+	transactionContext := &chaincode.TransactionContext{TXSimulator: txSimulator}
+	resultsIterator := &warningResultsIterator{
+		QueryResultsIterator: &mock.QueryResultsIterator{},
+		warning:              "no matching index found, create an index to optimize query time",
+	}
+	resultsIterator.NextReturnsOnCall(0, &queryresult.KV{Key: "key"}, nil)
+	resultsIterator.NextReturnsOnCall(1, nil, nil)
+	resultsIterator.GetBookmarkAndCloseReturns("bookmark")
+	transactionContext.InitializeQueryContext("query-id", resultsIterator)
+
+	responseGenerator := &chaincode.QueryResponseGenerator{MaxResultLimit: 100}
+	resp, err := responseGenerator.BuildQueryResponse(transactionContext, resultsIterator, "query-id", true, totalQueryLimit)
+	assert.NoError(t, err)
+	metadata := &pb.QueryResponseMetadata{}
+	assert.NoError(t, proto.Unmarshal(resp.Metadata, metadata))
+	assert.Equal(t, &pb.QueryResponseMetadata{
+		FetchedRecordsCount: 1,
+		Bookmark:            "bookmark",
+		Warning:             "no matching index found, create an index to optimize query time",
+	}, metadata)
+}
+
+func TestBuildQueryResponseWarningWithoutPagination(t *testing.T) {
+	txSimulator := &mock.TxSimulator{}
+	transactionContext := &chaincode.TransactionContext{TXSimulator: txSimulator}
+	resultsIterator := &warningResultsIterator{
+		QueryResultsIterator: &mock.QueryResultsIterator{},
+		warning:              "no matching index found, create an index to optimize query time",
+	}
+	resultsIterator.NextReturnsOnCall(0, &queryresult.KV{Key: "key1"}, nil)
+	resultsIterator.NextReturnsOnCall(1, &queryresult.KV{Key: "key2"}, nil)
+	resultsIterator.NextReturnsOnCall(2, nil, nil)
+	transactionContext.InitializeQueryContext("query-id", resultsIterator)
+
+	// the warning is set on every batch of the results
+	responseGenerator := &chaincode.QueryResponseGenerator{MaxResultLimit: 1}
+	for _, hasMore := range []bool{true, false} {
+		resp, err := responseGenerator.BuildQueryResponse(transactionContext, resultsIterator, "query-id", false, totalQueryLimit)
+		assert.NoError(t, err)
+		assert.Equal(t, hasMore, resp.HasMore)
+		metadata := &pb.QueryResponseMetadata{}
+		assert.NoError(t, proto.Unmarshal(resp.Metadata, metadata))
+		assert.Equal(t, &pb.QueryResponseMetadata{
+			Warning: "no matching index found, create an index to optimize query time",
+		}, metadata)
+	}
+
+	// the queries without warning have no metadata
+	resultsIterator.warning = ""
+	resultsIterator.NextReturnsOnCall(3, nil, nil)
+	transactionContext.InitializeQueryContext("other-query-id", resultsIterator)
+	resp, err := responseGenerator.BuildQueryResponse(transactionContext, resultsIterator, "other-query-id", false, totalQueryLimit)
+	assert.NoError(t, err)
+	assert.Nil(t, resp.Metadata)
+}
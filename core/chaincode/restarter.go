@@ -0,0 +1,269 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/pkg/errors"
+)
+
+// RestartPolicy configures the automatic restart of the chaincode containers
+// whose stream to the peer closed unexpectedly
+type RestartPolicy struct {
+	// MaxRestarts is the number of consecutive restarts of a chaincode after
+	// which its circuit breaker opens, zero disabling the restarts
+	MaxRestarts int
+	// InitialBackoff is the delay before the first restart of a chaincode,
+	// doubled for every further consecutive restart
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum delay before a restart
+	MaxBackoff time.Duration
+	// StableTime is the time a chaincode must run for its next exit not to
+	// count as a consecutive restart
+	StableTime time.Duration
+	// CircuitBreakTime is the time during which the launches of a chaincode
+	// whose circuit breaker opened fail without starting its container
+	CircuitBreakTime time.Duration
+}
+
+// The types of the restart events
+const (
+	RestartScheduled = "restart_scheduled"
+	Restarted        = "restarted"
+	RestartFailed    = "restart_failed"
+	CircuitOpened    = "circuit_opened"
+)
+
+// maxRestartEvents is the number of most recent events a Restarter keeps
+const maxRestartEvents = 100
+
+// RestartEvent is an event of the automatic restarts of a chaincode
+type RestartEvent struct {
+	Time      time.Time `json:"time"`
+	Chaincode string    `json:"chaincode"`
+	Type      string    `json:"type"`
+	Message   string    `json:"message,omitempty"`
+}
+
+// launchFunc launches the container of a chaincode on behalf of a channel
+type launchFunc func(chainID, cname string, ccci *ccprovider.ChaincodeContainerInfo) error
+
+// Restarter relaunches the chaincode containers which exit while the peer
+// runs them, with an exponential backoff. A chaincode which keeps exiting
+// opens its circuit breaker, so that its launches fail fast until the circuit
+// break time has elapsed; the next exit of the chaincode then opens the
+// circuit again unless it ran for the stable time.
+type Restarter struct {
+	policy  RestartPolicy
+	launch  launchFunc
+	metrics metrics.Scope
+
+	mutex      sync.Mutex
+	chaincodes map[string]*restartState
+	events     []RestartEvent
+}
+
+// restartState tracks the consecutive restarts of a chaincode
+type restartState struct {
+	chainID   string
+	ccci      *ccprovider.ChaincodeContainerInfo
+	launched  time.Time
+	restarts  int
+	openUntil time.Time
+	timer     *time.Timer
+
+	restartCount   metrics.Counter
+	restartFailure metrics.Counter
+	circuitBreaks  metrics.Counter
+	circuitOpen    metrics.Gauge
+}
+
+// NewRestarter returns a restarter launching the chaincodes with the given
+// function, or nil if the policy disables the restarts
+func NewRestarter(policy RestartPolicy, launch launchFunc, scope metrics.Scope) *Restarter {
+	if policy.MaxRestarts <= 0 {
+		return nil
+	}
+	if scope == nil {
+		scope = metrics.NewNoOpScope()
+	}
+	return &Restarter{
+		policy:     policy,
+		launch:     launch,
+		metrics:    scope,
+		chaincodes: map[string]*restartState{},
+	}
+}
+
+// Allow returns an error if the circuit breaker of the chaincode is open
+func (r *Restarter) Allow(cname string) error {
+	if r == nil {
+		return nil
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	state := r.chaincodes[cname]
+	if state != nil && time.Now().Before(state.openUntil) {
+		return errors.Errorf("chaincode %s exited %d times in a row, its launches are suspended until %s", cname, state.restarts, state.openUntil.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Launched records the launch of the chaincode on behalf of the channel,
+// which is used to restart it
+func (r *Restarter) Launched(chainID, cname string, ccci *ccprovider.ChaincodeContainerInfo) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	state := r.chaincodes[cname]
+	if state == nil {
+		scope := r.metrics.Tagged(map[string]string{"chaincode": cname})
+		state = &restartState{
+			restartCount:   scope.Counter("restarts"),
+			restartFailure: scope.Counter("restart_failures"),
+			circuitBreaks:  scope.Counter("circuit_breaks"),
+			circuitOpen:    scope.Gauge("circuit_open"),
+		}
+		r.chaincodes[cname] = state
+	}
+	state.chainID = chainID
+	state.ccci = ccci
+	state.launched = time.Now()
+	state.circuitOpen.Update(0)
+}
+
+// Stopped forgets the chaincode stopped by the peer, which is not restarted
+func (r *Restarter) Stopped(cname string) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if state := r.chaincodes[cname]; state != nil {
+		if state.timer != nil {
+			state.timer.Stop()
+		}
+		state.circuitOpen.Update(0)
+		delete(r.chaincodes, cname)
+	}
+}
+
+// Exited schedules the restart of the chaincode whose stream closed, unless
+// it was stopped by the peer or its circuit breaker opens
+func (r *Restarter) Exited(cname string) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	state := r.chaincodes[cname]
+	if state == nil || state.timer != nil {
+		return
+	}
+	if time.Since(state.launched) >= r.policy.StableTime {
+		state.restarts = 0
+	}
+	r.scheduleRestart(cname, state)
+}
+
+// scheduleRestart restarts the chaincode after its backoff, or opens its
+// circuit breaker if it reached the maximum number of restarts
+func (r *Restarter) scheduleRestart(cname string, state *restartState) {
+	if state.restarts >= r.policy.MaxRestarts {
+		state.openUntil = time.Now().Add(r.policy.CircuitBreakTime)
+		state.circuitBreaks.Inc(1)
+		state.circuitOpen.Update(1)
+		r.record(cname, CircuitOpened, fmt.Sprintf("exited %d times in a row, launches suspended until %s", state.restarts, state.openUntil.Format(time.RFC3339)))
+		chaincodeLogger.Errorf("Chaincode %s exited %d times in a row, suspending its launches until %s", cname, state.restarts, state.openUntil.Format(time.RFC3339))
+		return
+	}
+
+	backoff := r.policy.InitialBackoff << uint(state.restarts)
+	if backoff > r.policy.MaxBackoff || backoff <= 0 {
+		backoff = r.policy.MaxBackoff
+	}
+	state.restarts++
+	r.record(cname, RestartScheduled, fmt.Sprintf("restart %d of %d in %s", state.restarts, r.policy.MaxRestarts, backoff))
+	chaincodeLogger.Warningf("Chaincode %s exited, restarting it in %s (restart %d of %d)", cname, backoff, state.restarts, r.policy.MaxRestarts)
+	state.timer = time.AfterFunc(backoff, func() { r.restart(cname, state) })
+}
+
+func (r *Restarter) restart(cname string, state *restartState) {
+	r.mutex.Lock()
+	if r.chaincodes[cname] != state {
+		// the chaincode was stopped meanwhile
+		r.mutex.Unlock()
+		return
+	}
+	state.timer = nil
+	chainID, ccci := state.chainID, state.ccci
+	r.mutex.Unlock()
+
+	state.restartCount.Inc(1)
+	err := r.launch(chainID, cname, ccci)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err == nil {
+		r.record(cname, Restarted, "")
+		chaincodeLogger.Infof("Restarted chaincode %s", cname)
+		return
+	}
+
+	chaincodeLogger.Errorf("Failed restarting chaincode %s: %s", cname, err)
+	state.restartFailure.Inc(1)
+	r.record(cname, RestartFailed, err.Error())
+	if r.chaincodes[cname] == state && state.timer == nil {
+		r.scheduleRestart(cname, state)
+	}
+}
+
+// record keeps the event, forgetting the oldest one if there are too many. It
+// must be called with the mutex held.
+func (r *Restarter) record(cname, eventType, message string) {
+	r.events = append(r.events, RestartEvent{Time: time.Now(), Chaincode: cname, Type: eventType, Message: message})
+	if len(r.events) > maxRestartEvents {
+		r.events = append([]RestartEvent(nil), r.events[len(r.events)-maxRestartEvents:]...)
+	}
+}
+
+// Events returns the most recent restart events, the oldest first
+func (r *Restarter) Events() []RestartEvent {
+	if r == nil {
+		return nil
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]RestartEvent(nil), r.events...)
+}
+
+// ServeHTTP serves the most recent restart events, encoded as JSON
+func (r *Restarter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	events := r.Events()
+	if events == nil {
+		events = []RestartEvent{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
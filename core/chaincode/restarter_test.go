@@ -0,0 +1,172 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// launchRecorder records the launches requested by a restarter
+type launchRecorder struct {
+	launches chan string
+	err      error
+}
+
+func newLaunchRecorder() *launchRecorder {
+	return &launchRecorder{launches: make(chan string, 10)}
+}
+
+func (l *launchRecorder) launch(chainID, cname string, ccci *ccprovider.ChaincodeContainerInfo) error {
+	l.launches <- chainID + "/" + cname
+	return l.err
+}
+
+func (l *launchRecorder) expectLaunch(t *testing.T, expected string) {
+	select {
+	case launch := <-l.launches:
+		assert.Equal(t, expected, launch)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("%s was not launched", expected)
+	}
+}
+
+func (l *launchRecorder) expectNoLaunch(t *testing.T, wait time.Duration) {
+	select {
+	case launch := <-l.launches:
+		t.Fatalf("unexpected launch of %s", launch)
+	case <-time.After(wait):
+	}
+}
+
+var testRestartPolicy = RestartPolicy{
+	MaxRestarts:      2,
+	InitialBackoff:   10 * time.Millisecond,
+	MaxBackoff:       15 * time.Millisecond,
+	StableTime:       time.Hour,
+	CircuitBreakTime: time.Hour,
+}
+
+func TestNewRestarter(t *testing.T) {
+	var r *Restarter
+	assert.Nil(t, NewRestarter(RestartPolicy{}, nil, nil))
+
+	// a nil restarter allows any launch and restarts nothing
+	assert.NoError(t, r.Allow("mycc:1.0"))
+	r.Launched("ch1", "mycc:1.0", &ccprovider.ChaincodeContainerInfo{})
+	r.Exited("mycc:1.0")
+	r.Stopped("mycc:1.0")
+}
+
+func TestRestarter(t *testing.T) {
+	launcher := newLaunchRecorder()
+	scope := newRecordingScope()
+	r := NewRestarter(testRestartPolicy, launcher.launch, scope)
+	ccci := &ccprovider.ChaincodeContainerInfo{Name: "mycc", Version: "1.0"}
+
+	// the chaincodes not launched by the peer are not restarted
+	r.Exited("mycc:1.0")
+	launcher.expectNoLaunch(t, 50*time.Millisecond)
+
+	r.Launched("ch1", "mycc:1.0", ccci)
+	r.Exited("mycc:1.0")
+	launcher.expectLaunch(t, "ch1/mycc:1.0")
+	r.Launched("ch1", "mycc:1.0", ccci)
+	r.Exited("mycc:1.0")
+	launcher.expectLaunch(t, "ch1/mycc:1.0")
+	assert.Equal(t, int64(2), scope.counters["restarts"].get())
+	assert.NoError(t, r.Allow("mycc:1.0"))
+
+	// the circuit breaker opens after the maximum number of restarts
+	r.Launched("ch1", "mycc:1.0", ccci)
+	r.Exited("mycc:1.0")
+	launcher.expectNoLaunch(t, 50*time.Millisecond)
+	assert.EqualError(t, r.Allow("mycc:1.0"), "chaincode mycc:1.0 exited 2 times in a row, its launches are suspended until "+r.chaincodes["mycc:1.0"].openUntil.Format(time.RFC3339))
+	assert.Equal(t, int64(1), scope.counters["circuit_breaks"].get())
+	assert.Equal(t, float64(1), scope.gauges["circuit_open"].get())
+
+	// the circuit closes once the circuit break time has elapsed
+	r.chaincodes["mycc:1.0"].openUntil = time.Now()
+	assert.NoError(t, r.Allow("mycc:1.0"))
+	r.Launched("ch1", "mycc:1.0", ccci)
+	assert.Equal(t, float64(0), scope.gauges["circuit_open"].get())
+
+	// the chaincodes stopped by the peer are not restarted
+	r.Stopped("mycc:1.0")
+	r.Exited("mycc:1.0")
+	launcher.expectNoLaunch(t, 50*time.Millisecond)
+}
+
+func TestRestarterStableChaincode(t *testing.T) {
+	launcher := newLaunchRecorder()
+	policy := testRestartPolicy
+	policy.MaxRestarts = 1
+	policy.StableTime = 20 * time.Millisecond
+	r := NewRestarter(policy, launcher.launch, nil)
+	ccci := &ccprovider.ChaincodeContainerInfo{Name: "mycc", Version: "1.0"}
+
+	// the exits of a chaincode which ran for the stable time are not
+	// consecutive restarts
+	for i := 0; i < 3; i++ {
+		r.Launched("ch1", "mycc:1.0", ccci)
+		time.Sleep(policy.StableTime)
+		r.Exited("mycc:1.0")
+		launcher.expectLaunch(t, "ch1/mycc:1.0")
+	}
+	assert.NoError(t, r.Allow("mycc:1.0"))
+}
+
+func TestRestarterFailedRestart(t *testing.T) {
+	launcher := newLaunchRecorder()
+	launcher.err = errors.New("Bad lunch; upset stomach")
+	scope := newRecordingScope()
+	r := NewRestarter(testRestartPolicy, launcher.launch, scope)
+
+	// the failed restarts are retried up to the maximum number of restarts
+	r.Launched("ch1", "mycc:1.0", &ccprovider.ChaincodeContainerInfo{Name: "mycc", Version: "1.0"})
+	r.Exited("mycc:1.0")
+	launcher.expectLaunch(t, "ch1/mycc:1.0")
+	launcher.expectLaunch(t, "ch1/mycc:1.0")
+	launcher.expectNoLaunch(t, 50*time.Millisecond)
+	assert.Equal(t, int64(2), scope.counters["restart_failures"].get())
+	assert.Error(t, r.Allow("mycc:1.0"))
+
+	var types []string
+	for _, event := range r.Events() {
+		assert.Equal(t, "mycc:1.0", event.Chaincode)
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []string{RestartScheduled, RestartFailed, RestartScheduled, RestartFailed, CircuitOpened}, types)
+}
+
+func TestRestarterServeHTTP(t *testing.T) {
+	r := NewRestarter(testRestartPolicy, newLaunchRecorder().launch, nil)
+	for i := 0; i < maxRestartEvents+1; i++ {
+		r.record("mycc:1.0", RestartFailed, string(rune('a'+i%26)))
+	}
+	assert.Len(t, r.Events(), maxRestartEvents)
+	assert.Equal(t, "b", r.Events()[0].Message)
+
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/chaincode/restarts", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var events []RestartEvent
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&events))
+	assert.Len(t, events, maxRestartEvents)
+	assert.Equal(t, RestartFailed, events[0].Type)
+
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/chaincode/restarts", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+}
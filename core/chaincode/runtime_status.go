@@ -0,0 +1,86 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hyperledger/fabric/common/metrics"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// missedHeartbeats is the number of heartbeats a chaincode may miss before it
+// is reported as unresponsive
+const missedHeartbeats = 3
+
+// runtimeStatus records the heartbeats of a chaincode and the runtime stats
+// they carry
+type runtimeStatus struct {
+	mutex         sync.Mutex
+	registered    time.Time
+	lastHeartbeat time.Time
+	stats         *pb.ChaincodeRuntimeStats
+
+	heartbeats           metrics.Counter
+	transactionsInFlight metrics.Gauge
+	transactionErrors    metrics.Gauge
+	oldestTransactionAge metrics.Gauge
+	memoryBytes          metrics.Gauge
+	goroutines           metrics.Gauge
+}
+
+func newRuntimeStatus(registered time.Time, scope metrics.Scope) *runtimeStatus {
+	return &runtimeStatus{
+		registered:           registered,
+		heartbeats:           scope.Counter("heartbeats"),
+		transactionsInFlight: scope.Gauge("transactions_in_flight"),
+		transactionErrors:    scope.Gauge("transaction_errors"),
+		oldestTransactionAge: scope.Gauge("oldest_transaction_age_seconds"),
+		memoryBytes:          scope.Gauge("memory_bytes"),
+		goroutines:           scope.Gauge("goroutines"),
+	}
+}
+
+// record records a heartbeat of the chaincode received at the given time
+func (s *runtimeStatus) record(now time.Time, stats *pb.ChaincodeRuntimeStats) {
+	s.mutex.Lock()
+	s.lastHeartbeat = now
+	s.stats = stats
+	s.mutex.Unlock()
+
+	s.heartbeats.Inc(1)
+	s.transactionsInFlight.Update(float64(stats.TransactionsInFlight))
+	s.transactionErrors.Update(float64(stats.TransactionErrors))
+	s.memoryBytes.Update(float64(stats.MemoryBytes))
+	s.goroutines.Update(float64(stats.Goroutines))
+	age := time.Duration(0)
+	if start, err := ptypes.Timestamp(stats.OldestTransactionStart); err == nil && start.Before(now) {
+		age = now.Sub(start)
+	}
+	s.oldestTransactionAge.Update(age.Seconds())
+}
+
+// status returns the status of the chaincode at the given time. The chaincode
+// is unresponsive if it missed its last heartbeats, counting from its
+// registration if it sent none, unless the heartbeats are disabled.
+func (s *runtimeStatus) status(name string, now time.Time, interval time.Duration) *pb.ChaincodeStatus {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	status := &pb.ChaincodeStatus{Name: name, Stats: s.stats, Responsive: true}
+	last := s.registered
+	if !s.lastHeartbeat.IsZero() {
+		last = s.lastHeartbeat
+		status.LastHeartbeat, _ = ptypes.TimestampProto(s.lastHeartbeat)
+	}
+	if interval > 0 && now.Sub(last) > missedHeartbeats*interval {
+		status.Responsive = false
+	}
+	return status
+}
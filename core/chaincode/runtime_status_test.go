@@ -0,0 +1,64 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuntimeStatus(t *testing.T) {
+	scope := newRecordingScope()
+	registered := time.Now()
+	s := newRuntimeStatus(registered, scope)
+
+	// the chaincode has time to send its first heartbeat after registering
+	status := s.status("mycc:1.0", registered.Add(25*time.Second), 10*time.Second)
+	assert.Equal(t, &pb.ChaincodeStatus{Name: "mycc:1.0", Responsive: true}, status)
+	status = s.status("mycc:1.0", registered.Add(31*time.Second), 10*time.Second)
+	assert.False(t, status.Responsive)
+
+	oldest, _ := ptypes.TimestampProto(registered.Add(20 * time.Second))
+	stats := &pb.ChaincodeRuntimeStats{
+		TransactionsInFlight:   3,
+		TransactionErrors:      2,
+		OldestTransactionStart: oldest,
+		MemoryBytes:            4096,
+		Goroutines:             7,
+	}
+	heartbeat := registered.Add(30 * time.Second)
+	s.record(heartbeat, stats)
+	assert.Equal(t, int64(1), scope.counters["heartbeats"].get())
+	assert.Equal(t, float64(3), scope.gauges["transactions_in_flight"].get())
+	assert.Equal(t, float64(2), scope.gauges["transaction_errors"].get())
+	assert.Equal(t, float64(10), scope.gauges["oldest_transaction_age_seconds"].get())
+	assert.Equal(t, float64(4096), scope.gauges["memory_bytes"].get())
+	assert.Equal(t, float64(7), scope.gauges["goroutines"].get())
+
+	status = s.status("mycc:1.0", heartbeat.Add(30*time.Second), 10*time.Second)
+	assert.True(t, status.Responsive)
+	assert.Equal(t, stats, status.Stats)
+	lastHeartbeat, err := ptypes.Timestamp(status.LastHeartbeat)
+	assert.NoError(t, err)
+	assert.True(t, lastHeartbeat.Equal(heartbeat))
+
+	status = s.status("mycc:1.0", heartbeat.Add(31*time.Second), 10*time.Second)
+	assert.False(t, status.Responsive)
+
+	// no heartbeat is overdue when they are disabled
+	status = s.status("mycc:1.0", heartbeat.Add(time.Hour), 0)
+	assert.True(t, status.Responsive)
+
+	// no transaction is in flight
+	s.record(heartbeat.Add(10*time.Second), &pb.ChaincodeRuntimeStats{})
+	assert.Equal(t, int64(2), scope.counters["heartbeats"].get())
+	assert.Equal(t, float64(0), scope.gauges["oldest_transaction_age_seconds"].get())
+}
@@ -66,6 +66,9 @@ type ChaincodeStub struct {
 	binding   []byte
 
 	decorations map[string][]byte
+
+	// ctx is the context of the transaction, which expires at its deadline
+	ctx context.Context
 }
 
 // Peer address derived from command line or env var
@@ -153,7 +156,7 @@ func Start(cc Chaincode) error {
 		return err
 	}
 
-	err = chatWithPeer(chaincodename, stream, cc)
+	err = chatWithPeer(chaincodename, stream, cc, heartbeatInterval())
 
 	return err
 }
@@ -288,7 +291,9 @@ func StartInProc(env []string, args []string, cc Chaincode, recv <-chan *pb.Chai
 
 	stream := newInProcStream(recv, send)
 	chaincodeLogger.Debugf("starting chat with peer using name=%s", chaincodename)
-	err := chatWithPeer(chaincodename, stream, cc)
+	// the system chaincodes run in the process of the peer, which needs no
+	// heartbeat from them
+	err := chatWithPeer(chaincodename, stream, cc, 0)
 	return err
 }
 
@@ -318,7 +323,7 @@ func newPeerClientConnection() (*grpc.ClientConn, error) {
 	return comm.NewClientConnectionWithAddress(peerAddress, true, false, nil, kaOpts)
 }
 
-func chatWithPeer(chaincodename string, stream PeerChaincodeStream, cc Chaincode) error {
+func chatWithPeer(chaincodename string, stream PeerChaincodeStream, cc Chaincode, heartbeatInterval time.Duration) error {
 	// Create the shim handler responsible for all control logic
 	handler := newChaincodeHandler(stream, cc)
 	defer stream.CloseSend()
@@ -336,6 +341,12 @@ func chatWithPeer(chaincodename string, stream PeerChaincodeStream, cc Chaincode
 		return errors.WithMessage(err, "error sending chaincode REGISTER")
 	}
 
+	if heartbeatInterval > 0 {
+		done := make(chan struct{})
+		defer close(done)
+		go handler.sendHeartbeats(heartbeatInterval, done)
+	}
+
 	// holds return values from gRPC Recv below
 	type recvMsg struct {
 		msg *pb.ChaincodeMessage
@@ -453,11 +464,21 @@ func (stub *ChaincodeStub) InvokeChaincode(chaincodeName string, args [][]byte,
 
 // GetState documentation can be found in interfaces.go
 func (stub *ChaincodeStub) GetState(key string) ([]byte, error) {
+	if err := validateNotReserved(key); err != nil {
+		return nil, err
+	}
 	// Access public data by setting the collection to empty string
 	collection := ""
 	return stub.handler.handleGetState(collection, key, stub.ChannelId, stub.TxID)
 }
 
+// GetStateMultipleKeys documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetStateMultipleKeys(keys []string) ([][]byte, error) {
+	// Access public data by setting the collection to empty string
+	collection := ""
+	return stub.handler.handleGetStateMultiple(collection, keys, stub.ChannelId, stub.TxID)
+}
+
 // SetStateValidationParameter documentation can be found in interfaces.go
 func (stub *ChaincodeStub) SetStateValidationParameter(key string, ep []byte) error {
 	return stub.handler.handlePutStateMetadataEntry("", key, stub.validationParameterMetakey, ep, stub.ChannelId, stub.TxID)
@@ -480,6 +501,9 @@ func (stub *ChaincodeStub) PutState(key string, value []byte) error {
 	if key == "" {
 		return errors.New("key must not be an empty string")
 	}
+	if err := validateNotReserved(key); err != nil {
+		return err
+	}
 	// Access public data by setting the collection to empty string
 	collection := ""
 	return stub.handler.handlePutState(collection, key, value, stub.ChannelId, stub.TxID)
@@ -498,7 +522,8 @@ func (stub *ChaincodeStub) createStateQueryIterator(response *pb.QueryResponse)
 func (stub *ChaincodeStub) GetQueryResult(query string) (StateQueryIteratorInterface, error) {
 	// Access public data by setting the collection to empty string
 	collection := ""
-	// ignore QueryResponseMetadata as it is not applicable for a rich query without pagination
+	// the QueryResponseMetadata of a rich query without pagination only carries
+	// the warning of the state database, reported by the Warning of the iterator
 	iterator, _, err := stub.handleGetQueryResult(collection, query, nil)
 
 	return iterator, err
@@ -506,6 +531,9 @@ func (stub *ChaincodeStub) GetQueryResult(query string) (StateQueryIteratorInter
 
 // DelState documentation can be found in interfaces.go
 func (stub *ChaincodeStub) DelState(key string) error {
+	if err := validateNotReserved(key); err != nil {
+		return err
+	}
 	// Access public data by setting the collection to empty string
 	collection := ""
 	return stub.handler.handleDelState(collection, key, stub.ChannelId, stub.TxID)
@@ -521,6 +549,38 @@ func (stub *ChaincodeStub) GetPrivateData(collection string, key string) ([]byte
 	return stub.handler.handleGetState(collection, key, stub.ChannelId, stub.TxID)
 }
 
+// GetPrivateDataUnvalidated documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetPrivateDataUnvalidated(collection string, key string) ([]byte, error) {
+	if collection == "" {
+		return nil, fmt.Errorf("collection must not be an empty string")
+	}
+	return stub.handler.handleGetPrivateDataUnvalidated(collection, key, stub.ChannelId, stub.TxID)
+}
+
+// GetPrivateDataMultipleKeys documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetPrivateDataMultipleKeys(collection string, keys []string) ([][]byte, error) {
+	if collection == "" {
+		return nil, fmt.Errorf("collection must not be an empty string")
+	}
+	return stub.handler.handleGetStateMultiple(collection, keys, stub.ChannelId, stub.TxID)
+}
+
+// GetPrivateDataHash documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetPrivateDataHash(collection string, key string) ([]byte, error) {
+	if collection == "" {
+		return nil, fmt.Errorf("collection must not be an empty string")
+	}
+	return stub.handler.handleGetPrivateDataHash(collection, key, stub.ChannelId, stub.TxID)
+}
+
+// VerifyPrivateDataHash documentation can be found in interfaces.go
+func (stub *ChaincodeStub) VerifyPrivateDataHash(collection string, key string, preimage []byte) error {
+	if collection == "" {
+		return fmt.Errorf("collection must not be an empty string")
+	}
+	return stub.handler.handleVerifyPrivateDataHash(collection, key, preimage, stub.ChannelId, stub.TxID)
+}
+
 // PutPrivateData documentation can be found in interfaces.go
 func (stub *ChaincodeStub) PutPrivateData(collection string, key string, value []byte) error {
 	if collection == "" {
@@ -854,6 +914,20 @@ func (iter *StateQueryIterator) Next() (*queryresult.KV, error) {
 	}
 }
 
+// Warning implements the QueryWarner interface, the warning being the one of
+// the last batch of results received from the peer
+func (iter *StateQueryIterator) Warning() string {
+	if len(iter.response.Metadata) == 0 {
+		return ""
+	}
+	metadata, err := createQueryResponseMetadata(iter.response.Metadata)
+	if err != nil {
+		chaincodeLogger.Errorf("Failed to decode query response metadata: %+v", err)
+		return ""
+	}
+	return metadata.Warning
+}
+
 func (iter *HistoryQueryIterator) Next() (*queryresult.KeyModification, error) {
 	if result, err := iter.nextResult(HISTORY_QUERY_RESULT); err == nil {
 		return result.(*queryresult.KeyModification), err
@@ -1013,6 +1087,14 @@ func (stub *ChaincodeStub) GetTxTimestamp() (*timestamp.Timestamp, error) {
 	return chdr.GetTimestamp(), nil
 }
 
+// GetContext documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetContext() context.Context {
+	if stub.ctx == nil {
+		return context.Background()
+	}
+	return stub.ctx
+}
+
 // ------------- ChaincodeEvent API ----------------------
 
 // SetEvent documentation can be found in interfaces.go
@@ -0,0 +1,20 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policyeval
+
+import pb "github.com/hyperledger/fabric/protos/peer"
+
+// ChaincodeStubInterface is used by deployable chaincode apps to ask the peer
+// to evaluate endorsement policies on their channel
+type ChaincodeStubInterface interface {
+	// GetChannelID returns the channel the proposal is sent for
+	GetChannelID() string
+
+	// InvokeChaincode locally calls the specified chaincode `Invoke` using the
+	// same transaction context
+	InvokeChaincode(chaincodeName string, args [][]byte, channel string) pb.Response
+}
@@ -0,0 +1,67 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package policyeval lets chaincode check whether a set of identities would
+// satisfy an endorsement policy before performing writes which would
+// otherwise fail validation. The policies are evaluated by the query system
+// chaincode of the peer, against the principals of the identities only: no
+// signature is involved, and the key-level endorsement policies are the ones
+// committed to the ledger, not the ones set by the current transaction.
+package policyeval
+
+import (
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+const (
+	// statusOK is the status of successful chaincode responses
+	statusOK = 200
+
+	qscc              = "qscc"
+	evaluatePolicy    = "EvaluatePolicy"
+	evaluateKeyPolicy = "EvaluateKeyPolicy"
+)
+
+// EvaluatePolicy returns whether the serialized identities would satisfy the
+// endorsement policy, a marshaled SignaturePolicyEnvelope such as the ones
+// built by the statebased package
+func EvaluatePolicy(stub ChaincodeStubInterface, policy []byte, identities ...[]byte) (*pb.PolicyEvaluation, error) {
+	args := [][]byte{[]byte(evaluatePolicy), []byte(stub.GetChannelID()), policy}
+	return invoke(stub, args, identities)
+}
+
+// EvaluateKeyPolicy returns whether the serialized identities would satisfy
+// the endorsement policy of a key of the chaincode, which is the key-level
+// endorsement policy of the key or the endorsement policy of the chaincode
+// if the key has none
+func EvaluateKeyPolicy(stub ChaincodeStubInterface, chaincodeName, key string, identities ...[]byte) (*pb.PolicyEvaluation, error) {
+	return EvaluatePrivateDataKeyPolicy(stub, chaincodeName, "", key, identities...)
+}
+
+// EvaluatePrivateDataKeyPolicy is EvaluateKeyPolicy for a key of a private
+// data collection of the chaincode
+func EvaluatePrivateDataKeyPolicy(stub ChaincodeStubInterface, chaincodeName, collection, key string, identities ...[]byte) (*pb.PolicyEvaluation, error) {
+	args := [][]byte{[]byte(evaluateKeyPolicy), []byte(stub.GetChannelID()), []byte(chaincodeName), []byte(collection), []byte(key)}
+	return invoke(stub, args, identities)
+}
+
+// invoke calls the query system chaincode on the channel of the stub
+func invoke(stub ChaincodeStubInterface, args [][]byte, identities [][]byte) (*pb.PolicyEvaluation, error) {
+	if len(identities) == 0 {
+		return nil, errors.New("at least one identity must be provided")
+	}
+	response := stub.InvokeChaincode(qscc, append(args, identities...), "")
+	if response.Status != statusOK {
+		return nil, errors.Errorf("policy evaluation failed with status %d: %s", response.Status, response.Message)
+	}
+	evaluation := &pb.PolicyEvaluation{}
+	if err := proto.Unmarshal(response.Payload, evaluation); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshaling policy evaluation")
+	}
+	return evaluation, nil
+}
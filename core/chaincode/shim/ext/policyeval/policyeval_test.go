@@ -0,0 +1,74 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policyeval
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockStub struct {
+	args     [][]byte
+	channel  string
+	response pb.Response
+}
+
+func (m *mockStub) GetChannelID() string {
+	return "mychannel"
+}
+
+func (m *mockStub) InvokeChaincode(chaincodeName string, args [][]byte, channel string) pb.Response {
+	m.args = append([][]byte{[]byte(chaincodeName)}, args...)
+	m.channel = channel
+	return m.response
+}
+
+func successfulStub(t *testing.T, evaluation *pb.PolicyEvaluation) *mockStub {
+	payload, err := proto.Marshal(evaluation)
+	assert.NoError(t, err)
+	return &mockStub{response: pb.Response{Status: 200, Payload: payload}}
+}
+
+func TestEvaluatePolicy(t *testing.T) {
+	stub := successfulStub(t, &pb.PolicyEvaluation{Satisfied: true})
+	evaluation, err := EvaluatePolicy(stub, []byte("policy"), []byte("alice"), []byte("bob"))
+	assert.NoError(t, err)
+	assert.True(t, evaluation.Satisfied)
+	assert.Equal(t, [][]byte{[]byte("qscc"), []byte("EvaluatePolicy"), []byte("mychannel"), []byte("policy"), []byte("alice"), []byte("bob")}, stub.args)
+	assert.Empty(t, stub.channel)
+}
+
+func TestEvaluateKeyPolicy(t *testing.T) {
+	stub := successfulStub(t, &pb.PolicyEvaluation{Explanation: "not satisfied"})
+	evaluation, err := EvaluateKeyPolicy(stub, "mycc", "key", []byte("alice"))
+	assert.NoError(t, err)
+	assert.False(t, evaluation.Satisfied)
+	assert.Equal(t, "not satisfied", evaluation.Explanation)
+	assert.Equal(t, [][]byte{[]byte("qscc"), []byte("EvaluateKeyPolicy"), []byte("mychannel"), []byte("mycc"), []byte(""), []byte("key"), []byte("alice")}, stub.args)
+
+	_, err = EvaluatePrivateDataKeyPolicy(stub, "mycc", "collection", "key", []byte("alice"))
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("qscc"), []byte("EvaluateKeyPolicy"), []byte("mychannel"), []byte("mycc"), []byte("collection"), []byte("key"), []byte("alice")}, stub.args)
+}
+
+func TestEvaluatePolicyFailures(t *testing.T) {
+	stub := successfulStub(t, &pb.PolicyEvaluation{})
+	_, err := EvaluatePolicy(stub, []byte("policy"))
+	assert.EqualError(t, err, "at least one identity must be provided")
+	assert.Nil(t, stub.args)
+
+	stub = &mockStub{response: pb.Response{Status: 500, Message: "access denied"}}
+	_, err = EvaluateKeyPolicy(stub, "mycc", "key", []byte("alice"))
+	assert.EqualError(t, err, "policy evaluation failed with status 500: access denied")
+
+	stub = &mockStub{response: pb.Response{Status: 200, Payload: []byte("garbage")}}
+	_, err = EvaluatePolicy(stub, []byte("policy"), []byte("alice"))
+	assert.Contains(t, err.Error(), "failed unmarshaling policy evaluation")
+}
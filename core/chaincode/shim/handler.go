@@ -7,10 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package shim
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/pkg/errors"
 )
@@ -52,6 +54,8 @@ type Handler struct {
 	// Multiple queries (and one transaction) with different txids can be executing in parallel for this chaincode
 	// responseChannel is the channel on which responses are communicated by the shim to the chaincodeStub.
 	responseChannel map[string]chan pb.ChaincodeMessage
+	// stats tracks the transactions executed, reported in the heartbeats
+	stats *runtimeStats
 }
 
 func shorttxid(txid string) string {
@@ -167,21 +171,36 @@ func newChaincodeHandler(peerChatStream PeerChaincodeStream, chaincode Chaincode
 	v := &Handler{
 		ChatStream: peerChatStream,
 		cc:         chaincode,
+		stats:      newRuntimeStats(),
 	}
 	v.responseChannel = make(map[string]chan pb.ChaincodeMessage)
 	v.state = created
 	return v
 }
 
+// transactionContext returns the context of the transaction of the INIT or
+// TRANSACTION message, which expires at the deadline set by the peer
+func transactionContext(msg *pb.ChaincodeMessage) (context.Context, context.CancelFunc) {
+	if msg.Deadline != nil {
+		if deadline, err := ptypes.Timestamp(msg.Deadline); err == nil {
+			return context.WithDeadline(context.Background(), deadline)
+		}
+	}
+	return context.WithCancel(context.Background())
+}
+
 // handleInit handles request to initialize chaincode.
 func (handler *Handler) handleInit(msg *pb.ChaincodeMessage, errc chan error) {
 	// The defer followed by triggering a go routine dance is needed to ensure that the previous state transition
 	// is completed before the next one is triggered. The previous state transition is deemed complete only when
 	// the beforeInit function is exited. Interesting bug fix!!
+	txCtxID := handler.getTxCtxId(msg.ChannelId, msg.Txid)
+	handler.stats.begin(txCtxID)
 	go func() {
 		var nextStateMsg *pb.ChaincodeMessage
 
 		defer func() {
+			handler.stats.end(txCtxID, nextStateMsg == nil || nextStateMsg.Type == pb.ChaincodeMessage_ERROR)
 			handler.triggerNextState(nextStateMsg, errc)
 		}()
 
@@ -207,6 +226,9 @@ func (handler *Handler) handleInit(msg *pb.ChaincodeMessage, errc chan error) {
 		// Create the ChaincodeStub which the chaincode can use to callback
 		stub := new(ChaincodeStub)
 		err := stub.init(handler, msg.ChannelId, msg.Txid, input, msg.Proposal)
+		ctx, cancel := transactionContext(msg)
+		defer cancel()
+		stub.ctx = ctx
 		if nextStateMsg = errFunc(err, nil, stub.chaincodeEvent, "[%s] Init get error response. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR.String()); nextStateMsg != nil {
 			return
 		}
@@ -239,11 +261,17 @@ func (handler *Handler) handleTransaction(msg *pb.ChaincodeMessage, errc chan er
 	// The defer followed by triggering a go routine dance is needed to ensure that the previous state transition
 	// is completed before the next one is triggered. The previous state transition is deemed complete only when
 	// the beforeInit function is exited. Interesting bug fix!!
+	txCtxID := handler.getTxCtxId(msg.ChannelId, msg.Txid)
+	handler.stats.begin(txCtxID)
 	go func() {
 		//better not be nil
 		var nextStateMsg *pb.ChaincodeMessage
+		// the responses with an error status count as errors, even though
+		// they are sent as COMPLETED
+		failed := false
 
 		defer func() {
+			handler.stats.end(txCtxID, failed || nextStateMsg == nil || nextStateMsg.Type == pb.ChaincodeMessage_ERROR)
 			handler.triggerNextState(nextStateMsg, errc)
 		}()
 
@@ -267,10 +295,14 @@ func (handler *Handler) handleTransaction(msg *pb.ChaincodeMessage, errc chan er
 		// Create the ChaincodeStub which the chaincode can use to callback
 		stub := new(ChaincodeStub)
 		err := stub.init(handler, msg.ChannelId, msg.Txid, input, msg.Proposal)
+		ctx, cancel := transactionContext(msg)
+		defer cancel()
+		stub.ctx = ctx
 		if nextStateMsg = errFunc(err, stub.chaincodeEvent, "[%s] Transaction execution failed. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR.String()); nextStateMsg != nil {
 			return
 		}
 		res := handler.cc.Invoke(stub)
+		failed = res.Status >= ERROR
 
 		// Endorser will handle error contained in Response.
 		resBytes, err := proto.Marshal(&res)
@@ -302,8 +334,18 @@ func (handler *Handler) callPeerWithChaincodeMsg(msg *pb.ChaincodeMessage, chann
 // TODO: Implement a method to get multiple keys at a time [FAB-1244]
 // handleGetState communicates with the peer to fetch the requested state information from the ledger.
 func (handler *Handler) handleGetState(collection string, key string, channelId string, txid string) ([]byte, error) {
+	return handler.sendGetState(&pb.GetState{Collection: collection, Key: key}, channelId, txid)
+}
+
+// handleGetPrivateDataUnvalidated communicates with the peer to fetch the requested private data value from the ledger
+// without recording the read in the read set of the transaction.
+func (handler *Handler) handleGetPrivateDataUnvalidated(collection string, key string, channelId string, txid string) ([]byte, error) {
+	return handler.sendGetState(&pb.GetState{Collection: collection, Key: key, Unvalidated: true}, channelId, txid)
+}
+
+func (handler *Handler) sendGetState(getState *pb.GetState, channelId string, txid string) ([]byte, error) {
 	// Construct payload for GET_STATE
-	payloadBytes, _ := proto.Marshal(&pb.GetState{Collection: collection, Key: key})
+	payloadBytes, _ := proto.Marshal(getState)
 
 	msg := &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_STATE, Payload: payloadBytes, Txid: txid, ChannelId: channelId}
 	chaincodeLogger.Debugf("[%s] Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_GET_STATE)
@@ -329,6 +371,108 @@ func (handler *Handler) handleGetState(collection string, key string, channelId
 	return nil, errors.Errorf("[%s] incorrect chaincode message %s received. Expecting %s or %s", shorttxid(responseMsg.Txid), responseMsg.Type, pb.ChaincodeMessage_RESPONSE, pb.ChaincodeMessage_ERROR)
 }
 
+// handleGetStateMultiple communicates with the peer to fetch the values of the requested keys from the ledger in a single request.
+func (handler *Handler) handleGetStateMultiple(collection string, keys []string, channelId string, txid string) ([][]byte, error) {
+	// Construct payload for GET_STATE_MULTIPLE
+	payloadBytes, _ := proto.Marshal(&pb.GetStateMultiple{Collection: collection, Keys: keys})
+
+	msg := &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_STATE_MULTIPLE, Payload: payloadBytes, Txid: txid, ChannelId: channelId}
+	chaincodeLogger.Debugf("[%s] Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_GET_STATE_MULTIPLE)
+
+	responseMsg, err := handler.callPeerWithChaincodeMsg(msg, channelId, txid)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("[%s] error sending GET_STATE_MULTIPLE", shorttxid(txid)))
+	}
+
+	if responseMsg.Type.String() == pb.ChaincodeMessage_RESPONSE.String() {
+		// Success response
+		chaincodeLogger.Debugf("[%s] GetStateMultiple received payload %s", shorttxid(responseMsg.Txid), pb.ChaincodeMessage_RESPONSE)
+		result := &pb.GetStateMultipleResult{}
+		if err = proto.Unmarshal(responseMsg.Payload, result); err != nil {
+			chaincodeLogger.Errorf("[%s] GetStateMultipleResult unmarshall error", shorttxid(responseMsg.Txid))
+			return nil, errors.Errorf("[%s] GetStateMultipleResult unmarshall error", shorttxid(responseMsg.Txid))
+		}
+		if len(result.Values) != len(keys) {
+			return nil, errors.Errorf("[%s] GetStateMultiple received %d values for %d keys", shorttxid(responseMsg.Txid), len(result.Values), len(keys))
+		}
+		// keep the semantics of GetState, a non existing key has a nil value
+		for i, value := range result.Values {
+			if len(value) == 0 {
+				result.Values[i] = nil
+			}
+		}
+		return result.Values, nil
+	}
+	if responseMsg.Type.String() == pb.ChaincodeMessage_ERROR.String() {
+		// Error response
+		chaincodeLogger.Errorf("[%s] GetStateMultiple received error %s", shorttxid(responseMsg.Txid), pb.ChaincodeMessage_ERROR)
+		return nil, errors.New(string(responseMsg.Payload[:]))
+	}
+
+	// Incorrect chaincode message received
+	chaincodeLogger.Errorf("[%s] Incorrect chaincode message %s received. Expecting %s or %s", shorttxid(responseMsg.Txid), responseMsg.Type, pb.ChaincodeMessage_RESPONSE, pb.ChaincodeMessage_ERROR)
+	return nil, errors.Errorf("[%s] incorrect chaincode message %s received. Expecting %s or %s", shorttxid(responseMsg.Txid), responseMsg.Type, pb.ChaincodeMessage_RESPONSE, pb.ChaincodeMessage_ERROR)
+}
+
+// handleGetPrivateDataHash communicates with the peer to fetch the hash of the requested private data value from the ledger.
+func (handler *Handler) handleGetPrivateDataHash(collection string, key string, channelId string, txid string) ([]byte, error) {
+	// Construct payload for GET_PRIVATE_DATA_HASH
+	payloadBytes, _ := proto.Marshal(&pb.GetState{Collection: collection, Key: key})
+
+	msg := &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_PRIVATE_DATA_HASH, Payload: payloadBytes, Txid: txid, ChannelId: channelId}
+	chaincodeLogger.Debugf("[%s] Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_GET_PRIVATE_DATA_HASH)
+
+	responseMsg, err := handler.callPeerWithChaincodeMsg(msg, channelId, txid)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("[%s] error sending GET_PRIVATE_DATA_HASH", shorttxid(txid)))
+	}
+
+	if responseMsg.Type.String() == pb.ChaincodeMessage_RESPONSE.String() {
+		// Success response
+		chaincodeLogger.Debugf("[%s] GetPrivateDataHash received payload %s", shorttxid(responseMsg.Txid), pb.ChaincodeMessage_RESPONSE)
+		return responseMsg.Payload, nil
+	}
+	if responseMsg.Type.String() == pb.ChaincodeMessage_ERROR.String() {
+		// Error response
+		chaincodeLogger.Errorf("[%s] GetPrivateDataHash received error %s", shorttxid(responseMsg.Txid), pb.ChaincodeMessage_ERROR)
+		return nil, errors.New(string(responseMsg.Payload[:]))
+	}
+
+	// Incorrect chaincode message received
+	chaincodeLogger.Errorf("[%s] Incorrect chaincode message %s received. Expecting %s or %s", shorttxid(responseMsg.Txid), responseMsg.Type, pb.ChaincodeMessage_RESPONSE, pb.ChaincodeMessage_ERROR)
+	return nil, errors.Errorf("[%s] incorrect chaincode message %s received. Expecting %s or %s", shorttxid(responseMsg.Txid), responseMsg.Type, pb.ChaincodeMessage_RESPONSE, pb.ChaincodeMessage_ERROR)
+}
+
+// handleVerifyPrivateDataHash asks the peer to verify the preimage against the
+// private data hash of the key, which the peer hashes with the algorithm of the channel
+func (handler *Handler) handleVerifyPrivateDataHash(collection string, key string, preimage []byte, channelId string, txid string) error {
+	// Construct payload for VERIFY_PRIVATE_DATA_HASH
+	payloadBytes, _ := proto.Marshal(&pb.VerifyPrivateDataHash{Collection: collection, Key: key, Preimage: preimage})
+
+	msg := &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_VERIFY_PRIVATE_DATA_HASH, Payload: payloadBytes, Txid: txid, ChannelId: channelId}
+	chaincodeLogger.Debugf("[%s] Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_VERIFY_PRIVATE_DATA_HASH)
+
+	responseMsg, err := handler.callPeerWithChaincodeMsg(msg, channelId, txid)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("[%s] error sending VERIFY_PRIVATE_DATA_HASH", shorttxid(txid)))
+	}
+
+	if responseMsg.Type.String() == pb.ChaincodeMessage_RESPONSE.String() {
+		// Success response
+		chaincodeLogger.Debugf("[%s] VerifyPrivateDataHash received payload %s", shorttxid(responseMsg.Txid), pb.ChaincodeMessage_RESPONSE)
+		return nil
+	}
+	if responseMsg.Type.String() == pb.ChaincodeMessage_ERROR.String() {
+		// Error response
+		chaincodeLogger.Errorf("[%s] VerifyPrivateDataHash received error %s", shorttxid(responseMsg.Txid), pb.ChaincodeMessage_ERROR)
+		return errors.New(string(responseMsg.Payload[:]))
+	}
+
+	// Incorrect chaincode message received
+	chaincodeLogger.Errorf("[%s] Incorrect chaincode message %s received. Expecting %s or %s", shorttxid(responseMsg.Txid), responseMsg.Type, pb.ChaincodeMessage_RESPONSE, pb.ChaincodeMessage_ERROR)
+	return errors.Errorf("[%s] incorrect chaincode message %s received. Expecting %s or %s", shorttxid(responseMsg.Txid), responseMsg.Type, pb.ChaincodeMessage_RESPONSE, pb.ChaincodeMessage_ERROR)
+}
+
 func (handler *Handler) handleGetStateMetadata(collection string, key string, channelID string, txID string) (map[string][]byte, error) {
 	// Construct payload for GET_STATE_METADATA
 	payloadBytes, _ := proto.Marshal(&pb.GetStateMetadata{Collection: collection, Key: key})
@@ -0,0 +1,101 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shim
+
+import (
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
+)
+
+// heartbeatInterval returns the interval between the heartbeats sent to the
+// peer, set by the peer in CORE_CHAINCODE_HEARTBEAT_INTERVAL; zero disables
+// the heartbeats
+func heartbeatInterval() time.Duration {
+	return viper.GetDuration("chaincode.heartbeat.interval")
+}
+
+// runtimeStats tracks the transactions executed by the chaincode, which are
+// reported to the peer with each heartbeat
+type runtimeStats struct {
+	mutex    sync.Mutex
+	inFlight map[string]time.Time
+	errors   uint64
+}
+
+func newRuntimeStats() *runtimeStats {
+	return &runtimeStats{inFlight: map[string]time.Time{}}
+}
+
+// begin records the start of the execution of a transaction
+func (s *runtimeStats) begin(txCtxID string) {
+	s.mutex.Lock()
+	s.inFlight[txCtxID] = time.Now()
+	s.mutex.Unlock()
+}
+
+// end records the end of the execution of a transaction, which failed if the
+// message sent back to the peer is an error
+func (s *runtimeStats) end(txCtxID string, failed bool) {
+	s.mutex.Lock()
+	delete(s.inFlight, txCtxID)
+	if failed {
+		s.errors++
+	}
+	s.mutex.Unlock()
+}
+
+// snapshot returns the current stats of the chaincode process
+func (s *runtimeStats) snapshot() *pb.ChaincodeRuntimeStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	stats := &pb.ChaincodeRuntimeStats{
+		MemoryBytes: mem.Sys,
+		Goroutines:  int32(runtime.NumGoroutine()),
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	stats.TransactionsInFlight = int32(len(s.inFlight))
+	stats.TransactionErrors = s.errors
+	var oldest time.Time
+	for _, start := range s.inFlight {
+		if oldest.IsZero() || start.Before(oldest) {
+			oldest = start
+		}
+	}
+	if !oldest.IsZero() {
+		stats.OldestTransactionStart, _ = ptypes.TimestampProto(oldest)
+	}
+	return stats
+}
+
+// sendHeartbeats sends a HEARTBEAT carrying the runtime stats of the chaincode
+// to the peer at each interval, until done is closed
+func (handler *Handler) sendHeartbeats(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			payload, err := proto.Marshal(handler.stats.snapshot())
+			if err != nil {
+				chaincodeLogger.Errorf("Error marshalling the runtime stats: %s", err)
+				continue
+			}
+			// ignore errors, the stream failure is handled by the receive loop
+			handler.serialSend(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_HEARTBEAT, Payload: payload, Timestamp: ptypes.TimestampNow()})
+		case <-done:
+			return
+		}
+	}
+}
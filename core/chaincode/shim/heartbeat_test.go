@@ -0,0 +1,83 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shim
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuntimeStats(t *testing.T) {
+	s := newRuntimeStats()
+	stats := s.snapshot()
+	assert.Equal(t, int32(0), stats.TransactionsInFlight)
+	assert.Nil(t, stats.OldestTransactionStart)
+	assert.NotZero(t, stats.MemoryBytes)
+	assert.NotZero(t, stats.Goroutines)
+
+	s.begin("ch1tx1")
+	s.begin("ch1tx2")
+	stats = s.snapshot()
+	assert.Equal(t, int32(2), stats.TransactionsInFlight)
+	assert.NotNil(t, stats.OldestTransactionStart)
+
+	s.end("ch1tx1", true)
+	s.end("ch1tx2", false)
+	stats = s.snapshot()
+	assert.Equal(t, int32(0), stats.TransactionsInFlight)
+	assert.Equal(t, uint64(1), stats.TransactionErrors)
+	assert.Nil(t, stats.OldestTransactionStart)
+}
+
+// sentMessages is a stream to the peer recording the messages sent
+type sentMessages chan *pb.ChaincodeMessage
+
+func (s sentMessages) Send(msg *pb.ChaincodeMessage) error {
+	s <- msg
+	return nil
+}
+
+func (s sentMessages) Recv() (*pb.ChaincodeMessage, error) {
+	select {}
+}
+
+func (s sentMessages) CloseSend() error {
+	return nil
+}
+
+func TestSendHeartbeats(t *testing.T) {
+	stream := make(sentMessages, 10)
+	handler := newChaincodeHandler(stream, nil)
+	handler.stats.begin("ch1tx1")
+
+	done := make(chan struct{})
+	go handler.sendHeartbeats(10*time.Millisecond, done)
+	for i := 0; i < 2; i++ {
+		select {
+		case msg := <-stream:
+			assert.Equal(t, pb.ChaincodeMessage_HEARTBEAT, msg.Type)
+			stats := &pb.ChaincodeRuntimeStats{}
+			assert.NoError(t, proto.Unmarshal(msg.Payload, stats))
+			assert.Equal(t, int32(1), stats.TransactionsInFlight)
+		case <-time.After(5 * time.Second):
+			t.Fatal("no heartbeat was sent")
+		}
+	}
+	close(done)
+}
+
+func TestHeartbeatInterval(t *testing.T) {
+	defer viper.Reset()
+	assert.Equal(t, time.Duration(0), heartbeatInterval())
+	viper.Set("chaincode.heartbeat.interval", "30s")
+	assert.Equal(t, 30*time.Second, heartbeatInterval())
+}
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package shim
 
 import (
+	"context"
+
 	"github.com/golang/protobuf/ptypes/timestamp"
 
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
@@ -86,13 +88,21 @@ type ChaincodeStubInterface interface {
 	// If the key does not exist in the state database, (nil, nil) is returned.
 	GetState(key string) ([]byte, error)
 
+	// GetStateMultipleKeys returns the values of the specified `keys` from the
+	// ledger in a single request to the peer, in the same order as the keys.
+	// Like GetState, it doesn't consider data modified by PutState that has
+	// not been committed. The value of a key which does not exist in the state
+	// database is nil.
+	GetStateMultipleKeys(keys []string) ([][]byte, error)
+
 	// PutState puts the specified `key` and `value` into the transaction's
 	// writeset as a data-write proposal. PutState doesn't effect the ledger
 	// until the transaction is validated and successfully committed.
 	// Simple keys must not be an empty string and must not start with null
 	// character (0x00), in order to avoid range query collisions with
 	// composite keys, which internally get prefixed with 0x00 as composite
-	// key namespace.
+	// key namespace. The keys starting with 0x00 followed by U+10FFFF are
+	// reserved for the namespaces of the chaincode, see PutStateIn.
 	PutState(key string, value []byte) error
 
 	// DelState records the specified `key` to be deleted in the writeset of
@@ -189,6 +199,38 @@ type ChaincodeStubInterface interface {
 	// composite parts.
 	SplitCompositeKey(compositeKey string) (string, []string, error)
 
+	// GetStateIn returns the value of the specified `key` in the `namespace`
+	// of the chaincode. The namespaces organize the state of a chaincode in
+	// separate key ranges, which the other state functions cannot access:
+	// the keys of a namespace are not returned by the range queries of the
+	// other namespaces or of the keys outside namespaces. The `namespace` is
+	// expected to be a non-empty valid utf8 string, which does not contain
+	// U+0000 (nil byte) and U+10FFFF (biggest and unallocated code point).
+	GetStateIn(namespace, key string) ([]byte, error)
+
+	// PutStateIn puts the specified `key` and `value` into the `namespace` of
+	// the chaincode, like PutState. The `key` may be a composite key, in which
+	// case it can be queried with GetStateByPartialCompositeKeyIn.
+	PutStateIn(namespace, key string, value []byte) error
+
+	// DelStateIn records the specified `key` of the `namespace` of the
+	// chaincode to be deleted, like DelState.
+	DelStateIn(namespace, key string) error
+
+	// GetStateByRangeIn returns a range iterator over the keys of the
+	// `namespace` of the chaincode, like GetStateByRange. The keys returned
+	// by the iterator do not include the namespace, and the composite keys
+	// of the namespace are excluded.
+	// Call Close() on the returned StateQueryIteratorInterface object when done.
+	GetStateByRangeIn(namespace, startKey, endKey string) (StateQueryIteratorInterface, error)
+
+	// GetStateByPartialCompositeKeyIn queries the composite keys of the
+	// `namespace` of the chaincode based on a given partial composite key,
+	// like GetStateByPartialCompositeKey. The keys returned by the iterator
+	// do not include the namespace and can be split with SplitCompositeKey.
+	// Call Close() on the returned StateQueryIteratorInterface object when done.
+	GetStateByPartialCompositeKeyIn(namespace, objectType string, keys []string) (StateQueryIteratorInterface, error)
+
 	// GetQueryResult performs a "rich" query against a state database. It is
 	// only supported for state databases that support rich query,
 	// e.g.CouchDB. The query string is in the native syntax
@@ -204,6 +246,8 @@ type ChaincodeStubInterface interface {
 	// be detected at validation/commit time.  Applications susceptible to this
 	// should therefore not use GetQueryResult as part of transactions that update
 	// ledger, and should limit use to read-only chaincode operations.
+	// The iterator returned implements QueryWarner, which reports the queries
+	// the state database cannot serve efficiently, e.g. for lack of an index.
 	GetQueryResult(query string) (StateQueryIteratorInterface, error)
 
 	// GetQueryResultWithPagination performs a "rich" query against a state database.
@@ -243,6 +287,45 @@ type ChaincodeStubInterface interface {
 	// that has not been committed.
 	GetPrivateData(collection, key string) ([]byte, error)
 
+	// GetPrivateDataUnvalidated returns the value of the specified `key` from the
+	// specified `collection` like GetPrivateData, but opts the read out of
+	// validation: the read isn't recorded in the transaction's read set, so the
+	// transaction isn't invalidated if the value is modified before it commits.
+	// The value may also be stale, as the private data of the peer may lag behind
+	// the ledger. It suits reference data whose staleness is acceptable, and must
+	// not be used for data the writes of the transaction depend upon.
+	GetPrivateDataUnvalidated(collection, key string) ([]byte, error)
+
+	// GetPrivateDataMultipleKeys returns the values of the specified `keys` from
+	// the specified `collection` in a single request to the peer, in the same
+	// order as the keys. Like GetPrivateData, it doesn't consider data modified
+	// by PutPrivateData that has not been committed. The value of a key which
+	// does not exist in the `collection` is nil.
+	GetPrivateDataMultipleKeys(collection string, keys []string) ([][]byte, error)
+
+	// GetPrivateDataHash returns the hash of the value of the specified `key` from
+	// the specified `collection`. Unlike GetPrivateData, it can be invoked on a
+	// peer that is not a member of the `collection`, as the hashes of private
+	// data are part of the ledger on every peer. This allows, for instance, to
+	// verify a value provided by a client against the committed hash without
+	// being able to read the value itself. It returns nil if the key does not
+	// exist.
+	GetPrivateDataHash(collection, key string) ([]byte, error)
+
+	// VerifyPrivateDataHash verifies that the specified `preimage` is the value
+	// of the specified `key` in the specified `collection`. The peer compares
+	// the hash of the preimage, computed with the write-set hashing algorithm
+	// of the channel, with the committed hash of the value, or opens the
+	// committed Pedersen commitment to the value if the `collection` commits to
+	// its values with Pedersen commitments, with the blinding factor supplied
+	// by the client in the transient field under the key returned by
+	// pedersen.TransientKey. Like GetPrivateDataHash, it can be
+	// invoked on a peer that is not a member of the `collection`, so it allows,
+	// for instance, to check the private asset provided by a client in the
+	// transient field before transferring it to another collection. It returns
+	// an error if the key does not exist or if the preimage does not match.
+	VerifyPrivateDataHash(collection, key string, preimage []byte) error
+
 	// PutPrivateData puts the specified `key` and `value` into the transaction's
 	// private writeset. Note that only hash of the private writeset goes into the
 	// transaction proposal response (which is sent to the client who issued the
@@ -251,7 +334,10 @@ type ChaincodeStubInterface interface {
 	// transaction is validated and successfully committed. Simple keys must not be
 	// an empty string and must not start with null character (0x00), in order to
 	// avoid range query collisions with composite keys, which internally get
-	// prefixed with 0x00 as composite key namespace.
+	// prefixed with 0x00 as composite key namespace. If the `collection` commits to
+	// its values with Pedersen commitments, the client must supply the blinding
+	// factor of the commitment in the transient field under the key returned by
+	// pedersen.TransientKey.
 	PutPrivateData(collection string, key string, value []byte) error
 
 	// DelState records the specified `key` to be deleted in the private writeset of
@@ -278,8 +364,15 @@ type ChaincodeStubInterface interface {
 	// that startKey and endKey can be empty string, which implies unbounded range
 	// query on start or end.
 	// Call Close() on the returned StateQueryIteratorInterface object when done.
-	// The query is re-executed during validation phase to ensure result set
-	// has not changed since transaction endorsement (phantom reads detected).
+	// Unlike GetStateByRange, the query is NOT re-executed during validation
+	// phase, as the peers which are not members of the collection only have the
+	// hashes of its keys. The keys returned by the iterator are recorded in the
+	// transaction's readset, so updates or deletions of these keys are detected
+	// at validation/commit time, but the keys added to the range by other
+	// committed transactions are not (phantom reads not detected). The peer
+	// therefore only supports the queries on private data in transactions which
+	// don't write to the ledger, and returns an error for the writes performed
+	// after the query or for the query performed after a write.
 	GetPrivateDataByRange(collection, startKey, endKey string) (StateQueryIteratorInterface, error)
 
 	// GetPrivateDataByPartialCompositeKey queries the state in a given private
@@ -290,8 +383,9 @@ type ChaincodeStubInterface interface {
 	// U+0000 (nil byte) and U+10FFFF (biggest and unallocated code point).
 	// See related functions SplitCompositeKey and CreateCompositeKey.
 	// Call Close() on the returned StateQueryIteratorInterface object when done.
-	// The query is re-executed during validation phase to ensure result set
-	// has not changed since transaction endorsement (phantom reads detected).
+	// Like GetPrivateDataByRange, the query is NOT re-executed during validation
+	// phase (phantom reads not detected) and is only supported in transactions
+	// which don't write to the ledger.
 	GetPrivateDataByPartialCompositeKey(collection, objectType string, keys []string) (StateQueryIteratorInterface, error)
 
 	// GetPrivateDataQueryResult performs a "rich" query against a given private
@@ -340,6 +434,12 @@ type ChaincodeStubInterface interface {
 	// client's timestamp and will have the same value across all endorsers.
 	GetTxTimestamp() (*timestamp.Timestamp, error)
 
+	// GetContext returns the context of the transaction. It expires at the
+	// deadline set by the peer for the execution of the transaction, after
+	// which the peer no longer waits for the chaincode and discards its
+	// response, and is canceled once the chaincode returns.
+	GetContext() context.Context
+
 	// SetEvent allows the chaincode to set an event on the response to the
 	// proposal to be included as part of a transaction. The event will be
 	// available within the transaction in the committed block regardless of the
@@ -369,6 +469,14 @@ type StateQueryIteratorInterface interface {
 	Next() (*queryresult.KV, error)
 }
 
+// QueryWarner is implemented by the iterators returned by GetQueryResult, which
+// report the warning of the state database about the execution of the query.
+type QueryWarner interface {
+	// Warning returns the warning of the state database, such as the lack of
+	// an index matching the selector of the query, or an empty string.
+	Warning() string
+}
+
 // HistoryQueryIteratorInterface allows a chaincode to iterate over a set of
 // key/value pairs returned by a history query.
 type HistoryQueryIteratorInterface interface {
@@ -9,11 +9,15 @@ SPDX-License-Identifier: Apache-2.0
 package shim
 
 import (
+	"bytes"
 	"container/list"
+	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric/common/crypto/pedersen"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
 	pb "github.com/hyperledger/fabric/protos/peer"
@@ -26,6 +30,9 @@ var mockLogger = logging.MustGetLogger("mock")
 
 // MockStub is an implementation of ChaincodeStubInterface for unit testing chaincode.
 // Use this instead of ChaincodeStub in your chaincode's unit test calls to Init or Invoke.
+// The MockStub neither records read-write sets nor validates transactions, the
+// harness of package shimtest executes the chaincodes against a ledger for the
+// tests which depend on MVCC, range queries or private data collections.
 type MockStub struct {
 	// arguments the stub was called with
 	args [][]byte
@@ -58,6 +65,9 @@ type MockStub struct {
 	// stores a channel ID of the proposal
 	ChannelID string
 
+	// stores the transient field of the proposal
+	TransientMap map[string][]byte
+
 	PvtState map[string]map[string][]byte
 
 	// stores per-key endorsement policy, first map index is the collection, second map index is the key
@@ -67,6 +77,9 @@ type MockStub struct {
 	ChaincodeEventsChannel chan *pb.ChaincodeEvent
 
 	Decorations map[string][]byte
+
+	// context of the transactions, the background context if nil
+	Context context.Context
 }
 
 func (stub *MockStub) GetTxID() string {
@@ -165,6 +178,55 @@ func (stub *MockStub) GetPrivateData(collection string, key string) ([]byte, err
 	return m[key], nil
 }
 
+func (stub *MockStub) GetPrivateDataUnvalidated(collection string, key string) ([]byte, error) {
+	return stub.GetPrivateData(collection, key)
+}
+
+func (stub *MockStub) GetPrivateDataMultipleKeys(collection string, keys []string) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		values[i], _ = stub.GetPrivateData(collection, key)
+	}
+	return values, nil
+}
+
+func (stub *MockStub) GetPrivateDataHash(collection string, key string) ([]byte, error) {
+	value, err := stub.GetPrivateData(collection, key)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	return util.ComputeSHA256(value), nil
+}
+
+func (stub *MockStub) VerifyPrivateDataHash(collection string, key string, preimage []byte) error {
+	hash, err := stub.GetPrivateDataHash(collection, key)
+	if err != nil {
+		return err
+	}
+	return verifyPrivateDataHash(collection, key, hash, preimage, stub.TransientMap[pedersen.TransientKey(stub.Name, collection, key)])
+}
+
+// verifyPrivateDataHash compares the SHA256 hash of the preimage with the
+// hash of the value of the key, or opens the committed Pedersen commitment to
+// the value with the preimage and the blinding factor for the collections
+// committing to their values
+func verifyPrivateDataHash(collection, key string, hash, preimage, blindingFactor []byte) error {
+	if hash == nil {
+		return errors.Errorf("key %s does not exist in collection %s", key, collection)
+	}
+	if len(hash) == pedersen.CommitmentSize {
+		if !pedersen.Verify(hash, preimage, blindingFactor) {
+			return errors.Errorf("the preimage does not match the commitment of key %s in collection %s", key, collection)
+		}
+		return nil
+	}
+	if !bytes.Equal(util.ComputeSHA256(preimage), hash) {
+		return errors.Errorf("the preimage does not match the hash of key %s in collection %s", key, collection)
+	}
+	return nil
+}
+
 func (stub *MockStub) PutPrivateData(collection string, key string, value []byte) error {
 	m, in := stub.PvtState[collection]
 	if !in {
@@ -182,11 +244,21 @@ func (stub *MockStub) DelPrivateData(collection string, key string) error {
 }
 
 func (stub *MockStub) GetPrivateDataByRange(collection, startKey, endKey string) (StateQueryIteratorInterface, error) {
-	return nil, errors.New("Not Implemented")
+	if startKey == "" {
+		startKey = emptyKeySubstitute
+	}
+	if err := validateSimpleKeys(startKey, endKey); err != nil {
+		return nil, err
+	}
+	return newMockPrivateDataRangeQueryIterator(stub, collection, startKey, endKey), nil
 }
 
 func (stub *MockStub) GetPrivateDataByPartialCompositeKey(collection, objectType string, attributes []string) (StateQueryIteratorInterface, error) {
-	return nil, errors.New("Not Implemented")
+	partialCompositeKey, err := stub.CreateCompositeKey(objectType, attributes)
+	if err != nil {
+		return nil, err
+	}
+	return newMockPrivateDataRangeQueryIterator(stub, collection, partialCompositeKey, partialCompositeKey+string(maxUnicodeRuneValue)), nil
 }
 
 func (stub *MockStub) GetPrivateDataQueryResult(collection, query string) (StateQueryIteratorInterface, error) {
@@ -203,6 +275,14 @@ func (stub *MockStub) GetState(key string) ([]byte, error) {
 	return value, nil
 }
 
+func (stub *MockStub) GetStateMultipleKeys(keys []string) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		values[i], _ = stub.GetState(key)
+	}
+	return values, nil
+}
+
 // PutState writes the specified `value` and `key` into the ledger.
 func (stub *MockStub) PutState(key string, value []byte) error {
 	if stub.TxID == "" {
@@ -312,6 +392,56 @@ func (stub *MockStub) SplitCompositeKey(compositeKey string) (string, []string,
 	return splitCompositeKey(compositeKey)
 }
 
+func (stub *MockStub) GetStateIn(namespace, key string) ([]byte, error) {
+	nsKey, err := namespaceKey(namespace, key)
+	if err != nil {
+		return nil, err
+	}
+	return stub.GetState(nsKey)
+}
+
+func (stub *MockStub) PutStateIn(namespace, key string, value []byte) error {
+	nsKey, err := namespaceKey(namespace, key)
+	if err != nil {
+		return err
+	}
+	return stub.PutState(nsKey, value)
+}
+
+func (stub *MockStub) DelStateIn(namespace, key string) error {
+	nsKey, err := namespaceKey(namespace, key)
+	if err != nil {
+		return err
+	}
+	return stub.DelState(nsKey)
+}
+
+func (stub *MockStub) GetStateByRangeIn(namespace, startKey, endKey string) (StateQueryIteratorInterface, error) {
+	if startKey == "" {
+		startKey = emptyKeySubstitute
+	}
+	if err := validateSimpleKeys(startKey, endKey); err != nil {
+		return nil, err
+	}
+	start, end, err := namespaceRange(namespace, startKey, endKey)
+	if err != nil {
+		return nil, err
+	}
+	return newNamespaceIterator(NewMockStateRangeQueryIterator(stub, start, end), namespace), nil
+}
+
+func (stub *MockStub) GetStateByPartialCompositeKeyIn(namespace, objectType string, attributes []string) (StateQueryIteratorInterface, error) {
+	partialCompositeKey, err := stub.CreateCompositeKey(objectType, attributes)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := namespaceRange(namespace, partialCompositeKey, partialCompositeKey+string(maxUnicodeRuneValue))
+	if err != nil {
+		return nil, err
+	}
+	return newNamespaceIterator(NewMockStateRangeQueryIterator(stub, start, end), namespace), nil
+}
+
 func (stub *MockStub) GetStateByRangeWithPagination(startKey, endKey string, pageSize int32,
 	bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
 	return nil, nil, nil
@@ -350,9 +480,8 @@ func (stub *MockStub) GetCreator() ([]byte, error) {
 	return nil, nil
 }
 
-// Not implemented
 func (stub *MockStub) GetTransient() (map[string][]byte, error) {
-	return nil, nil
+	return stub.TransientMap, nil
 }
 
 // Not implemented
@@ -385,6 +514,13 @@ func (stub *MockStub) GetTxTimestamp() (*timestamp.Timestamp, error) {
 	return stub.TxTimestamp, nil
 }
 
+func (stub *MockStub) GetContext() context.Context {
+	if stub.Context == nil {
+		return context.Background()
+	}
+	return stub.Context
+}
+
 func (stub *MockStub) SetEvent(name string, payload []byte) error {
 	stub.ChaincodeEventsChannel <- &pb.ChaincodeEvent{EventName: name, Payload: payload}
 	return nil
@@ -558,6 +694,54 @@ func NewMockStateRangeQueryIterator(stub *MockStub, startKey string, endKey stri
 	return iter
 }
 
+// mockPrivateDataRangeQueryIterator iterates over the keys of a collection
+// of the MockStub between the start key (inclusive) and the end key
+// (exclusive) in lexical order, like the range queries on private data of the
+// peer; an empty start or end key leaves the range unbounded on that side
+type mockPrivateDataRangeQueryIterator struct {
+	closed bool
+	kvs    []*queryresult.KV
+}
+
+func newMockPrivateDataRangeQueryIterator(stub *MockStub, collection, startKey, endKey string) *mockPrivateDataRangeQueryIterator {
+	iter := &mockPrivateDataRangeQueryIterator{}
+	for key, value := range stub.PvtState[collection] {
+		if key < startKey || (endKey != "" && key >= endKey) {
+			continue
+		}
+		iter.kvs = append(iter.kvs, &queryresult.KV{Key: key, Value: value})
+	}
+	sort.Slice(iter.kvs, func(i, j int) bool {
+		return iter.kvs[i].Key < iter.kvs[j].Key
+	})
+	return iter
+}
+
+// HasNext returns true if the range query iterator contains additional keys
+// and values.
+func (iter *mockPrivateDataRangeQueryIterator) HasNext() bool {
+	return !iter.closed && len(iter.kvs) > 0
+}
+
+// Next returns the next key and value in the range query iterator.
+func (iter *mockPrivateDataRangeQueryIterator) Next() (*queryresult.KV, error) {
+	if !iter.HasNext() {
+		return nil, errors.New("mockPrivateDataRangeQueryIterator.Next() called when it does not HaveNext()")
+	}
+	kv := iter.kvs[0]
+	iter.kvs = iter.kvs[1:]
+	return kv, nil
+}
+
+// Close closes the range query iterator.
+func (iter *mockPrivateDataRangeQueryIterator) Close() error {
+	if iter.closed {
+		return errors.New("mockPrivateDataRangeQueryIterator.Close() called after Close()")
+	}
+	iter.closed = true
+	return nil
+}
+
 func getBytes(function string, args []string) [][]byte {
 	bytes := make([][]byte, 0, len(args)+1)
 	bytes = append(bytes, []byte(function))
@@ -17,13 +17,17 @@ limitations under the License.
 package shim
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"reflect"
 	"testing"
 
+	"github.com/hyperledger/fabric/common/crypto/pedersen"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestMockStateRangeQueryIterator(t *testing.T) {
@@ -235,6 +239,132 @@ func TestGetTxTimestamp(t *testing.T) {
 	stub.MockTransactionEnd("init")
 }
 
+func TestGetPrivateDataHash(t *testing.T) {
+	stub := NewMockStub("GetPrivateDataHash", nil)
+	stub.MockTransactionStart("init")
+	defer stub.MockTransactionEnd("init")
+
+	value := []byte("value")
+	err := stub.PutPrivateData("coll", "key", value)
+	assert.NoError(t, err)
+
+	hash, err := stub.GetPrivateDataHash("coll", "key")
+	assert.NoError(t, err)
+	expected := sha256.Sum256(value)
+	assert.Equal(t, expected[:], hash)
+
+	hash, err = stub.GetPrivateDataHash("coll", "missing")
+	assert.NoError(t, err)
+	assert.Nil(t, hash)
+}
+
+func TestGetPrivateDataByRange(t *testing.T) {
+	stub := NewMockStub("GetPrivateDataByRange", nil)
+	stub.MockTransactionStart("init")
+	defer stub.MockTransactionEnd("init")
+
+	for _, key := range []string{"key3", "key1", "key2"} {
+		err := stub.PutPrivateData("coll", key, []byte("value-"+key))
+		assert.NoError(t, err)
+	}
+	compositeKey, err := stub.CreateCompositeKey("marble", []string{"blue", "m1"})
+	assert.NoError(t, err)
+	err = stub.PutPrivateData("coll", compositeKey, []byte("marble1"))
+	assert.NoError(t, err)
+	err = stub.PutPrivateData("other", "key0", []byte("value"))
+	assert.NoError(t, err)
+
+	collectKeys := func(iter StateQueryIteratorInterface) []string {
+		defer iter.Close()
+		var keys []string
+		for iter.HasNext() {
+			kv, err := iter.Next()
+			assert.NoError(t, err)
+			keys = append(keys, kv.Key)
+		}
+		return keys
+	}
+
+	// the end key is excluded
+	iter, err := stub.GetPrivateDataByRange("coll", "key1", "key3")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"key1", "key2"}, collectKeys(iter))
+
+	// the composite keys are excluded from the unbounded range
+	iter, err = stub.GetPrivateDataByRange("coll", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"key1", "key2", "key3"}, collectKeys(iter))
+
+	iter, err = stub.GetPrivateDataByPartialCompositeKey("coll", "marble", []string{"blue"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{compositeKey}, collectKeys(iter))
+
+	iter, err = stub.GetPrivateDataByRange("missing", "", "")
+	assert.NoError(t, err)
+	assert.Empty(t, collectKeys(iter))
+	_, err = iter.Next()
+	assert.Error(t, err)
+	assert.Error(t, iter.Close())
+}
+
+func TestGetPrivateDataUnvalidated(t *testing.T) {
+	stub := NewMockStub("GetPrivateDataUnvalidated", nil)
+	stub.MockTransactionStart("init")
+	defer stub.MockTransactionEnd("init")
+
+	err := stub.PutPrivateData("coll", "key", []byte("value"))
+	assert.NoError(t, err)
+
+	value, err := stub.GetPrivateDataUnvalidated("coll", "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+}
+
+func TestVerifyPrivateDataHash(t *testing.T) {
+	stub := NewMockStub("VerifyPrivateDataHash", nil)
+	stub.MockTransactionStart("init")
+	defer stub.MockTransactionEnd("init")
+
+	err := stub.PutPrivateData("coll", "key", []byte("value"))
+	assert.NoError(t, err)
+
+	err = stub.VerifyPrivateDataHash("coll", "key", []byte("value"))
+	assert.NoError(t, err)
+
+	err = stub.VerifyPrivateDataHash("coll", "key", []byte("other value"))
+	assert.EqualError(t, err, "the preimage does not match the hash of key key in collection coll")
+
+	err = stub.VerifyPrivateDataHash("coll", "missing", []byte("value"))
+	assert.EqualError(t, err, "key missing does not exist in collection coll")
+
+	blindingFactor := bytes.Repeat([]byte{1}, pedersen.BlindingFactorSize)
+	commitment, err := pedersen.Commit([]byte("value"), blindingFactor)
+	assert.NoError(t, err)
+	assert.NoError(t, verifyPrivateDataHash("coll", "key", commitment, []byte("value"), blindingFactor))
+	err = verifyPrivateDataHash("coll", "key", commitment, []byte("other value"), blindingFactor)
+	assert.EqualError(t, err, "the preimage does not match the commitment of key key in collection coll")
+	err = verifyPrivateDataHash("coll", "key", commitment, []byte("value"), nil)
+	assert.EqualError(t, err, "the preimage does not match the commitment of key key in collection coll")
+}
+
+func TestGetStateMultipleKeys(t *testing.T) {
+	stub := NewMockStub("GetStateMultipleKeys", nil)
+	stub.MockTransactionStart("init")
+	defer stub.MockTransactionEnd("init")
+
+	assert.NoError(t, stub.PutState("A", []byte("a")))
+	assert.NoError(t, stub.PutState("C", []byte("c")))
+	assert.NoError(t, stub.PutPrivateData("coll", "A", []byte("pvt-a")))
+
+	values, err := stub.GetStateMultipleKeys([]string{"A", "B", "C"})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("a"), nil, []byte("c")}, values)
+
+	values, err = stub.GetPrivateDataMultipleKeys("coll", []string{"A", "B"})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("pvt-a"), nil}, values)
+}
+
 //TestMockMock clearly cheating for coverage... but not. Mock should
 //be tucked away under common/mocks package which is not
 //included for coverage. Moving mockstub to another package
@@ -0,0 +1,157 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shim
+
+import (
+	"strings"
+
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+	"github.com/pkg/errors"
+)
+
+// The keys of the namespaces of a chaincode start with a prefix which no
+// simple key or composite key can start with, followed by the namespace and
+// a nil byte separating it from the key. A range query within a namespace
+// thus never returns the keys of another namespace.
+const (
+	namespacePrefix    = compositeKeyNamespace + string(maxUnicodeRuneValue)
+	namespaceSeparator = "\x00"
+	namespaceEnd       = "\x01"
+)
+
+// namespaceKey returns the key of the state of the chaincode which stores the
+// key of the namespace
+func namespaceKey(namespace, key string) (string, error) {
+	if namespace == "" {
+		return "", errors.New("namespace must not be an empty string")
+	}
+	if err := validateCompositeKeyAttribute(namespace); err != nil {
+		return "", errors.WithMessage(err, "invalid namespace")
+	}
+	return namespacePrefix + namespace + namespaceSeparator + key, nil
+}
+
+// namespaceRange returns the range of the keys of the state of the chaincode
+// which store the keys of the namespace between startKey and endKey, an empty
+// endKey standing for the end of the namespace
+func namespaceRange(namespace, startKey, endKey string) (string, string, error) {
+	start, err := namespaceKey(namespace, startKey)
+	if err != nil {
+		return "", "", err
+	}
+	end := namespacePrefix + namespace + namespaceEnd
+	if endKey != "" {
+		end, _ = namespaceKey(namespace, endKey)
+	}
+	return start, end, nil
+}
+
+// validateNotReserved returns an error if the key is reserved for the
+// namespaces, whose keys are only accessed through the namespaced functions
+func validateNotReserved(key string) error {
+	if strings.HasPrefix(key, namespacePrefix) {
+		return errors.Errorf("key [%s] is reserved for the namespaces of the chaincode", key)
+	}
+	return nil
+}
+
+// namespaceIterator removes the namespace from the keys returned by an
+// iterator over the keys of a namespace
+type namespaceIterator struct {
+	StateQueryIteratorInterface
+	prefix string
+}
+
+func newNamespaceIterator(it StateQueryIteratorInterface, namespace string) *namespaceIterator {
+	return &namespaceIterator{
+		StateQueryIteratorInterface: it,
+		prefix:                      namespacePrefix + namespace + namespaceSeparator,
+	}
+}
+
+// Next returns the next key of the namespace and its value
+func (it *namespaceIterator) Next() (*queryresult.KV, error) {
+	kv, err := it.StateQueryIteratorInterface.Next()
+	if err != nil {
+		return nil, err
+	}
+	return &queryresult.KV{
+		Namespace: kv.Namespace,
+		Key:       strings.TrimPrefix(kv.Key, it.prefix),
+		Value:     kv.Value,
+	}, nil
+}
+
+// GetStateIn documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetStateIn(namespace, key string) ([]byte, error) {
+	nsKey, err := namespaceKey(namespace, key)
+	if err != nil {
+		return nil, err
+	}
+	return stub.handler.handleGetState("", nsKey, stub.ChannelId, stub.TxID)
+}
+
+// PutStateIn documentation can be found in interfaces.go
+func (stub *ChaincodeStub) PutStateIn(namespace, key string, value []byte) error {
+	if key == "" {
+		return errors.New("key must not be an empty string")
+	}
+	nsKey, err := namespaceKey(namespace, key)
+	if err != nil {
+		return err
+	}
+	return stub.handler.handlePutState("", nsKey, value, stub.ChannelId, stub.TxID)
+}
+
+// DelStateIn documentation can be found in interfaces.go
+func (stub *ChaincodeStub) DelStateIn(namespace, key string) error {
+	nsKey, err := namespaceKey(namespace, key)
+	if err != nil {
+		return err
+	}
+	return stub.handler.handleDelState("", nsKey, stub.ChannelId, stub.TxID)
+}
+
+// GetStateByRangeIn documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetStateByRangeIn(namespace, startKey, endKey string) (StateQueryIteratorInterface, error) {
+	if startKey == "" {
+		startKey = emptyKeySubstitute
+	}
+	if err := validateSimpleKeys(startKey, endKey); err != nil {
+		return nil, err
+	}
+	start, end, err := namespaceRange(namespace, startKey, endKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// ignore QueryResponseMetadata as it is not applicable for a range query without pagination
+	iterator, _, err := stub.handleGetStateByRange("", start, end, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newNamespaceIterator(iterator, namespace), nil
+}
+
+// GetStateByPartialCompositeKeyIn documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetStateByPartialCompositeKeyIn(namespace, objectType string, attributes []string) (StateQueryIteratorInterface, error) {
+	startKey, endKey, err := stub.createRangeKeysForPartialCompositeKey(objectType, attributes)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := namespaceRange(namespace, startKey, endKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// ignore QueryResponseMetadata as it is not applicable for a partial composite key query without pagination
+	iterator, _, err := stub.handleGetStateByRange("", start, end, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newNamespaceIterator(iterator, namespace), nil
+}
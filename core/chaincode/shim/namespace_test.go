@@ -0,0 +1,94 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rangeKeys(t *testing.T, it StateQueryIteratorInterface) []string {
+	defer it.Close()
+	var keys []string
+	for it.HasNext() {
+		kv, err := it.Next()
+		require.NoError(t, err)
+		keys = append(keys, kv.Key)
+	}
+	return keys
+}
+
+func TestNamespaces(t *testing.T) {
+	stub := NewMockStub("mycc", nil)
+	stub.MockTransactionStart("tx1")
+	compositeKey, err := stub.CreateCompositeKey("owner", []string{"alice", "asset1"})
+	require.NoError(t, err)
+	otherCompositeKey, err := stub.CreateCompositeKey("owner", []string{"bob", "asset2"})
+	require.NoError(t, err)
+
+	require.NoError(t, stub.PutState("a", []byte("plain")))
+	require.NoError(t, stub.PutStateIn("assets", "a", []byte("asset a")))
+	require.NoError(t, stub.PutStateIn("assets", "b", []byte("asset b")))
+	require.NoError(t, stub.PutStateIn("assets", compositeKey, []byte("alice")))
+	require.NoError(t, stub.PutStateIn("assetsArchive", "c", []byte("archived c")))
+	require.NoError(t, stub.PutStateIn("assetsArchive", otherCompositeKey, []byte("bob")))
+
+	value, err := stub.GetStateIn("assets", "a")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("asset a"), value)
+	value, err = stub.GetState("a")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("plain"), value)
+
+	// the range queries only return the keys of the namespace
+	it, err := stub.GetStateByRangeIn("assets", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, rangeKeys(t, it))
+	it, err = stub.GetStateByRangeIn("assets", "b", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, rangeKeys(t, it))
+	it, err = stub.GetStateByRangeIn("assetsArchive", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c"}, rangeKeys(t, it))
+
+	it, err = stub.GetStateByPartialCompositeKeyIn("assets", "owner", nil)
+	require.NoError(t, err)
+	keys := rangeKeys(t, it)
+	assert.Equal(t, []string{compositeKey}, keys)
+	objectType, attributes, err := stub.SplitCompositeKey(keys[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "owner", objectType)
+	assert.Equal(t, []string{"alice", "asset1"}, attributes)
+
+	require.NoError(t, stub.DelStateIn("assets", "a"))
+	value, err = stub.GetStateIn("assets", "a")
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+}
+
+func TestNamespaceErrors(t *testing.T) {
+	stub := NewMockStub("mycc", nil)
+	stub.MockTransactionStart("tx1")
+
+	_, err := stub.GetStateIn("", "a")
+	assert.EqualError(t, err, "namespace must not be an empty string")
+	err = stub.PutStateIn("ass\x00ets", "a", []byte("asset a"))
+	assert.Error(t, err)
+	_, err = stub.GetStateByRangeIn("assets", "\x00a", "")
+	assert.Error(t, err)
+
+	// the keys of the namespaces are reserved
+	key, err := namespaceKey("assets", "a")
+	require.NoError(t, err)
+	chaincodeStub := &ChaincodeStub{}
+	_, err = chaincodeStub.GetState(key)
+	assert.EqualError(t, err, "key ["+key+"] is reserved for the namespaces of the chaincode")
+	assert.Error(t, chaincodeStub.PutState(key, []byte("asset a")))
+	assert.Error(t, chaincodeStub.DelState(key))
+}
@@ -18,11 +18,14 @@ package shim
 
 import (
 	"bytes"
+	"context"
 	"os"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/hyperledger/fabric/common/flogging"
 	mockpeer "github.com/hyperledger/fabric/common/mocks/peer"
 	"github.com/hyperledger/fabric/common/util"
@@ -97,6 +100,14 @@ func (t *shimTestCC) Invoke(stub ChaincodeStubInterface) pb.Response {
 		return t.putEP(stub)
 	} else if function == "getep" {
 		return t.getEP(stub)
+	} else if function == "getpvthash" {
+		return t.getPvtHash(stub)
+	} else if function == "verifypvthash" {
+		return t.verifyPvtHash(stub)
+	} else if function == "getpvtunvalidated" {
+		return t.getPvtUnvalidated(stub)
+	} else if function == "getmultiple" {
+		return t.getMultiple(stub)
 	}
 
 	return Error("Invalid invoke function name. Expecting \"invoke\" \"delete\" \"query\"")
@@ -370,6 +381,44 @@ func (t *shimTestCC) getEP(stub ChaincodeStubInterface) pb.Response {
 	return Success(ep)
 }
 
+func (t *shimTestCC) getPvtHash(stub ChaincodeStubInterface) pb.Response {
+	args := stub.GetArgs()
+	hash, err := stub.GetPrivateDataHash(string(args[1]), string(args[2]))
+	if err != nil {
+		return Error(err.Error())
+	}
+	return Success(hash)
+}
+
+func (t *shimTestCC) getPvtUnvalidated(stub ChaincodeStubInterface) pb.Response {
+	args := stub.GetArgs()
+	value, err := stub.GetPrivateDataUnvalidated(string(args[1]), string(args[2]))
+	if err != nil {
+		return Error(err.Error())
+	}
+	return Success(value)
+}
+
+func (t *shimTestCC) verifyPvtHash(stub ChaincodeStubInterface) pb.Response {
+	args := stub.GetArgs()
+	if err := stub.VerifyPrivateDataHash(string(args[1]), string(args[2]), args[3]); err != nil {
+		return Error(err.Error())
+	}
+	return Success(nil)
+}
+
+func (t *shimTestCC) getMultiple(stub ChaincodeStubInterface) pb.Response {
+	args := stub.GetStringArgs()
+	values, err := stub.GetStateMultipleKeys(args[1:])
+	if err != nil {
+		return Error(err.Error())
+	}
+	if values[1] != nil {
+		return Error("expected no value for " + args[2])
+	}
+	return Success(bytes.Join(values, []byte(",")))
+}
+
 // Test Go shim functionality that can be tested outside of a real chaincode
 // context.
 
@@ -1033,6 +1082,83 @@ func TestSetKeyEP(t *testing.T) {
 	//wait for done
 	processDone(t, done, false)
 
+	// get the private data hash of A
+	respSet = &mockpeer.MockResponseSet{
+		DoneFunc:  errorFunc,
+		ErrorFunc: errorFunc,
+		Responses: []*mockpeer.MockResponse{
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_PRIVATE_DATA_HASH, Txid: "6", ChannelId: channelID}, RespMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: []byte("hashA"), Txid: "6", ChannelId: channelID}},
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED, Txid: "6", ChannelId: channelID}, RespMsg: nil},
+		},
+	}
+	peerSide.SetResponses(respSet)
+
+	ci = &pb.ChaincodeInput{Args: [][]byte{[]byte("getpvthash"), []byte("coll"), []byte("A")}, Decorations: nil}
+	payload = utils.MarshalOrPanic(ci)
+
+	peerSide.Send(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_TRANSACTION, Payload: payload, Txid: "6", ChannelId: channelID})
+
+	//wait for done
+	processDone(t, done, false)
+
+	// get A, B and C in a single request, B not existing
+	payload = utils.MarshalOrPanic(&pb.GetStateMultipleResult{Values: [][]byte{[]byte("100"), nil, []byte("300")}})
+	respSet = &mockpeer.MockResponseSet{
+		DoneFunc:  errorFunc,
+		ErrorFunc: errorFunc,
+		Responses: []*mockpeer.MockResponse{
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_STATE_MULTIPLE, Txid: "7", ChannelId: channelID}, RespMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: payload, Txid: "7", ChannelId: channelID}},
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED, Txid: "7", ChannelId: channelID}, RespMsg: nil},
+		},
+	}
+	peerSide.SetResponses(respSet)
+
+	ci = &pb.ChaincodeInput{Args: [][]byte{[]byte("getmultiple"), []byte("A"), []byte("B"), []byte("C")}, Decorations: nil}
+	payload = utils.MarshalOrPanic(ci)
+
+	peerSide.Send(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_TRANSACTION, Payload: payload, Txid: "7", ChannelId: channelID})
+
+	//wait for done
+	processDone(t, done, false)
+
+	// verify the value of A against its private data hash
+	respSet = &mockpeer.MockResponseSet{
+		DoneFunc:  errorFunc,
+		ErrorFunc: errorFunc,
+		Responses: []*mockpeer.MockResponse{
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_VERIFY_PRIVATE_DATA_HASH, Txid: "8", ChannelId: channelID}, RespMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Txid: "8", ChannelId: channelID}},
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED, Txid: "8", ChannelId: channelID}, RespMsg: nil},
+		},
+	}
+	peerSide.SetResponses(respSet)
+
+	ci = &pb.ChaincodeInput{Args: [][]byte{[]byte("verifypvthash"), []byte("coll"), []byte("A"), []byte("valueA")}, Decorations: nil}
+	payload = utils.MarshalOrPanic(ci)
+
+	peerSide.Send(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_TRANSACTION, Payload: payload, Txid: "8", ChannelId: channelID})
+
+	//wait for done
+	processDone(t, done, false)
+
+	// read the private value of A without recording it in the read set
+	respSet = &mockpeer.MockResponseSet{
+		DoneFunc:  errorFunc,
+		ErrorFunc: errorFunc,
+		Responses: []*mockpeer.MockResponse{
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_STATE, Txid: "9", ChannelId: channelID}, RespMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: []byte("valueA"), Txid: "9", ChannelId: channelID}},
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED, Txid: "9", ChannelId: channelID}, RespMsg: nil},
+		},
+	}
+	peerSide.SetResponses(respSet)
+
+	ci = &pb.ChaincodeInput{Args: [][]byte{[]byte("getpvtunvalidated"), []byte("coll"), []byte("A")}, Decorations: nil}
+	payload = utils.MarshalOrPanic(ci)
+
+	peerSide.Send(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_TRANSACTION, Payload: payload, Txid: "9", ChannelId: channelID})
+
+	//wait for done
+	processDone(t, done, false)
+
 }
 
 func TestStartInProc(t *testing.T) {
@@ -1197,3 +1323,37 @@ func TestSend(t *testing.T) {
 	err := stream.Send(msg)
 	assert.NotNil(t, err, "should have errored on panic")
 }
+
+func TestTransactionContext(t *testing.T) {
+	ctx, cancel := transactionContext(&pb.ChaincodeMessage{})
+	_, ok := ctx.Deadline()
+	assert.False(t, ok)
+	cancel()
+	assert.Equal(t, context.Canceled, ctx.Err())
+
+	deadline := time.Now().Add(time.Minute).Round(0)
+	ts, err := ptypes.TimestampProto(deadline)
+	assert.NoError(t, err)
+	ctx, cancel = transactionContext(&pb.ChaincodeMessage{Deadline: ts})
+	defer cancel()
+	d, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.True(t, deadline.Equal(d))
+
+	stub := &ChaincodeStub{}
+	assert.Equal(t, context.Background(), stub.GetContext())
+	stub.ctx = ctx
+	assert.Equal(t, ctx, stub.GetContext())
+}
+
+func TestStateQueryIteratorWarning(t *testing.T) {
+	iter := &StateQueryIterator{CommonIterator: &CommonIterator{response: &pb.QueryResponse{}}}
+	var warner QueryWarner = iter
+	assert.Empty(t, warner.Warning())
+
+	iter.response.Metadata = utils.MarshalOrPanic(&pb.QueryResponseMetadata{Warning: "no index"})
+	assert.Equal(t, "no index", warner.Warning())
+
+	iter.response.Metadata = []byte("garbage")
+	assert.Empty(t, warner.Warning())
+}
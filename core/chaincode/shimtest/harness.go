@@ -0,0 +1,586 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package shimtest provides a harness for the unit tests of the chaincodes,
+// which unlike the MockStub of the shim executes them with the shim and the
+// handler of the peer against a ledger. The transactions are simulated,
+// endorsed and committed as they are on a network: their endorsements must
+// satisfy the endorsement policies of the chaincodes and the validation
+// parameters of the keys they write, their read-write sets are validated for
+// MVCC and phantom read conflicts, and their private data is only stored for
+// the collections the organization of the peer is a member of.
+package shimtest
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/flogging"
+	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/chaincode"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/hyperledger/fabric/core/scc/lscc"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+// chaincodeVersion is the version all the chaincodes are deployed with
+const chaincodeVersion = "1.0"
+
+var logger = flogging.MustGetLogger("shimtest")
+
+// Collection is a private data collection of a chaincode
+type Collection struct {
+	// Name is the name of the collection
+	Name string
+	// MemberMSPIDs are the MSPs of the organizations members of the collection
+	MemberMSPIDs []string
+	// BlockToLive is the number of blocks the private data is kept for, zero
+	// meaning forever
+	BlockToLive uint64
+}
+
+// Proposal is a proposal for a transaction of a chaincode
+type Proposal struct {
+	// Chaincode is the name of the invoked chaincode
+	Chaincode string
+	// Args are the arguments of the invocation
+	Args [][]byte
+	// Transient is the transient data of the proposal
+	Transient map[string][]byte
+	// Creator is the serialized identity of the creator of the proposal,
+	// the default identity of the harness being used when it is nil
+	Creator []byte
+	// Init is set to invoke the Init function of the chaincode
+	Init bool
+	// Endorsers are the MSPs of the organizations whose peers endorse the
+	// proposal, the MSP of the harness being used when it is empty
+	Endorsers []string
+}
+
+// Transaction is a transaction endorsed by the harness
+type Transaction struct {
+	// TxID is the ID of the transaction
+	TxID string
+	// Response is the response of the chaincode
+	Response *pb.Response
+	// Event is the event set by the chaincode, if any
+	Event *pb.ChaincodeEvent
+	// Results are the results of the simulation of the transaction
+	Results *ledger.TxSimulationResults
+
+	proposal    *pb.Proposal
+	chaincodeID *pb.ChaincodeID
+	endorsers   []string
+}
+
+// Harness executes the chaincodes deployed on a channel of a ledger of its
+// own. The harness configures the ledger through viper, so the harnesses of
+// a process must not be used concurrently.
+type Harness struct {
+	// MSPID is the MSP of the organization of the peer, which determines the
+	// collections whose private data the peer stores, and of the default
+	// creator of the proposals
+	MSPID string
+	// Timeout bounds the execution of the transactions
+	Timeout time.Duration
+
+	channelID   string
+	dir         string
+	provider    ledger.PeerLedgerProvider
+	ledger      ledger.PeerLedger
+	registry    *chaincode.HandlerRegistry
+	appConfig   channelconfig.Application
+	mutex       sync.Mutex
+	collections map[string]map[string]*cb.StaticCollectionConfig
+	stops       []func()
+}
+
+// New returns a harness with an empty ledger for the channel, whose peer
+// belongs to the organization of the MSP
+func New(channelID, mspID string) (*Harness, error) {
+	dir, err := ioutil.TempDir("", "shimtest")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the directory of the ledger")
+	}
+	viper.Set("peer.fileSystemPath", dir)
+	viper.Set("ledger.state.stateDatabase", "goleveldb")
+	viper.Set("ledger.history.enableHistoryDatabase", true)
+
+	provider, err := kvledger.NewProvider()
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	provider.Initialize(&ledger.Initializer{
+		DeployedChaincodeInfoProvider: &lscc.DeployedCCInfoProvider{},
+		MembershipInfoProvider:        &membershipInfoProvider{mspID: mspID},
+	})
+
+	h := &Harness{
+		MSPID:     mspID,
+		Timeout:   30 * time.Second,
+		channelID: channelID,
+		dir:       dir,
+		provider:  provider,
+		registry:  chaincode.NewHandlerRegistry(true),
+		appConfig: &mockconfig.MockApplication{
+			CapabilitiesRv: &mockconfig.MockApplicationCapabilities{
+				PrivateChannelDataRv:  true,
+				KeyLevelEndorsementRv: true,
+			},
+		},
+		collections: map[string]map[string]*cb.StaticCollectionConfig{},
+	}
+
+	block, err := genesisBlock(channelID)
+	if err == nil {
+		h.ledger, err = provider.Create(block)
+	}
+	if err != nil {
+		h.Close()
+		return nil, errors.WithMessage(err, "failed to create the ledger")
+	}
+	return h, nil
+}
+
+// Close stops the chaincodes and removes the ledger
+func (h *Harness) Close() {
+	h.mutex.Lock()
+	stops := h.stops
+	h.stops = nil
+	h.mutex.Unlock()
+	for _, stop := range stops {
+		stop()
+	}
+
+	if h.ledger != nil {
+		h.ledger.Close()
+	}
+	h.provider.Close()
+	os.RemoveAll(h.dir)
+}
+
+// Deploy deploys the chaincode with its collections, and starts it. Its Init
+// function is only invoked by Init. Its endorsement policy is satisfied by
+// the endorsement of any member of the organization of the harness.
+func (h *Harness) Deploy(name string, cc shim.Chaincode, collections ...*Collection) error {
+	return h.DeployWithPolicy(name, cc, cauthdsl.SignedByAnyMember([]string{h.MSPID}), collections...)
+}
+
+// DeployWithPolicy deploys the chaincode as Deploy does, with the endorsement
+// policy the transactions of the chaincode must satisfy to be committed
+func (h *Harness) DeployWithPolicy(name string, cc shim.Chaincode, policy *cb.SignaturePolicyEnvelope, collections ...*Collection) error {
+	cname := name + ":" + chaincodeVersion
+	launchState, started := h.registry.Launching(cname)
+	if started {
+		return errors.Errorf("chaincode %s is already deployed", name)
+	}
+	if err := h.define(name, policy, collections); err != nil {
+		h.registry.Deregister(cname)
+		return err
+	}
+
+	peerRecv := make(chan *pb.ChaincodeMessage)
+	ccRecv := make(chan *pb.ChaincodeMessage)
+	ccDone := make(chan struct{})
+	peerDone := make(chan struct{})
+	go func() {
+		defer close(ccDone)
+		shim.StartInProc([]string{"CORE_CHAINCODE_ID_NAME=" + cname}, nil, cc, ccRecv, peerRecv)
+	}()
+	go func() {
+		defer close(peerDone)
+		h.handler().ProcessStream(&stream{recv: peerRecv, send: ccRecv})
+	}()
+
+	var stopOnce sync.Once
+	stop := func() {
+		stopOnce.Do(func() {
+			close(ccRecv)
+			<-ccDone
+			close(peerRecv)
+			<-peerDone
+		})
+	}
+	h.mutex.Lock()
+	h.stops = append(h.stops, stop)
+	h.mutex.Unlock()
+
+	select {
+	case <-launchState.Done():
+		return errors.WithMessage(launchState.Err(), "failed to start chaincode")
+	case <-time.After(h.Timeout):
+		return errors.Errorf("timeout expired while starting chaincode %s", name)
+	}
+}
+
+// define commits the definition of the chaincode and of its collections to
+// the state of the lifecycle, as an instantiation does
+func (h *Harness) define(name string, policy *cb.SignaturePolicyEnvelope, collections []*Collection) error {
+	policyBytes, err := proto.Marshal(policy)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal endorsement policy")
+	}
+	cd := &ccprovider.ChaincodeData{
+		Name:    name,
+		Version: chaincodeVersion,
+		Escc:    "escc",
+		Vscc:    "vscc",
+		Policy:  policyBytes,
+		Id:      util.ComputeSHA256([]byte(name + ":" + chaincodeVersion)),
+	}
+
+	configs := map[string]*cb.StaticCollectionConfig{}
+	pkg := &cb.CollectionConfigPackage{}
+	for _, c := range collections {
+		config := &cb.StaticCollectionConfig{
+			Name: c.Name,
+			MemberOrgsPolicy: &cb.CollectionPolicyConfig{
+				Payload: &cb.CollectionPolicyConfig_SignaturePolicy{
+					SignaturePolicy: cauthdsl.SignedByAnyMember(c.MemberMSPIDs),
+				},
+			},
+			MaximumPeerCount: int32(len(c.MemberMSPIDs)),
+			BlockToLive:      c.BlockToLive,
+		}
+		configs[c.Name] = config
+		pkg.Config = append(pkg.Config, &cb.CollectionConfig{Payload: &cb.CollectionConfig_StaticCollectionConfig{StaticCollectionConfig: config}})
+	}
+
+	lsccID := &pb.ChaincodeID{Name: lsccNamespace, Version: util.GetSysCCVersion()}
+	prop, txID, err := h.proposal(lsccID.Name, &pb.ChaincodeInput{Args: util.ToChaincodeArgs("deploy", h.channelID)}, nil, nil)
+	if err != nil {
+		return err
+	}
+	sim, err := h.ledger.NewTxSimulator(txID)
+	if err != nil {
+		return err
+	}
+	err = sim.SetState(lsccNamespace, name, utils.MarshalOrPanic(cd))
+	if err == nil && len(collections) != 0 {
+		err = sim.SetState(lsccNamespace, privdata.BuildCollectionKVSKey(name), utils.MarshalOrPanic(pkg))
+	}
+	var results *ledger.TxSimulationResults
+	if err == nil {
+		results, err = sim.GetTxSimulationResults()
+	}
+	// the simulator blocks the commit until it is done
+	sim.Done()
+	if err != nil {
+		return err
+	}
+
+	tx := &Transaction{
+		TxID:        txID,
+		Response:    &pb.Response{Status: shim.OK},
+		Results:     results,
+		proposal:    prop,
+		chaincodeID: lsccID,
+	}
+	codes, err := h.commit([]*Transaction{tx})
+	if err != nil {
+		return err
+	}
+	if codes[0] != pb.TxValidationCode_VALID {
+		return errors.Errorf("failed to deploy chaincode %s: %s", name, codes[0])
+	}
+
+	h.mutex.Lock()
+	h.collections[name] = configs
+	h.mutex.Unlock()
+	return nil
+}
+
+// handler returns a handler of the peer for a chaincode of the harness
+func (h *Harness) handler() *chaincode.Handler {
+	return &chaincode.Handler{
+		Invoker:                    &invoker{harness: h},
+		DefinitionGetter:           definitionGetter{},
+		Registry:                   h.registry,
+		ACLProvider:                allowAll{},
+		TXContexts:                 chaincode.NewTransactionContexts(),
+		ActiveTransactions:         chaincode.NewActiveTransactions(),
+		SystemCCProvider:           allowAll{},
+		SystemCCVersion:            util.GetSysCCVersion(),
+		InstantiationPolicyChecker: allowAll{},
+		QueryResponseBuilder:       &chaincode.QueryResponseGenerator{MaxResultLimit: 100},
+		UUIDGenerator:              chaincode.UUIDGeneratorFunc(util.GenerateUUID),
+		LedgerGetter:               h,
+		AppConfig:                  h,
+	}
+}
+
+// Endorse simulates the proposal and returns the endorsed transaction. A
+// transaction whose response has an error status is not endorsed and cannot
+// be committed.
+func (h *Harness) Endorse(p *Proposal) (*Transaction, error) {
+	input := &pb.ChaincodeInput{Args: p.Args}
+	prop, txID, err := h.proposal(p.Chaincode, input, p.Creator, p.Transient)
+	if err != nil {
+		return nil, err
+	}
+	propBytes, err := utils.GetBytesProposal(prop)
+	if err != nil {
+		return nil, err
+	}
+
+	sim, err := h.ledger.NewTxSimulator(txID)
+	if err != nil {
+		return nil, err
+	}
+	defer sim.Done()
+	hqe, err := h.ledger.NewHistoryQueryExecutor()
+	if err != nil {
+		return nil, err
+	}
+	txParams := &ccprovider.TransactionParams{
+		TxID:                 txID,
+		ChannelID:            h.channelID,
+		SignedProp:           &pb.SignedProposal{ProposalBytes: propBytes},
+		Proposal:             prop,
+		TXSimulator:          sim,
+		HistoryQueryExecutor: hqe,
+		Context:              context.Background(),
+	}
+
+	msgType := pb.ChaincodeMessage_TRANSACTION
+	if p.Init {
+		msgType = pb.ChaincodeMessage_INIT
+	}
+	resp, err := h.execute(msgType, txParams, p.Chaincode, input)
+	if err != nil {
+		return nil, err
+	}
+	res, event, err := executionResult(txID, p.Chaincode, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &Transaction{
+		TxID:        txID,
+		Response:    res,
+		Event:       event,
+		proposal:    prop,
+		chaincodeID: &pb.ChaincodeID{Name: p.Chaincode, Version: chaincodeVersion},
+		endorsers:   p.Endorsers,
+	}
+	if res.Status >= shim.ERRORTHRESHOLD {
+		return tx, nil
+	}
+	if tx.Results, err = sim.GetTxSimulationResults(); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// proposal returns a proposal for the invocation of the chaincode
+func (h *Harness) proposal(chaincodeName string, input *pb.ChaincodeInput, creator []byte, transient map[string][]byte) (*pb.Proposal, string, error) {
+	if creator == nil {
+		creator = utils.MarshalOrPanic(&msp.SerializedIdentity{Mspid: h.MSPID})
+	}
+	cis := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_GOLANG,
+			ChaincodeId: &pb.ChaincodeID{Name: chaincodeName},
+			Input:       input,
+		},
+	}
+	return utils.CreateChaincodeProposalWithTransient(cb.HeaderType_ENDORSER_TRANSACTION, h.channelID, cis, creator, transient)
+}
+
+// Commit commits a block of the endorsed transactions, and returns their
+// validation codes
+func (h *Harness) Commit(txs ...*Transaction) ([]pb.TxValidationCode, error) {
+	for _, tx := range txs {
+		if tx.Results == nil {
+			return nil, errors.Errorf("transaction %s was not endorsed: %s", tx.TxID, tx.Response.Message)
+		}
+	}
+	return h.commit(txs)
+}
+
+// Init invokes the Init function of the chaincode, and commits the
+// transaction if it is endorsed
+func (h *Harness) Init(chaincodeName string, args ...string) (*pb.Response, error) {
+	return h.invoke(&Proposal{Chaincode: chaincodeName, Args: util.ToChaincodeArgs(args...), Init: true})
+}
+
+// Invoke invokes the chaincode, and commits the transaction if it is
+// endorsed. An error is returned if the committed transaction is invalid.
+func (h *Harness) Invoke(chaincodeName string, args ...string) (*pb.Response, error) {
+	return h.invoke(&Proposal{Chaincode: chaincodeName, Args: util.ToChaincodeArgs(args...)})
+}
+
+func (h *Harness) invoke(p *Proposal) (*pb.Response, error) {
+	tx, err := h.Endorse(p)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Results == nil {
+		return tx.Response, nil
+	}
+	codes, err := h.Commit(tx)
+	if err != nil {
+		return nil, err
+	}
+	if codes[0] != pb.TxValidationCode_VALID {
+		return tx.Response, errors.Errorf("transaction %s is invalid: %s", tx.TxID, codes[0])
+	}
+	return tx.Response, nil
+}
+
+// Query invokes the chaincode without committing the transaction
+func (h *Harness) Query(chaincodeName string, args ...string) (*pb.Response, error) {
+	tx, err := h.Endorse(&Proposal{Chaincode: chaincodeName, Args: util.ToChaincodeArgs(args...)})
+	if err != nil {
+		return nil, err
+	}
+	return tx.Response, nil
+}
+
+// GetState returns the committed value of the key of the chaincode
+func (h *Harness) GetState(chaincodeName, key string) ([]byte, error) {
+	qe, err := h.ledger.NewQueryExecutor()
+	if err != nil {
+		return nil, err
+	}
+	defer qe.Done()
+	return qe.GetState(chaincodeName, key)
+}
+
+// GetPrivateData returns the committed value of the key of the collection of
+// the chaincode, which the peer only has if it is a member of the collection
+func (h *Harness) GetPrivateData(chaincodeName, collection, key string) ([]byte, error) {
+	qe, err := h.ledger.NewQueryExecutor()
+	if err != nil {
+		return nil, err
+	}
+	defer qe.Done()
+	return qe.GetPrivateData(chaincodeName, collection, key)
+}
+
+// collection returns the config of the collection of the chaincode
+func (h *Harness) collection(chaincodeName, collection string) (*cb.StaticCollectionConfig, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	config, ok := h.collections[chaincodeName][collection]
+	if !ok {
+		return nil, errors.Errorf("collection %s of chaincode %s is not defined", collection, chaincodeName)
+	}
+	return config, nil
+}
+
+// execute sends the message to the chaincode and waits for its response
+func (h *Harness) execute(msgType pb.ChaincodeMessage_Type, txParams *ccprovider.TransactionParams, chaincodeName string, input *pb.ChaincodeInput) (*pb.ChaincodeMessage, error) {
+	cname := chaincodeName + ":" + chaincodeVersion
+	handler := h.registry.Handler(cname)
+	if handler == nil {
+		return nil, errors.Errorf("chaincode %s is not deployed", chaincodeName)
+	}
+
+	msg := &pb.ChaincodeMessage{
+		Type:      msgType,
+		Payload:   utils.MarshalOrPanic(input),
+		Txid:      txParams.TxID,
+		ChannelId: txParams.ChannelID,
+	}
+	return handler.Execute(txParams, &ccprovider.CCContext{Name: chaincodeName, Version: chaincodeVersion}, msg, h.Timeout)
+}
+
+// executionResult returns the response and the event of the chaincode
+func executionResult(txID, chaincodeName string, resp *pb.ChaincodeMessage) (*pb.Response, *pb.ChaincodeEvent, error) {
+	if resp.ChaincodeEvent != nil {
+		resp.ChaincodeEvent.ChaincodeId = chaincodeName
+		resp.ChaincodeEvent.TxId = txID
+	}
+
+	switch resp.Type {
+	case pb.ChaincodeMessage_COMPLETED:
+		res := &pb.Response{}
+		if err := proto.Unmarshal(resp.Payload, res); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to unmarshal response for transaction %s", txID)
+		}
+		return res, resp.ChaincodeEvent, nil
+	case pb.ChaincodeMessage_ERROR:
+		return nil, nil, errors.Errorf("transaction returned with failure: %s", resp.Payload)
+	default:
+		return nil, nil, errors.Errorf("unexpected response type %d for transaction %s", resp.Type, txID)
+	}
+}
+
+// GetLedger returns the ledger of the channel of the harness
+func (h *Harness) GetLedger(cid string) ledger.PeerLedger {
+	if cid != h.channelID {
+		return nil
+	}
+	return h.ledger
+}
+
+// GetApplicationConfig returns the application config of the channel of the
+// harness, which enables all the capabilities the chaincodes use
+func (h *Harness) GetApplicationConfig(cid string) (channelconfig.Application, bool) {
+	return h.appConfig, cid == h.channelID
+}
+
+// invoker invokes the chaincodes called by other chaincodes
+type invoker struct {
+	harness *Harness
+}
+
+func (i *invoker) Invoke(txParams *ccprovider.TransactionParams, cccid *ccprovider.CCContext, input *pb.ChaincodeInput) (*pb.ChaincodeMessage, error) {
+	return i.harness.execute(pb.ChaincodeMessage_TRANSACTION, txParams, cccid.Name, input)
+}
+
+// allowAll allows all the invocations, and knows of no system chaincode
+type allowAll struct{}
+
+func (allowAll) CheckACL(resName string, channelID string, idinfo interface{}) error { return nil }
+func (allowAll) IsSysCC(name string) bool                                            { return false }
+func (allowAll) IsSysCCAndNotInvokableCC2CC(name string) bool                        { return false }
+func (allowAll) CheckInstantiationPolicy(name, version string, cd *ccprovider.ChaincodeData) error {
+	return nil
+}
+
+// stream is the stream between the handler of the peer and the chaincode
+type stream struct {
+	recv <-chan *pb.ChaincodeMessage
+	send chan<- *pb.ChaincodeMessage
+}
+
+func (s *stream) Send(msg *pb.ChaincodeMessage) (err error) {
+	// the chaincode may have been stopped
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("send failure %s", r)
+		}
+	}()
+	s.send <- msg
+	return nil
+}
+
+func (s *stream) Recv() (*pb.ChaincodeMessage, error) {
+	msg, ok := <-s.recv
+	if !ok {
+		return nil, errors.New("channel is closed")
+	}
+	return msg, nil
+}
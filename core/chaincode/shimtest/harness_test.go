@@ -0,0 +1,273 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shimtest
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/chaincode/shim/ext/statebased"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testChaincode stores its state in the public state and in a collection
+type testChaincode struct{}
+
+func (testChaincode) Init(stub shim.ChaincodeStubInterface) pb.Response {
+	if err := stub.PutState("initialized", []byte("true")); err != nil {
+		return shim.Error(err.Error())
+	}
+	return shim.Success(nil)
+}
+
+func (testChaincode) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
+	function, args := stub.GetFunctionAndParameters()
+	switch function {
+	case "put":
+		if err := stub.PutState(args[0], []byte(args[1])); err != nil {
+			return shim.Error(err.Error())
+		}
+		if err := stub.SetEvent("put", []byte(args[0])); err != nil {
+			return shim.Error(err.Error())
+		}
+		return shim.Success(nil)
+	case "get":
+		value, err := stub.GetState(args[0])
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		return shim.Success(value)
+	case "increment":
+		value, err := stub.GetState(args[0])
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		n, _ := strconv.Atoi(string(value))
+		if err := stub.PutState(args[0], []byte(strconv.Itoa(n+1))); err != nil {
+			return shim.Error(err.Error())
+		}
+		return shim.Success(nil)
+	case "count":
+		it, err := stub.GetStateByRange(args[0], args[1])
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		defer it.Close()
+		count := 0
+		for it.HasNext() {
+			if _, err := it.Next(); err != nil {
+				return shim.Error(err.Error())
+			}
+			count++
+		}
+		if err := stub.PutState("count", []byte(strconv.Itoa(count))); err != nil {
+			return shim.Error(err.Error())
+		}
+		return shim.Success([]byte(strconv.Itoa(count)))
+	case "putPrivate":
+		transient, err := stub.GetTransient()
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		if err := stub.PutPrivateData(args[0], args[1], transient["value"]); err != nil {
+			return shim.Error(err.Error())
+		}
+		return shim.Success(nil)
+	case "getPrivate":
+		value, err := stub.GetPrivateData(args[0], args[1])
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		return shim.Success(value)
+	case "setEndorsers":
+		ep, err := statebased.NewStateEP(nil)
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		if err := ep.AddOrgs(statebased.RoleTypePeer, args[1:]...); err != nil {
+			return shim.Error(err.Error())
+		}
+		policy, err := ep.Policy()
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		if err := stub.SetStateValidationParameter(args[0], policy); err != nil {
+			return shim.Error(err.Error())
+		}
+		return shim.Success(nil)
+	case "call":
+		return stub.InvokeChaincode(args[0], util.ToChaincodeArgs(args[1:]...), "")
+	default:
+		return shim.Error("unknown function " + function)
+	}
+}
+
+func newHarness(t *testing.T, mspID string) *Harness {
+	h, err := New("testchannel", mspID)
+	require.NoError(t, err)
+	require.NoError(t, h.Deploy("mycc", testChaincode{}, &Collection{Name: "secrets", MemberMSPIDs: []string{"Org1MSP"}}))
+	return h
+}
+
+func TestInvoke(t *testing.T) {
+	h := newHarness(t, "Org1MSP")
+	defer h.Close()
+
+	_, err := h.Init("mycc")
+	require.NoError(t, err)
+	value, err := h.GetState("mycc", "initialized")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("true"), value)
+
+	resp, err := h.Invoke("mycc", "put", "a", "1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(shim.OK), resp.Status)
+	resp, err = h.Query("mycc", "get", "a")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1"), resp.Payload)
+
+	tx, err := h.Endorse(&Proposal{Chaincode: "mycc", Args: util.ToChaincodeArgs("put", "b", "2")})
+	require.NoError(t, err)
+	assert.Equal(t, "put", tx.Event.EventName)
+	assert.Equal(t, "mycc", tx.Event.ChaincodeId)
+
+	// a query is not committed
+	resp, err = h.Query("mycc", "get", "b")
+	require.NoError(t, err)
+	assert.Nil(t, resp.Payload)
+
+	// the chaincodes invoke each other within the transaction
+	require.NoError(t, h.Deploy("othercc", testChaincode{}))
+	_, err = h.Invoke("othercc", "call", "mycc", "put", "c", "3")
+	require.NoError(t, err)
+	value, err = h.GetState("mycc", "c")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("3"), value)
+
+	// an error response is not endorsed
+	resp, err = h.Invoke("mycc", "foo")
+	require.NoError(t, err)
+	assert.Equal(t, int32(shim.ERROR), resp.Status)
+	tx, err = h.Endorse(&Proposal{Chaincode: "mycc", Args: util.ToChaincodeArgs("foo")})
+	require.NoError(t, err)
+	_, err = h.Commit(tx)
+	assert.EqualError(t, err, "transaction "+tx.TxID+" was not endorsed: unknown function foo")
+
+	_, err = h.Invoke("unknowncc", "get", "a")
+	assert.EqualError(t, err, "chaincode unknowncc is not deployed")
+	assert.EqualError(t, h.Deploy("mycc", testChaincode{}), "chaincode mycc is already deployed")
+}
+
+func TestConflicts(t *testing.T) {
+	h := newHarness(t, "Org1MSP")
+	defer h.Close()
+
+	// both transactions read the version of the key before either commits
+	tx1, err := h.Endorse(&Proposal{Chaincode: "mycc", Args: util.ToChaincodeArgs("increment", "counter")})
+	require.NoError(t, err)
+	tx2, err := h.Endorse(&Proposal{Chaincode: "mycc", Args: util.ToChaincodeArgs("increment", "counter")})
+	require.NoError(t, err)
+	codes, err := h.Commit(tx1, tx2)
+	require.NoError(t, err)
+	assert.Equal(t, []pb.TxValidationCode{pb.TxValidationCode_VALID, pb.TxValidationCode_MVCC_READ_CONFLICT}, codes)
+	value, err := h.GetState("mycc", "counter")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("1"), value)
+
+	// a key is added to the range read by the transaction before it commits
+	tx1, err = h.Endorse(&Proposal{Chaincode: "mycc", Args: util.ToChaincodeArgs("count", "a", "z")})
+	require.NoError(t, err)
+	_, err = h.Invoke("mycc", "put", "b", "2")
+	require.NoError(t, err)
+	codes, err = h.Commit(tx1)
+	require.NoError(t, err)
+	assert.Equal(t, []pb.TxValidationCode{pb.TxValidationCode_PHANTOM_READ_CONFLICT}, codes)
+
+	_, err = h.Invoke("mycc", "increment", "counter")
+	assert.NoError(t, err)
+	_, err = h.Invoke("mycc", "count", "a", "z")
+	assert.NoError(t, err)
+}
+
+func TestPrivateData(t *testing.T) {
+	transient := map[string][]byte{"value": []byte("secret")}
+
+	h := newHarness(t, "Org1MSP")
+	defer h.Close()
+	tx, err := h.Endorse(&Proposal{Chaincode: "mycc", Args: util.ToChaincodeArgs("putPrivate", "secrets", "a"), Transient: transient})
+	require.NoError(t, err)
+	codes, err := h.Commit(tx)
+	require.NoError(t, err)
+	assert.Equal(t, []pb.TxValidationCode{pb.TxValidationCode_VALID}, codes)
+	value, err := h.GetPrivateData("mycc", "secrets", "a")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("secret"), value)
+	resp, err := h.Query("mycc", "getPrivate", "secrets", "a")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), resp.Payload)
+
+	// the collections must be defined
+	tx, err = h.Endorse(&Proposal{Chaincode: "mycc", Args: util.ToChaincodeArgs("putPrivate", "others", "a"), Transient: transient})
+	require.NoError(t, err)
+	assert.Equal(t, int32(shim.ERROR), tx.Response.Status)
+	assert.Contains(t, tx.Response.Message, "collection [others] not defined")
+
+	// the peers of the other organizations only store the hashes
+	other := newHarness(t, "Org2MSP")
+	defer other.Close()
+	tx, err = other.Endorse(&Proposal{Chaincode: "mycc", Args: util.ToChaincodeArgs("putPrivate", "secrets", "a"), Transient: transient})
+	require.NoError(t, err)
+	codes, err = other.Commit(tx)
+	require.NoError(t, err)
+	assert.Equal(t, []pb.TxValidationCode{pb.TxValidationCode_VALID}, codes)
+	_, err = other.GetPrivateData("mycc", "secrets", "a")
+	assert.Contains(t, err.Error(), "private data matching public hash version is not available")
+}
+
+func TestEndorsementPolicy(t *testing.T) {
+	h, err := New("testchannel", "Org1MSP")
+	require.NoError(t, err)
+	defer h.Close()
+	policy, err := cauthdsl.FromString("AND('Org1MSP.member', 'Org2MSP.member')")
+	require.NoError(t, err)
+	require.NoError(t, h.DeployWithPolicy("mycc", testChaincode{}, policy))
+
+	// the endorsement of the peer of the harness does not satisfy the policy
+	_, err = h.Invoke("mycc", "put", "a", "1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is invalid: ENDORSEMENT_POLICY_FAILURE")
+
+	endorse := func(endorsers []string, args ...string) *Transaction {
+		tx, err := h.Endorse(&Proposal{Chaincode: "mycc", Args: util.ToChaincodeArgs(args...), Endorsers: endorsers})
+		require.NoError(t, err)
+		return tx
+	}
+	both := []string{"Org1MSP", "Org2MSP"}
+	codes, err := h.Commit(endorse(both, "put", "a", "1"), endorse([]string{"Org2MSP"}, "put", "b", "2"))
+	require.NoError(t, err)
+	assert.Equal(t, []pb.TxValidationCode{pb.TxValidationCode_VALID, pb.TxValidationCode_ENDORSEMENT_POLICY_FAILURE}, codes)
+	value, err := h.GetState("mycc", "b")
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	// the writes to a key with a validation parameter must satisfy it
+	// instead of the policy of the chaincode
+	codes, err = h.Commit(endorse(both, "setEndorsers", "a", "Org2MSP"))
+	require.NoError(t, err)
+	assert.Equal(t, []pb.TxValidationCode{pb.TxValidationCode_VALID}, codes)
+	codes, err = h.Commit(endorse([]string{"Org2MSP"}, "put", "a", "2"), endorse([]string{"Org1MSP"}, "put", "a", "3"))
+	require.NoError(t, err)
+	assert.Equal(t, []pb.TxValidationCode{pb.TxValidationCode_VALID, pb.TxValidationCode_ENDORSEMENT_POLICY_FAILURE}, codes)
+	value, err = h.GetState("mycc", "a")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("2"), value)
+}
@@ -0,0 +1,225 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shimtest
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/ledger"
+	lutils "github.com/hyperledger/fabric/core/ledger/util"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+const lsccNamespace = "lscc"
+
+// genesisBlock returns a genesis block for the channel holding a single
+// config transaction with an empty channel config, which the ledger commits
+// without processing it
+func genesisBlock(channelID string) (*cb.Block, error) {
+	configEnv := &cb.ConfigEnvelope{Config: &cb.Config{ChannelGroup: cb.NewConfigGroup()}}
+	env, err := utils.CreateSignedEnvelope(cb.HeaderType_CONFIG, channelID, nil, configEnv, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	block := cb.NewBlock(0, nil)
+	block.Data.Data = [][]byte{utils.MarshalOrPanic(env)}
+	block.Header.DataHash = block.Data.Hash()
+	setBlockFlagsToValid(block)
+	return block, nil
+}
+
+func setBlockFlagsToValid(block *cb.Block) {
+	utils.InitBlockMetadata(block)
+	block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] =
+		lutils.NewTxValidationFlagsSetValue(len(block.Data.Data), pb.TxValidationCode_VALID)
+}
+
+// commit cuts the next block from the transactions and commits it to the
+// ledger with their private data, returning the validation codes of the
+// transactions. The transactions whose endorsements do not satisfy their
+// endorsement policies are invalidated before the ledger validates the others.
+func (h *Harness) commit(txs []*Transaction) ([]pb.TxValidationCode, error) {
+	info, err := h.ledger.GetBlockchainInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	block := cb.NewBlock(info.Height, info.CurrentBlockHash)
+	blockPvtData := map[uint64]*ledger.TxPvtData{}
+	var envs []*cb.Envelope
+	for i, tx := range txs {
+		env, err := tx.envelope(h.MSPID)
+		if err != nil {
+			return nil, err
+		}
+		envs = append(envs, env)
+		block.Data.Data = append(block.Data.Data, utils.MarshalOrPanic(env))
+
+		pvtData, err := h.memberPvtData(tx.Results.PvtSimulationResults)
+		if err != nil {
+			return nil, err
+		}
+		if pvtData != nil {
+			blockPvtData[uint64(i)] = &ledger.TxPvtData{SeqInBlock: uint64(i), WriteSet: pvtData}
+		}
+	}
+	block.Header.DataHash = block.Data.Hash()
+	setBlockFlagsToValid(block)
+
+	flags := lutils.TxValidationFlags(block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	qe, err := h.ledger.NewQueryExecutor()
+	if err != nil {
+		return nil, err
+	}
+	for i, tx := range txs {
+		if err := checkEndorsements(envs[i], tx.Results, qe); err != nil {
+			logger.Debugf("Transaction %s fails its endorsement policy: %s", tx.TxID, err)
+			flags.SetFlag(i, pb.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+		}
+	}
+	// the query executor blocks the commit until it is done
+	qe.Done()
+
+	if err := h.ledger.CommitWithPvtData(&ledger.BlockAndPvtData{Block: block, BlockPvtData: blockPvtData}); err != nil {
+		return nil, errors.WithMessage(err, "failed to commit block")
+	}
+
+	flags = lutils.TxValidationFlags(block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	codes := make([]pb.TxValidationCode, len(txs))
+	for i := range txs {
+		codes[i] = flags.Flag(i)
+	}
+	return codes, nil
+}
+
+// envelope returns the transaction envelope of the endorsed transaction,
+// endorsed by the peers of its endorsers or else of the organization of the
+// MSP
+func (tx *Transaction) envelope(mspID string) (*cb.Envelope, error) {
+	hdr, err := utils.GetHeader(tx.proposal.Header)
+	if err != nil {
+		return nil, err
+	}
+	proposalHash, err := utils.GetProposalHash1(hdr, tx.proposal.Payload, nil)
+	if err != nil {
+		return nil, err
+	}
+	pubSimBytes, err := tx.Results.GetPubSimulationBytes()
+	if err != nil {
+		return nil, err
+	}
+	var event []byte
+	if tx.Event != nil {
+		event = utils.MarshalOrPanic(tx.Event)
+	}
+	prpBytes, err := utils.GetBytesProposalResponsePayload(proposalHash, tx.Response, pubSimBytes, event, tx.chaincodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	endorsers := tx.endorsers
+	if len(endorsers) == 0 {
+		endorsers = []string{mspID}
+	}
+	var responses []*pb.ProposalResponse
+	for _, endorser := range endorsers {
+		responses = append(responses, &pb.ProposalResponse{
+			Version:     1,
+			Payload:     prpBytes,
+			Response:    &pb.Response{Status: shim.OK},
+			Endorsement: endorse(prpBytes, endorser),
+		})
+	}
+	return utils.CreateUnsignedTx(tx.proposal, responses...)
+}
+
+// memberPvtData returns the private writes of the transaction to the
+// collections whose members include the peer, the other peers only storing
+// their hashes
+func (h *Harness) memberPvtData(pvtResults *rwset.TxPvtReadWriteSet) (*rwset.TxPvtReadWriteSet, error) {
+	if pvtResults == nil {
+		return nil, nil
+	}
+	var nsPvtRwsets []*rwset.NsPvtReadWriteSet
+	for _, nsPvtRwset := range pvtResults.NsPvtRwset {
+		var collPvtRwsets []*rwset.CollectionPvtReadWriteSet
+		for _, collPvtRwset := range nsPvtRwset.CollectionPvtRwset {
+			config, err := h.collection(nsPvtRwset.Namespace, collPvtRwset.CollectionName)
+			if err != nil {
+				return nil, err
+			}
+			member, err := memberOf(config.MemberOrgsPolicy, h.MSPID)
+			if err != nil {
+				return nil, err
+			}
+			if member {
+				collPvtRwsets = append(collPvtRwsets, collPvtRwset)
+			}
+		}
+		if len(collPvtRwsets) != 0 {
+			nsPvtRwsets = append(nsPvtRwsets, &rwset.NsPvtReadWriteSet{Namespace: nsPvtRwset.Namespace, CollectionPvtRwset: collPvtRwsets})
+		}
+	}
+	if len(nsPvtRwsets) == 0 {
+		return nil, nil
+	}
+	return &rwset.TxPvtReadWriteSet{DataModel: pvtResults.DataModel, NsPvtRwset: nsPvtRwsets}, nil
+}
+
+// memberOf returns whether the MSP is one of the members of the collection,
+// whose policy lists the member organizations
+func memberOf(policy *cb.CollectionPolicyConfig, mspID string) (bool, error) {
+	signaturePolicy := policy.GetSignaturePolicy()
+	if signaturePolicy == nil {
+		return false, errors.New("collection policy is not a signature policy")
+	}
+	for _, principal := range signaturePolicy.Identities {
+		role := &msp.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, role); err != nil {
+			return false, errors.Wrap(err, "failed to unmarshal collection member")
+		}
+		if role.MspIdentifier == mspID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// membershipInfoProvider tells the ledger which collections the peer is a
+// member of
+type membershipInfoProvider struct {
+	mspID string
+}
+
+func (m *membershipInfoProvider) AmMemberOf(channelName string, policy *cb.CollectionPolicyConfig) (bool, error) {
+	return memberOf(policy, m.mspID)
+}
+
+// definitionGetter reads the definitions of the chaincodes from the state of
+// the lifecycle, where the harness deploys them
+type definitionGetter struct{}
+
+func (definitionGetter) ChaincodeDefinition(chaincodeName string, qe ledger.QueryExecutor) (ccprovider.ChaincodeDefinition, error) {
+	cdBytes, err := qe.GetState(lsccNamespace, chaincodeName)
+	if err != nil {
+		return nil, err
+	}
+	if cdBytes == nil {
+		return nil, errors.Errorf("chaincode %s is not deployed", chaincodeName)
+	}
+	cd := &ccprovider.ChaincodeData{}
+	if err := proto.Unmarshal(cdBytes, cd); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal chaincode definition")
+	}
+	return cd, nil
+}
@@ -0,0 +1,226 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shimtest
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/msp"
+	cb "github.com/hyperledger/fabric/protos/common"
+	mspproto "github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// endorse returns the endorsement of the proposal response payload by a peer
+// of the organization of the MSP. The harness has no key material: the
+// signature of an endorsement is the hash of what it signs, which the
+// identities of the harness verify.
+func endorse(prpBytes []byte, mspID string) *pb.Endorsement {
+	endorser := utils.MarshalOrPanic(&mspproto.SerializedIdentity{Mspid: mspID})
+	return &pb.Endorsement{
+		Endorser:  endorser,
+		Signature: util.ComputeSHA256(append(append([]byte{}, prpBytes...), endorser...)),
+	}
+}
+
+// checkEndorsements evaluates the endorsement policies of the writes of the
+// transaction of the envelope against its endorsements, as the validation
+// of the peer does: the writes to the keys which have a validation parameter
+// must satisfy it, and the others the endorsement policy of the chaincode of
+// their namespace
+func checkEndorsements(env *cb.Envelope, results *ledger.TxSimulationResults, qe ledger.QueryExecutor) error {
+	signatureSet, err := endorsementSignatureSet(env)
+	if err != nil {
+		return err
+	}
+	txRwSet, err := rwsetutil.TxRwSetFromProtoMsg(results.PubSimulationResults)
+	if err != nil {
+		return err
+	}
+
+	provider := cauthdsl.NewPolicyProvider(identityDeserializer{})
+	evaluate := func(policyBytes []byte) error {
+		policy, _, err := provider.NewPolicy(policyBytes)
+		if err != nil {
+			return errors.WithMessage(err, "invalid endorsement policy")
+		}
+		return policy.Evaluate(signatureSet)
+	}
+
+	for _, nsRwSet := range txRwSet.NsRwSets {
+		if nsRwSet.NameSpace == lsccNamespace {
+			continue
+		}
+		parameters, checkChaincodePolicy, err := validationParameters(nsRwSet, qe)
+		if err != nil {
+			return err
+		}
+		for key, parameter := range parameters {
+			if err := evaluate(parameter); err != nil {
+				return errors.WithMessage(err, "validation parameter of key "+key+" of namespace "+nsRwSet.NameSpace+" is not satisfied")
+			}
+		}
+		if !checkChaincodePolicy {
+			continue
+		}
+		cd, err := (definitionGetter{}).ChaincodeDefinition(nsRwSet.NameSpace, qe)
+		if err != nil {
+			return err
+		}
+		_, policy := cd.Validation()
+		if err := evaluate(policy); err != nil {
+			return errors.WithMessage(err, "endorsement policy of chaincode "+nsRwSet.NameSpace+" is not satisfied")
+		}
+	}
+	return nil
+}
+
+// validationParameters returns the committed validation parameters of the
+// keys written by the namespace read-write set, and whether it writes keys
+// without one, whose writes the policy of the chaincode validates
+func validationParameters(nsRwSet *rwsetutil.NsRwSet, qe ledger.QueryExecutor) (map[string][]byte, bool, error) {
+	parameters := map[string][]byte{}
+	checkChaincodePolicy := false
+	add := func(key string, metadata map[string][]byte, err error) error {
+		if err != nil {
+			return err
+		}
+		if parameter, ok := metadata[pb.MetaDataKeys_VALIDATION_PARAMETER.String()]; ok {
+			parameters[key] = parameter
+		} else {
+			checkChaincodePolicy = true
+		}
+		return nil
+	}
+
+	ns := nsRwSet.NameSpace
+	if kvRwSet := nsRwSet.KvRwSet; kvRwSet != nil {
+		for _, write := range kvRwSet.Writes {
+			metadata, err := qe.GetStateMetadata(ns, write.Key)
+			if err := add(write.Key, metadata, err); err != nil {
+				return nil, false, err
+			}
+		}
+		for _, write := range kvRwSet.MetadataWrites {
+			metadata, err := qe.GetStateMetadata(ns, write.Key)
+			if err := add(write.Key, metadata, err); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+	for _, collHashedRwSet := range nsRwSet.CollHashedRwSets {
+		coll := collHashedRwSet.CollectionName
+		for _, write := range collHashedRwSet.HashedRwSet.HashedWrites {
+			metadata, err := qe.GetPrivateDataMetadataByHash(ns, coll, write.KeyHash)
+			if err := add(coll+"/"+string(write.KeyHash), metadata, err); err != nil {
+				return nil, false, err
+			}
+		}
+		for _, write := range collHashedRwSet.HashedRwSet.MetadataWrites {
+			metadata, err := qe.GetPrivateDataMetadataByHash(ns, coll, write.KeyHash)
+			if err := add(coll+"/"+string(write.KeyHash), metadata, err); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+	return parameters, checkChaincodePolicy, nil
+}
+
+// endorsementSignatureSet returns the endorsements of the transaction of the
+// envelope as the signed data the endorsement policies are evaluated against
+func endorsementSignatureSet(env *cb.Envelope) ([]*cb.SignedData, error) {
+	payload, err := utils.GetPayload(env)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := utils.GetTransaction(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+	if len(tx.Actions) != 1 {
+		return nil, errors.Errorf("transaction has %d actions", len(tx.Actions))
+	}
+	ccActionPayload, err := utils.GetChaincodeActionPayload(tx.Actions[0].Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var signatureSet []*cb.SignedData
+	for _, endorsement := range ccActionPayload.Action.Endorsements {
+		signatureSet = append(signatureSet, &cb.SignedData{
+			Data:      append(append([]byte{}, ccActionPayload.Action.ProposalResponsePayload...), endorsement.Endorser...),
+			Identity:  endorsement.Endorser,
+			Signature: endorsement.Signature,
+		})
+	}
+	return signatureSet, nil
+}
+
+// identityDeserializer deserializes the identities of the peers of the
+// harness, which are the members and the peers of their MSP
+type identityDeserializer struct{}
+
+func (identityDeserializer) DeserializeIdentity(serializedIdentity []byte) (msp.Identity, error) {
+	sID := &mspproto.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedIdentity, sID); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal identity")
+	}
+	return &identity{mspID: sID.Mspid, serialized: serializedIdentity}, nil
+}
+
+func (identityDeserializer) IsWellFormed(_ *mspproto.SerializedIdentity) error {
+	return nil
+}
+
+type identity struct {
+	mspID      string
+	serialized []byte
+}
+
+func (id *identity) ExpiresAt() time.Time { return time.Time{} }
+
+func (id *identity) GetIdentifier() *msp.IdentityIdentifier {
+	return &msp.IdentityIdentifier{Mspid: id.mspID, Id: id.mspID}
+}
+
+func (id *identity) GetMSPIdentifier() string                    { return id.mspID }
+func (id *identity) Validate() error                             { return nil }
+func (id *identity) GetOrganizationalUnits() []*msp.OUIdentifier { return nil }
+func (id *identity) Anonymous() bool                             { return false }
+func (id *identity) Serialize() ([]byte, error)                  { return id.serialized, nil }
+
+func (id *identity) Verify(msg []byte, sig []byte) error {
+	if !bytes.Equal(util.ComputeSHA256(msg), sig) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+func (id *identity) SatisfiesPrincipal(principal *mspproto.MSPPrincipal) error {
+	if principal.PrincipalClassification != mspproto.MSPPrincipal_ROLE {
+		return errors.Errorf("principal classification %s is not supported", principal.PrincipalClassification)
+	}
+	role := &mspproto.MSPRole{}
+	if err := proto.Unmarshal(principal.Principal, role); err != nil {
+		return errors.Wrap(err, "failed to unmarshal principal")
+	}
+	if role.MspIdentifier != id.mspID {
+		return errors.Errorf("identity of %s does not belong to %s", id.mspID, role.MspIdentifier)
+	}
+	if role.Role != mspproto.MSPRole_MEMBER && role.Role != mspproto.MSPRole_PEER {
+		return errors.Errorf("identity of %s is not a %s", id.mspID, role.Role)
+	}
+	return nil
+}
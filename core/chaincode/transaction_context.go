@@ -7,7 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package chaincode
 
 import (
+	"context"
 	"sync"
+	"time"
 
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/core/ledger"
@@ -22,11 +24,16 @@ type TransactionContext struct {
 	TXSimulator          ledger.TxSimulator
 	HistoryQueryExecutor ledger.HistoryQueryExecutor
 
+	// Context is the context of the proposal which bounds the execution of
+	// the transaction, passed to the chaincodes it invokes
+	Context context.Context
+
 	// tracks open iterators used for range queries
 	queryMutex          sync.Mutex
 	queryIteratorMap    map[string]commonledger.ResultsIterator
 	pendingQueryResults map[string]*PendingQueryResult
 	totalReturnCount    map[string]*int32
+	queryStartTimes     map[string]time.Time
 }
 
 func (t *TransactionContext) InitializeQueryContext(queryID string, iter commonledger.ResultsIterator) {
@@ -40,10 +47,14 @@ func (t *TransactionContext) InitializeQueryContext(queryID string, iter commonl
 	if t.totalReturnCount == nil {
 		t.totalReturnCount = map[string]*int32{}
 	}
+	if t.queryStartTimes == nil {
+		t.queryStartTimes = map[string]time.Time{}
+	}
 	t.queryIteratorMap[queryID] = iter
 	t.pendingQueryResults[queryID] = &PendingQueryResult{}
 	zeroValue := int32(0)
 	t.totalReturnCount[queryID] = &zeroValue
+	t.queryStartTimes[queryID] = time.Now()
 	t.queryMutex.Unlock()
 }
 
@@ -68,6 +79,14 @@ func (t *TransactionContext) GetTotalReturnCount(queryID string) *int32 {
 	return result
 }
 
+// GetQueryStartTime returns the time at which the query was initialized
+func (t *TransactionContext) GetQueryStartTime(queryID string) time.Time {
+	t.queryMutex.Lock()
+	result := t.queryStartTimes[queryID]
+	t.queryMutex.Unlock()
+	return result
+}
+
 func (t *TransactionContext) CleanupQueryContext(queryID string) {
 	t.queryMutex.Lock()
 	defer t.queryMutex.Unlock()
@@ -78,6 +97,7 @@ func (t *TransactionContext) CleanupQueryContext(queryID string) {
 	delete(t.queryIteratorMap, queryID)
 	delete(t.pendingQueryResults, queryID)
 	delete(t.totalReturnCount, queryID)
+	delete(t.queryStartTimes, queryID)
 }
 
 func (t *TransactionContext) CleanupQueryContextWithBookmark(queryID string) string {
@@ -93,6 +113,7 @@ func (t *TransactionContext) CleanupQueryContextWithBookmark(queryID string) str
 	delete(t.queryIteratorMap, queryID)
 	delete(t.pendingQueryResults, queryID)
 	delete(t.totalReturnCount, queryID)
+	delete(t.queryStartTimes, queryID)
 	return bookmark
 }
 
@@ -66,6 +66,7 @@ func (c *TransactionContexts) Create(txParams *ccprovider.TransactionParams) (*T
 		ResponseNotifier:     make(chan *pb.ChaincodeMessage, 1),
 		TXSimulator:          txParams.TXSimulator,
 		HistoryQueryExecutor: txParams.HistoryQueryExecutor,
+		Context:              txParams.Context,
 		queryIteratorMap:     map[string]commonledger.ResultsIterator{},
 		pendingQueryResults:  map[string]*PendingQueryResult{},
 	}
@@ -29,6 +29,8 @@ type GRPCClient struct {
 	maxRecvMsgSize int
 	// Maximum message size the client can send
 	maxSendMsgSize int
+	// Interval at which the host names of the connections are resolved again
+	dnsRefreshInterval time.Duration
 }
 
 // NewGRPCClient creates a new implementation of GRPCClient given an address
@@ -59,6 +61,7 @@ func NewGRPCClient(config ClientConfig) (*GRPCClient, error) {
 	client.dialOpts = append(client.dialOpts, grpc.WithKeepaliveParams(kap),
 		grpc.WithBlock())
 	client.timeout = config.Timeout
+	client.dnsRefreshInterval = config.DNSRefreshInterval
 	// set send/recv message size to package defaults
 	client.maxRecvMsgSize = MaxRecvMsgSize
 	client.maxSendMsgSize = MaxSendMsgSize
@@ -179,6 +182,8 @@ func (client *GRPCClient) NewConnection(address string, serverNameOverride strin
 	dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(
 		grpc.MaxCallRecvMsgSize(client.maxRecvMsgSize),
 		grpc.MaxCallSendMsgSize(client.maxSendMsgSize)))
+	dnsRefresh, watchDNS := WithDNSRefresh(address, client.dnsRefreshInterval)
+	dialOpts = append(dialOpts, dnsRefresh)
 
 	ctx, cancel := context.WithTimeout(context.Background(), client.timeout)
 	defer cancel()
@@ -187,5 +192,6 @@ func (client *GRPCClient) NewConnection(address string, serverNameOverride strin
 		return nil, errors.WithMessage(errors.WithStack(err),
 			"failed to create new connection")
 	}
+	watchDNS(conn)
 	return conn, nil
 }
@@ -70,6 +70,11 @@ type ClientConfig struct {
 	// Timeout specifies how long the client will block when attempting to
 	// establish a connection
 	Timeout time.Duration
+	// DNSRefreshInterval, if not zero, is the interval at which the host
+	// names of the connections are resolved again. A connection is closed
+	// once its host name no longer resolves to the IP address it is
+	// established to
+	DNSRefreshInterval time.Duration
 }
 
 // SecureOptions defines the security parameters (e.g. TLS) for a
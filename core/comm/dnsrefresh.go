@@ -0,0 +1,121 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// lookupHost resolves the IP addresses of a host name
+var lookupHost = net.LookupHost
+
+// WatchDNS resolves the host of the address again every interval, until done
+// is closed. It calls onStale once, and returns, when the host no longer
+// resolves to any of the IP addresses returned by connectedIPs, so that the
+// connections to these addresses can be replaced. The addresses whose host is
+// an IP address, and a zero interval, aren't watched.
+func WatchDNS(address string, interval time.Duration, connectedIPs func() []string, onStale func(), done <-chan struct{}) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil || interval <= 0 || net.ParseIP(host) != nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-done:
+			return
+		}
+
+		connected := connectedIPs()
+		if len(connected) == 0 {
+			continue
+		}
+		resolved, err := lookupHost(host)
+		if err != nil {
+			commLogger.Debugf("Failed resolving %s again: %s", host, err)
+			continue
+		}
+		if !containsAny(resolved, connected) {
+			commLogger.Infof("%s now resolves to %v instead of %v, replacing its connections", host, resolved, connected)
+			onStale()
+			return
+		}
+	}
+}
+
+func containsAny(addresses []string, wanted []string) bool {
+	for _, address := range addresses {
+		for _, w := range wanted {
+			if address == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// remoteIPRecorder dials TCP connections and records the IP address of the
+// last one established
+type remoteIPRecorder struct {
+	lock     sync.RWMutex
+	remoteIP string
+}
+
+func (r *remoteIPRecorder) dial(address string, timeout time.Duration) (net.Conn, error) {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	if tcpAddr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+		r.lock.Lock()
+		r.remoteIP = tcpAddr.IP.String()
+		r.lock.Unlock()
+	}
+	return conn, nil
+}
+
+func (r *remoteIPRecorder) connectedIPs() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if r.remoteIP == "" {
+		return nil
+	}
+	return []string{r.remoteIP}
+}
+
+// WithDNSRefresh returns the dial option of a long-lived gRPC connection to
+// the address whose host name is resolved again every interval, along with the
+// function to invoke with the connection once dialed. The connection is closed
+// once the host name no longer resolves to the IP address it is established
+// to, so that its user dials it again. A zero interval disables the refresh.
+func WithDNSRefresh(address string, interval time.Duration) (grpc.DialOption, func(*grpc.ClientConn)) {
+	if interval <= 0 {
+		return grpc.WithDefaultCallOptions(), func(*grpc.ClientConn) {}
+	}
+
+	recorder := &remoteIPRecorder{}
+	watch := func(cc *grpc.ClientConn) {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for state := cc.GetState(); state != connectivity.Shutdown; state = cc.GetState() {
+				cc.WaitForStateChange(context.Background(), state)
+			}
+		}()
+		go WatchDNS(address, interval, recorder.connectedIPs, func() { cc.Close() }, done)
+	}
+	return grpc.WithDialer(recorder.dial), watch
+}
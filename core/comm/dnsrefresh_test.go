@@ -0,0 +1,147 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// fakeResolver resolves all the host names to the IP addresses it is set to
+type fakeResolver struct {
+	lock sync.Mutex
+	ips  []string
+}
+
+func (r *fakeResolver) set(ips ...string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.ips = ips
+}
+
+func (r *fakeResolver) lookupHost(host string) ([]string, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.ips, nil
+}
+
+func useResolver(r *fakeResolver) func() {
+	lookupHost = r.lookupHost
+	return func() { lookupHost = net.LookupHost }
+}
+
+func TestWatchDNS(t *testing.T) {
+	resolver := &fakeResolver{}
+	resolver.set("10.0.0.1", "10.0.0.2")
+	defer useResolver(resolver)()
+
+	connected := func() []string { return []string{"10.0.0.2"} }
+	stale := make(chan struct{})
+	done := make(chan struct{})
+	watched := make(chan struct{})
+	go func() {
+		WatchDNS("orderer.example.com:7050", 10*time.Millisecond, connected, func() { close(stale) }, done)
+		close(watched)
+	}()
+
+	select {
+	case <-stale:
+		t.Fatal("host still resolves to the connected IP address")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	resolver.set("10.0.0.3")
+	select {
+	case <-stale:
+	case <-time.After(time.Second):
+		t.Fatal("host no longer resolving to the connected IP address wasn't detected")
+	}
+	select {
+	case <-watched:
+	case <-time.After(time.Second):
+		t.Fatal("watch didn't return once stale")
+	}
+}
+
+func TestWatchDNSNotWatched(t *testing.T) {
+	connected := func() []string { return []string{"10.0.0.1"} }
+	onStale := func() { t.Fatal("connection reported stale") }
+	done := make(chan struct{})
+
+	// IP addresses, zero intervals and invalid addresses return right away
+	WatchDNS("10.0.0.1:7050", time.Millisecond, connected, onStale, done)
+	WatchDNS("orderer.example.com:7050", 0, connected, onStale, done)
+	WatchDNS("orderer.example.com", time.Millisecond, connected, onStale, done)
+
+	// and the watch stops once done is closed
+	resolver := &fakeResolver{}
+	resolver.set("10.0.0.1")
+	defer useResolver(resolver)()
+	watched := make(chan struct{})
+	go func() {
+		WatchDNS("orderer.example.com:7050", time.Millisecond, connected, onStale, done)
+		close(watched)
+	}()
+	close(done)
+	select {
+	case <-watched:
+	case <-time.After(time.Second):
+		t.Fatal("watch didn't return once done was closed")
+	}
+}
+
+func TestNewConnectionDNSRefresh(t *testing.T) {
+	srv, err := NewGRPCServer("127.0.0.1:0", ServerConfig{})
+	require.NoError(t, err)
+	go srv.Start()
+	defer srv.Stop()
+	_, port, err := net.SplitHostPort(srv.Address())
+	require.NoError(t, err)
+
+	resolver := &fakeResolver{}
+	resolver.set("127.0.0.1")
+	defer useResolver(resolver)()
+
+	client, err := NewGRPCClient(ClientConfig{
+		Timeout:            time.Second,
+		DNSRefreshInterval: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	conn, err := client.NewConnection(net.JoinHostPort("localhost", port), "")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	time.Sleep(100 * time.Millisecond)
+	assert.NotEqual(t, connectivity.Shutdown, conn.GetState())
+
+	// the connection is closed once its host resolves to another IP address
+	resolver.set("10.0.0.1")
+	deadline := time.Now().Add(time.Second)
+	for conn.GetState() != connectivity.Shutdown && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, connectivity.Shutdown, conn.GetState())
+}
+
+func TestWithDNSRefreshDisabled(t *testing.T) {
+	opt, watch := WithDNSRefresh("orderer.example.com:7050", 0)
+	assert.NotNil(t, opt)
+
+	// the connection stays open as it isn't watched
+	cc, err := grpc.Dial("orderer.example.com:7050", grpc.WithInsecure(), opt)
+	require.NoError(t, err)
+	defer cc.Close()
+	watch(cc)
+	assert.NotEqual(t, connectivity.Shutdown, cc.GetState())
+}
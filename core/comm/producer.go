@@ -9,6 +9,7 @@ package comm
 import (
 	"fmt"
 	"math/rand"
+	"sort"
 	"sync"
 	"time"
 
@@ -20,6 +21,45 @@ var logger = flogging.MustGetLogger("ConnProducer")
 
 var EndpointDisableInterval = time.Second * 10
 
+// EndpointDisableMaxInterval is the maximum amount of time an endpoint
+// that is repeatedly disabled is kept disabled
+var EndpointDisableMaxInterval = time.Minute * 5
+
+// EndpointSelectionPolicy dictates the order in which a ConnectionProducer
+// attempts to connect to its endpoints
+type EndpointSelectionPolicy string
+
+const (
+	// RandomSelection attempts the endpoints in a random order
+	RandomSelection EndpointSelectionPolicy = "random"
+	// RoundRobinSelection attempts the endpoints in a rotating order,
+	// starting from the endpoint following the one last connected to
+	RoundRobinSelection EndpointSelectionPolicy = "roundrobin"
+	// StickySelection attempts the endpoint last connected to first,
+	// and fails over to the rest of the endpoints in their configured order
+	StickySelection EndpointSelectionPolicy = "sticky"
+	// LatencySelection attempts the endpoints in ascending order of
+	// their observed connection establishment latency
+	LatencySelection EndpointSelectionPolicy = "latency"
+)
+
+// latencySmoothingFactor is the weight given to the latest latency sample
+// in the exponentially weighted moving average of an endpoint's latency
+const latencySmoothingFactor = 0.3
+
+// ParseEndpointSelectionPolicy returns the EndpointSelectionPolicy
+// corresponding to the given name, or an error if no such policy exists
+func ParseEndpointSelectionPolicy(name string) (EndpointSelectionPolicy, error) {
+	switch policy := EndpointSelectionPolicy(name); policy {
+	case RandomSelection, RoundRobinSelection, StickySelection, LatencySelection:
+		return policy, nil
+	case "":
+		return RandomSelection, nil
+	default:
+		return "", fmt.Errorf("unknown endpoint selection policy: %s", name)
+	}
+}
+
 // ConnectionFactory creates a connection to a certain endpoint
 type ConnectionFactory func(endpoint string) (*grpc.ClientConn, error)
 
@@ -39,20 +79,46 @@ type ConnectionProducer interface {
 	GetEndpoints() []string
 }
 
+// endpointState holds the backoff and latency bookkeeping of an endpoint
+type endpointState struct {
+	// disabledAt is the time the endpoint was last disabled
+	disabledAt time.Time
+	// disablements is the number of consecutive times the endpoint was disabled
+	disablements uint
+	// latency is the smoothed connection establishment latency of the endpoint
+	latency time.Duration
+}
+
 type connProducer struct {
 	sync.RWMutex
 	endpoints         []string
 	disabledEndpoints map[string]time.Time
+	states            map[string]*endpointState
 	connect           ConnectionFactory
+	policy            EndpointSelectionPolicy
+	lastEndpoint      string
 }
 
 // NewConnectionProducer creates a new ConnectionProducer with given endpoints and connection factory.
 // It returns nil, if the given endpoints slice is empty.
 func NewConnectionProducer(factory ConnectionFactory, endpoints []string) ConnectionProducer {
+	return NewConnectionProducerWithPolicy(factory, endpoints, RandomSelection)
+}
+
+// NewConnectionProducerWithPolicy creates a new ConnectionProducer with given endpoints and connection factory,
+// that selects endpoints according to the given EndpointSelectionPolicy.
+// It returns nil, if the given endpoints slice is empty.
+func NewConnectionProducerWithPolicy(factory ConnectionFactory, endpoints []string, policy EndpointSelectionPolicy) ConnectionProducer {
 	if len(endpoints) == 0 {
 		return nil
 	}
-	return &connProducer{endpoints: endpoints, connect: factory, disabledEndpoints: make(map[string]time.Time)}
+	return &connProducer{
+		endpoints:         endpoints,
+		connect:           factory,
+		disabledEndpoints: make(map[string]time.Time),
+		states:            make(map[string]*endpointState),
+		policy:            policy,
+	}
 }
 
 // NewConnection creates a new connection.
@@ -63,27 +129,98 @@ func (cp *connProducer) NewConnection() (*grpc.ClientConn, string, error) {
 	defer cp.Unlock()
 
 	for endpoint, timeout := range cp.disabledEndpoints {
-		if time.Since(timeout) >= EndpointDisableInterval {
+		if time.Since(timeout) >= cp.disableInterval(endpoint) {
 			delete(cp.disabledEndpoints, endpoint)
 		}
 	}
 
-	endpoints := shuffle(cp.endpoints)
+	endpoints := cp.orderEndpoints()
 	checkedEndpoints := make([]string, 0)
 	for _, endpoint := range endpoints {
 		if _, ok := cp.disabledEndpoints[endpoint]; !ok {
 			checkedEndpoints = append(checkedEndpoints, endpoint)
+			start := time.Now()
 			conn, err := cp.connect(endpoint)
 			if err != nil {
 				logger.Error("Failed connecting to", endpoint, ", error:", err)
 				continue
 			}
+			cp.recordLatency(endpoint, time.Since(start))
+			cp.lastEndpoint = endpoint
 			return conn, endpoint, nil
 		}
 	}
 	return nil, "", fmt.Errorf("Could not connect to any of the endpoints: %v", checkedEndpoints)
 }
 
+// orderEndpoints returns the endpoints in the order they should be attempted,
+// according to the selection policy of the ConnectionProducer
+func (cp *connProducer) orderEndpoints() []string {
+	switch cp.policy {
+	case RoundRobinSelection:
+		return rotate(cp.endpoints, indexOf(cp.endpoints, cp.lastEndpoint)+1)
+	case StickySelection:
+		i := indexOf(cp.endpoints, cp.lastEndpoint)
+		if i == -1 {
+			return rotate(cp.endpoints, 0)
+		}
+		sticky := []string{cp.lastEndpoint}
+		sticky = append(sticky, cp.endpoints[:i]...)
+		return append(sticky, cp.endpoints[i+1:]...)
+	case LatencySelection:
+		// Endpoints with no latency samples are attempted last, in a random order
+		endpoints := shuffle(cp.endpoints)
+		latency := func(endpoint string) time.Duration {
+			if state, exists := cp.states[endpoint]; exists && state.latency > 0 {
+				return state.latency
+			}
+			return time.Duration(1<<63 - 1)
+		}
+		sort.SliceStable(endpoints, func(i, j int) bool {
+			return latency(endpoints[i]) < latency(endpoints[j])
+		})
+		return endpoints
+	default:
+		return shuffle(cp.endpoints)
+	}
+}
+
+// recordLatency updates the smoothed connection latency of the given endpoint
+func (cp *connProducer) recordLatency(endpoint string, latency time.Duration) {
+	state := cp.stateOf(endpoint)
+	if state.latency == 0 {
+		state.latency = latency
+		return
+	}
+	state.latency = time.Duration(latencySmoothingFactor*float64(latency) + (1-latencySmoothingFactor)*float64(state.latency))
+}
+
+// disableInterval returns the amount of time the given endpoint should be disabled for,
+// which doubles with each consecutive disablement of the endpoint.
+func (cp *connProducer) disableInterval(endpoint string) time.Duration {
+	state, exists := cp.states[endpoint]
+	if !exists || state.disablements <= 1 {
+		return EndpointDisableInterval
+	}
+	interval := EndpointDisableInterval
+	for i := uint(1); i < state.disablements; i++ {
+		interval *= 2
+		if interval >= EndpointDisableMaxInterval {
+			return EndpointDisableMaxInterval
+		}
+	}
+	return interval
+}
+
+func (cp *connProducer) stateOf(endpoint string) *endpointState {
+	state, exists := cp.states[endpoint]
+	if !exists {
+		state = &endpointState{}
+		cp.states[endpoint] = state
+	}
+	return state
+}
+
 // UpdateEndpoints updates the endpoints of the ConnectionProducer
 // to be the given endpoints
 func (cp *connProducer) UpdateEndpoints(endpoints []string) {
@@ -95,13 +232,18 @@ func (cp *connProducer) UpdateEndpoints(endpoints []string) {
 	defer cp.Unlock()
 
 	newDisabled := make(map[string]time.Time)
+	newStates := make(map[string]*endpointState)
 	for i := range endpoints {
 		if startTime, ok := cp.disabledEndpoints[endpoints[i]]; ok {
 			newDisabled[endpoints[i]] = startTime
 		}
+		if state, ok := cp.states[endpoints[i]]; ok {
+			newStates[endpoints[i]] = state
+		}
 	}
 	cp.endpoints = endpoints
 	cp.disabledEndpoints = newDisabled
+	cp.states = newStates
 }
 
 func (cp *connProducer) DisableEndpoint(endpoint string) {
@@ -116,11 +258,24 @@ func (cp *connProducer) DisableEndpoint(endpoint string) {
 	for _, currEndpoint := range cp.endpoints {
 		if currEndpoint == endpoint {
 			cp.disabledEndpoints[endpoint] = time.Now()
+			cp.recordDisablement(endpoint)
 			break
 		}
 	}
 }
 
+// recordDisablement updates the backoff state of the given endpoint.
+// An endpoint that is disabled again while its previous disablement is still
+// fresh is considered to be repeatedly failing, and is disabled for longer.
+func (cp *connProducer) recordDisablement(endpoint string) {
+	state := cp.stateOf(endpoint)
+	if state.disablements > 0 && time.Since(state.disabledAt) > 2*cp.disableInterval(endpoint) {
+		state.disablements = 0
+	}
+	state.disablements++
+	state.disabledAt = time.Now()
+}
+
 func shuffle(a []string) []string {
 	n := len(a)
 	returnedSlice := make([]string, n)
@@ -132,6 +287,24 @@ func shuffle(a []string) []string {
 	return returnedSlice
 }
 
+func rotate(a []string, start int) []string {
+	n := len(a)
+	returnedSlice := make([]string, n)
+	for i := range a {
+		returnedSlice[i] = a[(start+i)%n]
+	}
+	return returnedSlice
+}
+
+func indexOf(a []string, s string) int {
+	for i := range a {
+		if a[i] == s {
+			return i
+		}
+	}
+	return -1
+}
+
 // GetEndpoints returns configured endpoints for ordering service
 func (cp *connProducer) GetEndpoints() []string {
 	cp.RLock()
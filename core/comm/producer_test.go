@@ -8,6 +8,7 @@ package comm
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -132,3 +133,122 @@ func TestDisableEndpoint(t *testing.T) {
 	assert.Equal(t, "b", a)
 
 }
+
+func TestParseEndpointSelectionPolicy(t *testing.T) {
+	for _, name := range []string{"random", "roundrobin", "sticky", "latency"} {
+		policy, err := ParseEndpointSelectionPolicy(name)
+		assert.NoError(t, err)
+		assert.Equal(t, EndpointSelectionPolicy(name), policy)
+	}
+	policy, err := ParseEndpointSelectionPolicy("")
+	assert.NoError(t, err)
+	assert.Equal(t, RandomSelection, policy)
+	_, err = ParseEndpointSelectionPolicy("fastest")
+	assert.EqualError(t, err, "unknown endpoint selection policy: fastest")
+}
+
+func TestRoundRobinSelection(t *testing.T) {
+	t.Parallel()
+	connFactory := func(endpoint string) (*grpc.ClientConn, error) {
+		return &grpc.ClientConn{}, nil
+	}
+	producer := NewConnectionProducerWithPolicy(connFactory, []string{"a", "b", "c"}, RoundRobinSelection)
+	var selected []string
+	for i := 0; i < 4; i++ {
+		_, endpoint, err := producer.NewConnection()
+		assert.NoError(t, err)
+		selected = append(selected, endpoint)
+	}
+	assert.Equal(t, []string{"a", "b", "c", "a"}, selected)
+}
+
+func TestStickySelection(t *testing.T) {
+	t.Parallel()
+	shouldConnFail := map[string]bool{}
+	var lock sync.Mutex
+	connFactory := func(endpoint string) (*grpc.ClientConn, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		if shouldConnFail[endpoint] {
+			return nil, fmt.Errorf("Failed connecting to %s", endpoint)
+		}
+		return &grpc.ClientConn{}, nil
+	}
+	producer := NewConnectionProducerWithPolicy(connFactory, []string{"a", "b", "c"}, StickySelection)
+	for i := 0; i < 3; i++ {
+		_, endpoint, err := producer.NewConnection()
+		assert.NoError(t, err)
+		assert.Equal(t, "a", endpoint)
+	}
+	// Fail over to the next endpoint, and stick to it even after 'a' recovers
+	lock.Lock()
+	shouldConnFail["a"] = true
+	lock.Unlock()
+	_, endpoint, err := producer.NewConnection()
+	assert.NoError(t, err)
+	assert.Equal(t, "b", endpoint)
+	lock.Lock()
+	shouldConnFail["a"] = false
+	lock.Unlock()
+	_, endpoint, err = producer.NewConnection()
+	assert.NoError(t, err)
+	assert.Equal(t, "b", endpoint)
+}
+
+func TestLatencySelection(t *testing.T) {
+	t.Parallel()
+	delays := map[string]time.Duration{
+		"a": time.Millisecond * 30,
+		"b": time.Millisecond,
+		"c": time.Millisecond * 15,
+	}
+	connFactory := func(endpoint string) (*grpc.ClientConn, error) {
+		time.Sleep(delays[endpoint])
+		return &grpc.ClientConn{}, nil
+	}
+	producer := NewConnectionProducerWithPolicy(connFactory, []string{"a", "b", "c"}, LatencySelection).(*connProducer)
+	// Sample the latency of all endpoints
+	for _, endpoint := range []string{"a", "b", "c"} {
+		producer.recordLatency(endpoint, delays[endpoint])
+	}
+	for i := 0; i < 3; i++ {
+		_, endpoint, err := producer.NewConnection()
+		assert.NoError(t, err)
+		assert.Equal(t, "b", endpoint)
+	}
+	assert.Equal(t, []string{"b", "c", "a"}, producer.orderEndpoints())
+}
+
+func TestDisableEndpointBackoff(t *testing.T) {
+	orgEndpointDisableInterval := EndpointDisableInterval
+	orgEndpointDisableMaxInterval := EndpointDisableMaxInterval
+	EndpointDisableInterval = time.Second
+	EndpointDisableMaxInterval = time.Second * 3
+	defer func() {
+		EndpointDisableInterval = orgEndpointDisableInterval
+		EndpointDisableMaxInterval = orgEndpointDisableMaxInterval
+	}()
+
+	connFactory := func(endpoint string) (*grpc.ClientConn, error) {
+		return &grpc.ClientConn{}, nil
+	}
+	producer := NewConnectionProducer(connFactory, []string{"a", "b", "c"}).(*connProducer)
+	assert.Equal(t, time.Second, producer.disableInterval("a"))
+	producer.DisableEndpoint("a")
+	assert.Equal(t, time.Second, producer.disableInterval("a"))
+	// Consecutive disablements double the interval, up to the maximum
+	producer.DisableEndpoint("a")
+	assert.Equal(t, time.Second*2, producer.disableInterval("a"))
+	producer.DisableEndpoint("a")
+	assert.Equal(t, time.Second*3, producer.disableInterval("a"))
+	// Other endpoints are unaffected
+	assert.Equal(t, time.Second, producer.disableInterval("b"))
+	// A disablement long after the previous one restarts the backoff
+	producer.states["a"].disabledAt = time.Now().Add(-time.Minute)
+	producer.DisableEndpoint("a")
+	assert.Equal(t, time.Second, producer.disableInterval("a"))
+	// Removed endpoints lose their backoff state
+	producer.UpdateEndpoints([]string{"b", "c", "d"})
+	_, exists := producer.states["a"]
+	assert.False(t, exists)
+}
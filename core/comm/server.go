@@ -14,6 +14,7 @@ import (
 	"net"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/grpc-ecosystem/go-grpc-middleware"
 	"google.golang.org/grpc"
@@ -202,6 +203,26 @@ func (gServer *GRPCServer) Stop() {
 	gServer.server.Stop()
 }
 
+// GracefulStop stops the underlying grpc.Server from accepting new
+// connections and waits for the pending RPCs to finish, for at most the given
+// timeout after which the remaining ones are cancelled. It returns whether all
+// the RPCs finished in time
+func (gServer *GRPCServer) GracefulStop(timeout time.Duration) bool {
+	stopped := make(chan struct{})
+	go func() {
+		gServer.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return true
+	case <-time.After(timeout):
+		gServer.server.Stop()
+		<-stopped
+		return false
+	}
+}
+
 // AppendClientRootCAs appends PEM-encoded X509 certificate authorities to
 // the list of authorities used to verify client certificates
 func (gServer *GRPCServer) AppendClientRootCAs(clientRoots [][]byte) error {
@@ -27,6 +27,7 @@ import (
 	"github.com/hyperledger/fabric/core/comm"
 	testpb "github.com/hyperledger/fabric/core/comm/testdata/grpc"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
@@ -1800,3 +1801,36 @@ func TestServerInterceptors(t *testing.T) {
 	assert.Equal(t, grpc.ErrorDesc(err), msg, "Expected error from second ssi")
 	assert.Equal(t, uint32(2), atomic.LoadUint32(&ssiCount), "Expected both ssi handlers to be invoked")
 }
+
+func TestGracefulStop(t *testing.T) {
+	t.Parallel()
+	newServer := func() *comm.GRPCServer {
+		lis, err := net.Listen("tcp", "localhost:0")
+		require.NoError(t, err)
+		srv, err := comm.NewGRPCServerFromListener(lis, comm.ServerConfig{SecOpts: &comm.SecureOptions{UseTLS: false}})
+		require.NoError(t, err)
+		testpb.RegisterEmptyServiceServer(srv.Server(), &emptyServiceServer{})
+		go srv.Start()
+		return srv
+	}
+
+	// no RPC is pending
+	srv := newServer()
+	_, err := invokeEmptyCall(srv.Address(), []grpc.DialOption{grpc.WithInsecure()})
+	require.NoError(t, err)
+	assert.True(t, srv.GracefulStop(time.Minute))
+
+	// the stream left open is cancelled once the timeout expires
+	srv = newServer()
+	clientConn, err := grpc.Dial(srv.Address(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer clientConn.Close()
+	stream, err := testpb.NewEmptyServiceClient(clientConn).EmptyStream(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&testpb.Empty{}))
+	_, err = stream.Recv()
+	require.NoError(t, err)
+	assert.False(t, srv.GracefulStop(100*time.Millisecond))
+	_, err = stream.Recv()
+	assert.Error(t, err)
+}
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/fabric/common/configtx/test"
 	"github.com/hyperledger/fabric/common/ledger"
@@ -80,6 +81,21 @@ func (m *mockLedger) GetTxValidationCodeByTxID(txID string) (peer.TxValidationCo
 	return args.Get(0).(peer.TxValidationCode), args.Error(1)
 }
 
+func (m *mockLedger) GetTransactionsByCreator(creator []byte, startBlockNum uint64, limit int) ([]*ledger2.IndexedTransaction, error) {
+	args := m.Called(creator, startBlockNum, limit)
+	return args.Get(0).([]*ledger2.IndexedTransaction), args.Error(1)
+}
+
+func (m *mockLedger) GetBlocksByTimeRange(start, end time.Time, limit int) ([]*common.Block, error) {
+	args := m.Called(start, end, limit)
+	return args.Get(0).([]*common.Block), args.Error(1)
+}
+
+func (m *mockLedger) GetBlockNumByDedupKey(namespace, dedupKey string) (uint64, error) {
+	args := m.Called(namespace, dedupKey)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
 func (m *mockLedger) NewTxSimulator(txid string) (ledger2.TxSimulator, error) {
 	args := m.Called(txid)
 	return args.Get(0).(ledger2.TxSimulator), args.Error(1)
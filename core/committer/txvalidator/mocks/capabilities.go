@@ -22,6 +22,20 @@ func (_m *Capabilities) ACLs() bool {
 	return r0
 }
 
+// ChaincodeMigration provides a mock function with given fields:
+func (_m *Capabilities) ChaincodeMigration() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // CollectionUpgrade provides a mock function with given fields:
 func (_m *Capabilities) CollectionUpgrade() bool {
 	ret := _m.Called()
@@ -36,6 +50,20 @@ func (_m *Capabilities) CollectionUpgrade() bool {
 	return r0
 }
 
+// EphemeralCollections provides a mock function with given fields:
+func (_m *Capabilities) EphemeralCollections() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // ForbidDuplicateTXIdInBlock provides a mock function with given fields:
 func (_m *Capabilities) ForbidDuplicateTXIdInBlock() bool {
 	ret := _m.Called()
@@ -147,3 +175,17 @@ func (_m *Capabilities) V1_3Validation() bool {
 
 	return r0
 }
+
+// ValueCommitments provides a mock function with given fields:
+func (_m *Capabilities) ValueCommitments() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/hyperledger/fabric/common/cauthdsl"
 	ledger2 "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/core/handlers/validation/api"
 	. "github.com/hyperledger/fabric/core/handlers/validation/api/capabilities"
 	. "github.com/hyperledger/fabric/core/handlers/validation/api/identities"
@@ -185,7 +186,7 @@ func (id *PolicyEvaluator) Evaluate(policyBytes []byte, signatureSet []*common.S
 	if err != nil {
 		return err
 	}
-	return policy.Evaluate(signatureSet)
+	return policies.Evaluate(policy, signatureSet)
 }
 
 // DeserializeIdentity unmarshals the given identity to msp.Identity
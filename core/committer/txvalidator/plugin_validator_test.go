@@ -14,6 +14,7 @@ import (
 	"github.com/hyperledger/fabric/common/cauthdsl"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/mocks/ledger"
+	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/core/committer/txvalidator"
 	"github.com/hyperledger/fabric/core/committer/txvalidator/mocks"
 	"github.com/hyperledger/fabric/core/committer/txvalidator/testdata"
@@ -22,6 +23,7 @@ import (
 	"github.com/hyperledger/fabric/msp"
 	. "github.com/hyperledger/fabric/msp/mocks"
 	"github.com/hyperledger/fabric/protos/common"
+	protoutils "github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -118,6 +120,15 @@ func TestSamplePlugin(t *testing.T) {
 	assert.NoError(t, v.ValidateWithPlugin(ctx))
 }
 
+func TestPolicyEvaluatorExplainsFailures(t *testing.T) {
+	pe := &txvalidator.PolicyEvaluator{IdentityDeserializer: &mocks.IdentityDeserializer{}}
+	policy := protoutils.MarshalOrPanic(cauthdsl.SignedByMspMember("SampleOrg"))
+
+	err := pe.Evaluate(policy, nil)
+	assert.IsType(t, &policies.EvaluationError{}, err)
+	assert.Contains(t, err.Error(), "signature policy: not satisfied")
+}
+
 func TestCapabilitiesInterface(t *testing.T) {
 	// Make sure that the application capabilities are all implemented by the validation capabilities
 	// Obtain all methods of the ApplicationCapabilities and ensure
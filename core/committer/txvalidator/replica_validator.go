@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator
+
+import (
+	"github.com/hyperledger/fabric/common/configtx"
+	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// ReplicaValidator validates the blocks of a read replica, which are delivered
+// by its committer along with the validation codes of their transactions. It
+// keeps the validation codes of the committer, and only applies the valid config
+// transactions to the config of the channel.
+type ReplicaValidator struct {
+	ChainID string
+	Support Support
+}
+
+// NewReplicaValidator creates a validator of the blocks of a read replica
+func NewReplicaValidator(chainID string, support Support) *ReplicaValidator {
+	return &ReplicaValidator{ChainID: chainID, Support: support}
+}
+
+// Validate applies the valid config transactions of the block, and returns an
+// error if the block wasn't validated by the committer
+func (v *ReplicaValidator) Validate(block *common.Block) error {
+	txsFilter := ledgerUtil.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	if len(txsFilter) != len(block.Data.Data) {
+		return errors.Errorf("block [%d] of channel %s was not validated by the committer, it has %d validation codes for %d transactions",
+			block.Header.Number, v.ChainID, len(txsFilter), len(block.Data.Data))
+	}
+	for tIdx, d := range block.Data.Data {
+		if !txsFilter.IsValid(tIdx) {
+			continue
+		}
+		env, err := utils.GetEnvelopeFromBlock(d)
+		if err != nil {
+			return errors.WithMessage(err, "error extracting a transaction validated by the committer")
+		}
+		payload, err := utils.GetPayload(env)
+		if err != nil {
+			return errors.WithMessage(err, "error extracting a transaction validated by the committer")
+		}
+		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			return errors.WithMessage(err, "error extracting a transaction validated by the committer")
+		}
+		if common.HeaderType(chdr.Type) != common.HeaderType_CONFIG {
+			continue
+		}
+		configEnvelope, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+		if err != nil {
+			return errors.WithMessage(err, "error unmarshalling config validated by the committer")
+		}
+		if err := v.Support.Apply(configEnvelope); err != nil {
+			return errors.WithMessage(err, "error applying config validated by the committer")
+		}
+		logger.Debugf("config transaction received for chain %s", v.ChainID)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/committer/txvalidator"
+	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
+	mocktxvalidator "github.com/hyperledger/fabric/core/mocks/txvalidator"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sync/semaphore"
+)
+
+func TestReplicaValidator(t *testing.T) {
+	support := &mocktxvalidator.Support{ApplyVal: errors.New("bad config")}
+	vcs := struct {
+		*mocktxvalidator.Support
+		*semaphore.Weighted
+	}{support, semaphore.NewWeighted(10)}
+	v := txvalidator.NewReplicaValidator("testchain", vcs)
+
+	configTx, err := utils.CreateSignedEnvelope(common.HeaderType_CONFIG, "testchain", nil, &common.ConfigEnvelope{}, 0, 0)
+	assert.NoError(t, err)
+	block := &common.Block{
+		Header:   &common.BlockHeader{Number: 3},
+		Data:     &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(configTx)}},
+		Metadata: &common.BlockMetadata{Metadata: [][]byte{{}, {}, {}, {}}},
+	}
+
+	// the blocks must carry the validation codes of the committer
+	assert.EqualError(t, v.Validate(block), "block [3] of channel testchain was not validated by the committer, it has 0 validation codes for 1 transactions")
+
+	// the invalid config transactions are not applied
+	txsFilter := ledgerUtil.NewTxValidationFlagsSetValue(1, peer.TxValidationCode_BAD_CHANNEL_HEADER)
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsFilter
+	assert.NoError(t, v.Validate(block))
+
+	txsFilter.SetFlag(0, peer.TxValidationCode_VALID)
+	assert.EqualError(t, v.Validate(block), "error applying config validated by the committer: bad config")
+	support.ApplyVal = nil
+	assert.NoError(t, v.Validate(block))
+	// the validation codes are left untouched
+	assert.Equal(t, []byte(txsFilter), block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+}
@@ -14,13 +14,16 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/crypto"
 	commonerrors "github.com/hyperledger/fabric/common/errors"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/core/chaincode/platforms"
 	"github.com/hyperledger/fabric/core/chaincode/platforms/golang"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
 	"github.com/hyperledger/fabric/core/common/validation"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/customtx"
 	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/protos/common"
@@ -53,6 +56,16 @@ type Support interface {
 
 	// Capabilities defines the capabilities for the application portion of this channel
 	Capabilities() channelconfig.ApplicationCapabilities
+
+	// ChannelConfig returns the channel portion of the config of this channel
+	ChannelConfig() channelconfig.Channel
+
+	// PolicyManager returns the policy manager of this channel
+	PolicyManager() policies.Manager
+
+	// TxDedupWindow returns the number of blocks within which the transactions carrying
+	// the same dedup key for the same chaincode are duplicates, 0 if they are not deduplicated
+	TxDedupWindow() uint64
 }
 
 //Validator interface which defines API to validate block transactions
@@ -81,9 +94,11 @@ type TxValidator struct {
 var logger = flogging.MustGetLogger("committer/txvalidator")
 
 type blockValidationRequest struct {
-	block *common.Block
-	d     []byte
-	tIdx  int
+	block       *common.Block
+	d           []byte
+	tIdx        int
+	dedupWindow uint64
+	blockTime   time.Time
 }
 
 type blockValidationResult struct {
@@ -95,6 +110,12 @@ type blockValidationResult struct {
 	txid                 string
 }
 
+// txDedupKey is a dedup key carried by a transaction, which is scoped to its chaincode
+type txDedupKey struct {
+	namespace string
+	key       string
+}
+
 // NewTxValidator creates new transactions validator
 func NewTxValidator(chainID string, support Support, sccp sysccprovider.SystemChaincodeProvider, pm PluginMapper) *TxValidator {
 	// Encapsulates interface implementation
@@ -145,6 +166,11 @@ func (v *TxValidator) Validate(block *common.Block) error {
 	txsUpgradedChaincodes := make(map[int]*sysccprovider.ChaincodeInstance)
 	// array of txids
 	txidArray := make([]string, len(block.Data.Data))
+	dedupWindow := v.Support.TxDedupWindow()
+	var blockTime time.Time
+	if v.Support.ChannelConfig().Capabilities().EndorsementTimeExpiration() {
+		blockTime = getBlockTime(block)
+	}
 
 	results := make(chan *blockValidationResult)
 	go func() {
@@ -156,9 +182,11 @@ func (v *TxValidator) Validate(block *common.Block) error {
 				defer v.Support.Release(1)
 
 				v.validateTx(&blockValidationRequest{
-					d:     data,
-					block: block,
-					tIdx:  index,
+					d:           data,
+					block:       block,
+					tIdx:        index,
+					dedupWindow: dedupWindow,
+					blockTime:   blockTime,
 				}, results)
 			}(tIdx, d)
 		}
@@ -264,6 +292,155 @@ func markTXIdDuplicates(txids []string, txsfltr ledgerUtil.TxValidationFlags) {
 	}
 }
 
+// checkDedupKey returns the dedup key of the given endorser transaction, if it carries one, and
+// whether a valid transaction with the same key was committed within the dedup window of the block.
+// The transactions of the block carrying the same dedup key are detected by the ledger, once the
+// read-sets are validated, so that only the first of them which is valid gets committed
+func (v *TxValidator) checkDedupKey(hdr *common.Header, blockNum uint64, dedupWindow uint64) (*txDedupKey, bool, error) {
+	ext, err := utils.GetChaincodeHeaderExtension(hdr)
+	if err != nil {
+		return nil, false, err
+	}
+	if ext.DedupKey == "" || ext.ChaincodeId == nil {
+		return nil, false, nil
+	}
+	dedupKey := &txDedupKey{namespace: ext.ChaincodeId.Name, key: ext.DedupKey}
+
+	committedBlockNum, err := v.Support.Ledger().GetBlockNumByDedupKey(dedupKey.namespace, dedupKey.key)
+	if _, isNotFoundInIndexErrType := err.(ledger.NotFoundInIndexErr); isNotFoundInIndexErrType {
+		return dedupKey, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return dedupKey, blockNum-committedBlockNum <= dedupWindow, nil
+}
+
+// getBlockTime returns the time of the block, which is the latest timestamp of its endorser
+// transactions, or the zero time if none has one. The blocks carry no time of their own, yet
+// the orderer only admits the transactions whose timestamp is within the endorsement max skew
+// of its clock, hence the time of the block is as well, and all the peers agree on it.
+func getBlockTime(block *common.Block) time.Time {
+	var blockTime time.Time
+	for _, d := range block.Data.Data {
+		env, err := utils.GetEnvelopeFromBlock(d)
+		if err != nil {
+			continue
+		}
+		payload, err := utils.GetPayload(env)
+		if err != nil || payload.Header == nil {
+			continue
+		}
+		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil || common.HeaderType(chdr.Type) != common.HeaderType_ENDORSER_TRANSACTION || chdr.Timestamp == nil {
+			continue
+		}
+		if timestamp := time.Unix(chdr.Timestamp.Seconds, int64(chdr.Timestamp.Nanos)); timestamp.After(blockTime) {
+			blockTime = timestamp
+		}
+	}
+	return blockTime
+}
+
+// checkTimestamp returns an error if the timestamp of the given transaction is older than the
+// time of its block by more than twice the endorsement max skew: both the transaction and the
+// latest one of the block were within the max skew of the clock of the orderer when it ordered
+// them, so the peers need not trust the timestamp set by the client beyond that.
+func checkTimestamp(chdr *common.ChannelHeader, blockTime time.Time, maxSkew time.Duration) error {
+	if chdr.Timestamp == nil {
+		return errors.New("transaction has no timestamp")
+	}
+	timestamp := time.Unix(chdr.Timestamp.Seconds, int64(chdr.Timestamp.Nanos))
+	if timestamp.Before(blockTime.Add(-2 * maxSkew)) {
+		return errors.Errorf("timestamp %s is older than the time of the block %s by more than twice the endorsement max skew %s", timestamp, blockTime, maxSkew)
+	}
+	return nil
+}
+
+// checkExpiration returns an error if the creator or an endorser of the given endorser
+// transaction had expired at the timestamp of the transaction, when it was endorsed. As the
+// timestamp is recorded in the block, all the peers agree on the validity of the transaction
+// however late they commit the block, unlike when checking against their clock, and the
+// timestamp is bounded by the time of the block (see checkTimestamp).
+func checkExpiration(payload *common.Payload, chdr *common.ChannelHeader) error {
+	if chdr.Timestamp == nil {
+		return errors.New("transaction has no timestamp to check the expiration of its identities against")
+	}
+	endorsedAt := time.Unix(chdr.Timestamp.Seconds, int64(chdr.Timestamp.Nanos))
+	expiredAt := func(identity []byte) bool {
+		expiration := crypto.ExpiresAt(identity)
+		return !expiration.IsZero() && expiration.Before(endorsedAt)
+	}
+
+	shdr, err := utils.GetSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		return err
+	}
+	if expiredAt(shdr.Creator) {
+		return errors.Errorf("creator had expired by %s", endorsedAt)
+	}
+
+	tx, err := utils.GetTransaction(payload.Data)
+	if err != nil {
+		return err
+	}
+	for _, action := range tx.Actions {
+		cap, err := utils.GetChaincodeActionPayload(action.Payload)
+		if err != nil {
+			return err
+		}
+		if cap.Action == nil {
+			continue
+		}
+		for i, endorsement := range cap.Action.Endorsements {
+			if expiredAt(endorsement.Endorser) {
+				return errors.Errorf("endorser %d had expired by %s", i, endorsedAt)
+			}
+		}
+	}
+	return nil
+}
+
+// checkDuplicateTxID returns the result of the validation of the transaction
+// if a transaction with the same ID is already in the ledger, or if that
+// cannot be verified, nil otherwise
+func (v *TxValidator) checkDuplicateTxID(tIdx int, txID string) *blockValidationResult {
+	// GetTransactionByID will return:
+	_, err := v.Support.Ledger().GetTransactionByID(txID)
+	// 1) err == nil => there is already a tx in the ledger with the supplied id
+	if err == nil {
+		logger.Error("Duplicate transaction found, ", txID, ", skipping")
+		return &blockValidationResult{
+			tIdx:           tIdx,
+			validationCode: peer.TxValidationCode_DUPLICATE_TXID,
+		}
+	}
+	// 2) err is not of type blkstorage.NotFoundInIndexErr => we could not verify whether a tx with the supplied id is in the ledger
+	if _, isNotFoundInIndexErrType := err.(ledger.NotFoundInIndexErr); !isNotFoundInIndexErrType {
+		logger.Errorf("Ledger failure while attempting to detect duplicate status for txid %s, err '%s'. Aborting", txID, err)
+		return &blockValidationResult{
+			tIdx: tIdx,
+			err:  err,
+		}
+	}
+	// 3) err is of type blkstorage.NotFoundInIndexErr => there is no tx with the supplied id in the ledger
+	return nil
+}
+
+// checkWriters evaluates the Writers policy of the application of the channel
+// against the signature of the creator of the transaction
+func (v *TxValidator) checkWriters(env *common.Envelope) error {
+	policy, ok := v.Support.PolicyManager().GetPolicy(policies.ChannelApplicationWriters)
+	if !ok {
+		return errors.Errorf("could not find policy %s", policies.ChannelApplicationWriters)
+	}
+	signedData, err := env.AsSignedData()
+	if err != nil {
+		return err
+	}
+	return errors.WithMessage(policy.Evaluate(signedData), "creator does not satisfy the writers policy of the channel")
+}
+
 func (v *TxValidator) validateTx(req *blockValidationRequest, results chan<- *blockValidationResult) {
 	block := req.block
 	d := req.d
@@ -332,27 +509,51 @@ func (v *TxValidator) validateTx(req *blockValidationRequest, results chan<- *bl
 		if common.HeaderType(chdr.Type) == common.HeaderType_ENDORSER_TRANSACTION {
 			// Check duplicate transactions
 			txID = chdr.TxId
-			// GetTransactionByID will return:
-			_, err := v.Support.Ledger().GetTransactionByID(txID)
-			// 1) err == nil => there is already a tx in the ledger with the supplied id
-			if err == nil {
-				logger.Error("Duplicate transaction found, ", txID, ", skipping")
-				results <- &blockValidationResult{
-					tIdx:           tIdx,
-					validationCode: peer.TxValidationCode_DUPLICATE_TXID,
-				}
+			if res := v.checkDuplicateTxID(tIdx, txID); res != nil {
+				results <- res
 				return
 			}
-			// 2) err is not of type blkstorage.NotFoundInIndexErr => we could not verify whether a tx with the supplied id is in the ledger
-			if _, isNotFoundInIndexErrType := err.(ledger.NotFoundInIndexErr); !isNotFoundInIndexErrType {
-				logger.Errorf("Ledger failure while attempting to detect duplicate status for txid %s, err '%s'. Aborting", txID, err)
-				results <- &blockValidationResult{
-					tIdx: tIdx,
-					err:  err,
+
+			// Check duplicate submissions of the business operation identified by the dedup key, if any
+			if req.dedupWindow > 0 {
+				dedupKey, duplicate, err := v.checkDedupKey(payload.Header, block.Header.Number, req.dedupWindow)
+				if err != nil {
+					logger.Errorf("Ledger failure while attempting to detect duplicate dedup key of txid %s, err '%s'. Aborting", txID, err)
+					results <- &blockValidationResult{
+						tIdx: tIdx,
+						err:  err,
+					}
+					return
+				}
+				if duplicate {
+					logger.Warningf("Transaction %s carries the dedup key %s of chaincode %s of a recently committed transaction, skipping", txID, dedupKey.key, dedupKey.namespace)
+					results <- &blockValidationResult{
+						tIdx:           tIdx,
+						validationCode: peer.TxValidationCode_DUPLICATE_DEDUP_KEY,
+					}
+					return
+				}
+			}
+
+			// Check the expiration of the creator and the endorsers at the time of the endorsement
+			if channelConfig := v.Support.ChannelConfig(); channelConfig.Capabilities().EndorsementTimeExpiration() {
+				if err := checkTimestamp(chdr, req.blockTime, channelConfig.EndorsementMaxSkew()); err != nil {
+					logger.Warningf("Transaction %s is invalid: %s", txID, err)
+					results <- &blockValidationResult{
+						tIdx:           tIdx,
+						validationCode: peer.TxValidationCode_BAD_CHANNEL_HEADER,
+					}
+					return
+				}
+				if err := checkExpiration(payload, chdr); err != nil {
+					logger.Warningf("Transaction %s is invalid: %s", txID, err)
+					results <- &blockValidationResult{
+						tIdx:           tIdx,
+						validationCode: peer.TxValidationCode_EXPIRED_IDENTITY,
+					}
+					return
 				}
-				return
 			}
-			// 3) err is of type blkstorage.NotFoundInIndexErr => there is no tx with the supplied id in the ledger
 
 			// Validate tx with vscc and policy
 			logger.Debug("Validating transaction vscc tx validate")
@@ -417,6 +618,26 @@ func (v *TxValidator) validateTx(req *blockValidationRequest, results chan<- *bl
 				return
 			}
 			logger.Debugf("config transaction received for chain %s", channel)
+		} else if customtx.IsRegistered(common.HeaderType(chdr.Type)) {
+			// Check duplicate transactions
+			txID = chdr.TxId
+			if res := v.checkDuplicateTxID(tIdx, txID); res != nil {
+				results <- res
+				return
+			}
+
+			// Check that the creator may write to the channel, the
+			// transaction having no endorsement
+			if err := v.checkWriters(env); err != nil {
+				logger.Warningf("Transaction %s of custom type [%s] is invalid: %s", txID, common.HeaderType(chdr.Type), err)
+				results <- &blockValidationResult{
+					tIdx:           tIdx,
+					validationCode: peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE,
+				}
+				return
+			}
+			// the processor of the transaction type validates it at commit time
+			logger.Debugf("transaction of custom type [%s] received for chain %s", common.HeaderType(chdr.Type), channel)
 		} else {
 			logger.Warningf("Unknown transaction type [%s] in block number [%d] transaction index [%d]",
 				common.HeaderType(chdr.Type), block.Header.Number, tIdx)
@@ -598,10 +819,18 @@ func (ds *dynamicCapabilities) ACLs() bool {
 	return ds.support.Capabilities().ACLs()
 }
 
+func (ds *dynamicCapabilities) ChaincodeMigration() bool {
+	return ds.support.Capabilities().ChaincodeMigration()
+}
+
 func (ds *dynamicCapabilities) CollectionUpgrade() bool {
 	return ds.support.Capabilities().CollectionUpgrade()
 }
 
+func (ds *dynamicCapabilities) EphemeralCollections() bool {
+	return ds.support.Capabilities().EphemeralCollections()
+}
+
 func (ds *dynamicCapabilities) ForbidDuplicateTXIdInBlock() bool {
 	return ds.support.Capabilities().ForbidDuplicateTXIdInBlock()
 }
@@ -633,3 +862,7 @@ func (ds *dynamicCapabilities) V1_2Validation() bool {
 func (ds *dynamicCapabilities) V1_3Validation() bool {
 	return ds.support.Capabilities().V1_3Validation()
 }
+
+func (ds *dynamicCapabilities) ValueCommitments() bool {
+	return ds.support.Capabilities().ValueCommitments()
+}
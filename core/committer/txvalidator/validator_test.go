@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package txvalidator_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -14,25 +16,35 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/channelconfig"
 	ctxt "github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/common/crypto/pedersen"
 	commonerrors "github.com/hyperledger/fabric/common/errors"
 	ledger2 "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
+	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
 	"github.com/hyperledger/fabric/common/mocks/scc"
+	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/committer/txvalidator"
 	"github.com/hyperledger/fabric/core/committer/txvalidator/mocks"
 	"github.com/hyperledger/fabric/core/committer/txvalidator/testdata"
 	ccp "github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/handlers/validation/api"
 	"github.com/hyperledger/fabric/core/handlers/validation/builtin"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/customtx"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
 	lutils "github.com/hyperledger/fabric/core/ledger/util"
 	mocktxvalidator "github.com/hyperledger/fabric/core/mocks/txvalidator"
+	"github.com/hyperledger/fabric/core/scc/crlscc"
+	"github.com/hyperledger/fabric/core/scc/xcc"
 	mocks2 "github.com/hyperledger/fabric/discovery/support/mocks"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/msp/mgmt"
@@ -45,6 +57,7 @@ import (
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/sync/semaphore"
 )
 
@@ -99,6 +112,10 @@ func setupLedgerAndValidatorWithCapabilities(t *testing.T, c *mockconfig.MockApp
 }
 
 func setupLedgerAndValidatorExplicitWithMSP(t *testing.T, cpb *mockconfig.MockApplicationCapabilities, plugin validation.Plugin, mspMgr msp.MSPManager) (ledger.PeerLedger, txvalidator.Validator) {
+	return setupLedgerAndValidatorExplicitWithChannel(t, cpb, plugin, mspMgr, nil)
+}
+
+func setupLedgerAndValidatorExplicitWithChannel(t *testing.T, cpb *mockconfig.MockApplicationCapabilities, plugin validation.Plugin, mspMgr msp.MSPManager, channelConfig channelconfig.Channel) (ledger.PeerLedger, txvalidator.Validator) {
 	viper.Set("peer.fileSystemPath", "/tmp/fabric/validatortest")
 	ledgermgmt.InitializeTestEnv()
 	gb, err := ctxt.MakeGenesisBlock("TestLedger")
@@ -108,7 +125,7 @@ func setupLedgerAndValidatorExplicitWithMSP(t *testing.T, cpb *mockconfig.MockAp
 	vcs := struct {
 		*mocktxvalidator.Support
 		*semaphore.Weighted
-	}{&mocktxvalidator.Support{LedgerVal: theLedger, ACVal: cpb, MSPManagerVal: mspMgr}, semaphore.NewWeighted(10)}
+	}{&mocktxvalidator.Support{LedgerVal: theLedger, ACVal: cpb, MSPManagerVal: mspMgr, ChannelVal: channelConfig}, semaphore.NewWeighted(10)}
 	mp := (&scc.MocksccProviderFactory{}).NewSystemChaincodeProvider()
 	pm := &mocks.PluginMapper{}
 	factory := &mocks.PluginFactory{}
@@ -1002,6 +1019,110 @@ func testInvokeOKSCC(t *testing.T, l ledger.PeerLedger, v txvalidator.Validator)
 	assertValid(b, t)
 }
 
+func TestInvokeCrossChannel(t *testing.T) {
+	l, v := setupLedgerAndValidatorWithV13Capabilities(t)
+	defer ledgermgmt.CleanupTestEnv()
+	defer l.Close()
+
+	putCCInfo(l, "mycc", signedByAnyMember([]string{"SampleOrg"}), t)
+
+	createXCCRWset := func(ccname string, record *xcc.Record) []byte {
+		rwsetBuilder := rwsetutil.NewRWSetBuilder()
+		recordBytes, err := json.Marshal(record)
+		assert.NoError(t, err)
+		rwsetBuilder.AddToWriteSet(xcc.Name, record.ID, recordBytes)
+		rwsetBuilder.AddToWriteSet(ccname, "key", []byte("value"))
+		rwset, err := rwsetBuilder.GetTxSimulationResults()
+		assert.NoError(t, err)
+		rwsetBytes, err := rwset.GetPubSimulationBytes()
+		assert.NoError(t, err)
+		return rwsetBytes
+	}
+	prepared := &xcc.Record{ID: "x1", Channel: util.GetTestChainID(), CounterpartChannel: "other", Status: xcc.Prepared}
+
+	for i, tc := range []struct {
+		name     string
+		rwset    []byte
+		expected peer.TxValidationCode
+	}{
+		{"prepare", createXCCRWset("mycc", prepared), peer.TxValidationCode_VALID},
+		{"undeployed chaincode", createXCCRWset("notdeployed", prepared), peer.TxValidationCode_INVALID_OTHER_REASON},
+		{"write to LSCC", createXCCRWset("lscc", prepared), peer.TxValidationCode_ILLEGAL_WRITESET},
+		{"bogus record", createRWset(t, xcc.Name, "mycc"), peer.TxValidationCode_INVALID_OTHER_REASON},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tx := getEnv(xcc.Name, nil, tc.rwset, t)
+			b := &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}, Header: &common.BlockHeader{Number: uint64(i + 2)}}
+
+			err := v.Validate(b)
+			assert.NoError(t, err)
+			if tc.expected == peer.TxValidationCode_VALID {
+				assertValid(b, t)
+			} else {
+				assertInvalid(b, t, tc.expected)
+			}
+		})
+	}
+}
+
+func TestInvokeCRLUpdate(t *testing.T) {
+	mspmgr := &mocks2.MSPManager{}
+	idThatSatisfiesPrincipal := &mocks2.Identity{}
+	idThatSatisfiesPrincipal.SatisfiesPrincipalReturns(nil)
+	idThatSatisfiesPrincipal.GetIdentifierReturns(&msp.IdentityIdentifier{})
+	mspmgr.DeserializeIdentityReturns(idThatSatisfiesPrincipal, nil)
+	channelConfig := &mockconfig.Channel{CapabilitiesVal: &mockconfig.ChannelCapabilities{PublishedCRLsVal: true}}
+	l, v := setupLedgerAndValidatorExplicitWithChannel(t, v13Capabilities(), &builtin.DefaultValidation{}, mspmgr, channelConfig)
+	defer ledgermgmt.CleanupTestEnv()
+	defer l.Close()
+
+	createCRLRWset := func(namespaces ...string) []byte {
+		rwsetBuilder := rwsetutil.NewRWSetBuilder()
+		listsBytes, err := json.Marshal(&crlscc.RevocationLists{MSPID: "SampleOrg", CRLs: [][]byte{}})
+		assert.NoError(t, err)
+		for _, ns := range namespaces {
+			rwsetBuilder.AddToWriteSet(ns, "SampleOrg", listsBytes)
+		}
+		rwset, err := rwsetBuilder.GetTxSimulationResults()
+		assert.NoError(t, err)
+		rwsetBytes, err := rwset.GetPubSimulationBytes()
+		assert.NoError(t, err)
+		return rwsetBytes
+	}
+
+	for i, tc := range []struct {
+		name     string
+		rwset    []byte
+		expected peer.TxValidationCode
+	}{
+		// the mock system chaincode provider exposes no application config
+		{"no application organization", createCRLRWset(crlscc.Name), peer.TxValidationCode_INVALID_OTHER_REASON},
+		{"write to another namespace", createCRLRWset(crlscc.Name, "mycc"), peer.TxValidationCode_ILLEGAL_WRITESET},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tx := getEnv(crlscc.Name, nil, tc.rwset, t)
+			b := &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}, Header: &common.BlockHeader{Number: uint64(i + 2)}}
+
+			err := v.Validate(b)
+			assert.NoError(t, err)
+			assertInvalid(b, t, tc.expected)
+		})
+	}
+}
+
+func TestInvokeCRLUpdateWithoutCapability(t *testing.T) {
+	l, v := setupLedgerAndValidatorWithV13Capabilities(t)
+	defer ledgermgmt.CleanupTestEnv()
+	defer l.Close()
+
+	tx := getEnv(crlscc.Name, nil, createRWset(t, crlscc.Name), t)
+	b := &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}, Header: &common.BlockHeader{Number: 2}}
+
+	err := v.Validate(b)
+	assert.NoError(t, err)
+	assertInvalid(b, t, peer.TxValidationCode_ILLEGAL_WRITESET)
+}
+
 func TestInvokeNOKWritesToLSCC(t *testing.T) {
 	t.Run("1.2Capability", func(t *testing.T) {
 		l, v := setupLedgerAndValidatorWithV12Capabilities(t)
@@ -1033,6 +1154,179 @@ func testInvokeNOKWritesToLSCC(t *testing.T, l ledger.PeerLedger, v txvalidator.
 	assertInvalid(b, t, peer.TxValidationCode_ILLEGAL_WRITESET)
 }
 
+// getMigrationEnv returns a transaction invoking the Migrate function of the chaincode
+func getMigrationEnv(ccID string, res []byte, t *testing.T) *common.Envelope {
+	cis := &peer.ChaincodeInvocationSpec{
+		ChaincodeSpec: &peer.ChaincodeSpec{
+			ChaincodeId: &peer.ChaincodeID{Name: ccID, Version: ccVersion},
+			Input:       &peer.ChaincodeInput{Args: [][]byte{[]byte("Migrate")}},
+			Type:        peer.ChaincodeSpec_GOLANG}}
+	prop, _, err := utils.CreateProposalFromCIS(common.HeaderType_ENDORSER_TRANSACTION, util.GetTestChainID(), cis, signerSerialized)
+	assert.NoError(t, err)
+
+	presp, err := utils.CreateProposalResponse(prop.Header, prop.Payload, &peer.Response{Status: 200}, res, nil, &peer.ChaincodeID{Name: ccID, Version: ccVersion}, nil, signer)
+	assert.NoError(t, err)
+	tx, err := utils.CreateSignedTx(prop, signer, presp)
+	assert.NoError(t, err)
+	return tx
+}
+
+// createMigrationRWset returns the results of the migration of the chaincode,
+// recording the version in the given key of LSCC
+func createMigrationRWset(t *testing.T, ccID, key, version string, read bool) []byte {
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder.AddToWriteSet(ccID, "key", []byte("value"))
+	if read {
+		rwsetBuilder.AddToReadSet("lscc", key, nil)
+	}
+	rwsetBuilder.AddToWriteSet("lscc", key, []byte(version))
+	rwset, err := rwsetBuilder.GetTxSimulationResults()
+	assert.NoError(t, err)
+	rwsetBytes, err := rwset.GetPubSimulationBytes()
+	assert.NoError(t, err)
+	return rwsetBytes
+}
+
+func TestInvokeMigration(t *testing.T) {
+	ccID := "mycc"
+	migrationKey := ccp.BuildMigrationKVSKey(ccID)
+
+	validate := func(l ledger.PeerLedger, v txvalidator.Validator, tx *common.Envelope) *common.Block {
+		b := &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}, Header: &common.BlockHeader{Number: 2}}
+		assert.NoError(t, v.Validate(b))
+		return b
+	}
+
+	t.Run("1.3Capability", func(t *testing.T) {
+		l, v := setupLedgerAndValidatorWithV13Capabilities(t)
+		defer ledgermgmt.CleanupTestEnv()
+		defer l.Close()
+
+		// the migrations are not recorded before V1_4_2, so they can't write to LSCC
+		putCCInfo(l, ccID, signedByAnyMember([]string{"SampleOrg"}), t)
+		b := validate(l, v, getMigrationEnv(ccID, createMigrationRWset(t, ccID, migrationKey, ccVersion, true), t))
+		assertInvalid(b, t, peer.TxValidationCode_ILLEGAL_WRITESET)
+	})
+
+	t.Run("1.4.2Capability", func(t *testing.T) {
+		capabilities := v13Capabilities()
+		capabilities.ChaincodeMigrationRv = true
+		l, v := setupLedgerAndValidatorWithCapabilities(t, capabilities)
+		defer ledgermgmt.CleanupTestEnv()
+		defer l.Close()
+
+		putCCInfo(l, ccID, signedByAnyMember([]string{"SampleOrg"}), t)
+		b := validate(l, v, getMigrationEnv(ccID, createMigrationRWset(t, ccID, migrationKey, ccVersion, true), t))
+		assertValid(b, t)
+
+		// only the Migrate function records the migrations
+		b = validate(l, v, getEnv(ccID, nil, createMigrationRWset(t, ccID, migrationKey, ccVersion, true), t))
+		assertInvalid(b, t, peer.TxValidationCode_ILLEGAL_WRITESET)
+
+		// a migration only records the version it was endorsed with
+		b = validate(l, v, getMigrationEnv(ccID, createMigrationRWset(t, ccID, migrationKey, "2.0", true), t))
+		assertInvalid(b, t, peer.TxValidationCode_ILLEGAL_WRITESET)
+
+		// a migration only records its own chaincode
+		b = validate(l, v, getMigrationEnv(ccID, createMigrationRWset(t, ccID, ccp.BuildMigrationKVSKey("othercc"), ccVersion, true), t))
+		assertInvalid(b, t, peer.TxValidationCode_ILLEGAL_WRITESET)
+		b = validate(l, v, getMigrationEnv(ccID, createMigrationRWset(t, ccID, ccID, ccVersion, true), t))
+		assertInvalid(b, t, peer.TxValidationCode_ILLEGAL_WRITESET)
+
+		// a migration must read the previous record
+		b = validate(l, v, getMigrationEnv(ccID, createMigrationRWset(t, ccID, migrationKey, ccVersion, false), t))
+		assertInvalid(b, t, peer.TxValidationCode_ILLEGAL_WRITESET)
+	})
+}
+
+func putCollectionConfig(theLedger ledger.PeerLedger, ccname string, ccp *common.CollectionConfigPackage, t *testing.T) {
+	txid := util.GenerateUUID()
+	simulator, err := theLedger.NewTxSimulator(txid)
+	assert.NoError(t, err)
+	simulator.SetState("lscc", privdata.BuildCollectionKVSKey(ccname), utils.MarshalOrPanic(ccp))
+	simulator.Done()
+
+	simRes, err := simulator.GetTxSimulationResults()
+	assert.NoError(t, err)
+	pubSimulationBytes, err := simRes.GetPubSimulationBytes()
+	assert.NoError(t, err)
+	bcInfo, err := theLedger.GetBlockchainInfo()
+	assert.NoError(t, err)
+	block0 := testutil.ConstructBlock(t, 2, bcInfo.CurrentBlockHash, [][]byte{pubSimulationBytes}, true)
+	err = theLedger.CommitWithPvtData(&ledger.BlockAndPvtData{
+		Block: block0,
+	})
+	assert.NoError(t, err)
+}
+
+func TestInvokePedersenCommitments(t *testing.T) {
+	ccID := "mycc"
+	commit := func(value []byte) []byte {
+		commitment, err := pedersen.Commit(value, bytes.Repeat([]byte{1}, pedersen.BlindingFactorSize))
+		assert.NoError(t, err)
+		return commitment
+	}
+
+	setup := func(capabilities *mockconfig.MockApplicationCapabilities) (ledger.PeerLedger, func(func(*rwsetutil.RWSetBuilder)) *common.Block) {
+		l, v := setupLedgerAndValidatorWithCapabilities(t, capabilities)
+		putCCInfo(l, ccID, signedByAnyMember([]string{"SampleOrg"}), t)
+		putCollectionConfig(l, ccID, &common.CollectionConfigPackage{Config: []*common.CollectionConfig{
+			{Payload: &common.CollectionConfig_StaticCollectionConfig{StaticCollectionConfig: &common.StaticCollectionConfig{Name: "hashed"}}},
+			{Payload: &common.CollectionConfig_StaticCollectionConfig{StaticCollectionConfig: &common.StaticCollectionConfig{Name: "committed", ValueCommitment: lutils.ValueCommitmentPedersen}}},
+		}}, t)
+
+		return l, func(build func(*rwsetutil.RWSetBuilder)) *common.Block {
+			rwsetBuilder := rwsetutil.NewRWSetBuilder()
+			build(rwsetBuilder)
+			rwset, err := rwsetBuilder.GetTxSimulationResults()
+			assert.NoError(t, err)
+			rwsetBytes, err := rwset.GetPubSimulationBytes()
+			assert.NoError(t, err)
+			b := &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(getEnv(ccID, nil, rwsetBytes, t))}}, Header: &common.BlockHeader{Number: 3}}
+			assert.NoError(t, v.Validate(b))
+			return b
+		}
+	}
+
+	t.Run("1.3Capability", func(t *testing.T) {
+		l, validate := setup(v13Capabilities())
+		defer ledgermgmt.CleanupTestEnv()
+		defer l.Close()
+
+		// the value commitments are not enabled before V1_4_2, so the writes are not checked
+		assertValid(validate(func(b *rwsetutil.RWSetBuilder) {
+			b.AddToPvtAndHashedWriteSet(ccID, "committed", "key", []byte("value"))
+		}), t)
+	})
+
+	t.Run("1.4.2Capability", func(t *testing.T) {
+		capabilities := v13Capabilities()
+		capabilities.ValueCommitmentsRv = true
+		l, validate := setup(capabilities)
+		defer ledgermgmt.CleanupTestEnv()
+		defer l.Close()
+
+		// the writes to the collections are committed with the scheme of their collection
+		assertValid(validate(func(b *rwsetutil.RWSetBuilder) {
+			b.AddToPvtAndHashedWriteSet(ccID, "hashed", "key", []byte("value"))
+			b.AddToPvtAndHashedWriteSetWithValueHashFunc(ccID, "committed", "key", []byte("value"), commit)
+			b.AddToPvtAndHashedWriteSet(ccID, "committed", "deleted", nil)
+		}), t)
+
+		// a hash isn't a commitment
+		assertInvalid(validate(func(b *rwsetutil.RWSetBuilder) {
+			b.AddToPvtAndHashedWriteSet(ccID, "committed", "key", []byte("value"))
+		}), t, peer.TxValidationCode_INVALID_WRITESET)
+
+		// neither is a value which isn't a point of the curve
+		assertInvalid(validate(func(b *rwsetutil.RWSetBuilder) {
+			b.AddToPvtAndHashedWriteSetWithValueHashFunc(ccID, "committed", "key", []byte("value"), func(value []byte) []byte {
+				return append([]byte{4}, commit(value)[1:]...)
+			})
+		}), t, peer.TxValidationCode_INVALID_WRITESET)
+	})
+}
+
 func TestInvokeNOKWritesToESCC(t *testing.T) {
 	t.Run("1.2Capability", func(t *testing.T) {
 		l, v := setupLedgerAndValidatorWithV12Capabilities(t)
@@ -1413,6 +1707,24 @@ func (m *mockLedger) GetTxValidationCodeByTxID(txID string) (peer.TxValidationCo
 	return args.Get(0).(peer.TxValidationCode), nil
 }
 
+// GetTransactionsByCreator returns the transactions created by the given identity
+func (m *mockLedger) GetTransactionsByCreator(creator []byte, startBlockNum uint64, limit int) ([]*ledger.IndexedTransaction, error) {
+	args := m.Called(creator, startBlockNum, limit)
+	return args.Get(0).([]*ledger.IndexedTransaction), nil
+}
+
+// GetBlocksByTimeRange returns the blocks committed within the given time range
+func (m *mockLedger) GetBlocksByTimeRange(start, end time.Time, limit int) ([]*common.Block, error) {
+	args := m.Called(start, end, limit)
+	return args.Get(0).([]*common.Block), nil
+}
+
+// GetBlockNumByDedupKey returns the block of the last valid transaction with the given dedup key
+func (m *mockLedger) GetBlockNumByDedupKey(namespace, dedupKey string) (uint64, error) {
+	args := m.Called(namespace, dedupKey)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
 // NewTxSimulator creates new transaction simulator
 func (m *mockLedger) NewTxSimulator(txid string) (ledger.TxSimulator, error) {
 	args := m.Called()
@@ -1547,6 +1859,11 @@ func (exec *mockQueryExecutor) GetPrivateData(namespace, collection, key string)
 	return args.Get(0).([]byte), args.Error(1)
 }
 
+func (exec *mockQueryExecutor) GetPrivateDataHash(namespace, collection, key string) ([]byte, error) {
+	args := exec.Called(namespace, collection, key)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
 func (exec *mockQueryExecutor) GetPrivateDataMetadataByHash(namespace, collection string, keyhash []byte) (map[string][]byte, error) {
 	args := exec.Called(namespace, collection, keyhash)
 	return args.Get(0).(map[string][]byte), args.Error(1)
@@ -1744,6 +2061,309 @@ func TestDuplicateTxId(t *testing.T) {
 	assertion.True(txsfltr.Flag(0) == peer.TxValidationCode_DUPLICATE_TXID)
 }
 
+// customTxType is the header type of the custom transactions, whose processor
+// is registered before the tests initialize the ledgers
+const customTxType = common.HeaderType(100)
+
+func init() {
+	if err := customtx.Register(customTxType, customTxProcessor{}); err != nil {
+		panic(err)
+	}
+}
+
+type customTxProcessor struct{}
+
+func (customTxProcessor) GenerateSimulationResults(txEnvelop *common.Envelope, simulator ledger.TxSimulator, initializingLedger bool) error {
+	return nil
+}
+
+func TestValidateCustomTx(t *testing.T) {
+	getCustomEnv := func() *common.Envelope {
+		nonce := utils.CreateNonceOrPanic()
+		txID, err := utils.ComputeProposalTxID(nonce, signerSerialized)
+		require.NoError(t, err)
+		payload := &common.Payload{
+			Header: &common.Header{
+				ChannelHeader: utils.MarshalOrPanic(&common.ChannelHeader{
+					Type:      int32(customTxType),
+					ChannelId: util.GetTestChainID(),
+					TxId:      txID,
+				}),
+				SignatureHeader: utils.MarshalOrPanic(&common.SignatureHeader{
+					Creator: signerSerialized,
+					Nonce:   nonce,
+				}),
+			},
+			Data: []byte("custom data"),
+		}
+		env := &common.Envelope{Payload: utils.MarshalOrPanic(payload)}
+		env.Signature, err = signer.Sign(env.Payload)
+		require.NoError(t, err)
+		return env
+	}
+
+	validate := func(writersErr, txByIDErr error, envs ...*common.Envelope) *common.Block {
+		theLedger := new(mockLedger)
+		theLedger.On("GetTransactionByID", mock.Anything).Return(&peer.ProcessedTransaction{}, txByIDErr)
+		vcs := struct {
+			*mocktxvalidator.Support
+			*semaphore.Weighted
+		}{&mocktxvalidator.Support{
+			LedgerVal: theLedger,
+			ACVal:     &mockconfig.MockApplicationCapabilities{ForbidDuplicateTXIdInBlockRv: true},
+			PolicyManagerVal: &mockpolicies.Manager{PolicyMap: map[string]policies.Policy{
+				policies.ChannelApplicationWriters: &mockpolicies.Policy{Err: writersErr},
+			}},
+		}, semaphore.NewWeighted(10)}
+		mp := (&scc.MocksccProviderFactory{}).NewSystemChaincodeProvider()
+		validator := txvalidator.NewTxValidator("", vcs, mp, &mocks.PluginMapper{})
+
+		b := &common.Block{Data: &common.BlockData{}, Header: &common.BlockHeader{}}
+		for _, env := range envs {
+			b.Data.Data = append(b.Data.Data, utils.MarshalOrPanic(env))
+		}
+		assert.NoError(t, validator.Validate(b))
+		return b
+	}
+
+	b := validate(nil, ledger.NotFoundInIndexErr(""), getCustomEnv())
+	assertValid(b, t)
+
+	// the creator must satisfy the writers policy of the channel
+	b = validate(errors.New("not a writer"), ledger.NotFoundInIndexErr(""), getCustomEnv())
+	assertInvalid(b, t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+
+	// the transactions whose ID is in the ledger are duplicates
+	b = validate(nil, nil, getCustomEnv())
+	assertInvalid(b, t, peer.TxValidationCode_DUPLICATE_TXID)
+
+	// and so are the transactions whose ID is in the same block
+	env := getCustomEnv()
+	b = validate(nil, ledger.NotFoundInIndexErr(""), env, env)
+	txsfltr := lutils.TxValidationFlags(b.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	assert.Equal(t, peer.TxValidationCode_VALID, txsfltr.Flag(0))
+	assert.Equal(t, peer.TxValidationCode_DUPLICATE_TXID, txsfltr.Flag(1))
+}
+
+func getEnvWithDedupKey(ccID, dedupKey string, t *testing.T) *common.Envelope {
+	prop, err := getProposalWithType(ccID, common.HeaderType_ENDORSER_TRANSACTION)
+	assert.NoError(t, err)
+	err = utils.SetProposalDedupKey(prop, dedupKey)
+	assert.NoError(t, err)
+
+	presp, err := utils.CreateProposalResponse(prop.Header, prop.Payload, &peer.Response{Status: 200}, createRWset(t, ccID), nil, &peer.ChaincodeID{Name: ccID, Version: ccVersion}, nil, signer)
+	assert.NoError(t, err)
+	tx, err := utils.CreateSignedTx(prop, signer, presp)
+	assert.NoError(t, err)
+	return tx
+}
+
+func TestDuplicateDedupKey(t *testing.T) {
+	ccID := "mycc"
+	validate := func(theLedger *mockLedger, dedupWindow uint64, txs ...*common.Envelope) lutils.TxValidationFlags {
+		vcs := struct {
+			*mocktxvalidator.Support
+			*semaphore.Weighted
+		}{&mocktxvalidator.Support{LedgerVal: theLedger, ACVal: &mockconfig.MockApplicationCapabilities{}, DedupWindowVal: dedupWindow}, semaphore.NewWeighted(10)}
+		mp := (&scc.MocksccProviderFactory{}).NewSystemChaincodeProvider()
+		pm := &mocks.PluginMapper{}
+		factory := &mocks.PluginFactory{}
+		plugin := &mocks.Plugin{}
+		factory.On("New").Return(plugin)
+		plugin.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		plugin.On("Validate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		pm.On("PluginFactoryByName", txvalidator.PluginName("vscc")).Return(factory)
+		validator := txvalidator.NewTxValidator("", vcs, mp, pm)
+
+		theLedger.On("GetTransactionByID", mock.Anything).Return(&peer.ProcessedTransaction{}, ledger.NotFoundInIndexErr(""))
+		queryExecutor := new(mockQueryExecutor)
+		queryExecutor.On("GetState", "lscc", ccID).Return(utils.MarshalOrPanic(&ccp.ChaincodeData{
+			Name:    ccID,
+			Version: ccVersion,
+			Vscc:    "vscc",
+			Policy:  signedByAnyMember([]string{"SampleOrg"}),
+		}), nil)
+		theLedger.On("NewQueryExecutor", mock.Anything).Return(queryExecutor, nil)
+
+		b := &common.Block{Header: &common.BlockHeader{Number: 10}, Data: &common.BlockData{}}
+		for _, tx := range txs {
+			b.Data.Data = append(b.Data.Data, utils.MarshalOrPanic(tx))
+		}
+		err := validator.Validate(b)
+		assert.NoError(t, err)
+		return lutils.TxValidationFlags(b.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+
+	t.Run("WithinWindow", func(t *testing.T) {
+		theLedger := new(mockLedger)
+		theLedger.On("GetBlockNumByDedupKey", ccID, "transfer-1").Return(uint64(5), nil)
+		txsfltr := validate(theLedger, 5, getEnvWithDedupKey(ccID, "transfer-1", t))
+		assert.Equal(t, peer.TxValidationCode_DUPLICATE_DEDUP_KEY, txsfltr.Flag(0))
+	})
+
+	t.Run("OutsideWindow", func(t *testing.T) {
+		theLedger := new(mockLedger)
+		theLedger.On("GetBlockNumByDedupKey", ccID, "transfer-1").Return(uint64(4), nil)
+		txsfltr := validate(theLedger, 5, getEnvWithDedupKey(ccID, "transfer-1", t))
+		assert.Equal(t, peer.TxValidationCode_VALID, txsfltr.Flag(0))
+	})
+
+	t.Run("DuplicateInBlock", func(t *testing.T) {
+		theLedger := new(mockLedger)
+		theLedger.On("GetBlockNumByDedupKey", ccID, mock.Anything).Return(uint64(0), ledger.NotFoundInIndexErr(""))
+		txsfltr := validate(theLedger, 5,
+			getEnvWithDedupKey(ccID, "transfer-1", t),
+			getEnvWithDedupKey(ccID, "transfer-2", t),
+			getEnvWithDedupKey(ccID, "transfer-1", t),
+			getEnv(ccID, nil, createRWset(t, ccID), t),
+		)
+		assert.Equal(t, peer.TxValidationCode_VALID, txsfltr.Flag(0))
+		assert.Equal(t, peer.TxValidationCode_VALID, txsfltr.Flag(1))
+		// the duplicates within the block are invalidated by the ledger, once the
+		// read-sets are validated, so that the first valid one is committed
+		assert.Equal(t, peer.TxValidationCode_VALID, txsfltr.Flag(2))
+		assert.Equal(t, peer.TxValidationCode_VALID, txsfltr.Flag(3))
+	})
+
+	t.Run("NoWindow", func(t *testing.T) {
+		theLedger := new(mockLedger)
+		txsfltr := validate(theLedger, 0, getEnvWithDedupKey(ccID, "transfer-1", t), getEnvWithDedupKey(ccID, "transfer-1", t))
+		assert.Equal(t, peer.TxValidationCode_VALID, txsfltr.Flag(0))
+		assert.Equal(t, peer.TxValidationCode_VALID, txsfltr.Flag(1))
+		theLedger.AssertNotCalled(t, "GetBlockNumByDedupKey", mock.Anything, mock.Anything)
+	})
+
+	t.Run("LedgerFailure", func(t *testing.T) {
+		theLedger := new(mockLedger)
+		theLedger.On("GetBlockNumByDedupKey", ccID, "transfer-1").Return(uint64(0), errors.New("disk failure"))
+		vcs := struct {
+			*mocktxvalidator.Support
+			*semaphore.Weighted
+		}{&mocktxvalidator.Support{LedgerVal: theLedger, ACVal: &mockconfig.MockApplicationCapabilities{}, DedupWindowVal: 5}, semaphore.NewWeighted(10)}
+		theLedger.On("GetTransactionByID", mock.Anything).Return(&peer.ProcessedTransaction{}, ledger.NotFoundInIndexErr(""))
+		validator := txvalidator.NewTxValidator("", vcs, (&scc.MocksccProviderFactory{}).NewSystemChaincodeProvider(), &mocks.PluginMapper{})
+
+		b := &common.Block{
+			Header: &common.BlockHeader{Number: 10},
+			Data:   &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(getEnvWithDedupKey(ccID, "transfer-1", t))}},
+		}
+		err := validator.Validate(b)
+		assert.EqualError(t, err, "disk failure")
+	})
+}
+
+func getEnvAt(ccID string, timestamp *timestamp.Timestamp, t *testing.T) *common.Envelope {
+	prop, err := getProposalWithType(ccID, common.HeaderType_ENDORSER_TRANSACTION)
+	assert.NoError(t, err)
+	hdr, err := utils.GetHeader(prop.Header)
+	assert.NoError(t, err)
+	chdr, err := utils.UnmarshalChannelHeader(hdr.ChannelHeader)
+	assert.NoError(t, err)
+	chdr.Timestamp = timestamp
+	hdr.ChannelHeader = utils.MarshalOrPanic(chdr)
+	prop.Header = utils.MarshalOrPanic(hdr)
+
+	presp, err := utils.CreateProposalResponse(prop.Header, prop.Payload, &peer.Response{Status: 200}, createRWset(t, ccID), nil, &peer.ChaincodeID{Name: ccID, Version: ccVersion}, nil, signer)
+	assert.NoError(t, err)
+	tx, err := utils.CreateSignedTx(prop, signer, presp)
+	assert.NoError(t, err)
+	return tx
+}
+
+func TestExpiredIdentity(t *testing.T) {
+	ccID := "mycc"
+	validate := func(endorsementTimeExpiration bool, txs ...*common.Envelope) []peer.TxValidationCode {
+		theLedger := new(mockLedger)
+		channelConfig := &mockconfig.Channel{
+			EndorsementMaxSkewVal: 15 * time.Minute,
+			CapabilitiesVal: &mockconfig.ChannelCapabilities{
+				EndorsementTimeExpirationVal: endorsementTimeExpiration,
+			},
+		}
+		vcs := struct {
+			*mocktxvalidator.Support
+			*semaphore.Weighted
+		}{&mocktxvalidator.Support{LedgerVal: theLedger, ACVal: &mockconfig.MockApplicationCapabilities{}, ChannelVal: channelConfig}, semaphore.NewWeighted(10)}
+		mp := (&scc.MocksccProviderFactory{}).NewSystemChaincodeProvider()
+		pm := &mocks.PluginMapper{}
+		factory := &mocks.PluginFactory{}
+		plugin := &mocks.Plugin{}
+		factory.On("New").Return(plugin)
+		plugin.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		plugin.On("Validate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		pm.On("PluginFactoryByName", txvalidator.PluginName("vscc")).Return(factory)
+		validator := txvalidator.NewTxValidator("", vcs, mp, pm)
+
+		theLedger.On("GetTransactionByID", mock.Anything).Return(&peer.ProcessedTransaction{}, ledger.NotFoundInIndexErr(""))
+		queryExecutor := new(mockQueryExecutor)
+		queryExecutor.On("GetState", "lscc", ccID).Return(utils.MarshalOrPanic(&ccp.ChaincodeData{
+			Name:    ccID,
+			Version: ccVersion,
+			Vscc:    "vscc",
+			Policy:  signedByAnyMember([]string{"SampleOrg"}),
+		}), nil)
+		theLedger.On("NewQueryExecutor", mock.Anything).Return(queryExecutor, nil)
+
+		b := &common.Block{
+			Header: &common.BlockHeader{Number: 10},
+			Data:   &common.BlockData{},
+		}
+		for _, tx := range txs {
+			b.Data.Data = append(b.Data.Data, utils.MarshalOrPanic(tx))
+		}
+		err := validator.Validate(b)
+		assert.NoError(t, err)
+		var codes []peer.TxValidationCode
+		for i := range txs {
+			codes = append(codes, lutils.TxValidationFlags(b.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER]).Flag(i))
+		}
+		return codes
+	}
+
+	expiration := crypto.ExpiresAt(signerSerialized)
+	assert.False(t, expiration.IsZero())
+	endorsedAt := func(t time.Time) *timestamp.Timestamp {
+		return &timestamp.Timestamp{Seconds: t.Unix()}
+	}
+
+	t.Run("EndorsedBeforeExpiration", func(t *testing.T) {
+		tx := getEnvAt(ccID, endorsedAt(expiration.Add(-time.Minute)), t)
+		assert.Equal(t, []peer.TxValidationCode{peer.TxValidationCode_VALID}, validate(true, tx))
+	})
+
+	t.Run("EndorsedAfterExpiration", func(t *testing.T) {
+		tx := getEnvAt(ccID, endorsedAt(expiration.Add(time.Minute)), t)
+		assert.Equal(t, []peer.TxValidationCode{peer.TxValidationCode_EXPIRED_IDENTITY}, validate(true, tx))
+	})
+
+	t.Run("NoTimestamp", func(t *testing.T) {
+		tx := getEnvAt(ccID, nil, t)
+		assert.Equal(t, []peer.TxValidationCode{peer.TxValidationCode_BAD_CHANNEL_HEADER}, validate(true, tx))
+	})
+
+	t.Run("OlderThanTheBlock", func(t *testing.T) {
+		stale := getEnvAt(ccID, endorsedAt(expiration.Add(-time.Hour)), t)
+		recent := getEnvAt(ccID, endorsedAt(expiration.Add(-time.Minute)), t)
+		assert.Equal(t, []peer.TxValidationCode{
+			peer.TxValidationCode_BAD_CHANNEL_HEADER,
+			peer.TxValidationCode_VALID,
+		}, validate(true, stale, recent))
+
+		withinSkew := getEnvAt(ccID, endorsedAt(expiration.Add(-20*time.Minute)), t)
+		assert.Equal(t, []peer.TxValidationCode{
+			peer.TxValidationCode_VALID,
+			peer.TxValidationCode_VALID,
+		}, validate(true, withinSkew, recent))
+	})
+
+	t.Run("WithoutCapability", func(t *testing.T) {
+		tx := getEnvAt(ccID, endorsedAt(expiration.Add(time.Minute)), t)
+		assert.Equal(t, []peer.TxValidationCode{peer.TxValidationCode_VALID}, validate(false, tx))
+		tx = getEnvAt(ccID, nil, t)
+		assert.Equal(t, []peer.TxValidationCode{peer.TxValidationCode_VALID}, validate(false, tx))
+	})
+}
+
 func TestValidationInvalidEndorsing(t *testing.T) {
 	theLedger := new(mockLedger)
 	vcs := struct {
@@ -14,12 +14,17 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/crypto/pedersen"
 	commonerrors "github.com/hyperledger/fabric/common/errors"
 	coreUtil "github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
 	"github.com/hyperledger/fabric/core/handlers/validation/api"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/core/scc/crlscc"
+	"github.com/hyperledger/fabric/core/scc/xcc"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -159,10 +164,20 @@ func (v *VsccValidatorImpl) VSCCValidateTx(seq int, payload *common.Payload, env
 		//    for instance to get information about itself or another chaincode; however
 		//    these legitimate invocations only ready from LSCC's namespace; currently
 		//    only two functions of LSCC write to its namespace: deploy and upgrade and
-		//    neither should be used by an application chaincode
+		//    neither should be used by an application chaincode; the only exception
+		//    is the migration of the state of the chaincode, which records in LSCC
+		//    the version the state was migrated to
 		if writesToLSCC {
-			return errors.Errorf("chaincode %s attempted to write to the namespace of LSCC", ccID),
-				peer.TxValidationCode_ILLEGAL_WRITESET
+			if !v.isMigrationTx(payload) {
+				return errors.Errorf("chaincode %s attempted to write to the namespace of LSCC", ccID),
+					peer.TxValidationCode_ILLEGAL_WRITESET
+			}
+			if err = validateMigrationWrites(ccID, ccVer, txRWSet); err != nil {
+				return err, peer.TxValidationCode_ILLEGAL_WRITESET
+			}
+			// the migration is validated against the endorsement policy of the
+			// chaincode, since LSCC has none to speak of
+			wrNamespace = removeNamespace(wrNamespace, "lscc")
 		}
 		// 2) we don't write to the namespace of a chaincode that we cannot invoke - if
 		//    the chaincode cannot be invoked in the first place, there's no legitimate
@@ -175,6 +190,20 @@ func (v *VsccValidatorImpl) VSCCValidateTx(seq int, payload *common.Payload, env
 			return errors.Errorf("chaincode %s attempted to write to the namespace of a system chaincode that cannot be invoked", ccID),
 				peer.TxValidationCode_ILLEGAL_WRITESET
 		}
+		// 3) the hashed writes to the collections committing to their private values
+		//    with Pedersen commitments are well-formed commitments, since the peers
+		//    which lack the private values could not tell otherwise; only channels
+		//    whose capabilities enable the value commitments have such collections
+		if v.support.Capabilities().ValueCommitments() {
+			if err = v.validateValueCommitments(txRWSet); err != nil {
+				switch err.(type) {
+				case *commonerrors.VSCCInfoLookupFailureError:
+					return err, peer.TxValidationCode_INVALID_OTHER_REASON
+				default:
+					return err, peer.TxValidationCode_INVALID_WRITESET
+				}
+			}
+		}
 
 		// validate *EACH* read write set according to its chaincode's endorsement policy
 		for _, ns := range wrNamespace {
@@ -224,6 +253,13 @@ func (v *VsccValidatorImpl) VSCCValidateTx(seq int, payload *common.Payload, env
 				peer.TxValidationCode_ILLEGAL_WRITESET
 		}
 
+		// the peers of the channels without the capability don't validate
+		// nor apply the published CRLs, hence they may not be committed
+		if ccID == crlscc.Name && !v.support.ChannelConfig().Capabilities().PublishedCRLs() {
+			return errors.Errorf("committing an invocation of cc %s is illegal without the published CRLs capability", ccID),
+				peer.TxValidationCode_ILLEGAL_WRITESET
+		}
+
 		// Get latest chaincode version, vscc and validate policy
 		_, vscc, policy, err := v.GetInfoForValidate(chdr, ccID)
 		if err != nil {
@@ -254,11 +290,234 @@ func (v *VsccValidatorImpl) VSCCValidateTx(seq int, payload *common.Payload, env
 				return err, peer.TxValidationCode_INVALID_OTHER_REASON
 			}
 		}
+
+		if ccID == xcc.Name {
+			if err, code := v.validateCrossChannelTx(seq, envBytes, block, chdr, wrNamespace, txRWSet); err != nil {
+				return err, code
+			}
+		}
+
+		if ccID == crlscc.Name {
+			if err, code := v.validateCRLTx(envBytes, chdr, wrNamespace, txRWSet); err != nil {
+				return err, code
+			}
+		}
 	}
 	logger.Debugf("[%s] VSCCValidateTx completes env bytes %p", chainID, envBytes)
 	return nil, peer.TxValidationCode_VALID
 }
 
+// isMigrationTx returns true if the transaction invokes the Migrate function
+// of its chaincode on a channel which records the migrations
+func (v *VsccValidatorImpl) isMigrationTx(payload *common.Payload) bool {
+	if !v.support.Capabilities().ChaincodeMigration() {
+		return false
+	}
+	tx, err := utils.GetTransaction(payload.Data)
+	if err != nil {
+		return false
+	}
+	cap, err := utils.GetChaincodeActionPayload(tx.Actions[0].Payload)
+	if err != nil {
+		return false
+	}
+	cpp, err := utils.GetChaincodeProposalPayload(cap.ChaincodeProposalPayload)
+	if err != nil {
+		return false
+	}
+	cis := &peer.ChaincodeInvocationSpec{}
+	if err = proto.Unmarshal(cpp.Input, cis); err != nil {
+		return false
+	}
+	args := cis.GetChaincodeSpec().GetInput().GetArgs()
+	return len(args) > 0 && string(args[0]) == ccprovider.MigrateFunction
+}
+
+// validateMigrationWrites validates the writes of a migration to the namespace
+// of LSCC: it may only record the version of the chaincode it was endorsed
+// with, which is checked against the latest definition of the chaincode, and
+// it must have read the previous record, so that concurrent migrations
+// to the same version conflict
+func validateMigrationWrites(ccID, ccVer string, txRWSet *rwsetutil.TxRwSet) error {
+	key := ccprovider.BuildMigrationKVSKey(ccID)
+	for _, ns := range txRWSet.NsRwSets {
+		if ns.NameSpace != "lscc" {
+			continue
+		}
+		if len(ns.CollHashedRwSets) != 0 || len(ns.KvRwSet.MetadataWrites) != 0 {
+			return errors.Errorf("migration of chaincode %s attempted to write private data or metadata to the namespace of LSCC", ccID)
+		}
+		if len(ns.KvRwSet.Writes) != 1 || ns.KvRwSet.Writes[0].Key != key || ns.KvRwSet.Writes[0].IsDelete {
+			return errors.Errorf("migration of chaincode %s attempted to write to the namespace of LSCC other than key %s", ccID, key)
+		}
+		if string(ns.KvRwSet.Writes[0].Value) != ccVer {
+			return errors.Errorf("migration of chaincode %s to version %s recorded version %s", ccID, ccVer, ns.KvRwSet.Writes[0].Value)
+		}
+		for _, read := range ns.KvRwSet.Reads {
+			if read.Key == key {
+				return nil
+			}
+		}
+		return errors.Errorf("migration of chaincode %s did not read key %s", ccID, key)
+	}
+	return nil
+}
+
+// validateValueCommitments validates the format of the value hashes written to
+// the collections committing to their private values with Pedersen commitments
+func (v *VsccValidatorImpl) validateValueCommitments(txRWSet *rwsetutil.TxRwSet) error {
+	for _, ns := range txRWSet.NsRwSets {
+		var pedersenColls map[string]bool
+		for _, coll := range ns.CollHashedRwSets {
+			if coll.HashedRwSet == nil || len(coll.HashedRwSet.HashedWrites) == 0 {
+				continue
+			}
+			if pedersenColls == nil {
+				var err error
+				if pedersenColls, err = v.pedersenCollections(ns.NameSpace); err != nil {
+					return err
+				}
+			}
+			if !pedersenColls[coll.CollectionName] {
+				continue
+			}
+			for _, write := range coll.HashedRwSet.HashedWrites {
+				if write.IsDelete {
+					continue
+				}
+				if err := pedersen.CheckFormat(write.ValueHash); err != nil {
+					return errors.WithMessage(err, fmt.Sprintf("invalid write to collection %s of chaincode %s", coll.CollectionName, ns.NameSpace))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// pedersenCollections returns the names of the collections of the given chaincode
+// committing to their private values with Pedersen commitments
+func (v *VsccValidatorImpl) pedersenCollections(ccid string) (map[string]bool, error) {
+	l := v.support.Ledger()
+	if l == nil {
+		return nil, errors.New("nil ledger instance")
+	}
+
+	qe, err := l.NewQueryExecutor()
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not retrieve QueryExecutor")
+	}
+	defer qe.Done()
+
+	bytes, err := qe.GetState("lscc", privdata.BuildCollectionKVSKey(ccid))
+	if err != nil {
+		return nil, &commonerrors.VSCCInfoLookupFailureError{
+			Reason: fmt.Sprintf("Could not retrieve collections of chaincode %s, error %s", ccid, err),
+		}
+	}
+	ccp, err := privdata.ParseCollectionConfig(bytes)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("invalid collection configuration of chaincode %s", ccid))
+	}
+
+	colls := map[string]bool{}
+	for _, cc := range ccp.Config {
+		if scc := cc.GetStaticCollectionConfig(); scc != nil && scc.ValueCommitment == ledgerUtil.ValueCommitmentPedersen {
+			colls[scc.Name] = true
+		}
+	}
+	return colls, nil
+}
+
+// removeNamespace returns the namespaces without the given one
+func removeNamespace(namespaces []string, namespace string) []string {
+	var filtered []string
+	for _, ns := range namespaces {
+		if ns != namespace {
+			filtered = append(filtered, ns)
+		}
+	}
+	return filtered
+}
+
+// validateCrossChannelTx validates an invocation of the cross-channel
+// transaction coordinator: the application chaincodes it invoked must
+// satisfy their own endorsement policies, since the system chaincode
+// policy only covers the namespace of the coordinator, and the decisions
+// it recorded must still hold against the ledgers of the counterpart channels
+func (v *VsccValidatorImpl) validateCrossChannelTx(seq int, envBytes []byte, block *common.Block, chdr *common.ChannelHeader, wrNamespace []string, txRWSet *rwsetutil.TxRwSet) (error, peer.TxValidationCode) {
+	for _, ns := range wrNamespace {
+		if ns == xcc.Name {
+			continue
+		}
+		if v.sccprovider.IsSysCC(ns) {
+			return errors.Errorf("chaincode %s attempted to write to the namespace of system chaincode %s", xcc.Name, ns),
+				peer.TxValidationCode_ILLEGAL_WRITESET
+		}
+
+		_, vscc, policy, err := v.GetInfoForValidate(chdr, ns)
+		if err != nil {
+			logger.Errorf("GetInfoForValidate for txId = %s returned error: %+v", chdr.TxId, err)
+			return err, peer.TxValidationCode_INVALID_OTHER_REASON
+		}
+
+		ctx := &Context{
+			Seq:       seq,
+			Envelope:  envBytes,
+			Block:     block,
+			TxID:      chdr.TxId,
+			Channel:   chdr.ChannelId,
+			Namespace: ns,
+			Policy:    policy,
+			VSCCName:  vscc.ChaincodeName,
+		}
+		if err = v.VSCCValidateTxForCC(ctx); err != nil {
+			switch err.(type) {
+			case *commonerrors.VSCCEndorsementPolicyError:
+				return err, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE
+			default:
+				return err, peer.TxValidationCode_INVALID_OTHER_REASON
+			}
+		}
+	}
+
+	if err := xcc.ValidateDecisions(chdr.ChannelId, txRWSet, v.sccprovider); err != nil {
+		if _, ok := err.(*commonerrors.VSCCExecutionFailureError); ok {
+			logger.Errorf("Cross-channel transaction %s can't be validated: %s", chdr.TxId, err)
+			return err, peer.TxValidationCode_INVALID_OTHER_REASON
+		}
+		logger.Warningf("Cross-channel transaction %s is invalid: %s", chdr.TxId, err)
+		return err, peer.TxValidationCode_INVALID_OTHER_REASON
+	}
+	return nil, peer.TxValidationCode_VALID
+}
+
+// validateCRLTx validates an invocation of the CRL system chaincode: it may
+// only write to its own namespace, since the system chaincode policy only
+// covers this namespace, and the CRLs it publishes must still be issued by
+// the CAs of their MSPs and published by the admins of their organizations
+func (v *VsccValidatorImpl) validateCRLTx(envBytes []byte, chdr *common.ChannelHeader, wrNamespace []string, txRWSet *rwsetutil.TxRwSet) (error, peer.TxValidationCode) {
+	for _, ns := range wrNamespace {
+		if ns != crlscc.Name {
+			return errors.Errorf("chaincode %s attempted to write to the namespace of chaincode %s", crlscc.Name, ns),
+				peer.TxValidationCode_ILLEGAL_WRITESET
+		}
+	}
+
+	env, err := utils.GetEnvelopeFromBlock(envBytes)
+	if err != nil {
+		return err, peer.TxValidationCode_INVALID_OTHER_REASON
+	}
+	signedData, err := env.AsSignedData()
+	if err != nil {
+		return err, peer.TxValidationCode_INVALID_OTHER_REASON
+	}
+	if err := crlscc.ValidateUpdates(chdr.ChannelId, txRWSet, signedData, v.support.MSPManager(), v.sccprovider); err != nil {
+		logger.Warningf("CRL update %s is invalid: %s", chdr.TxId, err)
+		return err, peer.TxValidationCode_INVALID_OTHER_REASON
+	}
+	return nil, peer.TxValidationCode_VALID
+}
+
 func (v *VsccValidatorImpl) VSCCValidateTxForCC(ctx *Context) error {
 	logger.Debug("Validating", ctx, "with plugin")
 	err := v.pluginValidator.ValidateWithPlugin(ctx)
@@ -17,6 +17,7 @@ limitations under the License.
 package ccpackage
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"testing"
@@ -163,6 +164,46 @@ func TestCreateSignedCCDepSpecForInstall(t *testing.T) {
 	}
 }
 
+func TestCreateEndorsedSignedCCDepSpecForInstall(t *testing.T) {
+	mspid, _ := localmsp.GetIdentifier()
+	sigpolicy := createInstantiationPolicy(mspid, mspprotos.MSPRole_ADMIN)
+	env1, err := ownerCreateCCDepSpec([]byte("codepackage"), sigpolicy, signer)
+	if err != nil || env1 == nil {
+		t.Fatalf("error owner creating package %s", err)
+		return
+	}
+
+	env2, err := ownerCreateCCDepSpec([]byte("codepackage"), sigpolicy, signer)
+	if err != nil || env2 == nil {
+		t.Fatalf("error owner creating package %s", err)
+		return
+	}
+
+	env, err := CreateSignedCCDepSpecForInstall([]*common.Envelope{env1, env2})
+	if err != nil || env == nil {
+		t.Fatalf("error creating install package %s", err)
+		return
+	}
+
+	_, sigdepspec, err := ExtractSignedCCDepSpec(env)
+	if err != nil {
+		t.Fatalf("fatal error extracting sigdepspec %s", err)
+		return
+	}
+
+	// the endorsements of all the owners are collected
+	if len(sigdepspec.OwnerEndorsements) != 2 {
+		t.Fatalf("invalid number of endorsements %d", len(sigdepspec.OwnerEndorsements))
+		return
+	}
+	for _, e := range sigdepspec.OwnerEndorsements {
+		if e == nil || !bytes.Equal(e.Endorser, signerSerialized) {
+			t.Fatalf("invalid endorsement %v", e)
+			return
+		}
+	}
+}
+
 func TestMismatchedCodePackages(t *testing.T) {
 	mspid, _ := localmsp.GetIdentifier()
 	sigpolicy := createInstantiationPolicy(mspid, mspprotos.MSPRole_ADMIN)
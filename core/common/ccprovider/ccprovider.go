@@ -8,6 +8,7 @@ package ccprovider
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -17,6 +18,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/chaincode"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/tracing"
 	"github.com/hyperledger/fabric/core/ledger"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/pkg/errors"
@@ -501,6 +503,13 @@ type TransactionParams struct {
 
 	// this is additional data passed to the chaincode
 	ProposalDecorations map[string][]byte
+
+	// Span is the span of the simulation, if the transaction is traced
+	Span *tracing.Span
+
+	// Context is the context of the proposal, if any. Its deadline bounds
+	// the execution of the chaincode, which is aborted when it is done.
+	Context context.Context
 }
 
 // ChaincodeProvider provides an abstraction layer that is
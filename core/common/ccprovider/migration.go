@@ -0,0 +1,44 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccprovider
+
+import (
+	"strings"
+)
+
+const (
+	// MigrateFunction is the name of the optional function of a chaincode
+	// which transforms its existing state after an upgrade; the peers record
+	// in the namespace of LSCC the version its state was migrated to
+	MigrateFunction = "Migrate"
+
+	// migrationSeparator is the separator used to build the KVS key storing
+	// the migrated version of a chaincode; it is the separator of the keys
+	// storing the collections, so the readers of the LSCC namespace check
+	// for the migration keys first
+	migrationSeparator = "~"
+	// migrationSuffix is the suffix of the KVS key storing the migrated
+	// version of a chaincode
+	migrationSuffix = "migration"
+)
+
+// BuildMigrationKVSKey constructs the key of the LSCC namespace storing the
+// version the state of the given chaincode was migrated to
+func BuildMigrationKVSKey(ccname string) string {
+	return ccname + migrationSeparator + migrationSuffix
+}
+
+// IsMigrationKey detects if a key of the LSCC namespace stores the migrated
+// version of a chaincode
+func IsMigrationKey(key string) bool {
+	return strings.HasSuffix(key, migrationSeparator+migrationSuffix)
+}
+
+// GetCCNameFromMigrationKey returns the chaincode name given a migration key
+func GetCCNameFromMigrationKey(key string) string {
+	return strings.TrimSuffix(key, migrationSeparator+migrationSuffix)
+}
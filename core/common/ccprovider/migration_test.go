@@ -0,0 +1,23 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccprovider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationKey(t *testing.T) {
+	key := BuildMigrationKVSKey("mycc")
+	assert.Equal(t, "mycc~migration", key)
+	assert.True(t, IsMigrationKey(key))
+	assert.Equal(t, "mycc", GetCCNameFromMigrationKey(key))
+
+	assert.False(t, IsMigrationKey("mycc"))
+	assert.False(t, IsMigrationKey("mycc~collection"))
+}
@@ -108,6 +108,26 @@ func (ccpack *SignedCDSPackage) GetInstantiationPolicy() []byte {
 	return ccpack.sDepSpec.InstantiationPolicy
 }
 
+// GetOwnerSignedData gets the endorsements of the owners of the package as
+// signed data, so that they can be evaluated against a policy
+func (ccpack *SignedCDSPackage) GetOwnerSignedData() []*common.SignedData {
+	if ccpack.sDepSpec == nil {
+		panic("GetOwnerSignedData called on uninitialized package")
+	}
+
+	var sd []*common.SignedData
+	for _, o := range ccpack.sDepSpec.OwnerEndorsements {
+		// the owners sign the concatenation of the deployment spec, the
+		// instantiation policy and their serialized identity
+		var data []byte
+		data = append(data, ccpack.sDepSpec.ChaincodeDeploymentSpec...)
+		data = append(data, ccpack.sDepSpec.InstantiationPolicy...)
+		data = append(data, o.Endorser...)
+		sd = append(sd, &common.SignedData{Data: data, Identity: o.Endorser, Signature: o.Signature})
+	}
+	return sd
+}
+
 // GetDepSpecBytes gets the serialized ChaincodeDeploymentSpec from the package
 func (ccpack *SignedCDSPackage) GetDepSpecBytes() []byte {
 	//this has to be after creating a package and initializing it
@@ -346,3 +346,37 @@ func TestSignedCDSSwitchChaincodes(t *testing.T) {
 		return
 	}
 }
+
+func TestGetOwnerSignedData(t *testing.T) {
+	cds := &pb.ChaincodeDeploymentSpec{ChaincodeSpec: &pb.ChaincodeSpec{Type: 1, ChaincodeId: &pb.ChaincodeID{Name: "testcc", Version: "0"}, Input: &pb.ChaincodeInput{Args: [][]byte{[]byte("")}}}, CodePackage: []byte("code")}
+
+	ccpack, _, _, err := processSignedCDS(cds, &common.SignaturePolicyEnvelope{Version: 1}, false)
+	assert.NoError(t, err)
+	assert.Empty(t, ccpack.GetOwnerSignedData())
+
+	// add the endorsements of two owners to the package
+	env, err := ccpackage.OwnerCreateSignedCCDepSpec(cds, &common.SignaturePolicyEnvelope{Version: 1}, nil)
+	assert.NoError(t, err)
+	_, sdepspec, err := ccpackage.ExtractSignedCCDepSpec(env)
+	assert.NoError(t, err)
+	sdepspec.OwnerEndorsements = []*pb.Endorsement{
+		{Endorser: []byte("owner1"), Signature: []byte("signature1")},
+		{Endorser: []byte("owner2"), Signature: []byte("signature2")},
+	}
+	payload := utils.UnmarshalPayloadOrPanic(env.Payload)
+	payload.Data = utils.MarshalOrPanic(sdepspec)
+	env.Payload = utils.MarshalOrPanic(payload)
+
+	ccpack = &SignedCDSPackage{}
+	_, err = ccpack.InitFromBuffer(utils.MarshalOrPanic(env))
+	assert.NoError(t, err)
+
+	sd := ccpack.GetOwnerSignedData()
+	assert.Len(t, sd, 2)
+	for i, owner := range []string{"owner1", "owner2"} {
+		data := append(append(append([]byte{}, sdepspec.ChaincodeDeploymentSpec...), sdepspec.InstantiationPolicy...), owner...)
+		assert.Equal(t, data, sd[i].Data)
+		assert.Equal(t, []byte(owner), sd[i].Identity)
+		assert.Equal(t, []byte(fmt.Sprintf("signature%d", i+1)), sd[i].Signature)
+	}
+}
@@ -55,6 +55,10 @@ type CollectionPersistenceConfigs interface {
 	// For instance if the value is set to 10, a key last modified by block number 100
 	// will be purged at block number 111. A zero value is treated same as MaxUint64
 	BlockToLive() uint64
+
+	// Ephemeral returns whether the collection data is only kept in the transient
+	// store until the transaction commits, and never persisted by the peers
+	Ephemeral() bool
 }
 
 // Filter defines a rule that filters peers according to data signed by them.
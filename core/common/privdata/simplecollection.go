@@ -29,6 +29,7 @@ type SimpleCollection struct {
 
 type SimpleCollectionPersistenceConfigs struct {
 	blockToLive uint64
+	ephemeral   bool
 }
 
 // CollectionID returns the collection's ID
@@ -143,3 +144,8 @@ func (sc *SimpleCollection) setupAccessPolicy(collectionPolicyConfig *common.Col
 func (s *SimpleCollectionPersistenceConfigs) BlockToLive() uint64 {
 	return s.blockToLive
 }
+
+// Ephemeral return collection's ephemeral configuration
+func (s *SimpleCollectionPersistenceConfigs) Ephemeral() bool {
+	return s.ephemeral
+}
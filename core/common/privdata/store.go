@@ -147,5 +147,5 @@ func (c *simpleCollectionStore) RetrieveCollectionPersistenceConfigs(cc common.C
 	if err != nil {
 		return nil, err
 	}
-	return &SimpleCollectionPersistenceConfigs{staticCollectionConfig.BlockToLive}, nil
+	return &SimpleCollectionPersistenceConfigs{staticCollectionConfig.BlockToLive, staticCollectionConfig.Ephemeral}, nil
 }
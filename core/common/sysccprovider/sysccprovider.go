@@ -44,6 +44,15 @@ type SystemChaincodeProvider interface {
 	// access to the ledger
 	GetQueryExecutorForLedger(cid string) (ledger.QueryExecutor, error)
 
+	// GetLedgerHeight returns the height of the ledger of the
+	// supplied channel
+	GetLedgerHeight(cid string) (uint64, error)
+
+	// GetStateAtHeight returns the value the supplied key of the supplied
+	// namespace had on the ledger of the supplied channel when the ledger
+	// was at the supplied height, nil if the key didn't exist then
+	GetStateAtHeight(cid, namespace, key string, height uint64) ([]byte, error)
+
 	// GetApplicationConfig returns the configtxapplication.SharedConfig for the channel
 	// and whether the Application config exists
 	GetApplicationConfig(cid string) (channelconfig.Application, bool)
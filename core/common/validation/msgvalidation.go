@@ -22,6 +22,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/ledger/customtx"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/msp"
@@ -398,6 +399,12 @@ func ValidateTransaction(e *common.Envelope, c channelconfig.ApplicationCapabili
 		return nil, pb.TxValidationCode_BAD_COMMON_HEADER
 	}
 
+	// the transactions of the custom types are signed by their creator, and
+	// their content is validated by their processor at commit time
+	if customtx.IsRegistered(common.HeaderType(chdr.Type)) {
+		return validateCustomTransaction(e, payload, chdr)
+	}
+
 	err = validateChannelHeader(chdr)
 	if err != nil {
 		return nil, pb.TxValidationCode_BAD_COMMON_HEADER
@@ -473,3 +480,37 @@ func ValidateTransaction(e *common.Envelope, c channelconfig.ApplicationCapabili
 		return nil, pb.TxValidationCode_UNSUPPORTED_TX_PAYLOAD
 	}
 }
+
+// validateCustomTransaction checks that the transaction of a custom type has
+// a valid signature header, is signed by its creator and that its ID is
+// computed as the one of an endorser transaction, so that the committer
+// detects its duplicates
+func validateCustomTransaction(e *common.Envelope, payload *common.Payload, chdr *common.ChannelHeader) (*common.Payload, pb.TxValidationCode) {
+	if chdr.Epoch != 0 {
+		return nil, pb.TxValidationCode_BAD_COMMON_HEADER
+	}
+
+	shdr, err := utils.GetSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		return nil, pb.TxValidationCode_BAD_COMMON_HEADER
+	}
+
+	err = validateSignatureHeader(shdr)
+	if err != nil {
+		return nil, pb.TxValidationCode_BAD_COMMON_HEADER
+	}
+
+	err = checkSignatureFromCreator(shdr.Creator, e.Signature, e.Payload, chdr.ChannelId)
+	if err != nil {
+		putilsLogger.Errorf("checkSignatureFromCreator returns err %s", err)
+		return nil, pb.TxValidationCode_BAD_CREATOR_SIGNATURE
+	}
+
+	err = utils.CheckProposalTxID(chdr.TxId, shdr.Nonce, shdr.Creator)
+	if err != nil {
+		putilsLogger.Errorf("CheckProposalTxID returns err %s", err)
+		return nil, pb.TxValidationCode_BAD_PROPOSAL_TXID
+	}
+
+	return payload, pb.TxValidationCode_VALID
+}
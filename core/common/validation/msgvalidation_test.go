@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/mocks/config"
 	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/customtx"
 	"github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/peer"
@@ -117,3 +121,69 @@ func TestValidateProposalMessage(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), fmt.Sprintf("access denied: channel [%s] creator org [%s]", util.GetTestChainID(), signerMSPId))
 }
+
+type customTxProcessor struct{}
+
+func (customTxProcessor) GenerateSimulationResults(txEnvelop *common.Envelope, simulator ledger.TxSimulator, initializingLedger bool) error {
+	return nil
+}
+
+func TestValidateCustomTransaction(t *testing.T) {
+	const customType = common.HeaderType(100)
+	err := customtx.Register(customType, customTxProcessor{})
+	assert.NoError(t, err)
+
+	newEnvelope := func(txType common.HeaderType) *common.Envelope {
+		nonce := utils.CreateNonceOrPanic()
+		txID, err := utils.ComputeProposalTxID(nonce, signerSerialized)
+		assert.NoError(t, err)
+		env := &common.Envelope{
+			Payload: utils.MarshalOrPanic(&common.Payload{
+				Header: &common.Header{
+					ChannelHeader: utils.MarshalOrPanic(&common.ChannelHeader{
+						Type:      int32(txType),
+						ChannelId: util.GetTestChainID(),
+						TxId:      txID,
+					}),
+					SignatureHeader: utils.MarshalOrPanic(&common.SignatureHeader{
+						Creator: signerSerialized,
+						Nonce:   nonce,
+					}),
+				},
+				Data: []byte("custom data"),
+			}),
+		}
+		env.Signature, err = signer.Sign(env.Payload)
+		assert.NoError(t, err)
+		return env
+	}
+
+	payload, txResult := ValidateTransaction(newEnvelope(customType), &config.MockApplicationCapabilities{})
+	assert.Equal(t, peer.TxValidationCode_VALID, txResult)
+	assert.Equal(t, []byte("custom data"), payload.Data)
+
+	// the transaction must be signed by its creator
+	env := newEnvelope(customType)
+	env.Signature = []byte("bad signature")
+	_, txResult = ValidateTransaction(env, &config.MockApplicationCapabilities{})
+	assert.Equal(t, peer.TxValidationCode_BAD_CREATOR_SIGNATURE, txResult)
+
+	// the ID of the transaction must be derived from its nonce and creator
+	env = newEnvelope(customType)
+	payload = &common.Payload{}
+	assert.NoError(t, proto.Unmarshal(env.Payload, payload))
+	payload.Header.ChannelHeader = utils.MarshalOrPanic(&common.ChannelHeader{
+		Type:      int32(customType),
+		ChannelId: util.GetTestChainID(),
+		TxId:      "not the hash of the nonce and creator",
+	})
+	env.Payload = utils.MarshalOrPanic(payload)
+	env.Signature, err = signer.Sign(env.Payload)
+	assert.NoError(t, err)
+	_, txResult = ValidateTransaction(env, &config.MockApplicationCapabilities{})
+	assert.Equal(t, peer.TxValidationCode_BAD_PROPOSAL_TXID, txResult)
+
+	// the types without processor are rejected
+	_, txResult = ValidateTransaction(newEnvelope(common.HeaderType(101)), &config.MockApplicationCapabilities{})
+	assert.Equal(t, peer.TxValidationCode_BAD_COMMON_HEADER, txResult)
+}
@@ -0,0 +1,238 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package reload lets a whitelisted subset of the configuration of the peer
+// be read again from core.yaml and the environment while the peer runs.
+//
+// The global Viper instance is not safe for concurrent writes, so the
+// reloaded values are kept by the registry rather than set on Viper. The
+// modules using a reloadable key read it through the getters of this package
+// every time they need it, or subscribe to its changes.
+package reload
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+)
+
+var logger = flogging.MustGetLogger("config.reload")
+
+// Reloadable lists the keys of the peer configuration which can be changed
+// at runtime
+var Reloadable = []string{
+	"logging.level",
+	"peer.profile.enabled",
+	"peer.gossip.deepHistoryDepth",
+	"peer.gossip.pvtData.pullRetryThreshold",
+	"peer.gossip.pvtData.pushAckTimeout",
+	"peer.gossip.pvtData.btlPullMargin",
+	"peer.deliveryclient.reconnectTotalTimeThreshold",
+	"peer.deliveryclient.connTimeout",
+	"peer.deliveryclient.reConnectBackoffThreshold",
+	"peer.deliveryclient.endpointSelectionPolicy",
+	"peer.deliveryclient.dnsRefreshInterval",
+	"ledger.state.totalQueryLimit",
+	"ledger.state.couchDBConfig.internalQueryLimit",
+	"ledger.state.couchDBConfig.maxBatchUpdateSize",
+	"ledger.state.couchDBConfig.autoWarmIndexes",
+	"ledger.state.couchDBConfig.warmIndexesAfterNBlocks",
+}
+
+// Listener is invoked with the new value of a key after a reload changed it
+type Listener func(value interface{})
+
+// Registry keeps the reloaded values of a set of configuration keys and
+// notifies the listeners subscribed to their changes
+type Registry struct {
+	envPrefix string
+	keys      []string
+
+	lock      sync.RWMutex
+	values    map[string]interface{}
+	listeners map[string]map[int]Listener
+	nextID    int
+
+	// configFile returns the path of the configuration file to read again
+	configFile func() string
+}
+
+// NewRegistry returns a registry of the supplied keys, whose values are read
+// from the configuration file of the global Viper instance and from the
+// environment variables with the supplied prefix
+func NewRegistry(envPrefix string, keys []string) *Registry {
+	sortedKeys := append([]string(nil), keys...)
+	sort.Strings(sortedKeys)
+	return &Registry{
+		envPrefix:  envPrefix,
+		keys:       sortedKeys,
+		values:     make(map[string]interface{}),
+		listeners:  make(map[string]map[int]Listener),
+		configFile: viper.ConfigFileUsed,
+	}
+}
+
+func (r *Registry) isReloadable(key string) bool {
+	i := sort.SearchStrings(r.keys, key)
+	return i < len(r.keys) && r.keys[i] == key
+}
+
+// Subscribe registers a listener for the changes of a reloadable key, and
+// returns the function which unregisters it
+func (r *Registry) Subscribe(key string, listener Listener) (func(), error) {
+	if !r.isReloadable(key) {
+		return nil, errors.Errorf("configuration key %s is not reloadable", key)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.listeners[key] == nil {
+		r.listeners[key] = make(map[int]Listener)
+	}
+	id := r.nextID
+	r.nextID++
+	r.listeners[key][id] = listener
+
+	return func() {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		delete(r.listeners[key], id)
+	}, nil
+}
+
+// Get returns the reloaded value of the key if it was changed by a reload,
+// or its value in the global Viper instance otherwise
+func (r *Registry) Get(key string) interface{} {
+	r.lock.RLock()
+	value, reloaded := r.values[key]
+	r.lock.RUnlock()
+	if reloaded {
+		return value
+	}
+	return viper.Get(key)
+}
+
+// IsSet tells whether the key has a value
+func (r *Registry) IsSet(key string) bool {
+	r.lock.RLock()
+	value, reloaded := r.values[key]
+	r.lock.RUnlock()
+	if reloaded {
+		return value != nil
+	}
+	return viper.IsSet(key)
+}
+
+// Reload reads the configuration again, records the new values of the
+// reloadable keys and notifies their listeners. It returns the keys whose
+// value changed.
+func (r *Registry) Reload() ([]string, error) {
+	configFile := r.configFile()
+	if configFile == "" {
+		return nil, errors.New("no configuration file to reload")
+	}
+	v := viper.New()
+	v.SetConfigFile(configFile)
+	v.SetEnvPrefix(r.envPrefix)
+	v.AutomaticEnv()
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	if err := v.ReadInConfig(); err != nil {
+		return nil, errors.Wrapf(err, "failed reading configuration file %s", configFile)
+	}
+
+	type notification struct {
+		value     interface{}
+		listeners []Listener
+	}
+	var changed []string
+	var notifications []notification
+
+	r.lock.Lock()
+	for _, key := range r.keys {
+		value := v.Get(key)
+		current, reloaded := r.values[key]
+		if !reloaded {
+			current = viper.Get(key)
+		}
+		if reflect.DeepEqual(value, current) {
+			continue
+		}
+		r.values[key] = value
+		changed = append(changed, key)
+		n := notification{value: value}
+		for _, listener := range r.listeners[key] {
+			n.listeners = append(n.listeners, listener)
+		}
+		notifications = append(notifications, n)
+		logger.Infof("Configuration key %s changed from %v to %v", key, current, value)
+	}
+	r.lock.Unlock()
+
+	// The listeners are invoked without holding the lock, so that they can
+	// read the configuration through the registry
+	for _, n := range notifications {
+		for _, listener := range n.listeners {
+			listener(n.value)
+		}
+	}
+	return changed, nil
+}
+
+var global = NewRegistry("CORE", Reloadable)
+
+// Subscribe registers a listener for the changes of a reloadable key of the
+// peer configuration, and returns the function which unregisters it
+func Subscribe(key string, listener Listener) (func(), error) {
+	return global.Subscribe(key, listener)
+}
+
+// Reload reads the peer configuration again and returns the reloadable keys
+// whose value changed
+func Reload() ([]string, error) {
+	return global.Reload()
+}
+
+// Get returns the current value of a key of the peer configuration
+func Get(key string) interface{} {
+	return global.Get(key)
+}
+
+// IsSet tells whether a key of the peer configuration has a value
+func IsSet(key string) bool {
+	return global.IsSet(key)
+}
+
+// GetString returns the current value of a key of the peer configuration as a string
+func GetString(key string) string {
+	return cast.ToString(Get(key))
+}
+
+// GetBool returns the current value of a key of the peer configuration as a bool
+func GetBool(key string) bool {
+	return cast.ToBool(Get(key))
+}
+
+// GetInt returns the current value of a key of the peer configuration as an int
+func GetInt(key string) int {
+	return cast.ToInt(Get(key))
+}
+
+// GetFloat64 returns the current value of a key of the peer configuration as a float64
+func GetFloat64(key string) float64 {
+	return cast.ToFloat64(Get(key))
+}
+
+// GetDuration returns the current value of a key of the peer configuration as a duration
+func GetDuration(key string) time.Duration {
+	return cast.ToDuration(Get(key))
+}
@@ -0,0 +1,116 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package reload
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRegistry(t *testing.T) (*Registry, func(config string), func()) {
+	dir, err := ioutil.TempDir("", "reload")
+	require.NoError(t, err)
+	configFile := filepath.Join(dir, "core.yaml")
+	write := func(config string) {
+		require.NoError(t, ioutil.WriteFile(configFile, []byte(config), 0644))
+	}
+
+	r := NewRegistry("RELOADTEST", []string{"peer.gossip.deepHistoryDepth", "logging.level"})
+	r.configFile = func() string { return configFile }
+	return r, write, func() { os.RemoveAll(dir) }
+}
+
+func TestReload(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("peer.gossip.deepHistoryDepth", 10)
+	viper.Set("peer.gossip.pvtData.pushAckTimeout", "3s")
+
+	r, write, cleanup := newTestRegistry(t)
+	defer cleanup()
+
+	// the values of the global Viper instance are used until a reload
+	assert.Equal(t, 10, r.Get("peer.gossip.deepHistoryDepth"))
+	assert.False(t, r.IsSet("logging.level"))
+
+	var notified []interface{}
+	unsubscribe, err := r.Subscribe("peer.gossip.deepHistoryDepth", func(value interface{}) {
+		notified = append(notified, value)
+	})
+	require.NoError(t, err)
+
+	write("logging:\n  level: debug\npeer:\n  gossip:\n    deepHistoryDepth: 10\n    pvtData:\n      pushAckTimeout: 1s\n")
+	changed, err := r.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"logging.level"}, changed)
+	assert.Equal(t, "debug", r.Get("logging.level"))
+	assert.True(t, r.IsSet("logging.level"))
+	assert.Empty(t, notified)
+	// keys which are not reloadable are left untouched
+	assert.Equal(t, "3s", r.Get("peer.gossip.pvtData.pushAckTimeout"))
+
+	write("logging:\n  level: debug\npeer:\n  gossip:\n    deepHistoryDepth: 20\n")
+	changed, err = r.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"peer.gossip.deepHistoryDepth"}, changed)
+	assert.Equal(t, []interface{}{20}, notified)
+
+	// the environment overrides the configuration file
+	os.Setenv("RELOADTEST_PEER_GOSSIP_DEEPHISTORYDEPTH", "30")
+	defer os.Unsetenv("RELOADTEST_PEER_GOSSIP_DEEPHISTORYDEPTH")
+	changed, err = r.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"peer.gossip.deepHistoryDepth"}, changed)
+	assert.Equal(t, []interface{}{20, "30"}, notified)
+
+	unsubscribe()
+	os.Setenv("RELOADTEST_PEER_GOSSIP_DEEPHISTORYDEPTH", "40")
+	changed, err = r.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"peer.gossip.deepHistoryDepth"}, changed)
+	assert.Len(t, notified, 2)
+}
+
+func TestReloadErrors(t *testing.T) {
+	r, write, cleanup := newTestRegistry(t)
+	defer cleanup()
+
+	_, err := r.Subscribe("peer.address", func(interface{}) {})
+	assert.EqualError(t, err, "configuration key peer.address is not reloadable")
+
+	_, err = r.Reload()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed reading configuration file")
+
+	write("peer: [")
+	_, err = r.Reload()
+	assert.Error(t, err)
+
+	r.configFile = func() string { return "" }
+	_, err = r.Reload()
+	assert.EqualError(t, err, "no configuration file to reload")
+}
+
+func TestGetters(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("peer.gossip.pvtData.pushAckTimeout", "3s")
+	viper.Set("peer.gossip.pvtData.btlPullMargin", "10")
+	viper.Set("ledger.state.couchDBConfig.autoWarmIndexes", "true")
+
+	assert.Equal(t, 3*time.Second, GetDuration("peer.gossip.pvtData.pushAckTimeout"))
+	assert.Equal(t, 10, GetInt("peer.gossip.pvtData.btlPullMargin"))
+	assert.Equal(t, float64(10), GetFloat64("peer.gossip.pvtData.btlPullMargin"))
+	assert.Equal(t, "10", GetString("peer.gossip.pvtData.btlPullMargin"))
+	assert.True(t, GetBool("ledger.state.couchDBConfig.autoWarmIndexes"))
+	assert.True(t, IsSet("ledger.state.couchDBConfig.autoWarmIndexes"))
+}
@@ -13,13 +13,11 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
+	"regexp"
 	"strings"
+	"sync"
 	"time"
 
-	"bufio"
-
-	"regexp"
-
 	"github.com/fsouza/go-dockerclient"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/util"
@@ -270,7 +268,8 @@ func (vm *DockerVM) Start(ccid ccintf.CCID,
 		// Launch a few go-threads to manage output streams from the container.
 		// They will be automatically destroyed when the container exits
 		attached := make(chan struct{})
-		r, w := io.Pipe()
+		stdoutR, stdoutW := io.Pipe()
+		stderrR, stderrW := io.Pipe()
 
 		go func() {
 			// AttachToContainer will fire off a message on the "attached" channel once the
@@ -279,8 +278,8 @@ func (vm *DockerVM) Start(ccid ccintf.CCID,
 			// error to a local variable to prevent clobbering the function variable 'err'.
 			err := client.AttachToContainer(docker.AttachToContainerOptions{
 				Container:    containerName,
-				OutputStream: w,
-				ErrorStream:  w,
+				OutputStream: stdoutW,
+				ErrorStream:  stderrW,
 				Logs:         true,
 				Stdout:       true,
 				Stderr:       true,
@@ -288,9 +287,10 @@ func (vm *DockerVM) Start(ccid ccintf.CCID,
 				Success:      attached,
 			})
 
-			// If we get here, the container has terminated.  Send a signal on the pipe
+			// If we get here, the container has terminated.  Send a signal on the pipes
 			// so that downstream may clean up appropriately
-			_ = w.CloseWithError(err)
+			_ = stdoutW.CloseWithError(err)
+			_ = stderrW.CloseWithError(err)
 		}()
 
 		go func() {
@@ -308,31 +308,21 @@ func (vm *DockerVM) Start(ccid ccintf.CCID,
 			// appear to hurt anything.
 			attached <- struct{}{}
 
-			// Establish a buffer for our IO channel so that we may do readline-style
-			// ingestion of the IO, one log entry per line
-			is := bufio.NewReader(r)
-
-			// Acquire a custom logger for our chaincode, inheriting the level from the peer
-			containerLogger := flogging.MustGetLogger(containerName)
-			flogging.SetModuleLevel(flogging.GetModuleLevel("peer"), containerName)
-
-			for {
-				// Loop forever dumping lines of text into the containerLogger
-				// until the pipe is closed
-				line, err2 := is.ReadString('\n')
-				if err2 != nil {
-					switch err2 {
-					case io.EOF:
-						dockerLogger.Infof("Container %s has closed its IO channel", containerName)
-					default:
-						dockerLogger.Errorf("Error reading container output: %s", err2)
-					}
-
-					return
-				}
-
-				containerLogger.Info(line)
-			}
+			// Log the standard output of the container at the info level and its
+			// standard error at the warning level, one log entry per line
+			logs := newContainerLogs(ccid, containerName)
+			defer logs.close()
+
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				logs.forward(stderrR, logs.logger.Warning)
+			}()
+			logs.forward(stdoutR, logs.logger.Info)
+			wg.Wait()
+
+			dockerLogger.Infof("Container %s has closed its IO channel", containerName)
 		}()
 	}
 
@@ -0,0 +1,130 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dockercontroller
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	"github.com/spf13/viper"
+)
+
+// containerLogs forwards the output of a chaincode container to the logger
+// of the chaincode, and to the log file of the container if the peer keeps one
+type containerLogs struct {
+	containerName string
+	logger        *flogging.FabricLogger
+
+	mutex sync.Mutex
+	file  *os.File
+}
+
+// ContainerLoggerName returns the name of the logger which the output of the
+// containers of the chaincode is logged with
+func ContainerLoggerName(ccid ccintf.CCID) string {
+	return "chaincode." + ccid.Name
+}
+
+// newContainerLogs returns the logs of the container of the chaincode, whose
+// logger level is set from the configuration
+func newContainerLogs(ccid ccintf.CCID, containerName string) *containerLogs {
+	loggerName := ContainerLoggerName(ccid)
+	flogging.SetModuleLevel(loggerName, containerLogLevel(ccid.Name))
+	l := &containerLogs{
+		containerName: containerName,
+		logger:        flogging.MustGetLogger(loggerName).With("container", containerName),
+	}
+
+	dir := viper.GetString("vm.docker.logging.directory")
+	if dir == "" {
+		return l
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		dockerLogger.Errorf("Could not create the directory of the chaincode logs %s: %s", dir, err)
+		return l
+	}
+	path := filepath.Join(dir, containerName+".log")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		dockerLogger.Errorf("Could not open the log file of container %s: %s", containerName, err)
+		return l
+	}
+	l.file = file
+	return l
+}
+
+// containerLogLevel returns the level of the logger of the chaincode: the
+// level configured for the chaincode, else the level configured for all the
+// chaincodes, else the level of the peer
+func containerLogLevel(ccName string) string {
+	// viper lower cases the keys of the maps
+	for name, level := range viper.GetStringMapString("vm.docker.logging.chaincodeLevels") {
+		if strings.EqualFold(name, ccName) {
+			if flogging.IsValidLevel(level) {
+				return level
+			}
+			dockerLogger.Warningf("Invalid log level %s for chaincode %s, ignoring it", level, ccName)
+		}
+	}
+
+	level := viper.GetString("vm.docker.logging.level")
+	if level == "" {
+		return flogging.GetModuleLevel("peer")
+	}
+	if !flogging.IsValidLevel(level) {
+		dockerLogger.Warningf("Invalid log level %s for the chaincodes, using the level of the peer", level)
+		return flogging.GetModuleLevel("peer")
+	}
+	return level
+}
+
+// forward logs every line read from the output stream of the container until
+// the stream is closed
+func (l *containerLogs) forward(r io.Reader, log func(args ...interface{})) {
+	is := bufio.NewReader(r)
+	for {
+		line, err := is.ReadString('\n')
+		if line != "" {
+			l.write(line, log)
+		}
+		if err != nil {
+			if err != io.EOF {
+				dockerLogger.Errorf("Error reading container output: %s", err)
+			}
+			return
+		}
+	}
+}
+
+func (l *containerLogs) write(line string, log func(args ...interface{})) {
+	log(strings.TrimSuffix(line, "\n"))
+	if l.file == nil {
+		return
+	}
+
+	if !strings.HasSuffix(line, "\n") {
+		line += "\n"
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if _, err := l.file.WriteString(line); err != nil {
+		dockerLogger.Errorf("Could not write to the log file of container %s: %s", l.containerName, err)
+	}
+}
+
+// close closes the log file of the container
+func (l *containerLogs) close() {
+	if l.file != nil {
+		l.file.Close()
+	}
+}
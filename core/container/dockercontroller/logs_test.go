@@ -0,0 +1,65 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dockercontroller
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerLogLevel(t *testing.T) {
+	defer viper.Reset()
+	defer flogging.RestoreLevels(flogging.GetModuleLevels())
+
+	flogging.SetModuleLevel("peer", "warning")
+	assert.Equal(t, "WARN", containerLogLevel("mycc"))
+
+	viper.Set("vm.docker.logging.level", "error")
+	assert.Equal(t, "error", containerLogLevel("mycc"))
+
+	viper.Set("vm.docker.logging.chaincodeLevels", map[string]string{"mycc": "debug", "othercc": "foo"})
+	assert.Equal(t, "debug", containerLogLevel("MyCC"))
+	assert.Equal(t, "error", containerLogLevel("othercc"))
+
+	viper.Set("vm.docker.logging.level", "foo")
+	assert.Equal(t, "WARN", containerLogLevel("anothercc"))
+}
+
+func TestContainerLogs(t *testing.T) {
+	defer viper.Reset()
+	defer flogging.RestoreLevels(flogging.GetModuleLevels())
+
+	dir, err := ioutil.TempDir("", "chaincode-logs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	viper.Set("vm.docker.logging.directory", filepath.Join(dir, "logs"))
+	viper.Set("vm.docker.logging.level", "debug")
+
+	ccid := ccintf.CCID{Name: "mycc", Version: "1.0"}
+	assert.Equal(t, "chaincode.mycc", ContainerLoggerName(ccid))
+	logs := newContainerLogs(ccid, "peer0-mycc-1.0")
+	assert.Equal(t, "DEBUG", flogging.GetModuleLevel("chaincode.mycc"))
+
+	var logged []string
+	log := func(args ...interface{}) { logged = append(logged, args[0].(string)) }
+	logs.forward(strings.NewReader("first line\nsecond line\nlast line"), log)
+	logs.close()
+	assert.Equal(t, []string{"first line", "second line", "last line"}, logged)
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "logs", "peer0-mycc-1.0.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "first line\nsecond line\nlast line\n", string(content))
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/gossip/api"
 	gossipcommon "github.com/hyperledger/fabric/gossip/common"
 	"github.com/hyperledger/fabric/gossip/discovery"
@@ -94,6 +95,9 @@ type blocksProviderImpl struct {
 
 	mcs api.MessageCryptoService
 
+	// rejectedBlocks counts the blocks which failed the verification
+	rejectedBlocks metrics.Counter
+
 	done int32
 
 	wrongStatusThreshold int
@@ -104,13 +108,19 @@ const wrongStatusThreshold = 10
 var maxRetryDelay = time.Second * 10
 var logger = flogging.MustGetLogger("blocksProvider")
 
-// NewBlocksProvider constructor function to create blocks deliverer instance
-func NewBlocksProvider(chainID string, client streamClient, gossip GossipServiceAdapter, mcs api.MessageCryptoService) BlocksProvider {
+// NewBlocksProvider constructor function to create blocks deliverer instance.
+// The blocks rejected by the verification are counted in the supplied metrics
+// scope, or discarded if it is nil.
+func NewBlocksProvider(chainID string, client streamClient, gossip GossipServiceAdapter, mcs api.MessageCryptoService, metricsScope metrics.Scope) BlocksProvider {
+	if metricsScope == nil {
+		metricsScope = metrics.NewNoOpScope()
+	}
 	return &blocksProviderImpl{
 		chainID:              chainID,
 		client:               client,
 		gossip:               gossip,
 		mcs:                  mcs,
+		rejectedBlocks:       metricsScope.Tagged(map[string]string{"channel": chainID}).Counter("blocks_rejected"),
 		wrongStatusThreshold: wrongStatusThreshold,
 	}
 }
@@ -167,7 +177,11 @@ func (b *blocksProviderImpl) DeliverBlocks() {
 				continue
 			}
 			if err := b.mcs.VerifyBlock(gossipcommon.ChainID(b.chainID), blockNum, marshaledBlock); err != nil {
-				logger.Errorf("[%s] Error verifying block with sequnce number %d, due to %s", b.chainID, blockNum, err)
+				logger.Errorf("[%s] Rejecting block with sequence number %d, the verification of its signatures against the orderers of the channel failed: %s", b.chainID, blockNum, err)
+				b.rejectedBlocks.Inc(1)
+				// The ordering service node which sent the block is not trusted anymore,
+				// the block is requested again once connected to another one
+				b.client.Disconnect(true)
 				continue
 			}
 
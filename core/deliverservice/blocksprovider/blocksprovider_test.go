@@ -69,9 +69,12 @@ type rcvFunc func(mock *mocks.MockBlocksDeliverer) (*orderer.DeliverResponse, er
 func makeTestCase(ledgerHeight uint64, mcs api.MessageCryptoService, shouldSucceed bool, rcv rcvFunc) func(*testing.T) {
 	return func(t *testing.T) {
 		gossipServiceAdapter := &mocks.MockGossipServiceAdapter{GossipBlockDisseminations: make(chan uint64)}
-		deliverer := &mocks.MockBlocksDeliverer{Pos: ledgerHeight}
+		deliverer := &mocks.MockBlocksDeliverer{
+			Pos:                        ledgerHeight,
+			DisconnectAndDisableCalled: make(chan struct{}, 100),
+		}
 		deliverer.MockRecv = rcv
-		provider := NewBlocksProvider("***TEST_CHAINID***", deliverer, gossipServiceAdapter, mcs)
+		provider := NewBlocksProvider("***TEST_CHAINID***", deliverer, gossipServiceAdapter, mcs, nil)
 		defer provider.Stop()
 		ready := make(chan struct{})
 		go func() {
@@ -443,3 +446,55 @@ func TestBlockVerificationFailure(t *testing.T) {
 	mcs.On("VerifyBlock", mock.Anything).Return(errors.New("Invalid signature"))
 	makeTestCase(uint64(0), mcs, false, rcvr)(t)
 }
+
+type countingCounter struct {
+	count int64
+}
+
+func (c *countingCounter) Inc(delta int64) {
+	atomic.AddInt64(&c.count, delta)
+}
+
+func TestBlockVerificationFailureDisconnects(t *testing.T) {
+	bd := mocks.MockBlocksDeliverer{
+		DisconnectCalled:           make(chan struct{}, 100),
+		DisconnectAndDisableCalled: make(chan struct{}, 100),
+		CloseCalled:                make(chan struct{}, 1),
+	}
+	incomingMsgs := make(chan *orderer.DeliverResponse)
+	bd.MockRecv = func(mock *mocks.MockBlocksDeliverer) (*orderer.DeliverResponse, error) {
+		inMsg := <-incomingMsgs
+		return inMsg, nil
+	}
+	mcs := &mockMCS{}
+	mcs.On("VerifyBlock", mock.Anything).Return(errors.New("Invalid signature"))
+	gossipServiceAdapter := &mocks.MockGossipServiceAdapter{GossipBlockDisseminations: make(chan uint64, 1)}
+	rejectedBlocks := &countingCounter{}
+	provider := &blocksProviderImpl{
+		chainID:              "***TEST_CHAINID***",
+		gossip:               gossipServiceAdapter,
+		client:               &bd,
+		mcs:                  mcs,
+		rejectedBlocks:       rejectedBlocks,
+		wrongStatusThreshold: 5,
+	}
+	go provider.DeliverBlocks()
+	defer provider.Stop()
+
+	incomingMsgs <- &orderer.DeliverResponse{
+		Type: &orderer.DeliverResponse_Block{
+			Block: &common.Block{
+				Header: &common.BlockHeader{Number: 1},
+				Data:   &common.BlockData{Data: [][]byte{}},
+			}},
+	}
+
+	// the forged block is neither buffered nor gossiped, and the orderer which
+	// sent it is disabled
+	waitUntilOrFail(t, func() bool {
+		return len(bd.DisconnectAndDisableCalled) == 1
+	})
+	assert.Equal(t, int64(1), atomic.LoadInt64(&rejectedBlocks.count))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&gossipServiceAdapter.AddPayloadsCnt))
+	assert.Len(t, gossipServiceAdapter.GossipBlockDisseminations, 0)
+}
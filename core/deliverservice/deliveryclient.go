@@ -15,13 +15,16 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/core/config/reload"
 	"github.com/hyperledger/fabric/core/deliverservice/blocksprovider"
 	"github.com/hyperledger/fabric/gossip/api"
 	"github.com/hyperledger/fabric/gossip/util"
 	"github.com/hyperledger/fabric/protos/orderer"
 	"github.com/spf13/viper"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 var logger = flogging.MustGetLogger("deliveryClient")
@@ -40,10 +43,25 @@ func getConnectionTimeout() time.Duration {
 	return util.GetDurationOrDefault("peer.deliveryclient.connTimeout", defaultConnectionTimeout)
 }
 
+// getDNSRefreshInterval returns the interval at which the host names of the
+// endpoints connected to are resolved again, zero if they aren't
+func getDNSRefreshInterval() time.Duration {
+	return util.GetDurationOrDefault("peer.deliveryclient.dnsRefreshInterval", 0)
+}
+
 func getReConnectBackoffThreshold() float64 {
 	return util.GetFloat64OrDefault("peer.deliveryclient.reConnectBackoffThreshold", defaultReConnectBackoffThreshold)
 }
 
+func getEndpointSelectionPolicy() comm.EndpointSelectionPolicy {
+	policy, err := comm.ParseEndpointSelectionPolicy(reload.GetString("peer.deliveryclient.endpointSelectionPolicy"))
+	if err != nil {
+		logger.Warningf("%s, defaulting to %s", err, comm.RandomSelection)
+		return comm.RandomSelection
+	}
+	return policy
+}
+
 // DeliverService used to communicate with orderers to obtain
 // new blocks and send them to the committer service
 type DeliverService interface {
@@ -90,6 +108,9 @@ type Config struct {
 	Gossip blocksprovider.GossipServiceAdapter
 	// Endpoints specifies the endpoints of the ordering service
 	Endpoints []string
+	// Metrics is the scope in which the blocks providers report their metrics,
+	// they are discarded if it is nil
+	Metrics metrics.Scope
 }
 
 // NewDeliverService construction function to create and initialize
@@ -157,7 +178,7 @@ func (d *deliverServiceImpl) StartDeliverForChannel(chainID string, ledgerInfo b
 	} else {
 		client := d.newClient(chainID, ledgerInfo)
 		logger.Debug("This peer will pass blocks from orderer service to other peers for channel", chainID)
-		d.blockProviders[chainID] = blocksprovider.NewBlocksProvider(chainID, client, d.conf.Gossip, d.conf.CryptoSvc)
+		d.blockProviders[chainID] = blocksprovider.NewBlocksProvider(chainID, client, d.conf.Gossip, d.conf.CryptoSvc, d.conf.Metrics)
 		go d.launchBlockProvider(chainID, finalizer)
 	}
 	return nil
@@ -209,8 +230,6 @@ func (d *deliverServiceImpl) Stop() {
 }
 
 func (d *deliverServiceImpl) newClient(chainID string, ledgerInfoProvider blocksprovider.LedgerInfo) *broadcastClient {
-	reconnectBackoffThreshold := getReConnectBackoffThreshold()
-	reconnectTotalTimeThreshold := getReConnectTotalTimeThreshold()
 	requester := &blocksRequester{
 		tls:     viper.GetBool("peer.tls.enabled"),
 		chainID: chainID,
@@ -218,21 +237,41 @@ func (d *deliverServiceImpl) newClient(chainID string, ledgerInfoProvider blocks
 	broadcastSetup := func(bd blocksprovider.BlocksDeliverer) error {
 		return requester.RequestBlocks(ledgerInfoProvider)
 	}
+	// The thresholds are read on every attempt, so that the changes of the
+	// configuration apply to the clients which are already running
 	backoffPolicy := func(attemptNum int, elapsedTime time.Duration) (time.Duration, bool) {
-		if elapsedTime > reconnectTotalTimeThreshold {
+		if elapsedTime > getReConnectTotalTimeThreshold() {
 			return 0, false
 		}
 		sleepIncrement := float64(time.Millisecond * 500)
 		attempt := float64(attemptNum)
-		return time.Duration(math.Min(math.Pow(2, attempt)*sleepIncrement, reconnectBackoffThreshold)), true
+		return time.Duration(math.Min(math.Pow(2, attempt)*sleepIncrement, getReConnectBackoffThreshold())), true
 	}
-	connProd := comm.NewConnectionProducer(d.conf.ConnFactory(chainID), d.conf.Endpoints)
+	connProd := comm.NewConnectionProducerWithPolicy(d.conf.ConnFactory(chainID), d.conf.Endpoints, getEndpointSelectionPolicy())
 	bClient := NewBroadcastClient(connProd, d.conf.ABCFactory, broadcastSetup, backoffPolicy)
 	requester.client = bClient
 	return bClient
 }
 
 func DefaultConnectionFactory(channelID string) func(endpoint string) (*grpc.ClientConn, error) {
+	return connectionFactory(func() (credentials.TransportCredentials, error) {
+		creds, err := comm.GetCredentialSupport().GetDeliverServiceCredentials(channelID)
+		if err != nil {
+			return nil, fmt.Errorf("failed obtaining credentials for channel %s: %v", channelID, err)
+		}
+		return creds, nil
+	})
+}
+
+// PeerConnectionFactory returns a function that creates a connection to a
+// peer, whose TLS certificate is issued by a root CA of the channels
+func PeerConnectionFactory(channelID string) func(endpoint string) (*grpc.ClientConn, error) {
+	return connectionFactory(func() (credentials.TransportCredentials, error) {
+		return comm.GetCredentialSupport().GetPeerCredentials(), nil
+	})
+}
+
+func connectionFactory(tlsCredentials func() (credentials.TransportCredentials, error)) func(endpoint string) (*grpc.ClientConn, error) {
 	return func(endpoint string) (*grpc.ClientConn, error) {
 		dialOpts := []grpc.DialOption{grpc.WithBlock()}
 		// set max send/recv msg sizes
@@ -251,17 +290,27 @@ func DefaultConnectionFactory(channelID string) func(endpoint string) (*grpc.Cli
 		dialOpts = append(dialOpts, comm.ClientKeepaliveOptions(kaOpts)...)
 
 		if viper.GetBool("peer.tls.enabled") {
-			creds, err := comm.GetCredentialSupport().GetDeliverServiceCredentials(channelID)
+			creds, err := tlsCredentials()
 			if err != nil {
-				return nil, fmt.Errorf("failed obtaining credentials for channel %s: %v", channelID, err)
+				return nil, err
 			}
 			dialOpts = append(dialOpts, grpc.WithTransportCredentials(creds))
 		} else {
 			dialOpts = append(dialOpts, grpc.WithInsecure())
 		}
+		// the connection is closed, and then dialed again, once the endpoint
+		// no longer resolves to the IP address it is connected to
+		dnsRefresh, watchDNS := comm.WithDNSRefresh(endpoint, getDNSRefreshInterval())
+		dialOpts = append(dialOpts, dnsRefresh)
+
 		ctx, cancel := context.WithTimeout(context.Background(), getConnectionTimeout())
 		defer cancel()
-		return grpc.DialContext(ctx, endpoint, dialOpts...)
+		conn, err := grpc.DialContext(ctx, endpoint, dialOpts...)
+		if err != nil {
+			return nil, err
+		}
+		watchDNS(conn)
+		return conn, nil
 	}
 }
 
@@ -0,0 +1,64 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliverclient
+
+import (
+	"context"
+
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// PeerABCFactory creates an AtomicBroadcastClient out of a connection to a
+// peer, whose Deliver service serves the blocks of its ledger along with the
+// validation codes of their transactions. The read replicas are delivered the
+// blocks validated by their committer through it.
+func PeerABCFactory(conn *grpc.ClientConn) orderer.AtomicBroadcastClient {
+	return &peerABCClient{client: peer.NewDeliverClient(conn)}
+}
+
+type peerABCClient struct {
+	client peer.DeliverClient
+}
+
+// Broadcast is not supported by the peers
+func (c *peerABCClient) Broadcast(ctx context.Context, opts ...grpc.CallOption) (orderer.AtomicBroadcast_BroadcastClient, error) {
+	return nil, errors.New("peers don't support broadcast")
+}
+
+// Deliver opens a stream of the blocks of the peer
+func (c *peerABCClient) Deliver(ctx context.Context, opts ...grpc.CallOption) (orderer.AtomicBroadcast_DeliverClient, error) {
+	stream, err := c.client.Deliver(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &peerDeliverStream{Deliver_DeliverClient: stream}, nil
+}
+
+// peerDeliverStream presents the responses of the Deliver service of a peer
+// as the responses of the ordering service
+type peerDeliverStream struct {
+	peer.Deliver_DeliverClient
+}
+
+// Recv receives the next response of the peer
+func (s *peerDeliverStream) Recv() (*orderer.DeliverResponse, error) {
+	resp, err := s.Deliver_DeliverClient.Recv()
+	if err != nil {
+		return nil, err
+	}
+	switch t := resp.Type.(type) {
+	case *peer.DeliverResponse_Status:
+		return &orderer.DeliverResponse{Type: &orderer.DeliverResponse_Status{Status: t.Status}}, nil
+	case *peer.DeliverResponse_Block:
+		return &orderer.DeliverResponse{Type: &orderer.DeliverResponse_Block{Block: t.Block}}, nil
+	default:
+		return nil, errors.Errorf("unexpected response of type %T from the Deliver service of the peer", resp.Type)
+	}
+}
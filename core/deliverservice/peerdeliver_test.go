@@ -0,0 +1,55 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliverclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type peerDeliverClient struct {
+	peer.Deliver_DeliverClient
+	responses []*peer.DeliverResponse
+}
+
+func (c *peerDeliverClient) Recv() (*peer.DeliverResponse, error) {
+	if len(c.responses) == 0 {
+		return nil, errors.New("stream closed")
+	}
+	resp := c.responses[0]
+	c.responses = c.responses[1:]
+	return resp, nil
+}
+
+func TestPeerDeliverStream(t *testing.T) {
+	block := &common.Block{Header: &common.BlockHeader{Number: 5}}
+	stream := &peerDeliverStream{Deliver_DeliverClient: &peerDeliverClient{responses: []*peer.DeliverResponse{
+		{Type: &peer.DeliverResponse_Block{Block: block}},
+		{Type: &peer.DeliverResponse_Status{Status: common.Status_SUCCESS}},
+		{Type: &peer.DeliverResponse_FilteredBlock{FilteredBlock: &peer.FilteredBlock{Number: 5}}},
+	}}}
+
+	resp, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, &orderer.DeliverResponse{Type: &orderer.DeliverResponse_Block{Block: block}}, resp)
+	resp, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, &orderer.DeliverResponse{Type: &orderer.DeliverResponse_Status{Status: common.Status_SUCCESS}}, resp)
+	_, err = stream.Recv()
+	assert.EqualError(t, err, "unexpected response of type *peer.DeliverResponse_FilteredBlock from the Deliver service of the peer")
+	_, err = stream.Recv()
+	assert.EqualError(t, err, "stream closed")
+
+	_, err = (&peerABCClient{}).Broadcast(context.Background())
+	assert.EqualError(t, err, "peers don't support broadcast")
+}
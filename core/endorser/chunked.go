@@ -0,0 +1,58 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"io"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+// ChunkedEndorser provides the ChunkedEndorser service, which reassembles
+// signed proposals too large to fit in a single gRPC message and processes
+// them through the Endorser service
+type ChunkedEndorser struct {
+	// Endorser is the Endorser service the reassembled proposals are
+	// processed by
+	Endorser pb.EndorserServer
+	// MaxProposalSize is the maximum size in bytes of a reassembled signed
+	// proposal, 0 meaning no limit
+	MaxProposalSize int
+}
+
+// ProcessProposal receives the chunks of a signed proposal until the client
+// closes its side of the stream, and sends back the response of the endorser
+func (ce *ChunkedEndorser) ProcessProposal(stream pb.ChunkedEndorser_ProcessProposalServer) error {
+	var signedPropBytes []byte
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed receiving proposal chunk")
+		}
+		if ce.MaxProposalSize > 0 && len(signedPropBytes)+len(chunk.Content) > ce.MaxProposalSize {
+			return errors.Errorf("chunked proposal exceeds the limit of %d bytes", ce.MaxProposalSize)
+		}
+		signedPropBytes = append(signedPropBytes, chunk.Content...)
+	}
+
+	endorserLogger.Debugf("Received chunked proposal of %d bytes", len(signedPropBytes))
+	signedProp := &pb.SignedProposal{}
+	if err := proto.Unmarshal(signedPropBytes, signedProp); err != nil {
+		return errors.Wrap(err, "failed unmarshaling chunked proposal")
+	}
+
+	resp, err := ce.Endorser.ProcessProposal(stream.Context(), signedProp)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(resp)
+}
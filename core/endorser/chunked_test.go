@@ -0,0 +1,115 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/endorser"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+type recordingEndorser struct {
+	received []*pb.SignedProposal
+	err      error
+}
+
+func (re *recordingEndorser) ProcessProposal(_ context.Context, signedProp *pb.SignedProposal) (*pb.ProposalResponse, error) {
+	re.received = append(re.received, signedProp)
+	if re.err != nil {
+		return nil, re.err
+	}
+	return &pb.ProposalResponse{Response: &pb.Response{Status: 200, Payload: signedProp.Signature}}, nil
+}
+
+func startChunkedEndorser(t *testing.T, ce *endorser.ChunkedEndorser) (pb.ChunkedEndorserClient, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	srv := grpc.NewServer()
+	pb.RegisterChunkedEndorserServer(srv, ce)
+	go srv.Serve(lis)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure(), grpc.WithBlock())
+	assert.NoError(t, err)
+	return pb.NewChunkedEndorserClient(conn), func() {
+		conn.Close()
+		srv.Stop()
+	}
+}
+
+func sendChunks(t *testing.T, client pb.ChunkedEndorserClient, data []byte, chunkSize int) (*pb.ProposalResponse, error) {
+	stream, err := client.ProcessProposal(context.Background())
+	assert.NoError(t, err)
+	for offset := 0; offset < len(data); offset += chunkSize {
+		end := offset + chunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		assert.NoError(t, stream.Send(&pb.SignedProposalChunk{Content: data[offset:end]}))
+	}
+	return stream.CloseAndRecv()
+}
+
+func TestChunkedEndorser(t *testing.T) {
+	signedProp := &pb.SignedProposal{
+		ProposalBytes: make([]byte, 1000),
+		Signature:     []byte("signature"),
+	}
+	signedPropBytes, err := proto.Marshal(signedProp)
+	assert.NoError(t, err)
+
+	t.Run("reassembled proposal", func(t *testing.T) {
+		re := &recordingEndorser{}
+		client, stop := startChunkedEndorser(t, &endorser.ChunkedEndorser{Endorser: re, MaxProposalSize: 2000})
+		defer stop()
+
+		resp, err := sendChunks(t, client, signedPropBytes, 100)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(200), resp.Response.Status)
+		assert.Equal(t, []byte("signature"), resp.Response.Payload)
+		assert.Len(t, re.received, 1)
+		assert.True(t, proto.Equal(signedProp, re.received[0]))
+	})
+
+	t.Run("proposal exceeding the limit", func(t *testing.T) {
+		re := &recordingEndorser{}
+		client, stop := startChunkedEndorser(t, &endorser.ChunkedEndorser{Endorser: re, MaxProposalSize: 500})
+		defer stop()
+
+		_, err := sendChunks(t, client, signedPropBytes, 100)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "chunked proposal exceeds the limit of 500 bytes")
+		assert.Empty(t, re.received)
+	})
+
+	t.Run("malformed proposal", func(t *testing.T) {
+		re := &recordingEndorser{}
+		client, stop := startChunkedEndorser(t, &endorser.ChunkedEndorser{Endorser: re})
+		defer stop()
+
+		_, err := sendChunks(t, client, []byte("this-is-a-bogus-proposal"), 5)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed unmarshaling chunked proposal")
+		assert.Empty(t, re.received)
+	})
+
+	t.Run("endorser failure", func(t *testing.T) {
+		re := &recordingEndorser{err: errors.New("endorser failed")}
+		client, stop := startChunkedEndorser(t, &endorser.ChunkedEndorser{Endorser: re})
+		defer stop()
+
+		_, err := sendChunks(t, client, signedPropBytes, 100)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "endorser failed")
+	})
+}
@@ -15,11 +15,14 @@ import (
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/tracing"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/chaincode/platforms"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/validation"
+	"github.com/hyperledger/fabric/core/handlers/auth"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
@@ -34,7 +37,7 @@ var endorserLogger = flogging.MustGetLogger("endorser")
 // The Jira issue that documents Endorser flow along with its relationship to
 // the lifecycle chaincode - https://jira.hyperledger.org/browse/FAB-181
 
-type privateDataDistributor func(channel string, txID string, privateData *transientstore.TxPvtReadWriteSetWithConfigInfo, blkHt uint64) error
+type privateDataDistributor func(channel string, txID string, privateData *transientstore.TxPvtReadWriteSetWithConfigInfo, blkHt uint64) (*pb.PrivateDataDissemination, error)
 
 // Support contains functions that the endorser requires to execute its tasks
 type Support interface {
@@ -72,6 +75,10 @@ type Support interface {
 	// SignedProposal from which an id can be extracted for testing against a policy
 	CheckACL(signedProp *pb.SignedProposal, chdr *common.ChannelHeader, shdr *common.SignatureHeader, hdrext *pb.ChaincodeHeaderExtension) error
 
+	// CheckClientACL checks the ACL for the resource for the channel using the
+	// signed data which authenticates the client a proposal is sent on behalf of
+	CheckClientACL(signedData []*common.SignedData, chdr *common.ChannelHeader) error
+
 	// IsJavaCC returns true if the CDS package bytes describe a chaincode
 	// that requires the java runtime environment to execute
 	IsJavaCC(buf []byte) (bool, error)
@@ -103,6 +110,26 @@ type Endorser struct {
 	s                     Support
 	PlatformRegistry      *platforms.Registry
 	PvtRWSetAssembler
+	// TransientMapMaxSize is the maximum total size in bytes of the keys and
+	// values of the transient map of a proposal, 0 meaning no limit
+	TransientMapMaxSize int
+	// Metrics is the scope of the latency histograms of the validation of
+	// the proposals, their simulation, the execution of the chaincode and
+	// the endorsement by ESCC, which are tagged with the channel and the
+	// chaincode. No metrics are emitted if it is nil
+	Metrics metrics.Scope
+	// ProposalQuota, when set, bounds the number of proposals of each channel
+	// processed concurrently
+	ProposalQuota ProposalQuota
+}
+
+// ProposalQuota bounds the number of proposals of a channel processed
+// concurrently
+type ProposalQuota interface {
+	// AcquireProposal reserves a slot for a proposal of the channel, which is
+	// freed by calling the returned function, or returns an error if the
+	// channel has no slot left
+	AcquireProposal(channelID string) (func(), error)
 }
 
 // validateResult provides the result of endorseProposal verification
@@ -125,6 +152,18 @@ func NewEndorserServer(privDist privateDataDistributor, s Support, pr *platforms
 	return e
 }
 
+// recordDuration records the time elapsed since start in the named latency
+// histogram of the channel and chaincode
+func (e *Endorser) recordDuration(name, chainID, ccName string, start time.Time) {
+	if e.Metrics == nil {
+		return
+	}
+	e.Metrics.Tagged(map[string]string{
+		"channel":   chainID,
+		"chaincode": ccName,
+	}).Histogram(name).RecordDuration(time.Since(start))
+}
+
 // call specified chaincode (system or user)
 func (e *Endorser) callChaincode(txParams *ccprovider.TransactionParams, version string, input *pb.ChaincodeInput, cid *pb.ChaincodeID) (*pb.Response, *pb.ChaincodeEvent, error) {
 	endorserLogger.Infof("[%s][%s] Entry chaincode: %s", txParams.ChannelID, shorttxid(txParams.TxID), cid)
@@ -139,7 +178,9 @@ func (e *Endorser) callChaincode(txParams *ccprovider.TransactionParams, version
 	var ccevent *pb.ChaincodeEvent
 
 	// is this a system chaincode
+	executeStart := time.Now()
 	res, ccevent, err = e.s.Execute(txParams, txParams.ChannelID, cid.Name, version, txParams.TxID, txParams.SignedProp, txParams.Proposal, input)
+	e.recordDuration("chaincode_execute_duration", txParams.ChannelID, cid.Name, executeStart)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -200,7 +241,7 @@ func (e *Endorser) SanitizeUserCDS(userCDS *pb.ChaincodeDeploymentSpec) (*pb.Cha
 }
 
 // SimulateProposal simulates the proposal by calling the chaincode
-func (e *Endorser) SimulateProposal(txParams *ccprovider.TransactionParams, cid *pb.ChaincodeID) (ccprovider.ChaincodeDefinition, *pb.Response, []byte, *pb.ChaincodeEvent, error) {
+func (e *Endorser) SimulateProposal(txParams *ccprovider.TransactionParams, cid *pb.ChaincodeID) (ccprovider.ChaincodeDefinition, *pb.Response, []byte, *pb.ChaincodeEvent, *pb.PrivateDataDissemination, error) {
 	endorserLogger.Debugf("[%s][%s] Entry chaincode: %s", txParams.ChannelID, shorttxid(txParams.TxID), cid)
 	defer endorserLogger.Debugf("[%s][%s] Exit", txParams.ChannelID, shorttxid(txParams.TxID))
 	// we do expect the payload to be a ChaincodeInvocationSpec
@@ -208,7 +249,7 @@ func (e *Endorser) SimulateProposal(txParams *ccprovider.TransactionParams, cid
 	// as something that should change
 	cis, err := putils.GetChaincodeInvocationSpec(txParams.Proposal)
 	if err != nil {
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, err
 	}
 
 	var cdLedger ccprovider.ChaincodeDefinition
@@ -217,13 +258,13 @@ func (e *Endorser) SimulateProposal(txParams *ccprovider.TransactionParams, cid
 	if !e.s.IsSysCC(cid.Name) {
 		cdLedger, err = e.s.GetChaincodeDefinition(cid.Name, txParams.TXSimulator)
 		if err != nil {
-			return nil, nil, nil, nil, errors.WithMessage(err, fmt.Sprintf("make sure the chaincode %s has been successfully instantiated and try again", cid.Name))
+			return nil, nil, nil, nil, nil, errors.WithMessage(err, fmt.Sprintf("make sure the chaincode %s has been successfully instantiated and try again", cid.Name))
 		}
 		version = cdLedger.CCVersion()
 
 		err = e.s.CheckInstantiationPolicy(cid.Name, version, cdLedger)
 		if err != nil {
-			return nil, nil, nil, nil, err
+			return nil, nil, nil, nil, nil, err
 		}
 	} else {
 		version = util.GetSysCCVersion()
@@ -234,23 +275,36 @@ func (e *Endorser) SimulateProposal(txParams *ccprovider.TransactionParams, cid
 	var pubSimResBytes []byte
 	var res *pb.Response
 	var ccevent *pb.ChaincodeEvent
+	var dissemination *pb.PrivateDataDissemination
+	migration := e.isMigration(txParams, cid, cis.ChaincodeSpec.Input)
+	if migration {
+		if err = checkMigration(txParams.TXSimulator, cid.Name, version); err != nil {
+			return nil, nil, nil, nil, nil, err
+		}
+	}
 	res, ccevent, err = e.callChaincode(txParams, version, cis.ChaincodeSpec.Input, cid)
 	if err != nil {
 		endorserLogger.Errorf("[%s][%s] failed to invoke chaincode %s, error: %+v", txParams.ChannelID, shorttxid(txParams.TxID), cid, err)
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, err
+	}
+	if migration && res.Status < shim.ERRORTHRESHOLD {
+		// the committers record the migration along with the new state
+		if err = txParams.TXSimulator.SetState("lscc", ccprovider.BuildMigrationKVSKey(cid.Name), []byte(version)); err != nil {
+			return nil, nil, nil, nil, nil, err
+		}
 	}
 
 	if txParams.TXSimulator != nil {
 		if simResult, err = txParams.TXSimulator.GetTxSimulationResults(); err != nil {
 			txParams.TXSimulator.Done()
-			return nil, nil, nil, nil, err
+			return nil, nil, nil, nil, nil, err
 		}
 
 		if simResult.PvtSimulationResults != nil {
 			if cid.Name == "lscc" {
 				// TODO: remove once we can store collection configuration outside of LSCC
 				txParams.TXSimulator.Done()
-				return nil, nil, nil, nil, errors.New("Private data is forbidden to be used in instantiate")
+				return nil, nil, nil, nil, nil, errors.New("Private data is forbidden to be used in instantiate")
 			}
 			pvtDataWithConfig, err := e.AssemblePvtRWSet(simResult.PvtSimulationResults, txParams.TXSimulator)
 			// To read collection config need to read collection updates before
@@ -258,11 +312,11 @@ func (e *Endorser) SimulateProposal(txParams *ccprovider.TransactionParams, cid
 			txParams.TXSimulator.Done()
 
 			if err != nil {
-				return nil, nil, nil, nil, errors.WithMessage(err, "failed to obtain collections config")
+				return nil, nil, nil, nil, nil, errors.WithMessage(err, "failed to obtain collections config")
 			}
 			endorsedAt, err := e.s.GetLedgerHeight(txParams.ChannelID)
 			if err != nil {
-				return nil, nil, nil, nil, errors.WithMessage(err, fmt.Sprint("failed to obtain ledger height for channel", txParams.ChannelID))
+				return nil, nil, nil, nil, nil, errors.WithMessage(err, fmt.Sprint("failed to obtain ledger height for channel", txParams.ChannelID))
 			}
 			// Add ledger height at which transaction was endorsed,
 			// `endorsedAt` is obtained from the block storage and at times this could be 'endorsement Height + 1'.
@@ -270,17 +324,45 @@ func (e *Endorser) SimulateProposal(txParams *ccprovider.TransactionParams, cid
 			// manage transient store purge for orphaned private writesets (4th parameter in distributePrivateData), this works for now.
 			// Ideally, ledger should add support in the simulator as a first class function `GetHeight()`.
 			pvtDataWithConfig.EndorsedAt = endorsedAt
-			if err := e.distributePrivateData(txParams.ChannelID, txParams.TxID, pvtDataWithConfig, endorsedAt); err != nil {
-				return nil, nil, nil, nil, err
+			dissemination, err = e.distributePrivateData(txParams.ChannelID, txParams.TxID, pvtDataWithConfig, endorsedAt)
+			if err != nil {
+				return nil, nil, nil, nil, nil, err
 			}
 		}
 
 		txParams.TXSimulator.Done()
 		if pubSimResBytes, err = simResult.GetPubSimulationBytes(); err != nil {
-			return nil, nil, nil, nil, err
+			return nil, nil, nil, nil, nil, err
 		}
 	}
-	return cdLedger, res, pubSimResBytes, ccevent, nil
+	return cdLedger, res, pubSimResBytes, ccevent, dissemination, nil
+}
+
+// isMigration returns true if the proposal invokes the Migrate function of an
+// application chaincode on a channel which records the migrations
+func (e *Endorser) isMigration(txParams *ccprovider.TransactionParams, cid *pb.ChaincodeID, input *pb.ChaincodeInput) bool {
+	if txParams.TXSimulator == nil || e.s.IsSysCC(cid.Name) {
+		return false
+	}
+	if len(input.Args) == 0 || string(input.Args[0]) != ccprovider.MigrateFunction {
+		return false
+	}
+	ac, ok := e.s.GetApplicationConfig(txParams.ChannelID)
+	return ok && ac.Capabilities().ChaincodeMigration()
+}
+
+// checkMigration checks that the state of the chaincode wasn't migrated to
+// its version already; reading the migration also makes the concurrent
+// migrations conflict at commit time
+func checkMigration(txsim ledger.TxSimulator, ccname, version string) error {
+	migratedVersion, err := txsim.GetState("lscc", ccprovider.BuildMigrationKVSKey(ccname))
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed to get the migration of chaincode %s", ccname))
+	}
+	if string(migratedVersion) == version {
+		return errors.Errorf("the state of chaincode %s has already been migrated to version %s", ccname, version)
+	}
+	return nil
 }
 
 // endorse the proposal by calling the ESCC
@@ -335,7 +417,7 @@ func (e *Endorser) endorseProposal(_ context.Context, chainID string, txid strin
 }
 
 // preProcess checks the tx proposal headers, uniqueness and ACL
-func (e *Endorser) preProcess(signedProp *pb.SignedProposal) (*validateResult, error) {
+func (e *Endorser) preProcess(ctx context.Context, signedProp *pb.SignedProposal) (*validateResult, error) {
 	vr := &validateResult{}
 	// at first, we check whether the message is valid
 	prop, hdr, hdrExt, err := validation.ValidateProposalMessage(signedProp)
@@ -357,6 +439,11 @@ func (e *Endorser) preProcess(signedProp *pb.SignedProposal) (*validateResult, e
 		return vr, err
 	}
 
+	if err = e.checkTransientMapSize(prop); err != nil {
+		vr.resp = &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}
+		return vr, err
+	}
+
 	// block invocations to security-sensitive system chaincodes
 	if e.s.IsSysCCAndNotInvokableExternal(hdrExt.ChaincodeId.Name) {
 		endorserLogger.Errorf("Error: an attempt was made by %#v to invoke system chaincode %s", shdr.Creator, hdrExt.ChaincodeId.Name)
@@ -381,8 +468,15 @@ func (e *Endorser) preProcess(signedProp *pb.SignedProposal) (*validateResult, e
 		// check ACL only for application chaincodes; ACLs
 		// for system chaincodes are checked elsewhere
 		if !e.s.IsSysCC(hdrExt.ChaincodeId.Name) {
-			// check that the proposal complies with the Channel's writers
-			if err = e.s.CheckACL(signedProp, chdr, shdr, hdrExt); err != nil {
+			// check that the proposal complies with the Channel's writers,
+			// on behalf of the client which an auth filter authenticated
+			// instead of the creator if there is one
+			if clientSignedData := auth.ClientSignedData(ctx); clientSignedData != nil {
+				err = e.s.CheckClientACL(clientSignedData, chdr)
+			} else {
+				err = e.s.CheckACL(signedProp, chdr, shdr, hdrExt)
+			}
+			if err != nil {
 				vr.resp = &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}
 				return vr, err
 			}
@@ -398,20 +492,63 @@ func (e *Endorser) preProcess(signedProp *pb.SignedProposal) (*validateResult, e
 	return vr, nil
 }
 
+// checkTransientMapSize checks that the transient map of the proposal does
+// not exceed the configured limit
+func (e *Endorser) checkTransientMapSize(prop *pb.Proposal) error {
+	if e.TransientMapMaxSize <= 0 {
+		return nil
+	}
+
+	cpp, err := putils.GetChaincodeProposalPayload(prop.Payload)
+	if err != nil {
+		return err
+	}
+
+	size := 0
+	for key, value := range cpp.TransientMap {
+		size += len(key) + len(value)
+	}
+	if size > e.TransientMapMaxSize {
+		return errors.Errorf("transient map of %d bytes exceeds the limit of %d bytes", size, e.TransientMapMaxSize)
+	}
+	return nil
+}
+
 // ProcessProposal process the Proposal
 func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedProposal) (*pb.ProposalResponse, error) {
 	addr := util.ExtractRemoteAddress(ctx)
 	endorserLogger.Debug("Entering: request from", addr)
 	defer endorserLogger.Debug("Exit: request from", addr)
 
+	// the span of the request is recorded by the gRPC interceptor if
+	// tracing is enabled
+	span := tracing.SpanFromContext(ctx)
+
 	// 0 -- check and validate
-	vr, err := e.preProcess(signedProp)
+	preProcessSpan := span.Child("endorser.preProcess")
+	preProcessStart := time.Now()
+	vr, err := e.preProcess(ctx, signedProp)
+	preProcessSpan.SetError(err)
+	preProcessSpan.End()
 	if err != nil {
 		resp := vr.resp
 		return resp, err
 	}
 
 	prop, hdrExt, chainID, txid := vr.prop, vr.hdrExt, vr.chainID, vr.txid
+	e.recordDuration("proposal_validation_duration", chainID, hdrExt.ChaincodeId.Name, preProcessStart)
+	span.SetAttribute("channel", chainID)
+	span.SetAttribute("tx_id", txid)
+	span.SetAttribute("chaincode", hdrExt.ChaincodeId.Name)
+
+	if chainID != "" && e.ProposalQuota != nil {
+		release, err := e.ProposalQuota.AcquireProposal(chainID)
+		if err != nil {
+			endorserLogger.Warningf("[%s][%s] Rejecting proposal: %s", chainID, shorttxid(txid), err)
+			return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, nil
+		}
+		defer release()
+	}
 
 	// obtaining once the tx simulator for this proposal. This will be nil
 	// for chainless proposals
@@ -444,6 +581,9 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 		Proposal:             prop,
 		TXSimulator:          txsim,
 		HistoryQueryExecutor: historyQueryExecutor,
+		// the chaincode stops executing when the client cancels the
+		// proposal or its deadline is exceeded, which releases txsim
+		Context: ctx,
 	}
 	// this could be a request to a chainless SysCC
 
@@ -453,7 +593,16 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 	//       to validate the supplied action before endorsing it
 
 	// 1 -- simulate
-	cd, res, simulationResult, ccevent, err := e.SimulateProposal(txParams, hdrExt.ChaincodeId)
+	simulateSpan := span.Child("endorser.SimulateProposal")
+	txParams.Span = simulateSpan
+	simulateStart := time.Now()
+	cd, res, simulationResult, ccevent, dissemination, err := e.SimulateProposal(txParams, hdrExt.ChaincodeId)
+	e.recordDuration("simulation_duration", chainID, hdrExt.ChaincodeId.Name, simulateStart)
+	if res != nil {
+		simulateSpan.SetAttribute("status", res.Status)
+	}
+	simulateSpan.SetError(err)
+	simulateSpan.End()
 	if err != nil {
 		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, nil
 	}
@@ -485,7 +634,12 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 		pResp = &pb.ProposalResponse{Response: res}
 	} else {
 		//Note: To endorseProposal(), we pass the released txsim. Hence, an error would occur if we try to use this txsim
+		endorseSpan := span.Child("endorser.endorseProposal")
+		endorseStart := time.Now()
 		pResp, err = e.endorseProposal(ctx, chainID, txid, signedProp, prop, res, simulationResult, ccevent, hdrExt.PayloadVisibility, hdrExt.ChaincodeId, txsim, cd)
+		e.recordDuration("endorsement_duration", chainID, hdrExt.ChaincodeId.Name, endorseStart)
+		endorseSpan.SetError(err)
+		endorseSpan.End()
 		if err != nil {
 			return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, nil
 		}
@@ -499,6 +653,9 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 	// contains the "return value" from the
 	// chaincode invocation
 	pResp.Response = res
+	// Report to the client which peers acknowledged
+	// the receipt of the private data
+	pResp.PrivateDataDissemination = dissemination
 
 	return pResp, nil
 }
@@ -11,11 +11,13 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics"
 	mc "github.com/hyperledger/fabric/common/mocks/config"
 	"github.com/hyperledger/fabric/common/mocks/resourcesconfig"
 	"github.com/hyperledger/fabric/common/util"
@@ -24,10 +26,12 @@ import (
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/endorser"
 	"github.com/hyperledger/fabric/core/endorser/mocks"
+	"github.com/hyperledger/fabric/core/handlers/auth"
 	"github.com/hyperledger/fabric/core/handlers/endorsement/builtin"
 	"github.com/hyperledger/fabric/core/ledger"
 	mockccprovider "github.com/hyperledger/fabric/core/mocks/ccprovider"
 	em "github.com/hyperledger/fabric/core/mocks/endorser"
+	"github.com/hyperledger/fabric/core/quota"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/msp/mgmt/testtools"
@@ -42,8 +46,8 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
-func pvtEmptyDistributor(_ string, _ string, _ *transientstore.TxPvtReadWriteSetWithConfigInfo, _ uint64) error {
-	return nil
+func pvtEmptyDistributor(_ string, _ string, _ *transientstore.TxPvtReadWriteSetWithConfigInfo, _ uint64) (*pb.PrivateDataDissemination, error) {
+	return nil, nil
 }
 
 func getSignedPropWithCHID(ccid, ccver, chid string, t *testing.T) *pb.SignedProposal {
@@ -116,6 +120,40 @@ func TestEndorserUninvokableSysCC(t *testing.T) {
 	assert.Equal(t, "chaincode ccid cannot be invoked through a proposal", pResp.Response.Message)
 }
 
+func TestEndorserTransientMapLimit(t *testing.T) {
+	es := endorser.NewEndorserServer(pvtEmptyDistributor, &em.MockSupport{
+		GetApplicationConfigBoolRv:       true,
+		GetApplicationConfigRv:           &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
+		GetTransactionByIDErr:            errors.New(""),
+		IsSysCCAndNotInvokableExternalRv: true,
+	}, platforms.NewRegistry(&golang.Platform{}))
+	es.TransientMapMaxSize = 20
+
+	signedPropWithTransient := func(transientMap map[string][]byte) *pb.SignedProposal {
+		spec := &pb.ChaincodeSpec{Type: 1, ChaincodeId: &pb.ChaincodeID{Name: "ccid", Version: "0"}, Input: &pb.ChaincodeInput{Args: [][]byte{[]byte("args")}}}
+		creator, err := signer.Serialize()
+		assert.NoError(t, err)
+		prop, _, err := utils.CreateChaincodeProposalWithTransient(common.HeaderType_ENDORSER_TRANSACTION, util.GetTestChainID(), &pb.ChaincodeInvocationSpec{ChaincodeSpec: spec}, creator, transientMap)
+		assert.NoError(t, err)
+		propBytes, err := utils.GetBytesProposal(prop)
+		assert.NoError(t, err)
+		signature, err := signer.Sign(propBytes)
+		assert.NoError(t, err)
+		return &pb.SignedProposal{ProposalBytes: propBytes, Signature: signature}
+	}
+
+	// the transient map fits, so the proposal proceeds to the next check
+	pResp, err := es.ProcessProposal(context.Background(), signedPropWithTransient(map[string][]byte{"key": []byte("value")}))
+	assert.Error(t, err)
+	assert.EqualValues(t, 500, pResp.Response.Status)
+	assert.Equal(t, "chaincode ccid cannot be invoked through a proposal", pResp.Response.Message)
+
+	pResp, err = es.ProcessProposal(context.Background(), signedPropWithTransient(map[string][]byte{"key": []byte("a-value-too-large-for-the-limit")}))
+	assert.Error(t, err)
+	assert.EqualValues(t, 500, pResp.Response.Status)
+	assert.Equal(t, "transient map of 34 bytes exceeds the limit of 20 bytes", pResp.Response.Message)
+}
+
 func TestEndorserCCInvocationFailed(t *testing.T) {
 	es := endorser.NewEndorserServer(pvtEmptyDistributor, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
@@ -138,6 +176,42 @@ func TestEndorserCCInvocationFailed(t *testing.T) {
 	assert.Regexp(t, "Chaincode Error", pResp.Response.Message)
 }
 
+func TestEndorserProposalQuota(t *testing.T) {
+	es := endorser.NewEndorserServer(pvtEmptyDistributor, &em.MockSupport{
+		GetApplicationConfigBoolRv: true,
+		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
+		GetTransactionByIDErr:      errors.New(""),
+		ChaincodeDefinitionRv:      &ccprovider.ChaincodeData{Escc: "ESCC"},
+		ExecuteResp:                &pb.Response{Status: 1000, Payload: utils.MarshalOrPanic(&pb.ProposalResponse{Response: &pb.Response{}}), Message: "Chaincode Error"},
+		GetTxSimulatorRv: &mockccprovider.MockTxSim{
+			GetTxSimulationResultsRv: &ledger.TxSimulationResults{
+				PubSimulationResults: &rwset.TxReadWriteSet{},
+			},
+		},
+	}, platforms.NewRegistry(&golang.Platform{}))
+	quotaManager := quota.NewManager(quota.Config{Default: quota.Limits{MaxConcurrentProposals: 1}}, nil)
+	es.ProposalQuota = quotaManager
+
+	// the slot of the channel is freed once the proposal is processed
+	for i := 0; i < 2; i++ {
+		pResp, err := es.ProcessProposal(context.Background(), getSignedProp("ccid", "0", t))
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1000, pResp.Response.Status)
+	}
+
+	release, err := quotaManager.AcquireProposal(util.GetTestChainID())
+	assert.NoError(t, err)
+	pResp, err := es.ProcessProposal(context.Background(), getSignedProp("ccid", "0", t))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 500, pResp.Response.Status)
+	assert.Equal(t, fmt.Sprintf("channel %s has reached its quota of 1 concurrent proposals", util.GetTestChainID()), pResp.Response.Message)
+
+	release()
+	pResp, err = es.ProcessProposal(context.Background(), getSignedProp("ccid", "0", t))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1000, pResp.Response.Status)
+}
+
 func TestEndorserNoCCDef(t *testing.T) {
 	es := endorser.NewEndorserServer(pvtEmptyDistributor, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
@@ -409,6 +483,59 @@ func TestEndorserGoodPathWEvents(t *testing.T) {
 	assert.EqualValues(t, 200, pResp.Response.Status)
 }
 
+// recordingScope records the histograms of the durations by name and tags
+type recordingScope struct {
+	metrics.Scope
+	tags      map[string]string
+	durations map[string][]time.Duration
+}
+
+func (s *recordingScope) Tagged(tags map[string]string) metrics.Scope {
+	return &recordingScope{Scope: s.Scope, tags: tags, durations: s.durations}
+}
+
+func (s *recordingScope) Histogram(name string) metrics.Histogram {
+	return &recordingHistogram{name: fmt.Sprintf("%s[channel=%s,chaincode=%s]", name, s.tags["channel"], s.tags["chaincode"]), scope: s}
+}
+
+type recordingHistogram struct {
+	name  string
+	scope *recordingScope
+}
+
+func (h *recordingHistogram) RecordDuration(value time.Duration) {
+	h.scope.durations[h.name] = append(h.scope.durations[h.name], value)
+}
+
+func TestEndorserMetrics(t *testing.T) {
+	m := &mock.Mock{}
+	m.On("Sign", mock.Anything).Return([]byte{1, 2, 3, 4, 5}, nil)
+	m.On("Serialize").Return([]byte{1, 1, 1}, nil)
+	m.On("GetTxSimulator", mock.Anything, mock.Anything).Return(newMockTxSim(), nil)
+	support := &em.MockSupport{
+		Mock: m,
+		GetApplicationConfigBoolRv: true,
+		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
+		GetTransactionByIDErr:      errors.New(""),
+		ChaincodeDefinitionRv:      &ccprovider.ChaincodeData{Escc: "ESCC"},
+		ExecuteResp:                &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(&pb.ProposalResponse{Response: &pb.Response{}})},
+	}
+	attachPluginEndorser(support)
+	es := endorser.NewEndorserServer(pvtEmptyDistributor, support, platforms.NewRegistry(&golang.Platform{}))
+	scope := &recordingScope{Scope: metrics.NewNoOpScope(), durations: map[string][]time.Duration{}}
+	es.Metrics = scope
+
+	pResp, err := es.ProcessProposal(context.Background(), getSignedProp("ccid", "0", t))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 200, pResp.Response.Status)
+
+	tags := fmt.Sprintf("[channel=%s,chaincode=ccid]", util.GetTestChainID())
+	for _, name := range []string{"proposal_validation_duration", "simulation_duration", "chaincode_execute_duration", "endorsement_duration"} {
+		assert.Len(t, scope.durations[name+tags], 1, name)
+	}
+	assert.Len(t, scope.durations, 4)
+}
+
 func TestEndorserBadChannel(t *testing.T) {
 	es := endorser.NewEndorserServer(pvtEmptyDistributor, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
@@ -454,6 +581,174 @@ func TestEndorserGoodPath(t *testing.T) {
 	assert.EqualValues(t, 200, pResp.Response.Status)
 }
 
+func TestEndorserClientACL(t *testing.T) {
+	m := &mock.Mock{}
+	m.On("Sign", mock.Anything).Return([]byte{1, 2, 3, 4, 5}, nil)
+	m.On("Serialize").Return([]byte{1, 1, 1}, nil)
+	m.On("GetTxSimulator", mock.Anything, mock.Anything).Return(newMockTxSim(), nil)
+	support := &em.MockSupport{
+		Mock: m,
+		GetApplicationConfigBoolRv: true,
+		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
+		GetTransactionByIDErr:      errors.New(""),
+		CheckACLErr:                errors.New("creator is not a writer"),
+		ChaincodeDefinitionRv:      &ccprovider.ChaincodeData{Escc: "ESCC"},
+		ExecuteResp:                &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(&pb.ProposalResponse{Response: &pb.Response{}})},
+	}
+	attachPluginEndorser(support)
+	es := endorser.NewEndorserServer(pvtEmptyDistributor, support, platforms.NewRegistry(&golang.Platform{}))
+	ctx := auth.WithClientSignedData(context.Background(), []*common.SignedData{{Identity: []byte("client")}})
+
+	// the ACL is checked against the client authenticated by the filters
+	// instead of the creator of the proposal
+	pResp, err := es.ProcessProposal(ctx, getSignedProp("ccid", "0", t))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 200, pResp.Response.Status)
+
+	support.CheckClientACLErr = errors.New("client is not a writer")
+	pResp, err = es.ProcessProposal(ctx, getSignedProp("ccid", "0", t))
+	assert.EqualError(t, err, "client is not a writer")
+	assert.EqualValues(t, 500, pResp.Response.Status)
+}
+
+type pvtTxSim struct {
+	*mockccprovider.MockTxSim
+	collectionConfig []byte
+}
+
+func (s *pvtTxSim) GetState(namespace string, key string) ([]byte, error) {
+	return s.collectionConfig, nil
+}
+
+func TestEndorserPrivateDataDissemination(t *testing.T) {
+	txsim := &pvtTxSim{
+		MockTxSim: &mockccprovider.MockTxSim{
+			GetTxSimulationResultsRv: &ledger.TxSimulationResults{
+				PubSimulationResults: &rwset.TxReadWriteSet{},
+				PvtSimulationResults: &rwset.TxPvtReadWriteSet{
+					NsPvtRwset: []*rwset.NsPvtReadWriteSet{{
+						Namespace:          "ccid",
+						CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{{CollectionName: "c1"}},
+					}},
+				},
+			},
+		},
+		collectionConfig: utils.MarshalOrPanic(&common.CollectionConfigPackage{
+			Config: []*common.CollectionConfig{{
+				Payload: &common.CollectionConfig_StaticCollectionConfig{
+					StaticCollectionConfig: &common.StaticCollectionConfig{Name: "c1"},
+				},
+			}},
+		}),
+	}
+	m := &mock.Mock{}
+	m.On("Sign", mock.Anything).Return([]byte{1, 2, 3, 4, 5}, nil)
+	m.On("Serialize").Return([]byte{1, 1, 1}, nil)
+	m.On("GetTxSimulator", mock.Anything, mock.Anything).Return(txsim, nil)
+	m.On("GetLedgerHeight", mock.Anything).Return(uint64(10), nil)
+	support := &em.MockSupport{
+		Mock: m,
+		GetApplicationConfigBoolRv: true,
+		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
+		GetTransactionByIDErr:      errors.New(""),
+		ChaincodeDefinitionRv:      &ccprovider.ChaincodeData{Escc: "ESCC"},
+		ExecuteResp:                &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(&pb.ProposalResponse{Response: &pb.Response{}})},
+	}
+	attachPluginEndorser(support)
+
+	dissemination := &pb.PrivateDataDissemination{
+		Collections: []*pb.CollectionDissemination{{
+			Namespace:         "ccid",
+			Collection:        "c1",
+			RequiredPeerCount: 1,
+			MaximumPeerCount:  2,
+			AcknowledgedBy:    []*pb.PrivateDataRecipient{{Endpoint: "peer0.org1:7051", Mspid: "Org1MSP"}},
+		}},
+	}
+	var distributedTxID string
+	distributor := func(_ string, txID string, _ *transientstore.TxPvtReadWriteSetWithConfigInfo, _ uint64) (*pb.PrivateDataDissemination, error) {
+		distributedTxID = txID
+		return dissemination, nil
+	}
+	es := endorser.NewEndorserServer(distributor, support, platforms.NewRegistry(&golang.Platform{}))
+
+	signedProp := getSignedProp("ccid", "0", t)
+
+	pResp, err := es.ProcessProposal(context.Background(), signedProp)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 200, pResp.Response.Status)
+	assert.NotEmpty(t, distributedTxID)
+	assert.True(t, proto.Equal(dissemination, pResp.PrivateDataDissemination))
+}
+
+type migrationTxSim struct {
+	*mockccprovider.MockTxSim
+	state map[string][]byte
+}
+
+func (s *migrationTxSim) GetState(namespace string, key string) ([]byte, error) {
+	return s.state[namespace+"/"+key], nil
+}
+
+func (s *migrationTxSim) SetState(namespace string, key string, value []byte) error {
+	s.state[namespace+"/"+key] = value
+	return nil
+}
+
+func TestEndorserMigration(t *testing.T) {
+	txsim := &migrationTxSim{MockTxSim: newMockTxSim(), state: map[string][]byte{}}
+	m := &mock.Mock{}
+	m.On("Sign", mock.Anything).Return([]byte{1, 2, 3, 4, 5}, nil)
+	m.On("Serialize").Return([]byte{1, 1, 1}, nil)
+	m.On("GetTxSimulator", mock.Anything, mock.Anything).Return(txsim, nil)
+	capabilities := &mc.MockApplicationCapabilities{}
+	support := &em.MockSupport{
+		Mock: m,
+		GetApplicationConfigBoolRv: true,
+		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: capabilities},
+		GetTransactionByIDErr:      errors.New(""),
+		ChaincodeDefinitionRv:      &ccprovider.ChaincodeData{Escc: "ESCC", Version: "2"},
+		ExecuteResp:                &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(&pb.ProposalResponse{Response: &pb.Response{}})},
+	}
+	attachPluginEndorser(support)
+	es := endorser.NewEndorserServer(pvtEmptyDistributor, support, platforms.NewRegistry(&golang.Platform{}))
+	migrate := func() (*pb.ProposalResponse, error) {
+		return es.ProcessProposal(context.Background(), getSignedPropWithCHIdAndArgs(util.GetTestChainID(), "ccid", "2", [][]byte{[]byte("Migrate")}, t))
+	}
+
+	// the migrations are not recorded before V1_4_2
+	pResp, err := migrate()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 200, pResp.Response.Status)
+	assert.Empty(t, txsim.state)
+
+	// the failed migrations are not recorded
+	capabilities.ChaincodeMigrationRv = true
+	support.ExecuteResp = &pb.Response{Status: 500, Message: "migration failed"}
+	pResp, err = migrate()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 500, pResp.Response.Status)
+	assert.Empty(t, txsim.state)
+
+	// the invocations of other functions are not migrations
+	support.ExecuteResp = &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(&pb.ProposalResponse{Response: &pb.Response{}})}
+	pResp, err = es.ProcessProposal(context.Background(), getSignedProp("ccid", "2", t))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 200, pResp.Response.Status)
+	assert.Empty(t, txsim.state)
+
+	pResp, err = migrate()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 200, pResp.Response.Status)
+	assert.Equal(t, []byte("2"), txsim.state["lscc/ccid~migration"])
+
+	// the state is only migrated once to a version
+	pResp, err = migrate()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 500, pResp.Response.Status)
+	assert.Equal(t, "the state of chaincode ccid has already been migrated to version 2", pResp.Response.Message)
+}
+
 func TestEndorserChaincodeCallLogging(t *testing.T) {
 	gt := NewGomegaWithT(t)
 	m := &mock.Mock{}
@@ -569,7 +864,7 @@ func TestSimulateProposal(t *testing.T) {
 		},
 	}, platforms.NewRegistry(&golang.Platform{}))
 
-	_, _, _, _, err := es.SimulateProposal(&ccprovider.TransactionParams{}, nil)
+	_, _, _, _, _, err := es.SimulateProposal(&ccprovider.TransactionParams{}, nil)
 	assert.Error(t, err)
 }
 
@@ -172,6 +172,18 @@ type Support struct {
 	checkACLReturnsOnCall map[int]struct {
 		result1 error
 	}
+	CheckClientACLStub        func(signedData []*common.SignedData, chdr *common.ChannelHeader) error
+	checkClientACLMutex       sync.RWMutex
+	checkClientACLArgsForCall []struct {
+		signedData []*common.SignedData
+		chdr       *common.ChannelHeader
+	}
+	checkClientACLReturns struct {
+		result1 error
+	}
+	checkClientACLReturnsOnCall map[int]struct {
+		result1 error
+	}
 	IsJavaCCStub        func(buf []byte) (bool, error)
 	isJavaCCMutex       sync.RWMutex
 	isJavaCCArgsForCall []struct {
@@ -842,6 +854,60 @@ func (fake *Support) CheckACLReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *Support) CheckClientACL(signedData []*common.SignedData, chdr *common.ChannelHeader) error {
+	var signedDataCopy []*common.SignedData
+	if signedData != nil {
+		signedDataCopy = make([]*common.SignedData, len(signedData))
+		copy(signedDataCopy, signedData)
+	}
+	fake.checkClientACLMutex.Lock()
+	ret, specificReturn := fake.checkClientACLReturnsOnCall[len(fake.checkClientACLArgsForCall)]
+	fake.checkClientACLArgsForCall = append(fake.checkClientACLArgsForCall, struct {
+		signedData []*common.SignedData
+		chdr       *common.ChannelHeader
+	}{signedDataCopy, chdr})
+	fake.recordInvocation("CheckClientACL", []interface{}{signedDataCopy, chdr})
+	fake.checkClientACLMutex.Unlock()
+	if fake.CheckClientACLStub != nil {
+		return fake.CheckClientACLStub(signedData, chdr)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.checkClientACLReturns.result1
+}
+
+func (fake *Support) CheckClientACLCallCount() int {
+	fake.checkClientACLMutex.RLock()
+	defer fake.checkClientACLMutex.RUnlock()
+	return len(fake.checkClientACLArgsForCall)
+}
+
+func (fake *Support) CheckClientACLArgsForCall(i int) ([]*common.SignedData, *common.ChannelHeader) {
+	fake.checkClientACLMutex.RLock()
+	defer fake.checkClientACLMutex.RUnlock()
+	return fake.checkClientACLArgsForCall[i].signedData, fake.checkClientACLArgsForCall[i].chdr
+}
+
+func (fake *Support) CheckClientACLReturns(result1 error) {
+	fake.CheckClientACLStub = nil
+	fake.checkClientACLReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Support) CheckClientACLReturnsOnCall(i int, result1 error) {
+	fake.CheckClientACLStub = nil
+	if fake.checkClientACLReturnsOnCall == nil {
+		fake.checkClientACLReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.checkClientACLReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *Support) IsJavaCC(buf []byte) (bool, error) {
 	var bufCopy []byte
 	if buf != nil {
@@ -1228,6 +1294,8 @@ func (fake *Support) Invocations() map[string][][]interface{} {
 	defer fake.getChaincodeDefinitionMutex.RUnlock()
 	fake.checkACLMutex.RLock()
 	defer fake.checkACLMutex.RUnlock()
+	fake.checkClientACLMutex.RLock()
+	defer fake.checkClientACLMutex.RUnlock()
 	fake.isJavaCCMutex.RLock()
 	defer fake.isJavaCCMutex.RUnlock()
 	fake.checkInstantiationPolicyMutex.RLock()
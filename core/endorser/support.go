@@ -158,6 +158,12 @@ func (s *SupportImpl) CheckACL(signedProp *pb.SignedProposal, chdr *common.Chann
 	return s.ACLProvider.CheckACL(resources.Peer_Propose, chdr.ChannelId, signedProp)
 }
 
+// CheckClientACL checks the ACL for the resource for the Channel using the
+// signed data which authenticates the client a proposal is sent on behalf of
+func (s *SupportImpl) CheckClientACL(signedData []*common.SignedData, chdr *common.ChannelHeader) error {
+	return s.ACLProvider.CheckACL(resources.Peer_Propose, chdr.ChannelId, signedData)
+}
+
 // IsJavaCC returns true if the CDS package bytes describe a chaincode
 // that requires the java runtime environment to execute
 func (s *SupportImpl) IsJavaCC(buf []byte) (bool, error) {
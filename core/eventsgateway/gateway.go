@@ -0,0 +1,299 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package eventsgateway
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/websocket"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/tools/protolator"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	grpcpeer "google.golang.org/grpc/peer"
+)
+
+var logger = flogging.MustGetLogger("eventsgateway")
+
+const (
+	// BlocksStream streams the full blocks of a channel
+	BlocksStream = "blocks"
+	// FilteredBlocksStream streams the filtered blocks of a channel
+	FilteredBlocksStream = "filteredblocks"
+	// ChaincodeEventsStream streams the chaincode events of a channel
+	ChaincodeEventsStream = "events"
+
+	// maxRequestSize is the maximum size of the deliver request of a stream
+	maxRequestSize = 1024 * 1024
+	// maxCloseReason is the maximum size of the reason of a close message, as defined by RFC 6455
+	maxCloseReason = 123
+	// closeTimeout bounds the time spent sending the close message of a stream
+	closeTimeout = 5 * time.Second
+)
+
+// upgrader accepts WebSockets from any origin, since the streams are
+// authorized against the deliver requests signed by the clients rather than
+// against the credentials a browser would attach to the request
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+// Gateway is an http.Handler which bridges the deliver service of the peer to
+// WebSocket clients. Streams are requested at /channels/<channel>/<stream>,
+// where stream is one of blocks, filteredblocks or events. Once the WebSocket
+// is open, the client sends its deliver request as a single binary message:
+// a marshaled common.Envelope of type DELIVER_SEEK_INFO signed by the client,
+// exactly as sent to the deliver gRPC service. Chaincode event filters, and
+// the slimming of the full blocks, are set in the extension of its channel
+// header.
+//
+// Every message then sent to the client is the JSON representation of a
+// delivered protobuf message, and the WebSocket is closed after the final
+// status message.
+//
+// The deliver requests are authorized against the identity of the client which
+// signed them, through the same policies as the deliver gRPC service. When the
+// listener of the gateway verifies client TLS certificates, the requests must be
+// bound to the TLS certificate of the client in the same way too.
+type Gateway struct {
+	deliverServer peer.DeliverServer
+}
+
+// NewGateway creates a Gateway serving the streams of the given deliver server
+func NewGateway(deliverServer peer.DeliverServer) *Gateway {
+	return &Gateway{
+		deliverServer: deliverServer,
+	}
+}
+
+// streamRequest is a parsed request for a stream
+type streamRequest struct {
+	channelID string
+	stream    string
+	envelope  *common.Envelope
+}
+
+// ServeHTTP upgrades the request to a WebSocket, reads the deliver request of
+// the client from it and streams the requested events over it
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req, err := parseStreamPath(r.URL.Path)
+	if err != nil {
+		logger.Debugf("Rejecting request for %s from %s: %s", r.URL.Path, r.RemoteAddr, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader has replied with an error already
+		logger.Debugf("Rejecting request for %s from %s: %s", r.URL.Path, r.RemoteAddr, err)
+		return
+	}
+	defer ws.Close()
+
+	ws.SetReadLimit(maxRequestSize)
+	messageType, request, err := ws.ReadMessage()
+	if err == nil && messageType != websocket.BinaryMessage {
+		err = errors.New("deliver request must be a binary message")
+	}
+	if err != nil {
+		logger.Debugf("Failed reading the deliver request of %s: %s", r.RemoteAddr, err)
+		closeWebSocket(ws, websocket.ClosePolicyViolation, err.Error())
+		return
+	}
+	if req.envelope, err = parseDeliverRequest(req, request); err != nil {
+		logger.Debugf("Rejecting deliver request of %s: %s", r.RemoteAddr, err)
+		closeWebSocket(ws, websocket.ClosePolicyViolation, err.Error())
+		return
+	}
+
+	logger.Debugf("Streaming %s of channel %s to %s", req.stream, req.channelID, r.RemoteAddr)
+	ctx, cancel := context.WithCancel(streamContext(r))
+	defer cancel()
+	go func() {
+		// reading answers the pings and the close message of the client,
+		// which has nothing else to say
+		for {
+			if _, _, err := ws.NextReader(); err != nil {
+				logger.Debugf("Websocket of %s terminated: %s", r.RemoteAddr, err)
+				break
+			}
+		}
+		cancel()
+	}()
+
+	stream := &deliverStream{
+		ctx:      ctx,
+		cancel:   cancel,
+		envelope: req.envelope,
+		ws:       ws,
+		events:   req.stream == ChaincodeEventsStream,
+	}
+	switch req.stream {
+	case BlocksStream:
+		err = g.deliverServer.Deliver(stream)
+	default:
+		err = g.deliverServer.DeliverFiltered(stream)
+	}
+	if err != nil && ctx.Err() == nil {
+		logger.Warningf("Streaming %s of channel %s to %s failed: %s", req.stream, req.channelID, r.RemoteAddr, err)
+	}
+	closeWebSocket(ws, websocket.CloseNormalClosure, "")
+}
+
+// closeWebSocket sends a close message with the given code and reason
+func closeWebSocket(ws *websocket.Conn, code int, reason string) {
+	if len(reason) > maxCloseReason {
+		reason = reason[:maxCloseReason]
+	}
+	ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(closeTimeout))
+}
+
+// streamContext returns the context of the deliver stream of the given
+// request, which carries the address and the TLS state of the client in the
+// same way as the context of a gRPC stream
+func streamContext(r *http.Request) context.Context {
+	client := &grpcpeer.Peer{}
+	if addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr); err == nil {
+		client.Addr = addr
+	}
+	if r.TLS != nil {
+		client.AuthInfo = credentials.TLSInfo{State: *r.TLS}
+	}
+	// the connection is hijacked by the WebSocket, hence the stream outlives
+	// the context of the request
+	return grpcpeer.NewContext(context.Background(), client)
+}
+
+// parseStreamPath extracts the channel and the stream of a request from its path
+func parseStreamPath(path string) (*streamRequest, error) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 3 || parts[0] != "channels" || parts[1] == "" {
+		return nil, errors.New("path must be /channels/<channel>/<stream>")
+	}
+	req := &streamRequest{channelID: parts[1], stream: parts[2]}
+	switch req.stream {
+	case BlocksStream, FilteredBlocksStream, ChaincodeEventsStream:
+	default:
+		return nil, errors.Errorf("unknown stream %s", req.stream)
+	}
+	return req, nil
+}
+
+// parseDeliverRequest unmarshals the deliver request sent by the client for
+// the given stream, and checks it matches the stream
+func parseDeliverRequest(req *streamRequest, request []byte) (*common.Envelope, error) {
+	envelope := &common.Envelope{}
+	if err := proto.Unmarshal(request, envelope); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling deliver request")
+	}
+	payload, err := utils.UnmarshalPayload(envelope.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, errors.New("deliver request has no header")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	if chdr.Type != int32(common.HeaderType_DELIVER_SEEK_INFO) {
+		return nil, errors.Errorf("deliver request has type %s instead of %s", common.HeaderType(chdr.Type), common.HeaderType_DELIVER_SEEK_INFO)
+	}
+	if chdr.ChannelId != req.channelID {
+		return nil, errors.Errorf("deliver request is for channel %s instead of %s", chdr.ChannelId, req.channelID)
+	}
+	return envelope, nil
+}
+
+// deliverStream implements the server stream of the deliver services,
+// forwarding the responses to a WebSocket as JSON
+type deliverStream struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	envelope *common.Envelope
+	received bool
+	ws       *websocket.Conn
+	// events sends only the chaincode events of the filtered blocks
+	events bool
+}
+
+// Recv returns the deliver request of the stream once, then waits for the stream to end
+func (s *deliverStream) Recv() (*common.Envelope, error) {
+	if !s.received {
+		s.received = true
+		return s.envelope, nil
+	}
+	<-s.ctx.Done()
+	return nil, io.EOF
+}
+
+// Send writes the given response to the WebSocket, a status response ends the stream
+func (s *deliverStream) Send(response *peer.DeliverResponse) error {
+	if _, ok := response.Type.(*peer.DeliverResponse_Status); ok {
+		defer s.cancel()
+	}
+
+	if filteredBlock := response.GetFilteredBlock(); s.events && filteredBlock != nil {
+		for _, tx := range filteredBlock.FilteredTransactions {
+			for _, action := range tx.GetTransactionActions().GetChaincodeActions() {
+				if err := s.sendJSON(action.ChaincodeEvent); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return s.sendJSON(response)
+}
+
+func (s *deliverStream) sendJSON(msg proto.Message) error {
+	buf := &bytes.Buffer{}
+	if err := protolator.DeepMarshalJSON(buf, msg); err != nil {
+		return errors.WithMessage(err, "failed marshaling message to JSON")
+	}
+	return s.ws.WriteMessage(websocket.TextMessage, buf.Bytes())
+}
+
+func (s *deliverStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *deliverStream) SetHeader(metadata.MD) error {
+	return nil
+}
+
+func (s *deliverStream) SendHeader(metadata.MD) error {
+	return nil
+}
+
+func (s *deliverStream) SetTrailer(metadata.MD) {
+}
+
+func (s *deliverStream) SendMsg(m interface{}) error {
+	response, ok := m.(*peer.DeliverResponse)
+	if !ok {
+		return errors.Errorf("unexpected message type %T", m)
+	}
+	return s.Send(response)
+}
+
+func (s *deliverStream) RecvMsg(m interface{}) error {
+	return errors.New("not supported")
+}
@@ -0,0 +1,370 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package eventsgateway
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/websocket"
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/hyperledger/fabric/common/deliver"
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	ramledger "github.com/hyperledger/fabric/common/ledger/blockledger/ram"
+	mockcrypto "github.com/hyperledger/fabric/common/mocks/crypto"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/util"
+	corepeer "github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDeliverServer records the deliver request of a stream
+// and replies with the configured responses
+type fakeDeliverServer struct {
+	method    string
+	request   *common.Envelope
+	responses []*peer.DeliverResponse
+}
+
+func (f *fakeDeliverServer) serve(method string, srv peer.Deliver_DeliverServer) error {
+	f.method = method
+	env, err := srv.Recv()
+	if err != nil {
+		return err
+	}
+	f.request = env
+	for _, response := range f.responses {
+		if err := srv.Send(response); err != nil {
+			return err
+		}
+	}
+	_, err = srv.Recv()
+	return err
+}
+
+func (f *fakeDeliverServer) Deliver(srv peer.Deliver_DeliverServer) error {
+	return f.serve("Deliver", srv)
+}
+
+func (f *fakeDeliverServer) DeliverFiltered(srv peer.Deliver_DeliverFilteredServer) error {
+	return f.serve("DeliverFiltered", srv)
+}
+
+func (f *fakeDeliverServer) DeliverWithPrivateData(srv peer.Deliver_DeliverWithPrivateDataServer) error {
+	return f.serve("DeliverWithPrivateData", srv)
+}
+
+func filteredBlockResponse() *peer.DeliverResponse {
+	return &peer.DeliverResponse{
+		Type: &peer.DeliverResponse_FilteredBlock{
+			FilteredBlock: &peer.FilteredBlock{
+				ChannelId: "mychannel",
+				Number:    7,
+				FilteredTransactions: []*peer.FilteredTransaction{
+					{
+						Txid: "tx1",
+						Data: &peer.FilteredTransaction_TransactionActions{
+							TransactionActions: &peer.FilteredTransactionActions{
+								ChaincodeActions: []*peer.FilteredChaincodeAction{
+									{ChaincodeEvent: &peer.ChaincodeEvent{ChaincodeId: "mycc", TxId: "tx1", EventName: "e1"}},
+									{ChaincodeEvent: &peer.ChaincodeEvent{ChaincodeId: "mycc", TxId: "tx1", EventName: "e2"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func statusResponse() *peer.DeliverResponse {
+	return &peer.DeliverResponse{Type: &peer.DeliverResponse_Status{Status: common.Status_SUCCESS}}
+}
+
+// seekRequest returns the marshaled deliver request of a client
+func seekRequest(t *testing.T, channelID string, tlsCertHash []byte, extension proto.Message) []byte {
+	seekInfo := &orderer.SeekInfo{
+		Start:    &orderer.SeekPosition{Type: &orderer.SeekPosition_Oldest{Oldest: &orderer.SeekOldest{}}},
+		Stop:     &orderer.SeekPosition{Type: &orderer.SeekPosition_Newest{Newest: &orderer.SeekNewest{}}},
+		Behavior: orderer.SeekInfo_BLOCK_UNTIL_READY,
+	}
+	env, err := utils.CreateSignedEnvelopeWithExtension(common.HeaderType_DELIVER_SEEK_INFO, channelID, &mockcrypto.LocalSigner{Identity: []byte("client")}, seekInfo, 0, 0, tlsCertHash, extension)
+	require.NoError(t, err)
+	return utils.MarshalOrPanic(env)
+}
+
+// dial opens a WebSocket to the given path of the gateway at the given URL
+func dial(t *testing.T, dialer *websocket.Dialer, url string, path string) (*websocket.Conn, *http.Response) {
+	url = strings.Replace(url, "http", "ws", 1) + path
+	client, resp, err := dialer.Dial(url, nil)
+	if err != websocket.ErrBadHandshake {
+		require.NoError(t, err)
+	}
+	return client, resp
+}
+
+// readStream returns the JSON messages of the stream of the given client,
+// until the gateway closes the WebSocket, and the code it closes it with
+func readStream(t *testing.T, client *websocket.Conn) ([]map[string]interface{}, int) {
+	defer client.Close()
+	var messages []map[string]interface{}
+	for {
+		messageType, payload, err := client.ReadMessage()
+		if closeErr, ok := err.(*websocket.CloseError); ok {
+			return messages, closeErr.Code
+		}
+		require.NoError(t, err)
+		require.Equal(t, websocket.TextMessage, messageType)
+		msg := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(payload, &msg), string(payload))
+		messages = append(messages, msg)
+	}
+}
+
+func TestGatewayStreams(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		extension      proto.Message
+		expectedMethod string
+		expected       []map[string]interface{}
+	}{
+		{
+			name:           "filtered blocks",
+			path:           "/channels/mychannel/filteredblocks",
+			expectedMethod: "DeliverFiltered",
+			expected: []map[string]interface{}{
+				{"filtered_block": map[string]interface{}{"channel_id": "mychannel", "number": "7"}},
+				{"status": "SUCCESS"},
+			},
+		},
+		{
+			name:           "chaincode events",
+			path:           "/channels/mychannel/events",
+			extension:      &peer.ChaincodeEventFilter{ChaincodeId: "mycc", EventName: "e.*"},
+			expectedMethod: "DeliverFiltered",
+			expected: []map[string]interface{}{
+				{"chaincode_id": "mycc", "event_name": "e1"},
+				{"chaincode_id": "mycc", "event_name": "e2"},
+				{"status": "SUCCESS"},
+			},
+		},
+		{
+			name:           "blocks",
+			path:           "/channels/mychannel/blocks",
+			expectedMethod: "Deliver",
+			expected: []map[string]interface{}{
+				{"filtered_block": map[string]interface{}{"channel_id": "mychannel", "number": "7"}},
+				{"status": "SUCCESS"},
+			},
+		},
+		{
+			name:           "slimmed blocks",
+			path:           "/channels/mychannel/blocks",
+			extension:      &peer.BlockSlimming{StripSignatures: true, StripRwsets: true},
+			expectedMethod: "Deliver",
+			expected: []map[string]interface{}{
+				{"filtered_block": map[string]interface{}{"channel_id": "mychannel", "number": "7"}},
+				{"status": "SUCCESS"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			deliverServer := &fakeDeliverServer{responses: []*peer.DeliverResponse{filteredBlockResponse(), statusResponse()}}
+			server := httptest.NewServer(NewGateway(deliverServer))
+			defer server.Close()
+
+			client, resp := dial(t, websocket.DefaultDialer, server.URL, test.path)
+			require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+			request := seekRequest(t, "mychannel", nil, test.extension)
+			require.NoError(t, client.WriteMessage(websocket.BinaryMessage, request))
+			// the stream ends after the status
+			messages, code := readStream(t, client)
+			assert.Equal(t, websocket.CloseNormalClosure, code)
+			require.Len(t, messages, len(test.expected))
+			for i, expected := range test.expected {
+				assertContains(t, expected, messages[i])
+			}
+
+			// the deliver request of the client is served as is
+			assert.Equal(t, test.expectedMethod, deliverServer.method)
+			assert.Equal(t, request, utils.MarshalOrPanic(deliverServer.request))
+		})
+	}
+}
+
+// assertContains asserts all the expected entries are found in the actual message
+func assertContains(t *testing.T, expected map[string]interface{}, actual map[string]interface{}) {
+	for key, value := range expected {
+		if nested, ok := value.(map[string]interface{}); ok {
+			actualNested, ok := actual[key].(map[string]interface{})
+			require.True(t, ok, "missing %s in %v", key, actual)
+			assertContains(t, nested, actualNested)
+			continue
+		}
+		assert.Equal(t, value, actual[key], "unexpected %s in %v", key, actual)
+	}
+}
+
+func TestGatewayRejectsInvalidRequests(t *testing.T) {
+	server := httptest.NewServer(NewGateway(&fakeDeliverServer{}))
+	defer server.Close()
+
+	// plain HTTP requests and unknown streams are rejected before the upgrade
+	resp, err := http.Get(server.URL + "/channels/mychannel/blocks")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	for _, path := range []string{"/channels/mychannel", "/channels//blocks", "/channels/mychannel/unknown"} {
+		_, resp := dial(t, websocket.DefaultDialer, server.URL, path)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, path)
+	}
+
+	// invalid deliver requests close the WebSocket
+	configUpdate, err := utils.CreateSignedEnvelope(common.HeaderType_CONFIG_UPDATE, "mychannel", nil, &common.ConfigUpdateEnvelope{}, 0, 0)
+	require.NoError(t, err)
+	for _, test := range []struct {
+		path        string
+		messageType int
+		body        []byte
+		code        int
+	}{
+		{path: "/channels/mychannel/blocks", messageType: websocket.BinaryMessage, body: []byte("not a deliver request"), code: websocket.ClosePolicyViolation},
+		{path: "/channels/mychannel/blocks", messageType: websocket.BinaryMessage, body: utils.MarshalOrPanic(&common.Envelope{}), code: websocket.ClosePolicyViolation},
+		{path: "/channels/mychannel/blocks", messageType: websocket.BinaryMessage, body: utils.MarshalOrPanic(configUpdate), code: websocket.ClosePolicyViolation},
+		{path: "/channels/otherchannel/blocks", messageType: websocket.BinaryMessage, body: seekRequest(t, "mychannel", nil, nil), code: websocket.ClosePolicyViolation},
+		{path: "/channels/mychannel/blocks", messageType: websocket.TextMessage, body: seekRequest(t, "mychannel", nil, nil), code: websocket.ClosePolicyViolation},
+		{path: "/channels/mychannel/blocks", messageType: websocket.BinaryMessage, body: make([]byte, maxRequestSize+1), code: websocket.CloseMessageTooBig},
+	} {
+		client, resp := dial(t, websocket.DefaultDialer, server.URL, test.path)
+		require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+		require.NoError(t, client.WriteMessage(test.messageType, test.body))
+		messages, code := readStream(t, client)
+		assert.Empty(t, messages, test.path)
+		assert.Equal(t, test.code, code, test.path)
+	}
+}
+
+type fakeChain struct {
+	reader blockledger.Reader
+}
+
+func (*fakeChain) Sequence() uint64 {
+	return 0
+}
+
+func (*fakeChain) PolicyManager() policies.Manager {
+	return nil
+}
+
+func (f *fakeChain) Reader() blockledger.Reader {
+	return f.reader
+}
+
+func (*fakeChain) Errored() <-chan struct{} {
+	return nil
+}
+
+type fakeChainManager struct {
+	reader blockledger.Reader
+}
+
+func (f *fakeChainManager) GetChain(chainID string) (deliver.Chain, bool) {
+	return &fakeChain{reader: f.reader}, chainID == "mychannel"
+}
+
+func TestGatewayStreamsBlocksOfLedger(t *testing.T) {
+	ledger, err := ramledger.New(10).GetOrCreate("mychannel")
+	require.NoError(t, err)
+	genesisBlock := common.NewBlock(0, nil)
+	require.NoError(t, ledger.Append(genesisBlock))
+	require.NoError(t, ledger.Append(common.NewBlock(1, genesisBlock.Header.Hash())))
+
+	allowAll := func(resourceName string) deliver.PolicyCheckerFunc {
+		return func(env *common.Envelope, channelID string) error {
+			return nil
+		}
+	}
+	deliverServer := corepeer.NewDeliverEventsServer(false, allowAll, &fakeChainManager{reader: ledger}, nil)
+	server := httptest.NewServer(NewGateway(deliverServer))
+	defer server.Close()
+
+	// the full blocks, sent by the deliver server as it sends them to its
+	// gRPC clients, are streamed until the newest one
+	client, _ := dial(t, websocket.DefaultDialer, server.URL, "/channels/mychannel/blocks")
+	require.NoError(t, client.WriteMessage(websocket.BinaryMessage, seekRequest(t, "mychannel", nil, nil)))
+	messages, code := readStream(t, client)
+	assert.Equal(t, websocket.CloseNormalClosure, code)
+	require.Len(t, messages, 3)
+	assert.Contains(t, messages[0], "block")
+	assertContains(t, map[string]interface{}{"block": map[string]interface{}{
+		"header": map[string]interface{}{"number": "1"},
+	}}, messages[1])
+	assertContains(t, map[string]interface{}{"status": "SUCCESS"}, messages[2])
+}
+
+func TestGatewayAuthorizesClients(t *testing.T) {
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+	clientKeyPair, err := ca.NewClientCertKeyPair()
+	require.NoError(t, err)
+	clientCert, err := tls.X509KeyPair(clientKeyPair.Cert, clientKeyPair.Key)
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	require.True(t, clientCAs.AppendCertsFromPEM(ca.CertBytes()))
+
+	// the policy records the identity the request is evaluated against, and denies it
+	var creators [][]byte
+	policyCheckerProvider := func(resourceName string) deliver.PolicyCheckerFunc {
+		return func(env *common.Envelope, channelID string) error {
+			shdr, err := utils.GetSignatureHeader(utils.UnmarshalPayloadOrPanic(env.Payload).Header.SignatureHeader)
+			require.NoError(t, err)
+			creators = append(creators, shdr.Creator)
+			return errors.New("access denied")
+		}
+	}
+	deliverServer := corepeer.NewDeliverEventsServer(true, policyCheckerProvider, &fakeChainManager{}, nil)
+	server := httptest.NewUnstartedServer(NewGateway(deliverServer))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+	tlsConfig := server.Client().Transport.(*http.Transport).TLSClientConfig
+	tlsConfig.Certificates = []tls.Certificate{clientCert}
+	dialer := &websocket.Dialer{TLSClientConfig: tlsConfig}
+
+	// requests which aren't bound to the TLS certificate of the client are rejected
+	client, _ := dial(t, dialer, server.URL, "/channels/mychannel/blocks")
+	require.NoError(t, client.WriteMessage(websocket.BinaryMessage, seekRequest(t, "mychannel", nil, nil)))
+	messages, _ := readStream(t, client)
+	require.Len(t, messages, 1)
+	assertContains(t, map[string]interface{}{"status": "BAD_REQUEST"}, messages[0])
+	assert.Empty(t, creators)
+
+	// bound requests are authorized against the identity of the client
+	tlsCertHash := util.ComputeSHA256(clientKeyPair.TLSCert.Raw)
+	client, _ = dial(t, dialer, server.URL, "/channels/mychannel/filteredblocks")
+	require.NoError(t, client.WriteMessage(websocket.BinaryMessage, seekRequest(t, "mychannel", tlsCertHash, nil)))
+	messages, _ = readStream(t, client)
+	require.Len(t, messages, 1)
+	assertContains(t, map[string]interface{}{"status": "FORBIDDEN"}, messages[0])
+	assert.Equal(t, [][]byte{[]byte("client")}, creators)
+}
@@ -0,0 +1,159 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	gcommon "github.com/hyperledger/fabric/gossip/common"
+	discprotos "github.com/hyperledger/fabric/protos/discovery"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// EndorsementPlanner computes the peers which can endorse a chaincode invocation
+type EndorsementPlanner interface {
+	// PeersForEndorsement returns the endorsement layouts of the given chaincode interest
+	PeersForEndorsement(chainID gcommon.ChainID, interest *discprotos.ChaincodeInterest) (*discprotos.EndorsementDescriptor, error)
+}
+
+// Endorser processes proposals
+type Endorser interface {
+	ProcessProposal(ctx context.Context, signedProp *pb.SignedProposal) (*pb.ProposalResponse, error)
+}
+
+// Endorsers provides the endorsers of peers
+type Endorsers interface {
+	// Endorser returns the endorser of the peer with the given endpoint
+	Endorser(endpoint string) (Endorser, error)
+	// Remove discards the endorser of the peer with the given endpoint,
+	// which could not be reached
+	Remove(endpoint string)
+}
+
+// endorse collects the endorsements of the first endorsement layout of the
+// chaincode for which every required peer successfully endorses the proposal
+func (s *Server) endorse(ctx context.Context, channelID string, ccName string, signedProp *pb.SignedProposal) ([]*pb.ProposalResponse, error) {
+	desc, err := s.planner.PeersForEndorsement(gcommon.ChainID(channelID), &discprotos.ChaincodeInterest{
+		Chaincodes: []*discprotos.ChaincodeCall{{Name: ccName}},
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed computing endorsement plan")
+	}
+	if len(desc.Layouts) == 0 {
+		return nil, errors.Errorf("no endorsement layout found for chaincode %s", ccName)
+	}
+
+	// endorsements already collected, by endpoint, are reused across layouts
+	endorsements := make(map[string]*pb.ProposalResponse)
+	failed := make(map[string]bool)
+	var lastErr error
+	for _, layout := range desc.Layouts {
+		responses, err := s.endorseLayout(ctx, desc, layout, signedProp, endorsements, failed)
+		if err == nil {
+			return responses, nil
+		}
+		logger.Debugf("[channel: %s] Endorsement layout %v of chaincode %s not satisfied: %s", channelID, layout.QuantitiesByGroup, ccName, err)
+		lastErr = err
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+// endorseLayout collects an endorsement from as many distinct peers of every
+// group of the layout as the layout requires
+func (s *Server) endorseLayout(ctx context.Context, desc *discprotos.EndorsementDescriptor, layout *discprotos.Layout, signedProp *pb.SignedProposal,
+	endorsements map[string]*pb.ProposalResponse, failed map[string]bool) ([]*pb.ProposalResponse, error) {
+	groups := make([]string, 0, len(layout.QuantitiesByGroup))
+	for group := range layout.QuantitiesByGroup {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	var responses []*pb.ProposalResponse
+	used := make(map[string]bool)
+	for _, group := range groups {
+		required := int(layout.QuantitiesByGroup[group])
+		endorsed := 0
+		for _, p := range desc.EndorsersByGroups[group].GetPeers() {
+			if endorsed == required {
+				break
+			}
+			endpoint, err := peerEndpoint(p)
+			if err != nil {
+				logger.Warningf("Skipping endorser of group %s: %s", group, err)
+				continue
+			}
+			// a peer endorses for one group only, as a signature satisfies a single principal
+			if used[endpoint] || failed[endpoint] {
+				continue
+			}
+			resp, exists := endorsements[endpoint]
+			if !exists {
+				resp, err = s.endorseWith(ctx, endpoint, signedProp)
+				if err != nil {
+					logger.Warningf("Failed endorsing with %s: %s", endpoint, err)
+					failed[endpoint] = true
+					continue
+				}
+				endorsements[endpoint] = resp
+			}
+			used[endpoint] = true
+			responses = append(responses, resp)
+			endorsed++
+		}
+		if endorsed < required {
+			return nil, errors.Errorf("collected %d out of %d endorsements from group %s", endorsed, required, group)
+		}
+	}
+	return responses, nil
+}
+
+// endorseWith sends the proposal to the endorser of the given endpoint
+// and returns its response if it successfully endorsed the proposal
+func (s *Server) endorseWith(ctx context.Context, endpoint string, signedProp *pb.SignedProposal) (*pb.ProposalResponse, error) {
+	endorser, err := s.endorsers.Endorser(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := endorser.ProcessProposal(ctx, signedProp)
+	if status.Code(err) == codes.Unavailable {
+		s.endorsers.Remove(endpoint)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.Response == nil || resp.Response.Status >= shim.ERRORTHRESHOLD {
+		return nil, errors.Errorf("proposal was not endorsed: %v", resp.Response)
+	}
+	if resp.Endorsement == nil {
+		return nil, errors.New("proposal response carries no endorsement")
+	}
+	return resp, nil
+}
+
+// peerEndpoint returns the endpoint advertised in the membership info of the given peer
+func peerEndpoint(p *discprotos.Peer) (string, error) {
+	if p.MembershipInfo == nil {
+		return "", errors.New("peer has no membership info")
+	}
+	msg, err := p.MembershipInfo.ToGossipMessage()
+	if err != nil {
+		return "", errors.WithMessage(err, "malformed membership info")
+	}
+	endpoint := msg.GetAliveMsg().GetMembership().GetEndpoint()
+	if endpoint == "" {
+		return "", errors.New("peer has no endpoint")
+	}
+	return endpoint, nil
+}
@@ -0,0 +1,285 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	cb "github.com/hyperledger/fabric/protos/common"
+	gp "github.com/hyperledger/fabric/protos/gateway"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var logger = flogging.MustGetLogger("gateway")
+
+// Broadcaster submits transactions to the ordering service of a channel
+type Broadcaster interface {
+	// Broadcast submits the given transaction envelope and returns once it is accepted
+	Broadcast(ctx context.Context, channelID string, env *cb.Envelope) error
+}
+
+// CommitFinder finds the commit status of transactions
+type CommitFinder interface {
+	// Height returns the height of the ledger of the given channel
+	Height(channelID string) (uint64, error)
+	// TransactionStatus returns the commit status of the given transaction,
+	// or nil if it isn't committed yet
+	TransactionStatus(channelID string, txID string) (*gp.CommitStatus, error)
+	// WaitForCommit returns the commit status of the given transaction,
+	// looking for it from the given block and waiting for it to be committed
+	WaitForCommit(ctx context.Context, channelID string, txID string, fromBlock uint64) (*gp.CommitStatus, error)
+}
+
+// ConflictChecker checks the read sets of transactions against the committed state
+type ConflictChecker interface {
+	// CheckConflicts checks the given marshaled simulation results against
+	// the committed state of the given channel
+	CheckConflicts(channelID string, txRWSetBytes []byte) (*gp.ConflictCheck, error)
+}
+
+// ACLProvider checks access to the resources of a channel
+type ACLProvider interface {
+	// CheckACL checks the ACL of the given resource of the channel against the given identity info
+	CheckACL(resName string, channelID string, idinfo interface{}) error
+}
+
+// Default timeouts of the gateway service, used when they aren't configured
+const (
+	DefaultEndorsementTimeout = 30 * time.Second
+	DefaultBroadcastTimeout   = 30 * time.Second
+	DefaultCommitTimeout      = 5 * time.Minute
+)
+
+// Config holds the settings of the gateway
+type Config struct {
+	// EndorsementTimeout bounds the time to collect the endorsements of a transaction
+	EndorsementTimeout time.Duration
+	// BroadcastTimeout bounds the time to submit a transaction to the ordering service
+	BroadcastTimeout time.Duration
+	// CommitTimeout bounds the time to wait for the commit of a transaction
+	CommitTimeout time.Duration
+}
+
+// Server implements the gateway service
+type Server struct {
+	config          Config
+	planner         EndorsementPlanner
+	endorsers       Endorsers
+	broadcaster     Broadcaster
+	commitFinder    CommitFinder
+	conflictChecker ConflictChecker
+	aclProvider     ACLProvider
+}
+
+// NewServer creates a gateway server which collects endorsements according to
+// the layouts of the given planner, from the peers of the given endorsers,
+// and serves commit statuses and conflict checks to the clients the given ACL
+// provider allows
+func NewServer(config Config, planner EndorsementPlanner, endorsers Endorsers, broadcaster Broadcaster, commitFinder CommitFinder, conflictChecker ConflictChecker, aclProvider ACLProvider) *Server {
+	return &Server{
+		config:          config,
+		planner:         planner,
+		endorsers:       endorsers,
+		broadcaster:     broadcaster,
+		commitFinder:    commitFinder,
+		conflictChecker: conflictChecker,
+		aclProvider:     aclProvider,
+	}
+}
+
+// Transact endorses, submits and awaits the commit of the transaction of the
+// proposal sent by the client, which is asked to sign the endorsed transaction
+func (s *Server) Transact(srv gp.Gateway_TransactServer) error {
+	req, err := srv.Recv()
+	if err != nil {
+		return err
+	}
+	signedProp := req.GetProposal()
+	if signedProp == nil {
+		return errors.New("the first request must carry a signed proposal")
+	}
+
+	prop, err := utils.GetProposal(signedProp.ProposalBytes)
+	if err != nil {
+		return errors.WithMessage(err, "malformed proposal")
+	}
+	channelID, txID, ccName, err := proposalInfo(prop)
+	if err != nil {
+		return err
+	}
+	logger.Debugf("[channel: %s] Collecting endorsements of transaction %s for chaincode %s", channelID, txID, ccName)
+
+	endorseCtx, cancel := context.WithTimeout(srv.Context(), s.config.EndorsementTimeout)
+	responses, err := s.endorse(endorseCtx, channelID, ccName, signedProp)
+	cancel()
+	if err != nil {
+		return errors.WithMessage(err, "failed collecting endorsements")
+	}
+
+	env, err := utils.CreateUnsignedTx(prop, responses...)
+	if err != nil {
+		return errors.WithMessage(err, "failed assembling transaction")
+	}
+	err = srv.Send(&gp.TransactResponse{
+		Type: &gp.TransactResponse_Prepared{
+			Prepared: &gp.PreparedTransaction{
+				TxId:     txID,
+				Payload:  env.Payload,
+				Response: responses[0].Response,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err = srv.Recv()
+	if err != nil {
+		return err
+	}
+	if req.GetSignature() == nil {
+		return errors.New("the second request must carry the signature of the transaction")
+	}
+	env.Signature = req.GetSignature()
+
+	// the height is taken before the submission, so that the transaction is committed above it
+	height, err := s.commitFinder.Height(channelID)
+	if err != nil {
+		return errors.WithMessage(err, "failed retrieving ledger height")
+	}
+
+	broadcastCtx, cancel := context.WithTimeout(srv.Context(), s.config.BroadcastTimeout)
+	err = s.broadcaster.Broadcast(broadcastCtx, channelID, env)
+	cancel()
+	if err != nil {
+		return errors.WithMessage(err, "failed submitting transaction")
+	}
+	logger.Debugf("[channel: %s] Submitted transaction %s", channelID, txID)
+
+	commitCtx, cancel := context.WithTimeout(srv.Context(), s.config.CommitTimeout)
+	status, err := s.commitFinder.WaitForCommit(commitCtx, channelID, txID, height)
+	cancel()
+	if err != nil {
+		return errors.WithMessage(err, "failed waiting for commit")
+	}
+
+	return srv.Send(&gp.TransactResponse{
+		Type: &gp.TransactResponse_Committed{Committed: status},
+	})
+}
+
+// CommitStatus returns the commit status of the requested transaction, waiting for its
+// commit if needed, provided the client satisfies the ACL of the gateway/CommitStatus resource
+func (s *Server) CommitStatus(ctx context.Context, signedReq *gp.SignedCommitStatusRequest) (*gp.CommitStatus, error) {
+	req := &gp.CommitStatusRequest{}
+	if err := proto.Unmarshal(signedReq.Request, req); err != nil {
+		return nil, errors.Wrap(err, "malformed commit status request")
+	}
+	if req.ChannelId == "" || req.TxId == "" {
+		return nil, errors.New("commit status request must specify a channel and a transaction ID")
+	}
+	err := s.aclProvider.CheckACL(resources.Gateway_CommitStatus, req.ChannelId, &cb.SignedData{
+		Data:      signedReq.Request,
+		Identity:  req.Identity,
+		Signature: signedReq.Signature,
+	})
+	if err != nil {
+		logger.Warningf("[channel: %s] Access denied to commit status of transaction %s: %s", req.ChannelId, req.TxId, err)
+		return nil, status.Error(codes.PermissionDenied, "access denied")
+	}
+
+	// the height is taken before looking up the transaction, so that it is found
+	// above it if it gets committed in between
+	height, err := s.commitFinder.Height(req.ChannelId)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed retrieving ledger height")
+	}
+	committed, err := s.commitFinder.TransactionStatus(req.ChannelId, req.TxId)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed looking up transaction")
+	}
+	if committed != nil {
+		return committed, nil
+	}
+
+	commitCtx, cancel := context.WithTimeout(ctx, s.config.CommitTimeout)
+	defer cancel()
+	committed, err = s.commitFinder.WaitForCommit(commitCtx, req.ChannelId, req.TxId, height)
+	if err != nil {
+		if commitCtx.Err() == context.DeadlineExceeded {
+			return nil, status.Errorf(codes.DeadlineExceeded, "transaction %s was not committed in time", req.TxId)
+		}
+		return nil, errors.WithMessage(err, "failed waiting for commit")
+	}
+	return committed, nil
+}
+
+// CheckConflicts checks the simulation results of the requested proposal response payload against the
+// committed state, provided the client satisfies the ACL of the gateway/CheckConflicts resource
+func (s *Server) CheckConflicts(ctx context.Context, signedReq *gp.SignedConflictCheckRequest) (*gp.ConflictCheck, error) {
+	req := &gp.ConflictCheckRequest{}
+	if err := proto.Unmarshal(signedReq.Request, req); err != nil {
+		return nil, errors.Wrap(err, "malformed conflict check request")
+	}
+	if req.ChannelId == "" {
+		return nil, errors.New("conflict check request must specify a channel")
+	}
+	err := s.aclProvider.CheckACL(resources.Gateway_CheckConflicts, req.ChannelId, &cb.SignedData{
+		Data:      signedReq.Request,
+		Identity:  req.Identity,
+		Signature: signedReq.Signature,
+	})
+	if err != nil {
+		logger.Warningf("[channel: %s] Access denied to conflict check: %s", req.ChannelId, err)
+		return nil, status.Error(codes.PermissionDenied, "access denied")
+	}
+
+	prp, err := utils.GetProposalResponsePayload(req.ProposalResponsePayload)
+	if err != nil {
+		return nil, errors.WithMessage(err, "malformed proposal response payload")
+	}
+	ccAction, err := utils.GetChaincodeAction(prp.Extension)
+	if err != nil {
+		return nil, errors.WithMessage(err, "malformed chaincode action")
+	}
+	check, err := s.conflictChecker.CheckConflicts(req.ChannelId, ccAction.Results)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed checking read set")
+	}
+	return check, nil
+}
+
+// proposalInfo returns the channel, transaction ID and chaincode name of the given proposal
+func proposalInfo(prop *pb.Proposal) (channelID string, txID string, ccName string, err error) {
+	hdr, err := utils.GetHeader(prop.Header)
+	if err != nil {
+		return "", "", "", errors.WithMessage(err, "malformed proposal header")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(hdr.ChannelHeader)
+	if err != nil {
+		return "", "", "", errors.WithMessage(err, "malformed proposal channel header")
+	}
+	if chdr.ChannelId == "" {
+		return "", "", "", errors.New("proposal must target a channel")
+	}
+	ext, err := utils.GetChaincodeHeaderExtension(hdr)
+	if err != nil {
+		return "", "", "", errors.WithMessage(err, "malformed proposal chaincode header extension")
+	}
+	if ext.ChaincodeId == nil || ext.ChaincodeId.Name == "" {
+		return "", "", "", errors.New("proposal must target a chaincode")
+	}
+	return chdr.ChannelId, chdr.TxId, ext.ChaincodeId.Name, nil
+}
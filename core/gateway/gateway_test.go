@@ -0,0 +1,444 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	gcommon "github.com/hyperledger/fabric/gossip/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	discprotos "github.com/hyperledger/fabric/protos/discovery"
+	gp "github.com/hyperledger/fabric/protos/gateway"
+	"github.com/hyperledger/fabric/protos/gossip"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type fakePlanner struct {
+	desc *discprotos.EndorsementDescriptor
+	err  error
+}
+
+func (p *fakePlanner) PeersForEndorsement(_ gcommon.ChainID, _ *discprotos.ChaincodeInterest) (*discprotos.EndorsementDescriptor, error) {
+	return p.desc, p.err
+}
+
+// fakeEndorser endorses with the given status, or fails with the given error,
+// and records the proposals it processed
+type fakeEndorser struct {
+	endpoint  string
+	status    int32
+	err       error
+	processed int
+}
+
+func (e *fakeEndorser) ProcessProposal(_ context.Context, _ *pb.SignedProposal) (*pb.ProposalResponse, error) {
+	e.processed++
+	if e.err != nil {
+		return nil, e.err
+	}
+	return &pb.ProposalResponse{
+		Payload:     []byte("payload"),
+		Response:    &pb.Response{Status: e.status, Payload: []byte("result")},
+		Endorsement: &pb.Endorsement{Endorser: []byte(e.endpoint)},
+	}, nil
+}
+
+type fakeEndorsers map[string]*fakeEndorser
+
+func (f fakeEndorsers) Endorser(endpoint string) (Endorser, error) {
+	e, exists := f[endpoint]
+	if !exists {
+		return nil, errors.Errorf("unknown peer %s", endpoint)
+	}
+	return e, nil
+}
+
+func (f fakeEndorsers) Remove(endpoint string) {
+	delete(f, endpoint)
+}
+
+type fakeBroadcaster struct {
+	env *cb.Envelope
+	err error
+}
+
+func (b *fakeBroadcaster) Broadcast(_ context.Context, _ string, env *cb.Envelope) error {
+	b.env = env
+	return b.err
+}
+
+type fakeCommitFinder struct {
+	fromBlock uint64
+	// committed is the status of a transaction already in the ledger
+	committed *gp.CommitStatus
+	// pending makes WaitForCommit wait until its context is done
+	pending bool
+}
+
+func (f *fakeCommitFinder) Height(_ string) (uint64, error) {
+	return 10, nil
+}
+
+func (f *fakeCommitFinder) TransactionStatus(_ string, _ string) (*gp.CommitStatus, error) {
+	return f.committed, nil
+}
+
+func (f *fakeCommitFinder) WaitForCommit(ctx context.Context, _ string, txID string, fromBlock uint64) (*gp.CommitStatus, error) {
+	f.fromBlock = fromBlock
+	if f.pending {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return &gp.CommitStatus{TxId: txID, ValidationCode: pb.TxValidationCode_VALID, BlockNumber: fromBlock}, nil
+}
+
+// fakeConflictChecker returns the given check and records the simulation results it checked
+type fakeConflictChecker struct {
+	check   *gp.ConflictCheck
+	checked []byte
+}
+
+func (c *fakeConflictChecker) CheckConflicts(_ string, txRWSetBytes []byte) (*gp.ConflictCheck, error) {
+	c.checked = txRWSetBytes
+	return c.check, nil
+}
+
+// fakeACLProvider allows the identities in the set to access the commit statuses and conflict checks
+type fakeACLProvider map[string]bool
+
+func (p fakeACLProvider) CheckACL(resName string, _ string, idinfo interface{}) error {
+	sd, ok := idinfo.(*cb.SignedData)
+	if resName != resources.Gateway_CommitStatus && resName != resources.Gateway_CheckConflicts {
+		return errors.New("access denied")
+	}
+	if !ok || !p[string(sd.Identity)] {
+		return errors.New("access denied")
+	}
+	return nil
+}
+
+// fakeStream serves the given requests and records the responses
+type fakeStream struct {
+	requests  []*gp.TransactRequest
+	responses []*gp.TransactResponse
+}
+
+func (s *fakeStream) Send(resp *gp.TransactResponse) error {
+	s.responses = append(s.responses, resp)
+	return nil
+}
+
+func (s *fakeStream) Recv() (*gp.TransactRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+	req := s.requests[0]
+	s.requests = s.requests[1:]
+	return req, nil
+}
+
+func (s *fakeStream) Context() context.Context     { return context.Background() }
+func (s *fakeStream) SetHeader(metadata.MD) error  { return nil }
+func (s *fakeStream) SendHeader(metadata.MD) error { return nil }
+func (s *fakeStream) SetTrailer(metadata.MD)       {}
+func (s *fakeStream) SendMsg(interface{}) error    { return nil }
+func (s *fakeStream) RecvMsg(interface{}) error    { return nil }
+
+func discoveredPeer(endpoint string) *discprotos.Peer {
+	msg := &gossip.GossipMessage{
+		Content: &gossip.GossipMessage_AliveMsg{
+			AliveMsg: &gossip.AliveMessage{Membership: &gossip.Member{Endpoint: endpoint}},
+		},
+	}
+	return &discprotos.Peer{
+		MembershipInfo: &gossip.Envelope{Payload: utils.MarshalOrPanic(msg)},
+	}
+}
+
+func createSignedProposal(t *testing.T) (*pb.SignedProposal, string) {
+	prop, txID, err := utils.CreateChaincodeProposal(cb.HeaderType_ENDORSER_TRANSACTION, "mychannel", &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "mycc"}},
+	}, []byte("creator"))
+	require.NoError(t, err)
+	return &pb.SignedProposal{ProposalBytes: utils.MarshalOrPanic(prop)}, txID
+}
+
+func newTestServer(desc *discprotos.EndorsementDescriptor, endorsers fakeEndorsers, broadcaster *fakeBroadcaster, commitFinder *fakeCommitFinder) *Server {
+	return NewServer(Config{
+		EndorsementTimeout: time.Second,
+		BroadcastTimeout:   time.Second,
+		CommitTimeout:      time.Second,
+	}, &fakePlanner{desc: desc}, endorsers, broadcaster, commitFinder, nil, fakeACLProvider{"reader": true})
+}
+
+func TestTransact(t *testing.T) {
+	desc := &discprotos.EndorsementDescriptor{
+		Chaincode: "mycc",
+		EndorsersByGroups: map[string]*discprotos.Peers{
+			"G0": {Peers: []*discprotos.Peer{discoveredPeer("p0:7051")}},
+			"G1": {Peers: []*discprotos.Peer{discoveredPeer("p1:7051"), discoveredPeer("p2:7051")}},
+		},
+		Layouts: []*discprotos.Layout{
+			{QuantitiesByGroup: map[string]uint32{"G0": 1, "G1": 2}},
+		},
+	}
+	endorsers := fakeEndorsers{
+		"p0:7051": {endpoint: "p0:7051", status: 200},
+		"p1:7051": {endpoint: "p1:7051", status: 200},
+		"p2:7051": {endpoint: "p2:7051", status: 200},
+	}
+	broadcaster := &fakeBroadcaster{}
+	commitFinder := &fakeCommitFinder{}
+	server := newTestServer(desc, endorsers, broadcaster, commitFinder)
+
+	signedProp, txID := createSignedProposal(t)
+	stream := &fakeStream{requests: []*gp.TransactRequest{
+		{Type: &gp.TransactRequest_Proposal{Proposal: signedProp}},
+		{Type: &gp.TransactRequest_Signature{Signature: []byte("signature")}},
+	}}
+	err := server.Transact(stream)
+	require.NoError(t, err)
+	require.Len(t, stream.responses, 2)
+
+	prepared := stream.responses[0].GetPrepared()
+	require.NotNil(t, prepared)
+	assert.Equal(t, txID, prepared.TxId)
+	assert.Equal(t, []byte("result"), prepared.Response.Payload)
+
+	// the submitted transaction is the prepared one, signed by the client
+	require.NotNil(t, broadcaster.env)
+	assert.Equal(t, prepared.Payload, broadcaster.env.Payload)
+	assert.Equal(t, []byte("signature"), broadcaster.env.Signature)
+	payload, err := utils.UnmarshalPayload(broadcaster.env.Payload)
+	require.NoError(t, err)
+	tx, err := utils.GetTransaction(payload.Data)
+	require.NoError(t, err)
+	cap, err := utils.GetChaincodeActionPayload(tx.Actions[0].Payload)
+	require.NoError(t, err)
+	var endorsersOfTx []string
+	for _, e := range cap.Action.Endorsements {
+		endorsersOfTx = append(endorsersOfTx, string(e.Endorser))
+	}
+	assert.Equal(t, []string{"p0:7051", "p1:7051", "p2:7051"}, endorsersOfTx)
+
+	committed := stream.responses[1].GetCommitted()
+	require.NotNil(t, committed)
+	assert.True(t, proto.Equal(&gp.CommitStatus{TxId: txID, ValidationCode: pb.TxValidationCode_VALID, BlockNumber: 10}, committed))
+	assert.Equal(t, uint64(10), commitFinder.fromBlock)
+}
+
+func TestTransactFailures(t *testing.T) {
+	desc := &discprotos.EndorsementDescriptor{
+		EndorsersByGroups: map[string]*discprotos.Peers{
+			"G0": {Peers: []*discprotos.Peer{discoveredPeer("p0:7051")}},
+		},
+		Layouts: []*discprotos.Layout{{QuantitiesByGroup: map[string]uint32{"G0": 1}}},
+	}
+	signedProp, _ := createSignedProposal(t)
+	proposalRequest := &gp.TransactRequest{Type: &gp.TransactRequest_Proposal{Proposal: signedProp}}
+	signatureRequest := &gp.TransactRequest{Type: &gp.TransactRequest_Signature{Signature: []byte("signature")}}
+
+	t.Run("first request without proposal", func(t *testing.T) {
+		server := newTestServer(desc, fakeEndorsers{"p0:7051": {status: 200}}, &fakeBroadcaster{}, &fakeCommitFinder{})
+		err := server.Transact(&fakeStream{requests: []*gp.TransactRequest{signatureRequest}})
+		assert.EqualError(t, err, "the first request must carry a signed proposal")
+	})
+
+	t.Run("malformed proposal", func(t *testing.T) {
+		server := newTestServer(desc, fakeEndorsers{"p0:7051": {status: 200}}, &fakeBroadcaster{}, &fakeCommitFinder{})
+		err := server.Transact(&fakeStream{requests: []*gp.TransactRequest{
+			{Type: &gp.TransactRequest_Proposal{Proposal: &pb.SignedProposal{ProposalBytes: []byte("garbage")}}},
+		}})
+		assert.Contains(t, err.Error(), "malformed proposal")
+	})
+
+	t.Run("endorsement refused", func(t *testing.T) {
+		stream := &fakeStream{requests: []*gp.TransactRequest{proposalRequest, signatureRequest}}
+		server := newTestServer(desc, fakeEndorsers{"p0:7051": {status: 500}}, &fakeBroadcaster{}, &fakeCommitFinder{})
+		err := server.Transact(stream)
+		assert.Contains(t, err.Error(), "failed collecting endorsements")
+		assert.Empty(t, stream.responses)
+	})
+
+	t.Run("endorser unreachable", func(t *testing.T) {
+		stream := &fakeStream{requests: []*gp.TransactRequest{proposalRequest, signatureRequest}}
+		endorsers := fakeEndorsers{"p0:7051": {err: status.Error(codes.Unavailable, "connection refused")}}
+		server := newTestServer(desc, endorsers, &fakeBroadcaster{}, &fakeCommitFinder{})
+		err := server.Transact(stream)
+		assert.Contains(t, err.Error(), "failed collecting endorsements")
+		// the connection to the endorser is discarded
+		assert.NotContains(t, endorsers, "p0:7051")
+	})
+
+	t.Run("missing signature", func(t *testing.T) {
+		broadcaster := &fakeBroadcaster{}
+		server := newTestServer(desc, fakeEndorsers{"p0:7051": {status: 200}}, broadcaster, &fakeCommitFinder{})
+		err := server.Transact(&fakeStream{requests: []*gp.TransactRequest{proposalRequest, proposalRequest}})
+		assert.EqualError(t, err, "the second request must carry the signature of the transaction")
+		assert.Nil(t, broadcaster.env)
+	})
+
+	t.Run("broadcast failure", func(t *testing.T) {
+		stream := &fakeStream{requests: []*gp.TransactRequest{proposalRequest, signatureRequest}}
+		server := newTestServer(desc, fakeEndorsers{"p0:7051": {status: 200}}, &fakeBroadcaster{err: errors.New("orderer down")}, &fakeCommitFinder{})
+		err := server.Transact(stream)
+		assert.EqualError(t, err, "failed submitting transaction: orderer down")
+		assert.Len(t, stream.responses, 1)
+	})
+}
+
+func TestEndorseLayoutFallback(t *testing.T) {
+	desc := &discprotos.EndorsementDescriptor{
+		EndorsersByGroups: map[string]*discprotos.Peers{
+			"G0": {Peers: []*discprotos.Peer{discoveredPeer("p0:7051")}},
+			"G1": {Peers: []*discprotos.Peer{discoveredPeer("p1:7051"), discoveredPeer("p2:7051")}},
+			"G2": {Peers: []*discprotos.Peer{discoveredPeer("p0:7051"), discoveredPeer("p3:7051")}},
+		},
+		Layouts: []*discprotos.Layout{
+			// p1 refuses to endorse, hence this layout can't be satisfied
+			{QuantitiesByGroup: map[string]uint32{"G0": 1, "G1": 2}},
+			// p0 endorses for G0 only, hence p3 is needed for G2
+			{QuantitiesByGroup: map[string]uint32{"G0": 1, "G2": 1}},
+		},
+	}
+	endorsers := fakeEndorsers{
+		"p0:7051": {endpoint: "p0:7051", status: 200},
+		"p1:7051": {endpoint: "p1:7051", status: 500},
+		"p2:7051": {endpoint: "p2:7051", status: 200},
+		"p3:7051": {endpoint: "p3:7051", status: 200},
+	}
+	server := newTestServer(desc, endorsers, &fakeBroadcaster{}, &fakeCommitFinder{})
+	signedProp, _ := createSignedProposal(t)
+
+	responses, err := server.endorse(context.Background(), "mychannel", "mycc", signedProp)
+	require.NoError(t, err)
+	var endorsersOfTx []string
+	for _, resp := range responses {
+		endorsersOfTx = append(endorsersOfTx, string(resp.Endorsement.Endorser))
+	}
+	assert.Equal(t, []string{"p0:7051", "p3:7051"}, endorsersOfTx)
+	// the endorsements of the first layout are reused, and failed peers aren't retried
+	assert.Equal(t, 1, endorsers["p0:7051"].processed)
+	assert.Equal(t, 1, endorsers["p1:7051"].processed)
+
+	server = newTestServer(&discprotos.EndorsementDescriptor{}, endorsers, &fakeBroadcaster{}, &fakeCommitFinder{})
+	_, err = server.endorse(context.Background(), "mychannel", "mycc", signedProp)
+	assert.EqualError(t, err, "no endorsement layout found for chaincode mycc")
+}
+
+func TestCommitStatus(t *testing.T) {
+	signedRequest := func(identity string, txID string) *gp.SignedCommitStatusRequest {
+		return &gp.SignedCommitStatusRequest{
+			Request: utils.MarshalOrPanic(&gp.CommitStatusRequest{
+				ChannelId: "mychannel",
+				TxId:      txID,
+				Identity:  []byte(identity),
+			}),
+			Signature: []byte("signature"),
+		}
+	}
+
+	t.Run("already committed", func(t *testing.T) {
+		committed := &gp.CommitStatus{TxId: "tx1", ValidationCode: pb.TxValidationCode_MVCC_READ_CONFLICT, BlockNumber: 3}
+		server := newTestServer(nil, nil, nil, &fakeCommitFinder{committed: committed})
+		res, err := server.CommitStatus(context.Background(), signedRequest("reader", "tx1"))
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(committed, res))
+	})
+
+	t.Run("committed later", func(t *testing.T) {
+		commitFinder := &fakeCommitFinder{}
+		server := newTestServer(nil, nil, nil, commitFinder)
+		res, err := server.CommitStatus(context.Background(), signedRequest("reader", "tx1"))
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(&gp.CommitStatus{TxId: "tx1", ValidationCode: pb.TxValidationCode_VALID, BlockNumber: 10}, res))
+		assert.Equal(t, uint64(10), commitFinder.fromBlock)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		server := newTestServer(nil, nil, nil, &fakeCommitFinder{pending: true})
+		server.config.CommitTimeout = 10 * time.Millisecond
+		_, err := server.CommitStatus(context.Background(), signedRequest("reader", "tx1"))
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	})
+
+	t.Run("access denied", func(t *testing.T) {
+		server := newTestServer(nil, nil, nil, &fakeCommitFinder{})
+		_, err := server.CommitStatus(context.Background(), signedRequest("stranger", "tx1"))
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("malformed request", func(t *testing.T) {
+		server := newTestServer(nil, nil, nil, &fakeCommitFinder{})
+		_, err := server.CommitStatus(context.Background(), &gp.SignedCommitStatusRequest{Request: []byte("garbage")})
+		assert.Contains(t, err.Error(), "malformed commit status request")
+		_, err = server.CommitStatus(context.Background(), signedRequest("reader", ""))
+		assert.EqualError(t, err, "commit status request must specify a channel and a transaction ID")
+	})
+}
+
+func TestCheckConflicts(t *testing.T) {
+	signedRequest := func(identity string, prp []byte) *gp.SignedConflictCheckRequest {
+		return &gp.SignedConflictCheckRequest{
+			Request: utils.MarshalOrPanic(&gp.ConflictCheckRequest{
+				ChannelId:               "mychannel",
+				ProposalResponsePayload: prp,
+				Identity:                []byte(identity),
+			}),
+			Signature: []byte("signature"),
+		}
+	}
+	prp := utils.MarshalOrPanic(&pb.ProposalResponsePayload{
+		Extension: utils.MarshalOrPanic(&pb.ChaincodeAction{Results: []byte("results")}),
+	})
+	check := &gp.ConflictCheck{
+		ValidationCode: pb.TxValidationCode_MVCC_READ_CONFLICT,
+		Conflict:       &gp.ReadConflict{Namespace: "mycc", Key: "key1"},
+	}
+
+	t.Run("checked", func(t *testing.T) {
+		checker := &fakeConflictChecker{check: check}
+		server := newTestServer(nil, nil, nil, nil)
+		server.conflictChecker = checker
+		res, err := server.CheckConflicts(context.Background(), signedRequest("reader", prp))
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(check, res))
+		assert.Equal(t, []byte("results"), checker.checked)
+	})
+
+	t.Run("access denied", func(t *testing.T) {
+		server := newTestServer(nil, nil, nil, nil)
+		server.conflictChecker = &fakeConflictChecker{check: check}
+		_, err := server.CheckConflicts(context.Background(), signedRequest("stranger", prp))
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("malformed request", func(t *testing.T) {
+		server := newTestServer(nil, nil, nil, nil)
+		server.conflictChecker = &fakeConflictChecker{check: check}
+		_, err := server.CheckConflicts(context.Background(), &gp.SignedConflictCheckRequest{Request: []byte("garbage")})
+		assert.Contains(t, err.Error(), "malformed conflict check request")
+		_, err = server.CheckConflicts(context.Background(), &gp.SignedConflictCheckRequest{
+			Request: utils.MarshalOrPanic(&gp.ConflictCheckRequest{Identity: []byte("reader")}),
+		})
+		assert.EqualError(t, err, "conflict check request must specify a channel")
+		_, err = server.CheckConflicts(context.Background(), signedRequest("reader", []byte("garbage")))
+		assert.Contains(t, err.Error(), "malformed proposal response payload")
+	})
+}
@@ -0,0 +1,299 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/tracing"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	cb "github.com/hyperledger/fabric/protos/common"
+	gp "github.com/hyperledger/fabric/protos/gateway"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// EndorserRegistry provides the endorsers of peers, serving the proposals
+// for the local peer in process and dialing the remote peers on demand. The
+// connections to the remote peers are kept until they are removed or the
+// registry is closed.
+type EndorserRegistry struct {
+	localEndpoint string
+	localEndorser pb.EndorserServer
+	dial          func(endpoint string) (*grpc.ClientConn, error)
+
+	lock    sync.Mutex
+	remotes map[string]*remoteEndorser
+}
+
+// NewEndorserRegistry creates an EndorserRegistry which serves the proposals for
+// the given local endpoint with the given endorser, and dials other peers with dial
+func NewEndorserRegistry(localEndpoint string, localEndorser pb.EndorserServer, dial func(endpoint string) (*grpc.ClientConn, error)) *EndorserRegistry {
+	return &EndorserRegistry{
+		localEndpoint: localEndpoint,
+		localEndorser: localEndorser,
+		dial:          dial,
+		remotes:       make(map[string]*remoteEndorser),
+	}
+}
+
+// Endorser returns the endorser of the peer with the given endpoint
+func (r *EndorserRegistry) Endorser(endpoint string) (Endorser, error) {
+	if endpoint == r.localEndpoint {
+		return r.localEndorser, nil
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if endorser, exists := r.remotes[endpoint]; exists {
+		return endorser, nil
+	}
+	conn, err := r.dial(endpoint)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed connecting to "+endpoint)
+	}
+	endorser := &remoteEndorser{conn: conn, client: pb.NewEndorserClient(conn)}
+	r.remotes[endpoint] = endorser
+	return endorser, nil
+}
+
+// Remove closes the connection to the peer with the given endpoint, which is
+// dialed again the next time its endorser is requested
+func (r *EndorserRegistry) Remove(endpoint string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if endorser, exists := r.remotes[endpoint]; exists {
+		endorser.conn.Close()
+		delete(r.remotes, endpoint)
+	}
+}
+
+// Close closes the connections to all the remote peers
+func (r *EndorserRegistry) Close() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for endpoint, endorser := range r.remotes {
+		endorser.conn.Close()
+		delete(r.remotes, endpoint)
+	}
+}
+
+// remoteEndorser adapts an EndorserClient to the Endorser interface
+type remoteEndorser struct {
+	conn   *grpc.ClientConn
+	client pb.EndorserClient
+}
+
+func (e *remoteEndorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedProposal) (*pb.ProposalResponse, error) {
+	return e.client.ProcessProposal(tracing.NewOutgoingContext(ctx), signedProp)
+}
+
+// OrdererBroadcaster submits transactions to one of the orderers of a channel
+type OrdererBroadcaster struct {
+	// OrdererAddresses returns the orderer endpoints of a channel
+	OrdererAddresses func(channelID string) []string
+	// Dial connects to the given orderer endpoint of a channel
+	Dial func(channelID string, endpoint string) (*grpc.ClientConn, error)
+}
+
+// Broadcast submits the given envelope to the orderers of the channel, in random
+// order, until one of them accepts it
+func (b *OrdererBroadcaster) Broadcast(ctx context.Context, channelID string, env *cb.Envelope) error {
+	addresses := b.OrdererAddresses(channelID)
+	if len(addresses) == 0 {
+		return errors.Errorf("no orderer found for channel %s", channelID)
+	}
+
+	var err error
+	for _, i := range rand.Perm(len(addresses)) {
+		if err = b.broadcastTo(ctx, channelID, addresses[i], env); err == nil {
+			return nil
+		}
+		logger.Warningf("[channel: %s] Failed broadcasting to %s: %s", channelID, addresses[i], err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return err
+}
+
+func (b *OrdererBroadcaster) broadcastTo(ctx context.Context, channelID string, address string, env *cb.Envelope) error {
+	conn, err := b.Dial(channelID, address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client, err := ab.NewAtomicBroadcastClient(conn).Broadcast(tracing.NewOutgoingContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer client.CloseSend()
+	if err := client.Send(env); err != nil {
+		return err
+	}
+	resp, err := client.Recv()
+	if err != nil {
+		return err
+	}
+	if resp.Status != cb.Status_SUCCESS {
+		return errors.Errorf("transaction rejected with status %s: %s", resp.Status, resp.Info)
+	}
+	return nil
+}
+
+// LedgerCommitFinder finds the commit status of transactions in the ledgers of the peer
+type LedgerCommitFinder struct {
+	// GetLedger returns the ledger of a channel, or nil if the peer hasn't joined the channel
+	GetLedger func(channelID string) ledger.PeerLedger
+}
+
+func (f *LedgerCommitFinder) ledger(channelID string) (ledger.PeerLedger, error) {
+	l := f.GetLedger(channelID)
+	if l == nil {
+		return nil, errors.Errorf("channel %s not found", channelID)
+	}
+	return l, nil
+}
+
+// Height returns the height of the ledger of the given channel
+func (f *LedgerCommitFinder) Height(channelID string) (uint64, error) {
+	l, err := f.ledger(channelID)
+	if err != nil {
+		return 0, err
+	}
+	info, err := l.GetBlockchainInfo()
+	if err != nil {
+		return 0, err
+	}
+	return info.Height, nil
+}
+
+// TransactionStatus returns the commit status of the given transaction
+// if it is in the ledger of the channel, or nil otherwise
+func (f *LedgerCommitFinder) TransactionStatus(channelID string, txID string) (*gp.CommitStatus, error) {
+	l, err := f.ledger(channelID)
+	if err != nil {
+		return nil, err
+	}
+	block, err := l.GetBlockByTxID(txID)
+	if _, notFound := err.(ledger.NotFoundInIndexErr); notFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	status, _ := findTransaction(block, txID)
+	return status, nil
+}
+
+// WaitForCommit reads the blocks of the channel from the given one until it finds the given transaction
+func (f *LedgerCommitFinder) WaitForCommit(ctx context.Context, channelID string, txID string, fromBlock uint64) (*gp.CommitStatus, error) {
+	l, err := f.ledger(channelID)
+	if err != nil {
+		return nil, err
+	}
+	itr, err := l.GetBlocksIterator(fromBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		// closing the iterator releases a pending Next
+		itr.Close()
+	}()
+
+	for {
+		res, err := itr.Next()
+		if err != nil {
+			return nil, err
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		block, ok := res.(*cb.Block)
+		if !ok || block == nil {
+			return nil, errors.New("blocks iterator closed")
+		}
+		if status, found := findTransaction(block, txID); found {
+			return status, nil
+		}
+	}
+}
+
+// LedgerConflictChecker checks the read sets of transactions against the ledgers of the peer
+type LedgerConflictChecker struct {
+	// GetLedger returns the ledger of a channel, or nil if the peer hasn't joined the channel
+	GetLedger func(channelID string) ledger.PeerLedger
+}
+
+// CheckConflicts checks the given marshaled simulation results against the
+// state of the ledger of the channel, reporting the first conflicting read
+func (c *LedgerConflictChecker) CheckConflicts(channelID string, txRWSetBytes []byte) (*gp.ConflictCheck, error) {
+	l := c.GetLedger(channelID)
+	if l == nil {
+		return nil, errors.Errorf("channel %s not found", channelID)
+	}
+	checker, ok := l.(ledger.ReadSetChecker)
+	if !ok {
+		return nil, errors.Errorf("the ledger of channel %s does not support checking read sets", channelID)
+	}
+	conflict, err := checker.FindReadConflict(txRWSetBytes)
+	if err != nil {
+		return nil, err
+	}
+	if conflict == nil {
+		return &gp.ConflictCheck{ValidationCode: pb.TxValidationCode_VALID}, nil
+	}
+	return &gp.ConflictCheck{
+		ValidationCode: conflict.ValidationCode,
+		Conflict: &gp.ReadConflict{
+			Namespace:  conflict.Namespace,
+			Collection: conflict.Collection,
+			KeyHash:    conflict.KeyHash,
+			Key:        conflict.Key,
+			EndKey:     conflict.EndKey,
+		},
+	}, nil
+}
+
+// findTransaction returns the commit status of the given transaction if it is part of the block
+func findTransaction(block *cb.Block, txID string) (*gp.CommitStatus, bool) {
+	txsFltr := util.TxValidationFlags(block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	for txIndex, envBytes := range block.Data.Data {
+		env, err := utils.GetEnvelopeFromBlock(envBytes)
+		if err != nil {
+			continue
+		}
+		payload, err := utils.GetPayload(env)
+		if err != nil || payload.Header == nil {
+			continue
+		}
+		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil || chdr.TxId != txID {
+			continue
+		}
+		return &gp.CommitStatus{
+			TxId:           txID,
+			ValidationCode: txsFltr.Flag(txIndex),
+			BlockNumber:    block.Header.Number,
+		}, true
+	}
+	return nil, false
+}
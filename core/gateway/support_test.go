@@ -0,0 +1,137 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	cb "github.com/hyperledger/fabric/protos/common"
+	gp "github.com/hyperledger/fabric/protos/gateway"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+func TestEndorserRegistry(t *testing.T) {
+	var conns []*grpc.ClientConn
+	registry := NewEndorserRegistry("local:7051", nil, func(endpoint string) (*grpc.ClientConn, error) {
+		if endpoint == "unreachable:7051" {
+			return nil, errors.New("no route to host")
+		}
+		conn, err := grpc.Dial(endpoint, grpc.WithInsecure())
+		conns = append(conns, conn)
+		return conn, err
+	})
+
+	_, err := registry.Endorser("unreachable:7051")
+	assert.EqualError(t, err, "failed connecting to unreachable:7051: no route to host")
+
+	// the connections are reused
+	e1, err := registry.Endorser("p1:7051")
+	require.NoError(t, err)
+	e1Again, err := registry.Endorser("p1:7051")
+	require.NoError(t, err)
+	assert.True(t, e1 == e1Again)
+	_, err = registry.Endorser("p2:7051")
+	require.NoError(t, err)
+	require.Len(t, conns, 2)
+
+	// removed endorsers are closed and dialed again
+	registry.Remove("p1:7051")
+	assert.Equal(t, connectivity.Shutdown, conns[0].GetState())
+	assert.NotEqual(t, connectivity.Shutdown, conns[1].GetState())
+	e1Again, err = registry.Endorser("p1:7051")
+	require.NoError(t, err)
+	assert.False(t, e1 == e1Again)
+	require.Len(t, conns, 3)
+
+	// closing the registry closes all the connections
+	registry.Close()
+	for _, conn := range conns {
+		assert.Equal(t, connectivity.Shutdown, conn.GetState())
+	}
+}
+
+func TestFindTransaction(t *testing.T) {
+	block := cb.NewBlock(7, nil)
+	for _, txID := range []string{"tx1", "tx2"} {
+		env := &cb.Envelope{
+			Payload: utils.MarshalOrPanic(&cb.Payload{
+				Header: &cb.Header{
+					ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{
+						Type:      int32(cb.HeaderType_ENDORSER_TRANSACTION),
+						ChannelId: "mychannel",
+						TxId:      txID,
+					}),
+				},
+			}),
+		}
+		block.Data.Data = append(block.Data.Data, utils.MarshalOrPanic(env))
+	}
+	txsFltr := util.NewTxValidationFlags(2)
+	txsFltr.SetFlag(0, pb.TxValidationCode_VALID)
+	txsFltr.SetFlag(1, pb.TxValidationCode_MVCC_READ_CONFLICT)
+	block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsFltr
+
+	status, found := findTransaction(block, "tx2")
+	require.True(t, found)
+	assert.Equal(t, "tx2", status.TxId)
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, status.ValidationCode)
+	assert.Equal(t, uint64(7), status.BlockNumber)
+
+	_, found = findTransaction(block, "tx3")
+	assert.False(t, found)
+}
+
+// readSetCheckerLedger is a ledger which reports the given conflict for any read set
+type readSetCheckerLedger struct {
+	ledger.PeerLedger
+	conflict *ledger.ReadConflict
+}
+
+func (l *readSetCheckerLedger) FindReadConflict(_ []byte) (*ledger.ReadConflict, error) {
+	return l.conflict, nil
+}
+
+func TestLedgerConflictChecker(t *testing.T) {
+	l := &readSetCheckerLedger{}
+	checker := &LedgerConflictChecker{
+		GetLedger: func(channelID string) ledger.PeerLedger {
+			if channelID != "mychannel" {
+				return nil
+			}
+			return l
+		},
+	}
+
+	check, err := checker.CheckConflicts("mychannel", nil)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(&gp.ConflictCheck{ValidationCode: pb.TxValidationCode_VALID}, check))
+
+	l.conflict = &ledger.ReadConflict{
+		ValidationCode: pb.TxValidationCode_PHANTOM_READ_CONFLICT,
+		Namespace:      "mycc",
+		Key:            "key1",
+		EndKey:         "key9",
+	}
+	check, err = checker.CheckConflicts("mychannel", nil)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(&gp.ConflictCheck{
+		ValidationCode: pb.TxValidationCode_PHANTOM_READ_CONFLICT,
+		Conflict:       &gp.ReadConflict{Namespace: "mycc", Key: "key1", EndKey: "key9"},
+	}, check))
+
+	_, err = checker.CheckConflicts("otherchannel", nil)
+	assert.EqualError(t, err, "channel otherchannel not found")
+}
@@ -7,6 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package auth
 
 import (
+	"context"
+
+	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/peer"
 )
 
@@ -35,3 +38,22 @@ func ChainFilters(endorser peer.EndorserServer, filters ...Filter) peer.Endorser
 
 	return filters[0]
 }
+
+type clientSignedDataKey struct{}
+
+// WithClientSignedData returns a copy of the context of a proposal carrying
+// the signed data which authenticates the client the proposal is sent on
+// behalf of. Filters which authenticate clients by other means than the
+// signature of the proposal, such as tokens, set it so that the ACLs of the
+// proposal are checked against the identity of the client instead of the
+// creator of the proposal, like a gateway terminating the client connections.
+func WithClientSignedData(ctx context.Context, signedData []*common.SignedData) context.Context {
+	return context.WithValue(ctx, clientSignedDataKey{}, signedData)
+}
+
+// ClientSignedData returns the signed data which authenticates the client of
+// the proposal of the context, nil if the client is its creator
+func ClientSignedData(ctx context.Context) []*common.SignedData {
+	signedData, _ := ctx.Value(clientSignedDataKey{}).([]*common.SignedData)
+	return signedData
+}
@@ -11,6 +11,7 @@ import (
 	"encoding/binary"
 	"testing"
 
+	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/stretchr/testify/assert"
 )
@@ -40,6 +41,14 @@ func TestChainFilters(t *testing.T) {
 		"Expected endorser to be invoked first")
 }
 
+func TestClientSignedData(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, ClientSignedData(ctx))
+
+	signedData := []*common.SignedData{{Data: []byte("data"), Identity: []byte("client"), Signature: []byte("signature")}}
+	assert.Equal(t, signedData, ClientSignedData(WithClientSignedData(ctx, signedData)))
+}
+
 func createNFilters(n int) []Filter {
 	filters := make([]Filter, n)
 	for i := 0; i < n; i++ {
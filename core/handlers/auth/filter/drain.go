@@ -0,0 +1,73 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package filter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewDrainFilter creates a new Filter that tracks the proposals being
+// processed, so that they are drained when the peer shuts down
+func NewDrainFilter() *DrainFilter {
+	return &DrainFilter{}
+}
+
+// DrainFilter is a Filter which rejects the proposals once it is drained,
+// with the Unavailable code so that the clients send them to another peer
+type DrainFilter struct {
+	next peer.EndorserServer
+
+	lock     sync.Mutex
+	draining bool
+	inFlight sync.WaitGroup
+}
+
+// Init initializes the Filter with the next EndorserServer
+func (f *DrainFilter) Init(next peer.EndorserServer) {
+	f.next = next
+}
+
+// ProcessProposal processes a signed proposal
+func (f *DrainFilter) ProcessProposal(ctx context.Context, signedProp *peer.SignedProposal) (*peer.ProposalResponse, error) {
+	f.lock.Lock()
+	if f.draining {
+		f.lock.Unlock()
+		return nil, status.Error(codes.Unavailable, "peer is shutting down")
+	}
+	f.inFlight.Add(1)
+	f.lock.Unlock()
+	defer f.inFlight.Done()
+
+	return f.next.ProcessProposal(ctx, signedProp)
+}
+
+// Drain stops accepting new proposals and waits for those being processed,
+// for at most the given timeout
+func (f *DrainFilter) Drain(timeout time.Duration) error {
+	f.lock.Lock()
+	f.draining = true
+	f.lock.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		f.inFlight.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		return nil
+	case <-time.After(timeout):
+		return errors.Errorf("proposals still being processed after %s", timeout)
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package filter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type blockingEndorserServer struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (es *blockingEndorserServer) ProcessProposal(context.Context, *peer.SignedProposal) (*peer.ProposalResponse, error) {
+	close(es.started)
+	<-es.release
+	return &peer.ProposalResponse{}, nil
+}
+
+func TestDrainFilter(t *testing.T) {
+	nextEndorser := &blockingEndorserServer{started: make(chan struct{}), release: make(chan struct{})}
+	auth := NewDrainFilter()
+	auth.Init(nextEndorser)
+
+	processed := make(chan error)
+	go func() {
+		_, err := auth.ProcessProposal(context.Background(), nil)
+		processed <- err
+	}()
+	<-nextEndorser.started
+
+	// The proposal being processed isn't drained in time
+	assert.EqualError(t, auth.Drain(10*time.Millisecond), "proposals still being processed after 10ms")
+
+	// New proposals are rejected once draining
+	_, err := auth.ProcessProposal(context.Background(), nil)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	drained := make(chan error)
+	go func() {
+		drained <- auth.Drain(time.Minute)
+	}()
+	close(nextEndorser.release)
+	assert.NoError(t, <-processed)
+	assert.NoError(t, <-drained)
+}
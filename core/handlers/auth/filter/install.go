@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package filter
+
+import (
+	"context"
+
+	"github.com/hyperledger/fabric/core/handlers/auth"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+const (
+	lifecycleSysCC = "lscc"
+	installFunc    = "install"
+)
+
+// NewInstallRejectionFilter creates a new Filter that rejects the chaincode
+// installation proposals, which are served by another listener of the peer
+func NewInstallRejectionFilter() auth.Filter {
+	return &installRejectionFilter{}
+}
+
+type installRejectionFilter struct {
+	next peer.EndorserServer
+}
+
+// Init initializes the Filter with the next EndorserServer
+func (f *installRejectionFilter) Init(next peer.EndorserServer) {
+	f.next = next
+}
+
+func isInstallProposal(signedProp *peer.SignedProposal) (bool, error) {
+	prop, err := utils.GetProposal(signedProp.ProposalBytes)
+	if err != nil {
+		return false, errors.Wrap(err, "failed parsing proposal")
+	}
+
+	hdr, err := utils.GetHeader(prop.Header)
+	if err != nil {
+		return false, errors.Wrap(err, "failed parsing header")
+	}
+
+	hdrExt, err := utils.GetChaincodeHeaderExtension(hdr)
+	if err != nil {
+		return false, errors.Wrap(err, "failed parsing chaincode header extension")
+	}
+	if hdrExt.ChaincodeId == nil || hdrExt.ChaincodeId.Name != lifecycleSysCC {
+		return false, nil
+	}
+
+	cis, err := utils.GetChaincodeInvocationSpec(prop)
+	if err != nil {
+		return false, errors.Wrap(err, "failed parsing chaincode invocation spec")
+	}
+	if cis.ChaincodeSpec == nil || cis.ChaincodeSpec.Input == nil || len(cis.ChaincodeSpec.Input.Args) == 0 {
+		return false, nil
+	}
+	return string(cis.ChaincodeSpec.Input.Args[0]) == installFunc, nil
+}
+
+// ProcessProposal processes a signed proposal
+func (f *installRejectionFilter) ProcessProposal(ctx context.Context, signedProp *peer.SignedProposal) (*peer.ProposalResponse, error) {
+	install, err := isInstallProposal(signedProp)
+	if err != nil {
+		return nil, err
+	}
+	if install {
+		return nil, errors.New("chaincode installation is only served by the admin service of the peer")
+	}
+	return f.next.ProcessProposal(ctx, signedProp)
+}
+
+// NewInstallOnlyFilter creates a new Filter that rejects the proposals other
+// than the chaincode installation proposals, so that the admin service of the
+// peer serves no other proposal
+func NewInstallOnlyFilter() auth.Filter {
+	return &installOnlyFilter{}
+}
+
+type installOnlyFilter struct {
+	next peer.EndorserServer
+}
+
+// Init initializes the Filter with the next EndorserServer
+func (f *installOnlyFilter) Init(next peer.EndorserServer) {
+	f.next = next
+}
+
+// ProcessProposal processes a signed proposal
+func (f *installOnlyFilter) ProcessProposal(ctx context.Context, signedProp *peer.SignedProposal) (*peer.ProposalResponse, error) {
+	install, err := isInstallProposal(signedProp)
+	if err != nil {
+		return nil, err
+	}
+	if !install {
+		return nil, errors.New("the admin service of the peer only serves chaincode installation proposals")
+	}
+	return f.next.ProcessProposal(ctx, signedProp)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package filter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createChaincodeProposal(t *testing.T, ccName string, args ...string) *peer.SignedProposal {
+	input := &peer.ChaincodeInput{}
+	for _, arg := range args {
+		input.Args = append(input.Args, []byte(arg))
+	}
+	cis := &peer.ChaincodeInvocationSpec{
+		ChaincodeSpec: &peer.ChaincodeSpec{
+			ChaincodeId: &peer.ChaincodeID{Name: ccName},
+			Input:       input,
+		},
+	}
+	prop, _, err := utils.CreateProposalFromCIS(common.HeaderType_ENDORSER_TRANSACTION, "", cis, []byte("creator"))
+	require.NoError(t, err)
+	propBytes, err := utils.GetBytesProposal(prop)
+	require.NoError(t, err)
+	return &peer.SignedProposal{ProposalBytes: propBytes}
+}
+
+func TestInstallRejectionFilter(t *testing.T) {
+	nextEndorser := &mockEndorserServer{}
+	auth := NewInstallRejectionFilter()
+	auth.Init(nextEndorser)
+
+	// Scenario I: Chaincode installation
+	_, err := auth.ProcessProposal(context.Background(), createChaincodeProposal(t, "lscc", "install", "package"))
+	assert.EqualError(t, err, "chaincode installation is only served by the admin service of the peer")
+	assert.False(t, nextEndorser.invoked)
+
+	// Scenario II: Other functions of the lifecycle system chaincode
+	_, err = auth.ProcessProposal(context.Background(), createChaincodeProposal(t, "lscc", "getinstalledchaincodes"))
+	assert.NoError(t, err)
+	assert.True(t, nextEndorser.invoked)
+	nextEndorser.invoked = false
+
+	// Scenario III: Application chaincode invocation
+	_, err = auth.ProcessProposal(context.Background(), createChaincodeProposal(t, "mycc", "install"))
+	assert.NoError(t, err)
+	assert.True(t, nextEndorser.invoked)
+	nextEndorser.invoked = false
+
+	// Scenario IV: Malformed proposal
+	sp := createChaincodeProposal(t, "lscc", "install")
+	sp.ProposalBytes = append(sp.ProposalBytes, 0)
+	_, err = auth.ProcessProposal(context.Background(), sp)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed parsing proposal")
+	assert.False(t, nextEndorser.invoked)
+}
+
+func TestInstallOnlyFilter(t *testing.T) {
+	nextEndorser := &mockEndorserServer{}
+	auth := NewInstallOnlyFilter()
+	auth.Init(nextEndorser)
+
+	// Scenario I: Chaincode installation
+	_, err := auth.ProcessProposal(context.Background(), createChaincodeProposal(t, "lscc", "install", "package"))
+	assert.NoError(t, err)
+	assert.True(t, nextEndorser.invoked)
+	nextEndorser.invoked = false
+
+	// Scenario II: Other functions of the lifecycle system chaincode
+	_, err = auth.ProcessProposal(context.Background(), createChaincodeProposal(t, "lscc", "getinstalledchaincodes"))
+	assert.EqualError(t, err, "the admin service of the peer only serves chaincode installation proposals")
+	assert.False(t, nextEndorser.invoked)
+
+	// Scenario III: Application chaincode invocation
+	_, err = auth.ProcessProposal(context.Background(), createChaincodeProposal(t, "mycc", "install"))
+	assert.EqualError(t, err, "the admin service of the peer only serves chaincode installation proposals")
+	assert.False(t, nextEndorser.invoked)
+
+	// Scenario IV: Malformed proposal
+	sp := createChaincodeProposal(t, "lscc", "install")
+	sp.ProposalBytes = append(sp.ProposalBytes, 0)
+	_, err = auth.ProcessProposal(context.Background(), sp)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed parsing proposal")
+	assert.False(t, nextEndorser.invoked)
+}
@@ -0,0 +1,253 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package filter
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/hyperledger/fabric/core/handlers/auth"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protos/common"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/peer"
+	protoutils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// TokenMetadataKey is the gRPC metadata key of the tokens which
+	// authenticate the clients of the proposals
+	TokenMetadataKey = "authorization"
+	bearerScheme     = "Bearer "
+	tokenAlgorithm   = "ES256"
+)
+
+// Enrollment is the MSP identity a client is enrolled with; the client signs
+// its tokens with the key of the certificate of the identity
+type Enrollment struct {
+	MSPID       string
+	Certificate *x509.Certificate
+}
+
+// NewTokenAuthFilter creates a new Filter that authenticates the clients of
+// the proposals with the bearer tokens of the gRPC metadata of the requests.
+// A token is a JWT signed with ES256 by the key of an enrollment, which is
+// named by the kid of its header, and which must have an exp claim and a
+// txid claim binding it to the proposal. The proposals with a token must be
+// created by one of the gateways, which send them on behalf of the clients.
+// The proposals without a token are forwarded unchanged, and the client of
+// those with a valid token is the identity of the enrollment.
+func NewTokenAuthFilter(enrollments map[string]*Enrollment, gateways map[string]*Enrollment) auth.Filter {
+	return &tokenAuthFilter{
+		enrollments: enrollments,
+		gateways:    gateways,
+		now:         time.Now,
+	}
+}
+
+type tokenAuthFilter struct {
+	next        peer.EndorserServer
+	enrollments map[string]*Enrollment
+	gateways    map[string]*Enrollment
+	now         func() time.Time
+}
+
+// Init initializes the Filter with the next EndorserServer
+func (f *tokenAuthFilter) Init(next peer.EndorserServer) {
+	f.next = next
+}
+
+type tokenHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+type tokenClaims struct {
+	ExpiresAt *int64  `json:"exp"`
+	NotBefore *int64  `json:"nbf"`
+	TxID      *string `json:"txid"`
+}
+
+// bearerToken returns the token of the gRPC metadata of the context, empty
+// if there is none
+func bearerToken(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", nil
+	}
+	values := md.Get(TokenMetadataKey)
+	if len(values) == 0 {
+		return "", nil
+	}
+	if len(values) > 1 || !strings.HasPrefix(values[0], bearerScheme) {
+		return "", errors.New("expected a single bearer token")
+	}
+	return strings.TrimPrefix(values[0], bearerScheme), nil
+}
+
+func decodeTokenPart(part string, v interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// authenticate verifies the token for the proposal of the transaction and
+// returns the signed data which authenticates the identity of its enrollment
+func (f *tokenAuthFilter) authenticate(token string, txID string) ([]*common.SignedData, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	header := &tokenHeader{}
+	if err := decodeTokenPart(parts[0], header); err != nil {
+		return nil, errors.Wrap(err, "failed parsing token header")
+	}
+	if header.Algorithm != tokenAlgorithm {
+		return nil, errors.Errorf("unsupported token algorithm %s", header.Algorithm)
+	}
+	enrollment, ok := f.enrollments[header.KeyID]
+	if !ok {
+		return nil, errors.Errorf("unknown enrollment %s", header.KeyID)
+	}
+	publicKey, ok := enrollment.Certificate.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.Errorf("enrollment %s doesn't have an ECDSA key", header.KeyID)
+	}
+
+	claims := &tokenClaims{}
+	if err := decodeTokenPart(parts[1], claims); err != nil {
+		return nil, errors.Wrap(err, "failed parsing token claims")
+	}
+	now := f.now().Unix()
+	if claims.ExpiresAt == nil {
+		return nil, errors.New("token has no expiration time")
+	}
+	if now >= *claims.ExpiresAt {
+		return nil, errors.New("token expired")
+	}
+	if claims.NotBefore != nil && now < *claims.NotBefore {
+		return nil, errors.New("token not valid yet")
+	}
+	if claims.TxID == nil {
+		return nil, errors.New("token has no transaction ID")
+	}
+	if *claims.TxID != txID {
+		return nil, errors.Errorf("token is for transaction %s, not %s", *claims.TxID, txID)
+	}
+
+	// the signature of ES256 is the concatenation of r and s
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || len(signature) != 64 {
+		return nil, errors.New("malformed token signature")
+	}
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:])
+	signingInput := parts[0] + "." + parts[1]
+	digest := sha256.Sum256([]byte(signingInput))
+	if !ecdsa.Verify(publicKey, digest[:], r, s) {
+		return nil, errors.Errorf("invalid token signature for enrollment %s", header.KeyID)
+	}
+
+	// the signatures are verified by the MSPs in their DER low-S form
+	derSignature, err := utils.MarshalECDSASignature(r, s)
+	if err != nil {
+		return nil, err
+	}
+	derSignature, err = utils.SignatureToLowS(publicKey, derSignature)
+	if err != nil {
+		return nil, err
+	}
+	identity, err := msp.NewSerializedIdentity(enrollment.MSPID, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: enrollment.Certificate.Raw}))
+	if err != nil {
+		return nil, err
+	}
+	return []*common.SignedData{{
+		Data:      []byte(signingInput),
+		Identity:  identity,
+		Signature: derSignature,
+	}}, nil
+}
+
+// proposalHeaders returns the creator and the transaction ID of the proposal
+func proposalHeaders(signedProp *peer.SignedProposal) ([]byte, string, error) {
+	prop, err := protoutils.GetProposal(signedProp.ProposalBytes)
+	if err != nil {
+		return nil, "", err
+	}
+	hdr, err := protoutils.GetHeader(prop.Header)
+	if err != nil {
+		return nil, "", err
+	}
+	chdr, err := protoutils.UnmarshalChannelHeader(hdr.ChannelHeader)
+	if err != nil {
+		return nil, "", err
+	}
+	shdr, err := protoutils.GetSignatureHeader(hdr.SignatureHeader)
+	if err != nil {
+		return nil, "", err
+	}
+	return shdr.Creator, chdr.TxId, nil
+}
+
+// isGateway returns whether the creator is the identity of a gateway; the
+// endorser verifies afterwards that the creator signed the proposal
+func (f *tokenAuthFilter) isGateway(creator []byte) bool {
+	sID := &mspprotos.SerializedIdentity{}
+	if err := proto.Unmarshal(creator, sID); err != nil {
+		return false
+	}
+	block, _ := pem.Decode(sID.IdBytes)
+	if block == nil {
+		return false
+	}
+	for _, gateway := range f.gateways {
+		if gateway.MSPID == sID.Mspid && bytes.Equal(gateway.Certificate.Raw, block.Bytes) {
+			return true
+		}
+	}
+	return false
+}
+
+// ProcessProposal processes a signed proposal
+func (f *tokenAuthFilter) ProcessProposal(ctx context.Context, signedProp *peer.SignedProposal) (*peer.ProposalResponse, error) {
+	token, err := bearerToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if token == "" {
+		return f.next.ProcessProposal(ctx, signedProp)
+	}
+
+	creator, txID, err := proposalHeaders(signedProp)
+	if err != nil {
+		return nil, errors.WithMessage(err, "token authentication failed: malformed proposal")
+	}
+	if !f.isGateway(creator) {
+		return nil, errors.New("token authentication failed: the creator of the proposal is not a gateway")
+	}
+	signedData, err := f.authenticate(token, txID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "token authentication failed")
+	}
+	return f.next.ProcessProposal(auth.WithClientSignedData(ctx, signedData), signedProp)
+}
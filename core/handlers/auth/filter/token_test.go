@@ -0,0 +1,234 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package filter
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/config/configtest"
+	"github.com/hyperledger/fabric/core/handlers/auth"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+type clientEndorserServer struct {
+	signedData []*common.SignedData
+	invoked    bool
+}
+
+func (es *clientEndorserServer) ProcessProposal(ctx context.Context, _ *peer.SignedProposal) (*peer.ProposalResponse, error) {
+	es.invoked = true
+	es.signedData = auth.ClientSignedData(ctx)
+	return nil, nil
+}
+
+// loadSampleEnrollment returns the MSP, the certificate and the key of the
+// sample config
+func loadSampleEnrollment(t *testing.T) (msp.MSP, *x509.Certificate, *ecdsa.PrivateKey) {
+	mspDir, err := configtest.GetDevMspDir()
+	assert.NoError(t, err)
+	conf, err := msp.GetLocalMspConfig(mspDir, nil, "SampleOrg")
+	assert.NoError(t, err)
+	theMsp, err := msp.New(&msp.BCCSPNewOpts{NewBaseOpts: msp.NewBaseOpts{Version: msp.MSPv1_3}})
+	assert.NoError(t, err)
+	assert.NoError(t, theMsp.Setup(conf))
+
+	certPEM, err := ioutil.ReadFile(filepath.Join(mspDir, "signcerts", "peer.pem"))
+	assert.NoError(t, err)
+	block, _ := pem.Decode(certPEM)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	assert.NoError(t, err)
+
+	keyPEM, err := ioutil.ReadFile(filepath.Join(mspDir, "keystore", "key.pem"))
+	assert.NoError(t, err)
+	block, _ = pem.Decode(keyPEM)
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	assert.NoError(t, err)
+	return theMsp, cert, key.(*ecdsa.PrivateKey)
+}
+
+func createToken(t *testing.T, key *ecdsa.PrivateKey, header, claims map[string]interface{}) string {
+	headerBytes, err := json.Marshal(header)
+	assert.NoError(t, err)
+	claimsBytes, err := json.Marshal(claims)
+	assert.NoError(t, err)
+	signingInput := base64.RawURLEncoding.EncodeToString(headerBytes) + "." + base64.RawURLEncoding.EncodeToString(claimsBytes)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	assert.NoError(t, err)
+	signature := make([]byte, 64)
+	rBytes, sBytes := r.Bytes(), s.Bytes()
+	copy(signature[32-len(rBytes):32], rBytes)
+	copy(signature[64-len(sBytes):], sBytes)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// signedProposal returns a proposal of the transaction created by the MSP
+// identity of the certificate
+func signedProposal(t *testing.T, mspID string, cert *x509.Certificate, txID string) *peer.SignedProposal {
+	creator, err := msp.NewSerializedIdentity(mspID, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	assert.NoError(t, err)
+	hdr := &common.Header{
+		ChannelHeader:   utils.MarshalOrPanic(&common.ChannelHeader{TxId: txID}),
+		SignatureHeader: utils.MarshalOrPanic(&common.SignatureHeader{Creator: creator}),
+	}
+	prop := &peer.Proposal{Header: utils.MarshalOrPanic(hdr)}
+	return &peer.SignedProposal{ProposalBytes: utils.MarshalOrPanic(prop)}
+}
+
+func tokenContext(tokens ...string) context.Context {
+	md := metadata.MD{}
+	for _, token := range tokens {
+		md.Append(TokenMetadataKey, token)
+	}
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+func TestTokenAuthFilter(t *testing.T) {
+	theMsp, cert, key := loadSampleEnrollment(t)
+	now := time.Now()
+	header := map[string]interface{}{"alg": "ES256", "kid": "user1"}
+	claims := map[string]interface{}{"exp": now.Add(time.Hour).Unix(), "txid": "tx1"}
+	prop := signedProposal(t, "SampleOrg", cert, "tx1")
+
+	filter := NewTokenAuthFilter(map[string]*Enrollment{
+		"user1": {MSPID: "SampleOrg", Certificate: cert},
+	}, map[string]*Enrollment{
+		"gateway1": {MSPID: "SampleOrg", Certificate: cert},
+	})
+	next := &clientEndorserServer{}
+	filter.Init(next)
+
+	// the proposals without a token are forwarded unchanged
+	_, err := filter.ProcessProposal(context.Background(), &peer.SignedProposal{})
+	assert.NoError(t, err)
+	assert.True(t, next.invoked)
+	assert.Nil(t, next.signedData)
+
+	// the client of a proposal with a valid token is the identity of the
+	// enrollment, whose MSP verifies the signed data
+	for i := 0; i < 10; i++ {
+		next = &clientEndorserServer{}
+		filter.Init(next)
+		_, err = filter.ProcessProposal(tokenContext("Bearer "+createToken(t, key, header, claims)), prop)
+		assert.NoError(t, err)
+		assert.True(t, next.invoked)
+		assert.Len(t, next.signedData, 1)
+		identity, err := theMsp.DeserializeIdentity(next.signedData[0].Identity)
+		assert.NoError(t, err)
+		assert.NoError(t, theMsp.Validate(identity))
+		assert.NoError(t, identity.Verify(next.signedData[0].Data, next.signedData[0].Signature))
+	}
+
+	otherKey, err := ecdsa.GenerateKey(key.Curve, rand.Reader)
+	assert.NoError(t, err)
+	for _, testCase := range []struct {
+		name string
+		ctx  context.Context
+		prop *peer.SignedProposal
+		err  string
+	}{
+		{
+			name: "several tokens",
+			ctx:  tokenContext("Bearer a", "Bearer b"),
+			err:  "expected a single bearer token",
+		},
+		{
+			name: "not a bearer token",
+			ctx:  tokenContext("Basic dXNlcjE6cGFzc3dvcmQ="),
+			err:  "expected a single bearer token",
+		},
+		{
+			name: "malformed token",
+			ctx:  tokenContext("Bearer a.b"),
+			err:  "token authentication failed: malformed token",
+		},
+		{
+			name: "unsupported algorithm",
+			ctx:  tokenContext("Bearer " + createToken(t, key, map[string]interface{}{"alg": "HS256", "kid": "user1"}, claims)),
+			err:  "token authentication failed: unsupported token algorithm HS256",
+		},
+		{
+			name: "unknown enrollment",
+			ctx:  tokenContext("Bearer " + createToken(t, key, map[string]interface{}{"alg": "ES256", "kid": "user2"}, claims)),
+			err:  "token authentication failed: unknown enrollment user2",
+		},
+		{
+			name: "no expiration time",
+			ctx:  tokenContext("Bearer " + createToken(t, key, header, map[string]interface{}{})),
+			err:  "token authentication failed: token has no expiration time",
+		},
+		{
+			name: "expired token",
+			ctx:  tokenContext("Bearer " + createToken(t, key, header, map[string]interface{}{"exp": now.Add(-time.Minute).Unix()})),
+			err:  "token authentication failed: token expired",
+		},
+		{
+			name: "token not valid yet",
+			ctx: tokenContext("Bearer " + createToken(t, key, header, map[string]interface{}{
+				"exp": now.Add(time.Hour).Unix(),
+				"nbf": now.Add(time.Minute).Unix(),
+			})),
+			err: "token authentication failed: token not valid yet",
+		},
+		{
+			name: "signed by another key",
+			ctx:  tokenContext("Bearer " + createToken(t, otherKey, header, claims)),
+			err:  "token authentication failed: invalid token signature for enrollment user1",
+		},
+		{
+			name: "no transaction ID",
+			ctx:  tokenContext("Bearer " + createToken(t, key, header, map[string]interface{}{"exp": now.Add(time.Hour).Unix()})),
+			err:  "token authentication failed: token has no transaction ID",
+		},
+		{
+			name: "token of another proposal",
+			ctx:  tokenContext("Bearer " + createToken(t, key, header, claims)),
+			prop: signedProposal(t, "SampleOrg", cert, "tx2"),
+			err:  "token authentication failed: token is for transaction tx1, not tx2",
+		},
+		{
+			name: "proposal not created by a gateway",
+			ctx:  tokenContext("Bearer " + createToken(t, key, header, claims)),
+			prop: signedProposal(t, "OtherOrg", cert, "tx1"),
+			err:  "token authentication failed: the creator of the proposal is not a gateway",
+		},
+		{
+			name: "malformed proposal",
+			ctx:  tokenContext("Bearer " + createToken(t, key, header, claims)),
+			prop: &peer.SignedProposal{ProposalBytes: []byte("bogus")},
+			err:  "token authentication failed: malformed proposal: error unmarshaling Proposal: unexpected EOF",
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			next := &clientEndorserServer{}
+			filter.Init(next)
+			if testCase.prop == nil {
+				testCase.prop = prop
+			}
+			_, err := filter.ProcessProposal(testCase.ctx, testCase.prop)
+			assert.EqualError(t, err, testCase.err)
+			assert.False(t, next.invoked)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorsement
+
+import (
+	"github.com/hyperledger/fabric/protos/peer"
+)
+
+// Enclave is a trusted execution environment executing a chaincode
+type Enclave interface {
+	// Attest returns the evidence that the given payload (ProposalResponsePayload bytes)
+	// was produced by the chaincode executing inside the enclave for the given proposal
+	Attest(payload []byte, sp *peer.SignedProposal) (*peer.AttestationEvidence, error)
+}
+
+// EnclaveRetriever retrieves the enclaves executing chaincodes
+type EnclaveRetriever interface {
+	// EnclaveForChaincode returns the enclave executing the given chaincode,
+	// or nil if the chaincode doesn't execute inside an enclave
+	EnclaveForChaincode(chaincodeName string) Enclave
+}
@@ -0,0 +1,79 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package builtin
+
+import (
+	. "github.com/hyperledger/fabric/core/handlers/endorsement/api"
+	. "github.com/hyperledger/fabric/core/handlers/endorsement/api/attestation"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// TEEEndorsementFactory returns an endorsement plugin factory which returns plugins
+// that endorse the proposal responses of chaincodes executing inside enclaves
+type TEEEndorsementFactory struct {
+	EnclaveRetriever
+}
+
+// New returns an endorsement plugin that endorses the proposal responses of chaincodes
+// executing inside enclaves
+func (f *TEEEndorsementFactory) New() Plugin {
+	return &TEEEndorsement{EnclaveRetriever: f.EnclaveRetriever}
+}
+
+// TEEEndorsement is an endorsement plugin that behaves as the default endorsement system
+// chaincode, and adds to the endorsement the attestation evidence of the enclave which
+// executed the chaincode
+type TEEEndorsement struct {
+	DefaultEndorsement
+	EnclaveRetriever
+}
+
+// Endorse asks the enclave executing the chaincode to attest the given payload
+// (ProposalResponsePayload bytes), and signs it.
+// Returns:
+// The Endorsement: A signature over the payload, an identity that is used to verify the signature,
+// and the attestation evidence of the enclave
+// The payload that was given as input
+// Or error on failure
+func (e *TEEEndorsement) Endorse(prpBytes []byte, sp *peer.SignedProposal) (*peer.Endorsement, []byte, error) {
+	prp, err := utils.GetProposalResponsePayload(prpBytes)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "failed unmarshaling the proposal response payload")
+	}
+	action, err := utils.GetChaincodeAction(prp.Extension)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "failed unmarshaling the chaincode action")
+	}
+	if action.ChaincodeId == nil {
+		return nil, nil, errors.New("no chaincode ID in the chaincode action")
+	}
+	enclave := e.EnclaveForChaincode(action.ChaincodeId.Name)
+	if enclave == nil {
+		return nil, nil, errors.Errorf("chaincode %s doesn't execute inside an enclave", action.ChaincodeId.Name)
+	}
+	evidence, err := enclave.Attest(prpBytes, sp)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "enclave of chaincode %s failed attesting the proposal response", action.ChaincodeId.Name)
+	}
+
+	endorsement, prpBytes, err := e.DefaultEndorsement.Endorse(prpBytes, sp)
+	if err != nil {
+		return nil, nil, err
+	}
+	endorsement.Attestation = evidence
+	return endorsement, prpBytes, nil
+}
+
+// Init injects dependencies into the instance of the Plugin
+func (e *TEEEndorsement) Init(dependencies ...Dependency) error {
+	if e.EnclaveRetriever == nil {
+		return errors.New("no EnclaveRetriever in the plugin factory")
+	}
+	return e.DefaultEndorsement.Init(dependencies...)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package builtin_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/endorser/mocks"
+	endorsement "github.com/hyperledger/fabric/core/handlers/endorsement/api/attestation"
+	"github.com/hyperledger/fabric/core/handlers/endorsement/builtin"
+	mocks2 "github.com/hyperledger/fabric/core/handlers/endorsement/builtin/mocks"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type enclave struct {
+	evidence *peer.AttestationEvidence
+	err      error
+}
+
+func (e *enclave) Attest(payload []byte, sp *peer.SignedProposal) (*peer.AttestationEvidence, error) {
+	return e.evidence, e.err
+}
+
+type enclaves map[string]endorsement.Enclave
+
+func (e enclaves) EnclaveForChaincode(chaincodeName string) endorsement.Enclave {
+	return e[chaincodeName]
+}
+
+func proposalResponsePayload(t *testing.T, chaincodeName string) []byte {
+	action, err := proto.Marshal(&peer.ChaincodeAction{ChaincodeId: &peer.ChaincodeID{Name: chaincodeName}})
+	assert.NoError(t, err)
+	prpBytes, err := proto.Marshal(&peer.ProposalResponsePayload{ProposalHash: []byte{1}, Extension: action})
+	assert.NoError(t, err)
+	return prpBytes
+}
+
+func TestTEEEndorsement(t *testing.T) {
+	evidence := &peer.AttestationEvidence{Type: "SGX", Quote: []byte{1}, EnclavePublicKey: []byte{2}, Signature: []byte{3}}
+	attestingEnclave := &enclave{evidence: evidence}
+
+	// Scenario I: The factory has no enclave retriever, and the initialization fails
+	err := (&builtin.TEEEndorsementFactory{}).New().Init(&mocks.SigningIdentityFetcher{})
+	assert.EqualError(t, err, "no EnclaveRetriever in the plugin factory")
+
+	endorser := (&builtin.TEEEndorsementFactory{EnclaveRetriever: enclaves{"mycc": attestingEnclave}}).New()
+	assert.EqualError(t, endorser.Init(), "could not find SigningIdentityFetcher in dependencies")
+	sif := &mocks.SigningIdentityFetcher{}
+	assert.NoError(t, endorser.Init(sif))
+	sid := &mocks2.SigningIdentity{}
+	sid.On("Serialize").Return([]byte{1, 2, 3}, nil)
+	sid.On("Sign", mock.Anything).Return([]byte{10, 20, 30}, nil)
+	sif.On("SigningIdentityForRequest", mock.Anything).Return(sid, nil)
+
+	// Scenario II: The payload isn't a proposal response payload
+	_, _, err = endorser.Endorse([]byte{1, 1, 1}, nil)
+	assert.Contains(t, err.Error(), "failed unmarshaling the proposal response payload")
+
+	// Scenario III: The chaincode doesn't execute inside an enclave
+	_, _, err = endorser.Endorse(proposalResponsePayload(t, "othercc"), nil)
+	assert.EqualError(t, err, "chaincode othercc doesn't execute inside an enclave")
+
+	// Scenario IV: The enclave fails attesting the payload
+	attestingEnclave.err = errors.New("quote generation failed")
+	_, _, err = endorser.Endorse(proposalResponsePayload(t, "mycc"), nil)
+	assert.EqualError(t, err, "enclave of chaincode mycc failed attesting the proposal response: quote generation failed")
+
+	// Scenario V: The endorsement carries the evidence of the enclave
+	attestingEnclave.err = nil
+	prpBytes := proposalResponsePayload(t, "mycc")
+	endorsement, resp, err := endorser.Endorse(prpBytes, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, prpBytes, resp)
+	assert.Equal(t, &peer.Endorsement{
+		Signature:   []byte{10, 20, 30},
+		Endorser:    []byte{1, 2, 3},
+		Attestation: evidence,
+	}, endorsement)
+}
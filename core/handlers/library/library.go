@@ -7,14 +7,23 @@ SPDX-License-Identifier: Apache-2.0
 package library
 
 import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/hyperledger/fabric/core/config"
 	"github.com/hyperledger/fabric/core/handlers/auth"
 	"github.com/hyperledger/fabric/core/handlers/auth/filter"
 	"github.com/hyperledger/fabric/core/handlers/decoration"
 	"github.com/hyperledger/fabric/core/handlers/decoration/decorator"
 	"github.com/hyperledger/fabric/core/handlers/endorsement/api"
 	"github.com/hyperledger/fabric/core/handlers/endorsement/builtin"
+	"github.com/hyperledger/fabric/core/handlers/tee"
 	"github.com/hyperledger/fabric/core/handlers/validation/api"
 	. "github.com/hyperledger/fabric/core/handlers/validation/builtin"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
 )
 
 // HandlerLibrary is used to assert
@@ -37,6 +46,22 @@ func (r *HandlerLibrary) ExpirationCheck() auth.Filter {
 	return filter.NewExpirationCheckFilter()
 }
 
+// TokenAuth is an auth filter which authenticates the clients
+// of the proposals with tokens signed by the keys of their
+// enrollments, configured in peer.authentication.enrollments,
+// sent by the gateways of peer.authentication.gateways
+func (r *HandlerLibrary) TokenAuth() auth.Filter {
+	enrollments, err := loadEnrollments("peer.authentication.enrollments")
+	if err != nil {
+		logger.Panicf("Failed loading the enrollments of the token authentication: %s", err)
+	}
+	gateways, err := loadEnrollments("peer.authentication.gateways")
+	if err != nil {
+		logger.Panicf("Failed loading the gateways of the token authentication: %s", err)
+	}
+	return filter.NewTokenAuthFilter(enrollments, gateways)
+}
+
 // DefaultDecorator creates a default decorator
 // that doesn't do anything with the input, simply
 // returns the input as output.
@@ -51,3 +76,58 @@ func (r *HandlerLibrary) DefaultEndorsement() endorsement.PluginFactory {
 func (r *HandlerLibrary) DefaultValidation() validation.PluginFactory {
 	return &DefaultValidationFactory{}
 }
+
+// TEEEndorsement creates an endorsement plugin factory whose plugins
+// add to the endorsements the attestation evidence of the enclaves,
+// registered with tee.RegisterEnclave, which execute the chaincodes
+func (r *HandlerLibrary) TEEEndorsement() endorsement.PluginFactory {
+	return &builtin.TEEEndorsementFactory{EnclaveRetriever: tee.NewRegistry()}
+}
+
+// TEEValidation creates a validation plugin factory whose plugins
+// require the endorsements to be attested by the enclaves trusted in
+// the validation parameters of the chaincodes, with the quote verifiers
+// registered with tee.RegisterQuoteVerifier
+func (r *HandlerLibrary) TEEValidation() validation.PluginFactory {
+	return &TEEValidationFactory{QuoteVerifierRetriever: tee.NewRegistry()}
+}
+
+type enrollmentConfig struct {
+	ID          string `mapstructure:"id"`
+	MSPID       string `mapstructure:"mspid"`
+	Certificate string `mapstructure:"certificate"`
+}
+
+// loadEnrollments loads the enrollments of the config key, whose certificate
+// paths are relative to the config file
+func loadEnrollments(key string) (map[string]*filter.Enrollment, error) {
+	var configs []*enrollmentConfig
+	if err := viper.UnmarshalKey(key, &configs); err != nil {
+		return nil, errors.Wrapf(err, "failed unmarshaling %s", key)
+	}
+
+	enrollments := map[string]*filter.Enrollment{}
+	for _, c := range configs {
+		if c.ID == "" || c.MSPID == "" {
+			return nil, errors.New("an enrollment requires an id and an mspid")
+		}
+		if _, exists := enrollments[c.ID]; exists {
+			return nil, errors.Errorf("enrollment %s is defined more than once", c.ID)
+		}
+		certPath := config.TranslatePath(filepath.Dir(viper.ConfigFileUsed()), c.Certificate)
+		certPEM, err := ioutil.ReadFile(certPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed reading the certificate of enrollment %s", c.ID)
+		}
+		block, _ := pem.Decode(certPEM)
+		if block == nil {
+			return nil, errors.Errorf("no PEM certificate for enrollment %s in %s", c.ID, certPath)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed parsing the certificate of enrollment %s", c.ID)
+		}
+		enrollments[c.ID] = &filter.Enrollment{MSPID: c.MSPID, Certificate: cert}
+	}
+	return enrollments, nil
+}
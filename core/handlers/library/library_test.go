@@ -0,0 +1,85 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package library
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/config/configtest"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadEnrollments(t *testing.T) {
+	defer viper.Reset()
+	mspDir, err := configtest.GetDevMspDir()
+	assert.NoError(t, err)
+	certPath := filepath.Join(mspDir, "signcerts", "peer.pem")
+
+	enrollments, err := loadEnrollments("peer.authentication.enrollments")
+	assert.NoError(t, err)
+	assert.Empty(t, enrollments)
+
+	viper.Set("peer.authentication.enrollments", []map[string]interface{}{
+		{"id": "user1", "mspid": "SampleOrg", "certificate": certPath},
+	})
+	enrollments, err = loadEnrollments("peer.authentication.enrollments")
+	assert.NoError(t, err)
+	assert.Len(t, enrollments, 1)
+	assert.Equal(t, "SampleOrg", enrollments["user1"].MSPID)
+	assert.NotNil(t, enrollments["user1"].Certificate)
+	assert.NotNil(t, (&HandlerLibrary{}).TokenAuth())
+
+	viper.Set("peer.authentication.gateways", []map[string]interface{}{{"id": "gateway1", "certificate": certPath}})
+	assert.Panics(t, func() { (&HandlerLibrary{}).TokenAuth() })
+	viper.Set("peer.authentication.gateways", []map[string]interface{}{{"id": "gateway1", "mspid": "SampleOrg", "certificate": certPath}})
+	assert.NotNil(t, (&HandlerLibrary{}).TokenAuth())
+
+	for _, testCase := range []struct {
+		name        string
+		enrollments []map[string]interface{}
+		err         string
+	}{
+		{
+			name:        "no mspid",
+			enrollments: []map[string]interface{}{{"id": "user1", "certificate": certPath}},
+			err:         "an enrollment requires an id and an mspid",
+		},
+		{
+			name: "duplicate",
+			enrollments: []map[string]interface{}{
+				{"id": "user1", "mspid": "SampleOrg", "certificate": certPath},
+				{"id": "user1", "mspid": "SampleOrg", "certificate": certPath},
+			},
+			err: "enrollment user1 is defined more than once",
+		},
+		{
+			name:        "missing certificate",
+			enrollments: []map[string]interface{}{{"id": "user1", "mspid": "SampleOrg", "certificate": filepath.Join(mspDir, "missing.pem")}},
+			err:         "failed reading the certificate of enrollment user1",
+		},
+		{
+			name:        "not a certificate",
+			enrollments: []map[string]interface{}{{"id": "user1", "mspid": "SampleOrg", "certificate": filepath.Join(mspDir, "config.yaml")}},
+			err:         "no PEM certificate for enrollment user1",
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			viper.Set("peer.authentication.enrollments", testCase.enrollments)
+			_, err := loadEnrollments("peer.authentication.enrollments")
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), testCase.err)
+			assert.Panics(t, func() { (&HandlerLibrary{}).TokenAuth() })
+		})
+	}
+}
+
+func TestTEEPlugins(t *testing.T) {
+	assert.NotNil(t, (&HandlerLibrary{}).TEEEndorsement())
+	assert.NotNil(t, (&HandlerLibrary{}).TEEValidation())
+}
@@ -11,6 +11,7 @@ import (
 	"os"
 	"plugin"
 	"reflect"
+	"strconv"
 	"sync"
 
 	"github.com/hyperledger/fabric/common/flogging"
@@ -18,6 +19,8 @@ import (
 	"github.com/hyperledger/fabric/core/handlers/decoration"
 	endorsement2 "github.com/hyperledger/fabric/core/handlers/endorsement/api"
 	"github.com/hyperledger/fabric/core/handlers/validation/api"
+	"github.com/hyperledger/fabric/core/ledger/customtx"
+	"github.com/hyperledger/fabric/protos/common"
 )
 
 var logger = flogging.MustGetLogger("core/handlers")
@@ -42,10 +45,14 @@ const (
 	Decoration
 	Endorsement
 	Validation
-
-	authPluginFactory      = "NewFilter"
-	decoratorPluginFactory = "NewDecorator"
-	pluginFactory          = "NewPluginFactory"
+	// TxProcessor handler - process the transactions of a custom
+	// header type at commit time
+	TxProcessor
+
+	authPluginFactory        = "NewFilter"
+	decoratorPluginFactory   = "NewDecorator"
+	pluginFactory            = "NewPluginFactory"
+	txProcessorPluginFactory = "NewTxProcessor"
 )
 
 type registry struct {
@@ -53,6 +60,7 @@ type registry struct {
 	decorators []decoration.Decorator
 	endorsers  map[string]endorsement2.PluginFactory
 	validators map[string]validation.PluginFactory
+	processors customtx.Processors
 }
 
 var once sync.Once
@@ -65,6 +73,8 @@ type Config struct {
 	Decorators  []*HandlerConfig `mapstructure:"decorators" yaml:"decorators"`
 	Endorsers   PluginMapping    `mapstructure:"endorsers" yaml:"endorsers"`
 	Validators  PluginMapping    `mapstructure:"validators" yaml:"validators"`
+	// TxProcessors maps the custom header types to their processors
+	TxProcessors PluginMapping `mapstructure:"txProcessors" yaml:"txProcessors"`
 }
 
 type PluginMapping map[string]*HandlerConfig
@@ -82,6 +92,7 @@ func InitRegistry(c Config) Registry {
 		reg = registry{
 			endorsers:  make(map[string]endorsement2.PluginFactory),
 			validators: make(map[string]validation.PluginFactory),
+			processors: make(customtx.Processors),
 		}
 		reg.loadHandlers(c)
 	})
@@ -104,6 +115,10 @@ func (r *registry) loadHandlers(c Config) {
 	for chaincodeID, config := range c.Validators {
 		r.evaluateModeAndLoad(config, Validation, chaincodeID)
 	}
+
+	for txType, config := range c.TxProcessors {
+		r.evaluateModeAndLoad(config, TxProcessor, txType)
+	}
 }
 
 // evaluateModeAndLoad if a library path is provided, load the shared object
@@ -140,6 +155,11 @@ func (r *registry) loadCompiled(handlerFactory string, handlerType HandlerType,
 			logger.Panicf("expected 1 argument in extraArgs")
 		}
 		r.validators[extraArgs[0]] = inst.(validation.PluginFactory)
+	} else if handlerType == TxProcessor {
+		if len(extraArgs) != 1 {
+			logger.Panicf("expected 1 argument in extraArgs")
+		}
+		r.registerTxProcessor(extraArgs[0], inst.(customtx.Processor))
 	}
 }
 
@@ -161,6 +181,8 @@ func (r *registry) loadPlugin(pluginPath string, handlerType HandlerType, extraA
 		r.initEndorsementPlugin(p, extraArgs...)
 	} else if handlerType == Validation {
 		r.initValidationPlugin(p, extraArgs...)
+	} else if handlerType == TxProcessor {
+		r.initTxProcessorPlugin(p, extraArgs...)
 	}
 }
 
@@ -237,6 +259,39 @@ func (r *registry) initValidationPlugin(p *plugin.Plugin, extraArgs ...string) {
 	r.validators[extraArgs[0]] = factory
 }
 
+func (r *registry) initTxProcessorPlugin(p *plugin.Plugin, extraArgs ...string) {
+	if len(extraArgs) != 1 {
+		logger.Panicf("expected 1 argument in extraArgs")
+	}
+	constructorSymbol, err := p.Lookup(txProcessorPluginFactory)
+	if err != nil {
+		panicWithLookupError(txProcessorPluginFactory, err)
+	}
+
+	constructor, ok := constructorSymbol.(func() customtx.Processor)
+	if !ok {
+		panicWithDefinitionError(txProcessorPluginFactory)
+	}
+	processor := constructor()
+	if processor == nil {
+		logger.Panicf("processor instance returned nil")
+	}
+	r.registerTxProcessor(extraArgs[0], processor)
+}
+
+// registerTxProcessor registers the processor of the custom header type with
+// the ledger
+func (r *registry) registerTxProcessor(txType string, processor customtx.Processor) {
+	t, err := strconv.ParseInt(txType, 10, 32)
+	if err != nil {
+		logger.Panicf("Invalid header type %s of transaction processor: %s", txType, err)
+	}
+	if err := customtx.Register(common.HeaderType(t), processor); err != nil {
+		logger.Panicf("Could not register transaction processor: %s", err)
+	}
+	r.processors[common.HeaderType(t)] = processor
+}
+
 // panicWithLookupError panics when a handler constructor lookup fails
 func panicWithLookupError(factory string, err error) {
 	logger.Panicf(fmt.Sprintf("Plugin must contain constructor with name %s. Error from lookup: %s",
@@ -261,6 +316,8 @@ func (r *registry) Lookup(handlerType HandlerType) interface{} {
 		return r.endorsers
 	} else if handlerType == Validation {
 		return r.validators
+	} else if handlerType == TxProcessor {
+		return r.processors
 	}
 
 	return nil
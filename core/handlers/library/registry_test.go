@@ -11,6 +11,9 @@ import (
 
 	"github.com/hyperledger/fabric/core/handlers/auth"
 	"github.com/hyperledger/fabric/core/handlers/decoration"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/customtx"
+	"github.com/hyperledger/fabric/protos/common"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -43,3 +46,20 @@ func TestLoadCompiledInvalid(t *testing.T) {
 	testReg := registry{}
 	testReg.loadCompiled("InvalidFactory", Auth)
 }
+
+type txProcessor struct{}
+
+func (txProcessor) GenerateSimulationResults(txEnvelop *common.Envelope, simulator ledger.TxSimulator, initializingLedger bool) error {
+	return nil
+}
+
+func TestRegisterTxProcessor(t *testing.T) {
+	testReg := registry{processors: customtx.Processors{}}
+	testReg.registerTxProcessor("100", txProcessor{})
+	assert.Equal(t, customtx.Processors{100: txProcessor{}}, testReg.Lookup(TxProcessor))
+	assert.True(t, customtx.IsRegistered(100))
+
+	assert.Panics(t, func() { testReg.registerTxProcessor("foo", txProcessor{}) })
+	assert.Panics(t, func() { testReg.registerTxProcessor("3", txProcessor{}) })
+	assert.Panics(t, func() { testReg.registerTxProcessor("100", txProcessor{}) })
+}
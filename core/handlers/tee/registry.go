@@ -0,0 +1,81 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tee
+
+import (
+	"sync"
+
+	endorsement "github.com/hyperledger/fabric/core/handlers/endorsement/api/attestation"
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api/attestation"
+	"github.com/pkg/errors"
+)
+
+var (
+	registeredLock sync.RWMutex
+	enclaves       = map[string]endorsement.Enclave{}
+	quoteVerifiers = map[string]validation.QuoteVerifier{}
+)
+
+// RegisterEnclave registers the enclave executing a chaincode, which the
+// TEEEndorsement plugin asks to attest the proposal responses of the chaincode.
+// It is expected to be invoked by the init functions of the runtimes compiled in
+// the peer which execute chaincodes inside trusted execution environments.
+func RegisterEnclave(chaincodeName string, enclave endorsement.Enclave) error {
+	if enclave == nil {
+		return errors.Errorf("nil enclave for chaincode %s", chaincodeName)
+	}
+
+	registeredLock.Lock()
+	defer registeredLock.Unlock()
+	if _, ok := enclaves[chaincodeName]; ok {
+		return errors.Errorf("an enclave is already registered for chaincode %s", chaincodeName)
+	}
+	enclaves[chaincodeName] = enclave
+	return nil
+}
+
+// RegisterQuoteVerifier registers the verifier of the quotes of a type of trusted
+// execution environment, which the TEEValidation plugin uses to verify the
+// attestation evidence of the endorsements. It is expected to be invoked by the
+// init functions of the packages compiled in the peer.
+func RegisterQuoteVerifier(evidenceType string, verifier validation.QuoteVerifier) error {
+	if verifier == nil {
+		return errors.Errorf("nil quote verifier for type %s", evidenceType)
+	}
+
+	registeredLock.Lock()
+	defer registeredLock.Unlock()
+	if _, ok := quoteVerifiers[evidenceType]; ok {
+		return errors.Errorf("a quote verifier is already registered for type %s", evidenceType)
+	}
+	quoteVerifiers[evidenceType] = verifier
+	return nil
+}
+
+// Registry retrieves the registered enclaves and quote verifiers
+type Registry struct{}
+
+// NewRegistry returns a Registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// EnclaveForChaincode returns the enclave executing the given chaincode,
+// or nil if the chaincode doesn't execute inside an enclave
+func (r *Registry) EnclaveForChaincode(chaincodeName string) endorsement.Enclave {
+	registeredLock.RLock()
+	defer registeredLock.RUnlock()
+	return enclaves[chaincodeName]
+}
+
+// QuoteVerifierByType returns the quote verifier of the given type of trusted
+// execution environment, or nil if there is none
+func (r *Registry) QuoteVerifierByType(evidenceType string) validation.QuoteVerifier {
+	registeredLock.RLock()
+	defer registeredLock.RUnlock()
+	return quoteVerifiers[evidenceType]
+}
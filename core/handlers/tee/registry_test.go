@@ -0,0 +1,44 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tee
+
+import (
+	"testing"
+
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api/attestation"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+type enclave struct{}
+
+func (*enclave) Attest(payload []byte, sp *peer.SignedProposal) (*peer.AttestationEvidence, error) {
+	return &peer.AttestationEvidence{}, nil
+}
+
+type quoteVerifier struct{}
+
+func (*quoteVerifier) VerifyQuote(quote []byte) (*validation.Report, error) {
+	return &validation.Report{}, nil
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	assert.Nil(t, r.EnclaveForChaincode("mycc"))
+	assert.Nil(t, r.QuoteVerifierByType("SGX"))
+
+	assert.EqualError(t, RegisterEnclave("mycc", nil), "nil enclave for chaincode mycc")
+	assert.NoError(t, RegisterEnclave("mycc", &enclave{}))
+	assert.EqualError(t, RegisterEnclave("mycc", &enclave{}), "an enclave is already registered for chaincode mycc")
+	assert.EqualError(t, RegisterQuoteVerifier("SGX", nil), "nil quote verifier for type SGX")
+	assert.NoError(t, RegisterQuoteVerifier("SGX", &quoteVerifier{}))
+	assert.EqualError(t, RegisterQuoteVerifier("SGX", &quoteVerifier{}), "a quote verifier is already registered for type SGX")
+
+	assert.NotNil(t, r.EnclaveForChaincode("mycc"))
+	assert.Nil(t, r.EnclaveForChaincode("othercc"))
+	assert.NotNil(t, r.QuoteVerifierByType("SGX"))
+}
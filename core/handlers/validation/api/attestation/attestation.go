@@ -0,0 +1,30 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package validation
+
+// Report is the content of a verified quote
+type Report struct {
+	// Measurement identifies the code executing inside the enclave
+	Measurement []byte
+	// ReportData is the data the enclave bound to the quote
+	ReportData []byte
+}
+
+// QuoteVerifier verifies the quotes of a type of trusted execution environment
+type QuoteVerifier interface {
+	// VerifyQuote returns the report of the given quote, or an error if the
+	// quote wasn't produced by a genuine enclave
+	VerifyQuote(quote []byte) (*Report, error)
+}
+
+// QuoteVerifierRetriever retrieves the quote verifiers of the trusted execution
+// environments
+type QuoteVerifierRetriever interface {
+	// QuoteVerifierByType returns the quote verifier of the given type of trusted
+	// execution environment, or nil if there is none
+	QuoteVerifierByType(evidenceType string) QuoteVerifier
+}
@@ -51,4 +51,16 @@ type Capabilities interface {
 	// KeyLevelEndorsement returns true if this channel supports endorsement
 	// policies expressible at a ledger key granularity, as described in FAB-8812
 	KeyLevelEndorsement() bool
+
+	// ChaincodeMigration returns true if this channel supports recording the
+	// migrations of the state of the chaincodes by their Migrate function
+	ChaincodeMigration() bool
+
+	// EphemeralCollections returns true if this channel supports the collections
+	// whose private data is never persisted by the peers
+	EphemeralCollections() bool
+
+	// ValueCommitments returns true if this channel supports the collections
+	// committing to their private values with Pedersen commitments
+	ValueCommitments() bool
 }
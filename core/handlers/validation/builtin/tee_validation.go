@@ -0,0 +1,182 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package builtin
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/bccsp/utils"
+	commonerrors "github.com/hyperledger/fabric/common/errors"
+	"github.com/hyperledger/fabric/core/handlers/validation/api"
+	. "github.com/hyperledger/fabric/core/handlers/validation/api/attestation"
+	. "github.com/hyperledger/fabric/core/handlers/validation/api/policies"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// TEEValidationFactory returns a validation plugin factory which returns plugins that
+// validate the transactions of chaincodes executing inside enclaves
+type TEEValidationFactory struct {
+	QuoteVerifierRetriever
+}
+
+// New returns a validation plugin that validates the transactions of chaincodes
+// executing inside enclaves
+func (f *TEEValidationFactory) New() validation.Plugin {
+	return &TEEValidation{QuoteVerifierRetriever: f.QuoteVerifierRetriever}
+}
+
+// TEEValidation is a validation plugin that behaves as the default validation system
+// chaincode, and additionally requires every endorsement of the transaction to carry
+// the attestation evidence of an enclave trusted to execute the chaincode. Its
+// validation parameter, recorded in the chaincode definition, is a
+// TEEValidationParameter carrying the endorsement policy and the measurements
+// of the trusted enclaves.
+type TEEValidation struct {
+	DefaultValidation
+	QuoteVerifierRetriever
+}
+
+// serializedPolicy is the endorsement policy of the TEEValidationParameter
+// passed to the default validation
+type serializedPolicy []byte
+
+// Bytes returns the bytes of the serializedPolicy
+func (sp serializedPolicy) Bytes() []byte {
+	return sp
+}
+
+// Validate returns nil if the action at the given position inside the transaction
+// at the given position in the given block satisfies the endorsement policy, and
+// all of its endorsements were attested by trusted enclaves, or an error if not.
+func (v *TEEValidation) Validate(block *common.Block, namespace string, txPosition int, actionPosition int, contextData ...validation.ContextDatum) error {
+	if len(contextData) == 0 {
+		logger.Panicf("Expected to receive the validation parameter in context data")
+	}
+	validationParameter, isSerializedPolicy := contextData[0].(SerializedPolicy)
+	if !isSerializedPolicy {
+		logger.Panicf("Expected to receive the serialized validation parameter in the first context data")
+	}
+	param := &peer.TEEValidationParameter{}
+	if err := proto.Unmarshal(validationParameter.Bytes(), param); err != nil {
+		return &commonerrors.VSCCEndorsementPolicyError{Err: errors.Wrapf(err, "invalid TEE validation parameter of chaincode %s", namespace)}
+	}
+
+	contextData = append([]validation.ContextDatum{serializedPolicy(param.EndorsementPolicy)}, contextData[1:]...)
+	if err := v.DefaultValidation.Validate(block, namespace, txPosition, actionPosition, contextData...); err != nil {
+		return err
+	}
+
+	cap, err := chaincodeActionPayload(block, txPosition, actionPosition)
+	if err != nil {
+		return &commonerrors.VSCCEndorsementPolicyError{Err: err}
+	}
+	for _, endorsement := range cap.Action.Endorsements {
+		if err := v.verifyAttestation(namespace, param.TrustedMeasurements, endorsement.Attestation, cap.Action.ProposalResponsePayload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyAttestation verifies that the given payload was produced by an enclave whose
+// measurement is one of the measurements trusted to execute the chaincode
+func (v *TEEValidation) verifyAttestation(namespace string, trustedMeasurements [][]byte, evidence *peer.AttestationEvidence, prpBytes []byte) error {
+	if evidence == nil {
+		return &commonerrors.VSCCEndorsementPolicyError{Err: errors.New("endorsement without attestation evidence")}
+	}
+	verifier := v.QuoteVerifierByType(evidence.Type)
+	if verifier == nil {
+		return &validation.ExecutionFailureError{Reason: fmt.Sprintf("no quote verifier for attestation type %s", evidence.Type)}
+	}
+	report, err := verifier.VerifyQuote(evidence.Quote)
+	if err != nil {
+		return &commonerrors.VSCCEndorsementPolicyError{Err: errors.WithMessage(err, "invalid quote")}
+	}
+	keyHash := sha256.Sum256(evidence.EnclavePublicKey)
+	if !bytes.HasPrefix(report.ReportData, keyHash[:]) {
+		return &commonerrors.VSCCEndorsementPolicyError{Err: errors.New("the quote doesn't bind the public key of the enclave")}
+	}
+	if !containsMeasurement(trustedMeasurements, report.Measurement) {
+		return &commonerrors.VSCCEndorsementPolicyError{Err: errors.Errorf("enclave with measurement %x isn't trusted to execute chaincode %s", report.Measurement, namespace)}
+	}
+	if err := verifyEnclaveSignature(evidence.EnclavePublicKey, evidence.Signature, prpBytes); err != nil {
+		return &commonerrors.VSCCEndorsementPolicyError{Err: err}
+	}
+	return nil
+}
+
+func chaincodeActionPayload(block *common.Block, txPosition int, actionPosition int) (*peer.ChaincodeActionPayload, error) {
+	env, err := putils.GetEnvelopeFromBlock(block.Data.Data[txPosition])
+	if err != nil {
+		return nil, err
+	}
+	payl, err := putils.GetPayload(env)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := putils.GetTransaction(payl.Data)
+	if err != nil {
+		return nil, err
+	}
+	if actionPosition >= len(tx.Actions) {
+		return nil, errors.Errorf("transaction has only %d actions, but requested action at position %d", len(tx.Actions), actionPosition)
+	}
+	cap, err := putils.GetChaincodeActionPayload(tx.Actions[actionPosition].Payload)
+	if err != nil {
+		return nil, err
+	}
+	if cap.Action == nil {
+		return nil, errors.New("nil chaincode endorsed action")
+	}
+	return cap, nil
+}
+
+func containsMeasurement(measurements [][]byte, measurement []byte) bool {
+	for _, m := range measurements {
+		if bytes.Equal(m, measurement) {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyEnclaveSignature verifies the ECDSA signature of the enclave over the payload
+func verifyEnclaveSignature(publicKey, signature, payload []byte) error {
+	key, err := x509.ParsePKIXPublicKey(publicKey)
+	if err != nil {
+		return errors.Wrap(err, "invalid public key of the enclave")
+	}
+	ecdsaKey, isECDSA := key.(*ecdsa.PublicKey)
+	if !isECDSA {
+		return errors.Errorf("public key of the enclave of type %T isn't an ECDSA key", key)
+	}
+	r, s, err := utils.UnmarshalECDSASignature(signature)
+	if err != nil {
+		return errors.WithMessage(err, "invalid signature of the enclave")
+	}
+	digest := sha256.Sum256(payload)
+	if !ecdsa.Verify(ecdsaKey, digest[:], r, s) {
+		return errors.New("the signature of the enclave over the proposal response payload is invalid")
+	}
+	return nil
+}
+
+// Init injects dependencies into the instance of the Plugin
+func (v *TEEValidation) Init(dependencies ...validation.Dependency) error {
+	if v.QuoteVerifierRetriever == nil {
+		return errors.New("no QuoteVerifierRetriever in the plugin factory")
+	}
+	return v.DefaultValidation.Init(dependencies...)
+}
@@ -0,0 +1,156 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package builtin
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	commonerrors "github.com/hyperledger/fabric/common/errors"
+	"github.com/hyperledger/fabric/core/committer/txvalidator"
+	. "github.com/hyperledger/fabric/core/handlers/validation/api"
+	. "github.com/hyperledger/fabric/core/handlers/validation/api/attestation"
+	vmocks "github.com/hyperledger/fabric/core/handlers/validation/builtin/mocks"
+	"github.com/hyperledger/fabric/core/handlers/validation/builtin/v12/mocks"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type quoteVerifier struct {
+	report *Report
+	err    error
+}
+
+func (v *quoteVerifier) VerifyQuote(quote []byte) (*Report, error) {
+	return v.report, v.err
+}
+
+type quoteVerifiers map[string]QuoteVerifier
+
+func (v quoteVerifiers) QuoteVerifierByType(evidenceType string) QuoteVerifier {
+	return v[evidenceType]
+}
+
+func blockWithEndorsements(t *testing.T, prpBytes []byte, endorsements ...*peer.Endorsement) *common.Block {
+	capBytes, err := proto.Marshal(&peer.ChaincodeActionPayload{
+		Action: &peer.ChaincodeEndorsedAction{ProposalResponsePayload: prpBytes, Endorsements: endorsements},
+	})
+	assert.NoError(t, err)
+	txBytes, err := proto.Marshal(&peer.Transaction{Actions: []*peer.TransactionAction{{Payload: capBytes}}})
+	assert.NoError(t, err)
+	paylBytes, err := proto.Marshal(&common.Payload{Header: &common.Header{}, Data: txBytes})
+	assert.NoError(t, err)
+	envBytes, err := proto.Marshal(&common.Envelope{Payload: paylBytes})
+	assert.NoError(t, err)
+	return &common.Block{
+		Header: &common.BlockHeader{},
+		Data:   &common.BlockData{Data: [][]byte{envBytes}},
+	}
+}
+
+func TestTEEValidationInit(t *testing.T) {
+	deps := []Dependency{&mocks.IdentityDeserializer{}, &mocks.Capabilities{}, &mocks.StateFetcher{}, &mocks.PolicyEvaluator{}}
+	assert.EqualError(t, (&TEEValidationFactory{}).New().Init(deps...), "no QuoteVerifierRetriever in the plugin factory")
+
+	validation := (&TEEValidationFactory{QuoteVerifierRetriever: quoteVerifiers{}}).New()
+	assert.EqualError(t, validation.Init(deps[1:]...), "identityDeserializer not passed in init")
+	assert.NoError(t, validation.Init(deps...))
+}
+
+func TestTEEValidation(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	keyHash := sha256.Sum256(publicKey)
+	prpBytes := []byte("proposal response payload")
+	digest := sha256.Sum256(prpBytes)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	assert.NoError(t, err)
+
+	verifier := &quoteVerifier{report: &Report{Measurement: []byte{1, 2, 3}, ReportData: append(keyHash[:], make([]byte, 32)...)}}
+	validator := &vmocks.TransactionValidator{}
+	capabilities := &mocks.Capabilities{}
+	capabilities.On("V1_3Validation").Return(false)
+	capabilities.On("V1_2Validation").Return(true)
+	validation := &TEEValidation{
+		DefaultValidation: DefaultValidation{
+			TxValidatorV1_2: validator,
+			Capabilities:    capabilities,
+		},
+		QuoteVerifierRetriever: quoteVerifiers{"SGX": verifier},
+	}
+	validationParameter, err := proto.Marshal(&peer.TEEValidationParameter{
+		EndorsementPolicy:   []byte("policy"),
+		TrustedMeasurements: [][]byte{{1, 2, 3}},
+	})
+	assert.NoError(t, err)
+	validate := func(endorsements ...*peer.Endorsement) error {
+		return validation.Validate(blockWithEndorsements(t, prpBytes, endorsements...), "mycc", 0, 0, txvalidator.SerializedPolicy(validationParameter))
+	}
+	evidence := func() *peer.AttestationEvidence {
+		return &peer.AttestationEvidence{Type: "SGX", Quote: []byte{1}, EnclavePublicKey: publicKey, Signature: signature}
+	}
+
+	// Scenario I: The endorsement policy isn't satisfied
+	validator.On("Validate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&commonerrors.VSCCEndorsementPolicyError{Err: errors.New("policy not satisfied")}).Once()
+	assert.EqualError(t, validate(&peer.Endorsement{Attestation: evidence()}), "policy not satisfied")
+
+	// the default validation evaluates the endorsement policy of the validation parameter
+	validator.On("Validate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, []byte("policy")).Return(nil)
+
+	// Scenario II: All the endorsements are attested by trusted enclaves
+	assert.NoError(t, validate(&peer.Endorsement{Attestation: evidence()}, &peer.Endorsement{Attestation: evidence()}))
+
+	// Scenario III: An endorsement has no attestation evidence
+	err = validate(&peer.Endorsement{Attestation: evidence()}, &peer.Endorsement{})
+	assert.IsType(t, &commonerrors.VSCCEndorsementPolicyError{}, err)
+	assert.EqualError(t, err, "endorsement without attestation evidence")
+
+	// Scenario IV: The peer can't verify the quotes of the type of the evidence
+	unknownType := evidence()
+	unknownType.Type = "SEV"
+	err = validate(&peer.Endorsement{Attestation: unknownType})
+	assert.IsType(t, &ExecutionFailureError{}, err)
+	assert.EqualError(t, err, "no quote verifier for attestation type SEV")
+
+	// Scenario V: The quote is invalid
+	verifier.err = errors.New("bad quote")
+	assert.EqualError(t, validate(&peer.Endorsement{Attestation: evidence()}), "invalid quote: bad quote")
+	verifier.err = nil
+
+	// Scenario VI: The quote doesn't bind the public key of the enclave
+	otherKey := evidence()
+	otherKey.EnclavePublicKey = append([]byte{}, publicKey...)
+	otherKey.EnclavePublicKey[len(otherKey.EnclavePublicKey)-1] ^= 1
+	assert.EqualError(t, validate(&peer.Endorsement{Attestation: otherKey}), "the quote doesn't bind the public key of the enclave")
+
+	// Scenario VII: The enclave isn't trusted to execute the chaincode
+	verifier.report.Measurement = []byte{4, 5, 6}
+	assert.EqualError(t, validate(&peer.Endorsement{Attestation: evidence()}), "enclave with measurement 040506 isn't trusted to execute chaincode mycc")
+	verifier.report.Measurement = []byte{1, 2, 3}
+
+	// Scenario VIII: The enclave didn't sign the payload
+	forged := evidence()
+	digest = sha256.Sum256([]byte("other payload"))
+	forged.Signature, err = ecdsa.SignASN1(rand.Reader, key, digest[:])
+	assert.NoError(t, err)
+	assert.EqualError(t, validate(&peer.Endorsement{Attestation: forged}), "the signature of the enclave over the proposal response payload is invalid")
+
+	// Scenario IX: The validation parameter of the chaincode isn't a TEEValidationParameter
+	err = validation.Validate(blockWithEndorsements(t, prpBytes, &peer.Endorsement{Attestation: evidence()}), "mycc", 0, 0, txvalidator.SerializedPolicy("barf"))
+	assert.IsType(t, &commonerrors.VSCCEndorsementPolicyError{}, err)
+	assert.Contains(t, err.Error(), "invalid TEE validation parameter of chaincode mycc")
+}
@@ -170,6 +170,22 @@ func (vscc *Validator) checkInstantiationPolicy(chainName string, env *common.En
 	return nil
 }
 
+func validateCollectionCapabilities(newCollectionConfigs []*common.CollectionConfig, ac channelconfig.ApplicationCapabilities) error {
+	// The collections may only use the features enabled by the capabilities of the channel
+	for _, newCollectionConfig := range newCollectionConfigs {
+		newCollection := newCollectionConfig.GetStaticCollectionConfig()
+		if newCollection.GetEphemeral() && !ac.EphemeralCollections() {
+			return fmt.Errorf("collection %s is ephemeral but the ephemeral collections are not enabled by the capabilities of the channel",
+				newCollection.GetName())
+		}
+		if newCollection.GetValueCommitment() != "" && !ac.ValueCommitments() {
+			return fmt.Errorf("collection %s commits to its values but the value commitments are not enabled by the capabilities of the channel",
+				newCollection.GetName())
+		}
+	}
+	return nil
+}
+
 func validateNewCollectionConfigs(newCollectionConfigs []*common.CollectionConfig) error {
 	newCollectionsMap := make(map[string]bool, len(newCollectionConfigs))
 	// Process each collection config from a set of collection configs
@@ -295,6 +311,36 @@ func checkForModifiedCollectionsBTL(newCollectionsMap map[string]*common.StaticC
 	return nil
 }
 
+func checkForModifiedCollectionsStateDatabase(newCollectionsMap map[string]*common.StaticCollectionConfig, oldCollectionConfigs []*common.CollectionConfig,
+) error {
+	var modifiedCollectionsStateDatabase []string
+
+	// In the new collection config package, ensure that the state database is not
+	// modified for the existing collections, their private data being stored there
+	for _, oldCollectionConfig := range oldCollectionConfigs {
+
+		oldCollection := oldCollectionConfig.GetStaticCollectionConfig()
+		// It cannot be nil
+		if oldCollection == nil {
+			return policyErr(fmt.Errorf("unknown collection configuration type"))
+		}
+
+		oldCollectionName := oldCollection.GetName()
+		newCollection, _ := newCollectionsMap[oldCollectionName]
+		// StateDatabase cannot be changed
+		if newCollection.GetStateDatabase() != oldCollection.GetStateDatabase() {
+			modifiedCollectionsStateDatabase = append(modifiedCollectionsStateDatabase, oldCollectionName)
+		}
+	}
+
+	if len(modifiedCollectionsStateDatabase) > 0 {
+		return policyErr(fmt.Errorf("the StateDatabase in the following existing collections must not be modified: %v",
+			modifiedCollectionsStateDatabase))
+	}
+
+	return nil
+}
+
 func validateNewCollectionConfigsAgainstOld(newCollectionConfigs []*common.CollectionConfig, oldCollectionConfigs []*common.CollectionConfig,
 ) error {
 	newCollectionsMap := make(map[string]*common.StaticCollectionConfig, len(newCollectionConfigs))
@@ -314,6 +360,10 @@ func validateNewCollectionConfigsAgainstOld(newCollectionConfigs []*common.Colle
 		return err
 	}
 
+	if err := checkForModifiedCollectionsStateDatabase(newCollectionsMap, oldCollectionConfigs); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -421,6 +471,9 @@ func (vscc *Validator) validateRWSetAndCollection(
 		if err := validateNewCollectionConfigs(newCollectionConfigs); err != nil {
 			return policyErr(err)
 		}
+		if err := validateCollectionCapabilities(newCollectionConfigs, ac); err != nil {
+			return policyErr(err)
+		}
 
 		if lsccFunc == lscc.UPGRADE {
 
@@ -1896,7 +1896,31 @@ func TestValidateRWSetAndCollectionForDeploy(t *testing.T) {
 	err = testValidateCollection(t, v, []*common.CollectionConfig{coll3}, cdRWSet, lsccFunc, ac, chid)
 	assert.EqualError(t, err, "collection-name: mycollection3 -- error in member org policy: signature policy is not an OR concatenation, NOutOf 2")
 
-	// Test 13: deploy with existing collection config on the ledger -> error
+	// Test 13: ephemeral collection without the V1_4_2 capability -> error
+	policyEnvelope = cauthdsl.Envelope(cauthdsl.Or(cauthdsl.SignedBy(0), cauthdsl.SignedBy(1)), signers)
+	coll3 = createCollectionConfig(collName3, policyEnvelope, requiredPeerCount, maximumPeerCount, blockToLive)
+	coll3.GetStaticCollectionConfig().Ephemeral = true
+	err = testValidateCollection(t, v, []*common.CollectionConfig{coll1, coll3}, cdRWSet, lsccFunc, ac, chid)
+	assert.EqualError(t, err, "collection mycollection3 is ephemeral but the ephemeral collections are not enabled by the capabilities of the channel")
+
+	// Test 14: ephemeral collection with the V1_4_2 capability -> success
+	v142ac := capabilities.NewApplicationProvider(map[string]*common.Capability{
+		capabilities.ApplicationV1_4_2: {},
+	})
+	err = testValidateCollection(t, v, []*common.CollectionConfig{coll1, coll3}, cdRWSet, lsccFunc, v142ac, chid)
+	assert.NoError(t, err)
+
+	// Test 15: collection committing to its values without the V1_4_2 capability -> error
+	coll3 = createCollectionConfig(collName3, policyEnvelope, requiredPeerCount, maximumPeerCount, blockToLive)
+	coll3.GetStaticCollectionConfig().ValueCommitment = "Pedersen"
+	err = testValidateCollection(t, v, []*common.CollectionConfig{coll1, coll3}, cdRWSet, lsccFunc, ac, chid)
+	assert.EqualError(t, err, "collection mycollection3 commits to its values but the value commitments are not enabled by the capabilities of the channel")
+
+	// Test 16: collection committing to its values with the V1_4_2 capability -> success
+	err = testValidateCollection(t, v, []*common.CollectionConfig{coll1, coll3}, cdRWSet, lsccFunc, v142ac, chid)
+	assert.NoError(t, err)
+
+	// Test 17: deploy with existing collection config on the ledger -> error
 	ccp := &common.CollectionConfigPackage{Config: []*common.CollectionConfig{coll1}}
 	ccpBytes, err := proto.Marshal(ccp)
 	assert.NoError(t, err)
@@ -1968,6 +1992,13 @@ func TestValidateRWSetAndCollectionForUpgrade(t *testing.T) {
 	// Test 6: modify the BlockToLive in an existing collection -> error
 	err = testValidateCollection(t, v, []*common.CollectionConfig{coll1, coll2, coll3}, cdRWSet, lsccFunc, ac, chid)
 	assert.EqualError(t, err, "the BlockToLive in the following existing collections must not be modified: [mycollection2]")
+
+	coll2 = createCollectionConfig(collName2, policyEnvelope, requiredPeerCount, maximumPeerCount, blockToLive)
+	coll2.GetStaticCollectionConfig().StateDatabase = "CouchDB"
+
+	// Test 7: modify the StateDatabase in an existing collection -> error
+	err = testValidateCollection(t, v, []*common.CollectionConfig{coll1, coll2, coll3}, cdRWSet, lsccFunc, ac, chid)
+	assert.EqualError(t, err, "the StateDatabase in the following existing collections must not be modified: [mycollection2]")
 }
 
 var lccctestpath = "/tmp/lscc-validation-test"
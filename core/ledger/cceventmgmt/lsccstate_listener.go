@@ -34,6 +34,12 @@ func (listener *KVLedgerLSCCStateListener) HandleStateUpdates(trigger *ledger.St
 	chaincodesCollConfigs := make(map[string][]byte)
 
 	for _, kvWrite := range kvWrites {
+		// The migrations of the chaincode states, which also contain the
+		// CollectionSeparator, don't change the chaincode definitions
+		if ccprovider.IsMigrationKey(kvWrite.Key) {
+			logger.Infof("Channel [%s]: Chaincode [%s] migrated its state to version [%s]", channelName, ccprovider.GetCCNameFromMigrationKey(kvWrite.Key), kvWrite.Value)
+			continue
+		}
 		// There are LSCC entries for the chaincode and for the chaincode collections.
 		// We can detect collections based on the presence of a CollectionSeparator,
 		// which never exists in chaincode names.
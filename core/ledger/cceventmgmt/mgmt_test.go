@@ -148,6 +148,20 @@ func TestLSCCListener(t *testing.T) {
 		)
 		assert.NotContains(t, handler1.eventsRecieved, &mockEvent{cc3Def, ccDBArtifactsTar})
 	})
+
+	// test4 migration lscc event is neither a chaincode definition nor sent to handler
+	t.Run("MigrationEvent", func(t *testing.T) {
+		eventsRecieved := len(handler1.eventsRecieved)
+		err := lsccStateListener.HandleStateUpdates(&ledger.StateUpdateTrigger{
+			LedgerID: channelName,
+			StateUpdates: ledger.StateUpdates{
+				lsccNamespace: []*kvrwset.KVWrite{{Key: ccprovider.BuildMigrationKVSKey(cc1Def.Name), Value: []byte(cc1Def.Version)}},
+			},
+			CommittingBlockNum: 51},
+		)
+		assert.NoError(t, err)
+		assert.Len(t, handler1.eventsRecieved, eventsRecieved)
+	})
 }
 
 type mockProvider struct {
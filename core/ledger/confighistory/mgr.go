@@ -11,6 +11,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
@@ -112,7 +113,7 @@ func prepareDBBatch(stateUpdates ledger.StateUpdates, committingBlock uint64) *b
 	batch := newBatch()
 	lsccWrites := stateUpdates[lsccNamespace]
 	for _, kv := range lsccWrites.([]*kvrwset.KVWrite) {
-		if !privdata.IsCollectionConfigKey(kv.Key) {
+		if ccprovider.IsMigrationKey(kv.Key) || !privdata.IsCollectionConfigKey(kv.Key) {
 			continue
 		}
 		batch.add(lsccNamespace, kv.Key, committingBlock, kv.Value)
@@ -10,15 +10,26 @@ import (
 	"sync"
 
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
 )
 
+// reservedHeaderType is the header type which fabric removed, and which must not be reused
+const reservedHeaderType = common.HeaderType(7)
+
 var processors Processors
 var once sync.Once
 
+var (
+	registeredLock sync.RWMutex
+	registered     = Processors{}
+	initialized    bool
+)
+
 // Processors maintains the association between a custom transaction type to its corresponding tx processor
 type Processors map[common.HeaderType]Processor
 
 // Initialize sets the custom processors. This function is expected to be invoked only during ledgermgmt.Initialize() function.
+// The processors registered with Register are added to the given ones.
 func Initialize(customTxProcessors Processors) {
 	once.Do(func() {
 		initialize(customTxProcessors)
@@ -26,7 +37,49 @@ func Initialize(customTxProcessors Processors) {
 }
 
 func initialize(customTxProcessors Processors) {
-	processors = customTxProcessors
+	registeredLock.Lock()
+	defer registeredLock.Unlock()
+	initialized = true
+	processors = Processors{}
+	for txType, processor := range registered {
+		processors[txType] = processor
+	}
+	for txType, processor := range customTxProcessors {
+		processors[txType] = processor
+	}
+}
+
+// Register registers the processor of a new transaction type, which is then
+// accepted by the committer and processed at commit time. It is expected to be
+// invoked by the init functions of the packages compiled in the peer, or while
+// loading the plugins of the peer, before the ledgers are initialized. The header
+// types which fabric defines cannot be registered.
+func Register(txType common.HeaderType, processor Processor) error {
+	if _, ok := common.HeaderType_name[int32(txType)]; ok || txType == reservedHeaderType {
+		return errors.Errorf("header type %s is reserved", txType)
+	}
+	if processor == nil {
+		return errors.Errorf("nil processor for header type %s", txType)
+	}
+
+	registeredLock.Lock()
+	defer registeredLock.Unlock()
+	if initialized {
+		return errors.Errorf("processor for header type %s registered after the ledgers were initialized", txType)
+	}
+	if _, ok := registered[txType]; ok {
+		return errors.Errorf("a processor is already registered for header type %s", txType)
+	}
+	registered[txType] = processor
+	return nil
+}
+
+// IsRegistered returns whether a processor was registered for the transaction type
+func IsRegistered(txType common.HeaderType) bool {
+	registeredLock.RLock()
+	defer registeredLock.RUnlock()
+	_, ok := registered[txType]
+	return ok
 }
 
 // GetProcessor returns a Processor associated with the txType
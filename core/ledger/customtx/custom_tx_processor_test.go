@@ -0,0 +1,52 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package customtx
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type testProcessor struct {
+	name string
+}
+
+func (p *testProcessor) GenerateSimulationResults(txEnvelop *common.Envelope, simulator ledger.TxSimulator, initializingLedger bool) error {
+	return nil
+}
+
+func resetRegistered() {
+	registered = Processors{}
+	initialized = false
+}
+
+func TestRegister(t *testing.T) {
+	defer resetRegistered()
+	configProcessor := &testProcessor{name: "config"}
+	processor := &testProcessor{name: "custom"}
+
+	assert.NoError(t, Register(100, processor))
+	assert.True(t, IsRegistered(100))
+	assert.False(t, IsRegistered(101))
+	assert.False(t, IsRegistered(common.HeaderType_CONFIG))
+
+	assert.EqualError(t, Register(100, &testProcessor{}), "a processor is already registered for header type 100")
+	assert.EqualError(t, Register(common.HeaderType_ENDORSER_TRANSACTION, processor), "header type ENDORSER_TRANSACTION is reserved")
+	assert.EqualError(t, Register(7, processor), "header type 7 is reserved")
+	assert.EqualError(t, Register(101, nil), "nil processor for header type 101")
+
+	// the registered processors are added to the processors of the ledger
+	InitializeTestEnv(Processors{common.HeaderType_CONFIG: configProcessor})
+	assert.Equal(t, configProcessor, GetProcessor(common.HeaderType_CONFIG))
+	assert.Equal(t, processor, GetProcessor(100))
+	assert.Nil(t, GetProcessor(101))
+
+	assert.EqualError(t, Register(101, processor), "processor for header type 101 registered after the ledgers were initialized")
+}
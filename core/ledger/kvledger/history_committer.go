@@ -0,0 +1,97 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// historyQueueSize is the number of committed blocks which may wait for being
+// committed to the history database, beyond which the commit of the blocks
+// waits for the history database
+const historyQueueSize = 100
+
+// historyCommitter commits the blocks to the history database asynchronously
+// with their commit to the block store and the state database. As the history
+// database records its savepoint, the blocks not committed to it yet when the
+// peer stops are recommitted by recoverDBs at the next start.
+type historyCommitter struct {
+	ledgerID  string
+	historyDB historydb.HistoryDB
+	duration  metrics.Histogram
+	queue     chan *common.Block
+	done      chan struct{}
+
+	cond *sync.Cond
+	// height is the number of blocks committed to the history database so far
+	height uint64
+}
+
+func newHistoryCommitter(ledgerID string, historyDB historydb.HistoryDB, duration metrics.Histogram, height uint64) *historyCommitter {
+	c := &historyCommitter{
+		ledgerID:  ledgerID,
+		historyDB: historyDB,
+		duration:  duration,
+		queue:     make(chan *common.Block, historyQueueSize),
+		done:      make(chan struct{}),
+		cond:      sync.NewCond(&sync.Mutex{}),
+		height:    height,
+	}
+	go c.run()
+	return c
+}
+
+func (c *historyCommitter) run() {
+	defer close(c.done)
+	for block := range c.queue {
+		logger.Debugf("[%s] Committing block [%d] transactions to history database", c.ledgerID, block.Header.Number)
+		startCommitHistory := time.Now()
+		if err := c.historyDB.Commit(block); err != nil {
+			panic(errors.WithMessage(err, "Error during commit to history db"))
+		}
+		c.duration.RecordDuration(time.Since(startCommitHistory))
+
+		c.cond.L.Lock()
+		c.height = block.Header.Number + 1
+		c.cond.Broadcast()
+		c.cond.L.Unlock()
+	}
+}
+
+// enqueue schedules the commit of a block to the history database, waiting if
+// too many blocks are already waiting for being committed
+func (c *historyCommitter) enqueue(block *common.Block) {
+	c.queue <- block
+}
+
+// waitCommitted waits for the blocks below the given height to be committed to
+// the history database
+func (c *historyCommitter) waitCommitted(height uint64) {
+	c.cond.L.Lock()
+	defer c.cond.L.Unlock()
+	for c.height < height {
+		c.cond.Wait()
+	}
+}
+
+func (c *historyCommitter) committedHeight() uint64 {
+	c.cond.L.Lock()
+	defer c.cond.L.Unlock()
+	return c.height
+}
+
+// close waits for the blocks already scheduled to be committed
+func (c *historyCommitter) close() {
+	close(c.queue)
+	<-c.done
+}
@@ -11,8 +11,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/flogging"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
@@ -20,11 +23,13 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr/lockbasedtxmgr"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/ledgerstorage"
 	"github.com/hyperledger/fabric/core/ledger/pvtdatapolicy"
+	lgrutil "github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/pkg/errors"
@@ -41,6 +46,20 @@ type kvLedger struct {
 	historyDB              historydb.HistoryDB
 	configHistoryRetriever ledger.ConfigHistoryRetriever
 	blockAPIsRWLock        *sync.RWMutex
+	// replica is set when the peer is a read replica of the shared state database
+	replica *replica
+	// historyCommitter is set when the blocks are committed to the history
+	// database asynchronously
+	historyCommitter *historyCommitter
+	// snapshotMgr generates the snapshots of the state
+	snapshotMgr *snapshotMgr
+	// valueHasher selects the hash function of the write-sets of the blocks
+	valueHasher *valueHasher
+
+	stateValidationDuration metrics.Histogram
+	blockCommitDuration     metrics.Histogram
+	stateCommitDuration     metrics.Histogram
+	historyCommitDuration   metrics.Histogram
 }
 
 // NewKVLedger constructs new `KVLedger`
@@ -52,13 +71,25 @@ func newKVLedger(
 	configHistoryMgr confighistory.Mgr,
 	stateListeners []ledger.StateListener,
 	bookkeeperProvider bookkeeping.Provider,
-	ccInfoProvider ledger.DeployedChaincodeInfoProvider) (*kvLedger, error) {
+	ccInfoProvider ledger.DeployedChaincodeInfoProvider,
+	metricsScope metrics.Scope,
+	snapshotSigner crypto.LocalSigner) (*kvLedger, error) {
 
 	logger.Debugf("Creating KVLedger ledgerID=%s: ", ledgerID)
 	stateListeners = append(stateListeners, configHistoryMgr)
 	// Create a kvLedger for this chain/ledger, which encasulates the underlying
 	// id store, blockstore, txmgr (state database), history database
 	l := &kvLedger{ledgerID: ledgerID, blockStore: blockStore, historyDB: historyDB, blockAPIsRWLock: &sync.RWMutex{}}
+	l.valueHasher = newValueHasher(ledgerID, blockStore)
+	if metricsScope == nil {
+		metricsScope = metrics.NewNoOpScope()
+	}
+	metricsScope = metricsScope.Tagged(map[string]string{"channel": ledgerID})
+	l.stateValidationDuration = metricsScope.Histogram("state_validation_duration")
+	l.blockCommitDuration = metricsScope.Histogram("block_commit_duration")
+	l.stateCommitDuration = metricsScope.Histogram("state_commit_duration")
+	l.historyCommitDuration = metricsScope.Histogram("history_commit_duration")
+	blockStore.InitMetrics(metricsScope)
 
 	// TODO Move the function `GetChaincodeEventListener` to ledger interface and
 	// this functionality of regiserting for events to ledgermgmt package so that this
@@ -68,6 +99,13 @@ func newKVLedger(
 	if ccEventListener != nil {
 		cceventmgmt.GetMgr().Register(ledgerID, ccEventListener)
 	}
+	if ledgerconfig.IsReadReplica() {
+		markers, ok := versionedDB.(statedb.ReplicationCapable)
+		if !ok {
+			return nil, errors.New("the state database cannot be shared with read replicas")
+		}
+		l.replica = newReplica(ledgerID, markers, l.height)
+	}
 	btlPolicy := pvtdatapolicy.NewBTLPolicy(l)
 	if err := l.initTxMgr(versionedDB, stateListeners, btlPolicy, bookkeeperProvider); err != nil {
 		return nil, err
@@ -77,6 +115,14 @@ func newKVLedger(
 	if err := l.recoverDBs(); err != nil {
 		panic(errors.WithMessage(err, "error during state DB recovery"))
 	}
+	if ledgerconfig.IsHistoryDBEnabled() && ledgerconfig.IsAsyncHistoryCommitEnabled() {
+		height, err := l.height()
+		if err != nil {
+			return nil, err
+		}
+		l.historyCommitter = newHistoryCommitter(ledgerID, historyDB, l.historyCommitDuration, height)
+	}
+	l.snapshotMgr = newSnapshotMgr(ledgerID, snapshotSigner)
 	l.configHistoryRetriever = configHistoryMgr.GetRetriever(ledgerID, l)
 	return l, nil
 }
@@ -84,7 +130,7 @@ func newKVLedger(
 func (l *kvLedger) initTxMgr(versionedDB privacyenabledstate.DB, stateListeners []ledger.StateListener,
 	btlPolicy pvtdatapolicy.BTLPolicy, bookkeeperProvider bookkeeping.Provider) error {
 	var err error
-	l.txtmgmt, err = lockbasedtxmgr.NewLockBasedTxMgr(l.ledgerID, versionedDB, stateListeners, btlPolicy, bookkeeperProvider)
+	l.txtmgmt, err = lockbasedtxmgr.NewLockBasedTxMgr(l.ledgerID, versionedDB, stateListeners, btlPolicy, bookkeeperProvider, l.valueHasher)
 	return err
 }
 
@@ -104,6 +150,10 @@ func (l *kvLedger) recoverDBs() error {
 	}
 	lastAvailableBlockNum := info.Height - 1
 	recoverables := []recoverable{l.txtmgmt, l.historyDB}
+	if l.replica != nil {
+		// the shared state database is recovered by the committer
+		recoverables = []recoverable{l.historyDB}
+	}
 	recoverers := []*recoverer{}
 	for _, recoverable := range recoverables {
 		recoverFlag, firstBlockNum, err := recoverable.ShouldRecover(lastAvailableBlockNum)
@@ -225,6 +275,31 @@ func (l *kvLedger) GetTxValidationCodeByTxID(txID string) (peer.TxValidationCode
 	return txValidationCode, err
 }
 
+// GetTransactionsByCreator returns the transactions created by the given serialized identity
+func (l *kvLedger) GetTransactionsByCreator(creator []byte, startBlockNum uint64, limit int) ([]*ledger.IndexedTransaction, error) {
+	txs, err := l.blockStore.RetrieveTxsByCreator(creator, startBlockNum, limit)
+	l.blockAPIsRWLock.RLock()
+	l.blockAPIsRWLock.RUnlock()
+	return txs, err
+}
+
+// GetBlocksByTimeRange returns the blocks whose time falls within the given range
+func (l *kvLedger) GetBlocksByTimeRange(start, end time.Time, limit int) ([]*common.Block, error) {
+	blocks, err := l.blockStore.RetrieveBlocksByTimeRange(start, end, limit)
+	l.blockAPIsRWLock.RLock()
+	l.blockAPIsRWLock.RUnlock()
+	return blocks, err
+}
+
+// GetBlockNumByDedupKey returns the number of the block of the last valid transaction
+// carrying the given dedup key for the given chaincode
+func (l *kvLedger) GetBlockNumByDedupKey(namespace, dedupKey string) (uint64, error) {
+	blockNum, err := l.blockStore.RetrieveBlockNumByDedupKey(namespace, dedupKey)
+	l.blockAPIsRWLock.RLock()
+	l.blockAPIsRWLock.RUnlock()
+	return blockNum, err
+}
+
 //Prune prunes the blocks/transactions that satisfy the given policy
 func (l *kvLedger) Prune(policy commonledger.PrunePolicy) error {
 	return errors.New("not yet implemented")
@@ -232,13 +307,31 @@ func (l *kvLedger) Prune(policy commonledger.PrunePolicy) error {
 
 // NewTxSimulator returns new `ledger.TxSimulator`
 func (l *kvLedger) NewTxSimulator(txid string) (ledger.TxSimulator, error) {
-	return l.txtmgmt.NewTxSimulator(txid)
+	if l.replica == nil {
+		return l.txtmgmt.NewTxSimulator(txid)
+	}
+	marker, err := l.replica.waitConsistent()
+	if err != nil {
+		return nil, err
+	}
+	txsim, err := l.txtmgmt.NewTxSimulator(txid)
+	if err != nil {
+		return nil, err
+	}
+	return &replicaTxSimulator{TxSimulator: txsim, replica: l.replica, marker: marker}, nil
 }
 
 // NewQueryExecutor gives handle to a query executor.
 // A client can obtain more than one 'QueryExecutor's for parallel execution.
 // Any synchronization should be performed at the implementation level if required
 func (l *kvLedger) NewQueryExecutor() (ledger.QueryExecutor, error) {
+	if l.replica != nil {
+		// the reads of a query executor are not checked against the commits
+		// started meanwhile, since it doesn't report the completion of its reads
+		if _, err := l.replica.waitConsistent(); err != nil {
+			return nil, err
+		}
+	}
 	return l.txtmgmt.NewQueryExecutor(util.GenerateUUID())
 }
 
@@ -247,11 +340,22 @@ func (l *kvLedger) NewQueryExecutor() (ledger.QueryExecutor, error) {
 // Any synchronization should be performed at the implementation level if required
 // Pass the ledger blockstore so that historical values can be looked up from the chain
 func (l *kvLedger) NewHistoryQueryExecutor() (ledger.HistoryQueryExecutor, error) {
+	if l.historyCommitter != nil {
+		// the history queries read the blocks committed when they start
+		height, err := l.height()
+		if err != nil {
+			return nil, err
+		}
+		l.historyCommitter.waitCommitted(height)
+	}
 	return l.historyDB.NewHistoryQueryExecutor(l.blockStore)
 }
 
 // CommitWithPvtData commits the block and the corresponding pvt data in an atomic operation
 func (l *kvLedger) CommitWithPvtData(pvtdataAndBlock *ledger.BlockAndPvtData) error {
+	if l.replica != nil {
+		return l.commitAsReplica(pvtdataAndBlock)
+	}
 	var err error
 	block := pvtdataAndBlock.Block
 	blockNo := pvtdataAndBlock.Block.Header.Number
@@ -262,6 +366,7 @@ func (l *kvLedger) CommitWithPvtData(pvtdataAndBlock *ledger.BlockAndPvtData) er
 	if err != nil {
 		return err
 	}
+	l.stateValidationDuration.RecordDuration(time.Since(startStateValidation))
 	elapsedStateValidation := time.Since(startStateValidation) / time.Millisecond // duration in ms
 
 	startCommitBlockStorage := time.Now()
@@ -271,6 +376,7 @@ func (l *kvLedger) CommitWithPvtData(pvtdataAndBlock *ledger.BlockAndPvtData) er
 	if err = l.blockStore.CommitWithPvtData(pvtdataAndBlock); err != nil {
 		return err
 	}
+	l.blockCommitDuration.RecordDuration(time.Since(startCommitBlockStorage))
 	elapsedCommitBlockStorage := time.Since(startCommitBlockStorage) / time.Millisecond // duration in ms
 
 	startCommitState := time.Now()
@@ -278,17 +384,18 @@ func (l *kvLedger) CommitWithPvtData(pvtdataAndBlock *ledger.BlockAndPvtData) er
 	if err = l.txtmgmt.Commit(); err != nil {
 		panic(errors.WithMessage(err, "error during commit to txmgr"))
 	}
+	l.valueHasher.committed(block)
+	l.stateCommitDuration.RecordDuration(time.Since(startCommitState))
 	elapsedCommitState := time.Since(startCommitState) / time.Millisecond // duration in ms
 
-	// History database could be written in parallel with state and/or async as a future optimization,
-	// although it has not been a bottleneck...no need to clutter the log with elapsed duration.
-	if ledgerconfig.IsHistoryDBEnabled() {
-		logger.Debugf("[%s] Committing block [%d] transactions to history database", l.ledgerID, blockNo)
-		if err := l.historyDB.Commit(block); err != nil {
-			panic(errors.WithMessage(err, "Error during commit to history db"))
-		}
+	if l.snapshotMgr.due(blockNo) {
+		l.snapshotState(block)
 	}
 
+	// History database could be written in parallel with state as a future optimization,
+	// although it has not been a bottleneck...no need to clutter the log with elapsed duration.
+	l.commitHistory(block)
+
 	elapsedCommitWithPvtData := time.Since(startStateValidation) / time.Millisecond // total duration in ms
 
 	logger.Infof("[%s] Committed block [%d] with %d transaction(s) in %dms (state_validation=%dms block_commit=%dms state_commit=%dms)",
@@ -298,6 +405,73 @@ func (l *kvLedger) CommitWithPvtData(pvtdataAndBlock *ledger.BlockAndPvtData) er
 	return nil
 }
 
+// commitAsReplica commits the block validated by the committer to the block
+// store and the history database, the committer applying it to the shared
+// state database
+func (l *kvLedger) commitAsReplica(pvtdataAndBlock *ledger.BlockAndPvtData) error {
+	block := pvtdataAndBlock.Block
+	if err := setReplicaTxFilter(block); err != nil {
+		return err
+	}
+
+	startCommitBlockStorage := time.Now()
+	logger.Debugf("[%s] Committing block [%d] to storage", l.ledgerID, block.Header.Number)
+	l.blockAPIsRWLock.Lock()
+	defer l.blockAPIsRWLock.Unlock()
+	if err := l.blockStore.CommitWithPvtData(pvtdataAndBlock); err != nil {
+		return err
+	}
+	l.blockCommitDuration.RecordDuration(time.Since(startCommitBlockStorage))
+	l.valueHasher.committed(block)
+	l.commitHistory(block)
+
+	logger.Infof("[%s] Committed block [%d] with %d transaction(s) validated by the committer in %dms",
+		l.ledgerID, block.Header.Number, len(block.Data.Data), time.Since(startCommitBlockStorage)/time.Millisecond)
+	return nil
+}
+
+// commitHistory commits the block to the history database, or schedules its
+// commit when the history database is committed asynchronously
+func (l *kvLedger) commitHistory(block *common.Block) {
+	if !ledgerconfig.IsHistoryDBEnabled() {
+		return
+	}
+	if l.historyCommitter != nil {
+		l.historyCommitter.enqueue(block)
+		return
+	}
+	logger.Debugf("[%s] Committing block [%d] transactions to history database", l.ledgerID, block.Header.Number)
+	startCommitHistory := time.Now()
+	if err := l.historyDB.Commit(block); err != nil {
+		panic(errors.WithMessage(err, "Error during commit to history db"))
+	}
+	l.historyCommitDuration.RecordDuration(time.Since(startCommitHistory))
+}
+
+// setReplicaTxFilter ensures that the block carries the validation codes set
+// by the committer. The genesis block, with which the replica joins the
+// channel, has no validation codes and its transactions are valid.
+func setReplicaTxFilter(block *common.Block) error {
+	txsFilter := lgrutil.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	switch {
+	case len(txsFilter) == len(block.Data.Data):
+		return nil
+	case len(txsFilter) == 0 && block.Header.Number == 0:
+		block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = lgrutil.NewTxValidationFlagsSetValue(len(block.Data.Data), peer.TxValidationCode_VALID)
+		return nil
+	}
+	return errors.Errorf("block [%d] was not validated by the committer, it has %d validation codes for %d transactions",
+		block.Header.Number, len(txsFilter), len(block.Data.Data))
+}
+
+func (l *kvLedger) height() (uint64, error) {
+	info, err := l.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return 0, err
+	}
+	return info.Height, nil
+}
+
 // GetMissingPvtDataInfoForMostRecentBlocks returns the missing private data information for the
 // most recent `maxBlock` blocks which miss at least a private data of a eligible collection.
 func (l *kvLedger) GetMissingPvtDataInfoForMostRecentBlocks(maxBlock int) (ledger.MissingPvtDataInfo, error) {
@@ -346,8 +520,92 @@ func (l *kvLedger) GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker, err
 	return l, nil
 }
 
+// ListStateIndexes implements method in interface `ledger.StateIndexManager`
+func (l *kvLedger) ListStateIndexes(chaincodeName, collection string) ([]*ledger.StateIndex, error) {
+	indexManager, err := l.stateIndexManager()
+	if err != nil {
+		return nil, err
+	}
+	return indexManager.ListStateIndexes(chaincodeName, collection)
+}
+
+// CreateStateIndex implements method in interface `ledger.StateIndexManager`
+func (l *kvLedger) CreateStateIndex(chaincodeName, collection string, definition []byte) error {
+	indexManager, err := l.stateIndexManager()
+	if err != nil {
+		return err
+	}
+	return indexManager.CreateStateIndex(chaincodeName, collection, definition)
+}
+
+// RebuildStateIndexes implements method in interface `ledger.StateIndexManager`
+func (l *kvLedger) RebuildStateIndexes(chaincodeName, collection string) error {
+	indexManager, err := l.stateIndexManager()
+	if err != nil {
+		return err
+	}
+	return indexManager.RebuildStateIndexes(chaincodeName, collection)
+}
+
+// StateDBSize implements method in interface `ledger.StateDBSizer`
+func (l *kvLedger) StateDBSize() (int64, error) {
+	sizer, ok := l.txtmgmt.(ledger.StateDBSizer)
+	if !ok {
+		return 0, errors.Errorf("the state database of ledger [%s] does not report its size", l.ledgerID)
+	}
+	return sizer.StateDBSize()
+}
+
+// FindReadConflict implements method in interface `ledger.ReadSetChecker`
+func (l *kvLedger) FindReadConflict(txRWSetBytes []byte) (*ledger.ReadConflict, error) {
+	checker, ok := l.txtmgmt.(ledger.ReadSetChecker)
+	if !ok {
+		return nil, errors.Errorf("the transaction manager of ledger [%s] does not support checking read sets", l.ledgerID)
+	}
+	return checker.FindReadConflict(txRWSetBytes)
+}
+
+// GetIndexLag implements method in interface `ledger.IndexLagReporter`
+func (l *kvLedger) GetIndexLag() (*ledger.IndexLag, error) {
+	// the indexes are read before the height, which they can't exceed
+	var historyDBHeight, blockIndexHeight uint64
+	if l.historyCommitter != nil {
+		historyDBHeight = l.historyCommitter.committedHeight()
+	}
+	reporter, reportsIndex := l.blockStore.BlockStore.(blkstorage.IndexedHeightReporter)
+	if reportsIndex {
+		var err error
+		if blockIndexHeight, err = reporter.GetIndexedHeight(); err != nil {
+			return nil, err
+		}
+	}
+	height, err := l.height()
+	if err != nil {
+		return nil, err
+	}
+	if l.historyCommitter == nil {
+		historyDBHeight = height
+	}
+	if !reportsIndex {
+		blockIndexHeight = height
+	}
+	return &ledger.IndexLag{Height: height, BlockIndexHeight: blockIndexHeight, HistoryDBHeight: historyDBHeight}, nil
+}
+
+func (l *kvLedger) stateIndexManager() (ledger.StateIndexManager, error) {
+	indexManager, ok := l.txtmgmt.(ledger.StateIndexManager)
+	if !ok {
+		return nil, errors.Errorf("the state database of ledger [%s] does not support indexes", l.ledgerID)
+	}
+	return indexManager, nil
+}
+
 // Close closes `KVLedger`
 func (l *kvLedger) Close() {
+	l.snapshotMgr.close()
+	if l.historyCommitter != nil {
+		l.historyCommitter.close()
+	}
 	l.blockStore.Shutdown()
 	l.txtmgmt.Shutdown()
 }
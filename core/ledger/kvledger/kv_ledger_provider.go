@@ -7,8 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package kvledger
 
 import (
-	"bytes"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
@@ -18,6 +20,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb/historyleveldb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/ledgerstorage"
 	"github.com/hyperledger/fabric/protos/common"
@@ -35,7 +38,9 @@ var (
 	ErrLedgerNotOpened = errors.New("ledger is not opened yet")
 
 	underConstructionLedgerKey = []byte("underConstructionLedgerKey")
+	mixedStateDatabasesKey     = []byte("mixedStateDatabasesKey")
 	ledgerKeyPrefix            = []byte("l")
+	ledgerKeyStop              = []byte("m")
 )
 
 // Provider implements interface ledger.PeerLedgerProvider
@@ -56,6 +61,10 @@ func NewProvider() (ledger.PeerLedgerProvider, error) {
 	logger.Info("Initializing ledger provider")
 	// Initialize the ID store (inventory of chainIds/ledgerIds)
 	idStore := openIDStore(ledgerconfig.GetLedgerProviderPath())
+	if err := idStore.checkMixedStateDatabases(ledgerconfig.IsMixedStateDatabasesEnabled()); err != nil {
+		idStore.close()
+		return nil, err
+	}
 	ledgerStoreProvider := ledgerstorage.NewProvider()
 	bookkeepingProvider := bookkeeping.NewProvider()
 	// Initialize the versioned database (state database)
@@ -116,6 +125,94 @@ func (provider *Provider) Create(genesisBlock *common.Block) (ledger.PeerLedger,
 	return lgr, nil
 }
 
+// CreateFromSnapshot implements the corresponding method from interface ledger.SnapshotImporter
+// The archive is extracted in a temporary directory under the snapshots root directory and the
+// last block and the state checkpoint of the snapshot are checked by the verifier. The state database is loaded from the
+// state of the snapshot and the block store is bootstrapped with the last block and the last
+// config block of the snapshot, the blocks above the height of the snapshot being then committed
+// as for any ledger. The transactions below the height are not known to the ledger, the snapshot
+// must hence be taken from a trusted peer of the channel using the same type of state database.
+// As for Create, the under construction flag is set until the ledger is created, the block store
+// being bootstrapped last so that a crash leaves it empty.
+func (provider *Provider) CreateFromSnapshot(snapshotArchive io.Reader, verifier ledger.SnapshotVerifier) (ledger.PeerLedger, error) {
+	snapshotDir, err := extractSnapshot(snapshotArchive)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(snapshotDir)
+	snapshot, err := readSnapshot(snapshotDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifier(snapshot.lastBlock, snapshot.configBlock, snapshot.checkpoint, snapshot.stateHash); err != nil {
+		return nil, errors.WithMessage(err, "error verifying the snapshot")
+	}
+	ledgerID := snapshot.metadata.ChannelID
+	exists, err := provider.idStore.ledgerIDExists(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrLedgerIDExists
+	}
+	if err = provider.idStore.setUnderConstructionFlag(ledgerID); err != nil {
+		return nil, err
+	}
+	if err := provider.importSnapshot(ledgerID, snapshot); err != nil {
+		logger.Errorf("Error importing snapshot of ledger [%s] at height [%d]. Unsetting under construction flag. Error: %+v", ledgerID, snapshot.metadata.Height, err)
+		panicOnErr(provider.runCleanup(ledgerID), "Error running cleanup for ledger id [%s]", ledgerID)
+		panicOnErr(provider.idStore.unsetUnderConstructionFlag(), "Error while unsetting under construction flag")
+		return nil, err
+	}
+	lgr, err := provider.openInternal(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	panicOnErr(provider.idStore.createLedgerID(ledgerID, snapshot.configBlock), "Error while marking ledger as created")
+	logger.Infof("Created ledger [%s] at height [%d] from snapshot with %d keys", ledgerID, snapshot.metadata.Height, snapshot.metadata.StateKeys)
+	return lgr, nil
+}
+
+// importSnapshot loads the state of the snapshot and sets the save points of the state and history
+// databases to the last block of the snapshot, then bootstraps the block store
+func (provider *Provider) importSnapshot(ledgerID string, snapshot *importedSnapshot) error {
+	vDB, err := provider.vdbProvider.GetDBHandle(ledgerID)
+	if err != nil {
+		return err
+	}
+	importer, ok := vDB.(privacyenabledstate.ImportCapable)
+	if !ok {
+		return errors.New("the state database does not support the import of snapshots")
+	}
+	itr, err := newStateReader(snapshot.statePath, snapshot.metadata.StateKeys)
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+	lastBlock := snapshot.lastBlock
+	savepoint := version.NewHeight(lastBlock.Header.Number, uint64(len(lastBlock.Data.Data)-1))
+	if err := importer.ImportState(itr, savepoint); err != nil {
+		return errors.WithMessage(err, "error importing the state of the snapshot")
+	}
+
+	if ledgerconfig.IsHistoryDBEnabled() {
+		historyDB, err := provider.historydbProvider.GetDBHandle(ledgerID)
+		if err != nil {
+			return err
+		}
+		if err := historyDB.Commit(lastBlock); err != nil {
+			return errors.WithMessage(err, "error setting the save point of the history database")
+		}
+	}
+
+	blockStore, err := provider.ledgerStoreProvider.Open(ledgerID)
+	if err != nil {
+		return err
+	}
+	defer blockStore.Shutdown()
+	return errors.WithMessage(blockStore.Bootstrap(lastBlock, snapshot.configBlock), "error bootstrapping the block store")
+}
+
 // Open implements the corresponding method from interface ledger.PeerLedgerProvider
 func (provider *Provider) Open(ledgerID string) (ledger.PeerLedger, error) {
 	logger.Debugf("Open() opening kvledger: %s", ledgerID)
@@ -152,7 +249,8 @@ func (provider *Provider) openInternal(ledgerID string) (ledger.PeerLedger, erro
 	// Create a kvLedger for this chain/ledger, which encasulates the underlying data stores
 	// (id store, blockstore, state database, history database)
 	l, err := newKVLedger(ledgerID, blockStore, vDB, historyDB, provider.configHistoryMgr,
-		provider.stateListeners, provider.bookkeepingProvider, provider.initializer.DeployedChaincodeInfoProvider)
+		provider.stateListeners, provider.bookkeepingProvider, provider.initializer.DeployedChaincodeInfoProvider,
+		provider.initializer.Metrics, provider.initializer.SnapshotSigner)
 	if err != nil {
 		return nil, err
 	}
@@ -209,6 +307,14 @@ func (provider *Provider) recoverUnderConstructionLedger() {
 		panicOnErr(err, "Error while retrieving genesis block from blockchain for ledger [%s]", ledgerID)
 		panicOnErr(provider.idStore.createLedgerID(ledgerID, genesisBlock), "Error while adding ledgerID [%s] to created list", ledgerID)
 	default:
+		// a ledger created from a snapshot is complete once its block store is bootstrapped
+		configBlock, err := ledger.(*kvLedger).blockStore.GetBootstrapConfigBlock()
+		panicOnErr(err, "Error while retrieving bootstrap config block for ledger [%s]", ledgerID)
+		if configBlock != nil {
+			logger.Infof("Block store was bootstrapped from a snapshot. Hence, marking the peer ledger as created")
+			panicOnErr(provider.idStore.createLedgerID(ledgerID, configBlock), "Error while adding ledgerID [%s] to created list", ledgerID)
+			return
+		}
 		panic(errors.Errorf(
 			"data inconsistency: under construction flag is set for ledger [%s] while the height of the blockchain is [%d]",
 			ledgerID, bcInfo.Height))
@@ -264,6 +370,40 @@ func (s *idStore) getUnderConstructionFlag() (string, error) {
 	return string(val), nil
 }
 
+// checkMixedStateDatabases checks that the mixed state databases are enabled,
+// or not, as when the existing ledgers were created, the private data of their
+// collections being stored in the state database their config requests only
+// when they are. The setting is recorded while the peer has no ledger, and
+// when none was recorded, i.e. by the ledgers of the peers of prior versions.
+func (s *idStore) checkMixedStateDatabases(enabled bool) error {
+	recorded, err := s.db.Get(mixedStateDatabasesKey)
+	if err != nil {
+		return err
+	}
+	hasLedgers, err := s.hasLedgers()
+	if err != nil {
+		return err
+	}
+	if recorded != nil && hasLedgers {
+		wasEnabled, err := strconv.ParseBool(string(recorded))
+		if err != nil {
+			return errors.Wrapf(err, "invalid mixed state databases setting [%s]", recorded)
+		}
+		if wasEnabled != enabled {
+			return errors.Errorf("ledger.state.mixedStateDatabases cannot be changed from %t to %t once the peer has ledgers", wasEnabled, enabled)
+		}
+		return nil
+	}
+	return s.db.Put(mixedStateDatabasesKey, []byte(strconv.FormatBool(enabled)), true)
+}
+
+func (s *idStore) hasLedgers() (bool, error) {
+	itr := s.db.GetIterator(ledgerKeyPrefix, ledgerKeyStop)
+	defer itr.Release()
+	hasLedgers := itr.First()
+	return hasLedgers, itr.Error()
+}
+
 func (s *idStore) createLedgerID(ledgerID string, gb *common.Block) error {
 	key := s.encodeLedgerKey(ledgerID)
 	var val []byte
@@ -295,13 +435,10 @@ func (s *idStore) ledgerIDExists(ledgerID string) (bool, error) {
 
 func (s *idStore) getAllLedgerIds() ([]string, error) {
 	var ids []string
-	itr := s.db.GetIterator(nil, nil)
+	itr := s.db.GetIterator(ledgerKeyPrefix, ledgerKeyStop)
 	defer itr.Release()
 	itr.First()
 	for itr.Valid() {
-		if bytes.Equal(itr.Key(), underConstructionLedgerKey) {
-			continue
-		}
 		id := string(s.decodeLedgerID(itr.Key()))
 		ids = append(ids, id)
 		itr.Next()
@@ -26,6 +26,7 @@ import (
 	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	mockcrypto "github.com/hyperledger/fabric/common/mocks/crypto"
 	"github.com/hyperledger/fabric/common/util"
 	lgr "github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
@@ -136,6 +137,31 @@ func TestRecovery(t *testing.T) {
 
 }
 
+func TestMixedStateDatabasesSetting(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	s := openIDStore(ledgerconfig.GetLedgerProviderPath())
+	defer s.close()
+
+	// the setting can be changed while the peer has no ledger
+	assert.NoError(t, s.checkMixedStateDatabases(true))
+	assert.NoError(t, s.checkMixedStateDatabases(false))
+
+	genesisBlock, _ := configtxtest.MakeGenesisBlock(constructTestLedgerID(1))
+	assert.NoError(t, s.createLedgerID(constructTestLedgerID(1), genesisBlock))
+	assert.NoError(t, s.checkMixedStateDatabases(false))
+	assert.EqualError(t, s.checkMixedStateDatabases(true), "ledger.state.mixedStateDatabases cannot be changed from false to true once the peer has ledgers")
+
+	// the ledgers created before the setting was recorded adopt the current one
+	assert.NoError(t, s.db.Delete(mixedStateDatabasesKey, true))
+	assert.NoError(t, s.checkMixedStateDatabases(true))
+	assert.EqualError(t, s.checkMixedStateDatabases(false), "ledger.state.mixedStateDatabases cannot be changed from true to false once the peer has ledgers")
+
+	ledgerIDs, err := s.getAllLedgerIds()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{constructTestLedgerID(1)}, ledgerIDs)
+}
+
 func TestMultipleLedgerBasicRW(t *testing.T) {
 	env := newTestEnv(t)
 	defer env.cleanup()
@@ -308,6 +334,7 @@ func testutilNewProvider(t *testing.T) lgr.PeerLedgerProvider {
 	assert.NoError(t, err)
 	provider.Initialize(&lgr.Initializer{
 		DeployedChaincodeInfoProvider: &mock.DeployedChaincodeInfoProvider{},
+		SnapshotSigner:                mockcrypto.FakeLocalSigner,
 	})
 	return provider
 }
@@ -123,6 +123,57 @@ func TestKVLedgerBlockStorage(t *testing.T) {
 	assert.Equal(t, peer.TxValidationCode_VALID, validCode)
 }
 
+func TestKVLedgerAsyncIndexes(t *testing.T) {
+	viper.Set("ledger.blockchain.asyncIndexing", true)
+	viper.Set("ledger.history.asyncCommit", true)
+	defer viper.Set("ledger.blockchain.asyncIndexing", false)
+	defer viper.Set("ledger.history.asyncCommit", false)
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider := testutilNewProvider(t)
+	defer provider.Close()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, err := provider.Create(gb)
+	assert.NoError(t, err)
+	defer ledger.Close()
+
+	var blocks []*common.Block
+	for i := 0; i < 5; i++ {
+		simulator, _ := ledger.NewTxSimulator(util.GenerateUUID())
+		simulator.SetState("ns1", "key1", []byte{byte(i)})
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		pubSimBytes, _ := simRes.GetPubSimulationBytes()
+		block := bg.NextBlock([][]byte{pubSimBytes})
+		assert.NoError(t, ledger.CommitWithPvtData(&lgr.BlockAndPvtData{Block: block}))
+		blocks = append(blocks, block)
+	}
+
+	// the lookups and the history queries wait for the blocks committed
+	b, err := ledger.GetBlockByHash(blocks[4].Header.Hash())
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(blocks[4], b), "proto messages are not equal")
+	qhistory, err := ledger.NewHistoryQueryExecutor()
+	assert.NoError(t, err)
+	itr, err := qhistory.GetHistoryForKey("ns1", "key1")
+	assert.NoError(t, err)
+	defer itr.Close()
+	count := 0
+	for {
+		kmod, _ := itr.Next()
+		if kmod == nil {
+			break
+		}
+		count++
+	}
+	assert.Equal(t, 5, count)
+
+	lag, err := ledger.(lgr.IndexLagReporter).GetIndexLag()
+	assert.NoError(t, err)
+	assert.Equal(t, &lgr.IndexLag{Height: 6, BlockIndexHeight: 6, HistoryDBHeight: 6}, lag)
+}
+
 func TestKVLedgerBlockStorageWithPvtdata(t *testing.T) {
 	t.Skip()
 	env := newTestEnv(t)
@@ -0,0 +1,122 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/pkg/errors"
+)
+
+// replicaPollInterval is the interval between two reads of the commit marker
+// of the shared state database, while waiting for it to be consistent
+const replicaPollInterval = 20 * time.Millisecond
+
+// replica coordinates a read replica with the committer maintaining the state
+// database it shares. The committer marks the start and the completion of the
+// commit of each block in the shared state database, so that the simulations
+// of the replica neither start during a commit nor read the state of a block
+// its ledger lags too far behind, and fail if a commit started meanwhile.
+type replica struct {
+	ledgerID string
+	markers  statedb.ReplicationCapable
+	// height returns the height of the ledger of the replica
+	height  func() (uint64, error)
+	maxLag  uint64
+	timeout time.Duration
+}
+
+func newReplica(ledgerID string, markers statedb.ReplicationCapable, height func() (uint64, error)) *replica {
+	return &replica{
+		ledgerID: ledgerID,
+		markers:  markers,
+		height:   height,
+		maxLag:   ledgerconfig.GetReplicationMaxLag(),
+		timeout:  ledgerconfig.GetReplicationConsistencyTimeout(),
+	}
+}
+
+// waitConsistent waits until no commit of the shared state database is in
+// progress, and the state database holds all the blocks of the ledger of the
+// replica while being at most maxLag blocks ahead of it. It returns the commit
+// marker the reads from then on are consistent with.
+func (r *replica) waitConsistent() (*statedb.CommitMarker, error) {
+	deadline := time.Now().Add(r.timeout)
+	for {
+		marker, inconsistency, err := r.check()
+		if err != nil {
+			return nil, err
+		}
+		if inconsistency == "" {
+			return marker, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.Errorf("[%s] the ledger and the shared state database are not consistent after %s: %s", r.ledgerID, r.timeout, inconsistency)
+		}
+		time.Sleep(replicaPollInterval)
+	}
+}
+
+// check returns the commit marker of the shared state database, along with
+// the reason why the replica can't read it yet, if any
+func (r *replica) check() (*statedb.CommitMarker, string, error) {
+	marker, err := r.markers.GetCommitMarker()
+	if err != nil {
+		return nil, "", errors.WithMessage(err, "failed reading the commit marker of the shared state database")
+	}
+	if marker == nil {
+		return nil, "the committer did not publish any commit", nil
+	}
+	if marker.Committing {
+		return nil, fmt.Sprintf("the committer is committing block [%d]", marker.Height.BlockNum), nil
+	}
+	height, err := r.height()
+	if err != nil {
+		return nil, "", err
+	}
+	stateHeight := marker.Height.BlockNum + 1
+	switch {
+	case height > stateHeight:
+		return nil, fmt.Sprintf("the state database is at height %d, behind the ledger at height %d", stateHeight, height), nil
+	case stateHeight-height > r.maxLag:
+		return nil, fmt.Sprintf("the ledger is at height %d, more than %d blocks behind the state database at height %d", height, r.maxLag, stateHeight), nil
+	}
+	return marker, "", nil
+}
+
+// checkUnchanged returns an error if a commit of the shared state database
+// started since the given commit marker was read
+func (r *replica) checkUnchanged(marker *statedb.CommitMarker) error {
+	current, err := r.markers.GetCommitMarker()
+	if err != nil {
+		return errors.WithMessage(err, "failed reading the commit marker of the shared state database")
+	}
+	if current == nil || current.Committing || current.Height.Compare(marker.Height) != 0 {
+		return errors.Errorf("[%s] the shared state database was updated during the simulation, its reads may not be consistent", r.ledgerID)
+	}
+	return nil
+}
+
+// replicaTxSimulator fails the simulations overlapping a commit of the shared
+// state database, whose reads may mix the states of several blocks
+type replicaTxSimulator struct {
+	ledger.TxSimulator
+	replica *replica
+	marker  *statedb.CommitMarker
+}
+
+// GetTxSimulationResults implements method in interface `ledger.TxSimulator`
+func (s *replicaTxSimulator) GetTxSimulationResults() (*ledger.TxSimulationResults, error) {
+	if err := s.replica.checkUnchanged(s.marker); err != nil {
+		return nil, err
+	}
+	return s.TxSimulator.GetTxSimulationResults()
+}
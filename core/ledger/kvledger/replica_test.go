@@ -0,0 +1,136 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/util"
+	lgr "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// commitMarkers returns its markers one after the other, and then the last one
+type commitMarkers struct {
+	markers []*statedb.CommitMarker
+	err     error
+}
+
+func (m *commitMarkers) GetCommitMarker() (*statedb.CommitMarker, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	marker := m.markers[0]
+	if len(m.markers) > 1 {
+		m.markers = m.markers[1:]
+	}
+	return marker, nil
+}
+
+func committed(blockNum uint64) *statedb.CommitMarker {
+	return &statedb.CommitMarker{Height: version.NewHeight(blockNum, 0)}
+}
+
+func committing(blockNum uint64) *statedb.CommitMarker {
+	return &statedb.CommitMarker{Height: version.NewHeight(blockNum, 0), Committing: true}
+}
+
+func heightOf(height uint64) func() (uint64, error) {
+	return func() (uint64, error) { return height, nil }
+}
+
+func TestReplicaWaitConsistent(t *testing.T) {
+	r := &replica{
+		ledgerID: "ledger1",
+		markers:  &commitMarkers{markers: []*statedb.CommitMarker{nil, committing(5), committed(5)}},
+		height:   heightOf(6),
+		maxLag:   1,
+		timeout:  time.Second,
+	}
+	marker, err := r.waitConsistent()
+	assert.NoError(t, err)
+	assert.Equal(t, committed(5), marker)
+
+	// the ledger may lag behind the state database by up to maxLag blocks
+	r.height = heightOf(5)
+	_, err = r.waitConsistent()
+	assert.NoError(t, err)
+
+	r.timeout = 50 * time.Millisecond
+	r.height = heightOf(4)
+	_, err = r.waitConsistent()
+	assert.EqualError(t, err, "[ledger1] the ledger and the shared state database are not consistent after 50ms: "+
+		"the ledger is at height 4, more than 1 blocks behind the state database at height 6")
+
+	r.height = heightOf(7)
+	_, err = r.waitConsistent()
+	assert.EqualError(t, err, "[ledger1] the ledger and the shared state database are not consistent after 50ms: "+
+		"the state database is at height 6, behind the ledger at height 7")
+
+	r.markers = &commitMarkers{markers: []*statedb.CommitMarker{committing(6)}}
+	_, err = r.waitConsistent()
+	assert.EqualError(t, err, "[ledger1] the ledger and the shared state database are not consistent after 50ms: "+
+		"the committer is committing block [6]")
+
+	r.markers = &commitMarkers{err: errors.New("connection refused")}
+	_, err = r.waitConsistent()
+	assert.EqualError(t, err, "failed reading the commit marker of the shared state database: connection refused")
+}
+
+func TestReplicaLedger(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider := testutilNewProvider(t)
+	defer provider.Close()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, err := provider.Create(gb)
+	assert.NoError(t, err)
+	defer ledger.Close()
+
+	simulator, _ := ledger.NewTxSimulator(util.GenerateUUID())
+	simulator.SetState("ns1", "key1", []byte("value1"))
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	pubSimBytes, _ := simRes.GetPubSimulationBytes()
+
+	// from now on, the ledger reads the state database maintained by a committer
+	markers := &commitMarkers{markers: []*statedb.CommitMarker{committed(1)}}
+	ledger.(*kvLedger).replica = &replica{ledgerID: "testLedger", markers: markers, height: ledger.(*kvLedger).height, timeout: time.Second}
+
+	// the block is not applied to the state database
+	block1 := bg.NextBlock([][]byte{pubSimBytes})
+	assert.NoError(t, ledger.CommitWithPvtData(&lgr.BlockAndPvtData{Block: block1}))
+	bcInfo, _ := ledger.GetBlockchainInfo()
+	assert.Equal(t, uint64(2), bcInfo.Height)
+	qe, err := ledger.NewQueryExecutor()
+	assert.NoError(t, err)
+	value, err := qe.GetState("ns1", "key1")
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+	qe.Done()
+
+	// the simulations overlapping a commit of the committer fail
+	simulator, err = ledger.NewTxSimulator(util.GenerateUUID())
+	assert.NoError(t, err)
+	markers.markers = []*statedb.CommitMarker{committing(2)}
+	_, err = simulator.GetTxSimulationResults()
+	assert.EqualError(t, err, "[testLedger] the shared state database was updated during the simulation, its reads may not be consistent")
+	simulator.Done()
+
+	// the blocks must be validated by the committer
+	block2 := bg.NextBlock([][]byte{pubSimBytes})
+	block2.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = nil
+	assert.EqualError(t, ledger.CommitWithPvtData(&lgr.BlockAndPvtData{Block: block2}),
+		"block [2] was not validated by the committer, it has 0 validation codes for 1 transactions")
+}
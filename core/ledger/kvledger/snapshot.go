@@ -0,0 +1,672 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/checkpoint"
+	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// The snapshots of the state of a channel are stored in the directory of the
+// channel under ledgerconfig.GetSnapshotsRootDir(), in a directory per
+// snapshot named after the height of the ledger at the time of the snapshot:
+//
+//   <height>/state.data      the public state and the hashes of the private data
+//   <height>/last.block      the block below the height, a marshaled common.Block
+//   <height>/config.block    the last config block of the channel, a marshaled common.Block
+//   <height>/metadata.json   the snapshotMetadata
+//   <height>/checkpoint.data the state checkpoint, a marshaled common.Metadata
+//
+// state.data holds a record per key, made of the namespace, the key, the value,
+// the metadata and the version of the key, each prefixed by its varint length.
+// A snapshot is generated in a temporary directory renamed once complete, and
+// is shipped to a joining peer as a gzip compressed tar archive of its files.
+// The state checkpoint is signed by the peer generating the snapshot, and
+// commits to the hash of state.data as of the last block, so that the joining
+// peer trusts the state as it trusts the last block. It is missing if the peer
+// has no signer.
+const (
+	snapshotStateFile       = "state.data"
+	snapshotLastBlockFile   = "last.block"
+	snapshotConfigBlockFile = "config.block"
+	snapshotMetadataFile    = "metadata.json"
+	snapshotCheckpointFile  = "checkpoint.data"
+	snapshotTempSuffix      = ".tmp"
+)
+
+// snapshotMetadata describes a snapshot of the state of a channel
+type snapshotMetadata struct {
+	ChannelID string `json:"channel_id"`
+	// Height is the height of the ledger whose state was snapshotted
+	Height            uint64    `json:"height"`
+	LastBlockHash     string    `json:"last_block_hash"`
+	PreviousBlockHash string    `json:"previous_block_hash"`
+	ConfigBlockNumber uint64    `json:"config_block_number"`
+	StateKeys         uint64    `json:"state_keys"`
+	StateHash         string    `json:"state_hash"`
+	Timestamp         time.Time `json:"timestamp"`
+}
+
+// stateSnapshotter is implemented by the transaction managers whose state
+// database can be iterated over from a snapshot
+type stateSnapshotter interface {
+	NewStateSnapshotIterator() (statedb.ResultsIterator, *version.Height, error)
+}
+
+// snapshotMgr generates the snapshots of the state of a channel in the
+// background, every policy.Interval blocks, and removes the older ones beyond
+// the policy.Retain most recent ones. A snapshot due while the previous one is
+// still being generated is skipped.
+type snapshotMgr struct {
+	ledgerID string
+	dir      string
+	policy   ledgerconfig.SnapshotPolicy
+	signer   crypto.LocalSigner
+
+	mutex      sync.Mutex
+	generating bool
+	closed     bool
+	done       chan struct{}
+	wg         sync.WaitGroup
+}
+
+func newSnapshotMgr(ledgerID string, signer crypto.LocalSigner) *snapshotMgr {
+	m := &snapshotMgr{
+		ledgerID: ledgerID,
+		dir:      filepath.Join(ledgerconfig.GetSnapshotsRootDir(), ledgerID),
+		policy:   ledgerconfig.GetSnapshotPolicy(ledgerID),
+		signer:   signer,
+		done:     make(chan struct{}),
+	}
+	m.removeIncomplete()
+	return m
+}
+
+// due returns whether the state is snapshotted once the block is committed
+func (m *snapshotMgr) due(blockNum uint64) bool {
+	return m.policy.Interval != 0 && (blockNum+1)%m.policy.Interval == 0
+}
+
+// generate writes the snapshot of the state iterated over in the background,
+// closing the iterator once done
+func (m *snapshotMgr) generate(metadata *snapshotMetadata, lastBlock, configBlock *common.Block, itr statedb.ResultsIterator) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.closed {
+		itr.Close()
+		return
+	}
+	if m.generating {
+		logger.Warningf("[%s] Skipping the snapshot of the state at height [%d], the previous snapshot is still being generated", m.ledgerID, metadata.Height)
+		itr.Close()
+		return
+	}
+	m.generating = true
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer itr.Close()
+		start := time.Now()
+		if err := m.write(metadata, lastBlock, configBlock, itr); err != nil {
+			logger.Errorf("[%s] Failed to generate the snapshot of the state at height [%d]: %s", m.ledgerID, metadata.Height, err)
+		} else {
+			logger.Infof("[%s] Generated the snapshot of the state at height [%d] with %d keys in %dms",
+				m.ledgerID, metadata.Height, metadata.StateKeys, time.Since(start)/time.Millisecond)
+			m.prune()
+		}
+		m.mutex.Lock()
+		m.generating = false
+		m.mutex.Unlock()
+	}()
+}
+
+func (m *snapshotMgr) write(metadata *snapshotMetadata, lastBlock, configBlock *common.Block, itr statedb.ResultsIterator) error {
+	dir := m.snapshotDir(metadata.Height)
+	tempDir := dir + snapshotTempSuffix
+	if err := os.RemoveAll(tempDir); err != nil {
+		return errors.Wrapf(err, "error removing %s", tempDir)
+	}
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		return errors.Wrapf(err, "error creating %s", tempDir)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := m.writeState(filepath.Join(tempDir, snapshotStateFile), metadata, itr); err != nil {
+		return err
+	}
+	for file, block := range map[string]*common.Block{snapshotLastBlockFile: lastBlock, snapshotConfigBlockFile: configBlock} {
+		blockBytes, err := proto.Marshal(block)
+		if err != nil {
+			return errors.Wrapf(err, "error marshaling block [%d]", block.Header.Number)
+		}
+		if err := writeSnapshotFile(filepath.Join(tempDir, file), blockBytes); err != nil {
+			return err
+		}
+	}
+	metadataBytes, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "error marshaling the snapshot metadata")
+	}
+	if err := writeSnapshotFile(filepath.Join(tempDir, snapshotMetadataFile), metadataBytes); err != nil {
+		return err
+	}
+	if err := m.writeCheckpoint(filepath.Join(tempDir, snapshotCheckpointFile), metadata, lastBlock); err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return errors.Wrapf(err, "error removing %s", dir)
+	}
+	return errors.Wrapf(os.Rename(tempDir, dir), "error renaming %s", tempDir)
+}
+
+// writeState writes the records of the keys iterated over, and sets the number
+// of keys and the hash of the file in the metadata
+func (m *snapshotMgr) writeState(path string, metadata *snapshotMetadata, itr statedb.ResultsIterator) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "error creating %s", path)
+	}
+	defer f.Close()
+	hash := sha256.New()
+	w := bufio.NewWriter(io.MultiWriter(f, hash))
+	buf := proto.NewBuffer(nil)
+	for {
+		select {
+		case <-m.done:
+			return errors.New("the ledger was closed")
+		default:
+		}
+		result, err := itr.Next()
+		if err != nil {
+			return errors.WithMessage(err, "error iterating over the state")
+		}
+		if result == nil {
+			break
+		}
+		kv := result.(*statedb.VersionedKV)
+		buf.Reset()
+		buf.EncodeStringBytes(kv.Namespace)
+		buf.EncodeStringBytes(kv.Key)
+		buf.EncodeRawBytes(kv.Value)
+		buf.EncodeRawBytes(kv.Metadata)
+		buf.EncodeRawBytes(kv.Version.ToBytes())
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return errors.Wrapf(err, "error writing %s", path)
+		}
+		metadata.StateKeys++
+	}
+	if err := w.Flush(); err != nil {
+		return errors.Wrapf(err, "error writing %s", path)
+	}
+	if err := f.Sync(); err != nil {
+		return errors.Wrapf(err, "error syncing %s", path)
+	}
+	metadata.StateHash = hex.EncodeToString(hash.Sum(nil))
+	return nil
+}
+
+// writeCheckpoint writes the state checkpoint signed by the peer, if it has a
+// signer
+func (m *snapshotMgr) writeCheckpoint(path string, metadata *snapshotMetadata, lastBlock *common.Block) error {
+	if m.signer == nil {
+		return nil
+	}
+	stateHash, err := hex.DecodeString(metadata.StateHash)
+	if err != nil {
+		return errors.Wrap(err, "error decoding the state hash")
+	}
+	stateCheckpoint, err := checkpoint.SignState(lastBlock, stateHash, m.signer)
+	if err != nil {
+		return errors.WithMessage(err, "error signing the state checkpoint")
+	}
+	checkpointBytes, err := proto.Marshal(stateCheckpoint)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling the state checkpoint")
+	}
+	return writeSnapshotFile(path, checkpointBytes)
+}
+
+func writeSnapshotFile(path string, content []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "error creating %s", path)
+	}
+	defer f.Close()
+	if _, err := f.Write(content); err != nil {
+		return errors.Wrapf(err, "error writing %s", path)
+	}
+	return errors.Wrapf(f.Sync(), "error syncing %s", path)
+}
+
+// prune removes the snapshots beyond the most recent ones to retain
+func (m *snapshotMgr) prune() {
+	heights, err := m.heights()
+	if err != nil {
+		logger.Errorf("[%s] Failed to list the snapshots of the state: %s", m.ledgerID, err)
+		return
+	}
+	for len(heights) > m.policy.Retain {
+		dir := m.snapshotDir(heights[0])
+		if err := os.RemoveAll(dir); err != nil {
+			logger.Errorf("[%s] Failed to remove the snapshot %s: %s", m.ledgerID, dir, err)
+			return
+		}
+		logger.Infof("[%s] Removed the snapshot of the state at height [%d]", m.ledgerID, heights[0])
+		heights = heights[1:]
+	}
+}
+
+// removeIncomplete removes the snapshots whose generation was interrupted by
+// the stop of the peer
+func (m *snapshotMgr) removeIncomplete() {
+	entries, err := ioutil.ReadDir(m.dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasSuffix(entry.Name(), snapshotTempSuffix) {
+			os.RemoveAll(filepath.Join(m.dir, entry.Name()))
+		}
+	}
+}
+
+// heights returns the heights of the snapshots, in increasing order
+func (m *snapshotMgr) heights() ([]uint64, error) {
+	entries, err := ioutil.ReadDir(m.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading %s", m.dir)
+	}
+	var heights []uint64
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		height, err := strconv.ParseUint(entry.Name(), 10, 64)
+		if err != nil {
+			continue
+		}
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	return heights, nil
+}
+
+func (m *snapshotMgr) snapshotDir(height uint64) string {
+	return filepath.Join(m.dir, fmt.Sprintf("%d", height))
+}
+
+// list returns the snapshots of the state, by increasing height
+func (m *snapshotMgr) list() ([]*ledger.SnapshotInfo, error) {
+	heights, err := m.heights()
+	if err != nil {
+		return nil, err
+	}
+	snapshots := []*ledger.SnapshotInfo{}
+	for _, height := range heights {
+		dir := m.snapshotDir(height)
+		metadata, err := readSnapshotMetadata(dir)
+		if err != nil {
+			// the snapshot may be removed meanwhile
+			logger.Debugf("[%s] Skipping snapshot %s: %s", m.ledgerID, dir, err)
+			continue
+		}
+		lastBlockHash, err := hex.DecodeString(metadata.LastBlockHash)
+		if err != nil {
+			logger.Debugf("[%s] Skipping snapshot %s: invalid last block hash: %s", m.ledgerID, dir, err)
+			continue
+		}
+		snapshots = append(snapshots, &ledger.SnapshotInfo{
+			Height:        metadata.Height,
+			Path:          dir,
+			LastBlockHash: lastBlockHash,
+			Timestamp:     metadata.Timestamp,
+		})
+	}
+	return snapshots, nil
+}
+
+// close interrupts the generation of the snapshot in progress, if any
+func (m *snapshotMgr) close() {
+	m.mutex.Lock()
+	if !m.closed {
+		m.closed = true
+		close(m.done)
+	}
+	m.mutex.Unlock()
+	m.wg.Wait()
+}
+
+func readSnapshotMetadata(dir string) (*snapshotMetadata, error) {
+	path := filepath.Join(dir, snapshotMetadataFile)
+	metadataBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading %s", path)
+	}
+	metadata := &snapshotMetadata{}
+	if err := json.Unmarshal(metadataBytes, metadata); err != nil {
+		return nil, errors.Wrapf(err, "error unmarshaling %s", path)
+	}
+	return metadata, nil
+}
+
+// importedSnapshot is a snapshot of the state of a channel from which a ledger
+// is created
+type importedSnapshot struct {
+	metadata    *snapshotMetadata
+	lastBlock   *common.Block
+	configBlock *common.Block
+	checkpoint  *common.Metadata
+	stateHash   []byte
+	statePath   string
+}
+
+// extractSnapshot extracts the files of a snapshot from a gzip compressed tar
+// archive, in a temporary directory under the snapshots root directory whose
+// name cannot be the one of a channel, and returns the directory
+func extractSnapshot(archive io.Reader) (string, error) {
+	rootDir := ledgerconfig.GetSnapshotsRootDir()
+	if err := os.MkdirAll(rootDir, 0755); err != nil {
+		return "", errors.Wrapf(err, "error creating %s", rootDir)
+	}
+	dir, err := ioutil.TempDir(rootDir, "_import")
+	if err != nil {
+		return "", errors.Wrapf(err, "error creating a directory under %s", rootDir)
+	}
+	if err := extractSnapshotFiles(archive, dir); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
+
+func extractSnapshotFiles(archive io.Reader, dir string) error {
+	gz, err := gzip.NewReader(archive)
+	if err != nil {
+		return errors.Wrap(err, "error reading the snapshot archive")
+	}
+	defer gz.Close()
+	files := map[string]bool{
+		snapshotStateFile:       true,
+		snapshotLastBlockFile:   true,
+		snapshotConfigBlockFile: true,
+		snapshotMetadataFile:    true,
+		snapshotCheckpointFile:  true,
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "error reading the snapshot archive")
+		}
+		name := path.Clean(hdr.Name)
+		if hdr.Typeflag == tar.TypeDir && name == "." {
+			continue
+		}
+		if hdr.Typeflag != tar.TypeReg || !files[name] {
+			return errors.Errorf("unexpected entry %s in the snapshot archive", hdr.Name)
+		}
+		// a file appearing twice is rejected when created
+		if err := copySnapshotFile(filepath.Join(dir, name), tr); err != nil {
+			return err
+		}
+	}
+}
+
+func copySnapshotFile(path string, r io.Reader) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "error creating %s", path)
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, r); err != nil {
+		return errors.Wrapf(err, "error writing %s", path)
+	}
+	return nil
+}
+
+// readSnapshot reads the snapshot in the directory, checking that its blocks
+// and its state match its metadata
+func readSnapshot(dir string) (*importedSnapshot, error) {
+	metadata, err := readSnapshotMetadata(dir)
+	if err != nil {
+		return nil, err
+	}
+	lastBlock, err := readSnapshotBlock(filepath.Join(dir, snapshotLastBlockFile))
+	if err != nil {
+		return nil, err
+	}
+	configBlock, err := readSnapshotBlock(filepath.Join(dir, snapshotConfigBlockFile))
+	if err != nil {
+		return nil, err
+	}
+
+	if metadata.Height == 0 || lastBlock.Header.Number+1 != metadata.Height {
+		return nil, errors.Errorf("the last block [%d] of the snapshot does not match its height [%d]", lastBlock.Header.Number, metadata.Height)
+	}
+	if hex.EncodeToString(lastBlock.Header.Hash()) != metadata.LastBlockHash {
+		return nil, errors.Errorf("the hash of the last block [%d] of the snapshot does not match its metadata", lastBlock.Header.Number)
+	}
+	configBlockNum, err := utils.GetLastConfigIndexFromBlock(lastBlock)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error reading the last config index of the last block of the snapshot")
+	}
+	if configBlockNum != metadata.ConfigBlockNumber || configBlock.Header.Number != metadata.ConfigBlockNumber {
+		return nil, errors.Errorf("the config block [%d] of the snapshot does not match its metadata", configBlock.Header.Number)
+	}
+	channelID, err := utils.GetChainIDFromBlock(configBlock)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error reading the channel of the config block of the snapshot")
+	}
+	if channelID != metadata.ChannelID {
+		return nil, errors.Errorf("the config block of the snapshot is of channel [%s], its metadata of channel [%s]", channelID, metadata.ChannelID)
+	}
+
+	statePath := filepath.Join(dir, snapshotStateFile)
+	f, err := os.Open(statePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error opening %s", statePath)
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return nil, errors.Wrapf(err, "error reading %s", statePath)
+	}
+	stateHash := hash.Sum(nil)
+	if hex.EncodeToString(stateHash) != metadata.StateHash {
+		return nil, errors.Errorf("the hash of %s does not match the snapshot metadata", statePath)
+	}
+	stateCheckpoint, err := readSnapshotCheckpoint(filepath.Join(dir, snapshotCheckpointFile))
+	if err != nil {
+		return nil, err
+	}
+	return &importedSnapshot{metadata, lastBlock, configBlock, stateCheckpoint, stateHash, statePath}, nil
+}
+
+// readSnapshotCheckpoint reads the state checkpoint of the snapshot, which is
+// nil if the snapshot has none
+func readSnapshotCheckpoint(path string) (*common.Metadata, error) {
+	checkpointBytes, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading %s", path)
+	}
+	stateCheckpoint := &common.Metadata{}
+	if err := proto.Unmarshal(checkpointBytes, stateCheckpoint); err != nil {
+		return nil, errors.Wrapf(err, "error unmarshaling %s", path)
+	}
+	return stateCheckpoint, nil
+}
+
+func readSnapshotBlock(path string) (*common.Block, error) {
+	blockBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading %s", path)
+	}
+	block := &common.Block{}
+	if err := proto.Unmarshal(blockBytes, block); err != nil {
+		return nil, errors.Wrapf(err, "error unmarshaling %s", path)
+	}
+	if block.Header == nil || block.Data == nil || block.Metadata == nil {
+		return nil, errors.Errorf("%s is not a complete block", path)
+	}
+	return block, nil
+}
+
+// stateReader iterates over the records of the state of a snapshot, checking
+// that it holds the number of keys of the snapshot
+type stateReader struct {
+	path     string
+	f        *os.File
+	r        *bufio.Reader
+	keys     uint64
+	expected uint64
+}
+
+func newStateReader(path string, expected uint64) (*stateReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error opening %s", path)
+	}
+	return &stateReader{path: path, f: f, r: bufio.NewReader(f), expected: expected}, nil
+}
+
+// Next implements method in interface statedb.ResultsIterator
+func (r *stateReader) Next() (statedb.QueryResult, error) {
+	var fields [5][]byte
+	for i := range fields {
+		field, err := r.readField()
+		if err == io.EOF && i == 0 {
+			if r.keys != r.expected {
+				return nil, errors.Errorf("%s holds %d keys, the snapshot metadata %d", r.path, r.keys, r.expected)
+			}
+			return nil, nil
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading %s", r.path)
+		}
+		fields[i] = field
+	}
+	r.keys++
+	ver, _ := version.NewHeightFromBytes(fields[4])
+	metadata := fields[3]
+	if len(metadata) == 0 {
+		metadata = nil
+	}
+	return &statedb.VersionedKV{
+		CompositeKey:   statedb.CompositeKey{Namespace: string(fields[0]), Key: string(fields[1])},
+		VersionedValue: statedb.VersionedValue{Value: fields[2], Metadata: metadata, Version: ver},
+	}, nil
+}
+
+// readField reads a field prefixed by its varint length, io.EOF being returned
+// only if the reader is at the end of the file
+func (r *stateReader) readField() ([]byte, error) {
+	length, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return nil, err
+	}
+	field := make([]byte, length)
+	if _, err := io.ReadFull(r.r, field); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return field, nil
+}
+
+// Close implements method in interface statedb.ResultsIterator
+func (r *stateReader) Close() {
+	r.f.Close()
+}
+
+// snapshotState starts the generation of the snapshot of the state as of the
+// commit of the block
+func (l *kvLedger) snapshotState(block *common.Block) {
+	blockNum := block.Header.Number
+	snapshotter, ok := l.txtmgmt.(stateSnapshotter)
+	if !ok {
+		logger.Errorf("[%s] Failed to snapshot the state at height [%d]: the transaction manager does not support snapshots", l.ledgerID, blockNum+1)
+		return
+	}
+	itr, savepoint, err := snapshotter.NewStateSnapshotIterator()
+	if err != nil {
+		logger.Errorf("[%s] Failed to snapshot the state at height [%d]: %s", l.ledgerID, blockNum+1, err)
+		return
+	}
+	if savepoint == nil || savepoint.BlockNum != blockNum {
+		logger.Errorf("[%s] Failed to snapshot the state at height [%d]: the save point of the state database is %v", l.ledgerID, blockNum+1, savepoint)
+		itr.Close()
+		return
+	}
+	configBlockNum, err := utils.GetLastConfigIndexFromBlock(block)
+	if err != nil {
+		logger.Errorf("[%s] Failed to snapshot the state at height [%d]: %s", l.ledgerID, blockNum+1, err)
+		itr.Close()
+		return
+	}
+	configBlock := block
+	if configBlockNum != blockNum {
+		if configBlock, err = l.blockStore.RetrieveBlockByNumber(configBlockNum); err != nil {
+			logger.Errorf("[%s] Failed to snapshot the state at height [%d]: error retrieving config block [%d]: %s", l.ledgerID, blockNum+1, configBlockNum, err)
+			itr.Close()
+			return
+		}
+	}
+	l.snapshotMgr.generate(&snapshotMetadata{
+		ChannelID:         l.ledgerID,
+		Height:            blockNum + 1,
+		LastBlockHash:     hex.EncodeToString(block.Header.Hash()),
+		PreviousBlockHash: hex.EncodeToString(block.Header.PreviousHash),
+		ConfigBlockNumber: configBlockNum,
+		Timestamp:         time.Now().UTC(),
+	}, block, configBlock, itr)
+}
+
+// ListSnapshots implements method in interface `ledger.SnapshotLister`
+func (l *kvLedger) ListSnapshots() ([]*ledger.SnapshotInfo, error) {
+	return l.snapshotMgr.list()
+}
@@ -0,0 +1,464 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/checkpoint"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
+	"github.com/hyperledger/fabric/common/util"
+	lgr "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	ledgertestutil "github.com/hyperledger/fabric/core/ledger/testutil"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshots(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	viper.Set("ledger.snapshots.interval", 2)
+	viper.Set("ledger.snapshots.retain", 2)
+	provider := testutilNewProvider(t)
+	defer provider.Close()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, err := provider.Create(gb)
+	require.NoError(t, err)
+	defer ledger.Close()
+	snapshotMgr := ledger.(*kvLedger).snapshotMgr
+
+	var blocks []*common.Block
+	for i := 1; i <= 5; i++ {
+		simulator, _ := ledger.NewTxSimulator(util.GenerateUUID())
+		simulator.SetState("ns1", "key1", []byte{byte(i)})
+		simulator.SetState("ns2", fmt.Sprintf("key%d", i), []byte{byte(i)})
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		pubSimBytes, _ := simRes.GetPubSimulationBytes()
+		block := bg.NextBlock([][]byte{pubSimBytes})
+		require.NoError(t, ledger.CommitWithPvtData(&lgr.BlockAndPvtData{Block: block}))
+		blocks = append(blocks, block)
+		// the snapshots are generated in the background
+		snapshotMgr.wg.Wait()
+	}
+
+	// the snapshot at height 2 was removed once the one at height 6 was generated
+	snapshots, err := ledger.(lgr.SnapshotLister).ListSnapshots()
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, uint64(4), snapshots[0].Height)
+	assert.Equal(t, uint64(6), snapshots[1].Height)
+	assert.Equal(t, blocks[4].Header.Hash(), snapshots[1].LastBlockHash)
+	assert.Equal(t, filepath.Join(env.path, "snapshots", "testLedger", "6"), snapshots[1].Path)
+	assert.False(t, snapshots[1].Timestamp.IsZero())
+
+	metadata, err := readSnapshotMetadata(snapshots[0].Path)
+	require.NoError(t, err)
+	assert.Equal(t, "testLedger", metadata.ChannelID)
+	assert.Equal(t, hex.EncodeToString(blocks[2].Header.PreviousHash), metadata.PreviousBlockHash)
+	assert.Equal(t, uint64(0), metadata.ConfigBlockNumber)
+	assert.Equal(t, uint64(4), metadata.StateKeys)
+
+	configBlockBytes, err := ioutil.ReadFile(filepath.Join(snapshots[0].Path, snapshotConfigBlockFile))
+	require.NoError(t, err)
+	configBlock := &common.Block{}
+	require.NoError(t, proto.Unmarshal(configBlockBytes, configBlock))
+	assert.True(t, proto.Equal(gb, configBlock))
+	lastBlockBytes, err := ioutil.ReadFile(filepath.Join(snapshots[0].Path, snapshotLastBlockFile))
+	require.NoError(t, err)
+	lastBlock := &common.Block{}
+	require.NoError(t, proto.Unmarshal(lastBlockBytes, lastBlock))
+	assert.True(t, proto.Equal(blocks[2], lastBlock))
+
+	// the state is as of the commit of block 3
+	stateBytes, err := ioutil.ReadFile(filepath.Join(snapshots[0].Path, snapshotStateFile))
+	require.NoError(t, err)
+	stateHash := sha256.Sum256(stateBytes)
+	assert.Equal(t, hex.EncodeToString(stateHash[:]), metadata.StateHash)
+	buf := proto.NewBuffer(stateBytes)
+	type record struct {
+		ns, key, value string
+		version        *version.Height
+	}
+	var records []record
+	for i := uint64(0); i < metadata.StateKeys; i++ {
+		ns, err := buf.DecodeStringBytes()
+		require.NoError(t, err)
+		key, err := buf.DecodeStringBytes()
+		require.NoError(t, err)
+		value, err := buf.DecodeRawBytes(true)
+		require.NoError(t, err)
+		_, err = buf.DecodeRawBytes(false)
+		require.NoError(t, err)
+		versionBytes, err := buf.DecodeRawBytes(false)
+		require.NoError(t, err)
+		ver, _ := version.NewHeightFromBytes(versionBytes)
+		records = append(records, record{ns, key, string(value), ver})
+	}
+	assert.Equal(t, []record{
+		{"ns1", "key1", "\x03", version.NewHeight(3, 0)},
+		{"ns2", "key1", "\x01", version.NewHeight(1, 0)},
+		{"ns2", "key2", "\x02", version.NewHeight(2, 0)},
+		{"ns2", "key3", "\x03", version.NewHeight(3, 0)},
+	}, records)
+}
+
+func TestSnapshotsIncomplete(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	viper.Set("ledger.snapshots.interval", 1)
+
+	// the snapshots interrupted by the stop of the peer are removed
+	incomplete := filepath.Join(env.path, "snapshots", "testLedger", "3"+snapshotTempSuffix)
+	require.NoError(t, os.MkdirAll(incomplete, 0755))
+	snapshotMgr := newSnapshotMgr("testLedger", nil)
+	_, err := os.Stat(incomplete)
+	assert.True(t, os.IsNotExist(err))
+	snapshots, err := snapshotMgr.list()
+	assert.NoError(t, err)
+	assert.Empty(t, snapshots)
+
+	// a snapshot due while one is being generated is skipped
+	snapshotMgr.generating = true
+	itr := &closeRecorder{}
+	snapshotMgr.generate(&snapshotMetadata{Height: 5}, &common.Block{}, &common.Block{}, itr)
+	assert.True(t, itr.closed)
+	snapshotMgr.close()
+}
+
+// closeRecorder is an empty iterator recording whether it was closed
+type closeRecorder struct {
+	closed bool
+}
+
+func (itr *closeRecorder) Next() (statedb.QueryResult, error) {
+	return nil, nil
+}
+
+func (itr *closeRecorder) Close() {
+	itr.closed = true
+}
+
+func TestCreateFromSnapshot(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	viper.Set("ledger.snapshots.interval", 2)
+	viper.Set("ledger.history.enableHistoryDatabase", true)
+	provider := testutilNewProvider(t)
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, err := provider.Create(gb)
+	require.NoError(t, err)
+	var blocks []*common.Block
+	for i := 1; i <= 4; i++ {
+		simulator, _ := ledger.NewTxSimulator(util.GenerateUUID())
+		simulator.SetState("ns1", "key1", []byte{byte(i)})
+		simulator.SetState("ns2", fmt.Sprintf("key%d", i), []byte{byte(i)})
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		pubSimBytes, _ := simRes.GetPubSimulationBytes()
+		block := bg.NextBlock([][]byte{pubSimBytes})
+		require.NoError(t, ledger.CommitWithPvtData(&lgr.BlockAndPvtData{Block: block}))
+		blocks = append(blocks, block)
+		ledger.(*kvLedger).snapshotMgr.wg.Wait()
+	}
+	snapshots, err := ledger.(lgr.SnapshotLister).ListSnapshots()
+	require.NoError(t, err)
+	snapshotDir := snapshots[1].Path
+	require.Equal(t, uint64(4), snapshots[1].Height)
+	ledger.Close()
+	provider.Close()
+
+	archive := archiveSnapshot(t, snapshotDir, nil)
+
+	// the ledger is created on another peer at the height of the snapshot,
+	// once the last block is verified
+	otherEnv := newTestEnv(t)
+	defer otherEnv.cleanup()
+	provider = testutilNewProvider(t)
+	_, err = provider.(lgr.SnapshotImporter).CreateFromSnapshot(bytes.NewReader(archive), func(lastBlock, configBlock *common.Block, stateCheckpoint *common.Metadata, stateHash []byte) error {
+		return errors.New("not signed by the ordering service")
+	})
+	assert.EqualError(t, err, "error verifying the snapshot: not signed by the ordering service")
+	exists, err := provider.Exists("testLedger")
+	require.NoError(t, err)
+	assert.False(t, exists)
+	entries, err := ioutil.ReadDir(ledgerconfig.GetSnapshotsRootDir())
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	var verified []uint64
+	verifier := func(lastBlock, configBlock *common.Block, stateCheckpoint *common.Metadata, stateHash []byte) error {
+		verified = append(verified, lastBlock.Header.Number, configBlock.Header.Number)
+		// the state checkpoint signed by the peer commits to the state
+		_, err := checkpoint.VerifyState(lastBlock, stateCheckpoint, stateHash, &mockpolicies.Policy{})
+		return err
+	}
+	ledger, err = provider.(lgr.SnapshotImporter).CreateFromSnapshot(bytes.NewReader(archive), verifier)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{3, 0}, verified)
+	_, err = provider.(lgr.SnapshotImporter).CreateFromSnapshot(bytes.NewReader(archive), verifier)
+	assert.Equal(t, ErrLedgerIDExists, err)
+
+	bcInfo, err := ledger.GetBlockchainInfo()
+	require.NoError(t, err)
+	assert.Equal(t, &common.BlockchainInfo{
+		Height:            4,
+		CurrentBlockHash:  blocks[2].Header.Hash(),
+		PreviousBlockHash: blocks[2].Header.PreviousHash,
+	}, bcInfo)
+	block, err := ledger.GetBlockByNumber(3)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(blocks[2], block))
+	block, err = ledger.GetBlockByNumber(0)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(gb, block))
+	_, err = ledger.GetBlockByNumber(1)
+	assert.Error(t, err)
+
+	qe, err := ledger.NewQueryExecutor()
+	require.NoError(t, err)
+	value, err := qe.GetState("ns1", "key1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{3}, value)
+	value, err = qe.GetState("ns2", "key3")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{3}, value)
+	qe.Done()
+
+	// the blocks above the height are committed as for any ledger
+	require.NoError(t, ledger.CommitWithPvtData(&lgr.BlockAndPvtData{Block: blocks[3]}))
+	qe, err = ledger.NewQueryExecutor()
+	require.NoError(t, err)
+	value, err = qe.GetState("ns1", "key1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{4}, value)
+	qe.Done()
+	hqe, err := ledger.NewHistoryQueryExecutor()
+	require.NoError(t, err)
+	itr, err := hqe.GetHistoryForKey("ns1", "key1")
+	require.NoError(t, err)
+	var values [][]byte
+	for {
+		result, err := itr.Next()
+		require.NoError(t, err)
+		if result == nil {
+			break
+		}
+		values = append(values, result.(*queryresult.KeyModification).Value)
+	}
+	itr.Close()
+	assert.ElementsMatch(t, [][]byte{{3}, {4}}, values)
+	ledger.Close()
+	provider.Close()
+
+	// the ledger is created once its creation is complete
+	provider = testutilNewProvider(t)
+	defer provider.Close()
+	ledgerIDs, err := provider.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"testLedger"}, ledgerIDs)
+	ledger, err = provider.Open("testLedger")
+	require.NoError(t, err)
+	bcInfo, err = ledger.GetBlockchainInfo()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), bcInfo.Height)
+	ledger.Close()
+}
+
+func TestCreateFromSnapshotRecovery(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	viper.Set("ledger.snapshots.interval", 2)
+	provider := testutilNewProvider(t)
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, err := provider.Create(gb)
+	require.NoError(t, err)
+	require.NoError(t, ledger.CommitWithPvtData(&lgr.BlockAndPvtData{Block: bg.NextBlock([][]byte{})}))
+	ledger.(*kvLedger).snapshotMgr.wg.Wait()
+	snapshots, err := ledger.(lgr.SnapshotLister).ListSnapshots()
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	ledger.Close()
+	provider.Close()
+
+	// the peer stops between the bootstrap of the block store and the end of
+	// the creation of the ledger
+	otherEnv := newTestEnv(t)
+	defer otherEnv.cleanup()
+	provider = testutilNewProvider(t)
+	snapshot, err := readSnapshot(snapshots[0].Path)
+	require.NoError(t, err)
+	kvProvider := provider.(*Provider)
+	require.NoError(t, kvProvider.idStore.setUnderConstructionFlag("testLedger"))
+	require.NoError(t, kvProvider.importSnapshot("testLedger", snapshot))
+	provider.Close()
+
+	provider = testutilNewProvider(t)
+	defer provider.Close()
+	exists, err := provider.Exists("testLedger")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	flag, err := provider.(*Provider).idStore.getUnderConstructionFlag()
+	require.NoError(t, err)
+	assert.Empty(t, flag)
+}
+
+func TestReadInvalidSnapshot(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	viper.Set("ledger.snapshots.interval", 2)
+	provider := testutilNewProvider(t)
+	defer provider.Close()
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, err := provider.Create(gb)
+	require.NoError(t, err)
+	defer ledger.Close()
+	simulator, _ := ledger.NewTxSimulator(util.GenerateUUID())
+	simulator.SetState("ns1", "key1", []byte("value1"))
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	pubSimBytes, _ := simRes.GetPubSimulationBytes()
+	require.NoError(t, ledger.CommitWithPvtData(&lgr.BlockAndPvtData{Block: bg.NextBlock([][]byte{pubSimBytes})}))
+	ledger.(*kvLedger).snapshotMgr.wg.Wait()
+	snapshots, err := ledger.(lgr.SnapshotLister).ListSnapshots()
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	dir := snapshots[0].Path
+	snapshot, err := readSnapshot(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "testLedger", snapshot.metadata.ChannelID)
+
+	// the records of the state are checked against the metadata
+	statePath := filepath.Join(dir, snapshotStateFile)
+	stateBytes, err := ioutil.ReadFile(statePath)
+	require.NoError(t, err)
+	itr, err := newStateReader(statePath, snapshot.metadata.StateKeys+1)
+	require.NoError(t, err)
+	for i := uint64(0); i < snapshot.metadata.StateKeys; i++ {
+		result, err := itr.Next()
+		require.NoError(t, err)
+		require.NotNil(t, result)
+	}
+	_, err = itr.Next()
+	assert.Contains(t, err.Error(), fmt.Sprintf("holds %d keys, the snapshot metadata %d", snapshot.metadata.StateKeys, snapshot.metadata.StateKeys+1))
+	itr.Close()
+	require.NoError(t, ioutil.WriteFile(statePath, stateBytes[:len(stateBytes)-1], 0644))
+	itr, err = newStateReader(statePath, snapshot.metadata.StateKeys)
+	require.NoError(t, err)
+	for err == nil {
+		_, err = itr.Next()
+	}
+	assert.Contains(t, err.Error(), "unexpected EOF")
+	itr.Close()
+
+	// the files of the snapshot are checked against the metadata
+	_, err = readSnapshot(dir)
+	assert.EqualError(t, err, fmt.Sprintf("the hash of %s does not match the snapshot metadata", statePath))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, snapshotLastBlockFile), utils.MarshalOrPanic(&common.Block{
+		Header:   &common.BlockHeader{Number: 5},
+		Data:     &common.BlockData{},
+		Metadata: &common.BlockMetadata{},
+	}), 0644))
+	_, err = readSnapshot(dir)
+	assert.EqualError(t, err, "the last block [5] of the snapshot does not match its height [2]")
+}
+
+func TestExtractSnapshot(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	viper.Set("ledger.snapshots.interval", 2)
+	provider := testutilNewProvider(t)
+	defer provider.Close()
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, err := provider.Create(gb)
+	require.NoError(t, err)
+	defer ledger.Close()
+	require.NoError(t, ledger.CommitWithPvtData(&lgr.BlockAndPvtData{Block: bg.NextBlock([][]byte{})}))
+	ledger.(*kvLedger).snapshotMgr.wg.Wait()
+	snapshots, err := ledger.(lgr.SnapshotLister).ListSnapshots()
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+
+	dir, err := extractSnapshot(bytes.NewReader(archiveSnapshot(t, snapshots[0].Path, nil)))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(filepath.Base(dir), "_import"))
+	for _, file := range []string{snapshotStateFile, snapshotLastBlockFile, snapshotConfigBlockFile, snapshotMetadataFile, snapshotCheckpointFile} {
+		expected, err := ioutil.ReadFile(filepath.Join(snapshots[0].Path, file))
+		require.NoError(t, err)
+		actual, err := ioutil.ReadFile(filepath.Join(dir, file))
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	}
+	os.RemoveAll(dir)
+
+	// the entries other than the files of a snapshot are rejected
+	_, err = extractSnapshot(bytes.NewReader(archiveSnapshot(t, snapshots[0].Path, map[string][]byte{"../evil": []byte("evil")})))
+	assert.EqualError(t, err, "unexpected entry ../evil in the snapshot archive")
+	_, err = extractSnapshot(bytes.NewReader(archiveSnapshot(t, snapshots[0].Path, map[string][]byte{snapshotMetadataFile: []byte("{}")})))
+	assert.Contains(t, err.Error(), "error creating")
+	_, err = extractSnapshot(bytes.NewReader([]byte("not an archive")))
+	assert.Contains(t, err.Error(), "error reading the snapshot archive")
+
+	// the directories of the rejected archives are removed
+	entries, err := ioutil.ReadDir(ledgerconfig.GetSnapshotsRootDir())
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "testLedger", entries[0].Name())
+}
+
+// archiveSnapshot returns the gzip compressed tar archive of the files of the
+// snapshot in the directory, followed by the extra files
+func archiveSnapshot(t *testing.T, dir string, extra map[string][]byte) []byte {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	addFile := func(name string, content []byte) {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "./", Mode: 0755, Typeflag: tar.TypeDir}))
+	for _, file := range []string{snapshotStateFile, snapshotLastBlockFile, snapshotConfigBlockFile, snapshotMetadataFile, snapshotCheckpointFile} {
+		content, err := ioutil.ReadFile(filepath.Join(dir, file))
+		require.NoError(t, err)
+		addFile("./"+file, content)
+	}
+	for name, content := range extra {
+		addFile(name, content)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
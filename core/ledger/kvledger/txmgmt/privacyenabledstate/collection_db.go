@@ -0,0 +1,102 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privacyenabledstate
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+// collectionDBCache caches whether the private data namespaces are stored in
+// the secondary db of the mixed state databases. The state database of a
+// collection cannot be modified once the collection is defined, so only the
+// namespaces of the undefined collections are looked up again.
+type collectionDBCache struct {
+	lock        sync.RWMutex
+	inSecondary map[string]bool
+}
+
+func newCollectionDBCache() *collectionDBCache {
+	return &collectionDBCache{inSecondary: make(map[string]bool)}
+}
+
+func (c *collectionDBCache) get(pvtDataNs string) (inSecondary bool, ok bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	inSecondary, ok = c.inSecondary[pvtDataNs]
+	return inSecondary, ok
+}
+
+func (c *collectionDBCache) put(pvtDataNs string, inSecondary bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.inSecondary[pvtDataNs] = inSecondary
+}
+
+// storesInSecondary returns whether the private data of the collection is
+// stored in the secondary db
+func (s *CommonStorageDB) storesInSecondary(collectionConfig *common.StaticCollectionConfig) bool {
+	return s.secondary != nil && collectionConfig.GetStateDatabase() == s.secondaryDBName
+}
+
+// isInSecondary returns whether the private data of the collection is stored
+// in the secondary db, looking up the committed config of the collection
+func (s *CommonStorageDB) isInSecondary(namespace, collection string) (bool, error) {
+	if s.secondary == nil {
+		return false, nil
+	}
+	pvtDataNs := derivePvtDataNs(namespace, collection)
+	if inSecondary, ok := s.collectionDBCache.get(pvtDataNs); ok {
+		return inSecondary, nil
+	}
+	collectionConfigMap, err := s.getCollectionConfigMap(&cceventmgmt.ChaincodeDefinition{Name: namespace})
+	if err != nil {
+		return false, err
+	}
+	collectionConfig, ok := collectionConfigMap[collection]
+	if !ok {
+		// an undefined collection has no private data
+		return false, nil
+	}
+	inSecondary := s.storesInSecondary(collectionConfig)
+	s.collectionDBCache.put(pvtDataNs, inSecondary)
+	return inSecondary, nil
+}
+
+// isInSecondaryAfterUpdates returns whether the private data of the collection is
+// stored in the secondary db, the collection config possibly being committed by
+// the public updates of the same block
+func (s *CommonStorageDB) isInSecondaryAfterUpdates(namespace, collection string, pubUpdates *PubUpdateBatch) (bool, error) {
+	if s.secondary == nil {
+		return false, nil
+	}
+	vv := pubUpdates.Get(lsccNamespace, privdata.BuildCollectionKVSKey(namespace))
+	if vv == nil || vv.Value == nil {
+		return s.isInSecondary(namespace, collection)
+	}
+	collectionConfigMap, err := s.getCollectionConfigMap(&cceventmgmt.ChaincodeDefinition{Name: namespace, CollectionConfigs: vv.Value})
+	if err != nil {
+		return false, err
+	}
+	return s.storesInSecondary(collectionConfigMap[collection]), nil
+}
+
+// pvtDataDB returns the state database storing the private data of the collection
+func (s *CommonStorageDB) pvtDataDB(namespace, collection string) (statedb.VersionedDB, error) {
+	inSecondary, err := s.isInSecondary(namespace, collection)
+	if err != nil {
+		return nil, err
+	}
+	if inSecondary {
+		return s.secondary, nil
+	}
+	return s.VersionedDB, nil
+}
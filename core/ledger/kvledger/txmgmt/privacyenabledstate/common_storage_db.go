@@ -8,12 +8,15 @@ package privacyenabledstate
 
 import (
 	"encoding/base64"
+	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
@@ -31,12 +34,22 @@ const (
 	nsJoiner       = "$$"
 	pvtDataPrefix  = "p"
 	hashDataPrefix = "h"
+
+	// importBatchSize is the number of keys applied at once by ImportState
+	importBatchSize = 1000
+
+	// lsccNamespace is the namespace of the chaincode definitions and their collection configs
+	lsccNamespace = "lscc"
 )
 
 // CommonStorageDBProvider implements interface DBProvider
 type CommonStorageDBProvider struct {
 	statedb.VersionedDBProvider
 	bookkeepingProvider bookkeeping.Provider
+	// secondaryProvider provides the state databases other than the configured
+	// one when the mixed state databases are enabled, nil otherwise
+	secondaryProvider statedb.VersionedDBProvider
+	secondaryDBName   string
 }
 
 // NewCommonStorageDBProvider constructs an instance of DBProvider
@@ -50,7 +63,22 @@ func NewCommonStorageDBProvider(bookkeeperProvider bookkeeping.Provider) (DBProv
 	} else {
 		vdbProvider = stateleveldb.NewVersionedDBProvider()
 	}
-	return &CommonStorageDBProvider{vdbProvider, bookkeeperProvider}, nil
+	provider := &CommonStorageDBProvider{VersionedDBProvider: vdbProvider, bookkeepingProvider: bookkeeperProvider}
+	if !ledgerconfig.IsMixedStateDatabasesEnabled() {
+		return provider, nil
+	}
+	if ledgerconfig.IsCouchDBEnabled() {
+		provider.secondaryProvider = stateleveldb.NewVersionedDBProvider()
+		provider.secondaryDBName = ledgerconfig.StateDatabaseLevelDB
+	} else {
+		if provider.secondaryProvider, err = statecouchdb.NewVersionedDBProvider(); err != nil {
+			vdbProvider.Close()
+			return nil, err
+		}
+		provider.secondaryDBName = ledgerconfig.StateDatabaseCouchDB
+	}
+	logger.Infof("The private data of the collections requesting the %s state database are stored in a %s database", provider.secondaryDBName, provider.secondaryDBName)
+	return provider, nil
 }
 
 // GetDBHandle implements function from interface DBProvider
@@ -61,25 +89,85 @@ func (p *CommonStorageDBProvider) GetDBHandle(id string) (DB, error) {
 	}
 	bookkeeper := p.bookkeepingProvider.GetDBHandle(id, bookkeeping.MetadataPresenceIndicator)
 	metadataHint := newMetadataHint(bookkeeper)
-	return NewCommonStorageDB(vdb, id, metadataHint)
+	if p.secondaryProvider == nil {
+		return NewCommonStorageDB(vdb, id, metadataHint)
+	}
+	secondary, err := p.secondaryProvider.GetDBHandle(id)
+	if err != nil {
+		return nil, err
+	}
+	return newMixedCommonStorageDB(vdb, secondary, p.secondaryDBName, metadataHint), nil
 }
 
 // Close implements function from interface DBProvider
 func (p *CommonStorageDBProvider) Close() {
 	p.VersionedDBProvider.Close()
+	if p.secondaryProvider != nil {
+		p.secondaryProvider.Close()
+	}
 }
 
 // CommonStorageDB implements interface DB. This implementation uses a single database to maintain
-// both the public and private data
+// both the public and private data, except for the private data of the collections requesting the
+// secondary db of the mixed state databases
 type CommonStorageDB struct {
 	statedb.VersionedDB
 	metadataHint *metadataHint
+	// secondary stores the private data of the collections requesting the
+	// state database named secondaryDBName, nil unless the mixed state
+	// databases are enabled
+	secondary         statedb.VersionedDB
+	secondaryDBName   string
+	collectionDBCache *collectionDBCache
 }
 
 // NewCommonStorageDB wraps a VersionedDB instance. The public data is managed directly by the wrapped versionedDB.
 // For managing the hashed data and private data, this implementation creates separate namespaces in the wrapped db
 func NewCommonStorageDB(vdb statedb.VersionedDB, ledgerid string, metadataHint *metadataHint) (DB, error) {
-	return &CommonStorageDB{vdb, metadataHint}, nil
+	return &CommonStorageDB{VersionedDB: vdb, metadataHint: metadataHint}, nil
+}
+
+// newMixedCommonStorageDB wraps a VersionedDB instance like NewCommonStorageDB, the private data of the
+// collections whose config requests the state database named secondaryDBName being stored in the secondary db
+func newMixedCommonStorageDB(vdb, secondary statedb.VersionedDB, secondaryDBName string, metadataHint *metadataHint) *CommonStorageDB {
+	return &CommonStorageDB{
+		VersionedDB:       vdb,
+		metadataHint:      metadataHint,
+		secondary:         secondary,
+		secondaryDBName:   secondaryDBName,
+		collectionDBCache: newCollectionDBCache(),
+	}
+}
+
+// Open implements function from interface statedb.VersionedDB
+func (s *CommonStorageDB) Open() error {
+	if err := s.VersionedDB.Open(); err != nil {
+		return err
+	}
+	if s.secondary != nil {
+		return s.secondary.Open()
+	}
+	return nil
+}
+
+// Close implements function from interface statedb.VersionedDB
+func (s *CommonStorageDB) Close() {
+	s.VersionedDB.Close()
+	if s.secondary != nil {
+		s.secondary.Close()
+	}
+}
+
+// ValidateKeyValue implements function from interface statedb.VersionedDB. With the mixed state databases,
+// the keys and values have to be valid for both databases, as the private data may be stored in the secondary db
+func (s *CommonStorageDB) ValidateKeyValue(key string, value []byte) error {
+	if err := s.VersionedDB.ValidateKeyValue(key, value); err != nil {
+		return err
+	}
+	if s.secondary != nil {
+		return s.secondary.ValidateKeyValue(key, value)
+	}
+	return nil
 }
 
 // IsBulkOptimizable implements corresponding function in interface DB
@@ -88,6 +176,48 @@ func (s *CommonStorageDB) IsBulkOptimizable() bool {
 	return ok
 }
 
+// IsSnapshotCapable implements corresponding function in interface DB
+func (s *CommonStorageDB) IsSnapshotCapable() bool {
+	_, ok := s.VersionedDB.(statedb.SnapshotCapable)
+	if s.secondary != nil {
+		_, secondaryOK := s.secondary.(statedb.SnapshotCapable)
+		ok = ok && secondaryOK
+	}
+	return ok
+}
+
+// GetSnapshot implements corresponding function in interface DB
+func (s *CommonStorageDB) GetSnapshot() (DB, error) {
+	snapshotCapable, ok := s.VersionedDB.(statedb.SnapshotCapable)
+	if !ok {
+		return nil, errors.New("the state database does not support snapshots")
+	}
+	snapshot, err := snapshotCapable.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	if s.secondary == nil {
+		return &CommonStorageDB{VersionedDB: snapshot, metadataHint: s.metadataHint}, nil
+	}
+	secondarySnapshotCapable, ok := s.secondary.(statedb.SnapshotCapable)
+	if !ok {
+		snapshot.Close()
+		return nil, errors.New("the secondary state database does not support snapshots")
+	}
+	secondarySnapshot, err := secondarySnapshotCapable.GetSnapshot()
+	if err != nil {
+		snapshot.Close()
+		return nil, err
+	}
+	return &CommonStorageDB{
+		VersionedDB:       snapshot,
+		metadataHint:      s.metadataHint,
+		secondary:         secondarySnapshot,
+		secondaryDBName:   s.secondaryDBName,
+		collectionDBCache: s.collectionDBCache,
+	}, nil
+}
+
 // LoadCommittedVersionsOfPubAndHashedKeys implements corresponding function in interface DB
 func (s *CommonStorageDB) LoadCommittedVersionsOfPubAndHashedKeys(pubKeys []*statedb.CompositeKey,
 	hashedKeys []*HashedCompositeKey) error {
@@ -130,16 +260,27 @@ func (s *CommonStorageDB) ClearCachedVersions() {
 
 // GetChaincodeEventListener implements corresponding function in interface DB
 func (s *CommonStorageDB) GetChaincodeEventListener() cceventmgmt.ChaincodeLifecycleEventListener {
-	_, ok := s.VersionedDB.(statedb.IndexCapable)
-	if ok {
+	primaryIndexCapable, secondaryIndexCapable := s.indexCapableDBs()
+	if primaryIndexCapable != nil || secondaryIndexCapable != nil {
 		return s
 	}
 	return nil
 }
 
+// indexCapableDBs returns the primary and the secondary dbs if they support indexes, nil otherwise
+func (s *CommonStorageDB) indexCapableDBs() (primary, secondary statedb.IndexCapable) {
+	primary, _ = s.VersionedDB.(statedb.IndexCapable)
+	secondary, _ = s.secondary.(statedb.IndexCapable)
+	return primary, secondary
+}
+
 // GetPrivateData implements corresponding function in interface DB
 func (s *CommonStorageDB) GetPrivateData(namespace, collection, key string) (*statedb.VersionedValue, error) {
-	return s.GetState(derivePvtDataNs(namespace, collection), key)
+	vdb, err := s.pvtDataDB(namespace, collection)
+	if err != nil {
+		return nil, err
+	}
+	return vdb.GetState(derivePvtDataNs(namespace, collection), key)
 }
 
 // GetValueHash implements corresponding function in interface DB
@@ -176,17 +317,29 @@ func (s *CommonStorageDB) GetCachedKeyHashVersion(namespace, collection string,
 
 // GetPrivateDataMultipleKeys implements corresponding function in interface DB
 func (s *CommonStorageDB) GetPrivateDataMultipleKeys(namespace, collection string, keys []string) ([]*statedb.VersionedValue, error) {
-	return s.GetStateMultipleKeys(derivePvtDataNs(namespace, collection), keys)
+	vdb, err := s.pvtDataDB(namespace, collection)
+	if err != nil {
+		return nil, err
+	}
+	return vdb.GetStateMultipleKeys(derivePvtDataNs(namespace, collection), keys)
 }
 
 // GetPrivateDataRangeScanIterator implements corresponding function in interface DB
 func (s *CommonStorageDB) GetPrivateDataRangeScanIterator(namespace, collection, startKey, endKey string) (statedb.ResultsIterator, error) {
-	return s.GetStateRangeScanIterator(derivePvtDataNs(namespace, collection), startKey, endKey)
+	vdb, err := s.pvtDataDB(namespace, collection)
+	if err != nil {
+		return nil, err
+	}
+	return vdb.GetStateRangeScanIterator(derivePvtDataNs(namespace, collection), startKey, endKey)
 }
 
 // ExecuteQueryOnPrivateData implements corresponding function in interface DB
 func (s CommonStorageDB) ExecuteQueryOnPrivateData(namespace, collection, query string) (statedb.ResultsIterator, error) {
-	return s.ExecuteQuery(derivePvtDataNs(namespace, collection), query)
+	vdb, err := s.pvtDataDB(namespace, collection)
+	if err != nil {
+		return nil, err
+	}
+	return vdb.ExecuteQuery(derivePvtDataNs(namespace, collection), query)
 }
 
 // ApplyUpdates overrides the function in statedb.VersionedDB and throws appropriate error message
@@ -199,9 +352,19 @@ func (s *CommonStorageDB) ApplyUpdates(batch *statedb.UpdateBatch, height *versi
 func (s *CommonStorageDB) ApplyPrivacyAwareUpdates(updates *UpdateBatch, height *version.Height) error {
 	// combinedUpdates includes both updates to public db and private db, which are partitioned by a separate namespace
 	combinedUpdates := updates.PubUpdates
-	addPvtUpdates(combinedUpdates, updates.PvtUpdates)
+	secondaryUpdates := statedb.NewUpdateBatch()
+	if err := s.addPvtUpdates(combinedUpdates, secondaryUpdates, updates.PvtUpdates); err != nil {
+		return err
+	}
 	addHashedUpdates(combinedUpdates, updates.HashUpdates, !s.BytesKeySuppoted())
 	s.metadataHint.setMetadataUsedFlag(updates)
+	if len(secondaryUpdates.GetUpdatedNamespaces()) > 0 {
+		// the secondary db is updated first, so that the savepoint of the primary db, from which
+		// the blocks are recommitted upon recovery, is never ahead of the secondary db
+		if err := s.secondary.ApplyUpdates(secondaryUpdates, height); err != nil {
+			return err
+		}
+	}
 	return s.VersionedDB.ApplyUpdates(combinedUpdates.UpdateBatch, height)
 }
 
@@ -234,9 +397,9 @@ func (s *CommonStorageDB) GetPrivateDataMetadataByHash(namespace, collection str
 	return vv.Metadata, nil
 }
 
-func (s *CommonStorageDB) getCollectionConfigMap(chaincodeDefinition *cceventmgmt.ChaincodeDefinition) (map[string]bool, error) {
+func (s *CommonStorageDB) getCollectionConfigMap(chaincodeDefinition *cceventmgmt.ChaincodeDefinition) (map[string]*common.StaticCollectionConfig, error) {
 	var collectionConfigsBytes []byte
-	collectionConfigsMap := make(map[string]bool)
+	collectionConfigsMap := make(map[string]*common.StaticCollectionConfig)
 
 	// We need use the collection config present in the chaincodeDefinition to build the
 	// collection map.  If the chaincode definition does not contain the collection config,
@@ -249,7 +412,6 @@ func (s *CommonStorageDB) getCollectionConfigMap(chaincodeDefinition *cceventmgm
 		// When the collection configs are not passed in the instantiate/upgrade or
 		// when the current request is install after an instantiate, we need to fetch the
 		// collection config from the state database
-		lsccNamespace := "lscc"
 		collectionConfigKey := privdata.BuildCollectionKVSKey(chaincodeDefinition.Name)
 
 		versionedValue, err := s.VersionedDB.GetState(lsccNamespace, collectionConfigKey)
@@ -274,7 +436,7 @@ func (s *CommonStorageDB) getCollectionConfigMap(chaincodeDefinition *cceventmgm
 			if sConfig == nil {
 				continue
 			}
-			collectionConfigsMap[sConfig.Name] = true
+			collectionConfigsMap[sConfig.Name] = sConfig
 		}
 	}
 
@@ -288,9 +450,13 @@ func (s *CommonStorageDB) getCollectionConfigMap(chaincodeDefinition *cceventmgm
 // is acceptable since peer can continue in the committing role without the indexes. However, executing chaincode queries
 // may be affected, until a new chaincode with fixed indexes is installed and instantiated
 func (s *CommonStorageDB) HandleChaincodeDeploy(chaincodeDefinition *cceventmgmt.ChaincodeDefinition, dbArtifactsTar []byte) error {
-	//Check to see if the interface for IndexCapable is implemented
-	indexCapable, ok := s.VersionedDB.(statedb.IndexCapable)
-	if !ok {
+	//Check to see if the interface for IndexCapable is implemented, by the secondary db if not by the primary one
+	primaryIndexCapable, secondaryIndexCapable := s.indexCapableDBs()
+	indexCapable := primaryIndexCapable
+	if indexCapable == nil {
+		indexCapable = secondaryIndexCapable
+	}
+	if indexCapable == nil {
 		return nil
 	}
 	if chaincodeDefinition == nil {
@@ -314,7 +480,10 @@ func (s *CommonStorageDB) HandleChaincodeDeploy(chaincodeDefinition *cceventmgmt
 		directoryPathArray := strings.Split(directoryPath, "/")
 		// process the indexes for the chain
 		if directoryPathArray[3] == "indexes" {
-			err := indexCapable.ProcessIndexesForChaincodeDeploy(chaincodeDefinition.Name, archiveDirectoryEntries)
+			if primaryIndexCapable == nil {
+				continue
+			}
+			err := primaryIndexCapable.ProcessIndexesForChaincodeDeploy(chaincodeDefinition.Name, archiveDirectoryEntries)
 			if err != nil {
 				logger.Errorf("Error processing index for chaincode [%s]: %s", chaincodeDefinition.Name, err)
 			}
@@ -323,11 +492,18 @@ func (s *CommonStorageDB) HandleChaincodeDeploy(chaincodeDefinition *cceventmgmt
 		// check for the indexes directory for the collection
 		if directoryPathArray[3] == "collections" && directoryPathArray[5] == "indexes" {
 			collectionName := directoryPathArray[4]
-			_, ok := collectionConfigMap[collectionName]
+			collectionConfig, ok := collectionConfigMap[collectionName]
 			if !ok {
 				logger.Errorf("Error processing index for chaincode [%s]: cannot create an index for an undefined collection=[%s]", chaincodeDefinition.Name, collectionName)
 			} else {
-				err := indexCapable.ProcessIndexesForChaincodeDeploy(derivePvtDataNs(chaincodeDefinition.Name, collectionName),
+				collIndexCapable := primaryIndexCapable
+				if s.storesInSecondary(collectionConfig) {
+					collIndexCapable = secondaryIndexCapable
+				}
+				if collIndexCapable == nil {
+					continue
+				}
+				err := collIndexCapable.ProcessIndexesForChaincodeDeploy(derivePvtDataNs(chaincodeDefinition.Name, collectionName),
 					archiveDirectoryEntries)
 				if err != nil {
 					logger.Errorf("Error processing collection index for chaincode [%s]: %s", chaincodeDefinition.Name, err)
@@ -338,6 +514,181 @@ func (s *CommonStorageDB) HandleChaincodeDeploy(chaincodeDefinition *cceventmgmt
 	return nil
 }
 
+// StateDBSize implements function from interface ledger.StateDBSizer
+func (s *CommonStorageDB) StateDBSize() (int64, error) {
+	sizeCapable, ok := s.VersionedDB.(statedb.SizeCapable)
+	if !ok {
+		return 0, errors.New("the state database does not report its size")
+	}
+	size, err := sizeCapable.ApproximateSize()
+	if err != nil || s.secondary == nil {
+		return size, err
+	}
+	// the private data stored in the secondary db is counted as well
+	secondarySizeCapable, ok := s.secondary.(statedb.SizeCapable)
+	if !ok {
+		return size, nil
+	}
+	secondarySize, err := secondarySizeCapable.ApproximateSize()
+	if err != nil {
+		return 0, err
+	}
+	return size + secondarySize, nil
+}
+
+// GetFullScanIterator implements function from interface FullScanCapable
+func (s *CommonStorageDB) GetFullScanIterator() (statedb.ResultsIterator, error) {
+	fullScanCapable, ok := s.VersionedDB.(statedb.FullScanCapable)
+	if !ok {
+		return nil, errors.New("the state database cannot be scanned in full")
+	}
+	return fullScanCapable.GetFullScanIterator(isPvtDataNs)
+}
+
+// ImportState implements function from interface ImportCapable. The records are applied to the DB in
+// batches, the namespaces of the hashes of the private data being kept as is.
+func (s *CommonStorageDB) ImportState(itr statedb.ResultsIterator, savepoint *version.Height) error {
+	batch := statedb.NewUpdateBatch()
+	size := 0
+	namespacesWithMetadata := map[string]bool{}
+	for {
+		result, err := itr.Next()
+		if err != nil {
+			return err
+		}
+		if result == nil {
+			break
+		}
+		kv := result.(*statedb.VersionedKV)
+		batch.PutValAndMetadata(kv.Namespace, kv.Key, kv.Value, kv.Metadata, kv.Version)
+		if kv.Metadata != nil {
+			// the hint is kept per chaincode, for its public and private data alike
+			namespacesWithMetadata[strings.SplitN(kv.Namespace, nsJoiner, 2)[0]] = true
+		}
+		if size++; size == importBatchSize {
+			if err := s.VersionedDB.ApplyUpdates(batch, savepoint); err != nil {
+				return err
+			}
+			batch = statedb.NewUpdateBatch()
+			size = 0
+		}
+	}
+	s.metadataHint.setMetadataUsedFlagFor(namespacesWithMetadata)
+	return s.VersionedDB.ApplyUpdates(batch, savepoint)
+}
+
+// GetCommitMarker implements function from interface statedb.ReplicationCapable
+func (s *CommonStorageDB) GetCommitMarker() (*statedb.CommitMarker, error) {
+	replicationCapable, ok := s.VersionedDB.(statedb.ReplicationCapable)
+	if !ok {
+		return nil, errors.New("the state database cannot be shared with read replicas")
+	}
+	return replicationCapable.GetCommitMarker()
+}
+
+// ListStateIndexes implements function from interface ledger.StateIndexManager
+func (s *CommonStorageDB) ListStateIndexes(chaincodeName, collection string) ([]*ledger.StateIndex, error) {
+	collections, err := s.indexedCollections(chaincodeName, collection)
+	if err != nil {
+		return nil, err
+	}
+	var stateIndexes []*ledger.StateIndex
+	for _, coll := range collections {
+		indexes, err := coll.indexCapable.ListIndexes(indexedNamespace(chaincodeName, coll.name))
+		if err != nil {
+			return nil, err
+		}
+		for _, index := range indexes {
+			stateIndexes = append(stateIndexes, &ledger.StateIndex{
+				Collection:     coll.name,
+				DesignDocument: index.DesignDocument,
+				Name:           index.Name,
+				Definition:     index.Definition,
+			})
+		}
+	}
+	return stateIndexes, nil
+}
+
+// CreateStateIndex implements function from interface ledger.StateIndexManager
+func (s *CommonStorageDB) CreateStateIndex(chaincodeName, collection string, definition []byte) error {
+	collections, err := s.indexedCollections(chaincodeName, collection)
+	if err != nil {
+		return err
+	}
+	for _, coll := range collections {
+		if coll.name == collection {
+			return coll.indexCapable.CreateIndex(indexedNamespace(chaincodeName, collection), definition)
+		}
+	}
+	return errors.Errorf("the state database of collection [%s] of chaincode [%s] does not support indexes", collection, chaincodeName)
+}
+
+// RebuildStateIndexes implements function from interface ledger.StateIndexManager
+func (s *CommonStorageDB) RebuildStateIndexes(chaincodeName, collection string) error {
+	collections, err := s.indexedCollections(chaincodeName, collection)
+	if err != nil {
+		return err
+	}
+	for _, coll := range collections {
+		if err := coll.indexCapable.RebuildIndexes(indexedNamespace(chaincodeName, coll.name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexedCollection is a collection of a chaincode, the empty name standing
+// for the public state, along with the state database holding its indexes
+type indexedCollection struct {
+	name         string
+	indexCapable statedb.IndexCapable
+}
+
+// indexedCollections returns the collections of the chaincode whose state
+// databases hold indexes, or only the supplied collection if set
+func (s *CommonStorageDB) indexedCollections(chaincodeName, collection string) ([]*indexedCollection, error) {
+	primaryIndexCapable, secondaryIndexCapable := s.indexCapableDBs()
+	if primaryIndexCapable == nil && secondaryIndexCapable == nil {
+		return nil, errors.New("the state database does not support indexes")
+	}
+	collectionConfigMap, err := s.getCollectionConfigMap(&cceventmgmt.ChaincodeDefinition{Name: chaincodeName})
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("error retrieving collection config for chaincode [%s]", chaincodeName))
+	}
+	var names []string
+	if collection != "" {
+		if collectionConfigMap[collection] == nil {
+			return nil, errors.Errorf("collection [%s] is not defined for chaincode [%s]", collection, chaincodeName)
+		}
+		names = []string{collection}
+	} else {
+		names = []string{""}
+		for coll := range collectionConfigMap {
+			names = append(names, coll)
+		}
+		sort.Strings(names)
+	}
+	var collections []*indexedCollection
+	for _, name := range names {
+		indexCapable := primaryIndexCapable
+		if name != "" && s.storesInSecondary(collectionConfigMap[name]) {
+			indexCapable = secondaryIndexCapable
+		}
+		if indexCapable != nil {
+			collections = append(collections, &indexedCollection{name: name, indexCapable: indexCapable})
+		}
+	}
+	return collections, nil
+}
+
+func indexedNamespace(chaincodeName, collection string) string {
+	if collection == "" {
+		return chaincodeName
+	}
+	return derivePvtDataNs(chaincodeName, collection)
+}
+
 // ChaincodeDeployDone is a noop for couchdb state impl
 func (s *CommonStorageDB) ChaincodeDeployDone(succeeded bool) {
 	// NOOP
@@ -351,14 +702,31 @@ func deriveHashedDataNs(namespace, collection string) string {
 	return namespace + nsJoiner + hashDataPrefix + collection
 }
 
-func addPvtUpdates(pubUpdateBatch *PubUpdateBatch, pvtUpdateBatch *PvtUpdateBatch) {
+// isPvtDataNs returns whether the namespace is derived by derivePvtDataNs, the
+// chaincode and collection names not holding the nsJoiner
+func isPvtDataNs(ns string) bool {
+	split := strings.SplitN(ns, nsJoiner, 2)
+	return len(split) == 2 && strings.HasPrefix(split[1], pvtDataPrefix)
+}
+
+// addPvtUpdates adds the updates of the private data to the batch of the db storing them
+func (s *CommonStorageDB) addPvtUpdates(pubUpdateBatch *PubUpdateBatch, secondaryUpdateBatch *statedb.UpdateBatch, pvtUpdateBatch *PvtUpdateBatch) error {
 	for ns, nsBatch := range pvtUpdateBatch.UpdateMap {
 		for _, coll := range nsBatch.GetCollectionNames() {
+			inSecondary, err := s.isInSecondaryAfterUpdates(ns, coll, pubUpdateBatch)
+			if err != nil {
+				return err
+			}
+			batch := pubUpdateBatch.UpdateBatch
+			if inSecondary {
+				batch = secondaryUpdateBatch
+			}
 			for key, vv := range nsBatch.GetUpdates(coll) {
-				pubUpdateBatch.Update(derivePvtDataNs(ns, coll), key, vv)
+				batch.Update(derivePvtDataNs(ns, coll), key, vv)
 			}
 		}
 	}
+	return nil
 }
 
 func addHashedUpdates(pubUpdateBatch *PubUpdateBatch, hashedUpdateBatch *HashedUpdateBatch, base64Key bool) {
@@ -26,6 +26,10 @@ type DBProvider interface {
 type DB interface {
 	statedb.VersionedDB
 	IsBulkOptimizable() bool
+	IsSnapshotCapable() bool
+	// GetSnapshot returns a read-only DB presenting the state as of the call. The updates cannot be
+	// applied to the returned DB and its Close function releases the snapshot.
+	GetSnapshot() (DB, error)
 	LoadCommittedVersionsOfPubAndHashedKeys(pubKeys []*statedb.CompositeKey, hashedKeys []*HashedCompositeKey) error
 	GetCachedKeyHashVersion(namespace, collection string, keyHash []byte) (*version.Height, bool)
 	ClearCachedVersions()
@@ -41,6 +45,19 @@ type DB interface {
 	ApplyPrivacyAwareUpdates(updates *UpdateBatch, height *version.Height) error
 }
 
+// FullScanCapable is implemented by the DBs capable of iterating over their whole public state and the
+// hashes of their private data, the private data itself being skipped. The hashes of the private data
+// are returned in namespaces derived from the ones of their chaincodes and collections.
+type FullScanCapable interface {
+	GetFullScanIterator() (statedb.ResultsIterator, error)
+}
+
+// ImportCapable is implemented by the DBs which can be loaded with the records returned by the full scan of
+// another DB, see FullScanCapable, along with the savepoint of that DB
+type ImportCapable interface {
+	ImportState(itr statedb.ResultsIterator, savepoint *version.Height) error
+}
+
 // PvtdataCompositeKey encloses Namespace, CollectionName and Key components
 type PvtdataCompositeKey struct {
 	Namespace      string
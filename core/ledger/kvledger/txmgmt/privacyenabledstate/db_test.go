@@ -17,9 +17,13 @@ import (
 
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 )
@@ -582,6 +586,227 @@ func testMetadataRetrieval(t *testing.T, env TestEnv) {
 	assert.Nil(t, vm)
 }
 
+func TestStateDBSize(t *testing.T) {
+	env := &LevelDBCommonStorageTestEnv{}
+	env.Init(t)
+	defer env.Cleanup()
+	db := env.GetDBHandle("test-ledger-id")
+
+	updates := NewUpdateBatch()
+	updates.PubUpdates.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	db.ApplyPrivacyAwareUpdates(updates, version.NewHeight(1, 1))
+
+	// the size of the leveldb tables, the entries of the journal are not part of it
+	size, err := db.(ledger.StateDBSizer).StateDBSize()
+	assert.NoError(t, err)
+	assert.True(t, size >= 0)
+}
+
+func TestGetCommitMarker(t *testing.T) {
+	env := &LevelDBCommonStorageTestEnv{}
+	env.Init(t)
+	defer env.Cleanup()
+	db := env.GetDBHandle("test-ledger-id")
+
+	_, err := db.(statedb.ReplicationCapable).GetCommitMarker()
+	assert.EqualError(t, err, "the state database cannot be shared with read replicas")
+}
+
+func TestSnapshot(t *testing.T) {
+	env := &LevelDBCommonStorageTestEnv{}
+	env.Init(t)
+	defer env.Cleanup()
+	db := env.GetDBHandle("test-ledger-id")
+	assert.True(t, db.IsSnapshotCapable())
+
+	updates := NewUpdateBatch()
+	updates.PubUpdates.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	putPvtUpdates(t, updates, "ns1", "coll1", "key1", []byte("pvt_value1"), version.NewHeight(1, 2))
+	db.ApplyPrivacyAwareUpdates(updates, version.NewHeight(1, 2))
+
+	snapshot, err := db.GetSnapshot()
+	assert.NoError(t, err)
+	defer snapshot.Close()
+
+	updates = NewUpdateBatch()
+	updates.PubUpdates.Put("ns1", "key1", []byte("value1-new"), version.NewHeight(2, 1))
+	putPvtUpdates(t, updates, "ns1", "coll1", "key1", []byte("pvt_value1-new"), version.NewHeight(2, 1))
+	db.ApplyPrivacyAwareUpdates(updates, version.NewHeight(2, 1))
+
+	vv, err := snapshot.GetState("ns1", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, &statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 1)}, vv)
+	vv, err = snapshot.GetPrivateData("ns1", "coll1", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, &statedb.VersionedValue{Value: []byte("pvt_value1"), Version: version.NewHeight(1, 2)}, vv)
+	hashVersion, err := snapshot.GetKeyHashVersion("ns1", "coll1", util.ComputeStringHash("key1"))
+	assert.NoError(t, err)
+	assert.Equal(t, version.NewHeight(1, 2), hashVersion)
+
+	vv, err = db.GetPrivateData("ns1", "coll1", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("pvt_value1-new"), vv.Value)
+
+	assert.Error(t, snapshot.ApplyPrivacyAwareUpdates(NewUpdateBatch(), version.NewHeight(3, 1)))
+}
+
+func TestFullScanIterator(t *testing.T) {
+	env := &LevelDBCommonStorageTestEnv{}
+	env.Init(t)
+	defer env.Cleanup()
+	db := env.GetDBHandle("test-ledger-id")
+
+	updates := NewUpdateBatch()
+	updates.PubUpdates.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	putPvtUpdates(t, updates, "ns1", "coll1", "key1", []byte("pvt_value1"), version.NewHeight(1, 2))
+	db.ApplyPrivacyAwareUpdates(updates, version.NewHeight(1, 2))
+
+	itr, err := db.(FullScanCapable).GetFullScanIterator()
+	assert.NoError(t, err)
+	defer itr.Close()
+	var kvs []*statedb.VersionedKV
+	for {
+		result, err := itr.Next()
+		assert.NoError(t, err)
+		if result == nil {
+			break
+		}
+		kvs = append(kvs, result.(*statedb.VersionedKV))
+	}
+	// the private data is skipped, its hash is not
+	assert.Len(t, kvs, 2)
+	assert.Equal(t, statedb.CompositeKey{Namespace: "ns1", Key: "key1"}, kvs[0].CompositeKey)
+	assert.Equal(t, statedb.CompositeKey{Namespace: deriveHashedDataNs("ns1", "coll1"), Key: string(util.ComputeStringHash("key1"))}, kvs[1].CompositeKey)
+	assert.Equal(t, util.ComputeHash([]byte("pvt_value1")), kvs[1].Value)
+}
+
+func TestImportState(t *testing.T) {
+	env := &LevelDBCommonStorageTestEnv{}
+	env.Init(t)
+	defer env.Cleanup()
+	db := env.GetDBHandle("test-ledger-id")
+
+	hashedNs := deriveHashedDataNs("ns1", "coll1")
+	keyHash := util.ComputeStringHash("key1")
+	itr := &kvsIterator{kvs: []*statedb.VersionedKV{
+		{
+			CompositeKey:   statedb.CompositeKey{Namespace: "ns1", Key: "key1"},
+			VersionedValue: statedb.VersionedValue{Value: []byte("value1"), Metadata: []byte("metadata1"), Version: version.NewHeight(1, 1)},
+		},
+		{
+			CompositeKey:   statedb.CompositeKey{Namespace: hashedNs, Key: string(keyHash)},
+			VersionedValue: statedb.VersionedValue{Value: util.ComputeHash([]byte("pvt_value1")), Version: version.NewHeight(1, 2)},
+		},
+	}}
+	assert.NoError(t, db.(ImportCapable).ImportState(itr, version.NewHeight(5, 3)))
+
+	vv, err := db.GetState("ns1", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, &statedb.VersionedValue{Value: []byte("value1"), Metadata: []byte("metadata1"), Version: version.NewHeight(1, 1)}, vv)
+	vv, err = db.GetValueHash("ns1", "coll1", keyHash)
+	assert.NoError(t, err)
+	assert.Equal(t, util.ComputeHash([]byte("pvt_value1")), vv.Value)
+	savepoint, err := db.GetLatestSavePoint()
+	assert.NoError(t, err)
+	assert.Equal(t, version.NewHeight(5, 3), savepoint)
+
+	// the metadata of the imported keys is not hidden by the metadata hint
+	metadata, err := db.GetStateMetadata("ns1", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("metadata1"), metadata)
+}
+
+func TestMixedStateDatabases(t *testing.T) {
+	env := &LevelDBCommonStorageTestEnv{}
+	env.Init(t)
+	defer env.Cleanup()
+	// the secondary db is a leveldb as well, standing for a CouchDB
+	vdbProvider := env.provider.(*CommonStorageDBProvider).VersionedDBProvider
+	primary, err := vdbProvider.GetDBHandle("test-ledger-id")
+	assert.NoError(t, err)
+	secondary, err := vdbProvider.GetDBHandle("test-ledger-id-secondary")
+	assert.NoError(t, err)
+	bookkeeper := env.bookkeeperTestEnv.TestProvider.GetDBHandle("test-ledger-id", bookkeeping.MetadataPresenceIndicator)
+	db := newMixedCommonStorageDB(primary, secondary, ledgerconfig.StateDatabaseCouchDB, newMetadataHint(bookkeeper))
+
+	coll1 := createCollectionConfig("coll1")
+	coll1.GetStaticCollectionConfig().StateDatabase = ledgerconfig.StateDatabaseCouchDB
+	ccpBytes, err := proto.Marshal(&common.CollectionConfigPackage{Config: []*common.CollectionConfig{coll1, createCollectionConfig("coll2")}})
+	assert.NoError(t, err)
+
+	// the collection config is committed by the block writing the private data
+	updates := NewUpdateBatch()
+	updates.PubUpdates.Put(lsccNamespace, privdata.BuildCollectionKVSKey("ns1"), ccpBytes, version.NewHeight(1, 1))
+	putPvtUpdates(t, updates, "ns1", "coll1", "key1", []byte("pvt_value1"), version.NewHeight(1, 2))
+	putPvtUpdates(t, updates, "ns1", "coll1", "key2", []byte("pvt_value2"), version.NewHeight(1, 2))
+	putPvtUpdates(t, updates, "ns1", "coll2", "key1", []byte("pvt_value3"), version.NewHeight(1, 2))
+	assert.NoError(t, db.ApplyPrivacyAwareUpdates(updates, version.NewHeight(1, 2)))
+
+	vv, err := secondary.GetState(derivePvtDataNs("ns1", "coll1"), "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("pvt_value1"), vv.Value)
+	vv, err = primary.GetState(derivePvtDataNs("ns1", "coll1"), "key1")
+	assert.NoError(t, err)
+	assert.Nil(t, vv)
+	vv, err = primary.GetState(derivePvtDataNs("ns1", "coll2"), "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("pvt_value3"), vv.Value)
+	// the hashes are kept in the primary db
+	vv, err = primary.GetState(deriveHashedDataNs("ns1", "coll1"), string(util.ComputeStringHash("key1")))
+	assert.NoError(t, err)
+	assert.Equal(t, util.ComputeHash([]byte("pvt_value1")), vv.Value)
+
+	vv, err = db.GetPrivateData("ns1", "coll1", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, &statedb.VersionedValue{Value: []byte("pvt_value1"), Version: version.NewHeight(1, 2)}, vv)
+	vvs, err := db.GetPrivateDataMultipleKeys("ns1", "coll1", []string{"key1", "key2"})
+	assert.NoError(t, err)
+	assert.Len(t, vvs, 2)
+	assert.Equal(t, []byte("pvt_value2"), vvs[1].Value)
+	itr, err := db.GetPrivateDataRangeScanIterator("ns1", "coll1", "", "")
+	assert.NoError(t, err)
+	testItr(t, itr, []string{"key1", "key2"})
+	vv, err = db.GetPrivateData("ns1", "coll2", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("pvt_value3"), vv.Value)
+
+	// the later blocks look up the committed collection config
+	updates = NewUpdateBatch()
+	deletePvtUpdates(t, updates, "ns1", "coll1", "key1", version.NewHeight(2, 1))
+	assert.NoError(t, db.ApplyPrivacyAwareUpdates(updates, version.NewHeight(2, 1)))
+	vv, err = secondary.GetState(derivePvtDataNs("ns1", "coll1"), "key1")
+	assert.NoError(t, err)
+	assert.Nil(t, vv)
+	savepoint, err := secondary.GetLatestSavePoint()
+	assert.NoError(t, err)
+	assert.Equal(t, version.NewHeight(2, 1), savepoint)
+
+	assert.True(t, db.IsSnapshotCapable())
+	snapshot, err := db.GetSnapshot()
+	assert.NoError(t, err)
+	defer snapshot.Close()
+	vv, err = snapshot.GetPrivateData("ns1", "coll1", "key2")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("pvt_value2"), vv.Value)
+}
+
+// kvsIterator iterates over a list of keys
+type kvsIterator struct {
+	kvs []*statedb.VersionedKV
+}
+
+func (itr *kvsIterator) Next() (statedb.QueryResult, error) {
+	if len(itr.kvs) == 0 {
+		return nil, nil
+	}
+	kv := itr.kvs[0]
+	itr.kvs = itr.kvs[1:]
+	return kv, nil
+}
+
+func (itr *kvsIterator) Close() {
+}
+
 func putPvtUpdates(t *testing.T, updates *UpdateBatch, ns, coll, key string, value []byte, ver *version.Height) {
 	updates.PvtUpdates.Put(ns, coll, key, value, ver)
 	updates.HashUpdates.Put(ns, coll, util.ComputeStringHash(key), util.ComputeHash(value), ver)
@@ -31,8 +31,12 @@ func (h *metadataHint) metadataEverUsedFor(namespace string) bool {
 }
 
 func (h *metadataHint) setMetadataUsedFlag(updates *UpdateBatch) {
+	h.setMetadataUsedFlagFor(filterNamespacesThatHasMetadata(updates))
+}
+
+func (h *metadataHint) setMetadataUsedFlagFor(namespaces map[string]bool) {
 	batch := leveldbhelper.NewUpdateBatch()
-	for ns := range filterNamespacesThatHasMetadata(updates) {
+	for ns := range namespaces {
 		if h.cache[ns] {
 			continue
 		}
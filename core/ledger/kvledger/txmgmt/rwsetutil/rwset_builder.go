@@ -31,6 +31,7 @@ var logger = flogging.MustGetLogger("rwsetutil")
 type RWSetBuilder struct {
 	pubRwBuilderMap map[string]*nsPubRwBuilder
 	pvtRwBuilderMap map[string]*nsPvtRwBuilder
+	valueHashFunc   func([]byte) []byte
 }
 
 type nsPubRwBuilder struct {
@@ -70,7 +71,13 @@ type rangeQueryKey struct {
 
 // NewRWSetBuilder constructs a new instance of RWSetBuilder
 func NewRWSetBuilder() *RWSetBuilder {
-	return &RWSetBuilder{make(map[string]*nsPubRwBuilder), make(map[string]*nsPvtRwBuilder)}
+	return NewRWSetBuilderWithValueHashFunc(util.ComputeHash)
+}
+
+// NewRWSetBuilderWithValueHashFunc constructs a new instance of RWSetBuilder which
+// hashes the private values and write-sets with the given function
+func NewRWSetBuilderWithValueHashFunc(valueHashFunc func([]byte) []byte) *RWSetBuilder {
+	return &RWSetBuilder{make(map[string]*nsPubRwBuilder), make(map[string]*nsPvtRwBuilder), valueHashFunc}
 }
 
 // AddToReadSet adds a key and corresponding version to the read-set
@@ -111,7 +118,13 @@ func (b *RWSetBuilder) AddToHashedReadSet(ns string, coll string, key string, ve
 
 // AddToPvtAndHashedWriteSet adds a key and value to the private and hashed write-set
 func (b *RWSetBuilder) AddToPvtAndHashedWriteSet(ns string, coll string, key string, value []byte) {
-	kvWrite, kvWriteHash := newPvtKVWriteAndHash(key, value)
+	b.AddToPvtAndHashedWriteSetWithValueHashFunc(ns, coll, key, value, b.valueHashFunc)
+}
+
+// AddToPvtAndHashedWriteSetWithValueHashFunc adds a key and value to the private and hashed write-set,
+// the value being hashed in the hashed write-set with the given function
+func (b *RWSetBuilder) AddToPvtAndHashedWriteSetWithValueHashFunc(ns string, coll string, key string, value []byte, valueHashFunc func([]byte) []byte) {
+	kvWrite, kvWriteHash := newPvtKVWriteAndHash(key, value, valueHashFunc)
 	b.getOrCreateCollPvtRwBuilder(ns, coll).writeMap[key] = kvWrite
 	b.getOrCreateCollHashedRwBuilder(ns, coll).writeMap[key] = kvWriteHash
 }
@@ -162,7 +175,7 @@ func (b *RWSetBuilder) GetTxSimulationResults() (*ledger.TxSimulationResults, er
 
 func (b *RWSetBuilder) setPvtCollectionHash(ns string, coll string, pvtDataProto []byte) {
 	collHashedBuilder := b.getOrCreateCollHashedRwBuilder(ns, coll)
-	collHashedBuilder.pvtDataHash = util.ComputeHash(pvtDataProto)
+	collHashedBuilder.pvtDataHash = b.valueHashFunc(pvtDataProto)
 }
 
 // GetTxReadWriteSet returns the read-write set
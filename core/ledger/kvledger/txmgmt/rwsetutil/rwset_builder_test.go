@@ -12,6 +12,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
+	commonutil "github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/ledger/rwset"
@@ -342,13 +343,53 @@ func TestTxSimulationResultWithMetadata(t *testing.T) {
 	assert.Equal(t, expectedPubRWSet, actualSimRes.PubSimulationResults)
 }
 
+func TestTxSimulationResultWithValueHashFunc(t *testing.T) {
+	rwSetBuilder := NewRWSetBuilderWithValueHashFunc(commonutil.ComputeSHA3256)
+	rwSetBuilder.AddToPvtAndHashedWriteSet("ns1", "coll1", "key1", []byte("pvt-ns1-coll1-key1-value"))
+
+	actualSimRes, err := rwSetBuilder.GetTxSimulationResults()
+	assert.NoError(t, err)
+
+	pvtColl := actualSimRes.PvtSimulationResults.NsPvtRwset[0].CollectionPvtRwset[0]
+	hashedColl := actualSimRes.PubSimulationResults.NsRwset[0].CollectionHashedRwset[0]
+	assert.Equal(t, commonutil.ComputeSHA3256(pvtColl.Rwset), hashedColl.PvtRwsetHash)
+
+	hashedRwSet := &kvrwset.HashedRWSet{}
+	assert.NoError(t, proto.Unmarshal(hashedColl.HashedRwset, hashedRwSet))
+	assert.Equal(t, util.ComputeStringHash("key1"), hashedRwSet.HashedWrites[0].KeyHash)
+	assert.Equal(t, commonutil.ComputeSHA3256([]byte("pvt-ns1-coll1-key1-value")), hashedRwSet.HashedWrites[0].ValueHash)
+}
+
+func TestTxSimulationResultWithPerWriteValueHashFunc(t *testing.T) {
+	rwSetBuilder := NewRWSetBuilder()
+	rwSetBuilder.AddToPvtAndHashedWriteSetWithValueHashFunc("ns1", "coll1", "key1", []byte("value1"), commonutil.ComputeSHA3256)
+	rwSetBuilder.AddToPvtAndHashedWriteSetWithValueHashFunc("ns1", "coll1", "key2", nil, commonutil.ComputeSHA3256)
+	rwSetBuilder.AddToPvtAndHashedWriteSet("ns1", "coll2", "key1", []byte("value1"))
+
+	actualSimRes, err := rwSetBuilder.GetTxSimulationResults()
+	assert.NoError(t, err)
+
+	// the hash of the private write-set of the collection is unchanged
+	hashedColls := actualSimRes.PubSimulationResults.NsRwset[0].CollectionHashedRwset
+	pvtColl := actualSimRes.PvtSimulationResults.NsPvtRwset[0].CollectionPvtRwset[0]
+	assert.Equal(t, util.ComputeHash(pvtColl.Rwset), hashedColls[0].PvtRwsetHash)
+
+	hashedRwSet := &kvrwset.HashedRWSet{}
+	assert.NoError(t, proto.Unmarshal(hashedColls[0].HashedRwset, hashedRwSet))
+	assert.Equal(t, commonutil.ComputeSHA3256([]byte("value1")), hashedRwSet.HashedWrites[0].ValueHash)
+	assert.True(t, hashedRwSet.HashedWrites[1].IsDelete)
+	assert.Nil(t, hashedRwSet.HashedWrites[1].ValueHash)
+	assert.NoError(t, proto.Unmarshal(hashedColls[1].HashedRwset, hashedRwSet))
+	assert.Equal(t, util.ComputeHash([]byte("value1")), hashedRwSet.HashedWrites[0].ValueHash)
+}
+
 func constructTestPvtKVReadHash(t *testing.T, key string, version *version.Height) *kvrwset.KVReadHash {
 	kvReadHash := newPvtKVReadHash(key, version)
 	return kvReadHash
 }
 
 func constructTestPvtKVWriteHash(t *testing.T, key string, value []byte) *kvrwset.KVWriteHash {
-	_, kvWriteHash := newPvtKVWriteAndHash(key, value)
+	_, kvWriteHash := newPvtKVWriteAndHash(key, value, util.ComputeHash)
 	return kvWriteHash
 }
 
@@ -307,12 +307,12 @@ func newPvtKVReadHash(key string, version *version.Height) *kvrwset.KVReadHash {
 	return &kvrwset.KVReadHash{KeyHash: util.ComputeStringHash(key), Version: newProtoVersion(version)}
 }
 
-func newPvtKVWriteAndHash(key string, value []byte) (*kvrwset.KVWrite, *kvrwset.KVWriteHash) {
+func newPvtKVWriteAndHash(key string, value []byte, valueHashFunc func([]byte) []byte) (*kvrwset.KVWrite, *kvrwset.KVWriteHash) {
 	kvWrite := newKVWrite(key, value)
 	var keyHash, valueHash []byte
 	keyHash = util.ComputeStringHash(key)
 	if !kvWrite.IsDelete {
-		valueHash = util.ComputeHash(value)
+		valueHash = valueHashFunc(value)
 	}
 	return kvWrite, &kvrwset.KVWriteHash{KeyHash: keyHash, IsDelete: kvWrite.IsDelete, ValueHash: valueHash}
 }
@@ -43,6 +43,15 @@ func (vdb *VersionedDB) buildCommitters(updates *statedb.UpdateBatch) ([]batch,
 		if nsRevs == nil {
 			nsRevs = make(nsRevisions)
 		}
+		// the revisions of the keys written without being read may be cached
+		for key := range nsUpdates {
+			if _, ok := nsRevs[key]; ok {
+				continue
+			}
+			if entry, ok := vdb.cache.get(vdb.chainName, ns, key); ok {
+				nsRevs[key] = entry.rev
+			}
+		}
 		// for each namespace, construct one builder with the corresponding couchdb handle and couch revisions
 		// that are already loaded into cache (during validation phase)
 		nsCommitterBuilder = append(nsCommitterBuilder, &nsCommittersBuilder{updates: nsUpdates, db: db, revisions: nsRevs})
@@ -0,0 +1,61 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statecouchdb
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/util/couchdb"
+	"github.com/pkg/errors"
+)
+
+// Commit marker docid (key) for couchdb
+const commitMarkerDocID = "statedb_commit_marker"
+
+// couchCommitMarkerData data for couchdb
+type couchCommitMarkerData struct {
+	BlockNum   uint64 `json:"BlockNum"`
+	TxNum      uint64 `json:"TxNum"`
+	Committing bool   `json:"Committing"`
+}
+
+// recordCommitMarker publishes in the metadata db that the commit of the batch of updates at the
+// given height started or completed, for the read replicas sharing the db. The marker of a commit
+// interrupted by a crash is completed when the lost blocks are recommitted during the recovery.
+func (vdb *VersionedDB) recordCommitMarker(height *version.Height, committing bool) error {
+	markerJSON, err := json.Marshal(couchCommitMarkerData{BlockNum: height.BlockNum, TxNum: height.TxNum, Committing: committing})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal commit marker data")
+	}
+	if _, err := vdb.metadataDB.SaveDoc(commitMarkerDocID, "", &couchdb.CouchDoc{JSONValue: markerJSON}); err != nil {
+		logger.Errorf("Failed to save the commit marker to DB %s", err.Error())
+		return err
+	}
+	return nil
+}
+
+// GetCommitMarker implements method in ReplicationCapable interface
+func (vdb *VersionedDB) GetCommitMarker() (*statedb.CommitMarker, error) {
+	couchDoc, _, err := vdb.metadataDB.ReadDoc(commitMarkerDocID)
+	if err != nil {
+		logger.Errorf("Failed to read commit marker data %s", err.Error())
+		return nil, err
+	}
+	// ReadDoc() not found (404) will result in nil response, in these cases return marker nil
+	if couchDoc == nil || couchDoc.JSONValue == nil {
+		return nil, nil
+	}
+	markerDoc := &couchCommitMarkerData{}
+	if err := json.Unmarshal(couchDoc.JSONValue, markerDoc); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal commit marker data")
+	}
+	return &statedb.CommitMarker{
+		Height:     version.NewHeight(markerDoc.BlockNum, markerDoc.TxNum),
+		Committing: markerDoc.Committing,
+	}, nil
+}
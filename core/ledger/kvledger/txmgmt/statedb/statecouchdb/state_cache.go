@@ -0,0 +1,175 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statecouchdb
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+)
+
+// cacheEntryOverhead approximates the memory used by an entry of the cache
+// beyond its keys, value and revision
+const cacheEntryOverhead = 200
+
+type cacheNs struct {
+	chainName, namespace string
+}
+
+type cacheKey struct {
+	cacheNs
+	key string
+}
+
+// cacheEntry holds the committed value and couch revision of a key
+type cacheEntry struct {
+	// value is nil if the key doesn't exist
+	value   *statedb.VersionedValue
+	version *version.Height
+	rev     string
+	// versionOnly is set for the entries loaded along with the versions of the
+	// keys for the validation of the read sets, which don't hold the value
+	versionOnly bool
+}
+
+type cacheElement struct {
+	key   cacheKey
+	entry cacheEntry
+	size  int
+}
+
+// stateCache is a read-through cache of the values and couch revisions of the
+// keys of the state databases of the peer, shared by its channels within a
+// budget of memory. The least recently used keys are evicted first.
+//
+// The keys updated by a batch are removed from the cache before and after the
+// batch is pushed to the db, and the values read from the db while a batch of
+// their namespace is pushed are not cached, so that the cache never serves a
+// value the db no longer holds. A nil stateCache caches nothing.
+type stateCache struct {
+	maxSize int
+
+	mutex    sync.Mutex
+	size     int
+	elements map[cacheKey]*list.Element
+	lru      *list.List
+	// generations counts the updates started and completed for each
+	// namespace, it is odd while an update is pushed to the db
+	generations map[cacheNs]uint64
+}
+
+// newStateCache returns a cache of the given size in bytes, or nil if the
+// size is not positive
+func newStateCache(maxSize int) *stateCache {
+	if maxSize <= 0 {
+		return nil
+	}
+	return &stateCache{
+		maxSize:     maxSize,
+		elements:    make(map[cacheKey]*list.Element),
+		lru:         list.New(),
+		generations: make(map[cacheNs]uint64),
+	}
+}
+
+// get returns the entry cached for the given key
+func (c *stateCache) get(chainName, namespace, key string) (cacheEntry, bool) {
+	if c == nil {
+		return cacheEntry{}, false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	element, ok := c.elements[cacheKey{cacheNs{chainName, namespace}, key}]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	c.lru.MoveToFront(element)
+	return element.Value.(*cacheElement).entry, true
+}
+
+// generation returns the generation of the namespace, to be passed to put
+// along with the entries read from the db afterwards
+func (c *stateCache) generation(chainName, namespace string) uint64 {
+	if c == nil {
+		return 0
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.generations[cacheNs{chainName, namespace}]
+}
+
+// put caches the entry of the given key, read from the db at the given
+// generation of its namespace, unless the namespace was updated since. An
+// entry holding the value of the key isn't replaced by one holding only its
+// version.
+func (c *stateCache) put(chainName, namespace, key string, entry cacheEntry, generation uint64) {
+	if c == nil {
+		return
+	}
+	k := cacheKey{cacheNs{chainName, namespace}, key}
+	size := cacheEntryOverhead + len(chainName) + len(namespace) + len(key) + len(entry.rev)
+	if entry.value != nil {
+		size += len(entry.value.Value) + len(entry.value.Metadata)
+	}
+	if size > c.maxSize {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if generation%2 == 1 || c.generations[k.cacheNs] != generation {
+		return
+	}
+	if element, ok := c.elements[k]; ok {
+		if entry.versionOnly && !element.Value.(*cacheElement).entry.versionOnly {
+			c.lru.MoveToFront(element)
+			return
+		}
+		c.remove(element)
+	}
+	c.elements[k] = c.lru.PushFront(&cacheElement{key: k, entry: entry, size: size})
+	c.size += size
+	for c.size > c.maxSize {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *stateCache) remove(element *list.Element) {
+	e := c.lru.Remove(element).(*cacheElement)
+	delete(c.elements, e.key)
+	c.size -= e.size
+}
+
+// startUpdate removes the keys of the batch from the cache, and stops caching
+// the values of their namespaces until the batch is pushed to the db
+func (c *stateCache) startUpdate(chainName string, updates *statedb.UpdateBatch) {
+	c.invalidate(chainName, updates)
+}
+
+// endUpdate removes the keys of the batch pushed to the db from the cache,
+// and resumes caching the values of their namespaces
+func (c *stateCache) endUpdate(chainName string, updates *statedb.UpdateBatch) {
+	c.invalidate(chainName, updates)
+}
+
+func (c *stateCache) invalidate(chainName string, updates *statedb.UpdateBatch) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, namespace := range updates.GetUpdatedNamespaces() {
+		ns := cacheNs{chainName, namespace}
+		c.generations[ns]++
+		for key := range updates.GetUpdates(namespace) {
+			if element, ok := c.elements[cacheKey{ns, key}]; ok {
+				c.remove(element)
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statecouchdb
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateCacheGetPut(t *testing.T) {
+	var disabled *stateCache
+	disabled.put("ch1", "ns1", "key1", cacheEntry{}, 0)
+	_, ok := disabled.get("ch1", "ns1", "key1")
+	assert.False(t, ok)
+	assert.Nil(t, newStateCache(0))
+
+	c := newStateCache(1024 * 1024)
+	ver := version.NewHeight(1, 1)
+	value := cacheEntry{value: &statedb.VersionedValue{Value: []byte("value1"), Version: ver}, version: ver, rev: "1-a"}
+	c.put("ch1", "ns1", "key1", value, 0)
+	entry, ok := c.get("ch1", "ns1", "key1")
+	assert.True(t, ok)
+	assert.Equal(t, value, entry)
+	// the keys of the channels and namespaces are distinct
+	_, ok = c.get("ch2", "ns1", "key1")
+	assert.False(t, ok)
+	_, ok = c.get("ch1", "ns2", "key1")
+	assert.False(t, ok)
+
+	// an entry holding the value is not replaced by one holding only the version
+	c.put("ch1", "ns1", "key1", cacheEntry{version: ver, rev: "1-a", versionOnly: true}, 0)
+	entry, _ = c.get("ch1", "ns1", "key1")
+	assert.Equal(t, value, entry)
+	// but a version only entry is replaced by one holding the value
+	c.put("ch1", "ns1", "key2", cacheEntry{version: ver, rev: "1-b", versionOnly: true}, 0)
+	c.put("ch1", "ns1", "key2", value, 0)
+	entry, _ = c.get("ch1", "ns1", "key2")
+	assert.Equal(t, value, entry)
+}
+
+func TestStateCacheEviction(t *testing.T) {
+	entrySize := cacheEntryOverhead + len("ch1") + len("ns1") + len("key1") + len("value1")
+	c := newStateCache(3 * entrySize)
+	value := func(v string) cacheEntry {
+		return cacheEntry{value: &statedb.VersionedValue{Value: []byte(v)}}
+	}
+	c.put("ch1", "ns1", "key1", value("value1"), 0)
+	c.put("ch1", "ns1", "key2", value("value2"), 0)
+	c.put("ch1", "ns1", "key3", value("value3"), 0)
+	// key1 becomes the most recently used
+	_, ok := c.get("ch1", "ns1", "key1")
+	assert.True(t, ok)
+
+	c.put("ch1", "ns1", "key4", value("value4"), 0)
+	_, ok = c.get("ch1", "ns1", "key2")
+	assert.False(t, ok)
+	for _, key := range []string{"key1", "key3", "key4"} {
+		_, ok = c.get("ch1", "ns1", key)
+		assert.True(t, ok)
+	}
+	assert.Equal(t, 3*entrySize, c.size)
+
+	// an entry larger than the cache is not cached
+	c.put("ch1", "ns1", "key5", value(string(make([]byte, 3*entrySize))), 0)
+	_, ok = c.get("ch1", "ns1", "key5")
+	assert.False(t, ok)
+	assert.Len(t, c.elements, 3)
+}
+
+func TestStateCacheInvalidation(t *testing.T) {
+	c := newStateCache(1024 * 1024)
+	c.put("ch1", "ns1", "key1", cacheEntry{rev: "1-a"}, 0)
+	c.put("ch1", "ns1", "key2", cacheEntry{rev: "1-b"}, 0)
+	c.put("ch1", "ns2", "key1", cacheEntry{rev: "1-c"}, 0)
+
+	updates := statedb.NewUpdateBatch()
+	updates.Put("ns1", "key1", []byte("value1"), version.NewHeight(2, 1))
+	generation := c.generation("ch1", "ns1")
+	c.startUpdate("ch1", updates)
+	_, ok := c.get("ch1", "ns1", "key1")
+	assert.False(t, ok)
+	// the other keys are still cached
+	_, ok = c.get("ch1", "ns1", "key2")
+	assert.True(t, ok)
+	_, ok = c.get("ch1", "ns2", "key1")
+	assert.True(t, ok)
+
+	// the values read before or during the update are not cached
+	c.put("ch1", "ns1", "key1", cacheEntry{rev: "1-a"}, generation)
+	c.put("ch1", "ns1", "key1", cacheEntry{rev: "1-a"}, c.generation("ch1", "ns1"))
+	_, ok = c.get("ch1", "ns1", "key1")
+	assert.False(t, ok)
+	// the other namespaces are cached
+	c.put("ch1", "ns2", "key2", cacheEntry{}, c.generation("ch1", "ns2"))
+	_, ok = c.get("ch1", "ns2", "key2")
+	assert.True(t, ok)
+
+	c.endUpdate("ch1", updates)
+	c.put("ch1", "ns1", "key1", cacheEntry{rev: "2-a"}, c.generation("ch1", "ns1"))
+	entry, ok := c.get("ch1", "ns1", "key1")
+	assert.True(t, ok)
+	assert.Equal(t, "2-a", entry.rev)
+}
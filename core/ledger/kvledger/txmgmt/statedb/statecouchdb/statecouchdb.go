@@ -9,6 +9,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/hyperledger/fabric/common/flogging"
@@ -32,18 +33,26 @@ type VersionedDBProvider struct {
 	databases     map[string]*VersionedDB
 	mux           sync.Mutex
 	openCounts    uint64
+	cache         *stateCache
 }
 
 // NewVersionedDBProvider instantiates VersionedDBProvider
 func NewVersionedDBProvider() (*VersionedDBProvider, error) {
 	logger.Debugf("constructing CouchDB VersionedDBProvider")
-	couchDBDef := couchdb.GetCouchDBDefinition()
-	couchInstance, err := couchdb.CreateCouchInstance(couchDBDef.URL, couchDBDef.Username, couchDBDef.Password,
-		couchDBDef.MaxRetries, couchDBDef.MaxRetriesOnStartup, couchDBDef.RequestTimeout, couchDBDef.CreateGlobalChangesDB)
+	couchDBDef, err := couchdb.GetCouchDBDefinition()
 	if err != nil {
 		return nil, err
 	}
-	return &VersionedDBProvider{couchInstance, make(map[string]*VersionedDB), sync.Mutex{}, 0}, nil
+	couchInstance, err := couchdb.CreateCouchInstanceFromDefinition(couchDBDef)
+	if err != nil {
+		return nil, err
+	}
+	cacheSize := ledgerconfig.GetStateCacheSize() * 1024 * 1024
+	if ledgerconfig.IsReadReplica() {
+		// the shared db is updated by the committer, which can't invalidate the cache
+		cacheSize = 0
+	}
+	return &VersionedDBProvider{couchInstance, make(map[string]*VersionedDB), sync.Mutex{}, 0, newStateCache(cacheSize)}, nil
 }
 
 // GetDBHandle gets the handle to a named database
@@ -53,7 +62,7 @@ func (provider *VersionedDBProvider) GetDBHandle(dbName string) (statedb.Version
 	vdb := provider.databases[dbName]
 	if vdb == nil {
 		var err error
-		vdb, err = newVersionedDB(provider.couchInstance, dbName)
+		vdb, err = newVersionedDB(provider.couchInstance, dbName, provider.cache)
 		if err != nil {
 			return nil, err
 		}
@@ -69,20 +78,22 @@ func (provider *VersionedDBProvider) Close() {
 
 // VersionedDB implements VersionedDB interface
 type VersionedDB struct {
-	couchInstance      *couchdb.CouchInstance
-	metadataDB         *couchdb.CouchDatabase            // A database per channel to store metadata such as savepoint.
-	chainName          string                            // The name of the chain/channel.
-	namespaceDBs       map[string]*couchdb.CouchDatabase // One database per deployed chaincode.
-	committedDataCache *versionsCache                    // Used as a local cache during bulk processing of a block.
-	verCacheLock       sync.RWMutex
-	mux                sync.RWMutex
+	couchInstance       *couchdb.CouchInstance
+	metadataDB          *couchdb.CouchDatabase            // A database per channel to store metadata such as savepoint.
+	chainName           string                            // The name of the chain/channel.
+	namespaceDBs        map[string]*couchdb.CouchDatabase // One database per deployed chaincode.
+	committedDataCache  *versionsCache                    // Used as a local cache during bulk processing of a block.
+	verCacheLock        sync.RWMutex
+	mux                 sync.RWMutex
+	publishCommitMarker bool        // Whether the db is shared with read replicas.
+	cache               *stateCache // The cache of the values and revisions, shared by the channels.
 }
 
 // newVersionedDB constructs an instance of VersionedDB
-func newVersionedDB(couchInstance *couchdb.CouchInstance, dbName string) (*VersionedDB, error) {
+func newVersionedDB(couchInstance *couchdb.CouchInstance, dbName string, cache *stateCache) (*VersionedDB, error) {
 	// CreateCouchDatabase creates a CouchDB database object, as well as the underlying database if it does not exist
 	chainName := dbName
-	dbName = couchdb.ConstructMetadataDBName(dbName)
+	dbName = couchdb.ConstructMetadataDBName(couchInstance.DatabaseNamePrefix() + dbName)
 
 	metadataDB, err := couchdb.CreateCouchDatabase(couchInstance, dbName)
 	if err != nil {
@@ -90,7 +101,8 @@ func newVersionedDB(couchInstance *couchdb.CouchInstance, dbName string) (*Versi
 	}
 	namespaceDBMap := make(map[string]*couchdb.CouchDatabase)
 	return &VersionedDB{couchInstance: couchInstance, metadataDB: metadataDB, chainName: chainName, namespaceDBs: namespaceDBMap,
-		committedDataCache: newVersionCache(), mux: sync.RWMutex{}}, nil
+		committedDataCache: newVersionCache(), mux: sync.RWMutex{},
+		publishCommitMarker: ledgerconfig.IsReplicationCommitter(), cache: cache}, nil
 }
 
 // getNamespaceDBHandle gets the handle to a named chaincode database
@@ -101,7 +113,7 @@ func (vdb *VersionedDB) getNamespaceDBHandle(namespace string) (*couchdb.CouchDa
 	if db != nil {
 		return db, nil
 	}
-	namespaceDBName := couchdb.ConstructNamespaceDBName(vdb.chainName, namespace)
+	namespaceDBName := couchdb.ConstructNamespaceDBName(vdb.couchInstance.DatabaseNamePrefix()+vdb.chainName, namespace)
 	vdb.mux.Lock()
 	defer vdb.mux.Unlock()
 	db = vdb.namespaceDBs[namespace]
@@ -127,32 +139,142 @@ func (vdb *VersionedDB) ProcessIndexesForChaincodeDeploy(namespace string, fileE
 	for _, fileEntry := range fileEntries {
 		indexData := fileEntry.FileContent
 		filename := fileEntry.FileHeader.Name
-		_, err = db.CreateIndex(string(indexData))
+		resp, err := db.CreateIndex(string(indexData))
 		if err != nil {
 			return errors.WithMessage(err, fmt.Sprintf(
 				"error creating index from file [%s] for channel [%s]", filename, namespace))
 		}
+		// build the index right away, rather than on the first query using it
+		warmIndex(db, resp)
 	}
 
 	return nil
 
 }
 
+// ListIndexes returns the indexes of the database of the namespace
+func (vdb *VersionedDB) ListIndexes(namespace string) ([]*statedb.Index, error) {
+	db, err := vdb.getNamespaceDBHandle(namespace)
+	if err != nil {
+		return nil, err
+	}
+	indexes, err := db.ListIndex()
+	if err != nil {
+		return nil, err
+	}
+	var results []*statedb.Index
+	for _, index := range indexes {
+		results = append(results, &statedb.Index{DesignDocument: index.DesignDocument, Name: index.Name, Definition: index.Definition})
+	}
+	return results, nil
+}
+
+// CreateIndex creates an index in the database of the namespace and warms it
+func (vdb *VersionedDB) CreateIndex(namespace string, definition []byte) error {
+	db, err := vdb.getNamespaceDBHandle(namespace)
+	if err != nil {
+		return err
+	}
+	resp, err := db.CreateIndex(string(definition))
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("error creating index for namespace [%s]", namespace))
+	}
+	warmIndex(db, resp)
+	return nil
+}
+
+// RebuildIndexes drops and re-creates the indexes of the database of the
+// namespace, and warms them
+func (vdb *VersionedDB) RebuildIndexes(namespace string) error {
+	db, err := vdb.getNamespaceDBHandle(namespace)
+	if err != nil {
+		return err
+	}
+	indexes, err := db.ListIndex()
+	if err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		definition, err := json.Marshal(map[string]interface{}{
+			"index": json.RawMessage(index.Definition),
+			"ddoc":  index.DesignDocument,
+			"name":  index.Name,
+			"type":  "json",
+		})
+		if err != nil {
+			return errors.Wrapf(err, "error encoding index [%s] for namespace [%s]", index.Name, namespace)
+		}
+		if err := db.DeleteIndex(index.DesignDocument, index.Name); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("error dropping index [%s] for namespace [%s]", index.Name, namespace))
+		}
+		resp, err := db.CreateIndex(string(definition))
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("error re-creating index [%s] for namespace [%s]", index.Name, namespace))
+		}
+		warmIndex(db, resp)
+	}
+	logger.Infof("Rebuilt %d indexes for namespace [%s]", len(indexes), namespace)
+	return nil
+}
+
+// warmIndex triggers the build of a created index, whose failure only delays
+// the build until the first query using the index
+func warmIndex(db *couchdb.CouchDatabase, resp *couchdb.CreateIndexResponse) {
+	designDoc := strings.TrimPrefix(resp.ID, "_design/")
+	if err := db.WarmIndex(designDoc, resp.Name); err != nil {
+		logger.Warningf("Failed warming index [%s] of database [%s]: %s", resp.Name, db.DBName, err)
+	}
+}
+
 func (vdb *VersionedDB) GetDBType() string {
 	return "couchdb"
 }
 
+// ApproximateSize implements method in SizeCapable interface. It sums the
+// sizes of the metadata database of the channel and of the databases of the
+// namespaces used since the peer started
+func (vdb *VersionedDB) ApproximateSize() (int64, error) {
+	vdb.mux.RLock()
+	dbs := []*couchdb.CouchDatabase{vdb.metadataDB}
+	for _, db := range vdb.namespaceDBs {
+		dbs = append(dbs, db)
+	}
+	vdb.mux.RUnlock()
+
+	var size int64
+	for _, db := range dbs {
+		info, _, err := db.GetDatabaseInfo()
+		if err != nil {
+			return 0, err
+		}
+		if info.Sizes.File > 0 {
+			size += int64(info.Sizes.File)
+		} else {
+			size += int64(info.DiskSize)
+		}
+	}
+	return size, nil
+}
+
 // LoadCommittedVersions populates committedVersions and revisionNumbers into cache.
 // A bulk retrieve from couchdb is used to populate the cache.
 // committedVersions cache will be used for state validation of readsets
 // revisionNumbers cache will be used during commit phase for couchdb bulk updates
 func (vdb *VersionedDB) LoadCommittedVersions(keys []*statedb.CompositeKey) error {
 	nsKeysMap := map[string][]string{}
+	nsGenerations := map[string]uint64{}
 	committedDataCache := newVersionCache()
 	for _, compositeKey := range keys {
 		ns, key := compositeKey.Namespace, compositeKey.Key
+		if entry, ok := vdb.cache.get(vdb.chainName, ns, key); ok {
+			committedDataCache.setVerAndRev(ns, key, entry.version, entry.rev)
+			continue
+		}
 		committedDataCache.setVerAndRev(ns, key, nil, "")
 		logger.Debugf("Load into version cache: %s~%s", ns, key)
+		if _, ok := nsGenerations[ns]; !ok {
+			nsGenerations[ns] = vdb.cache.generation(vdb.chainName, ns)
+		}
 		nsKeysMap[ns] = append(nsKeysMap[ns], key)
 	}
 	nsMetadataMap, err := vdb.retrieveMetadata(nsKeysMap)
@@ -162,7 +284,9 @@ func (vdb *VersionedDB) LoadCommittedVersions(keys []*statedb.CompositeKey) erro
 		return err
 	}
 	for ns, nsMetadata := range nsMetadataMap {
+		retrieved := make(map[string]bool, len(nsMetadata))
 		for _, keyMetadata := range nsMetadata {
+			retrieved[keyMetadata.ID] = true
 			// TODO - why would version be ever zero if loaded from db?
 			if len(keyMetadata.Version) != 0 {
 				version, _, err := decodeVersionAndMetadata(keyMetadata.Version)
@@ -170,6 +294,14 @@ func (vdb *VersionedDB) LoadCommittedVersions(keys []*statedb.CompositeKey) erro
 					return err
 				}
 				committedDataCache.setVerAndRev(ns, keyMetadata.ID, version, keyMetadata.Rev)
+				entry := cacheEntry{version: version, rev: keyMetadata.Rev, versionOnly: true}
+				vdb.cache.put(vdb.chainName, ns, keyMetadata.ID, entry, nsGenerations[ns])
+			}
+		}
+		// the keys not retrieved don't exist
+		for _, key := range nsKeysMap[ns] {
+			if !retrieved[key] {
+				vdb.cache.put(vdb.chainName, ns, key, cacheEntry{}, nsGenerations[ns])
 			}
 		}
 	}
@@ -219,21 +351,27 @@ func (vdb *VersionedDB) BytesKeySuppoted() bool {
 // GetState implements method in VersionedDB interface
 func (vdb *VersionedDB) GetState(namespace string, key string) (*statedb.VersionedValue, error) {
 	logger.Debugf("GetState(). ns=%s, key=%s", namespace, key)
+	if entry, ok := vdb.cache.get(vdb.chainName, namespace, key); ok && !entry.versionOnly {
+		return entry.value, nil
+	}
+	generation := vdb.cache.generation(vdb.chainName, namespace)
 	db, err := vdb.getNamespaceDBHandle(namespace)
 	if err != nil {
 		return nil, err
 	}
-	couchDoc, _, err := db.ReadDoc(key)
+	couchDoc, rev, err := db.ReadDoc(key)
 	if err != nil {
 		return nil, err
 	}
 	if couchDoc == nil {
+		vdb.cache.put(vdb.chainName, namespace, key, cacheEntry{}, generation)
 		return nil, nil
 	}
 	kv, err := couchDocToKeyValue(couchDoc)
 	if err != nil {
 		return nil, err
 	}
+	vdb.cache.put(vdb.chainName, namespace, key, cacheEntry{value: kv.VersionedValue, version: kv.Version, rev: rev}, generation)
 	return kv.VersionedValue, nil
 }
 
@@ -375,11 +513,14 @@ func (scanner *queryScanner) executeQueryWithBookmark() error {
 		return err
 	}
 
-	queryResult, bookmark, err := scanner.db.QueryDocuments(queryString)
+	queryResult, bookmark, warning, err := scanner.db.QueryDocumentsWithWarning(queryString)
 	if err != nil {
-		logger.Debugf("Error calling QueryDocuments(): %s\n", err.Error())
+		logger.Debugf("Error calling QueryDocumentsWithWarning(): %s\n", err.Error())
 		return err
 	}
+	if warning != "" {
+		scanner.resultsInfo.warning = warning
+	}
 
 	scanner.resultsInfo.results = queryResult
 	scanner.paginationInfo.bookmark = bookmark
@@ -419,6 +560,14 @@ func (vdb *VersionedDB) ApplyUpdates(updates *statedb.UpdateBatch, height *versi
 	// the function `Apply update can be split into three functions. Each carrying out one of the following three stages`.
 	// The write lock is needed only for the stage 2.
 
+	// the read replicas sharing the db are told that a batch is being applied before any
+	// change is pushed to the DB
+	if vdb.publishCommitMarker {
+		if err := vdb.recordCommitMarker(height, true); err != nil {
+			return err
+		}
+	}
+
 	// stage 1 - PrepareForUpdates - db transforms the given batch in the form of underlying db
 	// and keep it in memory
 	var updateBatches []batch
@@ -427,6 +576,8 @@ func (vdb *VersionedDB) ApplyUpdates(updates *statedb.UpdateBatch, height *versi
 		return err
 	}
 	// stage 2 - ApplyUpdates push the changes to the DB
+	vdb.cache.startUpdate(vdb.chainName, updates)
+	defer vdb.cache.endUpdate(vdb.chainName, updates)
 	if err = executeBatches(updateBatches); err != nil {
 		return err
 	}
@@ -438,6 +589,9 @@ func (vdb *VersionedDB) ApplyUpdates(updates *statedb.UpdateBatch, height *versi
 		logger.Errorf("Error during recordSavepoint: %s", err.Error())
 		return err
 	}
+	if vdb.publishCommitMarker {
+		return vdb.recordCommitMarker(height, false)
+	}
 	return nil
 }
 
@@ -580,12 +734,13 @@ type paginationInfo struct {
 type resultsInfo struct {
 	totalRecordsReturned int32
 	results              []*couchdb.QueryResult
+	warning              string
 }
 
 func newQueryScanner(namespace string, db *couchdb.CouchDatabase, query string, internalQueryLimit,
 	limit int32, bookmark, startKey, endKey string) (*queryScanner, error) {
 
-	scanner := &queryScanner{namespace, db, &queryDefinition{startKey, endKey, query, internalQueryLimit}, &paginationInfo{-1, limit, bookmark}, &resultsInfo{0, nil}}
+	scanner := &queryScanner{namespace, db, &queryDefinition{startKey, endKey, query, internalQueryLimit}, &paginationInfo{-1, limit, bookmark}, &resultsInfo{0, nil, ""}}
 	var err error
 
 	// query is defined, then execute the query and return the records and bookmark
@@ -659,6 +814,12 @@ func (scanner *queryScanner) Close() {
 	scanner = nil
 }
 
+// GetWarning returns the warning of CouchDB about the execution of the query,
+// such as the lack of an index matching its selector
+func (scanner *queryScanner) GetWarning() string {
+	return scanner.resultsInfo.warning
+}
+
 func (scanner *queryScanner) GetBookmarkAndClose() string {
 	retval := ""
 	if scanner.queryDefinition.query != "" {
@@ -407,6 +407,97 @@ func TestIsBulkOptimizable(t *testing.T) {
 	}
 }
 
+func TestIsNotSnapshotCapable(t *testing.T) {
+	// CouchDB cannot serve the documents as of a past state
+	var db statedb.VersionedDB = &VersionedDB{}
+	_, ok := db.(statedb.SnapshotCapable)
+	assert.False(t, ok)
+}
+
+func TestCommitMarker(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+
+	db, err := env.DBProvider.GetDBHandle("testcommitmarker")
+	assert.NoError(t, err)
+	marker, err := db.(statedb.ReplicationCapable).GetCommitMarker()
+	assert.NoError(t, err)
+	assert.Nil(t, marker)
+
+	// markers are only published by the committers
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	assert.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 1)))
+	marker, err = db.(statedb.ReplicationCapable).GetCommitMarker()
+	assert.NoError(t, err)
+	assert.Nil(t, marker)
+
+	db.(*VersionedDB).publishCommitMarker = true
+	assert.NoError(t, db.(*VersionedDB).recordCommitMarker(version.NewHeight(2, 1), true))
+	marker, err = db.(statedb.ReplicationCapable).GetCommitMarker()
+	assert.NoError(t, err)
+	assert.Equal(t, &statedb.CommitMarker{Height: version.NewHeight(2, 1), Committing: true}, marker)
+
+	batch = statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value2"), version.NewHeight(2, 1))
+	assert.NoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 1)))
+	marker, err = db.(statedb.ReplicationCapable).GetCommitMarker()
+	assert.NoError(t, err)
+	assert.Equal(t, &statedb.CommitMarker{Height: version.NewHeight(2, 1)}, marker)
+}
+
+func TestStateCache(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+
+	db, err := env.DBProvider.GetDBHandle("teststatecache")
+	assert.NoError(t, err)
+	vdb := db.(*VersionedDB)
+	assert.NotNil(t, vdb.cache)
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	assert.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 1)))
+
+	// the reads of the simulations populate the cache
+	vv, err := db.GetState("ns1", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value1"), vv.Value)
+	entry, ok := vdb.cache.get("teststatecache", "ns1", "key1")
+	assert.True(t, ok)
+	assert.Equal(t, vv, entry.value)
+	assert.NotEmpty(t, entry.rev)
+	vv, err = db.GetState("ns1", "key2")
+	assert.NoError(t, err)
+	assert.Nil(t, vv)
+	_, ok = vdb.cache.get("teststatecache", "ns1", "key2")
+	assert.True(t, ok)
+
+	// the validation reads the versions from the cache
+	keys := []*statedb.CompositeKey{{Namespace: "ns1", Key: "key1"}, {Namespace: "ns1", Key: "key3"}}
+	assert.NoError(t, vdb.LoadCommittedVersions(keys))
+	ver, ok := vdb.GetCachedVersion("ns1", "key1")
+	assert.True(t, ok)
+	assert.Equal(t, version.NewHeight(1, 1), ver)
+	_, ok = vdb.cache.get("teststatecache", "ns1", "key3")
+	assert.True(t, ok)
+
+	// the committed write sets invalidate the cache, and the cached
+	// revisions are used to update the keys written without being read
+	vdb.ClearCachedVersions()
+	batch = statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value2"), version.NewHeight(2, 1))
+	batch.Put("ns1", "key2", []byte("value3"), version.NewHeight(2, 1))
+	assert.NoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 1)))
+	_, ok = vdb.cache.get("teststatecache", "ns1", "key1")
+	assert.False(t, ok)
+	vv, err = db.GetState("ns1", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value2"), vv.Value)
+	vv, err = db.GetState("ns1", "key2")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value3"), vv.Value)
+}
+
 func printCompositeKeys(keys []*statedb.CompositeKey) string {
 
 	compositeKeyString := []string{}
@@ -86,6 +86,61 @@ type BulkOptimizable interface {
 type IndexCapable interface {
 	GetDBType() string
 	ProcessIndexesForChaincodeDeploy(namespace string, fileEntries []*ccprovider.TarFileEntry) error
+	// ListIndexes returns the indexes of the database of the namespace
+	ListIndexes(namespace string) ([]*Index, error)
+	// CreateIndex creates an index in the database of the namespace and warms it
+	CreateIndex(namespace string, definition []byte) error
+	// RebuildIndexes drops and re-creates the indexes of the database of the namespace
+	RebuildIndexes(namespace string) error
+}
+
+// Index describes an index of the database of a namespace
+type Index struct {
+	DesignDocument string
+	Name           string
+	Definition     string
+}
+
+//SnapshotCapable interface provides additional functions for
+//databases capable of serving reads from a consistent snapshot
+type SnapshotCapable interface {
+	// GetSnapshot returns a read-only VersionedDB presenting the state of the db as of the call,
+	// while the updates are applied concurrently to the db. The updates cannot be applied to the
+	// returned VersionedDB and its Close function releases the snapshot.
+	GetSnapshot() (VersionedDB, error)
+}
+
+//FullScanCapable interface provides additional functions for
+//databases capable of iterating over the keys of all their namespaces
+type FullScanCapable interface {
+	// GetFullScanIterator returns an iterator over the keys of all the namespaces of the db, skipping
+	// the namespaces for which skipNamespace returns true. The keys of a namespace are returned in
+	// order, and the returned ResultsIterator contains results of type *VersionedKV
+	GetFullScanIterator(skipNamespace func(namespace string) bool) (ResultsIterator, error)
+}
+
+//SizeCapable interface provides additional functions for
+//databases capable of reporting their size
+type SizeCapable interface {
+	// ApproximateSize returns the approximate size in bytes of the db on disk
+	ApproximateSize() (int64, error)
+}
+
+//ReplicationCapable interface provides additional functions for
+//databases capable of being shared by a committing peer with read replicas
+type ReplicationCapable interface {
+	// GetCommitMarker returns the marker of the last batch of updates whose commit was started
+	// by the committing peer, or nil if the committing peer never published one
+	GetCommitMarker() (*CommitMarker, error)
+}
+
+// CommitMarker is published in a shared db by the committing peer around the commit of each
+// batch of updates, so that the read replicas detect the reads overlapping a commit
+type CommitMarker struct {
+	// Height is the height of the last batch of updates whose commit was started
+	Height *version.Height
+	// Committing is whether the commit of the batch of updates is in progress
+	Committing bool
 }
 
 // CompositeKey encloses Namespace and Key components
@@ -124,6 +179,14 @@ type QueryResultsIterator interface {
 	GetBookmarkAndClose() string
 }
 
+// QueryWarner is implemented by the query iterators of the databases reporting
+// warnings about the execution of the queries
+type QueryWarner interface {
+	// GetWarning returns the warning about the execution of the query, such as
+	// the lack of an index matching its selector, or an empty string
+	GetWarning() string
+}
+
 // QueryResult - a general interface for supporting different types of query results. Actual types differ for different queries
 type QueryResult interface{}
 
@@ -46,15 +46,42 @@ func (provider *VersionedDBProvider) Close() {
 	provider.dbProvider.Close()
 }
 
+// dbReader reads the keys of a named leveldb, either from the
+// db itself or from one of its snapshots
+type dbReader interface {
+	Get(key []byte) ([]byte, error)
+	GetIterator(startKey []byte, endKey []byte) *leveldbhelper.Iterator
+}
+
 // VersionedDB implements VersionedDB interface
 type versionedDB struct {
 	db     *leveldbhelper.DBHandle
 	dbName string
+	reader dbReader
+	// snapshot is set when the versionedDB presents a snapshot of the db
+	snapshot *leveldbhelper.Snapshot
 }
 
 // newVersionedDB constructs an instance of VersionedDB
 func newVersionedDB(db *leveldbhelper.DBHandle, dbName string) *versionedDB {
-	return &versionedDB{db, dbName}
+	return &versionedDB{db: db, dbName: dbName, reader: db}
+}
+
+// GetSnapshot implements method in SnapshotCapable interface
+func (vdb *versionedDB) GetSnapshot() (statedb.VersionedDB, error) {
+	if vdb.snapshot != nil {
+		return nil, errors.New("a snapshot cannot be taken from a snapshot")
+	}
+	snapshot, err := vdb.db.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &versionedDB{db: vdb.db, dbName: vdb.dbName, reader: snapshot, snapshot: snapshot}, nil
+}
+
+// ApproximateSize implements method in SizeCapable interface
+func (vdb *versionedDB) ApproximateSize() (int64, error) {
+	return vdb.db.ApproximateSize()
 }
 
 // Open implements method in VersionedDB interface
@@ -65,7 +92,10 @@ func (vdb *versionedDB) Open() error {
 
 // Close implements method in VersionedDB interface
 func (vdb *versionedDB) Close() {
-	// do nothing because shared db is used
+	// only the snapshot is released because shared db is used
+	if vdb.snapshot != nil {
+		vdb.snapshot.Release()
+	}
 }
 
 // ValidateKeyValue implements method in VersionedDB interface
@@ -82,7 +112,7 @@ func (vdb *versionedDB) BytesKeySuppoted() bool {
 func (vdb *versionedDB) GetState(namespace string, key string) (*statedb.VersionedValue, error) {
 	logger.Debugf("GetState(). ns=%s, key=%s", namespace, key)
 	compositeKey := constructCompositeKey(namespace, key)
-	dbVal, err := vdb.db.Get(compositeKey)
+	dbVal, err := vdb.reader.Get(compositeKey)
 	if err != nil {
 		return nil, err
 	}
@@ -148,7 +178,7 @@ func (vdb *versionedDB) GetStateRangeScanIteratorWithMetadata(namespace string,
 	if endKey == "" {
 		compositeEndKey[len(compositeEndKey)-1] = lastKeyIndicator
 	}
-	dbItr := vdb.db.GetIterator(compositeStartKey, compositeEndKey)
+	dbItr := vdb.reader.GetIterator(compositeStartKey, compositeEndKey)
 
 	return newKVScanner(namespace, dbItr, requestedLimit), nil
 
@@ -164,8 +194,16 @@ func (vdb *versionedDB) ExecuteQueryWithMetadata(namespace, query string, metada
 	return nil, errors.New("ExecuteQueryWithMetadata not supported for leveldb")
 }
 
+// GetFullScanIterator implements method in FullScanCapable interface
+func (vdb *versionedDB) GetFullScanIterator(skipNamespace func(string) bool) (statedb.ResultsIterator, error) {
+	return &fullScanner{vdb.reader.GetIterator(nil, nil), skipNamespace}, nil
+}
+
 // ApplyUpdates implements method in VersionedDB interface
 func (vdb *versionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version.Height) error {
+	if vdb.snapshot != nil {
+		return errors.New("updates cannot be applied to a snapshot")
+	}
 	dbBatch := leveldbhelper.NewUpdateBatch()
 	namespaces := batch.GetUpdatedNamespaces()
 	for _, ns := range namespaces {
@@ -195,7 +233,7 @@ func (vdb *versionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version
 
 // GetLatestSavePoint implements method in VersionedDB interface
 func (vdb *versionedDB) GetLatestSavePoint() (*version.Height, error) {
-	versionBytes, err := vdb.db.Get(savePointKey)
+	versionBytes, err := vdb.reader.Get(savePointKey)
 	if err != nil {
 		return nil, err
 	}
@@ -269,3 +307,38 @@ func (scanner *kvScanner) GetBookmarkAndClose() string {
 	scanner.Close()
 	return retval
 }
+
+// fullScanner iterates over the keys of all the namespaces of the db, the
+// save point, whose key belongs to no namespace, being skipped
+type fullScanner struct {
+	dbItr         iterator.Iterator
+	skipNamespace func(string) bool
+}
+
+func (scanner *fullScanner) Next() (statedb.QueryResult, error) {
+	for scanner.dbItr.Next() {
+		dbKey := scanner.dbItr.Key()
+		if bytes.Equal(dbKey, savePointKey) {
+			continue
+		}
+		ns, key := splitCompositeKey(dbKey)
+		if scanner.skipNamespace != nil && scanner.skipNamespace(ns) {
+			continue
+		}
+		dbVal := scanner.dbItr.Value()
+		dbValCopy := make([]byte, len(dbVal))
+		copy(dbValCopy, dbVal)
+		vv, err := decodeValue(dbValCopy)
+		if err != nil {
+			return nil, err
+		}
+		return &statedb.VersionedKV{
+			CompositeKey:   statedb.CompositeKey{Namespace: ns, Key: key},
+			VersionedValue: *vv}, nil
+	}
+	return nil, nil
+}
+
+func (scanner *fullScanner) Close() {
+	scanner.dbItr.Release()
+}
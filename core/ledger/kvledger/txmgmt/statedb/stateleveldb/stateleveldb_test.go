@@ -130,3 +130,107 @@ func TestPaginatedRangeQuery(t *testing.T) {
 	defer env.Cleanup()
 	commontests.TestPaginatedRangeQuery(t, env.DBProvider)
 }
+
+func TestSnapshot(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+
+	db, err := env.DBProvider.GetDBHandle("testsnapshot")
+	assert.NoError(t, err)
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	batch.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 2))
+	batch.Put("ns1", "key3", []byte("value3"), version.NewHeight(1, 3))
+	assert.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 3)))
+
+	snapshot, err := db.(statedb.SnapshotCapable).GetSnapshot()
+	assert.NoError(t, err)
+	defer snapshot.Close()
+	itr, err := snapshot.GetStateRangeScanIterator("ns1", "", "")
+	assert.NoError(t, err)
+	defer itr.Close()
+
+	// a block committed while the iterator is open is not visible through the snapshot
+	batch = statedb.NewUpdateBatch()
+	batch.Put("ns1", "key2", []byte("value2-new"), version.NewHeight(2, 1))
+	batch.Delete("ns1", "key3", version.NewHeight(2, 2))
+	batch.Put("ns1", "key4", []byte("value4"), version.NewHeight(2, 3))
+	assert.NoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 3)))
+
+	var keys []string
+	for {
+		result, err := itr.Next()
+		assert.NoError(t, err)
+		if result == nil {
+			break
+		}
+		kv := result.(*statedb.VersionedKV)
+		keys = append(keys, kv.Key)
+		if kv.Key == "key2" {
+			assert.Equal(t, []byte("value2"), kv.Value)
+		}
+	}
+	assert.Equal(t, []string{"key1", "key2", "key3"}, keys)
+
+	vv, err := snapshot.GetState("ns1", "key2")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value2"), vv.Value)
+	vv, err = db.GetState("ns1", "key2")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value2-new"), vv.Value)
+
+	savepoint, err := snapshot.GetLatestSavePoint()
+	assert.NoError(t, err)
+	assert.Equal(t, version.NewHeight(1, 3), savepoint)
+
+	assert.EqualError(t, snapshot.ApplyUpdates(statedb.NewUpdateBatch(), version.NewHeight(3, 1)), "updates cannot be applied to a snapshot")
+	_, err = snapshot.(statedb.SnapshotCapable).GetSnapshot()
+	assert.EqualError(t, err, "a snapshot cannot be taken from a snapshot")
+}
+
+func TestFullScanIterator(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+
+	db, err := env.DBProvider.GetDBHandle("testfullscan")
+	assert.NoError(t, err)
+	otherDB, err := env.DBProvider.GetDBHandle("testfullscanother")
+	assert.NoError(t, err)
+	batch := statedb.NewUpdateBatch()
+	batch.PutValAndMetadata("ns1", "key1", []byte("value1"), []byte("metadata1"), version.NewHeight(1, 1))
+	batch.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 2))
+	batch.Put("ns2", "key1", []byte("value3"), version.NewHeight(1, 3))
+	batch.Put("ns3", "key1", []byte("value4"), version.NewHeight(1, 4))
+	assert.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 4)))
+	batch = statedb.NewUpdateBatch()
+	batch.Put("ns1", "key3", []byte("value5"), version.NewHeight(1, 1))
+	assert.NoError(t, otherDB.ApplyUpdates(batch, version.NewHeight(1, 1)))
+
+	itr, err := db.(statedb.FullScanCapable).GetFullScanIterator(func(ns string) bool { return ns == "ns2" })
+	assert.NoError(t, err)
+	defer itr.Close()
+	var kvs []*statedb.VersionedKV
+	for {
+		result, err := itr.Next()
+		assert.NoError(t, err)
+		if result == nil {
+			break
+		}
+		kvs = append(kvs, result.(*statedb.VersionedKV))
+	}
+	// the save point and the keys of the other db and of the skipped namespace are not returned
+	assert.Equal(t, []*statedb.VersionedKV{
+		{
+			CompositeKey:   statedb.CompositeKey{Namespace: "ns1", Key: "key1"},
+			VersionedValue: statedb.VersionedValue{Value: []byte("value1"), Metadata: []byte("metadata1"), Version: version.NewHeight(1, 1)},
+		},
+		{
+			CompositeKey:   statedb.CompositeKey{Namespace: "ns1", Key: "key2"},
+			VersionedValue: statedb.VersionedValue{Value: []byte("value2"), Version: version.NewHeight(1, 2)},
+		},
+		{
+			CompositeKey:   statedb.CompositeKey{Namespace: "ns3", Key: "key1"},
+			VersionedValue: statedb.VersionedValue{Value: []byte("value4"), Version: version.NewHeight(1, 4)},
+		},
+	}, kvs)
+}
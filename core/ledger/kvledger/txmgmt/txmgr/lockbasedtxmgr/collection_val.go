@@ -44,6 +44,12 @@ func (v *collNameValidator) validateCollName(ns, coll string) error {
 	return nil
 }
 
+// valueCommitment returns the value commitment scheme of a collection validated
+// with validateCollName
+func (v *collNameValidator) valueCommitment(ns, coll string) string {
+	return v.cache.valueCommitment(ns, coll)
+}
+
 func (v *collNameValidator) retrieveCollConfigFromStateDB(ns string) (*common.CollectionConfigPackage, error) {
 	logger.Debugf("retrieveCollConfigFromStateDB() begin - ns=[%s]", ns)
 	configPkgBytes, _, err := v.queryHelper.getState(lsccNamespace, constructCollectionConfigKey(ns))
@@ -61,7 +67,7 @@ func (v *collNameValidator) retrieveCollConfigFromStateDB(ns string) (*common.Co
 	return confPkg, nil
 }
 
-type collConfigCache map[collConfigkey]bool
+type collConfigCache map[collConfigkey]*common.StaticCollectionConfig
 
 type collConfigkey struct {
 	ns, coll string
@@ -70,22 +76,26 @@ type collConfigkey struct {
 func (c collConfigCache) populate(ns string, pkg *common.CollectionConfigPackage) {
 	// an entry with an empty collection name to indicate that the cache is populated for the namespace 'ns'
 	// see function 'isPopulatedFor'
-	c[collConfigkey{ns, ""}] = true
+	c[collConfigkey{ns, ""}] = &common.StaticCollectionConfig{}
 	for _, config := range pkg.Config {
 		sConfig := config.GetStaticCollectionConfig()
 		if sConfig == nil {
 			continue
 		}
-		c[collConfigkey{ns, sConfig.Name}] = true
+		c[collConfigkey{ns, sConfig.Name}] = sConfig
 	}
 }
 
 func (c collConfigCache) isPopulatedFor(ns string) bool {
-	return c[collConfigkey{ns, ""}]
+	return c[collConfigkey{ns, ""}] != nil
 }
 
 func (c collConfigCache) containsCollName(ns, coll string) bool {
-	return c[collConfigkey{ns, coll}]
+	return c[collConfigkey{ns, coll}] != nil
+}
+
+func (c collConfigCache) valueCommitment(ns, coll string) string {
+	return c[collConfigkey{ns, coll}].GetValueCommitment()
 }
 
 func constructCollectionConfigKey(chaincodeName string) string {
@@ -13,6 +13,7 @@ import (
 
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	ledger "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
@@ -25,6 +26,8 @@ import (
 
 type queryHelper struct {
 	txmgr             *LockBasedTxMgr
+	db                privacyenabledstate.DB
+	snapshot          bool // whether db is a snapshot to close when done, instead of the commit lock to release
 	collNameValidator *collNameValidator
 	rwsetBuilder      *rwsetutil.RWSetBuilder
 	itrs              []*resultsItr
@@ -33,7 +36,7 @@ type queryHelper struct {
 }
 
 func newQueryHelper(txmgr *LockBasedTxMgr, rwsetBuilder *rwsetutil.RWSetBuilder) *queryHelper {
-	helper := &queryHelper{txmgr: txmgr, rwsetBuilder: rwsetBuilder}
+	helper := &queryHelper{txmgr: txmgr, db: txmgr.db, rwsetBuilder: rwsetBuilder}
 	validator := newCollNameValidator(helper)
 	helper.collNameValidator = validator
 	return helper
@@ -43,7 +46,7 @@ func (h *queryHelper) getState(ns string, key string) ([]byte, []byte, error) {
 	if err := h.checkDone(); err != nil {
 		return nil, nil, err
 	}
-	versionedValue, err := h.txmgr.db.GetState(ns, key)
+	versionedValue, err := h.db.GetState(ns, key)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -58,7 +61,7 @@ func (h *queryHelper) getStateMultipleKeys(namespace string, keys []string) ([][
 	if err := h.checkDone(); err != nil {
 		return nil, err
 	}
-	versionedValues, err := h.txmgr.db.GetStateMultipleKeys(namespace, keys)
+	versionedValues, err := h.db.GetStateMultipleKeys(namespace, keys)
 	if err != nil {
 		return nil, nil
 	}
@@ -77,7 +80,7 @@ func (h *queryHelper) getStateRangeScanIterator(namespace string, startKey strin
 	if err := h.checkDone(); err != nil {
 		return nil, err
 	}
-	itr, err := newResultsItr(namespace, startKey, endKey, nil, h.txmgr.db, h.rwsetBuilder,
+	itr, err := newResultsItr(namespace, startKey, endKey, nil, h.db, h.rwsetBuilder,
 		ledgerconfig.IsQueryReadsHashingEnabled(), ledgerconfig.GetMaxDegreeQueryReadsHashing())
 	if err != nil {
 		return nil, err
@@ -90,7 +93,7 @@ func (h *queryHelper) getStateRangeScanIteratorWithMetadata(namespace string, st
 	if err := h.checkDone(); err != nil {
 		return nil, err
 	}
-	itr, err := newResultsItr(namespace, startKey, endKey, metadata, h.txmgr.db, h.rwsetBuilder,
+	itr, err := newResultsItr(namespace, startKey, endKey, metadata, h.db, h.rwsetBuilder,
 		ledgerconfig.IsQueryReadsHashingEnabled(), ledgerconfig.GetMaxDegreeQueryReadsHashing())
 	if err != nil {
 		return nil, err
@@ -103,7 +106,7 @@ func (h *queryHelper) executeQuery(namespace, query string) (commonledger.Result
 	if err := h.checkDone(); err != nil {
 		return nil, err
 	}
-	dbItr, err := h.txmgr.db.ExecuteQuery(namespace, query)
+	dbItr, err := h.db.ExecuteQuery(namespace, query)
 	if err != nil {
 		return nil, err
 	}
@@ -114,7 +117,7 @@ func (h *queryHelper) executeQueryWithMetadata(namespace, query string, metadata
 	if err := h.checkDone(); err != nil {
 		return nil, err
 	}
-	dbItr, err := h.txmgr.db.ExecuteQueryWithMetadata(namespace, query, metadata)
+	dbItr, err := h.db.ExecuteQueryWithMetadata(namespace, query, metadata)
 	if err != nil {
 		return nil, err
 	}
@@ -129,30 +132,53 @@ func (h *queryHelper) getPrivateData(ns, coll, key string) ([]byte, error) {
 		return nil, err
 	}
 
-	var err error
-	var hashVersion *version.Height
-	var versionedValue *statedb.VersionedValue
-
-	if versionedValue, err = h.txmgr.db.GetPrivateData(ns, coll, key); err != nil {
+	versionedValue, err := h.db.GetPrivateData(ns, coll, key)
+	if err != nil {
 		return nil, err
 	}
 
 	// metadata is always nil for private data - because, the metadata is part of the hashed key (instead of raw key)
 	val, _, ver := decomposeVersionedValue(versionedValue)
+	if err := h.addPrivateDataRead(ns, coll, key, ver); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
 
-	keyHash := util.ComputeStringHash(key)
-	if hashVersion, err = h.txmgr.db.GetKeyHashVersion(ns, coll, keyHash); err != nil {
+// getPrivateDataUnvalidated returns the private data value without checking
+// its version nor recording the read, as a stale value is acceptable
+func (h *queryHelper) getPrivateDataUnvalidated(ns, coll, key string) ([]byte, error) {
+	if err := h.validateCollName(ns, coll); err != nil {
 		return nil, err
 	}
+	if err := h.checkDone(); err != nil {
+		return nil, err
+	}
+	versionedValue, err := h.db.GetPrivateData(ns, coll, key)
+	if err != nil {
+		return nil, err
+	}
+	val, _, _ := decomposeVersionedValue(versionedValue)
+	return val, nil
+}
+
+// addPrivateDataRead checks that the version of a private data value matches
+// the version of its hash in the public state, as the private data of a peer
+// may be stale, and records the read in the hashed read set
+func (h *queryHelper) addPrivateDataRead(ns, coll, key string, ver *version.Height) error {
+	hashVersion, err := h.db.GetKeyHashVersion(ns, coll, util.ComputeStringHash(key))
+	if err != nil {
+		return err
+	}
 	if !version.AreSame(hashVersion, ver) {
-		return nil, &txmgr.ErrPvtdataNotAvailable{Msg: fmt.Sprintf(
+		return &txmgr.ErrPvtdataNotAvailable{Msg: fmt.Sprintf(
 			"private data matching public hash version is not available. Public hash version = %#v, Private data version = %#v",
 			hashVersion, ver)}
 	}
 	if h.rwsetBuilder != nil {
 		h.rwsetBuilder.AddToHashedReadSet(ns, coll, key, ver)
 	}
-	return val, nil
+	return nil
 }
 
 func (h *queryHelper) getPrivateDataValueHash(ns, coll, key string) (valueHash, metadataBytes []byte, err error) {
@@ -165,7 +191,7 @@ func (h *queryHelper) getPrivateDataValueHash(ns, coll, key string) (valueHash,
 	var versionedValue *statedb.VersionedValue
 
 	keyHash := util.ComputeStringHash(key)
-	if versionedValue, err = h.txmgr.db.GetValueHash(ns, coll, keyHash); err != nil {
+	if versionedValue, err = h.db.GetValueHash(ns, coll, keyHash); err != nil {
 		return nil, nil, err
 	}
 	valHash, metadata, ver := decomposeVersionedValue(versionedValue)
@@ -175,6 +201,14 @@ func (h *queryHelper) getPrivateDataValueHash(ns, coll, key string) (valueHash,
 	return valHash, metadata, nil
 }
 
+func (h *queryHelper) getPrivateDataHash(ns, coll, key string) ([]byte, error) {
+	valueHash, _, err := h.getPrivateDataValueHash(ns, coll, key)
+	if err != nil {
+		return nil, err
+	}
+	return valueHash, nil
+}
+
 func (h *queryHelper) getPrivateDataMultipleKeys(ns, coll string, keys []string) ([][]byte, error) {
 	if err := h.validateCollName(ns, coll); err != nil {
 		return nil, err
@@ -182,7 +216,7 @@ func (h *queryHelper) getPrivateDataMultipleKeys(ns, coll string, keys []string)
 	if err := h.checkDone(); err != nil {
 		return nil, err
 	}
-	versionedValues, err := h.txmgr.db.GetPrivateDataMultipleKeys(ns, coll, keys)
+	versionedValues, err := h.db.GetPrivateDataMultipleKeys(ns, coll, keys)
 	if err != nil {
 		return nil, nil
 	}
@@ -204,11 +238,11 @@ func (h *queryHelper) getPrivateDataRangeScanIterator(namespace, collection, sta
 	if err := h.checkDone(); err != nil {
 		return nil, err
 	}
-	dbItr, err := h.txmgr.db.GetPrivateDataRangeScanIterator(namespace, collection, startKey, endKey)
+	dbItr, err := h.db.GetPrivateDataRangeScanIterator(namespace, collection, startKey, endKey)
 	if err != nil {
 		return nil, err
 	}
-	return &pvtdataResultsItr{namespace, collection, dbItr}, nil
+	return &pvtdataResultsItr{namespace, collection, dbItr, h}, nil
 }
 
 func (h *queryHelper) executeQueryOnPrivateData(namespace, collection, query string) (commonledger.ResultsIterator, error) {
@@ -218,11 +252,11 @@ func (h *queryHelper) executeQueryOnPrivateData(namespace, collection, query str
 	if err := h.checkDone(); err != nil {
 		return nil, err
 	}
-	dbItr, err := h.txmgr.db.ExecuteQueryOnPrivateData(namespace, collection, query)
+	dbItr, err := h.db.ExecuteQueryOnPrivateData(namespace, collection, query)
 	if err != nil {
 		return nil, err
 	}
-	return &pvtdataResultsItr{namespace, collection, dbItr}, nil
+	return &pvtdataResultsItr{namespace, collection, dbItr, h}, nil
 }
 
 func (h *queryHelper) getStateMetadata(ns string, key string) (map[string][]byte, error) {
@@ -233,7 +267,7 @@ func (h *queryHelper) getStateMetadata(ns string, key string) (map[string][]byte
 	var err error
 	if h.rwsetBuilder == nil {
 		// reads versions are not getting recorded, retrieve metadata value via optimized path
-		if metadataBytes, err = h.txmgr.db.GetStateMetadata(ns, key); err != nil {
+		if metadataBytes, err = h.db.GetStateMetadata(ns, key); err != nil {
 			return nil, err
 		}
 	} else {
@@ -273,7 +307,7 @@ func (h *queryHelper) getPrivateDataMetadataByHash(ns, coll string, keyhash []by
 		// this requires to improve rwset builder to accept a keyhash
 		return nil, errors.New("retrieving private data metadata by keyhash is not supported in simulation. This function is only available for query as yet")
 	}
-	metadataBytes, err := h.txmgr.db.GetPrivateDataMetadataByHash(ns, coll, keyhash)
+	metadataBytes, err := h.db.GetPrivateDataMetadataByHash(ns, coll, keyhash)
 	if err != nil {
 		return nil, err
 	}
@@ -286,11 +320,15 @@ func (h *queryHelper) done() {
 	}
 
 	defer func() {
-		h.txmgr.commitRWLock.RUnlock()
 		h.doneInvoked = true
 		for _, itr := range h.itrs {
 			itr.Close()
 		}
+		if h.snapshot {
+			h.db.Close()
+		} else {
+			h.txmgr.commitRWLock.RUnlock()
+		}
 	}()
 }
 
@@ -324,6 +362,10 @@ func (h *queryHelper) validateCollName(ns, coll string) error {
 	return h.collNameValidator.validateCollName(ns, coll)
 }
 
+func (h *queryHelper) valueCommitment(ns, coll string) string {
+	return h.collNameValidator.valueCommitment(ns, coll)
+}
+
 // resultsItr implements interface ledger.ResultsIterator
 // this wraps the actual db iterator and intercept the calls
 // to build rangeQueryInfo in the ReadWriteSet that is used
@@ -460,6 +502,14 @@ func (itr *queryResultsItr) GetBookmarkAndClose() string {
 	return returnBookmark
 }
 
+// GetWarning implements method in interface ledger.QueryWarner
+func (itr *queryResultsItr) GetWarning() string {
+	if queryWarner, ok := itr.DBItr.(statedb.QueryWarner); ok {
+		return queryWarner.GetWarning()
+	}
+	return ""
+}
+
 func decomposeVersionedValue(versionedValue *statedb.VersionedValue) ([]byte, []byte, *version.Height) {
 	var value []byte
 	var metadata []byte
@@ -472,11 +522,14 @@ func decomposeVersionedValue(versionedValue *statedb.VersionedValue) ([]byte, []
 	return value, metadata, ver
 }
 
-// pvtdataResultsItr iterates over results of a query on pvt data
+// pvtdataResultsItr iterates over results of a query on pvt data. Each result
+// is checked against the version of its hash and recorded in the hashed read set,
+// like the values returned by getPrivateData
 type pvtdataResultsItr struct {
-	ns    string
-	coll  string
-	dbItr statedb.ResultsIterator
+	ns     string
+	coll   string
+	dbItr  statedb.ResultsIterator
+	helper *queryHelper
 }
 
 // Next implements method in interface ledger.ResultsIterator
@@ -489,6 +542,9 @@ func (itr *pvtdataResultsItr) Next() (commonledger.QueryResult, error) {
 		return nil, nil
 	}
 	versionedQueryRecord := queryResult.(*statedb.VersionedKV)
+	if err := itr.helper.addPrivateDataRead(itr.ns, itr.coll, versionedQueryRecord.Key, versionedQueryRecord.Version); err != nil {
+		return nil, err
+	}
 	return &queryresult.KV{
 		Namespace: itr.ns,
 		Key:       versionedQueryRecord.Key,
@@ -10,6 +10,7 @@ import (
 
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
@@ -112,6 +113,61 @@ func testPrivateDataMetadataRetrievalByHash(t *testing.T, env testEnv) {
 	})
 }
 
+func TestPrivateDataHashRetrieval(t *testing.T) {
+	for _, testEnv := range testEnvs {
+		testPrivateDataHashRetrieval(t, testEnv)
+	}
+}
+
+func testPrivateDataHashRetrieval(t *testing.T, env testEnv) {
+	ledgerid := "test-privatedata-hash-retrieval"
+	cs := btltestutil.NewMockCollectionStore()
+	cs.SetBTL("ns", "coll", 0)
+	btlPolicy := pvtdatapolicy.ConstructBTLPolicy(cs)
+	env.init(t, ledgerid, btlPolicy)
+	defer env.cleanup()
+
+	txMgr := env.getTxMgr()
+	bg, _ := testutil.NewBlockGenerator(t, ledgerid, false)
+	populateCollConfigForTest(t, txMgr.(*LockBasedTxMgr), []collConfigkey{{"ns", "coll"}}, version.NewHeight(1, 1))
+	// Simulate and commit tx1 - set val for key1
+	key1, value1 := "key1", []byte("value1")
+	s1, _ := txMgr.NewTxSimulator("test_tx1")
+	s1.SetPrivateData("ns", "coll", key1, value1)
+	s1.Done()
+	blkAndPvtdata1 := prepareNextBlockForTestFromSimulator(t, bg, s1)
+	assert.NoError(t, txMgr.ValidateAndPrepare(blkAndPvtdata1, true))
+	assert.NoError(t, txMgr.Commit())
+
+	t.Run("query-executor", func(t *testing.T) {
+		qe, err := txMgr.NewQueryExecutor("test_tx2")
+		assert.NoError(t, err)
+		defer qe.Done()
+		hash, err := qe.GetPrivateDataHash("ns", "coll", key1)
+		assert.NoError(t, err)
+		assert.Equal(t, util.ComputeHash(value1), hash)
+
+		hash, err = qe.GetPrivateDataHash("ns", "coll", "non-existing-key")
+		assert.NoError(t, err)
+		assert.Nil(t, hash)
+	})
+
+	t.Run("tx-simulator", func(t *testing.T) {
+		s2, err := txMgr.NewTxSimulator("test_tx3")
+		assert.NoError(t, err)
+		defer s2.Done()
+		hash, err := s2.GetPrivateDataHash("ns", "coll", key1)
+		assert.NoError(t, err)
+		assert.Equal(t, util.ComputeHash(value1), hash)
+	})
+
+	t.Run("undefined-collection", func(t *testing.T) {
+		queryHelper := newQueryHelper(txMgr.(*LockBasedTxMgr), nil)
+		_, err := queryHelper.getPrivateDataHash("ns", "undefined-coll", key1)
+		assert.IsType(t, &ledger.InvalidCollNameError{}, err)
+	})
+}
+
 func putPvtUpdates(t *testing.T, updates *privacyenabledstate.UpdateBatch, ns, coll, key string, value []byte, ver *version.Height) {
 	updates.PvtUpdates.Put(ns, coll, key, value, ver)
 	updates.HashUpdates.Put(ns, coll, util.ComputeStringHash(key), util.ComputeHash(value), ver)
@@ -70,6 +70,11 @@ func (q *lockBasedQueryExecutor) GetPrivateData(namespace, collection, key strin
 	return q.helper.getPrivateData(namespace, collection, key)
 }
 
+// GetPrivateDataHash implements method in interface `ledger.QueryExecutor`
+func (q *lockBasedQueryExecutor) GetPrivateDataHash(namespace, collection, key string) ([]byte, error) {
+	return q.helper.getPrivateDataHash(namespace, collection, key)
+}
+
 // GetPrivateDataMetadata implements method in interface `ledger.QueryExecutor`
 func (q *lockBasedQueryExecutor) GetPrivateDataMetadata(namespace, collection, key string) (map[string][]byte, error) {
 	return q.helper.getPrivateDataMetadata(namespace, collection, key)
@@ -8,10 +8,12 @@ package lockbasedtxmgr
 import (
 	"fmt"
 
+	"github.com/hyperledger/fabric/common/crypto/pedersen"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr"
+	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/pkg/errors"
 )
 
@@ -26,7 +28,7 @@ type lockBasedTxSimulator struct {
 }
 
 func newLockBasedTxSimulator(txmgr *LockBasedTxMgr, txid string) (*lockBasedTxSimulator, error) {
-	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder := rwsetutil.NewRWSetBuilderWithValueHashFunc(txmgr.valueHasher.Latest())
 	helper := newQueryHelper(txmgr, rwsetBuilder)
 	logger.Debugf("constructing new tx simulator txid = [%s]", txid)
 	return &lockBasedTxSimulator{lockBasedQueryExecutor{helper, txid}, rwsetBuilder, false, false, false, false}, nil
@@ -72,14 +74,39 @@ func (s *lockBasedTxSimulator) DeleteStateMetadata(namespace, key string) error
 
 // SetPrivateData implements method in interface `ledger.TxSimulator`
 func (s *lockBasedTxSimulator) SetPrivateData(ns, coll, key string, value []byte) error {
+	return s.setPrivateData(ns, coll, key, value, nil)
+}
+
+// SetPrivateDataWithBlindingFactor implements method in interface `ledger.TxSimulator`
+func (s *lockBasedTxSimulator) SetPrivateDataWithBlindingFactor(ns, coll, key string, value, blindingFactor []byte) error {
+	return s.setPrivateData(ns, coll, key, value, blindingFactor)
+}
+
+func (s *lockBasedTxSimulator) setPrivateData(ns, coll, key string, value, blindingFactor []byte) error {
 	if err := s.helper.validateCollName(ns, coll); err != nil {
 		return err
 	}
 	if err := s.checkWritePrecondition(key, value); err != nil {
 		return err
 	}
+	pedersenCommitted := s.helper.valueCommitment(ns, coll) == util.ValueCommitmentPedersen
+	if blindingFactor != nil && !pedersenCommitted {
+		return errors.Errorf("collection [%s:%s] does not commit to its values with Pedersen commitments", ns, coll)
+	}
+	if !pedersenCommitted || value == nil {
+		s.writePerformed = true
+		s.rwsetBuilder.AddToPvtAndHashedWriteSet(ns, coll, key, value)
+		return nil
+	}
+	if blindingFactor == nil {
+		return errors.Errorf("the blinding factor of the commitment to the value of key [%s] of collection [%s:%s] is missing", key, ns, coll)
+	}
+	commitment, err := pedersen.Commit(value, blindingFactor)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("invalid blinding factor for key [%s] of collection [%s:%s]", key, ns, coll))
+	}
 	s.writePerformed = true
-	s.rwsetBuilder.AddToPvtAndHashedWriteSet(ns, coll, key, value)
+	s.rwsetBuilder.AddToPvtAndHashedWriteSetWithValueHashFunc(ns, coll, key, value, func([]byte) []byte { return commitment })
 	return nil
 }
 
@@ -98,6 +125,11 @@ func (s *lockBasedTxSimulator) SetPrivateDataMultipleKeys(ns, coll string, kvs m
 	return nil
 }
 
+// GetPrivateDataUnvalidated implements method in interface `ledger.TxSimulator`
+func (s *lockBasedTxSimulator) GetPrivateDataUnvalidated(ns, coll, key string) ([]byte, error) {
+	return s.helper.getPrivateDataUnvalidated(ns, coll, key)
+}
+
 // GetPrivateDataRangeScanIterator implements method in interface `ledger.TxSimulator`
 func (s *lockBasedTxSimulator) GetPrivateDataRangeScanIterator(namespace, collection, startKey, endKey string) (commonledger.ResultsIterator, error) {
 	if err := s.checkBeforePvtdataQueries(); err != nil {
@@ -14,26 +14,37 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/pvtstatepurgemgmt"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/queryutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validator"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validator/statebasedval"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validator/valimpl"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/pvtdatapolicy"
+	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	"github.com/pkg/errors"
 )
 
 var logger = flogging.MustGetLogger("lockbasedtxmgr")
 
 // LockBasedTxMgr a simple implementation of interface `txmgmt.TxMgr`.
-// This implementation uses a read-write lock to prevent conflicts between transaction simulation and committing
+// This implementation uses a read-write lock to prevent conflicts between transaction simulation and committing.
+// When snapshot isolation is enabled, the simulations read a snapshot of the state db instead, and hold the lock
+// only while the snapshot is taken.
 type LockBasedTxMgr struct {
-	ledgerid        string
-	db              privacyenabledstate.DB
-	pvtdataPurgeMgr *pvtdataPurgeMgr
-	validator       validator.Validator
-	stateListeners  []ledger.StateListener
-	commitRWLock    sync.RWMutex
-	current         *current
+	ledgerid          string
+	db                privacyenabledstate.DB
+	snapshotIsolation bool
+	pvtdataPurgeMgr   *pvtdataPurgeMgr
+	validator         validator.Validator
+	valueHasher       txmgr.ValueHasher
+	stateListeners    []ledger.StateListener
+	commitRWLock      sync.RWMutex
+	current           *current
 }
 
 type current struct {
@@ -50,20 +61,42 @@ func (c *current) maxTxNumber() uint64 {
 	return uint64(len(c.block.Data.Data)) - 1
 }
 
-// NewLockBasedTxMgr constructs a new instance of NewLockBasedTxMgr
+// NewLockBasedTxMgr constructs a new instance of NewLockBasedTxMgr.
+// The values of the write-sets are hashed with SHA256 when valueHasher is nil
 func NewLockBasedTxMgr(ledgerid string, db privacyenabledstate.DB, stateListeners []ledger.StateListener,
-	btlPolicy pvtdatapolicy.BTLPolicy, bookkeepingProvider bookkeeping.Provider) (*LockBasedTxMgr, error) {
+	btlPolicy pvtdatapolicy.BTLPolicy, bookkeepingProvider bookkeeping.Provider, valueHasher txmgr.ValueHasher) (*LockBasedTxMgr, error) {
 	db.Open()
-	txmgr := &LockBasedTxMgr{ledgerid: ledgerid, db: db, stateListeners: stateListeners}
+	if valueHasher == nil {
+		valueHasher = defaultValueHasher{}
+	}
+	txmgr := &LockBasedTxMgr{ledgerid: ledgerid, db: db, stateListeners: stateListeners, valueHasher: valueHasher}
+	if ledgerconfig.IsSnapshotIsolationEnabled() {
+		if db.IsSnapshotCapable() {
+			txmgr.snapshotIsolation = true
+		} else {
+			logger.Warningf("Channel [%s]: snapshot isolation is enabled but not supported by the state database", ledgerid)
+		}
+	}
 	pvtstatePurgeMgr, err := pvtstatepurgemgmt.InstantiatePurgeMgr(ledgerid, db, btlPolicy, bookkeepingProvider)
 	if err != nil {
 		return nil, err
 	}
 	txmgr.pvtdataPurgeMgr = &pvtdataPurgeMgr{pvtstatePurgeMgr, false}
-	txmgr.validator = valimpl.NewStatebasedValidator(txmgr, db)
+	txmgr.validator = valimpl.NewStatebasedValidator(ledgerid, txmgr, db, valueHasher)
 	return txmgr, nil
 }
 
+// defaultValueHasher hashes the values of the write-sets of all the blocks with SHA256
+type defaultValueHasher struct{}
+
+func (defaultValueHasher) ForBlock(block *common.Block) (func([]byte) []byte, error) {
+	return util.ComputeHash, nil
+}
+
+func (defaultValueHasher) Latest() func([]byte) []byte {
+	return util.ComputeHash
+}
+
 // GetLastSavepoint returns the block num recorded in savepoint,
 // returns 0 if NO savepoint is found
 func (txmgr *LockBasedTxMgr) GetLastSavepoint() (*version.Height, error) {
@@ -73,7 +106,9 @@ func (txmgr *LockBasedTxMgr) GetLastSavepoint() (*version.Height, error) {
 // NewQueryExecutor implements method in interface `txmgmt.TxMgr`
 func (txmgr *LockBasedTxMgr) NewQueryExecutor(txid string) (ledger.QueryExecutor, error) {
 	qe := newQueryExecutor(txmgr, txid)
-	txmgr.commitRWLock.RLock()
+	if err := txmgr.pinState(qe.helper); err != nil {
+		return nil, err
+	}
 	return qe, nil
 }
 
@@ -84,10 +119,29 @@ func (txmgr *LockBasedTxMgr) NewTxSimulator(txid string) (ledger.TxSimulator, er
 	if err != nil {
 		return nil, err
 	}
-	txmgr.commitRWLock.RLock()
+	if err := txmgr.pinState(s.helper); err != nil {
+		return nil, err
+	}
 	return s, nil
 }
 
+// pinState makes the reads of the query helper consistent with the state committed at the time of the call,
+// either by blocking the commits until the helper is done or by having the helper read a snapshot of the state
+func (txmgr *LockBasedTxMgr) pinState(h *queryHelper) error {
+	txmgr.commitRWLock.RLock()
+	if !txmgr.snapshotIsolation {
+		return nil
+	}
+	defer txmgr.commitRWLock.RUnlock()
+	snapshot, err := txmgr.db.GetSnapshot()
+	if err != nil {
+		return err
+	}
+	h.db = snapshot
+	h.snapshot = true
+	return nil
+}
+
 // ValidateAndPrepare implements method in interface `txmgmt.TxMgr`
 func (txmgr *LockBasedTxMgr) ValidateAndPrepare(blockAndPvtdata *ledger.BlockAndPvtData, doMVCCValidation bool) error {
 	block := blockAndPvtdata.Block
@@ -140,6 +194,104 @@ func (txmgr *LockBasedTxMgr) invokeNamespaceListeners() error {
 	return nil
 }
 
+// ListStateIndexes implements method in interface `ledger.StateIndexManager`
+func (txmgr *LockBasedTxMgr) ListStateIndexes(chaincodeName, collection string) ([]*ledger.StateIndex, error) {
+	indexManager, err := txmgr.stateIndexManager()
+	if err != nil {
+		return nil, err
+	}
+	return indexManager.ListStateIndexes(chaincodeName, collection)
+}
+
+// CreateStateIndex implements method in interface `ledger.StateIndexManager`
+func (txmgr *LockBasedTxMgr) CreateStateIndex(chaincodeName, collection string, definition []byte) error {
+	indexManager, err := txmgr.stateIndexManager()
+	if err != nil {
+		return err
+	}
+	return indexManager.CreateStateIndex(chaincodeName, collection, definition)
+}
+
+// RebuildStateIndexes implements method in interface `ledger.StateIndexManager`
+func (txmgr *LockBasedTxMgr) RebuildStateIndexes(chaincodeName, collection string) error {
+	indexManager, err := txmgr.stateIndexManager()
+	if err != nil {
+		return err
+	}
+	return indexManager.RebuildStateIndexes(chaincodeName, collection)
+}
+
+func (txmgr *LockBasedTxMgr) stateIndexManager() (ledger.StateIndexManager, error) {
+	indexManager, ok := txmgr.db.(ledger.StateIndexManager)
+	if !ok {
+		return nil, errors.Errorf("the state database of ledger [%s] does not support indexes", txmgr.ledgerid)
+	}
+	return indexManager, nil
+}
+
+// StateDBSize implements method in interface `ledger.StateDBSizer`
+func (txmgr *LockBasedTxMgr) StateDBSize() (int64, error) {
+	sizer, ok := txmgr.db.(ledger.StateDBSizer)
+	if !ok {
+		return 0, errors.Errorf("the state database of ledger [%s] does not report its size", txmgr.ledgerid)
+	}
+	return sizer.StateDBSize()
+}
+
+// NewStateSnapshotIterator returns an iterator over a snapshot of the public state and of the hashes of the
+// private data, as committed at the time of the call, along with the save point of the snapshot. Closing the
+// iterator releases the snapshot.
+func (txmgr *LockBasedTxMgr) NewStateSnapshotIterator() (statedb.ResultsIterator, *version.Height, error) {
+	if !txmgr.db.IsSnapshotCapable() {
+		return nil, nil, errors.Errorf("the state database of ledger [%s] does not support snapshots", txmgr.ledgerid)
+	}
+	txmgr.commitRWLock.RLock()
+	snapshot, err := txmgr.db.GetSnapshot()
+	txmgr.commitRWLock.RUnlock()
+	if err != nil {
+		return nil, nil, err
+	}
+	savepoint, err := snapshot.GetLatestSavePoint()
+	if err != nil {
+		snapshot.Close()
+		return nil, nil, err
+	}
+	fullScanCapable, ok := snapshot.(privacyenabledstate.FullScanCapable)
+	if !ok {
+		snapshot.Close()
+		return nil, nil, errors.Errorf("the state database of ledger [%s] cannot be scanned in full", txmgr.ledgerid)
+	}
+	itr, err := fullScanCapable.GetFullScanIterator()
+	if err != nil {
+		snapshot.Close()
+		return nil, nil, err
+	}
+	return &snapshotIterator{itr, snapshot}, savepoint, nil
+}
+
+// snapshotIterator releases the snapshot it iterates over when closed
+type snapshotIterator struct {
+	statedb.ResultsIterator
+	snapshot privacyenabledstate.DB
+}
+
+func (itr *snapshotIterator) Close() {
+	itr.ResultsIterator.Close()
+	itr.snapshot.Close()
+}
+
+// FindReadConflict implements method in interface `ledger.ReadSetChecker`
+func (txmgr *LockBasedTxMgr) FindReadConflict(txRWSetBytes []byte) (*ledger.ReadConflict, error) {
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(txRWSetBytes); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling simulation results")
+	}
+	// the lock keeps the state from changing while the reads are checked
+	txmgr.commitRWLock.RLock()
+	defer txmgr.commitRWLock.RUnlock()
+	return statebasedval.NewValidator(txmgr.db).FindReadConflict(txRWSet)
+}
+
 // Shutdown implements method in interface `txmgmt.TxMgr`
 func (txmgr *LockBasedTxMgr) Shutdown() {
 	// wait for background go routine to finish else the timing issue causes a nil pointer inside goleveldb code
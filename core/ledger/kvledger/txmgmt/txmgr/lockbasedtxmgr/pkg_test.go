@@ -85,7 +85,7 @@ func (env *lockBasedEnv) init(t *testing.T, testLedgerID string, btlPolicy pvtda
 	env.testDB = env.testDBEnv.GetDBHandle(testLedgerID)
 	assert.NoError(t, err)
 	env.testBookkeepingEnv = bookkeeping.NewTestEnv(t)
-	env.txmgr, err = NewLockBasedTxMgr(testLedgerID, env.testDB, nil, btlPolicy, env.testBookkeepingEnv.TestProvider)
+	env.txmgr, err = NewLockBasedTxMgr(testLedgerID, env.testDB, nil, btlPolicy, env.testBookkeepingEnv.TestProvider, nil)
 	assert.NoError(t, err)
 }
 
@@ -13,16 +13,20 @@ import (
 	"os"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/crypto/pedersen"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/pvtdatapolicy"
 	btltestutil "github.com/hyperledger/fabric/core/ledger/pvtdatapolicy/testutil"
 	ledgertestutil "github.com/hyperledger/fabric/core/ledger/testutil"
 	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -1008,6 +1012,112 @@ func TestTxSimulatorMissingPvtdata(t *testing.T) {
 	assert.Nil(t, val)
 }
 
+func TestTxSimulatorPvtdataReads(t *testing.T) {
+	testEnv := testEnvs[0]
+	testEnv.init(t, "TestTxSimulatorPvtdataReads", nil)
+	defer testEnv.cleanup()
+
+	txMgr := testEnv.getTxMgr()
+	populateCollConfigForTest(t, txMgr.(*LockBasedTxMgr),
+		[]collConfigkey{{"ns1", "coll1"}},
+		version.NewHeight(1, 1),
+	)
+
+	db := testEnv.getVDB()
+	updateBatch := privacyenabledstate.NewUpdateBatch()
+	for i := 1; i <= 2; i++ {
+		key, value := fmt.Sprintf("key%d", i), fmt.Sprintf("value%d", i)
+		updateBatch.HashUpdates.Put("ns1", "coll1", util.ComputeStringHash(key), util.ComputeStringHash(value), version.NewHeight(1, uint64(i)))
+		updateBatch.PvtUpdates.Put("ns1", "coll1", key, []byte(value), version.NewHeight(1, uint64(i)))
+	}
+	// the private data of key2 is stale once its hash is updated without it
+	updateBatch.HashUpdates.Put("ns1", "coll1", util.ComputeStringHash("key2"), util.ComputeStringHash("value2"), version.NewHeight(2, 1))
+	db.ApplyPrivacyAwareUpdates(updateBatch, version.NewHeight(2, 1))
+
+	simulator, _ := txMgr.NewTxSimulator("testTxid1")
+	val, err := simulator.GetPrivateData("ns1", "coll1", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value1"), val)
+	_, err = simulator.GetPrivateData("ns1", "coll1", "key2")
+	_, ok := err.(*txmgr.ErrPvtdataNotAvailable)
+	assert.True(t, ok)
+	// an unvalidated read returns stale private data
+	val, err = simulator.GetPrivateDataUnvalidated("ns1", "coll1", "key2")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value2"), val)
+	val, err = simulator.GetPrivateDataUnvalidated("ns1", "coll1", "key3")
+	assert.NoError(t, err)
+	assert.Nil(t, val)
+	_, err = simulator.GetPrivateDataUnvalidated("ns1", "coll2", "key1")
+	_, ok = err.(*ledger.InvalidCollNameError)
+	assert.True(t, ok)
+	simulator.Done()
+
+	// only the validated read is recorded, and only in the hashed read set
+	simRes, err := simulator.GetTxSimulationResults()
+	assert.NoError(t, err)
+	assert.Nil(t, simRes.PvtSimulationResults)
+	txRWSet, err := rwsetutil.TxRwSetFromProtoMsg(simRes.PubSimulationResults)
+	assert.NoError(t, err)
+	// the collection config is read from the lscc namespace
+	assert.Len(t, txRWSet.NsRwSets, 2)
+	assert.Equal(t, "ns1", txRWSet.NsRwSets[1].NameSpace)
+	assert.Empty(t, txRWSet.NsRwSets[1].KvRwSet.Reads)
+	assert.Len(t, txRWSet.NsRwSets[1].CollHashedRwSets, 1)
+	hashedReads := txRWSet.NsRwSets[1].CollHashedRwSets[0].HashedRwSet.HashedReads
+	assert.Len(t, hashedReads, 1)
+	assert.Equal(t, util.ComputeStringHash("key1"), hashedReads[0].KeyHash)
+}
+
+func TestTxSimulatorPedersenCommitments(t *testing.T) {
+	testEnv := testEnvs[0]
+	testEnv.init(t, "TestTxSimulatorPedersenCommitments", nil)
+	defer testEnv.cleanup()
+
+	txMgr := testEnv.getTxMgr().(*LockBasedTxMgr)
+	pkgBytes, err := proto.Marshal(&common.CollectionConfigPackage{
+		Config: []*common.CollectionConfig{
+			{Payload: &common.CollectionConfig_StaticCollectionConfig{
+				StaticCollectionConfig: &common.StaticCollectionConfig{Name: "coll1", ValueCommitment: util.ValueCommitmentPedersen},
+			}},
+			{Payload: &common.CollectionConfig_StaticCollectionConfig{
+				StaticCollectionConfig: &common.StaticCollectionConfig{Name: "coll2"},
+			}},
+		},
+	})
+	assert.NoError(t, err)
+	updates := privacyenabledstate.NewUpdateBatch()
+	updates.PubUpdates.Put(lsccNamespace, constructCollectionConfigKey("ns1"), pkgBytes, version.NewHeight(1, 1))
+	txMgr.db.ApplyPrivacyAwareUpdates(updates, version.NewHeight(1, 1))
+
+	blindingFactor := bytes.Repeat([]byte{1}, pedersen.BlindingFactorSize)
+	simulator, _ := txMgr.NewTxSimulator("testTxid1")
+	err = simulator.SetPrivateData("ns1", "coll1", "key1", []byte("value1"))
+	assert.EqualError(t, err, "the blinding factor of the commitment to the value of key [key1] of collection [ns1:coll1] is missing")
+	err = simulator.SetPrivateDataWithBlindingFactor("ns1", "coll1", "key1", []byte("value1"), blindingFactor[1:])
+	assert.EqualError(t, err, "invalid blinding factor for key [key1] of collection [ns1:coll1]: blinding factor of 31 bytes instead of 32")
+	err = simulator.SetPrivateDataWithBlindingFactor("ns1", "coll2", "key1", []byte("value1"), blindingFactor)
+	assert.EqualError(t, err, "collection [ns1:coll2] does not commit to its values with Pedersen commitments")
+	assert.NoError(t, simulator.SetPrivateDataWithBlindingFactor("ns1", "coll1", "key1", []byte("value1"), blindingFactor))
+	assert.NoError(t, simulator.DeletePrivateData("ns1", "coll1", "key2"))
+	assert.NoError(t, simulator.SetPrivateData("ns1", "coll2", "key1", []byte("value1")))
+	simulator.Done()
+	simRes, err := simulator.GetTxSimulationResults()
+	assert.NoError(t, err)
+	txRWSet, err := rwsetutil.TxRwSetFromProtoMsg(simRes.PubSimulationResults)
+	assert.NoError(t, err)
+
+	collHashedRwSets := txRWSet.NsRwSets[1].CollHashedRwSets
+	assert.Len(t, collHashedRwSets, 2)
+	hashedWrites := collHashedRwSets[0].HashedRwSet.HashedWrites
+	commitment, err := pedersen.Commit([]byte("value1"), blindingFactor)
+	assert.NoError(t, err)
+	assert.Equal(t, commitment, hashedWrites[0].ValueHash)
+	assert.True(t, hashedWrites[1].IsDelete)
+	assert.Nil(t, hashedWrites[1].ValueHash)
+	assert.Equal(t, util.ComputeHash([]byte("value1")), collHashedRwSets[1].HashedRwSet.HashedWrites[0].ValueHash)
+}
+
 func TestDeleteOnCursor(t *testing.T) {
 	cID := "cid"
 	env := testEnvs[0]
@@ -1056,6 +1166,61 @@ func TestDeleteOnCursor(t *testing.T) {
 	s3.Done()
 }
 
+func TestSnapshotIsolation(t *testing.T) {
+	viper.Set("ledger.state.snapshotIsolation", true)
+	defer viper.Set("ledger.state.snapshotIsolation", false)
+	cID := "cid"
+	env := testEnvs[0]
+	env.init(t, "TestSnapshotIsolation", nil)
+	defer env.cleanup()
+
+	txMgr := env.getTxMgr()
+	assert.True(t, txMgr.(*LockBasedTxMgr).snapshotIsolation)
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+
+	s1, _ := txMgr.NewTxSimulator("test_tx1")
+	for i := 1; i <= 4; i++ {
+		s1.SetState(cID, createTestKey(i), createTestValue(i))
+	}
+	s1.Done()
+	txRWSet1, _ := s1.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet1.PubSimulationResults)
+
+	// the block of tx3 is committed while the iterator of tx2 is open, without waiting for tx2 to be done
+	s2, _ := txMgr.NewTxSimulator("test_tx2")
+	itr2, err := s2.GetStateRangeScanIterator(cID, createTestKey(1), "")
+	assert.NoError(t, err)
+	s3, _ := txMgr.NewTxSimulator("test_tx3")
+	s3.SetState(cID, createTestKey(2), []byte("new-value"))
+	s3.DeleteState(cID, createTestKey(3))
+	s3.SetState(cID, createTestKey(5), createTestValue(5))
+	s3.Done()
+	txRWSet3, _ := s3.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet3.PubSimulationResults)
+
+	for i := 1; i <= 4; i++ {
+		kv, err := itr2.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, createTestKey(i), kv.(*queryresult.KV).Key)
+		assert.Equal(t, createTestValue(i), kv.(*queryresult.KV).Value)
+	}
+	kv, err := itr2.Next()
+	assert.NoError(t, err)
+	assert.Nil(t, kv)
+	val, err := s2.GetState(cID, createTestKey(2))
+	assert.NoError(t, err)
+	assert.Equal(t, createTestValue(2), val)
+	itr2.Close()
+	s2.Done()
+
+	// the simulations started after the commit see the new state
+	qe, _ := txMgr.NewQueryExecutor("test_tx4")
+	val, err = qe.GetState(cID, createTestKey(2))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("new-value"), val)
+	qe.Done()
+}
+
 func TestTxSimulatorMissingPvtdataExpiry(t *testing.T) {
 	ledgerid := "TestTxSimulatorMissingPvtdataExpiry"
 	testEnv := testEnvs[0]
@@ -1262,3 +1427,70 @@ func checkPvtdataTestQueryResults(t *testing.T, qe ledger.QueryExecutor, ns, col
 	assert.Equal(t, expectedMetadata, committedMetadata)
 	t.Logf("key=%s, value=%s, metadata=%s", key, committedVal, committedMetadata)
 }
+
+func TestTxSimulatorPvtdataRangeScan(t *testing.T) {
+	testEnv := testEnvs[0]
+	testEnv.init(t, "TestTxSimulatorPvtdataRangeScan", nil)
+	defer testEnv.cleanup()
+
+	txMgr := testEnv.getTxMgr()
+	populateCollConfigForTest(t, txMgr.(*LockBasedTxMgr),
+		[]collConfigkey{{"ns1", "coll1"}},
+		version.NewHeight(1, 1),
+	)
+
+	db := testEnv.getVDB()
+	updateBatch := privacyenabledstate.NewUpdateBatch()
+	for i := 1; i <= 3; i++ {
+		key, value := fmt.Sprintf("key%d", i), fmt.Sprintf("value%d", i)
+		updateBatch.HashUpdates.Put("ns1", "coll1", util.ComputeStringHash(key), util.ComputeStringHash(value), version.NewHeight(1, uint64(i)))
+		updateBatch.PvtUpdates.Put("ns1", "coll1", key, []byte(value), version.NewHeight(1, uint64(i)))
+	}
+	db.ApplyPrivacyAwareUpdates(updateBatch, version.NewHeight(1, 3))
+
+	// the keys returned by the range scan are recorded in the hashed read set
+	simulator, _ := txMgr.NewTxSimulator("testTxid1")
+	itr, err := simulator.GetPrivateDataRangeScanIterator("ns1", "coll1", "key1", "key3")
+	assert.NoError(t, err)
+	var keys []string
+	for {
+		queryResult, err := itr.Next()
+		assert.NoError(t, err)
+		if queryResult == nil {
+			break
+		}
+		keys = append(keys, queryResult.(*queryresult.KV).Key)
+	}
+	itr.Close()
+	assert.Equal(t, []string{"key1", "key2"}, keys)
+	simulator.Done()
+	simRes, err := simulator.GetTxSimulationResults()
+	assert.NoError(t, err)
+	txRWSet, err := rwsetutil.TxRwSetFromProtoMsg(simRes.PubSimulationResults)
+	assert.NoError(t, err)
+	// the collection config is read from the lscc namespace
+	assert.Len(t, txRWSet.NsRwSets, 2)
+	assert.Equal(t, "ns1", txRWSet.NsRwSets[1].NameSpace)
+	assert.Len(t, txRWSet.NsRwSets[1].CollHashedRwSets, 1)
+	hashedReads := txRWSet.NsRwSets[1].CollHashedRwSets[0].HashedRwSet.HashedReads
+	assert.Len(t, hashedReads, 2)
+	assert.Equal(t, util.ComputeStringHash("key1"), hashedReads[0].KeyHash)
+	assert.Equal(t, uint64(1), hashedReads[0].Version.TxNum)
+	assert.Equal(t, util.ComputeStringHash("key2"), hashedReads[1].KeyHash)
+
+	// the private data of key2 is stale once its hash is updated without it
+	updateBatch = privacyenabledstate.NewUpdateBatch()
+	updateBatch.HashUpdates.Put("ns1", "coll1", util.ComputeStringHash("key2"), util.ComputeStringHash("value2"), version.NewHeight(2, 1))
+	db.ApplyPrivacyAwareUpdates(updateBatch, version.NewHeight(2, 1))
+	simulator, _ = txMgr.NewTxSimulator("testTxid2")
+	defer simulator.Done()
+	itr, err = simulator.GetPrivateDataRangeScanIterator("ns1", "coll1", "", "")
+	assert.NoError(t, err)
+	defer itr.Close()
+	queryResult, err := itr.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, "key1", queryResult.(*queryresult.KV).Key)
+	_, err = itr.Next()
+	_, ok := err.(*txmgr.ErrPvtdataNotAvailable)
+	assert.True(t, ok)
+}
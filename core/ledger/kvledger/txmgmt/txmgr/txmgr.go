@@ -19,6 +19,7 @@ package txmgr
 import (
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/protos/common"
 )
 
 // TxMgr - an interface that a transaction manager should implement
@@ -34,6 +35,25 @@ type TxMgr interface {
 	Shutdown()
 }
 
+// ValueHasher returns the functions hashing the values of the write-sets, which
+// the channel config of the blocks selects
+type ValueHasher interface {
+	// ForBlock returns the hash function of the write-sets of the given block
+	ForBlock(block *common.Block) (func([]byte) []byte, error)
+	// Latest returns the hash function of the write-sets simulated on the latest state
+	Latest() func([]byte) []byte
+}
+
+// TxDeduplicator is optionally implemented by the ValueHasher, and returns the
+// transaction dedup window which the channel config of the blocks selects. The
+// transactions of a block carrying the dedup key of a previous transaction of the
+// block which is valid once the read-sets are validated are then invalid
+type TxDeduplicator interface {
+	// TxDedupWindow returns the dedup window of the transactions of the given
+	// block, 0 if they are not deduplicated
+	TxDedupWindow(block *common.Block) (uint64, error)
+}
+
 // ErrUnsupportedTransaction is expected to be thrown if a unsupported query is performed in an update transaction
 type ErrUnsupportedTransaction struct {
 	Msg string
@@ -36,6 +36,15 @@ type Transaction struct {
 	ID             string
 	RWSet          *rwsetutil.TxRwSet
 	ValidationCode peer.TxValidationCode
+	// DedupKey is the dedup key carried by the transaction, nil if it carries
+	// none or the transactions of the block are not deduplicated
+	DedupKey *DedupKey
+}
+
+// DedupKey is the dedup key of a transaction, which is scoped to its chaincode
+type DedupKey struct {
+	Namespace string
+	Key       string
 }
 
 // PubAndHashUpdates encapsulates public and hash updates. The intended use of this to hold the updates
@@ -7,6 +7,7 @@ package statebasedval
 
 import (
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
@@ -98,12 +99,21 @@ func (v *Validator) ValidateAndPrepareBatch(block *internal.Block, doMVCCValidat
 	}
 
 	updates := internal.NewPubAndHashUpdates()
+	// dedup keys of the transactions of the block found valid so far
+	dedupKeys := make(map[internal.DedupKey]struct{})
 	for _, tx := range block.Txs {
 		var validationCode peer.TxValidationCode
 		var err error
 		if validationCode, err = v.validateEndorserTX(tx.RWSet, doMVCCValidation, updates); err != nil {
 			return nil, err
 		}
+		if validationCode == peer.TxValidationCode_VALID && tx.DedupKey != nil {
+			if _, exists := dedupKeys[*tx.DedupKey]; exists {
+				validationCode = peer.TxValidationCode_DUPLICATE_DEDUP_KEY
+			} else {
+				dedupKeys[*tx.DedupKey] = struct{}{}
+			}
+		}
 
 		tx.ValidationCode = validationCode
 		if validationCode == peer.TxValidationCode_VALID {
@@ -163,6 +173,61 @@ func (v *Validator) validateTx(txRWSet *rwsetutil.TxRwSet, updates *internal.Pub
 	return peer.TxValidationCode_VALID, nil
 }
 
+// FindReadConflict returns the first read of the given simulation results which conflicts
+// with the committed state, checking the reads in the same order as the validation of a block
+func (v *Validator) FindReadConflict(txRWSet *rwsetutil.TxRwSet) (*ledger.ReadConflict, error) {
+	// the reads are checked against the committed state only
+	updates := internal.NewPubAndHashUpdates()
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		ns := nsRWSet.NameSpace
+		for _, kvRead := range nsRWSet.KvRwSet.Reads {
+			valid, err := v.validateKVRead(ns, kvRead, updates.PubUpdates)
+			if err != nil {
+				return nil, err
+			}
+			if !valid {
+				return &ledger.ReadConflict{
+					ValidationCode: peer.TxValidationCode_MVCC_READ_CONFLICT,
+					Namespace:      ns,
+					Key:            kvRead.Key,
+				}, nil
+			}
+		}
+		for _, rqi := range nsRWSet.KvRwSet.RangeQueriesInfo {
+			valid, err := v.validateRangeQuery(ns, rqi, updates.PubUpdates)
+			if err != nil {
+				return nil, err
+			}
+			if !valid {
+				return &ledger.ReadConflict{
+					ValidationCode: peer.TxValidationCode_PHANTOM_READ_CONFLICT,
+					Namespace:      ns,
+					Key:            rqi.StartKey,
+					EndKey:         rqi.EndKey,
+				}, nil
+			}
+		}
+		for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+			coll := collHashedRWSet.CollectionName
+			for _, kvReadHash := range collHashedRWSet.HashedRwSet.HashedReads {
+				valid, err := v.validateKVReadHash(ns, coll, kvReadHash, updates.HashUpdates)
+				if err != nil {
+					return nil, err
+				}
+				if !valid {
+					return &ledger.ReadConflict{
+						ValidationCode: peer.TxValidationCode_MVCC_READ_CONFLICT,
+						Namespace:      ns,
+						Collection:     coll,
+						KeyHash:        kvReadHash.KeyHash,
+					}, nil
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 /////                 Validation of public read-set
 ////////////////////////////////////////////////////////////////////////////////
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
@@ -221,6 +222,51 @@ func TestValidator(t *testing.T) {
 	checkValidation(t, validator, getTestPubSimulationRWSet(t, rwsetBuilder4, rwsetBuilder5), []int{1})
 }
 
+func TestDedupKeyValidation(t *testing.T) {
+	testDBEnv := privacyenabledstate.LevelDBCommonStorageTestEnv{}
+	testDBEnv.Init(t)
+	defer testDBEnv.Cleanup()
+	db := testDBEnv.GetDBHandle("TestDB")
+
+	batch := privacyenabledstate.NewUpdateBatch()
+	batch.PubUpdates.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 0))
+	db.ApplyPrivacyAwareUpdates(batch, version.NewHeight(1, 0))
+
+	validator := NewValidator(db)
+
+	// tx0 is invalid for a read conflict, which leaves tx1 as the first valid
+	// transaction carrying the dedup key, and makes tx2 a duplicate of it
+	rwsetBuilder0 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder0.AddToReadSet("ns1", "key1", version.NewHeight(1, 1))
+	rwsetBuilder0.AddToWriteSet("ns1", "key2", []byte("value2_tx0"))
+	rwsetBuilder1 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder1.AddToWriteSet("ns1", "key2", []byte("value2_tx1"))
+	rwsetBuilder2 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder2.AddToWriteSet("ns1", "key2", []byte("value2_tx2"))
+	rwsetBuilder3 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder3.AddToWriteSet("ns1", "key3", []byte("value3"))
+
+	dedupKey := &internal.DedupKey{Namespace: "ns1", Key: "transfer-1"}
+	var trans []*internal.Transaction
+	for i, txRWSet := range getTestPubSimulationRWSet(t, rwsetBuilder0, rwsetBuilder1, rwsetBuilder2, rwsetBuilder3) {
+		trans = append(trans, &internal.Transaction{
+			ID:           fmt.Sprintf("txid-%d", i),
+			IndexInBlock: i,
+			RWSet:        txRWSet,
+			DedupKey:     dedupKey,
+		})
+	}
+	trans[3].DedupKey = &internal.DedupKey{Namespace: "ns2", Key: "transfer-1"}
+	block := &internal.Block{Num: 2, Txs: trans}
+	updates, err := validator.ValidateAndPrepareBatch(block, true)
+	assert.NoError(t, err)
+	assert.Equal(t, peer.TxValidationCode_MVCC_READ_CONFLICT, block.Txs[0].ValidationCode)
+	assert.Equal(t, peer.TxValidationCode_VALID, block.Txs[1].ValidationCode)
+	assert.Equal(t, peer.TxValidationCode_DUPLICATE_DEDUP_KEY, block.Txs[2].ValidationCode)
+	assert.Equal(t, peer.TxValidationCode_VALID, block.Txs[3].ValidationCode)
+	assert.Equal(t, []byte("value2_tx1"), updates.PubUpdates.Get("ns1", "key2").Value)
+}
+
 func TestPhantomValidation(t *testing.T) {
 	testDBEnv := privacyenabledstate.LevelDBCommonStorageTestEnv{}
 	testDBEnv.Init(t)
@@ -346,6 +392,72 @@ func TestPhantomHashBasedValidation(t *testing.T) {
 	checkValidation(t, validator, getTestPubSimulationRWSet(t, rwsetBuilder2), []int{0})
 }
 
+func TestFindReadConflict(t *testing.T) {
+	testDBEnv := privacyenabledstate.LevelDBCommonStorageTestEnv{}
+	testDBEnv.Init(t)
+	defer testDBEnv.Cleanup()
+	db := testDBEnv.GetDBHandle("TestDB")
+
+	//populate db with initial data
+	batch := privacyenabledstate.NewUpdateBatch()
+	batch.PubUpdates.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 0))
+	batch.PubUpdates.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 1))
+	batch.PubUpdates.Put("ns1", "key3", []byte("value3"), version.NewHeight(1, 2))
+	batch.HashUpdates.Put("ns2", "coll1", util.ComputeStringHash("pvtKey1"), []byte("value1"), version.NewHeight(1, 3))
+	db.ApplyPrivacyAwareUpdates(batch, version.NewHeight(1, 3))
+
+	validator := NewValidator(db)
+	findConflict := func(b *rwsetutil.RWSetBuilder) *ledger.ReadConflict {
+		conflict, err := validator.FindReadConflict(getTestPubSimulationRWSet(t, b)[0])
+		assert.NoError(t, err)
+		return conflict
+	}
+
+	// reads of the committed versions don't conflict
+	rwsetBuilder1 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder1.AddToReadSet("ns1", "key1", version.NewHeight(1, 0))
+	rqi1 := &kvrwset.RangeQueryInfo{StartKey: "key2", EndKey: "key4", ItrExhausted: true}
+	rqi1.SetRawReads([]*kvrwset.KVRead{
+		rwsetutil.NewKVRead("key2", version.NewHeight(1, 1)),
+		rwsetutil.NewKVRead("key3", version.NewHeight(1, 2))})
+	rwsetBuilder1.AddToRangeQuerySet("ns1", rqi1)
+	rwsetBuilder1.AddToHashedReadSet("ns2", "coll1", "pvtKey1", version.NewHeight(1, 3))
+	rwsetBuilder1.AddToWriteSet("ns1", "key1", []byte("value1_1"))
+	assert.Nil(t, findConflict(rwsetBuilder1))
+
+	// a stale read of a key conflicts
+	rwsetBuilder2 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder2.AddToReadSet("ns1", "key1", version.NewHeight(1, 0))
+	rwsetBuilder2.AddToReadSet("ns1", "key2", version.NewHeight(1, 0))
+	assert.Equal(t, &ledger.ReadConflict{
+		ValidationCode: peer.TxValidationCode_MVCC_READ_CONFLICT,
+		Namespace:      "ns1",
+		Key:            "key2",
+	}, findConflict(rwsetBuilder2))
+
+	// a range query missing a committed key conflicts
+	rwsetBuilder3 := rwsetutil.NewRWSetBuilder()
+	rqi3 := &kvrwset.RangeQueryInfo{StartKey: "key2", EndKey: "key4", ItrExhausted: true}
+	rqi3.SetRawReads([]*kvrwset.KVRead{rwsetutil.NewKVRead("key2", version.NewHeight(1, 1))})
+	rwsetBuilder3.AddToRangeQuerySet("ns1", rqi3)
+	assert.Equal(t, &ledger.ReadConflict{
+		ValidationCode: peer.TxValidationCode_PHANTOM_READ_CONFLICT,
+		Namespace:      "ns1",
+		Key:            "key2",
+		EndKey:         "key4",
+	}, findConflict(rwsetBuilder3))
+
+	// a stale read of a private key conflicts
+	rwsetBuilder4 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder4.AddToHashedReadSet("ns2", "coll1", "pvtKey1", version.NewHeight(1, 2))
+	assert.Equal(t, &ledger.ReadConflict{
+		ValidationCode: peer.TxValidationCode_MVCC_READ_CONFLICT,
+		Namespace:      "ns2",
+		Collection:     "coll1",
+		KeyHash:        util.ComputeStringHash("pvtKey1"),
+	}, findConflict(rwsetBuilder4))
+}
+
 func checkValidation(t *testing.T, val *Validator, transRWSets []*rwsetutil.TxRwSet, expectedInvalidTxIndexes []int) {
 	var trans []*internal.Transaction
 	for i, tranRWSet := range transRWSets {
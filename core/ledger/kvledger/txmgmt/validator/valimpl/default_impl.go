@@ -23,15 +23,17 @@ var logger = flogging.MustGetLogger("valimpl")
 // and for actual validation of the public rwset, it encloses an internal validator (that implements interface
 // internal.InternalValidator) such as statebased validator
 type DefaultImpl struct {
+	ledgerID          string
 	txmgr             txmgr.TxMgr
 	db                privacyenabledstate.DB
+	valueHasher       txmgr.ValueHasher
 	internalValidator internal.Validator
 }
 
 // NewStatebasedValidator constructs a validator that internally manages statebased validator and in addition
 // handles the tasks that are agnostic to a particular validation scheme such as parsing the block and handling the pvt data
-func NewStatebasedValidator(txmgr txmgr.TxMgr, db privacyenabledstate.DB) validator.Validator {
-	return &DefaultImpl{txmgr, db, statebasedval.NewValidator(db)}
+func NewStatebasedValidator(ledgerID string, txmgr txmgr.TxMgr, db privacyenabledstate.DB, valueHasher txmgr.ValueHasher) validator.Validator {
+	return &DefaultImpl{ledgerID, txmgr, db, valueHasher, statebasedval.NewValidator(db)}
 }
 
 // ValidateAndPrepareBatch implements the function in interface validator.Validator
@@ -44,8 +46,17 @@ func (impl *DefaultImpl) ValidateAndPrepareBatch(blockAndPvtdata *ledger.BlockAn
 	var pvtUpdates *privacyenabledstate.PvtUpdateBatch
 	var err error
 
+	var dedupTxs bool
+	if deduplicator, ok := impl.valueHasher.(txmgr.TxDeduplicator); ok {
+		dedupWindow, err := deduplicator.TxDedupWindow(block)
+		if err != nil {
+			return nil, err
+		}
+		dedupTxs = dedupWindow > 0
+	}
+
 	logger.Debug("preprocessing ProtoBlock...")
-	if internalBlock, err = preprocessProtoBlock(impl.txmgr, impl.db.ValidateKeyValue, block, doMVCCValidation); err != nil {
+	if internalBlock, err = preprocessProtoBlock(impl.txmgr, impl.db.ValidateKeyValue, block, doMVCCValidation, dedupTxs); err != nil {
 		return nil, err
 	}
 
@@ -53,7 +64,11 @@ func (impl *DefaultImpl) ValidateAndPrepareBatch(blockAndPvtdata *ledger.BlockAn
 		return nil, err
 	}
 	logger.Debug("validating rwset...")
-	if pvtUpdates, err = validateAndPreparePvtBatch(internalBlock, impl.db, pubAndHashUpdates, blockAndPvtdata.BlockPvtData); err != nil {
+	valueHashFunc, err := impl.valueHasher.ForBlock(block)
+	if err != nil {
+		return nil, err
+	}
+	if pvtUpdates, err = validateAndPreparePvtBatch(internalBlock, impl.db, pubAndHashUpdates, blockAndPvtdata.BlockPvtData, valueHashFunc); err != nil {
 		return nil, err
 	}
 	logger.Debug("postprocessing ProtoBlock...")
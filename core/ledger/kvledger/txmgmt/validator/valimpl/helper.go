@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"fmt"
 
+	"github.com/hyperledger/fabric/common/crypto/pedersen"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/customtx"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
@@ -28,11 +29,13 @@ import (
 
 // validateAndPreparePvtBatch pulls out the private write-set for the transactions that are marked as valid
 // by the internal public data validator. Finally, it validates (if not already self-endorsed) the pvt rwset against the
-// corresponding hash present in the public rwset
+// corresponding hash present in the public rwset, computed with the given value hash function
 func validateAndPreparePvtBatch(block *internal.Block, db privacyenabledstate.DB,
-	pubAndHashUpdates *internal.PubAndHashUpdates, pvtdata map[uint64]*ledger.TxPvtData) (*privacyenabledstate.PvtUpdateBatch, error) {
+	pubAndHashUpdates *internal.PubAndHashUpdates, pvtdata map[uint64]*ledger.TxPvtData,
+	valueHashFunc func([]byte) []byte) (*privacyenabledstate.PvtUpdateBatch, error) {
 	pvtUpdates := privacyenabledstate.NewPvtUpdateBatch()
 	metadataUpdates := metadataUpdates{}
+	valueCommitments := valueCommitments{}
 	for _, tx := range block.Txs {
 		if tx.ValidationCode != peer.TxValidationCode_VALID {
 			continue
@@ -45,7 +48,7 @@ func validateAndPreparePvtBatch(block *internal.Block, db privacyenabledstate.DB
 			continue
 		}
 		if requiresPvtdataValidation(txPvtdata) {
-			if err := validatePvtdata(tx, txPvtdata); err != nil {
+			if err := validatePvtdata(tx, txPvtdata, valueHashFunc); err != nil {
 				return nil, err
 			}
 		}
@@ -56,8 +59,9 @@ func validateAndPreparePvtBatch(block *internal.Block, db privacyenabledstate.DB
 		}
 		addPvtRWSetToPvtUpdateBatch(pvtRWSet, pvtUpdates, version.NewHeight(block.Num, uint64(tx.IndexInBlock)))
 		addEntriesToMetadataUpdates(metadataUpdates, pvtRWSet)
+		addEntriesToValueCommitments(valueCommitments, tx, pvtRWSet)
 	}
-	if err := incrementPvtdataVersionIfNeeded(metadataUpdates, pvtUpdates, pubAndHashUpdates, db); err != nil {
+	if err := incrementPvtdataVersionIfNeeded(metadataUpdates, valueCommitments, pvtUpdates, pubAndHashUpdates, db, valueHashFunc); err != nil {
 		return nil, err
 	}
 	return pvtUpdates, nil
@@ -73,14 +77,14 @@ func requiresPvtdataValidation(tx *ledger.TxPvtData) bool {
 
 // validPvtdata returns true if hashes of all the collections writeset present in the pvt data
 // match with the corresponding hashes present in the public read-write set
-func validatePvtdata(tx *internal.Transaction, pvtdata *ledger.TxPvtData) error {
+func validatePvtdata(tx *internal.Transaction, pvtdata *ledger.TxPvtData, valueHashFunc func([]byte) []byte) error {
 	if pvtdata.WriteSet == nil {
 		return nil
 	}
 
 	for _, nsPvtdata := range pvtdata.WriteSet.NsPvtRwset {
 		for _, collPvtdata := range nsPvtdata.CollectionPvtRwset {
-			collPvtdataHash := util.ComputeHash(collPvtdata.Rwset)
+			collPvtdataHash := valueHashFunc(collPvtdata.Rwset)
 			hashInPubdata := tx.RetrieveHash(nsPvtdata.Namespace, collPvtdata.CollectionName)
 			if !bytes.Equal(collPvtdataHash, hashInPubdata) {
 				return &validator.ErrPvtdataHashMissmatch{
@@ -94,9 +98,10 @@ func validatePvtdata(tx *internal.Transaction, pvtdata *ledger.TxPvtData) error
 }
 
 // preprocessProtoBlock parses the proto instance of block into 'Block' structure.
-// The retuned 'Block' structure contains only transactions that are endorser transactions and are not alredy marked as invalid
+// The retuned 'Block' structure contains only transactions that are endorser transactions and are not alredy marked as invalid.
+// The dedup keys of the transactions are extracted when dedupTxs is true
 func preprocessProtoBlock(txmgr txmgr.TxMgr, validateKVFunc func(key string, value []byte) error,
-	block *common.Block, doMVCCValidation bool) (*internal.Block, error) {
+	block *common.Block, doMVCCValidation bool, dedupTxs bool) (*internal.Block, error) {
 	b := &internal.Block{Num: block.Header.Number}
 	// Committer validator has already set validation flags based on well formed tran checks
 	txsFilter := util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
@@ -123,6 +128,7 @@ func preprocessProtoBlock(txmgr txmgr.TxMgr, validateKVFunc func(key string, val
 		}
 
 		var txRWSet *rwsetutil.TxRwSet
+		var dedupKey *internal.DedupKey
 		txType := common.HeaderType(chdr.Type)
 		logger.Debugf("txType=%s", txType)
 		if txType == common.HeaderType_ENDORSER_TRANSACTION {
@@ -137,6 +143,11 @@ func preprocessProtoBlock(txmgr txmgr.TxMgr, validateKVFunc func(key string, val
 				txsFilter.SetFlag(txIndex, peer.TxValidationCode_INVALID_OTHER_REASON)
 				continue
 			}
+			if dedupTxs {
+				if ext, err := utils.GetChaincodeHeaderExtension(payload.Header); err == nil && ext.DedupKey != "" && ext.ChaincodeId != nil {
+					dedupKey = &internal.DedupKey{Namespace: ext.ChaincodeId.Name, Key: ext.DedupKey}
+				}
+			}
 		} else {
 			rwsetProto, err := processNonEndorserTx(env, chdr.TxId, txType, txmgr, !doMVCCValidation)
 			if _, ok := err.(*customtx.InvalidTxError); ok {
@@ -160,7 +171,7 @@ func preprocessProtoBlock(txmgr txmgr.TxMgr, validateKVFunc func(key string, val
 				txsFilter.SetFlag(txIndex, peer.TxValidationCode_INVALID_WRITESET)
 				continue
 			}
-			b.Txs = append(b.Txs, &internal.Transaction{IndexInBlock: txIndex, ID: chdr.TxId, RWSet: txRWSet})
+			b.Txs = append(b.Txs, &internal.Transaction{IndexInBlock: txIndex, ID: chdr.TxId, RWSet: txRWSet, DedupKey: dedupKey})
 		}
 	}
 	return b, nil
@@ -235,9 +246,11 @@ func addPvtRWSetToPvtUpdateBatch(pvtRWSet *rwsetutil.TxPvtRwSet, pvtUpdateBatch
 // gets left as stale and will cause simulation failure because of wrongly assuming that we have stale value
 func incrementPvtdataVersionIfNeeded(
 	metadataUpdates metadataUpdates,
+	valueCommitments valueCommitments,
 	pvtUpdateBatch *privacyenabledstate.PvtUpdateBatch,
 	pubAndHashUpdates *internal.PubAndHashUpdates,
-	db privacyenabledstate.DB) error {
+	db privacyenabledstate.DB,
+	valueHashFunc func([]byte) []byte) error {
 
 	for collKey := range metadataUpdates {
 		ns, coll, key := collKey.ns, collKey.coll, collKey.key
@@ -259,8 +272,11 @@ func incrementPvtdataVersionIfNeeded(
 		}
 		// TODO - computing hash could be avoided. In the hashed updates, we can augment additional info that
 		// which original version has been renewed
-		latestValHash := util.ComputeHash(latestVal.Value)
-		if bytes.Equal(latestValHash, hashedVal.Value) { // since we allow block commits with missing pvt data, the private value available may be stale.
+		matches, err := matchesHashedValue(collKey, latestVal, hashedVal, valueCommitments, pvtUpdateBatch, db, valueHashFunc)
+		if err != nil {
+			return err
+		}
+		if matches { // since we allow block commits with missing pvt data, the private value available may be stale.
 			// upgrade the version only if the pvt value matches with corresponding hash in the hashed space
 			pvtUpdateBatch.Put(ns, coll, key, latestVal.Value, hashedVal.Version)
 		}
@@ -274,6 +290,10 @@ type collKey struct {
 
 type metadataUpdates map[collKey]bool
 
+// valueCommitments are the Pedersen commitments written in the hashed write-sets along with the
+// private values of the block
+type valueCommitments map[collKey][]byte
+
 func addEntriesToMetadataUpdates(metadataUpdates metadataUpdates, pvtRWSet *rwsetutil.TxPvtRwSet) {
 	for _, ns := range pvtRWSet.NsPvtRwSet {
 		for _, coll := range ns.CollPvtRwSets {
@@ -285,6 +305,51 @@ func addEntriesToMetadataUpdates(metadataUpdates metadataUpdates, pvtRWSet *rwse
 	}
 }
 
+// addEntriesToValueCommitments records the Pedersen commitments written by the transaction along with
+// its private values, and forgets those of the values it overwrites without commitments
+func addEntriesToValueCommitments(valueCommitments valueCommitments, tx *internal.Transaction, pvtRWSet *rwsetutil.TxPvtRwSet) {
+	commitments := map[collKey][]byte{} // by the hashes of the keys
+	for _, ns := range tx.RWSet.NsRwSets {
+		for _, coll := range ns.CollHashedRwSets {
+			for _, write := range coll.HashedRwSet.GetHashedWrites() {
+				if len(write.ValueHash) == pedersen.CommitmentSize {
+					commitments[collKey{ns.NameSpace, coll.CollectionName, string(write.KeyHash)}] = write.ValueHash
+				}
+			}
+		}
+	}
+	for _, ns := range pvtRWSet.NsPvtRwSet {
+		for _, coll := range ns.CollPvtRwSets {
+			for _, kvwrite := range coll.KvRwSet.Writes {
+				commitment := commitments[collKey{ns.NameSpace, coll.CollectionName, string(util.ComputeStringHash(kvwrite.Key))}]
+				if commitment != nil {
+					valueCommitments[collKey{ns.NameSpace, coll.CollectionName, kvwrite.Key}] = commitment
+				} else {
+					delete(valueCommitments, collKey{ns.NameSpace, coll.CollectionName, kvwrite.Key})
+				}
+			}
+		}
+	}
+}
+
+// matchesHashedValue returns whether the latest private value of a key is the one whose hash is in the hashed space.
+// The peers cannot compute the Pedersen commitments to the values without the blinding factors, chosen by the clients,
+// so a value matches a commitment if it was written along with it, either by the block or at the committed version
+func matchesHashedValue(key collKey, latestVal, hashedVal *statedb.VersionedValue, valueCommitments valueCommitments,
+	pvtUpdateBatch *privacyenabledstate.PvtUpdateBatch, db privacyenabledstate.DB, valueHashFunc func([]byte) []byte) (bool, error) {
+	if len(hashedVal.Value) != pedersen.CommitmentSize {
+		return bytes.Equal(valueHashFunc(latestVal.Value), hashedVal.Value), nil
+	}
+	if pvtUpdateBatch.Get(key.ns, key.coll, key.key) != nil {
+		return bytes.Equal(valueCommitments[key], hashedVal.Value), nil
+	}
+	committedVal, err := db.GetValueHash(key.ns, key.coll, util.ComputeStringHash(key.key))
+	if err != nil || committedVal == nil {
+		return false, err
+	}
+	return version.AreSame(committedVal.Version, latestVal.Version) && bytes.Equal(committedVal.Value, hashedVal.Value), nil
+}
+
 func retrieveLatestVal(ns, coll, key string, pvtUpdateBatch *privacyenabledstate.PvtUpdateBatch,
 	db privacyenabledstate.DB) (val *statedb.VersionedValue, err error) {
 	val = pvtUpdateBatch.Get(ns, coll, key)
@@ -7,10 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package valimpl
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"testing"
 
+	"github.com/hyperledger/fabric/common/crypto/pedersen"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/flogging/floggingtest"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
@@ -19,6 +21,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validator"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validator/internal"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	lutils "github.com/hyperledger/fabric/core/ledger/util"
@@ -108,7 +111,7 @@ func TestValidateAndPreparePvtBatch(t *testing.T) {
 	alwaysValidKVFunc := func(key string, value []byte) error {
 		return nil
 	}
-	actualPreProcessedBlock, err := preprocessProtoBlock(nil, alwaysValidKVFunc, block, false)
+	actualPreProcessedBlock, err := preprocessProtoBlock(nil, alwaysValidKVFunc, block, false, false)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedPerProcessedBlock, actualPreProcessedBlock)
 
@@ -128,7 +131,7 @@ func TestValidateAndPreparePvtBatch(t *testing.T) {
 	assert.NoError(t, err)
 	addPvtRWSetToPvtUpdateBatch(tx1TxPvtRWSet, expectedPvtUpdates, version.NewHeight(uint64(10), uint64(0)))
 
-	actualPvtUpdates, err := validateAndPreparePvtBatch(mvccValidatedBlock, testDB, nil, pvtDataMap)
+	actualPvtUpdates, err := validateAndPreparePvtBatch(mvccValidatedBlock, testDB, nil, pvtDataMap, lutils.ComputeHash)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedPvtUpdates, actualPvtUpdates)
 
@@ -138,6 +141,21 @@ func TestValidateAndPreparePvtBatch(t *testing.T) {
 	assert.Equal(t, expectedtxsFilter, block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
 }
 
+func TestValidatePvtdataWithValueHashFunc(t *testing.T) {
+	rwsetBuilder := rwsetutil.NewRWSetBuilderWithValueHashFunc(util.ComputeSHA3256)
+	rwsetBuilder.AddToPvtAndHashedWriteSet("ns1", "coll1", "key1", []byte("value1"))
+	simRes, err := rwsetBuilder.GetTxSimulationResults()
+	assert.NoError(t, err)
+	txRWSet, err := rwsetutil.TxRwSetFromProtoMsg(simRes.PubSimulationResults)
+	assert.NoError(t, err)
+	tx := &internal.Transaction{IndexInBlock: 0, ID: "tx1", RWSet: txRWSet}
+	pvtdata := &ledger.TxPvtData{SeqInBlock: 0, WriteSet: simRes.PvtSimulationResults}
+
+	assert.NoError(t, validatePvtdata(tx, pvtdata, util.ComputeSHA3256))
+	err = validatePvtdata(tx, pvtdata, lutils.ComputeHash)
+	assert.IsType(t, &validator.ErrPvtdataHashMissmatch{}, err)
+}
+
 func TestPreprocessProtoBlock(t *testing.T) {
 	allwaysValidKVfunc := func(key string, value []byte) error {
 		return nil
@@ -145,21 +163,21 @@ func TestPreprocessProtoBlock(t *testing.T) {
 	// good block
 	//_, gb := testutil.NewBlockGenerator(t, "testLedger", false)
 	gb := testutil.ConstructTestBlock(t, 10, 1, 1)
-	_, err := preprocessProtoBlock(nil, allwaysValidKVfunc, gb, false)
+	_, err := preprocessProtoBlock(nil, allwaysValidKVfunc, gb, false, false)
 	assert.NoError(t, err)
 	// bad envelope
 	gb = testutil.ConstructTestBlock(t, 11, 1, 1)
 	gb.Data = &common.BlockData{Data: [][]byte{{123}}}
 	gb.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] =
 		lutils.NewTxValidationFlagsSetValue(len(gb.Data.Data), peer.TxValidationCode_VALID)
-	_, err = preprocessProtoBlock(nil, allwaysValidKVfunc, gb, false)
+	_, err = preprocessProtoBlock(nil, allwaysValidKVfunc, gb, false, false)
 	assert.Error(t, err)
 	t.Log(err)
 	// bad payload
 	gb = testutil.ConstructTestBlock(t, 12, 1, 1)
 	envBytes, _ := putils.GetBytesEnvelope(&common.Envelope{Payload: []byte{123}})
 	gb.Data = &common.BlockData{Data: [][]byte{envBytes}}
-	_, err = preprocessProtoBlock(nil, allwaysValidKVfunc, gb, false)
+	_, err = preprocessProtoBlock(nil, allwaysValidKVfunc, gb, false, false)
 	assert.Error(t, err)
 	t.Log(err)
 	// bad channel header
@@ -169,7 +187,7 @@ func TestPreprocessProtoBlock(t *testing.T) {
 	})
 	envBytes, _ = putils.GetBytesEnvelope(&common.Envelope{Payload: payloadBytes})
 	gb.Data = &common.BlockData{Data: [][]byte{envBytes}}
-	_, err = preprocessProtoBlock(nil, allwaysValidKVfunc, gb, false)
+	_, err = preprocessProtoBlock(nil, allwaysValidKVfunc, gb, false, false)
 	assert.Error(t, err)
 	t.Log(err)
 
@@ -183,7 +201,7 @@ func TestPreprocessProtoBlock(t *testing.T) {
 	flags := lutils.NewTxValidationFlags(len(gb.Data.Data))
 	flags.SetFlag(0, peer.TxValidationCode_BAD_CHANNEL_HEADER)
 	gb.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = flags
-	_, err = preprocessProtoBlock(nil, allwaysValidKVfunc, gb, false)
+	_, err = preprocessProtoBlock(nil, allwaysValidKVfunc, gb, false, false)
 	assert.NoError(t, err) // invalid filter should take precendence
 
 	// new block
@@ -201,7 +219,7 @@ func TestPreprocessProtoBlock(t *testing.T) {
 	l, recorder := floggingtest.NewTestLogger(t)
 	logger = l
 
-	_, err = preprocessProtoBlock(nil, allwaysValidKVfunc, gb, false)
+	_, err = preprocessProtoBlock(nil, allwaysValidKVfunc, gb, false, false)
 	assert.NoError(t, err)
 	expected := fmt.Sprintf(
 		"Channel [%s]: Block [%d] Transaction index [%d] TxId [%s] marked as invalid by committer. Reason code [%s]",
@@ -238,7 +256,7 @@ func TestPreprocessProtoBlockInvalidWriteset(t *testing.T) {
 	assert.True(t, txfilter.IsValid(0))
 	assert.True(t, txfilter.IsValid(1)) // both txs are valid initially at the time of block cutting
 
-	internalBlock, err := preprocessProtoBlock(nil, kvValidationFunc, block, false)
+	internalBlock, err := preprocessProtoBlock(nil, kvValidationFunc, block, false, false)
 	assert.NoError(t, err)
 	assert.False(t, txfilter.IsValid(0)) // tx at index 0 should be marked as invalid
 	assert.True(t, txfilter.IsValid(1))  // tx at index 1 should be marked as valid
@@ -277,7 +295,7 @@ func TestIncrementPvtdataVersionIfNeeded(t *testing.T) {
 	metadataUpdates := metadataUpdates{collKey{"ns", "coll1", "key1"}: true, collKey{"ns", "coll2", "key2"}: true}
 
 	// invoke function and test results
-	err := incrementPvtdataVersionIfNeeded(metadataUpdates, pvtUpdateBatch, pubAndHashedUpdatesBatch, testDB)
+	err := incrementPvtdataVersionIfNeeded(metadataUpdates, valueCommitments{}, pvtUpdateBatch, pubAndHashedUpdatesBatch, testDB, lutils.ComputeHash)
 	assert.NoError(t, err)
 
 	assert.Equal(t,
@@ -296,6 +314,85 @@ func TestIncrementPvtdataVersionIfNeeded(t *testing.T) {
 	)
 }
 
+func TestIncrementPvtdataVersionIfNeededWithValueCommitments(t *testing.T) {
+	testDBEnv := &privacyenabledstate.LevelDBCommonStorageTestEnv{}
+	testDBEnv.Init(t)
+	defer testDBEnv.Cleanup()
+	testDB := testDBEnv.GetDBHandle("testdb")
+
+	commit := func(value string) []byte {
+		commitment, err := pedersen.Commit([]byte(value), bytes.Repeat([]byte{1}, pedersen.BlindingFactorSize))
+		assert.NoError(t, err)
+		return commitment
+	}
+	txWithPvtWrites := func(coll, key, value string) (*internal.Transaction, *rwsetutil.TxPvtRwSet) {
+		b := rwsetutil.NewRWSetBuilder()
+		b.AddToPvtAndHashedWriteSetWithValueHashFunc("ns", coll, key, []byte(value), func([]byte) []byte { return commit(value) })
+		simResults, err := b.GetTxSimulationResults()
+		assert.NoError(t, err)
+		txRWSet, err := rwsetutil.TxRwSetFromProtoMsg(simResults.PubSimulationResults)
+		assert.NoError(t, err)
+		pvtRWSet, err := rwsetutil.TxPvtRwSetFromProtoMsg(simResults.PvtSimulationResults)
+		assert.NoError(t, err)
+		return &internal.Transaction{RWSet: txRWSet}, pvtRWSet
+	}
+
+	// populate db with some pvt data and their commitments
+	updateBatch := privacyenabledstate.NewUpdateBatch()
+	updateBatch.PvtUpdates.Put("ns", "coll1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	updateBatch.HashUpdates.Put("ns", "coll1", lutils.ComputeStringHash("key1"), commit("value1"), version.NewHeight(1, 1))
+	updateBatch.PvtUpdates.Put("ns", "coll4", "key4", []byte("value4"), version.NewHeight(1, 2))
+	updateBatch.HashUpdates.Put("ns", "coll4", lutils.ComputeStringHash("key4"), commit("value4_set_by_missing_tx"), version.NewHeight(1, 3))
+	testDB.ApplyPrivacyAwareUpdates(updateBatch, version.NewHeight(1, 3))
+
+	// for the current block, mimic the resultant hashed updates
+	hashUpdates := privacyenabledstate.NewHashedUpdateBatch()
+	hashUpdates.PutValHashAndMetadata("ns", "coll1", lutils.ComputeStringHash("key1"),
+		commit("value1"), []byte("metadata1_set_by_tx1"), version.NewHeight(2, 1)) // only metadata set by tx1
+	hashUpdates.PutValHashAndMetadata("ns", "coll2", lutils.ComputeStringHash("key2"),
+		commit("value2_set_by_tx2"), []byte("metadata2_set_by_tx3"), version.NewHeight(2, 3)) // value set by tx2 and metadata by tx3
+	hashUpdates.PutValHashAndMetadata("ns", "coll3", lutils.ComputeStringHash("key3"),
+		commit("value3_set_by_tx5"), []byte("metadata3_set_by_tx6"), version.NewHeight(2, 6)) // value set by tx5 and metadata by tx6
+	hashUpdates.PutValHashAndMetadata("ns", "coll4", lutils.ComputeStringHash("key4"),
+		commit("value4_set_by_missing_tx"), []byte("metadata4_set_by_tx7"), version.NewHeight(2, 7)) // only metadata set by tx7
+	pubAndHashedUpdatesBatch := &internal.PubAndHashUpdates{HashUpdates: hashUpdates}
+
+	// for the current block, mimic the resultant pvt updates. Assume that Tx5 pvt data is missing
+	pvtUpdateBatch := privacyenabledstate.NewPvtUpdateBatch()
+	commitments := valueCommitments{}
+	tx2, pvtRWSet2 := txWithPvtWrites("coll2", "key2", "value2_set_by_tx2")
+	pvtUpdateBatch.Put("ns", "coll2", "key2", []byte("value2_set_by_tx2"), version.NewHeight(2, 2))
+	addEntriesToValueCommitments(commitments, tx2, pvtRWSet2)
+	tx4, pvtRWSet4 := txWithPvtWrites("coll3", "key3", "value3_set_by_tx4")
+	pvtUpdateBatch.Put("ns", "coll3", "key3", []byte("value3_set_by_tx4"), version.NewHeight(2, 4))
+	addEntriesToValueCommitments(commitments, tx4, pvtRWSet4)
+	assert.Equal(t, valueCommitments{
+		collKey{"ns", "coll2", "key2"}: commit("value2_set_by_tx2"),
+		collKey{"ns", "coll3", "key3"}: commit("value3_set_by_tx4"),
+	}, commitments)
+
+	metadataUpdates := metadataUpdates{
+		collKey{"ns", "coll1", "key1"}: true, collKey{"ns", "coll2", "key2"}: true,
+		collKey{"ns", "coll3", "key3"}: true, collKey{"ns", "coll4", "key4"}: true,
+	}
+	err := incrementPvtdataVersionIfNeeded(metadataUpdates, commitments, pvtUpdateBatch, pubAndHashedUpdatesBatch, testDB, lutils.ComputeHash)
+	assert.NoError(t, err)
+
+	assert.Equal(t,
+		&statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(2, 1)}, // key1 committed along with its commitment
+		pvtUpdateBatch.Get("ns", "coll1", "key1"),
+	)
+	assert.Equal(t,
+		&statedb.VersionedValue{Value: []byte("value2_set_by_tx2"), Version: version.NewHeight(2, 3)}, // key2 written by the block along with its commitment
+		pvtUpdateBatch.Get("ns", "coll2", "key2"),
+	)
+	assert.Equal(t,
+		&statedb.VersionedValue{Value: []byte("value3_set_by_tx4"), Version: version.NewHeight(2, 4)}, // key3 unaffected since the tx5 was missing from pvt data
+		pvtUpdateBatch.Get("ns", "coll3", "key3"),
+	)
+	assert.Nil(t, pvtUpdateBatch.Get("ns", "coll4", "key4")) // key4 stale since the commitment was committed at another version
+}
+
 // from go-logging memory_test.go
 func memoryRecordN(b *logging.MemoryBackend, n int) *logging.Record {
 	node := b.Head()
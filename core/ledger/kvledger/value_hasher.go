@@ -0,0 +1,133 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"fmt"
+
+	"sync"
+
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/core/ledger/ledgerstorage"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// valueHasher implements `txmgr.ValueHasher`, hashing the values of the
+// write-sets of a block with the algorithm selected by the config block its
+// LAST_CONFIG metadata points to. It also implements `txmgr.TxDeduplicator`,
+// as the same config block selects the transaction dedup window of the block
+type valueHasher struct {
+	ledgerID   string
+	blockStore *ledgerstorage.Store
+
+	mutex sync.Mutex
+	// configBlockNum, hashFunc and dedupWindow are the last config block
+	// resolved, its hash function and its transaction dedup window
+	configBlockNum uint64
+	hashFunc       func([]byte) []byte
+	dedupWindow    uint64
+	// latest is the hash function of the last block committed, nil until it is known
+	latest func([]byte) []byte
+}
+
+func newValueHasher(ledgerID string, blockStore *ledgerstorage.Store) *valueHasher {
+	return &valueHasher{ledgerID: ledgerID, blockStore: blockStore}
+}
+
+// ForBlock implements method in interface `txmgr.ValueHasher`
+func (h *valueHasher) ForBlock(block *common.Block) (func([]byte) []byte, error) {
+	hashFunc, _, err := h.resolve(block)
+	return hashFunc, err
+}
+
+// TxDedupWindow implements method in interface `txmgr.TxDeduplicator`
+func (h *valueHasher) TxDedupWindow(block *common.Block) (uint64, error) {
+	_, dedupWindow, err := h.resolve(block)
+	return dedupWindow, err
+}
+
+// resolve returns the hash function and the transaction dedup window selected by the
+// config block the LAST_CONFIG metadata of the given block points to
+func (h *valueHasher) resolve(block *common.Block) (func([]byte) []byte, uint64, error) {
+	configBlockNum, err := utils.GetLastConfigIndexFromBlock(block)
+	if err != nil {
+		return nil, 0, errors.WithMessage(err, "error reading the last config index of the block")
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.hashFunc != nil && h.configBlockNum == configBlockNum {
+		return h.hashFunc, h.dedupWindow, nil
+	}
+	configBlock := block
+	if configBlockNum != block.Header.Number {
+		if configBlock, err = h.blockStore.RetrieveBlockByNumber(configBlockNum); err != nil {
+			return nil, 0, errors.WithMessage(err, fmt.Sprintf("error retrieving config block [%d]", configBlockNum))
+		}
+	}
+	hashFunc := util.ComputeHash
+	var dedupWindow uint64
+	// the blocks of the ledgers which do not start with a config block, only
+	// constructed by tests, are hashed with the default algorithm
+	if utils.IsConfigBlock(configBlock) {
+		if hashFunc, err = channelconfig.RWSetHashFuncFromConfigBlock(configBlock); err != nil {
+			return nil, 0, errors.WithMessage(err, fmt.Sprintf("error reading the write-set hashing algorithm of config block [%d]", configBlockNum))
+		}
+		if dedupWindow, err = channelconfig.TxDedupWindowFromConfigBlock(configBlock); err != nil {
+			return nil, 0, errors.WithMessage(err, fmt.Sprintf("error reading the transaction dedup window of config block [%d]", configBlockNum))
+		}
+	}
+	h.configBlockNum, h.hashFunc, h.dedupWindow = configBlockNum, hashFunc, dedupWindow
+	return hashFunc, dedupWindow, nil
+}
+
+// Latest implements method in interface `txmgr.ValueHasher`
+func (h *valueHasher) Latest() func([]byte) []byte {
+	h.mutex.Lock()
+	latest := h.latest
+	h.mutex.Unlock()
+	if latest != nil {
+		return latest
+	}
+
+	info, err := h.blockStore.GetBlockchainInfo()
+	if err != nil || info.Height == 0 {
+		return util.ComputeHash
+	}
+	lastBlock, err := h.blockStore.RetrieveBlockByNumber(info.Height - 1)
+	if err != nil {
+		logger.Errorf("[%s] Failed to retrieve the last block [%d]: %s", h.ledgerID, info.Height-1, err)
+		return util.ComputeHash
+	}
+	hashFunc, err := h.ForBlock(lastBlock)
+	if err != nil {
+		logger.Errorf("[%s] Failed to resolve the write-set hashing algorithm of block [%d]: %s", h.ledgerID, lastBlock.Header.Number, err)
+		return util.ComputeHash
+	}
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.latest == nil {
+		h.latest = hashFunc
+	}
+	return h.latest
+}
+
+// committed records the hash function of the block committed last, which is
+// used by the transactions simulated from then on
+func (h *valueHasher) committed(block *common.Block) {
+	hashFunc, err := h.ForBlock(block)
+	if err != nil {
+		// resolved again from the block store by the next simulation
+		logger.Errorf("[%s] Failed to resolve the write-set hashing algorithm of block [%d]: %s", h.ledgerID, block.Header.Number, err)
+	}
+	h.mutex.Lock()
+	h.latest = hashFunc
+	h.mutex.Unlock()
+}
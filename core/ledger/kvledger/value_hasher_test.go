@@ -0,0 +1,119 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/capabilities"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/protos/common"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValueHasher(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider := testutilNewProvider(t)
+	defer provider.Close()
+	value := []byte("value")
+
+	// the sample genesis block doesn't select a hashing algorithm
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, err := provider.Create(gb)
+	assert.NoError(t, err)
+	defer ledger.Close()
+	hasher := ledger.(*kvLedger).valueHasher
+	assert.Equal(t, util.ComputeSHA256(value), hasher.Latest()(value))
+	hashFunc, err := hasher.ForBlock(bg.NextBlock([][]byte{}))
+	assert.NoError(t, err)
+	assert.Equal(t, util.ComputeSHA256(value), hashFunc(value))
+
+	// the blocks pointing to a config block selecting BLAKE2b are hashed with BLAKE2b
+	bg, gb = testutil.NewBlockGenerator(t, "testLedger2", false)
+	setRWSetHashingAlgorithm(t, gb, channelconfig.BLAKE2b256)
+	ledger2, err := provider.Create(gb)
+	assert.NoError(t, err)
+	hasher = ledger2.(*kvLedger).valueHasher
+	assert.Equal(t, util.ComputeBLAKE2b256(value), hasher.Latest()(value))
+	hashFunc, err = hasher.ForBlock(bg.NextBlock([][]byte{}))
+	assert.NoError(t, err)
+	assert.Equal(t, util.ComputeBLAKE2b256(value), hashFunc(value))
+
+	// the algorithm is resolved from the block store when the ledger is reopened
+	ledger2.Close()
+	ledger2, err = provider.Open("testLedger2")
+	assert.NoError(t, err)
+	defer ledger2.Close()
+	assert.Equal(t, util.ComputeBLAKE2b256(value), ledger2.(*kvLedger).valueHasher.Latest()(value))
+
+	// the last config index of the block must point to a block of the ledger
+	block := bg.NextBlock([][]byte{})
+	block.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG] = putils.MarshalOrPanic(&common.Metadata{
+		Value: putils.MarshalOrPanic(&common.LastConfig{Index: 10}),
+	})
+	_, err = ledger2.(*kvLedger).valueHasher.ForBlock(block)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error retrieving config block [10]")
+}
+
+func TestValueHasherTxDedupWindow(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider := testutilNewProvider(t)
+	defer provider.Close()
+
+	// the sample genesis block doesn't deduplicate the transactions
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, err := provider.Create(gb)
+	assert.NoError(t, err)
+	defer ledger.Close()
+	dedupWindow, err := ledger.(*kvLedger).valueHasher.TxDedupWindow(bg.NextBlock([][]byte{}))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), dedupWindow)
+
+	bg, gb = testutil.NewBlockGenerator(t, "testLedger2", false)
+	setApplicationValue(t, gb, channelconfig.TxDedupWindowValue(10))
+	ledger2, err := provider.Create(gb)
+	assert.NoError(t, err)
+	defer ledger2.Close()
+	dedupWindow, err = ledger2.(*kvLedger).valueHasher.TxDedupWindow(bg.NextBlock([][]byte{}))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), dedupWindow)
+}
+
+// setRWSetHashingAlgorithm enables the V1_4_2 application capability and the
+// given write-set hashing algorithm in the config of the genesis block
+func setRWSetHashingAlgorithm(t *testing.T, gb *common.Block, algorithm string) {
+	setApplicationValue(t, gb, channelconfig.RWSetHashingAlgorithmValue(algorithm))
+}
+
+// setApplicationValue enables the V1_4_2 application capability and sets the
+// given application value in the config of the genesis block
+func setApplicationValue(t *testing.T, gb *common.Block, value *channelconfig.StandardConfigValue) {
+	env, err := putils.ExtractEnvelope(gb, 0)
+	assert.NoError(t, err)
+	payload, err := putils.UnmarshalPayload(env.Payload)
+	assert.NoError(t, err)
+	configEnv, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	assert.NoError(t, err)
+	appGroup := configEnv.Config.ChannelGroup.Groups[channelconfig.ApplicationGroupKey]
+	appGroup.Values[channelconfig.CapabilitiesKey] = &common.ConfigValue{
+		Value: putils.MarshalOrPanic(channelconfig.CapabilitiesValue(map[string]bool{capabilities.ApplicationV1_4_2: true}).Value()),
+	}
+	appGroup.Values[value.Key()] = &common.ConfigValue{
+		Value: putils.MarshalOrPanic(value.Value()),
+	}
+	payload.Data = putils.MarshalOrPanic(configEnv)
+	env.Payload = putils.MarshalOrPanic(payload)
+	gb.Data.Data[0] = putils.MarshalOrPanic(env)
+	gb.Header.DataHash = gb.Data.Hash()
+}
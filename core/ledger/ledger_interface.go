@@ -8,9 +8,13 @@ package ledger
 
 import (
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/crypto"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
@@ -22,6 +26,12 @@ type Initializer struct {
 	StateListeners                []StateListener
 	DeployedChaincodeInfoProvider DeployedChaincodeInfoProvider
 	MembershipInfoProvider        MembershipInfoProvider
+	// Metrics is the scope of the durations of the commit phases of the
+	// blocks, no metrics are emitted if it is nil
+	Metrics metrics.Scope
+	// SnapshotSigner signs the checkpoints of the state of the snapshots, which
+	// are not signed if it is nil
+	SnapshotSigner crypto.LocalSigner
 }
 
 // PeerLedgerProvider provides handle to ledger instances
@@ -53,6 +63,15 @@ type PeerLedger interface {
 	GetBlockByTxID(txID string) (*common.Block, error)
 	// GetTxValidationCodeByTxID returns reason code of transaction validation
 	GetTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error)
+	// GetTransactionsByCreator returns, in commit order, up to limit transactions created by the given
+	// serialized identity in the blocks starting at startBlockNum. A limit of 0 returns all of them
+	GetTransactionsByCreator(creator []byte, startBlockNum uint64, limit int) ([]*IndexedTransaction, error)
+	// GetBlocksByTimeRange returns up to limit blocks whose time falls within [start, end).
+	// The time of a block is the timestamp of its first transaction. A limit of 0 returns all of them
+	GetBlocksByTimeRange(start, end time.Time, limit int) ([]*common.Block, error)
+	// GetBlockNumByDedupKey returns the number of the block of the last valid transaction carrying
+	// the given dedup key for the given chaincode, or a NotFoundInIndexErr if there is none
+	GetBlockNumByDedupKey(namespace, dedupKey string) (uint64, error)
 	// NewTxSimulator gives handle to a transaction simulator.
 	// A client can obtain more than one 'TxSimulator's for parallel execution.
 	// Any snapshoting/synchronization should be performed at the implementation level if required
@@ -143,6 +162,11 @@ type QueryExecutor interface {
 	ExecuteQueryWithMetadata(namespace, query string, metadata map[string]interface{}) (QueryResultsIterator, error)
 	// GetPrivateData gets the value of a private data item identified by a tuple <namespace, collection, key>
 	GetPrivateData(namespace, collection, key string) ([]byte, error)
+	// GetPrivateDataHash gets the hash of the value of a private data item identified by a tuple <namespace, collection, key>
+	// Function `GetPrivateData` is only meaningful when it is invoked on a peer that is authorized to have the private data
+	// for the collection <namespace, collection>. However, the function `GetPrivateDataHash` can be invoked on any peer
+	// to get the hash of the current value
+	GetPrivateDataHash(namespace, collection, key string) ([]byte, error)
 	// GetPrivateDataMetadata gets the metadata of a private data item identified by a tuple <namespace, collection, key>
 	GetPrivateDataMetadata(namespace, collection, key string) (map[string][]byte, error)
 	// GetPrivateDataMetadataByHash gets the metadata of a private data item identified by a tuple <namespace, collection, keyhash>
@@ -154,6 +178,10 @@ type QueryExecutor interface {
 	// and an empty endKey refers to the last available key. For scanning all the keys, both the startKey and the endKey
 	// can be supplied as empty strings. However, a full scan shuold be used judiciously for performance reasons.
 	// The returned ResultsIterator contains results of type *KV which is defined in protos/ledger/queryresult.
+	// Like GetPrivateData, the iterator returns an error of type ErrPvtdataNotAvailable for a key whose private
+	// data does not match the version of its hash, and a TxSimulator records the returned keys in the hashed read set.
+	// The range itself is not recorded, as the private keys are not available to all the peers; hence phantom
+	// reads are not detected at validation time and a TxSimulator does not allow writes in the same transaction.
 	GetPrivateDataRangeScanIterator(namespace, collection, startKey, endKey string) (commonledger.ResultsIterator, error)
 	// ExecuteQuery executes the given query and returns an iterator that contains results of type specific to the underlying data store.
 	// Only used for state databases that support query
@@ -189,6 +217,9 @@ type TxSimulator interface {
 	ExecuteUpdate(query string) error
 	// SetPrivateData sets the given value to a key in the private data state represented by the tuple <namespace, collection, key>
 	SetPrivateData(namespace, collection, key string, value []byte) error
+	// SetPrivateDataWithBlindingFactor sets the given value to a key of a collection committing to its values with
+	// Pedersen commitments, whose commitment is blinded with the given factor. SetPrivateData fails on such collections.
+	SetPrivateDataWithBlindingFactor(namespace, collection, key string, value, blindingFactor []byte) error
 	// SetPrivateDataMultipleKeys sets the values for multiple keys in the private data space in a single call
 	SetPrivateDataMultipleKeys(namespace, collection string, kvs map[string][]byte) error
 	// DeletePrivateData deletes the given tuple <namespace, collection, key> from private data
@@ -197,6 +228,12 @@ type TxSimulator interface {
 	SetPrivateDataMetadata(namespace, collection, key string, metadata map[string][]byte) error
 	// DeletePrivateDataMetadata deletes the metadata associated with an existing key-tuple <namespace, collection, key>
 	DeletePrivateDataMetadata(namespace, collection, key string) error
+	// GetPrivateDataUnvalidated gets the value of a private data item identified by a tuple <namespace, collection, key>
+	// without recording the read in the hashed read set; hence the version of the value is not checked at validation time,
+	// and the value may even be stale, as the private data of a peer may lag behind the hashes committed on the ledger.
+	// Like the reads of `GetPrivateData`, which are only recorded in the hashed read set, the read does not appear in
+	// the public read set.
+	GetPrivateDataUnvalidated(namespace, collection, key string) ([]byte, error)
 	// GetTxSimulationResults encapsulates the results of the transaction simulation.
 	// This should contain enough detail for
 	// - The update in the state that would be caused if the transaction is to be committed
@@ -217,6 +254,13 @@ type QueryResultsIterator interface {
 	GetBookmarkAndClose() string
 }
 
+// IndexedTransaction encapsulates a committed transaction along with its position in the ledger
+type IndexedTransaction struct {
+	BlockNum    uint64
+	TxNum       uint64
+	Transaction *peer.ProcessedTransaction
+}
+
 // TxPvtData encapsulates the transaction number and pvt write-set for a transaction
 type TxPvtData struct {
 	SeqInBlock uint64
@@ -369,6 +413,54 @@ type MissingPvtDataTracker interface {
 	GetMissingPvtDataInfoForMostRecentBlocks(maxBlocks int) (MissingPvtDataInfo, error)
 }
 
+// StateIndexManager is implemented by the ledgers whose state database supports
+// indexes, to manage the indexes of the state databases of the chaincodes. The
+// operations apply to the databases of the chaincode and of its private data
+// collections, or only to the one of the collection if set
+type StateIndexManager interface {
+	ListStateIndexes(chaincodeName, collection string) ([]*StateIndex, error)
+	CreateStateIndex(chaincodeName, collection string, definition []byte) error
+	RebuildStateIndexes(chaincodeName, collection string) error
+}
+
+// StateIndex is an index of the state database of a chaincode, or of one of
+// its private data collections
+type StateIndex struct {
+	Collection     string
+	DesignDocument string
+	Name           string
+	Definition     string
+}
+
+// StateDBSizer is implemented by the ledgers whose state database can report
+// its approximate size in bytes, so that the growth of the state is monitored
+type StateDBSizer interface {
+	StateDBSize() (int64, error)
+}
+
+// ReadSetChecker is implemented by the ledgers which can check the read set of
+// simulation results against the committed state, so that the transactions which
+// would fail the MVCC checks are detected before they are submitted for ordering
+type ReadSetChecker interface {
+	// FindReadConflict returns the first read of the given marshaled simulation
+	// results which conflicts with the committed state, or nil if there is none
+	FindReadConflict(txRWSetBytes []byte) (*ReadConflict, error)
+}
+
+// ReadConflict describes a read of simulation results which conflicts with the committed state
+type ReadConflict struct {
+	// ValidationCode is the code the transaction would be invalidated with
+	ValidationCode peer.TxValidationCode
+	Namespace      string
+	// Collection and KeyHash are set for a read of private data
+	Collection string
+	KeyHash    []byte
+	// Key is the key read, or the start key of a range query whose results changed
+	Key string
+	// EndKey is the end key of a range query whose results changed
+	EndKey string
+}
+
 // MissingPvtDataInfo is a map of block number to MissingBlockPvtdataInfo
 type MissingPvtDataInfo map[uint64]MissingBlockPvtdataInfo
 
@@ -492,3 +584,55 @@ type MembershipInfoProvider interface {
 }
 
 //go:generate counterfeiter -o mock/deployed_ccinfo_provider.go -fake-name DeployedChaincodeInfoProvider . DeployedChaincodeInfoProvider
+
+// SnapshotLister is implemented by the ledgers which generate snapshots of
+// their state, so that a new peer joins the channel from a recent snapshot
+// instead of replaying all the blocks of the channel
+type SnapshotLister interface {
+	// ListSnapshots returns the snapshots available, by increasing height
+	ListSnapshots() ([]*SnapshotInfo, error)
+}
+
+// SnapshotInfo describes a snapshot of the state of a ledger as of the
+// commit of the block below its height
+type SnapshotInfo struct {
+	Height        uint64
+	Path          string
+	LastBlockHash []byte
+	Timestamp     time.Time
+}
+
+// SnapshotImporter is implemented by the ledger providers which create a
+// ledger from a snapshot listed by a SnapshotLister, at the height of the
+// snapshot. The blocks, the history and the private data below the height are
+// not available from the created ledger.
+type SnapshotImporter interface {
+	// CreateFromSnapshot creates the ledger of the channel of the snapshot
+	// read from the archive, a gzip compressed tar archive of the files of
+	// the directory of the snapshot, once its last block is verified
+	CreateFromSnapshot(snapshotArchive io.Reader, verifier SnapshotVerifier) (PeerLedger, error)
+}
+
+// SnapshotVerifier checks that the last block of a snapshot was produced by
+// the ordering service of the channel, according to the last config block of
+// the snapshot, and that the state checkpoint of the snapshot, the metadata
+// returned by checkpoint.SignState, commits to the hash of its state, before a
+// ledger is created from the snapshot. The state checkpoint is nil if the
+// snapshot has none.
+type SnapshotVerifier func(lastBlock, configBlock *common.Block, stateCheckpoint *common.Metadata, stateHash []byte) error
+
+// IndexLagReporter is implemented by the ledgers which may update their block
+// index and history database asynchronously with the commit of the blocks, so
+// that the clients can tell how far the lookups and history queries may wait
+type IndexLagReporter interface {
+	GetIndexLag() (*IndexLag, error)
+}
+
+// IndexLag reports the numbers of blocks committed to the ledger, indexed and
+// committed to the history database. The history database of a ledger which
+// doesn't maintain one never lags.
+type IndexLag struct {
+	Height           uint64
+	BlockIndexHeight uint64
+	HistoryDBHeight  uint64
+}
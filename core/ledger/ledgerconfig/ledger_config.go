@@ -8,20 +8,39 @@ package ledgerconfig
 
 import (
 	"path/filepath"
+	"time"
 
 	"github.com/hyperledger/fabric/core/config"
+	"github.com/hyperledger/fabric/core/config/reload"
+	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 )
 
+// The state databases of the peer
+const (
+	// StateDatabaseLevelDB is the name of the goleveldb state database
+	StateDatabaseLevelDB = "goleveldb"
+	// StateDatabaseCouchDB is the name of the CouchDB state database
+	StateDatabaseCouchDB = "CouchDB"
+)
+
 //IsCouchDBEnabled exposes the useCouchDB variable
 func IsCouchDBEnabled() bool {
 	stateDatabase := viper.GetString("ledger.state.stateDatabase")
-	if stateDatabase == "CouchDB" {
+	if stateDatabase == StateDatabaseCouchDB {
 		return true
 	}
 	return false
 }
 
+// IsMixedStateDatabasesEnabled returns whether the peer maintains both a
+// goleveldb and a CouchDB state database, the private data of the collections
+// requesting the database other than the configured stateDatabase being
+// stored in the other one
+func IsMixedStateDatabasesEnabled() bool {
+	return viper.GetBool(confMixedStateDatabases)
+}
+
 const confPeerFileSystemPath = "peer.fileSystemPath"
 const confLedgersData = "ledgersData"
 const confLedgerProvider = "ledgerProvider"
@@ -34,9 +53,39 @@ const confPvtdataStore = "pvtdataStore"
 const confTotalQueryLimit = "ledger.state.totalQueryLimit"
 const confInternalQueryLimit = "ledger.state.couchDBConfig.internalQueryLimit"
 const confEnableHistoryDatabase = "ledger.history.enableHistoryDatabase"
+const confAsyncHistoryCommit = "ledger.history.asyncCommit"
+const confAsyncBlockIndexing = "ledger.blockchain.asyncIndexing"
+const confBlockfileSyncPolicy = "ledger.blockchain.sync.policy"
+const confBlockfileSyncMaxDelay = "ledger.blockchain.sync.maxDelay"
+const confBlockPruningRetainBlocks = "ledger.blockchain.pruning.retainBlocks"
+const confBlockPruningArchiveDir = "ledger.blockchain.pruning.archiveDir"
+const confArchiveEnabled = "ledger.archive.enabled"
+const confSnapshotIsolation = "ledger.state.snapshotIsolation"
+const confMixedStateDatabases = "ledger.state.mixedStateDatabases"
 const confMaxBatchSize = "ledger.state.couchDBConfig.maxBatchUpdateSize"
 const confAutoWarmIndexes = "ledger.state.couchDBConfig.autoWarmIndexes"
 const confWarmIndexesAfterNBlocks = "ledger.state.couchDBConfig.warmIndexesAfterNBlocks"
+const confStateCacheSize = "ledger.state.couchDBConfig.cacheSize"
+const confReplicationRole = "ledger.replication.role"
+const confReplicationCommitterAddress = "ledger.replication.committerAddress"
+const confReplicationMaxLag = "ledger.replication.maxLag"
+const confReplicationConsistencyTimeout = "ledger.replication.consistencyTimeout"
+const confSnapshots = "snapshots"
+const confSnapshotsRootDir = "ledger.snapshots.rootDir"
+const confSnapshotsInterval = "ledger.snapshots.interval"
+const confSnapshotsRetain = "ledger.snapshots.retain"
+const confSnapshotsChannels = "ledger.snapshots.channels"
+const confSnapshotsCheckpointPolicy = "ledger.snapshots.checkpointPolicy"
+
+// The roles of the peers sharing a state database
+const (
+	// ReplicationRoleCommitter is the role of the peer committing the blocks
+	// and maintaining the state database shared with read replicas
+	ReplicationRoleCommitter = "committer"
+	// ReplicationRoleReplica is the role of the peers serving the proposals
+	// and queries from the state database maintained by a committer
+	ReplicationRoleReplica = "replica"
+)
 
 // GetRootPath returns the filesystem path.
 // All ledger related contents are expected to be stored under this path
@@ -87,9 +136,9 @@ func GetMaxBlockfileSize() int {
 
 //GetTotalLimit exposes the totalLimit variable
 func GetTotalQueryLimit() int {
-	totalQueryLimit := viper.GetInt(confTotalQueryLimit)
+	totalQueryLimit := reload.GetInt(confTotalQueryLimit)
 	// if queryLimit was unset, default to 10000
-	if !viper.IsSet(confTotalQueryLimit) {
+	if !reload.IsSet(confTotalQueryLimit) {
 		totalQueryLimit = 10000
 	}
 	return totalQueryLimit
@@ -97,9 +146,9 @@ func GetTotalQueryLimit() int {
 
 //GetQueryLimit exposes the queryLimit variable
 func GetInternalQueryLimit() int {
-	internalQueryLimit := viper.GetInt(confInternalQueryLimit)
+	internalQueryLimit := reload.GetInt(confInternalQueryLimit)
 	// if queryLimit was unset, default to 1000
-	if !viper.IsSet(confInternalQueryLimit) {
+	if !reload.IsSet(confInternalQueryLimit) {
 		internalQueryLimit = 1000
 	}
 	return internalQueryLimit
@@ -107,9 +156,9 @@ func GetInternalQueryLimit() int {
 
 //GetMaxBatchUpdateSize exposes the maxBatchUpdateSize variable
 func GetMaxBatchUpdateSize() int {
-	maxBatchUpdateSize := viper.GetInt(confMaxBatchSize)
+	maxBatchUpdateSize := reload.GetInt(confMaxBatchSize)
 	// if maxBatchUpdateSize was unset, default to 500
-	if !viper.IsSet(confMaxBatchSize) {
+	if !reload.IsSet(confMaxBatchSize) {
 		maxBatchUpdateSize = 500
 	}
 	return maxBatchUpdateSize
@@ -125,11 +174,228 @@ func GetPvtdataStorePurgeInterval() uint64 {
 	return uint64(purgeInterval)
 }
 
+// IsArchiveEnabled returns whether the peer retains the full history of its
+// ledgers, including the private data which has expired
+func IsArchiveEnabled() bool {
+	return viper.GetBool(confArchiveEnabled)
+}
+
+// IsReplicationCommitter returns whether the peer publishes the progress of its
+// commits in its state database, for the read replicas sharing it
+func IsReplicationCommitter() bool {
+	return viper.GetString(confReplicationRole) == ReplicationRoleCommitter
+}
+
+// IsReadReplica returns whether the peer reads the state database maintained
+// by a committer, instead of applying the blocks to a state database of its own
+func IsReadReplica() bool {
+	return viper.GetString(confReplicationRole) == ReplicationRoleReplica
+}
+
+// GetReplicationCommitterAddress returns the address of the committer the
+// blocks of a read replica are delivered by
+func GetReplicationCommitterAddress() string {
+	return viper.GetString(confReplicationCommitterAddress)
+}
+
+// GetReplicationMaxLag returns the number of blocks the ledger of a read replica
+// may lag behind the shared state database when serving a proposal
+func GetReplicationMaxLag() uint64 {
+	if !viper.IsSet(confReplicationMaxLag) {
+		return 1
+	}
+	maxLag := viper.GetInt(confReplicationMaxLag)
+	if maxLag < 0 {
+		maxLag = 0
+	}
+	return uint64(maxLag)
+}
+
+// GetReplicationConsistencyTimeout returns how long a read replica waits for
+// its ledger and the shared state database to be consistent before failing a
+// proposal
+func GetReplicationConsistencyTimeout() time.Duration {
+	timeout := viper.GetDuration(confReplicationConsistencyTimeout)
+	if timeout <= 0 {
+		timeout = 3 * time.Second
+	}
+	return timeout
+}
+
+// CheckReplicationConfig returns an error if the replication role of the peer
+// is unknown or can't be fulfilled with its configuration
+func CheckReplicationConfig() error {
+	role := viper.GetString(confReplicationRole)
+	switch role {
+	case "":
+		return nil
+	case ReplicationRoleCommitter, ReplicationRoleReplica:
+	default:
+		return errors.Errorf("unknown replication role [%s], must be %s or %s", role, ReplicationRoleCommitter, ReplicationRoleReplica)
+	}
+	if !IsCouchDBEnabled() {
+		return errors.Errorf("the replication role [%s] requires CouchDB as state database", role)
+	}
+	if IsMixedStateDatabasesEnabled() {
+		// the read replicas would not share the goleveldb state database
+		return errors.Errorf("the replication role [%s] does not support mixed state databases", role)
+	}
+	if role == ReplicationRoleReplica && GetReplicationCommitterAddress() == "" {
+		return errors.Errorf("a read replica requires the address of its committer in %s", confReplicationCommitterAddress)
+	}
+	return nil
+}
+
+// SnapshotPolicy tells how often the state of a channel is snapshotted, and
+// how many of its snapshots are retained
+type SnapshotPolicy struct {
+	// Interval is the number of blocks between two snapshots, 0 if the state
+	// of the channel is not snapshotted
+	Interval uint64
+	// Retain is the number of snapshots retained
+	Retain int
+}
+
+// GetSnapshotsRootDir returns the filesystem path holding the snapshots of the
+// state of the channels
+func GetSnapshotsRootDir() string {
+	if rootDir := config.GetPath(confSnapshotsRootDir); rootDir != "" {
+		return rootDir
+	}
+	return filepath.Join(config.GetPath(confPeerFileSystemPath), confSnapshots)
+}
+
+// GetSnapshotPolicy returns the snapshot policy of the channel, the one of
+// ledger.snapshots unless it is overridden for the channel
+func GetSnapshotPolicy(channelID string) SnapshotPolicy {
+	policy := SnapshotPolicy{Retain: 2}
+	if interval := viper.GetInt(confSnapshotsInterval); interval > 0 {
+		policy.Interval = uint64(interval)
+	}
+	if viper.IsSet(confSnapshotsRetain) {
+		policy.Retain = viper.GetInt(confSnapshotsRetain)
+	}
+
+	var overrides []struct {
+		ChannelID string
+		Interval  *int
+		Retain    *int
+	}
+	// malformed overrides are ignored as the other malformed settings
+	viper.UnmarshalKey(confSnapshotsChannels, &overrides)
+	for _, override := range overrides {
+		if override.ChannelID != channelID {
+			continue
+		}
+		if override.Interval != nil {
+			policy.Interval = 0
+			if *override.Interval > 0 {
+				policy.Interval = uint64(*override.Interval)
+			}
+		}
+		if override.Retain != nil {
+			policy.Retain = *override.Retain
+		}
+	}
+	if policy.Retain < 1 {
+		policy.Retain = 1
+	}
+	return policy
+}
+
+// GetSnapshotCheckpointPolicy returns the channel policy which the signatures
+// of the state checkpoint of a snapshot must satisfy for a peer to join a
+// channel from the snapshot
+func GetSnapshotCheckpointPolicy() string {
+	if policy := viper.GetString(confSnapshotsCheckpointPolicy); policy != "" {
+		return policy
+	}
+	return "/Channel/Application/Readers"
+}
+
+// IsSnapshotIsolationEnabled returns whether the simulations read the state
+// database from a snapshot instead of blocking the commits until they are done
+func IsSnapshotIsolationEnabled() bool {
+	return viper.GetBool(confSnapshotIsolation)
+}
+
 //IsHistoryDBEnabled exposes the historyDatabase variable
 func IsHistoryDBEnabled() bool {
 	return viper.GetBool(confEnableHistoryDatabase)
 }
 
+// IsAsyncHistoryCommitEnabled returns whether the blocks are committed to the
+// history database in the background, rather than as part of their commit
+func IsAsyncHistoryCommitEnabled() bool {
+	return viper.GetBool(confAsyncHistoryCommit)
+}
+
+// IsAsyncBlockIndexingEnabled returns whether the blocks are indexed in the
+// background, rather than as part of their addition to the block store
+func IsAsyncBlockIndexingEnabled() bool {
+	return viper.GetBool(confAsyncBlockIndexing)
+}
+
+// BlockfileSyncPolicy tells how the blocks appended to the block files are
+// flushed to the disk
+type BlockfileSyncPolicy struct {
+	// Grouped is true when the blocks appended concurrently by the channels
+	// are flushed together, rather than each block on its own
+	Grouped bool
+	// MaxDelay is the longest time the flush of a group waits for the blocks
+	// of other channels to join it
+	MaxDelay time.Duration
+}
+
+// CheckBlockfileSyncConfig returns an error if the sync policy of
+// ledger.blockchain.sync is unknown
+func CheckBlockfileSyncConfig() error {
+	switch policy := viper.GetString(confBlockfileSyncPolicy); policy {
+	case "", "block", "group":
+		return nil
+	default:
+		return errors.Errorf("unknown block file sync policy [%s], must be block or group", policy)
+	}
+}
+
+// GetBlockfileSyncPolicy returns the policy of ledger.blockchain.sync, which
+// CheckBlockfileSyncConfig validates, the policies other than "group"
+// flushing each block on its own
+func GetBlockfileSyncPolicy() BlockfileSyncPolicy {
+	policy := BlockfileSyncPolicy{
+		Grouped:  viper.GetString(confBlockfileSyncPolicy) == "group",
+		MaxDelay: 2 * time.Millisecond,
+	}
+	if viper.IsSet(confBlockfileSyncMaxDelay) {
+		if maxDelay := viper.GetDuration(confBlockfileSyncMaxDelay); maxDelay >= 0 {
+			policy.MaxDelay = maxDelay
+		}
+	}
+	return policy
+}
+
+// BlockPruningPolicy tells which blocks are pruned from the block files
+type BlockPruningPolicy struct {
+	// RetainBlocks is the number of blocks below the height of a ledger kept
+	// in its block files, 0 keeping all the blocks
+	RetainBlocks uint64
+	// ArchiveDir is the directory the pruned block files are moved to, they
+	// are removed when it is empty
+	ArchiveDir string
+}
+
+// GetBlockPruningPolicy returns the policy of ledger.blockchain.pruning. The
+// archive peers never prune their blocks.
+func GetBlockPruningPolicy() BlockPruningPolicy {
+	if IsArchiveEnabled() {
+		return BlockPruningPolicy{}
+	}
+	return BlockPruningPolicy{
+		RetainBlocks: uint64(viper.GetInt(confBlockPruningRetainBlocks)),
+		ArchiveDir:   config.GetPath(confBlockPruningArchiveDir),
+	}
+}
+
 // IsQueryReadsHashingEnabled enables or disables computing of hash
 // of range query results for phantom item validation
 func IsQueryReadsHashingEnabled() bool {
@@ -143,11 +409,21 @@ func GetMaxDegreeQueryReadsHashing() uint32 {
 	return 50
 }
 
+// GetStateCacheSize returns the size in megabytes of the cache of the values
+// and revisions read from CouchDB, shared by the channels of the peer
+func GetStateCacheSize() int {
+	// if cacheSize was unset, default to 64
+	if !viper.IsSet(confStateCacheSize) {
+		return 64
+	}
+	return viper.GetInt(confStateCacheSize)
+}
+
 //IsAutoWarmIndexesEnabled exposes the autoWarmIndexes variable
 func IsAutoWarmIndexesEnabled() bool {
 	//Return the value set in core.yaml, if not set, the return true
-	if viper.IsSet(confAutoWarmIndexes) {
-		return viper.GetBool(confAutoWarmIndexes)
+	if reload.IsSet(confAutoWarmIndexes) {
+		return reload.GetBool(confAutoWarmIndexes)
 	}
 	return true
 
@@ -155,9 +431,9 @@ func IsAutoWarmIndexesEnabled() bool {
 
 //GetWarmIndexesAfterNBlocks exposes the warmIndexesAfterNBlocks variable
 func GetWarmIndexesAfterNBlocks() int {
-	warmAfterNBlocks := viper.GetInt(confWarmIndexesAfterNBlocks)
+	warmAfterNBlocks := reload.GetInt(confWarmIndexesAfterNBlocks)
 	// if warmIndexesAfterNBlocks was unset, default to 1
-	if !viper.IsSet(confWarmIndexesAfterNBlocks) {
+	if !reload.IsSet(confWarmIndexesAfterNBlocks) {
 		warmAfterNBlocks = 1
 	}
 	return warmAfterNBlocks
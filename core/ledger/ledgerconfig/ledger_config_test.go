@@ -17,7 +17,9 @@ limitations under the License.
 package ledgerconfig
 
 import (
+	"path/filepath"
 	"testing"
+	"time"
 
 	ledgertestutil "github.com/hyperledger/fabric/core/ledger/testutil"
 	"github.com/spf13/viper"
@@ -180,6 +182,123 @@ func TestIsHistoryDBEnabledFalse(t *testing.T) {
 	assert.False(t, updatedValue) //test config returns false
 }
 
+func TestIsSnapshotIsolationEnabled(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	assert.False(t, IsSnapshotIsolationEnabled()) //test default config is false
+	viper.Set("ledger.state.snapshotIsolation", true)
+	assert.True(t, IsSnapshotIsolationEnabled())
+}
+
+func TestIsMixedStateDatabasesEnabled(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	assert.False(t, IsMixedStateDatabasesEnabled()) //test default config is false
+	viper.Set("ledger.state.mixedStateDatabases", true)
+	assert.True(t, IsMixedStateDatabasesEnabled())
+}
+
+func TestGetSnapshotPolicy(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	assert.Equal(t, SnapshotPolicy{Retain: 2}, GetSnapshotPolicy("mychannel")) //test default config is no snapshot
+	assert.Equal(t, filepath.Join(viper.GetString("peer.fileSystemPath"), "snapshots"), GetSnapshotsRootDir())
+	viper.Set("ledger.snapshots.rootDir", "/tmp/snapshots")
+	assert.Equal(t, "/tmp/snapshots", GetSnapshotsRootDir())
+
+	viper.Set("ledger.snapshots.interval", 100)
+	viper.Set("ledger.snapshots.retain", 0)
+	viper.Set("ledger.snapshots.channels", []interface{}{
+		map[interface{}]interface{}{"channelID": "hotchannel", "interval": 10, "retain": 5},
+		map[interface{}]interface{}{"channelID": "coldchannel", "interval": 0},
+	})
+	assert.Equal(t, SnapshotPolicy{Interval: 100, Retain: 1}, GetSnapshotPolicy("mychannel"))
+	assert.Equal(t, SnapshotPolicy{Interval: 10, Retain: 5}, GetSnapshotPolicy("hotchannel"))
+	assert.Equal(t, SnapshotPolicy{Interval: 0, Retain: 1}, GetSnapshotPolicy("coldchannel"))
+}
+
+func TestGetStateCacheSize(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	assert.Equal(t, 64, GetStateCacheSize()) //test default config is 64
+	viper.Set("ledger.state.couchDBConfig.cacheSize", 0)
+	assert.Equal(t, 0, GetStateCacheSize())
+}
+
+func TestAsyncIndexesConfig(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	assert.False(t, IsAsyncHistoryCommitEnabled()) //test default config is false
+	assert.False(t, IsAsyncBlockIndexingEnabled())
+	viper.Set("ledger.history.asyncCommit", true)
+	viper.Set("ledger.blockchain.asyncIndexing", true)
+	assert.True(t, IsAsyncHistoryCommitEnabled())
+	assert.True(t, IsAsyncBlockIndexingEnabled())
+}
+
+func TestBlockfileSyncPolicy(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	assert.NoError(t, CheckBlockfileSyncConfig())
+	assert.Equal(t, BlockfileSyncPolicy{MaxDelay: 2 * time.Millisecond}, GetBlockfileSyncPolicy()) //test default config is a flush per block
+	viper.Set("ledger.blockchain.sync.policy", "group")
+	viper.Set("ledger.blockchain.sync.maxDelay", "5ms")
+	assert.NoError(t, CheckBlockfileSyncConfig())
+	assert.Equal(t, BlockfileSyncPolicy{Grouped: true, MaxDelay: 5 * time.Millisecond}, GetBlockfileSyncPolicy())
+	viper.Set("ledger.blockchain.sync.policy", "unknown")
+	viper.Set("ledger.blockchain.sync.maxDelay", "-1ms")
+	assert.EqualError(t, CheckBlockfileSyncConfig(), "unknown block file sync policy [unknown], must be block or group")
+	assert.Equal(t, BlockfileSyncPolicy{MaxDelay: 2 * time.Millisecond}, GetBlockfileSyncPolicy())
+}
+
+func TestBlockPruningPolicy(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	assert.Equal(t, BlockPruningPolicy{}, GetBlockPruningPolicy()) //test default config keeps all the blocks
+	viper.Set("ledger.blockchain.pruning.retainBlocks", 1000)
+	viper.Set("ledger.blockchain.pruning.archiveDir", "/archive")
+	assert.Equal(t, BlockPruningPolicy{RetainBlocks: 1000, ArchiveDir: "/archive"}, GetBlockPruningPolicy())
+	viper.Set("ledger.archive.enabled", true)
+	defer viper.Set("ledger.archive.enabled", false)
+	assert.Equal(t, BlockPruningPolicy{}, GetBlockPruningPolicy())
+}
+
+func TestReplicationConfig(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	assert.False(t, IsReplicationCommitter()) //test default config is no replication
+	assert.False(t, IsReadReplica())
+	assert.NoError(t, CheckReplicationConfig())
+	assert.Equal(t, uint64(1), GetReplicationMaxLag())
+	assert.Equal(t, 3*time.Second, GetReplicationConsistencyTimeout())
+
+	viper.Set("ledger.replication.role", "replica")
+	assert.True(t, IsReadReplica())
+	assert.EqualError(t, CheckReplicationConfig(), "the replication role [replica] requires CouchDB as state database")
+	viper.Set("ledger.state.stateDatabase", "CouchDB")
+	assert.EqualError(t, CheckReplicationConfig(), "a read replica requires the address of its committer in ledger.replication.committerAddress")
+	viper.Set("ledger.replication.committerAddress", "peer0:7051")
+	assert.NoError(t, CheckReplicationConfig())
+
+	viper.Set("ledger.replication.role", "committer")
+	assert.True(t, IsReplicationCommitter())
+	assert.False(t, IsReadReplica())
+	assert.NoError(t, CheckReplicationConfig())
+	viper.Set("ledger.state.mixedStateDatabases", true)
+	assert.EqualError(t, CheckReplicationConfig(), "the replication role [committer] does not support mixed state databases")
+	viper.Set("ledger.state.mixedStateDatabases", false)
+
+	viper.Set("ledger.replication.role", "follower")
+	assert.EqualError(t, CheckReplicationConfig(), "unknown replication role [follower], must be committer or replica")
+
+	viper.Set("ledger.replication.maxLag", -1)
+	assert.Equal(t, uint64(0), GetReplicationMaxLag())
+	viper.Set("ledger.replication.maxLag", 5)
+	assert.Equal(t, uint64(5), GetReplicationMaxLag())
+	viper.Set("ledger.replication.consistencyTimeout", "10s")
+	assert.Equal(t, 10*time.Second, GetReplicationConsistencyTimeout())
+}
+
 func TestIsAutoWarmIndexesEnabledDefault(t *testing.T) {
 	setUpCoreYAMLConfig()
 	defaultValue := IsAutoWarmIndexesEnabled()
@@ -7,9 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package ledgermgmt
 
 import (
+	"io"
 	"sync"
 
+	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/core/chaincode/platforms"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
@@ -29,6 +32,10 @@ var ErrLedgerAlreadyOpened = errors.New("ledger already opened")
 var ErrLedgerMgmtNotInitialized = errors.New("ledger mgmt should be initialized before using")
 
 var openedLedgers map[string]ledger.PeerLedger
+
+// ids of the ledgers being opened, without holding the lock so that ledgers
+// are opened and recovered concurrently
+var openingLedgers map[string]struct{}
 var ledgerProvider ledger.PeerLedgerProvider
 var lock sync.Mutex
 var initialized bool
@@ -40,6 +47,9 @@ type Initializer struct {
 	PlatformRegistry              *platforms.Registry
 	DeployedChaincodeInfoProvider ledger.DeployedChaincodeInfoProvider
 	MembershipInfoProvider        ledger.MembershipInfoProvider
+	Metrics                       metrics.Scope
+	StateListeners                []ledger.StateListener
+	SnapshotSigner                crypto.LocalSigner
 }
 
 // Initialize initializes ledgermgmt
@@ -55,9 +65,10 @@ func initialize(initializer *Initializer) {
 	defer lock.Unlock()
 	initialized = true
 	openedLedgers = make(map[string]ledger.PeerLedger)
+	openingLedgers = make(map[string]struct{})
 	customtx.Initialize(initializer.CustomTxProcessors)
 	cceventmgmt.Initialize(initializer.PlatformRegistry)
-	finalStateListeners := addListenerForCCEventsHandler([]ledger.StateListener{})
+	finalStateListeners := addListenerForCCEventsHandler(append([]ledger.StateListener{}, initializer.StateListeners...))
 	provider, err := kvledger.NewProvider()
 	if err != nil {
 		panic(errors.WithMessage(err, "Error in instantiating ledger provider"))
@@ -66,6 +77,8 @@ func initialize(initializer *Initializer) {
 		StateListeners:                finalStateListeners,
 		DeployedChaincodeInfoProvider: initializer.DeployedChaincodeInfoProvider,
 		MembershipInfoProvider:        initializer.MembershipInfoProvider,
+		Metrics:                       initializer.Metrics,
+		SnapshotSigner:                initializer.SnapshotSigner,
 	})
 
 	ledgerProvider = provider
@@ -97,22 +110,80 @@ func CreateLedger(genesisBlock *common.Block) (ledger.PeerLedger, error) {
 	return l, nil
 }
 
-// OpenLedger returns a ledger for the given id
+// CreateLedgerFromSnapshot creates a new ledger from the snapshot of the state
+// of a channel read from the archive, at the height of the snapshot, once the
+// verifier accepts the last block of the snapshot
+func CreateLedgerFromSnapshot(snapshotArchive io.Reader, verifier ledger.SnapshotVerifier) (ledger.PeerLedger, error) {
+	lock.Lock()
+	defer lock.Unlock()
+	if !initialized {
+		return nil, ErrLedgerMgmtNotInitialized
+	}
+	importer, ok := ledgerProvider.(ledger.SnapshotImporter)
+	if !ok {
+		return nil, errors.New("the ledger provider does not support the creation of ledgers from snapshots")
+	}
+
+	logger.Info("Creating ledger from snapshot")
+	l, err := importer.CreateFromSnapshot(snapshotArchive, verifier)
+	if err != nil {
+		return nil, err
+	}
+	id, err := getLedgerID(l)
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	l = wrapLedger(id, l)
+	openedLedgers[id] = l
+	logger.Infof("Created ledger [%s] from snapshot", id)
+	return l, nil
+}
+
+// getLedgerID returns the id of the ledger, read from its last block
+func getLedgerID(l ledger.PeerLedger) (string, error) {
+	bcInfo, err := l.GetBlockchainInfo()
+	if err != nil {
+		return "", err
+	}
+	lastBlock, err := l.GetBlockByNumber(bcInfo.Height - 1)
+	if err != nil {
+		return "", err
+	}
+	return utils.GetChainIDFromBlock(lastBlock)
+}
+
+// OpenLedger returns a ledger for the given id. Distinct ledgers may be opened
+// concurrently
 func OpenLedger(id string) (ledger.PeerLedger, error) {
 	logger.Infof("Opening ledger with id = %s", id)
 	lock.Lock()
-	defer lock.Unlock()
 	if !initialized {
+		lock.Unlock()
 		return nil, ErrLedgerMgmtNotInitialized
 	}
-	l, ok := openedLedgers[id]
-	if ok {
+	_, opened := openedLedgers[id]
+	_, opening := openingLedgers[id]
+	if opened || opening {
+		lock.Unlock()
 		return nil, ErrLedgerAlreadyOpened
 	}
+	openingLedgers[id] = struct{}{}
+	lock.Unlock()
+
 	l, err := ledgerProvider.Open(id)
+
+	lock.Lock()
+	defer lock.Unlock()
+	delete(openingLedgers, id)
 	if err != nil {
 		return nil, err
 	}
+	if openedLedgers == nil {
+		// ledger mgmt was closed while the ledger was being opened
+		l.Close()
+		return nil, ErrLedgerMgmtNotInitialized
+	}
 	l = wrapLedger(id, l)
 	openedLedgers[id] = l
 	logger.Infof("Opened ledger with id = %s", id)
@@ -149,6 +220,16 @@ func wrapLedger(id string, l ledger.PeerLedger) ledger.PeerLedger {
 	return &closableLedger{id, l}
 }
 
+// Unwrap returns the ledger wrapped by the ones returned by CreateLedger and
+// OpenLedger, so that the optional interfaces it implements, such as
+// ledger.StateIndexManager, are found by type assertions
+func Unwrap(l ledger.PeerLedger) ledger.PeerLedger {
+	if cl, ok := l.(*closableLedger); ok {
+		return cl.PeerLedger
+	}
+	return l
+}
+
 // closableLedger extends from actual validated ledger and overwrites the Close method
 type closableLedger struct {
 	id string
@@ -17,14 +17,23 @@ limitations under the License.
 package ledgermgmt
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/fabric/common/configtx/test"
 	"github.com/hyperledger/fabric/core/chaincode/platforms"
 	"github.com/hyperledger/fabric/core/chaincode/platforms/golang"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/common"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
@@ -97,3 +106,106 @@ func TestLedgerMgmt(t *testing.T) {
 func constructTestLedgerID(i int) string {
 	return fmt.Sprintf("ledger_%06d", i)
 }
+
+func TestUnwrap(t *testing.T) {
+	InitializeTestEnv()
+	defer CleanupTestEnv()
+	gb, _ := test.MakeGenesisBlock(constructTestLedgerID(0))
+	l, err := CreateLedger(gb)
+	assert.NoError(t, err)
+	defer l.Close()
+
+	// the optional interfaces of the ledger are hidden by the wrapper
+	_, ok := l.(ledger.StateIndexManager)
+	assert.False(t, ok)
+	_, ok = Unwrap(l).(ledger.StateIndexManager)
+	assert.True(t, ok)
+	assert.Equal(t, Unwrap(l), Unwrap(Unwrap(l)))
+}
+
+func TestCreateLedgerFromSnapshot(t *testing.T) {
+	defer viper.Set("ledger.snapshots.interval", 0)
+	viper.Set("ledger.snapshots.interval", 1)
+	InitializeTestEnv()
+	gb, _ := test.MakeGenesisBlock(constructTestLedgerID(0))
+	l, err := CreateLedger(gb)
+	assert.NoError(t, err)
+	var snapshots []*ledger.SnapshotInfo
+	for i := 0; len(snapshots) == 0 && i < 100; i++ {
+		time.Sleep(10 * time.Millisecond)
+		snapshots, err = Unwrap(l).(ledger.SnapshotLister).ListSnapshots()
+		assert.NoError(t, err)
+	}
+	if !assert.Len(t, snapshots, 1) {
+		CleanupTestEnv()
+		return
+	}
+	// the snapshot is archived for another peer
+	archive := &bytes.Buffer{}
+	gz := gzip.NewWriter(archive)
+	tw := tar.NewWriter(gz)
+	files, err := ioutil.ReadDir(snapshots[0].Path)
+	assert.NoError(t, err)
+	for _, file := range files {
+		content, err := ioutil.ReadFile(filepath.Join(snapshots[0].Path, file.Name()))
+		assert.NoError(t, err)
+		assert.NoError(t, tw.WriteHeader(&tar.Header{Name: file.Name(), Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err = tw.Write(content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gz.Close())
+	CleanupTestEnv()
+
+	InitializeTestEnv()
+	defer CleanupTestEnv()
+	var lastBlock *common.Block
+	l, err = CreateLedgerFromSnapshot(bytes.NewReader(archive.Bytes()), func(block, configBlock *common.Block, stateCheckpoint *common.Metadata, stateHash []byte) error {
+		lastBlock = block
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, gb.Header.Hash(), lastBlock.Header.Hash())
+	ids, err := GetLedgerIDs()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{constructTestLedgerID(0)}, ids)
+	_, err = OpenLedger(constructTestLedgerID(0))
+	assert.Equal(t, ErrLedgerAlreadyOpened, err)
+	bcInfo, err := l.GetBlockchainInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), bcInfo.Height)
+	l.Close()
+}
+
+func TestOpenLedgersConcurrently(t *testing.T) {
+	InitializeTestEnv()
+	defer CleanupTestEnv()
+	numLedgers := 5
+	for i := 0; i < numLedgers; i++ {
+		gb, _ := test.MakeGenesisBlock(constructTestLedgerID(i))
+		l, err := CreateLedger(gb)
+		assert.NoError(t, err)
+		l.Close()
+	}
+
+	// each ledger is opened twice at the same time, only once successfully
+	var wg sync.WaitGroup
+	opened := make([]int32, numLedgers)
+	for i := 0; i < 2*numLedgers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			l, err := OpenLedger(constructTestLedgerID(i % numLedgers))
+			if err == ErrLedgerAlreadyOpened {
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, l)
+			atomic.AddInt32(&opened[i%numLedgers], 1)
+		}(i)
+	}
+	wg.Wait()
+	for i := 0; i < numLedgers; i++ {
+		assert.Equal(t, int32(1), opened[i])
+	}
+}
@@ -8,10 +8,12 @@ package ledgerstorage
 
 import (
 	"sync"
+	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/pvtdatapolicy"
@@ -34,6 +36,8 @@ type Store struct {
 	blkstorage.BlockStore
 	pvtdataStore pvtdatastorage.Store
 	rwlock       *sync.RWMutex
+
+	pvtdataCommitDuration metrics.Histogram
 }
 
 // NewProvider returns the handle to the provider
@@ -46,11 +50,22 @@ func NewProvider() *Provider {
 		blkstorage.IndexableAttrBlockNumTranNum,
 		blkstorage.IndexableAttrBlockTxID,
 		blkstorage.IndexableAttrTxValidationCode,
+		blkstorage.IndexableAttrTxCreator,
+		blkstorage.IndexableAttrBlockTime,
+		blkstorage.IndexableAttrTxDedupKey,
+	}
+	indexConfig := &blkstorage.IndexConfig{
+		AttrsToIndex:  attrsToIndex,
+		AsyncIndexing: ledgerconfig.IsAsyncBlockIndexingEnabled(),
+	}
+	blockStoreConf := fsblkstorage.NewConf(ledgerconfig.GetBlockStorePath(), ledgerconfig.GetMaxBlockfileSize())
+	if syncPolicy := ledgerconfig.GetBlockfileSyncPolicy(); syncPolicy.Grouped {
+		blockStoreConf = fsblkstorage.NewConfWithGroupSync(ledgerconfig.GetBlockStorePath(), ledgerconfig.GetMaxBlockfileSize(), syncPolicy.MaxDelay)
 	}
-	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: attrsToIndex}
-	blockStoreProvider := fsblkstorage.NewProvider(
-		fsblkstorage.NewConf(ledgerconfig.GetBlockStorePath(), ledgerconfig.GetMaxBlockfileSize()),
-		indexConfig)
+	if pruningPolicy := ledgerconfig.GetBlockPruningPolicy(); pruningPolicy.RetainBlocks > 0 {
+		blockStoreConf = blockStoreConf.WithPruning(pruningPolicy.RetainBlocks, pruningPolicy.ArchiveDir)
+	}
+	blockStoreProvider := fsblkstorage.NewProvider(blockStoreConf, indexConfig)
 
 	pvtStoreProvider := pvtdatastorage.NewProvider()
 	return &Provider{blockStoreProvider, pvtStoreProvider}
@@ -68,7 +83,7 @@ func (p *Provider) Open(ledgerid string) (*Store, error) {
 	if pvtdataStore, err = p.pvtdataStoreProvider.OpenStore(ledgerid); err != nil {
 		return nil, err
 	}
-	store := &Store{blockStore, pvtdataStore, &sync.RWMutex{}}
+	store := &Store{blockStore, pvtdataStore, &sync.RWMutex{}, metrics.NewNoOpScope().Histogram("")}
 	if err := store.init(); err != nil {
 		return nil, err
 	}
@@ -86,6 +101,12 @@ func (s *Store) Init(btlPolicy pvtdatapolicy.BTLPolicy) {
 	s.pvtdataStore.Init(btlPolicy)
 }
 
+// InitMetrics sets the scope of the durations of the commits of the private
+// data of the blocks
+func (s *Store) InitMetrics(scope metrics.Scope) {
+	s.pvtdataCommitDuration = scope.Histogram("pvtdata_commit_duration")
+}
+
 // CommitWithPvtData commits the block and the corresponding pvt data in an atomic operation
 func (s *Store) CommitWithPvtData(blockAndPvtdata *ledger.BlockAndPvtData) error {
 	blockNum := blockAndPvtdata.Block.Header.Number
@@ -104,6 +125,7 @@ func (s *Store) CommitWithPvtData(blockAndPvtdata *ledger.BlockAndPvtData) error
 		return err
 	}
 
+	var pvtdataCommitDuration time.Duration
 	writtenToPvtStore := false
 	if pvtBlkStoreHt < blockNum+1 { // The pvt data store sanity check does not allow rewriting the pvt data.
 		// when re-processing blocks (rejoin the channel or re-fetching last few block),
@@ -113,9 +135,11 @@ func (s *Store) CommitWithPvtData(blockAndPvtdata *ledger.BlockAndPvtData) error
 		for _, v := range blockAndPvtdata.BlockPvtData {
 			pvtdata = append(pvtdata, v)
 		}
+		startPrepare := time.Now()
 		if err := s.pvtdataStore.Prepare(blockAndPvtdata.Block.Header.Number, pvtdata, missingDataList); err != nil {
 			return err
 		}
+		pvtdataCommitDuration = time.Since(startPrepare)
 		writtenToPvtStore = true
 	} else {
 		logger.Debugf("Skipping writing block [%d] to pvt block store as the store height is [%d]", blockNum, pvtBlkStoreHt)
@@ -127,11 +151,40 @@ func (s *Store) CommitWithPvtData(blockAndPvtdata *ledger.BlockAndPvtData) error
 	}
 
 	if writtenToPvtStore {
-		return s.pvtdataStore.Commit()
+		startCommit := time.Now()
+		err := s.pvtdataStore.Commit()
+		s.pvtdataCommitDuration.RecordDuration(pvtdataCommitDuration + time.Since(startCommit))
+		return err
 	}
 	return nil
 }
 
+// Bootstrap starts the empty blockchain at the last block of a snapshot of the
+// state, see `blkstorage.BootstrapCapable`, and the pvt data store after the
+// same block
+func (s *Store) Bootstrap(lastBlock, configBlock *common.Block) error {
+	bootstrapCapable, ok := s.BlockStore.(blkstorage.BootstrapCapable)
+	if !ok {
+		return errors.New("the block store cannot be bootstrapped from a snapshot")
+	}
+	s.rwlock.Lock()
+	defer s.rwlock.Unlock()
+	if err := bootstrapCapable.Bootstrap(lastBlock, configBlock); err != nil {
+		return err
+	}
+	return s.pvtdataStore.InitLastCommittedBlock(lastBlock.Header.Number)
+}
+
+// GetBootstrapConfigBlock returns the config block kept by Bootstrap, nil if the
+// blockchain starts at the genesis block
+func (s *Store) GetBootstrapConfigBlock() (*common.Block, error) {
+	bootstrapCapable, ok := s.BlockStore.(blkstorage.BootstrapCapable)
+	if !ok {
+		return nil, nil
+	}
+	return bootstrapCapable.GetBootstrapConfigBlock()
+}
+
 // GetPvtDataAndBlockByNum returns the block and the corresponding pvt data.
 // The pvt data is filtered by the list of 'collections' supplied
 func (s *Store) GetPvtDataAndBlockByNum(blockNum uint64, filter ledger.PvtNsCollFilter) (*ledger.BlockAndPvtData, error) {
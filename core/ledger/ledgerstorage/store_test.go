@@ -9,12 +9,14 @@ package ledgerstorage
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/pvtdatapolicy"
@@ -32,6 +34,43 @@ func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
 
+type countingScope struct {
+	metrics.Scope
+	histogram *countingHistogram
+}
+
+func (s *countingScope) Histogram(name string) metrics.Histogram {
+	return s.histogram
+}
+
+type countingHistogram struct {
+	count int
+}
+
+func (h *countingHistogram) RecordDuration(time.Duration) {
+	h.count++
+}
+
+func TestStoreMetrics(t *testing.T) {
+	testEnv := newTestEnv(t)
+	defer testEnv.cleanup()
+	provider := NewProvider()
+	defer provider.Close()
+	store, err := provider.Open("testLedger")
+	assert.NoError(t, err)
+	store.Init(btlPolicyForSampleData())
+	defer store.Shutdown()
+	scope := &countingScope{Scope: metrics.NewNoOpScope(), histogram: &countingHistogram{}}
+	store.InitMetrics(scope)
+
+	sampleData := sampleDataWithPvtdataForSelectiveTx(t)
+	for _, sampleDatum := range sampleData {
+		assert.NoError(t, store.CommitWithPvtData(sampleDatum))
+	}
+	// the commit of every block writes to the pvt data store
+	assert.Equal(t, len(sampleData), scope.histogram.count)
+}
+
 func TestStore(t *testing.T) {
 	testEnv := newTestEnv(t)
 	defer testEnv.cleanup()
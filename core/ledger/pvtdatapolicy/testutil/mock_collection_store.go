@@ -58,3 +58,7 @@ type mockResponse struct {
 func (m *mockResponse) BlockToLive() uint64 {
 	return m.btl
 }
+
+func (m *mockResponse) Ephemeral() bool {
+	return false
+}
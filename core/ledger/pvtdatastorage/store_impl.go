@@ -30,6 +30,8 @@ type store struct {
 	db        *leveldbhelper.DBHandle
 	ledgerid  string
 	btlPolicy pvtdatapolicy.BTLPolicy
+	// archive tells whether the expired data is retained
+	archive bool
 
 	isEmpty            bool
 	lastCommittedBlock uint64
@@ -88,7 +90,7 @@ func NewProvider() Provider {
 // OpenStore returns a handle to a store
 func (p *provider) OpenStore(ledgerid string) (Store, error) {
 	dbHandle := p.dbProvider.GetDBHandle(ledgerid)
-	s := &store{db: dbHandle, ledgerid: ledgerid}
+	s := &store{db: dbHandle, ledgerid: ledgerid, archive: ledgerconfig.IsArchiveEnabled()}
 	if err := s.initState(); err != nil {
 		return nil, err
 	}
@@ -235,7 +237,7 @@ func (s *store) GetPvtDataByBlockNum(blockNum uint64, filter ledger.PvtNsCollFil
 		}
 		dataValueBytes := itr.Value()
 		dataKey := decodeDatakey(dataKeyBytes)
-		expired, err := isExpired(dataKey.nsCollBlk, s.btlPolicy, s.lastCommittedBlock)
+		expired, err := s.isExpired(dataKey.nsCollBlk)
 		if err != nil {
 			return nil, err
 		}
@@ -352,7 +354,19 @@ func (s *store) GetMissingPvtDataInfoForMostRecentBlocks(maxBlock int) (ledger.M
 	return missingPvtDataInfo, nil
 }
 
+// isExpired tells whether the data of the supplied collection and block
+// has expired and should not be retrieved anymore
+func (s *store) isExpired(key nsCollBlk) (bool, error) {
+	if s.archive {
+		return false, nil
+	}
+	return isExpired(key, s.btlPolicy, s.lastCommittedBlock)
+}
+
 func (s *store) performPurgeIfScheduled(latestCommittedBlk uint64) {
+	if s.archive {
+		return
+	}
 	if latestCommittedBlk%ledgerconfig.GetPvtdataStorePurgeInterval() != 0 {
 		return
 	}
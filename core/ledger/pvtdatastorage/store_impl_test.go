@@ -403,6 +403,43 @@ func TestStorePurge(t *testing.T) {
 	assert.True(testDataKeyExists(t, s, &dataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-2", blkNum: 1}, txNum: 2}))
 }
 
+func TestArchiveStoreRetainsExpiredData(t *testing.T) {
+	ledgerid := "TestArchiveStoreRetainsExpiredData"
+	viper.Set("ledger.pvtdataStore.purgeInterval", 2)
+	viper.Set("ledger.archive.enabled", true)
+	defer viper.Set("ledger.archive.enabled", false)
+	cs := btltestutil.NewMockCollectionStore()
+	cs.SetBTL("ns-1", "coll-1", 1)
+	cs.SetBTL("ns-1", "coll-2", 0)
+	btlPolicy := pvtdatapolicy.ConstructBTLPolicy(cs)
+
+	env := NewTestStoreEnv(t, ledgerid, btlPolicy)
+	defer env.Cleanup()
+	assert := assert.New(t)
+	s := env.TestStore
+
+	assert.NoError(s.Prepare(0, nil, nil))
+	assert.NoError(s.Commit())
+
+	testDataForBlk1 := []*ledger.TxPvtData{
+		produceSamplePvtdata(t, 2, []string{"ns-1:coll-1", "ns-1:coll-2"}),
+	}
+	assert.NoError(s.Prepare(1, testDataForBlk1, nil))
+	assert.NoError(s.Commit())
+
+	// the data of "ns-1:coll-1" expires when block 3 is committed, and
+	// would be purged when block 4 is committed by a regular peer
+	for blkNum := uint64(2); blkNum <= 6; blkNum++ {
+		assert.NoError(s.Prepare(blkNum, nil, nil))
+		assert.NoError(s.Commit())
+	}
+	retrievedData, err := s.GetPvtDataByBlockNum(1, nil)
+	assert.NoError(err)
+	assert.Len(retrievedData, 1)
+	assert.True(proto.Equal(testDataForBlk1[0].WriteSet, retrievedData[0].WriteSet))
+	assert.True(testDataKeyExists(t, s, &dataKey{nsCollBlk{"ns-1", "coll-1", 1}, 2}))
+}
+
 func TestStoreState(t *testing.T) {
 	cs := btltestutil.NewMockCollectionStore()
 	cs.SetBTL("ns-1", "coll-1", 0)
@@ -78,8 +78,17 @@ func ResetConfigToDefaultValues() {
 	viper.Set("ledger.state.couchDBConfig.internalQueryLimit", 1000)
 	viper.Set("ledger.state.stateDatabase", "goleveldb")
 	viper.Set("ledger.history.enableHistoryDatabase", false)
+	viper.Set("ledger.history.asyncCommit", false)
+	viper.Set("ledger.blockchain.asyncIndexing", false)
+	viper.Set("ledger.state.snapshotIsolation", false)
+	viper.Set("ledger.state.mixedStateDatabases", false)
+	viper.Set("ledger.snapshots.rootDir", "")
+	viper.Set("ledger.snapshots.interval", 0)
+	viper.Set("ledger.snapshots.retain", 2)
+	viper.Set("ledger.snapshots.channels", nil)
 	viper.Set("ledger.state.couchDBConfig.autoWarmIndexes", true)
 	viper.Set("ledger.state.couchDBConfig.warmIndexesAfterNBlocks", 1)
+	viper.Set("ledger.state.couchDBConfig.cacheSize", 64)
 	viper.Set("peer.fileSystemPath", "/var/hyperledger/production")
 }
 
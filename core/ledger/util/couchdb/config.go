@@ -17,8 +17,11 @@ limitations under the License.
 package couchdb
 
 import (
+	"regexp"
 	"time"
 
+	"github.com/hyperledger/fabric/common/secrets"
+	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 )
 
@@ -31,10 +34,23 @@ type CouchDBDef struct {
 	MaxRetriesOnStartup   int
 	RequestTimeout        time.Duration
 	CreateGlobalChangesDB bool
+	// ClusterAddresses are the addresses of the other nodes of a CouchDB
+	// cluster, the requests are balanced across URL and these nodes
+	ClusterAddresses []string
+	// Shards and Replicas are the q and n parameters of the created
+	// databases, zero means the defaults of the cluster
+	Shards   int
+	Replicas int
+	// DatabaseNamePrefix is prepended to the names of the state databases,
+	// so that several peers can share a cluster
+	DatabaseNamePrefix string
 }
 
-//GetCouchDBDefinition exposes the useCouchDB variable
-func GetCouchDBDefinition() *CouchDBDef {
+var databaseNamePrefixPattern = regexp.MustCompile(`^` + expectedDatabaseNamePattern + `$`)
+
+//GetCouchDBDefinition exposes the useCouchDB variable. The username and the
+//password can reference a file or an environment variable, see package secrets.
+func GetCouchDBDefinition() (*CouchDBDef, error) {
 
 	couchDBAddress := viper.GetString("ledger.state.couchDBConfig.couchDBAddress")
 	username := viper.GetString("ledger.state.couchDBConfig.username")
@@ -43,6 +59,36 @@ func GetCouchDBDefinition() *CouchDBDef {
 	maxRetriesOnStartup := viper.GetInt("ledger.state.couchDBConfig.maxRetriesOnStartup")
 	requestTimeout := viper.GetDuration("ledger.state.couchDBConfig.requestTimeout")
 	createGlobalChangesDB := viper.GetBool("ledger.state.couchDBConfig.createGlobalChangesDB")
+	clusterAddresses := viper.GetStringSlice("ledger.state.couchDBConfig.clusterAddresses")
+	shards := viper.GetInt("ledger.state.couchDBConfig.shards")
+	replicas := viper.GetInt("ledger.state.couchDBConfig.replicas")
+	databaseNamePrefix := viper.GetString("ledger.state.couchDBConfig.databaseNamePrefix")
+
+	if err := secrets.ResolveInPlace(&username); err != nil {
+		return nil, errors.WithMessage(err, "failed resolving ledger.state.couchDBConfig.username")
+	}
+	if err := secrets.ResolveInPlace(&password); err != nil {
+		return nil, errors.WithMessage(err, "failed resolving ledger.state.couchDBConfig.password")
+	}
+
+	if shards < 0 || replicas < 0 {
+		return nil, errors.Errorf("invalid CouchDB shards %d and replicas %d, they must be zero or greater", shards, replicas)
+	}
+	if databaseNamePrefix != "" && !databaseNamePrefixPattern.MatchString(databaseNamePrefix) {
+		return nil, errors.Errorf("invalid CouchDB database name prefix %s, it must match %s", databaseNamePrefix, expectedDatabaseNamePattern)
+	}
 
-	return &CouchDBDef{couchDBAddress, username, password, maxRetries, maxRetriesOnStartup, requestTimeout, createGlobalChangesDB}
+	return &CouchDBDef{
+		URL:                   couchDBAddress,
+		Username:              username,
+		Password:              password,
+		MaxRetries:            maxRetries,
+		MaxRetriesOnStartup:   maxRetriesOnStartup,
+		RequestTimeout:        requestTimeout,
+		CreateGlobalChangesDB: createGlobalChangesDB,
+		ClusterAddresses:      clusterAddresses,
+		Shards:                shards,
+		Replicas:              replicas,
+		DatabaseNamePrefix:    databaseNamePrefix,
+	}, nil
 }
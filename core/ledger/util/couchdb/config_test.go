@@ -7,6 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package couchdb
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -17,7 +20,8 @@ import (
 func TestGetCouchDBDefinition(t *testing.T) {
 	expectedAddress := viper.GetString("ledger.state.couchDBConfig.couchDBAddress")
 
-	couchDBDef := GetCouchDBDefinition()
+	couchDBDef, err := GetCouchDBDefinition()
+	assert.NoError(t, err)
 	assert.Equal(t, expectedAddress, couchDBDef.URL)
 	assert.Equal(t, "", couchDBDef.Username)
 	assert.Equal(t, "", couchDBDef.Password)
@@ -25,3 +29,55 @@ func TestGetCouchDBDefinition(t *testing.T) {
 	assert.Equal(t, 20, couchDBDef.MaxRetriesOnStartup)
 	assert.Equal(t, time.Second*35, couchDBDef.RequestTimeout)
 }
+
+func TestGetCouchDBDefinitionSecrets(t *testing.T) {
+	defer viper.Set("ledger.state.couchDBConfig.username", "")
+	defer viper.Set("ledger.state.couchDBConfig.password", "")
+
+	dir, err := ioutil.TempDir("", "couchdb")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	passwordFile := filepath.Join(dir, "password")
+	assert.NoError(t, ioutil.WriteFile(passwordFile, []byte("adminpw\n"), 0600))
+	os.Setenv("COUCHDB_TEST_USERNAME", "admin")
+	defer os.Unsetenv("COUCHDB_TEST_USERNAME")
+
+	viper.Set("ledger.state.couchDBConfig.username", "env://COUCHDB_TEST_USERNAME")
+	viper.Set("ledger.state.couchDBConfig.password", "file://"+passwordFile)
+	couchDBDef, err := GetCouchDBDefinition()
+	assert.NoError(t, err)
+	assert.Equal(t, "admin", couchDBDef.Username)
+	assert.Equal(t, "adminpw", couchDBDef.Password)
+
+	viper.Set("ledger.state.couchDBConfig.password", "env://COUCHDB_TEST_MISSING")
+	_, err = GetCouchDBDefinition()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed resolving ledger.state.couchDBConfig.password")
+}
+
+func TestGetCouchDBDefinitionCluster(t *testing.T) {
+	defer viper.Set("ledger.state.couchDBConfig.clusterAddresses", []string{})
+	defer viper.Set("ledger.state.couchDBConfig.shards", 0)
+	defer viper.Set("ledger.state.couchDBConfig.replicas", 0)
+	defer viper.Set("ledger.state.couchDBConfig.databaseNamePrefix", "")
+
+	viper.Set("ledger.state.couchDBConfig.clusterAddresses", []string{"couchdb1:5984", "couchdb2:5984"})
+	viper.Set("ledger.state.couchDBConfig.shards", 4)
+	viper.Set("ledger.state.couchDBConfig.replicas", 3)
+	viper.Set("ledger.state.couchDBConfig.databaseNamePrefix", "peer0_")
+	couchDBDef, err := GetCouchDBDefinition()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"couchdb1:5984", "couchdb2:5984"}, couchDBDef.ClusterAddresses)
+	assert.Equal(t, 4, couchDBDef.Shards)
+	assert.Equal(t, 3, couchDBDef.Replicas)
+	assert.Equal(t, "peer0_", couchDBDef.DatabaseNamePrefix)
+
+	viper.Set("ledger.state.couchDBConfig.databaseNamePrefix", "Peer0_")
+	_, err = GetCouchDBDefinition()
+	assert.EqualError(t, err, "invalid CouchDB database name prefix Peer0_, it must match "+expectedDatabaseNamePattern)
+
+	viper.Set("ledger.state.couchDBConfig.databaseNamePrefix", "")
+	viper.Set("ledger.state.couchDBConfig.replicas", -1)
+	_, err = GetCouchDBDefinition()
+	assert.EqualError(t, err, "invalid CouchDB shards 4 and replicas -1, they must be zero or greater")
+}
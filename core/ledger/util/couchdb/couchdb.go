@@ -23,6 +23,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 	"unicode/utf8"
 
@@ -126,12 +127,17 @@ type CouchConnectionDef struct {
 	MaxRetriesOnStartup   int
 	RequestTimeout        time.Duration
 	CreateGlobalChangesDB bool
+	ClusterURLs           []string // URLs of the other nodes of the cluster
+	Shards                int      // q parameter of the created databases
+	Replicas              int      // n parameter of the created databases
+	DatabaseNamePrefix    string
 }
 
 //CouchInstance represents a CouchDB instance
 type CouchInstance struct {
-	conf   CouchConnectionDef //connection configuration
-	client *http.Client       // a client to connect to this instance
+	conf     CouchConnectionDef //connection configuration
+	client   *http.Client       // a client to connect to this instance
+	nextNode uint32             // index of the cluster node receiving the next request
 }
 
 //CouchDatabase represents a database within a CouchDB instance
@@ -253,8 +259,15 @@ func CreateConnectionDefinition(couchDBAddress, username, password string, maxRe
 	logger.Debugf("Exiting CreateConnectionDefinition()")
 
 	//return an object containing the connection information
-	return &CouchConnectionDef{finalURL.String(), username, password, maxRetries,
-		maxRetriesOnStartup, requestTimeout, createGlobalChangesDB}, nil
+	return &CouchConnectionDef{
+		URL:                   finalURL.String(),
+		Username:              username,
+		Password:              password,
+		MaxRetries:            maxRetries,
+		MaxRetriesOnStartup:   maxRetriesOnStartup,
+		RequestTimeout:        requestTimeout,
+		CreateGlobalChangesDB: createGlobalChangesDB,
+	}, nil
 
 }
 
@@ -295,11 +308,24 @@ func (dbclient *CouchDatabase) CreateDatabaseIfNotExist() error {
 	}
 	connectURL = constructCouchDBUrl(connectURL, dbclient.DBName, "")
 
+	//set the sharding of the database within the cluster, if configured
+	createURL := connectURL.String()
+	queryParms := url.Values{}
+	if dbclient.CouchInstance.conf.Shards > 0 {
+		queryParms.Set("q", strconv.Itoa(dbclient.CouchInstance.conf.Shards))
+	}
+	if dbclient.CouchInstance.conf.Replicas > 0 {
+		queryParms.Set("n", strconv.Itoa(dbclient.CouchInstance.conf.Replicas))
+	}
+	if len(queryParms) > 0 {
+		createURL += "?" + queryParms.Encode()
+	}
+
 	//get the number of retries
 	maxRetries := dbclient.CouchInstance.conf.MaxRetries
 
 	//process the URL with a PUT, creates the database
-	resp, _, err := dbclient.CouchInstance.handleRequest(http.MethodPut, connectURL.String(), nil, "", "", maxRetries, true)
+	resp, _, err := dbclient.CouchInstance.handleRequest(http.MethodPut, createURL, nil, "", "", maxRetries, true)
 
 	if err != nil {
 
@@ -1028,15 +1054,22 @@ func (dbclient *CouchDatabase) DeleteDoc(id, rev string) error {
 
 //QueryDocuments method provides function for processing a query
 func (dbclient *CouchDatabase) QueryDocuments(query string) ([]*QueryResult, string, error) {
+	results, bookmark, _, err := dbclient.QueryDocumentsWithWarning(query)
+	return results, bookmark, err
+}
 
-	logger.Debugf("[%s] Entering QueryDocuments()  query=%s", dbclient.DBName, query)
+//QueryDocumentsWithWarning processes a query and also returns the warning of CouchDB
+//about its execution, such as the lack of an index matching the selector of the query
+func (dbclient *CouchDatabase) QueryDocumentsWithWarning(query string) ([]*QueryResult, string, string, error) {
+
+	logger.Debugf("[%s] Entering QueryDocumentsWithWarning()  query=%s", dbclient.DBName, query)
 
 	var results []*QueryResult
 
 	queryURL, err := url.Parse(dbclient.CouchInstance.conf.URL)
 	if err != nil {
 		logger.Errorf("URL parse error: %s", err)
-		return nil, "", errors.Wrapf(err, "error parsing CouchDB URL: %s", dbclient.CouchInstance.conf.URL)
+		return nil, "", "", errors.Wrapf(err, "error parsing CouchDB URL: %s", dbclient.CouchInstance.conf.URL)
 	}
 	queryURL = constructCouchDBUrl(queryURL, dbclient.DBName, "_find")
 
@@ -1045,7 +1078,7 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) ([]*QueryResult, str
 
 	resp, _, err := dbclient.CouchInstance.handleRequest(http.MethodPost, queryURL.String(), []byte(query), "", "", maxRetries, true)
 	if err != nil {
-		return nil, "", err
+		return nil, "", "", err
 	}
 	defer closeResponseBody(resp)
 
@@ -1060,14 +1093,14 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) ([]*QueryResult, str
 	//handle as JSON document
 	jsonResponseRaw, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, "", errors.Wrap(err, "error reading response body")
+		return nil, "", "", errors.Wrap(err, "error reading response body")
 	}
 
 	var jsonResponse = &QueryResponse{}
 
 	err2 := json.Unmarshal(jsonResponseRaw, &jsonResponse)
 	if err2 != nil {
-		return nil, "", errors.Wrap(err2, "error unmarshalling json data")
+		return nil, "", "", errors.Wrap(err2, "error unmarshalling json data")
 	}
 
 	if jsonResponse.Warning != "" {
@@ -1079,7 +1112,7 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) ([]*QueryResult, str
 		var docMetadata = &DocMetadata{}
 		err3 := json.Unmarshal(row, &docMetadata)
 		if err3 != nil {
-			return nil, "", errors.Wrap(err3, "error unmarshalling json data")
+			return nil, "", "", errors.Wrap(err3, "error unmarshalling json data")
 		}
 
 		if docMetadata.AttachmentsInfo != nil {
@@ -1088,7 +1121,7 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) ([]*QueryResult, str
 
 			couchDoc, _, err := dbclient.ReadDoc(docMetadata.ID)
 			if err != nil {
-				return nil, "", err
+				return nil, "", "", err
 			}
 			var addDocument = &QueryResult{ID: docMetadata.ID, Value: couchDoc.JSONValue, Attachments: couchDoc.Attachments}
 			results = append(results, addDocument)
@@ -1102,9 +1135,9 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) ([]*QueryResult, str
 		}
 	}
 
-	logger.Debugf("[%s] Exiting QueryDocuments()", dbclient.DBName)
+	logger.Debugf("[%s] Exiting QueryDocumentsWithWarning()", dbclient.DBName)
 
-	return results, jsonResponse.Bookmark, nil
+	return results, jsonResponse.Bookmark, jsonResponse.Warning, nil
 
 }
 
@@ -1672,8 +1705,9 @@ func (couchInstance *CouchInstance) handleRequest(method, connectURL string, dat
 
 		payloadData.ReadFrom(bytes.NewReader(data))
 
-		//Create request based on URL for couchdb operation
-		req, err := http.NewRequest(method, connectURL, payloadData)
+		//Create request based on URL for couchdb operation, each attempt is
+		//sent to the next node of the cluster
+		req, err := http.NewRequest(method, couchInstance.nodeRequestURL(connectURL), payloadData)
 		if err != nil {
 			return nil, nil, errors.Wrap(err, "error creating http request")
 		}
@@ -1831,6 +1865,26 @@ func (couchInstance *CouchInstance) handleRequest(method, connectURL string, dat
 	return resp, couchDBReturn, nil
 }
 
+// nodeRequestURL returns the supplied request URL addressed to the next node
+// of the cluster, in round robin order
+func (couchInstance *CouchInstance) nodeRequestURL(connectURL string) string {
+	clusterURLs := couchInstance.conf.ClusterURLs
+	if len(clusterURLs) == 0 || !strings.HasPrefix(connectURL, couchInstance.conf.URL) {
+		return connectURL
+	}
+	// the node 0 is URL, followed by the other nodes of the cluster
+	node := (atomic.AddUint32(&couchInstance.nextNode, 1) - 1) % uint32(len(clusterURLs)+1)
+	if node == 0 {
+		return connectURL
+	}
+	return clusterURLs[node-1] + strings.TrimPrefix(connectURL, couchInstance.conf.URL)
+}
+
+// DatabaseNamePrefix returns the prefix of the names of the state databases
+func (couchInstance *CouchInstance) DatabaseNamePrefix() string {
+	return couchInstance.conf.DatabaseNamePrefix
+}
+
 //invalidCouchDBResponse checks to make sure either a valid response or error is returned
 func invalidCouchDBReturn(resp *http.Response, errResp error) bool {
 	if resp == nil && errResp == nil {
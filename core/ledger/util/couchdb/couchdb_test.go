@@ -10,9 +10,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 	"unicode/utf8"
@@ -87,7 +89,11 @@ func testMain(m *testing.M) int {
 	viper.Set("logging.peer", "debug")
 
 	// Create CouchDB definition from config parameters
-	couchDBDef = GetCouchDBDefinition()
+	var err error
+	couchDBDef, err = GetCouchDBDefinition()
+	if err != nil {
+		panic(err)
+	}
 
 	//run the tests
 	return m.Run()
@@ -153,7 +159,7 @@ func TestBadCouchDBInstance(t *testing.T) {
 	client := &http.Client{}
 
 	//Create a bad couchdb instance
-	badCouchDBInstance := CouchInstance{badConnectDef, client}
+	badCouchDBInstance := CouchInstance{conf: badConnectDef, client: client}
 
 	//Create a bad CouchDatabase
 	badDB := CouchDatabase{&badCouchDBInstance, "baddb", 1}
@@ -228,6 +234,61 @@ func TestBadCouchDBInstance(t *testing.T) {
 
 }
 
+func TestCouchDBCluster(t *testing.T) {
+	var lock sync.Mutex
+	var createQuery url.Values
+	requests := map[string]int{}
+	newNode := func() *httptest.Server {
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lock.Lock()
+			requests[server.URL]++
+			lock.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case r.URL.Path == "/":
+				w.Write([]byte(`{"couchdb":"Welcome","version":"2.1.1"}`))
+			case r.Method == http.MethodGet:
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error":"not_found","reason":"Database does not exist."}`))
+			case r.Method == http.MethodPut:
+				lock.Lock()
+				createQuery = r.URL.Query()
+				lock.Unlock()
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"ok":true}`))
+			}
+		}))
+		return server
+	}
+	node1 := newNode()
+	defer node1.Close()
+	node2 := newNode()
+	defer node2.Close()
+
+	couchInstance, err := CreateCouchInstanceFromDefinition(&CouchDBDef{
+		URL:              strings.TrimPrefix(node1.URL, "http://"),
+		ClusterAddresses: []string{strings.TrimPrefix(node2.URL, "http://")},
+		RequestTimeout:   time.Second,
+		Shards:           8,
+		Replicas:         2,
+	})
+	assert.NoError(t, err)
+
+	_, err = CreateCouchDatabase(couchInstance, "testcouchdbcluster")
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{"q": {"8"}, "n": {"2"}}, createQuery)
+	// the requests are balanced across the nodes of the cluster
+	assert.NotZero(t, requests[node2.URL])
+	assert.InDelta(t, requests[node1.URL], requests[node2.URL], 1)
+
+	_, err = CreateCouchInstanceFromDefinition(&CouchDBDef{
+		URL:              strings.TrimPrefix(node1.URL, "http://"),
+		ClusterAddresses: []string{badParseConnectURL},
+	})
+	assert.Error(t, err)
+}
+
 func TestDBCreateSaveWithoutRevision(t *testing.T) {
 
 	database := "testdbcreatesavewithoutrevision"
@@ -42,6 +42,37 @@ func CreateCouchInstance(couchDBConnectURL, id, pw string, maxRetries,
 		return nil, err
 	}
 
+	return newCouchInstance(couchConf)
+}
+
+//CreateCouchInstanceFromDefinition creates a CouchDB instance balancing the
+//requests across the nodes of the cluster of the definition
+func CreateCouchInstanceFromDefinition(couchDBDef *CouchDBDef) (*CouchInstance, error) {
+
+	couchConf, err := CreateConnectionDefinition(couchDBDef.URL, couchDBDef.Username, couchDBDef.Password,
+		couchDBDef.MaxRetries, couchDBDef.MaxRetriesOnStartup, couchDBDef.RequestTimeout, couchDBDef.CreateGlobalChangesDB)
+	if err != nil {
+		logger.Errorf("Error calling CouchDB CreateConnectionDefinition(): %s", err)
+		return nil, err
+	}
+	for _, address := range couchDBDef.ClusterAddresses {
+		nodeConf, err := CreateConnectionDefinition(address, couchDBDef.Username, couchDBDef.Password,
+			couchDBDef.MaxRetries, couchDBDef.MaxRetriesOnStartup, couchDBDef.RequestTimeout, couchDBDef.CreateGlobalChangesDB)
+		if err != nil {
+			logger.Errorf("Error calling CouchDB CreateConnectionDefinition() for cluster node %s: %s", address, err)
+			return nil, err
+		}
+		couchConf.ClusterURLs = append(couchConf.ClusterURLs, nodeConf.URL)
+	}
+	couchConf.Shards = couchDBDef.Shards
+	couchConf.Replicas = couchDBDef.Replicas
+	couchConf.DatabaseNamePrefix = couchDBDef.DatabaseNamePrefix
+
+	return newCouchInstance(couchConf)
+}
+
+func newCouchInstance(couchConf *CouchConnectionDef) (*CouchInstance, error) {
+
 	// Create the http client once
 	// Clients and Transports are safe for concurrent use by multiple goroutines
 	// and for efficiency should only be created once and re-used.
@@ -88,3 +88,7 @@ func ComputeStringHash(input string) []byte {
 func ComputeHash(input []byte) []byte {
 	return util.ComputeSHA256(input)
 }
+
+// ValueCommitmentPedersen is the value commitment scheme of the collections
+// committing to their private values with Pedersen commitments
+const ValueCommitmentPedersen = "Pedersen"
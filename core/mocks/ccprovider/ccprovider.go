@@ -109,6 +109,10 @@ func (m *MockTxSim) SetPrivateData(namespace, collection, key string, value []by
 	return nil
 }
 
+func (m *MockTxSim) SetPrivateDataWithBlindingFactor(namespace, collection, key string, value, blindingFactor []byte) error {
+	return nil
+}
+
 func (m *MockTxSim) SetPrivateDataMultipleKeys(namespace, collection string, kvs map[string][]byte) error {
 	return nil
 }
@@ -117,6 +121,10 @@ func (m *MockTxSim) GetStateMetadata(namespace, key string) (map[string][]byte,
 	return nil, nil
 }
 
+func (m *MockTxSim) GetPrivateDataHash(namespace, collection, key string) ([]byte, error) {
+	return nil, nil
+}
+
 func (m *MockTxSim) GetPrivateDataMetadata(namespace, collection, key string) (map[string][]byte, error) {
 	return nil, nil
 }
@@ -141,6 +149,10 @@ func (m *MockTxSim) DeletePrivateDataMetadata(namespace, collection, key string)
 	return nil
 }
 
+func (m *MockTxSim) GetPrivateDataUnvalidated(namespace, collection, key string) ([]byte, error) {
+	return nil, nil
+}
+
 // ExecuteInit executes the chaincode given context and spec deploy
 func (c *MockCcProviderImpl) ExecuteLegacyInit(txParams *ccprovider.TransactionParams, cccid *ccprovider.CCContext, spec *peer.ChaincodeDeploymentSpec) (*peer.Response, *peer.ChaincodeEvent, error) {
 	return &peer.Response{}, nil, nil
@@ -35,6 +35,7 @@ type MockSupport struct {
 	CheckInstantiationPolicyError    error
 	GetTransactionByIDErr            error
 	CheckACLErr                      error
+	CheckClientACLErr                error
 	SysCCMap                         map[string]struct{}
 	IsJavaRV                         bool
 	IsJavaErr                        error
@@ -114,6 +115,10 @@ func (s *MockSupport) CheckACL(signedProp *pb.SignedProposal, chdr *common.Chann
 	return s.CheckACLErr
 }
 
+func (s *MockSupport) CheckClientACL(signedData []*common.SignedData, chdr *common.ChannelHeader) error {
+	return s.CheckClientACLErr
+}
+
 func (s *MockSupport) IsJavaCC(buf []byte) (bool, error) {
 	return s.IsJavaRV, s.IsJavaErr
 }
@@ -24,6 +24,7 @@ type MockSupport struct {
 	GetInstantiationPolicyMap        map[string][]byte
 	CheckInstantiationPolicyMap      map[string]error
 	CheckCollectionConfigErr         error
+	CheckInstallPolicyErr            error
 }
 
 func (s *MockSupport) PutChaincodeToLocalStorage(ccpack ccprovider.CCPackage) error {
@@ -54,6 +55,10 @@ func (s *MockSupport) CheckInstantiationPolicy(signedProp *peer.SignedProposal,
 	return s.CheckInstantiationPolicyErr
 }
 
+func (s *MockSupport) CheckInstallPolicy(ccpack ccprovider.CCPackage) error {
+	return s.CheckInstallPolicyErr
+}
+
 func (s *MockSupport) CheckCollectionConfig(collectionConfig *common.CollectionConfig, channelName string) error {
 	return s.CheckCollectionConfigErr
 }
@@ -20,6 +20,7 @@ import (
 	"sync"
 
 	"github.com/hyperledger/fabric/common/channelconfig"
+	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
 	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/core/ledger"
@@ -28,10 +29,13 @@ import (
 )
 
 type Support struct {
-	LedgerVal     ledger.PeerLedger
-	MSPManagerVal msp.MSPManager
-	ApplyVal      error
-	ACVal         channelconfig.ApplicationCapabilities
+	LedgerVal        ledger.PeerLedger
+	MSPManagerVal    msp.MSPManager
+	ApplyVal         error
+	ACVal            channelconfig.ApplicationCapabilities
+	DedupWindowVal   uint64
+	ChannelVal       channelconfig.Channel
+	PolicyManagerVal policies.Manager
 
 	sync.Mutex
 	capabilitiesInvokeCount int
@@ -45,6 +49,19 @@ func (ms *Support) Capabilities() channelconfig.ApplicationCapabilities {
 	return ms.ACVal
 }
 
+// ChannelConfig returns ChannelVal, or a channel config without capabilities if unset
+func (ms *Support) ChannelConfig() channelconfig.Channel {
+	if ms.ChannelVal == nil {
+		return &mockconfig.Channel{CapabilitiesVal: &mockconfig.ChannelCapabilities{}}
+	}
+	return ms.ChannelVal
+}
+
+// TxDedupWindow returns DedupWindowVal
+func (ms *Support) TxDedupWindow() uint64 {
+	return ms.DedupWindowVal
+}
+
 // Ledger returns LedgerVal
 func (ms *Support) Ledger() ledger.PeerLedger {
 	return ms.LedgerVal
@@ -63,8 +80,12 @@ func (ms *Support) Apply(configtx *common.ConfigEnvelope) error {
 	return ms.ApplyVal
 }
 
+// PolicyManager returns PolicyManagerVal, or a policy manager without policies if unset
 func (ms *Support) PolicyManager() policies.Manager {
-	return &mockpolicies.Manager{}
+	if ms.PolicyManagerVal == nil {
+		return &mockpolicies.Manager{}
+	}
+	return ms.PolicyManagerVal
 }
 
 func (ms *Support) GetMSPIDs(cid string) []string {
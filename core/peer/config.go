@@ -126,44 +126,10 @@ func GetPeerEndpoint() (*pb.PeerEndpoint, error) {
 
 // GetServerConfig returns the gRPC server configuration for the peer
 func GetServerConfig() (comm.ServerConfig, error) {
-	secureOptions := &comm.SecureOptions{
-		UseTLS: viper.GetBool("peer.tls.enabled"),
-	}
+	secureOptions, err := getSecureOptions("peer.tls")
 	serverConfig := comm.ServerConfig{SecOpts: secureOptions}
-	if secureOptions.UseTLS {
-		// get the certs from the file system
-		serverKey, err := ioutil.ReadFile(config.GetPath("peer.tls.key.file"))
-		if err != nil {
-			return serverConfig, fmt.Errorf("error loading TLS key (%s)", err)
-		}
-		serverCert, err := ioutil.ReadFile(config.GetPath("peer.tls.cert.file"))
-		if err != nil {
-			return serverConfig, fmt.Errorf("error loading TLS certificate (%s)", err)
-		}
-		secureOptions.Certificate = serverCert
-		secureOptions.Key = serverKey
-		secureOptions.RequireClientCert = viper.GetBool("peer.tls.clientAuthRequired")
-		if secureOptions.RequireClientCert {
-			var clientRoots [][]byte
-			for _, file := range viper.GetStringSlice("peer.tls.clientRootCAs.files") {
-				clientRoot, err := ioutil.ReadFile(
-					config.TranslatePath(filepath.Dir(viper.ConfigFileUsed()), file))
-				if err != nil {
-					return serverConfig,
-						fmt.Errorf("error loading client root CAs (%s)", err)
-				}
-				clientRoots = append(clientRoots, clientRoot)
-			}
-			secureOptions.ClientRootCAs = clientRoots
-		}
-		// check for root cert
-		if config.GetPath("peer.tls.rootcert.file") != "" {
-			rootCert, err := ioutil.ReadFile(config.GetPath("peer.tls.rootcert.file"))
-			if err != nil {
-				return serverConfig, fmt.Errorf("error loading TLS root certificate (%s)", err)
-			}
-			secureOptions.ServerRootCAs = [][]byte{rootCert}
-		}
+	if err != nil {
+		return serverConfig, err
 	}
 	// get the default keepalive options
 	serverConfig.KaOpts = comm.DefaultKeepaliveOptions
@@ -174,6 +140,75 @@ func GetServerConfig() (comm.ServerConfig, error) {
 	return serverConfig, nil
 }
 
+// GetAdminServerConfig returns the gRPC server configuration of the admin
+// service when it has its own listener. Its TLS settings are read from
+// peer.adminService.tls if peer.adminService.tls.enabled is set, and are the
+// ones of the peer otherwise.
+func GetAdminServerConfig() (comm.ServerConfig, error) {
+	serverConfig, err := GetServerConfig()
+	if err != nil || !viper.IsSet("peer.adminService.tls.enabled") {
+		return serverConfig, err
+	}
+	serverConfig.SecOpts, err = getSecureOptions("peer.adminService.tls")
+	return serverConfig, err
+}
+
+// GetEventsGatewaySecureOptions returns the TLS settings of the events gateway
+// listener, read from peer.eventsGateway.tls
+func GetEventsGatewaySecureOptions() (*comm.SecureOptions, error) {
+	return getSecureOptions("peer.eventsGateway.tls")
+}
+
+// GetProfilingSecureOptions returns the TLS settings of the profiling
+// listener, read from peer.profile.tls
+func GetProfilingSecureOptions() (*comm.SecureOptions, error) {
+	return getSecureOptions("peer.profile.tls")
+}
+
+// getSecureOptions reads the TLS settings of a server under the given key
+func getSecureOptions(prefix string) (*comm.SecureOptions, error) {
+	secureOptions := &comm.SecureOptions{
+		UseTLS: viper.GetBool(prefix + ".enabled"),
+	}
+	if !secureOptions.UseTLS {
+		return secureOptions, nil
+	}
+	// get the certs from the file system
+	serverKey, err := ioutil.ReadFile(config.GetPath(prefix + ".key.file"))
+	if err != nil {
+		return secureOptions, fmt.Errorf("error loading TLS key (%s)", err)
+	}
+	serverCert, err := ioutil.ReadFile(config.GetPath(prefix + ".cert.file"))
+	if err != nil {
+		return secureOptions, fmt.Errorf("error loading TLS certificate (%s)", err)
+	}
+	secureOptions.Certificate = serverCert
+	secureOptions.Key = serverKey
+	secureOptions.RequireClientCert = viper.GetBool(prefix + ".clientAuthRequired")
+	if secureOptions.RequireClientCert {
+		var clientRoots [][]byte
+		for _, file := range viper.GetStringSlice(prefix + ".clientRootCAs.files") {
+			clientRoot, err := ioutil.ReadFile(
+				config.TranslatePath(filepath.Dir(viper.ConfigFileUsed()), file))
+			if err != nil {
+				return secureOptions,
+					fmt.Errorf("error loading client root CAs (%s)", err)
+			}
+			clientRoots = append(clientRoots, clientRoot)
+		}
+		secureOptions.ClientRootCAs = clientRoots
+	}
+	// check for root cert
+	if config.GetPath(prefix+".rootcert.file") != "" {
+		rootCert, err := ioutil.ReadFile(config.GetPath(prefix + ".rootcert.file"))
+		if err != nil {
+			return secureOptions, fmt.Errorf("error loading TLS root certificate (%s)", err)
+		}
+		secureOptions.ServerRootCAs = [][]byte{rootCert}
+	}
+	return secureOptions, nil
+}
+
 // GetClientCertificate returns the TLS certificate to use for gRPC client
 // connections
 func GetClientCertificate() (tls.Certificate, error) {
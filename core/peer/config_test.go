@@ -8,6 +8,7 @@ package peer
 import (
 	"crypto/tls"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"path/filepath"
 	"testing"
@@ -178,6 +179,52 @@ func TestGetServerConfig(t *testing.T) {
 
 }
 
+func TestGetAdminServerConfig(t *testing.T) {
+	defer func() {
+		viper.Set("peer.tls.enabled", false)
+		viper.Set("peer.adminService.tls.enabled", nil)
+	}()
+	viper.Set("peer.tls.enabled", true)
+	viper.Set("peer.tls.cert.file", filepath.Join("testdata", "Org1-server1-cert.pem"))
+	viper.Set("peer.tls.key.file", filepath.Join("testdata", "Org1-server1-key.pem"))
+	viper.Set("peer.tls.rootcert.file", filepath.Join("testdata", "Org1-cert.pem"))
+	viper.Set("peer.tls.clientAuthRequired", false)
+
+	// the TLS settings of the peer are used by default
+	sc, err := GetAdminServerConfig()
+	assert.NoError(t, err)
+	assert.True(t, sc.SecOpts.UseTLS)
+	assert.False(t, sc.SecOpts.RequireClientCert)
+	assert.Equal(t, comm.DefaultKeepaliveOptions.ServerTimeout, sc.KaOpts.ServerTimeout)
+
+	// the admin service has its own TLS settings and client certificate policy
+	viper.Set("peer.adminService.tls.enabled", true)
+	viper.Set("peer.adminService.tls.cert.file", filepath.Join("testdata", "Org2-server1-cert.pem"))
+	viper.Set("peer.adminService.tls.key.file", filepath.Join("testdata", "Org2-server1-key.pem"))
+	viper.Set("peer.adminService.tls.clientAuthRequired", true)
+	viper.Set("peer.adminService.tls.clientRootCAs.files", []string{filepath.Join("testdata", "Org2-cert.pem")})
+	sc, err = GetAdminServerConfig()
+	assert.NoError(t, err)
+	assert.True(t, sc.SecOpts.UseTLS)
+	assert.True(t, sc.SecOpts.RequireClientCert)
+	assert.Len(t, sc.SecOpts.ClientRootCAs, 1)
+	serverCert, err := ioutil.ReadFile(filepath.Join("testdata", "Org2-server1-cert.pem"))
+	assert.NoError(t, err)
+	assert.Equal(t, serverCert, sc.SecOpts.Certificate)
+
+	// bad admin TLS config
+	viper.Set("peer.adminService.tls.key.file", filepath.Join("testdata", "Org22-server1-key.pem"))
+	_, err = GetAdminServerConfig()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error loading TLS key")
+
+	// TLS can be disabled on the admin service only
+	viper.Set("peer.adminService.tls.enabled", false)
+	sc, err = GetAdminServerConfig()
+	assert.NoError(t, err)
+	assert.False(t, sc.SecOpts.UseTLS)
+}
+
 func TestGetClientCertificate(t *testing.T) {
 	viper.Set("peer.tls.key.file", "")
 	viper.Set("peer.tls.cert.file", "")
@@ -16,19 +16,27 @@ limitations under the License.
 package peer
 
 import (
+	"regexp"
 	"runtime/debug"
+	"sync"
 	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/deliver"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
+	"google.golang.org/grpc"
 )
 
 const pkgLogID = "common/deliverevents"
@@ -39,15 +47,79 @@ var logger = flogging.MustGetLogger(pkgLogID)
 // given resource name
 type PolicyCheckerProvider func(resourceName string) deliver.PolicyCheckerFunc
 
+// PeerChain is a deliver.Chain of the peer, which is also able to retrieve
+// the private data of its blocks and the history of its collection configurations
+type PeerChain interface {
+	deliver.Chain
+	// GetPvtDataByNum returns the private data of the given block number
+	GetPvtDataByNum(blockNum uint64, filter ledger.PvtNsCollFilter) ([]*ledger.TxPvtData, error)
+	// GetConfigHistoryRetriever returns the ConfigHistoryRetriever of the chain
+	GetConfigHistoryRetriever() (ledger.ConfigHistoryRetriever, error)
+}
+
+// IdentityDeserializerManager returns instances of msp.IdentityDeserializer
+type IdentityDeserializerManager interface {
+	// Deserializer returns an instance of msp.IdentityDeserializer for the passed channel
+	// if the channel exists
+	Deserializer(channel string) (msp.IdentityDeserializer, error)
+}
+
+// CollectionPolicyChecker checks whether a client is eligible to receive
+// the private data of a collection
+type CollectionPolicyChecker interface {
+	// CheckCollectionPolicy returns whether the creator of the given signed data satisfies
+	// the access policy of the given collection, as it was defined at the given block
+	CheckCollectionPolicy(blockNum uint64, ccName string, collName string, cfgHistoryRetriever ledger.ConfigHistoryRetriever,
+		deserializer msp.IdentityDeserializer, signedData *common.SignedData) (bool, error)
+}
+
 // server holds the dependencies necessary to create a deliver server
 type server struct {
-	dh                    *deliver.Handler
-	policyCheckerProvider PolicyCheckerProvider
+	dh                      *deliver.Handler
+	policyCheckerProvider   PolicyCheckerProvider
+	collectionPolicyChecker CollectionPolicyChecker
+	idDeserializerManager   IdentityDeserializerManager
+	// blockResponses are the encoded block responses the deliver streams share
+	blockResponses *deliver.EncodedResponses
+
+	closing   chan struct{}
+	closeOnce sync.Once
+}
+
+// Drainer is implemented by the deliver servers which end the deliver
+// requests they serve when the peer shuts down
+type Drainer interface {
+	// Drain ends the deliver requests being served and rejects the new ones,
+	// telling the clients to retry against another peer
+	Drain()
+}
+
+// Drain ends the deliver requests being served and rejects the new ones with
+// SERVICE_UNAVAILABLE
+func (s *server) Drain() {
+	s.closeOnce.Do(func() {
+		close(s.closing)
+	})
 }
 
 // blockResponseSender structure used to send block responses
 type blockResponseSender struct {
 	peer.Deliver_DeliverServer
+	// slimming is the block slimming of the request being served
+	slimming *peer.BlockSlimming
+	// blockResponses, if set, are the encoded block responses shared with the
+	// other deliver streams
+	blockResponses *deliver.EncodedResponses
+}
+
+// PrepareRequest reads the block slimming of the request
+func (brs *blockResponseSender) PrepareRequest(signedData *common.SignedData) error {
+	slimming, err := newBlockSlimming(signedData)
+	if err != nil {
+		return errors.WithMessage(err, "invalid block slimming")
+	}
+	brs.slimming = slimming
+	return nil
 }
 
 // SendStatusResponse generates status reply proto message
@@ -59,16 +131,40 @@ func (brs *blockResponseSender) SendStatusResponse(status common.Status) error {
 }
 
 // SendBlockResponse generates deliver response with block message
-func (brs *blockResponseSender) SendBlockResponse(block *common.Block) error {
+func (brs *blockResponseSender) SendBlockResponse(block *common.Block, channelID string, _ deliver.Chain, _ *common.SignedData) error {
+	if brs.slimming != nil {
+		block = slimBlock(block, brs.slimming)
+	}
 	response := &peer.DeliverResponse{
-		Type: &peer.DeliverResponse_Block{Block: block},
+		Type:       &peer.DeliverResponse_Block{Block: block},
+		Checkpoint: deliver.CheckpointAfter(channelID, block.Header.Number),
+	}
+	// the slimmed blocks are specific to the request
+	if brs.blockResponses == nil || brs.slimming != nil {
+		return brs.Send(response)
 	}
-	return brs.Send(response)
+	encoded, err := brs.blockResponses.Encode(channelID, block, response)
+	if err != nil {
+		return err
+	}
+	return brs.SendMsg(deliver.EncodedMessage(response, encoded))
 }
 
 // filteredBlockResponseSender structure used to send filtered block responses
 type filteredBlockResponseSender struct {
 	peer.Deliver_DeliverFilteredServer
+	// filter is the chaincode event filter of the request being served
+	filter *chaincodeEventFilter
+}
+
+// PrepareRequest compiles the chaincode event filter of the request
+func (fbrs *filteredBlockResponseSender) PrepareRequest(signedData *common.SignedData) error {
+	filter, err := newChaincodeEventFilter(signedData)
+	if err != nil {
+		return errors.WithMessage(err, "invalid chaincode event filter")
+	}
+	fbrs.filter = filter
+	return nil
 }
 
 func (fbrs *filteredBlockResponseSender) SendStatusResponse(status common.Status) error {
@@ -79,20 +175,326 @@ func (fbrs *filteredBlockResponseSender) SendStatusResponse(status common.Status
 }
 
 // SendBlockResponse generates deliver response with block message
-func (fbrs *filteredBlockResponseSender) SendBlockResponse(block *common.Block) error {
+func (fbrs *filteredBlockResponseSender) SendBlockResponse(block *common.Block, channelID string, _ deliver.Chain, _ *common.SignedData) error {
 	// Generates filtered block response
 	b := blockEvent(*block)
-	filteredBlock, err := b.toFilteredBlock()
+	filteredBlock, err := b.toFilteredBlock(fbrs.filter)
 	if err != nil {
 		logger.Warningf("Failed to generate filtered block due to: %s", err)
 		return fbrs.SendStatusResponse(common.Status_BAD_REQUEST)
 	}
 	response := &peer.DeliverResponse{
-		Type: &peer.DeliverResponse_FilteredBlock{FilteredBlock: filteredBlock},
+		Type:       &peer.DeliverResponse_FilteredBlock{FilteredBlock: filteredBlock},
+		Checkpoint: deliver.CheckpointAfter(channelID, block.Header.Number),
 	}
 	return fbrs.Send(response)
 }
 
+// blockAndPrivateDataResponseSender structure used to send block and private data responses
+type blockAndPrivateDataResponseSender struct {
+	peer.Deliver_DeliverWithPrivateDataServer
+	CollectionPolicyChecker
+	IdentityDeserializerManager
+}
+
+// SendStatusResponse generates status reply proto message
+func (bprs *blockAndPrivateDataResponseSender) SendStatusResponse(status common.Status) error {
+	reply := &peer.DeliverResponse{
+		Type: &peer.DeliverResponse_Status{Status: status},
+	}
+	return bprs.Send(reply)
+}
+
+// SendBlockResponse gets private data and generates deliver response with both block and private data
+func (bprs *blockAndPrivateDataResponseSender) SendBlockResponse(block *common.Block, channelID string, chain deliver.Chain, signedData *common.SignedData) error {
+	pvtData, err := bprs.getPrivateData(block, chain, channelID, signedData)
+	if err != nil {
+		logger.Warningf("[channel: %s] Failed to get private data for block %d due to: %s", channelID, block.Header.Number, err)
+		if sendErr := bprs.SendStatusResponse(common.Status_INTERNAL_SERVER_ERROR); sendErr != nil {
+			return sendErr
+		}
+		return errors.Wrapf(err, "failed to get private data for block %d", block.Header.Number)
+	}
+
+	response := &peer.DeliverResponse{
+		Type: &peer.DeliverResponse_BlockAndPrivateData{
+			BlockAndPrivateData: &peer.BlockAndPrivateData{
+				Block:          block,
+				PrivateDataMap: pvtData,
+			},
+		},
+		Checkpoint: deliver.CheckpointAfter(channelID, block.Header.Number),
+	}
+	return bprs.Send(response)
+}
+
+// getPrivateData returns the private data of the given block, filtered to only
+// the collections the creator of the signed data is eligible to receive
+func (bprs *blockAndPrivateDataResponseSender) getPrivateData(block *common.Block, chain deliver.Chain, channelID string, signedData *common.SignedData) (map[uint64]*rwset.TxPvtReadWriteSet, error) {
+	peerChain, ok := chain.(PeerChain)
+	if !ok {
+		return nil, errors.New("wrong chain type")
+	}
+
+	pvtData, err := peerChain.GetPvtDataByNum(block.Header.Number, nil)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed retrieving private data")
+	}
+
+	configHistory, err := peerChain.GetConfigHistoryRetriever()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed retrieving config history retriever")
+	}
+
+	deserializer, err := bprs.Deserializer(channelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed retrieving identity deserializer")
+	}
+
+	pvtDataMap := make(map[uint64]*rwset.TxPvtReadWriteSet)
+	for _, item := range pvtData {
+		if item.WriteSet == nil {
+			continue
+		}
+		filteredRWSet := &rwset.TxPvtReadWriteSet{
+			DataModel: item.WriteSet.DataModel,
+		}
+		for _, ns := range item.WriteSet.NsPvtRwset {
+			filteredNsRWSet := &rwset.NsPvtReadWriteSet{
+				Namespace: ns.Namespace,
+			}
+			for _, coll := range ns.CollectionPvtRwset {
+				eligible, err := bprs.CheckCollectionPolicy(block.Header.Number, ns.Namespace, coll.CollectionName, configHistory, deserializer, signedData)
+				if err != nil {
+					return nil, err
+				}
+				if !eligible {
+					logger.Debugf("[channel: %s] Client isn't eligible for collection %s of namespace %s in block %d",
+						channelID, coll.CollectionName, ns.Namespace, block.Header.Number)
+					continue
+				}
+				filteredNsRWSet.CollectionPvtRwset = append(filteredNsRWSet.CollectionPvtRwset, coll)
+			}
+			if len(filteredNsRWSet.CollectionPvtRwset) > 0 {
+				filteredRWSet.NsPvtRwset = append(filteredRWSet.NsPvtRwset, filteredNsRWSet)
+			}
+		}
+		if len(filteredRWSet.NsPvtRwset) > 0 {
+			pvtDataMap[item.SeqInBlock] = filteredRWSet
+		}
+	}
+
+	return pvtDataMap, nil
+}
+
+// chaincodeEventFilter is the compiled form of a peer.ChaincodeEventFilter
+type chaincodeEventFilter struct {
+	chaincodeID *regexp.Regexp
+	eventName   *regexp.Regexp
+}
+
+// requestExtension returns the channel header extension of the given deliver request
+func requestExtension(signedData *common.SignedData) ([]byte, error) {
+	if signedData == nil {
+		return nil, nil
+	}
+	payload, err := utils.UnmarshalPayload(signedData.Data)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, errors.New("payload header is nil")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	return chdr.Extension, nil
+}
+
+// newChaincodeEventFilter returns the chaincode event filter carried in the
+// channel header extension of the given deliver request, or nil if there is none
+func newChaincodeEventFilter(signedData *common.SignedData) (*chaincodeEventFilter, error) {
+	extension, err := requestExtension(signedData)
+	if err != nil || len(extension) == 0 {
+		return nil, err
+	}
+
+	eventFilter := &peer.ChaincodeEventFilter{}
+	if err := proto.Unmarshal(extension, eventFilter); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling chaincode event filter")
+	}
+
+	filter := &chaincodeEventFilter{}
+	if filter.chaincodeID, err = compileEventPattern(eventFilter.ChaincodeId); err != nil {
+		return nil, errors.WithMessage(err, "invalid chaincode ID pattern")
+	}
+	if filter.eventName, err = compileEventPattern(eventFilter.EventName); err != nil {
+		return nil, errors.WithMessage(err, "invalid event name pattern")
+	}
+	return filter, nil
+}
+
+// compileEventPattern compiles the given pattern so that it must match the
+// whole string, an empty pattern yields a nil regexp which matches everything
+func compileEventPattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// matches returns whether the given chaincode event passes the filter,
+// a nil filter lets every event through
+func (f *chaincodeEventFilter) matches(event *peer.ChaincodeEvent) bool {
+	if f == nil {
+		return true
+	}
+	if f.chaincodeID != nil && !f.chaincodeID.MatchString(event.ChaincodeId) {
+		return false
+	}
+	if f.eventName != nil && !f.eventName.MatchString(event.EventName) {
+		return false
+	}
+	return true
+}
+
+// newBlockSlimming returns the block slimming carried in the channel header
+// extension of the given deliver request, or nil if there is none
+func newBlockSlimming(signedData *common.SignedData) (*peer.BlockSlimming, error) {
+	extension, err := requestExtension(signedData)
+	if err != nil || len(extension) == 0 {
+		return nil, err
+	}
+
+	slimming := &peer.BlockSlimming{}
+	if err := proto.Unmarshal(extension, slimming); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling block slimming")
+	}
+	return slimming, nil
+}
+
+// slimBlock returns a copy of the given block whose endorser transactions are
+// stripped as requested by the given slimming. The transactions which can't be
+// parsed are kept as they are
+func slimBlock(block *common.Block, slimming *peer.BlockSlimming) *common.Block {
+	slim := &common.Block{
+		Header:   block.Header,
+		Data:     &common.BlockData{Data: make([][]byte, len(block.Data.Data))},
+		Metadata: block.Metadata,
+	}
+	for txIndex, envBytes := range block.Data.Data {
+		slimEnvBytes, err := slimEnvelope(envBytes, slimming)
+		if err != nil {
+			logger.Debugf("Not slimming transaction %d of block %d: %s", txIndex, block.Header.Number, err)
+			slimEnvBytes = envBytes
+		}
+		slim.Data.Data[txIndex] = slimEnvBytes
+	}
+	return slim
+}
+
+// slimEnvelope returns the given marshaled envelope stripped as requested by the given
+// slimming, if it is an endorser transaction
+func slimEnvelope(envBytes []byte, slimming *peer.BlockSlimming) ([]byte, error) {
+	env, err := utils.GetEnvelopeFromBlock(envBytes)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, errors.New("payload header is nil")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	if chdr.Type != int32(common.HeaderType_ENDORSER_TRANSACTION) {
+		return envBytes, nil
+	}
+
+	if slimming.StripCreators {
+		if payload.Header.SignatureHeader, err = stripCreator(payload.Header.SignatureHeader); err != nil {
+			return nil, err
+		}
+	}
+	tx, err := utils.GetTransaction(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+	for _, action := range tx.Actions {
+		if slimming.StripCreators {
+			if action.Header, err = stripCreator(action.Header); err != nil {
+				return nil, err
+			}
+		}
+		if action.Payload, err = slimChaincodeActionPayload(action.Payload, slimming); err != nil {
+			return nil, err
+		}
+	}
+	if payload.Data, err = proto.Marshal(tx); err != nil {
+		return nil, errors.Wrap(err, "error marshaling transaction")
+	}
+	if env.Payload, err = proto.Marshal(payload); err != nil {
+		return nil, errors.Wrap(err, "error marshaling payload")
+	}
+	if slimming.StripSignatures {
+		env.Signature = nil
+	}
+	return proto.Marshal(env)
+}
+
+// stripCreator removes the creator of the given marshaled signature header
+func stripCreator(shdrBytes []byte) ([]byte, error) {
+	shdr, err := utils.GetSignatureHeader(shdrBytes)
+	if err != nil {
+		return nil, err
+	}
+	shdr.Creator = nil
+	return proto.Marshal(shdr)
+}
+
+// slimChaincodeActionPayload strips the endorsements, read-write sets and events of the
+// given marshaled chaincode action payload, as requested by the given slimming
+func slimChaincodeActionPayload(capBytes []byte, slimming *peer.BlockSlimming) ([]byte, error) {
+	cap, err := utils.GetChaincodeActionPayload(capBytes)
+	if err != nil {
+		return nil, err
+	}
+	if cap.Action == nil {
+		return capBytes, nil
+	}
+	if slimming.StripSignatures {
+		cap.Action.Endorsements = nil
+	}
+	if slimming.StripRwsets || slimming.StripEvents {
+		prp, err := utils.GetProposalResponsePayload(cap.Action.ProposalResponsePayload)
+		if err != nil {
+			return nil, err
+		}
+		chaincodeAction, err := utils.GetChaincodeAction(prp.Extension)
+		if err != nil {
+			return nil, err
+		}
+		if slimming.StripRwsets {
+			chaincodeAction.Results = nil
+		}
+		if slimming.StripEvents {
+			chaincodeAction.Events = nil
+		}
+		if prp.Extension, err = proto.Marshal(chaincodeAction); err != nil {
+			return nil, errors.Wrap(err, "error marshaling chaincode action")
+		}
+		if cap.Action.ProposalResponsePayload, err = proto.Marshal(prp); err != nil {
+			return nil, errors.Wrap(err, "error marshaling proposal response payload")
+		}
+	}
+	return proto.Marshal(cap)
+}
+
 // transactionActions aliasing for peer.TransactionAction pointers slice
 type transactionActions []*peer.TransactionAction
 
@@ -119,32 +521,106 @@ func (s *server) DeliverFiltered(srv peer.Deliver_DeliverFilteredServer) error {
 func (s *server) Deliver(srv peer.Deliver_DeliverServer) (err error) {
 	logger.Debugf("Starting new Deliver handler")
 	defer dumpStacktraceOnPanic()
+	responseSender := &blockResponseSender{Deliver_DeliverServer: srv}
+	// only gRPC passes the shared encoding of the blocks through as is, the
+	// other streams, like the ones of the events gateway, send the responses
+	if _, ok := grpc.MethodFromServerStream(srv); ok {
+		responseSender.blockResponses = s.blockResponses
+	}
+	// getting policy checker based on resources.Event_Block resource name
+	deliverServer := &deliver.Server{
+		PolicyChecker:  s.policyCheckerProvider(resources.Event_Block),
+		Receiver:       srv,
+		ResponseSender: responseSender,
+	}
+	return s.dh.Handle(srv.Context(), deliverServer)
+}
+
+// DeliverWithPrivateData sends a stream of blocks and pvtdata to a client after commitment
+func (s *server) DeliverWithPrivateData(srv peer.Deliver_DeliverWithPrivateDataServer) (err error) {
+	logger.Debugf("Starting new DeliverWithPrivateData handler")
+	defer dumpStacktraceOnPanic()
 	// getting policy checker based on resources.Event_Block resource name
 	deliverServer := &deliver.Server{
 		PolicyChecker: s.policyCheckerProvider(resources.Event_Block),
 		Receiver:      srv,
-		ResponseSender: &blockResponseSender{
-			Deliver_DeliverServer: srv,
+		ResponseSender: &blockAndPrivateDataResponseSender{
+			Deliver_DeliverWithPrivateDataServer: srv,
+			CollectionPolicyChecker:              s.collectionPolicyChecker,
+			IdentityDeserializerManager:          s.idDeserializerManager,
 		},
 	}
 	return s.dh.Handle(srv.Context(), deliverServer)
 }
 
-// NewDeliverEventsServer creates a peer.Deliver server to deliver block and
-// filtered block events
-func NewDeliverEventsServer(mutualTLS bool, policyCheckerProvider PolicyCheckerProvider, chainManager deliver.ChainManager) peer.DeliverServer {
+// NewDeliverEventsServer creates a peer.Deliver server to deliver block,
+// filtered block and block with private data events. The stream quota, if
+// any, bounds the number of deliver requests of each channel served concurrently
+func NewDeliverEventsServer(mutualTLS bool, policyCheckerProvider PolicyCheckerProvider, chainManager deliver.ChainManager, streamQuota deliver.StreamQuota) peer.DeliverServer {
 	timeWindow := viper.GetDuration("peer.authentication.timewindow")
 	if timeWindow == 0 {
 		defaultTimeWindow := 15 * time.Minute
 		logger.Warningf("`peer.authentication.timewindow` not set; defaulting to %s", defaultTimeWindow)
 		timeWindow = defaultTimeWindow
 	}
+	dh := deliver.NewHandler(chainManager, timeWindow, mutualTLS)
+	dh.StreamQuota = streamQuota
+	dh.MaxStreamsPerIdentity = viper.GetInt("peer.deliver.maxStreamsPerIdentity")
+	dh.BlocksInFlight = viper.GetInt("peer.deliver.blocksInFlight")
+	closing := make(chan struct{})
+	dh.Closing = closing
 	return &server{
-		dh: deliver.NewHandler(chainManager, timeWindow, mutualTLS),
-		policyCheckerProvider: policyCheckerProvider,
+		dh:                      dh,
+		policyCheckerProvider:   policyCheckerProvider,
+		collectionPolicyChecker: &collPolicyChecker{},
+		idDeserializerManager:   &identityDeserializerMgr{},
+		blockResponses:          deliver.NewEncodedResponses(),
+		closing:                 closing,
 	}
 }
 
+// collPolicyChecker is the default implementation of CollectionPolicyChecker,
+// which evaluates the access filter of the collection's CollectionAccessPolicy
+type collPolicyChecker struct{}
+
+// CheckCollectionPolicy returns whether the creator of the given signed data satisfies
+// the access policy of the given collection, as it was defined at the given block
+func (cs *collPolicyChecker) CheckCollectionPolicy(blockNum uint64, ccName string, collName string, cfgHistoryRetriever ledger.ConfigHistoryRetriever,
+	deserializer msp.IdentityDeserializer, signedData *common.SignedData) (bool, error) {
+	configInfo, err := cfgHistoryRetriever.MostRecentCollectionConfigBelow(blockNum, ccName)
+	if err != nil {
+		return false, errors.WithMessage(err, "failed retrieving collection config")
+	}
+	if configInfo == nil {
+		return false, nil
+	}
+
+	for _, config := range configInfo.CollectionConfig.GetConfig() {
+		staticConfig := config.GetStaticCollectionConfig()
+		if staticConfig == nil || staticConfig.Name != collName {
+			continue
+		}
+		coll := &privdata.SimpleCollection{}
+		if err := coll.Setup(staticConfig, deserializer); err != nil {
+			return false, errors.WithMessage(err, "failed setting up collection")
+		}
+		return coll.AccessFilter()(*signedData), nil
+	}
+	return false, nil
+}
+
+// identityDeserializerMgr is the default implementation of IdentityDeserializerManager,
+// which returns the MSP manager of the channel
+type identityDeserializerMgr struct{}
+
+func (*identityDeserializerMgr) Deserializer(channelID string) (msp.IdentityDeserializer, error) {
+	id := mgmt.GetManagerForChain(channelID)
+	if id == nil {
+		return nil, errors.Errorf("channel %s not found", channelID)
+	}
+	return id, nil
+}
+
 func (s *server) sendProducer(srv peer.Deliver_DeliverFilteredServer) func(msg proto.Message) error {
 	return func(msg proto.Message) error {
 		response, ok := msg.(*peer.DeliverResponse)
@@ -156,7 +632,9 @@ func (s *server) sendProducer(srv peer.Deliver_DeliverFilteredServer) func(msg p
 	}
 }
 
-func (block *blockEvent) toFilteredBlock() (*peer.FilteredBlock, error) {
+// toFilteredBlock converts the block to a filtered block, if a chaincode
+// event filter is given only the transactions with matching events are kept
+func (block *blockEvent) toFilteredBlock(filter *chaincodeEventFilter) (*peer.FilteredBlock, error) {
 	filteredBlock := &peer.FilteredBlock{
 		Number: block.Header.Number,
 	}
@@ -208,11 +686,17 @@ func (block *blockEvent) toFilteredBlock() (*peer.FilteredBlock, error) {
 				return nil, errors.WithMessage(err, "error unmarshal transaction payload for block event")
 			}
 
-			filteredTransaction.Data, err = transactionActions(tx.Actions).toFilteredActions()
+			filteredActions, err := transactionActions(tx.Actions).toFilteredActions(filter)
 			if err != nil {
 				logger.Errorf(err.Error())
 				return nil, err
 			}
+			if filter != nil && len(filteredActions.TransactionActions.ChaincodeActions) == 0 {
+				continue
+			}
+			filteredTransaction.Data = filteredActions
+		} else if filter != nil {
+			continue
 		}
 
 		filteredBlock.FilteredTransactions = append(filteredBlock.FilteredTransactions, filteredTransaction)
@@ -221,7 +705,7 @@ func (block *blockEvent) toFilteredBlock() (*peer.FilteredBlock, error) {
 	return filteredBlock, nil
 }
 
-func (ta transactionActions) toFilteredActions() (*peer.FilteredTransaction_TransactionActions, error) {
+func (ta transactionActions) toFilteredActions(filter *chaincodeEventFilter) (*peer.FilteredTransaction_TransactionActions, error) {
 	transactionActions := &peer.FilteredTransactionActions{}
 	for _, action := range ta {
 		chaincodeActionPayload, err := utils.GetChaincodeActionPayload(action.Payload)
@@ -248,7 +732,7 @@ func (ta transactionActions) toFilteredActions() (*peer.FilteredTransaction_Tran
 			return nil, errors.WithMessage(err, "error unmarshal chaincode event for block event")
 		}
 
-		if ccEvent.GetChaincodeId() != "" {
+		if ccEvent.GetChaincodeId() != "" && filter.matches(ccEvent) {
 			filteredAction := &peer.FilteredChaincodeAction{
 				ChaincodeEvent: &peer.ChaincodeEvent{
 					TxId:        ccEvent.TxId,
@@ -8,6 +8,7 @@ package peer
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"sync"
 	"testing"
@@ -17,7 +18,10 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	"github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -133,8 +137,17 @@ func (*mockDeliverServer) SendHeader(metadata.MD) error {
 	panic("implement me")
 }
 
-func (*mockDeliverServer) SendMsg(m interface{}) error {
-	panic("implement me")
+// SendMsg decodes the block responses, sent encoded ahead, and sends them
+func (m *mockDeliverServer) SendMsg(msg interface{}) error {
+	encoded, err := proto.Marshal(msg.(proto.Message))
+	if err != nil {
+		return err
+	}
+	response := &peer.DeliverResponse{}
+	if err := proto.Unmarshal(encoded, response); err != nil {
+		return err
+	}
+	return m.Send(response)
 }
 
 func (*mockDeliverServer) SetHeader(metadata.MD) error {
@@ -371,7 +384,7 @@ func TestEventsServer_DeliverFiltered(t *testing.T) {
 			wg := &sync.WaitGroup{}
 			chainManager, deliverServer := test.prepare(wg)
 
-			server := NewDeliverEventsServer(false, defaultPolicyCheckerProvider, chainManager)
+			server := NewDeliverEventsServer(false, defaultPolicyCheckerProvider, chainManager, nil)
 			err := server.DeliverFiltered(deliverServer)
 			wg.Wait()
 			// no error expected
@@ -510,3 +523,468 @@ func createTestBlock(data []*common.Envelope) (*common.Block, error) {
 	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = make([]byte, len(data))
 	return block, nil
 }
+
+// mockPeerChain mock implementation of the PeerChain interface
+type mockPeerChain struct {
+	mockChainSupport
+}
+
+func (m *mockPeerChain) GetPvtDataByNum(blockNum uint64, filter ledger.PvtNsCollFilter) ([]*ledger.TxPvtData, error) {
+	args := m.Called(blockNum, filter)
+	return args.Get(0).([]*ledger.TxPvtData), args.Error(1)
+}
+
+func (m *mockPeerChain) GetConfigHistoryRetriever() (ledger.ConfigHistoryRetriever, error) {
+	args := m.Called()
+	retriever, _ := args.Get(0).(ledger.ConfigHistoryRetriever)
+	return retriever, args.Error(1)
+}
+
+// mockCollectionPolicyChecker grants access only to the collections it holds
+type mockCollectionPolicyChecker map[string]bool
+
+func (m mockCollectionPolicyChecker) CheckCollectionPolicy(_ uint64, ccName string, collName string, _ ledger.ConfigHistoryRetriever,
+	_ msp.IdentityDeserializer, _ *common.SignedData) (bool, error) {
+	return m[ccName+"/"+collName], nil
+}
+
+type mockIdentityDeserializerManager struct{}
+
+func (*mockIdentityDeserializerManager) Deserializer(_ string) (msp.IdentityDeserializer, error) {
+	return nil, nil
+}
+
+// mockPrivateDataServer mock implementation of the Deliver_DeliverWithPrivateDataServer
+type mockPrivateDataServer struct {
+	mockDeliverServer
+}
+
+func TestBlockAndPrivateDataResponseSender(t *testing.T) {
+	block := &common.Block{Header: &common.BlockHeader{Number: 5}}
+	pvtData := []*ledger.TxPvtData{
+		{
+			SeqInBlock: 0,
+			WriteSet: &rwset.TxPvtReadWriteSet{
+				DataModel: rwset.TxReadWriteSet_KV,
+				NsPvtRwset: []*rwset.NsPvtReadWriteSet{
+					{
+						Namespace: "mycc",
+						CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{
+							{CollectionName: "coll1", Rwset: []byte("rws1")},
+							{CollectionName: "coll2", Rwset: []byte("rws2")},
+						},
+					},
+				},
+			},
+		},
+		{
+			SeqInBlock: 1,
+			WriteSet: &rwset.TxPvtReadWriteSet{
+				NsPvtRwset: []*rwset.NsPvtReadWriteSet{
+					{
+						Namespace: "othercc",
+						CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{
+							{CollectionName: "coll1", Rwset: []byte("rws3")},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	chain := &mockPeerChain{}
+	chain.On("GetPvtDataByNum", uint64(5), mock.Anything).Return(pvtData, nil)
+	chain.On("GetConfigHistoryRetriever").Return(nil, nil)
+
+	srv := &mockPrivateDataServer{}
+	var response *peer.DeliverResponse
+	srv.On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		response = args.Get(0).(*peer.DeliverResponse)
+	}).Return(nil)
+
+	sender := &blockAndPrivateDataResponseSender{
+		Deliver_DeliverWithPrivateDataServer: srv,
+		CollectionPolicyChecker:              mockCollectionPolicyChecker{"mycc/coll2": true},
+		IdentityDeserializerManager:          &mockIdentityDeserializerManager{},
+	}
+
+	err := sender.SendBlockResponse(block, "testChainID", chain, &common.SignedData{})
+	assert.NoError(t, err)
+	blockAndPvtData := response.GetBlockAndPrivateData()
+	assert.NotNil(t, blockAndPvtData)
+	assert.Equal(t, block, blockAndPvtData.Block)
+	// only the eligible collection of the first transaction is sent
+	assert.Len(t, blockAndPvtData.PrivateDataMap, 1)
+	txPvtData := blockAndPvtData.PrivateDataMap[0]
+	assert.Equal(t, rwset.TxReadWriteSet_KV, txPvtData.DataModel)
+	assert.Len(t, txPvtData.NsPvtRwset, 1)
+	assert.Equal(t, "mycc", txPvtData.NsPvtRwset[0].Namespace)
+	assert.Len(t, txPvtData.NsPvtRwset[0].CollectionPvtRwset, 1)
+	assert.Equal(t, "coll2", txPvtData.NsPvtRwset[0].CollectionPvtRwset[0].CollectionName)
+
+	// a chain which isn't a PeerChain can't serve private data
+	err = sender.SendBlockResponse(block, "testChainID", &mockChainSupport{}, &common.SignedData{})
+	assert.EqualError(t, err, "failed to get private data for block 5: wrong chain type")
+	assert.Equal(t, common.Status_INTERNAL_SERVER_ERROR, response.GetStatus())
+}
+
+func TestDeliverWithPrivateDataEndsOnError(t *testing.T) {
+	viper.Set("peer.authentication.timewindow", "1s")
+	block, err := createTestBlock(nil)
+	assert.NoError(t, err)
+
+	// the chain has blocks to deliver but, not being a PeerChain, no private data
+	iter := &mockIterator{}
+	iter.On("Next").Return(block, common.Status_SUCCESS)
+	reader := &mockReader{}
+	reader.On("Iterator", mock.Anything).Return(iter, uint64(0))
+	reader.On("Height").Return(uint64(2))
+	chain := &mockChainSupport{}
+	chain.On("Sequence").Return(uint64(0))
+	chain.On("Reader").Return(reader)
+	chainManager := &mockChainManager{}
+	chainManager.On("GetChain", "testChainID").Return(chain, true)
+
+	srv := &mockPrivateDataServer{}
+	srv.On("Context").Return(peer2.NewContext(context.TODO(), &peer2.Peer{}))
+	srv.On("Recv").Return(&common.Envelope{
+		Payload: utils.MarshalOrPanic(&common.Payload{
+			Header: &common.Header{
+				ChannelHeader: utils.MarshalOrPanic(&common.ChannelHeader{
+					ChannelId: "testChainID",
+					Timestamp: util.CreateUtcTimestamp(),
+				}),
+				SignatureHeader: utils.MarshalOrPanic(&common.SignatureHeader{}),
+			},
+			Data: utils.MarshalOrPanic(&orderer.SeekInfo{
+				Start:    &orderer.SeekPosition{Type: &orderer.SeekPosition_Oldest{Oldest: &orderer.SeekOldest{}}},
+				Stop:     &orderer.SeekPosition{Type: &orderer.SeekPosition_Newest{Newest: &orderer.SeekNewest{}}},
+				Behavior: orderer.SeekInfo_BLOCK_UNTIL_READY,
+			}),
+		}),
+	}, nil).Once()
+	srv.On("Recv").Return(nil, io.EOF)
+	var responses []*peer.DeliverResponse
+	srv.On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		responses = append(responses, args.Get(0).(*peer.DeliverResponse))
+	}).Return(nil)
+
+	server := NewDeliverEventsServer(false, defaultPolicyCheckerProvider, chainManager, nil)
+	err = server.DeliverWithPrivateData(srv)
+	assert.EqualError(t, err, "failed to get private data for block 0: wrong chain type")
+	// the stream ends with the status, no block follows it
+	assert.Len(t, responses, 1)
+	assert.Equal(t, common.Status_INTERNAL_SERVER_ERROR, responses[0].GetStatus())
+}
+
+func TestFilteredBlockChaincodeEventFilter(t *testing.T) {
+	var envelopes []*common.Envelope
+	for i, ev := range []struct{ ccName, eventName string }{
+		{"mycc", "transfer"},
+		{"mycc", "mint"},
+		{"othercc", "transfer"},
+	} {
+		chaincodeActionPayload, err := createChaincodeAction(ev.ccName, ev.eventName, fmt.Sprintf("tx%d", i))
+		assert.NoError(t, err)
+		payload, err := createEndorsement("testChainID", fmt.Sprintf("tx%d", i), chaincodeActionPayload)
+		assert.NoError(t, err)
+		envelopes = append(envelopes, &common.Envelope{Payload: utils.MarshalOrPanic(payload)})
+	}
+	block, err := createTestBlock(envelopes)
+	assert.NoError(t, err)
+
+	signedDataWithFilter := func(filter proto.Message) *common.SignedData {
+		env, err := utils.CreateSignedEnvelopeWithExtension(common.HeaderType_DELIVER_SEEK_INFO, "testChainID", nil, &orderer.SeekInfo{}, 0, 0, nil, filter)
+		assert.NoError(t, err)
+		return &common.SignedData{Data: env.Payload}
+	}
+
+	tests := []struct {
+		name          string
+		filter        *peer.ChaincodeEventFilter
+		expectedTxIDs []string
+	}{
+		{name: "no filter", expectedTxIDs: []string{"tx0", "tx1", "tx2"}},
+		{name: "empty filter", filter: &peer.ChaincodeEventFilter{}, expectedTxIDs: []string{"tx0", "tx1", "tx2"}},
+		{name: "chaincode ID", filter: &peer.ChaincodeEventFilter{ChaincodeId: "mycc"}, expectedTxIDs: []string{"tx0", "tx1"}},
+		{name: "event name", filter: &peer.ChaincodeEventFilter{EventName: "trans.*"}, expectedTxIDs: []string{"tx0", "tx2"}},
+		{name: "both", filter: &peer.ChaincodeEventFilter{ChaincodeId: "other.*", EventName: "transfer"}, expectedTxIDs: []string{"tx2"}},
+		{name: "whole name match", filter: &peer.ChaincodeEventFilter{ChaincodeId: "my"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var extension proto.Message
+			if test.filter != nil {
+				extension = test.filter
+			}
+			filter, err := newChaincodeEventFilter(signedDataWithFilter(extension))
+			assert.NoError(t, err)
+			b := blockEvent(*block)
+			filteredBlock, err := b.toFilteredBlock(filter)
+			assert.NoError(t, err)
+			var txIDs []string
+			for _, tx := range filteredBlock.FilteredTransactions {
+				txIDs = append(txIDs, tx.Txid)
+			}
+			assert.Equal(t, test.expectedTxIDs, txIDs)
+		})
+	}
+
+	t.Run("prepared filter", func(t *testing.T) {
+		srv := &mockDeliverServer{}
+		var response *peer.DeliverResponse
+		srv.On("Send", mock.Anything).Run(func(args mock.Arguments) {
+			response = args.Get(0).(*peer.DeliverResponse)
+		}).Return(nil)
+		sender := &filteredBlockResponseSender{Deliver_DeliverFilteredServer: srv}
+		err := sender.PrepareRequest(signedDataWithFilter(&peer.ChaincodeEventFilter{ChaincodeId: "other.*"}))
+		assert.NoError(t, err)
+		err = sender.SendBlockResponse(block, "testChainID", nil, nil)
+		assert.NoError(t, err)
+		assert.Len(t, response.GetFilteredBlock().FilteredTransactions, 1)
+		assert.Equal(t, "tx2", response.GetFilteredBlock().FilteredTransactions[0].Txid)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		sender := &filteredBlockResponseSender{}
+		err := sender.PrepareRequest(signedDataWithFilter(&peer.ChaincodeEventFilter{EventName: "("}))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid chaincode event filter: invalid event name pattern")
+	})
+}
+
+func TestDeliverFilteredInvalidFilter(t *testing.T) {
+	viper.Set("peer.authentication.timewindow", "1s")
+	block, err := createTestBlock(nil)
+	assert.NoError(t, err)
+
+	iter := &mockIterator{}
+	iter.On("Next").Return(block, common.Status_SUCCESS)
+	reader := &mockReader{}
+	reader.On("Iterator", mock.Anything).Return(iter, uint64(0))
+	reader.On("Height").Return(uint64(2))
+	chain := &mockChainSupport{}
+	chain.On("Sequence").Return(uint64(0))
+	chain.On("Reader").Return(reader)
+	chainManager := &mockChainManager{}
+	chainManager.On("GetChain", "testChainID").Return(chain, true)
+
+	seekInfo := &orderer.SeekInfo{
+		Start:    &orderer.SeekPosition{Type: &orderer.SeekPosition_Oldest{Oldest: &orderer.SeekOldest{}}},
+		Stop:     &orderer.SeekPosition{Type: &orderer.SeekPosition_Newest{Newest: &orderer.SeekNewest{}}},
+		Behavior: orderer.SeekInfo_BLOCK_UNTIL_READY,
+	}
+	env, err := utils.CreateSignedEnvelopeWithExtension(common.HeaderType_DELIVER_SEEK_INFO, "testChainID", nil, seekInfo, 0, 0, nil, &peer.ChaincodeEventFilter{ChaincodeId: "("})
+	assert.NoError(t, err)
+
+	srv := &mockDeliverServer{}
+	srv.On("Context").Return(peer2.NewContext(context.TODO(), &peer2.Peer{}))
+	srv.On("Recv").Return(env, nil).Once()
+	srv.On("Recv").Return(nil, io.EOF)
+	var responses []*peer.DeliverResponse
+	srv.On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		responses = append(responses, args.Get(0).(*peer.DeliverResponse))
+	}).Return(nil)
+
+	server := NewDeliverEventsServer(false, defaultPolicyCheckerProvider, chainManager, nil)
+	err = server.DeliverFiltered(srv)
+	assert.NoError(t, err)
+	// the request is rejected once, before any block is delivered
+	assert.Len(t, responses, 1)
+	assert.Equal(t, common.Status_BAD_REQUEST, responses[0].GetStatus())
+	reader.AssertNotCalled(t, "Iterator", mock.Anything)
+}
+
+func TestDeliverDrained(t *testing.T) {
+	viper.Set("peer.authentication.timewindow", "1s")
+	reader := &mockReader{}
+	chain := &mockChainSupport{}
+	chain.On("Sequence").Return(uint64(0))
+	chain.On("Reader").Return(reader)
+	chainManager := &mockChainManager{}
+	chainManager.On("GetChain", "testChainID").Return(chain, true)
+
+	seekInfo := &orderer.SeekInfo{
+		Start:    &orderer.SeekPosition{Type: &orderer.SeekPosition_Oldest{Oldest: &orderer.SeekOldest{}}},
+		Stop:     &orderer.SeekPosition{Type: &orderer.SeekPosition_Newest{Newest: &orderer.SeekNewest{}}},
+		Behavior: orderer.SeekInfo_BLOCK_UNTIL_READY,
+	}
+	env, err := utils.CreateSignedEnvelope(common.HeaderType_DELIVER_SEEK_INFO, "testChainID", nil, seekInfo, 0, 0)
+	assert.NoError(t, err)
+
+	srv := &mockDeliverServer{}
+	srv.On("Context").Return(peer2.NewContext(context.TODO(), &peer2.Peer{}))
+	srv.On("Recv").Return(env, nil).Once()
+	srv.On("Recv").Return(nil, io.EOF)
+	var responses []*peer.DeliverResponse
+	srv.On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		responses = append(responses, args.Get(0).(*peer.DeliverResponse))
+	}).Return(nil)
+
+	server := NewDeliverEventsServer(false, defaultPolicyCheckerProvider, chainManager, nil)
+	server.(Drainer).Drain()
+	server.(Drainer).Drain()
+	err = server.Deliver(srv)
+	assert.NoError(t, err)
+	// the client is told to retry against another peer
+	assert.Len(t, responses, 1)
+	assert.Equal(t, common.Status_SERVICE_UNAVAILABLE, responses[0].GetStatus())
+	reader.AssertNotCalled(t, "Iterator", mock.Anything)
+}
+
+func TestDeliverResponseCheckpoint(t *testing.T) {
+	srv := &mockDeliverServer{}
+	var response *peer.DeliverResponse
+	srv.On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		response = args.Get(0).(*peer.DeliverResponse)
+	}).Return(nil)
+
+	block, err := createTestBlock(nil)
+	assert.NoError(t, err)
+	block.Header.Number = 5
+
+	sender := &blockResponseSender{Deliver_DeliverServer: srv}
+	err = sender.SendBlockResponse(block, "testChainID", nil, nil)
+	assert.NoError(t, err)
+
+	checkpoint := &orderer.DeliverCheckpoint{}
+	err = proto.Unmarshal(response.Checkpoint, checkpoint)
+	assert.NoError(t, err)
+	assert.Equal(t, "testChainID", checkpoint.ChannelId)
+	assert.Equal(t, uint64(6), checkpoint.BlockNumber)
+
+	filteredSender := &filteredBlockResponseSender{Deliver_DeliverFilteredServer: srv}
+	err = filteredSender.SendBlockResponse(block, "testChainID", nil, nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, response.GetFilteredBlock())
+	assert.Equal(t, deliver.CheckpointAfter("testChainID", 5), response.Checkpoint)
+}
+
+func TestBlockSlimming(t *testing.T) {
+	endorserTx := func(txID string) *common.Envelope {
+		chaincodeAction := &peer.ChaincodeAction{
+			Results: []byte("rwset"),
+			Events:  utils.MarshalOrPanic(&peer.ChaincodeEvent{ChaincodeId: "mycc", EventName: "transfer", TxId: txID}),
+		}
+		chaincodeActionPayload := &peer.ChaincodeActionPayload{
+			Action: &peer.ChaincodeEndorsedAction{
+				ProposalResponsePayload: utils.MarshalOrPanic(&peer.ProposalResponsePayload{Extension: utils.MarshalOrPanic(chaincodeAction)}),
+				Endorsements:            []*peer.Endorsement{{Endorser: []byte("peer0"), Signature: []byte("endorsement")}},
+			},
+		}
+		tx := &peer.Transaction{Actions: []*peer.TransactionAction{{
+			Header:  utils.MarshalOrPanic(&common.SignatureHeader{Creator: []byte("alice"), Nonce: []byte("nonce")}),
+			Payload: utils.MarshalOrPanic(chaincodeActionPayload),
+		}}}
+		chdr := utils.MakeChannelHeader(common.HeaderType_ENDORSER_TRANSACTION, 0, "testChainID", 0)
+		chdr.TxId = txID
+		payload := &common.Payload{
+			Header: utils.MakePayloadHeader(chdr, &common.SignatureHeader{Creator: []byte("alice"), Nonce: []byte("nonce")}),
+			Data:   utils.MarshalOrPanic(tx),
+		}
+		return &common.Envelope{Payload: utils.MarshalOrPanic(payload), Signature: []byte("signature")}
+	}
+	configPayload := &common.Payload{
+		Header: utils.MakePayloadHeader(utils.MakeChannelHeader(common.HeaderType_CONFIG, 0, "testChainID", 0), &common.SignatureHeader{Creator: []byte("orderer")}),
+		Data:   utils.MarshalOrPanic(&common.ConfigEnvelope{}),
+	}
+	configTx := &common.Envelope{Payload: utils.MarshalOrPanic(configPayload), Signature: []byte("signature")}
+	block, err := createTestBlock([]*common.Envelope{endorserTx("tx0"), configTx})
+	assert.NoError(t, err)
+	block.Data.Data = append(block.Data.Data, []byte("garbage"))
+	original := proto.Clone(block).(*common.Block)
+
+	// parts returns the creators, signatures, endorsements, rwset and events of the endorser transaction
+	type parts struct {
+		creator, actionCreator, signature []byte
+		endorsements                      int
+		results, events                   []byte
+	}
+	partsOf := func(envBytes []byte) parts {
+		env, err := utils.GetEnvelopeFromBlock(envBytes)
+		assert.NoError(t, err)
+		payload, err := utils.UnmarshalPayload(env.Payload)
+		assert.NoError(t, err)
+		shdr, err := utils.GetSignatureHeader(payload.Header.SignatureHeader)
+		assert.NoError(t, err)
+		tx, err := utils.GetTransaction(payload.Data)
+		assert.NoError(t, err)
+		actionShdr, err := utils.GetSignatureHeader(tx.Actions[0].Header)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("nonce"), actionShdr.Nonce)
+		chaincodeActionPayload, chaincodeAction, err := utils.GetPayloads(tx.Actions[0])
+		assert.NoError(t, err)
+		return parts{
+			creator:       shdr.Creator,
+			actionCreator: actionShdr.Creator,
+			signature:     env.Signature,
+			endorsements:  len(chaincodeActionPayload.Action.Endorsements),
+			results:       chaincodeAction.Results,
+			events:        chaincodeAction.Events,
+		}
+	}
+	full := partsOf(block.Data.Data[0])
+
+	tests := []struct {
+		name     string
+		slimming *peer.BlockSlimming
+		expected parts
+	}{
+		{name: "nothing", slimming: &peer.BlockSlimming{}, expected: full},
+		{name: "signatures", slimming: &peer.BlockSlimming{StripSignatures: true},
+			expected: parts{creator: full.creator, actionCreator: full.actionCreator, results: full.results, events: full.events}},
+		{name: "creators", slimming: &peer.BlockSlimming{StripCreators: true},
+			expected: parts{signature: full.signature, endorsements: 1, results: full.results, events: full.events}},
+		{name: "rwsets", slimming: &peer.BlockSlimming{StripSignatures: true, StripCreators: true, StripRwsets: true},
+			expected: parts{events: full.events}},
+		{name: "events", slimming: &peer.BlockSlimming{StripSignatures: true, StripCreators: true, StripEvents: true},
+			expected: parts{results: full.results}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			slim := slimBlock(block, test.slimming)
+			assert.Equal(t, test.expected, partsOf(slim.Data.Data[0]))
+			// only the endorser transactions are slimmed
+			assert.Equal(t, block.Data.Data[1:], slim.Data.Data[1:])
+			assert.Equal(t, block.Header, slim.Header)
+			assert.True(t, proto.Equal(original, block), "the block must be left untouched")
+		})
+	}
+
+	signedDataWithSlimming := func(slimming proto.Message) *common.SignedData {
+		env, err := utils.CreateSignedEnvelopeWithExtension(common.HeaderType_DELIVER_SEEK_INFO, "testChainID", nil, &orderer.SeekInfo{}, 0, 0, nil, slimming)
+		assert.NoError(t, err)
+		return &common.SignedData{Data: env.Payload}
+	}
+
+	t.Run("prepared slimming", func(t *testing.T) {
+		srv := &mockDeliverServer{}
+		var response *peer.DeliverResponse
+		srv.On("Send", mock.Anything).Run(func(args mock.Arguments) {
+			response = args.Get(0).(*peer.DeliverResponse)
+		}).Return(nil)
+		sender := &blockResponseSender{Deliver_DeliverServer: srv}
+
+		err := sender.PrepareRequest(signedDataWithSlimming(nil))
+		assert.NoError(t, err)
+		err = sender.SendBlockResponse(block, "testChainID", nil, nil)
+		assert.NoError(t, err)
+		assert.Exactly(t, block, response.GetBlock())
+
+		err = sender.PrepareRequest(signedDataWithSlimming(&peer.BlockSlimming{StripRwsets: true}))
+		assert.NoError(t, err)
+		err = sender.SendBlockResponse(block, "testChainID", nil, nil)
+		assert.NoError(t, err)
+		assert.Nil(t, partsOf(response.GetBlock().Data.Data[0]).results)
+		assert.Equal(t, deliver.CheckpointAfter("testChainID", 0), response.Checkpoint)
+	})
+
+	t.Run("invalid slimming", func(t *testing.T) {
+		sender := &blockResponseSender{}
+		err := sender.PrepareRequest(&common.SignedData{Data: utils.MarshalOrPanic(&common.Payload{
+			Header: &common.Header{ChannelHeader: utils.MarshalOrPanic(&common.ChannelHeader{Extension: []byte("garbage")})},
+		})})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid block slimming: error unmarshaling block slimming")
+	})
+}
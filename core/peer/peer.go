@@ -7,12 +7,16 @@ SPDX-License-Identifier: Apache-2.0
 package peer
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"net"
 	"runtime"
 	"sync"
+	"time"
 
 	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/checkpoint"
 	cc "github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/configtx"
 	"github.com/hyperledger/fabric/common/deliver"
@@ -21,6 +25,7 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	fileledger "github.com/hyperledger/fabric/common/ledger/blockledger/file"
 	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/chaincode/platforms"
 	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/core/committer"
@@ -30,7 +35,9 @@ import (
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/customtx"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/hyperledger/fabric/core/scc/crlscc"
 	"github.com/hyperledger/fabric/core/transientstore"
 	"github.com/hyperledger/fabric/gossip/api"
 	"github.com/hyperledger/fabric/gossip/service"
@@ -116,26 +123,32 @@ func (cs *chainSupport) Apply(configtx *common.ConfigEnvelope) error {
 			return err
 		}
 
-		capabilitiesSupportedOrPanic(bundle)
+		if err := capabilitiesSupported(bundle); err != nil {
+			return err
+		}
 
 		cs.bundleSource.Update(bundle)
 	}
 	return nil
 }
 
-func capabilitiesSupportedOrPanic(res channelconfig.Resources) {
+// capabilitiesSupported returns an error if the peer can't process the channel
+// of the given config, the cause of which is a *capabilities.UnsupportedError if
+// the channel requires a capability the peer doesn't support
+func capabilitiesSupported(res channelconfig.Resources) error {
 	ac, ok := res.ApplicationConfig()
 	if !ok {
-		peerLogger.Panicf("[channel %s] does not have application config so is incompatible", res.ConfigtxValidator().ChainID())
+		return errors.Errorf("[channel %s] does not have application config so is incompatible", res.ConfigtxValidator().ChainID())
 	}
 
 	if err := ac.Capabilities().Supported(); err != nil {
-		peerLogger.Panicf("[channel %s] incompatible: %s", res.ConfigtxValidator().ChainID(), err)
+		return errors.WithMessage(err, fmt.Sprintf("[channel %s] incompatible", res.ConfigtxValidator().ChainID()))
 	}
 
 	if err := res.ChannelConfig().Capabilities().Supported(); err != nil {
-		peerLogger.Panicf("[channel %s] incompatible: %s", res.ConfigtxValidator().ChainID(), err)
+		return errors.WithMessage(err, fmt.Sprintf("[channel %s] incompatible", res.ConfigtxValidator().ChainID()))
 	}
+	return nil
 }
 
 func (cs *chainSupport) Ledger() ledger.PeerLedger {
@@ -157,6 +170,16 @@ func (cs *chainSupport) Reader() blockledger.Reader {
 	return fileledger.NewFileLedger(fileLedgerBlockStore{cs.ledger})
 }
 
+// GetPvtDataByNum returns the private data of the given block number
+func (cs *chainSupport) GetPvtDataByNum(blockNum uint64, filter ledger.PvtNsCollFilter) ([]*ledger.TxPvtData, error) {
+	return cs.ledger.GetPvtDataByNum(blockNum, filter)
+}
+
+// GetConfigHistoryRetriever returns the ConfigHistoryRetriever of the underlying ledger
+func (cs *chainSupport) GetConfigHistoryRetriever() (ledger.ConfigHistoryRetriever, error) {
+	return cs.ledger.GetConfigHistoryRetriever()
+}
+
 // Errored returns a channel that can be used to determine
 // if a backing resource has errored. At this point in time,
 // the peer does not have any error conditions that lead to
@@ -194,7 +217,8 @@ var validationWorkersSemaphore *semaphore.Weighted
 
 // Initialize sets up any chains that the peer has from the persistence. This
 // function should be called at the start up when the ledger and gossip
-// ready
+// ready. The chains are started concurrently, up to peer.channelStartupPoolSize
+// at a time, and their listeners are notified as each of them becomes ready
 func Initialize(init func(string), ccp ccprovider.ChaincodeProvider, sccp sysccprovider.SystemChaincodeProvider, pm txvalidator.PluginMapper, pr *platforms.Registry, deployedCCInfoProvider ledger.DeployedChaincodeInfoProvider) {
 	nWorkers := viper.GetInt("peer.validatorPoolSize")
 	if nWorkers <= 0 {
@@ -205,8 +229,6 @@ func Initialize(init func(string), ccp ccprovider.ChaincodeProvider, sccp sysccp
 	pluginMapper = pm
 	chainInitializer = init
 
-	var cb *common.Block
-	var ledger ledger.PeerLedger
 	ledgermgmt.Initialize(&ledgermgmt.Initializer{
 		CustomTxProcessors:            ConfigTxProcessors,
 		PlatformRegistry:              pr,
@@ -216,27 +238,102 @@ func Initialize(init func(string), ccp ccprovider.ChaincodeProvider, sccp sysccp
 	if err != nil {
 		panic(fmt.Errorf("Error in initializing ledgermgmt: %s", err))
 	}
-	for _, cid := range ledgerIds {
-		peerLogger.Infof("Loading chain %s", cid)
-		if ledger, err = ledgermgmt.OpenLedger(cid); err != nil {
-			peerLogger.Warningf("Failed to load ledger %s(%s)", cid, err)
-			peerLogger.Debugf("Error while loading ledger %s with message %s. We continue to the next ledger rather than abort.", cid, err)
-			continue
-		}
-		if cb, err = getCurrConfigBlockFromLedger(ledger); err != nil {
-			peerLogger.Warningf("Failed to find config block on ledger %s(%s)", cid, err)
-			peerLogger.Debugf("Error while looking for config block on ledger %s with message %s. We continue to the next ledger rather than abort.", cid, err)
-			continue
-		}
-		// Create a chain if we get a valid ledger with config block
-		if err = createChain(cid, ledger, cb, ccp, sccp, pm); err != nil {
-			peerLogger.Warningf("Failed to load chain %s(%s)", cid, err)
-			peerLogger.Debugf("Error reloading chain %s with message %s. We continue to the next chain rather than abort.", cid, err)
-			continue
-		}
 
-		InitChain(cid)
+	nStartupWorkers := viper.GetInt("peer.channelStartupPoolSize")
+	if nStartupWorkers <= 0 {
+		nStartupWorkers = runtime.NumCPU()
 	}
+	startChains(ledgerIds, nStartupWorkers, func(cid string) error {
+		return startChain(cid, ccp, sccp, pm)
+	})
+}
+
+// startChain recovers the ledger of the chain and creates the chain from its
+// current config block
+func startChain(cid string, ccp ccprovider.ChaincodeProvider, sccp sysccprovider.SystemChaincodeProvider, pm txvalidator.PluginMapper) error {
+	peerLogger.Infof("Loading chain %s", cid)
+	ledger, err := ledgermgmt.OpenLedger(cid)
+	if err != nil {
+		peerLogger.Warningf("Failed to load ledger %s(%s)", cid, err)
+		peerLogger.Debugf("Error while loading ledger %s with message %s. We continue to the next ledger rather than abort.", cid, err)
+		return err
+	}
+	cb, err := getCurrConfigBlockFromLedger(ledger)
+	if err != nil {
+		peerLogger.Warningf("Failed to find config block on ledger %s(%s)", cid, err)
+		peerLogger.Debugf("Error while looking for config block on ledger %s with message %s. We continue to the next ledger rather than abort.", cid, err)
+		return err
+	}
+	// Create a chain if we get a valid ledger with config block
+	if err = createChain(cid, ledger, cb, ccp, sccp, pm); err != nil {
+		peerLogger.Warningf("Failed to load chain %s(%s)", cid, err)
+		peerLogger.Debugf("Error reloading chain %s with message %s. We continue to the next chain rather than abort.", cid, err)
+		return err
+	}
+
+	InitChain(cid)
+	return nil
+}
+
+// ChannelReadiness is the outcome of the startup of a channel at peer boot
+type ChannelReadiness struct {
+	ChannelID string
+	// Err is the reason the channel failed to start, nil if it is ready
+	Err error
+	// Duration is the time taken to start the channel
+	Duration time.Duration
+}
+
+var readinessListeners struct {
+	sync.Mutex
+	listeners []func(ChannelReadiness)
+}
+
+// AddChannelReadinessListener registers a listener notified of the startup of
+// each channel at peer boot. The listener is called concurrently for distinct
+// channels, as soon as each of them is started
+func AddChannelReadinessListener(listener func(ChannelReadiness)) {
+	readinessListeners.Lock()
+	defer readinessListeners.Unlock()
+	readinessListeners.listeners = append(readinessListeners.listeners, listener)
+}
+
+func notifyChannelReadiness(readiness ChannelReadiness) {
+	readinessListeners.Lock()
+	listeners := readinessListeners.listeners
+	readinessListeners.Unlock()
+	for _, listener := range listeners {
+		listener(readiness)
+	}
+}
+
+// startChains starts the chains concurrently, with at most nWorkers of them
+// being started at a time, and returns once all of them are started or
+// failed to start
+func startChains(cids []string, nWorkers int, start func(cid string) error) {
+	pending := make(chan string, len(cids))
+	for _, cid := range cids {
+		pending <- cid
+	}
+	close(pending)
+
+	var wg sync.WaitGroup
+	for i := 0; i < nWorkers && i < len(cids); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for cid := range pending {
+				startTime := time.Now()
+				err := start(cid)
+				readiness := ChannelReadiness{ChannelID: cid, Err: err, Duration: time.Since(startTime)}
+				if err == nil {
+					peerLogger.Infof("Channel [%s] is ready, started in %s", cid, readiness.Duration)
+				}
+				notifyChannelReadiness(readiness)
+			}
+		}()
+	}
+	wg.Wait()
 }
 
 // InitChain takes care to initialize chain after peer joined, for example deploys system CCs
@@ -305,7 +402,9 @@ func createChain(cid string, ledger ledger.PeerLedger, cb *common.Block, ccp ccp
 		}
 	}
 
-	capabilitiesSupportedOrPanic(bundle)
+	if err := capabilitiesSupported(bundle); err != nil {
+		return err
+	}
 
 	channelconfig.LogSanityChecks(bundle)
 
@@ -335,6 +434,23 @@ func createChain(cid string, ledger ledger.PeerLedger, cb *common.Block, ccp ccp
 		updateTrustedRoots(bundle)
 	}
 
+	crlCallback := func(bundle *channelconfig.Bundle) {
+		// the MSPs of the bundle only hold the CRLs of the config, the
+		// ones published through crlscc are restored from the state
+		if !bundle.ChannelConfig().Capabilities().PublishedCRLs() {
+			return
+		}
+		qe, err := ledger.NewQueryExecutor()
+		if err != nil {
+			peerLogger.Errorf("[channel %s] Failed applying the published CRLs: %s", cid, err)
+			return
+		}
+		defer qe.Done()
+		if err := crlscc.ApplyRevocationLists(qe, bundle.MSPManager()); err != nil {
+			peerLogger.Errorf("[channel %s] Failed applying the published CRLs: %s", cid, err)
+		}
+	}
+
 	mspCallback := func(bundle *channelconfig.Bundle) {
 		// TODO remove once all references to mspmgmt are gone from peer code
 		mspmgmt.XXXSetMSPManager(cid, bundle.MSPManager())
@@ -361,6 +477,7 @@ func createChain(cid string, ledger ledger.PeerLedger, cb *common.Block, ccp ccp
 
 	cs.bundleSource = channelconfig.NewBundleSource(
 		bundle,
+		crlCallback,
 		gossipCallbackWrapper,
 		trustedRootsCallbackWrapper,
 		mspCallback,
@@ -371,7 +488,11 @@ func createChain(cid string, ledger ledger.PeerLedger, cb *common.Block, ccp ccp
 		*chainSupport
 		*semaphore.Weighted
 	}{cs, validationWorkersSemaphore}
-	validator := txvalidator.NewTxValidator(cid, vcs, sccp, pm)
+	var validator txvalidator.Validator = txvalidator.NewTxValidator(cid, vcs, sccp, pm)
+	if ledgerconfig.IsReadReplica() {
+		// the blocks of a read replica are validated by its committer
+		validator = txvalidator.NewReplicaValidator(cid, vcs)
+	}
 	c := committer.NewLedgerCommitterReactive(ledger, func(block *common.Block) error {
 		chainID, err := utils.GetChainIDFromBlock(block)
 		if err != nil {
@@ -429,6 +550,89 @@ func CreateChainFromBlock(cb *common.Block, ccp ccprovider.ChaincodeProvider, sc
 	return createChain(cid, l, cb, ccp, sccp, pluginMapper)
 }
 
+// CreateChainFromSnapshot creates a new chain from the snapshot of the state of
+// a channel read from the archive, and returns the ID of the channel
+func CreateChainFromSnapshot(snapshotArchive io.Reader, ccp ccprovider.ChaincodeProvider, sccp sysccprovider.SystemChaincodeProvider) (string, error) {
+	l, err := ledgermgmt.CreateLedgerFromSnapshot(snapshotArchive, verifySnapshot)
+	if err != nil {
+		return "", errors.WithMessage(err, "cannot create ledger from snapshot")
+	}
+	cb, err := getCurrConfigBlockFromLedger(l)
+	if err != nil {
+		return "", err
+	}
+	cid, err := utils.GetChainIDFromBlock(cb)
+	if err != nil {
+		return "", err
+	}
+	return cid, createChain(cid, l, cb, ccp, sccp, pluginMapper)
+}
+
+// verifySnapshot checks that the last block of a snapshot is signed by the
+// ordering service according to the block validation policy of the last config
+// block of the snapshot, which is trusted as the genesis block of JoinChain is,
+// and that the state checkpoint of the snapshot commits to its state and is
+// signed according to the channel policy ledger.snapshots.checkpointPolicy
+func verifySnapshot(lastBlock, configBlock *common.Block, stateCheckpoint *common.Metadata, stateHash []byte) error {
+	envelopeConfig, err := utils.ExtractEnvelope(configBlock, 0)
+	if err != nil {
+		return errors.WithMessage(err, "error extracting the config envelope of the config block")
+	}
+	bundle, err := channelconfig.NewBundleFromEnvelope(envelopeConfig)
+	if err != nil {
+		return errors.WithMessage(err, "error loading the config of the config block")
+	}
+	if err := verifySnapshotBlock(bundle, lastBlock); err != nil {
+		return err
+	}
+
+	policyName := ledgerconfig.GetSnapshotCheckpointPolicy()
+	policy, ok := bundle.PolicyManager().GetPolicy(policyName)
+	if !ok {
+		return errors.Errorf("no policy %s in the config of channel [%s]", policyName, bundle.ConfigtxValidator().ChainID())
+	}
+	_, err = checkpoint.VerifyState(lastBlock, stateCheckpoint, stateHash, policy)
+	return errors.WithMessage(err, "the state of the snapshot is not checkpointed")
+}
+
+// verifySnapshotBlock checks that the last block of a snapshot is signed by the
+// ordering service according to the block validation policy of the bundle
+func verifySnapshotBlock(bundle *channelconfig.Bundle, lastBlock *common.Block) error {
+	channelID := bundle.ConfigtxValidator().ChainID()
+	lastBlockChannelID, err := utils.GetChainIDFromBlock(lastBlock)
+	if err != nil {
+		return errors.WithMessage(err, "error reading the channel of the last block")
+	}
+	if lastBlockChannelID != channelID {
+		return errors.Errorf("the last block is of channel [%s], the config block of channel [%s]", lastBlockChannelID, channelID)
+	}
+	if !bytes.Equal(lastBlock.Data.Hash(), lastBlock.Header.DataHash) {
+		return errors.Errorf("the data hash of the header of block [%d] does not match its data", lastBlock.Header.Number)
+	}
+
+	policy, ok := bundle.PolicyManager().GetPolicy(policies.BlockValidation)
+	if !ok {
+		return errors.Errorf("no block validation policy in the config of channel [%s]", channelID)
+	}
+	metadata, err := utils.GetMetadataFromBlock(lastBlock, common.BlockMetadataIndex_SIGNATURES)
+	if err != nil {
+		return errors.WithMessage(err, "error reading the signatures of the last block")
+	}
+	var signatureSet []*common.SignedData
+	for _, metadataSignature := range metadata.Signatures {
+		shdr, err := utils.GetSignatureHeader(metadataSignature.SignatureHeader)
+		if err != nil {
+			return errors.WithMessage(err, "error reading a signature header of the last block")
+		}
+		signatureSet = append(signatureSet, &common.SignedData{
+			Identity:  shdr.Creator,
+			Data:      util.ConcatenateBytes(metadata.Value, metadataSignature.SignatureHeader, lastBlock.Header.Bytes()),
+			Signature: metadataSignature.Signature,
+		})
+	}
+	return errors.WithMessage(policy.Evaluate(signatureSet), "the last block is not signed by the ordering service")
+}
+
 // GetLedger returns the ledger of the chain with chain ID. Note that this
 // call returns nil if chain cid has not been created.
 func GetLedger(cid string) ledger.PeerLedger {
@@ -484,8 +688,15 @@ func GetCurrConfigBlock(cid string) *common.Block {
 	return nil
 }
 
+// trustedRootsLock serializes the updates of the trusted roots of the peer
+// server, so that those of the channels started concurrently are not lost
+var trustedRootsLock sync.Mutex
+
 // updates the trusted roots for the peer based on updates to channels
 func updateTrustedRoots(cm channelconfig.Resources) {
+	trustedRootsLock.Lock()
+	defer trustedRootsLock.Unlock()
+
 	// this is triggered on per channel basis so first update the roots for the channel
 	peerLogger.Debugf("Updating trusted root authorities for channel %s", cm.ConfigtxValidator().ChainID())
 	var serverConfig comm.ServerConfig
@@ -633,21 +844,27 @@ func SetCurrConfigBlock(block *common.Block, cid string) error {
 	return errors.Errorf("[channel %s] channel not associated with this peer", cid)
 }
 
-// GetLocalIP returns the non loopback local IP of the host
+// GetLocalIP returns the non loopback local IP of the host, preferring IPv4
 func GetLocalIP() string {
 	addrs, err := net.InterfaceAddrs()
 	if err != nil {
 		return ""
 	}
+	var ipv6 string
 	for _, address := range addrs {
 		// check the address type and if it is not a loopback then display it
 		if ipnet, ok := address.(*net.IPNet); ok && !ipnet.IP.IsLoopback() {
 			if ipnet.IP.To4() != nil {
 				return ipnet.IP.String()
 			}
+			// on IPv6-only hosts, fall back to the first global address, as
+			// the link-local ones can't be reached without their zone
+			if ipv6 == "" && ipnet.IP.IsGlobalUnicast() {
+				ipv6 = ipnet.IP.String()
+			}
 		}
 	}
-	return ""
+	return ipv6
 }
 
 // GetChannelsInfo returns an array with information about all channels for
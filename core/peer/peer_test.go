@@ -10,11 +10,20 @@ import (
 	"fmt"
 	"math/rand"
 	"net"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/capabilities"
+	"github.com/hyperledger/fabric/common/checkpoint"
 	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
 	"github.com/hyperledger/fabric/common/localmsp"
+	mockchannelconfig "github.com/hyperledger/fabric/common/mocks/config"
+	mockconfigtx "github.com/hyperledger/fabric/common/mocks/configtx"
 	mscc "github.com/hyperledger/fabric/common/mocks/scc"
+	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/chaincode/platforms"
 	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/core/committer/txvalidator"
@@ -29,6 +38,10 @@ import (
 	"github.com/hyperledger/fabric/msp/mgmt/testtools"
 	peergossip "github.com/hyperledger/fabric/peer/gossip"
 	"github.com/hyperledger/fabric/peer/gossip/mocks"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
@@ -217,3 +230,113 @@ func TestDeliverSupportManager(t *testing.T) {
 	assert.NotNil(t, chainSupport, "chain support should not be nil")
 	assert.True(t, ok, "Should find testchain channel")
 }
+
+func TestCapabilitiesSupported(t *testing.T) {
+	unsupported := &capabilities.UnsupportedError{Type: "Application", Capability: "V9_9"}
+	newResources := func(appErr, channelErr error) *mockchannelconfig.Resources {
+		return &mockchannelconfig.Resources{
+			ConfigtxValidatorVal: &mockconfigtx.Validator{ChainIDVal: "mychannel"},
+			ApplicationConfigVal: &mockchannelconfig.MockApplication{
+				CapabilitiesRv: &mockchannelconfig.MockApplicationCapabilities{SupportedRv: appErr},
+			},
+			ChannelConfigVal: &mockchannelconfig.Channel{
+				CapabilitiesVal: &mockchannelconfig.ChannelCapabilities{SupportedErr: channelErr},
+			},
+		}
+	}
+
+	assert.NoError(t, capabilitiesSupported(newResources(nil, nil)))
+
+	err := capabilitiesSupported(newResources(unsupported, nil))
+	assert.EqualError(t, err, "[channel mychannel] incompatible: Application capability V9_9 is required but not supported")
+	assert.Equal(t, unsupported, errors.Cause(err))
+
+	err = capabilitiesSupported(newResources(nil, unsupported))
+	assert.Equal(t, unsupported, errors.Cause(err))
+
+	err = capabilitiesSupported(&mockchannelconfig.Resources{ConfigtxValidatorVal: &mockconfigtx.Validator{ChainIDVal: "mychannel"}})
+	assert.EqualError(t, err, "[channel mychannel] does not have application config so is incompatible")
+}
+
+func TestStartChains(t *testing.T) {
+	var readiness []ChannelReadiness
+	var lock sync.Mutex
+	AddChannelReadinessListener(func(r ChannelReadiness) {
+		lock.Lock()
+		defer lock.Unlock()
+		readiness = append(readiness, r)
+	})
+
+	var running, maxRunning int32
+	cids := []string{"ch1", "ch2", "ch3", "ch4", "ch5"}
+	startChains(cids, 2, func(cid string) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		if cid == "ch3" {
+			return errors.New("no config block")
+		}
+		return nil
+	})
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&maxRunning))
+	assert.Len(t, readiness, len(cids))
+	for _, r := range readiness {
+		if r.ChannelID == "ch3" {
+			assert.EqualError(t, r.Err, "no config block")
+		} else {
+			assert.NoError(t, r.Err)
+		}
+	}
+}
+
+func TestVerifySnapshot(t *testing.T) {
+	msptesttools.LoadMSPSetupForTesting()
+	configBlock, err := configtxtest.MakeGenesisBlock("mychannel")
+	require.NoError(t, err)
+	otherConfigBlock, err := configtxtest.MakeGenesisBlock("otherchannel")
+	require.NoError(t, err)
+
+	// the snapshot taken at height 1 has the genesis block as last block
+	lastBlock := proto.Clone(configBlock).(*common.Block)
+	stateHash := []byte("state hash")
+	signer := localmsp.NewSigner()
+	stateCheckpoint, err := checkpoint.SignState(lastBlock, stateHash, signer)
+	require.NoError(t, err)
+	err = verifySnapshot(lastBlock, configBlock, stateCheckpoint, stateHash)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the last block is not signed by the ordering service")
+
+	shdr, err := signer.NewSignatureHeader()
+	require.NoError(t, err)
+	shdrBytes := utils.MarshalOrPanic(shdr)
+	signature, err := signer.Sign(util.ConcatenateBytes(nil, shdrBytes, lastBlock.Header.Bytes()))
+	require.NoError(t, err)
+	lastBlock.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(&common.Metadata{
+		Signatures: []*common.MetadataSignature{{SignatureHeader: shdrBytes, Signature: signature}},
+	})
+	assert.NoError(t, verifySnapshot(lastBlock, configBlock, stateCheckpoint, stateHash))
+
+	// the state must be checkpointed
+	err = verifySnapshot(lastBlock, configBlock, nil, stateHash)
+	assert.EqualError(t, err, "the state of the snapshot is not checkpointed: missing state checkpoint of block 0")
+	err = verifySnapshot(lastBlock, configBlock, stateCheckpoint, []byte("other state"))
+	assert.EqualError(t, err, "the state of the snapshot is not checkpointed: state checkpoint of block 0 does not match the state hash")
+	viper.Set("ledger.snapshots.checkpointPolicy", "/Channel/Application/Missing")
+	err = verifySnapshot(lastBlock, configBlock, stateCheckpoint, stateHash)
+	viper.Set("ledger.snapshots.checkpointPolicy", "")
+	assert.EqualError(t, err, "no policy /Channel/Application/Missing in the config of channel [mychannel]")
+
+	err = verifySnapshot(lastBlock, otherConfigBlock, stateCheckpoint, stateHash)
+	assert.EqualError(t, err, "the last block is of channel [mychannel], the config block of channel [otherchannel]")
+
+	lastBlock.Data.Data = append(lastBlock.Data.Data, []byte("tampered"))
+	err = verifySnapshot(lastBlock, configBlock, stateCheckpoint, stateHash)
+	assert.EqualError(t, err, "the data hash of the header of block [0] does not match its data")
+}
@@ -0,0 +1,275 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package quota bounds the resources of the peer used on behalf of each
+// channel, so that a busy channel of a peer joined to many channels can't
+// starve the other channels.
+package quota
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+var logger = flogging.MustGetLogger("quota")
+
+const defaultStateDBSizeCheckInterval = 5 * time.Minute
+
+// Limits are the quotas of a channel, a zero value meaning no quota
+type Limits struct {
+	// MaxChaincodeContainers is the maximum number of chaincode containers
+	// launched by the transactions of the channel and still running
+	MaxChaincodeContainers int
+	// MaxDeliverStreams is the maximum number of deliver requests of the
+	// channel served concurrently
+	MaxDeliverStreams int
+	// MaxConcurrentProposals is the maximum number of proposals of the
+	// channel processed concurrently by the endorser
+	MaxConcurrentProposals int
+	// StateDBSizeAlert is the size in bytes of the state database of the
+	// channel above which an alert is raised
+	StateDBSizeAlert int64
+}
+
+// Config is the configuration of the quotas of the channels
+type Config struct {
+	// Default are the quotas of all the channels
+	Default Limits
+	// Channels are the quotas of specific channels, whose non zero values
+	// override the default quotas
+	Channels map[string]Limits
+	// StateDBSizeCheckInterval is the interval between two checks of the
+	// sizes of the state databases
+	StateDBSizeCheckInterval time.Duration
+}
+
+// GlobalConfig returns the quotas configured in the peer.quotas section
+func GlobalConfig() Config {
+	c := Config{
+		Default:                  limitsOf("peer.quotas.default"),
+		Channels:                 map[string]Limits{},
+		StateDBSizeCheckInterval: viper.GetDuration("peer.quotas.stateDBSizeCheckInterval"),
+	}
+	for channelID := range viper.GetStringMap("peer.quotas.channels") {
+		c.Channels[channelID] = limitsOf("peer.quotas.channels." + channelID)
+	}
+	if c.StateDBSizeCheckInterval <= 0 {
+		c.StateDBSizeCheckInterval = defaultStateDBSizeCheckInterval
+	}
+	return c
+}
+
+func limitsOf(key string) Limits {
+	return Limits{
+		MaxChaincodeContainers: viper.GetInt(key + ".maxChaincodeContainers"),
+		MaxDeliverStreams:      viper.GetInt(key + ".maxDeliverStreams"),
+		MaxConcurrentProposals: viper.GetInt(key + ".maxConcurrentProposals"),
+		StateDBSizeAlert:       int64(viper.GetSizeInBytes(key + ".stateDBSizeAlert")),
+	}
+}
+
+// Limits returns the quotas of the given channel
+func (c Config) Limits(channelID string) Limits {
+	limits := c.Default
+	override, ok := c.Channels[channelID]
+	if !ok {
+		return limits
+	}
+	if override.MaxChaincodeContainers != 0 {
+		limits.MaxChaincodeContainers = override.MaxChaincodeContainers
+	}
+	if override.MaxDeliverStreams != 0 {
+		limits.MaxDeliverStreams = override.MaxDeliverStreams
+	}
+	if override.MaxConcurrentProposals != 0 {
+		limits.MaxConcurrentProposals = override.MaxConcurrentProposals
+	}
+	if override.StateDBSizeAlert != 0 {
+		limits.StateDBSizeAlert = override.StateDBSizeAlert
+	}
+	return limits
+}
+
+// Manager enforces the quotas of the channels. A chaincode container serves
+// all the channels of the chaincode, and it is accounted to the channel whose
+// transaction launched it.
+type Manager struct {
+	config Config
+	scope  metrics.Scope
+
+	mutex          sync.Mutex
+	proposals      map[string]int
+	deliverStreams map[string]int
+	// containers holds the channel each running container is accounted to
+	containers      map[string]string
+	containerCounts map[string]int
+	// oversized holds the channels whose state database exceeds its alert size
+	oversized map[string]bool
+}
+
+// NewManager returns a manager of the supplied quotas
+func NewManager(config Config, scope metrics.Scope) *Manager {
+	if scope == nil {
+		scope = metrics.NewNoOpScope()
+	}
+	return &Manager{
+		config:          config,
+		scope:           scope,
+		proposals:       map[string]int{},
+		deliverStreams:  map[string]int{},
+		containers:      map[string]string{},
+		containerCounts: map[string]int{},
+		oversized:       map[string]bool{},
+	}
+}
+
+// AcquireProposal reserves a slot for a proposal of the channel, which is
+// freed by calling the returned function, or returns an error if the channel
+// has reached its quota of concurrent proposals
+func (m *Manager) AcquireProposal(channelID string) (func(), error) {
+	limit := m.config.Limits(channelID).MaxConcurrentProposals
+	if !m.acquire(m.proposals, channelID, limit) {
+		m.reject(channelID, "proposals")
+		return nil, errors.Errorf("channel %s has reached its quota of %d concurrent proposals", channelID, limit)
+	}
+	return func() { m.release(m.proposals, channelID) }, nil
+}
+
+// AcquireDeliverStream reserves a slot for a deliver request of the channel,
+// which is freed by calling the returned function, or returns an error if the
+// channel has reached its quota of deliver streams
+func (m *Manager) AcquireDeliverStream(channelID string) (func(), error) {
+	limit := m.config.Limits(channelID).MaxDeliverStreams
+	if !m.acquire(m.deliverStreams, channelID, limit) {
+		m.reject(channelID, "deliver_streams")
+		return nil, errors.Errorf("channel %s has reached its quota of %d deliver streams", channelID, limit)
+	}
+	return func() { m.release(m.deliverStreams, channelID) }, nil
+}
+
+// AcquireContainer accounts the container of the given chaincode to the
+// channel launching it, or returns an error if the channel has reached its
+// quota of chaincode containers. A container which is already accounted to a
+// channel is not accounted again.
+func (m *Manager) AcquireContainer(channelID, cname string) error {
+	limit := m.config.Limits(channelID).MaxChaincodeContainers
+
+	m.mutex.Lock()
+	if _, ok := m.containers[cname]; ok {
+		m.mutex.Unlock()
+		return nil
+	}
+	if limit > 0 && m.containerCounts[channelID] >= limit {
+		m.mutex.Unlock()
+		m.reject(channelID, "chaincode_containers")
+		return errors.Errorf("channel %s has reached its quota of %d chaincode containers", channelID, limit)
+	}
+	m.containers[cname] = channelID
+	m.containerCounts[channelID]++
+	m.mutex.Unlock()
+	return nil
+}
+
+// ReleaseContainer frees the slot of the container of the given chaincode,
+// once it failed to launch or stopped
+func (m *Manager) ReleaseContainer(cname string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	channelID, ok := m.containers[cname]
+	if !ok {
+		return
+	}
+	delete(m.containers, cname)
+	decrement(m.containerCounts, channelID)
+}
+
+func (m *Manager) acquire(counts map[string]int, channelID string, limit int) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if limit > 0 && counts[channelID] >= limit {
+		return false
+	}
+	counts[channelID]++
+	return true
+}
+
+func (m *Manager) release(counts map[string]int, channelID string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	decrement(counts, channelID)
+}
+
+func decrement(counts map[string]int, channelID string) {
+	if counts[channelID]--; counts[channelID] <= 0 {
+		delete(counts, channelID)
+	}
+}
+
+func (m *Manager) reject(channelID, quota string) {
+	m.scope.Tagged(map[string]string{"channel": channelID, "quota": quota}).Counter("rejections").Inc(1)
+}
+
+// CheckStateDBSizes checks the sizes of the state databases of the given
+// ledgers, and raises an alert for the channels whose state database grew
+// beyond their alert size
+func (m *Manager) CheckStateDBSizes(ledgers map[string]ledger.PeerLedger) {
+	for channelID, l := range ledgers {
+		size, err := stateDBSize(l)
+		if err != nil {
+			logger.Debugf("[channel: %s] Not checking the size of the state database: %s", channelID, err)
+			continue
+		}
+		m.scope.Tagged(map[string]string{"channel": channelID}).Gauge("state_db_size").Update(float64(size))
+
+		alertSize := m.config.Limits(channelID).StateDBSizeAlert
+		oversized := alertSize > 0 && size > alertSize
+		m.mutex.Lock()
+		wasOversized := m.oversized[channelID]
+		if oversized {
+			m.oversized[channelID] = true
+		} else {
+			delete(m.oversized, channelID)
+		}
+		m.mutex.Unlock()
+
+		switch {
+		case oversized && !wasOversized:
+			m.scope.Tagged(map[string]string{"channel": channelID}).Counter("state_db_size_alerts").Inc(1)
+			logger.Warningf("[channel: %s] The state database of %d bytes exceeds its alert size of %d bytes", channelID, size, alertSize)
+		case !oversized && wasOversized:
+			logger.Infof("[channel: %s] The state database of %d bytes is back below its alert size of %d bytes", channelID, size, alertSize)
+		}
+	}
+}
+
+// MonitorStateDBSizes checks the sizes of the state databases of the ledgers
+// returned by the supplied function at every check interval, until stopped
+func (m *Manager) MonitorStateDBSizes(ledgers func() map[string]ledger.PeerLedger, stop <-chan struct{}) {
+	ticker := time.NewTicker(m.config.StateDBSizeCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.CheckStateDBSizes(ledgers())
+		case <-stop:
+			return
+		}
+	}
+}
+
+func stateDBSize(l ledger.PeerLedger) (int64, error) {
+	sizer, ok := l.(ledger.StateDBSizer)
+	if !ok {
+		return 0, errors.New("the ledger does not report the size of its state database")
+	}
+	return sizer.StateDBSize()
+}
@@ -0,0 +1,177 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package quota
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/ledger"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlobalConfig(t *testing.T) {
+	defer viper.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(bytes.NewBufferString(`
+peer:
+    quotas:
+        default:
+            maxChaincodeContainers: 10
+            maxConcurrentProposals: 100
+            stateDBSizeAlert: 1gb
+        channels:
+            busy:
+                maxConcurrentProposals: 20
+                maxDeliverStreams: 5
+`))
+	assert.NoError(t, err)
+
+	config := GlobalConfig()
+	assert.Equal(t, defaultStateDBSizeCheckInterval, config.StateDBSizeCheckInterval)
+	assert.Equal(t, Limits{MaxChaincodeContainers: 10, MaxConcurrentProposals: 100, StateDBSizeAlert: 1 << 30}, config.Limits("other"))
+	assert.Equal(t, Limits{MaxChaincodeContainers: 10, MaxDeliverStreams: 5, MaxConcurrentProposals: 20, StateDBSizeAlert: 1 << 30}, config.Limits("busy"))
+
+	viper.Set("peer.quotas.stateDBSizeCheckInterval", "1m")
+	assert.Equal(t, time.Minute, GlobalConfig().StateDBSizeCheckInterval)
+}
+
+func TestAcquireProposal(t *testing.T) {
+	m := NewManager(Config{
+		Default:  Limits{MaxConcurrentProposals: 2},
+		Channels: map[string]Limits{"small": {MaxConcurrentProposals: 1}},
+	}, nil)
+
+	release1, err := m.AcquireProposal("small")
+	assert.NoError(t, err)
+	_, err = m.AcquireProposal("small")
+	assert.EqualError(t, err, "channel small has reached its quota of 1 concurrent proposals")
+
+	// the other channels have their own slots
+	release2, err := m.AcquireProposal("other")
+	assert.NoError(t, err)
+	_, err = m.AcquireProposal("other")
+	assert.NoError(t, err)
+	_, err = m.AcquireProposal("other")
+	assert.EqualError(t, err, "channel other has reached its quota of 2 concurrent proposals")
+
+	release1()
+	release2()
+	_, err = m.AcquireProposal("small")
+	assert.NoError(t, err)
+	_, err = m.AcquireProposal("other")
+	assert.NoError(t, err)
+}
+
+func TestAcquireDeliverStream(t *testing.T) {
+	m := NewManager(Config{Default: Limits{MaxDeliverStreams: 1}}, nil)
+
+	release, err := m.AcquireDeliverStream("foo")
+	assert.NoError(t, err)
+	_, err = m.AcquireDeliverStream("foo")
+	assert.EqualError(t, err, "channel foo has reached its quota of 1 deliver streams")
+	release()
+	_, err = m.AcquireDeliverStream("foo")
+	assert.NoError(t, err)
+
+	// without quota, the streams are not bounded
+	m = NewManager(Config{}, nil)
+	for i := 0; i < 100; i++ {
+		_, err = m.AcquireDeliverStream("foo")
+		assert.NoError(t, err)
+	}
+}
+
+func TestAcquireContainer(t *testing.T) {
+	m := NewManager(Config{Default: Limits{MaxChaincodeContainers: 1}}, nil)
+
+	assert.NoError(t, m.AcquireContainer("foo", "cc1:1.0"))
+	// the container is already accounted to foo
+	assert.NoError(t, m.AcquireContainer("foo", "cc1:1.0"))
+	assert.NoError(t, m.AcquireContainer("bar", "cc1:1.0"))
+	assert.EqualError(t, m.AcquireContainer("foo", "cc2:1.0"), "channel foo has reached its quota of 1 chaincode containers")
+	assert.NoError(t, m.AcquireContainer("bar", "cc2:1.0"))
+
+	m.ReleaseContainer("cc1:1.0")
+	// releasing a container which isn't accounted is a no-op
+	m.ReleaseContainer("cc1:1.0")
+	m.ReleaseContainer("cc3:1.0")
+	assert.NoError(t, m.AcquireContainer("foo", "cc3:1.0"))
+	assert.EqualError(t, m.AcquireContainer("bar", "cc4:1.0"), "channel bar has reached its quota of 1 chaincode containers")
+}
+
+type sizedLedger struct {
+	ledger.PeerLedger
+	size int64
+	err  error
+}
+
+func (l *sizedLedger) StateDBSize() (int64, error) {
+	return l.size, l.err
+}
+
+type unsizedLedger struct {
+	ledger.PeerLedger
+}
+
+func TestCheckStateDBSizes(t *testing.T) {
+	m := NewManager(Config{
+		Default:  Limits{StateDBSizeAlert: 100},
+		Channels: map[string]Limits{"large": {StateDBSizeAlert: 1000}},
+	}, nil)
+
+	small := &sizedLedger{size: 50}
+	large := &sizedLedger{size: 500}
+	ledgers := map[string]ledger.PeerLedger{
+		"small":    small,
+		"large":    large,
+		"failing":  &sizedLedger{err: errors.New("unreachable")},
+		"unsized":  &unsizedLedger{},
+		"oversize": &sizedLedger{size: 200},
+	}
+
+	m.CheckStateDBSizes(ledgers)
+	assert.Equal(t, map[string]bool{"oversize": true}, m.oversized)
+
+	small.size = 150
+	large.size = 1500
+	m.CheckStateDBSizes(ledgers)
+	assert.Equal(t, map[string]bool{"oversize": true, "small": true, "large": true}, m.oversized)
+
+	small.size = 10
+	m.CheckStateDBSizes(ledgers)
+	assert.Equal(t, map[string]bool{"oversize": true, "large": true}, m.oversized)
+}
+
+func TestMonitorStateDBSizes(t *testing.T) {
+	m := NewManager(Config{
+		Default:                  Limits{StateDBSizeAlert: 100},
+		StateDBSizeCheckInterval: 10 * time.Millisecond,
+	}, nil)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		m.MonitorStateDBSizes(func() map[string]ledger.PeerLedger {
+			return map[string]ledger.PeerLedger{"foo": &sizedLedger{size: 200}}
+		}, stop)
+		close(done)
+	}()
+
+	gt := NewGomegaWithT(t)
+	gt.Eventually(func() bool {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+		return m.oversized["foo"]
+	}).Should(BeTrue())
+	close(stop)
+	<-done
+}
@@ -0,0 +1,174 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package crlscc
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/msp"
+	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("crlscc")
+
+// Name is the name of the CRL system chaincode, which is also the namespace
+// the CRLs it publishes are stored in
+const Name = "crlscc"
+
+// These are function names from Invoke first parameter
+const (
+	PublishCRL string = "PublishCRL"
+	GetCRLs    string = "GetCRLs"
+)
+
+// getMSPManager returns the manager of the MSPs of a channel
+var getMSPManager = func(chainID string) msp.MSPManager {
+	return mspmgmt.GetManagerForChain(chainID)
+}
+
+// New returns an instance of the CRL system chaincode.
+// Typically this is called once per peer.
+func New(sccp sysccprovider.SystemChaincodeProvider) *Publisher {
+	return &Publisher{
+		sccp: sccp,
+	}
+}
+
+func (p *Publisher) Name() string              { return Name }
+func (p *Publisher) Path() string              { return "github.com/hyperledger/fabric/core/scc/crlscc" }
+func (p *Publisher) InitArgs() [][]byte        { return nil }
+func (p *Publisher) Chaincode() shim.Chaincode { return p }
+func (p *Publisher) InvokableExternal() bool   { return true }
+func (p *Publisher) InvokableCC2CC() bool      { return false }
+func (p *Publisher) Enabled() bool             { return true }
+
+// Publisher is the system chaincode which lets the admins of the
+// application organizations of a channel publish the CRLs of their MSPs
+// with regular transactions, instead of updating the config of the channel.
+// The CRLs are stored in the namespace of the chaincode, keyed by MSP ID,
+// and every peer applies them to the MSPs of the channel when it commits
+// them, in addition to the CRLs of the config of the MSPs.
+type Publisher struct {
+	sccp sysccprovider.SystemChaincodeProvider
+}
+
+// Init is called once per chain when the chain is created.
+func (p *Publisher) Init(stub shim.ChaincodeStubInterface) pb.Response {
+	logger.Info("Init CRLSCC")
+
+	return shim.Success(nil)
+}
+
+// Invoke is called with args[0] containing the function name.
+// Each function requires additional parameters as described below:
+// # PublishCRL: publish the PEM or DER encoded CRL in args[2] for the MSP
+// args[1]; the CRL must be issued by a CA of the MSP and the creator must
+// satisfy the Admins policy of its organization. The CRL replaces the one
+// previously published by the same issuer, which must be older.
+// # GetCRLs: return the JSON encoded RevocationLists published for the MSP
+// args[1]
+func (p *Publisher) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
+	args := stub.GetArgs()
+	if len(args) < 2 {
+		return shim.Error(fmt.Sprintf("Incorrect number of arguments, %d", len(args)))
+	}
+	fname := string(args[0])
+	mspID := string(args[1])
+
+	logger.Debugf("Invoke function: %s on chain: %s", fname, stub.GetChannelID())
+
+	switch fname {
+	case PublishCRL:
+		if len(args) < 3 {
+			return shim.Error(fmt.Sprintf("Incorrect number of arguments, %d", len(args)))
+		}
+		return p.publish(stub, mspID, args[2])
+	case GetCRLs:
+		lists, err := getRevocationLists(stub, mspID)
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		listsBytes, err := json.Marshal(lists)
+		if err != nil {
+			return shim.Error(fmt.Sprintf("failed marshaling CRLs: %s", err))
+		}
+		return shim.Success(listsBytes)
+	}
+
+	return shim.Error(fmt.Sprintf("Requested function %s not found.", fname))
+}
+
+func (p *Publisher) publish(stub shim.ChaincodeStubInterface, mspID string, crlBytes []byte) pb.Response {
+	channel := stub.GetChannelID()
+	signedData, err := proposalSignedData(stub)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	crl, err := x509.ParseCRL(crlBytes)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("failed parsing CRL: %s", err))
+	}
+	lists, err := getRevocationLists(stub, mspID)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	if err := lists.add(crl, crlBytes); err != nil {
+		return shim.Error(err.Error())
+	}
+	if err := checkRevocationLists(channel, lists, signedData, getMSPManager(channel), p.sccp); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	listsBytes, err := json.Marshal(lists)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("failed marshaling CRLs: %s", err))
+	}
+	if err := stub.PutState(mspID, listsBytes); err != nil {
+		return shim.Error(fmt.Sprintf("failed storing CRLs of MSP %s: %s", mspID, err))
+	}
+	logger.Infof("Publishing CRL of %s for MSP %s on channel %s", crl.TBSCertList.Issuer, mspID, channel)
+	return shim.Success(listsBytes)
+}
+
+// proposalSignedData returns the signed data of the proposal of the
+// invocation, which the policies of the creator are evaluated against
+func proposalSignedData(stub shim.ChaincodeStubInterface) ([]*cb.SignedData, error) {
+	signedProp, err := stub.GetSignedProposal()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed getting signed proposal")
+	}
+	if signedProp == nil {
+		return nil, errors.New("no signed proposal")
+	}
+	proposal, err := utils.GetProposal(signedProp.ProposalBytes)
+	if err != nil {
+		return nil, err
+	}
+	header, err := utils.GetHeader(proposal.Header)
+	if err != nil {
+		return nil, err
+	}
+	shdr, err := utils.GetSignatureHeader(header.SignatureHeader)
+	if err != nil {
+		return nil, err
+	}
+	return []*cb.SignedData{{
+		Data:      signedProp.ProposalBytes,
+		Identity:  shdr.Creator,
+		Signature: signedProp.Signature,
+	}}, nil
+}
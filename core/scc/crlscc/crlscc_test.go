@@ -0,0 +1,392 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package crlscc
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
+	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/msp"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCA issues certificates and CRLs
+type testCA struct {
+	cert    *x509.Certificate
+	certPEM []byte
+	key     *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T, cn string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		SubjectKeyId:          []byte(cn),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{
+		cert:    cert,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		key:     key,
+	}
+}
+
+func (ca *testCA) issue(t *testing.T, cn string, serial int64) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:   big.NewInt(serial),
+		Subject:        pkix.Name{CommonName: cn},
+		NotBefore:      time.Now().Add(-time.Hour),
+		NotAfter:       time.Now().Add(time.Hour),
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+		AuthorityKeyId: ca.cert.SubjectKeyId,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func (ca *testCA) crl(t *testing.T, thisUpdate time.Time, serials ...int64) []byte {
+	var revoked []pkix.RevokedCertificate
+	for _, serial := range serials {
+		revoked = append(revoked, pkix.RevokedCertificate{SerialNumber: big.NewInt(serial), RevocationTime: thisUpdate})
+	}
+	der, err := ca.cert.CreateCRL(rand.Reader, ca.key, revoked, thisUpdate, thisUpdate.Add(time.Hour))
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der})
+}
+
+// newMSPManager returns the manager of a fabric MSP trusting the CA
+func newMSPManager(t *testing.T, mspID string, ca *testCA) msp.MSPManager {
+	m, err := msp.New(&msp.BCCSPNewOpts{NewBaseOpts: msp.NewBaseOpts{Version: msp.MSPv1_0}})
+	require.NoError(t, err)
+	fabricConfig, err := proto.Marshal(&mspprotos.FabricMSPConfig{
+		Name:      mspID,
+		RootCerts: [][]byte{ca.certPEM},
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.Setup(&mspprotos.MSPConfig{Type: int32(msp.FABRIC), Config: fabricConfig}))
+	mgr := msp.NewMSPManager()
+	require.NoError(t, mgr.Setup([]msp.MSP{m}))
+	return mgr
+}
+
+func validate(t *testing.T, mgr msp.MSPManager, mspID string, certPEM []byte) error {
+	id, err := mgr.DeserializeIdentity(utils.MarshalOrPanic(&mspprotos.SerializedIdentity{Mspid: mspID, IdBytes: certPEM}))
+	require.NoError(t, err)
+	return id.Validate()
+}
+
+type mockOrg struct {
+	channelconfig.ApplicationOrg
+	mspID string
+}
+
+func (o *mockOrg) MSPID() string { return o.mspID }
+
+type mockApplication struct {
+	channelconfig.Application
+	orgs map[string]channelconfig.ApplicationOrg
+}
+
+func (a *mockApplication) Organizations() map[string]channelconfig.ApplicationOrg { return a.orgs }
+
+// adminsPolicy is satisfied by the signed data of the admin identity
+type adminsPolicy struct {
+	admin []byte
+}
+
+func (p *adminsPolicy) Evaluate(signedData []*cb.SignedData) error {
+	for _, sd := range signedData {
+		if bytes.Equal(sd.Identity, p.admin) {
+			return nil
+		}
+	}
+	return errors.New("signature set did not satisfy policy")
+}
+
+// mockProvider exposes the application config and the policies of a channel
+type mockProvider struct {
+	sysccprovider.SystemChaincodeProvider
+	channel       string
+	app           channelconfig.Application
+	policyManager policies.Manager
+}
+
+func (p *mockProvider) GetApplicationConfig(cid string) (channelconfig.Application, bool) {
+	return p.app, cid == p.channel
+}
+
+func (p *mockProvider) PolicyManager(channelID string) (policies.Manager, bool) {
+	return p.policyManager, channelID == p.channel
+}
+
+func newMockProvider(channel string, admin []byte) *mockProvider {
+	return &mockProvider{
+		channel: channel,
+		app: &mockApplication{orgs: map[string]channelconfig.ApplicationOrg{
+			"Org1": &mockOrg{mspID: "Org1MSP"},
+		}},
+		policyManager: &mockpolicies.Manager{PolicyMap: map[string]policies.Policy{
+			"/Channel/Application/Org1/Admins": &adminsPolicy{admin: admin},
+		}},
+	}
+}
+
+func signedProposal(t *testing.T, creator []byte) *pb.SignedProposal {
+	cis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: Name}}}
+	prop, _, err := utils.CreateProposalFromCIS(cb.HeaderType_ENDORSER_TRANSACTION, "mychannel", cis, creator)
+	require.NoError(t, err)
+	return &pb.SignedProposal{ProposalBytes: utils.MarshalOrPanic(prop), Signature: []byte("signature")}
+}
+
+func TestInvoke(t *testing.T) {
+	ca := newTestCA(t, "ca.org1")
+	otherCA := newTestCA(t, "ca.org2")
+	mgr := newMSPManager(t, "Org1MSP", ca)
+	defer func(f func(string) msp.MSPManager) { getMSPManager = f }(getMSPManager)
+	getMSPManager = func(chainID string) msp.MSPManager { return mgr }
+
+	admin := []byte("admin")
+	stub := shim.NewMockStub(Name, New(newMockProvider("mychannel", admin)))
+	stub.ChannelID = "mychannel"
+	adminProp := signedProposal(t, admin)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	crl := ca.crl(t, now, 2)
+	newerCRL := ca.crl(t, now.Add(time.Minute), 2, 3)
+
+	for _, tc := range []struct {
+		name     string
+		args     []string
+		prop     *pb.SignedProposal
+		expected string
+	}{
+		{"no arguments", []string{PublishCRL}, adminProp, "Incorrect number of arguments, 1"},
+		{"no CRL", []string{PublishCRL, "Org1MSP"}, adminProp, "Incorrect number of arguments, 2"},
+		{"unknown function", []string{"Unknown", "Org1MSP"}, adminProp, "Requested function Unknown not found."},
+		{"no signed proposal", []string{PublishCRL, "Org1MSP", string(crl)}, nil, "no signed proposal"},
+		{"not a CRL", []string{PublishCRL, "Org1MSP", "not a CRL"}, adminProp, "failed parsing CRL"},
+		{"not an admin", []string{PublishCRL, "Org1MSP", string(crl)}, signedProposal(t, []byte("member")),
+			"the creator does not satisfy policy /Channel/Application/Org1/Admins: signature set did not satisfy policy"},
+		{"not an application organization", []string{PublishCRL, "OrdererMSP", string(crl)}, adminProp,
+			"MSP OrdererMSP is not the MSP of an application organization of channel mychannel"},
+		{"issued by another CA", []string{PublishCRL, "Org1MSP", string(otherCA.crl(t, now, 2))}, adminProp,
+			"invalid CRL for MSP Org1MSP: CRL is not signed by a CA of MSP Org1MSP"},
+		{"published", []string{PublishCRL, "Org1MSP", string(crl)}, adminProp, ""},
+		{"not newer", []string{PublishCRL, "Org1MSP", string(crl)}, adminProp,
+			"the CRL of CN=ca.org1 published for MSP Org1MSP is not older than this one"},
+		{"replaced", []string{PublishCRL, "Org1MSP", string(newerCRL)}, adminProp, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var args [][]byte
+			for _, arg := range tc.args {
+				args = append(args, []byte(arg))
+			}
+			res := stub.MockInvokeWithSignedProposal("tx", args, tc.prop)
+			if tc.expected != "" {
+				assert.Equal(t, int32(shim.ERROR), res.Status)
+				assert.Contains(t, res.Message, tc.expected)
+				return
+			}
+			assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+		})
+	}
+
+	res := stub.MockInvoke("get", [][]byte{[]byte(GetCRLs), []byte("Org1MSP")})
+	require.Equal(t, int32(shim.OK), res.Status, res.Message)
+	lists := &RevocationLists{}
+	require.NoError(t, json.Unmarshal(res.Payload, lists))
+	assert.Equal(t, &RevocationLists{MSPID: "Org1MSP", CRLs: [][]byte{newerCRL}}, lists)
+	assert.Equal(t, res.Payload, stub.State["Org1MSP"])
+
+	res = stub.MockInvoke("get", [][]byte{[]byte(GetCRLs), []byte("Org2MSP")})
+	require.Equal(t, int32(shim.OK), res.Status, res.Message)
+	assert.JSONEq(t, `{"msp_id":"Org2MSP","crls":[]}`, string(res.Payload))
+}
+
+func writeSet(t *testing.T, writes ...*kvrwset.KVWrite) *rwsetutil.TxRwSet {
+	return &rwsetutil.TxRwSet{NsRwSets: []*rwsetutil.NsRwSet{
+		{NameSpace: "other", KvRwSet: &kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{{Key: "Org1MSP", Value: []byte("ignored")}}}},
+		{NameSpace: Name, KvRwSet: &kvrwset.KVRWSet{Writes: writes}},
+	}}
+}
+
+func marshalLists(t *testing.T, mspID string, crls ...[]byte) []byte {
+	listsBytes, err := json.Marshal(&RevocationLists{MSPID: mspID, CRLs: crls})
+	require.NoError(t, err)
+	return listsBytes
+}
+
+func TestValidateUpdates(t *testing.T) {
+	ca := newTestCA(t, "ca.org1")
+	mgr := newMSPManager(t, "Org1MSP", ca)
+	admin := []byte("admin")
+	sccp := newMockProvider("mychannel", admin)
+	adminData := []*cb.SignedData{{Identity: admin}}
+
+	now := time.Now()
+	crl := ca.crl(t, now, 2)
+	for _, tc := range []struct {
+		name       string
+		write      *kvrwset.KVWrite
+		signedData []*cb.SignedData
+		expected   string
+	}{
+		{"valid", &kvrwset.KVWrite{Key: "Org1MSP", Value: marshalLists(t, "Org1MSP", crl)}, adminData, ""},
+		{"delete", &kvrwset.KVWrite{Key: "Org1MSP", IsDelete: true}, adminData, "the CRLs of MSP Org1MSP can't be deleted"},
+		{"bogus value", &kvrwset.KVWrite{Key: "Org1MSP", Value: []byte("bogus")}, adminData, "failed unmarshaling CRLs"},
+		{"other MSP", &kvrwset.KVWrite{Key: "Org1MSP", Value: marshalLists(t, "Org2MSP", crl)}, adminData, "invalid CRLs for MSP Org1MSP"},
+		{"bogus CRL", &kvrwset.KVWrite{Key: "Org1MSP", Value: marshalLists(t, "Org1MSP", []byte("bogus"))}, adminData, "failed parsing CRL of MSP Org1MSP"},
+		{"two CRLs of an issuer", &kvrwset.KVWrite{Key: "Org1MSP", Value: marshalLists(t, "Org1MSP", crl, ca.crl(t, now.Add(time.Minute)))}, adminData,
+			"more than one CRL of CN=ca.org1 for MSP Org1MSP"},
+		{"not an admin", &kvrwset.KVWrite{Key: "Org1MSP", Value: marshalLists(t, "Org1MSP", crl)}, []*cb.SignedData{{Identity: []byte("member")}},
+			"the creator does not satisfy policy /Channel/Application/Org1/Admins"},
+		{"issued by another CA", &kvrwset.KVWrite{Key: "Org1MSP", Value: marshalLists(t, "Org1MSP", newTestCA(t, "ca.org2").crl(t, now))}, adminData,
+			"CRL is not signed by a CA of MSP Org1MSP"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateUpdates("mychannel", writeSet(t, tc.write), tc.signedData, mgr, sccp)
+			if tc.expected == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expected)
+		})
+	}
+}
+
+type mockQueryExecutor struct {
+	ledger.SimpleQueryExecutor
+	state map[string][]byte
+	err   error
+}
+
+func (qe *mockQueryExecutor) GetState(namespace, key string) ([]byte, error) {
+	if namespace != Name {
+		return nil, nil
+	}
+	return qe.state[key], qe.err
+}
+
+func TestApplyRevocationLists(t *testing.T) {
+	ca := newTestCA(t, "ca.org1")
+	mgr := newMSPManager(t, "Org1MSP", ca)
+	member := ca.issue(t, "member", 2)
+	require.NoError(t, validate(t, mgr, "Org1MSP", member))
+
+	err := ApplyRevocationLists(&mockQueryExecutor{err: errors.New("db down")}, mgr)
+	assert.EqualError(t, err, "failed reading CRLs of MSP Org1MSP: db down")
+
+	// invalid CRLs are skipped
+	err = ApplyRevocationLists(&mockQueryExecutor{state: map[string][]byte{"Org1MSP": []byte("bogus")}}, mgr)
+	assert.NoError(t, err)
+	assert.NoError(t, validate(t, mgr, "Org1MSP", member))
+
+	err = ApplyRevocationLists(&mockQueryExecutor{state: map[string][]byte{"Org1MSP": marshalLists(t, "Org1MSP", ca.crl(t, time.Now(), 2))}}, mgr)
+	assert.NoError(t, err)
+	err = validate(t, mgr, "Org1MSP", member)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "The certificate has been revoked")
+}
+
+func TestStateListener(t *testing.T) {
+	ca := newTestCA(t, "ca.org1")
+	mgr := newMSPManager(t, "Org1MSP", ca)
+	member := ca.issue(t, "member", 2)
+	other := ca.issue(t, "other", 3)
+
+	var updated []string
+	capabilities := &mockconfig.ChannelCapabilities{PublishedCRLsVal: true}
+	resources := &mockconfig.Resources{
+		ChannelConfigVal: &mockconfig.Channel{CapabilitiesVal: capabilities},
+		MSPManagerVal:    mgr,
+	}
+	listener := NewStateListener(func(channelID string) channelconfig.Resources {
+		if channelID != "mychannel" {
+			return nil
+		}
+		return resources
+	}, func(channelID string) {
+		updated = append(updated, channelID)
+	})
+	assert.Equal(t, []string{Name}, listener.InterestedInNamespaces())
+
+	handle := func(channelID string, writes ...*kvrwset.KVWrite) error {
+		return listener.HandleStateUpdates(&ledger.StateUpdateTrigger{
+			LedgerID:     channelID,
+			StateUpdates: ledger.StateUpdates{Name: writes},
+		})
+	}
+
+	err := handle("mychannel", &kvrwset.KVWrite{Key: "Org1MSP", Value: []byte("bogus")})
+	assert.Error(t, err)
+
+	// the CRLs are applied once the block is committed
+	require.NoError(t, handle("mychannel", &kvrwset.KVWrite{Key: "Org1MSP", Value: marshalLists(t, "Org1MSP", ca.crl(t, time.Now(), 2))}))
+	assert.NoError(t, validate(t, mgr, "Org1MSP", member))
+	listener.StateCommitDone("mychannel")
+	assert.Error(t, validate(t, mgr, "Org1MSP", member))
+	assert.NoError(t, validate(t, mgr, "Org1MSP", other))
+	assert.Equal(t, []string{"mychannel"}, updated)
+
+	// the CRLs of a channel being created are not applied
+	require.NoError(t, handle("newchannel", &kvrwset.KVWrite{Key: "Org1MSP", Value: marshalLists(t, "Org1MSP")}))
+	listener.StateCommitDone("newchannel")
+	assert.Equal(t, []string{"mychannel"}, updated)
+
+	// the CRLs replace the ones published before
+	require.NoError(t, handle("mychannel", &kvrwset.KVWrite{Key: "Org1MSP", Value: marshalLists(t, "Org1MSP", ca.crl(t, time.Now().Add(time.Minute), 3))}))
+	listener.StateCommitDone("mychannel")
+	assert.NoError(t, validate(t, mgr, "Org1MSP", member))
+	assert.Error(t, validate(t, mgr, "Org1MSP", other))
+	assert.Equal(t, []string{"mychannel", "mychannel"}, updated)
+
+	// the CRLs are not applied without the capability
+	capabilities.PublishedCRLsVal = false
+	require.NoError(t, handle("mychannel", &kvrwset.KVWrite{Key: "Org1MSP", Value: marshalLists(t, "Org1MSP")}))
+	listener.StateCommitDone("mychannel")
+	assert.Error(t, validate(t, mgr, "Org1MSP", other))
+	assert.Equal(t, []string{"mychannel", "mychannel"}, updated)
+}
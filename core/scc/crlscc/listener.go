@@ -0,0 +1,98 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package crlscc
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+)
+
+// StateListener applies the CRLs published in a block to the MSPs of the
+// channel once the block is committed
+type StateListener struct {
+	// ChannelConfig returns the config resources of a channel, nil if the
+	// channel is not created yet
+	ChannelConfig func(channelID string) channelconfig.Resources
+	// Updated, if set, is called after the CRLs of a channel are updated
+	Updated func(channelID string)
+
+	lock    sync.Mutex
+	pending map[string][]*RevocationLists
+}
+
+// NewStateListener returns a listener which applies the CRLs published in
+// the blocks of a channel to the MSPs of its config
+func NewStateListener(channelConfig func(channelID string) channelconfig.Resources, updated func(channelID string)) *StateListener {
+	return &StateListener{
+		ChannelConfig: channelConfig,
+		Updated:       updated,
+		pending:       map[string][]*RevocationLists{},
+	}
+}
+
+// InterestedInNamespaces implements function from interface `ledger.StateListener`
+func (l *StateListener) InterestedInNamespaces() []string {
+	return []string{Name}
+}
+
+// HandleStateUpdates implements function from interface `ledger.StateListener`;
+// it records the CRLs written by the block, which are applied once it is
+// committed
+func (l *StateListener) HandleStateUpdates(trigger *ledger.StateUpdateTrigger) error {
+	kvWrites := trigger.StateUpdates[Name].([]*kvrwset.KVWrite)
+	var updates []*RevocationLists
+	for _, kvWrite := range kvWrites {
+		if kvWrite.IsDelete {
+			continue
+		}
+		lists, err := unmarshalRevocationLists(kvWrite.Value)
+		if err != nil {
+			return err
+		}
+		updates = append(updates, lists)
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.pending[trigger.LedgerID] = updates
+	return nil
+}
+
+// StateCommitDone implements function from interface `ledger.StateListener`;
+// it applies the CRLs of the committed block to the MSPs of the channel,
+// given the capability is active
+func (l *StateListener) StateCommitDone(channelID string) {
+	l.lock.Lock()
+	updates := l.pending[channelID]
+	delete(l.pending, channelID)
+	l.lock.Unlock()
+
+	resources := l.ChannelConfig(channelID)
+	if resources == nil || len(updates) == 0 {
+		// the CRLs of a channel which is being created are applied when
+		// its MSPs are set up
+		return
+	}
+	if !resources.ChannelConfig().Capabilities().PublishedCRLs() {
+		logger.Warningf("Not applying the CRLs published on channel %s, which lacks the capability", channelID)
+		return
+	}
+	mspManager := resources.MSPManager()
+	for _, lists := range updates {
+		if err := updateRevocationLists(mspManager, lists); err != nil {
+			logger.Errorf("Failed applying the published CRLs of MSP %s on channel %s: %s", lists.MSPID, channelID, err)
+			continue
+		}
+		logger.Infof("Applied %d published CRLs to MSP %s on channel %s", len(lists.CRLs), lists.MSPID, channelID)
+	}
+	if l.Updated != nil {
+		l.Updated(channelID)
+	}
+}
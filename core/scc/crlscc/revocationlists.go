@@ -0,0 +1,218 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package crlscc
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/msp"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// RevocationLists are the CRLs published for an MSP, at most one per issuer
+type RevocationLists struct {
+	MSPID string   `json:"msp_id"`
+	CRLs  [][]byte `json:"crls"`
+}
+
+// add adds the CRL to the lists, in place of the CRL of the same issuer
+// which must be older
+func (l *RevocationLists) add(crl *pkix.CertificateList, crlBytes []byte) error {
+	issuer := crl.TBSCertList.Issuer.String()
+	for i, publishedBytes := range l.CRLs {
+		published, err := x509.ParseCRL(publishedBytes)
+		if err != nil {
+			return errors.Wrapf(err, "failed parsing published CRL of MSP %s", l.MSPID)
+		}
+		if published.TBSCertList.Issuer.String() != issuer {
+			continue
+		}
+		if !crl.TBSCertList.ThisUpdate.After(published.TBSCertList.ThisUpdate) {
+			return errors.Errorf("the CRL of %s published for MSP %s is not older than this one", issuer, l.MSPID)
+		}
+		l.CRLs[i] = crlBytes
+		return nil
+	}
+	l.CRLs = append(l.CRLs, crlBytes)
+	return nil
+}
+
+// validate checks that the CRLs of the lists can be parsed, and that they
+// are issued by distinct issuers
+func (l *RevocationLists) validate() error {
+	issuers := map[string]bool{}
+	for _, crlBytes := range l.CRLs {
+		crl, err := x509.ParseCRL(crlBytes)
+		if err != nil {
+			return errors.Wrapf(err, "failed parsing CRL of MSP %s", l.MSPID)
+		}
+		issuer := crl.TBSCertList.Issuer.String()
+		if issuers[issuer] {
+			return errors.Errorf("more than one CRL of %s for MSP %s", issuer, l.MSPID)
+		}
+		issuers[issuer] = true
+	}
+	return nil
+}
+
+func unmarshalRevocationLists(listsBytes []byte) (*RevocationLists, error) {
+	lists := &RevocationLists{}
+	if err := json.Unmarshal(listsBytes, lists); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshaling CRLs")
+	}
+	return lists, nil
+}
+
+// getRevocationLists returns the CRLs published for the MSP, which are empty
+// if none was published
+func getRevocationLists(stub shim.ChaincodeStubInterface, mspID string) (*RevocationLists, error) {
+	listsBytes, err := stub.GetState(mspID)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("failed reading CRLs of MSP %s", mspID))
+	}
+	if listsBytes == nil {
+		return &RevocationLists{MSPID: mspID, CRLs: [][]byte{}}, nil
+	}
+	return unmarshalRevocationLists(listsBytes)
+}
+
+// checkRevocationLists checks that the CRLs are issued by the CAs of their
+// MSP, and that the signed data satisfies the Admins policy of the
+// application organization of the MSP
+func checkRevocationLists(channel string, lists *RevocationLists, signedData []*cb.SignedData, mspManager msp.MSPManager, sccp sysccprovider.SystemChaincodeProvider) error {
+	ac, ok := sccp.GetApplicationConfig(channel)
+	if !ok {
+		return errors.Errorf("channel %s has no application config", channel)
+	}
+	orgName := ""
+	for name, org := range ac.Organizations() {
+		if org.MSPID() == lists.MSPID {
+			orgName = name
+			break
+		}
+	}
+	if orgName == "" {
+		return errors.Errorf("MSP %s is not the MSP of an application organization of channel %s", lists.MSPID, channel)
+	}
+
+	policyManager, ok := sccp.PolicyManager(channel)
+	if !ok {
+		return errors.Errorf("no policy manager for channel %s", channel)
+	}
+	policyName := fmt.Sprintf("/%s/%s/%s/%s", channelconfig.RootGroupKey, channelconfig.ApplicationGroupKey, orgName, channelconfig.AdminsPolicyKey)
+	policy, ok := policyManager.GetPolicy(policyName)
+	if !ok {
+		return errors.Errorf("policy %s not found", policyName)
+	}
+	if err := policy.Evaluate(signedData); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("the creator does not satisfy policy %s", policyName))
+	}
+
+	updater, err := revocationListUpdater(mspManager, lists.MSPID)
+	if err != nil {
+		return err
+	}
+	for _, crl := range lists.CRLs {
+		if err := updater.ValidateRevocationList(crl); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("invalid CRL for MSP %s", lists.MSPID))
+		}
+	}
+	return nil
+}
+
+func revocationListUpdater(mspManager msp.MSPManager, mspID string) (msp.RevocationListUpdater, error) {
+	msps, err := mspManager.GetMSPs()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed getting MSPs")
+	}
+	m, ok := msps[mspID]
+	if !ok {
+		return nil, errors.Errorf("MSP %s not found", mspID)
+	}
+	updater, ok := m.(msp.RevocationListUpdater)
+	if !ok {
+		return nil, errors.Errorf("MSP %s does not support CRL updates", mspID)
+	}
+	return updater, nil
+}
+
+// ValidateUpdates re-checks, at commit time, the CRLs a transaction of the
+// supplied channel writes to the namespace of the chaincode, against the
+// MSPs and the policies of the channel the transaction is committed with:
+// the CRLs must be issued by the CAs of their MSPs, and the creator of the
+// transaction must satisfy the Admins policy of their organizations.
+func ValidateUpdates(channel string, txRWSet *rwsetutil.TxRwSet, signedData []*cb.SignedData, mspManager msp.MSPManager, sccp sysccprovider.SystemChaincodeProvider) error {
+	for _, ns := range txRWSet.NsRwSets {
+		if ns.NameSpace != Name || ns.KvRwSet == nil {
+			continue
+		}
+		for _, write := range ns.KvRwSet.Writes {
+			if write.IsDelete {
+				return errors.Errorf("the CRLs of MSP %s can't be deleted", write.Key)
+			}
+			lists, err := unmarshalRevocationLists(write.Value)
+			if err != nil {
+				return err
+			}
+			if lists.MSPID != write.Key {
+				return errors.Errorf("invalid CRLs for MSP %s", write.Key)
+			}
+			if err := lists.validate(); err != nil {
+				return err
+			}
+			if err := checkRevocationLists(channel, lists, signedData, mspManager, sccp); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyRevocationLists updates the MSPs of the manager with the CRLs
+// published for them in the state. It is called whenever the MSPs of a
+// channel are set up from its config, which only holds the CRLs of the
+// config. The MSPs whose CRLs can't be applied are logged and skipped.
+func ApplyRevocationLists(qe ledger.SimpleQueryExecutor, mspManager msp.MSPManager) error {
+	msps, err := mspManager.GetMSPs()
+	if err != nil {
+		return errors.WithMessage(err, "failed getting MSPs")
+	}
+	for mspID := range msps {
+		listsBytes, err := qe.GetState(Name, mspID)
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("failed reading CRLs of MSP %s", mspID))
+		}
+		if listsBytes == nil {
+			continue
+		}
+		lists, err := unmarshalRevocationLists(listsBytes)
+		if err == nil {
+			err = updateRevocationLists(mspManager, lists)
+		}
+		if err != nil {
+			logger.Warningf("Failed applying the published CRLs of MSP %s: %s", mspID, err)
+		}
+	}
+	return nil
+}
+
+func updateRevocationLists(mspManager msp.MSPManager, lists *RevocationLists) error {
+	updater, err := revocationListUpdater(mspManager, lists.MSPID)
+	if err != nil {
+		return err
+	}
+	return updater.UpdateRevocationLists(lists.CRLs)
+}
@@ -12,12 +12,19 @@ SPDX-License-Identifier: Apache-2.0
 package cscc
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/capabilities"
+	"github.com/hyperledger/fabric/common/certmonitor"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/tools/protolator"
 	"github.com/hyperledger/fabric/core/aclmgmt"
 	"github.com/hyperledger/fabric/core/aclmgmt/resources"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
@@ -25,9 +32,8 @@ import (
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/core/peer"
-	"github.com/hyperledger/fabric/core/policy"
-	"github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/protos/common"
+	_ "github.com/hyperledger/fabric/protos/orderer" // register the Orderer config group type
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
@@ -37,11 +43,6 @@ import (
 // Typically, only one will be created per peer instance.
 func New(ccp ccprovider.ChaincodeProvider, sccp sysccprovider.SystemChaincodeProvider, aclProvider aclmgmt.ACLProvider) *PeerConfiger {
 	return &PeerConfiger{
-		policyChecker: policy.NewPolicyChecker(
-			peer.NewChannelPolicyManagerGetter(),
-			mgmt.GetLocalMSP(),
-			mgmt.NewLocalMSPPrincipalGetter(),
-		),
 		configMgr:   peer.NewConfigSupport(),
 		ccp:         ccp,
 		sccp:        sccp,
@@ -61,11 +62,10 @@ func (e *PeerConfiger) Enabled() bool             { return true }
 // configuration transaction coming in from the ordering service, the
 // committer calls this system chaincode to process the transaction.
 type PeerConfiger struct {
-	policyChecker policy.PolicyChecker
-	configMgr     config.Manager
-	ccp           ccprovider.ChaincodeProvider
-	sccp          sysccprovider.SystemChaincodeProvider
-	aclProvider   aclmgmt.ACLProvider
+	configMgr   config.Manager
+	ccp         ccprovider.ChaincodeProvider
+	sccp        sysccprovider.SystemChaincodeProvider
+	aclProvider aclmgmt.ACLProvider
 }
 
 var cnflogger = flogging.MustGetLogger("cscc")
@@ -73,10 +73,14 @@ var cnflogger = flogging.MustGetLogger("cscc")
 // These are function names from Invoke first parameter
 const (
 	JoinChain                string = "JoinChain"
+	JoinChainBySnapshot      string = "JoinChainBySnapshot"
 	GetConfigBlock           string = "GetConfigBlock"
 	GetChannels              string = "GetChannels"
 	GetConfigTree            string = "GetConfigTree"
 	SimulateConfigTreeUpdate string = "SimulateConfigTreeUpdate"
+	GetChannelConfig         string = "GetChannelConfig"
+	GetCapabilities          string = "GetCapabilities"
+	GetExpiringIdentities    string = "GetExpiringIdentities"
 )
 
 // Init is mostly useless from an SCC perspective
@@ -93,7 +97,8 @@ func (e *PeerConfiger) Init(stub shim.ChaincodeStubInterface) pb.Response {
 // # args[0] is the function name, which must be JoinChain, GetConfigBlock or
 // UpdateConfigBlock
 // # args[1] is a configuration Block if args[0] is JoinChain or
-// UpdateConfigBlock; otherwise it is the chain id
+// UpdateConfigBlock, the gzip compressed tar archive of a state snapshot if
+// args[0] is JoinChainBySnapshot; otherwise it is the chain id
 // TODO: Improve the scc interface to avoid marshal/unmarshal args
 func (e *PeerConfiger) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 	args := stub.GetArgs()
@@ -146,9 +151,8 @@ func (e *PeerConfiger) InvokeNoShim(args [][]byte, sp *pb.SignedProposal) pb.Res
 				"of configuration block, because of %s", cid, err))
 		}
 
-		// 2. check local MSP Admins policy
-		// TODO: move to ACLProvider once it will support chainless ACLs
-		if err = e.policyChecker.CheckPolicyNoChannel(mgmt.Admins, sp); err != nil {
+		// 2. check the peer wide policy
+		if err = e.aclProvider.CheckACL(resources.Cscc_JoinChain, "", sp); err != nil {
 			return shim.Error(fmt.Sprintf("access denied for [%s][%s]: [%s]", fname, cid, err))
 		}
 
@@ -162,6 +166,18 @@ func (e *PeerConfiger) InvokeNoShim(args [][]byte, sp *pb.SignedProposal) pb.Res
 		}
 
 		return joinChain(cid, block, e.ccp, e.sccp)
+	case JoinChainBySnapshot:
+		if len(args[1]) == 0 {
+			return shim.Error("Cannot join the channel, no snapshot archive provided")
+		}
+
+		// 2. check the peer wide policy, the channel being known only once
+		// the snapshot is read
+		if err = e.aclProvider.CheckACL(resources.Cscc_JoinChain, "", sp); err != nil {
+			return shim.Error(fmt.Sprintf("access denied for [%s]: [%s]", fname, err))
+		}
+
+		return joinChainBySnapshot(args[1], e.ccp, e.sccp)
 	case GetConfigBlock:
 		// 2. check policy
 		if err = e.aclProvider.CheckACL(resources.Cscc_GetConfigBlock, string(args[1]), sp); err != nil {
@@ -182,10 +198,36 @@ func (e *PeerConfiger) InvokeNoShim(args [][]byte, sp *pb.SignedProposal) pb.Res
 			return shim.Error(fmt.Sprintf("access denied for [%s][%s]: %s", fname, args[1], err))
 		}
 		return e.simulateConfigTreeUpdate(args[1], args[2])
+	case GetChannelConfig:
+		// 2. check policy
+		if err = e.aclProvider.CheckACL(resources.Cscc_GetChannelConfig, string(args[1]), sp); err != nil {
+			return shim.Error(fmt.Sprintf("access denied for [%s][%s]: %s", fname, args[1], err))
+		}
+
+		return e.getChannelConfig(args[1])
+	case GetCapabilities:
+		// 2. check policy
+		if err = e.aclProvider.CheckACL(resources.Cscc_GetCapabilities, string(args[1]), sp); err != nil {
+			return shim.Error(fmt.Sprintf("access denied for [%s][%s]: %s", fname, args[1], err))
+		}
+
+		return e.getCapabilities(args[1])
+	case GetExpiringIdentities:
+		// 2. check policy
+		if err = e.aclProvider.CheckACL(resources.Cscc_GetExpiringIdentities, string(args[1]), sp); err != nil {
+			return shim.Error(fmt.Sprintf("access denied for [%s][%s]: %s", fname, args[1], err))
+		}
+
+		window := defaultExpiryWindow
+		if len(args) > 2 {
+			if window, err = time.ParseDuration(string(args[2])); err != nil {
+				return shim.Error(fmt.Sprintf("Invalid expiry window %s: %s", args[2], err))
+			}
+		}
+		return e.getExpiringIdentities(args[1], window)
 	case GetChannels:
-		// 2. check local MSP Members policy
-		// TODO: move to ACLProvider once it will support chainless ACLs
-		if err = e.policyChecker.CheckPolicyNoChannel(mgmt.Members, sp); err != nil {
+		// 2. check the peer wide policy
+		if err = e.aclProvider.CheckACL(resources.Cscc_GetChannels, "", sp); err != nil {
 			return shim.Error(fmt.Sprintf("access denied for [%s]: %s", fname, err))
 		}
 
@@ -242,6 +284,19 @@ func joinChain(chainID string, block *common.Block, ccp ccprovider.ChaincodeProv
 	return shim.Success(nil)
 }
 
+// joinChainBySnapshot will join the chain of the state snapshot in the
+// directory, the ledger of the chain starting at the height of the snapshot
+func joinChainBySnapshot(snapshotArchive []byte, ccp ccprovider.ChaincodeProvider, sccp sysccprovider.SystemChaincodeProvider) pb.Response {
+	chainID, err := peer.CreateChainFromSnapshot(bytes.NewReader(snapshotArchive), ccp, sccp)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	peer.InitChain(chainID)
+
+	return shim.Success(nil)
+}
+
 // Return the current configuration block for the specified chainID. If the
 // peer doesn't belong to the chain, return error
 func getConfigBlock(chainID []byte) pb.Response {
@@ -278,6 +333,185 @@ func (e *PeerConfiger) getConfigTree(chainID []byte) pb.Response {
 	return shim.Success(configBytes)
 }
 
+// getChannelConfig returns the current config of the channel, decoded to JSON
+func (e *PeerConfiger) getChannelConfig(chainID []byte) pb.Response {
+	if chainID == nil {
+		return shim.Error("Chain ID must not be nil")
+	}
+	channelCfg := e.configMgr.GetChannelConfig(string(chainID)).ConfigProto()
+	if channelCfg == nil {
+		return shim.Error(fmt.Sprintf("Unknown chain ID, %s", string(chainID)))
+	}
+	buf := &bytes.Buffer{}
+	if err := protolator.DeepMarshalJSON(buf, channelCfg); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to decode the config of chain ID %s: %s", string(chainID), err))
+	}
+	return shim.Success(buf.Bytes())
+}
+
+// Capabilities is the JSON payload returned by GetCapabilities, listing the
+// capabilities enabled at each level of the channel config and the
+// features which they turn on
+type Capabilities struct {
+	Channel     []string        `json:"channel"`
+	Orderer     []string        `json:"orderer"`
+	Application []string        `json:"application"`
+	Features    map[string]bool `json:"features"`
+}
+
+// getCapabilities returns the capabilities of the channel, encoded as JSON
+func (e *PeerConfiger) getCapabilities(chainID []byte) pb.Response {
+	if chainID == nil {
+		return shim.Error("Chain ID must not be nil")
+	}
+	channelCfg := e.configMgr.GetChannelConfig(string(chainID)).ConfigProto()
+	if channelCfg == nil || channelCfg.ChannelGroup == nil {
+		return shim.Error(fmt.Sprintf("Unknown chain ID, %s", string(chainID)))
+	}
+
+	channelGroup := channelCfg.ChannelGroup
+	channelCaps, err := capabilitiesOf(channelGroup)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	ordererCaps, err := capabilitiesOf(channelGroup.Groups[channelconfig.OrdererGroupKey])
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	applicationCaps, err := capabilitiesOf(channelGroup.Groups[channelconfig.ApplicationGroupKey])
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	caps := &Capabilities{
+		Channel:     capabilityNames(channelCaps),
+		Orderer:     capabilityNames(ordererCaps),
+		Application: capabilityNames(applicationCaps),
+		Features: features(
+			capabilities.NewChannelProvider(channelCaps),
+			capabilities.NewOrdererProvider(ordererCaps),
+			capabilities.NewApplicationProvider(applicationCaps),
+		),
+	}
+	capsBytes, err := json.Marshal(caps)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	return shim.Success(capsBytes)
+}
+
+// features returns whether the capabilities of the channel turn on each of
+// the features gated by them
+func features(cp *capabilities.ChannelProvider, op *capabilities.OrdererProvider, ap *capabilities.ApplicationProvider) map[string]bool {
+	return map[string]bool{
+		// channel
+		"endorsement_time_expiration": cp.EndorsementTimeExpiration(),
+		"published_crls":              cp.PublishedCRLs(),
+		// orderer
+		"predictable_channel_template": op.PredictableChannelTemplate(),
+		"resubmission":                 op.Resubmission(),
+		"expiration_check":             op.ExpirationCheck(),
+		"unsatisfiable_policies_check": op.UnsatisfiablePoliciesCheck(),
+		"deliver_identity_binding":     op.DeliverIdentityBinding(),
+		"transaction_size_limit":       op.TransactionSizeLimit(),
+		"kafka_ipv6_brokers":           op.KafkaIPv6Brokers(),
+		// application
+		"acls":                           ap.ACLs(),
+		"forbid_duplicate_txid_in_block": ap.ForbidDuplicateTXIdInBlock(),
+		"private_channel_data":           ap.PrivateChannelData(),
+		"collection_upgrade":             ap.CollectionUpgrade(),
+		"v1_1_validation":                ap.V1_1Validation(),
+		"v1_2_validation":                ap.V1_2Validation(),
+		"v1_3_validation":                ap.V1_3Validation(),
+		"metadata_lifecycle":             ap.MetadataLifecycle(),
+		"key_level_endorsement":          ap.KeyLevelEndorsement(),
+		"configurable_rwset_hashing":     ap.ConfigurableRWSetHashing(),
+		"tx_deduplication":               ap.TxDeduplication(),
+		"chaincode_migration":            ap.ChaincodeMigration(),
+		"ephemeral_collections":          ap.EphemeralCollections(),
+		"value_commitments":              ap.ValueCommitments(),
+	}
+}
+
+// defaultExpiryWindow is the window of GetExpiringIdentities when none is requested
+const defaultExpiryWindow = 30 * 24 * time.Hour
+
+// ExpiringIdentity is a certificate of an MSP of the channel config, listed by
+// GetExpiringIdentities as it expires within the requested window or expired
+type ExpiringIdentity struct {
+	MSPID     string    `json:"msp_id"`
+	Role      string    `json:"role"`
+	Subject   string    `json:"subject"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Expired   bool      `json:"expired"`
+}
+
+// getExpiringIdentities returns the certificates of the MSPs of the orderer and
+// application organizations of the channel which expire within the window, the
+// earliest first, encoded as JSON
+func (e *PeerConfiger) getExpiringIdentities(chainID []byte, window time.Duration) pb.Response {
+	if chainID == nil {
+		return shim.Error("Chain ID must not be nil")
+	}
+	channelCfg := e.configMgr.GetChannelConfig(string(chainID)).ConfigProto()
+	if channelCfg == nil || channelCfg.ChannelGroup == nil {
+		return shim.Error(fmt.Sprintf("Unknown chain ID, %s", string(chainID)))
+	}
+
+	certs, err := certmonitor.ChannelCertificates(string(chainID), channelCfg)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	now := time.Now()
+	expiring := []*ExpiringIdentity{}
+	for _, c := range certs {
+		if c.NotAfter.After(now.Add(window)) {
+			continue
+		}
+		expiring = append(expiring, &ExpiringIdentity{
+			MSPID:     c.MSPID,
+			Role:      c.Role,
+			Subject:   c.Subject,
+			ExpiresAt: c.NotAfter,
+			Expired:   c.NotAfter.Before(now),
+		})
+	}
+	sort.SliceStable(expiring, func(i, j int) bool {
+		return expiring[i].ExpiresAt.Before(expiring[j].ExpiresAt)
+	})
+
+	expiringBytes, err := json.Marshal(expiring)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	return shim.Success(expiringBytes)
+}
+
+// capabilitiesOf returns the capabilities set in the config group, if any
+func capabilitiesOf(group *common.ConfigGroup) (map[string]*common.Capability, error) {
+	if group == nil {
+		return nil, nil
+	}
+	value, ok := group.Values[channelconfig.CapabilitiesKey]
+	if !ok {
+		return nil, nil
+	}
+	caps := &common.Capabilities{}
+	if err := proto.Unmarshal(value.Value, caps); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshaling capabilities")
+	}
+	return caps.Capabilities, nil
+}
+
+func capabilityNames(caps map[string]*common.Capability) []string {
+	names := []string{}
+	for name := range caps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (e *PeerConfiger) simulateConfigTreeUpdate(chainID []byte, envb []byte) pb.Response {
 	if chainID == nil {
 		return shim.Error("Chain ID must not be nil")
@@ -7,12 +7,16 @@ SPDX-License-Identifier: Apache-2.0
 package cscc
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/capabilities"
+	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/configtx"
 	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
@@ -20,7 +24,6 @@ import (
 	"github.com/hyperledger/fabric/common/genesis"
 	"github.com/hyperledger/fabric/common/localmsp"
 	"github.com/hyperledger/fabric/common/mocks/scc"
-	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/common/tools/configtxgen/configtxgentest"
 	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
@@ -40,8 +43,6 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
 	ccprovidermocks "github.com/hyperledger/fabric/core/mocks/ccprovider"
 	"github.com/hyperledger/fabric/core/peer"
-	"github.com/hyperledger/fabric/core/policy"
-	policymocks "github.com/hyperledger/fabric/core/policy/mocks"
 	"github.com/hyperledger/fabric/core/scc/cscc/mock"
 	"github.com/hyperledger/fabric/gossip/api"
 	"github.com/hyperledger/fabric/gossip/service"
@@ -135,10 +136,12 @@ func TestConfigerInvokeInvalidParameters(t *testing.T) {
 	assert.Equal(t, res.Status, int32(shim.ERROR), "CSCC invoke expected to fail having zero arguments")
 	assert.Equal(t, res.Message, "Incorrect number of arguments, 0")
 
+	mockAclProvider.Reset()
+	mockAclProvider.On("CheckACL", resources.Cscc_GetChannels, "", (*pb.SignedProposal)(nil)).Return(errors.New("Nil SignedProposal"))
 	args := [][]byte{[]byte("GetChannels")}
 	res = stub.MockInvokeWithSignedProposal("3", args, nil)
 	assert.Equal(t, res.Status, int32(shim.ERROR), "CSCC invoke expected to fail no signed proposal provided")
-	assert.Contains(t, res.Message, "access denied for [GetChannels]")
+	assert.Equal(t, "access denied for [GetChannels]: Nil SignedProposal", res.Message)
 
 	args = [][]byte{[]byte("fooFunction"), []byte("testChainID")}
 	res = stub.MockInvoke("5", args)
@@ -195,6 +198,42 @@ func TestConfigerInvokeJoinChainWrongParams(t *testing.T) {
 	}
 }
 
+func TestConfigerInvokeJoinChainBySnapshot(t *testing.T) {
+	viper.Set("peer.fileSystemPath", "/tmp/hyperledgertest/")
+	os.Mkdir("/tmp/hyperledgertest", 0755)
+	defer os.RemoveAll("/tmp/hyperledgertest/")
+	ledgermgmt.InitializeTestEnv()
+	defer ledgermgmt.CleanupTestEnv()
+
+	e := New(nil, nil, mockAclProvider)
+	stub := shim.NewMockStub("PeerConfiger", e)
+	sProp := &pb.SignedProposal{}
+
+	// Failed path: no snapshot archive
+	args := [][]byte{[]byte(JoinChainBySnapshot), nil}
+	res := stub.MockInvokeWithSignedProposal("1", args, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Equal(t, "Cannot join the channel, no snapshot archive provided", res.Message)
+
+	// Failed path: the peer wide policy is checked
+	mockAclProvider.Reset()
+	mockAclProvider.On("CheckACL", resources.Cscc_JoinChain, "", sProp).Return(errors.New("Failed authorization"))
+	args = [][]byte{[]byte(JoinChainBySnapshot), []byte("not an archive")}
+	res = stub.MockInvokeWithSignedProposal("2", args, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Equal(t, "access denied for [JoinChainBySnapshot]: [Failed authorization]", res.Message)
+	mockAclProvider.AssertExpectations(t)
+
+	// Failed path: the archive is not a snapshot
+	mockAclProvider.Reset()
+	mockAclProvider.On("CheckACL", resources.Cscc_JoinChain, "", sProp).Return(nil)
+	res = stub.MockInvokeWithSignedProposal("3", args, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "cannot create ledger from snapshot")
+	assert.Contains(t, res.Message, "error reading the snapshot archive")
+	mockAclProvider.AssertExpectations(t)
+}
+
 func TestConfigerInvokeJoinChainCorrectParams(t *testing.T) {
 	mp := (&scc.MocksccProviderFactory{}).NewSystemChaincodeProvider()
 	ccp := &ccprovidermocks.MockCcProviderImpl{}
@@ -234,31 +273,7 @@ func TestConfigerInvokeJoinChainCorrectParams(t *testing.T) {
 		mp,
 		platforms.NewRegistry(&golang.Platform{}),
 		peer.DefaultSupport,
-	)
-
-	// Init the policy checker
-	policyManagerGetter := &policymocks.MockChannelPolicyManagerGetter{
-		Managers: map[string]policies.Manager{
-			"mytestchainid": &policymocks.MockChannelPolicyManager{
-				MockPolicy: &policymocks.MockPolicy{
-					Deserializer: &policymocks.MockIdentityDeserializer{
-						Identity: []byte("Alice"),
-						Msg:      []byte("msg1"),
-					},
-				},
-			},
-		},
-	}
-
-	identityDeserializer := &policymocks.MockIdentityDeserializer{
-		Identity: []byte("Alice"),
-		Msg:      []byte("msg1"),
-	}
-
-	e.policyChecker = policy.NewPolicyChecker(
-		policyManagerGetter,
-		identityDeserializer,
-		&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+		nil,
 	)
 
 	identity, _ := mgmt.GetLocalSigningIdentityOrPanic().Serialize()
@@ -274,8 +289,9 @@ func TestConfigerInvokeJoinChainCorrectParams(t *testing.T) {
 	}
 	args := [][]byte{[]byte("JoinChain"), blockBytes}
 	sProp, _ := utils.MockSignedEndorserProposalOrPanic("", &pb.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
-	identityDeserializer.Msg = sProp.ProposalBytes
 	sProp.Signature = sProp.ProposalBytes
+	mockAclProvider.Reset()
+	mockAclProvider.On("CheckACL", resources.Cscc_JoinChain, "", sProp).Return(nil)
 
 	// Try fail path with nil block
 	res := stub.MockInvokeWithSignedProposal("2", [][]byte{[]byte("JoinChain"), nil}, sProp)
@@ -301,13 +317,14 @@ func TestConfigerInvokeJoinChainCorrectParams(t *testing.T) {
 	}
 
 	// This call must fail
-	sProp.Signature = nil
+	mockAclProvider.Reset()
+	mockAclProvider.On("CheckACL", resources.Cscc_JoinChain, "", sProp).Return(errors.New("Failed authorization"))
 	res = stub.MockInvokeWithSignedProposal("3", args, sProp)
 	if res.Status == shim.OK {
 		t.Fatalf("cscc invoke JoinChain must fail : %v", res.Message)
 	}
-	assert.Contains(t, res.Message, "access denied for [JoinChain][mytestchainid]")
-	sProp.Signature = sProp.ProposalBytes
+	assert.Equal(t, "access denied for [JoinChain][mytestchainid]: [Failed authorization]", res.Message)
+	mockAclProvider.AssertExpectations(t)
 
 	// Query the configuration block
 	//chainID := []byte{143, 222, 22, 192, 73, 145, 76, 110, 167, 154, 118, 66, 132, 204, 113, 168}
@@ -335,6 +352,8 @@ func TestConfigerInvokeJoinChainCorrectParams(t *testing.T) {
 	}
 
 	// get channels for the peer
+	mockAclProvider.Reset()
+	mockAclProvider.On("CheckACL", resources.Cscc_GetChannels, "", sProp).Return(nil)
 	args = [][]byte{[]byte(GetChannels)}
 	res = stub.MockInvokeWithSignedProposal("2", args, sProp)
 	if res.Status != shim.OK {
@@ -408,6 +427,153 @@ func TestGetConfigTree(t *testing.T) {
 	})
 }
 
+// applicationChannelConfig returns the config of an application channel
+// ordered by the sample solo orderer
+func applicationChannelConfig(t *testing.T) *cb.Config {
+	conf := configtxgentest.Load(genesisconfig.SampleSingleMSPSoloProfile)
+	conf.Consortiums = nil
+	conf.Application = configtxgentest.Load(genesisconfig.SampleSingleMSPChannelProfile).Application
+	cg, err := encoder.NewChannelGroup(conf)
+	assert.NoError(t, err)
+	return &cb.Config{ChannelGroup: cg}
+}
+
+func TestGetChannelConfig(t *testing.T) {
+	aclProvider := &mock.ACLProvider{}
+	configMgr := &mock.ConfigManager{}
+	pc := &PeerConfiger{
+		aclProvider: aclProvider,
+		configMgr:   configMgr,
+	}
+
+	args := [][]byte{[]byte("GetChannelConfig"), []byte("testchan")}
+
+	t.Run("Success", func(t *testing.T) {
+		ctxv := &mock.ConfigtxValidator{}
+		configMgr.GetChannelConfigReturns(ctxv)
+		ctxv.ConfigProtoReturns(applicationChannelConfig(t))
+		res := pc.InvokeNoShim(args, nil)
+		assert.Equal(t, int32(shim.OK), res.Status)
+		config := map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal(res.Payload, &config))
+		// the values of the groups are decoded rather than left as bytes
+		assert.Contains(t, string(res.Payload), `"max_message_count"`)
+		assert.Contains(t, string(res.Payload), `"root_certs"`)
+	})
+
+	t.Run("MissingConfig", func(t *testing.T) {
+		ctxv := &mock.ConfigtxValidator{}
+		configMgr.GetChannelConfigReturns(ctxv)
+		res := pc.InvokeNoShim(args, nil)
+		assert.NotEqual(t, int32(shim.OK), res.Status)
+		assert.Equal(t, "Unknown chain ID, testchan", res.Message)
+	})
+
+	t.Run("NilChannel", func(t *testing.T) {
+		res := pc.InvokeNoShim([][]byte{[]byte("GetChannelConfig"), nil}, nil)
+		assert.NotEqual(t, int32(shim.OK), res.Status)
+		assert.Equal(t, "Chain ID must not be nil", res.Message)
+	})
+
+	t.Run("BadACL", func(t *testing.T) {
+		aclProvider.CheckACLReturns(fmt.Errorf("fake-error"))
+		res := pc.InvokeNoShim(args, nil)
+		assert.NotEqual(t, int32(shim.OK), res.Status)
+		assert.Equal(t, "access denied for [GetChannelConfig][testchan]: fake-error", res.Message)
+		resource, channel, _ := aclProvider.CheckACLArgsForCall(aclProvider.CheckACLCallCount() - 1)
+		assert.Equal(t, resources.Cscc_GetChannelConfig, resource)
+		assert.Equal(t, "testchan", channel)
+	})
+}
+
+func TestGetCapabilities(t *testing.T) {
+	aclProvider := &mock.ACLProvider{}
+	configMgr := &mock.ConfigManager{}
+	pc := &PeerConfiger{
+		aclProvider: aclProvider,
+		configMgr:   configMgr,
+	}
+
+	args := [][]byte{[]byte("GetCapabilities"), []byte("testchan")}
+
+	t.Run("Success", func(t *testing.T) {
+		ctxv := &mock.ConfigtxValidator{}
+		configMgr.GetChannelConfigReturns(ctxv)
+		ctxv.ConfigProtoReturns(applicationChannelConfig(t))
+		res := pc.InvokeNoShim(args, nil)
+		assert.Equal(t, int32(shim.OK), res.Status)
+		caps := &Capabilities{}
+		assert.NoError(t, json.Unmarshal(res.Payload, caps))
+		assert.Equal(t, []string{"V1_3"}, caps.Channel)
+		assert.Equal(t, []string{"V1_1"}, caps.Orderer)
+		assert.Equal(t, []string{"V1_3"}, caps.Application)
+		assert.True(t, caps.Features["private_channel_data"])
+		assert.True(t, caps.Features["key_level_endorsement"])
+	})
+
+	t.Run("AllFeatures", func(t *testing.T) {
+		// every feature gated by a capability is listed
+		cp := capabilities.NewChannelProvider(nil)
+		op := capabilities.NewOrdererProvider(nil)
+		ap := capabilities.NewApplicationProvider(nil)
+		count := 0
+		for _, provider := range []interface{}{cp, op, ap} {
+			providerType := reflect.TypeOf(provider)
+			for i := 0; i < providerType.NumMethod(); i++ {
+				method := providerType.Method(i).Type
+				if method.NumIn() == 1 && method.NumOut() == 1 && method.Out(0).Kind() == reflect.Bool {
+					count++
+				}
+			}
+		}
+		assert.Len(t, features(cp, op, ap), count)
+	})
+
+	t.Run("NoApplication", func(t *testing.T) {
+		config := applicationChannelConfig(t)
+		delete(config.ChannelGroup.Groups, channelconfig.ApplicationGroupKey)
+		ctxv := &mock.ConfigtxValidator{}
+		configMgr.GetChannelConfigReturns(ctxv)
+		ctxv.ConfigProtoReturns(config)
+		res := pc.InvokeNoShim(args, nil)
+		assert.Equal(t, int32(shim.OK), res.Status)
+		caps := &Capabilities{}
+		assert.NoError(t, json.Unmarshal(res.Payload, caps))
+		assert.Empty(t, caps.Application)
+		assert.False(t, caps.Features["private_channel_data"])
+	})
+
+	t.Run("BadCapabilities", func(t *testing.T) {
+		ctxv := &mock.ConfigtxValidator{}
+		configMgr.GetChannelConfigReturns(ctxv)
+		ctxv.ConfigProtoReturns(&cb.Config{
+			ChannelGroup: &cb.ConfigGroup{
+				Values: map[string]*cb.ConfigValue{
+					"Capabilities": {Value: []byte("garbage")},
+				},
+			},
+		})
+		res := pc.InvokeNoShim(args, nil)
+		assert.NotEqual(t, int32(shim.OK), res.Status)
+		assert.Contains(t, res.Message, "failed unmarshaling capabilities")
+	})
+
+	t.Run("MissingConfig", func(t *testing.T) {
+		ctxv := &mock.ConfigtxValidator{}
+		configMgr.GetChannelConfigReturns(ctxv)
+		res := pc.InvokeNoShim(args, nil)
+		assert.NotEqual(t, int32(shim.OK), res.Status)
+		assert.Equal(t, "Unknown chain ID, testchan", res.Message)
+	})
+
+	t.Run("BadACL", func(t *testing.T) {
+		aclProvider.CheckACLReturns(fmt.Errorf("fake-error"))
+		res := pc.InvokeNoShim(args, nil)
+		assert.NotEqual(t, int32(shim.OK), res.Status)
+		assert.Equal(t, "access denied for [GetCapabilities][testchan]: fake-error", res.Message)
+	})
+}
+
 func TestSimulateConfigTreeUpdate(t *testing.T) {
 	aclProvider := &mock.ACLProvider{}
 	configMgr := &mock.ConfigManager{}
@@ -490,6 +656,79 @@ func TestSimulateConfigTreeUpdate(t *testing.T) {
 	})
 }
 
+func TestGetExpiringIdentities(t *testing.T) {
+	aclProvider := &mock.ACLProvider{}
+	configMgr := &mock.ConfigManager{}
+	pc := &PeerConfiger{
+		aclProvider: aclProvider,
+		configMgr:   configMgr,
+	}
+
+	args := [][]byte{[]byte("GetExpiringIdentities"), []byte("testchan")}
+	ctxv := &mock.ConfigtxValidator{}
+	configMgr.GetChannelConfigReturns(ctxv)
+	ctxv.ConfigProtoReturns(applicationChannelConfig(t))
+
+	t.Run("Success", func(t *testing.T) {
+		res := pc.InvokeNoShim(append(args, []byte("876000h")), nil)
+		assert.Equal(t, int32(shim.OK), res.Status)
+		expiring := []*ExpiringIdentity{}
+		assert.NoError(t, json.Unmarshal(res.Payload, &expiring))
+
+		// the SampleOrg MSP of both the orderer and the application is listed once
+		var roles []string
+		for i, id := range expiring {
+			assert.Equal(t, "SampleOrg", id.MSPID)
+			assert.NotEmpty(t, id.Subject)
+			if i > 0 {
+				assert.False(t, id.ExpiresAt.Before(expiring[i-1].ExpiresAt), "identities not sorted by expiration")
+			}
+			roles = append(roles, id.Role)
+		}
+		assert.ElementsMatch(t, []string{"root_cert", "admin", "tls_root_cert", "tls_intermediate_cert"}, roles)
+	})
+
+	t.Run("NoneWithinWindow", func(t *testing.T) {
+		res := pc.InvokeNoShim(append(args, []byte("-876000h")), nil)
+		assert.Equal(t, int32(shim.OK), res.Status)
+		assert.Equal(t, "[]", string(res.Payload))
+	})
+
+	t.Run("BadWindow", func(t *testing.T) {
+		res := pc.InvokeNoShim(append(args, []byte("a month")), nil)
+		assert.NotEqual(t, int32(shim.OK), res.Status)
+		assert.Contains(t, res.Message, "Invalid expiry window a month")
+	})
+
+	t.Run("BadMSP", func(t *testing.T) {
+		config := applicationChannelConfig(t)
+		org := config.ChannelGroup.Groups[channelconfig.ApplicationGroupKey].Groups["SampleOrg"]
+		org.Values[channelconfig.MSPKey].Value = []byte("garbage")
+		ctxv := &mock.ConfigtxValidator{}
+		configMgr.GetChannelConfigReturns(ctxv)
+		ctxv.ConfigProtoReturns(config)
+		res := pc.InvokeNoShim(args, nil)
+		assert.NotEqual(t, int32(shim.OK), res.Status)
+		assert.Contains(t, res.Message, "failed reading the MSP of organization SampleOrg")
+	})
+
+	t.Run("MissingConfig", func(t *testing.T) {
+		configMgr.GetChannelConfigReturns(&mock.ConfigtxValidator{})
+		res := pc.InvokeNoShim(args, nil)
+		assert.NotEqual(t, int32(shim.OK), res.Status)
+		assert.Equal(t, "Unknown chain ID, testchan", res.Message)
+	})
+
+	t.Run("BadACL", func(t *testing.T) {
+		aclProvider.CheckACLReturns(fmt.Errorf("fake-error"))
+		res := pc.InvokeNoShim(args, nil)
+		assert.NotEqual(t, int32(shim.OK), res.Status)
+		assert.Equal(t, "access denied for [GetExpiringIdentities][testchan]: fake-error", res.Message)
+		resource, _, _ := aclProvider.CheckACLArgsForCall(aclProvider.CheckACLCallCount() - 1)
+		assert.Equal(t, resources.Cscc_GetExpiringIdentities, resource)
+	})
+}
+
 func TestPeerConfiger_SubmittingOrdererGenesis(t *testing.T) {
 	viper.Set("peer.fileSystemPath", "/tmp/hyperledgertest/")
 	os.Mkdir("/tmp/hyperledgertest", 0755)
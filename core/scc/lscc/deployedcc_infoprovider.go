@@ -40,6 +40,11 @@ func (p *DeployedCCInfoProvider) UpdatedChaincodes(stateUpdates map[string][]*kv
 			// lscc namespace is not expected to have deletes
 			continue
 		}
+		// The migrations of the chaincode states, which also contain the
+		// CollectionSeparator, don't change the chaincode definitions
+		if ccprovider.IsMigrationKey(kvWrite.Key) {
+			continue
+		}
 		// There are LSCC entries for the chaincode and for the chaincode collections.
 		// We can detect collections based on the presence of a CollectionSeparator,
 		// which never exists in chaincode names.
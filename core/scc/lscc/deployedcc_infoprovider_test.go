@@ -16,6 +16,7 @@ import (
 	"github.com/hyperledger/fabric/core/scc/lscc"
 	"github.com/hyperledger/fabric/core/scc/lscc/mock"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -26,6 +27,19 @@ func TestNamespaces(t *testing.T) {
 	assert.Equal(t, "lscc", namespaces[0])
 }
 
+func TestUpdatedChaincodes(t *testing.T) {
+	ccInfoProvdier := &lscc.DeployedCCInfoProvider{}
+	updates, err := ccInfoProvdier.UpdatedChaincodes(map[string][]*kvrwset.KVWrite{
+		"lscc": {
+			{Key: "cc1", Value: []byte("cc1_data")},
+			{Key: "cc2~collection", Value: []byte("cc2_collections")},
+			{Key: ccprovider.BuildMigrationKVSKey("cc3"), Value: []byte("cc3_version")},
+		},
+	})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []*ledger.ChaincodeLifecycleInfo{{Name: "cc1"}, {Name: "cc2"}}, updates)
+}
+
 func TestChaincodeInfo(t *testing.T) {
 	cc1 := &ledger.DeployedChaincodeInfo{
 		Name:    "cc1",
@@ -12,6 +12,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/aclmgmt"
 	"github.com/hyperledger/fabric/core/aclmgmt/resources"
@@ -23,9 +24,9 @@ import (
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/core/peer"
-	"github.com/hyperledger/fabric/core/policy"
-	"github.com/hyperledger/fabric/core/policyprovider"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/protos/common"
@@ -92,6 +93,12 @@ const (
 	// GETCOLLECTIONSCONFIGALIAS gets the collections config for a chaincode
 	GETCOLLECTIONSCONFIGALIAS = "getcollectionsconfig"
 
+	// GETMIGRATION gets the version the state of a chaincode was migrated to
+	GETMIGRATION = "GetMigration"
+
+	// GETMIGRATIONALIAS gets the version the state of a chaincode was migrated to
+	GETMIGRATIONALIAS = "getmigration"
+
 	allowedChaincodeName = "^[a-zA-Z0-9]+([-_][a-zA-Z0-9]+)*$"
 	allowedCharsVersion  = "[A-Za-z0-9_.+-]+"
 )
@@ -117,6 +124,16 @@ type FilesystemSupport interface {
 	// CheckInstantiationPolicy checks whether the supplied signed proposal
 	// complies with the supplied instantiation policy
 	CheckInstantiationPolicy(signedProposal *pb.SignedProposal, chainName string, instantiationPolicy []byte) error
+
+	// CheckInstallPolicy checks whether the owner endorsements of the
+	// supplied chaincode package comply with the install policy of the peer
+	CheckInstallPolicy(ccpack ccprovider.CCPackage) error
+}
+
+// ChaincodeAnalyzer analyzes the code of the chaincodes installed on the peer
+type ChaincodeAnalyzer interface {
+	// Analyze returns an error if the chaincode must not be installed
+	Analyze(cds *pb.ChaincodeDeploymentSpec) error
 }
 
 //---------- the LSCC -----------------
@@ -130,15 +147,15 @@ type LifeCycleSysCC struct {
 	// to access other parts of the system
 	SCCProvider sysccprovider.SystemChaincodeProvider
 
-	// PolicyChecker is the interface used to perform
-	// access control
-	PolicyChecker policy.PolicyChecker
-
 	// Support provides the implementation of several
 	// static functions
 	Support FilesystemSupport
 
 	PlatformRegistry *platforms.Registry
+
+	// Analyzer, if set, analyzes the code of the chaincodes before they
+	// are installed
+	Analyzer ChaincodeAnalyzer
 }
 
 // New creates a new instance of the LSCC
@@ -146,7 +163,6 @@ type LifeCycleSysCC struct {
 func New(sccp sysccprovider.SystemChaincodeProvider, ACLProvider aclmgmt.ACLProvider, platformRegistry *platforms.Registry) *LifeCycleSysCC {
 	return &LifeCycleSysCC{
 		Support:          &supportImpl{},
-		PolicyChecker:    policyprovider.GetPolicyChecker(),
 		SCCProvider:      sccp,
 		ACLProvider:      ACLProvider,
 		PlatformRegistry: platformRegistry,
@@ -304,6 +320,103 @@ func checkCollectionMemberPolicy(collectionConfig *common.CollectionConfig, mspm
 	return nil
 }
 
+// existingCollections returns the static configurations of the collections of the
+// given chaincode defined before the current transaction, indexed by name
+func existingCollections(stub shim.ChaincodeStubInterface, chaincodeName string) (map[string]*common.StaticCollectionConfig, error) {
+	existingCollectionsBytes, err := stub.GetState(privdata.BuildCollectionKVSKey(chaincodeName))
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("error getting the existing collections of chaincode %s", chaincodeName))
+	}
+	existing := make(map[string]*common.StaticCollectionConfig)
+	if len(existingCollectionsBytes) == 0 {
+		return existing, nil
+	}
+	existingCollections := &common.CollectionConfigPackage{}
+	if err := proto.Unmarshal(existingCollectionsBytes, existingCollections); err != nil {
+		return nil, errors.Wrapf(err, "invalid existing collection configuration of chaincode %s", chaincodeName)
+	}
+	for _, collectionConfig := range existingCollections.Config {
+		coll := collectionConfig.GetStaticCollectionConfig()
+		existing[coll.GetName()] = coll
+	}
+	return existing, nil
+}
+
+// checkCollectionStateDatabases checks that the state databases requested by the
+// collections are known and, upon an upgrade, that those of the existing
+// collections are unchanged, their private data being stored there already
+func checkCollectionStateDatabases(stub shim.ChaincodeStubInterface, chaincodeName string, collections *common.CollectionConfigPackage) error {
+	for _, collectionConfig := range collections.Config {
+		switch stateDatabase := collectionConfig.GetStaticCollectionConfig().GetStateDatabase(); stateDatabase {
+		case "", ledgerconfig.StateDatabaseLevelDB, ledgerconfig.StateDatabaseCouchDB:
+		default:
+			return errors.Errorf("collection-name: %s -- unknown state database %s", collectionConfig.GetStaticCollectionConfig().GetName(), stateDatabase)
+		}
+	}
+
+	existing, err := existingCollections(stub, chaincodeName)
+	if err != nil {
+		return err
+	}
+	for _, collectionConfig := range collections.Config {
+		coll := collectionConfig.GetStaticCollectionConfig()
+		existingColl, ok := existing[coll.GetName()]
+		if ok && existingColl.GetStateDatabase() != coll.GetStateDatabase() {
+			return errors.Errorf("collection-name: %s -- the state database cannot be modified from [%s] to [%s]", coll.GetName(), existingColl.GetStateDatabase(), coll.GetStateDatabase())
+		}
+	}
+	return nil
+}
+
+// checkCollectionValueCommitments checks that the value commitment schemes of the
+// collections are known and, upon an upgrade, that those of the existing
+// collections are unchanged, their value hashes being committed already
+func checkCollectionValueCommitments(stub shim.ChaincodeStubInterface, chaincodeName string, collections *common.CollectionConfigPackage) error {
+	for _, collectionConfig := range collections.Config {
+		switch valueCommitment := collectionConfig.GetStaticCollectionConfig().GetValueCommitment(); valueCommitment {
+		case "", ledgerUtil.ValueCommitmentPedersen:
+		default:
+			return errors.Errorf("collection-name: %s -- unknown value commitment %s", collectionConfig.GetStaticCollectionConfig().GetName(), valueCommitment)
+		}
+	}
+
+	existing, err := existingCollections(stub, chaincodeName)
+	if err != nil {
+		return err
+	}
+	for _, collectionConfig := range collections.Config {
+		coll := collectionConfig.GetStaticCollectionConfig()
+		existingColl, ok := existing[coll.GetName()]
+		if ok && existingColl.GetValueCommitment() != coll.GetValueCommitment() {
+			return errors.Errorf("collection-name: %s -- the value commitment cannot be modified from [%s] to [%s]", coll.GetName(), existingColl.GetValueCommitment(), coll.GetValueCommitment())
+		}
+	}
+	return nil
+}
+
+// checkCollectionCapabilities checks that the collections only use the features
+// enabled by the capabilities of the channel. The invalid configurations are
+// rejected when the collection data is put.
+func checkCollectionCapabilities(collectionConfigBytes []byte, ac channelconfig.ApplicationCapabilities) error {
+	if len(collectionConfigBytes) == 0 {
+		return nil
+	}
+	collections := &common.CollectionConfigPackage{}
+	if err := proto.Unmarshal(collectionConfigBytes, collections); err != nil {
+		return nil
+	}
+	for _, collectionConfig := range collections.Config {
+		coll := collectionConfig.GetStaticCollectionConfig()
+		if coll.GetEphemeral() && !ac.EphemeralCollections() {
+			return errors.Errorf("collection-name: %s -- ephemeral collections are not enabled by the capabilities of the channel", coll.GetName())
+		}
+		if coll.GetValueCommitment() != "" && !ac.ValueCommitments() {
+			return errors.Errorf("collection-name: %s -- value commitments are not enabled by the capabilities of the channel", coll.GetName())
+		}
+	}
+	return nil
+}
+
 // putChaincodeCollectionData adds collection data for the chaincode
 func (lscc *LifeCycleSysCC) putChaincodeCollectionData(stub shim.ChaincodeStubInterface, cd *ccprovider.ChaincodeData, collectionConfigBytes []byte) error {
 	if cd == nil {
@@ -331,6 +444,12 @@ func (lscc *LifeCycleSysCC) putChaincodeCollectionData(stub shim.ChaincodeStubIn
 			return errors.Wrapf(err, "collection member policy check failed")
 		}
 	}
+	if err := checkCollectionStateDatabases(stub, cd.Name, collections); err != nil {
+		return errors.WithMessage(err, "collection state database check failed")
+	}
+	if err := checkCollectionValueCommitments(stub, cd.Name, collections); err != nil {
+		return errors.WithMessage(err, "collection value commitment check failed")
+	}
 
 	key := privdata.BuildCollectionKVSKey(cd.Name)
 
@@ -355,6 +474,19 @@ func (lscc *LifeCycleSysCC) getChaincodeCollectionData(stub shim.ChaincodeStubIn
 	return shim.Success(collectionsConfigBytes)
 }
 
+// getChaincodeMigration returns the version the state of the chaincode was
+// migrated to by its Migrate function, empty if it never was
+func (lscc *LifeCycleSysCC) getChaincodeMigration(stub shim.ChaincodeStubInterface, chaincodeName string) pb.Response {
+	if _, err := lscc.getCCInstance(stub, chaincodeName); err != nil {
+		return shim.Error(err.Error())
+	}
+	migratedVersion, err := stub.GetState(ccprovider.BuildMigrationKVSKey(chaincodeName))
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	return shim.Success(migratedVersion)
+}
+
 //checks for existence of chaincode on the given channel
 func (lscc *LifeCycleSysCC) getCCInstance(stub shim.ChaincodeStubInterface, ccname string) ([]byte, error) {
 	cdbytes, err := stub.GetState(ccname)
@@ -429,8 +561,8 @@ func (lscc *LifeCycleSysCC) getChaincodes(stub shim.ChaincodeStubInterface) pb.R
 			return shim.Error(err.Error())
 		}
 
-		// CollectionConfig isn't ChaincodeData
-		if privdata.IsCollectionConfigKey(response.Key) {
+		// CollectionConfig and the migrated versions aren't ChaincodeData
+		if ccprovider.IsMigrationKey(response.Key) || privdata.IsCollectionConfigKey(response.Key) {
 			continue
 		}
 
@@ -555,6 +687,21 @@ func isValidStatedbArtifactsTar(statedbArtifactsTar []byte) error {
 	return nil
 }
 
+// validStatedbArtifacts extracts the statedb artifacts of the given chaincode
+// package and checks that they are valid
+func (lscc *LifeCycleSysCC) validStatedbArtifacts(ccpack ccprovider.CCPackage) ([]byte, error) {
+	statedbArtifactsTar, err := ccprovider.ExtractStatedbArtifactsFromCCPackage(ccpack, lscc.PlatformRegistry)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = isValidStatedbArtifactsTar(statedbArtifactsTar); err != nil {
+		return nil, InvalidStatedbArtifactsErr(err.Error())
+	}
+
+	return statedbArtifactsTar, nil
+}
+
 // executeInstall implements the "install" Invoke transaction
 func (lscc *LifeCycleSysCC) executeInstall(stub shim.ChaincodeStubInterface, ccbytes []byte) error {
 	ccpack, err := ccprovider.GetCCPackage(ccbytes)
@@ -580,14 +727,21 @@ func (lscc *LifeCycleSysCC) executeInstall(stub shim.ChaincodeStubInterface, ccb
 		return errors.Errorf("cannot install: %s is the name of a system chaincode", cds.ChaincodeSpec.ChaincodeId.Name)
 	}
 
-	// Get any statedb artifacts from the chaincode package, e.g. couchdb index definitions
-	statedbArtifactsTar, err := ccprovider.ExtractStatedbArtifactsFromCCPackage(ccpack, lscc.PlatformRegistry)
-	if err != nil {
+	// the peer only runs the code approved by the owners its install policy requires
+	if err = lscc.Support.CheckInstallPolicy(ccpack); err != nil {
 		return err
 	}
 
-	if err = isValidStatedbArtifactsTar(statedbArtifactsTar); err != nil {
-		return InvalidStatedbArtifactsErr(err.Error())
+	if lscc.Analyzer != nil {
+		if err = lscc.Analyzer.Analyze(cds); err != nil {
+			return err
+		}
+	}
+
+	// Get any statedb artifacts from the chaincode package, e.g. couchdb index definitions
+	statedbArtifactsTar, err := lscc.validStatedbArtifacts(ccpack)
+	if err != nil {
+		return err
 	}
 
 	chaincodeDefinition := &cceventmgmt.ChaincodeDefinition{
@@ -646,6 +800,12 @@ func (lscc *LifeCycleSysCC) executeDeployOrUpgrade(
 	}
 	cd := ccpack.GetChaincodeData()
 
+	// the package may have been installed before its statedb artifacts were
+	// validated as strictly, check them again rather than failing at first query
+	if _, err := lscc.validStatedbArtifacts(ccpack); err != nil {
+		return nil, err
+	}
+
 	switch function {
 	case DEPLOY:
 		return lscc.executeDeploy(stub, chainname, cds, policy, escc, vscc, cd, ccpack, collectionConfigBytes)
@@ -822,8 +982,8 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 			return shim.Error(InvalidArgsLenErr(len(args)).Error())
 		}
 
-		// 2. check local MSP Admins policy
-		if err = lscc.PolicyChecker.CheckPolicyNoChannel(mgmt.Admins, sp); err != nil {
+		// 2. check the peer wide policy
+		if err = lscc.ACLProvider.CheckACL(resources.Lscc_Install, "", sp); err != nil {
 			return shim.Error(fmt.Sprintf("access denied for [%s]: %s", function, err))
 		}
 
@@ -904,6 +1064,9 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 		if ac.Capabilities().PrivateChannelData() && len(args) > 6 {
 			collectionsConfig = args[6]
 		}
+		if err := checkCollectionCapabilities(collectionsConfig, ac.Capabilities()); err != nil {
+			return shim.Error(err.Error())
+		}
 
 		cd, err := lscc.executeDeployOrUpgrade(stub, channel, cds, EP, escc, vscc, collectionsConfig, function)
 		if err != nil {
@@ -975,8 +1138,8 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 			return shim.Error(InvalidArgsLenErr(len(args)).Error())
 		}
 
-		// 2. check local MSP Admins policy
-		if err = lscc.PolicyChecker.CheckPolicyNoChannel(mgmt.Admins, sp); err != nil {
+		// 2. check the peer wide policy
+		if err = lscc.ACLProvider.CheckACL(resources.Lscc_GetInstalledChaincodes, "", sp); err != nil {
 			return shim.Error(fmt.Sprintf("access denied for [%s]: %s", function, err))
 		}
 
@@ -995,6 +1158,18 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 		}
 
 		return lscc.getChaincodeCollectionData(stub, chaincodeName)
+	case GETMIGRATION, GETMIGRATIONALIAS:
+		if len(args) != 2 {
+			return shim.Error(InvalidArgsLenErr(len(args)).Error())
+		}
+
+		chaincodeName := string(args[1])
+
+		if err = lscc.ACLProvider.CheckACL(resources.Lscc_GetChaincodeData, stub.GetChannelID(), sp); err != nil {
+			return shim.Error(fmt.Sprintf("access denied for [%s]: %s", function, err))
+		}
+
+		return lscc.getChaincodeMigration(stub, chaincodeName)
 	}
 
 	return shim.Error(InvalidFunctionErr(function).Error())
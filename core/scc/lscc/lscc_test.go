@@ -19,19 +19,17 @@ import (
 	"github.com/hyperledger/fabric/common/cauthdsl"
 	"github.com/hyperledger/fabric/common/mocks/config"
 	mscc "github.com/hyperledger/fabric/common/mocks/scc"
-	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/aclmgmt/mocks"
 	"github.com/hyperledger/fabric/core/aclmgmt/resources"
 	"github.com/hyperledger/fabric/core/chaincode/platforms"
 	"github.com/hyperledger/fabric/core/chaincode/platforms/golang"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/ccpackage"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	cutil "github.com/hyperledger/fabric/core/container/util"
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
 	"github.com/hyperledger/fabric/core/mocks/scc/lscc"
-	"github.com/hyperledger/fabric/core/policy"
-	policymocks "github.com/hyperledger/fabric/core/policy/mocks"
 	"github.com/hyperledger/fabric/core/scc/lscc/mock"
 	"github.com/hyperledger/fabric/msp"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
@@ -44,6 +42,7 @@ import (
 	"github.com/hyperledger/fabric/protos/utils"
 	putils "github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -142,28 +141,29 @@ func TestInstall(t *testing.T) {
 	testInstall(t, "example02-2", "1.0-alpha+001", path, false, "", "Alice", scc, stub)
 	testInstall(t, "example02-2", "1.0+sha.c0ffee", path, false, "", "Alice", scc, stub)
 
+	scc.Support.(*lscc.MockSupport).CheckInstallPolicyErr = errors.New("install policy violation")
+	testInstall(t, "example02", "0", path, false, "install policy violation", "Alice", scc, stub)
+	scc.Support.(*lscc.MockSupport).CheckInstallPolicyErr = nil
+
+	scc.Analyzer = analyzerFunc(func(cds *pb.ChaincodeDeploymentSpec) error {
+		return errors.Errorf("chaincode %s rejected by the static analysis", cds.ChaincodeSpec.ChaincodeId.Name)
+	})
+	testInstall(t, "example02", "0", path, false, "chaincode example02 rejected by the static analysis", "Alice", scc, stub)
+	scc.Analyzer = nil
+
 	scc.Support.(*lscc.MockSupport).PutChaincodeToLocalStorageErr = errors.New("barf")
 
 	testInstall(t, "example02", "0", path, false, "barf", "Alice", scc, stub)
 	testInstall(t, "lscc", "0", path, false, "cannot install: lscc is the name of a system chaincode", "Alice", scc, stub)
 }
 
-func testInstall(t *testing.T, ccname string, version string, path string, createInvalidIndex bool, expectedErrorMsg string, caller string, scc *LifeCycleSysCC, stub *shim.MockStub) {
-	identityDeserializer := &policymocks.MockIdentityDeserializer{
-		Identity: []byte("Alice"),
-		Msg:      []byte("msg1"),
-	}
-	policyManagerGetter := &policymocks.MockChannelPolicyManagerGetter{
-		Managers: map[string]policies.Manager{
-			"test": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
-		},
-	}
-	scc.PolicyChecker = policy.NewPolicyChecker(
-		policyManagerGetter,
-		identityDeserializer,
-		&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
-	)
+type analyzerFunc func(cds *pb.ChaincodeDeploymentSpec) error
+
+func (f analyzerFunc) Analyze(cds *pb.ChaincodeDeploymentSpec) error {
+	return f(cds)
+}
 
+func testInstall(t *testing.T, ccname string, version string, path string, createInvalidIndex bool, expectedErrorMsg string, caller string, scc *LifeCycleSysCC, stub *shim.MockStub) {
 	cds, err := constructDeploymentSpec(ccname, path, version, [][]byte{[]byte("init"), []byte("a"), []byte("100"), []byte("b"), []byte("200")}, createInvalidIndex, false, scc)
 	assert.NoError(t, err)
 	cdsBytes := utils.MarshalOrPanic(cds)
@@ -172,9 +172,15 @@ func testInstall(t *testing.T, ccname string, version string, path string, creat
 	args := [][]byte{[]byte("install"), cdsBytes}
 
 	sProp, _ := utils.MockSignedEndorserProposalOrPanic("", &pb.ChaincodeSpec{}, []byte(caller), []byte("msg1"))
-	identityDeserializer.Msg = sProp.ProposalBytes
 	sProp.Signature = sProp.ProposalBytes
 
+	mockAclProvider.Reset()
+	if caller == "Alice" {
+		mockAclProvider.On("CheckACL", resources.Lscc_Install, "", sProp).Return(nil)
+	} else {
+		mockAclProvider.On("CheckACL", resources.Lscc_Install, "", sProp).Return(errors.New("Failed authorization"))
+	}
+
 	if expectedErrorMsg == "" {
 		res := stub.MockInvokeWithSignedProposal("1", args, sProp)
 		assert.Equal(t, int32(shim.OK), res.Status, res.Message)
@@ -184,6 +190,68 @@ func testInstall(t *testing.T, ccname string, version string, path string, creat
 	}
 }
 
+func TestCheckInstallPolicy(t *testing.T) {
+	defer viper.Set("chaincode.installPolicy.policy", "")
+	defer viper.Set("chaincode.installPolicy.channel", "")
+
+	support := &supportImpl{}
+	mspid, err := mspmgmt.GetLocalMSP().GetIdentifier()
+	assert.NoError(t, err)
+	cds := &pb.ChaincodeDeploymentSpec{ChaincodeSpec: &pb.ChaincodeSpec{Type: 1, ChaincodeId: &pb.ChaincodeID{Name: "example02", Version: "0"}}, CodePackage: []byte("code")}
+	instPolicy := cauthdsl.SignedByAnyAdmin([]string{mspid})
+	newPackage := func(owner msp.SigningIdentity) ccprovider.CCPackage {
+		env, err := ccpackage.OwnerCreateSignedCCDepSpec(cds, instPolicy, owner)
+		assert.NoError(t, err)
+		ccpack, err := ccprovider.GetCCPackage(utils.MarshalOrPanic(env))
+		assert.NoError(t, err)
+		return ccpack
+	}
+	rawPackage, err := ccprovider.GetCCPackage(utils.MarshalOrPanic(cds))
+	assert.NoError(t, err)
+	signedPackage := newPackage(id)
+
+	// any package is installed without install policy
+	assert.NoError(t, support.CheckInstallPolicy(rawPackage))
+	assert.NoError(t, support.CheckInstallPolicy(newPackage(nil)))
+
+	viper.Set("chaincode.installPolicy.policy", "AND('"+mspid+".admin')")
+	assert.NoError(t, support.CheckInstallPolicy(signedPackage))
+	assert.EqualError(t, support.CheckInstallPolicy(rawPackage), "install policy violation: the chaincode package is not signed by its owners")
+	assert.EqualError(t, support.CheckInstallPolicy(newPackage(nil)), "install policy violation: the chaincode package has no owner endorsement")
+
+	// the signature must match the package
+	tampered := newPackage(id).(*ccprovider.SignedCDSPackage)
+	sd := tampered.GetOwnerSignedData()[0]
+	tamperedEnv, err := ccpackage.OwnerCreateSignedCCDepSpec(cds, cauthdsl.SignedByAnyMember([]string{mspid}), nil)
+	assert.NoError(t, err)
+	_, sdepspec, err := ccpackage.ExtractSignedCCDepSpec(tamperedEnv)
+	assert.NoError(t, err)
+	sdepspec.OwnerEndorsements = []*pb.Endorsement{{Endorser: sd.Identity, Signature: sd.Signature}}
+	payload := utils.UnmarshalPayloadOrPanic(tamperedEnv.Payload)
+	payload.Data = utils.MarshalOrPanic(sdepspec)
+	tamperedEnv.Payload = utils.MarshalOrPanic(payload)
+	tamperedPackage, err := ccprovider.GetCCPackage(utils.MarshalOrPanic(tamperedEnv))
+	assert.NoError(t, err)
+	err = support.CheckInstallPolicy(tamperedPackage)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "install policy violation")
+
+	// the package must be signed by the owners required by the policy
+	viper.Set("chaincode.installPolicy.policy", "AND('"+mspid+".admin', 'OtherMSP.admin')")
+	err = support.CheckInstallPolicy(signedPackage)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "install policy violation")
+
+	viper.Set("chaincode.installPolicy.policy", "AND('"+mspid)
+	err = support.CheckInstallPolicy(signedPackage)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid chaincode install policy")
+
+	viper.Set("chaincode.installPolicy.policy", "/Channel/Application/Admins")
+	viper.Set("chaincode.installPolicy.channel", "nonexistent")
+	assert.EqualError(t, support.CheckInstallPolicy(signedPackage), "error checking chaincode install policy: policy manager for channel nonexistent not found")
+}
+
 func TestDeploy(t *testing.T) {
 	path := "github.com/hyperledger/fabric/examples/chaincode/go/example02/cmd"
 
@@ -241,18 +309,34 @@ func TestDeploy(t *testing.T) {
 	res = stub.MockInit("1", nil)
 	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
 
+	// the installed package has an invalid index definition
+	cds, err := constructDeploymentSpec("example02", path, "1.0", [][]byte{[]byte("init")}, true, true, scc)
+	assert.NoError(t, err)
+	sProp, _ := putils.MockSignedEndorserProposal2OrPanic(chainid, &pb.ChaincodeSpec{}, id)
+	res = stub.MockInvokeWithSignedProposal("1", [][]byte{[]byte("deploy"), []byte("test"), utils.MarshalOrPanic(cds)}, sProp)
+	assert.NotEqual(t, int32(shim.OK), res.Status)
+	assert.Equal(t, "invalid state database artifact: Index metadata file [META-INF/statedb/couchdb/indexes/badIndex.json] is not a valid JSON: "+
+		"invalid character 'i' looking for beginning of value at line 1, column 1", res.Message)
+
+	scc = New(NewMockProvider(), mockAclProvider, platforms.NewRegistry(&golang.Platform{}))
+	scc.Support = &lscc.MockSupport{}
+	stub = shim.NewMockStub("lscc", scc)
+	res = stub.MockInit("1", nil)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+
 	// As the PrivateChannelData is disabled, the following error message is expected due to the presence of
 	// collectionConfigBytes in the stub.args
 	errMessage := InvalidArgsLenErr(7).Error()
 	testDeploy(t, "example02", "1.0", path, false, false, true, PrivateChannelDataNotAvailable("").Error(), scc, stub, []byte("collections"))
 
 	// Enable PrivateChannelData
+	appCapabilities := &config.MockApplicationCapabilities{
+		PrivateChannelDataRv: true,
+	}
 	mocksccProvider := (&mscc.MocksccProviderFactory{
 		ApplicationConfigBool: true,
 		ApplicationConfigRv: &config.MockApplication{
-			CapabilitiesRv: &config.MockApplicationCapabilities{
-				PrivateChannelDataRv: true,
-			},
+			CapabilitiesRv: appCapabilities,
 		},
 	}).NewSystemChaincodeProvider().(*mscc.MocksccProviderImpl)
 
@@ -305,6 +389,31 @@ func TestDeploy(t *testing.T) {
 	res = stub.MockInit("1", nil)
 	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
 
+	// As the ephemeral collections are not enabled, the deployment of an ephemeral collection fails
+	coll1.GetStaticCollectionConfig().Ephemeral = true
+	ephemeralCcpBytes, err := proto.Marshal(ccp)
+	assert.NoError(t, err)
+	coll1.GetStaticCollectionConfig().Ephemeral = false
+	testDeploy(t, "example02", "1.0", path, false, false, true, "collection-name: mycollection1 -- ephemeral collections are not enabled by the capabilities of the channel", scc, stub, ephemeralCcpBytes)
+
+	scc = New(mocksccProvider, mockAclProvider, platforms.NewRegistry(&golang.Platform{}))
+	scc.Support = &lscc.MockSupport{}
+	stub = shim.NewMockStub("lscc", scc)
+	res = stub.MockInit("1", nil)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+
+	// As the ephemeral collections are enabled, the ephemeral collection is deployed
+	appCapabilities.EphemeralCollectionsRv = true
+	testDeploy(t, "example02", "1.0", path, false, false, true, "", scc, stub, ephemeralCcpBytes)
+	appCapabilities.EphemeralCollectionsRv = false
+	assert.Equal(t, ephemeralCcpBytes, stub.State["example02~collection"])
+
+	scc = New(mocksccProvider, mockAclProvider, platforms.NewRegistry(&golang.Platform{}))
+	scc.Support = &lscc.MockSupport{}
+	stub = shim.NewMockStub("lscc", scc)
+	res = stub.MockInit("1", nil)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+
 	// As the PrivateChannelData is enabled and collectionConfigBytes is nil, no error is expected
 	testDeploy(t, "example02", "1.0", path, false, false, true, "", scc, stub, []byte("nil"))
 	// Should contain an entry for the chaincodeData only. As the collectionConfigBytes is nil, it
@@ -346,19 +455,7 @@ func testDeploy(t *testing.T, ccname string, version string, path string, forceB
 	}
 	stub.ChannelID = chainid
 
-	identityDeserializer := &policymocks.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
-	policyManagerGetter := &policymocks.MockChannelPolicyManagerGetter{
-		Managers: map[string]policies.Manager{
-			"test": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
-		},
-	}
-	scc.PolicyChecker = policy.NewPolicyChecker(
-		policyManagerGetter,
-		identityDeserializer,
-		&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
-	)
 	sProp, _ := utils.MockSignedEndorserProposalOrPanic(chainid, &pb.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
-	identityDeserializer.Msg = sProp.ProposalBytes
 	sProp.Signature = sProp.ProposalBytes
 
 	cds, err := constructDeploymentSpec(ccname, path, version, [][]byte{[]byte("init"), []byte("a"), []byte("100"), []byte("b"), []byte("200")}, false, install, scc)
@@ -641,19 +738,7 @@ func TestFunctionsWithAliases(t *testing.T) {
 	res := stub.MockInit("1", nil)
 	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
 
-	identityDeserializer := &policymocks.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
-	policyManagerGetter := &policymocks.MockChannelPolicyManagerGetter{
-		Managers: map[string]policies.Manager{
-			"test": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
-		},
-	}
-	scc.PolicyChecker = policy.NewPolicyChecker(
-		policyManagerGetter,
-		identityDeserializer,
-		&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
-	)
 	sProp, _ := utils.MockSignedEndorserProposalOrPanic("", &pb.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
-	identityDeserializer.Msg = sProp.ProposalBytes
 	sProp.Signature = sProp.ProposalBytes
 
 	testInvoke := func(function, resource string) {
@@ -723,6 +808,7 @@ func TestGetChaincodesFilter(t *testing.T) {
 	results := []*queryresult.KV{
 		{Key: "one", Value: utils.MarshalOrPanic(&ccprovider.ChaincodeData{Name: "name-one", Version: "1.0", Escc: "escc", Vscc: "vscc"})},
 		{Key: "something~collections", Value: []byte("completely-ignored")},
+		{Key: "one~migration", Value: []byte("1.0")},
 		{Key: "two", Value: utils.MarshalOrPanic(&ccprovider.ChaincodeData{Name: "name-two", Version: "2.0", Escc: "escc-2", Vscc: "vscc-2"})},
 	}
 	for i, r := range results {
@@ -759,28 +845,19 @@ func TestGetInstalledChaincodes(t *testing.T) {
 			assert.NotEqual(t, int32(shim.OK), res.Status)
 			assert.Equal(t, "invalid number of arguments to lscc: 2", res.Message)
 
-			identityDeserializer := &policymocks.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
-			policyManagerGetter := &policymocks.MockChannelPolicyManagerGetter{
-				Managers: map[string]policies.Manager{
-					"test": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
-				},
-			}
-			scc.PolicyChecker = policy.NewPolicyChecker(
-				policyManagerGetter,
-				identityDeserializer,
-				&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
-			)
 			sProp, _ := utils.MockSignedEndorserProposalOrPanic("", &pb.ChaincodeSpec{}, []byte("Bob"), []byte("msg1"))
-			identityDeserializer.Msg = sProp.ProposalBytes
 			sProp.Signature = sProp.ProposalBytes
 
+			mockAclProvider.Reset()
+			mockAclProvider.On("CheckACL", resources.Lscc_GetInstalledChaincodes, "", sProp).Return(errors.New("Failed authorization"))
 			res = stub.MockInvokeWithSignedProposal("1", [][]byte{[]byte(function)}, sProp)
 			assert.NotEqual(t, int32(shim.OK), res.Status)
-			assert.Contains(t, res.Message, "access denied for ["+function+"]")
+			assert.Equal(t, "access denied for ["+function+"]: Failed authorization", res.Message)
 
 			sProp, _ = utils.MockSignedEndorserProposalOrPanic("", &pb.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
-			identityDeserializer.Msg = sProp.ProposalBytes
 			sProp.Signature = sProp.ProposalBytes
+			mockAclProvider.Reset()
+			mockAclProvider.On("CheckACL", resources.Lscc_GetInstalledChaincodes, "", sProp).Return(nil)
 
 			res = stub.MockInvokeWithSignedProposal("1", [][]byte{[]byte(function)}, sProp)
 			assert.NotEqual(t, int32(shim.OK), res.Status)
@@ -891,6 +968,92 @@ func TestPutChaincodeCollectionData(t *testing.T) {
 	stub.MockTransactionEnd("foo")
 }
 
+func TestPutChaincodeCollectionDataStateDatabase(t *testing.T) {
+	scc := new(LifeCycleSysCC)
+	stub := shim.NewMockStub("lscc", scc)
+	scc.Support = &lscc.MockSupport{}
+	cd := &ccprovider.ChaincodeData{Name: "foo"}
+
+	collectionBytes := func(stateDatabases ...string) []byte {
+		ccp := &common.CollectionConfigPackage{}
+		for i, stateDatabase := range stateDatabases {
+			coll := createCollectionConfig(fmt.Sprintf("mycollection%d", i+1), &common.SignaturePolicyEnvelope{}, 1, 2)
+			coll.GetStaticCollectionConfig().StateDatabase = stateDatabase
+			ccp.Config = append(ccp.Config, coll)
+		}
+		ccpBytes, err := proto.Marshal(ccp)
+		assert.NoError(t, err)
+		return ccpBytes
+	}
+
+	stub.MockTransactionStart("unknown")
+	err := scc.putChaincodeCollectionData(stub, cd, collectionBytes("MySQL"))
+	assert.EqualError(t, err, "collection state database check failed: collection-name: mycollection1 -- unknown state database MySQL")
+	stub.MockTransactionEnd("unknown")
+
+	stub.MockTransactionStart("deploy")
+	err = scc.putChaincodeCollectionData(stub, cd, collectionBytes("CouchDB"))
+	assert.NoError(t, err)
+	stub.MockTransactionEnd("deploy")
+
+	// the new collections of an upgrade may request any state database
+	stub.MockTransactionStart("upgrade")
+	err = scc.putChaincodeCollectionData(stub, cd, collectionBytes("CouchDB", "goleveldb"))
+	assert.NoError(t, err)
+	stub.MockTransactionEnd("upgrade")
+
+	stub.MockTransactionStart("modify")
+	err = scc.putChaincodeCollectionData(stub, cd, collectionBytes("CouchDB", ""))
+	assert.EqualError(t, err, "collection state database check failed: collection-name: mycollection2 -- the state database cannot be modified from [goleveldb] to []")
+	stub.MockTransactionEnd("modify")
+}
+
+func TestPutChaincodeCollectionDataValueCommitment(t *testing.T) {
+	scc := new(LifeCycleSysCC)
+	stub := shim.NewMockStub("lscc", scc)
+	scc.Support = &lscc.MockSupport{}
+	cd := &ccprovider.ChaincodeData{Name: "foo"}
+
+	collectionBytes := func(valueCommitments ...string) []byte {
+		ccp := &common.CollectionConfigPackage{}
+		for i, valueCommitment := range valueCommitments {
+			coll := createCollectionConfig(fmt.Sprintf("mycollection%d", i+1), &common.SignaturePolicyEnvelope{}, 1, 2)
+			coll.GetStaticCollectionConfig().ValueCommitment = valueCommitment
+			ccp.Config = append(ccp.Config, coll)
+		}
+		ccpBytes, err := proto.Marshal(ccp)
+		assert.NoError(t, err)
+		return ccpBytes
+	}
+
+	stub.MockTransactionStart("unknown")
+	err := scc.putChaincodeCollectionData(stub, cd, collectionBytes("ElGamal"))
+	assert.EqualError(t, err, "collection value commitment check failed: collection-name: mycollection1 -- unknown value commitment ElGamal")
+	stub.MockTransactionEnd("unknown")
+
+	stub.MockTransactionStart("deploy")
+	err = scc.putChaincodeCollectionData(stub, cd, collectionBytes("Pedersen"))
+	assert.NoError(t, err)
+	stub.MockTransactionEnd("deploy")
+
+	// the new collections of an upgrade may use any value commitment
+	stub.MockTransactionStart("upgrade")
+	err = scc.putChaincodeCollectionData(stub, cd, collectionBytes("Pedersen", ""))
+	assert.NoError(t, err)
+	stub.MockTransactionEnd("upgrade")
+
+	stub.MockTransactionStart("modify")
+	err = scc.putChaincodeCollectionData(stub, cd, collectionBytes("", ""))
+	assert.EqualError(t, err, "collection value commitment check failed: collection-name: mycollection1 -- the value commitment cannot be modified from [Pedersen] to []")
+	stub.MockTransactionEnd("modify")
+
+	// the value commitments must be enabled by the capabilities of the channel
+	err = checkCollectionCapabilities(collectionBytes("", "Pedersen"), &config.MockApplicationCapabilities{})
+	assert.EqualError(t, err, "collection-name: mycollection2 -- value commitments are not enabled by the capabilities of the channel")
+	assert.NoError(t, checkCollectionCapabilities(collectionBytes("", "Pedersen"), &config.MockApplicationCapabilities{ValueCommitmentsRv: true}))
+	assert.NoError(t, checkCollectionCapabilities(collectionBytes(""), &config.MockApplicationCapabilities{}))
+}
+
 func TestGetChaincodeCollectionData(t *testing.T) {
 	scc := New(NewMockProvider(), mockAclProvider, platforms.NewRegistry(&golang.Platform{}))
 	stub := shim.NewMockStub("lscc", scc)
@@ -946,6 +1109,56 @@ func TestGetChaincodeCollectionData(t *testing.T) {
 	}
 }
 
+func TestGetChaincodeMigration(t *testing.T) {
+	scc := New(NewMockProvider(), mockAclProvider, platforms.NewRegistry(&golang.Platform{}))
+	stub := shim.NewMockStub("lscc", scc)
+	stub.ChannelID = "test"
+	scc.Support = &lscc.MockSupport{}
+
+	stub.MockTransactionStart("foo")
+	assert.NoError(t, stub.PutState("foo", utils.MarshalOrPanic(&ccprovider.ChaincodeData{Name: "foo", Version: "2"})))
+	assert.NoError(t, stub.PutState("bar", utils.MarshalOrPanic(&ccprovider.ChaincodeData{Name: "bar", Version: "1"})))
+	assert.NoError(t, stub.PutState(ccprovider.BuildMigrationKVSKey("foo"), []byte("2")))
+	stub.MockTransactionEnd("foo")
+
+	res := stub.MockInit("1", nil)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+
+	for _, function := range []string{"GetMigration", "getmigration"} {
+		sProp, _ := utils.MockSignedEndorserProposalOrPanic("test", &pb.ChaincodeSpec{}, []byte("Bob"), []byte("msg1"))
+		sProp.Signature = sProp.ProposalBytes
+
+		t.Run("invalid number of arguments", func(t *testing.T) {
+			res = stub.MockInvokeWithSignedProposal("1", util.ToChaincodeArgs(function, "foo", "bar"), nil)
+			assert.NotEqual(t, int32(shim.OK), res.Status)
+			assert.Equal(t, "invalid number of arguments to lscc: 3", res.Message)
+		})
+		t.Run("invalid identity", func(t *testing.T) {
+			mockAclProvider.Reset()
+			mockAclProvider.On("CheckACL", resources.Lscc_GetChaincodeData, "test", sProp).Return(errors.New("acl check failed"))
+			res = stub.MockInvokeWithSignedProposal("1", util.ToChaincodeArgs(function, "foo"), sProp)
+			assert.NotEqual(t, int32(shim.OK), res.Status)
+			assert.Contains(t, res.Message, "access denied for ["+function+"]")
+		})
+		t.Run("non-existing chaincode", func(t *testing.T) {
+			mockAclProvider.Reset()
+			mockAclProvider.On("CheckACL", resources.Lscc_GetChaincodeData, "test", sProp).Return(nil)
+			res = stub.MockInvokeWithSignedProposal("1", util.ToChaincodeArgs(function, "baz"), sProp)
+			assert.NotEqual(t, int32(shim.OK), res.Status)
+			assert.Equal(t, "could not find chaincode with name 'baz'", res.Message)
+		})
+		t.Run("Success", func(t *testing.T) {
+			res = stub.MockInvokeWithSignedProposal("1", util.ToChaincodeArgs(function, "foo"), sProp)
+			assert.Equal(t, int32(shim.OK), res.Status)
+			assert.Equal(t, []byte("2"), res.Payload)
+
+			res = stub.MockInvokeWithSignedProposal("1", util.ToChaincodeArgs(function, "bar"), sProp)
+			assert.Equal(t, int32(shim.OK), res.Status)
+			assert.Empty(t, res.Payload)
+		})
+	}
+}
+
 func TestCheckCollectionMemberPolicy(t *testing.T) {
 	// error case: no msp manager set, no collection config set
 	err := checkCollectionMemberPolicy(nil, nil)
@@ -72,6 +72,17 @@ type FileSystemSupport struct {
 	checkInstantiationPolicyReturnsOnCall map[int]struct {
 		result1 error
 	}
+	CheckInstallPolicyStub        func(ccpack ccprovider.CCPackage) error
+	checkInstallPolicyMutex       sync.RWMutex
+	checkInstallPolicyArgsForCall []struct {
+		ccpack ccprovider.CCPackage
+	}
+	checkInstallPolicyReturns struct {
+		result1 error
+	}
+	checkInstallPolicyReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -326,6 +337,54 @@ func (fake *FileSystemSupport) CheckInstantiationPolicyReturnsOnCall(i int, resu
 	}{result1}
 }
 
+func (fake *FileSystemSupport) CheckInstallPolicy(ccpack ccprovider.CCPackage) error {
+	fake.checkInstallPolicyMutex.Lock()
+	ret, specificReturn := fake.checkInstallPolicyReturnsOnCall[len(fake.checkInstallPolicyArgsForCall)]
+	fake.checkInstallPolicyArgsForCall = append(fake.checkInstallPolicyArgsForCall, struct {
+		ccpack ccprovider.CCPackage
+	}{ccpack})
+	fake.recordInvocation("CheckInstallPolicy", []interface{}{ccpack})
+	fake.checkInstallPolicyMutex.Unlock()
+	if fake.CheckInstallPolicyStub != nil {
+		return fake.CheckInstallPolicyStub(ccpack)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.checkInstallPolicyReturns.result1
+}
+
+func (fake *FileSystemSupport) CheckInstallPolicyCallCount() int {
+	fake.checkInstallPolicyMutex.RLock()
+	defer fake.checkInstallPolicyMutex.RUnlock()
+	return len(fake.checkInstallPolicyArgsForCall)
+}
+
+func (fake *FileSystemSupport) CheckInstallPolicyArgsForCall(i int) ccprovider.CCPackage {
+	fake.checkInstallPolicyMutex.RLock()
+	defer fake.checkInstallPolicyMutex.RUnlock()
+	return fake.checkInstallPolicyArgsForCall[i].ccpack
+}
+
+func (fake *FileSystemSupport) CheckInstallPolicyReturns(result1 error) {
+	fake.CheckInstallPolicyStub = nil
+	fake.checkInstallPolicyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FileSystemSupport) CheckInstallPolicyReturnsOnCall(i int, result1 error) {
+	fake.CheckInstallPolicyStub = nil
+	if fake.checkInstallPolicyReturnsOnCall == nil {
+		fake.checkInstallPolicyReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.checkInstallPolicyReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FileSystemSupport) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -339,6 +398,8 @@ func (fake *FileSystemSupport) Invocations() map[string][][]interface{} {
 	defer fake.getInstantiationPolicyMutex.RUnlock()
 	fake.checkInstantiationPolicyMutex.RLock()
 	defer fake.checkInstantiationPolicyMutex.RUnlock()
+	fake.checkInstallPolicyMutex.RLock()
+	defer fake.checkInstallPolicyMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
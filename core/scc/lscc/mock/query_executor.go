@@ -126,6 +126,21 @@ type QueryExecutor struct {
 		result1 []byte
 		result2 error
 	}
+	GetPrivateDataHashStub        func(namespace, collection, key string) ([]byte, error)
+	getPrivateDataHashMutex       sync.RWMutex
+	getPrivateDataHashArgsForCall []struct {
+		namespace  string
+		collection string
+		key        string
+	}
+	getPrivateDataHashReturns struct {
+		result1 []byte
+		result2 error
+	}
+	getPrivateDataHashReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
 	GetPrivateDataMetadataStub        func(namespace, collection, key string) (map[string][]byte, error)
 	getPrivateDataMetadataMutex       sync.RWMutex
 	getPrivateDataMetadataArgsForCall []struct {
@@ -635,6 +650,59 @@ func (fake *QueryExecutor) GetPrivateDataReturnsOnCall(i int, result1 []byte, re
 	}{result1, result2}
 }
 
+func (fake *QueryExecutor) GetPrivateDataHash(namespace string, collection string, key string) ([]byte, error) {
+	fake.getPrivateDataHashMutex.Lock()
+	ret, specificReturn := fake.getPrivateDataHashReturnsOnCall[len(fake.getPrivateDataHashArgsForCall)]
+	fake.getPrivateDataHashArgsForCall = append(fake.getPrivateDataHashArgsForCall, struct {
+		namespace  string
+		collection string
+		key        string
+	}{namespace, collection, key})
+	fake.recordInvocation("GetPrivateDataHash", []interface{}{namespace, collection, key})
+	fake.getPrivateDataHashMutex.Unlock()
+	if fake.GetPrivateDataHashStub != nil {
+		return fake.GetPrivateDataHashStub(namespace, collection, key)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getPrivateDataHashReturns.result1, fake.getPrivateDataHashReturns.result2
+}
+
+func (fake *QueryExecutor) GetPrivateDataHashCallCount() int {
+	fake.getPrivateDataHashMutex.RLock()
+	defer fake.getPrivateDataHashMutex.RUnlock()
+	return len(fake.getPrivateDataHashArgsForCall)
+}
+
+func (fake *QueryExecutor) GetPrivateDataHashArgsForCall(i int) (string, string, string) {
+	fake.getPrivateDataHashMutex.RLock()
+	defer fake.getPrivateDataHashMutex.RUnlock()
+	return fake.getPrivateDataHashArgsForCall[i].namespace, fake.getPrivateDataHashArgsForCall[i].collection, fake.getPrivateDataHashArgsForCall[i].key
+}
+
+func (fake *QueryExecutor) GetPrivateDataHashReturns(result1 []byte, result2 error) {
+	fake.GetPrivateDataHashStub = nil
+	fake.getPrivateDataHashReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *QueryExecutor) GetPrivateDataHashReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.GetPrivateDataHashStub = nil
+	if fake.getPrivateDataHashReturnsOnCall == nil {
+		fake.getPrivateDataHashReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.getPrivateDataHashReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *QueryExecutor) GetPrivateDataMetadata(namespace string, collection string, key string) (map[string][]byte, error) {
 	fake.getPrivateDataMetadataMutex.Lock()
 	ret, specificReturn := fake.getPrivateDataMetadataReturnsOnCall[len(fake.getPrivateDataMetadataArgsForCall)]
@@ -946,6 +1014,8 @@ func (fake *QueryExecutor) Invocations() map[string][][]interface{} {
 	defer fake.executeQueryWithMetadataMutex.RUnlock()
 	fake.getPrivateDataMutex.RLock()
 	defer fake.getPrivateDataMutex.RUnlock()
+	fake.getPrivateDataHashMutex.RLock()
+	defer fake.getPrivateDataHashMutex.RUnlock()
 	fake.getPrivateDataMetadataMutex.RLock()
 	defer fake.getPrivateDataMetadataMutex.RUnlock()
 	fake.getPrivateDataMetadataByHashMutex.RLock()
@@ -56,6 +56,35 @@ type SystemChaincodeProvider struct {
 		result1 ledger.QueryExecutor
 		result2 error
 	}
+	GetLedgerHeightStub        func(cid string) (uint64, error)
+	getLedgerHeightMutex       sync.RWMutex
+	getLedgerHeightArgsForCall []struct {
+		cid string
+	}
+	getLedgerHeightReturns struct {
+		result1 uint64
+		result2 error
+	}
+	getLedgerHeightReturnsOnCall map[int]struct {
+		result1 uint64
+		result2 error
+	}
+	GetStateAtHeightStub        func(cid, namespace, key string, height uint64) ([]byte, error)
+	getStateAtHeightMutex       sync.RWMutex
+	getStateAtHeightArgsForCall []struct {
+		cid       string
+		namespace string
+		key       string
+		height    uint64
+	}
+	getStateAtHeightReturns struct {
+		result1 []byte
+		result2 error
+	}
+	getStateAtHeightReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
 	GetApplicationConfigStub        func(cid string) (channelconfig.Application, bool)
 	getApplicationConfigMutex       sync.RWMutex
 	getApplicationConfigArgsForCall []struct {
@@ -281,6 +310,111 @@ func (fake *SystemChaincodeProvider) GetQueryExecutorForLedgerReturnsOnCall(i in
 	}{result1, result2}
 }
 
+func (fake *SystemChaincodeProvider) GetLedgerHeight(cid string) (uint64, error) {
+	fake.getLedgerHeightMutex.Lock()
+	ret, specificReturn := fake.getLedgerHeightReturnsOnCall[len(fake.getLedgerHeightArgsForCall)]
+	fake.getLedgerHeightArgsForCall = append(fake.getLedgerHeightArgsForCall, struct {
+		cid string
+	}{cid})
+	fake.recordInvocation("GetLedgerHeight", []interface{}{cid})
+	fake.getLedgerHeightMutex.Unlock()
+	if fake.GetLedgerHeightStub != nil {
+		return fake.GetLedgerHeightStub(cid)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getLedgerHeightReturns.result1, fake.getLedgerHeightReturns.result2
+}
+
+func (fake *SystemChaincodeProvider) GetLedgerHeightCallCount() int {
+	fake.getLedgerHeightMutex.RLock()
+	defer fake.getLedgerHeightMutex.RUnlock()
+	return len(fake.getLedgerHeightArgsForCall)
+}
+
+func (fake *SystemChaincodeProvider) GetLedgerHeightArgsForCall(i int) string {
+	fake.getLedgerHeightMutex.RLock()
+	defer fake.getLedgerHeightMutex.RUnlock()
+	return fake.getLedgerHeightArgsForCall[i].cid
+}
+
+func (fake *SystemChaincodeProvider) GetLedgerHeightReturns(result1 uint64, result2 error) {
+	fake.GetLedgerHeightStub = nil
+	fake.getLedgerHeightReturns = struct {
+		result1 uint64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SystemChaincodeProvider) GetLedgerHeightReturnsOnCall(i int, result1 uint64, result2 error) {
+	fake.GetLedgerHeightStub = nil
+	if fake.getLedgerHeightReturnsOnCall == nil {
+		fake.getLedgerHeightReturnsOnCall = make(map[int]struct {
+			result1 uint64
+			result2 error
+		})
+	}
+	fake.getLedgerHeightReturnsOnCall[i] = struct {
+		result1 uint64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SystemChaincodeProvider) GetStateAtHeight(cid string, namespace string, key string, height uint64) ([]byte, error) {
+	fake.getStateAtHeightMutex.Lock()
+	ret, specificReturn := fake.getStateAtHeightReturnsOnCall[len(fake.getStateAtHeightArgsForCall)]
+	fake.getStateAtHeightArgsForCall = append(fake.getStateAtHeightArgsForCall, struct {
+		cid       string
+		namespace string
+		key       string
+		height    uint64
+	}{cid, namespace, key, height})
+	fake.recordInvocation("GetStateAtHeight", []interface{}{cid, namespace, key, height})
+	fake.getStateAtHeightMutex.Unlock()
+	if fake.GetStateAtHeightStub != nil {
+		return fake.GetStateAtHeightStub(cid, namespace, key, height)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getStateAtHeightReturns.result1, fake.getStateAtHeightReturns.result2
+}
+
+func (fake *SystemChaincodeProvider) GetStateAtHeightCallCount() int {
+	fake.getStateAtHeightMutex.RLock()
+	defer fake.getStateAtHeightMutex.RUnlock()
+	return len(fake.getStateAtHeightArgsForCall)
+}
+
+func (fake *SystemChaincodeProvider) GetStateAtHeightArgsForCall(i int) (string, string, string, uint64) {
+	fake.getStateAtHeightMutex.RLock()
+	defer fake.getStateAtHeightMutex.RUnlock()
+	return fake.getStateAtHeightArgsForCall[i].cid, fake.getStateAtHeightArgsForCall[i].namespace, fake.getStateAtHeightArgsForCall[i].key, fake.getStateAtHeightArgsForCall[i].height
+}
+
+func (fake *SystemChaincodeProvider) GetStateAtHeightReturns(result1 []byte, result2 error) {
+	fake.GetStateAtHeightStub = nil
+	fake.getStateAtHeightReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SystemChaincodeProvider) GetStateAtHeightReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.GetStateAtHeightStub = nil
+	if fake.getStateAtHeightReturnsOnCall == nil {
+		fake.getStateAtHeightReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.getStateAtHeightReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *SystemChaincodeProvider) GetApplicationConfig(cid string) (channelconfig.Application, bool) {
 	fake.getApplicationConfigMutex.Lock()
 	ret, specificReturn := fake.getApplicationConfigReturnsOnCall[len(fake.getApplicationConfigArgsForCall)]
@@ -394,6 +528,10 @@ func (fake *SystemChaincodeProvider) Invocations() map[string][][]interface{} {
 	defer fake.isSysCCAndNotInvokableExternalMutex.RUnlock()
 	fake.getQueryExecutorForLedgerMutex.RLock()
 	defer fake.getQueryExecutorForLedgerMutex.RUnlock()
+	fake.getLedgerHeightMutex.RLock()
+	defer fake.getLedgerHeightMutex.RUnlock()
+	fake.getStateAtHeightMutex.RLock()
+	defer fake.getStateAtHeightMutex.RUnlock()
 	fake.getApplicationConfigMutex.RLock()
 	defer fake.getApplicationConfigMutex.RUnlock()
 	fake.policyManagerMutex.RLock()
@@ -7,7 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package lscc
 
 import (
+	"fmt"
+
 	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/msp/mgmt"
@@ -15,6 +18,7 @@ import (
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
+	"github.com/spf13/viper"
 )
 
 type supportImpl struct {
@@ -107,3 +111,51 @@ func (s *supportImpl) CheckInstantiationPolicy(signedProp *pb.SignedProposal, ch
 	}
 	return nil
 }
+
+// CheckInstallPolicy checks whether the owner endorsements of the supplied
+// chaincode package comply with the install policy of the peer. The policy is
+// either a signature policy evaluated with the local MSP, or a policy of the
+// channel set in the configuration. Any package complies with an empty policy.
+func (s *supportImpl) CheckInstallPolicy(ccpack ccprovider.CCPackage) error {
+	policy := viper.GetString("chaincode.installPolicy.policy")
+	if policy == "" {
+		return nil
+	}
+
+	sccpack, isSccpack := ccpack.(*ccprovider.SignedCDSPackage)
+	if !isSccpack {
+		return errors.New("install policy violation: the chaincode package is not signed by its owners")
+	}
+	sd := sccpack.GetOwnerSignedData()
+	if len(sd) == 0 {
+		return errors.New("install policy violation: the chaincode package has no owner endorsement")
+	}
+
+	var installPol policies.Policy
+	if channel := viper.GetString("chaincode.installPolicy.channel"); channel != "" {
+		pm := peer.GetPolicyManager(channel)
+		if pm == nil {
+			return errors.Errorf("error checking chaincode install policy: policy manager for channel %s not found", channel)
+		}
+		var ok bool
+		installPol, ok = pm.GetPolicy(policy)
+		if !ok {
+			return errors.Errorf("error checking chaincode install policy: policy %s not found in channel %s", policy, channel)
+		}
+	} else {
+		p, err := cauthdsl.FromString(policy)
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("invalid chaincode install policy %s", policy))
+		}
+		npp := cauthdsl.NewPolicyProvider(mgmt.GetLocalMSP())
+		installPol, _, err = npp.NewPolicy(utils.MarshalOrPanic(p))
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := installPol.Evaluate(sd); err != nil {
+		return errors.WithMessage(err, "install policy violation")
+	}
+	return nil
+}
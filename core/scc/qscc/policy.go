@@ -0,0 +1,135 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package qscc
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/msp"
+	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// lsccNamespace is the namespace the chaincode definitions, holding their
+// endorsement policies, are stored in
+const lsccNamespace = "lscc"
+
+// getIdentityDeserializer returns the deserializer of the identities of a channel
+var getIdentityDeserializer = func(chainID string) msp.IdentityDeserializer {
+	return mspmgmt.GetIdentityDeserializer(chainID)
+}
+
+// principalDeserializer deserializes identities whose signatures are not
+// checked, so that a policy is evaluated against the principals of the
+// identities alone
+type principalDeserializer struct {
+	msp.IdentityDeserializer
+}
+
+func (d *principalDeserializer) DeserializeIdentity(serializedIdentity []byte) (msp.Identity, error) {
+	identity, err := d.IdentityDeserializer.DeserializeIdentity(serializedIdentity)
+	if err != nil {
+		return nil, err
+	}
+	return &unsignedIdentity{Identity: identity}, nil
+}
+
+// unsignedIdentity is an identity whose signatures are deemed valid
+type unsignedIdentity struct {
+	msp.Identity
+}
+
+func (id *unsignedIdentity) Verify(msg []byte, sig []byte) error {
+	return nil
+}
+
+// evaluatePolicy returns whether the serialized identities in args[1:] would
+// satisfy the marshaled SignaturePolicyEnvelope in args[0]
+func evaluatePolicy(cid string, args [][]byte) pb.Response {
+	if len(args) < 2 {
+		return shim.Error("A policy and at least one identity must be provided.")
+	}
+	return evaluate(cid, args[0], args[1:])
+}
+
+// evaluateKeyPolicy returns whether the serialized identities in args[3:]
+// would satisfy the endorsement policy of the key args[2] of the chaincode
+// args[0], in the private data collection args[1] unless empty. This is the
+// committed key-level endorsement policy of the key, or the endorsement
+// policy of the chaincode if the key has none.
+func evaluateKeyPolicy(vledger ledger.PeerLedger, cid string, args [][]byte) pb.Response {
+	if len(args) < 4 {
+		return shim.Error("A chaincode name, a collection, a key and at least one identity must be provided.")
+	}
+	ccName, collection, key := string(args[0]), string(args[1]), string(args[2])
+
+	qe, err := vledger.NewQueryExecutor()
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get query executor with error %s", err))
+	}
+	defer qe.Done()
+
+	var metadata map[string][]byte
+	if collection == "" {
+		metadata, err = qe.GetStateMetadata(ccName, key)
+	} else {
+		metadata, err = qe.GetPrivateDataMetadata(ccName, collection, key)
+	}
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get metadata of key %s with error %s", key, err))
+	}
+	policy := metadata[pb.MetaDataKeys_VALIDATION_PARAMETER.String()]
+	if len(policy) != 0 {
+		return evaluate(cid, policy, args[3:])
+	}
+
+	ccBytes, err := qe.GetState(lsccNamespace, ccName)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get chaincode %s with error %s", ccName, err))
+	}
+	if ccBytes == nil {
+		return shim.Error(fmt.Sprintf("Chaincode %s is not instantiated on channel %s", ccName, cid))
+	}
+	cd := &ccprovider.ChaincodeData{}
+	if err := proto.Unmarshal(ccBytes, cd); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to unmarshal chaincode %s with error %s", ccName, err))
+	}
+	return evaluate(cid, cd.Policy, args[3:])
+}
+
+// evaluate evaluates the marshaled SignaturePolicyEnvelope against the
+// principals of the serialized identities of the channel
+func evaluate(cid string, policyBytes []byte, identities [][]byte) pb.Response {
+	deserializer := &principalDeserializer{IdentityDeserializer: getIdentityDeserializer(cid)}
+	policy, _, err := cauthdsl.NewPolicyProvider(deserializer).NewPolicy(policyBytes)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to parse policy with error %s", err))
+	}
+
+	signedData := make([]*cb.SignedData, len(identities))
+	for i, identity := range identities {
+		signedData[i] = &cb.SignedData{Identity: identity}
+	}
+	evaluation := &pb.PolicyEvaluation{Satisfied: policy.Evaluate(signedData) == nil}
+	if !evaluation.Satisfied {
+		evaluation.Explanation = policies.Explain(policy, signedData).String()
+	}
+
+	bytes, err := utils.Marshal(evaluation)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	return shim.Success(bytes)
+}
@@ -0,0 +1,142 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package qscc
+
+import (
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	ledger2 "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/msp"
+	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
+	msptesttools "github.com/hyperledger/fabric/msp/mgmt/testtools"
+	peer2 "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPolicyTest(t *testing.T) (identity []byte, cleanup func()) {
+	require.NoError(t, msptesttools.LoadMSPSetupForTesting())
+	identity, err := mspmgmt.GetLocalSigningIdentityOrPanic().Serialize()
+	require.NoError(t, err)
+
+	getDeserializer := getIdentityDeserializer
+	getIdentityDeserializer = func(string) msp.IdentityDeserializer {
+		return mspmgmt.GetLocalMSP()
+	}
+	return identity, func() { getIdentityDeserializer = getDeserializer }
+}
+
+func invokeEvaluation(t *testing.T, stub *shim.MockStub, res, chainid string, args ...[]byte) (*peer2.PolicyEvaluation, string) {
+	sProp, _ := utils.MockSignedEndorserProposalOrPanic(chainid, &peer2.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
+	resetProvider(res, chainid, sProp, nil)
+	response := stub.MockInvokeWithSignedProposal("1", args, sProp)
+	if response.Status != shim.OK {
+		return nil, response.Message
+	}
+	evaluation := &peer2.PolicyEvaluation{}
+	require.NoError(t, proto.Unmarshal(response.Payload, evaluation))
+	return evaluation, ""
+}
+
+func TestQueryEvaluatePolicy(t *testing.T) {
+	chainid := "mytestchainid12"
+	path := tempDir(t, "test12")
+	defer os.RemoveAll(path)
+
+	identity, cleanup := setupPolicyTest(t)
+	defer cleanup()
+	stub, err := setupTestLedger(chainid, path)
+	require.NoError(t, err)
+
+	member := utils.MarshalOrPanic(cauthdsl.SignedByMspMember("SampleOrg"))
+	other := utils.MarshalOrPanic(cauthdsl.SignedByMspMember("OtherOrg"))
+	evaluate := func(args ...[]byte) (*peer2.PolicyEvaluation, string) {
+		return invokeEvaluation(t, stub, resources.Qscc_EvaluatePolicy, chainid, append([][]byte{[]byte(EvaluatePolicy), []byte(chainid)}, args...)...)
+	}
+
+	evaluation, errMsg := evaluate(member, identity)
+	assert.Empty(t, errMsg)
+	assert.True(t, evaluation.Satisfied)
+	assert.Empty(t, evaluation.Explanation)
+
+	evaluation, errMsg = evaluate(other, identity)
+	assert.Empty(t, errMsg)
+	assert.False(t, evaluation.Satisfied)
+	assert.Contains(t, evaluation.Explanation, "signed by 'OtherOrg.member': not satisfied")
+
+	evaluation, errMsg = evaluate(member, []byte("garbage"))
+	assert.Empty(t, errMsg)
+	assert.False(t, evaluation.Satisfied)
+
+	_, errMsg = evaluate(member)
+	assert.Equal(t, "A policy and at least one identity must be provided.", errMsg)
+
+	_, errMsg = evaluate([]byte("garbage"), identity)
+	assert.Contains(t, errMsg, "Failed to parse policy")
+}
+
+func TestQueryEvaluateKeyPolicy(t *testing.T) {
+	chainid := "mytestchainid13"
+	path := tempDir(t, "test13")
+	defer os.RemoveAll(path)
+
+	identity, cleanup := setupPolicyTest(t)
+	defer cleanup()
+	stub, err := setupTestLedger(chainid, path)
+	require.NoError(t, err)
+
+	member := utils.MarshalOrPanic(cauthdsl.SignedByMspMember("SampleOrg"))
+	other := utils.MarshalOrPanic(cauthdsl.SignedByMspMember("OtherOrg"))
+
+	// instantiate a chaincode endorsed by SampleOrg, with a key endorsed by OtherOrg only
+	ledger := peer.GetLedger(chainid)
+	simulator, err := ledger.NewTxSimulator(util.GenerateUUID())
+	require.NoError(t, err)
+	require.NoError(t, simulator.SetState(lsccNamespace, "mycc", utils.MarshalOrPanic(&ccprovider.ChaincodeData{Name: "mycc", Version: "1.0", Policy: member})))
+	require.NoError(t, simulator.SetState("mycc", "otherKey", []byte("value")))
+	require.NoError(t, simulator.SetStateMetadata("mycc", "otherKey", map[string][]byte{peer2.MetaDataKeys_VALIDATION_PARAMETER.String(): other}))
+	require.NoError(t, simulator.SetState("mycc", "key", []byte("value")))
+	simulator.Done()
+	simRes, err := simulator.GetTxSimulationResults()
+	require.NoError(t, err)
+	pubSimResBytes, err := simRes.GetPubSimulationBytes()
+	require.NoError(t, err)
+	bcInfo, err := ledger.GetBlockchainInfo()
+	require.NoError(t, err)
+	block := testutil.ConstructBlock(t, 1, bcInfo.CurrentBlockHash, [][]byte{pubSimResBytes}, false)
+	require.NoError(t, ledger.CommitWithPvtData(&ledger2.BlockAndPvtData{Block: block}))
+
+	evaluate := func(args ...[]byte) (*peer2.PolicyEvaluation, string) {
+		return invokeEvaluation(t, stub, resources.Qscc_EvaluateKeyPolicy, chainid, append([][]byte{[]byte(EvaluateKeyPolicy), []byte(chainid)}, args...)...)
+	}
+
+	// the key without key-level policy is endorsed as per the chaincode policy
+	evaluation, errMsg := evaluate([]byte("mycc"), nil, []byte("key"), identity)
+	assert.Empty(t, errMsg)
+	assert.True(t, evaluation.Satisfied)
+
+	evaluation, errMsg = evaluate([]byte("mycc"), nil, []byte("otherKey"), identity)
+	assert.Empty(t, errMsg)
+	assert.False(t, evaluation.Satisfied)
+	assert.Contains(t, evaluation.Explanation, "OtherOrg")
+
+	_, errMsg = evaluate([]byte("unknowncc"), nil, []byte("key"), identity)
+	assert.Equal(t, "Chaincode unknowncc is not instantiated on channel "+chainid, errMsg)
+
+	_, errMsg = evaluate([]byte("mycc"), nil, []byte("key"))
+	assert.Equal(t, "A chaincode name, a collection, a key and at least one identity must be provided.", errMsg)
+}
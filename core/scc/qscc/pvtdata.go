@@ -0,0 +1,163 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package qscc
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/pvtdatapolicy"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// getPrivateDataAvailability reports, for each valid transaction of the blocks
+// from block number args[2] to block number args[3], inclusive, which wrote to
+// the collection args[1] of the chaincode args[0], whether the private data of
+// the transaction is present in the private data store of the peer, missing,
+// or purged after the block to live of the collection. The private data of the
+// collections the peer is not a member of is reported as missing.
+func getPrivateDataAvailability(vledger ledger.PeerLedger, args [][]byte) pb.Response {
+	if len(args) < 4 {
+		return shim.Error("A chaincode name, a collection, and start and end block numbers must be provided.")
+	}
+	ns, coll := string(args[0]), string(args[1])
+	start, err := strconv.ParseUint(string(args[2]), 10, 64)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to parse start block number with error %s", err))
+	}
+	end, err := strconv.ParseUint(string(args[3]), 10, 64)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to parse end block number with error %s", err))
+	}
+	if end < start {
+		return shim.Error(fmt.Sprintf("End block number %d is lower than start block number %d", end, start))
+	}
+	binfo, err := vledger.GetBlockchainInfo()
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get block info with error %s", err))
+	}
+	if start >= binfo.Height {
+		return shim.Error(fmt.Sprintf("Start block number %d is beyond the ledger height %d", start, binfo.Height))
+	}
+	if end >= binfo.Height {
+		end = binfo.Height - 1
+	}
+	if end-start >= maxQueryResults {
+		end = start + maxQueryResults - 1
+	}
+
+	// the private data store discards the data of a collection once the ledger
+	// commits its expiring block
+	btlPolicy := pvtdatapolicy.NewBTLPolicy(vledger)
+	if _, err := btlPolicy.GetBTL(ns, coll); err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get block to live of collection %s of chaincode %s with error %s", coll, ns, err))
+	}
+	lastBlockNum := binfo.Height - 1
+
+	filter := ledger.NewPvtNsCollFilter()
+	filter.Add(ns, coll)
+	availability := &pb.PrivateDataAvailability{}
+	for bnum := start; bnum <= end; bnum++ {
+		blockAndPvtData, err := vledger.GetPvtDataAndBlockByNum(bnum, filter)
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to get block number %d, error %s", bnum, err))
+		}
+		expiringBlk, err := btlPolicy.GetExpiringBlock(ns, coll, bnum)
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to get expiring block of block number %d, error %s", bnum, err))
+		}
+		entries, err := privateDataEntries(blockAndPvtData, ns, coll, expiringBlk <= lastBlockNum)
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to read block number %d, error %s", bnum, err))
+		}
+		availability.Entries = append(availability.Entries, entries...)
+	}
+
+	bytes, err := utils.Marshal(availability)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(bytes)
+}
+
+// privateDataEntries returns the availability of the private data written to
+// the collection by the valid transactions of the block
+func privateDataEntries(blockAndPvtData *ledger.BlockAndPvtData, ns, coll string, expired bool) ([]*pb.PrivateDataEntry, error) {
+	block := blockAndPvtData.Block
+	txsFilter := util.TxValidationFlags(block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	var entries []*pb.PrivateDataEntry
+	for txNum, envBytes := range block.Data.Data {
+		if !txsFilter.IsValid(txNum) {
+			continue
+		}
+		env, err := utils.GetEnvelopeFromBlock(envBytes)
+		if err != nil {
+			return nil, err
+		}
+		payload, err := utils.GetPayload(env)
+		if err != nil {
+			return nil, err
+		}
+		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			return nil, err
+		}
+		if cb.HeaderType(chdr.Type) != cb.HeaderType_ENDORSER_TRANSACTION {
+			continue
+		}
+		written, err := writesToCollection(envBytes, ns, coll)
+		if err != nil {
+			return nil, err
+		}
+		if !written {
+			continue
+		}
+
+		entry := &pb.PrivateDataEntry{BlockNumber: block.Header.Number, TxNumber: uint64(txNum), TxId: chdr.TxId}
+		switch pvtData := blockAndPvtData.BlockPvtData[uint64(txNum)]; {
+		case pvtData != nil && pvtData.Has(ns, coll):
+			entry.Status = pb.PrivateDataEntry_PRESENT
+		case expired:
+			entry.Status = pb.PrivateDataEntry_PURGED
+		default:
+			entry.Status = pb.PrivateDataEntry_MISSING
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// writesToCollection returns whether the hashed read-write set of the
+// endorser transaction holds the collection
+func writesToCollection(envBytes []byte, ns, coll string) (bool, error) {
+	action, err := utils.GetActionFromEnvelope(envBytes)
+	if err != nil {
+		return false, err
+	}
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(action.Results); err != nil {
+		return false, err
+	}
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if nsRWSet.NameSpace != ns {
+			continue
+		}
+		for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+			if collHashedRWSet.CollectionName == coll {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package qscc
+
+import (
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/privdata"
+	ledger2 "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/protos/common"
+	peer2 "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// commitPvtDataBlockForTesting commits a block with a transaction per
+// simulation result, along with the private data of the transactions listed
+// in withPvtData
+func commitPvtDataBlockForTesting(t *testing.T, ledger ledger2.PeerLedger, simRes []*ledger2.TxSimulationResults, withPvtData ...int) {
+	var pubSimResBytes [][]byte
+	for _, res := range simRes {
+		bytes, err := res.GetPubSimulationBytes()
+		require.NoError(t, err)
+		pubSimResBytes = append(pubSimResBytes, bytes)
+	}
+	blockPvtData := map[uint64]*ledger2.TxPvtData{}
+	for _, txNum := range withPvtData {
+		blockPvtData[uint64(txNum)] = &ledger2.TxPvtData{SeqInBlock: uint64(txNum), WriteSet: simRes[txNum].PvtSimulationResults}
+	}
+	bcInfo, err := ledger.GetBlockchainInfo()
+	require.NoError(t, err)
+	block := testutil.ConstructBlock(t, bcInfo.Height, bcInfo.CurrentBlockHash, pubSimResBytes, false)
+	require.NoError(t, ledger.CommitWithPvtData(&ledger2.BlockAndPvtData{Block: block, BlockPvtData: blockPvtData}))
+}
+
+func pvtDataSimulationForTesting(t *testing.T, key string) *ledger2.TxSimulationResults {
+	builder := rwsetutil.NewRWSetBuilder()
+	builder.AddToPvtAndHashedWriteSet("mycc", "coll1", key, []byte("value"))
+	simRes, err := builder.GetTxSimulationResults()
+	require.NoError(t, err)
+	return simRes
+}
+
+func TestQueryGetPrivateDataAvailability(t *testing.T) {
+	chainid := "mytestchainid16"
+	path := tempDir(t, "test16")
+	defer os.RemoveAll(path)
+
+	stub, err := setupTestLedger(chainid, path)
+	require.NoError(t, err)
+
+	// define the collection with a block to live of 2 blocks
+	ledger := peer.GetLedger(chainid)
+	collections := &common.CollectionConfigPackage{
+		Config: []*common.CollectionConfig{{
+			Payload: &common.CollectionConfig_StaticCollectionConfig{
+				StaticCollectionConfig: &common.StaticCollectionConfig{Name: "coll1", BlockToLive: 2},
+			},
+		}},
+	}
+	simulator, err := ledger.NewTxSimulator(util.GenerateUUID())
+	require.NoError(t, err)
+	require.NoError(t, simulator.SetState(lsccNamespace, privdata.BuildCollectionKVSKey("mycc"), utils.MarshalOrPanic(collections)))
+	simulator.Done()
+	simRes, err := simulator.GetTxSimulationResults()
+	require.NoError(t, err)
+	commitPvtDataBlockForTesting(t, ledger, []*ledger2.TxSimulationResults{simRes})
+
+	// the peer receives the private data of the first transaction only
+	commitPvtDataBlockForTesting(t, ledger, []*ledger2.TxSimulationResults{
+		pvtDataSimulationForTesting(t, "key1"),
+		pvtDataSimulationForTesting(t, "key2"),
+	}, 0)
+
+	query := func(args ...string) (*peer2.PrivateDataAvailability, string) {
+		invokeArgs := [][]byte{[]byte(GetPrivateDataAvailability), []byte(chainid)}
+		for _, arg := range args {
+			invokeArgs = append(invokeArgs, []byte(arg))
+		}
+		prop := resetProvider(resources.Qscc_GetPrivateDataAvailability, chainid, &peer2.SignedProposal{}, nil)
+		res := stub.MockInvokeWithSignedProposal("1", invokeArgs, prop)
+		if res.Status != shim.OK {
+			return nil, res.Message
+		}
+		availability := &peer2.PrivateDataAvailability{}
+		require.NoError(t, proto.Unmarshal(res.Payload, availability))
+		return availability, ""
+	}
+	statuses := func(availability *peer2.PrivateDataAvailability) []peer2.PrivateDataEntry_Status {
+		var statuses []peer2.PrivateDataEntry_Status
+		for _, entry := range availability.Entries {
+			assert.Equal(t, uint64(2), entry.BlockNumber)
+			statuses = append(statuses, entry.Status)
+		}
+		return statuses
+	}
+
+	availability, errMsg := query("mycc", "coll1", "0", "10")
+	require.Empty(t, errMsg)
+	assert.Equal(t, []peer2.PrivateDataEntry_Status{peer2.PrivateDataEntry_PRESENT, peer2.PrivateDataEntry_MISSING}, statuses(availability))
+	assert.Equal(t, uint64(1), availability.Entries[1].TxNumber)
+	assert.NotEmpty(t, availability.Entries[1].TxId)
+
+	// the blocks of the range without private data report no entry
+	availability, errMsg = query("mycc", "coll1", "0", "1")
+	require.Empty(t, errMsg)
+	assert.Empty(t, availability.Entries)
+
+	// the private data expires once the ledger commits block 2+2+1
+	for i := 0; i < 3; i++ {
+		commitPvtDataBlockForTesting(t, ledger, []*ledger2.TxSimulationResults{pvtDataSimulationForTesting(t, "other")})
+	}
+	availability, errMsg = query("mycc", "coll1", "2", "2")
+	require.Empty(t, errMsg)
+	assert.Equal(t, []peer2.PrivateDataEntry_Status{peer2.PrivateDataEntry_PURGED, peer2.PrivateDataEntry_PURGED}, statuses(availability))
+
+	_, errMsg = query("mycc", "coll2", "0", "10")
+	assert.Contains(t, errMsg, "Failed to get block to live of collection coll2 of chaincode mycc")
+
+	_, errMsg = query("mycc", "coll1", "10", "20")
+	assert.Equal(t, "Start block number 10 is beyond the ledger height 6", errMsg)
+
+	_, errMsg = query("mycc", "coll1", "2", "1")
+	assert.Equal(t, "End block number 1 is lower than start block number 2", errMsg)
+
+	_, errMsg = query("mycc", "coll1", "0")
+	assert.Equal(t, "A chaincode name, a collection, and start and end block numbers must be provided.", errMsg)
+}
@@ -9,7 +9,9 @@ package qscc
 import (
 	"fmt"
 	"strconv"
+	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
 
 	"github.com/hyperledger/fabric/core/aclmgmt"
@@ -41,6 +43,14 @@ func (e *LedgerQuerier) Enabled() bool             { return true }
 // - GetBlockByNumber returns a block
 // - GetBlockByHash returns a block
 // - GetTransactionByID returns a transaction
+// - GetTxValidationCode returns the validation code of a transaction
+// - GetBlockRange returns a range of blocks
+// - GetBlockByTxIDRange returns the range of blocks holding a range of transactions
+// - GetTransactionsByCreator returns the transactions created by an identity
+// - GetBlocksByTimeRange returns the blocks committed within a time range
+// - GetPrivateDataAvailability returns whether the private data of a range of blocks is available
+// - EvaluatePolicy returns whether identities would satisfy a policy
+// - EvaluateKeyPolicy returns whether identities would satisfy the endorsement policy of a key
 type LedgerQuerier struct {
 	aclProvider aclmgmt.ACLProvider
 }
@@ -49,13 +59,32 @@ var qscclogger = flogging.MustGetLogger("qscc")
 
 // These are function names from Invoke first parameter
 const (
-	GetChainInfo       string = "GetChainInfo"
-	GetBlockByNumber   string = "GetBlockByNumber"
-	GetBlockByHash     string = "GetBlockByHash"
-	GetTransactionByID string = "GetTransactionByID"
-	GetBlockByTxID     string = "GetBlockByTxID"
+	GetChainInfo        string = "GetChainInfo"
+	GetBlockByNumber    string = "GetBlockByNumber"
+	GetBlockByHash      string = "GetBlockByHash"
+	GetTransactionByID  string = "GetTransactionByID"
+	GetBlockByTxID      string = "GetBlockByTxID"
+	GetTxValidationCode string = "GetTxValidationCode"
+
+	GetBlockRange            string = "GetBlockRange"
+	GetBlockByTxIDRange      string = "GetBlockByTxIDRange"
+	GetTransactionsByCreator string = "GetTransactionsByCreator"
+	GetBlocksByTimeRange     string = "GetBlocksByTimeRange"
+
+	GetPrivateDataAvailability string = "GetPrivateDataAvailability"
+
+	EvaluatePolicy    string = "EvaluatePolicy"
+	EvaluateKeyPolicy string = "EvaluateKeyPolicy"
 )
 
+// maxQueryResults is the maximum number of blocks or transactions returned by
+// a single range query, clients retrieve further results with subsequent queries
+const maxQueryResults = 100
+
+// maxQueryBytes is the maximum size of the blocks returned by a single block
+// range query, which returns at least one block whatever its size
+const maxQueryBytes = 32 * 1024 * 1024
+
 // Init is called once per chain when the chain is created.
 // This allows the chaincode to initialize any variables on the ledger prior
 // to any transaction execution on the chain.
@@ -72,6 +101,26 @@ func (e *LedgerQuerier) Init(stub shim.ChaincodeStubInterface) pb.Response {
 // # GetBlockByNumber: Return the block specified by block number in args[2]
 // # GetBlockByHash: Return the block specified by block hash in args[2]
 // # GetTransactionByID: Return the transaction specified by ID in args[2]
+// # GetTxValidationCode: Return the validation code of the transaction specified by ID in args[2]
+// # GetBlockRange: Return the blocks from block number args[2] to block number args[3], inclusive,
+// limited to the optional size in bytes in args[4]
+// # GetBlockByTxIDRange: Return the blocks from the block holding the transaction with ID args[2]
+// to the block holding the transaction with ID args[3], inclusive, limited to the optional size
+// in bytes in args[4]
+// # GetTransactionsByCreator: Return the transactions created by the serialized identity in args[2],
+// from the optional block number in args[3], limited to the optional number of transactions in args[4]
+// # GetBlocksByTimeRange: Return the blocks whose time is at or after args[2] and before args[3],
+// both in RFC 3339 format, limited to the optional number of blocks in args[4]
+// # GetPrivateDataAvailability: Return whether the private data written to the collection args[3]
+// of the chaincode args[2] by the transactions from block number args[4] to block number args[5],
+// inclusive, is present, missing, or purged
+// # EvaluatePolicy: Return a PolicyEvaluation telling whether the serialized identities in args[3:]
+// would satisfy the marshaled SignaturePolicyEnvelope in args[2], regardless of any signature
+// # EvaluateKeyPolicy: Return a PolicyEvaluation telling whether the serialized identities in args[5:]
+// would satisfy the endorsement policy of the key args[4] of the chaincode args[2], in the private
+// data collection args[3] unless empty
+// The range queries return at most maxQueryResults results, and the block range queries at most
+// maxQueryBytes of blocks
 func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 	args := stub.GetArgs()
 
@@ -116,6 +165,22 @@ func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 		return getChainInfo(targetLedger)
 	case GetBlockByTxID:
 		return getBlockByTxID(targetLedger, args[2])
+	case GetTxValidationCode:
+		return getTxValidationCode(targetLedger, args[2])
+	case GetBlockRange:
+		return getBlockRange(targetLedger, args[2:])
+	case GetBlockByTxIDRange:
+		return getBlockByTxIDRange(targetLedger, args[2:])
+	case GetTransactionsByCreator:
+		return getTransactionsByCreator(targetLedger, args[2:])
+	case GetBlocksByTimeRange:
+		return getBlocksByTimeRange(targetLedger, args[2:])
+	case GetPrivateDataAvailability:
+		return getPrivateDataAvailability(targetLedger, args[2:])
+	case EvaluatePolicy:
+		return evaluatePolicy(cid, args[2:])
+	case EvaluateKeyPolicy:
+		return evaluateKeyPolicy(targetLedger, cid, args[2:])
 	}
 
 	return shim.Error(fmt.Sprintf("Requested function %s not found.", fname))
@@ -215,6 +280,209 @@ func getBlockByTxID(vledger ledger.PeerLedger, rawTxID []byte) pb.Response {
 	return shim.Success(bytes)
 }
 
+func getTxValidationCode(vledger ledger.PeerLedger, rawTxID []byte) pb.Response {
+	if rawTxID == nil {
+		return shim.Error("Transaction ID must not be nil.")
+	}
+	txID := string(rawTxID)
+	code, err := vledger.GetTxValidationCodeByTxID(txID)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get validation code for txID %s, error %s", txID, err))
+	}
+
+	bytes, err := utils.Marshal(&pb.TransactionValidationCode{TxId: txID, ValidationCode: code})
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(bytes)
+}
+
+func getBlockRange(vledger ledger.PeerLedger, args [][]byte) pb.Response {
+	if len(args) < 2 {
+		return shim.Error("Start and end block numbers must be provided.")
+	}
+	start, err := strconv.ParseUint(string(args[0]), 10, 64)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to parse start block number with error %s", err))
+	}
+	end, err := strconv.ParseUint(string(args[1]), 10, 64)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to parse end block number with error %s", err))
+	}
+	if end < start {
+		return shim.Error(fmt.Sprintf("End block number %d is lower than start block number %d", end, start))
+	}
+	maxBytes, err := parseMaxBytes(args, 2)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	binfo, err := vledger.GetBlockchainInfo()
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get block info with error %s", err))
+	}
+	if start >= binfo.Height {
+		return shim.Error(fmt.Sprintf("Start block number %d is beyond the ledger height %d", start, binfo.Height))
+	}
+	if end >= binfo.Height {
+		end = binfo.Height - 1
+	}
+
+	return getBlocks(vledger, start, end, maxBytes)
+}
+
+func getBlockByTxIDRange(vledger ledger.PeerLedger, args [][]byte) pb.Response {
+	if len(args) < 2 {
+		return shim.Error("Start and end transaction IDs must be provided.")
+	}
+	maxBytes, err := parseMaxBytes(args, 2)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	startTxID, endTxID := string(args[0]), string(args[1])
+	startBlock, err := vledger.GetBlockByTxID(startTxID)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get block for txID %s, error %s", startTxID, err))
+	}
+	endBlock, err := vledger.GetBlockByTxID(endTxID)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get block for txID %s, error %s", endTxID, err))
+	}
+	start, end := startBlock.Header.Number, endBlock.Header.Number
+	if end < start {
+		return shim.Error(fmt.Sprintf("Transaction %s is committed before transaction %s", endTxID, startTxID))
+	}
+
+	return getBlocks(vledger, start, end, maxBytes)
+}
+
+// getBlocks returns the blocks from start to end, inclusive, up to
+// maxQueryResults blocks and to maxBytes of blocks
+func getBlocks(vledger ledger.PeerLedger, start, end uint64, maxBytes int) pb.Response {
+	if end-start >= maxQueryResults {
+		end = start + maxQueryResults - 1
+	}
+
+	blocks := &pb.LedgerBlocks{}
+	size := 0
+	for bnum := start; bnum <= end; bnum++ {
+		block, err := vledger.GetBlockByNumber(bnum)
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to get block number %d, error %s", bnum, err))
+		}
+		size += proto.Size(block)
+		if size > maxBytes && len(blocks.Blocks) > 0 {
+			break
+		}
+		blocks.Blocks = append(blocks.Blocks, block)
+	}
+
+	bytes, err := utils.Marshal(blocks)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(bytes)
+}
+
+func getTransactionsByCreator(vledger ledger.PeerLedger, args [][]byte) pb.Response {
+	creator := args[0]
+	if len(creator) == 0 {
+		return shim.Error("Creator must not be empty.")
+	}
+	var start uint64
+	if len(args) > 1 {
+		var err error
+		if start, err = strconv.ParseUint(string(args[1]), 10, 64); err != nil {
+			return shim.Error(fmt.Sprintf("Failed to parse start block number with error %s", err))
+		}
+	}
+	limit, err := parseLimit(args, 2)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	txs, err := vledger.GetTransactionsByCreator(creator, start, limit)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get transactions by creator, error %s", err))
+	}
+	ledgerTxs := &pb.LedgerTransactions{}
+	for _, tx := range txs {
+		ledgerTxs.Transactions = append(ledgerTxs.Transactions, &pb.LedgerTransaction{
+			BlockNumber: tx.BlockNum,
+			TxNumber:    tx.TxNum,
+			Transaction: tx.Transaction,
+		})
+	}
+
+	bytes, err := utils.Marshal(ledgerTxs)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(bytes)
+}
+
+func getBlocksByTimeRange(vledger ledger.PeerLedger, args [][]byte) pb.Response {
+	if len(args) < 2 {
+		return shim.Error("Start and end times must be provided.")
+	}
+	start, err := time.Parse(time.RFC3339, string(args[0]))
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to parse start time with error %s", err))
+	}
+	end, err := time.Parse(time.RFC3339, string(args[1]))
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to parse end time with error %s", err))
+	}
+	limit, err := parseLimit(args, 2)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	blocks, err := vledger.GetBlocksByTimeRange(start, end, limit)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get blocks from %s to %s, error %s", start, end, err))
+	}
+
+	bytes, err := utils.Marshal(&pb.LedgerBlocks{Blocks: blocks})
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(bytes)
+}
+
+// parseLimit parses the optional limit on the number of results at args[i],
+// which defaults to and is capped at maxQueryResults
+func parseLimit(args [][]byte, i int) (int, error) {
+	if len(args) <= i {
+		return maxQueryResults, nil
+	}
+	limit, err := strconv.ParseUint(string(args[i]), 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to parse limit with error %s", err)
+	}
+	if limit == 0 || limit > maxQueryResults {
+		return maxQueryResults, nil
+	}
+	return int(limit), nil
+}
+
+func parseMaxBytes(args [][]byte, i int) (int, error) {
+	if len(args) <= i {
+		return maxQueryBytes, nil
+	}
+	maxBytes, err := strconv.ParseUint(string(args[i]), 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to parse maximum size with error %s", err)
+	}
+	if maxBytes == 0 || maxBytes > maxQueryBytes {
+		return maxQueryBytes, nil
+	}
+	return int(maxBytes), nil
+}
+
 func getACLResource(fname string) string {
 	return "qscc/" + fname
 }
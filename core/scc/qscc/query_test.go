@@ -20,7 +20,9 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/aclmgmt/mocks"
@@ -74,7 +76,7 @@ func TestQueryGetChainInfo(t *testing.T) {
 
 	stub, err := setupTestLedger(chainid, path)
 	if err != nil {
-		t.Fatalf(err.Error())
+		t.Fatal(err)
 	}
 
 	args := [][]byte{[]byte(GetChainInfo), []byte(chainid)}
@@ -98,7 +100,7 @@ func TestQueryGetTransactionByID(t *testing.T) {
 
 	stub, err := setupTestLedger(chainid, path)
 	if err != nil {
-		t.Fatalf(err.Error())
+		t.Fatal(err)
 	}
 
 	args := [][]byte{[]byte(GetTransactionByID), []byte(chainid), []byte("1")}
@@ -123,7 +125,7 @@ func TestQueryGetBlockByNumber(t *testing.T) {
 
 	stub, err := setupTestLedger(chainid, path)
 	if err != nil {
-		t.Fatalf(err.Error())
+		t.Fatal(err)
 	}
 
 	// block number 0 (genesis block) would already be present in the ledger
@@ -150,7 +152,7 @@ func TestQueryGetBlockByHash(t *testing.T) {
 
 	stub, err := setupTestLedger(chainid, path)
 	if err != nil {
-		t.Fatalf(err.Error())
+		t.Fatal(err)
 	}
 
 	args := [][]byte{[]byte(GetBlockByHash), []byte(chainid), []byte("0")}
@@ -170,7 +172,7 @@ func TestQueryGetBlockByTxID(t *testing.T) {
 
 	stub, err := setupTestLedger(chainid, path)
 	if err != nil {
-		t.Fatalf(err.Error())
+		t.Fatal(err)
 	}
 
 	args := [][]byte{[]byte(GetBlockByTxID), []byte(chainid), []byte("")}
@@ -186,7 +188,7 @@ func TestFailingAccessControl(t *testing.T) {
 
 	_, err := setupTestLedger(chainid, path)
 	if err != nil {
-		t.Fatalf(err.Error())
+		t.Fatal(err)
 	}
 	e := &LedgerQuerier{
 		aclProvider: mockAclProvider,
@@ -241,6 +243,18 @@ func TestFailingAccessControl(t *testing.T) {
 	// assert that the expectations were met
 	mockAclProvider.AssertExpectations(t)
 
+	// GetBlockRange
+	args = [][]byte{[]byte(GetBlockRange), []byte(chainid), []byte("0"), []byte("1")}
+	sProp, _ = utils.MockSignedEndorserProposalOrPanic(chainid, &peer2.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
+	sProp.Signature = sProp.ProposalBytes
+	// Set the ACLProvider to have a failure
+	resetProvider(resources.Qscc_GetBlockRange, chainid, sProp, errors.New("Failed access control"))
+	res = stub.MockInvokeWithSignedProposal("2", args, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetBlockRange must fail: %s", res.Message)
+	assert.Contains(t, res.Message, "Failed access control")
+	// assert that the expectations were met
+	mockAclProvider.AssertExpectations(t)
+
 	// GetTransactionByID
 	args = [][]byte{[]byte(GetTransactionByID), []byte(chainid), []byte("1")}
 	sProp, _ = utils.MockSignedEndorserProposalOrPanic(chainid, &peer2.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
@@ -261,7 +275,7 @@ func TestQueryNonexistentFunction(t *testing.T) {
 
 	stub, err := setupTestLedger(chainid, path)
 	if err != nil {
-		t.Fatalf(err.Error())
+		t.Fatal(err)
 	}
 
 	args := [][]byte{[]byte("GetBlocks"), []byte(chainid), []byte("arg1")}
@@ -279,7 +293,7 @@ func TestQueryGeneratedBlock(t *testing.T) {
 
 	stub, err := setupTestLedger(chainid, path)
 	if err != nil {
-		t.Fatalf(err.Error())
+		t.Fatal(err)
 	}
 
 	block1 := addBlockForTesting(t, chainid)
@@ -309,7 +323,7 @@ func TestQueryGeneratedBlock(t *testing.T) {
 				}
 				chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
 				if err != nil {
-					t.Fatalf(err.Error())
+					t.Fatal(err)
 				}
 				if common.HeaderType(chdr.Type) == common.HeaderType_ENDORSER_TRANSACTION {
 					args = [][]byte{[]byte(GetBlockByTxID), []byte(chainid), []byte(chdr.TxId)}
@@ -328,6 +342,193 @@ func TestQueryGeneratedBlock(t *testing.T) {
 	}
 }
 
+func TestQueryGetBlockRange(t *testing.T) {
+	chainid := "mytestchainid9"
+	path := tempDir(t, "test9")
+	defer os.RemoveAll(path)
+
+	stub, err := setupTestLedger(chainid, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block1 := addBlockForTesting(t, chainid)
+
+	// the end of the range is capped at the ledger height
+	args := [][]byte{[]byte(GetBlockRange), []byte(chainid), []byte("0"), []byte("5")}
+	prop := resetProvider(resources.Qscc_GetBlockRange, chainid, &peer2.SignedProposal{}, nil)
+	res := stub.MockInvokeWithSignedProposal("1", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, "GetBlockRange failed with err: %s", res.Message)
+	blocks := &peer2.LedgerBlocks{}
+	require.NoError(t, proto.Unmarshal(res.Payload, blocks))
+	require.Len(t, blocks.Blocks, 2)
+	assert.Equal(t, uint64(0), blocks.Blocks[0].Header.Number)
+	assert.Equal(t, block1.Header, blocks.Blocks[1].Header)
+
+	args = [][]byte{[]byte(GetBlockRange), []byte(chainid), []byte("2"), []byte("5")}
+	res = stub.MockInvokeWithSignedProposal("2", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetBlockRange should have failed with a start beyond the ledger height")
+
+	args = [][]byte{[]byte(GetBlockRange), []byte(chainid), []byte("1"), []byte("0")}
+	res = stub.MockInvokeWithSignedProposal("3", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetBlockRange should have failed with an end lower than the start")
+
+	args = [][]byte{[]byte(GetBlockRange), []byte(chainid), []byte("0")}
+	res = stub.MockInvokeWithSignedProposal("4", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetBlockRange should have failed without an end block number")
+
+	// the range is capped at the maximum size, but holds at least a block
+	args = [][]byte{[]byte(GetBlockRange), []byte(chainid), []byte("0"), []byte("5"), []byte("1")}
+	res = stub.MockInvokeWithSignedProposal("5", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, "GetBlockRange failed with err: %s", res.Message)
+	blocks = &peer2.LedgerBlocks{}
+	require.NoError(t, proto.Unmarshal(res.Payload, blocks))
+	require.Len(t, blocks.Blocks, 1)
+	assert.Equal(t, uint64(0), blocks.Blocks[0].Header.Number)
+
+	args = [][]byte{[]byte(GetBlockRange), []byte(chainid), []byte("0"), []byte("5"), []byte("foo")}
+	res = stub.MockInvokeWithSignedProposal("6", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetBlockRange should have failed with an invalid maximum size")
+}
+
+func TestQueryGetBlockByTxIDRange(t *testing.T) {
+	chainid := "mytestchainid14"
+	path := tempDir(t, "test14")
+	defer os.RemoveAll(path)
+
+	stub, err := setupTestLedger(chainid, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block1 := addBlockForTesting(t, chainid)
+	txIDs := txIDsForTesting(t, block1)
+
+	args := [][]byte{[]byte(GetBlockByTxIDRange), []byte(chainid), []byte(txIDs[0]), []byte(txIDs[1])}
+	prop := resetProvider(resources.Qscc_GetBlockByTxIDRange, chainid, &peer2.SignedProposal{}, nil)
+	res := stub.MockInvokeWithSignedProposal("1", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, "GetBlockByTxIDRange failed with err: %s", res.Message)
+	blocks := &peer2.LedgerBlocks{}
+	require.NoError(t, proto.Unmarshal(res.Payload, blocks))
+	require.Len(t, blocks.Blocks, 1)
+	assert.Equal(t, block1.Header, blocks.Blocks[0].Header)
+
+	args = [][]byte{[]byte(GetBlockByTxIDRange), []byte(chainid), []byte(txIDs[0]), []byte("unknown")}
+	res = stub.MockInvokeWithSignedProposal("2", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetBlockByTxIDRange should have failed with an unknown transaction")
+
+	args = [][]byte{[]byte(GetBlockByTxIDRange), []byte(chainid), []byte(txIDs[0])}
+	res = stub.MockInvokeWithSignedProposal("3", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetBlockByTxIDRange should have failed without an end transaction")
+}
+
+func TestQueryGetTxValidationCode(t *testing.T) {
+	chainid := "mytestchainid15"
+	path := tempDir(t, "test15")
+	defer os.RemoveAll(path)
+
+	stub, err := setupTestLedger(chainid, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block1 := addBlockForTesting(t, chainid)
+	txIDs := txIDsForTesting(t, block1)
+
+	args := [][]byte{[]byte(GetTxValidationCode), []byte(chainid), []byte(txIDs[1])}
+	prop := resetProvider(resources.Qscc_GetTxValidationCode, chainid, &peer2.SignedProposal{}, nil)
+	res := stub.MockInvokeWithSignedProposal("1", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, "GetTxValidationCode failed with err: %s", res.Message)
+	code := &peer2.TransactionValidationCode{}
+	require.NoError(t, proto.Unmarshal(res.Payload, code))
+	assert.Equal(t, txIDs[1], code.TxId)
+	assert.Equal(t, peer2.TxValidationCode_VALID, code.ValidationCode)
+
+	args = [][]byte{[]byte(GetTxValidationCode), []byte(chainid), []byte("unknown")}
+	res = stub.MockInvokeWithSignedProposal("2", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetTxValidationCode should have failed with an unknown transaction")
+}
+
+func TestQueryGetTransactionsByCreator(t *testing.T) {
+	chainid := "mytestchainid10"
+	path := tempDir(t, "test10")
+	defer os.RemoveAll(path)
+
+	stub, err := setupTestLedger(chainid, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block1 := addBlockForTesting(t, chainid)
+	env, err := utils.GetEnvelopeFromBlock(block1.Data.Data[0])
+	require.NoError(t, err)
+	payload, err := utils.GetPayload(env)
+	require.NoError(t, err)
+	shdr, err := utils.GetSignatureHeader(payload.Header.SignatureHeader)
+	require.NoError(t, err)
+
+	args := [][]byte{[]byte(GetTransactionsByCreator), []byte(chainid), shdr.Creator, []byte("1")}
+	prop := resetProvider(resources.Qscc_GetTransactionsByCreator, chainid, &peer2.SignedProposal{}, nil)
+	res := stub.MockInvokeWithSignedProposal("1", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, "GetTransactionsByCreator failed with err: %s", res.Message)
+	txs := &peer2.LedgerTransactions{}
+	require.NoError(t, proto.Unmarshal(res.Payload, txs))
+	require.Len(t, txs.Transactions, 2)
+	for i, tx := range txs.Transactions {
+		assert.Equal(t, uint64(1), tx.BlockNumber)
+		assert.Equal(t, uint64(i), tx.TxNumber)
+		assert.Equal(t, int32(peer2.TxValidationCode_VALID), tx.Transaction.ValidationCode)
+	}
+
+	args = [][]byte{[]byte(GetTransactionsByCreator), []byte(chainid), shdr.Creator, []byte("1"), []byte("1")}
+	res = stub.MockInvokeWithSignedProposal("2", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, "GetTransactionsByCreator failed with err: %s", res.Message)
+	require.NoError(t, proto.Unmarshal(res.Payload, txs))
+	assert.Len(t, txs.Transactions, 1)
+
+	args = [][]byte{[]byte(GetTransactionsByCreator), []byte(chainid), []byte(nil)}
+	res = stub.MockInvokeWithSignedProposal("3", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetTransactionsByCreator should have failed with an empty creator")
+
+	args = [][]byte{[]byte(GetTransactionsByCreator), []byte(chainid), shdr.Creator, []byte("one")}
+	res = stub.MockInvokeWithSignedProposal("4", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetTransactionsByCreator should have failed with an invalid start block number")
+}
+
+func TestQueryGetBlocksByTimeRange(t *testing.T) {
+	chainid := "mytestchainid11"
+	path := tempDir(t, "test11")
+	defer os.RemoveAll(path)
+
+	stub, err := setupTestLedger(chainid, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block1 := addBlockForTesting(t, chainid)
+	now := time.Now()
+
+	args := [][]byte{[]byte(GetBlocksByTimeRange), []byte(chainid),
+		[]byte(now.Add(-time.Hour).Format(time.RFC3339)), []byte(now.Add(time.Hour).Format(time.RFC3339))}
+	prop := resetProvider(resources.Qscc_GetBlocksByTimeRange, chainid, &peer2.SignedProposal{}, nil)
+	res := stub.MockInvokeWithSignedProposal("1", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, "GetBlocksByTimeRange failed with err: %s", res.Message)
+	blocks := &peer2.LedgerBlocks{}
+	require.NoError(t, proto.Unmarshal(res.Payload, blocks))
+	require.NotEmpty(t, blocks.Blocks)
+	assert.Equal(t, block1.Header, blocks.Blocks[len(blocks.Blocks)-1].Header)
+
+	args = [][]byte{[]byte(GetBlocksByTimeRange), []byte(chainid),
+		[]byte(now.Add(time.Hour).Format(time.RFC3339)), []byte(now.Add(2 * time.Hour).Format(time.RFC3339))}
+	res = stub.MockInvokeWithSignedProposal("2", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, "GetBlocksByTimeRange failed with err: %s", res.Message)
+	require.NoError(t, proto.Unmarshal(res.Payload, blocks))
+	assert.Empty(t, blocks.Blocks)
+
+	args = [][]byte{[]byte(GetBlocksByTimeRange), []byte(chainid), []byte("yesterday"), []byte(now.Format(time.RFC3339))}
+	res = stub.MockInvokeWithSignedProposal("3", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetBlocksByTimeRange should have failed with an invalid start time")
+
+	args = [][]byte{[]byte(GetBlocksByTimeRange), []byte(chainid), []byte(now.Format(time.RFC3339)), []byte(now.Format(time.RFC3339)), []byte("-1")}
+	res = stub.MockInvokeWithSignedProposal("4", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetBlocksByTimeRange should have failed with an invalid limit")
+}
+
 func addBlockForTesting(t *testing.T, chainid string) *common.Block {
 	ledger := peer.GetLedger(chainid)
 	defer ledger.Close()
@@ -357,6 +558,21 @@ func addBlockForTesting(t *testing.T, chainid string) *common.Block {
 	return block1
 }
 
+// txIDsForTesting returns the IDs of the transactions of the block
+func txIDsForTesting(t *testing.T, block *common.Block) []string {
+	var txIDs []string
+	for _, d := range block.Data.Data {
+		env, err := utils.GetEnvelopeFromBlock(d)
+		require.NoError(t, err)
+		payload, err := utils.GetPayload(env)
+		require.NoError(t, err)
+		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		require.NoError(t, err)
+		txIDs = append(txIDs, chdr.TxId)
+	}
+	return txIDs
+}
+
 var mockAclProvider *mocks.MockACLProvider
 
 func TestMain(m *testing.M) {
@@ -107,6 +107,13 @@ func TestSccProviderImpl_GetQueryExecutorForLedger(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestSccProviderImpl_GetStateAtHeight(t *testing.T) {
+	p := NewProvider(peer.Default, peer.DefaultSupport, inproccontroller.NewRegistry())
+	value, err := p.GetStateAtHeight("", "ns", "key", 1)
+	assert.Nil(t, value)
+	assert.EqualError(t, err, "Could not retrieve ledger for channel ")
+}
+
 func TestCreatePluginSysCCs(t *testing.T) {
 	assert.NotPanics(t, func() { CreatePluginSysCCs(nil) }, "expected successful init")
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
 )
 
 // NewProvider creates a new Provider instance
@@ -81,6 +82,65 @@ func (p *Provider) GetQueryExecutorForLedger(cid string) (ledger.QueryExecutor,
 	return l.NewQueryExecutor()
 }
 
+// GetLedgerHeight returns the height of the ledger of the specified channel
+func (p *Provider) GetLedgerHeight(cid string) (uint64, error) {
+	l := p.Peer.GetLedger(cid)
+	if l == nil {
+		return 0, fmt.Errorf("Could not retrieve ledger for channel %s", cid)
+	}
+
+	info, err := l.GetBlockchainInfo()
+	if err != nil {
+		return 0, err
+	}
+	return info.Height, nil
+}
+
+// GetStateAtHeight returns the value of the key in the specified namespace
+// of the specified channel when the ledger was at the specified height,
+// which is read from the history database of the ledger
+func (p *Provider) GetStateAtHeight(cid, namespace, key string, height uint64) ([]byte, error) {
+	l := p.Peer.GetLedger(cid)
+	if l == nil {
+		return nil, fmt.Errorf("Could not retrieve ledger for channel %s", cid)
+	}
+
+	hqe, err := l.NewHistoryQueryExecutor()
+	if err != nil {
+		return nil, err
+	}
+	itr, err := hqe.GetHistoryForKey(namespace, key)
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Close()
+
+	// the history of the key is in ascending order of the blocks
+	var value []byte
+	for {
+		res, err := itr.Next()
+		if err != nil {
+			return nil, err
+		}
+		if res == nil {
+			return value, nil
+		}
+		mod := res.(*queryresult.KeyModification)
+		block, err := l.GetBlockByTxID(mod.TxId)
+		if err != nil {
+			return nil, fmt.Errorf("Could not retrieve the block of transaction %s: %s", mod.TxId, err)
+		}
+		if block.Header.Number >= height {
+			return value, nil
+		}
+		if mod.IsDelete {
+			value = nil
+		} else {
+			value = mod.Value
+		}
+	}
+}
+
 // IsSysCCAndNotInvokableExternal returns true if the chaincode
 // is a system chaincode and *CANNOT* be invoked through
 // a proposal to this peer
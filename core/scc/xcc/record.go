@@ -0,0 +1,154 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package xcc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/pkg/errors"
+)
+
+// Status is the status of a cross-channel transaction on one of its channels
+type Status string
+
+const (
+	// Prepared means the resources of the transaction are escrowed
+	Prepared Status = "PREPARED"
+	// Committed means the escrowed resources were released
+	Committed Status = "COMMITTED"
+	// Aborted means the escrowed resources were returned
+	Aborted Status = "ABORTED"
+)
+
+// PrepareRequest describes the part of a cross-channel transaction which
+// takes place on the channel the request is submitted to
+type PrepareRequest struct {
+	ID                 string   `json:"id"`
+	CounterpartChannel string   `json:"counterpart_channel"`
+	Primary            bool     `json:"primary"`
+	Chaincode          string   `json:"chaincode"`
+	PrepareArgs        []string `json:"prepare_args"`
+	CommitArgs         []string `json:"commit_args"`
+	AbortArgs          []string `json:"abort_args"`
+}
+
+// Record is the escrow record of a cross-channel transaction on one of its
+// channels, stored under the ID of the transaction in the namespace of the
+// coordinator
+type Record struct {
+	ID                 string   `json:"id"`
+	Channel            string   `json:"channel"`
+	CounterpartChannel string   `json:"counterpart_channel"`
+	Primary            bool     `json:"primary"`
+	Chaincode          string   `json:"chaincode,omitempty"`
+	CommitArgs         []string `json:"commit_args,omitempty"`
+	AbortArgs          []string `json:"abort_args,omitempty"`
+	Status             Status   `json:"status"`
+	// CounterpartHeight is the height of the ledger of the counterpart
+	// channel at which the decision recorded by Status was taken
+	CounterpartHeight uint64 `json:"counterpart_height,omitempty"`
+}
+
+func (req *PrepareRequest) validate(channel string) error {
+	if req.Chaincode == "" {
+		return errors.New("missing chaincode in prepare request")
+	}
+	if req.Chaincode == Name {
+		return errors.Errorf("chaincode %s can't be part of a cross-channel transaction", Name)
+	}
+	record := &Record{ID: req.ID, Channel: channel, CounterpartChannel: req.CounterpartChannel}
+	return record.validate()
+}
+
+func (r *Record) validate() error {
+	if r.ID == "" {
+		return errors.New("missing cross-channel transaction ID")
+	}
+	if strings.ContainsRune(r.ID, 0) {
+		return errors.Errorf("invalid cross-channel transaction ID %q", r.ID)
+	}
+	if r.CounterpartChannel == "" {
+		return errors.New("missing counterpart channel")
+	}
+	if r.CounterpartChannel == r.Channel {
+		return errors.Errorf("counterpart channel must differ from channel %s", r.Channel)
+	}
+	return nil
+}
+
+func unmarshalRecord(recordBytes []byte) (*Record, error) {
+	if recordBytes == nil {
+		return nil, nil
+	}
+	record := &Record{}
+	if err := json.Unmarshal(recordBytes, record); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshaling cross-channel transaction record")
+	}
+	return record, nil
+}
+
+// readRecordAtHeight returns the record of the cross-channel transaction
+// on the supplied channel when its ledger was at the supplied height, nil
+// if there was none
+func readRecordAtHeight(sccp sysccprovider.SystemChaincodeProvider, channel, id string, height uint64) (*Record, error) {
+	recordBytes, err := sccp.GetStateAtHeight(channel, Name, id, height)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("failed reading cross-channel transaction %s on channel %s at height %d", id, channel, height))
+	}
+	return unmarshalRecord(recordBytes)
+}
+
+// checkCounterpart verifies that the records of the two channels describe
+// the same cross-channel transaction with exactly one primary
+func checkCounterpart(record, counterpart *Record) error {
+	if counterpart.Channel != record.CounterpartChannel || counterpart.CounterpartChannel != record.Channel {
+		return errors.Errorf("cross-channel transaction %s on channel %s is not bound to channel %s", record.ID, counterpart.Channel, record.Channel)
+	}
+	if counterpart.Primary == record.Primary {
+		return errors.Errorf("cross-channel transaction %s must have exactly one primary channel", record.ID)
+	}
+	return nil
+}
+
+// CheckDecision returns an error if a cross-channel transaction can't move
+// to the supplied status on the channel of the supplied record, given the
+// record of the counterpart channel, nil if it has none.
+// The primary may abort at any time and may commit once the secondary is
+// prepared; the secondary follows the decision of the primary. Since a
+// prepared secondary only changes status after the primary has decided,
+// both conditions keep holding once they do, which lets peers re-check them
+// at commit time.
+func CheckDecision(record *Record, status Status, counterpart *Record) error {
+	if status == Aborted && record.Primary {
+		return nil
+	}
+	if counterpart == nil {
+		return errors.Errorf("cross-channel transaction %s not found on channel %s", record.ID, record.CounterpartChannel)
+	}
+	if err := checkCounterpart(record, counterpart); err != nil {
+		return err
+	}
+
+	switch {
+	case status == Committed && record.Primary:
+		if counterpart.Status == Prepared || counterpart.Status == Committed {
+			return nil
+		}
+	case status == Committed || status == Aborted:
+		if counterpart.Status == status {
+			return nil
+		}
+	default:
+		return errors.Errorf("invalid decision %s for cross-channel transaction %s", status, record.ID)
+	}
+
+	return errors.Errorf("cross-channel transaction %s can't be %s on channel %s while it is %s on channel %s",
+		record.ID, status, record.Channel, counterpart.Status, counterpart.Channel)
+}
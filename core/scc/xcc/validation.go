@@ -0,0 +1,84 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package xcc
+
+import (
+	"fmt"
+
+	commonerrors "github.com/hyperledger/fabric/common/errors"
+	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/pkg/errors"
+)
+
+// ValidateDecisions re-checks, at commit time, the commit and abort decisions
+// a transaction of the supplied channel writes to the namespace of the
+// coordinator against the records committed on their counterpart channels.
+// The records of the counterpart channels were read by the endorsers from
+// their own ledgers, outside of the read set of the transaction, so every
+// committing peer reads them again from its own ledger of the counterpart
+// channel, pinned at the height the decision was taken at, which gives the
+// same verdict on every peer. A peer whose ledger of the counterpart channel
+// is still below that height can't decide, and returns a
+// VSCCExecutionFailureError which halts the processing of the block instead
+// of invalidating the transaction.
+func ValidateDecisions(channel string, txRWSet *rwsetutil.TxRwSet, sccp sysccprovider.SystemChaincodeProvider) error {
+	for _, ns := range txRWSet.NsRwSets {
+		if ns.NameSpace != Name || ns.KvRwSet == nil {
+			continue
+		}
+		for _, write := range ns.KvRwSet.Writes {
+			if write.IsDelete {
+				return errors.Errorf("cross-channel transaction %s can't be deleted", write.Key)
+			}
+			record, err := unmarshalRecord(write.Value)
+			if err != nil {
+				return err
+			}
+			if record == nil || record.ID != write.Key || record.Channel != channel {
+				return errors.Errorf("invalid record for cross-channel transaction %s", write.Key)
+			}
+			if err := record.validate(); err != nil {
+				return err
+			}
+			if record.Status == Prepared || (record.Status == Aborted && record.Primary) {
+				continue
+			}
+
+			recordBytes, err := readStateAtHeight(sccp, record.CounterpartChannel, record.ID, record.CounterpartHeight)
+			if err != nil {
+				return &commonerrors.VSCCExecutionFailureError{Err: err}
+			}
+			counterpart, err := unmarshalRecord(recordBytes)
+			if err != nil {
+				return err
+			}
+			if err := CheckDecision(record, record.Status, counterpart); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readStateAtHeight returns the serialized record of the cross-channel transaction on
+// the supplied channel when its ledger was at the supplied height, or an
+// error if the ledger of the channel hasn't reached that height yet
+func readStateAtHeight(sccp sysccprovider.SystemChaincodeProvider, channel, id string, height uint64) ([]byte, error) {
+	current, err := sccp.GetLedgerHeight(channel)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("failed getting the height of channel %s", channel))
+	}
+	if current < height {
+		return nil, errors.Errorf("channel %s is at height %d, below the height %d cross-channel transaction %s was decided at", channel, current, height, id)
+	}
+	recordBytes, err := sccp.GetStateAtHeight(channel, Name, id, height)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("failed reading cross-channel transaction %s on channel %s at height %d", id, channel, height))
+	}
+	return recordBytes, nil
+}
@@ -0,0 +1,288 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package xcc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("xcc")
+
+// Name is the name of the cross-channel transaction coordinator, which is
+// also the namespace its escrow records are stored in
+const Name = "xcc"
+
+// These are function names from Invoke first parameter
+const (
+	Prepare   string = "Prepare"
+	Commit    string = "Commit"
+	Abort     string = "Abort"
+	GetRecord string = "GetRecord"
+)
+
+// New returns an instance of the cross-channel transaction coordinator.
+// Typically this is called once per peer.
+func New(sccp sysccprovider.SystemChaincodeProvider) *Coordinator {
+	return &Coordinator{
+		sccp: sccp,
+	}
+}
+
+func (c *Coordinator) Name() string              { return Name }
+func (c *Coordinator) Path() string              { return "github.com/hyperledger/fabric/core/scc/xcc" }
+func (c *Coordinator) InitArgs() [][]byte        { return nil }
+func (c *Coordinator) Chaincode() shim.Chaincode { return c }
+func (c *Coordinator) InvokableExternal() bool   { return true }
+func (c *Coordinator) InvokableCC2CC() bool      { return false }
+func (c *Coordinator) Enabled() bool             { return true }
+
+// Coordinator is an experimental system chaincode which lets a client
+// submit a pair of dependent transactions on two channels, such that
+// either both or neither take effect. It implements a two-phase commit
+// in which the chaincode invoked on each channel first escrows its
+// resources when the transaction is prepared, and then either releases
+// or returns them when the transaction is committed or aborted.
+// One of the two channels, the primary, decides the outcome: the primary
+// commits only after the secondary is prepared, and the secondary follows
+// the decision the primary has committed to its ledger.
+// Since the state of the counterpart channel is read from the local peer,
+// the peers of both channels must be joined to both of them.
+type Coordinator struct {
+	sccp sysccprovider.SystemChaincodeProvider
+}
+
+// Init is called once per chain when the chain is created.
+func (c *Coordinator) Init(stub shim.ChaincodeStubInterface) pb.Response {
+	logger.Info("Init XCC")
+
+	return shim.Success(nil)
+}
+
+// Invoke is called with args[0] containing the function name.
+// Each function requires additional parameters as described below:
+// # Prepare: escrow the resources of the cross-channel transaction described
+// by the JSON encoded PrepareRequest in args[1] by invoking its chaincode
+// with its prepare arguments
+// # Commit: commit the prepared cross-channel transaction args[1] by invoking
+// its chaincode with its commit arguments
+// # Abort: abort the prepared cross-channel transaction args[1] by invoking
+// its chaincode with its abort arguments; if the transaction was never
+// prepared on this channel, args[2] is its counterpart channel and the
+// transaction is recorded as aborted by the primary
+// # GetRecord: return the JSON encoded Record of the cross-channel
+// transaction args[1]
+// Prepare, Commit and Abort return the JSON encoded Record they wrote
+func (c *Coordinator) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
+	args := stub.GetArgs()
+	if len(args) < 2 {
+		return shim.Error(fmt.Sprintf("Incorrect number of arguments, %d", len(args)))
+	}
+	fname := string(args[0])
+
+	logger.Debugf("Invoke function: %s on chain: %s", fname, stub.GetChannelID())
+
+	switch fname {
+	case Prepare:
+		return c.prepare(stub, args[1])
+	case Commit:
+		return c.commit(stub, string(args[1]))
+	case Abort:
+		counterpartChannel := ""
+		if len(args) > 2 {
+			counterpartChannel = string(args[2])
+		}
+		return c.abort(stub, string(args[1]), counterpartChannel)
+	case GetRecord:
+		record, err := getRecord(stub, string(args[1]))
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		if record == nil {
+			return shim.Error(fmt.Sprintf("cross-channel transaction %s not found", args[1]))
+		}
+		return recordResponse(record)
+	}
+
+	return shim.Error(fmt.Sprintf("Requested function %s not found.", fname))
+}
+
+func (c *Coordinator) prepare(stub shim.ChaincodeStubInterface, requestBytes []byte) pb.Response {
+	req := &PrepareRequest{}
+	if err := json.Unmarshal(requestBytes, req); err != nil {
+		return shim.Error(fmt.Sprintf("failed unmarshaling prepare request: %s", err))
+	}
+	channel := stub.GetChannelID()
+	if err := req.validate(channel); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	existing, err := getRecord(stub, req.ID)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	if existing != nil {
+		return shim.Error(fmt.Sprintf("cross-channel transaction %s already exists with status %s", req.ID, existing.Status))
+	}
+
+	record := &Record{
+		ID:                 req.ID,
+		Channel:            channel,
+		CounterpartChannel: req.CounterpartChannel,
+		Primary:            req.Primary,
+		Chaincode:          req.Chaincode,
+		CommitArgs:         req.CommitArgs,
+		AbortArgs:          req.AbortArgs,
+		Status:             Prepared,
+	}
+
+	if !record.Primary {
+		// a secondary can't be prepared once the primary has decided
+		counterpart, _, err := c.counterpartRecord(record)
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		if counterpart != nil {
+			if err := checkCounterpart(record, counterpart); err != nil {
+				return shim.Error(err.Error())
+			}
+			if counterpart.Status != Prepared {
+				return shim.Error(fmt.Sprintf("cross-channel transaction %s is already %s on channel %s", record.ID, counterpart.Status, counterpart.Channel))
+			}
+		}
+	}
+
+	if err := invokeChaincode(stub, record.Chaincode, req.PrepareArgs); err != nil {
+		return shim.Error(err.Error())
+	}
+	return putRecord(stub, record)
+}
+
+func (c *Coordinator) commit(stub shim.ChaincodeStubInterface, id string) pb.Response {
+	return c.decide(stub, id, Committed)
+}
+
+func (c *Coordinator) abort(stub shim.ChaincodeStubInterface, id, counterpartChannel string) pb.Response {
+	record, err := getRecord(stub, id)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	if record != nil || counterpartChannel == "" {
+		return c.decide(stub, id, Aborted)
+	}
+
+	// the transaction was never prepared on this channel: recording it as
+	// aborted by the primary releases a secondary which may have been
+	// prepared, and prevents the transaction from being prepared later on
+	record = &Record{
+		ID:                 id,
+		Channel:            stub.GetChannelID(),
+		CounterpartChannel: counterpartChannel,
+		Primary:            true,
+		Status:             Aborted,
+	}
+	if err := record.validate(); err != nil {
+		return shim.Error(err.Error())
+	}
+	return putRecord(stub, record)
+}
+
+// decide moves a prepared cross-channel transaction to the supplied status,
+// provided the record of the counterpart channel allows it
+func (c *Coordinator) decide(stub shim.ChaincodeStubInterface, id string, status Status) pb.Response {
+	record, err := getRecord(stub, id)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	if record == nil {
+		return shim.Error(fmt.Sprintf("cross-channel transaction %s not found", id))
+	}
+	if record.Status != Prepared {
+		return shim.Error(fmt.Sprintf("cross-channel transaction %s is already %s", id, record.Status))
+	}
+
+	counterpart, height, err := c.counterpartRecord(record)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	if err := CheckDecision(record, status, counterpart); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	args := record.CommitArgs
+	if status == Aborted {
+		args = record.AbortArgs
+	}
+	if err := invokeChaincode(stub, record.Chaincode, args); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	record.Status = status
+	record.CounterpartHeight = height
+	return putRecord(stub, record)
+}
+
+// counterpartRecord returns the record of the cross-channel transaction on
+// the counterpart channel, along with the height of the ledger of the
+// counterpart channel the record was read at, which the committing peers
+// read it again at
+func (c *Coordinator) counterpartRecord(record *Record) (*Record, uint64, error) {
+	height, err := c.sccp.GetLedgerHeight(record.CounterpartChannel)
+	if err != nil {
+		return nil, 0, errors.WithMessage(err, fmt.Sprintf("failed getting the height of channel %s", record.CounterpartChannel))
+	}
+	counterpart, err := readRecordAtHeight(c.sccp, record.CounterpartChannel, record.ID, height)
+	if err != nil {
+		return nil, 0, err
+	}
+	return counterpart, height, nil
+}
+
+func invokeChaincode(stub shim.ChaincodeStubInterface, chaincode string, args []string) error {
+	ccArgs := make([][]byte, len(args))
+	for i, arg := range args {
+		ccArgs[i] = []byte(arg)
+	}
+	resp := stub.InvokeChaincode(chaincode, ccArgs, "")
+	if resp.Status != shim.OK {
+		return errors.Errorf("chaincode %s failed: %s", chaincode, resp.Message)
+	}
+	return nil
+}
+
+func getRecord(stub shim.ChaincodeStubInterface, id string) (*Record, error) {
+	recordBytes, err := stub.GetState(id)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("failed reading cross-channel transaction %s", id))
+	}
+	return unmarshalRecord(recordBytes)
+}
+
+func putRecord(stub shim.ChaincodeStubInterface, record *Record) pb.Response {
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("failed marshaling cross-channel transaction %s: %s", record.ID, err))
+	}
+	if err := stub.PutState(record.ID, recordBytes); err != nil {
+		return shim.Error(fmt.Sprintf("failed writing cross-channel transaction %s: %s", record.ID, err))
+	}
+	return shim.Success(recordBytes)
+}
+
+func recordResponse(record *Record) pb.Response {
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("failed marshaling cross-channel transaction %s: %s", record.ID, err))
+	}
+	return shim.Success(recordBytes)
+}
@@ -0,0 +1,280 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package xcc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	commonerrors "github.com/hyperledger/fabric/common/errors"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// assetChaincode records the arguments it is invoked with and fails when
+// the first one is "fail"
+type assetChaincode struct {
+	calls []string
+}
+
+func (a *assetChaincode) Init(stub shim.ChaincodeStubInterface) pb.Response {
+	return shim.Success(nil)
+}
+
+func (a *assetChaincode) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
+	args := stub.GetStringArgs()
+	if len(args) > 0 && args[0] == "fail" {
+		return shim.Error("asset failure")
+	}
+	a.calls = append(a.calls, strings.Join(args, " "))
+	return shim.Success(nil)
+}
+
+// mockProvider exposes the state of the coordinator on each channel, and
+// records the heights it is read at
+type mockProvider struct {
+	sysccprovider.SystemChaincodeProvider
+	stubs       map[string]*shim.MockStub
+	heights     map[string]uint64
+	readHeights []uint64
+}
+
+func (p *mockProvider) GetStateAtHeight(cid, namespace, key string, height uint64) ([]byte, error) {
+	stub, ok := p.stubs[cid]
+	if !ok {
+		return nil, fmt.Errorf("Could not retrieve ledger for channel %s", cid)
+	}
+	p.readHeights = append(p.readHeights, height)
+	return stub.State[key], nil
+}
+
+func (p *mockProvider) GetLedgerHeight(cid string) (uint64, error) {
+	if _, ok := p.stubs[cid]; !ok {
+		return 0, fmt.Errorf("Could not retrieve ledger for channel %s", cid)
+	}
+	return p.heights[cid], nil
+}
+
+type channel struct {
+	stub  *shim.MockStub
+	asset *assetChaincode
+}
+
+func setupChannels() (*mockProvider, map[string]*channel) {
+	sccp := &mockProvider{stubs: map[string]*shim.MockStub{}, heights: map[string]uint64{}}
+	channels := map[string]*channel{}
+	for _, name := range []string{"a", "b"} {
+		asset := &assetChaincode{}
+		assetStub := shim.NewMockStub("asset", asset)
+		stub := shim.NewMockStub(Name, New(sccp))
+		stub.ChannelID = name
+		stub.MockPeerChaincode("asset", assetStub)
+		sccp.stubs[name] = stub
+		sccp.heights[name] = 10
+		channels[name] = &channel{stub: stub, asset: asset}
+	}
+	return sccp, channels
+}
+
+func prepareArgs(t *testing.T, id, counterpart string, primary bool, prepare string) [][]byte {
+	req := &PrepareRequest{
+		ID:                 id,
+		CounterpartChannel: counterpart,
+		Primary:            primary,
+		Chaincode:          "asset",
+		PrepareArgs:        []string{prepare},
+		CommitArgs:         []string{"release", id},
+		AbortArgs:          []string{"refund", id},
+	}
+	reqBytes, err := json.Marshal(req)
+	assert.NoError(t, err)
+	return [][]byte{[]byte(Prepare), reqBytes}
+}
+
+func recordFrom(t *testing.T, res pb.Response) *Record {
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	record := &Record{}
+	assert.NoError(t, json.Unmarshal(res.Payload, record))
+	return record
+}
+
+func TestCrossChannelCommit(t *testing.T) {
+	_, channels := setupChannels()
+	a, b := channels["a"], channels["b"]
+
+	// the primary can't commit before the secondary is prepared
+	record := recordFrom(t, a.stub.MockInvoke("1", prepareArgs(t, "x1", "b", true, "escrow")))
+	assert.Equal(t, Prepared, record.Status)
+	res := a.stub.MockInvoke("2", [][]byte{[]byte(Commit), []byte("x1")})
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "cross-channel transaction x1 not found on channel b")
+
+	recordFrom(t, b.stub.MockInvoke("3", prepareArgs(t, "x1", "a", false, "escrow")))
+
+	// the secondary can't commit before the primary does
+	res = b.stub.MockInvoke("4", [][]byte{[]byte(Commit), []byte("x1")})
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "can't be COMMITTED on channel b while it is PREPARED on channel a")
+
+	record = recordFrom(t, a.stub.MockInvoke("5", [][]byte{[]byte(Commit), []byte("x1")}))
+	assert.Equal(t, Committed, record.Status)
+	assert.Equal(t, uint64(10), record.CounterpartHeight)
+
+	// once decided, the primary can't abort anymore
+	res = a.stub.MockInvoke("6", [][]byte{[]byte(Abort), []byte("x1")})
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "cross-channel transaction x1 is already COMMITTED")
+
+	record = recordFrom(t, b.stub.MockInvoke("7", [][]byte{[]byte(Commit), []byte("x1")}))
+	assert.Equal(t, Committed, record.Status)
+
+	assert.Equal(t, []string{"escrow", "release x1"}, a.asset.calls)
+	assert.Equal(t, []string{"escrow", "release x1"}, b.asset.calls)
+
+	record = recordFrom(t, b.stub.MockInvoke("8", [][]byte{[]byte(GetRecord), []byte("x1")}))
+	assert.Equal(t, "b", record.Channel)
+	assert.False(t, record.Primary)
+}
+
+func TestCrossChannelAbort(t *testing.T) {
+	_, channels := setupChannels()
+	a, b := channels["a"], channels["b"]
+
+	recordFrom(t, b.stub.MockInvoke("1", prepareArgs(t, "x1", "a", false, "escrow")))
+
+	// the secondary can't abort before the primary does
+	res := b.stub.MockInvoke("2", [][]byte{[]byte(Abort), []byte("x1")})
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "cross-channel transaction x1 not found on channel a")
+
+	// the primary was never prepared, it aborts without invoking the chaincode
+	record := recordFrom(t, a.stub.MockInvoke("3", [][]byte{[]byte(Abort), []byte("x1"), []byte("b")}))
+	assert.Equal(t, Aborted, record.Status)
+	assert.True(t, record.Primary)
+	assert.Empty(t, a.asset.calls)
+
+	res = a.stub.MockInvoke("4", prepareArgs(t, "x1", "b", true, "escrow"))
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "cross-channel transaction x1 already exists with status ABORTED")
+
+	res = b.stub.MockInvoke("5", [][]byte{[]byte(Commit), []byte("x1")})
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+
+	record = recordFrom(t, b.stub.MockInvoke("6", [][]byte{[]byte(Abort), []byte("x1")}))
+	assert.Equal(t, Aborted, record.Status)
+	assert.Equal(t, []string{"escrow", "refund x1"}, b.asset.calls)
+
+	// a secondary can't be prepared once the primary has aborted
+	recordFrom(t, a.stub.MockInvoke("7", [][]byte{[]byte(Abort), []byte("x3"), []byte("b")}))
+	res = b.stub.MockInvoke("8", prepareArgs(t, "x3", "a", false, "escrow"))
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "cross-channel transaction x3 is already ABORTED on channel a")
+}
+
+func TestCrossChannelBadRequests(t *testing.T) {
+	_, channels := setupChannels()
+	a := channels["a"]
+
+	for _, tc := range []struct {
+		args     [][]byte
+		expected string
+	}{
+		{[][]byte{[]byte(Prepare)}, "Incorrect number of arguments, 1"},
+		{[][]byte{[]byte("Foo"), []byte("x1")}, "Requested function Foo not found."},
+		{[][]byte{[]byte(Prepare), []byte("bogus")}, "failed unmarshaling prepare request"},
+		{prepareArgs(t, "", "b", true, "escrow"), "missing cross-channel transaction ID"},
+		{prepareArgs(t, "x1", "a", true, "escrow"), "counterpart channel must differ from channel a"},
+		{prepareArgs(t, "x1", "b", true, "fail"), "chaincode asset failed: asset failure"},
+		{[][]byte{[]byte(Commit), []byte("x1")}, "cross-channel transaction x1 not found"},
+		{[][]byte{[]byte(GetRecord), []byte("x1")}, "cross-channel transaction x1 not found"},
+		{[][]byte{[]byte(Abort), []byte("x1"), []byte("a")}, "counterpart channel must differ from channel a"},
+	} {
+		res := a.stub.MockInvoke("1", tc.args)
+		assert.Equal(t, int32(shim.ERROR), res.Status)
+		assert.Contains(t, res.Message, tc.expected)
+	}
+
+	res := a.stub.MockInvoke("2", prepareArgs(t, "x1", "c", false, "escrow"))
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "failed getting the height of channel c")
+}
+
+func decisionRWSet(t *testing.T, records ...*Record) *rwsetutil.TxRwSet {
+	builder := rwsetutil.NewRWSetBuilder()
+	for _, record := range records {
+		recordBytes, err := json.Marshal(record)
+		assert.NoError(t, err)
+		builder.AddToWriteSet(Name, record.ID, recordBytes)
+	}
+	results, err := builder.GetTxSimulationResults()
+	assert.NoError(t, err)
+	txRWSet := &rwsetutil.TxRwSet{}
+	assert.NoError(t, txRWSet.FromProtoBytes(pubSimulationBytes(t, results)))
+	return txRWSet
+}
+
+func pubSimulationBytes(t *testing.T, results *ledger.TxSimulationResults) []byte {
+	resultsBytes, err := results.GetPubSimulationBytes()
+	assert.NoError(t, err)
+	return resultsBytes
+}
+
+func TestValidateDecisions(t *testing.T) {
+	sccp, channels := setupChannels()
+	a, b := channels["a"], channels["b"]
+	recordFrom(t, b.stub.MockInvoke("1", prepareArgs(t, "x1", "a", false, "escrow")))
+	recordFrom(t, a.stub.MockInvoke("2", prepareArgs(t, "x1", "b", true, "escrow")))
+
+	primary := &Record{ID: "x1", Channel: "a", CounterpartChannel: "b", Primary: true, Status: Committed, CounterpartHeight: 10}
+	secondary := &Record{ID: "x1", Channel: "b", CounterpartChannel: "a", Status: Committed, CounterpartHeight: 10}
+
+	t.Run("primary commit", func(t *testing.T) {
+		sccp.heights["b"] = 12
+		sccp.readHeights = nil
+		assert.NoError(t, ValidateDecisions("a", decisionRWSet(t, primary), sccp))
+		assert.Equal(t, []uint64{10}, sccp.readHeights)
+		sccp.heights["b"] = 10
+	})
+
+	t.Run("secondary commit before the primary", func(t *testing.T) {
+		err := ValidateDecisions("b", decisionRWSet(t, secondary), sccp)
+		assert.EqualError(t, err, "cross-channel transaction x1 can't be COMMITTED on channel b while it is PREPARED on channel a")
+	})
+
+	t.Run("counterpart channel behind", func(t *testing.T) {
+		behind := *primary
+		behind.CounterpartHeight = 11
+		err := ValidateDecisions("a", decisionRWSet(t, &behind), sccp)
+		assert.IsType(t, &commonerrors.VSCCExecutionFailureError{}, err)
+		assert.EqualError(t, err, "channel b is at height 10, below the height 11 cross-channel transaction x1 was decided at")
+
+		sccp.heights["b"] = 11
+		assert.NoError(t, ValidateDecisions("a", decisionRWSet(t, &behind), sccp))
+	})
+
+	t.Run("record of another channel", func(t *testing.T) {
+		err := ValidateDecisions("b", decisionRWSet(t, primary), sccp)
+		assert.EqualError(t, err, "invalid record for cross-channel transaction x1")
+	})
+
+	t.Run("prepare and primary abort", func(t *testing.T) {
+		prepared := *secondary
+		prepared.Status = Prepared
+		aborted := *primary
+		aborted.ID = "x2"
+		aborted.Status = Aborted
+		aborted.CounterpartChannel = "c"
+		assert.NoError(t, ValidateDecisions("b", decisionRWSet(t, &prepared), sccp))
+		assert.NoError(t, ValidateDecisions("a", decisionRWSet(t, &aborted), sccp))
+	})
+}
@@ -8,6 +8,7 @@ package main
 
 import (
 	"context"
+	"encoding/hex"
 	"flag"
 	"fmt"
 	"io/ioutil"
@@ -16,6 +17,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/localmsp"
@@ -44,6 +46,10 @@ var (
 	filtered         bool
 	tlsEnabled       bool
 	mTlsEnabled      bool
+	chaincodeFilter  string
+	eventFilter      string
+	checkpoint       string
+	strip            string
 
 	oldest  = &orderer.SeekPosition{Type: &orderer.SeekPosition_Oldest{Oldest: &orderer.SeekOldest{}}}
 	newest  = &orderer.SeekPosition{Type: &orderer.SeekPosition_Newest{Newest: &orderer.SeekNewest{}}}
@@ -87,12 +93,41 @@ func (r *eventsClient) seekSingle(blockNumber uint64) error {
 	return r.client.Send(r.seekHelper(specific, specific))
 }
 
+func (r *eventsClient) seekCheckpoint(token []byte) error {
+	start := &orderer.SeekPosition{Type: &orderer.SeekPosition_Checkpoint{Checkpoint: &orderer.SeekCheckpoint{Token: token}}}
+	return r.client.Send(r.seekHelper(start, maxStop))
+}
+
 func (r *eventsClient) seekHelper(start *orderer.SeekPosition, stop *orderer.SeekPosition) *common.Envelope {
-	env, err := utils.CreateSignedEnvelopeWithTLSBinding(common.HeaderType_DELIVER_SEEK_INFO, channelID, r.signer, &orderer.SeekInfo{
+	// the chaincode event filter is only honored by the filtered delivery service,
+	// and the block slimming by the delivery service of regular blocks
+	var extension proto.Message
+	if filtered && (chaincodeFilter != "" || eventFilter != "") {
+		extension = &peer.ChaincodeEventFilter{ChaincodeId: chaincodeFilter, EventName: eventFilter}
+	}
+	if !filtered && strip != "" {
+		slimming := &peer.BlockSlimming{}
+		for _, part := range strings.Split(strip, ",") {
+			switch part {
+			case "signatures":
+				slimming.StripSignatures = true
+			case "creators":
+				slimming.StripCreators = true
+			case "rwsets":
+				slimming.StripRwsets = true
+			case "events":
+				slimming.StripEvents = true
+			default:
+				panic(fmt.Sprintf("unknown part %s to strip", part))
+			}
+		}
+		extension = slimming
+	}
+	env, err := utils.CreateSignedEnvelopeWithExtension(common.HeaderType_DELIVER_SEEK_INFO, channelID, r.signer, &orderer.SeekInfo{
 		Start:    start,
 		Stop:     stop,
 		Behavior: orderer.SeekInfo_BLOCK_UNTIL_READY,
-	}, 0, 0, r.tlsCertHash)
+	}, 0, 0, r.tlsCertHash, extension)
 	if err != nil {
 		panic(err)
 	}
@@ -132,6 +167,9 @@ func (r *eventsClient) readEventsStream() {
 				logger.Info("Received filtered block: ", t.FilteredBlock.Number)
 			}
 		}
+		if len(msg.Checkpoint) != 0 {
+			logger.Info("Checkpoint: ", hex.EncodeToString(msg.Checkpoint))
+		}
 	}
 }
 
@@ -223,7 +261,16 @@ func main() {
 		events.tlsCertHash = util.ComputeSHA256(grpcClient.Certificate().Certificate[0])
 	}
 
-	events.seek(seek)
+	if checkpoint != "" {
+		token, err := hex.DecodeString(checkpoint)
+		if err != nil {
+			logger.Info("Invalid checkpoint:", err)
+			return
+		}
+		err = events.seekCheckpoint(token)
+	} else {
+		err = events.seek(seek)
+	}
 	if err != nil {
 		logger.Info("Received error:", err)
 		return
@@ -242,6 +289,10 @@ func readCLInputs() {
 	flag.StringVar(&clientKeyPath, "clientKey", "", "Specify path to the client TLS key")
 	flag.StringVar(&clientCertPath, "clientCert", "", "Specify path to the client TLS certificate")
 	flag.StringVar(&serverRootCAPath, "rootCert", "", "Specify path to the server root CA certificate")
+	flag.StringVar(&chaincodeFilter, "chaincodeFilter", "", "Regular expression the chaincode ID of the filtered events must match.")
+	flag.StringVar(&eventFilter, "eventFilter", "", "Regular expression the name of the filtered events must match.")
+	flag.StringVar(&strip, "strip", "", "Comma separated parts of the regular blocks the peer strips before delivering them, among signatures, creators, rwsets and events.")
+	flag.StringVar(&checkpoint, "checkpoint", "", "Resume from the hex encoded checkpoint printed along with a previously received block, overrides seek.")
 	flag.IntVar(&seek, "seek", OLDEST, "Specify the range of requested blocks."+
 		"Acceptable values:"+
 		"-2 (or -1) to start from oldest (or newest) and keep at it indefinitely."+
@@ -18,6 +18,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/gossip/api"
 	"github.com/hyperledger/fabric/gossip/common"
 	"github.com/hyperledger/fabric/gossip/identity"
@@ -89,6 +90,7 @@ func NewCommInstanceWithServer(port int, idMapper identity.Mapper, peerIdentity
 		exitChan:       make(chan struct{}),
 		subscriptions:  make([]chan proto.ReceivedMessage, 0),
 		dialTimeout:    util.GetDurationOrDefault("peer.gossip.dialTimeout", defDialTimeout),
+		dnsRefresh:     util.GetDurationOrDefault("peer.gossip.dnsRefreshInterval", 0),
 		tlsCerts:       certs,
 	}
 	commInst.connStore = newConnStore(commInst, commInst.logger)
@@ -144,6 +146,7 @@ type commImpl struct {
 	port           int
 	stopping       int32
 	dialTimeout    time.Duration
+	dnsRefresh     time.Duration
 }
 
 func (c *commImpl) createConnection(endpoint string, expectedPKIID common.PKIidType) (*connection, error) {
@@ -163,12 +166,15 @@ func (c *commImpl) createConnection(endpoint string, expectedPKIID common.PKIidT
 	dialOpts = append(dialOpts, c.secureDialOpts()...)
 	dialOpts = append(dialOpts, grpc.WithBlock())
 	dialOpts = append(dialOpts, c.opts...)
+	dnsRefresh, watchDNS := comm.WithDNSRefresh(endpoint, c.dnsRefresh)
+	dialOpts = append(dialOpts, dnsRefresh)
 	ctx := context.Background()
 	ctx, _ = context.WithTimeout(ctx, c.dialTimeout)
 	cc, err = grpc.DialContext(ctx, endpoint, dialOpts...)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	watchDNS(cc)
 
 	cl := proto.NewGossipClient(cc)
 
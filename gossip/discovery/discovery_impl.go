@@ -10,8 +10,8 @@ import (
 	"bytes"
 	"fmt"
 	"math"
+	"net"
 	"strconv"
-	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -194,8 +194,9 @@ func (d *gossipDiscoveryImpl) Connect(member NetworkMember, id identifier) {
 }
 
 func (d *gossipDiscoveryImpl) isMyOwnEndpoint(endpoint string) bool {
-	return endpoint == fmt.Sprintf("127.0.0.1:%d", d.port) || endpoint == fmt.Sprintf("localhost:%d", d.port) ||
-		endpoint == d.self.InternalEndpoint || endpoint == d.self.Endpoint
+	port := strconv.Itoa(d.port)
+	return endpoint == net.JoinHostPort("127.0.0.1", port) || endpoint == net.JoinHostPort("localhost", port) ||
+		endpoint == net.JoinHostPort("::1", port) || endpoint == d.self.InternalEndpoint || endpoint == d.self.Endpoint
 }
 
 func (d *gossipDiscoveryImpl) validateSelfConfig() {
@@ -204,11 +205,11 @@ func (d *gossipDiscoveryImpl) validateSelfConfig() {
 		d.logger.Panic("Internal endpoint is empty:", endpoint)
 	}
 
-	internalEndpointSplit := strings.Split(endpoint, ":")
-	if len(internalEndpointSplit) != 2 {
+	_, port, err := net.SplitHostPort(endpoint)
+	if err != nil {
 		d.logger.Panicf("Self endpoint %s isn't formatted as 'host:port'", endpoint)
 	}
-	myPort, err := strconv.ParseInt(internalEndpointSplit[1], 10, 64)
+	myPort, err := strconv.ParseInt(port, 10, 64)
 	if err != nil {
 		d.logger.Panicf("Self endpoint %s has not valid port, %+v", endpoint, errors.WithStack(err))
 	}
@@ -405,6 +405,23 @@ func TestToString(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("%d, %d", now.UnixNano(), 42), fmt.Sprint(ts))
 }
 
+func TestSelfEndpointIPv6(t *testing.T) {
+	d := &gossipDiscoveryImpl{
+		self:   NetworkMember{InternalEndpoint: "[2001:db8::1]:7051", Endpoint: "[2001:db8::2]:7051"},
+		logger: util.GetLogger(util.LoggingDiscoveryModule, "[2001:db8::1]:7051"),
+	}
+	d.validateSelfConfig()
+	assert.Equal(t, 7051, d.port)
+
+	for _, endpoint := range []string{"[2001:db8::1]:7051", "[2001:db8::2]:7051", "[::1]:7051", "127.0.0.1:7051", "localhost:7051"} {
+		assert.True(t, d.isMyOwnEndpoint(endpoint), endpoint)
+	}
+	assert.False(t, d.isMyOwnEndpoint("[::1]:7052"))
+
+	d.self.InternalEndpoint = "2001:db8::1:7051"
+	assert.Panics(t, d.validateSelfConfig)
+}
+
 func TestBadInput(t *testing.T) {
 	inst := createDiscoveryInstance(2048, fmt.Sprintf("d%d", 0), []string{})
 	inst.Discovery.(*gossipDiscoveryImpl).handleMsgFromComm(nil)
@@ -39,6 +39,7 @@ type Config struct {
 	RequestStateInfoInterval    time.Duration
 	BlockExpirationInterval     time.Duration
 	StateInfoCacheSweepInterval time.Duration
+	Archive                     bool
 }
 
 // GossipChannel defines an object that deals with all channel-related messages
@@ -851,6 +852,7 @@ func (gc *gossipChannel) updateProperties(ledgerHeight uint64, chaincodes []*pro
 			LeftChannel:  leftChannel,
 			LedgerHeight: ledgerHeight,
 			Chaincodes:   chaincodes,
+			Archive:      gc.GetConf().Archive,
 		},
 	}
 	m := &proto.GossipMessage{
@@ -307,6 +307,27 @@ func TestSelf(t *testing.T) {
 	assert.Equal(t, gMsg.GetStateInfo().PkiId, []byte("1"))
 }
 
+func TestSelfArchive(t *testing.T) {
+	t.Parallel()
+
+	cs := &cryptoService{}
+	jcm := &joinChanMsg{
+		members2AnchorPeers: map[string][]api.AnchorPeer{
+			string(orgInChannelA): {},
+		},
+	}
+	archiveConf := conf
+	archiveConf.Archive = true
+	adapter := new(gossipAdapterMock)
+	adapter.On("GetConf").Return(archiveConf)
+	adapter.On("GetMembership").Return([]discovery.NetworkMember{})
+	adapter.On("GetOrgOfPeer", mock.Anything).Return(orgInChannelA)
+	adapter.On("Gossip", mock.Anything)
+	gc := NewGossipChannel(common.PKIidType("1"), orgInChannelA, cs, channelA, adapter, jcm)
+	gc.UpdateLedgerHeight(1)
+	assert.True(t, gc.Self().GetStateInfo().Properties.Archive)
+}
+
 func TestMsgStoreNotExpire(t *testing.T) {
 	t.Parallel()
 
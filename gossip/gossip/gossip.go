@@ -100,6 +100,7 @@ type SendCriteria struct {
 	IsEligible filter.RoutingFilter // IsEligible defines whether a specific peer is eligible of receiving the message
 	Channel    common.ChainID       // Channel specifies a channel to send this message on. \
 	// Only peers that joined the channel would receive this message
+	OnAck func(peer comm.RemotePeer) // OnAck, if not nil, is invoked for every peer that acknowledged the message
 }
 
 // String returns a string representation of this SendCriteria
@@ -133,4 +134,6 @@ type Config struct {
 
 	InternalEndpoint string // Endpoint we publish to peers in our organization
 	ExternalEndpoint string // Peer publishes this endpoint instead of SelfEndpoint to foreign organizations
+
+	Archive bool // Whether the peer advertises itself as retaining the full history of its ledgers
 }
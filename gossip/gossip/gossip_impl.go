@@ -9,7 +9,9 @@ package gossip
 import (
 	"bytes"
 	"fmt"
+	"net"
 	"reflect"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -229,7 +231,7 @@ func (g *gossipServiceImpl) learnAnchorPeers(channel string, orgOfAnchorPeers ap
 			g.logger.Warning("Got invalid port (0), skipping connecting to anchor peer", ap)
 			continue
 		}
-		endpoint := fmt.Sprintf("%s:%d", ap.Host, ap.Port)
+		endpoint := net.JoinHostPort(ap.Host, strconv.Itoa(int(ap.Port)))
 		// Skip connecting to self
 		if g.selfNetworkMember().Endpoint == endpoint || g.selfNetworkMember().InternalEndpoint == endpoint {
 			g.logger.Info("Anchor peer with same endpoint, skipping connecting to myself")
@@ -646,6 +648,9 @@ func (g *gossipServiceImpl) SendByCriteria(msg *proto.SignedGossipMessage, crite
 
 	for _, res := range results {
 		if res.Error() == "" {
+			if criteria.OnAck != nil {
+				criteria.OnAck(res.RemotePeer)
+			}
 			continue
 		}
 		g.logger.Warning("Failed sending to", res.Endpoint, "error:", res.Error())
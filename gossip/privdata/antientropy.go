@@ -0,0 +1,452 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	util2 "github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/committer"
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/gossip/comm"
+	gcommon "github.com/hyperledger/fabric/gossip/common"
+	privdatacommon "github.com/hyperledger/fabric/gossip/privdata/common"
+	"github.com/hyperledger/fabric/gossip/util"
+	"github.com/hyperledger/fabric/protos/common"
+	proto "github.com/hyperledger/fabric/protos/gossip"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+const (
+	antiEntropyIntervalConfigKey = "peer.gossip.pvtData.antiEntropy.interval"
+	antiEntropyIntervalDefault   = time.Minute
+	antiEntropyBlocksConfigKey   = "peer.gossip.pvtData.antiEntropy.blocks"
+	antiEntropyBlocksDefault     = 100
+
+	// summaryFalsePositiveRate is the rate of the private write sets the
+	// summaries of the peers wrongly report to be held, which the other
+	// peers then attempt to pull in vain
+	summaryFalsePositiveRate = 0.01
+	// maxSummaryHashFunctions bounds the hash functions of the received
+	// summaries, the optimal count for summaryFalsePositiveRate being 7
+	maxSummaryHashFunctions = 32
+)
+
+// AntiEntropy detects the private data missing from the ledger of the peer
+// without waiting for new blocks. The peers of a channel periodically send
+// each other summaries of the private data they hold for their most recent
+// blocks, and a peer receiving a summary pulls the private data it is
+// eligible for, misses, and the summary reports to be held by the sender.
+type AntiEntropy interface {
+	// Start starts sending the summaries and handling those received
+	Start()
+	// Stop stops the anti-entropy
+	Stop()
+}
+
+// NoOpAntiEntropy is the AntiEntropy used when the anti-entropy of private
+// data is disabled
+type NoOpAntiEntropy struct {
+}
+
+func (*NoOpAntiEntropy) Start() {
+	logger.Debug("Private data anti-entropy has been disabled")
+}
+
+func (*NoOpAntiEntropy) Stop() {
+	// do nothing
+}
+
+// AntiEntropyConfig holds config flags that are read from core.yaml
+type AntiEntropyConfig struct {
+	interval time.Duration
+	blocks   uint64
+}
+
+// GetAntiEntropyConfig reads the anti-entropy configuration values from core.yaml
+func GetAntiEntropyConfig() AntiEntropyConfig {
+	interval := viper.GetDuration(antiEntropyIntervalConfigKey)
+	if interval == 0 {
+		logger.Warning("Configuration key", antiEntropyIntervalConfigKey, "isn't set, defaulting to", antiEntropyIntervalDefault)
+		interval = antiEntropyIntervalDefault
+	}
+	blocks := viper.GetInt(antiEntropyBlocksConfigKey)
+	if blocks <= 0 {
+		logger.Warning("Configuration key", antiEntropyBlocksConfigKey, "isn't set, defaulting to", antiEntropyBlocksDefault)
+		blocks = antiEntropyBlocksDefault
+	}
+	return AntiEntropyConfig{interval: interval, blocks: uint64(blocks)}
+}
+
+// AntiEntropySupport aggregates the interfaces the anti-entropy relies on
+type AntiEntropySupport struct {
+	ChainID string
+	privdata.CollectionStore
+	committer.Committer
+	Fetcher
+}
+
+type antiEntropy struct {
+	AntiEntropySupport
+	gossip
+	config         AntiEntropyConfig
+	selfSignedData common.SignedData
+	msgChan        <-chan proto.ReceivedMessage
+	stopChan       chan struct{}
+	// lastSummaries is the time the last summary of each peer was handled,
+	// by PKI-ID, only accessed by the goroutine running the anti-entropy
+	lastSummaries map[string]time.Time
+	startOnce     sync.Once
+	stopOnce      sync.Once
+}
+
+// NewAntiEntropy creates a new instance of the anti-entropy of private data
+func NewAntiEntropy(support AntiEntropySupport, g gossip, selfSignedData common.SignedData, config AntiEntropyConfig) AntiEntropy {
+	ae := &antiEntropy{
+		AntiEntropySupport: support,
+		gossip:             g,
+		config:             config,
+		selfSignedData:     selfSignedData,
+		stopChan:           make(chan struct{}),
+		lastSummaries:      make(map[string]time.Time),
+	}
+	_, ae.msgChan = ae.Accept(func(o interface{}) bool {
+		msg := o.(proto.ReceivedMessage).GetGossipMessage()
+		if !bytes.Equal(msg.Channel, []byte(ae.ChainID)) {
+			return false
+		}
+		return msg.GetPvtDataSummary() != nil
+	}, true)
+	return ae
+}
+
+func (ae *antiEntropy) Start() {
+	ae.startOnce.Do(func() {
+		go ae.run()
+	})
+}
+
+func (ae *antiEntropy) Stop() {
+	ae.stopOnce.Do(func() {
+		close(ae.stopChan)
+	})
+}
+
+func (ae *antiEntropy) run() {
+	ticker := time.NewTicker(ae.config.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ae.stopChan:
+			return
+		case msg := <-ae.msgChan:
+			if msg == nil {
+				// comm module stopped, hence this channel
+				// closed
+				return
+			}
+			if err := ae.handleSummary(msg); err != nil {
+				logger.Warning("Failed handling private data summary from", msg.GetConnectionInfo().Endpoint, ":", err)
+			}
+		case <-ticker.C:
+			if err := ae.sendSummary(); err != nil {
+				logger.Warning("Failed sending private data summary:", err)
+			}
+		}
+	}
+}
+
+// recentBlocks returns the range of the most recent blocks the anti-entropy
+// covers that end at most with the given block, false if the ledger is empty
+func (ae *antiEntropy) recentBlocks(maxBlock uint64) (startBlock, endBlock uint64, ok bool) {
+	height, err := ae.LedgerHeight()
+	if err != nil || height == 0 {
+		return 0, 0, false
+	}
+	endBlock = height - 1
+	if maxBlock < endBlock {
+		endBlock = maxBlock
+	}
+	if endBlock+1 > ae.config.blocks {
+		startBlock = endBlock + 1 - ae.config.blocks
+	}
+	return startBlock, endBlock, true
+}
+
+// sendSummary sends the summary of the private data held for the most recent
+// blocks to a random peer of the channel
+func (ae *antiEntropy) sendSummary() error {
+	startBlock, endBlock, ok := ae.recentBlocks(math.MaxUint64)
+	if !ok {
+		return nil
+	}
+	var keys [][]byte
+	for blockSeq := startBlock; blockSeq <= endBlock; blockSeq++ {
+		blockAndPvtData, err := ae.GetPvtDataAndBlockByNum(blockSeq)
+		if err != nil {
+			return errors.WithMessage(err, "failed retrieving private data")
+		}
+		for seqInBlock, txPvtData := range blockAndPvtData.BlockPvtData {
+			if txPvtData.WriteSet == nil {
+				continue
+			}
+			for _, ns := range txPvtData.WriteSet.NsPvtRwset {
+				for _, coll := range ns.CollectionPvtRwset {
+					keys = append(keys, summaryKey(blockSeq, seqInBlock, ns.Namespace, coll.CollectionName))
+				}
+			}
+		}
+	}
+	if len(keys) == 0 {
+		logger.Debug("No private data held for blocks", startBlock, "to", endBlock, "on channel", ae.ChainID)
+		return nil
+	}
+
+	members := ae.PeersOfChannel(gcommon.ChainID(ae.ChainID))
+	if len(members) == 0 {
+		logger.Debug("No peers to send the private data summary to on channel", ae.ChainID)
+		return nil
+	}
+	member := members[rand.Intn(len(members))]
+
+	filter := newBloomFilter(len(keys), summaryFalsePositiveRate)
+	for _, key := range keys {
+		filter.add(key)
+	}
+	logger.Debug("Sending", member.Endpoint, "the summary of", len(keys), "private write sets of blocks", startBlock, "to", endBlock)
+	ae.Send(&proto.GossipMessage{
+		Tag:     proto.GossipMessage_CHAN_ONLY,
+		Channel: []byte(ae.ChainID),
+		Nonce:   util.RandomUInt64(),
+		Content: &proto.GossipMessage_PvtDataSummary{
+			PvtDataSummary: &proto.PvtDataSummary{
+				StartBlock:    startBlock,
+				EndBlock:      endBlock,
+				BloomFilter:   filter.bits,
+				HashFunctions: filter.hashFunctions,
+			},
+		},
+	}, &comm.RemotePeer{PKIID: member.PKIid, Endpoint: member.PreferredEndpoint()})
+	return nil
+}
+
+// handleSummary pulls from the sender of the summary the private data it
+// reports to hold, that this peer is eligible for and misses
+func (ae *antiEntropy) handleSummary(msg proto.ReceivedMessage) error {
+	summary := msg.GetGossipMessage().GetPvtDataSummary()
+	if len(summary.BloomFilter) == 0 || summary.HashFunctions == 0 || summary.HashFunctions > maxSummaryHashFunctions {
+		return errors.New("malformed summary")
+	}
+	if !ae.isMember(msg.GetConnectionInfo().ID) {
+		return errors.New("the sender is not a peer of the channel")
+	}
+	if !ae.acceptSummary(msg.GetConnectionInfo().ID, time.Now()) {
+		return errors.New("the sender sent too many summaries")
+	}
+	filter := &bloomFilter{bits: summary.BloomFilter, hashFunctions: summary.HashFunctions}
+	startBlock, endBlock, ok := ae.recentBlocks(summary.EndBlock)
+	if !ok {
+		return nil
+	}
+	if startBlock < summary.StartBlock {
+		startBlock = summary.StartBlock
+	}
+	height, err := ae.LedgerHeight()
+	if err != nil {
+		return errors.WithMessage(err, "failed retrieving ledger height")
+	}
+
+	// the sender is the preferred source of the missing private data
+	sources := []*peer.Endorsement{{Endorser: msg.GetConnectionInfo().Identity}}
+	dig2src := make(dig2sources)
+	for blockSeq := startBlock; blockSeq <= endBlock; blockSeq++ {
+		blockAndPvtData, err := ae.GetPvtDataAndBlockByNum(blockSeq)
+		if err != nil {
+			return errors.WithMessage(err, "failed retrieving private data")
+		}
+		block := blockAndPvtData.Block
+		if block.Metadata == nil || len(block.Metadata.Metadata) <= int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+			return errors.Errorf("block %d lacks a Tx filter bitmap", blockSeq)
+		}
+		txsFilter := txValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+		if len(txsFilter) != len(block.Data.Data) {
+			return errors.Errorf("block %d data size(%d) is different from Tx filter size(%d)", blockSeq, len(block.Data.Data), len(txsFilter))
+		}
+		blockData(block.Data.Data).forEachTxn(txsFilter, func(seqInBlock uint64, chdr *common.ChannelHeader, txRWSet *rwsetutil.TxRwSet, _ []*peer.Endorsement) {
+			txPvtData := blockAndPvtData.BlockPvtData[seqInBlock]
+			for _, ns := range txRWSet.NsRwSets {
+				for _, hashedCollection := range ns.CollHashedRwSets {
+					if !containsWrites(chdr.TxId, ns.NameSpace, hashedCollection) {
+						continue
+					}
+					if txPvtData != nil && txPvtData.Has(ns.NameSpace, hashedCollection.CollectionName) {
+						continue
+					}
+					if !filter.contains(summaryKey(blockSeq, seqInBlock, ns.NameSpace, hashedCollection.CollectionName)) {
+						continue
+					}
+					if !ae.isEligible(block, chdr, ns.NameSpace, hashedCollection.CollectionName, blockSeq, height) {
+						continue
+					}
+					dig2src[privdatacommon.DigKey{
+						TxId:       chdr.TxId,
+						Namespace:  ns.NameSpace,
+						Collection: hashedCollection.CollectionName,
+						BlockSeq:   blockSeq,
+						SeqInBlock: seqInBlock,
+					}] = sources
+				}
+			}
+		})
+	}
+	if len(dig2src) == 0 {
+		return nil
+	}
+
+	logger.Info("Detected", len(dig2src), "private write sets missing from blocks", startBlock, "to", endBlock, "held by", msg.GetConnectionInfo().Endpoint)
+	fetchedData, err := ae.fetch(dig2src)
+	if err != nil {
+		return errors.WithMessage(err, "failed fetching private data")
+	}
+	if len(fetchedData.AvailableElements) == 0 {
+		return nil
+	}
+	pvtdataHashMismatch, err := ae.CommitPvtData(preparePvtDataToCommit(ae.Committer, fetchedData.AvailableElements))
+	logMismatched(pvtdataHashMismatch)
+	if err != nil {
+		return errors.Wrap(err, "failed to commit private data")
+	}
+	return nil
+}
+
+// isMember returns true if the peer is a member of the channel
+func (ae *antiEntropy) isMember(pkiID gcommon.PKIidType) bool {
+	for _, member := range ae.PeersOfChannel(gcommon.ChainID(ae.ChainID)) {
+		if bytes.Equal(member.PKIid, pkiID) {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptSummary rate-limits the summaries handled per peer. The peers send
+// one summary per interval to a random peer, so a peer's summaries less than
+// half an interval apart, allowing for the delays of the network, are dropped.
+func (ae *antiEntropy) acceptSummary(pkiID gcommon.PKIidType, now time.Time) bool {
+	for id, last := range ae.lastSummaries {
+		if now.Sub(last) >= ae.config.interval/2 {
+			delete(ae.lastSummaries, id)
+		}
+	}
+	if _, exists := ae.lastSummaries[string(pkiID)]; exists {
+		return false
+	}
+	ae.lastSummaries[string(pkiID)] = now
+	return true
+}
+
+// isEligible checks if this peer is eligible for the private data of the
+// collection written by the transaction, and if it is persisted and isn't
+// purged yet
+func (ae *antiEntropy) isEligible(block *common.Block, chdr *common.ChannelHeader, namespace, collection string, blockSeq, height uint64) bool {
+	cc := common.CollectionCriteria{
+		Channel:    ae.ChainID,
+		Namespace:  namespace,
+		Collection: collection,
+		TxId:       chdr.TxId,
+	}
+	ap, err := ae.RetrieveCollectionAccessPolicy(cc)
+	if err != nil {
+		logger.Debug("Failed obtaining policy for", cc, ":", err, "skipping collection")
+		return false
+	}
+	filt := ap.AccessFilter()
+	if filt == nil || !filt(ae.selfSignedData) {
+		return false
+	}
+	persistenceConfig, err := ae.RetrieveCollectionPersistenceConfigs(cc)
+	if err != nil {
+		logger.Debug("Failed obtaining persistence config for", cc, ":", err, "skipping collection")
+		return false
+	}
+	if persistenceConfig.Ephemeral() {
+		enabled, err := ephemeralCollectionsEnabled(ae.Committer, block)
+		if err != nil {
+			logger.Debug("Failed resolving if the ephemeral collections are enabled for", cc, ":", err, "skipping collection")
+			return false
+		}
+		if enabled {
+			return false
+		}
+	}
+	btl := persistenceConfig.BlockToLive()
+	return btl == 0 || addWithOverflow(blockSeq, btl) >= height
+}
+
+// summaryKey returns the key of a private write set in the bloom filter of
+// the summaries
+func summaryKey(blockSeq, seqInBlock uint64, namespace, collection string) []byte {
+	key := make([]byte, 16, 16+len(namespace)+1+len(collection))
+	binary.BigEndian.PutUint64(key, blockSeq)
+	binary.BigEndian.PutUint64(key[8:], seqInBlock)
+	key = append(key, namespace...)
+	key = append(key, 0)
+	return append(key, collection...)
+}
+
+// bloomFilter is a bloom filter whose hash functions are derived from the
+// SHA256 hash of the keys by double hashing
+type bloomFilter struct {
+	bits          []byte
+	hashFunctions uint32
+}
+
+// newBloomFilter creates a bloom filter sized for the given count of keys
+// and rate of false positives
+func newBloomFilter(keys int, falsePositiveRate float64) *bloomFilter {
+	bitCount := math.Ceil(-float64(keys) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	hashFunctions := uint32(math.Max(1, math.Round(bitCount/float64(keys)*math.Ln2)))
+	return &bloomFilter{
+		bits:          make([]byte, (int(bitCount)+7)/8),
+		hashFunctions: hashFunctions,
+	}
+}
+
+func (f *bloomFilter) positions(key []byte) []uint64 {
+	hash := util2.ComputeSHA256(key)
+	h1 := binary.BigEndian.Uint64(hash[:8])
+	h2 := binary.BigEndian.Uint64(hash[8:16])
+	bitCount := uint64(len(f.bits)) * 8
+	positions := make([]uint64, f.hashFunctions)
+	for i := range positions {
+		positions[i] = (h1 + uint64(i)*h2) % bitCount
+	}
+	return positions
+}
+
+func (f *bloomFilter) add(key []byte) {
+	for _, pos := range f.positions(key) {
+		f.bits[pos/8] |= 1 << (pos % 8)
+	}
+}
+
+func (f *bloomFilter) contains(key []byte) bool {
+	for _, pos := range f.positions(key) {
+		if f.bits[pos/8]&(1<<(pos%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,242 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	util2 "github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/gossip/api"
+	"github.com/hyperledger/fabric/gossip/comm"
+	gcommon "github.com/hyperledger/fabric/gossip/common"
+	"github.com/hyperledger/fabric/gossip/discovery"
+	"github.com/hyperledger/fabric/gossip/filter"
+	privdatacommon "github.com/hyperledger/fabric/gossip/privdata/common"
+	fcommon "github.com/hyperledger/fabric/protos/common"
+	proto "github.com/hyperledger/fabric/protos/gossip"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type antiEntropyGossip struct {
+	members []discovery.NetworkMember
+	sent    chan *proto.GossipMessage
+	msgChan chan proto.ReceivedMessage
+}
+
+func newAntiEntropyGossip(members ...discovery.NetworkMember) *antiEntropyGossip {
+	return &antiEntropyGossip{
+		members: members,
+		sent:    make(chan *proto.GossipMessage, 1),
+		msgChan: make(chan proto.ReceivedMessage, 1),
+	}
+}
+
+func (g *antiEntropyGossip) Send(msg *proto.GossipMessage, peers ...*comm.RemotePeer) {
+	g.sent <- msg
+}
+
+func (g *antiEntropyGossip) PeersOfChannel(gcommon.ChainID) []discovery.NetworkMember {
+	return g.members
+}
+
+func (g *antiEntropyGossip) PeerFilter(channel gcommon.ChainID, messagePredicate api.SubChannelSelectionCriteria) (filter.RoutingFilter, error) {
+	panic("implement me")
+}
+
+func (g *antiEntropyGossip) Accept(acceptor gcommon.MessageAcceptor, passThrough bool) (<-chan *proto.GossipMessage, <-chan proto.ReceivedMessage) {
+	return nil, g.msgChan
+}
+
+type antiEntropyFetcher struct {
+	dig2src dig2sources
+}
+
+func (f *antiEntropyFetcher) fetch(dig2src dig2sources) (*privdatacommon.FetchedPvtDataContainer, error) {
+	f.dig2src = dig2src
+	res := &privdatacommon.FetchedPvtDataContainer{}
+	for dig := range dig2src {
+		res.AvailableElements = append(res.AvailableElements, &proto.PvtDataElement{
+			Digest: &proto.PvtDataDigest{
+				TxId:       dig.TxId,
+				Namespace:  dig.Namespace,
+				Collection: dig.Collection,
+				BlockSeq:   dig.BlockSeq,
+				SeqInBlock: dig.SeqInBlock,
+			},
+			Payload: [][]byte{[]byte("rws-pre-image")},
+		})
+	}
+	return res, nil
+}
+
+func asBlockPvtData(data []*ledger.TxPvtData) map[uint64]*ledger.TxPvtData {
+	res := make(map[uint64]*ledger.TxPvtData)
+	for _, txPvtData := range data {
+		res[txPvtData.SeqInBlock] = txPvtData
+	}
+	return res
+}
+
+func TestAntiEntropy(t *testing.T) {
+	// Scenario: the peer p1 holds the private data of the collections c1 and c2
+	// written by tx1, and of c1 and c3 written by tx2, while the peer p2 only
+	// holds that of c1 written by tx1, and isn't eligible for c3.
+	// After receiving the summary of p1, p2 pulls the private data it misses.
+	hash := util2.ComputeSHA256([]byte("rws-pre-image"))
+	bf := &blockFactory{channelID: "test"}
+	block := bf.AddTxn("tx1", "ns1", hash, "c1", "c2").AddTxn("tx2", "ns1", hash, "c1", "c3").create()
+	pdFactory := &pvtDataFactory{}
+	senderPvtData := pdFactory.addRWSet().addNSRWSet("ns1", "c1", "c2").addRWSet().addNSRWSet("ns1", "c1", "c3").create()
+	receiverPvtData := pdFactory.addRWSet().addNSRWSet("ns1", "c1").create()
+	config := AntiEntropyConfig{interval: time.Hour, blocks: 100}
+
+	senderCommitter := &committerMock{}
+	senderCommitter.On("LedgerHeight").Return(uint64(1), nil)
+	senderCommitter.On("GetPvtDataAndBlockByNum", uint64(0)).Return(&ledger.BlockAndPvtData{
+		Block:        block,
+		BlockPvtData: asBlockPvtData(senderPvtData),
+	}, nil)
+	senderGossip := newAntiEntropyGossip(membership(peerData{"p2", 1})...)
+	sender := NewAntiEntropy(AntiEntropySupport{
+		ChainID:   "test",
+		Committer: senderCommitter,
+	}, senderGossip, fcommon.SignedData{Identity: []byte("p1")}, config).(*antiEntropy)
+
+	assert.NoError(t, sender.sendSummary())
+	msg := <-senderGossip.sent
+	assert.Equal(t, proto.GossipMessage_CHAN_ONLY, msg.Tag)
+	assert.Equal(t, []byte("test"), msg.Channel)
+	summary := msg.GetPvtDataSummary()
+	assert.Equal(t, uint64(0), summary.StartBlock)
+	assert.Equal(t, uint64(0), summary.EndBlock)
+	assert.Equal(t, uint32(7), summary.HashFunctions)
+
+	cs := newCollectionStore()
+	cs.withPolicy("c1", 0).thatMapsTo("p2")
+	cs.withPolicy("c2", 0).thatMapsTo("p2")
+	cs.withPolicy("c3", 0).thatMapsTo("p1")
+	receiverCommitter := &committerMock{}
+	receiverCommitter.On("LedgerHeight").Return(uint64(1), nil)
+	receiverCommitter.On("GetPvtDataAndBlockByNum", uint64(0)).Return(&ledger.BlockAndPvtData{
+		Block:        block,
+		BlockPvtData: asBlockPvtData(receiverPvtData),
+	}, nil)
+	receiverCommitter.On("GetBlocks", []uint64{0}).Return([]*fcommon.Block{block})
+	committed := make(chan []*ledger.BlockPvtData, 1)
+	receiverCommitter.On("CommitPvtData", mock.Anything).Run(func(args mock.Arguments) {
+		committed <- args.Get(0).([]*ledger.BlockPvtData)
+	}).Return([]*ledger.PvtdataHashMismatch(nil), nil)
+	fetcher := &antiEntropyFetcher{}
+	receiverGossip := newAntiEntropyGossip(membership(peerData{"p1", 1})...)
+	receiver := NewAntiEntropy(AntiEntropySupport{
+		ChainID:         "test",
+		CollectionStore: cs,
+		Committer:       receiverCommitter,
+		Fetcher:         fetcher,
+	}, receiverGossip, fcommon.SignedData{Identity: []byte("p2")}, config)
+	receiver.Start()
+	defer receiver.Stop()
+
+	signedMsg, _ := msg.NoopSign()
+	receiverGossip.msgChan <- &receivedMsg{SignedGossipMessage: signedMsg, RemotePeer: &comm.RemotePeer{PKIID: gcommon.PKIidType("p1")}}
+	var blockPvtData []*ledger.BlockPvtData
+	select {
+	case blockPvtData = <-committed:
+	case <-time.After(10 * time.Second):
+		t.Fatal("The missing private data wasn't committed")
+	}
+
+	tx1c2 := privdatacommon.DigKey{TxId: "tx1", Namespace: "ns1", Collection: "c2", BlockSeq: 0, SeqInBlock: 0}
+	tx2c1 := privdatacommon.DigKey{TxId: "tx2", Namespace: "ns1", Collection: "c1", BlockSeq: 0, SeqInBlock: 1}
+	assert.Len(t, fetcher.dig2src, 2)
+	// the sender of the summary is the preferred source
+	assert.Equal(t, []byte("p1"), fetcher.dig2src[tx1c2][0].Endorser)
+	assert.Equal(t, []byte("p1"), fetcher.dig2src[tx2c1][0].Endorser)
+
+	assert.Len(t, blockPvtData, 1)
+	assert.Equal(t, uint64(0), blockPvtData[0].BlockNum)
+	var committedPvtData privateData = blockPvtData[0].WriteSets
+	assert.True(t, committedPvtData.Equal(asBlockPvtData(pdFactory.addRWSet().addNSRWSet("ns1", "c2").addRWSet().addNSRWSet("ns1", "c1").create())))
+}
+
+func TestAntiEntropyHandleSummary(t *testing.T) {
+	hash := util2.ComputeSHA256([]byte("rws-pre-image"))
+	bf := &blockFactory{channelID: "test"}
+	block := bf.AddTxn("tx1", "ns1", hash, "c1").create()
+	committer := &committerMock{}
+	committer.On("LedgerHeight").Return(uint64(12), nil)
+	committer.On("GetPvtDataAndBlockByNum", mock.Anything).Return(&ledger.BlockAndPvtData{
+		Block:        block,
+		BlockPvtData: map[uint64]*ledger.TxPvtData{},
+	}, nil)
+	committer.On("GetBlocks", mock.Anything).Return([]*fcommon.Block{block})
+	committer.On("CommitPvtData", mock.Anything).Return([]*ledger.PvtdataHashMismatch(nil), nil)
+	fetcher := &antiEntropyFetcher{}
+	ae := NewAntiEntropy(AntiEntropySupport{
+		ChainID:         "test",
+		CollectionStore: newCollectionStore().withPolicy("c1", 10).thatMapsTo("p2"),
+		Committer:       committer,
+		Fetcher:         fetcher,
+	}, newAntiEntropyGossip(membership(peerData{"p1", 12})...), fcommon.SignedData{Identity: []byte("p2")}, AntiEntropyConfig{interval: time.Hour, blocks: 5}).(*antiEntropy)
+
+	summaryMsg := func(sender string, summary *proto.PvtDataSummary) proto.ReceivedMessage {
+		msg, _ := (&proto.GossipMessage{
+			Tag:     proto.GossipMessage_CHAN_ONLY,
+			Channel: []byte("test"),
+			Content: &proto.GossipMessage_PvtDataSummary{PvtDataSummary: summary},
+		}).NoopSign()
+		return &receivedMsg{SignedGossipMessage: msg, RemotePeer: &comm.RemotePeer{PKIID: gcommon.PKIidType(sender)}}
+	}
+
+	assert.EqualError(t, ae.handleSummary(summaryMsg("p1", &proto.PvtDataSummary{})), "malformed summary")
+	assert.EqualError(t, ae.handleSummary(summaryMsg("p1", &proto.PvtDataSummary{BloomFilter: []byte{1}, HashFunctions: 33})), "malformed summary")
+
+	// the summaries of the peers which aren't members of the channel are rejected
+	filter := &bloomFilter{bits: []byte{0xff}, hashFunctions: 1}
+	assert.EqualError(t, ae.handleSummary(summaryMsg("p3", &proto.PvtDataSummary{StartBlock: 0, EndBlock: 20, BloomFilter: filter.bits, HashFunctions: 1})), "the sender is not a peer of the channel")
+	assert.Nil(t, fetcher.dig2src)
+
+	// the summary reports to hold every private write set of the blocks 0 to 20,
+	// but only the 5 most recent blocks of the ledger are inspected
+	assert.NoError(t, ae.handleSummary(summaryMsg("p1", &proto.PvtDataSummary{StartBlock: 0, EndBlock: 20, BloomFilter: filter.bits, HashFunctions: 1})))
+	assert.Len(t, fetcher.dig2src, 5)
+	for blockSeq := uint64(7); blockSeq <= 11; blockSeq++ {
+		assert.Contains(t, fetcher.dig2src, privdatacommon.DigKey{TxId: "tx1", Namespace: "ns1", Collection: "c1", BlockSeq: blockSeq})
+	}
+	committer.AssertNotCalled(t, "GetPvtDataAndBlockByNum", uint64(6))
+	committer.AssertNotCalled(t, "GetPvtDataAndBlockByNum", uint64(12))
+
+	// the next summary of the peer is dropped until half an interval passed
+	assert.EqualError(t, ae.handleSummary(summaryMsg("p1", &proto.PvtDataSummary{StartBlock: 0, EndBlock: 20, BloomFilter: filter.bits, HashFunctions: 1})), "the sender sent too many summaries")
+	now := time.Now()
+	assert.False(t, ae.acceptSummary(gcommon.PKIidType("p1"), now.Add(29*time.Minute)))
+	assert.True(t, ae.acceptSummary(gcommon.PKIidType("p3"), now.Add(29*time.Minute)))
+	assert.True(t, ae.acceptSummary(gcommon.PKIidType("p1"), now.Add(31*time.Minute)))
+	assert.False(t, ae.acceptSummary(gcommon.PKIidType("p3"), now.Add(31*time.Minute)))
+}
+
+func TestBloomFilter(t *testing.T) {
+	filter := newBloomFilter(1000, summaryFalsePositiveRate)
+	assert.Equal(t, uint32(7), filter.hashFunctions)
+	for i := 0; i < 1000; i++ {
+		filter.add(summaryKey(uint64(i), 0, "ns", fmt.Sprintf("coll%d", i)))
+	}
+	for i := 0; i < 1000; i++ {
+		assert.True(t, filter.contains(summaryKey(uint64(i), 0, "ns", fmt.Sprintf("coll%d", i))))
+	}
+	falsePositives := 0
+	for i := 1000; i < 11000; i++ {
+		if filter.contains(summaryKey(uint64(i), 0, "ns", fmt.Sprintf("coll%d", i))) {
+			falsePositives++
+		}
+	}
+	assert.True(t, falsePositives < 300, "%d false positives", falsePositives)
+}
@@ -8,17 +8,23 @@ package privdata
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang/protobuf/proto"
-	util2 "github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/tracing"
 	"github.com/hyperledger/fabric/core/committer"
 	"github.com/hyperledger/fabric/core/committer/txvalidator"
 	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/config/reload"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	ledgerutil "github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/core/transientstore"
 	privdatacommon "github.com/hyperledger/fabric/gossip/privdata/common"
 	"github.com/hyperledger/fabric/gossip/util"
@@ -114,12 +120,26 @@ type Support struct {
 	committer.Committer
 	TransientStore
 	Fetcher
+	// Metrics is the scope of the durations of the validation, the
+	// retrieval of the private data and the commit of the blocks, no
+	// metrics are emitted if it is nil
+	Metrics metrics.Scope
 }
 
 type coordinator struct {
 	selfSignedData common.SignedData
 	Support
 	transientBlockRetention uint64
+
+	validationDuration   metrics.Histogram
+	pvtDataFetchDuration metrics.Histogram
+	commitDuration       metrics.Histogram
+
+	// Number of blocks committed by the coordinator, accessed atomically
+	// since blocks are validated while the previous ones are committed
+	commits uint64
+	// Effects of the last committed block, nil if unknown
+	lastCommitted *blockEffects
 }
 
 // NewCoordinator creates a new instance of coordinator
@@ -129,7 +149,19 @@ func NewCoordinator(support Support, selfSignedData common.SignedData) Coordinat
 		logger.Warning("Configuration key", transientBlockRetentionConfigKey, "isn't set, defaulting to", transientBlockRetentionDefault)
 		transientBlockRetention = transientBlockRetentionDefault
 	}
-	return &coordinator{Support: support, selfSignedData: selfSignedData, transientBlockRetention: transientBlockRetention}
+	metricsScope := support.Metrics
+	if metricsScope == nil {
+		metricsScope = metrics.NewNoOpScope()
+	}
+	metricsScope = metricsScope.Tagged(map[string]string{"channel": support.ChainID})
+	return &coordinator{
+		Support:                 support,
+		selfSignedData:          selfSignedData,
+		transientBlockRetention: transientBlockRetention,
+		validationDuration:      metricsScope.Histogram("validation_duration"),
+		pvtDataFetchDuration:    metricsScope.Histogram("pvtdata_fetch_duration"),
+		commitDuration:          metricsScope.Histogram("commit_duration"),
+	}
 }
 
 // StorePvtData used to persist private date into transient store
@@ -138,41 +170,90 @@ func (c *coordinator) StorePvtData(txID string, privData *transientstore2.TxPvtR
 }
 
 // StoreBlock stores block with private data into the ledger
-func (c *coordinator) StoreBlock(block *common.Block, privateDataSets util.PvtDataCollections) error {
+func (c *coordinator) StoreBlock(block *common.Block, privateDataSets util.PvtDataCollections) (err error) {
+	if err := checkBlock(block); err != nil {
+		return err
+	}
+
+	logger.Infof("[%s] Received block [%d] from buffer", c.ChainID, block.Header.Number)
+
+	span := c.startStoreBlockSpan(block)
+	defer func() {
+		span.SetError(err)
+		span.End()
+	}()
+
+	if err = c.validate(span, block); err != nil {
+		return err
+	}
+	return c.commit(span, block, privateDataSets, nil)
+}
+
+func checkBlock(block *common.Block) error {
 	if block.Data == nil {
 		return errors.New("Block data is empty")
 	}
 	if block.Header == nil {
 		return errors.New("Block header is nil")
 	}
+	return nil
+}
 
-	logger.Infof("[%s] Received block [%d] from buffer", c.ChainID, block.Header.Number)
+// startStoreBlockSpan starts the span of the commit of the block. The trace
+// context of the transactions isn't carried by the blocks, so the commit of
+// each block is traced on its own
+func (c *coordinator) startStoreBlockSpan(block *common.Block) *tracing.Span {
+	_, span := tracing.Start(context.Background(), "committer.StoreBlock")
+	span.SetAttribute("channel", c.ChainID)
+	span.SetAttribute("block", block.Header.Number)
+	span.SetAttribute("tx_count", len(block.Data.Data))
+	return span
+}
 
+// validate validates the transactions of the block, recording their
+// validation codes in its metadata
+func (c *coordinator) validate(span *tracing.Span, block *common.Block) error {
 	logger.Debugf("[%s] Validating block [%d]", c.ChainID, block.Header.Number)
+	validateSpan := span.Child("committer.Validate")
+	startValidation := time.Now()
 	err := c.Validator.Validate(block)
+	c.validationDuration.RecordDuration(time.Since(startValidation))
+	validateSpan.SetError(err)
+	validateSpan.End()
 	if err != nil {
 		logger.Errorf("Validation failed: %+v", err)
 		return err
 	}
+	return nil
+}
 
+// commit commits the validated block along with its private data, the
+// effects of the block are those computed by ValidateBlock, if any
+func (c *coordinator) commit(span *tracing.Span, block *common.Block, privateDataSets util.PvtDataCollections, effects *blockEffects) error {
 	blockAndPvtData := &ledger.BlockAndPvtData{
 		Block:        block,
 		BlockPvtData: make(map[uint64]*ledger.TxPvtData),
 	}
 
-	ownedRWsets, err := computeOwnedRWsets(block, privateDataSets)
+	hashFunc, err := valueHashFunc(c.Committer, block)
+	if err != nil {
+		logger.Warning("Failed resolving the write-set hashing algorithm", err)
+		return err
+	}
+
+	ownedRWsets, err := computeOwnedRWsets(block, privateDataSets, hashFunc)
 	if err != nil {
 		logger.Warning("Failed computing owned RWSets", err)
 		return err
 	}
 
-	privateInfo, err := c.listMissingPrivateData(block, ownedRWsets)
+	privateInfo, err := c.listMissingPrivateData(block, ownedRWsets, hashFunc)
 	if err != nil {
 		logger.Warning(err)
 		return err
 	}
 
-	retryThresh := viper.GetDuration("peer.gossip.pvtData.pullRetryThreshold")
+	retryThresh := reload.GetDuration("peer.gossip.pvtData.pullRetryThreshold")
 	var bFetchFromPeers bool // defaults to false
 	if len(privateInfo.missingKeys) == 0 {
 		logger.Debugf("[%s] No missing collection private write sets to fetch from remote peers", c.ChainID)
@@ -184,7 +265,7 @@ func (c *coordinator) StoreBlock(block *common.Block, privateDataSets util.PvtDa
 	startPull := time.Now()
 	limit := startPull.Add(retryThresh)
 	for len(privateInfo.missingKeys) > 0 && time.Now().Before(limit) {
-		c.fetchFromPeers(block.Header.Number, ownedRWsets, privateInfo)
+		c.fetchFromPeers(block.Header.Number, ownedRWsets, privateInfo, hashFunc)
 		// If succeeded to fetch everything, no need to sleep before
 		// retry
 		if len(privateInfo.missingKeys) == 0 {
@@ -192,6 +273,7 @@ func (c *coordinator) StoreBlock(block *common.Block, privateDataSets util.PvtDa
 		}
 		time.Sleep(pullRetrySleepInterval)
 	}
+	c.pvtDataFetchDuration.RecordDuration(time.Since(startPull))
 	elapsedPull := int64(time.Since(startPull) / time.Millisecond) // duration in ms
 
 	// Only log results if we actually attempted to fetch
@@ -226,13 +308,21 @@ func (c *coordinator) StoreBlock(block *common.Block, privateDataSets util.PvtDa
 	}
 
 	// commit block and private data
+	commitSpan := span.Child("committer.Commit")
+	startCommit := time.Now()
 	err = c.CommitWithPvtData(blockAndPvtData)
+	c.commitDuration.RecordDuration(time.Since(startCommit))
+	commitSpan.SetError(err)
+	commitSpan.End()
 	if err != nil {
 		return errors.Wrap(err, "commit failed")
 	}
+	c.lastCommitted = effects
+	atomic.AddUint64(&c.commits, 1)
 
-	if len(blockAndPvtData.BlockPvtData) > 0 {
+	if len(blockAndPvtData.BlockPvtData) > 0 || privateInfo.ephemeral {
 		// Finally, purge all transactions in block - valid or not valid.
+		// The private data of ephemeral collections must not outlive the commit.
 		if err := c.PurgeByTxids(privateInfo.txns); err != nil {
 			logger.Error("Purging transactions", privateInfo.txns, "failed:", err)
 		}
@@ -249,7 +339,7 @@ func (c *coordinator) StoreBlock(block *common.Block, privateDataSets util.PvtDa
 	return nil
 }
 
-func (c *coordinator) fetchFromPeers(blockSeq uint64, ownedRWsets map[rwSetKey][]byte, privateInfo *privateDataInfo) {
+func (c *coordinator) fetchFromPeers(blockSeq uint64, ownedRWsets map[rwSetKey][]byte, privateInfo *privateDataInfo, hashFunc func([]byte) []byte) {
 	dig2src := make(map[privdatacommon.DigKey][]*peer.Endorsement)
 	privateInfo.missingKeys.foreach(func(k rwSetKey) {
 		logger.Debug("Fetching", k, "from peers")
@@ -272,7 +362,7 @@ func (c *coordinator) fetchFromPeers(blockSeq uint64, ownedRWsets map[rwSetKey][
 	for _, element := range fetchedData.AvailableElements {
 		dig := element.Digest
 		for _, rws := range element.Payload {
-			hash := hex.EncodeToString(util2.ComputeSHA256(rws))
+			hash := hex.EncodeToString(hashFunc(rws))
 			key := rwSetKey{
 				txID:       dig.TxId,
 				namespace:  dig.Namespace,
@@ -307,14 +397,14 @@ func (c *coordinator) fetchFromPeers(blockSeq uint64, ownedRWsets map[rwSetKey][
 	}
 }
 
-func (c *coordinator) fetchMissingFromTransientStore(missing rwSetKeysByTxIDs, ownedRWsets map[rwSetKey][]byte) {
+func (c *coordinator) fetchMissingFromTransientStore(missing rwSetKeysByTxIDs, ownedRWsets map[rwSetKey][]byte, hashFunc func([]byte) []byte) {
 	// Check transient store
 	for txAndSeq, filter := range missing.FiltersByTxIDs() {
-		c.fetchFromTransientStore(txAndSeq, filter, ownedRWsets)
+		c.fetchFromTransientStore(txAndSeq, filter, ownedRWsets, hashFunc)
 	}
 }
 
-func (c *coordinator) fetchFromTransientStore(txAndSeq txAndSeqInBlock, filter ledger.PvtNsCollFilter, ownedRWsets map[rwSetKey][]byte) {
+func (c *coordinator) fetchFromTransientStore(txAndSeq txAndSeqInBlock, filter ledger.PvtNsCollFilter, ownedRWsets map[rwSetKey][]byte, hashFunc func([]byte) []byte) {
 	iterator, err := c.TransientStore.GetTxPvtRWSetByTxid(txAndSeq.txID, filter)
 	if err != nil {
 		logger.Warning("Failed obtaining iterator from transient store:", err)
@@ -347,7 +437,7 @@ func (c *coordinator) fetchFromTransientStore(txAndSeq txAndSeqInBlock, filter l
 					seqInBlock: txAndSeq.seqInBlock,
 					collection: col.CollectionName,
 					namespace:  ns.Namespace,
-					hash:       hex.EncodeToString(util2.ComputeSHA256(col.Rwset)),
+					hash:       hex.EncodeToString(hashFunc(col.Rwset)),
 				}
 				// populate the ownedRWsets with the RW set from the transient store
 				ownedRWsets[key] = col.Rwset
@@ -356,8 +446,65 @@ func (c *coordinator) fetchFromTransientStore(txAndSeq txAndSeqInBlock, filter l
 	} // iterating over the TxPvtRWSet results
 }
 
+// valueHashFunc returns the function hashing the values of the write-sets of the
+// block, which the config block its LAST_CONFIG metadata points to selects
+func valueHashFunc(c committer.Committer, block *common.Block) (func([]byte) []byte, error) {
+	if len(block.Data.Data) == 0 {
+		// no write-set to hash
+		return ledgerutil.ComputeHash, nil
+	}
+	configBlock, err := lastConfigBlock(c, block)
+	if err != nil {
+		return nil, err
+	}
+	// the blocks of the ledgers which do not start with a config block, only
+	// constructed by tests, are hashed with the default algorithm
+	if configBlock == nil {
+		return ledgerutil.ComputeHash, nil
+	}
+	return channelconfig.RWSetHashFuncFromConfigBlock(configBlock)
+}
+
+// ephemeralCollectionsEnabled returns true if the config block the LAST_CONFIG
+// metadata of the block points to enables the ephemeral collections, whose
+// private data is otherwise persisted as the one of any other collection
+func ephemeralCollectionsEnabled(c committer.Committer, block *common.Block) (bool, error) {
+	if len(block.Data.Data) == 0 {
+		return false, nil
+	}
+	configBlock, err := lastConfigBlock(c, block)
+	if err != nil || configBlock == nil {
+		return false, err
+	}
+	return channelconfig.EphemeralCollectionsFromConfigBlock(configBlock)
+}
+
+// lastConfigBlock returns the config block the LAST_CONFIG metadata of the
+// block points to, nil if it isn't a config block
+func lastConfigBlock(c committer.Committer, block *common.Block) (*common.Block, error) {
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(common.BlockMetadataIndex_LAST_CONFIG) {
+		return nil, errors.New("Block.Metadata is nil or Block.Metadata lacks a last config index")
+	}
+	configBlockNum, err := utils.GetLastConfigIndexFromBlock(block)
+	if err != nil {
+		return nil, err
+	}
+	configBlock := block
+	if configBlockNum != block.Header.Number {
+		blocks := c.GetBlocks([]uint64{configBlockNum})
+		if len(blocks) == 0 || blocks[0] == nil {
+			return nil, errors.Errorf("config block [%d] is not available", configBlockNum)
+		}
+		configBlock = blocks[0]
+	}
+	if !utils.IsConfigBlock(configBlock) {
+		return nil, nil
+	}
+	return configBlock, nil
+}
+
 // computeOwnedRWsets identifies which block private data we already have
-func computeOwnedRWsets(block *common.Block, blockPvtData util.PvtDataCollections) (rwsetByKeys, error) {
+func computeOwnedRWsets(block *common.Block, blockPvtData util.PvtDataCollections, hashFunc func([]byte) []byte) (rwsetByKeys, error) {
 	lastBlockSeq := len(block.Data.Data) - 1
 
 	ownedRWsets := make(map[rwSetKey][]byte)
@@ -381,7 +528,7 @@ func computeOwnedRWsets(block *common.Block, blockPvtData util.PvtDataCollection
 		}
 		for _, ns := range txPvtData.WriteSet.NsPvtRwset {
 			for _, col := range ns.CollectionPvtRwset {
-				computedHash := hex.EncodeToString(util2.ComputeSHA256(col.Rwset))
+				computedHash := hex.EncodeToString(hashFunc(col.Rwset))
 				ownedRWsets[rwSetKey{
 					txID:       chdr.TxId,
 					seqInBlock: txPvtData.SeqInBlock,
@@ -622,10 +769,12 @@ type privateDataInfo struct {
 	missingKeys             rwsetKeys
 	txns                    txns
 	missingRWSButIneligible []rwSetKey
+	// ephemeral is true if the block contains private write sets of ephemeral collections
+	ephemeral bool
 }
 
 // listMissingPrivateData identifies missing private write sets and attempts to retrieve them from local transient store
-func (c *coordinator) listMissingPrivateData(block *common.Block, ownedRWsets map[rwSetKey][]byte) (*privateDataInfo, error) {
+func (c *coordinator) listMissingPrivateData(block *common.Block, ownedRWsets map[rwSetKey][]byte, hashFunc func([]byte) []byte) (*privateDataInfo, error) {
 	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
 		return nil, errors.New("Block.Metadata is nil or Block.Metadata lacks a Tx filter bitmap")
 	}
@@ -633,6 +782,10 @@ func (c *coordinator) listMissingPrivateData(block *common.Block, ownedRWsets ma
 	if len(txsFilter) != len(block.Data.Data) {
 		return nil, errors.Errorf("Block data size(%d) is different from Tx filter size(%d)", len(block.Data.Data), len(txsFilter))
 	}
+	ephemeralCollections, err := ephemeralCollectionsEnabled(c.Committer, block)
+	if err != nil {
+		return nil, err
+	}
 
 	sources := make(map[rwSetKey][]*peer.Endorsement)
 	privateRWsetsInBlock := make(map[rwSetKey]struct{})
@@ -643,21 +796,23 @@ func (c *coordinator) listMissingPrivateData(block *common.Block, ownedRWsets ma
 		missingKeys:          missing,
 		ownedRWsets:          ownedRWsets,
 		privateRWsetsInBlock: privateRWsetsInBlock,
+		ephemeralCollections: ephemeralCollections,
 		coordinator:          c,
 	}
 	txList := data.forEachTxn(txsFilter, bi.inspectTransaction)
 
 	privateInfo := &privateDataInfo{
-		sources:            sources,
-		missingKeysByTxIDs: missing,
-		txns:               txList,
+		sources:                 sources,
+		missingKeysByTxIDs:      missing,
+		txns:                    txList,
 		missingRWSButIneligible: bi.missingRWSButIneligible,
+		ephemeral:               bi.ephemeral,
 	}
 
 	logger.Debug("Retrieving private write sets for", len(privateInfo.missingKeysByTxIDs), "transactions from transient store")
 
 	// Put into ownedRWsets RW sets that are missing and found in the transient store
-	c.fetchMissingFromTransientStore(privateInfo.missingKeysByTxIDs, ownedRWsets)
+	c.fetchMissingFromTransientStore(privateInfo.missingKeysByTxIDs, ownedRWsets, hashFunc)
 
 	// In the end, iterate over the ownedRWsets, and if the key doesn't exist in
 	// the privateRWsetsInBlock - delete it from the ownedRWsets
@@ -685,6 +840,10 @@ type transactionInspector struct {
 	sources                 map[rwSetKey][]*peer.Endorsement
 	ownedRWsets             map[rwSetKey][]byte
 	missingRWSButIneligible []rwSetKey
+	ephemeral               bool
+	// ephemeralCollections is true if the config of the block enables the
+	// ephemeral collections
+	ephemeralCollections bool
 }
 
 func (bi *transactionInspector) inspectTransaction(seqInBlock uint64, chdr *common.ChannelHeader, txRWSet *rwsetutil.TxRwSet, endorsers []*peer.Endorsement) {
@@ -713,6 +872,16 @@ func (bi *transactionInspector) inspectTransaction(seqInBlock uint64, chdr *comm
 				collection: hashedCollection.CollectionName,
 			}
 
+			if bi.ephemeralCollections && bi.isEphemeral(chdr, ns.NameSpace, hashedCollection.CollectionName) {
+				logger.Debugf("Collection is ephemeral, channel [%s], chaincode [%s], collection name [%s], txID [%s]. "+
+					"Its private write set isn't passed to the ledger.", chdr.ChannelId, ns.NameSpace, hashedCollection.CollectionName, chdr.TxId)
+				// neither persisted nor recorded as missing, the private write set
+				// is only kept in the transient store until the block is committed
+				delete(bi.ownedRWsets, key)
+				bi.ephemeral = true
+				continue
+			}
+
 			if !bi.isEligible(policy, ns.NameSpace, hashedCollection.CollectionName) {
 				logger.Debugf("Peer is not eligible for collection, channel [%s], chaincode [%s], "+
 					"collection name [%s], txID [%s] the policy is [%#v]. Skipping.",
@@ -751,6 +920,23 @@ func (c *coordinator) accessPolicyForCollection(chdr *common.ChannelHeader, name
 	return sp
 }
 
+// isEphemeral checks if the private data of a given namespace, collection name
+// that corresponds to a given ChannelHeader is never persisted
+func (c *coordinator) isEphemeral(chdr *common.ChannelHeader, namespace string, col string) bool {
+	cp := common.CollectionCriteria{
+		Channel:    chdr.ChannelId,
+		Namespace:  namespace,
+		Collection: col,
+		TxId:       chdr.TxId,
+	}
+	persistenceConfigs, err := c.CollectionStore.RetrieveCollectionPersistenceConfigs(cp)
+	if err != nil {
+		logger.Warning("Failed obtaining persistence configs for", cp, ":", err, "assuming the collection isn't ephemeral")
+		return false
+	}
+	return persistenceConfigs.Ephemeral()
+}
+
 // isEligible checks if this peer is eligible for a given CollectionAccessPolicy
 func (c *coordinator) isEligible(ap privdata.CollectionAccessPolicy, namespace string, col string) bool {
 	filt := ap.AccessFilter()
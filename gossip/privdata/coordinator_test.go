@@ -17,6 +17,8 @@ import (
 
 	pb "github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/common/capabilities"
+	"github.com/hyperledger/fabric/common/channelconfig"
 	util2 "github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/ledger"
@@ -30,6 +32,7 @@ import (
 	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 	"github.com/hyperledger/fabric/protos/msp"
 	transientstore2 "github.com/hyperledger/fabric/protos/transientstore"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -349,6 +352,7 @@ func createcollectionStore(expectedSignedData common.SignedData) *collectionStor
 		expectedSignedData: expectedSignedData,
 		policies:           make(map[collectionAccessPolicy]CollectionCriteria),
 		store:              make(map[CollectionCriteria]collectionAccessPolicy),
+		ephemeral:          make(map[string]struct{}),
 	}
 }
 
@@ -359,6 +363,7 @@ type collectionStore struct {
 	lenient            bool
 	store              map[CollectionCriteria]collectionAccessPolicy
 	policies           map[collectionAccessPolicy]CollectionCriteria
+	ephemeral          map[string]struct{}
 }
 
 func (cs *collectionStore) thatAcceptsAll() *collectionStore {
@@ -366,6 +371,13 @@ func (cs *collectionStore) thatAcceptsAll() *collectionStore {
 	return cs
 }
 
+func (cs *collectionStore) withEphemeral(collections ...string) *collectionStore {
+	for _, collection := range collections {
+		cs.ephemeral[collection] = struct{}{}
+	}
+	return cs
+}
+
 func (cs *collectionStore) thatAcceptsNone() *collectionStore {
 	cs.acceptsNone = true
 	return cs
@@ -420,13 +432,26 @@ func (cs *collectionStore) RetrieveCollectionConfigPackage(cc common.CollectionC
 }
 
 func (cs *collectionStore) RetrieveCollectionPersistenceConfigs(cc common.CollectionCriteria) (privdata.CollectionPersistenceConfigs, error) {
-	panic("implement me")
+	_, ephemeral := cs.ephemeral[cc.Collection]
+	return &collectionPersistenceConfigs{ephemeral: ephemeral}, nil
 }
 
 func (cs *collectionStore) AccessFilter(channelName string, collectionPolicyConfig *common.CollectionPolicyConfig) (privdata.Filter, error) {
 	panic("implement me")
 }
 
+type collectionPersistenceConfigs struct {
+	ephemeral bool
+}
+
+func (cpc *collectionPersistenceConfigs) BlockToLive() uint64 {
+	return 0
+}
+
+func (cpc *collectionPersistenceConfigs) Ephemeral() bool {
+	return cpc.ephemeral
+}
+
 type collectionAccessPolicy struct {
 	cs *collectionStore
 	n  uint64
@@ -1259,6 +1284,76 @@ func TestProceedWithInEligiblePrivateData(t *testing.T) {
 	assertCommitHappened()
 }
 
+func TestProceedWithoutEphemeralPrivateData(t *testing.T) {
+	// Scenario: the private data of the ephemeral collection c2 in ns3 is in the transient store,
+	// but neither passed to the ledger nor recorded as missing, and it is purged
+	// from the transient store once the block is committed.
+	// Without the V1_4_2 capability the collection isn't ephemeral, and its
+	// private data is passed to the ledger.
+	peerSelfSignedData := common.SignedData{
+		Identity:  []byte{0, 1, 2},
+		Signature: []byte{3, 4, 5},
+		Data:      []byte{6, 7, 8},
+	}
+
+	for _, testCase := range []struct {
+		capability string
+		ephemeral  bool
+	}{
+		{capability: capabilities.ApplicationV1_4_2, ephemeral: true},
+		{capability: capabilities.ApplicationV1_3, ephemeral: false},
+	} {
+		t.Run(testCase.capability, func(t *testing.T) {
+			cs := createcollectionStore(peerSelfSignedData).thatAcceptsAll().withEphemeral("c2")
+
+			var commitHappened bool
+			committer := &committerMock{}
+			committer.On("GetBlocks", []uint64{0}).Return([]*common.Block{
+				configBlock(0, map[string]*common.ConfigValue{channelconfig.CapabilitiesKey: capabilitiesValue(testCase.capability)}),
+			})
+			committer.On("CommitWithPvtData", mock.Anything).Run(func(args mock.Arguments) {
+				blockAndPrivateData := args.Get(0).(*ledger.BlockAndPvtData)
+				if testCase.ephemeral {
+					assert.Empty(t, blockAndPrivateData.BlockPvtData)
+				} else {
+					assert.Len(t, blockAndPrivateData.BlockPvtData, 1)
+				}
+				assert.Equal(t, &ledger.MissingPrivateDataList{}, blockAndPrivateData.Missing)
+				commitHappened = true
+			}).Return(nil)
+
+			store := &mockTransientStore{t: t}
+			purgedTxns := make(map[string]struct{})
+			store.On("PurgeByTxids", mock.Anything).Run(func(args mock.Arguments) {
+				for _, txn := range args.Get(0).([]string) {
+					purgedTxns[txn] = struct{}{}
+				}
+			}).Return(nil)
+
+			hash := util2.ComputeSHA256([]byte("rws-pre-image"))
+			bf := &blockFactory{
+				channelID: "test",
+			}
+			block := withLastConfig(bf.AddTxn("tx1", "ns3", hash, "c2").create(), 0)
+			pvtData := (&pvtDataFactory{}).addRWSet().addNSRWSet("ns3", "c2").create()
+
+			// the fetcher would panic if the private data were pulled from other peers
+			coordinator := NewCoordinator(Support{
+				CollectionStore: cs,
+				Committer:       committer,
+				Fetcher:         nil,
+				TransientStore:  store,
+				Validator:       &validatorMock{},
+			}, peerSelfSignedData)
+			err := coordinator.StoreBlock(block, pvtData)
+			assert.NoError(t, err)
+			assert.True(t, commitHappened)
+			_, exists := purgedTxns["tx1"]
+			assert.True(t, exists)
+		})
+	}
+}
+
 func TestCoordinatorGetBlocks(t *testing.T) {
 	sd := common.SignedData{
 		Identity:  []byte{0, 1, 2},
@@ -1352,6 +1447,8 @@ func TestPurgeByHeight(t *testing.T) {
 		Validator:       &validatorMock{},
 	}, peerSelfSignedData)
 
+	// the blocks point to block 1 as their last config
+	committer.On("GetBlocks", []uint64{1}).Return([]*common.Block{bf.create()})
 	for i := 0; i <= 3000; i++ {
 		block := bf.create()
 		block.Header.Number = uint64(i)
@@ -1455,3 +1552,72 @@ func TestIgnoreReadOnlyColRWSets(t *testing.T) {
 	assert.NoError(t, err)
 	assertCommitHappened()
 }
+
+// configBlock returns a config block of the channel test with the given values
+// in its application group
+func configBlock(number uint64, appValues map[string]*common.ConfigValue) *common.Block {
+	configEnv := &common.ConfigEnvelope{
+		Config: &common.Config{
+			ChannelGroup: &common.ConfigGroup{
+				Groups: map[string]*common.ConfigGroup{channelconfig.ApplicationGroupKey: {Values: appValues}},
+			},
+		},
+	}
+	payload := &common.Payload{
+		Header: &common.Header{
+			ChannelHeader: utils.MarshalOrPanic(&common.ChannelHeader{Type: int32(common.HeaderType_CONFIG), ChannelId: "test"}),
+		},
+		Data: utils.MarshalOrPanic(configEnv),
+	}
+	return &common.Block{
+		Header: &common.BlockHeader{Number: number},
+		Data:   &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(&common.Envelope{Payload: utils.MarshalOrPanic(payload)})}},
+	}
+}
+
+// capabilitiesValue returns the config value enabling the given application capability
+func capabilitiesValue(capability string) *common.ConfigValue {
+	return &common.ConfigValue{
+		Value: utils.MarshalOrPanic(channelconfig.CapabilitiesValue(map[string]bool{capability: true}).Value()),
+	}
+}
+
+// withLastConfig points the LAST_CONFIG metadata of the block to the given config block
+func withLastConfig(block *common.Block, configBlockNum uint64) *common.Block {
+	block.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG] = utils.MarshalOrPanic(&common.Metadata{
+		Value: utils.MarshalOrPanic(&common.LastConfig{Index: configBlockNum}),
+	})
+	return block
+}
+
+func TestValueHashFunc(t *testing.T) {
+	v142ConfigBlock := configBlock(5, map[string]*common.ConfigValue{
+		channelconfig.CapabilitiesKey: capabilitiesValue(capabilities.ApplicationV1_4_2),
+		channelconfig.RWSetHashingAlgorithmKey: {
+			Value: utils.MarshalOrPanic(channelconfig.RWSetHashingAlgorithmValue(channelconfig.BLAKE2b256).Value()),
+		},
+	})
+
+	bf := &blockFactory{channelID: "test"}
+	block := withLastConfig(bf.AddTxn("tx1", "ns1", nil, "c1").create(), 5)
+	block.Header.Number = 7
+	value := []byte("value")
+
+	// the hash function of the block is selected by the config block it points to
+	committer := &committerMock{}
+	committer.On("GetBlocks", []uint64{5}).Return([]*common.Block{v142ConfigBlock})
+	hashFunc, err := valueHashFunc(committer, block)
+	assert.NoError(t, err)
+	assert.Equal(t, util2.ComputeBLAKE2b256(value), hashFunc(value))
+
+	// the config block is not available
+	committer = &committerMock{}
+	committer.On("GetBlocks", []uint64{5}).Return(nil)
+	_, err = valueHashFunc(committer, block)
+	assert.EqualError(t, err, "config block [5] is not available")
+
+	// the block is its own last config
+	hashFunc, err = valueHashFunc(committer, bf.create())
+	assert.NoError(t, err)
+	assert.Equal(t, util2.ComputeSHA256(value), hashFunc(value))
+}
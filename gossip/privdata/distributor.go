@@ -7,12 +7,16 @@ SPDX-License-Identifier: Apache-2.0
 package privdata
 
 import (
+	"bytes"
 	"fmt"
 	"sync"
 	"sync/atomic"
 
+	pb "github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/config/reload"
 	"github.com/hyperledger/fabric/gossip/api"
+	"github.com/hyperledger/fabric/gossip/comm"
 	gossipCommon "github.com/hyperledger/fabric/gossip/common"
 	"github.com/hyperledger/fabric/gossip/discovery"
 	"github.com/hyperledger/fabric/gossip/filter"
@@ -22,9 +26,9 @@ import (
 	"github.com/hyperledger/fabric/protos/common"
 	proto "github.com/hyperledger/fabric/protos/gossip"
 	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/transientstore"
 	"github.com/pkg/errors"
-	"github.com/spf13/viper"
 )
 
 // gossipAdapter an adapter for API's required from gossip module
@@ -35,12 +39,20 @@ type gossipAdapter interface {
 	// PeerFilter receives a SubChannelSelectionCriteria and returns a RoutingFilter that selects
 	// only peer identities that match the given criteria, and that they published their channel participation
 	PeerFilter(channel gossipCommon.ChainID, messagePredicate api.SubChannelSelectionCriteria) (filter.RoutingFilter, error)
+
+	// IdentityInfo returns information known peer identities
+	IdentityInfo() api.PeerIdentitySet
+
+	// PeersOfChannel returns the NetworkMembers considered alive
+	// and also subscribed to the channel given
+	PeersOfChannel(gossipCommon.ChainID) []discovery.NetworkMember
 }
 
 // PvtDataDistributor interface to defines API of distributing private data
 type PvtDataDistributor interface {
-	// Distribute broadcast reliably private data read write set based on policies
-	Distribute(txID string, privData *transientstore.TxPvtReadWriteSetWithConfigInfo, blkHt uint64) error
+	// Distribute broadcast reliably private data read write set based on policies,
+	// and reports the peers which acknowledged the receipt of the private data
+	Distribute(txID string, privData *transientstore.TxPvtReadWriteSetWithConfigInfo, blkHt uint64) (*peer.PrivateDataDissemination, error)
 }
 
 // IdentityDeserializerFactory is a factory interface to create
@@ -101,10 +113,10 @@ func NewDistributor(chainID string, gossip gossipAdapter, factory CollectionAcce
 }
 
 // Distribute broadcast reliably private data read write set based on policies
-func (d *distributorImpl) Distribute(txID string, privData *transientstore.TxPvtReadWriteSetWithConfigInfo, blkHt uint64) error {
+func (d *distributorImpl) Distribute(txID string, privData *transientstore.TxPvtReadWriteSetWithConfigInfo, blkHt uint64) (*peer.PrivateDataDissemination, error) {
 	disseminationPlan, err := d.computeDisseminationPlan(txID, privData, blkHt)
 	if err != nil {
-		return errors.WithStack(err)
+		return nil, errors.WithStack(err)
 	}
 	return d.disseminate(disseminationPlan)
 }
@@ -112,6 +124,12 @@ func (d *distributorImpl) Distribute(txID string, privData *transientstore.TxPvt
 type dissemination struct {
 	msg      *proto.SignedGossipMessage
 	criteria gossip2.SendCriteria
+	// fallbacks are the peers the message is sent to in turn when the peers
+	// of the criteria fail to acknowledge it
+	fallbacks []gossipCommon.PKIidType
+	// report is the dissemination report of the collection of the message,
+	// shared by all the disseminations of the collection
+	report *peer.CollectionDissemination
 }
 
 func (d *distributorImpl) computeDisseminationPlan(txID string,
@@ -156,6 +174,15 @@ func (d *distributorImpl) computeDisseminationPlan(txID string,
 			if err != nil {
 				return nil, errors.WithStack(err)
 			}
+			report := &peer.CollectionDissemination{
+				Namespace:         namespace,
+				Collection:        collectionName,
+				RequiredPeerCount: int32(colAP.RequiredPeerCount()),
+				MaximumPeerCount:  int32(colAP.MaximumPeerCount()),
+			}
+			for _, dis := range dPlan {
+				dis.report = report
+			}
 			disseminationPlan = append(disseminationPlan, dPlan...)
 		}
 	}
@@ -173,6 +200,14 @@ func (d *distributorImpl) getCollectionConfig(config *common.CollectionConfigPac
 	return nil, errors.New(fmt.Sprint("no configuration for collection", collection.CollectionName, "found"))
 }
 
+// disseminationPlanForMsg computes the dissemination plan of a private data message.
+// In order to maximize the durability of the private data across organizations,
+// a single representative peer is first selected out of each member organization
+// of the collection, and only then the remaining peers are selected, until
+// the collection's MaximumPeerCount is reached. The acknowledgements required by
+// the collection's RequiredPeerCount are spread across the representatives first,
+// the other peers of the organization of a representative standing in for it
+// when it fails to acknowledge the message.
 func (d *distributorImpl) disseminationPlanForMsg(colAP privdata.CollectionAccessPolicy, colFilter privdata.Filter, pvtDataMsg *proto.SignedGossipMessage) ([]*dissemination, error) {
 	var disseminationPlan []*dissemination
 	routingFilter, err := d.gossipAdapter.PeerFilter(gossipCommon.ChainID(d.chainID), func(signature api.PeerSignature) bool {
@@ -188,12 +223,72 @@ func (d *distributorImpl) disseminationPlanForMsg(colAP privdata.CollectionAcces
 		return nil, err
 	}
 
+	eligiblePeers := d.eligiblePeersOfChannel(routingFilter)
+	identitySets := d.identitiesOfEligiblePeers(eligiblePeers, colAP)
+
+	maximumPeerCount := colAP.MaximumPeerCount()
+	requiredPeerCount := colAP.RequiredPeerCount()
+	selectedPeers := make(map[string]struct{})
+
+	// Select one representative out of each member organization
+	for _, org := range colAP.MemberOrgs() {
+		if maximumPeerCount == 0 {
+			break
+		}
+		selectionPeers, exists := identitySets[org]
+		if !exists || len(selectionPeers) == 0 {
+			continue
+		}
+		chosen := util.RandomInt(len(selectionPeers))
+		representative := selectionPeers[chosen].PKIId
+		var fallbacks []gossipCommon.PKIidType
+		for i, selectionPeer := range selectionPeers {
+			if i != chosen {
+				fallbacks = append(fallbacks, selectionPeer.PKIId)
+			}
+		}
+		selectedPeers[string(representative)] = struct{}{}
+
+		required := 0
+		if requiredPeerCount > 0 {
+			required = 1
+			requiredPeerCount--
+		}
+		maximumPeerCount--
+
+		msg, err := clonePrivateDataMessage(pvtDataMsg)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		disseminationPlan = append(disseminationPlan, &dissemination{
+			criteria: gossip2.SendCriteria{
+				Timeout:  reload.GetDuration("peer.gossip.pvtData.pushAckTimeout"),
+				Channel:  gossipCommon.ChainID(d.chainID),
+				MaxPeers: 1,
+				MinAck:   required,
+				IsEligible: func(member discovery.NetworkMember) bool {
+					return bytes.Equal(member.PKIid, representative)
+				},
+			},
+			fallbacks: fallbacks,
+			msg:       msg,
+		})
+	}
+
+	if maximumPeerCount == 0 && len(disseminationPlan) > 0 {
+		return disseminationPlan, nil
+	}
+
+	// Select the remaining peers out of all eligible peers that weren't selected as representatives
 	sc := gossip2.SendCriteria{
-		Timeout:  viper.GetDuration("peer.gossip.pvtData.pushAckTimeout"),
+		Timeout:  reload.GetDuration("peer.gossip.pvtData.pushAckTimeout"),
 		Channel:  gossipCommon.ChainID(d.chainID),
-		MaxPeers: colAP.MaximumPeerCount(),
-		MinAck:   colAP.RequiredPeerCount(),
+		MaxPeers: maximumPeerCount,
+		MinAck:   requiredPeerCount,
 		IsEligible: func(member discovery.NetworkMember) bool {
+			if _, selected := selectedPeers[string(member.PKIid)]; selected {
+				return false
+			}
 			return routingFilter(member)
 		},
 	}
@@ -204,14 +299,89 @@ func (d *distributorImpl) disseminationPlanForMsg(colAP privdata.CollectionAcces
 	return disseminationPlan, nil
 }
 
-func (d *distributorImpl) disseminate(disseminationPlan []*dissemination) error {
+// identitiesOfEligiblePeers returns the identities of the given eligible peers
+// that belong to the member organizations of the collection, grouped by organization
+func (d *distributorImpl) identitiesOfEligiblePeers(eligiblePeers []discovery.NetworkMember, colAP privdata.CollectionAccessPolicy) map[string]api.PeerIdentitySet {
+	eligible := make(map[string]struct{})
+	for _, peer := range eligiblePeers {
+		eligible[string(peer.PKIid)] = struct{}{}
+	}
+	memberOrgs := make(map[string]struct{})
+	for _, org := range colAP.MemberOrgs() {
+		memberOrgs[org] = struct{}{}
+	}
+
+	var identities api.PeerIdentitySet
+	for _, info := range d.gossipAdapter.IdentityInfo() {
+		if _, isMember := memberOrgs[string(info.Organization)]; !isMember {
+			continue
+		}
+		if _, isEligible := eligible[string(info.PKIId)]; !isEligible {
+			continue
+		}
+		identities = append(identities, info)
+	}
+	return identities.ByOrg()
+}
+
+func (d *distributorImpl) eligiblePeersOfChannel(routingFilter filter.RoutingFilter) []discovery.NetworkMember {
+	var eligiblePeers []discovery.NetworkMember
+	for _, peer := range d.gossipAdapter.PeersOfChannel(gossipCommon.ChainID(d.chainID)) {
+		if routingFilter(peer) {
+			eligiblePeers = append(eligiblePeers, peer)
+		}
+	}
+	return eligiblePeers
+}
+
+// clonePrivateDataMessage returns a deep copy of the given message, as messages
+// are mutated when being sent, and each dissemination is sent in parallel
+func clonePrivateDataMessage(msg *proto.SignedGossipMessage) (*proto.SignedGossipMessage, error) {
+	gossipMsg, isGossipMsg := pb.Clone(msg.GossipMessage).(*proto.GossipMessage)
+	if !isGossipMsg {
+		return nil, errors.New("failed cloning private data message")
+	}
+	return gossipMsg.NoopSign()
+}
+
+func (d *distributorImpl) disseminate(disseminationPlan []*dissemination) (*peer.PrivateDataDissemination, error) {
+	orgs := make(map[string]string)
+	for _, info := range d.gossipAdapter.IdentityInfo() {
+		orgs[string(info.PKIId)] = string(info.Organization)
+	}
+
+	var lock sync.Mutex
+	report := &peer.PrivateDataDissemination{}
+	// the peers which acknowledged the private data of each collection, as
+	// a peer may be sent the same private data by several disseminations
+	acked := make(map[*peer.CollectionDissemination]map[string]struct{})
+	for _, dis := range disseminationPlan {
+		if _, exists := acked[dis.report]; !exists {
+			acked[dis.report] = make(map[string]struct{})
+			report.Collections = append(report.Collections, dis.report)
+		}
+		collectionReport := dis.report
+		dis.criteria.OnAck = func(member comm.RemotePeer) {
+			lock.Lock()
+			defer lock.Unlock()
+			if _, exists := acked[collectionReport][string(member.PKIID)]; exists {
+				return
+			}
+			acked[collectionReport][string(member.PKIID)] = struct{}{}
+			collectionReport.AcknowledgedBy = append(collectionReport.AcknowledgedBy, &peer.PrivateDataRecipient{
+				Endpoint: member.Endpoint,
+				Mspid:    orgs[string(member.PKIID)],
+			})
+		}
+	}
+
 	var failures uint32
 	var wg sync.WaitGroup
 	wg.Add(len(disseminationPlan))
 	for _, dis := range disseminationPlan {
 		go func(dis *dissemination) {
 			defer wg.Done()
-			err := d.SendByCriteria(dis.msg, dis.criteria)
+			err := d.send(dis)
 			if err != nil {
 				atomic.AddUint32(&failures, 1)
 				m := dis.msg.GetPrivateData().Payload
@@ -222,9 +392,35 @@ func (d *distributorImpl) disseminate(disseminationPlan []*dissemination) error
 	wg.Wait()
 	failureCount := atomic.LoadUint32(&failures)
 	if failureCount != 0 {
-		return errors.Errorf("Failed disseminating %d out of %d private RWSets", failureCount, len(disseminationPlan))
+		return nil, errors.Errorf("Failed disseminating %d out of %d private RWSets", failureCount, len(disseminationPlan))
+	}
+	return report, nil
+}
+
+// send sends the message of the dissemination according to its criteria, and
+// to each of its fallback peers in turn until the acknowledgements required by
+// the criteria are collected
+func (d *distributorImpl) send(dis *dissemination) error {
+	err := d.SendByCriteria(dis.msg, dis.criteria)
+	for _, fallback := range dis.fallbacks {
+		if err == nil {
+			return nil
+		}
+		m := dis.msg.GetPrivateData().Payload
+		logger.Debugf("Failed disseminating private RWSet for TxID %s, namespace %s, collection %s, retrying with another peer: %s",
+			m.TxId, m.Namespace, m.CollectionName, err)
+		msg, err2 := clonePrivateDataMessage(dis.msg)
+		if err2 != nil {
+			return errors.WithStack(err2)
+		}
+		criteria := dis.criteria
+		fallback := fallback
+		criteria.IsEligible = func(member discovery.NetworkMember) bool {
+			return bytes.Equal(member.PKIid, fallback)
+		}
+		err = d.SendByCriteria(msg, criteria)
 	}
-	return nil
+	return err
 }
 
 func (d *distributorImpl) createPrivateDataMessage(txID, namespace string,
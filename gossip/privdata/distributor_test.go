@@ -12,12 +12,15 @@ import (
 
 	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/gossip/api"
+	"github.com/hyperledger/fabric/gossip/comm"
 	gcommon "github.com/hyperledger/fabric/gossip/common"
 	"github.com/hyperledger/fabric/gossip/discovery"
 	"github.com/hyperledger/fabric/gossip/filter"
 	gossip2 "github.com/hyperledger/fabric/gossip/gossip"
 	"github.com/hyperledger/fabric/protos/common"
 	proto "github.com/hyperledger/fabric/protos/gossip"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/transientstore"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -68,6 +71,16 @@ type gossipMock struct {
 	err error
 	mock.Mock
 	api.PeerSignature
+	identities api.PeerIdentitySet
+	members    []discovery.NetworkMember
+}
+
+func (g *gossipMock) IdentityInfo() api.PeerIdentitySet {
+	return g.identities
+}
+
+func (g *gossipMock) PeersOfChannel(gcommon.ChainID) []discovery.NetworkMember {
+	return g.members
 }
 
 func (g *gossipMock) SendByCriteria(message *proto.SignedGossipMessage, criteria gossip2.SendCriteria) error {
@@ -142,7 +155,7 @@ func TestDistributor(t *testing.T) {
 	d := NewDistributor("test", g, accessFactoryMock)
 	pdFactory := &pvtDataFactory{}
 	pvtData := pdFactory.addRWSet().addNSRWSet("ns1", "c1", "c2").addRWSet().addNSRWSet("ns2", "c1", "c2").create()
-	err := d.Distribute("tx1", &transientstore.TxPvtReadWriteSetWithConfigInfo{
+	_, err := d.Distribute("tx1", &transientstore.TxPvtReadWriteSetWithConfigInfo{
 		PvtRwset: pvtData[0].WriteSet,
 		CollectionConfigs: map[string]*common.CollectionConfigPackage{
 			"ns1": {
@@ -151,7 +164,7 @@ func TestDistributor(t *testing.T) {
 		},
 	}, 0)
 	assert.NoError(t, err)
-	err = d.Distribute("tx2", &transientstore.TxPvtReadWriteSetWithConfigInfo{
+	_, err = d.Distribute("tx2", &transientstore.TxPvtReadWriteSetWithConfigInfo{
 		PvtRwset: pvtData[1].WriteSet,
 		CollectionConfigs: map[string]*common.CollectionConfigPackage{
 			"ns2": {
@@ -185,7 +198,7 @@ func TestDistributor(t *testing.T) {
 
 	// Bad path: dependencies (gossip and others) don't work properly
 	g.err = errors.New("failed obtaining filter")
-	err = d.Distribute("tx1", &transientstore.TxPvtReadWriteSetWithConfigInfo{
+	_, err = d.Distribute("tx1", &transientstore.TxPvtReadWriteSetWithConfigInfo{
 		PvtRwset: pvtData[0].WriteSet,
 		CollectionConfigs: map[string]*common.CollectionConfigPackage{
 			"ns1": {
@@ -199,7 +212,7 @@ func TestDistributor(t *testing.T) {
 	g.Mock = mock.Mock{}
 	g.On("SendByCriteria", mock.Anything, mock.Anything).Return(errors.New("failed sending"))
 	g.err = nil
-	err = d.Distribute("tx1", &transientstore.TxPvtReadWriteSetWithConfigInfo{
+	_, err = d.Distribute("tx1", &transientstore.TxPvtReadWriteSetWithConfigInfo{
 		PvtRwset: pvtData[0].WriteSet,
 		CollectionConfigs: map[string]*common.CollectionConfigPackage{
 			"ns1": {
@@ -210,3 +223,179 @@ func TestDistributor(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "Failed disseminating 2 out of 2 private RWSets")
 }
+
+func TestDistributorOrgCoverage(t *testing.T) {
+	// Scenario: org1 has 3 eligible peers and org2 has a single eligible peer.
+	// org3 is a member of the collection but has no peers, and org4 isn't a member
+	// of the collection at all. The dissemination plan should first pick a single
+	// representative of org1 and org2, and only then pick the rest of the peers.
+	peer := func(id string) discovery.NetworkMember {
+		return discovery.NetworkMember{PKIid: gcommon.PKIidType(id), Endpoint: id}
+	}
+	identity := func(id, org string) api.PeerIdentityInfo {
+		return api.PeerIdentityInfo{PKIId: gcommon.PKIidType(id), Organization: api.OrgIdentityType(org)}
+	}
+	g := &gossipMock{
+		members: []discovery.NetworkMember{peer("p1"), peer("p2"), peer("p3"), peer("p4"), peer("p5")},
+		identities: api.PeerIdentitySet{
+			identity("p1", "org1"), identity("p2", "org1"), identity("p3", "org1"),
+			identity("p4", "org2"), identity("p5", "org4"),
+		},
+	}
+	d := NewDistributor("test", g, &collectionAccessFactoryMock{}).(*distributorImpl)
+	msg, err := d.createPrivateDataMessage("tx1", "ns1", &rwset.CollectionPvtReadWriteSet{CollectionName: "c1"}, nil, 0)
+	assert.NoError(t, err)
+
+	policyMock := &collectionAccessPolicyMock{}
+	policyMock.Setup(1, 3, func(_ common.SignedData) bool {
+		return true
+	}, []string{"org1", "org2", "org3"})
+
+	plan, err := d.disseminationPlanForMsg(policyMock, policyMock.AccessFilter(), msg)
+	assert.NoError(t, err)
+	assert.Len(t, plan, 3)
+
+	eligiblePeers := func(sc gossip2.SendCriteria) []string {
+		var res []string
+		for _, member := range g.members {
+			if sc.IsEligible(member) {
+				res = append(res, member.Endpoint)
+			}
+		}
+		return res
+	}
+
+	// The representative of org1 is required to acknowledge the message
+	org1Rep := eligiblePeers(plan[0].criteria)
+	assert.Len(t, org1Rep, 1)
+	assert.Contains(t, []string{"p1", "p2", "p3"}, org1Rep[0])
+	assert.Equal(t, 1, plan[0].criteria.MaxPeers)
+	assert.Equal(t, 1, plan[0].criteria.MinAck)
+	// The other peers of org1 stand in for its representative
+	assert.Len(t, plan[0].fallbacks, 2)
+	assert.NotContains(t, plan[0].fallbacks, gcommon.PKIidType(org1Rep[0]))
+
+	// The representative of org2 isn't, as RequiredPeerCount was already met
+	assert.Equal(t, []string{"p4"}, eligiblePeers(plan[1].criteria))
+	assert.Equal(t, 1, plan[1].criteria.MaxPeers)
+	assert.Equal(t, 0, plan[1].criteria.MinAck)
+	assert.Empty(t, plan[1].fallbacks)
+
+	// The rest of the peers are selected out of those not already selected
+	remaining := eligiblePeers(plan[2].criteria)
+	assert.NotContains(t, remaining, org1Rep[0])
+	assert.NotContains(t, remaining, "p4")
+	assert.Len(t, remaining, 3)
+	assert.Equal(t, 1, plan[2].criteria.MaxPeers)
+	assert.Equal(t, 0, plan[2].criteria.MinAck)
+
+	// Each dissemination carries its own copy of the message
+	assert.False(t, plan[0].msg == plan[1].msg)
+	assert.Equal(t, plan[0].msg.GetPrivateData().Payload.TxId, plan[1].msg.GetPrivateData().Payload.TxId)
+
+	// Scenario: MaximumPeerCount is reached by the representatives alone
+	policyMock = &collectionAccessPolicyMock{}
+	policyMock.Setup(2, 2, func(_ common.SignedData) bool {
+		return true
+	}, []string{"org1", "org2"})
+	plan, err = d.disseminationPlanForMsg(policyMock, policyMock.AccessFilter(), msg)
+	assert.NoError(t, err)
+	assert.Len(t, plan, 2)
+	assert.Equal(t, 1, plan[0].criteria.MinAck)
+	assert.Equal(t, 1, plan[1].criteria.MinAck)
+}
+
+func TestDistributorFallback(t *testing.T) {
+	// Scenario: the representative of an organization fails to acknowledge
+	// the message, and another peer of the organization is sent the message
+	g := &gossipMock{}
+	var sentTo []string
+	record := func(args mock.Arguments) {
+		sc := args.Get(1).(gossip2.SendCriteria)
+		for _, id := range []string{"p1", "p2", "p3"} {
+			if sc.IsEligible(discovery.NetworkMember{PKIid: gcommon.PKIidType(id)}) {
+				sentTo = append(sentTo, id)
+			}
+		}
+	}
+	g.On("SendByCriteria", mock.Anything, mock.Anything).Run(record).Return(errors.New("timeout")).Twice()
+	g.On("SendByCriteria", mock.Anything, mock.Anything).Run(record).Return(nil).Once()
+	d := NewDistributor("test", g, &collectionAccessFactoryMock{}).(*distributorImpl)
+	msg, err := d.createPrivateDataMessage("tx1", "ns1", &rwset.CollectionPvtReadWriteSet{CollectionName: "c1"}, nil, 0)
+	assert.NoError(t, err)
+
+	dis := &dissemination{
+		msg: msg,
+		criteria: gossip2.SendCriteria{
+			MaxPeers: 1,
+			MinAck:   1,
+			IsEligible: func(member discovery.NetworkMember) bool {
+				return string(member.PKIid) == "p1"
+			},
+		},
+		fallbacks: []gcommon.PKIidType{gcommon.PKIidType("p3"), gcommon.PKIidType("p2"), gcommon.PKIidType("p1")},
+	}
+	assert.NoError(t, d.send(dis))
+	assert.Equal(t, []string{"p1", "p3", "p2"}, sentTo)
+
+	// Scenario: no peer acknowledges the message
+	sentTo = nil
+	g.On("SendByCriteria", mock.Anything, mock.Anything).Run(record).Return(errors.New("timeout"))
+	dis.fallbacks = []gcommon.PKIidType{gcommon.PKIidType("p3")}
+	assert.EqualError(t, d.send(dis), "timeout")
+	assert.Equal(t, []string{"p1", "p3"}, sentTo)
+}
+
+func TestDistributorDisseminationReport(t *testing.T) {
+	g := &gossipMock{
+		PeerSignature: api.PeerSignature{
+			Signature:    []byte{3, 4, 5},
+			Message:      []byte{6, 7, 8},
+			PeerIdentity: []byte{0, 1, 2},
+		},
+		identities: api.PeerIdentitySet{
+			{PKIId: gcommon.PKIidType("p1"), Organization: api.OrgIdentityType("org1")},
+		},
+	}
+	g.On("SendByCriteria", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		sendCriteria := args.Get(1).(gossip2.SendCriteria)
+		// p1 acknowledges the message twice, as if it was resent
+		sendCriteria.OnAck(comm.RemotePeer{Endpoint: "p1", PKIID: gcommon.PKIidType("p1")})
+		sendCriteria.OnAck(comm.RemotePeer{Endpoint: "p1", PKIID: gcommon.PKIidType("p1")})
+	}).Return(nil)
+
+	c1ColConfig := &common.CollectionConfig{
+		Payload: &common.CollectionConfig_StaticCollectionConfig{
+			StaticCollectionConfig: &common.StaticCollectionConfig{
+				Name:              "c1",
+				RequiredPeerCount: 1,
+				MaximumPeerCount:  2,
+			},
+		},
+	}
+	policyMock := &collectionAccessPolicyMock{}
+	policyMock.Setup(1, 2, func(_ common.SignedData) bool {
+		return true
+	}, []string{"org1"})
+	accessFactoryMock := &collectionAccessFactoryMock{}
+	accessFactoryMock.On("AccessPolicy", c1ColConfig, "test").Return(policyMock, nil)
+
+	d := NewDistributor("test", g, accessFactoryMock)
+	pvtData := (&pvtDataFactory{}).addRWSet().addNSRWSet("ns1", "c1").create()
+	report, err := d.Distribute("tx1", &transientstore.TxPvtReadWriteSetWithConfigInfo{
+		PvtRwset: pvtData[0].WriteSet,
+		CollectionConfigs: map[string]*common.CollectionConfigPackage{
+			"ns1": {
+				Config: []*common.CollectionConfig{c1ColConfig},
+			},
+		},
+	}, 0)
+	assert.NoError(t, err)
+	assert.Len(t, report.Collections, 1)
+	collectionReport := report.Collections[0]
+	assert.Equal(t, "ns1", collectionReport.Namespace)
+	assert.Equal(t, "c1", collectionReport.Collection)
+	assert.Equal(t, int32(1), collectionReport.RequiredPeerCount)
+	assert.Equal(t, int32(2), collectionReport.MaximumPeerCount)
+	assert.Equal(t, []*peer.PrivateDataRecipient{{Endpoint: "p1", Mspid: "org1"}}, collectionReport.AcknowledgedBy)
+}
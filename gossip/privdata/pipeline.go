@@ -0,0 +1,231 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"sync/atomic"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/gossip/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// lsccNamespace is the namespace of the chaincode definitions and the
+// collection configurations, which the validation of the transactions reads
+const lsccNamespace = "lscc"
+
+// crlsccNamespace is the namespace of the CRLs published through crlscc,
+// which revoke the identities the validation of the transactions checks
+const crlsccNamespace = "crlscc"
+
+// PipelinedCoordinator is a Coordinator able to validate a block while the
+// previous one is being committed
+type PipelinedCoordinator interface {
+	Coordinator
+
+	// ValidateBlock validates the transactions of the block against the
+	// state committed so far, ahead of the commit of the block
+	ValidateBlock(block *common.Block) (*ValidatedBlock, error)
+
+	// CommitValidatedBlock commits the block validated by ValidateBlock along
+	// with its private data. The block is validated again if the blocks
+	// committed since its validation started may change its outcome
+	CommitValidatedBlock(validated *ValidatedBlock, privateDataSets util.PvtDataCollections) error
+}
+
+// ValidatedBlock is a block validated ahead of its commit
+type ValidatedBlock struct {
+	Block *common.Block
+
+	// Number of blocks committed by the coordinator before the validation
+	// of the block started
+	commits uint64
+	effects *blockEffects
+}
+
+// ValidateBlock validates the transactions of the block ahead of its commit
+func (c *coordinator) ValidateBlock(block *common.Block) (validated *ValidatedBlock, err error) {
+	if err := checkBlock(block); err != nil {
+		return nil, err
+	}
+
+	logger.Infof("[%s] Received block [%d] from buffer", c.ChainID, block.Header.Number)
+
+	commits := atomic.LoadUint64(&c.commits)
+	span := c.startStoreBlockSpan(block)
+	defer func() {
+		span.SetError(err)
+		span.End()
+	}()
+	if err = c.validate(span, block); err != nil {
+		return nil, err
+	}
+	return &ValidatedBlock{Block: block, commits: commits, effects: blockEffectsOf(block)}, nil
+}
+
+// CommitValidatedBlock commits the block validated by ValidateBlock, which is
+// validated again unless the blocks committed since then are known not to
+// affect its validation
+func (c *coordinator) CommitValidatedBlock(validated *ValidatedBlock, privateDataSets util.PvtDataCollections) (err error) {
+	block := validated.Block
+	span := c.startStoreBlockSpan(block)
+	defer func() {
+		span.SetError(err)
+		span.End()
+	}()
+
+	effects := validated.effects
+	if c.requiresRevalidation(validated) {
+		logger.Debugf("[%s] Block [%d] was validated before the commit of a block it depends on, validating it again", c.ChainID, block.Header.Number)
+		if err = c.validate(span, block); err != nil {
+			return err
+		}
+		effects = blockEffectsOf(block)
+	}
+	return c.commit(span, block, privateDataSets, effects)
+}
+
+// requiresRevalidation returns whether the validation of the block may have
+// missed the changes of the blocks committed since it started. The pipeline
+// validates a block while a single block is committed, the validation is
+// repeated if more blocks were committed or if the effects of the committed
+// one are unknown
+func (c *coordinator) requiresRevalidation(validated *ValidatedBlock) bool {
+	switch atomic.LoadUint64(&c.commits) - validated.commits {
+	case 0:
+		return false
+	case 1:
+		return c.lastCommitted == nil || c.lastCommitted.affect(validated.effects)
+	default:
+		return true
+	}
+}
+
+// blockEffects summarizes the changes committed by a block which the
+// validation of the next blocks depends on
+type blockEffects struct {
+	// Whether the block updates the channel configuration, the chaincode
+	// definitions, the collection configurations or the key-level
+	// endorsement policies
+	updatesValidationInfo bool
+	// IDs of the transactions of the block, valid or not
+	txIDs map[string]struct{}
+	// Dedup keys of the transactions of the block, only those of the valid
+	// transactions once the block is committed
+	dedupKeys map[dedupKey]struct{}
+	// Dedup keys of all the transactions of the block
+	allDedupKeys map[dedupKey]struct{}
+}
+
+type dedupKey struct {
+	namespace string
+	key       string
+}
+
+// affect returns whether the commit of the block of these effects may
+// change the validation of the block of the next ones
+func (e *blockEffects) affect(next *blockEffects) bool {
+	if e.updatesValidationInfo {
+		return true
+	}
+	for txID := range next.txIDs {
+		if _, exists := e.txIDs[txID]; exists {
+			return true
+		}
+	}
+	for key := range next.allDedupKeys {
+		if _, exists := e.dedupKeys[key]; exists {
+			return true
+		}
+	}
+	return false
+}
+
+// blockEffectsOf returns the effects of the validated block
+func blockEffectsOf(block *common.Block) *blockEffects {
+	effects := &blockEffects{
+		txIDs:        make(map[string]struct{}),
+		dedupKeys:    make(map[dedupKey]struct{}),
+		allDedupKeys: make(map[dedupKey]struct{}),
+	}
+	var txsFilter txValidationFlags
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txsFilter = txValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+	if len(txsFilter) != len(block.Data.Data) {
+		// without the validation codes the effects are unknown
+		effects.updatesValidationInfo = true
+		return effects
+	}
+
+	for seqInBlock, envBytes := range block.Data.Data {
+		valid := txsFilter[seqInBlock] == uint8(peer.TxValidationCode_VALID)
+		env, err := utils.GetEnvelopeFromBlock(envBytes)
+		if err != nil {
+			continue
+		}
+		payload, err := utils.GetPayload(env)
+		if err != nil || payload.Header == nil {
+			continue
+		}
+		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			continue
+		}
+		effects.txIDs[chdr.TxId] = struct{}{}
+
+		switch common.HeaderType(chdr.Type) {
+		case common.HeaderType_CONFIG:
+			if valid {
+				effects.updatesValidationInfo = true
+			}
+		case common.HeaderType_ENDORSER_TRANSACTION:
+			if ext, err := utils.GetChaincodeHeaderExtension(payload.Header); err == nil && ext.DedupKey != "" && ext.ChaincodeId != nil {
+				key := dedupKey{namespace: ext.ChaincodeId.Name, key: ext.DedupKey}
+				effects.allDedupKeys[key] = struct{}{}
+				if valid {
+					effects.dedupKeys[key] = struct{}{}
+				}
+			}
+			if valid && updatesValidationInfo(envBytes) {
+				effects.updatesValidationInfo = true
+			}
+		}
+	}
+	return effects
+}
+
+// updatesValidationInfo returns whether the endorser transaction writes the
+// chaincode definitions, the collection configurations, the published CRLs
+// or the metadata of keys, which hold their endorsement policies. The write
+// set of a transaction which can't be parsed is assumed to update them
+func updatesValidationInfo(envBytes []byte) bool {
+	respPayload, err := utils.GetActionFromEnvelope(envBytes)
+	if err != nil {
+		return true
+	}
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(respPayload.Results); err != nil {
+		return true
+	}
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if (nsRWSet.NameSpace == lsccNamespace || nsRWSet.NameSpace == crlsccNamespace) && nsRWSet.KvRwSet != nil && len(nsRWSet.KvRwSet.Writes) > 0 {
+			return true
+		}
+		if nsRWSet.KvRwSet != nil && len(nsRWSet.KvRwSet.MetadataWrites) > 0 {
+			return true
+		}
+		for _, collRWSet := range nsRWSet.CollHashedRwSets {
+			if collRWSet.HashedRwSet != nil && len(collRWSet.HashedRwSet.MetadataWrites) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,186 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// countingValidator counts the validations of the blocks, and fails those
+// of the given blocks once they were validated
+type countingValidator struct {
+	sync.Mutex
+	validations map[uint64]int
+	failAgain   map[uint64]bool
+}
+
+func (v *countingValidator) Validate(block *common.Block) error {
+	v.Lock()
+	defer v.Unlock()
+	v.validations[block.Header.Number]++
+	if v.failAgain[block.Header.Number] && v.validations[block.Header.Number] > 1 {
+		return errors.New("validation failed")
+	}
+	return nil
+}
+
+func (v *countingValidator) count(blockNum uint64) int {
+	v.Lock()
+	defer v.Unlock()
+	return v.validations[blockNum]
+}
+
+func TestCommitValidatedBlock(t *testing.T) {
+	committer := &committerMock{}
+	committer.On("CommitWithPvtData", mock.Anything).Return(nil)
+	validator := &countingValidator{validations: map[uint64]int{}, failAgain: map[uint64]bool{8: true}}
+	coordinator := NewCoordinator(Support{
+		CollectionStore: createcollectionStore(common.SignedData{}).thatAcceptsAll(),
+		Committer:       committer,
+		Fetcher:         &fetcherMock{t: t},
+		TransientStore:  &mockTransientStore{t: t},
+		Validator:       validator,
+	}, common.SignedData{}).(PipelinedCoordinator)
+
+	bf := &blockFactory{channelID: "test"}
+	// the blocks point to block 1 as their last config
+	committer.On("GetBlocks", []uint64{1}).Return([]*common.Block{bf.create()})
+	newBlock := func(blockNum uint64, txID string, namespace string) *common.Block {
+		block := bf.AddTxn(txID, namespace, nil).create()
+		block.Header.Number = blockNum
+		return block
+	}
+	validate := func(block *common.Block) *ValidatedBlock {
+		validated, err := coordinator.ValidateBlock(block)
+		require.NoError(t, err)
+		return validated
+	}
+
+	// no block is committed during the validation of block 1
+	require.NoError(t, coordinator.CommitValidatedBlock(validate(newBlock(1, "tx1", "mycc")), nil))
+	assert.Equal(t, 1, validator.count(1))
+
+	// block 3 doesn't depend on block 2 committed during its validation
+	validated2 := validate(newBlock(2, "tx2", "mycc"))
+	validated3 := validate(newBlock(3, "tx3", "mycc"))
+	require.NoError(t, coordinator.CommitValidatedBlock(validated2, nil))
+	require.NoError(t, coordinator.CommitValidatedBlock(validated3, nil))
+	assert.Equal(t, 1, validator.count(3))
+
+	// block 4 deploys a chaincode, the validation of block 5 depends on it
+	validated4 := validate(newBlock(4, "tx4", "lscc"))
+	validated5 := validate(newBlock(5, "tx5", "mycc"))
+	require.NoError(t, coordinator.CommitValidatedBlock(validated4, nil))
+	require.NoError(t, coordinator.CommitValidatedBlock(validated5, nil))
+	assert.Equal(t, 2, validator.count(5))
+
+	// block 7 carries the ID of a transaction of block 6
+	validated6 := validate(newBlock(6, "tx6", "mycc"))
+	validated7 := validate(newBlock(7, "tx6", "mycc"))
+	require.NoError(t, coordinator.CommitValidatedBlock(validated6, nil))
+	require.NoError(t, coordinator.CommitValidatedBlock(validated7, nil))
+	assert.Equal(t, 2, validator.count(7))
+
+	// the effects of the blocks stored without ValidateBlock are unknown,
+	// the failure of the second validation of block 8 is returned
+	validated8 := validate(newBlock(8, "tx8", "mycc"))
+	require.NoError(t, coordinator.StoreBlock(newBlock(7, "tx7", "mycc"), nil))
+	committer.AssertNumberOfCalls(t, "CommitWithPvtData", 8)
+	assert.EqualError(t, coordinator.CommitValidatedBlock(validated8, nil), "validation failed")
+	assert.Equal(t, 2, validator.count(8))
+	committer.AssertNumberOfCalls(t, "CommitWithPvtData", 8)
+
+	_, err := coordinator.ValidateBlock(&common.Block{Header: &common.BlockHeader{}})
+	assert.EqualError(t, err, "Block data is empty")
+}
+
+func TestBlockEffects(t *testing.T) {
+	envelope := func(chdr *common.ChannelHeader, ext *peer.ChaincodeHeaderExtension, rws *rwsetutil.TxRwSet) []byte {
+		if ext != nil {
+			chdr.Extension = mustMarshal(ext)
+		}
+		payload := &common.Payload{Header: &common.Header{ChannelHeader: mustMarshal(chdr)}}
+		if rws != nil {
+			rwsBytes, err := rws.ToProtoBytes()
+			require.NoError(t, err)
+			ccAction := &peer.ChaincodeAction{Results: rwsBytes}
+			prp := &peer.ProposalResponsePayload{Extension: mustMarshal(ccAction)}
+			ccPayload := &peer.ChaincodeActionPayload{Action: &peer.ChaincodeEndorsedAction{ProposalResponsePayload: mustMarshal(prp)}}
+			tx := &peer.Transaction{Actions: []*peer.TransactionAction{{Payload: mustMarshal(ccPayload)}}}
+			payload.Data = mustMarshal(tx)
+		}
+		return mustMarshal(&common.Envelope{Payload: mustMarshal(payload)})
+	}
+	endorserTx := func(txID string, dedupKey string, rws *rwsetutil.TxRwSet) []byte {
+		return envelope(&common.ChannelHeader{Type: int32(common.HeaderType_ENDORSER_TRANSACTION), TxId: txID},
+			&peer.ChaincodeHeaderExtension{ChaincodeId: &peer.ChaincodeID{Name: "mycc"}, DedupKey: dedupKey}, rws)
+	}
+	writeSet := &rwsetutil.TxRwSet{NsRwSets: []*rwsetutil.NsRwSet{{
+		NameSpace: "mycc",
+		KvRwSet:   &kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{{Key: "key", Value: []byte("value")}}},
+	}}}
+	metadataWriteSet := &rwsetutil.TxRwSet{NsRwSets: []*rwsetutil.NsRwSet{{
+		NameSpace: "mycc",
+		KvRwSet:   &kvrwset.KVRWSet{MetadataWrites: []*kvrwset.KVMetadataWrite{{Key: "key"}}},
+	}}}
+	newBlock := func(txsFilter []uint8, txs ...[]byte) *common.Block {
+		block := common.NewBlock(1, nil)
+		block.Data.Data = txs
+		block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsFilter
+		return block
+	}
+	valid, invalid := uint8(peer.TxValidationCode_VALID), uint8(peer.TxValidationCode_MVCC_READ_CONFLICT)
+
+	effects := blockEffectsOf(newBlock([]uint8{valid, invalid}, endorserTx("tx1", "key1", writeSet), endorserTx("tx2", "key2", metadataWriteSet)))
+	assert.False(t, effects.updatesValidationInfo)
+	assert.Equal(t, map[string]struct{}{"tx1": {}, "tx2": {}}, effects.txIDs)
+	assert.Equal(t, map[dedupKey]struct{}{{namespace: "mycc", key: "key1"}: {}}, effects.dedupKeys)
+	assert.Len(t, effects.allDedupKeys, 2)
+
+	// only the dedup keys of the valid transactions are committed
+	assert.True(t, effects.affect(blockEffectsOf(newBlock([]uint8{valid}, endorserTx("tx3", "key1", writeSet)))))
+	assert.False(t, effects.affect(blockEffectsOf(newBlock([]uint8{valid}, endorserTx("tx3", "key2", writeSet)))))
+
+	// the metadata of the keys holds their endorsement policies
+	effects = blockEffectsOf(newBlock([]uint8{valid}, endorserTx("tx1", "", metadataWriteSet)))
+	assert.True(t, effects.updatesValidationInfo)
+
+	// the published CRLs revoke identities
+	crlWriteSet := &rwsetutil.TxRwSet{NsRwSets: []*rwsetutil.NsRwSet{{
+		NameSpace: "crlscc",
+		KvRwSet:   &kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{{Key: "Org1MSP", Value: []byte("crls")}}},
+	}}}
+	assert.True(t, blockEffectsOf(newBlock([]uint8{valid}, endorserTx("tx1", "", crlWriteSet))).updatesValidationInfo)
+	assert.False(t, blockEffectsOf(newBlock([]uint8{invalid}, endorserTx("tx1", "", crlWriteSet))).updatesValidationInfo)
+
+	// the config transactions update the channel configuration
+	configTx := envelope(&common.ChannelHeader{Type: int32(common.HeaderType_CONFIG), TxId: "config"}, nil, nil)
+	assert.True(t, blockEffectsOf(newBlock([]uint8{valid}, configTx)).updatesValidationInfo)
+	assert.False(t, blockEffectsOf(newBlock([]uint8{invalid}, configTx)).updatesValidationInfo)
+
+	// the effects of a block lacking validation codes are unknown
+	assert.True(t, blockEffectsOf(newBlock(nil, endorserTx("tx1", "", writeSet))).updatesValidationInfo)
+}
+
+func mustMarshal(msg proto.Message) []byte {
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
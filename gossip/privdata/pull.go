@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/config/reload"
 	"github.com/hyperledger/fabric/gossip/api"
 	"github.com/hyperledger/fabric/gossip/comm"
 	"github.com/hyperledger/fabric/gossip/common"
@@ -25,7 +26,6 @@ import (
 	fcommon "github.com/hyperledger/fabric/protos/common"
 	proto "github.com/hyperledger/fabric/protos/gossip"
 	"github.com/pkg/errors"
-	"github.com/spf13/viper"
 	"go.uber.org/zap/zapcore"
 )
 
@@ -73,6 +73,7 @@ type puller struct {
 	channel       string
 	cs            privdata.CollectionStore
 	btlPullMargin uint64
+	trustedPeers  []trustedPeer
 	gossip
 	PrivateDataRetriever
 	CollectionAccessFactory
@@ -86,6 +87,7 @@ func NewPuller(cs privdata.CollectionStore, g gossip, dataRetriever PrivateDataR
 		channel:                 channel,
 		cs:                      cs,
 		btlPullMargin:           getBtlPullMargin(),
+		trustedPeers:            getTrustedPeers(),
 		gossip:                  g,
 		PrivateDataRetriever:    dataRetriever,
 		CollectionAccessFactory: factory,
@@ -238,6 +240,10 @@ func (p *puller) fetchPrivateData(dig2Filter digestToFilterMapping) (*privdataco
 		return nil, errors.New("Empty membership")
 	}
 	members = randomizeMemberList(members)
+	trustedPeer, err := p.trustedPeersFilter()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
 	res := &privdatacommon.FetchedPvtDataContainer{}
 	// Distribute requests to peers, and obtain subscriptions for all their messages
 	// matchDigestToPeer returns a map from a peer to the digests which we would ask it for
@@ -266,7 +272,7 @@ func (p *puller) fetchPrivateData(dig2Filter digestToFilterMapping) (*privdataco
 			return res, nil
 		}
 
-		peer2digests, members = p.assignDigestsToPeers(members, dig2Filter)
+		peer2digests, members = p.assignDigestsToPeers(members, dig2Filter, trustedPeer)
 		if len(peer2digests) == 0 {
 			logger.Warning("No available peers for digests request, "+
 				"cannot pull missing private data for following digests [%+v], peer membership: [%+v]",
@@ -357,15 +363,22 @@ func (p *puller) scatterRequests(peersDigestMapping peer2Digests) []util.Subscri
 type peer2Digests map[remotePeer][]proto.PvtDataDigest
 type noneSelectedPeers []discovery.NetworkMember
 
-func (p *puller) assignDigestsToPeers(members []discovery.NetworkMember, dig2Filter digestToFilterMapping) (peer2Digests, noneSelectedPeers) {
+func (p *puller) assignDigestsToPeers(members []discovery.NetworkMember, dig2Filter digestToFilterMapping, trustedPeer filter.RoutingFilter) (peer2Digests, noneSelectedPeers) {
 	if logger.IsEnabledFor(zapcore.DebugLevel) {
 		logger.Debug("Matching", members, "to", dig2Filter.String())
 	}
 	res := make(map[remotePeer][]proto.PvtDataDigest)
 	// Create a mapping between peer and digests to ask for
 	for dig, collectionFilter := range dig2Filter {
-		// Find a peer that is a preferred peer
-		selectedPeer := filter.First(members, collectionFilter.preferredPeer)
+		// Find a trusted peer, preferably a preferred peer
+		selectedPeer := filter.First(members, filter.CombineRoutingFilters(trustedPeer, collectionFilter.preferredPeer))
+		if selectedPeer == nil {
+			selectedPeer = filter.First(members, filter.CombineRoutingFilters(trustedPeer, collectionFilter.anyPeer))
+		}
+		if selectedPeer == nil {
+			// Find a peer that is a preferred peer
+			selectedPeer = filter.First(members, collectionFilter.preferredPeer)
+		}
 		if selectedPeer == nil {
 			logger.Debug("No preferred peer found for", dig)
 			// Find some peer that is in the collection
@@ -676,8 +689,8 @@ func (rp remotePeer) AsRemotePeer() *comm.RemotePeer {
 
 func getBtlPullMargin() uint64 {
 	var result uint64
-	if viper.IsSet("peer.gossip.pvtData.btlPullMargin") {
-		btlMarginVal := viper.GetInt("peer.gossip.pvtData.btlPullMargin")
+	if reload.IsSet("peer.gossip.pvtData.btlPullMargin") {
+		btlMarginVal := reload.GetInt("peer.gossip.pvtData.btlPullMargin")
 		if btlMarginVal < 0 {
 			result = btlPullMarginDefault
 		} else {
@@ -103,14 +103,19 @@ func (cs mockCollectionStore) AccessFilter(channelName string, collectionPolicyC
 }
 
 type mockCollectionAccess struct {
-	cs  *mockCollectionStore
-	btl uint64
+	cs        *mockCollectionStore
+	btl       uint64
+	ephemeral bool
 }
 
 func (mc *mockCollectionAccess) BlockToLive() uint64 {
 	return mc.btl
 }
 
+func (mc *mockCollectionAccess) Ephemeral() bool {
+	return mc.ephemeral
+}
+
 func (mc *mockCollectionAccess) thatMapsTo(peers ...string) *mockCollectionStore {
 	policyLock.Lock()
 	defer policyLock.Unlock()
@@ -177,6 +182,7 @@ func (msg *receivedMsg) GetSourceEnvelope() *proto.Envelope {
 
 func (msg *receivedMsg) GetConnectionInfo() *proto.ConnectionInfo {
 	return &proto.ConnectionInfo{
+		ID:       msg.RemotePeer.PKIID,
 		Identity: api.PeerIdentityType(msg.RemotePeer.PKIID),
 		Auth: &proto.AuthInfo{
 			SignedData: []byte{},
@@ -797,6 +803,87 @@ func TestPullerPreferEndorsers(t *testing.T) {
 	assert.Contains(t, fetched, p2TransientStore.RWSet[1])
 }
 
+func TestPullerPreferTrustedPeers(t *testing.T) {
+	t.Parallel()
+	// Scenario: p1 pulls from p2, p3 and p4, and trusts p4 and p5.
+	// p3 is the endorser of col1 and col2, but p4 is eligible for col1,
+	// so it should be selected for col1. No trusted peer is eligible for col2,
+	// so p1 should fall back to the endorser p3.
+	gn := &gossipNetwork{}
+	factoryMock := &collectionAccessFactoryMock{}
+	accessPolicyMock := &collectionAccessPolicyMock{}
+	accessPolicyMock.Setup(1, 2, func(data fcommon.SignedData) bool {
+		return true
+	}, []string{"org1", "org2"})
+	factoryMock.On("AccessPolicy", mock.Anything, mock.Anything).Return(accessPolicyMock, nil)
+
+	policyStore := newCollectionStore().
+		withPolicy("col1", uint64(100)).
+		thatMapsTo("p1", "p2", "p3", "p4").
+		withPolicy("col2", uint64(100)).
+		thatMapsTo("p1", "p2", "p3")
+	p1 := gn.newPuller("p1", policyStore, factoryMock, membership(peerData{"p2", uint64(1)},
+		peerData{"p3", uint64(1)}, peerData{"p4", uint64(1)})...)
+	p1.trustedPeers = []trustedPeer{{Endpoint: "p4"}, {Endpoint: "p5"}}
+
+	newTransientStore := func(collection string) *util.PrivateRWSetWithConfig {
+		return &util.PrivateRWSetWithConfig{
+			RWSet: newPRWSet(),
+			CollectionConfig: &fcommon.CollectionConfig{
+				Payload: &fcommon.CollectionConfig_StaticCollectionConfig{
+					StaticCollectionConfig: &fcommon.StaticCollectionConfig{
+						Name: collection,
+					},
+				},
+			},
+		}
+	}
+	p4TransientStore := newTransientStore("col1")
+	p3TransientStore := newTransientStore("col2")
+
+	gn.newPuller("p2", policyStore, factoryMock)
+	p3 := gn.newPuller("p3", policyStore, factoryMock)
+	p4 := gn.newPuller("p4", policyStore, factoryMock)
+
+	dig1 := &proto.PvtDataDigest{
+		TxId:       "txID1",
+		Collection: "col1",
+		Namespace:  "ns1",
+	}
+
+	dig2 := &proto.PvtDataDigest{
+		TxId:       "txID1",
+		Collection: "col2",
+		Namespace:  "ns1",
+	}
+
+	store := Dig2PvtRWSetWithConfig{
+		*toDigKey(dig1): p4TransientStore,
+		*toDigKey(dig2): p3TransientStore,
+	}
+
+	// We only define an action for dig1 on p4 and for dig2 on p3, and the test would fail
+	// with panic if any other peer is asked for a private RWSet
+	p4.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", mock.MatchedBy(protoMatcher(dig1)), uint64(0)).Return(store, nil)
+	p3.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", mock.MatchedBy(protoMatcher(dig2)), uint64(0)).Return(store, nil)
+
+	dasf := &digestsAndSourceFactory{}
+	d2s := dasf.mapDigest(toDigKey(dig1)).toSources("p3").mapDigest(toDigKey(dig2)).toSources("p3").create()
+	fetchedMessages, err := p1.fetch(d2s)
+	assert.NoError(t, err)
+	assert.Len(t, fetchedMessages.AvailableElements, 2)
+	var fetched []util.PrivateRWSet
+	for _, element := range fetchedMessages.AvailableElements {
+		for _, payload := range element.Payload {
+			fetched = append(fetched, util.PrivateRWSet(payload))
+		}
+	}
+	assert.Contains(t, fetched, p4TransientStore.RWSet[0])
+	assert.Contains(t, fetched, p4TransientStore.RWSet[1])
+	assert.Contains(t, fetched, p3TransientStore.RWSet[0])
+	assert.Contains(t, fetched, p3TransientStore.RWSet[1])
+}
+
 func TestPullerFetchReconciledItemsPreferPeersFromOriginalConfig(t *testing.T) {
 	t.Parallel()
 	// Scenario: p1 pulls from p2, p3, p4, p5
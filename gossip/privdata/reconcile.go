@@ -12,7 +12,6 @@ import (
 	"sync"
 	"time"
 
-	util2 "github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/committer"
 	"github.com/hyperledger/fabric/core/ledger"
 	privdatacommon "github.com/hyperledger/fabric/gossip/privdata/common"
@@ -157,10 +156,10 @@ func (r *reconciler) reconcile() error {
 		return err
 	}
 
-	pvtDataToCommit := r.preparePvtDataToCommit(fetchedData.AvailableElements)
+	pvtDataToCommit := preparePvtDataToCommit(r.Committer, fetchedData.AvailableElements)
 	// commit missing private data that was reconciled and log mismatched
 	pvtdataHashMismatch, err := r.CommitPvtData(pvtDataToCommit)
-	r.logMismatched(pvtdataHashMismatch)
+	logMismatched(pvtdataHashMismatch)
 	if err != nil {
 		return errors.Wrap(err, "failed to commit private data")
 	}
@@ -231,8 +230,8 @@ func (r *reconciler) getMostRecentCollectionConfig(chaincodeName string, collect
 	return staticCollectionConfig.StaticCollectionConfig, nil
 }
 
-func (r *reconciler) preparePvtDataToCommit(elements []*gossip2.PvtDataElement) []*ledger.BlockPvtData {
-	rwSetByBlockByKeys := r.groupRwsetByBlock(elements)
+func preparePvtDataToCommit(c committer.Committer, elements []*gossip2.PvtDataElement) []*ledger.BlockPvtData {
+	rwSetByBlockByKeys := groupRwsetByBlock(c, elements)
 
 	// populate the private RWSets passed to the ledger
 	var pvtDataToCommit []*ledger.BlockPvtData
@@ -255,7 +254,7 @@ func (r *reconciler) preparePvtDataToCommit(elements []*gossip2.PvtDataElement)
 	return pvtDataToCommit
 }
 
-func (r *reconciler) logMismatched(pvtdataMismatched []*ledger.PvtdataHashMismatch) {
+func logMismatched(pvtdataMismatched []*ledger.PvtdataHashMismatch) {
 	if len(pvtdataMismatched) > 0 {
 		for _, hashMismatch := range pvtdataMismatched {
 			logger.Warningf("failed to reconciliation pvtdata chaincode %s, collection %s, block num %d, tx num %d due to hash mismatch",
@@ -265,17 +264,27 @@ func (r *reconciler) logMismatched(pvtdataMismatched []*ledger.PvtdataHashMismat
 }
 
 // return a mapping from block num to rwsetByKeys
-func (r *reconciler) groupRwsetByBlock(elements []*gossip2.PvtDataElement) map[uint64]rwsetByKeys {
+func groupRwsetByBlock(c committer.Committer, elements []*gossip2.PvtDataElement) map[uint64]rwsetByKeys {
 	rwSetByBlockByKeys := make(map[uint64]rwsetByKeys) // map from block num to rwsetByKeys
+	hashFuncs := make(map[uint64]func([]byte) []byte)  // map from block num to the hash function of its write-sets
 
 	// Iterate over data fetched from peers
 	for _, element := range elements {
 		dig := element.Digest
+		hashFunc, exists := hashFuncs[dig.BlockSeq]
+		if !exists {
+			var err error
+			if hashFunc, err = committedBlockValueHashFunc(c, dig.BlockSeq); err != nil {
+				logger.Warningf("Failed resolving the write-set hashing algorithm of block [%d], skipping its private data: %s", dig.BlockSeq, err)
+				continue
+			}
+			hashFuncs[dig.BlockSeq] = hashFunc
+		}
 		if _, exists := rwSetByBlockByKeys[dig.BlockSeq]; !exists {
 			rwSetByBlockByKeys[dig.BlockSeq] = make(map[rwSetKey][]byte)
 		}
 		for _, rws := range element.Payload {
-			hash := hex.EncodeToString(util2.ComputeSHA256(rws))
+			hash := hex.EncodeToString(hashFunc(rws))
 			key := rwSetKey{
 				txID:       dig.TxId,
 				namespace:  dig.Namespace,
@@ -288,3 +297,12 @@ func (r *reconciler) groupRwsetByBlock(elements []*gossip2.PvtDataElement) map[u
 	}
 	return rwSetByBlockByKeys
 }
+
+// committedBlockValueHashFunc returns the function hashing the values of the write-sets of the committed block
+func committedBlockValueHashFunc(c committer.Committer, blockNum uint64) (func([]byte) []byte, error) {
+	blocks := c.GetBlocks([]uint64{blockNum})
+	if len(blocks) == 0 || blocks[0] == nil {
+		return nil, errors.Errorf("block [%d] is not available", blockNum)
+	}
+	return valueHashFunc(c, blocks[0])
+}
@@ -65,6 +65,7 @@ func TestNotReconcilingWhenCollectionConfigNotAvailable(t *testing.T) {
 	configHistoryRetriever.On("MostRecentCollectionConfigBelow", mock.Anything, mock.Anything).Return(&collectionConfigInfo, errors.New("fail to get collection config"))
 	committer.On("GetMissingPvtDataTracker").Return(missingPvtDataTracker, nil)
 	committer.On("GetConfigHistoryRetriever").Return(configHistoryRetriever, nil)
+	committer.On("GetBlocks", mock.Anything).Return([]*common.Block{(&blockFactory{channelID: "test"}).create()})
 
 	var fetchCalled bool
 	fetcher.On("FetchReconciledItems", mock.Anything).Run(func(args mock.Arguments) {
@@ -112,6 +113,7 @@ func TestReconciliationHappyPathWithoutScheduler(t *testing.T) {
 	configHistoryRetriever.On("MostRecentCollectionConfigBelow", mock.Anything, mock.Anything).Return(&collectionConfigInfo, nil)
 	committer.On("GetMissingPvtDataTracker").Return(missingPvtDataTracker, nil)
 	committer.On("GetConfigHistoryRetriever").Return(configHistoryRetriever, nil)
+	committer.On("GetBlocks", mock.Anything).Return([]*common.Block{(&blockFactory{channelID: "test"}).create()})
 
 	result := &privdatacommon.FetchedPvtDataContainer{}
 	fetcher.On("FetchReconciledItems", mock.Anything).Run(func(args mock.Arguments) {
@@ -185,6 +187,7 @@ func TestReconciliationHappyPathWithScheduler(t *testing.T) {
 	configHistoryRetriever.On("MostRecentCollectionConfigBelow", mock.Anything, mock.Anything).Return(&collectionConfigInfo, nil)
 	committer.On("GetMissingPvtDataTracker").Return(missingPvtDataTracker, nil)
 	committer.On("GetConfigHistoryRetriever").Return(configHistoryRetriever, nil)
+	committer.On("GetBlocks", mock.Anything).Return([]*common.Block{(&blockFactory{channelID: "test"}).create()})
 
 	result := &privdatacommon.FetchedPvtDataContainer{}
 	fetcher.On("FetchReconciledItems", mock.Anything).Run(func(args mock.Arguments) {
@@ -0,0 +1,92 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/gossip/api"
+	"github.com/hyperledger/fabric/gossip/common"
+	"github.com/hyperledger/fabric/gossip/discovery"
+	"github.com/hyperledger/fabric/gossip/filter"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/spf13/viper"
+)
+
+const trustedPeersKey = "peer.gossip.pvtData.trustedPeers"
+
+// trustedPeer identifies remote peers pinned in the configuration as the
+// preferred sources of the private data pulled by this peer, either by
+// endpoint, by organization, or by both
+type trustedPeer struct {
+	Endpoint string `mapstructure:"endpoint"`
+	MSPID    string `mapstructure:"mspid"`
+}
+
+// getTrustedPeers returns the trusted peers of the configuration, skipping
+// the entries which identify no peer
+func getTrustedPeers() []trustedPeer {
+	var configs []trustedPeer
+	if err := viper.UnmarshalKey(trustedPeersKey, &configs); err != nil {
+		logger.Warningf("Failed unmarshaling %s, no peer is trusted: %v", trustedPeersKey, err)
+		return nil
+	}
+	var trustedPeers []trustedPeer
+	for _, c := range configs {
+		if c.Endpoint == "" && c.MSPID == "" {
+			logger.Warning("Skipping an entry of", trustedPeersKey, "without an endpoint nor an mspid")
+			continue
+		}
+		trustedPeers = append(trustedPeers, c)
+	}
+	return trustedPeers
+}
+
+// trustedPeersFilter returns a routing filter selecting the members of the
+// channel which match one of the trusted peers
+func (p *puller) trustedPeersFilter() (filter.RoutingFilter, error) {
+	if len(p.trustedPeers) == 0 {
+		return filter.SelectNonePolicy, nil
+	}
+	// the organization of a member is only known through its identity,
+	// which the gossip layer evaluates
+	orgFilters := make(map[string]filter.RoutingFilter)
+	for _, tp := range p.trustedPeers {
+		if _, exists := orgFilters[tp.MSPID]; tp.MSPID == "" || exists {
+			continue
+		}
+		mspID := tp.MSPID
+		orgFilter, err := p.PeerFilter(common.ChainID(p.channel), func(peerSignature api.PeerSignature) bool {
+			return mspIDOf(peerSignature.PeerIdentity) == mspID
+		})
+		if err != nil {
+			return nil, err
+		}
+		orgFilters[mspID] = orgFilter
+	}
+	return func(member discovery.NetworkMember) bool {
+		for _, tp := range p.trustedPeers {
+			if tp.Endpoint != "" && tp.Endpoint != member.Endpoint && tp.Endpoint != member.InternalEndpoint {
+				continue
+			}
+			if tp.MSPID != "" && !orgFilters[tp.MSPID](member) {
+				continue
+			}
+			return true
+		}
+		return false
+	}, nil
+}
+
+// mspIDOf returns the MSP ID of a serialized identity, or an empty string if
+// the identity can't be unmarshaled
+func mspIDOf(identity api.PeerIdentityType) string {
+	sID := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(identity, sID); err != nil {
+		return ""
+	}
+	return sID.Mspid
+}
@@ -0,0 +1,76 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/gossip/api"
+	"github.com/hyperledger/fabric/gossip/common"
+	"github.com/hyperledger/fabric/gossip/discovery"
+	"github.com/hyperledger/fabric/gossip/filter"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// orgsGossip evaluates the routing predicates against an identity of the
+// organization mapped to each member
+type orgsGossip struct {
+	gossip
+	orgs map[string]string
+}
+
+func (g *orgsGossip) PeerFilter(channel common.ChainID, messagePredicate api.SubChannelSelectionCriteria) (filter.RoutingFilter, error) {
+	return func(member discovery.NetworkMember) bool {
+		identity, _ := proto.Marshal(&msp.SerializedIdentity{Mspid: g.orgs[string(member.PKIid)]})
+		return messagePredicate(api.PeerSignature{PeerIdentity: identity})
+	}, nil
+}
+
+func TestTrustedPeers(t *testing.T) {
+	defer viper.Reset()
+	assert.Empty(t, getTrustedPeers())
+
+	viper.Set(trustedPeersKey, []map[string]interface{}{
+		{"endpoint": "p1"},
+		{"mspid": "Org2MSP"},
+		{"endpoint": "p3", "mspid": "Org3MSP"},
+		{},
+	})
+	trustedPeers := getTrustedPeers()
+	assert.Equal(t, []trustedPeer{{Endpoint: "p1"}, {MSPID: "Org2MSP"}, {Endpoint: "p3", MSPID: "Org3MSP"}}, trustedPeers)
+
+	p := &puller{
+		channel:      "A",
+		trustedPeers: trustedPeers,
+		gossip: &orgsGossip{orgs: map[string]string{
+			"p1": "Org1MSP",
+			"p2": "Org2MSP",
+			"p3": "Org3MSP",
+			"p4": "Org3MSP",
+			"p5": "Org4MSP",
+		}},
+	}
+	trustedPeer, err := p.trustedPeersFilter()
+	assert.NoError(t, err)
+	members := membership(peerData{"p1", 1}, peerData{"p2", 1}, peerData{"p3", 1}, peerData{"p4", 1}, peerData{"p5", 1})
+	members[4].InternalEndpoint = "p1"
+	assert.True(t, trustedPeer(members[0]))
+	assert.True(t, trustedPeer(members[1]))
+	assert.True(t, trustedPeer(members[2]))
+	// p4 is in Org3MSP, but only the peer p3 of Org3MSP is trusted
+	assert.False(t, trustedPeer(members[3]))
+	// the internal endpoint of p5 is trusted
+	assert.True(t, trustedPeer(members[4]))
+
+	p.trustedPeers = nil
+	trustedPeer, err = p.trustedPeersFilter()
+	assert.NoError(t, err)
+	assert.Empty(t, filter.AnyMatch(members, trustedPeer))
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 	"github.com/hyperledger/fabric/protos/msp"
 	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
 )
 
 type txValidationFlags []uint8
@@ -139,6 +140,10 @@ func (bf *blockFactory) create() *common.Block {
 	block.Metadata = &common.BlockMetadata{
 		Metadata: make([][]byte, common.BlockMetadataIndex_TRANSACTIONS_FILTER+1),
 	}
+	// the block is its own last config, hashing its write-sets with the default algorithm
+	block.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG], _ = proto.Marshal(&common.Metadata{
+		Value: utils.MarshalOrPanic(&common.LastConfig{Index: block.Header.Number}),
+	})
 	if bf.metadataSize > 0 {
 		block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = make([]uint8, bf.metadataSize)
 	} else {
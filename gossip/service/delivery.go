@@ -0,0 +1,205 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/core/deliverservice"
+	"github.com/hyperledger/fabric/core/deliverservice/blocksprovider"
+	"github.com/hyperledger/fabric/gossip/discovery"
+	"github.com/hyperledger/fabric/gossip/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+const (
+	// deliveryByLeader has the leader elected among the peers of the
+	// organization pull the blocks from the ordering service
+	deliveryByLeader = "leader"
+	// deliveryByAll has all the peers pull the blocks from the ordering service
+	deliveryByAll = "all"
+	// deliveryByStatic has the peers configured as organization leaders pull
+	// the blocks from the ordering service
+	deliveryByStatic = "static"
+
+	defaultLagCheckInterval = 10 * time.Second
+)
+
+// deliveryConfig defines which peers of the organization pull the blocks of
+// the channels from the ordering service and disseminate them through gossip
+type deliveryConfig struct {
+	mode string
+	// orgLeader tells whether the peer pulls the blocks in the static mode
+	orgLeader bool
+	// lagThreshold is the number of blocks the ledger of a peer which doesn't
+	// pull the blocks can be behind the other peers of the channel before it
+	// pulls them from the ordering service as well, 0 disables the fallback
+	lagThreshold     uint64
+	lagCheckInterval time.Duration
+}
+
+// readDeliveryConfig reads the delivery configuration of the peer. Without a
+// peer.gossip.delivery.mode, the mode derives from peer.gossip.useLeaderElection
+// and peer.gossip.orgLeader, which can't be both set.
+func readDeliveryConfig() deliveryConfig {
+	leaderElection := viper.GetBool("peer.gossip.useLeaderElection")
+	isStaticOrgLeader := viper.GetBool("peer.gossip.orgLeader")
+	conf := deliveryConfig{
+		mode:             viper.GetString("peer.gossip.delivery.mode"),
+		orgLeader:        isStaticOrgLeader,
+		lagCheckInterval: util.GetDurationOrDefault("peer.gossip.delivery.lagCheckInterval", defaultLagCheckInterval),
+	}
+	if threshold := viper.GetInt("peer.gossip.delivery.lagThreshold"); threshold > 0 {
+		conf.lagThreshold = uint64(threshold)
+	}
+
+	switch conf.mode {
+	case "":
+		if leaderElection && isStaticOrgLeader {
+			logger.Panic("Setting both orgLeader and useLeaderElection to true isn't supported, aborting execution")
+		}
+		conf.mode = deliveryByStatic
+		if leaderElection {
+			conf.mode = deliveryByLeader
+		}
+	case deliveryByLeader, deliveryByAll, deliveryByStatic:
+	default:
+		logger.Panicf("Unsupported delivery mode %q, it must be one of %s, %s or %s, aborting execution",
+			conf.mode, deliveryByLeader, deliveryByAll, deliveryByStatic)
+	}
+	return conf
+}
+
+// channelDelivery starts and stops the delivery of the blocks of a channel
+// from the ordering service, which runs while the peer is assigned to pull
+// the blocks, or while its ledger lags behind the other peers of the channel
+type channelDelivery struct {
+	chainID string
+	service deliverclient.DeliverService
+	ledger  blocksprovider.LedgerInfo
+	// yield relinquishes the leadership of the peer once the delivery fails,
+	// nil unless the leader is elected
+	yield func()
+
+	lock     sync.Mutex
+	assigned bool
+	lagging  bool
+	running  bool
+	// generation counts the deliveries started, so that the end of a
+	// delivery stopped or replaced since isn't taken for the current one
+	generation uint64
+	done       chan struct{}
+	stopOnce   sync.Once
+}
+
+func newChannelDelivery(chainID string, service deliverclient.DeliverService, ledger blocksprovider.LedgerInfo) *channelDelivery {
+	return &channelDelivery{
+		chainID: chainID,
+		service: service,
+		ledger:  ledger,
+		done:    make(chan struct{}),
+	}
+}
+
+// assign sets whether the peer is assigned to pull the blocks of the channel
+func (d *channelDelivery) assign(assigned bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.assigned = assigned
+	d.update()
+}
+
+// update starts or stops the delivery, must be called with the lock held
+func (d *channelDelivery) update() {
+	run := d.assigned || d.lagging
+	if run == d.running {
+		return
+	}
+	if run {
+		d.generation++
+		generation := d.generation
+		finalizer := func() { d.finalize(generation) }
+		if err := d.service.StartDeliverForChannel(d.chainID, d.ledger, finalizer); err != nil {
+			logger.Errorf("Delivery service is not able to start blocks delivery for chain, due to %+v", errors.WithStack(err))
+			return
+		}
+	} else {
+		if err := d.service.StopDeliverForChannel(d.chainID); err != nil {
+			logger.Errorf("Delivery service is not able to stop blocks delivery for chain, due to %+v", errors.WithStack(err))
+		}
+	}
+	d.running = run
+}
+
+// finalize is called once the delivery of the given generation ends, and
+// yields the leadership of the peer if it was elected to pull the blocks.
+// A delivery which ended on its own, as it was not stopped, is marked as
+// not running, so that the next update starts it again.
+func (d *channelDelivery) finalize(generation uint64) {
+	d.lock.Lock()
+	if d.running && d.generation == generation {
+		logger.Warningf("Delivery of the blocks of channel %s ended", d.chainID)
+		// the blocks provider of the delivery is released before it is started again
+		if err := d.service.StopDeliverForChannel(d.chainID); err != nil {
+			logger.Warningf("Failed releasing the delivery of channel %s: %s", d.chainID, err)
+		}
+		d.running = false
+	}
+	elected := d.assigned && d.yield != nil
+	d.lock.Unlock()
+	if elected {
+		d.yield()
+	}
+}
+
+// watchLag checks every interval the height of the ledger of the channel
+// against the highest height advertised by the peers of the channel. Once
+// it is more than threshold blocks behind, the peer pulls the blocks from the
+// ordering service until it catches up, so that it doesn't depend on the
+// dissemination of the blocks by gossip while it can't keep up.
+func (d *channelDelivery) watchLag(peers func() []discovery.NetworkMember, threshold uint64, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-d.done:
+			return
+		}
+
+		height, err := d.ledger.LedgerHeight()
+		if err != nil {
+			logger.Warningf("Failed getting the ledger height of channel %s: %s", d.chainID, err)
+			continue
+		}
+		var maxHeight uint64
+		for _, p := range peers() {
+			if p.Properties != nil && p.Properties.LedgerHeight > maxHeight {
+				maxHeight = p.Properties.LedgerHeight
+			}
+		}
+
+		d.lock.Lock()
+		switch {
+		case !d.lagging && maxHeight > height+threshold:
+			logger.Infof("Ledger of channel %s is %d blocks behind its peers, pulling the blocks from the ordering service", d.chainID, maxHeight-height)
+			d.lagging = true
+		case d.lagging && height >= maxHeight:
+			logger.Infof("Ledger of channel %s caught up with its peers at height %d", d.chainID, height)
+			d.lagging = false
+		}
+		d.update()
+		d.lock.Unlock()
+	}
+}
+
+// stop stops watching the lag of the ledger
+func (d *channelDelivery) stop() {
+	d.stopOnce.Do(func() { close(d.done) })
+}
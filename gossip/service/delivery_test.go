@@ -0,0 +1,217 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package service
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/deliverservice/blocksprovider"
+	"github.com/hyperledger/fabric/gossip/discovery"
+	proto "github.com/hyperledger/fabric/protos/gossip"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingDeliverService struct {
+	mockDeliverService
+	lock    sync.Mutex
+	running bool
+	starts  int
+	stops   int
+	// finalizer is the finalizer of the last delivery started
+	finalizer func()
+}
+
+func (ds *countingDeliverService) StartDeliverForChannel(chainID string, ledgerInfo blocksprovider.LedgerInfo, finalizer func()) error {
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	ds.running = true
+	ds.starts++
+	ds.finalizer = finalizer
+	return nil
+}
+
+// exit ends the last delivery started as if it ended on its own
+func (ds *countingDeliverService) exit() {
+	ds.lock.Lock()
+	finalizer := ds.finalizer
+	ds.lock.Unlock()
+	finalizer()
+}
+
+func (ds *countingDeliverService) StopDeliverForChannel(chainID string) error {
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	ds.running = false
+	ds.stops++
+	return nil
+}
+
+func (ds *countingDeliverService) isRunning() bool {
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	return ds.running
+}
+
+func TestReadDeliveryConfig(t *testing.T) {
+	defer viper.Reset()
+
+	viper.Set("peer.gossip.useLeaderElection", true)
+	viper.Set("peer.gossip.orgLeader", false)
+	conf := readDeliveryConfig()
+	assert.Equal(t, deliveryByLeader, conf.mode)
+	assert.Equal(t, uint64(0), conf.lagThreshold)
+	assert.Equal(t, defaultLagCheckInterval, conf.lagCheckInterval)
+
+	viper.Set("peer.gossip.useLeaderElection", false)
+	viper.Set("peer.gossip.orgLeader", true)
+	conf = readDeliveryConfig()
+	assert.Equal(t, deliveryByStatic, conf.mode)
+	assert.True(t, conf.orgLeader)
+
+	// the mode supersedes the leader election flag
+	viper.Set("peer.gossip.useLeaderElection", true)
+	viper.Set("peer.gossip.orgLeader", false)
+	viper.Set("peer.gossip.delivery.mode", "all")
+	viper.Set("peer.gossip.delivery.lagThreshold", 5)
+	viper.Set("peer.gossip.delivery.lagCheckInterval", "2s")
+	conf = readDeliveryConfig()
+	assert.Equal(t, deliveryByAll, conf.mode)
+	assert.Equal(t, uint64(5), conf.lagThreshold)
+	assert.Equal(t, 2*time.Second, conf.lagCheckInterval)
+
+	viper.Set("peer.gossip.delivery.mode", "everyone")
+	assert.Panics(t, func() { readDeliveryConfig() })
+
+	viper.Set("peer.gossip.delivery.mode", "")
+	viper.Set("peer.gossip.orgLeader", true)
+	assert.Panics(t, func() { readDeliveryConfig() })
+}
+
+func TestChannelDeliveryAssign(t *testing.T) {
+	ds := &countingDeliverService{}
+	delivery := newChannelDelivery("chanA", ds, &mockLedgerInfo{1})
+
+	delivery.assign(true)
+	delivery.assign(true)
+	assert.True(t, ds.isRunning())
+	assert.Equal(t, 1, ds.starts)
+
+	delivery.assign(false)
+	assert.False(t, ds.isRunning())
+	assert.Equal(t, 1, ds.stops)
+}
+
+func TestChannelDeliveryYield(t *testing.T) {
+	ds := &countingDeliverService{}
+	delivery := newChannelDelivery("chanA", ds, &mockLedgerInfo{1})
+	yields := 0
+	delivery.yield = func() { yields++ }
+
+	// the leadership is only yielded while elected
+	delivery.assign(true)
+	delivery.assign(false)
+	ds.exit()
+	assert.Equal(t, 0, yields)
+	delivery.assign(true)
+	ds.exit()
+	assert.Equal(t, 1, yields)
+}
+
+func TestChannelDeliveryExit(t *testing.T) {
+	ds := &countingDeliverService{}
+	delivery := newChannelDelivery("chanA", ds, &mockLedgerInfo{1})
+
+	delivery.assign(true)
+	stopped := ds.finalizer
+	delivery.assign(false)
+	delivery.assign(true)
+	assert.Equal(t, 2, ds.starts)
+	assert.Equal(t, 1, ds.stops)
+
+	// the end of a delivery stopped since doesn't affect the current one
+	stopped()
+	assert.True(t, delivery.running)
+	assert.Equal(t, 1, ds.stops)
+
+	// a delivery which ended on its own is released and started again by the
+	// next update
+	ds.exit()
+	assert.False(t, delivery.running)
+	assert.Equal(t, 2, ds.stops)
+	delivery.assign(true)
+	assert.True(t, ds.isRunning())
+	assert.Equal(t, 3, ds.starts)
+}
+
+func TestChannelDeliveryLagFallback(t *testing.T) {
+	ds := &countingDeliverService{}
+	ledger := &mockLedgerInfo{10}
+	delivery := newChannelDelivery("chanA", ds, ledger)
+	defer delivery.stop()
+
+	var lock sync.Mutex
+	peerHeight := uint64(12)
+	setPeerHeight := func(height uint64) {
+		lock.Lock()
+		defer lock.Unlock()
+		peerHeight = height
+	}
+	peers := func() []discovery.NetworkMember {
+		lock.Lock()
+		defer lock.Unlock()
+		return []discovery.NetworkMember{
+			{Endpoint: "p1"},
+			{Endpoint: "p2", Properties: &proto.Properties{LedgerHeight: peerHeight}},
+		}
+	}
+	go delivery.watchLag(peers, 5, 10*time.Millisecond)
+
+	waitFor := func(running bool) bool {
+		deadline := time.Now().Add(time.Second)
+		for ds.isRunning() != running && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		return ds.isRunning() == running
+	}
+
+	// a lag within the threshold is left to gossip
+	time.Sleep(100 * time.Millisecond)
+	assert.False(t, ds.isRunning())
+
+	setPeerHeight(20)
+	assert.True(t, waitFor(true), "delivery wasn't started once lagging")
+
+	// the delivery keeps running until the ledger caught up
+	setPeerHeight(14)
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, ds.isRunning())
+	setPeerHeight(10)
+	assert.True(t, waitFor(false), "delivery wasn't stopped once caught up")
+	assert.Equal(t, 1, ds.starts)
+}
+
+func TestChannelDeliveryLagFallbackWhileAssigned(t *testing.T) {
+	ds := &countingDeliverService{}
+	delivery := newChannelDelivery("chanA", ds, &mockLedgerInfo{1})
+	defer delivery.stop()
+	delivery.assign(true)
+
+	peers := func() []discovery.NetworkMember {
+		return []discovery.NetworkMember{{Properties: &proto.Properties{LedgerHeight: 100}}}
+	}
+	go delivery.watchLag(peers, 5, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+
+	// the delivery of the assigned peer is neither started again nor stopped
+	delivery.assign(false)
+	assert.Equal(t, 1, ds.starts)
+	assert.Equal(t, 0, ds.stops)
+	assert.True(t, ds.isRunning())
+}
@@ -9,13 +9,15 @@ package service
 import (
 	"sync"
 
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/core/committer"
 	"github.com/hyperledger/fabric/core/committer/txvalidator"
 	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/deliverservice"
-	"github.com/hyperledger/fabric/core/deliverservice/blocksprovider"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/gossip/api"
 	gossipCommon "github.com/hyperledger/fabric/gossip/common"
+	"github.com/hyperledger/fabric/gossip/discovery"
 	"github.com/hyperledger/fabric/gossip/election"
 	"github.com/hyperledger/fabric/gossip/gossip"
 	"github.com/hyperledger/fabric/gossip/integration"
@@ -24,6 +26,7 @@ import (
 	"github.com/hyperledger/fabric/gossip/util"
 	"github.com/hyperledger/fabric/protos/common"
 	gproto "github.com/hyperledger/fabric/protos/gossip"
+	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/transientstore"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
@@ -42,8 +45,9 @@ type GossipService interface {
 	gossip.Gossip
 
 	// DistributePrivateData distributes private data to the peers in the collections
-	// according to policies induced by the PolicyStore and PolicyParser
-	DistributePrivateData(chainID string, txID string, privateData *transientstore.TxPvtReadWriteSetWithConfigInfo, blkHt uint64) error
+	// according to policies induced by the PolicyStore and PolicyParser,
+	// and reports the peers which acknowledged the receipt of the private data
+	DistributePrivateData(chainID string, txID string, privateData *transientstore.TxPvtReadWriteSetWithConfigInfo, blkHt uint64) (*peer.PrivateDataDissemination, error)
 	// NewConfigEventer creates a ConfigProcessor which the channelconfig.BundleSource can ultimately route config updates to
 	NewConfigEventer() ConfigProcessor
 	// InitializeChannel allocates the state provider and should be invoked once per channel per execution
@@ -63,12 +67,28 @@ type deliveryFactoryImpl struct {
 
 // Returns an instance of delivery client
 func (*deliveryFactoryImpl) Service(g GossipService, endpoints []string, mcs api.MessageCryptoService) (deliverclient.DeliverService, error) {
+	var metricsScope metrics.Scope
+	if metrics.RootScope != nil {
+		metricsScope = metrics.RootScope.SubScope("deliveryclient")
+	}
+	if ledgerconfig.IsReadReplica() {
+		// the blocks of a read replica are validated by its committer
+		return deliverclient.NewDeliverService(&deliverclient.Config{
+			CryptoSvc:   mcs,
+			Gossip:      g,
+			Endpoints:   []string{ledgerconfig.GetReplicationCommitterAddress()},
+			ConnFactory: deliverclient.PeerConnectionFactory,
+			ABCFactory:  deliverclient.PeerABCFactory,
+			Metrics:     metricsScope,
+		})
+	}
 	return deliverclient.NewDeliverService(&deliverclient.Config{
 		CryptoSvc:   mcs,
 		Gossip:      g,
 		Endpoints:   endpoints,
 		ConnFactory: deliverclient.DefaultConnectionFactory,
 		ABCFactory:  deliverclient.DefaultABCFactory,
+		Metrics:     metricsScope,
 	})
 }
 
@@ -77,11 +97,13 @@ type privateHandler struct {
 	coordinator privdata2.Coordinator
 	distributor privdata2.PvtDataDistributor
 	reconciler  privdata2.Reconciler
+	antiEntropy privdata2.AntiEntropy
 }
 
 func (p privateHandler) close() {
 	p.coordinator.Close()
 	p.reconciler.Stop()
+	p.antiEntropy.Stop()
 }
 
 type gossipServiceImpl struct {
@@ -90,6 +112,7 @@ type gossipServiceImpl struct {
 	chains          map[string]state.GossipStateProvider
 	leaderElection  map[string]election.LeaderElectionService
 	deliveryService map[string]deliverclient.DeliverService
+	deliveries      map[string]*channelDelivery
 	deliveryFactory DeliveryServiceFactory
 	lock            sync.RWMutex
 	mcs             api.MessageCryptoService
@@ -159,6 +182,7 @@ func InitGossipServiceCustomDeliveryFactory(peerIdentity []byte, endpoint string
 			chains:          make(map[string]state.GossipStateProvider),
 			leaderElection:  make(map[string]election.LeaderElectionService),
 			deliveryService: make(map[string]deliverclient.DeliverService),
+			deliveries:      make(map[string]*channelDelivery),
 			deliveryFactory: factory,
 			peerIdentity:    peerIdentity,
 			secAdv:          secAdv,
@@ -173,25 +197,26 @@ func GetGossipService() GossipService {
 }
 
 // DistributePrivateData distribute private read write set inside the channel based on the collections policies
-func (g *gossipServiceImpl) DistributePrivateData(chainID string, txID string, privData *transientstore.TxPvtReadWriteSetWithConfigInfo, blkHt uint64) error {
+func (g *gossipServiceImpl) DistributePrivateData(chainID string, txID string, privData *transientstore.TxPvtReadWriteSetWithConfigInfo, blkHt uint64) (*peer.PrivateDataDissemination, error) {
 	g.lock.RLock()
 	handler, exists := g.privateHandlers[chainID]
 	g.lock.RUnlock()
 	if !exists {
-		return errors.Errorf("No private data handler for %s", chainID)
+		return nil, errors.Errorf("No private data handler for %s", chainID)
 	}
 
-	if err := handler.distributor.Distribute(txID, privData, blkHt); err != nil {
+	dissemination, err := handler.distributor.Distribute(txID, privData, blkHt)
+	if err != nil {
 		logger.Error("Failed to distributed private collection, txID", txID, "channel", chainID, "due to", err)
-		return err
+		return nil, err
 	}
 
 	if err := handler.coordinator.StorePvtData(txID, privData, blkHt); err != nil {
 		logger.Error("Failed to store private data into transient store, txID",
 			txID, "channel", chainID, "due to", err)
-		return err
+		return nil, err
 	}
-	return nil
+	return dissemination, nil
 }
 
 // NewConfigEventer creates a ConfigProcessor which the channelconfig.BundleSource can ultimately route config updates to
@@ -236,6 +261,11 @@ func (g *gossipServiceImpl) InitializeChannel(chainID string, endpoints []string
 	collectionAccessFactory := privdata2.NewCollectionAccessFactory(support.IdDeserializeFactory)
 	fetcher := privdata2.NewPuller(support.Cs, g.gossipSvc, dataRetriever, collectionAccessFactory, chainID)
 
+	var committerMetrics, stateMetrics metrics.Scope
+	if metrics.RootScope != nil {
+		committerMetrics = metrics.RootScope.SubScope("committer")
+		stateMetrics = metrics.RootScope.SubScope("gossip_state")
+	}
 	coordinator := privdata2.NewCoordinator(privdata2.Support{
 		ChainID:         chainID,
 		CollectionStore: support.Cs,
@@ -243,17 +273,30 @@ func (g *gossipServiceImpl) InitializeChannel(chainID string, endpoints []string
 		TransientStore:  support.Store,
 		Committer:       support.Committer,
 		Fetcher:         fetcher,
+		Metrics:         committerMetrics,
 	}, g.createSelfSignedData())
 
+	var antiEntropy privdata2.AntiEntropy = &privdata2.NoOpAntiEntropy{}
+	if viper.GetBool("peer.gossip.pvtData.antiEntropy.enabled") {
+		antiEntropy = privdata2.NewAntiEntropy(privdata2.AntiEntropySupport{
+			ChainID:         chainID,
+			CollectionStore: support.Cs,
+			Committer:       support.Committer,
+			Fetcher:         fetcher,
+		}, g.gossipSvc, g.createSelfSignedData(), privdata2.GetAntiEntropyConfig())
+	}
+
 	g.privateHandlers[chainID] = privateHandler{
 		support:     support,
 		coordinator: coordinator,
 		distributor: privdata2.NewDistributor(chainID, g, collectionAccessFactory),
 		reconciler:  &privdata2.NoOpReconciler{},
+		antiEntropy: antiEntropy,
 	}
 	g.privateHandlers[chainID].reconciler.Start()
+	g.privateHandlers[chainID].antiEntropy.Start()
 
-	g.chains[chainID] = state.NewGossipStateProvider(chainID, servicesAdapter, coordinator)
+	g.chains[chainID] = state.NewGossipStateProvider(chainID, servicesAdapter, coordinator, stateMetrics)
 	if g.deliveryService[chainID] == nil {
 		var err error
 		g.deliveryService[chainID], err = g.deliveryFactory.Service(g, endpoints, g.mcs)
@@ -270,23 +313,45 @@ func (g *gossipServiceImpl) InitializeChannel(chainID string, endpoints []string
 		//              - peer.gossip.orgLeader
 		//
 		// are mutual exclusive, setting both to true is not defined, hence
-		// peer will panic and terminate
-		leaderElection := viper.GetBool("peer.gossip.useLeaderElection")
-		isStaticOrgLeader := viper.GetBool("peer.gossip.orgLeader")
+		// peer will panic and terminate. They are superseded by
+		// peer.gossip.delivery.mode when it is set
+		conf := readDeliveryConfig()
 
-		if leaderElection && isStaticOrgLeader {
-			logger.Panic("Setting both orgLeader and useLeaderElection to true isn't supported, aborting execution")
+		if ledgerconfig.IsReadReplica() {
+			logger.Debug("This peer is a read replica, its committer delivers the blocks of channel", chainID)
+			g.deliveryService[chainID].StartDeliverForChannel(chainID, support.Committer, func() {})
+			return
 		}
 
-		if leaderElection {
+		delivery := newChannelDelivery(chainID, g.deliveryService[chainID], support.Committer)
+		g.deliveries[chainID] = delivery
+		switch {
+		case conf.mode == deliveryByLeader:
 			logger.Debug("Delivery uses dynamic leader election mechanism, channel", chainID)
-			g.leaderElection[chainID] = g.newLeaderElectionComponent(chainID, g.onStatusChangeFactory(chainID, support.Committer))
-		} else if isStaticOrgLeader {
+			delivery.yield = func() {
+				g.lock.RLock()
+				le := g.leaderElection[chainID]
+				g.lock.RUnlock()
+				le.Yield()
+			}
+			g.leaderElection[chainID] = g.newLeaderElectionComponent(chainID, g.onStatusChangeFactory(chainID, delivery))
+		case conf.mode == deliveryByAll:
+			logger.Debug("All the peers are configured to connect to ordering service for blocks delivery, channel", chainID)
+			delivery.assign(true)
+		case conf.orgLeader:
 			logger.Debug("This peer is configured to connect to ordering service for blocks delivery, channel", chainID)
-			g.deliveryService[chainID].StartDeliverForChannel(chainID, support.Committer, func() {})
-		} else {
+			delivery.assign(true)
+		default:
 			logger.Debug("This peer is not configured to connect to ordering service for blocks delivery, channel", chainID)
 		}
+
+		if conf.lagThreshold > 0 && conf.mode != deliveryByAll {
+			logger.Debugf("Pulling the blocks of channel %s from the ordering service when lagging more than %d blocks behind its peers", chainID, conf.lagThreshold)
+			peers := func() []discovery.NetworkMember {
+				return g.PeersOfChannel(gossipCommon.ChainID(chainID))
+			}
+			go delivery.watchLag(peers, conf.lagThreshold, conf.lagCheckInterval)
+		}
 	} else {
 		logger.Warning("Delivery client is down won't be able to pull blocks for chain", chainID)
 	}
@@ -332,6 +397,10 @@ func (g *gossipServiceImpl) updateAnchors(config Config) {
 }
 
 func (g *gossipServiceImpl) updateEndpoints(chainID string, endpoints []string) {
+	if ledgerconfig.IsReadReplica() {
+		// the blocks of a read replica are delivered by its committer
+		return
+	}
 	if ds, ok := g.deliveryService[chainID]; ok {
 		logger.Debugf("Updating endpoints for chainID", chainID)
 		if err := ds.UpdateEndpoints(chainID, endpoints); err != nil {
@@ -360,6 +429,9 @@ func (g *gossipServiceImpl) Stop() {
 			logger.Infof("Stopping leader election for %s", chainID)
 			le.Stop()
 		}
+		if delivery, exists := g.deliveries[chainID]; exists {
+			delivery.stop()
+		}
 		g.chains[chainID].Stop()
 		g.privateHandlers[chainID].close()
 
@@ -385,27 +457,14 @@ func (g *gossipServiceImpl) amIinChannel(myOrg string, config Config) bool {
 	return false
 }
 
-func (g *gossipServiceImpl) onStatusChangeFactory(chainID string, committer blocksprovider.LedgerInfo) func(bool) {
+func (g *gossipServiceImpl) onStatusChangeFactory(chainID string, delivery *channelDelivery) func(bool) {
 	return func(isLeader bool) {
 		if isLeader {
-			yield := func() {
-				g.lock.RLock()
-				le := g.leaderElection[chainID]
-				g.lock.RUnlock()
-				le.Yield()
-			}
 			logger.Info("Elected as a leader, starting delivery service for channel", chainID)
-			if err := g.deliveryService[chainID].StartDeliverForChannel(chainID, committer, yield); err != nil {
-				logger.Errorf("Delivery service is not able to start blocks delivery for chain, due to %+v", errors.WithStack(err))
-			}
 		} else {
 			logger.Info("Renounced leadership, stopping delivery service for channel", chainID)
-			if err := g.deliveryService[chainID].StopDeliverForChannel(chainID); err != nil {
-				logger.Errorf("Delivery service is not able to stop blocks delivery for chain, due to %+v", errors.WithStack(err))
-			}
-
 		}
-
+		delivery.assign(isLeader)
 	}
 }
 
@@ -31,6 +31,10 @@ type PayloadsBuffer interface {
 	// Get current buffer size
 	Size() int
 
+	// Get the number of payloads with consecutive sequence numbers
+	// starting from the next expected one, which can be committed
+	Consecutive() int
+
 	// Channel to indicate event when new payload pushed with sequence
 	// number equal to the next expected value.
 	Ready() chan struct{}
@@ -141,6 +145,18 @@ func (b *PayloadsBufferImpl) Size() int {
 	return len(b.buf)
 }
 
+// Consecutive returns the number of payloads stored within buffer whose
+// sequence numbers follow each other from the next expected one
+func (b *PayloadsBufferImpl) Consecutive() int {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	count := 0
+	for seqNum := b.Next(); b.buf[seqNum] != nil; seqNum++ {
+		count++
+	}
+	return count
+}
+
 // Close cleanups resources and channels in maintained
 func (b *PayloadsBufferImpl) Close() {
 	close(b.readyChan)
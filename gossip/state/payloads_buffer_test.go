@@ -263,3 +263,26 @@ func TestPayloadsBufferImpl_Interleave(t *testing.T) {
 		t.Log("buffer not ready (3) -- good")
 	}
 }
+
+func TestPayloadsBufferImpl_Consecutive(t *testing.T) {
+	buffer := NewPayloadsBuffer(5)
+	assert.Equal(t, 0, buffer.Consecutive())
+
+	for _, seqNum := range []uint64{6, 7, 9} {
+		payload, err := randomPayloadWithSeqNum(seqNum)
+		assert.NoError(t, err)
+		buffer.Push(payload)
+	}
+	// the next expected block is missing
+	assert.Equal(t, 3, buffer.Size())
+	assert.Equal(t, 0, buffer.Consecutive())
+
+	payload, err := randomPayloadWithSeqNum(5)
+	assert.NoError(t, err)
+	buffer.Push(payload)
+	assert.Equal(t, 3, buffer.Consecutive())
+
+	buffer.Pop()
+	assert.Equal(t, 3, buffer.Size())
+	assert.Equal(t, 2, buffer.Consecutive())
+}
@@ -13,16 +13,22 @@ import (
 	"time"
 
 	pb "github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/capabilities"
 	vsccErrors "github.com/hyperledger/fabric/common/errors"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/core/config/reload"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/gossip/api"
 	"github.com/hyperledger/fabric/gossip/comm"
 	common2 "github.com/hyperledger/fabric/gossip/common"
 	"github.com/hyperledger/fabric/gossip/discovery"
+	"github.com/hyperledger/fabric/gossip/privdata"
 	"github.com/hyperledger/fabric/gossip/util"
 	"github.com/hyperledger/fabric/protos/common"
 	proto "github.com/hyperledger/fabric/protos/gossip"
 	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	"github.com/hyperledger/fabric/protos/transientstore"
+	putils "github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 )
@@ -47,6 +53,14 @@ const (
 
 	defMaxBlockDistance = 100
 
+	defMaxPayloadBufferSize = defMaxBlockDistance * 2
+	maxPayloadBufferSizeKey = "peer.gossip.maxPayloadBufferSize"
+
+	defDeepHistoryDepth = 1000
+	deepHistoryDepthKey = "peer.gossip.deepHistoryDepth"
+
+	commitPipeliningKey = "peer.gossip.commitPipelining"
+
 	blocking    = true
 	nonBlocking = false
 
@@ -147,13 +161,42 @@ type GossipStateProviderImpl struct {
 	once sync.Once
 
 	stateTransferActive int32
+
+	// Number of blocks behind the highest ledger height of the channel
+	// beyond which blocks are requested from archive peers, accessed
+	// atomically since it may be changed by reloading the configuration
+	deepHistoryDepth uint64
+
+	unsubscribeDeepHistoryDepth func()
+
+	// Number of blocks in the payloads buffer beyond which the blocks
+	// received from the ordering service wait for the commit of the
+	// buffered ones, so that the delivery client stops reading them
+	maxPayloadBufferSize int
+
+	// Whether the blocks lacking the validation codes of their transactions
+	// are discarded, since read replicas don't validate the blocks
+	validatedBlocksOnly bool
+
+	// The ledger resources validating a block while the previous one is
+	// committed, nil if the blocks are validated and committed in turn
+	pipeline privdata.PipelinedCoordinator
+
+	// Blocks validated ahead of their commit, handed over to the goroutine
+	// committing them
+	validatedBlocks chan *validatedBlock
+
+	payloadBufferSize        metrics.Gauge
+	blocksAwaitingValidation metrics.Gauge
+	backpressureDuration     metrics.Histogram
 }
 
 var logger = util.GetLogger(util.LoggingStateModule, "")
 
 // NewGossipStateProvider creates state provider with coordinator instance
 // to orchestrate arrival of private rwsets and blocks before committing them into the ledger.
-func NewGossipStateProvider(chainID string, services *ServicesMediator, ledger ledgerResources) GossipStateProvider {
+// The metrics of the payloads buffer are emitted to the given scope, if not nil.
+func NewGossipStateProvider(chainID string, services *ServicesMediator, ledger ledgerResources, metricsScope metrics.Scope) GossipStateProvider {
 
 	gossipChan, _ := services.Accept(func(message interface{}) bool {
 		// Get only data messages
@@ -197,6 +240,11 @@ func NewGossipStateProvider(chainID string, services *ServicesMediator, ledger l
 		return nil
 	}
 
+	if metricsScope == nil {
+		metricsScope = metrics.NewNoOpScope()
+	}
+	metricsScope = metricsScope.Tagged(map[string]string{"channel": chainID})
+
 	s := &GossipStateProviderImpl{
 		// MessageCryptoService
 		mediator: services,
@@ -224,6 +272,23 @@ func NewGossipStateProvider(chainID string, services *ServicesMediator, ledger l
 		stateTransferActive: 0,
 
 		once: sync.Once{},
+
+		deepHistoryDepth: uint64(util.GetIntOrDefault(deepHistoryDepthKey, defDeepHistoryDepth)),
+
+		maxPayloadBufferSize: util.GetIntOrDefault(maxPayloadBufferSizeKey, defMaxPayloadBufferSize),
+
+		validatedBlocksOnly: ledgerconfig.IsReadReplica(),
+
+		payloadBufferSize:        metricsScope.Gauge("payload_buffer_size"),
+		blocksAwaitingValidation: metricsScope.Gauge("blocks_awaiting_validation"),
+		backpressureDuration:     metricsScope.Histogram("backpressure_duration"),
+	}
+
+	s.unsubscribeDeepHistoryDepth, err = reload.Subscribe(deepHistoryDepthKey, func(interface{}) {
+		atomic.StoreUint64(&s.deepHistoryDepth, uint64(util.GetIntOrDefault(deepHistoryDepthKey, defDeepHistoryDepth)))
+	})
+	if err != nil {
+		logger.Panicf("Failed subscribing to the changes of %s: %s", deepHistoryDepthKey, err)
 	}
 
 	logger.Infof("Updating metadata information, "+
@@ -231,6 +296,15 @@ func NewGossipStateProvider(chainID string, services *ServicesMediator, ledger l
 	logger.Debug("Updating gossip ledger height to", height)
 	services.UpdateLedgerHeight(height, common2.ChainID(s.chainID))
 
+	if pipeline, isPipelined := ledger.(privdata.PipelinedCoordinator); isPipelined && viper.GetBool(commitPipeliningKey) {
+		logger.Infof("[%s] Validating the blocks while the previous ones are committed", chainID)
+		s.pipeline = pipeline
+		s.validatedBlocks = make(chan *validatedBlock)
+		s.done.Add(1)
+		// Commit the blocks validated by deliverPayloads
+		go s.commitValidatedBlocks()
+	}
+
 	s.done.Add(4)
 
 	// Listen for incoming communication
@@ -493,6 +567,9 @@ func (s *GossipStateProviderImpl) Stop() {
 	// Make sure stop won't be executed twice
 	// and stop channel won't be used again
 	s.once.Do(func() {
+		if s.unsubscribeDeepHistoryDepth != nil {
+			s.unsubscribeDeepHistoryDepth()
+		}
 		s.stopCh <- struct{}{}
 		// Make sure all go-routines has finished
 		s.done.Wait()
@@ -514,6 +591,11 @@ func (s *GossipStateProviderImpl) queueNewMessage(msg *proto.GossipMessage) {
 
 	dataMsg := msg.GetDataMsg()
 	if dataMsg != nil {
+		if s.validatedBlocksOnly && !hasValidationCodes(dataMsg.GetPayload()) {
+			// the blocks disseminated from the ordering service are expected
+			logger.Debugf("[%s] Ignoring block [%d] received from gossip, it was not validated", s.chainID, dataMsg.GetPayload().GetSeqNum())
+			return
+		}
 		if err := s.addPayload(dataMsg.GetPayload(), nonBlocking); err != nil {
 			logger.Warningf("Block [%d] received from gossip wasn't added to payload buffer: %v", dataMsg.Payload.SeqNum, err)
 			return
@@ -534,8 +616,11 @@ func (s *GossipStateProviderImpl) deliverPayloads() {
 			logger.Debugf("[%s] Ready to transfer payloads (blocks) to the ledger, next block number is = [%d]", s.chainID, s.payloads.Next())
 			// Collect all subsequent payloads
 			for payload := s.payloads.Pop(); payload != nil; payload = s.payloads.Pop() {
-				rawBlock := &common.Block{}
-				if err := pb.Unmarshal(payload.Data, rawBlock); err != nil {
+				s.updateBufferMetrics()
+				// the transactions of the block refer to the payload, which
+				// isn't modified once received
+				rawBlock, err := putils.GetBlockFromBlockBytesNoCopy(payload.Data)
+				if err != nil {
 					logger.Errorf("Error getting block with seqNum = %d due to (%+v)...dropping block", payload.SeqNum, errors.WithStack(err))
 					continue
 				}
@@ -555,12 +640,15 @@ func (s *GossipStateProviderImpl) deliverPayloads() {
 						continue
 					}
 				}
-				if err := s.commitBlock(rawBlock, p); err != nil {
-					if executionErr, isExecutionErr := err.(*vsccErrors.VSCCExecutionFailureError); isExecutionErr {
-						logger.Errorf("Failed executing VSCC due to %v. Aborting chain processing", executionErr)
+				if s.pipeline != nil {
+					if !s.validateBlock(rawBlock, p) {
 						return
 					}
-					logger.Panicf("Cannot commit block to the ledger due to %+v", errors.WithStack(err))
+					continue
+				}
+				if err := s.commitBlock(rawBlock, p); err != nil {
+					s.handleCommitFailure(rawBlock, err)
+					return
 				}
 			}
 		case <-s.stopCh:
@@ -571,6 +659,85 @@ func (s *GossipStateProviderImpl) deliverPayloads() {
 	}
 }
 
+// validatedBlock is a block validated ahead of its commit, along with its
+// private data
+type validatedBlock struct {
+	block   *common.Block
+	pvtData util.PvtDataCollections
+	// The outcome of the validation, nil if it failed, in which case the
+	// block is validated again at its commit
+	validated *privdata.ValidatedBlock
+}
+
+// validateBlock validates the block while the previous one is committed, and
+// hands it over to the goroutine committing the blocks. It returns false if
+// the state provider is stopped meanwhile
+func (s *GossipStateProviderImpl) validateBlock(block *common.Block, pvtData util.PvtDataCollections) bool {
+	vb := &validatedBlock{block: block, pvtData: pvtData}
+	validated, err := s.pipeline.ValidateBlock(block)
+	if err != nil {
+		// the validation may fail due to the blocks which aren't committed yet
+		logger.Warningf("[%s] Failed validating block [%d] ahead of its commit, validating it again once the previous blocks are committed: %v",
+			s.chainID, block.Header.Number, err)
+	} else {
+		vb.validated = validated
+	}
+
+	select {
+	case s.validatedBlocks <- vb:
+		return true
+	case <-s.stopCh:
+		s.stopCh <- struct{}{}
+		logger.Debug("State provider has been stopped, finishing to push new blocks.")
+		return false
+	}
+}
+
+// commitValidatedBlocks commits the blocks validated ahead of their commit
+// by deliverPayloads, in turn
+func (s *GossipStateProviderImpl) commitValidatedBlocks() {
+	defer s.done.Done()
+
+	for {
+		select {
+		case vb := <-s.validatedBlocks:
+			var err error
+			if vb.validated != nil {
+				err = s.pipeline.CommitValidatedBlock(vb.validated, vb.pvtData)
+			} else {
+				err = s.ledger.StoreBlock(vb.block, vb.pvtData)
+			}
+			if err == nil {
+				s.blockCommitted(vb.block)
+				continue
+			}
+			logger.Errorf("Got error while committing(%+v)", errors.WithStack(err))
+			s.handleCommitFailure(vb.block, err)
+			return
+		case <-s.stopCh:
+			s.stopCh <- struct{}{}
+			logger.Debug("State provider has been stopped, finishing to commit validated blocks.")
+			return
+		}
+	}
+}
+
+// handleCommitFailure aborts the processing of the chain if the block can't
+// be committed due to the failure of VSCC or to a missing capability, and
+// panics otherwise
+func (s *GossipStateProviderImpl) handleCommitFailure(block *common.Block, err error) {
+	if executionErr, isExecutionErr := err.(*vsccErrors.VSCCExecutionFailureError); isExecutionErr {
+		logger.Errorf("Failed executing VSCC due to %v. Aborting chain processing", executionErr)
+		return
+	}
+	if _, isUnsupported := errors.Cause(err).(*capabilities.UnsupportedError); isUnsupported {
+		logger.Errorf("[%s] Block [%d] requires a capability which this peer doesn't support: %v. "+
+			"Aborting chain processing, upgrade the peer to resume it", s.chainID, block.Header.Number, err)
+		return
+	}
+	logger.Panicf("Cannot commit block to the ledger due to %+v", errors.WithStack(err))
+}
+
 func (s *GossipStateProviderImpl) antiEntropy() {
 	defer s.done.Done()
 	defer logger.Debug("State Provider stopped, stopping anti entropy procedure.")
@@ -639,7 +806,7 @@ func (s *GossipStateProviderImpl) requestBlocksInRange(start uint64, end uint64)
 				return
 			}
 			// Select peers to ask for blocks
-			peer, err := s.selectPeerToRequestFrom(next)
+			peer, err := s.selectPeerToRequestFrom(next, s.isDeepHistory(prev))
 			if err != nil {
 				logger.Warningf("Cannot send state request for blocks in range [%d...%d), due to %+v",
 					prev, next, errors.WithStack(err))
@@ -691,10 +858,18 @@ func (s *GossipStateProviderImpl) stateRequestMessage(beginSeq uint64, endSeq ui
 	}
 }
 
-// Select peer which has required blocks to ask missing blocks from
-func (s *GossipStateProviderImpl) selectPeerToRequestFrom(height uint64) (*comm.RemotePeer, error) {
+// Select peer which has required blocks to ask missing blocks from,
+// preferring archive peers for blocks deep in the history of the channel
+func (s *GossipStateProviderImpl) selectPeerToRequestFrom(height uint64, deepHistory bool) (*comm.RemotePeer, error) {
 	// Filter peers which posses required range of missing blocks
 	peers := s.filterPeers(s.hasRequiredHeight(height))
+	if deepHistory {
+		if archivePeers := s.filterPeers(s.isArchiveWithRequiredHeight(height)); len(archivePeers) > 0 {
+			peers = archivePeers
+		} else {
+			logger.Debug("There are no archive peers to ask for blocks deep in the history from")
+		}
+	}
 
 	n := len(peers)
 	if n == 0 {
@@ -730,6 +905,21 @@ func (s *GossipStateProviderImpl) hasRequiredHeight(height uint64) func(peer dis
 	}
 }
 
+// isArchiveWithRequiredHeight returns predicate which is capable to filter archive peers with
+// ledger height above than indicated by provided input parameter
+func (s *GossipStateProviderImpl) isArchiveWithRequiredHeight(height uint64) func(peer discovery.NetworkMember) bool {
+	hasRequiredHeight := s.hasRequiredHeight(height)
+	return func(peer discovery.NetworkMember) bool {
+		return hasRequiredHeight(peer) && peer.Properties.Archive
+	}
+}
+
+// isDeepHistory returns whether the block with the given sequence number is
+// more than deepHistoryDepth blocks behind the highest ledger height of the channel
+func (s *GossipStateProviderImpl) isDeepHistory(seqNum uint64) bool {
+	return s.maxAvailableLedgerHeight() > seqNum+atomic.LoadUint64(&s.deepHistoryDepth)
+}
+
 // AddPayload add new payload into state.
 func (s *GossipStateProviderImpl) AddPayload(payload *proto.Payload) error {
 	blockingMode := blocking
@@ -747,6 +937,9 @@ func (s *GossipStateProviderImpl) addPayload(payload *proto.Payload, blockingMod
 	if payload == nil {
 		return errors.New("Given payload is nil")
 	}
+	if s.validatedBlocksOnly && !hasValidationCodes(payload) {
+		return errors.Errorf("block [%d] was not validated, a read replica only commits the blocks validated by its committer", payload.SeqNum)
+	}
 	logger.Debugf("[%s] Adding payload to local buffer, blockNum = [%d]", s.chainID, payload.SeqNum)
 	height, err := s.ledger.LedgerHeight()
 	if err != nil {
@@ -757,14 +950,39 @@ func (s *GossipStateProviderImpl) addPayload(payload *proto.Payload, blockingMod
 		return errors.Errorf("Ledger height is at %d, cannot enqueue block with sequence of %d", height, payload.SeqNum)
 	}
 
-	for blockingMode && s.payloads.Size() > defMaxBlockDistance*2 {
-		time.Sleep(enqueueRetryInterval)
+	if blockingMode && s.payloads.Size() > s.maxPayloadBufferSize {
+		logger.Debugf("[%s] Payloads buffer holds more than %d blocks, waiting for their commit before adding block [%d]",
+			s.chainID, s.maxPayloadBufferSize, payload.SeqNum)
+		start := time.Now()
+		for s.payloads.Size() > s.maxPayloadBufferSize {
+			time.Sleep(enqueueRetryInterval)
+		}
+		s.backpressureDuration.RecordDuration(time.Since(start))
 	}
 
 	s.payloads.Push(payload)
+	s.updateBufferMetrics()
 	return nil
 }
 
+// hasValidationCodes returns whether the block of the payload carries the
+// validation codes of all its transactions
+func hasValidationCodes(payload *proto.Payload) bool {
+	block, err := putils.GetBlockFromBlockBytesNoCopy(payload.GetData())
+	if err != nil || block.Data == nil || block.Metadata == nil {
+		return false
+	}
+	if len(block.Metadata.Metadata) <= int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		return false
+	}
+	return len(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER]) == len(block.Data.Data)
+}
+
+func (s *GossipStateProviderImpl) updateBufferMetrics() {
+	s.payloadBufferSize.Update(float64(s.payloads.Size()))
+	s.blocksAwaitingValidation.Update(float64(s.payloads.Consecutive()))
+}
+
 func (s *GossipStateProviderImpl) commitBlock(block *common.Block, pvtData util.PvtDataCollections) error {
 
 	// Commit block with available private transactions
@@ -773,12 +991,15 @@ func (s *GossipStateProviderImpl) commitBlock(block *common.Block, pvtData util.
 		return err
 	}
 
+	s.blockCommitted(block)
+	return nil
+}
+
+func (s *GossipStateProviderImpl) blockCommitted(block *common.Block) {
 	// Update ledger height
 	s.mediator.UpdateLedgerHeight(block.Header.Number+1, common2.ChainID(s.chainID))
 	logger.Debugf("[%s] Committed block [%d] with %d transaction(s)",
 		s.chainID, block.Header.Number, len(block.Data.Data))
-
-	return nil
 }
 
 func min(a uint64, b uint64) uint64 {
@@ -18,6 +18,7 @@ import (
 
 	pb "github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/common/capabilities"
 	"github.com/hyperledger/fabric/common/configtx/test"
 	errors2 "github.com/hyperledger/fabric/common/errors"
 	"github.com/hyperledger/fabric/common/flogging/floggingtest"
@@ -39,6 +40,7 @@ import (
 	proto "github.com/hyperledger/fabric/protos/gossip"
 	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	transientstore2 "github.com/hyperledger/fabric/protos/transientstore"
+	perrors "github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -382,7 +384,7 @@ func newPeerNodeWithGossipWithValidator(config *gossip.Config, committer committ
 		TransientStore: &mockTransientStore{},
 		Committer:      committer,
 	}, pcomm.SignedData{})
-	sp := NewGossipStateProvider(util.GetTestChainID(), servicesAdapater, coord)
+	sp := NewGossipStateProvider(util.GetTestChainID(), servicesAdapater, coord, nil)
 	if sp == nil {
 		return nil
 	}
@@ -463,6 +465,31 @@ func TestAddPayloadLedgerUnavailable(t *testing.T) {
 	assert.Contains(t, err.Error(), "cannot query ledger")
 }
 
+func TestAddPayloadValidatedBlocksOnly(t *testing.T) {
+	t.Parallel()
+	mc := &mockCommitter{Mock: &mock.Mock{}}
+	mc.On("LedgerHeight", mock.Anything).Return(uint64(1), nil)
+	g := &mocks.GossipMock{}
+	g.On("Accept", mock.Anything, false).Return(make(<-chan *proto.GossipMessage), nil)
+	g.On("Accept", mock.Anything, true).Return(nil, make(chan proto.ReceivedMessage))
+	portPrefix := portStartRange + 175
+	p := newPeerNodeWithGossip(newGossipConfig(portPrefix, 0), mc, noopPeerIdentityAcceptor, g)
+	defer p.shutdown()
+	p.s.validatedBlocksOnly = true
+
+	// the blocks of the ordering service don't carry validation codes
+	rawblock := pcomm.NewBlock(uint64(1), []byte{})
+	rawblock.Data.Data = [][]byte{{1}, {2}}
+	b, _ := pb.Marshal(rawblock)
+	err := p.s.AddPayload(&proto.Payload{SeqNum: uint64(1), Data: b})
+	assert.EqualError(t, err, "block [1] was not validated, a read replica only commits the blocks validated by its committer")
+
+	assert.False(t, hasValidationCodes(&proto.Payload{SeqNum: uint64(1), Data: b}))
+	rawblock.Metadata.Metadata[pcomm.BlockMetadataIndex_TRANSACTIONS_FILTER] = []byte{0, 0}
+	b, _ = pb.Marshal(rawblock)
+	assert.True(t, hasValidationCodes(&proto.Payload{SeqNum: uint64(1), Data: b}))
+}
+
 func TestLargeBlockGap(t *testing.T) {
 	// Scenario: the peer knows of a peer who has a ledger height much higher
 	// than itself (500 blocks higher).
@@ -669,7 +696,205 @@ func TestBlockingEnqueue(t *testing.T) {
 	}
 }
 
+type gaugeRecorder struct {
+	lock  sync.Mutex
+	value float64
+}
+
+func (g *gaugeRecorder) Update(value float64) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.value = value
+}
+
+func (g *gaugeRecorder) get() float64 {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	return g.value
+}
+
+type histogramRecorder struct {
+	lock      sync.Mutex
+	durations []time.Duration
+}
+
+func (h *histogramRecorder) RecordDuration(value time.Duration) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.durations = append(h.durations, value)
+}
+
+func (h *histogramRecorder) count() int {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return len(h.durations)
+}
+
+func TestPayloadsBufferBackpressure(t *testing.T) {
+	// Scenario: the blocks from the orderer wait while the payloads buffer
+	// holds more blocks than its limit, the blocks from gossip don't
+	t.Parallel()
+	ledger := &coordinatorMock{}
+	ledger.On("LedgerHeight").Return(uint64(1), nil)
+	bufferSize, awaitingValidation := &gaugeRecorder{}, &gaugeRecorder{}
+	backpressure := &histogramRecorder{}
+	s := &GossipStateProviderImpl{
+		chainID:                  util.GetTestChainID(),
+		payloads:                 NewPayloadsBuffer(1),
+		ledger:                   ledger,
+		maxPayloadBufferSize:     2,
+		payloadBufferSize:        bufferSize,
+		blocksAwaitingValidation: awaitingValidation,
+		backpressureDuration:     backpressure,
+	}
+	payload := func(seqNum uint64) *proto.Payload {
+		return &proto.Payload{SeqNum: seqNum, Data: []byte{}}
+	}
+
+	// block 1 is missing, so none of the blocks can be committed
+	for _, seqNum := range []uint64{2, 3, 4} {
+		assert.NoError(t, s.addPayload(payload(seqNum), blocking))
+	}
+	assert.Equal(t, float64(3), bufferSize.get())
+	assert.Equal(t, float64(0), awaitingValidation.get())
+
+	added := make(chan error, 1)
+	go func() {
+		added <- s.addPayload(payload(5), blocking)
+	}()
+	select {
+	case <-added:
+		t.Fatal("block 5 was added to a full payloads buffer")
+	case <-time.After(3 * enqueueRetryInterval):
+	}
+	assert.Equal(t, 0, backpressure.count())
+
+	assert.NoError(t, s.addPayload(payload(1), nonBlocking))
+	assert.Equal(t, float64(4), bufferSize.get())
+	assert.Equal(t, float64(4), awaitingValidation.get())
+
+	// the commit of blocks 1 and 2 releases block 5
+	s.payloads.Pop()
+	s.payloads.Pop()
+	select {
+	case err := <-added:
+		assert.NoError(t, err)
+	case <-time.After(10 * enqueueRetryInterval):
+		t.Fatal("block 5 wasn't added after the commit of the buffered blocks")
+	}
+	assert.Equal(t, 1, backpressure.count())
+	assert.Equal(t, float64(3), bufferSize.get())
+	assert.Equal(t, float64(3), awaitingValidation.get())
+}
+
+// pipelinedCoordinatorMock records the validation and the commit of the
+// blocks, whose commits wait until the test releases them
+type pipelinedCoordinatorMock struct {
+	coordinatorMock
+	events  chan string
+	release chan struct{}
+	// blocks whose validation fails ahead of their commit
+	invalid map[uint64]bool
+}
+
+func (m *pipelinedCoordinatorMock) ValidateBlock(block *pcomm.Block) (*privdata.ValidatedBlock, error) {
+	m.events <- fmt.Sprintf("validate %d", block.Header.Number)
+	if m.invalid[block.Header.Number] {
+		return nil, errors.New("validation failed")
+	}
+	return &privdata.ValidatedBlock{Block: block}, nil
+}
+
+func (m *pipelinedCoordinatorMock) CommitValidatedBlock(validated *privdata.ValidatedBlock, _ gutil.PvtDataCollections) error {
+	m.events <- fmt.Sprintf("commit %d", validated.Block.Header.Number)
+	<-m.release
+	return nil
+}
+
+func (m *pipelinedCoordinatorMock) StoreBlock(block *pcomm.Block, _ gutil.PvtDataCollections) error {
+	m.events <- fmt.Sprintf("store %d", block.Header.Number)
+	<-m.release
+	return nil
+}
+
+func TestCommitPipelining(t *testing.T) {
+	// Scenario: the next block is validated while the current one is
+	// committed, the block whose validation fails ahead of its commit is
+	// validated and committed in turn
+	t.Parallel()
+	ledger := &pipelinedCoordinatorMock{
+		events:  make(chan string, 10),
+		release: make(chan struct{}),
+		invalid: map[uint64]bool{2: true},
+	}
+	ledger.On("LedgerHeight").Return(uint64(1), nil)
+	s := &GossipStateProviderImpl{
+		chainID:                  util.GetTestChainID(),
+		mediator:                 &ServicesMediator{GossipAdapter: &mocks.GossipMock{}},
+		payloads:                 NewPayloadsBuffer(1),
+		ledger:                   ledger,
+		pipeline:                 ledger,
+		validatedBlocks:          make(chan *validatedBlock),
+		stopCh:                   make(chan struct{}, 1),
+		maxPayloadBufferSize:     defMaxPayloadBufferSize,
+		payloadBufferSize:        &gaugeRecorder{},
+		blocksAwaitingValidation: &gaugeRecorder{},
+		backpressureDuration:     &histogramRecorder{},
+	}
+	s.done.Add(2)
+	go s.deliverPayloads()
+	go s.commitValidatedBlocks()
+
+	for seqNum := uint64(1); seqNum <= 3; seqNum++ {
+		b, err := pb.Marshal(pcomm.NewBlock(seqNum, []byte{}))
+		assert.NoError(t, err)
+		assert.NoError(t, s.addPayload(&proto.Payload{SeqNum: seqNum, Data: b}, nonBlocking))
+	}
+
+	nextEvents := func(n int) []string {
+		var events []string
+		for i := 0; i < n; i++ {
+			select {
+			case event := <-ledger.events:
+				events = append(events, event)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("expected %d events, got %v", n, events)
+			}
+		}
+		// no other block is processed until the commit is released
+		select {
+		case event := <-ledger.events:
+			t.Fatalf("unexpected event %s after %v", event, events)
+		case <-time.After(100 * time.Millisecond):
+		}
+		return events
+	}
+
+	// block 2 is validated while block 1 is committed
+	assert.ElementsMatch(t, []string{"validate 1", "commit 1", "validate 2"}, nextEvents(3))
+	ledger.release <- struct{}{}
+	// block 2 failed its validation, it is validated again at its commit
+	assert.ElementsMatch(t, []string{"store 2", "validate 3"}, nextEvents(2))
+	ledger.release <- struct{}{}
+	assert.Equal(t, []string{"commit 3"}, nextEvents(1))
+	ledger.release <- struct{}{}
+
+	s.stopCh <- struct{}{}
+	s.done.Wait()
+}
+
 func TestHaltChainProcessing(t *testing.T) {
+	testHaltChainProcessing(t, &errors2.VSCCExecutionFailureError{
+		Err: errors.New("foobar"),
+	}, portStartRange+350, "Aborting chain processing", "foobar")
+}
+
+func TestHaltChainProcessingOnUnsupportedCapability(t *testing.T) {
+	err := perrors.WithMessage(&capabilities.UnsupportedError{Type: "Application", Capability: "V9_9"}, "[channel testchainid] incompatible")
+	testHaltChainProcessing(t, err, portStartRange+550, "Aborting chain processing", "Application capability V9_9 is required but not supported")
+}
+
+func testHaltChainProcessing(t *testing.T, validationErr error, portPrefix int, expectedLogs ...string) {
 	gossipChannel := func(c chan *proto.GossipMessage) <-chan *proto.GossipMessage {
 		return c
 	}
@@ -720,15 +945,13 @@ func TestHaltChainProcessing(t *testing.T) {
 	g.On("PeersOfChannel", mock.Anything).Return([]discovery.NetworkMember{})
 
 	v := &validator.MockValidator{}
-	v.On("Validate").Return(&errors2.VSCCExecutionFailureError{
-		Err: errors.New("foobar"),
-	}).Once()
-	portPrefix := portStartRange + 350
+	v.On("Validate").Return(validationErr).Once()
 	newPeerNodeWithGossipWithValidator(newGossipConfig(portPrefix, 0), mc, noopPeerIdentityAcceptor, g, v)
 	gossipMsgs <- newBlockMsg(1)
 	assertLogged(t, recorder, "Got error while committing")
-	assertLogged(t, recorder, "Aborting chain processing")
-	assertLogged(t, recorder, "foobar")
+	for _, expectedLog := range expectedLogs {
+		assertLogged(t, recorder, expectedLog)
+	}
 }
 
 func TestFailures(t *testing.T) {
@@ -896,6 +1119,52 @@ func TestLedgerHeightFromProperties(t *testing.T) {
 	wg.Wait()
 }
 
+func TestDeepHistoryFromArchivePeers(t *testing.T) {
+	t.Parallel()
+	member := func(id string, height uint64, archive bool) discovery.NetworkMember {
+		return discovery.NetworkMember{
+			PKIid:            common.PKIidType(id),
+			InternalEndpoint: id,
+			Properties: &proto.Properties{
+				LedgerHeight: height,
+				Archive:      archive,
+			},
+		}
+	}
+
+	g := &mocks.GossipMock{}
+	s := &GossipStateProviderImpl{
+		chainID:          "testchainid",
+		mediator:         &ServicesMediator{GossipAdapter: g},
+		deepHistoryDepth: 100,
+	}
+
+	g.On("PeersOfChannel", mock.Anything).Return([]discovery.NetworkMember{
+		member("regular", 500, false),
+		member("archive", 500, true),
+		member("laggingArchive", 50, true),
+	})
+	assert.True(t, s.isDeepHistory(10))
+	assert.False(t, s.isDeepHistory(400))
+
+	for i := 0; i < 10; i++ {
+		peer, err := s.selectPeerToRequestFrom(100, true)
+		assert.NoError(t, err)
+		assert.Equal(t, "archive", peer.Endpoint)
+	}
+
+	// without any archive peer of the required height, any peer is selected
+	g = &mocks.GossipMock{}
+	s.mediator = &ServicesMediator{GossipAdapter: g}
+	g.On("PeersOfChannel", mock.Anything).Return([]discovery.NetworkMember{
+		member("regular", 500, false),
+		member("laggingArchive", 50, true),
+	})
+	peer, err := s.selectPeerToRequestFrom(100, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "regular", peer.Endpoint)
+}
+
 func TestAccessControl(t *testing.T) {
 	t.Parallel()
 	bootstrapSetSize := 5
@@ -1385,7 +1654,7 @@ func TestTransferOfPrivateRWSet(t *testing.T) {
 	coord1.On("Close")
 
 	servicesAdapater := &ServicesMediator{GossipAdapter: g, MCSAdapter: &cryptoServiceMock{acceptor: noopPeerIdentityAcceptor}}
-	st := NewGossipStateProvider(chainID, servicesAdapater, coord1)
+	st := NewGossipStateProvider(chainID, servicesAdapater, coord1, nil)
 	defer st.Stop()
 
 	// Mocked state request message
@@ -1617,11 +1886,11 @@ func TestTransferOfPvtDataBetweenPeers(t *testing.T) {
 	cryptoService := &cryptoServiceMock{acceptor: noopPeerIdentityAcceptor}
 
 	mediator := &ServicesMediator{GossipAdapter: peers["peer1"], MCSAdapter: cryptoService}
-	peer1State := NewGossipStateProvider(chainID, mediator, peers["peer1"].coord)
+	peer1State := NewGossipStateProvider(chainID, mediator, peers["peer1"].coord, nil)
 	defer peer1State.Stop()
 
 	mediator = &ServicesMediator{GossipAdapter: peers["peer2"], MCSAdapter: cryptoService}
-	peer2State := NewGossipStateProvider(chainID, mediator, peers["peer2"].coord)
+	peer2State := NewGossipStateProvider(chainID, mediator, peers["peer2"].coord, nil)
 	defer peer2State.Stop()
 
 	// Make sure state was replicated
@@ -17,6 +17,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hyperledger/fabric/core/config/reload"
 	"github.com/spf13/viper"
 )
 
@@ -150,7 +151,7 @@ func GetIntOrDefault(key string, defVal int) int {
 	viperLock.RLock()
 	defer viperLock.RUnlock()
 
-	if val := viper.GetInt(key); val != 0 {
+	if val := reload.GetInt(key); val != 0 {
 		return val
 	}
 
@@ -162,7 +163,7 @@ func GetFloat64OrDefault(key string, defVal float64) float64 {
 	viperLock.RLock()
 	defer viperLock.RUnlock()
 
-	if val := viper.GetFloat64(key); val != 0 {
+	if val := reload.GetFloat64(key); val != 0 {
 		return val
 	}
 
@@ -174,7 +175,7 @@ func GetDurationOrDefault(key string, defVal time.Duration) time.Duration {
 	viperLock.RLock()
 	defer viperLock.RUnlock()
 
-	if val := viper.GetDuration(key); val != 0 {
+	if val := reload.GetDuration(key); val != 0 {
 		return val
 	}
 
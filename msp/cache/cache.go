@@ -7,6 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package cache
 
 import (
+	"crypto/sha256"
+	"sync"
+
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/msp"
 	pmsp "github.com/hyperledger/fabric/protos/msp"
@@ -17,6 +20,7 @@ const (
 	deserializeIdentityCacheSize = 100
 	validateIdentityCacheSize    = 100
 	satisfiesPrincipalCacheSize  = 100
+	verifySignatureCacheSize     = 1000
 )
 
 var mspLogger = flogging.MustGetLogger("msp")
@@ -31,6 +35,7 @@ func New(o msp.MSP) (msp.MSP, error) {
 	theMsp.deserializeIdentityCache = newSecondChanceCache(deserializeIdentityCacheSize)
 	theMsp.satisfiesPrincipalCache = newSecondChanceCache(satisfiesPrincipalCacheSize)
 	theMsp.validateIdentityCache = newSecondChanceCache(validateIdentityCacheSize)
+	theMsp.verifySignatureCache = newSecondChanceCache(verifySignatureCacheSize)
 
 	return theMsp, nil
 }
@@ -38,6 +43,11 @@ func New(o msp.MSP) (msp.MSP, error) {
 type cachedMSP struct {
 	msp.MSP
 
+	// lock guards the caches, which are replaced when the CRLs are updated
+	// while identities are validated, so that an identity validated against
+	// the previous CRLs doesn't end up in the new caches
+	lock sync.RWMutex
+
 	// cache for DeserializeIdentity.
 	deserializeIdentityCache *secondChanceCache
 
@@ -47,6 +57,10 @@ type cachedMSP struct {
 	// basically a map of principals=>identities=>stringified to booleans
 	// specifying whether this identity satisfies this principal
 	satisfiesPrincipalCache *secondChanceCache
+
+	// set of the identities, message digests and signatures
+	// which were successfully verified
+	verifySignatureCache *secondChanceCache
 }
 
 type cachedIdentity struct {
@@ -62,7 +76,14 @@ func (id *cachedIdentity) Validate() error {
 	return id.cache.Validate(id.Identity)
 }
 
+func (id *cachedIdentity) Verify(msg []byte, sig []byte) error {
+	return id.cache.verify(id.Identity, msg, sig)
+}
+
 func (c *cachedMSP) DeserializeIdentity(serializedIdentity []byte) (msp.Identity, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
 	id, ok := c.deserializeIdentityCache.get(string(serializedIdentity))
 	if ok {
 		return &cachedIdentity{
@@ -83,12 +104,43 @@ func (c *cachedMSP) DeserializeIdentity(serializedIdentity []byte) (msp.Identity
 }
 
 func (c *cachedMSP) Setup(config *pmsp.MSPConfig) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
 	c.cleanCash()
 
 	return c.MSP.Setup(config)
 }
 
+// ValidateRevocationList checks the CRL against the cached MSP
+func (c *cachedMSP) ValidateRevocationList(crl []byte) error {
+	updater, ok := c.MSP.(msp.RevocationListUpdater)
+	if !ok {
+		return errors.Errorf("MSP of type %s does not support CRL updates", msp.ProviderTypeToString(c.MSP.GetType()))
+	}
+	return updater.ValidateRevocationList(crl)
+}
+
+// UpdateRevocationLists updates the CRLs of the cached MSP, and clears the
+// caches since identities validated before may have been revoked
+func (c *cachedMSP) UpdateRevocationLists(crls [][]byte) error {
+	updater, ok := c.MSP.(msp.RevocationListUpdater)
+	if !ok {
+		return errors.Errorf("MSP of type %s does not support CRL updates", msp.ProviderTypeToString(c.MSP.GetType()))
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := updater.UpdateRevocationLists(crls); err != nil {
+		return err
+	}
+	c.cleanCash()
+	return nil
+}
+
 func (c *cachedMSP) Validate(id msp.Identity) error {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
 	identifier := id.GetIdentifier()
 	key := string(identifier.Mspid + ":" + identifier.Id)
 
@@ -107,6 +159,9 @@ func (c *cachedMSP) Validate(id msp.Identity) error {
 }
 
 func (c *cachedMSP) SatisfiesPrincipal(id msp.Identity, principal *pmsp.MSPPrincipal) error {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
 	identifier := id.GetIdentifier()
 	identityKey := string(identifier.Mspid + ":" + identifier.Id)
 	principalKey := string(principal.PrincipalClassification) + string(principal.Principal)
@@ -127,10 +182,33 @@ func (c *cachedMSP) SatisfiesPrincipal(id msp.Identity, principal *pmsp.MSPPrinc
 	return err
 }
 
+func (c *cachedMSP) verify(id msp.Identity, msg []byte, sig []byte) error {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	identifier := id.GetIdentifier()
+	digest := sha256.Sum256(msg)
+	key := identifier.Mspid + ":" + identifier.Id + string(digest[:]) + string(sig)
+
+	_, ok := c.verifySignatureCache.get(key)
+	if ok {
+		// cache only stores the valid signatures.
+		return nil
+	}
+
+	err := id.Verify(msg, sig)
+	if err == nil {
+		c.verifySignatureCache.add(key, true)
+	}
+
+	return err
+}
+
 func (c *cachedMSP) cleanCash() error {
 	c.deserializeIdentityCache = newSecondChanceCache(deserializeIdentityCacheSize)
 	c.satisfiesPrincipalCache = newSecondChanceCache(satisfiesPrincipalCacheSize)
 	c.validateIdentityCache = newSecondChanceCache(validateIdentityCacheSize)
+	c.verifySignatureCache = newSecondChanceCache(verifySignatureCacheSize)
 
 	return nil
 }
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package cache
 
 import (
+	"fmt"
 	"sync"
 	"testing"
 
@@ -321,3 +322,127 @@ func TestSatisfiesPrincipal(t *testing.T) {
 	assert.NotNil(t, v)
 	assert.Contains(t, "Invalid", v.(error).Error())
 }
+
+func TestVerify(t *testing.T) {
+	mockMSP := &mocks.MockMSP{}
+	cache, err := New(mockMSP)
+	assert.NoError(t, err)
+
+	mockIdentity := &mocks.MockIdentity{ID: "Alice"}
+	mockIdentity.On("GetIdentifier").Return(&msp.IdentityIdentifier{Mspid: "MSP", Id: "Alice"})
+	mockIdentity.On("Verify", []byte("msg"), []byte("sig")).Return(nil)
+	mockIdentity.On("Verify", []byte("msg"), []byte("bad sig")).Return(errors.New("invalid signature"))
+	mockMSP.On("DeserializeIdentity", []byte{1, 2, 3}).Return(mockIdentity, nil)
+
+	identity, err := cache.DeserializeIdentity([]byte{1, 2, 3})
+	assert.NoError(t, err)
+
+	// Ensure the valid signatures are verified once
+	assert.NoError(t, identity.Verify([]byte("msg"), []byte("sig")))
+	assert.NoError(t, identity.Verify([]byte("msg"), []byte("sig")))
+	mockIdentity.AssertNumberOfCalls(t, "Verify", 1)
+
+	// Ensure the invalid signatures are verified each time
+	assert.EqualError(t, identity.Verify([]byte("msg"), []byte("bad sig")), "invalid signature")
+	assert.EqualError(t, identity.Verify([]byte("msg"), []byte("bad sig")), "invalid signature")
+	mockIdentity.AssertNumberOfCalls(t, "Verify", 3)
+
+	// Ensure the verified signatures are forgotten on setup
+	mockMSP.On("Setup", (*msp2.MSPConfig)(nil)).Return(nil)
+	assert.NoError(t, cache.Setup(nil))
+	assert.NoError(t, identity.Verify([]byte("msg"), []byte("sig")))
+	mockIdentity.AssertNumberOfCalls(t, "Verify", 4)
+}
+
+// mockUpdatableMSP is a MockMSP whose CRLs can be updated
+type mockUpdatableMSP struct {
+	*mocks.MockMSP
+}
+
+func (m *mockUpdatableMSP) ValidateRevocationList(crl []byte) error {
+	return m.Called(crl).Error(0)
+}
+
+func (m *mockUpdatableMSP) UpdateRevocationLists(crls [][]byte) error {
+	return m.Called(crls).Error(0)
+}
+
+func TestUpdateRevocationLists(t *testing.T) {
+	mockMSP := &mocks.MockMSP{}
+	mockMSP.On("GetType").Return(msp.IDEMIX)
+	cache, err := New(mockMSP)
+	assert.NoError(t, err)
+	updater := cache.(msp.RevocationListUpdater)
+	assert.EqualError(t, updater.ValidateRevocationList([]byte("crl")), "MSP of type idemix does not support CRL updates")
+	assert.EqualError(t, updater.UpdateRevocationLists([][]byte{[]byte("crl")}), "MSP of type idemix does not support CRL updates")
+
+	updatableMSP := &mockUpdatableMSP{MockMSP: &mocks.MockMSP{}}
+	updatableMSP.On("ValidateRevocationList", []byte("crl")).Return(nil)
+	updatableMSP.On("ValidateRevocationList", []byte("bad crl")).Return(errors.New("bad CRL"))
+	updatableMSP.On("UpdateRevocationLists", [][]byte{[]byte("crl")}).Return(nil)
+	updatableMSP.On("UpdateRevocationLists", [][]byte{[]byte("bad crl")}).Return(errors.New("bad CRL"))
+	cache, err = New(updatableMSP)
+	assert.NoError(t, err)
+	updater = cache.(msp.RevocationListUpdater)
+	assert.NoError(t, updater.ValidateRevocationList([]byte("crl")))
+	assert.EqualError(t, updater.ValidateRevocationList([]byte("bad crl")), "bad CRL")
+
+	mockIdentity := &mocks.MockIdentity{ID: "Alice"}
+	mockIdentity.On("GetIdentifier").Return(&msp.IdentityIdentifier{Mspid: "MSP", Id: "Alice"})
+	updatableMSP.On("Validate", mockIdentity).Return(nil)
+	assert.NoError(t, cache.Validate(mockIdentity))
+	assert.Equal(t, 1, cache.(*cachedMSP).validateIdentityCache.len())
+
+	// a failed update keeps the cached validations
+	assert.EqualError(t, updater.UpdateRevocationLists([][]byte{[]byte("bad crl")}), "bad CRL")
+	assert.Equal(t, 1, cache.(*cachedMSP).validateIdentityCache.len())
+
+	// the identities validated before an update are validated again
+	assert.NoError(t, updater.UpdateRevocationLists([][]byte{[]byte("crl")}))
+	assert.Equal(t, 0, cache.(*cachedMSP).validateIdentityCache.len())
+	assert.NoError(t, cache.Validate(mockIdentity))
+	updatableMSP.AssertNumberOfCalls(t, "Validate", 2)
+}
+
+// fakeIdentity is an identity which can be used from many goroutines, unlike
+// the MockIdentity whose recorded calls are read by the mocks it is passed to
+type fakeIdentity struct {
+	msp.Identity
+	id string
+}
+
+func (f *fakeIdentity) GetIdentifier() *msp.IdentityIdentifier {
+	return &msp.IdentityIdentifier{Mspid: "MSP", Id: f.id}
+}
+
+func (f *fakeIdentity) Verify(msg []byte, sig []byte) error {
+	return nil
+}
+
+func TestUpdateRevocationListsConcurrently(t *testing.T) {
+	updatableMSP := &mockUpdatableMSP{MockMSP: &mocks.MockMSP{}}
+	updatableMSP.On("UpdateRevocationLists", [][]byte{[]byte("crl")}).Return(nil)
+	updatableMSP.On("Validate", mock.Anything).Return(nil)
+	updatableMSP.On("SatisfiesPrincipal", mock.Anything, mock.Anything).Return(nil)
+	cache, err := New(updatableMSP)
+	assert.NoError(t, err)
+
+	// the transactions of a block are validated while the CRLs of the
+	// previous one are committed
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		identity := &fakeIdentity{id: fmt.Sprintf("user%d", i)}
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, cache.Validate(identity))
+			assert.NoError(t, cache.SatisfiesPrincipal(identity, &msp2.MSPPrincipal{}))
+			assert.NoError(t, cache.(*cachedMSP).verify(identity, []byte("msg"), []byte("sig")))
+		}()
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, cache.(msp.RevocationListUpdater).UpdateRevocationLists([][]byte{[]byte("crl")}))
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,56 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cache
+
+import (
+	"github.com/hyperledger/fabric/msp"
+	"github.com/pkg/errors"
+)
+
+const managerDeserializeIdentityCacheSize = 1000
+
+// NewManager returns an MSPManager caching the identities deserialized by
+// the passed one, keyed by their serialized form as found in the creator
+// field of the transactions and the messages
+func NewManager(mgr msp.MSPManager) (msp.MSPManager, error) {
+	mspLogger.Debugf("Creating Cache-MSPManager instance")
+	if mgr == nil {
+		return nil, errors.Errorf("Invalid passed MSPManager. It must be different from nil.")
+	}
+
+	return &cachedMSPManager{
+		MSPManager:               mgr,
+		deserializeIdentityCache: newSecondChanceCache(managerDeserializeIdentityCacheSize),
+	}, nil
+}
+
+type cachedMSPManager struct {
+	msp.MSPManager
+
+	// cache for DeserializeIdentity, across the MSPs of the manager
+	deserializeIdentityCache *secondChanceCache
+}
+
+func (c *cachedMSPManager) DeserializeIdentity(serializedIdentity []byte) (msp.Identity, error) {
+	id, ok := c.deserializeIdentityCache.get(string(serializedIdentity))
+	if ok {
+		return id.(msp.Identity), nil
+	}
+
+	id, err := c.MSPManager.DeserializeIdentity(serializedIdentity)
+	if err != nil {
+		return nil, err
+	}
+	c.deserializeIdentityCache.add(string(serializedIdentity), id)
+	return id.(msp.Identity), nil
+}
+
+func (c *cachedMSPManager) Setup(msps []msp.MSP) error {
+	c.deserializeIdentityCache = newSecondChanceCache(managerDeserializeIdentityCacheSize)
+
+	return c.MSPManager.Setup(msps)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cache
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/msp/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCacheMSPManager(t *testing.T) {
+	i, err := NewManager(&mocks.MockMSPManager{})
+	assert.NoError(t, err)
+	assert.NotNil(t, i)
+	_, ok := i.(*cachedMSPManager)
+	assert.True(t, ok)
+
+	_, err = NewManager(nil)
+	assert.EqualError(t, err, "Invalid passed MSPManager. It must be different from nil.")
+}
+
+func TestManagerDeserializeIdentity(t *testing.T) {
+	mockMgr := &mocks.MockMSPManager{}
+	cache, err := NewManager(mockMgr)
+	assert.NoError(t, err)
+
+	mockIdentity := &mocks.MockIdentity{ID: "Alice"}
+	mockMgr.On("DeserializeIdentity", []byte{1, 2, 3}).Return(mockIdentity, nil)
+	mockMgr.On("DeserializeIdentity", []byte{4, 5, 6}).Return(mockIdentity, errors.New("Invalid identity"))
+
+	// Check the id is cached
+	for i := 0; i < 2; i++ {
+		id, err := cache.DeserializeIdentity([]byte{1, 2, 3})
+		assert.NoError(t, err)
+		assert.True(t, mockIdentity == id)
+	}
+	mockMgr.AssertNumberOfCalls(t, "DeserializeIdentity", 1)
+
+	// Check the failures are not cached
+	for i := 0; i < 2; i++ {
+		_, err := cache.DeserializeIdentity([]byte{4, 5, 6})
+		assert.EqualError(t, err, "Invalid identity")
+	}
+	mockMgr.AssertNumberOfCalls(t, "DeserializeIdentity", 3)
+
+	// Check the cache is reset on setup
+	mockMgr.On("Setup", []msp.MSP(nil)).Return(nil)
+	assert.NoError(t, cache.Setup(nil))
+	_, ok := cache.(*cachedMSPManager).deserializeIdentityCache.get(string([]byte{1, 2, 3}))
+	assert.False(t, ok)
+	_, err = cache.DeserializeIdentity([]byte{1, 2, 3})
+	assert.NoError(t, err)
+	mockMgr.AssertNumberOfCalls(t, "DeserializeIdentity", 4)
+}
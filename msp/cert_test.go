@@ -97,6 +97,7 @@ func TestSanitizeCert(t *testing.T) {
 
 func TestCertExpiration(t *testing.T) {
 	msp := &bccspmsp{}
+	msp.internalSelectValidationChainFunc = msp.selectUniqueValidationChain
 	msp.opts = &x509.VerifyOptions{}
 	msp.opts.DNSName = "test.example.com"
 
@@ -0,0 +1,243 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	m "github.com/hyperledger/fabric/protos/msp"
+	"github.com/stretchr/testify/assert"
+)
+
+// crossCertCA is a CA key with the certificates issued for it
+type crossCertCA struct {
+	name string
+	key  *ecdsa.PrivateKey
+	cert *x509.Certificate
+}
+
+func newCrossCertKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	return key
+}
+
+// newCrossCertTemplate returns the template of a certificate of the name; a
+// negative maxPathLen leaves the path length of a CA unconstrained
+func newCrossCertTemplate(t *testing.T, name string, key *ecdsa.PrivateKey, isCA bool, maxPathLen int) *x509.Certificate {
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	assert.NoError(t, err)
+	ski := sha256.Sum256(elliptic.Marshal(key.Curve, key.X, key.Y))
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: name, Organization: []string{"org1.example.com"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		SubjectKeyId:          ski[:],
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+	}
+	if isCA {
+		template.NotBefore = template.NotBefore.Add(-time.Hour)
+		template.IsCA = true
+		template.KeyUsage |= x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+		template.MaxPathLen = maxPathLen
+		template.MaxPathLenZero = maxPathLen == 0
+	}
+	return template
+}
+
+func newRootCA(t *testing.T, name string, maxPathLen int) *crossCertCA {
+	key := newCrossCertKey(t)
+	template := newCrossCertTemplate(t, name, key, true, maxPathLen)
+	return &crossCertCA{name: name, key: key, cert: createCrossCert(t, template, template, key, key)}
+}
+
+// issueCA issues a certificate of a new intermediate CA
+func (ca *crossCertCA) issueCA(t *testing.T, name string, maxPathLen int) *crossCertCA {
+	key := newCrossCertKey(t)
+	template := newCrossCertTemplate(t, name, key, true, maxPathLen)
+	return &crossCertCA{name: name, key: key, cert: createCrossCert(t, template, ca.cert, key, ca.key)}
+}
+
+// crossSign issues a certificate for the key and the name of the other CA
+func (ca *crossCertCA) crossSign(t *testing.T, other *crossCertCA, maxPathLen int) *x509.Certificate {
+	template := newCrossCertTemplate(t, other.name, other.key, true, maxPathLen)
+	return createCrossCert(t, template, ca.cert, other.key, ca.key)
+}
+
+func (ca *crossCertCA) issueIdentity(t *testing.T, name string) *x509.Certificate {
+	key := newCrossCertKey(t)
+	return createCrossCert(t, newCrossCertTemplate(t, name, key, false, -1), ca.cert, key, ca.key)
+}
+
+func createCrossCert(t *testing.T, template, parent *x509.Certificate, key, parentKey *ecdsa.PrivateKey) *x509.Certificate {
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return cert
+}
+
+func pemEncodeCerts(certs ...*x509.Certificate) [][]byte {
+	var pems [][]byte
+	for _, cert := range certs {
+		pems = append(pems, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	}
+	return pems
+}
+
+func setupCrossCertMSP(version MSPVersion, roots, intermediates []*x509.Certificate) (*bccspmsp, error) {
+	theMsp, err := newBccspMsp(version)
+	if err != nil {
+		return nil, err
+	}
+	fabricConf, err := proto.Marshal(&m.FabricMSPConfig{
+		Name:              "SampleOrg",
+		RootCerts:         pemEncodeCerts(roots...),
+		IntermediateCerts: pemEncodeCerts(intermediates...),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := theMsp.Setup(&m.MSPConfig{Type: int32(FABRIC), Config: fabricConf}); err != nil {
+		return nil, err
+	}
+	return theMsp.(*bccspmsp), nil
+}
+
+// crossCertValidationChain validates the certificate with the MSP and
+// returns its validation chain
+func crossCertValidationChain(theMsp *bccspmsp, cert *x509.Certificate) ([]*x509.Certificate, error) {
+	serialized, err := proto.Marshal(&m.SerializedIdentity{Mspid: "SampleOrg", IdBytes: pemEncodeCerts(cert)[0]})
+	if err != nil {
+		return nil, err
+	}
+	id, err := theMsp.DeserializeIdentity(serialized)
+	if err != nil {
+		return nil, err
+	}
+	if err := theMsp.Validate(id); err != nil {
+		return nil, err
+	}
+	return theMsp.getCertificationChain(id)
+}
+
+func TestCrossCertifiedIntermediateCA(t *testing.T) {
+	root1 := newRootCA(t, "root1", -1)
+	root2 := newRootCA(t, "root2", -1)
+	ica := root1.issueCA(t, "ica", -1)
+	crossCert := root2.crossSign(t, ica, -1)
+	leaf := ica.issueIdentity(t, "leaf")
+
+	// the validation chain of the identity has to be unique before V1_4_2
+	theMsp, err := setupCrossCertMSP(MSPv1_3, []*x509.Certificate{root1.cert, root2.cert}, []*x509.Certificate{ica.cert, crossCert})
+	assert.NoError(t, err)
+	_, err = crossCertValidationChain(theMsp, leaf)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "this MSP only supports a single validation chain, got 2")
+
+	// the validation chain ending with the root CA listed first is selected
+	theMsp, err = setupCrossCertMSP(MSPv1_4_2, []*x509.Certificate{root1.cert, root2.cert}, []*x509.Certificate{ica.cert, crossCert})
+	assert.NoError(t, err)
+	chain, err := crossCertValidationChain(theMsp, leaf)
+	assert.NoError(t, err)
+	assert.Len(t, chain, 3)
+	assert.Equal(t, "root1", chain[2].Subject.CommonName)
+
+	theMsp, err = setupCrossCertMSP(MSPv1_4_2, []*x509.Certificate{root2.cert, root1.cert}, []*x509.Certificate{crossCert, ica.cert})
+	assert.NoError(t, err)
+	chain, err = crossCertValidationChain(theMsp, leaf)
+	assert.NoError(t, err)
+	assert.Len(t, chain, 3)
+	assert.Equal(t, "root2", chain[2].Subject.CommonName)
+
+	// the selection does not depend on the order of the intermediate CAs,
+	// so every identity of the intermediate CA has the same certifiers identifier
+	msp1, err := setupCrossCertMSP(MSPv1_4_2, []*x509.Certificate{root1.cert, root2.cert}, []*x509.Certificate{ica.cert, crossCert})
+	assert.NoError(t, err)
+	msp2, err := setupCrossCertMSP(MSPv1_4_2, []*x509.Certificate{root1.cert, root2.cert}, []*x509.Certificate{crossCert, ica.cert})
+	assert.NoError(t, err)
+	chain1, err := crossCertValidationChain(msp1, leaf)
+	assert.NoError(t, err)
+	chain2, err := crossCertValidationChain(msp2, leaf)
+	assert.NoError(t, err)
+	assert.Equal(t, chain1, chain2)
+
+	// both root CAs are internal nodes of the certification tree, so they
+	// can't issue identities directly
+	_, err = crossCertValidationChain(theMsp, root2.issueIdentity(t, "leaf2"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Parent certificate should be a leaf of the certification tree")
+}
+
+func TestCrossCertifiedRootCA(t *testing.T) {
+	root1 := newRootCA(t, "root1", -1)
+	root2 := newRootCA(t, "root2", -1)
+	crossCert := root1.crossSign(t, root2, -1)
+	leaf := root2.issueIdentity(t, "leaf")
+
+	theMsp, err := setupCrossCertMSP(MSPv1_3, []*x509.Certificate{root1.cert, root2.cert}, []*x509.Certificate{crossCert})
+	assert.NoError(t, err)
+	_, err = crossCertValidationChain(theMsp, leaf)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "this MSP only supports a single validation chain, got 2")
+
+	// the shortest validation chain is selected, whatever the order of the root CAs
+	theMsp, err = setupCrossCertMSP(MSPv1_4_2, []*x509.Certificate{root1.cert, root2.cert}, []*x509.Certificate{crossCert})
+	assert.NoError(t, err)
+	chain, err := crossCertValidationChain(theMsp, leaf)
+	assert.NoError(t, err)
+	assert.Len(t, chain, 2)
+	assert.Equal(t, "root2", chain[1].Issuer.CommonName)
+}
+
+func TestCrossCertifiedPathLength(t *testing.T) {
+	// root1 can't have intermediate CAs, so the only valid chain of the
+	// identities of the intermediate CA goes through root2
+	root1 := newRootCA(t, "root1", 0)
+	root2 := newRootCA(t, "root2", 1)
+	ica := root1.issueCA(t, "ica", -1)
+	crossCert := root2.crossSign(t, ica, -1)
+	leaf := ica.issueIdentity(t, "leaf")
+
+	for _, version := range []MSPVersion{MSPv1_3, MSPv1_4_2} {
+		theMsp, err := setupCrossCertMSP(version, []*x509.Certificate{root1.cert, root2.cert}, []*x509.Certificate{ica.cert, crossCert})
+		assert.NoError(t, err)
+		chain, err := crossCertValidationChain(theMsp, leaf)
+		assert.NoError(t, err)
+		assert.Len(t, chain, 3)
+		assert.Equal(t, "root2", chain[2].Subject.CommonName)
+	}
+
+	// the identities of a CA issued by the intermediate CA exceed the path
+	// length constraints of both root CAs
+	subCA := ica.issueCA(t, "subca", -1)
+	theMsp, err := setupCrossCertMSP(MSPv1_4_2, []*x509.Certificate{root1.cert, root2.cert}, []*x509.Certificate{ica.cert, crossCert, subCA.cert})
+	assert.NoError(t, err)
+	_, err = crossCertValidationChain(theMsp, subCA.issueIdentity(t, "leaf2"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "path length constraint")
+}
+
+func TestSelectPreferredValidationChain(t *testing.T) {
+	theMsp, err := newBccspMsp(MSPv1_4_2)
+	assert.NoError(t, err)
+	_, err = theMsp.(*bccspmsp).selectPreferredValidationChain(nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no validation chain")
+}
@@ -16,6 +16,7 @@ const (
 	MSPv1_0 = iota
 	MSPv1_1
 	MSPv1_3
+	MSPv1_4_2
 )
 
 // NewOpts represent
@@ -52,6 +53,8 @@ func New(opts NewOpts) (MSP, error) {
 			return newBccspMsp(MSPv1_0)
 		case MSPv1_1:
 			return newBccspMsp(MSPv1_1)
+		case MSPv1_4_2:
+			return newBccspMsp(MSPv1_4_2)
 		case MSPv1_3:
 			return newBccspMsp(MSPv1_3)
 		default:
@@ -59,6 +62,8 @@ func New(opts NewOpts) (MSP, error) {
 		}
 	case *IdemixNewOpts:
 		switch opts.GetVersion() {
+		case MSPv1_4_2:
+			return newIdemixMsp(MSPv1_4_2)
 		case MSPv1_3:
 			return newIdemixMsp(MSPv1_3)
 		case MSPv1_1:
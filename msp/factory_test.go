@@ -59,6 +59,15 @@ func TestNew(t *testing.T) {
 		runtime.FuncForPC(reflect.ValueOf(i.(*bccspmsp).validateIdentityOUsV11).Pointer()).Name(),
 	)
 
+	i, err = New(&BCCSPNewOpts{NewBaseOpts{Version: MSPv1_4_2}})
+	assert.NoError(t, err)
+	assert.NotNil(t, i)
+	assert.Equal(t, MSPVersion(MSPv1_4_2), i.(*bccspmsp).version)
+	assert.Equal(t,
+		runtime.FuncForPC(reflect.ValueOf(i.(*bccspmsp).internalSelectValidationChainFunc).Pointer()).Name(),
+		runtime.FuncForPC(reflect.ValueOf(i.(*bccspmsp).selectPreferredValidationChain).Pointer()).Name(),
+	)
+
 	i, err = New(&IdemixNewOpts{NewBaseOpts{Version: MSPv1_0}})
 	assert.Error(t, err)
 	assert.Nil(t, i)
@@ -67,4 +76,8 @@ func TestNew(t *testing.T) {
 	i, err = New(&IdemixNewOpts{NewBaseOpts{Version: MSPv1_1}})
 	assert.NoError(t, err)
 	assert.NotNil(t, i)
+
+	i, err = New(&IdemixNewOpts{NewBaseOpts{Version: MSPv1_4_2}})
+	assert.NoError(t, err)
+	assert.NotNil(t, i)
 }
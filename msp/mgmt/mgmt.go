@@ -89,7 +89,11 @@ func GetManagerForChain(chainID string) msp.MSPManager {
 	mspMgr, ok := mspMap[chainID]
 	if !ok {
 		mspLogger.Debugf("Created new msp manager for channel `%s`", chainID)
-		mspMgmtMgr := &mspMgmtMgr{msp.NewMSPManager(), false}
+		cachedMgr, err := cache.NewManager(msp.NewMSPManager())
+		if err != nil {
+			mspLogger.Panicf("Failed creating the msp manager cache for channel `%s`: %+v", chainID, err)
+		}
+		mspMgmtMgr := &mspMgmtMgr{cachedMgr, false}
 		mspMap[chainID] = mspMgmtMgr
 		mspMgr = mspMgmtMgr
 	} else {
@@ -77,6 +77,28 @@ func (m *MockMSP) SatisfiesPrincipal(id msp.Identity, principal *pmsp.MSPPrincip
 	return args.Error(0)
 }
 
+type MockMSPManager struct {
+	mock.Mock
+}
+
+func (m *MockMSPManager) IsWellFormed(_ *pmsp.SerializedIdentity) error {
+	return nil
+}
+
+func (m *MockMSPManager) DeserializeIdentity(serializedIdentity []byte) (msp.Identity, error) {
+	args := m.Called(serializedIdentity)
+	return args.Get(0).(msp.Identity), args.Error(1)
+}
+
+func (m *MockMSPManager) Setup(msps []msp.MSP) error {
+	return m.Called(msps).Error(0)
+}
+
+func (m *MockMSPManager) GetMSPs() (map[string]msp.MSP, error) {
+	args := m.Called()
+	return args.Get(0).(map[string]msp.MSP), args.Error(1)
+}
+
 type MockIdentity struct {
 	mock.Mock
 
@@ -108,8 +130,8 @@ func (*MockIdentity) GetOrganizationalUnits() []*msp.OUIdentifier {
 	panic("implement me")
 }
 
-func (*MockIdentity) Verify(msg []byte, sig []byte) error {
-	panic("implement me")
+func (m *MockIdentity) Verify(msg []byte, sig []byte) error {
+	return m.Called(msg, sig).Error(0)
 }
 
 func (*MockIdentity) Serialize() ([]byte, error) {
@@ -96,6 +96,20 @@ type MSP interface {
 	SatisfiesPrincipal(id Identity, principal *msp.MSPPrincipal) error
 }
 
+// RevocationListUpdater is implemented by the MSPs whose certificate
+// revocation lists can be updated after their setup, without an update
+// of the config they were set up from
+type RevocationListUpdater interface {
+	// ValidateRevocationList checks that the supplied PEM or DER encoded
+	// CRL is issued by one of the CAs of the MSP
+	ValidateRevocationList(crl []byte) error
+
+	// UpdateRevocationLists replaces the CRLs added to the MSP since its
+	// setup with the supplied ones. The CRLs of the config of the MSP
+	// remain in effect.
+	UpdateRevocationLists(crls [][]byte) error
+}
+
 // OUIdentifier represents an organizational unit and
 // its related chain of trust identifier.
 type OUIdentifier struct {
@@ -13,6 +13,7 @@ import (
 	"encoding/hex"
 	"encoding/pem"
 	"fmt"
+	"sync"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/bccsp"
@@ -31,6 +32,9 @@ type validateIdentityOUsFuncType func(id *identity) error
 // satisfiesPrincipalInternalFuncType is the prototype of the function to check if principals are satisfied
 type satisfiesPrincipalInternalFuncType func(id Identity, principal *m.MSPPrincipal) error
 
+// selectValidationChainFuncType is the prototype of the function to select the validation chain of a certificate
+type selectValidationChainFuncType func(validationChains [][]*x509.Certificate) ([]*x509.Certificate, error)
+
 // This is an instantiation of an MSP that
 // uses BCCSP for its cryptographic primitives.
 type bccspmsp struct {
@@ -47,6 +51,9 @@ type bccspmsp struct {
 	// internalSatisfiesPrincipalInternalFunc is the pointer to the function to check if principals are satisfied
 	internalSatisfiesPrincipalInternalFunc satisfiesPrincipalInternalFuncType
 
+	// internalSelectValidationChainFunc is the pointer to the function to select the validation chain of a certificate
+	internalSelectValidationChainFunc selectValidationChainFuncType
+
 	// list of CA certs we trust
 	rootCerts []Identity
 
@@ -83,6 +90,11 @@ type bccspmsp struct {
 	// list of certificate revocation lists
 	CRL []*pkix.CertificateList
 
+	// list of certificate revocation lists updated since the setup,
+	// guarded by updatedCRLLock
+	updatedCRL     []*pkix.CertificateList
+	updatedCRLLock sync.RWMutex
+
 	// list of OUs
 	ouIdentifiers map[string][][]byte
 
@@ -107,6 +119,7 @@ func newBccspMsp(version MSPVersion) (MSP, error) {
 	theMsp := &bccspmsp{}
 	theMsp.version = version
 	theMsp.bccsp = bccsp
+	theMsp.internalSelectValidationChainFunc = theMsp.selectUniqueValidationChain
 	switch version {
 	case MSPv1_0:
 		theMsp.internalSetupFunc = theMsp.setupV1
@@ -120,6 +133,11 @@ func newBccspMsp(version MSPVersion) (MSP, error) {
 		theMsp.internalSetupFunc = theMsp.setupV11
 		theMsp.internalValidateIdentityOusFunc = theMsp.validateIdentityOUsV11
 		theMsp.internalSatisfiesPrincipalInternalFunc = theMsp.satisfiesPrincipalInternalV13
+	case MSPv1_4_2:
+		theMsp.internalSetupFunc = theMsp.setupV11
+		theMsp.internalValidateIdentityOusFunc = theMsp.validateIdentityOUsV11
+		theMsp.internalSatisfiesPrincipalInternalFunc = theMsp.satisfiesPrincipalInternalV13
+		theMsp.internalSelectValidationChainFunc = theMsp.selectPreferredValidationChain
 	default:
 		return nil, errors.Errorf("Invalid MSP version [%v]", version)
 	}
@@ -288,6 +306,56 @@ func (msp *bccspmsp) Validate(id Identity) error {
 	}
 }
 
+// ValidateRevocationList checks that the supplied CRL
+// is signed by one of the root or intermediate CAs
+// of this MSP
+func (msp *bccspmsp) ValidateRevocationList(crlBytes []byte) error {
+	crl, err := x509.ParseCRL(crlBytes)
+	if err != nil {
+		return errors.Wrap(err, "could not parse CRL")
+	}
+	return msp.validateRevocationList(crl)
+}
+
+func (msp *bccspmsp) validateRevocationList(crl *pkix.CertificateList) error {
+	for _, ca := range append(append([]Identity{}, msp.rootCerts...), msp.intermediateCerts...) {
+		if ca.(*identity).cert.CheckCRLSignature(crl) == nil {
+			return nil
+		}
+	}
+	return errors.Errorf("CRL is not signed by a CA of MSP %s", msp.name)
+}
+
+// UpdateRevocationLists replaces the CRLs updated since
+// the setup of this MSP with the supplied ones, which must
+// be signed by its CAs; the CRLs of its config still apply
+func (msp *bccspmsp) UpdateRevocationLists(crls [][]byte) error {
+	updatedCRL := make([]*pkix.CertificateList, len(crls))
+	for i, crlBytes := range crls {
+		crl, err := x509.ParseCRL(crlBytes)
+		if err != nil {
+			return errors.Wrap(err, "could not parse CRL")
+		}
+		if err := msp.validateRevocationList(crl); err != nil {
+			return err
+		}
+		updatedCRL[i] = crl
+	}
+
+	msp.updatedCRLLock.Lock()
+	defer msp.updatedCRLLock.Unlock()
+	msp.updatedCRL = updatedCRL
+	return nil
+}
+
+// revocationLists returns the CRLs of the config of this
+// MSP followed by the ones updated since its setup
+func (msp *bccspmsp) revocationLists() []*pkix.CertificateList {
+	msp.updatedCRLLock.RLock()
+	defer msp.updatedCRLLock.RUnlock()
+	return append(append([]*pkix.CertificateList{}, msp.CRL...), msp.updatedCRL...)
+}
+
 // hasOURole checks that the identity belongs to the organizational unit
 // associated to the specified MSPRole.
 // This function does not check the certifiers identifier.
@@ -606,6 +674,10 @@ func (msp *bccspmsp) getUniqueValidationChain(cert *x509.Certificate, opts x509.
 		return nil, errors.WithMessage(err, "the supplied identity is not valid")
 	}
 
+	return msp.internalSelectValidationChainFunc(validationChains)
+}
+
+func (msp *bccspmsp) selectUniqueValidationChain(validationChains [][]*x509.Certificate) ([]*x509.Certificate, error) {
 	// we only support a single validation chain;
 	// if there's more than one then there might
 	// be unclarity about who owns the identity
@@ -616,6 +688,70 @@ func (msp *bccspmsp) getUniqueValidationChain(cert *x509.Certificate, opts x509.
 	return validationChains[0], nil
 }
 
+// selectPreferredValidationChain selects the validation chain with the
+// fewest certificates, among those which satisfy the path length constraints
+// of their CAs. Chains of the same length, like those going through an
+// intermediate CA cross-signed by several root CAs, are ordered by the
+// position of their root CA in the config of the MSP, then by the positions
+// of their intermediate CAs starting from the closest to the certificate,
+// and finally by the DER encoding of their certificates; every peer thus
+// selects the same chain, which the certifiers identifiers and the CRLs of
+// the certificate depend on.
+func (msp *bccspmsp) selectPreferredValidationChain(validationChains [][]*x509.Certificate) ([]*x509.Certificate, error) {
+	if len(validationChains) == 0 {
+		return nil, errors.New("no validation chain")
+	}
+
+	preferred := validationChains[0]
+	for _, chain := range validationChains[1:] {
+		if msp.compareValidationChains(chain, preferred) < 0 {
+			preferred = chain
+		}
+	}
+	if len(validationChains) > 1 {
+		mspLogger.Debugf("Selected the validation chain ending with [%s] among %d chains for [%s] in MSP %s",
+			preferred[len(preferred)-1].Subject, len(validationChains), preferred[0].Subject, msp.name)
+	}
+
+	return preferred, nil
+}
+
+// compareValidationChains returns a negative number if chain a is preferred
+// to chain b, a positive one if b is preferred to a, and 0 if they are equal
+func (msp *bccspmsp) compareValidationChains(a, b []*x509.Certificate) int {
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+
+	if diff := caPosition(a[len(a)-1], msp.rootCerts) - caPosition(b[len(b)-1], msp.rootCerts); diff != 0 {
+		return diff
+	}
+	for i := 1; i < len(a)-1; i++ {
+		if diff := caPosition(a[i], msp.intermediateCerts) - caPosition(b[i], msp.intermediateCerts); diff != 0 {
+			return diff
+		}
+	}
+	for i := range a {
+		if diff := bytes.Compare(a[i].Raw, b[i].Raw); diff != 0 {
+			return diff
+		}
+	}
+
+	return 0
+}
+
+// caPosition returns the position of the certificate among the CAs, or their
+// number if it is not one of them
+func caPosition(cert *x509.Certificate, cas []Identity) int {
+	for i, ca := range cas {
+		if bytes.Equal(ca.(*identity).cert.Raw, cert.Raw) {
+			return i
+		}
+	}
+
+	return len(cas)
+}
+
 func (msp *bccspmsp) getValidationChain(cert *x509.Certificate, isIntermediateChain bool) ([]*x509.Certificate, error) {
 	validationChain, err := msp.getUniqueValidationChain(cert, msp.getValidityOptsForCert(cert))
 	if err != nil {
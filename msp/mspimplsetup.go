@@ -213,14 +213,27 @@ func (msp *bccspmsp) finalizeSetupCAs(config *m.FabricMSPConfig) error {
 	// certification tree
 	msp.certificationTreeInternalNodesMap = make(map[string]bool)
 	for _, id := range append([]Identity{}, msp.intermediateCerts...) {
-		chain, err := msp.getUniqueValidationChain(id.(*identity).cert, msp.getValidityOptsForCert(id.(*identity).cert))
+		cert := id.(*identity).cert
+		chain, err := msp.getUniqueValidationChain(cert, msp.getValidityOptsForCert(cert))
 		if err != nil {
-			return errors.WithMessage(err, fmt.Sprintf("failed getting validation chain, (SN: %s)", id.(*identity).cert.SerialNumber))
+			return errors.WithMessage(err, fmt.Sprintf("failed getting validation chain, (SN: %s)", cert.SerialNumber))
 		}
 
-		// Recall chain[0] is id.(*identity).id so it does not count as a parent
-		for i := 1; i < len(chain); i++ {
-			msp.certificationTreeInternalNodesMap[string(chain[i].Raw)] = true
+		chains := [][]*x509.Certificate{chain}
+		if msp.version >= MSPv1_4_2 {
+			// an intermediate CA cross-signed by several CAs is a child of
+			// all of them, whichever chain is selected for it
+			chains, err = cert.Verify(msp.getValidityOptsForCert(cert))
+			if err != nil {
+				return errors.WithMessage(err, fmt.Sprintf("failed getting validation chains, (SN: %s)", cert.SerialNumber))
+			}
+		}
+
+		for _, chain := range chains {
+			// Recall chain[0] is id.(*identity).id so it does not count as a parent
+			for i := 1; i < len(chain); i++ {
+				msp.certificationTreeInternalNodesMap[string(chain[i].Raw)] = true
+			}
 		}
 	}
 
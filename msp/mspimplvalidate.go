@@ -88,7 +88,7 @@ func (msp *bccspmsp) validateCertAgainstChain(cert *x509.Certificate, validation
 
 	// check whether one of the CRLs we have has this cert's
 	// SKI as its AuthorityKeyIdentifier
-	for _, crl := range msp.CRL {
+	for _, crl := range msp.revocationLists() {
 		aki, err := getAuthorityKeyIdentifierFromCrl(crl)
 		if err != nil {
 			return errors.WithMessage(err, "could not obtain Authority Key Identifier for crl")
@@ -17,6 +17,7 @@ limitations under the License.
 package msp
 
 import (
+	"io/ioutil"
 	"path/filepath"
 	"testing"
 
@@ -94,3 +95,38 @@ func TestRevokedIntermediateCA(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "CA Certificate is not valid, ")
 }
+
+func TestUpdateRevocationLists(t *testing.T) {
+	// testdata/revocation2 holds a CRL revoking its signcert whose
+	// signature is invalid, testdata/revocation the same CRL signed
+	// by its CA, which is the CA of testdata/revocation2 too
+	thisMSP := getLocalMSP(t, "testdata/revocation2")
+	updater, ok := thisMSP.(RevocationListUpdater)
+	assert.True(t, ok)
+
+	id, err := thisMSP.GetDefaultSigningIdentity()
+	assert.NoError(t, err)
+	assert.NoError(t, id.Validate())
+
+	invalidCRL, err := ioutil.ReadFile("testdata/revocation2/crls/crl.pem")
+	assert.NoError(t, err)
+	err = updater.ValidateRevocationList(invalidCRL)
+	assert.EqualError(t, err, "CRL is not signed by a CA of MSP SampleOrg")
+	err = updater.UpdateRevocationLists([][]byte{invalidCRL})
+	assert.EqualError(t, err, "CRL is not signed by a CA of MSP SampleOrg")
+	err = updater.ValidateRevocationList([]byte("not a CRL"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "could not parse CRL")
+
+	crl, err := ioutil.ReadFile("testdata/revocation/crls/crl.pem")
+	assert.NoError(t, err)
+	assert.NoError(t, updater.ValidateRevocationList(crl))
+	assert.NoError(t, updater.UpdateRevocationLists([][]byte{crl}))
+	err = id.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "The certificate has been revoked")
+
+	// the CRLs replace the ones updated before
+	assert.NoError(t, updater.UpdateRevocationLists(nil))
+	assert.NoError(t, id.Validate())
+}
@@ -8,9 +8,14 @@ package broadcast
 
 import (
 	"io"
+	"sync"
+	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/replay"
+	"github.com/hyperledger/fabric/common/tracing"
 	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/orderer/common/broadcast/content"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
@@ -27,6 +32,14 @@ type Handler interface {
 	Handle(srv ab.AtomicBroadcast_BroadcastServer) error
 }
 
+// Drainer is implemented by the handlers which can stop accepting messages
+// before the orderer shuts down
+type Drainer interface {
+	// Drain rejects the messages received from then on, and waits for those
+	// being processed to be enqueued, or for the timeout to expire
+	Drain(timeout time.Duration) error
+}
+
 // ChannelSupportRegistrar provides a way for the Handler to look up the Support for a channel
 type ChannelSupportRegistrar interface {
 	// BroadcastChannelSupport returns the message channel header, whether the message is a config update
@@ -60,13 +73,24 @@ type Consenter interface {
 }
 
 type handlerImpl struct {
-	sm ChannelSupportRegistrar
+	sm               ChannelSupportRegistrar
+	replayCache      *replay.Cache
+	contentValidator content.Validator
+
+	lock     sync.RWMutex
+	draining bool
+	inFlight sync.WaitGroup
 }
 
-// NewHandlerImpl constructs a new implementation of the Handler interface
-func NewHandlerImpl(sm ChannelSupportRegistrar) Handler {
+// NewHandlerImpl constructs a new implementation of the Handler interface,
+// the replay cache and the content validator are optional: the replay cache
+// rejects the messages already received, and the content validator the
+// messages whose content is invalid
+func NewHandlerImpl(sm ChannelSupportRegistrar, replayCache *replay.Cache, contentValidator content.Validator) Handler {
 	return &handlerImpl{
-		sm: sm,
+		sm:               sm,
+		replayCache:      replayCache,
+		contentValidator: contentValidator,
 	}
 }
 
@@ -85,58 +109,161 @@ func (bh *handlerImpl) Handle(srv ab.AtomicBroadcast_BroadcastServer) error {
 			return err
 		}
 
-		chdr, isConfig, processor, err := bh.sm.BroadcastChannelSupport(msg)
+		// The span of each message is a child of the span of the stream,
+		// whose parent is the span of the client
+		if !bh.begin() {
+			logger.Debugf("Rejecting broadcast of message from %s with SERVICE_UNAVAILABLE: orderer is shutting down", addr)
+			return srv.Send(&ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: "orderer is shutting down"})
+		}
+		_, span := tracing.Start(srv.Context(), "broadcast.ProcessMessage")
+		resp := bh.processMessage(msg, addr, span)
+		span.SetAttribute("status", resp.Status.String())
+		span.End()
+		bh.inFlight.Done()
+
+		err = srv.Send(resp)
+		if resp.Status != cb.Status_SUCCESS {
+			return err
+		}
 		if err != nil {
-			channelID := "<malformed_header>"
-			if chdr != nil {
-				channelID = chdr.ChannelId
-			}
-			logger.Warningf("[channel: %s] Could not get message processor for serving %s: %s", channelID, addr, err)
-			return srv.Send(&ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST, Info: err.Error()})
+			logger.Warningf("Error sending to %s: %s", addr, err)
+			return err
 		}
+	}
+}
+
+// begin records a message being processed, unless the handler is draining
+func (bh *handlerImpl) begin() bool {
+	bh.lock.RLock()
+	defer bh.lock.RUnlock()
+	if bh.draining {
+		return false
+	}
+	bh.inFlight.Add(1)
+	return true
+}
+
+// Drain rejects the messages received from then on with SERVICE_UNAVAILABLE,
+// so that the clients send them to another orderer, and waits for the
+// messages being processed to be enqueued
+func (bh *handlerImpl) Drain(timeout time.Duration) error {
+	bh.lock.Lock()
+	bh.draining = true
+	bh.lock.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		bh.inFlight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		return errors.Errorf("broadcast messages still being processed after %s", timeout)
+	}
+}
 
-		if err = processor.WaitReady(); err != nil {
-			logger.Warningf("[channel: %s] Rejecting broadcast of message from %s with SERVICE_UNAVAILABLE: rejected by Consenter: %s", chdr.ChannelId, addr, err)
-			return srv.Send(&ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()})
+// processMessage validates and orders a message, and returns the response to send to the client
+func (bh *handlerImpl) processMessage(msg *cb.Envelope, addr string, span *tracing.Span) *ab.BroadcastResponse {
+	chdr, isConfig, processor, err := bh.sm.BroadcastChannelSupport(msg)
+	if err != nil {
+		channelID := "<malformed_header>"
+		if chdr != nil {
+			channelID = chdr.ChannelId
 		}
+		logger.Warningf("[channel: %s] Could not get message processor for serving %s: %s", channelID, addr, err)
+		span.SetError(err)
+		return &ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST, Info: err.Error()}
+	}
+	span.SetAttribute("channel", chdr.ChannelId)
+	span.SetAttribute("tx_id", chdr.TxId)
+
+	if err = processor.WaitReady(); err != nil {
+		logger.Warningf("[channel: %s] Rejecting broadcast of message from %s with SERVICE_UNAVAILABLE: rejected by Consenter: %s", chdr.ChannelId, addr, err)
+		span.SetError(err)
+		return &ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()}
+	}
 
-		if !isConfig {
-			logger.Debugf("[channel: %s] Broadcast is processing normal message from %s with txid '%s' of type %s", chdr.ChannelId, addr, chdr.TxId, cb.HeaderType_name[chdr.Type])
-
-			configSeq, err := processor.ProcessNormalMsg(msg)
-			if err != nil {
-				logger.Warningf("[channel: %s] Rejecting broadcast of normal message from %s because of error: %s", chdr.ChannelId, addr, err)
-				return srv.Send(&ab.BroadcastResponse{Status: ClassifyError(err), Info: err.Error()})
-			}
-
-			err = processor.Order(msg, configSeq)
-			if err != nil {
-				logger.Warningf("[channel: %s] Rejecting broadcast of normal message from %s with SERVICE_UNAVAILABLE: rejected by Order: %s", chdr.ChannelId, addr, err)
-				return srv.Send(&ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()})
-			}
-		} else { // isConfig
-			logger.Debugf("[channel: %s] Broadcast is processing config update message from %s", chdr.ChannelId, addr)
-
-			config, configSeq, err := processor.ProcessConfigUpdateMsg(msg)
-			if err != nil {
-				logger.Warningf("[channel: %s] Rejecting broadcast of config message from %s because of error: %s", chdr.ChannelId, addr, err)
-				return srv.Send(&ab.BroadcastResponse{Status: ClassifyError(err), Info: err.Error()})
-			}
-
-			err = processor.Configure(config, configSeq)
-			if err != nil {
-				logger.Warningf("[channel: %s] Rejecting broadcast of config message from %s with SERVICE_UNAVAILABLE: rejected by Configure: %s", chdr.ChannelId, addr, err)
-				return srv.Send(&ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()})
-			}
+	if bh.contentValidator != nil {
+		if err = bh.contentValidator.Validate(chdr, msg); err != nil {
+			logger.Warningf("[channel: %s] Rejecting broadcast of message from %s with invalid content: %s", chdr.ChannelId, addr, err)
+			span.SetError(err)
+			return &ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST, Info: err.Error()}
 		}
+	}
 
-		logger.Debugf("[channel: %s] Broadcast has successfully enqueued message of type %s from %s", chdr.ChannelId, cb.HeaderType_name[chdr.Type], addr)
+	if !isConfig {
+		logger.Debugf("[channel: %s] Broadcast is processing normal message from %s with txid '%s' of type %s", chdr.ChannelId, addr, chdr.TxId, cb.HeaderType_name[chdr.Type])
 
-		err = srv.Send(&ab.BroadcastResponse{Status: cb.Status_SUCCESS})
+		configSeq, err := processor.ProcessNormalMsg(msg)
 		if err != nil {
-			logger.Warningf("[channel: %s] Error sending to %s: %s", chdr.ChannelId, addr, err)
-			return err
+			logger.Warningf("[channel: %s] Rejecting broadcast of normal message from %s because of error: %s", chdr.ChannelId, addr, err)
+			span.SetError(err)
+			return &ab.BroadcastResponse{Status: ClassifyError(err), Info: err.Error()}
 		}
+
+		if resp := bh.checkReplay(msg, chdr, addr, span); resp != nil {
+			return resp
+		}
+
+		order := span.Child("broadcast.Order")
+		err = processor.Order(msg, configSeq)
+		order.SetError(err)
+		order.End()
+		if err != nil {
+			bh.forgetReplay(msg)
+			logger.Warningf("[channel: %s] Rejecting broadcast of normal message from %s with SERVICE_UNAVAILABLE: rejected by Order: %s", chdr.ChannelId, addr, err)
+			span.SetError(err)
+			return &ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()}
+		}
+	} else { // isConfig
+		logger.Debugf("[channel: %s] Broadcast is processing config update message from %s", chdr.ChannelId, addr)
+
+		config, configSeq, err := processor.ProcessConfigUpdateMsg(msg)
+		if err != nil {
+			logger.Warningf("[channel: %s] Rejecting broadcast of config message from %s because of error: %s", chdr.ChannelId, addr, err)
+			span.SetError(err)
+			return &ab.BroadcastResponse{Status: ClassifyError(err), Info: err.Error()}
+		}
+
+		if resp := bh.checkReplay(msg, chdr, addr, span); resp != nil {
+			return resp
+		}
+
+		err = processor.Configure(config, configSeq)
+		if err != nil {
+			bh.forgetReplay(msg)
+			logger.Warningf("[channel: %s] Rejecting broadcast of config message from %s with SERVICE_UNAVAILABLE: rejected by Configure: %s", chdr.ChannelId, addr, err)
+			span.SetError(err)
+			return &ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()}
+		}
+	}
+
+	logger.Debugf("[channel: %s] Broadcast has successfully enqueued message of type %s from %s", chdr.ChannelId, cb.HeaderType_name[chdr.Type], addr)
+
+	return &ab.BroadcastResponse{Status: cb.Status_SUCCESS}
+}
+
+// checkReplay returns the response rejecting the given message if it isn't fresh
+// or was already received, the message must already be validated so that only
+// the nonces of authentic messages are recorded
+func (bh *handlerImpl) checkReplay(msg *cb.Envelope, chdr *cb.ChannelHeader, addr string, span *tracing.Span) *ab.BroadcastResponse {
+	if bh.replayCache == nil {
+		return nil
+	}
+	if err := bh.replayCache.Check(msg); err != nil {
+		logger.Warningf("[channel: %s] Rejecting broadcast of replayed message from %s: %s", chdr.ChannelId, addr, err)
+		span.SetError(err)
+		return &ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST, Info: err.Error()}
+	}
+	return nil
+}
+
+// forgetReplay lets a message which couldn't be enqueued be retried
+func (bh *handlerImpl) forgetReplay(msg *cb.Envelope) {
+	if bh.replayCache != nil {
+		bh.replayCache.Forget(msg)
 	}
 }
 
@@ -14,9 +14,12 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/replay"
+	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
@@ -154,7 +157,7 @@ func getMockSupportManager() *mockSupportManager {
 
 func TestEnqueueFailure(t *testing.T) {
 	mm := getMockSupportManager()
-	bh := NewHandlerImpl(mm)
+	bh := NewHandlerImpl(mm, nil, nil)
 	m := newMockB()
 	defer close(m.recvChan)
 	done := make(chan struct{})
@@ -185,6 +188,71 @@ func TestEnqueueFailure(t *testing.T) {
 	}
 }
 
+func TestReplay(t *testing.T) {
+	env := &cb.Envelope{
+		Payload: utils.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader:   utils.MarshalOrPanic(&cb.ChannelHeader{Timestamp: util.CreateUtcTimestamp()}),
+				SignatureHeader: utils.MarshalOrPanic(&cb.SignatureHeader{Nonce: []byte("nonce"), Creator: []byte("alice")}),
+			},
+		}),
+	}
+
+	for _, isConfig := range []bool{false, true} {
+		mm := getMockSupportManager()
+		mm.MsgProcessorIsConfig = isConfig
+		bh := NewHandlerImpl(mm, replay.NewCache(time.Minute, time.Minute, 10), nil)
+
+		// a message which couldn't be enqueued may be retried
+		mm.MsgProcessorVal.rejectEnqueue = true
+		m := newMockB()
+		go bh.Handle(m)
+		m.recvChan <- env
+		assert.Equal(t, cb.Status_SERVICE_UNAVAILABLE, (<-m.sendChan).Status)
+		close(m.recvChan)
+
+		mm.MsgProcessorVal.rejectEnqueue = false
+		m = newMockB()
+		go bh.Handle(m)
+		m.recvChan <- env
+		assert.Equal(t, cb.Status_SUCCESS, (<-m.sendChan).Status)
+		m.recvChan <- env
+		reply := <-m.sendChan
+		assert.Equal(t, cb.Status_BAD_REQUEST, reply.Status)
+		assert.Equal(t, "envelope is a replay of an already received envelope", reply.Info)
+		close(m.recvChan)
+	}
+}
+
+type mockContentValidator struct {
+	err error
+}
+
+func (mv *mockContentValidator) Validate(chdr *cb.ChannelHeader, msg *cb.Envelope) error {
+	return mv.err
+}
+
+func TestContentValidator(t *testing.T) {
+	for _, isConfig := range []bool{false, true} {
+		mm := getMockSupportManager()
+		mm.MsgProcessorIsConfig = isConfig
+		validator := &mockContentValidator{}
+		bh := NewHandlerImpl(mm, nil, validator)
+		m := newMockB()
+		go bh.Handle(m)
+
+		m.recvChan <- nil
+		assert.Equal(t, cb.Status_SUCCESS, (<-m.sendChan).Status)
+
+		validator.err = errors.New("invalid content")
+		m.recvChan <- nil
+		reply := <-m.sendChan
+		assert.Equal(t, cb.Status_BAD_REQUEST, reply.Status)
+		assert.Equal(t, "invalid content", reply.Info)
+		close(m.recvChan)
+	}
+}
+
 func TestClassifyError(t *testing.T) {
 	t.Run("NotFound", func(t *testing.T) {
 		assert.Equal(t, cb.Status_NOT_FOUND, ClassifyError(msgprocessor.ErrChannelDoesNotExist))
@@ -203,7 +271,7 @@ func TestClassifyError(t *testing.T) {
 func TestBadChannelId(t *testing.T) {
 	mm := getMockSupportManager()
 	mm.MsgProcessorVal = &mockSupport{ProcessErr: msgprocessor.ErrChannelDoesNotExist}
-	bh := NewHandlerImpl(mm)
+	bh := NewHandlerImpl(mm, nil, nil)
 	m := newMockB()
 	defer close(m.recvChan)
 	done := make(chan struct{})
@@ -228,7 +296,7 @@ func TestBadChannelId(t *testing.T) {
 func TestGoodConfigUpdate(t *testing.T) {
 	mm := getMockSupportManager()
 	mm.MsgProcessorIsConfig = true
-	bh := NewHandlerImpl(mm)
+	bh := NewHandlerImpl(mm, nil, nil)
 	m := newMockB()
 	defer close(m.recvChan)
 	go bh.Handle(m)
@@ -242,7 +310,7 @@ func TestBadConfigUpdate(t *testing.T) {
 	mm := getMockSupportManager()
 	mm.MsgProcessorIsConfig = true
 	mm.MsgProcessorVal.ProcessErr = fmt.Errorf("Error")
-	bh := NewHandlerImpl(mm)
+	bh := NewHandlerImpl(mm, nil, nil)
 	m := newMockB()
 	defer close(m.recvChan)
 	go bh.Handle(m)
@@ -253,7 +321,7 @@ func TestBadConfigUpdate(t *testing.T) {
 }
 
 func TestGracefulShutdown(t *testing.T) {
-	bh := NewHandlerImpl(nil)
+	bh := NewHandlerImpl(nil, nil, nil)
 	m := newMockB()
 	close(m.recvChan)
 	assert.NoError(t, bh.Handle(m), "Should exit normally upon EOF")
@@ -264,7 +332,7 @@ func TestRejected(t *testing.T) {
 		MsgProcessorVal: &mockSupport{ProcessErr: fmt.Errorf("Reject")},
 		ChdrVal:         &cb.ChannelHeader{},
 	}
-	bh := NewHandlerImpl(mm)
+	bh := NewHandlerImpl(mm, nil, nil)
 	m := newMockB()
 	defer close(m.recvChan)
 	go bh.Handle(m)
@@ -276,13 +344,13 @@ func TestRejected(t *testing.T) {
 }
 
 func TestBadStreamRecv(t *testing.T) {
-	bh := NewHandlerImpl(nil)
+	bh := NewHandlerImpl(nil, nil, nil)
 	assert.Error(t, bh.Handle(&erroneousRecvMockB{}), "Should catch unexpected stream error")
 }
 
 func TestBadStreamSend(t *testing.T) {
 	mm := getMockSupportManager()
-	bh := NewHandlerImpl(mm)
+	bh := NewHandlerImpl(mm, nil, nil)
 	m := &erroneousSendMockB{recvVal: nil}
 	assert.Error(t, bh.Handle(m), "Should catch unexpected stream error")
 }
@@ -291,7 +359,7 @@ func TestMalformedHeader(t *testing.T) {
 	mm := getMockSupportManager()
 	mm.ChdrVal = nil
 	mm.MsgProcessorErr = errors.New("Mocked Error")
-	bh := NewHandlerImpl(mm)
+	bh := NewHandlerImpl(mm, nil, nil)
 	m := newMockB()
 	defer close(m.recvChan)
 	done := make(chan struct{})
@@ -312,3 +380,46 @@ func TestMalformedHeader(t *testing.T) {
 		t.Fatalf("Should have terminated the stream")
 	}
 }
+
+type blockingContentValidator struct {
+	validating chan struct{}
+	release    chan struct{}
+}
+
+func (bv *blockingContentValidator) Validate(chdr *cb.ChannelHeader, msg *cb.Envelope) error {
+	bv.validating <- struct{}{}
+	<-bv.release
+	return nil
+}
+
+func TestDrain(t *testing.T) {
+	validator := &blockingContentValidator{validating: make(chan struct{}), release: make(chan struct{})}
+	bh := NewHandlerImpl(getMockSupportManager(), nil, validator).(Drainer)
+	m := newMockB()
+	defer close(m.recvChan)
+	go bh.(Handler).Handle(m)
+
+	m.recvChan <- nil
+	<-validator.validating
+
+	// the message being processed delays the drain
+	assert.EqualError(t, bh.Drain(10*time.Millisecond), "broadcast messages still being processed after 10ms")
+
+	drained := make(chan error, 1)
+	go func() { drained <- bh.Drain(time.Second) }()
+	close(validator.release)
+	assert.Equal(t, cb.Status_SUCCESS, (<-m.sendChan).Status)
+	assert.NoError(t, <-drained)
+
+	// the messages received once draining are rejected
+	m.recvChan <- nil
+	reply := <-m.sendChan
+	assert.Equal(t, cb.Status_SERVICE_UNAVAILABLE, reply.Status)
+	assert.Equal(t, "orderer is shutting down", reply.Info)
+
+	m = newMockB()
+	defer close(m.recvChan)
+	go bh.(Handler).Handle(m)
+	m.recvChan <- nil
+	assert.Equal(t, cb.Status_SERVICE_UNAVAILABLE, (<-m.sendChan).Status)
+}
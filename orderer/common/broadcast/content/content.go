@@ -0,0 +1,52 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package content
+
+import (
+	"os"
+	"plugin"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// ValidatorFactory is the name of the function a content validator plugin
+// must export, with the signature func() content.Validator
+const ValidatorFactory = "NewContentValidator"
+
+// Validator validates the content of the messages received by the Broadcast
+// handler, so that the invalid messages are rejected before they are processed
+// by the channel and ordered.
+type Validator interface {
+	// Validate returns an error if the given message, with the given channel
+	// header, must be rejected
+	Validate(chdr *cb.ChannelHeader, msg *cb.Envelope) error
+}
+
+// LoadValidator loads a content validator from the Go plugin at the given path
+func LoadValidator(path string) (Validator, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, errors.Wrapf(err, "could not find content validator plugin at %s", path)
+	}
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed opening content validator plugin at %s", path)
+	}
+	symbol, err := p.Lookup(ValidatorFactory)
+	if err != nil {
+		return nil, errors.Wrapf(err, "content validator plugin at %s must export %s", path, ValidatorFactory)
+	}
+	factory, ok := symbol.(func() Validator)
+	if !ok {
+		return nil, errors.Errorf("%s of content validator plugin at %s must be a func() content.Validator", ValidatorFactory, path)
+	}
+	validator := factory()
+	if validator == nil {
+		return nil, errors.Errorf("content validator plugin at %s returned no validator", path)
+	}
+	return validator, nil
+}
@@ -0,0 +1,43 @@
+// +build go1.9,linux,cgo go1.10,darwin,cgo
+// +build !ppc64le
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package content_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/orderer/common/broadcast/content"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validatorTestPlugin = "github.com/hyperledger/fabric/orderer/common/broadcast/content/testdata/plugin"
+
+func TestLoadValidatorPlugin(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "validator")
+	require.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	path := filepath.Join(testDir, "validator.so")
+	output, err := exec.Command("go", "build", "-o", path, "-buildmode=plugin", validatorTestPlugin).CombinedOutput()
+	require.NoError(t, err, "Could not build plugin: "+string(output))
+
+	validator, err := content.LoadValidator(path)
+	require.NoError(t, err)
+
+	chdr := &cb.ChannelHeader{TxId: "tx"}
+	assert.NoError(t, validator.Validate(chdr, &cb.Envelope{Payload: utils.MarshalOrPanic(&cb.Payload{Data: []byte("data")})}))
+	assert.EqualError(t, validator.Validate(chdr, &cb.Envelope{Payload: utils.MarshalOrPanic(&cb.Payload{})}), "message tx has no data")
+}
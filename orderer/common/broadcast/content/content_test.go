@@ -0,0 +1,37 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package content_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/orderer/common/broadcast/content"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadValidatorMissing(t *testing.T) {
+	validator, err := content.LoadValidator("/does/not/exist.so")
+	assert.Nil(t, validator)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "could not find content validator plugin at /does/not/exist.so")
+}
+
+func TestLoadValidatorNotAPlugin(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "validator")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	path := filepath.Join(testDir, "validator.so")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("not a plugin"), 0644))
+	validator, err := content.LoadValidator(path)
+	assert.Nil(t, validator)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed opening content validator plugin at "+path)
+}
@@ -0,0 +1,36 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"github.com/hyperledger/fabric/orderer/common/broadcast/content"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// NewContentValidator creates a validator rejecting the messages without data
+func NewContentValidator() content.Validator {
+	return &validator{}
+}
+
+type validator struct{}
+
+// Validate returns an error if the payload of the message has no data
+func (v *validator) Validate(chdr *cb.ChannelHeader, msg *cb.Envelope) error {
+	payload, err := utils.UnmarshalPayload(msg.Payload)
+	if err != nil {
+		return err
+	}
+	if len(payload.Data) == 0 {
+		return errors.Errorf("message %s has no data", chdr.TxId)
+	}
+	return nil
+}
+
+func main() {
+}
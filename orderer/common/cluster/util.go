@@ -104,9 +104,10 @@ func NewTLSPinningDialer(config comm.ClientConfig) *PredicateDialer {
 // SetConfig sets the configuration of the PredicateDialer
 func (dialer *PredicateDialer) SetConfig(config comm.ClientConfig) {
 	configCopy := comm.ClientConfig{
-		Timeout: config.Timeout,
-		SecOpts: &comm.SecureOptions{},
-		KaOpts:  &comm.KeepaliveOptions{},
+		Timeout:            config.Timeout,
+		DNSRefreshInterval: config.DNSRefreshInterval,
+		SecOpts:            &comm.SecureOptions{},
+		KaOpts:             &comm.KeepaliveOptions{},
 	}
 	// Explicitly copy configuration
 	if config.SecOpts != nil {
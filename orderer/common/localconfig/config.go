@@ -11,6 +11,7 @@ import (
 
 	bccsp "github.com/hyperledger/fabric/bccsp/factory"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/secrets"
 	"github.com/hyperledger/fabric/common/viperutil"
 	coreconfig "github.com/hyperledger/fabric/core/config"
 
@@ -35,6 +36,7 @@ type TopLevel struct {
 	Kafka      Kafka
 	BFTsmart   BFTsmart //JCS my struct
 	Debug      Debug
+	Metrics    Metrics
 }
 
 // General contains config which should be common among all orderer types.
@@ -54,7 +56,16 @@ type General struct {
 	LocalMSPDir    string
 	LocalMSPID     string
 	BCCSP          *bccsp.FactoryOpts
-	Authentication Authentication
+	Authentication   Authentication
+	Checkpoint       Checkpoint
+	Tracing          Tracing
+	ContentValidator ContentValidator
+	// ShutdownGracePeriod is the time the orderer lets the cut of the
+	// pending batches and the in-flight requests complete when it shuts down.
+	ShutdownGracePeriod   time.Duration
+	Deliver               Deliver
+	CertificateExpiration CertificateExpiration
+	Cluster               Cluster
 }
 
 // Keepalive contains configuration for gRPC servers.
@@ -84,13 +95,89 @@ type SASLPlain struct {
 // Authentication contains configuration parameters related to authenticating
 // client messages.
 type Authentication struct {
-	TimeWindow time.Duration
+	TimeWindow       time.Duration
+	ReplayProtection ReplayProtection
+}
+
+// ReplayProtection contains configuration for rejecting the broadcast messages
+// and deliver requests which were already received.
+type ReplayProtection struct {
+	Enabled      bool
+	CacheSize    int
+	MaxClockSkew time.Duration
+}
+
+// Deliver contains configuration for the limits of the deliver streams served
+// to the clients.
+type Deliver struct {
+	MaxStreamsPerIdentity int
+	BlocksInFlight        int
+}
+
+// CertificateExpiration contains configuration for the monitoring of the
+// expiration of the certificates of the orderer and of its channels.
+type CertificateExpiration struct {
+	Enabled       bool
+	CheckInterval time.Duration
+	WarnBefore    time.Duration
+}
+
+// Cluster contains configuration for the connections of the orderer to the
+// other orderers of its cluster.
+type Cluster struct {
+	DialTimeout time.Duration
+	// DNSRefreshInterval, if not zero, is the interval at which the host
+	// names of the orderers connected to are resolved again
+	DNSRefreshInterval time.Duration
+}
+
+// ContentValidator contains configuration for the plugin validating the content
+// of the messages received by Broadcast.
+type ContentValidator struct {
+	Library string
+}
+
+// Checkpoint contains configuration for the signed checkpoints embedded in
+// the metadata of blocks.
+type Checkpoint struct {
+	Interval uint64
+}
+
+// Tracing contains configuration for the tracing of the requests served by
+// the orderer.
+type Tracing struct {
+	Enabled       bool
+	ServiceName   string
+	Exporter      string
+	SampleRate    float64
+	BatchSize     int
+	FlushInterval time.Duration
+	OTLP          TracingOTLP
+}
+
+// TracingOTLP contains configuration for the export of the spans to an
+// OTLP/HTTP endpoint.
+type TracingOTLP struct {
+	Endpoint string
+	Timeout  time.Duration
 }
 
 // Profile contains configuration for Go pprof profiling.
 type Profile struct {
-	Enabled bool
-	Address string
+	Enabled              bool
+	Address              string
+	BlockProfileRate     int
+	MutexProfileFraction int
+	TLS                  ProfileTLS
+}
+
+// ProfileTLS contains configuration for the TLS listener of the profiling
+// service, which authenticates the clients by their certificate.
+type ProfileTLS struct {
+	Enabled       bool
+	PrivateKey    string
+	Certificate   string
+	ClientRootCAs []string
 }
 
 // FileLedger contains configuration for the file-based ledger.
@@ -112,6 +199,14 @@ type Kafka struct {
 	TLS       TLS
 	SASLPlain SASLPlain
 	Topic     Topic
+	// DNSRefreshInterval, if not zero, is the interval at which the host
+	// names of the brokers connected to are resolved again
+	DNSRefreshInterval time.Duration
+	// TransactionalID, if set, makes the orderer post the messages of every
+	// channel in Kafka transactions, under a transactional ID made of it and
+	// of the channel. It must be unique to the orderer, and stay the same
+	// across its restarts.
+	TransactionalID string
 }
 
 //JCS: BFTsmart contains configuration for the BFT-SMaRt orderer
@@ -174,6 +269,30 @@ type Debug struct {
 	DeliverTraceDir   string
 }
 
+// Metrics contains configuration for the reporting of the metrics of the
+// orderer.
+type Metrics struct {
+	Enabled        bool
+	Reporter       string
+	Interval       time.Duration
+	StatsdReporter StatsdReporter
+	PromReporter   PromReporter
+}
+
+// StatsdReporter contains configuration for the push of the metrics to a
+// statsd server.
+type StatsdReporter struct {
+	Address       string
+	FlushInterval time.Duration
+	FlushBytes    int
+}
+
+// PromReporter contains configuration for the prometheus server from which
+// the metrics are pulled.
+type PromReporter struct {
+	ListenAddress string
+}
+
 // Defaults carries the default orderer configuration values.
 var Defaults = TopLevel{
 	General: General{
@@ -186,7 +305,7 @@ var Defaults = TopLevel{
 		GenesisFile:    "genesisblock",
 		Profile: Profile{
 			Enabled: false,
-			Address: "0.0.0.0:6060",
+			Address: "127.0.0.1:6060",
 		},
 		LogLevel:    "INFO",
 		LogFormat:   "%{color}%{time:2006-01-02 15:04:05.000 MST} [%{module}] %{shortfunc} -> %{level:.4s} %{id:03x}%{color:reset} %{message}",
@@ -195,6 +314,31 @@ var Defaults = TopLevel{
 		BCCSP:       bccsp.GetDefaultOpts(),
 		Authentication: Authentication{
 			TimeWindow: time.Duration(15 * time.Minute),
+			ReplayProtection: ReplayProtection{
+				Enabled:      false,
+				CacheSize:    100000,
+				MaxClockSkew: time.Minute,
+			},
+		},
+		Tracing: Tracing{
+			Enabled:     false,
+			ServiceName: "orderer",
+			Exporter:    "otlp",
+			SampleRate:  1,
+		},
+		ShutdownGracePeriod: 20 * time.Second,
+		Deliver: Deliver{
+			MaxStreamsPerIdentity: 0,
+			BlocksInFlight:        1,
+		},
+		CertificateExpiration: CertificateExpiration{
+			Enabled:       true,
+			CheckInterval: time.Hour,
+			WarnBefore:    30 * 24 * time.Hour,
+		},
+		Cluster: Cluster{
+			DialTimeout:        5 * time.Second,
+			DNSRefreshInterval: 0,
 		},
 	},
 	RAMLedger: RAMLedger{
@@ -245,6 +389,19 @@ var Defaults = TopLevel{
 		BroadcastTraceDir: "",
 		DeliverTraceDir:   "",
 	},
+	Metrics: Metrics{
+		Enabled:  false,
+		Reporter: "statsd",
+		Interval: time.Second,
+		StatsdReporter: StatsdReporter{
+			Address:       "0.0.0.0:8125",
+			FlushInterval: 2 * time.Second,
+			FlushBytes:    1432,
+		},
+		PromReporter: PromReporter{
+			ListenAddress: "0.0.0.0:8443",
+		},
+	},
 }
 
 // Load parses the orderer YAML file and environment, producing
@@ -266,10 +423,26 @@ func Load() (*TopLevel, error) {
 		return nil, fmt.Errorf("Error unmarshaling config into struct: %s", err)
 	}
 
+	if err := uconf.resolveSecrets(); err != nil {
+		return nil, fmt.Errorf("Error resolving secrets of config: %s", err)
+	}
+
 	uconf.completeInitialization(filepath.Dir(config.ConfigFileUsed()))
 	return &uconf, nil
 }
 
+// resolveSecrets replaces the credentials which reference a file or an
+// environment variable with their value
+func (c *TopLevel) resolveSecrets() error {
+	if err := secrets.ResolveInPlace(&c.Kafka.SASLPlain.User); err != nil {
+		return fmt.Errorf("Kafka.SASLPlain.User: %s", err)
+	}
+	if err := secrets.ResolveInPlace(&c.Kafka.SASLPlain.Password); err != nil {
+		return fmt.Errorf("Kafka.SASLPlain.Password: %s", err)
+	}
+	return nil
+}
+
 func (c *TopLevel) completeInitialization(configDir string) {
 	defer func() {
 		// Translate any paths
@@ -279,6 +452,9 @@ func (c *TopLevel) completeInitialization(configDir string) {
 		coreconfig.TranslatePathInPlace(configDir, &c.General.TLS.Certificate)
 		coreconfig.TranslatePathInPlace(configDir, &c.General.GenesisFile)
 		coreconfig.TranslatePathInPlace(configDir, &c.General.LocalMSPDir)
+		c.General.Profile.TLS.ClientRootCAs = translateCAs(configDir, c.General.Profile.TLS.ClientRootCAs)
+		coreconfig.TranslatePathInPlace(configDir, &c.General.Profile.TLS.PrivateKey)
+		coreconfig.TranslatePathInPlace(configDir, &c.General.Profile.TLS.Certificate)
 	}()
 
 	for {
@@ -326,6 +502,10 @@ func (c *TopLevel) completeInitialization(configDir string) {
 			logger.Infof("Profiling enabled and General.Profile.Address unset, setting to %s", Defaults.General.Profile.Address)
 			c.General.Profile.Address = Defaults.General.Profile.Address
 
+		case c.General.Tracing.Enabled && c.General.Tracing.ServiceName == "":
+			logger.Infof("Tracing enabled and General.Tracing.ServiceName unset, setting to %s", Defaults.General.Tracing.ServiceName)
+			c.General.Tracing.ServiceName = Defaults.General.Tracing.ServiceName
+
 		case c.General.LocalMSPDir == "":
 			logger.Infof("General.LocalMSPDir unset, setting to %s", Defaults.General.LocalMSPDir)
 			c.General.LocalMSPDir = Defaults.General.LocalMSPDir
@@ -333,9 +513,39 @@ func (c *TopLevel) completeInitialization(configDir string) {
 			logger.Infof("General.LocalMSPID unset, setting to %s", Defaults.General.LocalMSPID)
 			c.General.LocalMSPID = Defaults.General.LocalMSPID
 
+		case c.General.ShutdownGracePeriod == 0:
+			logger.Infof("General.ShutdownGracePeriod unset, setting to %s", Defaults.General.ShutdownGracePeriod)
+			c.General.ShutdownGracePeriod = Defaults.General.ShutdownGracePeriod
+		case c.General.Deliver.BlocksInFlight == 0:
+			logger.Infof("General.Deliver.BlocksInFlight unset, setting to %d", Defaults.General.Deliver.BlocksInFlight)
+			c.General.Deliver.BlocksInFlight = Defaults.General.Deliver.BlocksInFlight
+		case c.General.Cluster.DialTimeout == 0:
+			logger.Infof("General.Cluster.DialTimeout unset, setting to %s", Defaults.General.Cluster.DialTimeout)
+			c.General.Cluster.DialTimeout = Defaults.General.Cluster.DialTimeout
+		case c.General.Cluster.DNSRefreshInterval < 0:
+			logger.Panicf("General.Cluster.DNSRefreshInterval must not be negative")
+		case c.General.CertificateExpiration.Enabled && c.General.CertificateExpiration.CheckInterval == 0:
+			logger.Infof("Certificate expiration monitoring enabled and General.CertificateExpiration.CheckInterval unset, setting to %s", Defaults.General.CertificateExpiration.CheckInterval)
+			c.General.CertificateExpiration.CheckInterval = Defaults.General.CertificateExpiration.CheckInterval
+		case c.General.CertificateExpiration.Enabled && c.General.CertificateExpiration.WarnBefore == 0:
+			logger.Infof("Certificate expiration monitoring enabled and General.CertificateExpiration.WarnBefore unset, setting to %s", Defaults.General.CertificateExpiration.WarnBefore)
+			c.General.CertificateExpiration.WarnBefore = Defaults.General.CertificateExpiration.WarnBefore
 		case c.General.Authentication.TimeWindow == 0:
 			logger.Infof("General.Authentication.TimeWindow unset, setting to %s", Defaults.General.Authentication.TimeWindow)
 			c.General.Authentication.TimeWindow = Defaults.General.Authentication.TimeWindow
+		case c.General.Authentication.ReplayProtection.Enabled && c.General.Authentication.ReplayProtection.CacheSize == 0:
+			logger.Infof("Replay protection enabled and General.Authentication.ReplayProtection.CacheSize unset, setting to %d", Defaults.General.Authentication.ReplayProtection.CacheSize)
+			c.General.Authentication.ReplayProtection.CacheSize = Defaults.General.Authentication.ReplayProtection.CacheSize
+		case c.General.Authentication.ReplayProtection.Enabled && c.General.Authentication.ReplayProtection.MaxClockSkew == 0:
+			logger.Infof("Replay protection enabled and General.Authentication.ReplayProtection.MaxClockSkew unset, setting to %s", Defaults.General.Authentication.ReplayProtection.MaxClockSkew)
+			c.General.Authentication.ReplayProtection.MaxClockSkew = Defaults.General.Authentication.ReplayProtection.MaxClockSkew
+
+		case c.Metrics.Enabled && c.Metrics.Reporter == "":
+			logger.Infof("Metrics enabled and Metrics.Reporter unset, setting to %s", Defaults.Metrics.Reporter)
+			c.Metrics.Reporter = Defaults.Metrics.Reporter
+		case c.Metrics.Enabled && c.Metrics.Interval == 0:
+			logger.Infof("Metrics enabled and Metrics.Interval unset, setting to %s", Defaults.Metrics.Interval)
+			c.Metrics.Interval = Defaults.Metrics.Interval
 
 		case c.FileLedger.Prefix == "":
 			logger.Infof("FileLedger.Prefix unset, setting to %s", Defaults.FileLedger.Prefix)
@@ -385,6 +595,8 @@ func (c *TopLevel) completeInitialization(configDir string) {
 		case c.Kafka.Version == sarama.KafkaVersion{}:
 			logger.Infof("Kafka.Version unset, setting to %v", Defaults.Kafka.Version)
 			c.Kafka.Version = Defaults.Kafka.Version
+		case c.Kafka.TransactionalID != "" && !c.Kafka.Version.IsAtLeast(sarama.V0_11_0_0):
+			logger.Panicf("Kafka.Version must be at least 0.11.0.0 if Kafka.TransactionalID is set.")
 
 			//JCS: BFT-SMaRt parameters
 		case c.BFTsmart.ConnectionPoolSize == 0:
@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Shopify/sarama"
 	"github.com/hyperledger/fabric/core/config/configtest"
 	"github.com/stretchr/testify/assert"
 )
@@ -131,6 +132,54 @@ func TestKafkaSASLPlain(t *testing.T) {
 	}
 }
 
+func TestKafkaTransactionalID(t *testing.T) {
+	testCases := []struct {
+		name        string
+		kafka       Kafka
+		shouldPanic bool
+	}{
+		{"Unset", Kafka{}, false},
+		{"DefaultVersion", Kafka{TransactionalID: "orderer0"}, true},
+		{"Version0_11", Kafka{TransactionalID: "orderer0", Version: sarama.V0_11_0_0}, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uconf := &TopLevel{Kafka: tc.kafka}
+			if tc.shouldPanic {
+				assert.Panics(t, func() { uconf.completeInitialization("/dummy/path") }, "Should panic")
+			} else {
+				assert.NotPanics(t, func() { uconf.completeInitialization("/dummy/path") }, "Should not panic")
+			}
+		})
+	}
+}
+
+func TestKafkaSASLPlainSecrets(t *testing.T) {
+	name, err := ioutil.TempDir("", "hyperledger_fabric")
+	assert.Nil(t, err, "Error creating temp dir: %s", err)
+	defer os.RemoveAll(name)
+	passwordFile := filepath.Join(name, "password")
+	assert.NoError(t, ioutil.WriteFile(passwordFile, []byte("pwd\n"), 0600))
+
+	os.Setenv("ORDERER_KAFKA_SASLPLAIN_USER", "env://SASL_TEST_USER")
+	defer os.Unsetenv("ORDERER_KAFKA_SASLPLAIN_USER")
+	os.Setenv("ORDERER_KAFKA_SASLPLAIN_PASSWORD", "file://"+passwordFile)
+	defer os.Unsetenv("ORDERER_KAFKA_SASLPLAIN_PASSWORD")
+	cleanup := configtest.SetDevFabricConfigPath(t)
+	defer cleanup()
+
+	_, err = Load()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Kafka.SASLPlain.User")
+
+	os.Setenv("SASL_TEST_USER", "user")
+	defer os.Unsetenv("SASL_TEST_USER")
+	config, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "user", config.Kafka.SASLPlain.User)
+	assert.Equal(t, "pwd", config.Kafka.SASLPlain.Password)
+}
+
 func TestSystemChannel(t *testing.T) {
 	cleanup := configtest.SetDevFabricConfigPath(t)
 	defer cleanup()
@@ -138,3 +187,18 @@ func TestSystemChannel(t *testing.T) {
 	assert.Equal(t, Defaults.General.SystemChannel, conf.General.SystemChannel,
 		"Expected default system channel ID to be '%s', got '%s' instead", Defaults.General.SystemChannel, conf.General.SystemChannel)
 }
+
+func TestReplayProtectionCacheSize(t *testing.T) {
+	uconf := &TopLevel{General: General{Authentication: Authentication{ReplayProtection: ReplayProtection{Enabled: true}}}}
+	uconf.completeInitialization("/dummy/path")
+	assert.Equal(t, Defaults.General.Authentication.ReplayProtection.CacheSize, uconf.General.Authentication.ReplayProtection.CacheSize)
+	assert.Equal(t, Defaults.General.Authentication.ReplayProtection.MaxClockSkew, uconf.General.Authentication.ReplayProtection.MaxClockSkew)
+
+	cleanup := configtest.SetDevFabricConfigPath(t)
+	defer cleanup()
+	conf, err := Load()
+	assert.NoError(t, err)
+	assert.False(t, conf.General.Authentication.ReplayProtection.Enabled)
+	assert.Equal(t, 100000, conf.General.Authentication.ReplayProtection.CacheSize)
+	assert.Equal(t, time.Minute, conf.General.Authentication.ReplayProtection.MaxClockSkew)
+}
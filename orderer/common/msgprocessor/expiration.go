@@ -12,6 +12,7 @@ import (
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
 )
 
@@ -19,16 +20,20 @@ type resources interface {
 	// OrdererConfig returns the config.Orderer for the channel
 	// and whether the Orderer config exists
 	OrdererConfig() (channelconfig.Orderer, bool)
+
+	// ChannelConfig returns the config.Channel for the channel
+	ChannelConfig() channelconfig.Channel
 }
 
 // NewExpirationRejectRule returns a rule that rejects messages signed by identities
 // who's identities have expired, given the capability is active
 func NewExpirationRejectRule(filterSupport resources) Rule {
-	return &expirationRejectRule{filterSupport: filterSupport}
+	return &expirationRejectRule{filterSupport: filterSupport, now: time.Now}
 }
 
 type expirationRejectRule struct {
 	filterSupport resources
+	now           func() time.Time
 }
 
 // Apply checks whether the identity that created the envelope has expired
@@ -45,10 +50,48 @@ func (exp *expirationRejectRule) Apply(message *common.Envelope) error {
 	if err != nil {
 		return errors.Errorf("could not convert message to signedData: %s", err)
 	}
+	checkTime, err := exp.checkTime(message)
+	if err != nil {
+		return err
+	}
 	expirationTime := crypto.ExpiresAt(signedData[0].Identity)
 	// Identity cannot expire, or identity has not expired yet
-	if expirationTime.IsZero() || time.Now().Before(expirationTime) {
+	if expirationTime.IsZero() || checkTime.Before(expirationTime) {
 		return nil
 	}
 	return errors.New("identity expired")
 }
+
+// checkTime returns the time at which the identity that created the envelope
+// must not have expired. It is now, unless the channel capability is active:
+// then it is the timestamp of the envelope, when it was endorsed, if it is
+// earlier than now. The timestamp is anchored to the clock of the orderer,
+// which rejects the envelopes whose timestamp is further from now than the
+// endorsement max skew of the channel, so that the peers committing the
+// envelope, checking it against its timestamp, bound it as well.
+func (exp *expirationRejectRule) checkTime(message *common.Envelope) (time.Time, error) {
+	now := exp.now()
+	channelConf := exp.filterSupport.ChannelConfig()
+	if !channelConf.Capabilities().EndorsementTimeExpiration() {
+		return now, nil
+	}
+	chdr, err := utils.ChannelHeader(message)
+	if err != nil {
+		return time.Time{}, errors.Errorf("could not get the channel header: %s", err)
+	}
+	if chdr.Timestamp == nil {
+		return time.Time{}, errors.New("timestamp is missing")
+	}
+	timestamp := time.Unix(chdr.Timestamp.Seconds, int64(chdr.Timestamp.Nanos))
+	maxSkew := channelConf.EndorsementMaxSkew()
+	if timestamp.Before(now.Add(-maxSkew)) {
+		return time.Time{}, errors.Errorf("timestamp %s is older than the endorsement max skew %s", timestamp, maxSkew)
+	}
+	if timestamp.After(now.Add(maxSkew)) {
+		return time.Time{}, errors.Errorf("timestamp %s is later than the endorsement max skew %s", timestamp, maxSkew)
+	}
+	if timestamp.After(now) {
+		return now, nil
+	}
+	return timestamp, nil
+}
@@ -10,9 +10,12 @@ import (
 	"io/ioutil"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/mocks/config"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/msp"
@@ -22,8 +25,12 @@ import (
 )
 
 func createEnvelope(t *testing.T, serializedIdentity []byte) *common.Envelope {
+	return createEnvelopeAt(t, serializedIdentity, nil)
+}
+
+func createEnvelopeAt(t *testing.T, serializedIdentity []byte, timestamp *timestamp.Timestamp) *common.Envelope {
 	sHdr := utils.MakeSignatureHeader(serializedIdentity, nil)
-	hdr := utils.MakePayloadHeader(&common.ChannelHeader{}, sHdr)
+	hdr := utils.MakePayloadHeader(&common.ChannelHeader{ChannelId: "foo", Timestamp: timestamp}, sHdr)
 	payload := &common.Payload{
 		Header: hdr,
 	}
@@ -64,6 +71,14 @@ func createIdemixIdentity(t *testing.T) []byte {
 
 type resourcesMock struct {
 	mock.Mock
+	channelConfig channelconfig.Channel
+}
+
+func (r *resourcesMock) ChannelConfig() channelconfig.Channel {
+	if r.channelConfig == nil {
+		return &config.Channel{CapabilitiesVal: &config.ChannelCapabilities{}}
+	}
+	return r.channelConfig
 }
 
 func (r *resourcesMock) OrdererConfig() (channelconfig.Orderer, bool) {
@@ -126,3 +141,53 @@ func TestExpirationRejectRule(t *testing.T) {
 		assert.Nil(t, NewExpirationRejectRule(resources).Apply(env))
 	})
 }
+
+func TestExpirationRejectRuleAtEndorsementTime(t *testing.T) {
+	activeCapability := &config.Orderer{CapabilitiesVal: &config.OrdererCapabilities{
+		ExpirationVal: true,
+	}}
+	resources := &resourcesMock{channelConfig: &config.Channel{
+		EndorsementMaxSkewVal: 15 * time.Minute,
+		CapabilitiesVal: &config.ChannelCapabilities{
+			EndorsementTimeExpirationVal: true,
+		},
+	}}
+	resources.On("OrdererConfig").Return(activeCapability, true)
+
+	identity := createX509Identity(t, "expiredCert.pem")
+	expiration := crypto.ExpiresAt(identity)
+	at := func(t time.Time) *timestamp.Timestamp {
+		return &timestamp.Timestamp{Seconds: t.Unix()}
+	}
+	ruleAt := func(now time.Time) Rule {
+		rule := NewExpirationRejectRule(resources).(*expirationRejectRule)
+		rule.now = func() time.Time { return now }
+		return rule
+	}
+
+	// endorsed before the identity expired, and ordered within the max skew
+	env := createEnvelopeAt(t, identity, at(expiration.Add(-time.Minute)))
+	assert.NoError(t, ruleAt(expiration.Add(10*time.Minute)).Apply(env))
+
+	// endorsed before the identity expired, but older than the max skew
+	err := ruleAt(expiration.Add(time.Hour)).Apply(env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "is older than the endorsement max skew 15m0s")
+
+	// endorsed after the identity expired
+	env = createEnvelopeAt(t, identity, at(expiration.Add(time.Minute)))
+	assert.EqualError(t, ruleAt(expiration.Add(2*time.Minute)).Apply(env), "identity expired")
+
+	// without a timestamp
+	env = createEnvelope(t, identity)
+	assert.EqualError(t, ruleAt(expiration.Add(-time.Hour)).Apply(env), "timestamp is missing")
+
+	// a timestamp later than now, within the max skew, is checked as now
+	env = createEnvelopeAt(t, identity, at(expiration.Add(time.Minute)))
+	assert.NoError(t, ruleAt(expiration.Add(-time.Minute)).Apply(env))
+
+	// a timestamp later than the max skew
+	err = ruleAt(expiration.Add(-time.Hour)).Apply(env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "is later than the endorsement max skew 15m0s")
+}
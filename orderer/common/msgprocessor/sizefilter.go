@@ -9,6 +9,7 @@ package msgprocessor
 import (
 	"fmt"
 
+	"github.com/hyperledger/fabric/common/channelconfig"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 )
@@ -16,6 +17,7 @@ import (
 // Support defines the subset of the channel support required to create this filter
 type Support interface {
 	BatchSize() *ab.BatchSize
+	Capabilities() channelconfig.OrdererCapabilities
 }
 
 // New creates a size filter which rejects messages larger than maxBytes
@@ -28,9 +30,14 @@ type MaxBytesRule struct {
 	support Support
 }
 
-// Apply returns an error if the message exceeds the configured absolute max batch size.
+// Apply returns an error if the message exceeds the configured absolute max batch size,
+// or the configured max transaction size if it is enforced and lower.
 func (r *MaxBytesRule) Apply(message *cb.Envelope) error {
-	maxBytes := r.support.BatchSize().AbsoluteMaxBytes
+	batchSize := r.support.BatchSize()
+	maxBytes := batchSize.AbsoluteMaxBytes
+	if r.support.Capabilities().TransactionSizeLimit() && batchSize.MaxTransactionBytes != 0 && batchSize.MaxTransactionBytes < maxBytes {
+		maxBytes = batchSize.MaxTransactionBytes
+	}
 	if size := messageByteSize(message); size > maxBytes {
 		return fmt.Errorf("message payload is %d bytes and exceeds maximum allowed %d bytes", size, maxBytes)
 	}
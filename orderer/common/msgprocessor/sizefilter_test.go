@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package msgprocessor
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
@@ -20,7 +21,10 @@ import (
 func TestMaxBytesRule(t *testing.T) {
 	dataSize := uint32(100)
 	maxBytes := calcMessageBytesForPayloadDataSize(dataSize)
-	msf := NewSizeFilter(&mockconfig.Orderer{BatchSizeVal: &ab.BatchSize{AbsoluteMaxBytes: maxBytes}})
+	msf := NewSizeFilter(&mockconfig.Orderer{
+		BatchSizeVal:    &ab.BatchSize{AbsoluteMaxBytes: maxBytes},
+		CapabilitiesVal: &mockconfig.OrdererCapabilities{},
+	})
 
 	t.Run("LessThan", func(t *testing.T) {
 		assert.Nil(t, msf.Apply(makeMessage(make([]byte, dataSize-1))))
@@ -33,6 +37,29 @@ func TestMaxBytesRule(t *testing.T) {
 	})
 }
 
+func TestMaxTransactionBytesRule(t *testing.T) {
+	dataSize := uint32(100)
+	maxTransactionBytes := calcMessageBytesForPayloadDataSize(dataSize)
+	capabilities := &mockconfig.OrdererCapabilities{TransactionSizeLimitVal: true}
+	msf := NewSizeFilter(&mockconfig.Orderer{
+		BatchSizeVal:    &ab.BatchSize{AbsoluteMaxBytes: 2 * maxTransactionBytes, MaxTransactionBytes: maxTransactionBytes},
+		CapabilitiesVal: capabilities,
+	})
+
+	t.Run("Exact", func(t *testing.T) {
+		assert.Nil(t, msf.Apply(makeMessage(make([]byte, dataSize))))
+	})
+	t.Run("TooBig", func(t *testing.T) {
+		err := msf.Apply(makeMessage(make([]byte, dataSize+1)))
+		assert.EqualError(t, err, fmt.Sprintf("message payload is %d bytes and exceeds maximum allowed %d bytes", maxTransactionBytes+1, maxTransactionBytes))
+	})
+	t.Run("NotEnforced", func(t *testing.T) {
+		capabilities.TransactionSizeLimitVal = false
+		defer func() { capabilities.TransactionSizeLimitVal = true }()
+		assert.Nil(t, msf.Apply(makeMessage(make([]byte, dataSize+1))))
+	})
+}
+
 func calcMessageBytesForPayloadDataSize(dataSize uint32) uint32 {
 	return messageByteSize(makeMessage(make([]byte, dataSize)))
 }
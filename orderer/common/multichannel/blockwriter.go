@@ -39,6 +39,10 @@ type BlockWriter struct {
 	lastConfigSeq      uint64
 	lastBlock          *cb.Block
 	committingBlock    sync.Mutex
+
+	checkpointInterval uint64
+	lastCheckpointNum  uint64
+	lastCheckpointHash []byte
 }
 
 func newBlockWriter(lastBlock *cb.Block, r *Registrar, support blockWriterSupport) *BlockWriter {
@@ -59,10 +63,58 @@ func newBlockWriter(lastBlock *cb.Block, r *Registrar, support blockWriterSuppor
 		}
 	}
 
+	if r != nil && r.checkpointInterval > 0 {
+		bw.checkpointInterval = r.checkpointInterval
+		bw.initLastCheckpoint(lastBlock)
+	}
+
 	logger.Debugf("[channel: %s] Creating block writer for tip of chain (blockNumber=%d, lastConfigBlockNum=%d, lastConfigSeq=%d)", support.ChainID(), lastBlock.Header.Number, bw.lastConfigBlockNum, bw.lastConfigSeq)
 	return bw
 }
 
+// initLastCheckpoint locates the block carrying the last checkpoint of the chain,
+// which is either the last config block or the last block whose number is a
+// multiple of the checkpoint interval. If that block carries no checkpoint, as
+// happens when checkpoints were just enabled or their interval changed, the next
+// checkpoint links to the genesis block instead.
+func (bw *BlockWriter) initLastCheckpoint(lastBlock *cb.Block) {
+	lastBlockNum := lastBlock.Header.Number
+	candidate := lastBlockNum - lastBlockNum%bw.checkpointInterval
+	if bw.lastConfigBlockNum > candidate {
+		candidate = bw.lastConfigBlockNum
+	}
+
+	if candidate != 0 {
+		block := lastBlock
+		if candidate != lastBlockNum {
+			block = blockledger.GetBlock(bw.support, candidate)
+		}
+		if block != nil {
+			checkpoint, err := utils.GetCheckpointFromBlock(block)
+			if err != nil {
+				logger.Warningf("[channel: %s] Error extracting checkpoint from block %d: %s", bw.support.ChainID(), candidate, err)
+			}
+			if checkpoint != nil {
+				bw.lastCheckpointNum = candidate
+				bw.lastCheckpointHash = block.Header.Hash()
+				logger.Debugf("[channel: %s] Last checkpoint is in block %d", bw.support.ChainID(), candidate)
+				return
+			}
+		}
+	}
+
+	genesisBlock := lastBlock
+	if lastBlockNum != 0 {
+		genesisBlock = blockledger.GetBlock(bw.support, 0)
+		if genesisBlock == nil {
+			logger.Panicf("[channel: %s] Error retrieving the genesis block", bw.support.ChainID())
+		}
+	}
+	bw.lastCheckpointNum = 0
+	bw.lastCheckpointHash = genesisBlock.Header.Hash()
+	logger.Debugf("[channel: %s] No checkpoint found, the next checkpoint will link to the genesis block", bw.support.ChainID())
+}
+
 //JCS: my own method
 func (bw *BlockWriter) GetLastBlock() *cb.Block {
 	return bw.lastBlock
@@ -188,6 +240,7 @@ func (bw *BlockWriter) commitBlock(encodedMetadataValue []byte) {
 	}
 	bw.addBlockSignature(bw.lastBlock)
 	bw.addLastConfigSignature(bw.lastBlock)
+	bw.addCheckpoint(bw.lastBlock)
 
 	err := bw.support.Append(bw.lastBlock)
 	if err != nil {
@@ -239,3 +292,38 @@ func (bw *BlockWriter) addLastConfigSignature(block *cb.Block) {
 		},
 	})
 }
+
+// addCheckpoint embeds a signed checkpoint in config blocks and in the blocks whose
+// number is a multiple of the checkpoint interval. It must be invoked after
+// addLastConfigSignature so that the last config block number is up to date.
+func (bw *BlockWriter) addCheckpoint(block *cb.Block) {
+	if bw.checkpointInterval == 0 {
+		return
+	}
+	if block.Header.Number%bw.checkpointInterval != 0 && block.Header.Number != bw.lastConfigBlockNum {
+		return
+	}
+
+	checkpointSignature := &cb.MetadataSignature{
+		SignatureHeader: utils.MarshalOrPanic(utils.NewSignatureHeaderOrPanic(bw.support)),
+	}
+
+	checkpointValue := utils.MarshalOrPanic(&cb.Checkpoint{
+		LastConfig:             bw.lastConfigBlockNum,
+		PreviousCheckpoint:     bw.lastCheckpointNum,
+		PreviousCheckpointHash: bw.lastCheckpointHash,
+	})
+	logger.Debugf("[channel: %s] About to write block, setting its CHECKPOINT with previous checkpoint %d", bw.support.ChainID(), bw.lastCheckpointNum)
+
+	checkpointSignature.Signature = utils.SignOrPanic(bw.support, util.ConcatenateBytes(checkpointValue, checkpointSignature.SignatureHeader, block.Header.Bytes()))
+
+	block.Metadata.Metadata[cb.BlockMetadataIndex_CHECKPOINT] = utils.MarshalOrPanic(&cb.Metadata{
+		Value: checkpointValue,
+		Signatures: []*cb.MetadataSignature{
+			checkpointSignature,
+		},
+	})
+
+	bw.lastCheckpointNum = block.Header.Number
+	bw.lastCheckpointHash = block.Header.Hash()
+}
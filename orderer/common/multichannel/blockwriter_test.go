@@ -20,9 +20,11 @@ import (
 	"testing"
 
 	newchannelconfig "github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/checkpoint"
 	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	mockconfigtx "github.com/hyperledger/fabric/common/mocks/configtx"
+	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
 	cb "github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -99,6 +101,98 @@ func TestBlockLastConfig(t *testing.T) {
 	assert.Equal(t, newBlockNum, lc)
 }
 
+func TestBlockCheckpoint(t *testing.T) {
+	bw := &BlockWriter{
+		support: &mockBlockWriterSupport{
+			LocalSigner: mockCrypto(),
+			Validator:   &mockconfigtx.Validator{},
+		},
+		checkpointInterval: 10,
+		lastConfigBlockNum: 3,
+		lastCheckpointNum:  3,
+		lastCheckpointHash: []byte("hash"),
+	}
+
+	block := cb.NewBlock(9, []byte("foo"))
+	bw.addCheckpoint(block)
+	cp, err := utils.GetCheckpointFromBlock(block)
+	assert.NoError(t, err)
+	assert.Nil(t, cp, "Block 9 should not carry a checkpoint")
+
+	block = cb.NewBlock(10, []byte("foo"))
+	bw.addCheckpoint(block)
+	md := utils.GetMetadataFromBlockOrPanic(block, cb.BlockMetadataIndex_CHECKPOINT)
+	assert.NotNil(t, md.Signatures, "Should have signature")
+	cp, err = utils.GetCheckpointFromBlock(block)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), cp.LastConfig)
+	assert.Equal(t, uint64(3), cp.PreviousCheckpoint)
+	assert.Equal(t, []byte("hash"), cp.PreviousCheckpointHash)
+	assert.Equal(t, uint64(10), bw.lastCheckpointNum)
+	assert.Equal(t, block.Header.Hash(), bw.lastCheckpointHash)
+
+	// config blocks always carry a checkpoint
+	bw.lastConfigBlockNum = 13
+	config := cb.NewBlock(13, []byte("foo"))
+	bw.addCheckpoint(config)
+	cp, err = utils.GetCheckpointFromBlock(config)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(13), cp.LastConfig)
+	assert.Equal(t, uint64(10), cp.PreviousCheckpoint)
+	assert.Equal(t, block.Header.Hash(), cp.PreviousCheckpointHash)
+
+	// checkpoints are disabled
+	bw.checkpointInterval = 0
+	block = cb.NewBlock(20, []byte("foo"))
+	bw.addCheckpoint(block)
+	cp, err = utils.GetCheckpointFromBlock(block)
+	assert.NoError(t, err)
+	assert.Nil(t, cp, "Checkpoints should be disabled")
+}
+
+func TestCheckpointChain(t *testing.T) {
+	l := NewRAMLedger(10)
+	support := &mockBlockWriterSupport{
+		LocalSigner: mockCrypto(),
+		ReadWriter:  l,
+		Validator:   &mockconfigtx.Validator{},
+	}
+	writeBlocks := func(bw *BlockWriter, count int) {
+		for i := 0; i < count; i++ {
+			bw.WriteBlock(bw.CreateNextBlock(nil), nil)
+		}
+		// Wait for the commit to complete
+		bw.committingBlock.Lock()
+		bw.committingBlock.Unlock()
+	}
+
+	bw := newBlockWriter(genesisBlock, &Registrar{checkpointInterval: 2}, support)
+	assert.Equal(t, uint64(0), bw.lastCheckpointNum)
+	assert.Equal(t, genesisBlock.Header.Hash(), bw.lastCheckpointHash)
+	writeBlocks(bw, 5)
+
+	previous := blockledger.GetBlock(l, 0)
+	for _, n := range []uint64{2, 4} {
+		block := blockledger.GetBlock(l, n)
+		_, err := checkpoint.Verify(block, previous, &mockpolicies.Policy{})
+		assert.NoError(t, err, "Checkpoint of block %d should be valid", n)
+		previous = block
+	}
+
+	// the block writer of a restarted orderer follows the existing checkpoints
+	bw = newBlockWriter(blockledger.GetBlock(l, 5), &Registrar{checkpointInterval: 2}, support)
+	assert.Equal(t, uint64(4), bw.lastCheckpointNum)
+	assert.Equal(t, previous.Header.Hash(), bw.lastCheckpointHash)
+	writeBlocks(bw, 1)
+	_, err := checkpoint.Verify(blockledger.GetBlock(l, 6), previous, &mockpolicies.Policy{})
+	assert.NoError(t, err)
+
+	// the checkpoints change interval and restart from the genesis block
+	bw = newBlockWriter(blockledger.GetBlock(l, 6), &Registrar{checkpointInterval: 5}, support)
+	assert.Equal(t, uint64(0), bw.lastCheckpointNum)
+	assert.Equal(t, genesisBlock.Header.Hash(), bw.lastCheckpointHash)
+}
+
 func TestWriteConfigBlock(t *testing.T) {
 	// TODO, use assert.PanicsWithValue once available
 	t.Run("EmptyBlock", func(t *testing.T) {
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package multichannel
 
 import (
+	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
@@ -113,7 +114,20 @@ func (cs *ChainSupport) ProposeConfigUpdate(configtx *cb.Envelope) (*cb.ConfigEn
 		return nil, errors.Wrap(err, "config update is not compatible")
 	}
 
-	return env, cs.ValidateNew(bundle)
+	if err = cs.ValidateNew(bundle); err != nil {
+		return nil, err
+	}
+
+	// Unsatisfiable policies are only reported until the V1_4 orderer capability
+	// is required, so that all the orderers of the channel agree on the update
+	if err = channelconfig.ValidateNewPolicies(cs, bundle); err != nil {
+		if cs.SharedConfig().Capabilities().UnsatisfiablePoliciesCheck() {
+			return nil, err
+		}
+		logger.Warningf("[channel: %s] Accepting config update although %s", cs.ChainID(), err)
+	}
+
+	return env, nil
 }
 
 // ChainID passes through to the underlying configtx.Validator
@@ -126,6 +140,12 @@ func (cs *ChainSupport) ConfigProto() *cb.Config {
 	return cs.ConfigtxValidator().ConfigProto()
 }
 
+// DeliverIdentityBinding returns whether the deliver requests of the channel must be
+// bound to the TLS client certificate of their connection
+func (cs *ChainSupport) DeliverIdentityBinding() bool {
+	return cs.SharedConfig().Capabilities().DeliverIdentityBinding()
+}
+
 // Sequence passes through to the underlying configtx.Validator
 func (cs *ChainSupport) Sequence() uint64 {
 	return cs.ConfigtxValidator().Sequence()
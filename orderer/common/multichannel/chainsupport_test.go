@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multichannel
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/capabilities"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/deliver"
+	mockconfigtx "github.com/hyperledger/fabric/common/mocks/configtx"
+	"github.com/hyperledger/fabric/common/tools/configtxgen/configtxgentest"
+	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
+	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// proposingResources are the resources of a channel whose validator
+// proposes a fixed config
+type proposingResources struct {
+	*channelconfig.Bundle
+	validator configtx.Validator
+}
+
+func (pr *proposingResources) ConfigtxValidator() configtx.Validator {
+	return pr.validator
+}
+
+func (pr *proposingResources) Update(*channelconfig.Bundle) {}
+
+func TestProposeConfigUpdateUnsatisfiablePolicies(t *testing.T) {
+	for _, v14 := range []bool{false, true} {
+		profile := configtxgentest.Load(genesisconfig.SampleSingleMSPSoloProfile)
+		profile.Orderer.Capabilities[capabilities.OrdererV1_4] = v14
+		channelGroup, err := encoder.NewChannelGroup(profile)
+		require.NoError(t, err)
+		config := &cb.Config{ChannelGroup: channelGroup}
+		current, err := channelconfig.NewBundle("foo", config)
+		require.NoError(t, err)
+
+		next := proto.Clone(config).(*cb.Config)
+		policy, err := cauthdsl.FromString("OR('OtherOrg.member')")
+		require.NoError(t, err)
+		next.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Policies["Other"] = &cb.ConfigPolicy{
+			ModPolicy: channelconfig.AdminsPolicyKey,
+			Policy:    &cb.Policy{Type: int32(cb.Policy_SIGNATURE), Value: utils.MarshalOrPanic(policy)},
+		}
+
+		cs := &ChainSupport{
+			ledgerResources: &ledgerResources{
+				configResources: &configResources{
+					mutableResources: &proposingResources{
+						Bundle: current,
+						validator: &mockconfigtx.Validator{
+							ChainIDVal:             "foo",
+							ProposeConfigUpdateVal: &cb.ConfigEnvelope{Config: next},
+						},
+					},
+				},
+			},
+		}
+
+		env, err := cs.ProposeConfigUpdate(&cb.Envelope{})
+		if v14 {
+			assert.EqualError(t, err, "policies can never be satisfied: /Channel/Orderer/Other: "+
+				"1 out of 1 rules are required, but 0 can be satisfied (rule 0: identity 0 cannot be satisfied: MSP OtherOrg is not defined)")
+			assert.Nil(t, env)
+		} else {
+			assert.NoError(t, err)
+			assert.Equal(t, next, env.Config)
+		}
+	}
+}
+
+func TestDeliverIdentityBinding(t *testing.T) {
+	for _, v142 := range []bool{false, true} {
+		profile := configtxgentest.Load(genesisconfig.SampleSingleMSPSoloProfile)
+		profile.Orderer.Capabilities[capabilities.OrdererV1_4_2] = v142
+		channelGroup, err := encoder.NewChannelGroup(profile)
+		require.NoError(t, err)
+		bundle, err := channelconfig.NewBundle("foo", &cb.Config{ChannelGroup: channelGroup})
+		require.NoError(t, err)
+
+		cs := &ChainSupport{
+			ledgerResources: &ledgerResources{
+				configResources: &configResources{
+					mutableResources: &proposingResources{Bundle: bundle},
+				},
+			},
+		}
+
+		var binder deliver.IdentityBinder = cs
+		assert.Equal(t, v142, binder.DeliverIdentityBinding())
+	}
+}
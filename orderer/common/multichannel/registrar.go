@@ -11,7 +11,10 @@ package multichannel
 
 import (
 	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/configtx"
@@ -102,6 +105,8 @@ type Registrar struct {
 	systemChannel   *ChainSupport
 	templator       msgprocessor.ChannelConfigTemplator
 	callbacks       []func(bundle *channelconfig.Bundle)
+
+	checkpointInterval uint64
 }
 
 func getConfigTx(reader blockledger.Reader) *cb.Envelope {
@@ -120,13 +125,14 @@ func getConfigTx(reader blockledger.Reader) *cb.Envelope {
 
 // NewRegistrar produces an instance of a *Registrar.
 func NewRegistrar(ledgerFactory blockledger.Factory, consenters map[string]consensus.Consenter,
-	signer crypto.LocalSigner, callbacks ...func(bundle *channelconfig.Bundle)) *Registrar {
+	signer crypto.LocalSigner, checkpointInterval uint64, callbacks ...func(bundle *channelconfig.Bundle)) *Registrar {
 	r := &Registrar{
-		chains:        make(map[string]*ChainSupport),
-		ledgerFactory: ledgerFactory,
-		consenters:    consenters,
-		signer:        signer,
-		callbacks:     callbacks,
+		chains:             make(map[string]*ChainSupport),
+		ledgerFactory:      ledgerFactory,
+		consenters:         consenters,
+		signer:             signer,
+		callbacks:          callbacks,
+		checkpointInterval: checkpointInterval,
 	}
 
 	existingChains := ledgerFactory.ChainIDs()
@@ -303,6 +309,65 @@ func (r *Registrar) ChannelsCount() int {
 	return len(r.chains)
 }
 
+// ChannelIDs returns the IDs of the channels of the orderer, sorted.
+func (r *Registrar) ChannelIDs() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	channelIDs := make([]string, 0, len(r.chains))
+	for channelID := range r.chains {
+		channelIDs = append(channelIDs, channelID)
+	}
+	sort.Strings(channelIDs)
+	return channelIDs
+}
+
+// Drain lets the chains complete the work they have pending before the orderer
+// shuts down, concurrently. The chains which don't implement consensus.Drainer
+// are skipped, an error is returned if any chain could not be drained within
+// the timeout.
+func (r *Registrar) Drain(timeout time.Duration) error {
+	r.lock.RLock()
+	drainers := make(map[string]consensus.Drainer)
+	for chainID, cs := range r.chains {
+		if drainer, ok := cs.Chain.(consensus.Drainer); ok {
+			drainers[chainID] = drainer
+		}
+	}
+	r.lock.RUnlock()
+
+	var wg sync.WaitGroup
+	var failed int32
+	for chainID, drainer := range drainers {
+		wg.Add(1)
+		go func(chainID string, drainer consensus.Drainer) {
+			defer wg.Done()
+			if err := drainer.Drain(timeout); err != nil {
+				logger.Warningf("[channel: %s] Failed draining chain: %s", chainID, err)
+				atomic.AddInt32(&failed, 1)
+				return
+			}
+			logger.Debugf("[channel: %s] Drained chain", chainID)
+		}(chainID, drainer)
+	}
+	wg.Wait()
+
+	if failed > 0 {
+		return errors.Errorf("failed draining %d of %d chains", failed, len(drainers))
+	}
+	return nil
+}
+
+// Halt halts all the chains.
+func (r *Registrar) Halt() {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for _, cs := range r.chains {
+		cs.Halt()
+	}
+}
+
 // NewChannelConfig produces a new template channel configuration based on the system channel's current config.
 func (r *Registrar) NewChannelConfig(envConfigUpdate *cb.Envelope) (channelconfig.Resources, error) {
 	return r.templator.NewChannelConfig(envConfigUpdate)
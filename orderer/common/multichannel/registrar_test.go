@@ -7,7 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package multichannel
 
 import (
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/crypto"
@@ -109,7 +111,7 @@ func TestNoSystemChain(t *testing.T) {
 	consenters := make(map[string]consensus.Consenter)
 	consenters[conf.Orderer.OrdererType] = &mockConsenter{}
 
-	assert.Panics(t, func() { NewRegistrar(lf, consenters, mockCrypto()) }, "Should have panicked when starting without a system chain")
+	assert.Panics(t, func() { NewRegistrar(lf, consenters, mockCrypto(), 0) }, "Should have panicked when starting without a system chain")
 }
 
 // This test checks to make sure that the orderer refuses to come up if there are multiple system channels
@@ -127,7 +129,7 @@ func TestMultiSystemChannel(t *testing.T) {
 	consenters := make(map[string]consensus.Consenter)
 	consenters[conf.Orderer.OrdererType] = &mockConsenter{}
 
-	assert.Panics(t, func() { NewRegistrar(lf, consenters, mockCrypto()) }, "Two system channels should have caused panic")
+	assert.Panics(t, func() { NewRegistrar(lf, consenters, mockCrypto(), 0) }, "Two system channels should have caused panic")
 }
 
 // This test essentially brings the entire system up and is ultimately what main.go will replicate
@@ -137,13 +139,14 @@ func TestManagerImpl(t *testing.T) {
 	consenters := make(map[string]consensus.Consenter)
 	consenters[conf.Orderer.OrdererType] = &mockConsenter{}
 
-	manager := NewRegistrar(lf, consenters, mockCrypto())
+	manager := NewRegistrar(lf, consenters, mockCrypto(), 0)
 
 	_, ok := manager.GetChain("Fake")
 	assert.False(t, ok, "Should not have found a chain that was not created")
 
 	chainSupport, ok := manager.GetChain(genesisconfig.TestChainID)
 	assert.True(t, ok, "Should have gotten chain which was initialized by ramledger")
+	assert.Equal(t, []string{genesisconfig.TestChainID}, manager.ChannelIDs())
 
 	messages := make([]*cb.Envelope, conf.Orderer.BatchSize.MaxMessageCount)
 	for i := 0; i < int(conf.Orderer.BatchSize.MaxMessageCount); i++ {
@@ -163,6 +166,23 @@ func TestManagerImpl(t *testing.T) {
 	}
 }
 
+func TestDrain(t *testing.T) {
+	lf, _ := NewRAMLedgerAndFactory(10)
+	manager := NewRegistrar(lf, map[string]consensus.Consenter{conf.Orderer.OrdererType: &mockConsenter{}}, mockCrypto(), 0)
+	chainSupport, _ := manager.GetChain(genesisconfig.TestChainID)
+	chain := chainSupport.Chain.(*mockChain)
+
+	assert.NoError(t, manager.Drain(time.Second))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&chain.drained))
+
+	chain.drainErr = errors.New("timed out")
+	assert.EqualError(t, manager.Drain(time.Second), "failed draining 1 of 1 chains")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&chain.drained))
+
+	manager.Halt()
+	<-chain.done
+}
+
 // This test brings up the entire system, with the mock consenter, including the broadcasters etc. and creates a new chain
 func TestNewChain(t *testing.T) {
 	expectedLastConfigBlockNumber := uint64(0)
@@ -174,7 +194,7 @@ func TestNewChain(t *testing.T) {
 	consenters := make(map[string]consensus.Consenter)
 	consenters[conf.Orderer.OrdererType] = &mockConsenter{}
 
-	manager := NewRegistrar(lf, consenters, mockCrypto())
+	manager := NewRegistrar(lf, consenters, mockCrypto(), 0)
 	orglessChannelConf := configtxgentest.Load(genesisconfig.SampleSingleMSPChannelProfile)
 	orglessChannelConf.Application.Organizations = nil
 	envConfigUpdate, err := encoder.MakeChannelCreationTransaction(newChainID, mockCrypto(), orglessChannelConf)
@@ -331,7 +351,7 @@ func TestResourcesCheck(t *testing.T) {
 func TestBroadcastChannelSupportRejection(t *testing.T) {
 	ledgerFactory, _ := NewRAMLedgerAndFactory(10)
 	mockConsenters := map[string]consensus.Consenter{conf.Orderer.OrdererType: &mockConsenter{}}
-	registrar := NewRegistrar(ledgerFactory, mockConsenters, mockCrypto())
+	registrar := NewRegistrar(ledgerFactory, mockConsenters, mockCrypto(), 0)
 	randomValue := 1
 	configTx := makeConfigTx(genesisconfig.TestChainID, randomValue)
 	_, _, _, err := registrar.BroadcastChannelSupport(configTx)
@@ -8,6 +8,8 @@ package multichannel
 
 import (
 	"fmt"
+	"sync/atomic"
+	"time"
 
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/configtx"
@@ -38,6 +40,8 @@ type mockChain struct {
 	support  consensus.ConsenterSupport
 	metadata *cb.Metadata
 	done     chan struct{}
+	drainErr error
+	drained  int32
 }
 
 func (mch *mockChain) Errored() <-chan struct{} {
@@ -107,6 +111,11 @@ func (mch *mockChain) Halt() {
 	close(mch.queue)
 }
 
+func (mch *mockChain) Drain(timeout time.Duration) error {
+	atomic.AddInt32(&mch.drained, 1)
+	return mch.drainErr
+}
+
 func makeConfigTx(chainID string, i int) *cb.Envelope {
 	group := cb.NewConfigGroup()
 	group.Groups[channelconfig.OrdererGroupKey] = cb.NewConfigGroup()
@@ -0,0 +1,122 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package server
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/consensus/kafka"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// diagnoseKafka writes the inconsistencies of the Kafka offsets recorded in the
+// ledgers of the given channels, or of all the Kafka channels if none is given,
+// and returns whether any was found. Unless offline, the offsets are compared
+// to the partitions of the channels on the brokers of their config.
+func diagnoseKafka(conf *localconfig.TopLevel, lf blockledger.Factory, channelID string, offline bool, out io.Writer) (bool, error) {
+	channelIDs := lf.ChainIDs()
+	if channelID != "" {
+		channelIDs = []string{channelID}
+	}
+
+	found := false
+	for _, channelID := range channelIDs {
+		rl, err := existingLedger(lf, channelID)
+		if err != nil {
+			return found, err
+		}
+		ordererConfig, err := ordererConfigOf(channelID, rl)
+		if err != nil {
+			return found, err
+		}
+		if ordererConfig.ConsensusType() != "kafka" {
+			fmt.Fprintf(out, "channel %s: skipped, its consensus type is %s\n", channelID, ordererConfig.ConsensusType())
+			continue
+		}
+
+		var partition *kafka.PartitionOffsets
+		if !offline {
+			partition, err = kafka.GetPartitionOffsets(conf.Kafka, conf.General.TLS, ordererConfig.KafkaBrokers(), channelID)
+			if err != nil {
+				return found, errors.WithMessage(err, fmt.Sprintf("failed retrieving the offsets of the partition of channel %s", channelID))
+			}
+		}
+		inconsistencies, err := kafka.Diagnose(rl, partition)
+		if err != nil {
+			return found, errors.WithMessage(err, fmt.Sprintf("failed diagnosing channel %s", channelID))
+		}
+		if len(inconsistencies) == 0 {
+			fmt.Fprintf(out, "channel %s: consistent up to block %d\n", channelID, rl.Height()-1)
+			continue
+		}
+		found = true
+		for _, inconsistency := range inconsistencies {
+			fmt.Fprintf(out, "channel %s: %s\n", channelID, inconsistency)
+		}
+	}
+	return found, nil
+}
+
+// repairKafka makes the given channel resume consuming its partition from the
+// given offset at the next start of the orderer
+func repairKafka(conf *localconfig.TopLevel, channelID string, offset int64) error {
+	if conf.General.LedgerType == "ram" {
+		return errors.New("the ram ledger doesn't persist across restarts")
+	}
+	lf, ld := createLedgerFactory(conf)
+	if _, err := existingLedger(lf, channelID); err != nil {
+		return err
+	}
+	return kafka.SetResumeOffset(ld, channelID, offset)
+}
+
+// existingLedger returns the ledger of the given channel, without creating it
+// if the channel doesn't exist
+func existingLedger(lf blockledger.Factory, channelID string) (blockledger.ReadWriter, error) {
+	for _, existing := range lf.ChainIDs() {
+		if existing != channelID {
+			continue
+		}
+		rl, err := lf.GetOrCreate(channelID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed opening the ledger of channel %s", channelID)
+		}
+		return rl, nil
+	}
+	return nil, errors.Errorf("channel %s does not exist", channelID)
+}
+
+// ordererConfigOf returns the orderer config of the last config block of the given ledger
+func ordererConfigOf(channelID string, rl blockledger.Reader) (channelconfig.Orderer, error) {
+	lastBlock := blockledger.GetBlock(rl, rl.Height()-1)
+	index, err := utils.GetLastConfigIndexFromBlock(lastBlock)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed retrieving the last config index of channel %s", channelID)
+	}
+	configBlock := blockledger.GetBlock(rl, index)
+	if configBlock == nil {
+		return nil, errors.Errorf("config block %d of channel %s does not exist", index, channelID)
+	}
+	env, err := utils.ExtractEnvelope(configBlock, 0)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed extracting the config of channel %s", channelID)
+	}
+	bundle, err := channelconfig.NewBundleFromEnvelope(env)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed parsing the config of channel %s", channelID)
+	}
+	ordererConfig, ok := bundle.OrdererConfig()
+	if !ok {
+		return nil, errors.Errorf("channel %s has no orderer config", channelID)
+	}
+	return ordererConfig, nil
+}
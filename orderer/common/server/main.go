@@ -8,21 +8,25 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net"
-	"net/http"
-	_ "net/http/pprof" // This is essentially the main package for the orderer
-
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
 
+	"github.com/hyperledger/fabric/common/certmonitor"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/profiling"
 	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
 	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/orderer/common/bootstrap/file"
+	"github.com/hyperledger/fabric/orderer/common/broadcast/content"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/metadata"
 	"github.com/hyperledger/fabric/orderer/common/multichannel"
@@ -35,6 +39,7 @@ import (
 	"github.com/hyperledger/fabric/protos/utils"
 
 	"github.com/hyperledger/fabric/common/localmsp"
+	"github.com/hyperledger/fabric/common/tracing"
 	"github.com/hyperledger/fabric/common/util"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/orderer/common/performance"
@@ -52,6 +57,14 @@ var (
 	start     = app.Command("start", "Start the orderer node").Default()
 	version   = app.Command("version", "Show version information")
 	benchmark = app.Command("benchmark", "Run orderer in benchmark mode")
+
+	kafkaCmd           = app.Command("kafka", "Inspect and repair the Kafka offsets recorded in the ledger")
+	kafkaDoctor        = kafkaCmd.Command("doctor", "Report the inconsistencies of the Kafka offsets recorded in the ledger")
+	kafkaDoctorChannel = kafkaDoctor.Flag("channelID", "The channel to inspect, all the channels if unset").String()
+	kafkaDoctorOffline = kafkaDoctor.Flag("offline", "Do not compare the offsets to the partitions on the Kafka brokers").Bool()
+	kafkaRepair        = kafkaCmd.Command("repair", "Resume consuming the partition of a channel from the given offset at the next start")
+	kafkaRepairChannel = kafkaRepair.Flag("channelID", "The channel to repair").Required().String()
+	kafkaRepairOffset  = kafkaRepair.Flag("offset", "The offset of the partition to resume from").Required().Int64()
 )
 
 // Main is the entry point of orderer process
@@ -70,6 +83,26 @@ func Main() {
 		os.Exit(1)
 	}
 	initializeLoggingLevel(conf)
+
+	switch fullCmd {
+	case kafkaDoctor.FullCommand():
+		lf, _ := createLedgerFactory(conf)
+		found, err := diagnoseKafka(conf, lf, *kafkaDoctorChannel, *kafkaDoctorOffline, os.Stdout)
+		if err != nil {
+			logger.Fatalf("Failed diagnosing the Kafka offsets: %s", err)
+		}
+		if found {
+			os.Exit(1)
+		}
+		return
+	case kafkaRepair.FullCommand():
+		if err := repairKafka(conf, *kafkaRepairChannel, *kafkaRepairOffset); err != nil {
+			logger.Fatalf("Failed repairing the Kafka offsets: %s", err)
+		}
+		fmt.Printf("channel %s will resume from offset %d at the next start, repair all the orderers of the channel with the same offset\n", *kafkaRepairChannel, *kafkaRepairOffset)
+		return
+	}
+
 	initializeLocalMsp(conf)
 
 	prettyPrintStruct(conf)
@@ -79,6 +112,10 @@ func Main() {
 // Start provides a layer of abstraction for benchmark test
 func Start(cmd string, conf *localconfig.TopLevel) {
 	signer := localmsp.NewSigner()
+	initializeTracing(conf)
+	defer tracing.Shutdown()
+	initializeMetrics(conf)
+	defer metrics.Shutdown()
 	serverConfig := initializeServerConfig(conf)
 	grpcServer := initializeGrpcServer(conf, serverConfig)
 	caSupport := &comm.CASupport{
@@ -95,24 +132,61 @@ func Start(cmd string, conf *localconfig.TopLevel) {
 	}
 
 	manager := initializeMultichannelRegistrar(conf, signer, tlsCallback)
+	certMonitor := initializeCertificateMonitor(conf, manager)
+	if certMonitor != nil {
+		defer certMonitor.Stop()
+	}
 	mutualTLS := serverConfig.SecOpts.UseTLS && serverConfig.SecOpts.RequireClientCert
-	server := NewServer(manager, signer, &conf.Debug, conf.General.Authentication.TimeWindow, mutualTLS)
+	abServer := NewServer(manager, signer, &conf.Debug, conf.General.Authentication.TimeWindow, mutualTLS, conf.General.Authentication.ReplayProtection, conf.General.Deliver, initializeContentValidator(conf))
 
 	switch cmd {
 	case start.FullCommand(): // "start" command
 		logger.Infof("Starting %s", metadata.GetVersionInfo())
-		initializeProfilingService(conf)
-		ab.RegisterAtomicBroadcastServer(grpcServer.Server(), server)
+		if profilingServer := initializeProfilingService(conf); profilingServer != nil {
+			defer profilingServer.Stop()
+			if certMonitor != nil {
+				profilingServer.Handle("/certificates", certMonitor)
+			}
+		}
+		ab.RegisterAtomicBroadcastServer(grpcServer.Server(), abServer)
+		halted := handleShutdownSignals(abServer.(*server), grpcServer, conf.General.ShutdownGracePeriod)
 		logger.Info("Beginning to serve requests")
-		grpcServer.Start()
+		if err := grpcServer.Start(); err == nil {
+			// The gRPC server was stopped by the shutdown
+			<-halted
+		}
 	case benchmark.FullCommand(): // "benchmark" command
 		logger.Info("Starting orderer in benchmark mode")
 		benchmarkServer := performance.GetBenchmarkServer()
-		benchmarkServer.RegisterService(server)
+		benchmarkServer.RegisterService(abServer)
 		benchmarkServer.Start()
 	}
 }
 
+// handleShutdownSignals shuts the orderer down gracefully once it receives
+// SIGTERM or SIGINT: the orderer is drained, then the gRPC server is stopped
+// and the chains are halted. The returned channel is closed once the chains
+// are halted.
+func handleShutdownSignals(srv *server, grpcServer *comm.GRPCServer, gracePeriod time.Duration) <-chan struct{} {
+	halted := make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		sig := <-signals
+		logger.Infof("Received signal %s, shutting down with a grace period of %s", sig, gracePeriod)
+		deadline := time.Now().Add(gracePeriod)
+
+		srv.shutdown(gracePeriod)
+		if !grpcServer.GracefulStop(time.Until(deadline)) {
+			logger.Warning("Cancelled the requests still being served at the end of the grace period")
+		}
+		srv.Registrar.Halt()
+		logger.Info("Halted the chains")
+		close(halted)
+	}()
+	return halted
+}
+
 // Set the logging level
 func initializeLoggingLevel(conf *localconfig.TopLevel) {
 	flogging.Init(flogging.Config{
@@ -122,15 +196,71 @@ func initializeLoggingLevel(conf *localconfig.TopLevel) {
 	})
 }
 
+// initializeClusterClientConfig returns the configuration of the connections
+// to the other orderers of the cluster, which present the TLS certificate of
+// the server of the orderer and trust its root CAs
+func initializeClusterClientConfig(conf *localconfig.TopLevel, serverConfig comm.ServerConfig) comm.ClientConfig {
+	cc := comm.ClientConfig{
+		Timeout:            conf.General.Cluster.DialTimeout,
+		DNSRefreshInterval: conf.General.Cluster.DNSRefreshInterval,
+		KaOpts:             comm.DefaultKeepaliveOptions,
+		SecOpts:            &comm.SecureOptions{},
+	}
+	if serverConfig.SecOpts != nil && serverConfig.SecOpts.UseTLS {
+		cc.SecOpts = &comm.SecureOptions{
+			UseTLS:            true,
+			RequireClientCert: true,
+			Certificate:       serverConfig.SecOpts.Certificate,
+			Key:               serverConfig.SecOpts.Key,
+			ServerRootCAs:     serverConfig.SecOpts.ServerRootCAs,
+		}
+	}
+	return cc
+}
+
 // Start the profiling service if enabled.
-func initializeProfilingService(conf *localconfig.TopLevel) {
-	if conf.General.Profile.Enabled {
-		go func() {
-			logger.Info("Starting Go pprof profiling service on:", conf.General.Profile.Address)
-			// The ListenAndServe() call does not return unless an error occurs.
-			logger.Panic("Go pprof service failed:", http.ListenAndServe(conf.General.Profile.Address, nil))
-		}()
+func initializeProfilingService(conf *localconfig.TopLevel) *profiling.Server {
+	if !conf.General.Profile.Enabled {
+		return nil
 	}
+	options := profiling.Options{
+		ListenAddress:        conf.General.Profile.Address,
+		Enabled:              true,
+		BlockProfileRate:     conf.General.Profile.BlockProfileRate,
+		MutexProfileFraction: conf.General.Profile.MutexProfileFraction,
+	}
+	if tlsConf := conf.General.Profile.TLS; tlsConf.Enabled {
+		certificate, err := ioutil.ReadFile(tlsConf.Certificate)
+		if err != nil {
+			logger.Fatalf("Failed to load profiling Certificate file '%s' (%s)", tlsConf.Certificate, err)
+		}
+		key, err := ioutil.ReadFile(tlsConf.PrivateKey)
+		if err != nil {
+			logger.Fatalf("Failed to load profiling PrivateKey file '%s' (%s)", tlsConf.PrivateKey, err)
+		}
+		var clientRootCAs [][]byte
+		for _, clientRoot := range tlsConf.ClientRootCAs {
+			root, err := ioutil.ReadFile(clientRoot)
+			if err != nil {
+				logger.Fatalf("Failed to load profiling ClientRootCAs file '%s' (%s)", clientRoot, err)
+			}
+			clientRootCAs = append(clientRootCAs, root)
+		}
+		if options.TLS, err = profiling.NewTLSConfig(certificate, key, clientRootCAs); err != nil {
+			logger.Panicf("Failed configuring profiling TLS: %s", err)
+		}
+	}
+
+	server, err := profiling.NewServer(options)
+	if err != nil {
+		logger.Panicf("Go pprof service failed: %s", err)
+	}
+	go func() {
+		if err := server.Start(); err != nil {
+			logger.Errorf("Go pprof service exited with error: %s", err)
+		}
+	}()
+	return server
 }
 
 func initializeServerConfig(conf *localconfig.TopLevel) comm.ServerConfig {
@@ -188,7 +318,100 @@ func initializeServerConfig(conf *localconfig.TopLevel) comm.ServerConfig {
 	kaOpts.ServerInterval = conf.General.Keepalive.ServerInterval
 	kaOpts.ServerTimeout = conf.General.Keepalive.ServerTimeout
 
-	return comm.ServerConfig{SecOpts: secureOpts, KaOpts: kaOpts}
+	serverConfig := comm.ServerConfig{SecOpts: secureOpts, KaOpts: kaOpts}
+	if tracer := tracing.GlobalTracer(); tracer != nil {
+		serverConfig.UnaryInterceptors = append(serverConfig.UnaryInterceptors, tracing.UnaryServerInterceptor(tracer))
+		serverConfig.StreamInterceptors = append(serverConfig.StreamInterceptors, tracing.StreamServerInterceptor(tracer))
+	}
+	return serverConfig
+}
+
+// Start tracing the requests if enabled.
+func initializeTracing(conf *localconfig.TopLevel) {
+	err := tracing.Init(tracing.Config{
+		Enabled:       conf.General.Tracing.Enabled,
+		ServiceName:   conf.General.Tracing.ServiceName,
+		Exporter:      conf.General.Tracing.Exporter,
+		Endpoint:      conf.General.Tracing.OTLP.Endpoint,
+		SampleRate:    conf.General.Tracing.SampleRate,
+		BatchSize:     conf.General.Tracing.BatchSize,
+		FlushInterval: conf.General.Tracing.FlushInterval,
+		Timeout:       conf.General.Tracing.OTLP.Timeout,
+	})
+	if err != nil {
+		logger.Fatalf("Failed initializing tracing: %s", err)
+	}
+}
+
+// initializeMetrics sets up the root scope of the metrics, which discards them
+// unless Metrics.Enabled is set, and starts reporting them
+func initializeMetrics(conf *localconfig.TopLevel) {
+	opts := metrics.Opts{
+		Enabled:  conf.Metrics.Enabled,
+		Reporter: conf.Metrics.Reporter,
+		Interval: conf.Metrics.Interval,
+		StatsdReporterOpts: metrics.StatsdReporterOpts{
+			Address:       conf.Metrics.StatsdReporter.Address,
+			FlushInterval: conf.Metrics.StatsdReporter.FlushInterval,
+			FlushBytes:    conf.Metrics.StatsdReporter.FlushBytes,
+		},
+		PromReporterOpts: metrics.PromReporterOpts{
+			ListenAddress: conf.Metrics.PromReporter.ListenAddress,
+		},
+	}
+	if err := metrics.Init(opts); err != nil {
+		logger.Fatalf("Failed initializing metrics: %s", err)
+	}
+	if conf.Metrics.Enabled {
+		go func() {
+			if err := metrics.Start(); err != nil {
+				logger.Errorf("Error starting metrics server: %s", err)
+			}
+		}()
+	}
+}
+
+// initializeCertificateMonitor starts watching the expiration of the
+// certificates of the local MSP, of the TLS certificate and of the
+// certificates of the MSPs of the channels of the orderer, unless
+// General.CertificateExpiration.Enabled is unset
+func initializeCertificateMonitor(conf *localconfig.TopLevel, registrar *multichannel.Registrar) *certmonitor.Monitor {
+	expirationConf := conf.General.CertificateExpiration
+	if !expirationConf.Enabled {
+		return nil
+	}
+	sources := []certmonitor.Source{
+		certmonitor.LocalMSP(conf.General.LocalMSPDir, conf.General.LocalMSPID, msp.ProviderTypeToString(msp.FABRIC)),
+		certmonitor.Channels(registrar.ChannelIDs, func(channelID string) *cb.Config {
+			if cs, ok := registrar.GetChain(channelID); ok {
+				return cs.ConfigtxValidator().ConfigProto()
+			}
+			return nil
+		}),
+	}
+	if conf.General.TLS.Enabled {
+		sources = append(sources, certmonitor.PEMFiles(conf.General.LocalMSPID, "tls_server", conf.General.TLS.Certificate))
+	}
+
+	logger.Infof("Monitoring certificate expiration every %s, warning %s before", expirationConf.CheckInterval, expirationConf.WarnBefore)
+	monitor := certmonitor.NewMonitor(certmonitor.Options{
+		Interval:   expirationConf.CheckInterval,
+		WarnBefore: expirationConf.WarnBefore,
+	}, metrics.RootScope.SubScope("certificates"), sources...)
+	monitor.Start()
+	return monitor
+}
+
+func initializeContentValidator(conf *localconfig.TopLevel) content.Validator {
+	if conf.General.ContentValidator.Library == "" {
+		return nil
+	}
+	validator, err := content.LoadValidator(conf.General.ContentValidator.Library)
+	if err != nil {
+		logger.Fatalf("Failed loading the content validator: %s", err)
+	}
+	logger.Infof("Validating the content of the broadcast messages with %s", conf.General.ContentValidator.Library)
+	return validator
 }
 
 func initializeBootstrapChannel(conf *localconfig.TopLevel, lf blockledger.Factory) {
@@ -220,7 +443,7 @@ func initializeBootstrapChannel(conf *localconfig.TopLevel, lf blockledger.Facto
 }
 
 func initializeGrpcServer(conf *localconfig.TopLevel, serverConfig comm.ServerConfig) *comm.GRPCServer {
-	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", conf.General.ListenAddress, conf.General.ListenPort))
+	lis, err := net.Listen("tcp", net.JoinHostPort(conf.General.ListenAddress, strconv.Itoa(int(conf.General.ListenPort))))
 	if err != nil {
 		logger.Fatal("Failed to listen:", err)
 	}
@@ -244,7 +467,7 @@ func initializeLocalMsp(conf *localconfig.TopLevel) {
 
 func initializeMultichannelRegistrar(conf *localconfig.TopLevel, signer crypto.LocalSigner,
 	callbacks ...func(bundle *channelconfig.Bundle)) *multichannel.Registrar {
-	lf, _ := createLedgerFactory(conf)
+	lf, ld := createLedgerFactory(conf)
 	// Are we bootstrapping?
 	if len(lf.ChainIDs()) == 0 {
 		initializeBootstrapChannel(conf, lf)
@@ -254,10 +477,10 @@ func initializeMultichannelRegistrar(conf *localconfig.TopLevel, signer crypto.L
 
 	consenters := make(map[string]consensus.Consenter)
 	consenters["solo"] = solo.New()
-	consenters["kafka"] = kafka.New(conf.Kafka)
+	consenters["kafka"] = kafka.New(conf.Kafka, ld)
 	consenters["bftsmart"] = bftsmart.New(conf.BFTsmart) //JCS: create my own consenter
 
-	return multichannel.NewRegistrar(lf, consenters, signer, callbacks...)
+	return multichannel.NewRegistrar(lf, consenters, signer, conf.General.Checkpoint.Interval, callbacks...)
 }
 
 func updateTrustedRoots(srv *comm.GRPCServer, rootCASupport *comm.CASupport,
@@ -66,6 +66,37 @@ func TestInitializeProfilingService(t *testing.T) {
 	}
 }
 
+func TestInitializeClusterClientConfig(t *testing.T) {
+	conf := &localconfig.TopLevel{
+		General: localconfig.General{
+			Cluster: localconfig.Cluster{
+				DialTimeout:        3 * time.Second,
+				DNSRefreshInterval: time.Minute,
+			},
+		},
+	}
+
+	cc := initializeClusterClientConfig(conf, comm.ServerConfig{SecOpts: &comm.SecureOptions{}})
+	assert.Equal(t, 3*time.Second, cc.Timeout)
+	assert.Equal(t, time.Minute, cc.DNSRefreshInterval)
+	assert.False(t, cc.SecOpts.UseTLS)
+
+	cc = initializeClusterClientConfig(conf, comm.ServerConfig{SecOpts: &comm.SecureOptions{
+		UseTLS:        true,
+		Certificate:   []byte("cert"),
+		Key:           []byte("key"),
+		ServerRootCAs: [][]byte{[]byte("root")},
+		ClientRootCAs: [][]byte{[]byte("client root")},
+	}})
+	assert.Equal(t, &comm.SecureOptions{
+		UseTLS:            true,
+		RequireClientCert: true,
+		Certificate:       []byte("cert"),
+		Key:               []byte("key"),
+		ServerRootCAs:     [][]byte{[]byte("root")},
+	}, cc.SecOpts)
+}
+
 func TestInitializeServerConfig(t *testing.T) {
 	conf := &localconfig.TopLevel{
 		General: localconfig.General{
@@ -11,13 +11,16 @@ import (
 	"io/ioutil"
 	"os"
 	"runtime/debug"
+	"sync"
 	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/deliver"
 	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/replay"
 	"github.com/hyperledger/fabric/orderer/common/broadcast"
+	"github.com/hyperledger/fabric/orderer/common/broadcast/content"
 	localconfig "github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	"github.com/hyperledger/fabric/orderer/common/multichannel"
@@ -47,10 +50,19 @@ type server struct {
 	dh    *deliver.Handler
 	debug *localconfig.Debug
 	*multichannel.Registrar
+
+	// closing is closed to end the deliver streams when the orderer shuts down
+	closing   chan struct{}
+	closeOnce sync.Once
+	// blockResponses are the encoded block responses the deliver streams share
+	blockResponses *deliver.EncodedResponses
 }
 
 type responseSender struct {
 	ab.AtomicBroadcast_DeliverServer
+	// blockResponses, if set, are the encoded block responses shared with the
+	// other deliver streams
+	blockResponses *deliver.EncodedResponses
 }
 
 func (rs *responseSender) SendStatusResponse(status cb.Status) error {
@@ -60,24 +72,68 @@ func (rs *responseSender) SendStatusResponse(status cb.Status) error {
 	return rs.Send(reply)
 }
 
-func (rs *responseSender) SendBlockResponse(block *cb.Block) error {
+func (rs *responseSender) SendBlockResponse(block *cb.Block, channelID string, chain deliver.Chain, signedData *cb.SignedData) error {
 	response := &ab.DeliverResponse{
 		Type: &ab.DeliverResponse_Block{Block: block},
 	}
-	return rs.Send(response)
+	if rs.blockResponses == nil {
+		return rs.Send(response)
+	}
+	encoded, err := rs.blockResponses.Encode(channelID, block, response)
+	if err != nil {
+		return err
+	}
+	return rs.SendMsg(deliver.EncodedMessage(response, encoded))
 }
 
 // NewServer creates an ab.AtomicBroadcastServer based on the broadcast target and ledger Reader
-func NewServer(r *multichannel.Registrar, _ crypto.LocalSigner, debug *localconfig.Debug, timeWindow time.Duration, mutualTLS bool) ab.AtomicBroadcastServer {
+func NewServer(r *multichannel.Registrar, _ crypto.LocalSigner, debug *localconfig.Debug, timeWindow time.Duration, mutualTLS bool, replayProtection localconfig.ReplayProtection, deliverLimits localconfig.Deliver, contentValidator content.Validator) ab.AtomicBroadcastServer {
+	dh := deliver.NewHandler(deliverSupport{Registrar: r}, timeWindow, mutualTLS)
+	dh.MaxStreamsPerIdentity = deliverLimits.MaxStreamsPerIdentity
+	dh.BlocksInFlight = deliverLimits.BlocksInFlight
+	closing := make(chan struct{})
+	dh.Closing = closing
+	var broadcastReplayCache *replay.Cache
+	if replayProtection.Enabled {
+		dh.ReplayCache = replay.NewCache(timeWindow, replayProtection.MaxClockSkew, replayProtection.CacheSize)
+		broadcastReplayCache = replay.NewCache(timeWindow, replayProtection.MaxClockSkew, replayProtection.CacheSize)
+	}
 	s := &server{
-		dh:        deliver.NewHandler(deliverSupport{Registrar: r}, timeWindow, mutualTLS),
-		bh:        broadcast.NewHandlerImpl(broadcastSupport{Registrar: r}),
-		debug:     debug,
-		Registrar: r,
+		dh:             dh,
+		bh:             broadcast.NewHandlerImpl(broadcastSupport{Registrar: r}, broadcastReplayCache, contentValidator),
+		debug:          debug,
+		Registrar:      r,
+		closing:        closing,
+		blockResponses: deliver.NewEncodedResponses(),
 	}
 	return s
 }
 
+// shutdown drains the orderer within the grace period: Broadcast stops
+// accepting messages and waits for those being enqueued, the chains cut their
+// pending batches and transfer their consensus leadership, and finally the
+// deliver streams are ended with SERVICE_UNAVAILABLE so that the clients
+// reconnect to another orderer
+func (s *server) shutdown(gracePeriod time.Duration) {
+	deadline := time.Now().Add(gracePeriod)
+
+	if drainer, ok := s.bh.(broadcast.Drainer); ok {
+		if err := drainer.Drain(gracePeriod); err != nil {
+			logger.Warningf("Failed draining broadcast: %s", err)
+		} else {
+			logger.Info("Drained broadcast")
+		}
+	}
+
+	if err := s.Registrar.Drain(time.Until(deadline)); err != nil {
+		logger.Warningf("Failed draining the chains: %s", err)
+	} else {
+		logger.Info("Drained the chains")
+	}
+
+	s.closeOnce.Do(func() { close(s.closing) })
+}
+
 type msgTracer struct {
 	function string
 	debug    *localconfig.Debug
@@ -177,6 +233,7 @@ func (s *server) Deliver(srv ab.AtomicBroadcast_DeliverServer) error {
 		},
 		ResponseSender: &responseSender{
 			AtomicBroadcast_DeliverServer: srv,
+			blockResponses:                s.blockResponses,
 		},
 	}
 	return s.dh.Handle(srv.Context(), deliverServer)
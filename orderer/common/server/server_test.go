@@ -16,6 +16,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	localconfig "github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/multichannel"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -35,6 +36,41 @@ func TestDeliverNoPanic(t *testing.T) {
 	_ = (&server{}).Deliver(nil)
 }
 
+type recordingBroadcastSrv struct {
+	mockSrv
+	responses []*ab.BroadcastResponse
+}
+
+func (rbs *recordingBroadcastSrv) Recv() (*cb.Envelope, error) {
+	return rbs.msg, rbs.err
+}
+
+func (rbs *recordingBroadcastSrv) Send(br *ab.BroadcastResponse) error {
+	rbs.responses = append(rbs.responses, br)
+	return nil
+}
+
+func TestShutdown(t *testing.T) {
+	s := NewServer(&multichannel.Registrar{}, nil, &localconfig.Debug{}, time.Minute, false, localconfig.ReplayProtection{}, localconfig.Deliver{}, nil).(*server)
+	s.shutdown(time.Second)
+
+	select {
+	case <-s.dh.Closing:
+	default:
+		t.Fatal("Deliver streams should be closing")
+	}
+
+	// Broadcast rejects the messages once drained
+	srv := &recordingBroadcastSrv{mockSrv: mockSrv{msg: &cb.Envelope{}}}
+	assert.NoError(t, s.Broadcast(srv))
+	assert.Len(t, srv.responses, 1)
+	assert.Equal(t, cb.Status_SERVICE_UNAVAILABLE, srv.responses[0].Status)
+	assert.Equal(t, "orderer is shutting down", srv.responses[0].Info)
+
+	// Shutting down again is harmless
+	s.shutdown(time.Second)
+}
+
 type recvr interface {
 	Recv() (*cb.Envelope, error)
 }
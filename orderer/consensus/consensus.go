@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package consensus
 
 import (
+	"time"
+
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
@@ -67,6 +69,15 @@ type Chain interface {
 	Halt()
 }
 
+// Drainer is implemented by the chains which can complete their pending work
+// before the orderer shuts down.
+type Drainer interface {
+	// Drain cuts the messages pending in the block cutter into a block, and
+	// transfers the consensus leadership if this node holds it. It returns an
+	// error if this could not be done within the timeout.
+	Drain(timeout time.Duration) error
+}
+
 //go:generate counterfeiter -o mocks/mock_consenter_support.go . ConsenterSupport
 
 // ConsenterSupport provides the resources available to a Consenter implementation.
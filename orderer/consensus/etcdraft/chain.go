@@ -56,6 +56,7 @@ type Chain struct {
 
 	submitC  chan *orderer.SubmitRequest
 	commitC  chan *common.Block
+	drainC   chan chan struct{}
 	observeC chan<- uint64 // Notifies external observer on leader change
 	haltC    chan struct{}
 	doneC    chan struct{}
@@ -81,6 +82,7 @@ func NewChain(support consensus.ConsenterSupport, opts Options, observe chan<- u
 		raftID:   opts.RaftID,
 		submitC:  make(chan *orderer.SubmitRequest),
 		commitC:  make(chan *common.Block),
+		drainC:   make(chan chan struct{}),
 		haltC:    make(chan struct{}),
 		doneC:    make(chan struct{}),
 		observeC: observe,
@@ -141,6 +143,74 @@ func (c *Chain) Halt() {
 	<-c.doneC
 }
 
+// Drain cuts the pending requests into a block and, if this node is the
+// leader, transfers the leadership to another node of the cluster.
+func (c *Chain) Drain(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	done := make(chan struct{})
+	select {
+	case c.drainC <- done:
+	case <-c.doneC:
+		return errors.Errorf("chain is stopped")
+	case <-ctx.Done():
+		return errors.Errorf("timed out after %s waiting to cut the pending batch", timeout)
+	}
+
+	select {
+	case <-done:
+	case <-c.doneC:
+		return errors.Errorf("chain is stopped")
+	case <-ctx.Done():
+		return errors.Errorf("timed out after %s waiting to cut the pending batch", timeout)
+	}
+
+	return c.transferLeadership(ctx)
+}
+
+// transferLeadership hands the leadership over to the first other node of
+// the cluster and waits for the new leader to be elected.
+func (c *Chain) transferLeadership(ctx context.Context) error {
+	if !c.isLeader() {
+		return nil
+	}
+
+	transferee := raft.None
+	for _, p := range c.opts.Peers {
+		if p.ID != c.raftID {
+			transferee = p.ID
+			break
+		}
+	}
+	if transferee == raft.None {
+		c.logger.Debugf("No other raft node to transfer the leadership to")
+		return nil
+	}
+
+	c.logger.Infof("Transferring raft leadership from node %x to node %x", c.raftID, transferee)
+	c.node.TransferLeadership(ctx, c.raftID, transferee)
+
+	ticker := c.clock.NewTicker(c.opts.TickInterval)
+	defer ticker.Stop()
+	for c.isLeader() {
+		select {
+		case <-ticker.C():
+		case <-c.doneC:
+			return errors.Errorf("chain is stopped")
+		case <-ctx.Done():
+			return errors.Errorf("timed out transferring raft leadership to node %x", transferee)
+		}
+	}
+	return nil
+}
+
+func (c *Chain) isLeader() bool {
+	c.leaderLock.RLock()
+	defer c.leaderLock.RUnlock()
+	return c.leader == c.raftID
+}
+
 // Submit forwards the incoming request to:
 // - the local serveRequest goroutine if this is leader
 // - the actual leader via the transport mechanism
@@ -233,6 +303,17 @@ func (c *Chain) serveRequest() {
 				c.logger.Errorf("Failed to commit block: %s", err)
 			}
 
+		case done := <-c.drainC:
+			stop()
+
+			if batch := c.support.BlockCutter().Cut(); len(batch) != 0 {
+				c.logger.Debugf("Draining, creating block")
+				if err := c.commitBatches(batch); err != nil {
+					c.logger.Errorf("Failed to commit block: %s", err)
+				}
+			}
+			close(done)
+
 		case <-c.doneC:
 			c.logger.Infof("Stop serving requests")
 			return
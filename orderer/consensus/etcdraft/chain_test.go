@@ -206,6 +206,33 @@ var _ = Describe("Chain", func() {
 				Consistently(support.WriteBlockCallCount).Should(Equal(0))
 			})
 
+			It("cuts the pending envelopes when drained", func() {
+				close(cutter.Block)
+				support.CreateNextBlockReturns(normalBlock)
+
+				err := chain.Order(m, uint64(0))
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(func() int {
+					return len(cutter.CurBatch)
+				}).Should(Equal(1))
+
+				// there is no other node to transfer the leadership to
+				err = chain.Drain(time.Minute)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(support.WriteBlockCallCount()).To(Equal(1))
+
+				By("writing no block without pending envelopes")
+				err = chain.Drain(time.Minute)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(support.WriteBlockCallCount()).To(Equal(1))
+			})
+
+			It("fails to drain if chain is halted", func() {
+				chain.Halt()
+				err := chain.Drain(time.Minute)
+				Expect(err).To(MatchError("chain is stopped"))
+			})
+
 			It("stops the timer if a batch is cut", func() {
 				close(cutter.Block)
 				support.CreateNextBlockReturns(normalBlock)
@@ -8,6 +8,7 @@ package kafka
 
 import (
 	"fmt"
+	"os"
 	"strconv"
 	"sync"
 	"time"
@@ -90,6 +91,12 @@ type chainImpl struct {
 	lastResubmittedConfigOffset int64
 	lastCutBlockNumber          uint64
 
+	// resumeOffsetPath is where the offset set by SetResumeOffset the chain
+	// resumed from is recorded, if any, until a block is committed above
+	// resumeHeight
+	resumeOffsetPath string
+	resumeHeight     uint64
+
 	producer        sarama.SyncProducer
 	parentConsumer  sarama.Consumer
 	channelConsumer sarama.PartitionConsumer
@@ -263,7 +270,7 @@ func startThread(chain *chainImpl) {
 	}
 
 	// Set up the producer
-	chain.producer, err = setupProducerForChannel(chain.consenter.retryOptions(), chain.haltChan, chain.SharedConfig().KafkaBrokers(), chain.consenter.brokerConfig(), chain.channel)
+	chain.producer, err = setupProducerForChannel(chain.consenter.retryOptions(), chain.haltChan, chain.SharedConfig().KafkaBrokers(), chain.consenter.brokerConfig(), chain.consenter.dnsRefreshInterval(), chain.consenter.transactionalIDPrefix(), chain.channel)
 	if err != nil {
 		logger.Panicf("[channel: %s] Cannot set up producer = %s", chain.channel.topic(), err)
 	}
@@ -276,7 +283,7 @@ func startThread(chain *chainImpl) {
 	logger.Infof("[channel: %s] CONNECT message posted successfully", chain.channel.topic())
 
 	// Set up the parent consumer
-	chain.parentConsumer, err = setupParentConsumerForChannel(chain.consenter.retryOptions(), chain.haltChan, chain.SharedConfig().KafkaBrokers(), chain.consenter.brokerConfig(), chain.channel)
+	chain.parentConsumer, err = setupParentConsumerForChannel(chain.consenter.retryOptions(), chain.haltChan, chain.SharedConfig().KafkaBrokers(), chain.consenter.brokerConfig(), chain.consenter.dnsRefreshInterval(), chain.channel)
 	if err != nil {
 		logger.Panicf("[channel: %s] Cannot set up parent consumer = %s", chain.channel.topic(), err)
 	}
@@ -444,6 +451,7 @@ func (chain *chainImpl) processMessagesToBlocks() ([]uint64, error) {
 					counts[indexProcessRegularPass]++
 				}
 			}
+			chain.releaseResumeOffset()
 		case <-chain.timer:
 			if err := sendTimeToCut(chain.producer, chain.channel, chain.lastCutBlockNumber+1, &chain.timer); err != nil {
 				logger.Errorf("[channel: %s] cannot post time-to-cut message = %s", chain.ChainID(), err)
@@ -456,6 +464,21 @@ func (chain *chainImpl) processMessagesToBlocks() ([]uint64, error) {
 	}
 }
 
+// releaseResumeOffset removes the offset set by SetResumeOffset the chain
+// resumed from once a block is committed after it, since the chain then
+// resumes after the offset persisted in that block
+func (chain *chainImpl) releaseResumeOffset() {
+	if chain.resumeOffsetPath == "" || chain.Height() <= chain.resumeHeight {
+		return
+	}
+	if err := os.Remove(chain.resumeOffsetPath); err != nil && !os.IsNotExist(err) {
+		logger.Errorf("[channel: %s] Failed removing the resume offset in %s: %s", chain.ChainID(), chain.resumeOffsetPath, err)
+		return
+	}
+	logger.Infof("[channel: %s] Removed the resume offset in %s", chain.ChainID(), chain.resumeOffsetPath)
+	chain.resumeOffsetPath = ""
+}
+
 func (chain *chainImpl) closeKafkaObjects() []error {
 	var errs []error
 
@@ -936,7 +959,7 @@ func setupChannelConsumerForChannel(retryOptions localconfig.Retry, haltChan cha
 }
 
 // Sets up the parent consumer for a channel using the given retry options.
-func setupParentConsumerForChannel(retryOptions localconfig.Retry, haltChan chan struct{}, brokers []string, brokerConfig *sarama.Config, channel channel) (sarama.Consumer, error) {
+func setupParentConsumerForChannel(retryOptions localconfig.Retry, haltChan chan struct{}, brokers []string, brokerConfig *sarama.Config, dnsRefreshInterval time.Duration, channel channel) (sarama.Consumer, error) {
 	var err error
 	var parentConsumer sarama.Consumer
 
@@ -944,7 +967,7 @@ func setupParentConsumerForChannel(retryOptions localconfig.Retry, haltChan chan
 
 	retryMsg := "Connecting to the Kafka cluster"
 	setupParentConsumer := newRetryProcess(retryOptions, haltChan, channel, retryMsg, func() error {
-		parentConsumer, err = sarama.NewConsumer(brokers, brokerConfig)
+		parentConsumer, err = newConsumer(brokers, brokerConfig, dnsRefreshInterval)
 		return err
 	})
 
@@ -952,7 +975,8 @@ func setupParentConsumerForChannel(retryOptions localconfig.Retry, haltChan chan
 }
 
 // Sets up the writer/producer for a channel using the given retry options.
-func setupProducerForChannel(retryOptions localconfig.Retry, haltChan chan struct{}, brokers []string, brokerConfig *sarama.Config, channel channel) (sarama.SyncProducer, error) {
+// The producer is transactional if the transactional ID prefix isn't empty.
+func setupProducerForChannel(retryOptions localconfig.Retry, haltChan chan struct{}, brokers []string, brokerConfig *sarama.Config, dnsRefreshInterval time.Duration, transactionalIDPrefix string, channel channel) (sarama.SyncProducer, error) {
 	var err error
 	var producer sarama.SyncProducer
 
@@ -960,7 +984,11 @@ func setupProducerForChannel(retryOptions localconfig.Retry, haltChan chan struc
 
 	retryMsg := "Connecting to the Kafka cluster"
 	setupProducer := newRetryProcess(retryOptions, haltChan, channel, retryMsg, func() error {
-		producer, err = sarama.NewSyncProducer(brokers, brokerConfig)
+		if transactionalIDPrefix != "" {
+			producer, err = newTransactionalProducer(brokers, brokerConfig, dnsRefreshInterval, transactionalIDPrefix+"."+channel.String())
+		} else {
+			producer, err = newSyncProducer(brokers, brokerConfig, dnsRefreshInterval)
+		}
 		return err
 	})
 
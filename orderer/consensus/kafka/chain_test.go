@@ -57,7 +57,7 @@ func TestChain(t *testing.T) {
 			"OffsetRequest": sarama.NewMockOffsetResponse(t).
 				SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetOldest, oldestOffset).
 				SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetNewest, newestOffset),
-			"FetchRequest": sarama.NewMockFetchResponse(t, 1).
+			"FetchRequest": sarama.NewMockFetchResponse(t, 1).SetVersion(1).
 				SetMessage(mockChannel.topic(), mockChannel.partition(), newestOffset, message),
 		})
 		mockSupport = &mockmultichannel.ConsenterSupport{
@@ -196,7 +196,7 @@ func TestChain(t *testing.T) {
 			"OffsetRequest": sarama.NewMockOffsetResponse(t).
 				SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetOldest, oldestOffset).
 				SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetNewest, newestOffset),
-			"FetchRequest": sarama.NewMockFetchResponse(t, 1).
+			"FetchRequest": sarama.NewMockFetchResponse(t, 1).SetVersion(1).
 				SetMessage(mockChannel.topic(), mockChannel.partition(), newestOffset, message),
 		})
 
@@ -220,7 +220,7 @@ func TestChain(t *testing.T) {
 			"OffsetRequest": sarama.NewMockOffsetResponse(t).
 				SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetOldest, oldestOffset).
 				SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetNewest, newestOffset),
-			"FetchRequest": sarama.NewMockFetchResponse(t, 1).
+			"FetchRequest": sarama.NewMockFetchResponse(t, 1).SetVersion(1).
 				SetMessage(mockChannel.topic(), mockChannel.partition(), newestOffset, message),
 		})
 
@@ -249,7 +249,7 @@ func TestChain(t *testing.T) {
 			"OffsetRequest": sarama.NewMockOffsetResponse(t).
 				SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetOldest, oldestOffset).
 				SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetNewest, newestOffset),
-			"FetchRequest": sarama.NewMockFetchResponse(t, 1).
+			"FetchRequest": sarama.NewMockFetchResponse(t, 1).SetVersion(1).
 				SetMessage(mockChannel.topic(), mockChannel.partition(), newestOffset, message),
 		})
 
@@ -270,7 +270,7 @@ func TestChain(t *testing.T) {
 			"OffsetRequest": sarama.NewMockOffsetResponse(t).
 				SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetOldest, oldestOffset).
 				SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetNewest, newestOffset),
-			"FetchRequest": sarama.NewMockFetchResponse(t, 1).
+			"FetchRequest": sarama.NewMockFetchResponse(t, 1).SetVersion(1).
 				SetMessage(mockChannel.topic(), mockChannel.partition(), newestOffset, message),
 		})
 
@@ -303,7 +303,7 @@ func TestChain(t *testing.T) {
 			"OffsetRequest": sarama.NewMockOffsetResponse(t).
 				SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetOldest, oldestOffset).
 				SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetNewest, newestOffset),
-			"FetchRequest": sarama.NewMockFetchResponse(t, 1).
+			"FetchRequest": sarama.NewMockFetchResponse(t, 1).SetVersion(1).
 				SetMessage(mockChannel.topic(), mockChannel.partition(), newestOffset, message),
 		})
 
@@ -337,7 +337,7 @@ func TestChain(t *testing.T) {
 			"OffsetRequest": sarama.NewMockOffsetResponse(t).
 				SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetOldest, oldestOffset).
 				SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetNewest, newestOffset),
-			"FetchRequest": sarama.NewMockFetchResponse(t, 1).
+			"FetchRequest": sarama.NewMockFetchResponse(t, 1).SetVersion(1).
 				SetMessage(mockChannel.topic(), mockChannel.partition(), newestOffset, message),
 		})
 
@@ -384,7 +384,7 @@ func TestChain(t *testing.T) {
 				"OffsetRequest": sarama.NewMockOffsetResponse(t).
 					SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetOldest, oldestOffset).
 					SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetNewest, newestOffset),
-				"FetchRequest": sarama.NewMockFetchResponse(t, 1).
+				"FetchRequest": sarama.NewMockFetchResponse(t, 1).SetVersion(1).
 					SetMessage(mockChannel.topic(), mockChannel.partition(), newestOffset, message),
 			})
 
@@ -427,7 +427,7 @@ func TestChain(t *testing.T) {
 				"OffsetRequest": sarama.NewMockOffsetResponse(t).
 					SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetOldest, oldestOffset).
 					SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetNewest, newestOffset),
-				"FetchRequest": sarama.NewMockFetchResponse(t, 1).
+				"FetchRequest": sarama.NewMockFetchResponse(t, 1).SetVersion(1).
 					SetMessage(mockChannel.topic(), mockChannel.partition(), newestOffset, message),
 			})
 
@@ -617,16 +617,16 @@ func TestSetupProducerForChannel(t *testing.T) {
 	t.Run("Proper", func(t *testing.T) {
 		metadataResponse := new(sarama.MetadataResponse)
 		metadataResponse.AddBroker(mockBroker.Addr(), mockBroker.BrokerID())
-		metadataResponse.AddTopicPartition(mockChannel.topic(), mockChannel.partition(), mockBroker.BrokerID(), nil, nil, sarama.ErrNoError)
+		metadataResponse.AddTopicPartition(mockChannel.topic(), mockChannel.partition(), mockBroker.BrokerID(), nil, nil, nil, sarama.ErrNoError)
 		mockBroker.Returns(metadataResponse)
 
-		producer, err := setupProducerForChannel(mockConsenter.retryOptions(), haltChan, []string{mockBroker.Addr()}, mockBrokerConfig, mockChannel)
+		producer, err := setupProducerForChannel(mockConsenter.retryOptions(), haltChan, []string{mockBroker.Addr()}, mockBrokerConfig, 0, "", mockChannel)
 		assert.NoError(t, err, "Expected the setupProducerForChannel call to return without errors")
 		assert.NoError(t, producer.Close(), "Expected to close the producer without errors")
 	})
 
 	t.Run("WithError", func(t *testing.T) {
-		_, err := setupProducerForChannel(mockConsenter.retryOptions(), haltChan, []string{}, mockBrokerConfig, mockChannel)
+		_, err := setupProducerForChannel(mockConsenter.retryOptions(), haltChan, []string{}, mockBrokerConfig, 0, "", mockChannel)
 		assert.Error(t, err, "Expected the setupProducerForChannel call to return an error")
 	})
 }
@@ -650,20 +650,20 @@ func TestSetupConsumerForChannel(t *testing.T) {
 		"OffsetRequest": sarama.NewMockOffsetResponse(t).
 			SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetOldest, oldestOffset).
 			SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetNewest, newestOffset),
-		"FetchRequest": sarama.NewMockFetchResponse(t, 1).
+		"FetchRequest": sarama.NewMockFetchResponse(t, 1).SetVersion(1).
 			SetMessage(mockChannel.topic(), mockChannel.partition(), startFrom, message),
 	})
 
 	haltChan := make(chan struct{})
 
 	t.Run("ProperParent", func(t *testing.T) {
-		parentConsumer, err := setupParentConsumerForChannel(mockConsenter.retryOptions(), haltChan, []string{mockBroker.Addr()}, mockBrokerConfig, mockChannel)
+		parentConsumer, err := setupParentConsumerForChannel(mockConsenter.retryOptions(), haltChan, []string{mockBroker.Addr()}, mockBrokerConfig, 0, mockChannel)
 		assert.NoError(t, err, "Expected the setupParentConsumerForChannel call to return without errors")
 		assert.NoError(t, parentConsumer.Close(), "Expected to close the parentConsumer without errors")
 	})
 
 	t.Run("ProperChannel", func(t *testing.T) {
-		parentConsumer, _ := setupParentConsumerForChannel(mockConsenter.retryOptions(), haltChan, []string{mockBroker.Addr()}, mockBrokerConfig, mockChannel)
+		parentConsumer, _ := setupParentConsumerForChannel(mockConsenter.retryOptions(), haltChan, []string{mockBroker.Addr()}, mockBrokerConfig, 0, mockChannel)
 		defer func() { parentConsumer.Close() }()
 		channelConsumer, err := setupChannelConsumerForChannel(mockConsenter.retryOptions(), haltChan, parentConsumer, mockChannel, newestOffset)
 		assert.NoError(t, err, "Expected the setupChannelConsumerForChannel call to return without errors")
@@ -672,13 +672,13 @@ func TestSetupConsumerForChannel(t *testing.T) {
 
 	t.Run("WithParentConsumerError", func(t *testing.T) {
 		// Provide an empty brokers list
-		_, err := setupParentConsumerForChannel(mockConsenter.retryOptions(), haltChan, []string{}, mockBrokerConfig, mockChannel)
+		_, err := setupParentConsumerForChannel(mockConsenter.retryOptions(), haltChan, []string{}, mockBrokerConfig, 0, mockChannel)
 		assert.Error(t, err, "Expected the setupParentConsumerForChannel call to return an error")
 	})
 
 	t.Run("WithChannelConsumerError", func(t *testing.T) {
 		// Provide an out-of-range offset
-		parentConsumer, _ := setupParentConsumerForChannel(mockConsenter.retryOptions(), haltChan, []string{mockBroker.Addr()}, mockBrokerConfig, mockChannel)
+		parentConsumer, _ := setupParentConsumerForChannel(mockConsenter.retryOptions(), haltChan, []string{mockBroker.Addr()}, mockBrokerConfig, 0, mockChannel)
 		_, err := setupChannelConsumerForChannel(mockConsenter.retryOptions(), haltChan, parentConsumer, mockChannel, newestOffset+1)
 		defer func() { parentConsumer.Close() }()
 		assert.Error(t, err, "Expected the setupChannelConsumerForChannel call to return an error")
@@ -708,15 +708,15 @@ func TestCloseKafkaObjects(t *testing.T) {
 		"OffsetRequest": sarama.NewMockOffsetResponse(t).
 			SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetOldest, oldestOffset).
 			SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetNewest, newestOffset),
-		"FetchRequest": sarama.NewMockFetchResponse(t, 1).
+		"FetchRequest": sarama.NewMockFetchResponse(t, 1).SetVersion(1).
 			SetMessage(mockChannel.topic(), mockChannel.partition(), startFrom, message),
 	})
 
 	haltChan := make(chan struct{})
 
 	t.Run("Proper", func(t *testing.T) {
-		producer, _ := setupProducerForChannel(mockConsenter.retryOptions(), haltChan, []string{mockBroker.Addr()}, mockBrokerConfig, mockChannel)
-		parentConsumer, _ := setupParentConsumerForChannel(mockConsenter.retryOptions(), haltChan, []string{mockBroker.Addr()}, mockBrokerConfig, mockChannel)
+		producer, _ := setupProducerForChannel(mockConsenter.retryOptions(), haltChan, []string{mockBroker.Addr()}, mockBrokerConfig, 0, "", mockChannel)
+		parentConsumer, _ := setupParentConsumerForChannel(mockConsenter.retryOptions(), haltChan, []string{mockBroker.Addr()}, mockBrokerConfig, 0, mockChannel)
 		channelConsumer, _ := setupChannelConsumerForChannel(mockConsenter.retryOptions(), haltChan, parentConsumer, mockChannel, startFrom)
 
 		// Set up a chain with just the minimum necessary fields instantiated so
@@ -842,7 +842,7 @@ func TestSendConnectMessage(t *testing.T) {
 
 	metadataResponse := new(sarama.MetadataResponse)
 	metadataResponse.AddBroker(mockBroker.Addr(), mockBroker.BrokerID())
-	metadataResponse.AddTopicPartition(mockChannel.topic(), mockChannel.partition(), mockBroker.BrokerID(), nil, nil, sarama.ErrNoError)
+	metadataResponse.AddTopicPartition(mockChannel.topic(), mockChannel.partition(), mockBroker.BrokerID(), nil, nil, nil, sarama.ErrNoError)
 	mockBroker.Returns(metadataResponse)
 
 	producer, _ := sarama.NewSyncProducer([]string{mockBroker.Addr()}, mockBrokerConfig)
@@ -881,7 +881,7 @@ func TestSendTimeToCut(t *testing.T) {
 
 	metadataResponse := new(sarama.MetadataResponse)
 	metadataResponse.AddBroker(mockBroker.Addr(), mockBroker.BrokerID())
-	metadataResponse.AddTopicPartition(mockChannel.topic(), mockChannel.partition(), mockBroker.BrokerID(), nil, nil, sarama.ErrNoError)
+	metadataResponse.AddTopicPartition(mockChannel.topic(), mockChannel.partition(), mockBroker.BrokerID(), nil, nil, nil, sarama.ErrNoError)
 	mockBroker.Returns(metadataResponse)
 
 	producer, err := sarama.NewSyncProducer([]string{mockBroker.Addr()}, mockBrokerConfig)
@@ -926,7 +926,7 @@ func TestProcessMessagesToBlocks(t *testing.T) {
 
 	metadataResponse := new(sarama.MetadataResponse)
 	metadataResponse.AddBroker(mockBroker.Addr(), mockBroker.BrokerID())
-	metadataResponse.AddTopicPartition(mockChannel.topic(), mockChannel.partition(), mockBroker.BrokerID(), nil, nil, sarama.ErrNoError)
+	metadataResponse.AddTopicPartition(mockChannel.topic(), mockChannel.partition(), mockBroker.BrokerID(), nil, nil, nil, sarama.ErrNoError)
 	mockBroker.Returns(metadataResponse)
 
 	producer, _ := sarama.NewSyncProducer([]string{mockBroker.Addr()}, mockBrokerConfig)
@@ -3339,13 +3339,13 @@ func TestDeliverSession(t *testing.T) {
 				SetOffset(topic, partition, sarama.OffsetOldest, 0).
 				SetOffset(topic, partition, sarama.OffsetNewest, nextOffset),
 			// respond to fetch requests with empty response while starting up
-			"FetchRequest": sarama.NewMockFetchResponse(t, 1),
+			"FetchRequest": sarama.NewMockFetchResponse(t, 1).SetVersion(1),
 		})
 
 		// configure broker2 with a default fetch request response
 		broker2.SetHandlerByMap(map[string]sarama.MockResponse{
 			// respond to fetch requests with empty response while starting up
-			"FetchRequest": sarama.NewMockFetchResponse(t, 1),
+			"FetchRequest": sarama.NewMockFetchResponse(t, 1).SetVersion(1),
 		})
 
 		// setup mock blockcutter
@@ -3402,7 +3402,7 @@ func TestDeliverSession(t *testing.T) {
 		defer env.broker2.Close()
 
 		// initialize consenter
-		consenter := New(mockLocalConfig.Kafka)
+		consenter := New(mockLocalConfig.Kafka, "")
 
 		// initialize chain
 		metadata := &cb.Metadata{Value: utils.MarshalOrPanic(&ab.KafkaMetadata{LastOffsetPersisted: env.height})}
@@ -3427,7 +3427,7 @@ func TestDeliverSession(t *testing.T) {
 		})
 
 		// send a few messages from broker1
-		fetchResponse1 := sarama.NewMockFetchResponse(t, 1)
+		fetchResponse1 := sarama.NewMockFetchResponse(t, 1).SetVersion(1)
 		for i := 0; i < 5; i++ {
 			fetchResponse1.SetMessage(env.topic, env.partition, env.nextOffset, env.testMsg)
 			env.nextOffset++
@@ -3446,7 +3446,7 @@ func TestDeliverSession(t *testing.T) {
 		}
 
 		// prepare broker2 to send a few messages
-		fetchResponse2 := sarama.NewMockFetchResponse(t, 1)
+		fetchResponse2 := sarama.NewMockFetchResponse(t, 1).SetVersion(1)
 		for i := 0; i < 5; i++ {
 			fetchResponse2.SetMessage(env.topic, env.partition, env.nextOffset, env.testMsg)
 			env.nextOffset++
@@ -3491,7 +3491,7 @@ func TestDeliverSession(t *testing.T) {
 		defer env.broker0.Close()
 
 		// initialize consenter
-		consenter := New(mockLocalConfig.Kafka)
+		consenter := New(mockLocalConfig.Kafka, "")
 
 		// initialize chain
 		metadata := &cb.Metadata{Value: utils.MarshalOrPanic(&ab.KafkaMetadata{LastOffsetPersisted: env.height})}
@@ -3553,7 +3553,7 @@ func TestDeliverSession(t *testing.T) {
 		defer env.broker0.Close()
 
 		// initialize consenter
-		consenter := New(mockLocalConfig.Kafka)
+		consenter := New(mockLocalConfig.Kafka, "")
 
 		// initialize chain
 		metadata := &cb.Metadata{Value: utils.MarshalOrPanic(&ab.KafkaMetadata{LastOffsetPersisted: env.height})}
@@ -3578,7 +3578,7 @@ func TestDeliverSession(t *testing.T) {
 		})
 
 		metadataResponse := new(sarama.MetadataResponse)
-		metadataResponse.AddTopicPartition(env.topic, env.partition, -1, []int32{}, []int32{}, sarama.ErrBrokerNotAvailable)
+		metadataResponse.AddTopicPartition(env.topic, env.partition, -1, []int32{}, []int32{}, []int32{}, sarama.ErrBrokerNotAvailable)
 
 		// configure seed broker to return error on metadata request, otherwise the
 		// consumer client will keep 'subscribing' successfully to the topic/partition
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package kafka
 
 import (
+	"time"
+
 	"github.com/Shopify/sarama"
 	localconfig "github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/consensus"
@@ -14,8 +16,9 @@ import (
 	logging "github.com/op/go-logging"
 )
 
-// New creates a Kafka-based consenter. Called by orderer's main.go.
-func New(config localconfig.Kafka) consensus.Consenter {
+// New creates a Kafka-based consenter. Called by orderer's main.go. The ledger
+// directory is where the offsets set by SetResumeOffset are looked up, if any.
+func New(config localconfig.Kafka, ledgerDir string) consensus.Consenter {
 	if config.Verbose {
 		logging.SetLevel(logging.DEBUG, saramaLogID)
 	}
@@ -25,11 +28,18 @@ func New(config localconfig.Kafka) consensus.Consenter {
 		config.Retry,
 		config.Version,
 		defaultPartition)
+	if config.TransactionalID != "" {
+		// the messages of the aborted transactions are never consumed
+		brokerConfig.Consumer.IsolationLevel = sarama.ReadCommitted
+	}
 	return &consenterImpl{
 		brokerConfigVal: brokerConfig,
 		tlsConfigVal:    config.TLS,
 		retryOptionsVal: config.Retry,
 		kafkaVersionVal: config.Version,
+		dnsRefreshVal:   config.DNSRefreshInterval,
+		transactionalID: config.TransactionalID,
+		ledgerDir:       ledgerDir,
 		topicDetailVal: &sarama.TopicDetail{
 			NumPartitions:     1,
 			ReplicationFactor: config.Topic.ReplicationFactor,
@@ -46,6 +56,9 @@ type consenterImpl struct {
 	retryOptionsVal localconfig.Retry
 	kafkaVersionVal sarama.KafkaVersion
 	topicDetailVal  *sarama.TopicDetail
+	dnsRefreshVal   time.Duration
+	transactionalID string
+	ledgerDir       string
 }
 
 // HandleChain creates/returns a reference to a consensus.Chain object for the
@@ -55,7 +68,24 @@ type consenterImpl struct {
 // existingChains.
 func (consenter *consenterImpl) HandleChain(support consensus.ConsenterSupport, metadata *cb.Metadata) (consensus.Chain, error) {
 	lastOffsetPersisted, lastOriginalOffsetProcessed, lastResubmittedConfigOffset := getOffsets(metadata.Value, support.ChainID())
-	return newChain(consenter, support, lastOffsetPersisted, lastOriginalOffsetProcessed, lastResubmittedConfigOffset)
+	resumeOffset, resumeOffsetPath, err := readResumeOffset(consenter.ledgerDir, support.ChainID())
+	if err != nil {
+		return nil, err
+	}
+	if resumeOffsetPath != "" {
+		logger.Warningf("[channel: %s] Resuming from offset %d set by a repair, rather than after the last persisted offset %d",
+			support.ChainID(), resumeOffset, lastOffsetPersisted)
+		lastOffsetPersisted = resumeOffset - 1
+	}
+	chain, err := newChain(consenter, support, lastOffsetPersisted, lastOriginalOffsetProcessed, lastResubmittedConfigOffset)
+	if err != nil {
+		return nil, err
+	}
+	// the offset is only removed once a block consumed from it is committed,
+	// so that the chain resumes from it again if it stops before that
+	chain.resumeOffsetPath = resumeOffsetPath
+	chain.resumeHeight = support.Height()
+	return chain, nil
 }
 
 // commonConsenter allows us to retrieve the configuration options set on the
@@ -66,6 +96,8 @@ type commonConsenter interface {
 	brokerConfig() *sarama.Config
 	retryOptions() localconfig.Retry
 	topicDetail() *sarama.TopicDetail
+	dnsRefreshInterval() time.Duration
+	transactionalIDPrefix() string
 }
 
 func (consenter *consenterImpl) brokerConfig() *sarama.Config {
@@ -80,6 +112,14 @@ func (consenter *consenterImpl) topicDetail() *sarama.TopicDetail {
 	return consenter.topicDetailVal
 }
 
+func (consenter *consenterImpl) dnsRefreshInterval() time.Duration {
+	return consenter.dnsRefreshVal
+}
+
+func (consenter *consenterImpl) transactionalIDPrefix() string {
+	return consenter.transactionalID
+}
+
 // closeable allows the shut down of the calling resource.
 type closeable interface {
 	close() error
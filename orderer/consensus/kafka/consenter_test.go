@@ -69,11 +69,17 @@ func init() {
 }
 
 func TestNew(t *testing.T) {
-	_ = consensus.Consenter(New(mockLocalConfig.Kafka))
+	_ = consensus.Consenter(New(mockLocalConfig.Kafka, ""))
+
+	kafkaConfig := mockLocalConfig.Kafka
+	kafkaConfig.TransactionalID = "orderer0"
+	consenter := New(kafkaConfig, "").(*consenterImpl)
+	assert.Equal(t, "orderer0", consenter.transactionalIDPrefix())
+	assert.Equal(t, sarama.ReadCommitted, consenter.brokerConfig().Consumer.IsolationLevel)
 }
 
 func TestHandleChain(t *testing.T) {
-	consenter := consensus.Consenter(New(mockLocalConfig.Kafka))
+	consenter := consensus.Consenter(New(mockLocalConfig.Kafka, ""))
 
 	oldestOffset := int64(0)
 	newestOffset := int64(5)
@@ -91,7 +97,7 @@ func TestHandleChain(t *testing.T) {
 		"OffsetRequest": sarama.NewMockOffsetResponse(t).
 			SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetOldest, oldestOffset).
 			SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetNewest, newestOffset),
-		"FetchRequest": sarama.NewMockFetchResponse(t, 1).
+		"FetchRequest": sarama.NewMockFetchResponse(t, 1).SetVersion(1).
 			SetMessage(mockChannel.topic(), mockChannel.partition(), newestOffset, message),
 	})
 
@@ -0,0 +1,166 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+)
+
+// lookupHost resolves the IP addresses of a host name
+var lookupHost = net.LookupHost
+
+// newSyncProducer returns a producer of the brokers, whose connections are
+// replaced once the host names of the brokers resolve to different IP
+// addresses if the DNS refresh interval isn't zero
+func newSyncProducer(brokers []string, brokerConfig *sarama.Config, dnsRefreshInterval time.Duration) (sarama.SyncProducer, error) {
+	if dnsRefreshInterval <= 0 {
+		return sarama.NewSyncProducer(brokers, brokerConfig)
+	}
+	client, err := newRefreshingClient(brokers, brokerConfig, dnsRefreshInterval)
+	if err != nil {
+		return nil, err
+	}
+	producer, err := sarama.NewSyncProducerFromClient(client)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return &clientClosingProducer{SyncProducer: producer, client: client}, nil
+}
+
+// newConsumer returns a consumer of the brokers, whose connections are
+// replaced once the host names of the brokers resolve to different IP
+// addresses if the DNS refresh interval isn't zero
+func newConsumer(brokers []string, brokerConfig *sarama.Config, dnsRefreshInterval time.Duration) (sarama.Consumer, error) {
+	if dnsRefreshInterval <= 0 {
+		return sarama.NewConsumer(brokers, brokerConfig)
+	}
+	client, err := newRefreshingClient(brokers, brokerConfig, dnsRefreshInterval)
+	if err != nil {
+		return nil, err
+	}
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return &clientClosingConsumer{Consumer: consumer, client: client}, nil
+}
+
+// newRefreshingClient returns a client of the brokers which resolves the host
+// names of the brokers it is connected to again every interval. The
+// connections to the brokers whose host name no longer resolves to the IP
+// addresses it resolved to once they were connected are closed, so that the
+// client connects to them again on its next request.
+func newRefreshingClient(brokers []string, brokerConfig *sarama.Config, interval time.Duration) (sarama.Client, error) {
+	client, err := sarama.NewClient(brokers, brokerConfig)
+	if err != nil {
+		return nil, err
+	}
+	refreshing := &refreshingClient{Client: client, done: make(chan struct{})}
+	go watchBrokers(client, interval, refreshing.done)
+	return refreshing, nil
+}
+
+// refreshingClient stops watching the host names of the brokers when closed
+type refreshingClient struct {
+	sarama.Client
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (c *refreshingClient) Close() error {
+	c.closeOnce.Do(func() { close(c.done) })
+	return c.Client.Close()
+}
+
+// watchBrokers closes the connections to the brokers of the client whose host
+// name resolves to different IP addresses than at the previous interval, until
+// done is closed
+func watchBrokers(client sarama.Client, interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	resolved := make(map[string][]string)
+	for {
+		select {
+		case <-ticker.C:
+		case <-done:
+			return
+		}
+
+		for _, broker := range client.Brokers() {
+			addr := broker.Addr()
+			host, _, err := net.SplitHostPort(addr)
+			if err != nil || net.ParseIP(host) != nil {
+				continue
+			}
+			if connected, _ := broker.Connected(); !connected {
+				delete(resolved, addr)
+				continue
+			}
+			ips, err := lookupHost(host)
+			if err != nil {
+				logger.Debugf("Failed resolving Kafka broker %s again: %s", host, err)
+				continue
+			}
+			previous, known := resolved[addr]
+			if known && !resolvesToAny(ips, previous) {
+				logger.Infof("Kafka broker %s now resolves to %v instead of %v, closing its connection", host, ips, previous)
+				broker.Close()
+				delete(resolved, addr)
+				continue
+			}
+			resolved[addr] = ips
+		}
+	}
+}
+
+func resolvesToAny(ips []string, previous []string) bool {
+	for _, ip := range ips {
+		for _, p := range previous {
+			if ip == p {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// clientClosingProducer is a producer created from a client, which closes
+// the client when closed
+type clientClosingProducer struct {
+	sarama.SyncProducer
+	client sarama.Client
+}
+
+func (p *clientClosingProducer) Close() error {
+	err := p.SyncProducer.Close()
+	if clientErr := p.client.Close(); err == nil {
+		err = clientErr
+	}
+	return err
+}
+
+// clientClosingConsumer is a consumer created from a client, which closes
+// the client when closed
+type clientClosingConsumer struct {
+	sarama.Consumer
+	client sarama.Client
+}
+
+func (c *clientClosingConsumer) Close() error {
+	err := c.Consumer.Close()
+	if clientErr := c.client.Close(); err == nil {
+		err = clientErr
+	}
+	return err
+}
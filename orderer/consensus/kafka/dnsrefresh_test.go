@@ -0,0 +1,100 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type brokersClient struct {
+	sarama.Client
+	brokers []*sarama.Broker
+}
+
+func (c *brokersClient) Brokers() []*sarama.Broker {
+	return c.brokers
+}
+
+func TestWatchBrokers(t *testing.T) {
+	mockBroker := sarama.NewMockBroker(t, 0)
+	defer mockBroker.Close()
+	_, port, err := net.SplitHostPort(mockBroker.Addr())
+	require.NoError(t, err)
+
+	var lock sync.Mutex
+	ips := []string{"127.0.0.1"}
+	lookupHost = func(host string) ([]string, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		return ips, nil
+	}
+	defer func() { lookupHost = net.LookupHost }()
+
+	byName := sarama.NewBroker(net.JoinHostPort("localhost", port))
+	require.NoError(t, byName.Open(mockBrokerConfig))
+	defer byName.Close()
+	byIP := sarama.NewBroker(mockBroker.Addr())
+	require.NoError(t, byIP.Open(mockBrokerConfig))
+	defer byIP.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go watchBrokers(&brokersClient{brokers: []*sarama.Broker{byName, byIP}}, 10*time.Millisecond, done)
+
+	time.Sleep(100 * time.Millisecond)
+	connected, _ := byName.Connected()
+	assert.True(t, connected, "broker still resolving to the same IP address was closed")
+
+	lock.Lock()
+	ips = []string{"10.0.0.1"}
+	lock.Unlock()
+	deadline := time.Now().Add(time.Second)
+	for connected && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		connected, _ = byName.Connected()
+	}
+	assert.False(t, connected, "broker resolving to another IP address wasn't closed")
+
+	// the brokers addressed by IP aren't watched
+	connected, _ = byIP.Connected()
+	assert.True(t, connected)
+}
+
+func TestNewSyncProducerDNSRefresh(t *testing.T) {
+	mockChannel := newChannel(channelNameForTest(t), defaultPartition)
+	mockBroker := sarama.NewMockBroker(t, 0)
+	defer mockBroker.Close()
+	mockBroker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(mockBroker.Addr(), mockBroker.BrokerID()).
+			SetLeader(mockChannel.topic(), mockChannel.partition(), mockBroker.BrokerID()),
+		"OffsetRequest": sarama.NewMockOffsetResponse(t).
+			SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetNewest, 0),
+	})
+
+	producer, err := newSyncProducer([]string{mockBroker.Addr()}, mockBrokerConfig, time.Minute)
+	require.NoError(t, err)
+	assert.IsType(t, &clientClosingProducer{}, producer)
+	assert.NoError(t, producer.Close())
+
+	consumer, err := newConsumer([]string{mockBroker.Addr()}, mockBrokerConfig, time.Minute)
+	require.NoError(t, err)
+	assert.IsType(t, &clientClosingConsumer{}, consumer)
+	assert.NoError(t, consumer.Close())
+
+	_, err = newSyncProducer([]string{}, mockBrokerConfig, time.Minute)
+	assert.Error(t, err)
+	_, err = newConsumer([]string{}, mockBrokerConfig, time.Minute)
+	assert.Error(t, err)
+}
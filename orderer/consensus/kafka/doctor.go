@@ -0,0 +1,138 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka
+
+import (
+	"fmt"
+
+	"github.com/Shopify/sarama"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	localconfig "github.com/hyperledger/fabric/orderer/common/localconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// Inconsistency is an inconsistency between the Kafka offsets recorded in the
+// blocks of a channel, or between them and the partition of the channel.
+type Inconsistency struct {
+	// Block is the number of the block where the inconsistency was found
+	Block uint64
+	// Description describes the inconsistency
+	Description string
+}
+
+func (i Inconsistency) String() string {
+	return fmt.Sprintf("block %d: %s", i.Block, i.Description)
+}
+
+// PartitionOffsets are the offsets of the partition of a channel.
+type PartitionOffsets struct {
+	// Oldest is the offset of the oldest message still in the partition
+	Oldest int64
+	// Newest is the offset the next message of the partition will have
+	Newest int64
+}
+
+// GetPartitionOffsets retrieves the offsets of the partition of the given
+// channel from the given brokers.
+func GetPartitionOffsets(config localconfig.Kafka, tlsConfig localconfig.TLS, brokers []string, chainID string) (*PartitionOffsets, error) {
+	brokerConfig := newBrokerConfig(tlsConfig, config.SASLPlain, config.Retry, config.Version, defaultPartition)
+	client, err := sarama.NewClient(brokers, brokerConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed connecting to the Kafka brokers")
+	}
+	defer client.Close()
+
+	channel := newChannel(chainID, defaultPartition)
+	oldest, err := client.GetOffset(channel.topic(), channel.partition(), sarama.OffsetOldest)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed retrieving the oldest offset of %s", channel)
+	}
+	newest, err := client.GetOffset(channel.topic(), channel.partition(), sarama.OffsetNewest)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed retrieving the newest offset of %s", channel)
+	}
+	return &PartitionOffsets{Oldest: oldest, Newest: newest}, nil
+}
+
+// Diagnose reports the inconsistencies of the Kafka offsets recorded in the
+// blocks of the given channel: the blocks which don't persist an offset after
+// the one of their previous block, which are duplicates of messages already
+// cut into blocks, and the recorded offsets which contradict each other. If the
+// offsets of the partition of the channel are given, it also reports whether
+// the chain can't resume consuming the partition after its last block, because
+// messages were deleted from the partition before being cut into blocks, or
+// because the partition doesn't have the messages of the last blocks.
+func Diagnose(reader blockledger.Reader, partition *PartitionOffsets) ([]Inconsistency, error) {
+	var inconsistencies []Inconsistency
+	report := func(block uint64, format string, args ...interface{}) {
+		inconsistencies = append(inconsistencies, Inconsistency{Block: block, Description: fmt.Sprintf(format, args...)})
+	}
+
+	height := reader.Height()
+	if height < 2 {
+		// the genesis block records no offset
+		return nil, nil
+	}
+
+	previous := &ab.KafkaMetadata{LastOffsetPersisted: sarama.OffsetOldest - 1}
+	previousBlock := uint64(0)
+	iterator, _ := reader.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: 1}}})
+	defer iterator.Close()
+	for number := uint64(1); number < height; number++ {
+		block, status := iterator.Next()
+		if status != cb.Status_SUCCESS {
+			return nil, errors.Errorf("failed reading block %d: %s", number, status)
+		}
+
+		metadata, err := utils.GetMetadataFromBlock(block, cb.BlockMetadataIndex_ORDERER)
+		if err != nil || len(metadata.Value) == 0 {
+			report(number, "the block records no Kafka offsets")
+			continue
+		}
+		current := &ab.KafkaMetadata{}
+		if err := proto.Unmarshal(metadata.Value, current); err != nil {
+			report(number, "the Kafka offsets of the block are malformed: %s", err)
+			continue
+		}
+
+		if current.LastOffsetPersisted <= previous.LastOffsetPersisted {
+			report(number, "last offset persisted %d is not after the offset %d of block %d, the block duplicates messages already cut into blocks",
+				current.LastOffsetPersisted, previous.LastOffsetPersisted, previousBlock)
+		}
+		if current.LastOriginalOffsetProcessed < previous.LastOriginalOffsetProcessed {
+			report(number, "last original offset processed %d is before the offset %d of block %d",
+				current.LastOriginalOffsetProcessed, previous.LastOriginalOffsetProcessed, previousBlock)
+		}
+		if current.LastOriginalOffsetProcessed > current.LastOffsetPersisted {
+			report(number, "last original offset processed %d is after the last offset persisted %d",
+				current.LastOriginalOffsetProcessed, current.LastOffsetPersisted)
+		}
+		if current.LastResubmittedConfigOffset > current.LastOffsetPersisted {
+			report(number, "last resubmitted config offset %d is after the last offset persisted %d",
+				current.LastResubmittedConfigOffset, current.LastOffsetPersisted)
+		}
+		previous, previousBlock = current, number
+	}
+
+	if partition != nil && previousBlock != 0 {
+		next := previous.LastOffsetPersisted + 1
+		switch {
+		case next < partition.Oldest:
+			report(previousBlock, "the messages from offset %d to %d were deleted from the partition before being cut into blocks, resuming would skip them",
+				next, partition.Oldest-1)
+		case next > partition.Newest:
+			report(previousBlock, "the last offset persisted %d is beyond the newest offset %d of the partition, which may have been truncated or recreated",
+				previous.LastOffsetPersisted, partition.Newest-1)
+		}
+	}
+
+	return inconsistencies, nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka
+
+import (
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	ramledger "github.com/hyperledger/fabric/common/ledger/blockledger/ram"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDiagnosedLedger returns a ledger whose blocks after the genesis block
+// record the given Kafka metadata, a nil metadata records none
+func newDiagnosedLedger(t *testing.T, metadata ...*ab.KafkaMetadata) blockledger.ReadWriter {
+	rl, err := ramledger.New(10).GetOrCreate("foo")
+	require.NoError(t, err)
+	require.NoError(t, rl.Append(blockledger.CreateNextBlock(rl, []*cb.Envelope{{Payload: []byte("genesis")}})))
+	for _, md := range metadata {
+		block := blockledger.CreateNextBlock(rl, []*cb.Envelope{{Payload: []byte("tx")}})
+		if md != nil {
+			block.Metadata.Metadata[cb.BlockMetadataIndex_ORDERER] = utils.MarshalOrPanic(&cb.Metadata{Value: utils.MarshalOrPanic(md)})
+		}
+		require.NoError(t, rl.Append(block))
+	}
+	return rl
+}
+
+func TestDiagnoseConsistent(t *testing.T) {
+	rl := newDiagnosedLedger(t,
+		&ab.KafkaMetadata{LastOffsetPersisted: 2},
+		&ab.KafkaMetadata{LastOffsetPersisted: 5, LastOriginalOffsetProcessed: 3, LastResubmittedConfigOffset: 3},
+		&ab.KafkaMetadata{LastOffsetPersisted: 6, LastOriginalOffsetProcessed: 3, LastResubmittedConfigOffset: 3},
+	)
+
+	inconsistencies, err := Diagnose(rl, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, inconsistencies)
+
+	inconsistencies, err = Diagnose(rl, &PartitionOffsets{Oldest: 0, Newest: 7})
+	assert.NoError(t, err)
+	assert.Empty(t, inconsistencies)
+
+	// only the genesis block
+	inconsistencies, err = Diagnose(newDiagnosedLedger(t), &PartitionOffsets{Oldest: 10, Newest: 20})
+	assert.NoError(t, err)
+	assert.Empty(t, inconsistencies)
+}
+
+func TestDiagnoseInconsistent(t *testing.T) {
+	rl := newDiagnosedLedger(t,
+		&ab.KafkaMetadata{LastOffsetPersisted: 5, LastOriginalOffsetProcessed: 4},
+		&ab.KafkaMetadata{LastOffsetPersisted: 5, LastOriginalOffsetProcessed: 4},
+		nil,
+		&ab.KafkaMetadata{LastOffsetPersisted: 8, LastOriginalOffsetProcessed: 2, LastResubmittedConfigOffset: 9},
+		&ab.KafkaMetadata{LastOffsetPersisted: 9, LastOriginalOffsetProcessed: 10},
+	)
+
+	inconsistencies, err := Diagnose(rl, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []Inconsistency{
+		{Block: 2, Description: "last offset persisted 5 is not after the offset 5 of block 1, the block duplicates messages already cut into blocks"},
+		{Block: 3, Description: "the block records no Kafka offsets"},
+		{Block: 4, Description: "last original offset processed 2 is before the offset 4 of block 2"},
+		{Block: 4, Description: "last resubmitted config offset 9 is after the last offset persisted 8"},
+		{Block: 5, Description: "last original offset processed 10 is after the last offset persisted 9"},
+	}, inconsistencies)
+	assert.Equal(t, "block 3: the block records no Kafka offsets", inconsistencies[1].String())
+}
+
+func TestDiagnosePartition(t *testing.T) {
+	rl := newDiagnosedLedger(t, &ab.KafkaMetadata{LastOffsetPersisted: 5})
+
+	inconsistencies, err := Diagnose(rl, &PartitionOffsets{Oldest: 10, Newest: 20})
+	assert.NoError(t, err)
+	assert.Equal(t, []Inconsistency{
+		{Block: 1, Description: "the messages from offset 6 to 9 were deleted from the partition before being cut into blocks, resuming would skip them"},
+	}, inconsistencies)
+
+	inconsistencies, err = Diagnose(rl, &PartitionOffsets{Oldest: 0, Newest: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, []Inconsistency{
+		{Block: 1, Description: "the last offset persisted 5 is beyond the newest offset 2 of the partition, which may have been truncated or recreated"},
+	}, inconsistencies)
+}
+
+func TestGetPartitionOffsets(t *testing.T) {
+	mockChannel := newChannel(channelNameForTest(t), defaultPartition)
+	mockBroker := sarama.NewMockBroker(t, 0)
+	defer mockBroker.Close()
+	mockBroker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(mockBroker.Addr(), mockBroker.BrokerID()).
+			SetLeader(mockChannel.topic(), mockChannel.partition(), mockBroker.BrokerID()),
+		"OffsetRequest": sarama.NewMockOffsetResponse(t).
+			SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetOldest, 3).
+			SetOffset(mockChannel.topic(), mockChannel.partition(), sarama.OffsetNewest, 8),
+	})
+
+	offsets, err := GetPartitionOffsets(mockLocalConfig.Kafka, mockLocalConfig.General.TLS, []string{mockBroker.Addr()}, mockChannel.topic())
+	assert.NoError(t, err)
+	assert.Equal(t, &PartitionOffsets{Oldest: 3, Newest: 8}, offsets)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ResumeOffsetsDir is the directory of the ledger where the offsets the chains
+// must resume consuming their partition from are recorded
+const ResumeOffsetsDir = "kafkaResumeOffsets"
+
+// SetResumeOffset records that the chain of the given channel must resume
+// consuming its partition from the given offset at its next start, rather than
+// after the last offset persisted in its ledger. It repairs a chain which can't
+// resume consuming its partition, e.g. because the partition was recreated. All
+// the orderers of the channel must resume from the same offset, or they fork.
+func SetResumeOffset(ledgerDir, chainID string, offset int64) error {
+	if offset < 0 {
+		return errors.Errorf("invalid offset %d", offset)
+	}
+	dir := filepath.Join(ledgerDir, ResumeOffsetsDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrapf(err, "failed creating %s", dir)
+	}
+	path := resumeOffsetPath(ledgerDir, chainID)
+	if err := ioutil.WriteFile(path, []byte(strconv.FormatInt(offset, 10)), 0644); err != nil {
+		return errors.Wrapf(err, "failed writing %s", path)
+	}
+	return nil
+}
+
+// readResumeOffset returns the offset recorded by SetResumeOffset for the given
+// channel, if any, along with the path it is recorded at
+func readResumeOffset(ledgerDir, chainID string) (int64, string, error) {
+	if ledgerDir == "" {
+		return 0, "", nil
+	}
+	path := resumeOffsetPath(ledgerDir, chainID)
+	raw, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, "", nil
+	}
+	if err != nil {
+		return 0, "", errors.Wrapf(err, "failed reading %s", path)
+	}
+	offset, err := strconv.ParseInt(strings.TrimSpace(string(raw)), 10, 64)
+	if err != nil {
+		return 0, "", errors.Wrapf(err, "malformed resume offset in %s", path)
+	}
+	return offset, path, nil
+}
+
+func resumeOffsetPath(ledgerDir, chainID string) string {
+	return filepath.Join(ledgerDir, ResumeOffsetsDir, chainID)
+}
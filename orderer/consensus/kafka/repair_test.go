@@ -0,0 +1,85 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
+	mockmultichannel "github.com/hyperledger/fabric/orderer/mocks/common/multichannel"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResumeOffset(t *testing.T) {
+	ledgerDir, err := ioutil.TempDir("", "kafka-repair")
+	require.NoError(t, err)
+	defer os.RemoveAll(ledgerDir)
+
+	_, path, err := readResumeOffset(ledgerDir, "foo")
+	assert.NoError(t, err)
+	assert.Empty(t, path)
+
+	assert.EqualError(t, SetResumeOffset(ledgerDir, "foo", -1), "invalid offset -1")
+	assert.NoError(t, SetResumeOffset(ledgerDir, "foo", 42))
+	_, path, err = readResumeOffset(ledgerDir, "bar")
+	assert.NoError(t, err)
+	assert.Empty(t, path)
+
+	offset, path, err := readResumeOffset(ledgerDir, "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(ledgerDir, ResumeOffsetsDir, "foo"), path)
+	assert.Equal(t, int64(42), offset)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(ledgerDir, ResumeOffsetsDir, "foo"), []byte("garbage"), 0644))
+	_, _, err = readResumeOffset(ledgerDir, "foo")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "malformed resume offset in")
+
+	_, path, err = readResumeOffset("", "foo")
+	assert.NoError(t, err)
+	assert.Empty(t, path)
+}
+
+func TestHandleChainResumeOffset(t *testing.T) {
+	ledgerDir, err := ioutil.TempDir("", "kafka-repair")
+	require.NoError(t, err)
+	defer os.RemoveAll(ledgerDir)
+
+	consenter := New(mockLocalConfig.Kafka, ledgerDir)
+	mockSupport := &mockmultichannel.ConsenterSupport{
+		ChainIDVal:      "foo",
+		HeightVal:       uint64(3),
+		SharedConfigVal: &mockconfig.Orderer{},
+	}
+	mockMetadata := &cb.Metadata{Value: utils.MarshalOrPanic(&ab.KafkaMetadata{LastOffsetPersisted: 9})}
+
+	require.NoError(t, SetResumeOffset(ledgerDir, "foo", 4))
+	chain, err := consenter.HandleChain(mockSupport, mockMetadata)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), chain.(*chainImpl).lastOffsetPersisted)
+
+	// the offset isn't removed until a block is committed
+	chain.(*chainImpl).releaseResumeOffset()
+	chain, err = consenter.HandleChain(mockSupport, mockMetadata)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), chain.(*chainImpl).lastOffsetPersisted)
+
+	mockSupport.HeightVal++
+	chain.(*chainImpl).releaseResumeOffset()
+	_, err = os.Stat(filepath.Join(ledgerDir, ResumeOffsetsDir, "foo"))
+	assert.True(t, os.IsNotExist(err))
+	chain, err = consenter.HandleChain(mockSupport, mockMetadata)
+	require.NoError(t, err)
+	assert.Equal(t, int64(9), chain.(*chainImpl).lastOffsetPersisted)
+}
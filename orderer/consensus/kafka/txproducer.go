@@ -0,0 +1,272 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/pkg/errors"
+)
+
+// transactionalProducer is a sarama.SyncProducer which posts every message in
+// a Kafka transaction of its own. Its transactional ID stays the same across
+// the restarts of the orderer, so that initializing it aborts the transaction
+// a previous instance of the producer left open, and fences that instance off.
+// A post which fails is aborted before it is retried, so that the consumers,
+// which only read the committed messages, neither read a message twice nor a
+// message whose post failed.
+type transactionalProducer struct {
+	client          sarama.Client
+	transactionalID string
+
+	mutex         sync.Mutex
+	coordinator   *sarama.Broker
+	producerID    int64
+	producerEpoch int16
+	// sequences holds the sequence number of the next batch of the producer,
+	// per topic and partition
+	sequences map[string]map[int32]int32
+}
+
+// newTransactionalProducer returns a transactional producer of the brokers with
+// the given transactional ID, which must be unique to the orderer and channel
+func newTransactionalProducer(brokers []string, brokerConfig *sarama.Config, dnsRefreshInterval time.Duration, transactionalID string) (sarama.SyncProducer, error) {
+	if !brokerConfig.Version.IsAtLeast(sarama.V0_11_0_0) {
+		return nil, errors.New("transactional producers require Kafka version 0.11.0.0 or higher")
+	}
+	var client sarama.Client
+	var err error
+	if dnsRefreshInterval > 0 {
+		client, err = newRefreshingClient(brokers, brokerConfig, dnsRefreshInterval)
+	} else {
+		client, err = sarama.NewClient(brokers, brokerConfig)
+	}
+	if err != nil {
+		return nil, err
+	}
+	producer := &transactionalProducer{
+		client:          client,
+		transactionalID: transactionalID,
+		producerID:      -1,
+	}
+	// initializing the producer aborts the transaction left open, if any,
+	// before any message is posted
+	if err := producer.init(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return producer, nil
+}
+
+// init looks up the transaction coordinator of the producer, and obtains the
+// producer ID and the epoch of the producer from it
+func (p *transactionalProducer) init() error {
+	controller, err := p.client.Controller()
+	if err != nil {
+		return errors.Wrap(err, "failed connecting to the controller")
+	}
+	findResp, err := controller.FindCoordinator(&sarama.FindCoordinatorRequest{
+		Version:         1,
+		CoordinatorKey:  p.transactionalID,
+		CoordinatorType: sarama.CoordinatorTransaction,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed finding the transaction coordinator")
+	}
+	if findResp.Err != sarama.ErrNoError {
+		return errors.Wrap(findResp.Err, "failed finding the transaction coordinator")
+	}
+
+	coordinator := findResp.Coordinator
+	if err := coordinator.Open(p.client.Config()); err != nil && err != sarama.ErrAlreadyConnected {
+		return errors.Wrap(err, "failed connecting to the transaction coordinator")
+	}
+	initResp, err := coordinator.InitProducerID(&sarama.InitProducerIDRequest{
+		TransactionalID:    &p.transactionalID,
+		TransactionTimeout: p.client.Config().Producer.Timeout,
+	})
+	if err == nil && initResp.Err != sarama.ErrNoError {
+		err = initResp.Err
+	}
+	if err != nil {
+		coordinator.Close()
+		return errors.Wrap(err, "failed initializing the producer ID")
+	}
+
+	if p.coordinator != nil && p.coordinator != coordinator {
+		p.coordinator.Close()
+	}
+	p.coordinator = coordinator
+	p.producerID = initResp.ProducerID
+	p.producerEpoch = initResp.ProducerEpoch
+	p.sequences = make(map[string]map[int32]int32)
+	return nil
+}
+
+// SendMessage posts the message in a transaction of its own, and returns the
+// partition and the offset it is posted at
+func (p *transactionalProducer) SendMessage(msg *sarama.ProducerMessage) (partition int32, offset int64, err error) {
+	err = p.SendMessages([]*sarama.ProducerMessage{msg})
+	return msg.Partition, msg.Offset, err
+}
+
+// SendMessages posts the messages in a single transaction, and sets the
+// partition and the offset they are posted at
+func (p *transactionalProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	retry := p.client.Config().Producer.Retry
+	var err error
+	for attempt := 0; attempt <= retry.Max; attempt++ {
+		if attempt > 0 {
+			time.Sleep(retry.Backoff)
+		}
+		if p.producerID < 0 {
+			if err = p.init(); err != nil {
+				continue
+			}
+		}
+		if err = p.sendInTransaction(msgs); err == nil {
+			return nil
+		}
+		logger.Debugf("Failed posting %d messages in transaction of %s, aborting it: %s", len(msgs), p.transactionalID, err)
+		// the producer is initialized again before the next attempt, which
+		// aborts the transaction if ending it fails, and resets the sequences
+		p.endTransaction(false)
+		p.producerID = -1
+		p.client.RefreshMetadata()
+	}
+	return err
+}
+
+func (p *transactionalProducer) sendInTransaction(msgs []*sarama.ProducerMessage) error {
+	conf := p.client.Config()
+	topicPartitions := make(map[string][]int32)
+	for _, msg := range msgs {
+		partitions, err := p.client.Partitions(msg.Topic)
+		if err != nil {
+			return err
+		}
+		msg.Partition, err = conf.Producer.Partitioner(msg.Topic).Partition(msg, int32(len(partitions)))
+		if err != nil {
+			return err
+		}
+		topicPartitions[msg.Topic] = append(topicPartitions[msg.Topic], msg.Partition)
+	}
+
+	addResp, err := p.coordinator.AddPartitionsToTxn(&sarama.AddPartitionsToTxnRequest{
+		TransactionalID: p.transactionalID,
+		ProducerID:      p.producerID,
+		ProducerEpoch:   p.producerEpoch,
+		TopicPartitions: topicPartitions,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed adding the partitions to the transaction")
+	}
+	for _, partitionErrs := range addResp.Errors {
+		for _, partitionErr := range partitionErrs {
+			if partitionErr.Err != sarama.ErrNoError {
+				return errors.Wrap(partitionErr.Err, "failed adding the partitions to the transaction")
+			}
+		}
+	}
+
+	for _, msg := range msgs {
+		if err := p.produce(msg); err != nil {
+			return err
+		}
+	}
+	return p.endTransaction(true)
+}
+
+func (p *transactionalProducer) produce(msg *sarama.ProducerMessage) error {
+	conf := p.client.Config()
+	record := &sarama.Record{}
+	var err error
+	if msg.Key != nil {
+		if record.Key, err = msg.Key.Encode(); err != nil {
+			return err
+		}
+	}
+	if msg.Value != nil {
+		if record.Value, err = msg.Value.Encode(); err != nil {
+			return err
+		}
+	}
+
+	if p.sequences[msg.Topic] == nil {
+		p.sequences[msg.Topic] = make(map[int32]int32)
+	}
+	now := time.Now()
+	request := &sarama.ProduceRequest{
+		TransactionalID: &p.transactionalID,
+		RequiredAcks:    conf.Producer.RequiredAcks,
+		Timeout:         int32(conf.Producer.Timeout / time.Millisecond),
+		Version:         3,
+	}
+	request.AddBatch(msg.Topic, msg.Partition, &sarama.RecordBatch{
+		Version:         2,
+		ProducerID:      p.producerID,
+		ProducerEpoch:   p.producerEpoch,
+		FirstSequence:   p.sequences[msg.Topic][msg.Partition],
+		IsTransactional: true,
+		FirstTimestamp:  now,
+		MaxTimestamp:    now,
+		Records:         []*sarama.Record{record},
+	})
+
+	leader, err := p.client.Leader(msg.Topic, msg.Partition)
+	if err != nil {
+		return err
+	}
+	response, err := leader.Produce(request)
+	if err != nil {
+		return errors.Wrapf(err, "failed posting to partition %d of %s", msg.Partition, msg.Topic)
+	}
+	block := response.GetBlock(msg.Topic, msg.Partition)
+	if block == nil {
+		return errors.Errorf("no response for partition %d of %s", msg.Partition, msg.Topic)
+	}
+	if block.Err != sarama.ErrNoError {
+		return errors.Wrapf(block.Err, "failed posting to partition %d of %s", msg.Partition, msg.Topic)
+	}
+	p.sequences[msg.Topic][msg.Partition]++
+	msg.Offset = block.Offset
+	msg.Timestamp = now
+	return nil
+}
+
+// endTransaction commits or aborts the ongoing transaction of the producer
+func (p *transactionalProducer) endTransaction(commit bool) error {
+	response, err := p.coordinator.EndTxn(&sarama.EndTxnRequest{
+		TransactionalID:   p.transactionalID,
+		ProducerID:        p.producerID,
+		ProducerEpoch:     p.producerEpoch,
+		TransactionResult: commit,
+	})
+	if err == nil && response.Err != sarama.ErrNoError {
+		err = response.Err
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed ending the transaction")
+	}
+	return nil
+}
+
+// Close closes the connections of the producer to the brokers
+func (p *transactionalProducer) Close() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.coordinator != nil {
+		p.coordinator.Close()
+	}
+	return p.client.Close()
+}
@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka
+
+import (
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionalProducer(t *testing.T) {
+	mockChannel := newChannel(channelNameForTest(t), defaultPartition)
+	brokerConfig := newMockBrokerConfig(
+		mockLocalConfig.General.TLS,
+		mockLocalConfig.Kafka.SASLPlain,
+		mockLocalConfig.Kafka.Retry,
+		sarama.V0_11_0_0,
+		defaultPartition)
+	brokerConfig.Producer.Retry.Max = 1
+	brokerConfig.Producer.Retry.Backoff = 0
+
+	setupBroker := func(t *testing.T, produceErr sarama.KError) *sarama.MockBroker {
+		mockBroker := sarama.NewMockBroker(t, 0)
+		mockBroker.SetHandlerByMap(map[string]sarama.MockResponse{
+			"MetadataRequest": sarama.NewMockMetadataResponse(t).
+				SetBroker(mockBroker.Addr(), mockBroker.BrokerID()).
+				SetController(mockBroker.BrokerID()).
+				SetLeader(mockChannel.topic(), mockChannel.partition(), mockBroker.BrokerID()),
+			"FindCoordinatorRequest": sarama.NewMockWrapper(&sarama.FindCoordinatorResponse{
+				Version:     1,
+				Coordinator: sarama.NewBroker(mockBroker.Addr()),
+			}),
+			"InitProducerIDRequest": sarama.NewMockWrapper(&sarama.InitProducerIDResponse{
+				ProducerID:    7,
+				ProducerEpoch: 1,
+			}),
+			"AddPartitionsToTxnRequest": sarama.NewMockWrapper(&sarama.AddPartitionsToTxnResponse{}),
+			"ProduceRequest": sarama.NewMockProduceResponse(t).
+				SetVersion(3).
+				SetError(mockChannel.topic(), mockChannel.partition(), produceErr),
+			"EndTxnRequest": sarama.NewMockWrapper(&sarama.EndTxnResponse{}),
+		})
+		return mockBroker
+	}
+
+	endTxnResults := func(mockBroker *sarama.MockBroker) []bool {
+		var results []bool
+		for _, rr := range mockBroker.History() {
+			if req, ok := rr.Request.(*sarama.EndTxnRequest); ok {
+				assert.Equal(t, "orderer."+mockChannel.String(), req.TransactionalID)
+				assert.Equal(t, int64(7), req.ProducerID)
+				results = append(results, req.TransactionResult)
+			}
+		}
+		return results
+	}
+
+	t.Run("unsupported version", func(t *testing.T) {
+		_, err := newTransactionalProducer([]string{"127.0.0.1:0"}, mockBrokerConfig, 0, "orderer")
+		assert.EqualError(t, err, "transactional producers require Kafka version 0.11.0.0 or higher")
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		mockBroker := setupBroker(t, sarama.ErrNoError)
+		defer mockBroker.Close()
+
+		producer, err := newTransactionalProducer([]string{mockBroker.Addr()}, brokerConfig, 0, "orderer."+mockChannel.String())
+		require.NoError(t, err)
+		defer producer.Close()
+
+		partition, _, err := producer.SendMessage(newProducerMessage(mockChannel, []byte("foo")))
+		assert.NoError(t, err)
+		assert.Equal(t, mockChannel.partition(), partition)
+		assert.Equal(t, []bool{true}, endTxnResults(mockBroker))
+		assert.Equal(t, int32(1), producer.(*transactionalProducer).sequences[mockChannel.topic()][mockChannel.partition()])
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		mockBroker := setupBroker(t, sarama.ErrNotEnoughReplicas)
+		defer mockBroker.Close()
+
+		producer, err := newTransactionalProducer([]string{mockBroker.Addr()}, brokerConfig, 0, "orderer."+mockChannel.String())
+		require.NoError(t, err)
+		defer producer.Close()
+
+		_, _, err = producer.SendMessage(newProducerMessage(mockChannel, []byte("foo")))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), sarama.ErrNotEnoughReplicas.Error())
+		// every failed attempt is aborted, and the producer initialized again
+		assert.Equal(t, []bool{false, false}, endTxnResults(mockBroker))
+		initCount := 0
+		for _, rr := range mockBroker.History() {
+			if _, ok := rr.Request.(*sarama.InitProducerIDRequest); ok {
+				initCount++
+			}
+		}
+		assert.Equal(t, 2, initCount)
+	})
+}
@@ -32,9 +32,10 @@ var logger = flogging.MustGetLogger(pkgLogID)
 type consenter struct{}
 
 type chain struct {
-	support  consensus.ConsenterSupport
-	sendChan chan *message
-	exitChan chan struct{}
+	support   consensus.ConsenterSupport
+	sendChan  chan *message
+	drainChan chan chan struct{}
+	exitChan  chan struct{}
 }
 
 type message struct {
@@ -57,9 +58,10 @@ func (solo *consenter) HandleChain(support consensus.ConsenterSupport, metadata
 
 func newChain(support consensus.ConsenterSupport) *chain {
 	return &chain{
-		support:  support,
-		sendChan: make(chan *message),
-		exitChan: make(chan struct{}),
+		support:   support,
+		sendChan:  make(chan *message),
+		drainChan: make(chan chan struct{}),
+		exitChan:  make(chan struct{}),
 	}
 }
 
@@ -106,6 +108,28 @@ func (ch *chain) Configure(config *cb.Envelope, configSeq uint64) error {
 	}
 }
 
+// Drain cuts the pending messages into a block, there is no leadership to
+// transfer with a single consenter
+func (ch *chain) Drain(timeout time.Duration) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	done := make(chan struct{})
+	select {
+	case ch.drainChan <- done:
+	case <-ch.exitChan:
+		return fmt.Errorf("Exiting")
+	case <-timer.C:
+		return fmt.Errorf("timed out after %s waiting to cut the pending batch", timeout)
+	}
+	select {
+	case <-done:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("timed out after %s waiting to cut the pending batch", timeout)
+	}
+}
+
 // Errored only closes on exit
 func (ch *chain) Errored() <-chan struct{} {
 	return ch.exitChan
@@ -181,6 +205,16 @@ func (ch *chain) main() {
 			logger.Debugf("Batch timer expired, creating block")
 			block := ch.support.CreateNextBlock(batch)
 			ch.support.WriteBlock(block, nil)
+		case done := <-ch.drainChan:
+			timer = nil
+
+			batch := ch.support.BlockCutter().Cut()
+			if len(batch) != 0 {
+				logger.Debugf("Draining, creating block")
+				block := ch.support.CreateNextBlock(batch)
+				ch.support.WriteBlock(block, nil)
+			}
+			close(done)
 		case <-ch.exitChan:
 			logger.Debugf("Exiting")
 			return
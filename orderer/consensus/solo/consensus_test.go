@@ -424,3 +424,38 @@ func TestPendingMsgCutByTimeout(t *testing.T) {
 	case <-wg.done:
 	}
 }
+
+func TestDrain(t *testing.T) {
+	support := &mockmultichannel.ConsenterSupport{
+		Blocks:          make(chan *cb.Block),
+		BlockCutterVal:  mockblockcutter.NewReceiver(),
+		SharedConfigVal: &mockconfig.Orderer{BatchTimeoutVal: time.Hour},
+	}
+	defer close(support.BlockCutterVal.Block)
+
+	bs := newChain(support)
+	wg := goWithWait(bs.main)
+	defer bs.Halt()
+
+	syncQueueMessage(testMessage, bs, support.BlockCutterVal)
+
+	drained := make(chan error, 1)
+	go func() { drained <- bs.Drain(time.Second) }()
+	select {
+	case <-support.Blocks:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the pending message to be cut by the drain")
+	}
+	assert.NoError(t, <-drained)
+
+	// Draining again without pending messages writes no block
+	assert.NoError(t, bs.Drain(time.Second))
+
+	bs.Halt()
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("Should have exited")
+	case <-wg.done:
+	}
+	assert.EqualError(t, bs.Drain(time.Second), "Exiting")
+}
@@ -23,7 +23,7 @@ import (
 
 const (
 	chainFuncName = "chaincode"
-	chainCmdDes   = "Operate a chaincode: install|instantiate|invoke|package|query|signpackage|upgrade|list."
+	chainCmdDes   = "Operate a chaincode: install|instantiate|invoke|package|query|signpackage|combinepackage|upgrade|list|status."
 )
 
 var logger = flogging.MustGetLogger("chaincodeCmd")
@@ -55,8 +55,10 @@ func Cmd(cf *ChaincodeCmdFactory) *cobra.Command {
 	chaincodeCmd.AddCommand(packageCmd(cf, nil))
 	chaincodeCmd.AddCommand(queryCmd(cf))
 	chaincodeCmd.AddCommand(signpackageCmd(cf))
+	chaincodeCmd.AddCommand(combinepackageCmd())
 	chaincodeCmd.AddCommand(upgradeCmd(cf))
 	chaincodeCmd.AddCommand(listCmd(cf))
+	chaincodeCmd.AddCommand(statusCmd(nil))
 
 	return chaincodeCmd
 }
@@ -84,6 +86,11 @@ var (
 	connectionProfile     string
 	waitForEvent          bool
 	waitForEventTimeout   time.Duration
+	batchFile             string
+	batchWorkers          int
+	dedupKey              string
+	migrate               bool
+	teeMeasurements       []string
 )
 
 var chaincodeCmd = &cobra.Command{
@@ -142,6 +149,16 @@ func resetFlags() {
 		fmt.Sprint("Whether to wait for the event from each peer's deliver filtered service signifying that the 'invoke' transaction has been committed successfully"))
 	flags.DurationVar(&waitForEventTimeout, "waitForEventTimeout", 30*time.Second,
 		fmt.Sprint("Time to wait for the event from each peer's deliver filtered service signifying that the 'invoke' transaction has been committed successfully"))
+	flags.StringVar(&batchFile, "batch", common.UndefinedParamValue,
+		fmt.Sprint("Path to a JSON file listing the invocations to submit, as an array of {\"name\": ..., \"ctor\": {\"Args\": [...]}} objects whose name defaults to the --name flag"))
+	flags.IntVar(&batchWorkers, "batchWorkers", 10,
+		fmt.Sprint("Maximum number of invocations of a --batch file endorsed concurrently"))
+	flags.StringVar(&dedupKey, "dedupKey", "",
+		fmt.Sprint("Key identifying the 'invoke' transaction, which is invalidated when a transaction of the chaincode with the same key was committed within the dedup window of the channel"))
+	flags.BoolVar(&migrate, "migrate", false,
+		fmt.Sprint("Whether to invoke the Migrate function of the new chaincode on the peers, under its endorsement policy, once they have committed the 'upgrade' transaction"))
+	flags.StringArrayVar(&teeMeasurements, "teeMeasurements", nil,
+		fmt.Sprint("Hex encoded measurements of the enclaves trusted to execute the chaincode, recorded along with the endorsement policy in the validation parameter of the chaincodes validated by the TEEValidation plugin"))
 }
 
 func attachFlags(cmd *cobra.Command, names []string) {
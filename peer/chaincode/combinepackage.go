@@ -0,0 +1,69 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hyperledger/fabric/core/common/ccpackage"
+	pcommon "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// combinepackageCmd returns the cobra command for combining the packages
+// signed separately by the owners of a chaincode
+func combinepackageCmd() *cobra.Command {
+	cpCmd := &cobra.Command{
+		Use:   "combinepackage",
+		Short: "Combine the chaincode packages signed by each owner",
+		Long:  "Combine the chaincode packages created and signed separately by each owner with \"peer chaincode package -s -S\" into a package endorsed by all of them",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 2 {
+				return fmt.Errorf("peer chaincode combinepackage <outputpackage> <inputpackage>...")
+			}
+			return combinepackage(cmd, args[0], args[1:])
+		},
+	}
+
+	return cpCmd
+}
+
+func combinepackage(cmd *cobra.Command, opackageFile string, ipackageFiles []string) error {
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	var pack []*pcommon.Envelope
+	for _, ipackageFile := range ipackageFiles {
+		b, err := ioutil.ReadFile(ipackageFile)
+		if err != nil {
+			return err
+		}
+		env, err := utils.UnmarshalEnvelope(b)
+		if err != nil {
+			return fmt.Errorf("invalid chaincode package %s: %s", ipackageFile, err)
+		}
+		pack = append(pack, env)
+	}
+
+	env, err := ccpackage.CreateSignedCCDepSpecForInstall(pack)
+	if err != nil {
+		return err
+	}
+
+	b := utils.MarshalOrPanic(env)
+	err = ioutil.WriteFile(opackageFile, b, 0700)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote combined package to %s successfully\n", opackageFile)
+
+	return nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+func combinePackages(args []string) error {
+	cmd := combinepackageCmd()
+	addFlags(cmd)
+
+	cmd.SetArgs(args)
+
+	return cmd.Execute()
+}
+
+// TestCombinePackages combines the packages signed by two owners
+func TestCombinePackages(t *testing.T) {
+	pdir := newTempDir()
+	defer os.RemoveAll(pdir)
+
+	owner1file := pdir + "/owner1.file"
+	err := createSignedCDSPackage([]string{"-n", "somecc", "-p", "some/go/package", "-v", "0", "-s", "-S", owner1file}, true)
+	assert.NoError(t, err)
+	owner2file := pdir + "/owner2.file"
+	err = createSignedCDSPackage([]string{"-n", "somecc", "-p", "some/go/package", "-v", "0", "-s", "-S", owner2file}, true)
+	assert.NoError(t, err)
+
+	combinedfile := pdir + "/combined.file"
+	err = combinePackages([]string{combinedfile, owner1file, owner2file})
+	assert.NoError(t, err)
+
+	b, err := ioutil.ReadFile(combinedfile)
+	assert.NoError(t, err)
+	e, err := utils.UnmarshalEnvelope(b)
+	assert.NoError(t, err)
+	_, p, err := extractSignedCCDepSpec(e)
+	assert.NoError(t, err)
+	assert.Len(t, p.OwnerEndorsements, 2)
+	for _, endorsement := range p.OwnerEndorsements {
+		assert.NotNil(t, endorsement)
+	}
+}
+
+// TestCombinePackagesErrors combines invalid or mismatched packages
+func TestCombinePackagesErrors(t *testing.T) {
+	pdir := newTempDir()
+	defer os.RemoveAll(pdir)
+
+	signedfile := pdir + "/signed.file"
+	err := createSignedCDSPackage([]string{"-n", "somecc", "-p", "some/go/package", "-v", "0", "-s", "-S", signedfile}, true)
+	assert.NoError(t, err)
+	unsignedfile := pdir + "/unsigned.file"
+	err = createSignedCDSPackage([]string{"-n", "somecc", "-p", "some/go/package", "-v", "0", "-s", unsignedfile}, false)
+	assert.NoError(t, err)
+
+	combinedfile := pdir + "/combined.file"
+	err = combinePackages([]string{combinedfile})
+	assert.EqualError(t, err, "peer chaincode combinepackage <outputpackage> <inputpackage>...")
+
+	err = combinePackages([]string{combinedfile, signedfile, pdir + "/nonexistent.file"})
+	assert.Error(t, err)
+
+	// all the owners must have signed the package
+	err = combinePackages([]string{combinedfile, signedfile, unsignedfile})
+	assert.Error(t, err)
+	_, err = os.Stat(combinedfile)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -9,6 +9,7 @@ package chaincode
 import (
 	"context"
 	"crypto/tls"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -139,6 +140,13 @@ func chaincodeInvokeOrQuery(cmd *cobra.Command, invoke bool, cf *ChaincodeCmdFac
 		if chaincodeQueryRaw && chaincodeQueryHex {
 			return fmt.Errorf("options --raw (-r) and --hex (-x) are not compatible")
 		}
+		if common.StructuredOutput() {
+			result := &queryResult{Payload: string(proposalResp.Response.Payload)}
+			if chaincodeQueryHex {
+				result.Payload = hex.EncodeToString(proposalResp.Response.Payload)
+			}
+			return common.PrintOutput(result)
+		}
 		if chaincodeQueryRaw {
 			fmt.Println(proposalResp.Response.Payload)
 			return nil
@@ -152,12 +160,21 @@ func chaincodeInvokeOrQuery(cmd *cobra.Command, invoke bool, cf *ChaincodeCmdFac
 	return nil
 }
 
+// queryResult is the machine readable output of the query command, whose
+// payload is hex encoded with --hex
+type queryResult struct {
+	Payload string `json:"payload" yaml:"payload"`
+}
+
 type collectionConfigJson struct {
-	Name          string `json:"name"`
-	Policy        string `json:"policy"`
-	RequiredCount int32  `json:"requiredPeerCount"`
-	MaxPeerCount  int32  `json:"maxPeerCount"`
-	BlockToLive   uint64 `json:"blockToLive"`
+	Name            string `json:"name"`
+	Policy          string `json:"policy"`
+	RequiredCount   int32  `json:"requiredPeerCount"`
+	MaxPeerCount    int32  `json:"maxPeerCount"`
+	BlockToLive     uint64 `json:"blockToLive"`
+	StateDatabase   string `json:"stateDatabase"`
+	Ephemeral       bool   `json:"ephemeral"`
+	ValueCommitment string `json:"valueCommitment"`
 }
 
 // getCollectionConfig retrieves the collection configuration
@@ -203,6 +220,9 @@ func getCollectionConfigFromBytes(cconfBytes []byte) ([]byte, error) {
 					RequiredPeerCount: cconfitem.RequiredCount,
 					MaximumPeerCount:  cconfitem.MaxPeerCount,
 					BlockToLive:       cconfitem.BlockToLive,
+					StateDatabase:     cconfitem.StateDatabase,
+					Ephemeral:         cconfitem.Ephemeral,
+					ValueCommitment:   cconfitem.ValueCommitment,
 				},
 			},
 		}
@@ -248,6 +268,21 @@ func checkChaincodeCmdParams(cmd *cobra.Command) error {
 			policyMarshalled = putils.MarshalOrPanic(p)
 		}
 
+		if len(teeMeasurements) > 0 {
+			if policy == common.UndefinedParamValue {
+				return errors.New("the endorsement policy must be provided along with the trusted enclave measurements")
+			}
+			param := &pb.TEEValidationParameter{EndorsementPolicy: policyMarshalled}
+			for _, hexMeasurement := range teeMeasurements {
+				measurement, err := hex.DecodeString(hexMeasurement)
+				if err != nil {
+					return errors.Wrapf(err, "invalid enclave measurement %s", hexMeasurement)
+				}
+				param.TrustedMeasurements = append(param.TrustedMeasurements, measurement)
+			}
+			policyMarshalled = putils.MarshalOrPanic(param)
+		}
+
 		if collectionsConfigFile != common.UndefinedParamValue {
 			var err error
 			collectionConfigBytes, err = getCollectionConfigFromFile(collectionsConfigFile)
@@ -395,6 +430,7 @@ func InitCmdFactory(cmdName string, isEndorserRequired, isOrdererRequired bool)
 			logger.Infof("Retrieved channel (%s) orderer endpoint: %s", channelID, orderingEndpoints[0])
 			// override viper env
 			viper.Set("orderer.address", orderingEndpoints[0])
+			viper.Set("orderer.addresses", orderingEndpoints)
 		}
 
 		broadcastClient, err = common.GetBroadcastClientFnc()
@@ -432,12 +468,29 @@ func ChaincodeInvokeOrQuery(
 	deliverClients []api.PeerDeliverClient,
 	bc common.BroadcastClient,
 ) (*pb.ProposalResponse, error) {
+	proposalResp, _, err := chaincodeInvokeOrQueryWithTxID(spec, cID, txID, invoke, signer, certificate, endorserClients, deliverClients, bc)
+	return proposalResp, err
+}
+
+// chaincodeInvokeOrQueryWithTxID is ChaincodeInvokeOrQuery, also returning
+// the ID of the transaction once the proposal is created
+func chaincodeInvokeOrQueryWithTxID(
+	spec *pb.ChaincodeSpec,
+	cID string,
+	txID string,
+	invoke bool,
+	signer msp.SigningIdentity,
+	certificate tls.Certificate,
+	endorserClients []pb.EndorserClient,
+	deliverClients []api.PeerDeliverClient,
+	bc common.BroadcastClient,
+) (*pb.ProposalResponse, string, error) {
 	// Build the ChaincodeInvocationSpec message
 	invocation := &pb.ChaincodeInvocationSpec{ChaincodeSpec: spec}
 
 	creator, err := signer.Serialize()
 	if err != nil {
-		return nil, errors.WithMessage(err, fmt.Sprintf("error serializing identity for %s", signer.GetIdentifier()))
+		return nil, "", errors.WithMessage(err, fmt.Sprintf("error serializing identity for %s", signer.GetIdentifier()))
 	}
 
 	funcName := "invoke"
@@ -449,31 +502,36 @@ func ChaincodeInvokeOrQuery(
 	var tMap map[string][]byte
 	if transient != "" {
 		if err := json.Unmarshal([]byte(transient), &tMap); err != nil {
-			return nil, errors.Wrap(err, "error parsing transient string")
+			return nil, "", errors.Wrap(err, "error parsing transient string")
 		}
 	}
 
 	prop, txid, err := putils.CreateChaincodeProposalWithTxIDAndTransient(pcommon.HeaderType_ENDORSER_TRANSACTION, cID, invocation, creator, txID, tMap)
 	if err != nil {
-		return nil, errors.WithMessage(err, fmt.Sprintf("error creating proposal for %s", funcName))
+		return nil, "", errors.WithMessage(err, fmt.Sprintf("error creating proposal for %s", funcName))
+	}
+	if invoke && dedupKey != "" {
+		if err := putils.SetProposalDedupKey(prop, dedupKey); err != nil {
+			return nil, txid, errors.WithMessage(err, "error setting the dedup key of the proposal")
+		}
 	}
 
 	signedProp, err := putils.GetSignedProposal(prop, signer)
 	if err != nil {
-		return nil, errors.WithMessage(err, fmt.Sprintf("error creating signed proposal for %s", funcName))
+		return nil, txid, errors.WithMessage(err, fmt.Sprintf("error creating signed proposal for %s", funcName))
 	}
 	var responses []*pb.ProposalResponse
 	for _, endorser := range endorserClients {
 		proposalResp, err := endorser.ProcessProposal(context.Background(), signedProp)
 		if err != nil {
-			return nil, errors.WithMessage(err, fmt.Sprintf("error endorsing %s", funcName))
+			return nil, txid, errors.WithMessage(err, fmt.Sprintf("error endorsing %s", funcName))
 		}
 		responses = append(responses, proposalResp)
 	}
 
 	if len(responses) == 0 {
 		// this should only happen if some new code has introduced a bug
-		return nil, errors.New("no proposal responses received - this might indicate a bug")
+		return nil, txid, errors.New("no proposal responses received - this might indicate a bug")
 	}
 	// all responses will be checked when the signed transaction is created.
 	// for now, just set this so we check the first response's status
@@ -482,12 +540,12 @@ func ChaincodeInvokeOrQuery(
 	if invoke {
 		if proposalResp != nil {
 			if proposalResp.Response.Status >= shim.ERRORTHRESHOLD {
-				return proposalResp, nil
+				return proposalResp, txid, nil
 			}
 			// assemble a signed transaction (it's an Envelope message)
 			env, err := putils.CreateSignedTx(prop, signer, responses...)
 			if err != nil {
-				return proposalResp, errors.WithMessage(err, "could not assemble transaction")
+				return proposalResp, txid, errors.WithMessage(err, "could not assemble transaction")
 			}
 			var dg *deliverGroup
 			var ctx context.Context
@@ -500,26 +558,26 @@ func ChaincodeInvokeOrQuery(
 				// connect to deliver service on all peers
 				err := dg.Connect(ctx)
 				if err != nil {
-					return nil, err
+					return nil, txid, err
 				}
 			}
 
 			// send the envelope for ordering
 			if err = bc.Send(env); err != nil {
-				return proposalResp, errors.WithMessage(err, fmt.Sprintf("error sending transaction for %s", funcName))
+				return proposalResp, txid, errors.WithMessage(err, fmt.Sprintf("error sending transaction for %s", funcName))
 			}
 
 			if dg != nil && ctx != nil {
 				// wait for event that contains the txid from all peers
 				err = dg.Wait(ctx)
 				if err != nil {
-					return nil, err
+					return nil, txid, err
 				}
 			}
 		}
 	}
 
-	return proposalResp, nil
+	return proposalResp, txid, nil
 }
 
 // deliverGroup holds all of the information needed to connect
@@ -542,9 +600,10 @@ type deliverGroup struct {
 // deliverClient holds the client/connection related to a specific
 // peer. The address is included for logging purposes
 type deliverClient struct {
-	Client     api.PeerDeliverClient
-	Connection ccapi.Deliver
-	Address    string
+	Client           api.PeerDeliverClient
+	Connection       ccapi.Deliver
+	Address          string
+	TxValidationCode pb.TxValidationCode
 }
 
 func newDeliverGroup(deliverClients []api.PeerDeliverClient, peerAddresses []string, certificate tls.Certificate, channelID string, txid string) *deliverGroup {
@@ -662,6 +721,7 @@ func (dg *deliverGroup) ClientWait(dc *deliverClient) {
 			for _, tx := range filteredTransactions {
 				if tx.Txid == dg.TxID {
 					logger.Infof("txid [%s] committed with status (%s) at %s", dg.TxID, tx.TxValidationCode, dc.Address)
+					dc.TxValidationCode = tx.TxValidationCode
 					return
 				}
 			}
@@ -87,6 +87,35 @@ func TestCheckChaincodeCmdParamsEmptyCtor(t *testing.T) {
 	require.Error(result)
 }
 
+func TestCheckChaincodeCmdParamsWithTEEMeasurements(t *testing.T) {
+	defer func() {
+		policy, policyMarshalled, teeMeasurements = common.UndefinedParamValue, nil, nil
+		chaincodeVersion, escc, vscc = common.UndefinedParamValue, common.UndefinedParamValue, common.UndefinedParamValue
+	}()
+	chaincodeCtorJSON = `{ "Args":["init"] }`
+	chaincodeName = "somename"
+	chaincodeVersion = "1.0"
+	cmd := &cobra.Command{Use: instantiateCmdName}
+
+	teeMeasurements = []string{"010203"}
+	policy = common.UndefinedParamValue
+	assert.EqualError(t, checkChaincodeCmdParams(cmd), "the endorsement policy must be provided along with the trusted enclave measurements")
+
+	policy = "OR('A.member', 'B.member')"
+	teeMeasurements = []string{"0x01"}
+	assert.Contains(t, checkChaincodeCmdParams(cmd).Error(), "invalid enclave measurement 0x01")
+
+	// the validation parameter carries the endorsement policy and the measurements
+	teeMeasurements = []string{"010203", "0a0b"}
+	assert.NoError(t, checkChaincodeCmdParams(cmd))
+	param := &pb.TEEValidationParameter{}
+	assert.NoError(t, proto.Unmarshal(policyMarshalled, param))
+	p, err := cauthdsl.FromString(policy)
+	assert.NoError(t, err)
+	assert.Equal(t, utils.MarshalOrPanic(p), param.EndorsementPolicy)
+	assert.Equal(t, [][]byte{{1, 2, 3}, {10, 11}}, param.TrustedMeasurements)
+}
+
 func TestCheckValidJSON(t *testing.T) {
 	validJSON := `{"Args":["a","b","c"]}`
 	input := &pb.ChaincodeInput{}
@@ -182,7 +211,10 @@ const sampleCollectionConfigGood = `[
 		"policy": "OR('A.member', 'B.member')",
 		"requiredPeerCount": 3,
 		"maxPeerCount": 483279847,
-		"blockToLive":10
+		"blockToLive":10,
+		"stateDatabase": "CouchDB",
+		"ephemeral": true,
+		"valueCommitment": "Pedersen"
 	}
 ]`
 
@@ -208,6 +240,9 @@ func TestCollectionParsing(t *testing.T) {
 	assert.Equal(t, "foo", conf.Name)
 	assert.Equal(t, pol, conf.MemberOrgsPolicy.GetSignaturePolicy())
 	assert.Equal(t, 10, int(conf.BlockToLive))
+	assert.Equal(t, "CouchDB", conf.StateDatabase)
+	assert.True(t, conf.Ephemeral)
+	assert.Equal(t, "Pedersen", conf.ValueCommitment)
 	t.Logf("conf=%s", conf)
 
 	cc, err = getCollectionConfigFromBytes([]byte(sampleCollectionConfigBad))
@@ -44,6 +44,7 @@ func instantiateCmd(cf *ChaincodeCmdFactory) *cobra.Command {
 		"escc",
 		"vscc",
 		"collections-config",
+		"teeMeasurements",
 		"peerAddresses",
 		"tlsRootCertFiles",
 		"connectionProfile",
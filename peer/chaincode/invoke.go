@@ -7,8 +7,16 @@ SPDX-License-Identifier: Apache-2.0
 package chaincode
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
 
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -35,6 +43,9 @@ func invokeCmd(cf *ChaincodeCmdFactory) *cobra.Command {
 		"connectionProfile",
 		"waitForEvent",
 		"waitForEventTimeout",
+		"batch",
+		"batchWorkers",
+		"dedupKey",
 	}
 	attachFlags(chaincodeInvokeCmd, flagList)
 
@@ -45,6 +56,9 @@ func chaincodeInvoke(cmd *cobra.Command, cf *ChaincodeCmdFactory) error {
 	if channelID == "" {
 		return errors.New("The required parameter 'channelID' is empty. Rerun the command with -C flag")
 	}
+	if batchFile != common.UndefinedParamValue && dedupKey != "" {
+		return errors.New("the --dedupKey flag may not be combined with the --batch flag")
+	}
 	// Parsing of the command line is done so silence cmd usage
 	cmd.SilenceUsage = true
 
@@ -57,5 +71,153 @@ func chaincodeInvoke(cmd *cobra.Command, cf *ChaincodeCmdFactory) error {
 	}
 	defer cf.BroadcastClient.Close()
 
+	if batchFile != common.UndefinedParamValue {
+		return chaincodeInvokeBatch(cmd, cf)
+	}
 	return chaincodeInvokeOrQuery(cmd, true, cf)
 }
+
+// batchInvocation is an invocation listed in a --batch file
+type batchInvocation struct {
+	Name string             `json:"name"`
+	Ctor *pb.ChaincodeInput `json:"ctor"`
+}
+
+// batchResult is the outcome of an invocation of a --batch file
+type batchResult struct {
+	Index   int    `json:"index"`
+	TxID    string `json:"txid,omitempty"`
+	Status  int32  `json:"status,omitempty"`
+	Message string `json:"message,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// serialBroadcastClient serializes the submissions of concurrent invocations
+// over a single broadcast stream
+type serialBroadcastClient struct {
+	common.BroadcastClient
+	mutex sync.Mutex
+}
+
+func (s *serialBroadcastClient) Send(env *cb.Envelope) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.BroadcastClient.Send(env)
+}
+
+// chaincodeInvokeBatch endorses the invocations of the batch file with up to
+// batchWorkers concurrent invocations, submits them to the orderer and writes
+// the result of every invocation, in the order of the file, as a JSON line
+func chaincodeInvokeBatch(cmd *cobra.Command, cf *ChaincodeCmdFactory) error {
+	specs, err := readBatchFile(batchFile)
+	if err != nil {
+		return err
+	}
+	if batchWorkers < 1 {
+		return errors.Errorf("invalid number of batch workers %d, must be at least 1", batchWorkers)
+	}
+
+	bc := &serialBroadcastClient{BroadcastClient: cf.BroadcastClient}
+	results := make([]*batchResult, len(specs))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < batchWorkers && i < len(specs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				results[index] = invokeBatchSpec(index, specs[index], cf, bc)
+			}
+		}()
+	}
+	for i := range specs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+		line, err := json.Marshal(result)
+		if err != nil {
+			return errors.Wrap(err, "error marshaling batch result")
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(line))
+	}
+	if failed != 0 {
+		return errors.Errorf("%d of %d batch invocations failed", failed, len(specs))
+	}
+	logger.Infof("Chaincode batch invoke successful, %d transactions submitted", len(specs))
+	return nil
+}
+
+// invokeBatchSpec endorses and submits a single invocation of a batch file
+func invokeBatchSpec(index int, spec *pb.ChaincodeSpec, cf *ChaincodeCmdFactory, bc common.BroadcastClient) *batchResult {
+	result := &batchResult{Index: index}
+	proposalResp, txid, err := chaincodeInvokeOrQueryWithTxID(
+		spec,
+		channelID,
+		"",
+		true,
+		cf.Signer,
+		cf.Certificate,
+		cf.EndorserClients,
+		cf.DeliverClients,
+		bc)
+	result.TxID = txid
+	if proposalResp != nil && proposalResp.Response != nil {
+		result.Status = proposalResp.Response.Status
+		result.Message = proposalResp.Response.Message
+	}
+	switch {
+	case err != nil:
+		result.Error = err.Error()
+	case proposalResp == nil:
+		result.Error = "received nil proposal response"
+	case proposalResp.Endorsement == nil || result.Status >= shim.ERRORTHRESHOLD:
+		result.Error = "endorsement failure"
+	}
+	return result
+}
+
+// readBatchFile reads the invocations of a batch file as chaincode specs
+func readBatchFile(path string) ([]*pb.ChaincodeSpec, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading batch file %s", path)
+	}
+	var invocations []*batchInvocation
+	if err := json.Unmarshal(contents, &invocations); err != nil {
+		return nil, errors.Wrapf(err, "error parsing batch file %s", path)
+	}
+	if len(invocations) == 0 {
+		return nil, errors.Errorf("batch file %s lists no invocation", path)
+	}
+
+	lang := pb.ChaincodeSpec_Type(pb.ChaincodeSpec_Type_value[strings.ToUpper(chaincodeLang)])
+	specs := make([]*pb.ChaincodeSpec, len(invocations))
+	for i, invocation := range invocations {
+		if invocation == nil {
+			return nil, errors.Errorf("invocation %d of batch file %s is empty", i, path)
+		}
+		name := invocation.Name
+		if name == "" && chaincodeName != common.UndefinedParamValue {
+			name = chaincodeName
+		}
+		if name == "" {
+			return nil, errors.Errorf("must supply value for %s name of invocation %d of batch file %s", chainFuncName, i, path)
+		}
+		if invocation.Ctor == nil || len(invocation.Ctor.Args) == 0 {
+			return nil, errors.Errorf("must supply arguments for invocation %d of batch file %s", i, path)
+		}
+		specs[i] = &pb.ChaincodeSpec{
+			Type:        lang,
+			ChaincodeId: &pb.ChaincodeID{Name: name},
+			Input:       invocation.Ctor,
+		}
+	}
+	return specs, nil
+}
@@ -7,9 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package chaincode
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -223,6 +228,44 @@ func TestInvokeCmdEndorsementFailure(t *testing.T) {
 }
 
 // Returns mock chaincode command factory with multiple endorser and deliver clients
+// capturingBroadcastClient records the envelopes it is sent
+type capturingBroadcastClient struct {
+	envelopes []*cb.Envelope
+}
+
+func (c *capturingBroadcastClient) Send(env *cb.Envelope) error {
+	c.envelopes = append(c.envelopes, env)
+	return nil
+}
+
+func (c *capturingBroadcastClient) Close() error {
+	return nil
+}
+
+func TestInvokeCmdDedupKey(t *testing.T) {
+	defer resetFlags()
+
+	resetFlags()
+	mockCF, err := getMockChaincodeCmdFactory()
+	assert.NoError(t, err)
+	bc := &capturingBroadcastClient{}
+	mockCF.BroadcastClient = bc
+
+	cmd := invokeCmd(mockCF)
+	addFlags(cmd)
+	cmd.SetArgs([]string{"-n", "example02", "-c", "{\"Args\": [\"invoke\",\"a\",\"b\",\"10\"]}", "-C", "mychannel", "--dedupKey", "transfer-1"})
+	err = cmd.Execute()
+	assert.NoError(t, err)
+
+	assert.Len(t, bc.envelopes, 1)
+	payload, err := utils.UnmarshalPayload(bc.envelopes[0].Payload)
+	assert.NoError(t, err)
+	ccHdrExt, err := utils.GetChaincodeHeaderExtension(payload.Header)
+	assert.NoError(t, err)
+	assert.Equal(t, "transfer-1", ccHdrExt.DedupKey)
+	assert.Equal(t, "example02", ccHdrExt.ChaincodeId.Name)
+}
+
 func getMockChaincodeCmdFactory() (*ChaincodeCmdFactory, error) {
 	signer, err := common.GetDefaultSigner()
 	if err != nil {
@@ -408,3 +451,111 @@ func createFilteredBlock(txIDs ...string) *pb.FilteredBlock {
 	}
 	return fb
 }
+
+func TestInvokeCmdBatch(t *testing.T) {
+	defer resetFlags()
+
+	dir, err := ioutil.TempDir("", "batch")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	writeBatchFile := func(contents string) string {
+		f, err := ioutil.TempFile(dir, "batch")
+		assert.NoError(t, err)
+		defer f.Close()
+		_, err = f.WriteString(contents)
+		assert.NoError(t, err)
+		return f.Name()
+	}
+	batch := writeBatchFile(`[
+		{"ctor": {"Args": ["invoke", "a", "b", "10"]}},
+		{"name": "othercc", "ctor": {"Args": ["put", "c", "1"]}},
+		{"ctor": {"Args": ["invoke", "b", "a", "5"]}}
+	]`)
+
+	runBatch := func(cf *ChaincodeCmdFactory, args ...string) ([]*batchResult, error) {
+		resetFlags()
+		cmd := invokeCmd(cf)
+		addFlags(cmd)
+		out := &bytes.Buffer{}
+		cmd.SetOutput(out)
+		cmd.SetArgs(append([]string{"-C", "mychannel"}, args...))
+		err := cmd.Execute()
+		var results []*batchResult
+		decoder := json.NewDecoder(out)
+		for decoder.More() {
+			result := &batchResult{}
+			if decoder.Decode(result) != nil {
+				break
+			}
+			results = append(results, result)
+		}
+		return results, err
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockCF, err := getMockChaincodeCmdFactory()
+		assert.NoError(t, err)
+		results, err := runBatch(mockCF, "-n", "example02", "--batch", batch, "--batchWorkers", "2")
+		assert.NoError(t, err)
+		assert.Len(t, results, 3)
+		txids := map[string]struct{}{}
+		for i, result := range results {
+			assert.Equal(t, i, result.Index)
+			assert.Equal(t, int32(200), result.Status)
+			assert.Empty(t, result.Error)
+			assert.NotEmpty(t, result.TxID)
+			txids[result.TxID] = struct{}{}
+		}
+		assert.Len(t, txids, 3)
+	})
+
+	t.Run("BroadcastFailure", func(t *testing.T) {
+		mockCF, err := getMockChaincodeCmdFactory()
+		assert.NoError(t, err)
+		mockCF.BroadcastClient = common.GetMockBroadcastClient(errors.New("service unavailable"))
+		results, err := runBatch(mockCF, "-n", "example02", "--batch", batch)
+		assert.EqualError(t, err, "3 of 3 batch invocations failed")
+		assert.Len(t, results, 3)
+		for _, result := range results {
+			assert.Contains(t, result.Error, "error sending transaction for invoke: service unavailable")
+		}
+	})
+
+	t.Run("EndorsementFailure", func(t *testing.T) {
+		mockCF, err := getMockChaincodeCmdFactoryEndorsementFailure(500, []byte("failed"))
+		assert.NoError(t, err)
+		results, err := runBatch(mockCF, "-n", "example02", "--batch", batch)
+		assert.EqualError(t, err, "3 of 3 batch invocations failed")
+		assert.Len(t, results, 3)
+		for _, result := range results {
+			assert.Equal(t, int32(500), result.Status)
+			assert.Equal(t, "endorsement failure", result.Error)
+		}
+	})
+
+	t.Run("InvalidBatch", func(t *testing.T) {
+		mockCF, err := getMockChaincodeCmdFactory()
+		assert.NoError(t, err)
+
+		_, err = runBatch(mockCF, "--batch", filepath.Join(dir, "missing"))
+		assert.Contains(t, err.Error(), "error reading batch file")
+
+		_, err = runBatch(mockCF, "--batch", writeBatchFile(`{"ctor": {}}`))
+		assert.Contains(t, err.Error(), "error parsing batch file")
+
+		_, err = runBatch(mockCF, "--batch", writeBatchFile(`[]`))
+		assert.Contains(t, err.Error(), "lists no invocation")
+
+		_, err = runBatch(mockCF, "--batch", batch)
+		assert.Contains(t, err.Error(), "must supply value for chaincode name of invocation 0")
+
+		_, err = runBatch(mockCF, "--batch", writeBatchFile(`[{"name": "mycc"}]`))
+		assert.Contains(t, err.Error(), "must supply arguments for invocation 0")
+
+		_, err = runBatch(mockCF, "-n", "example02", "--batch", batch, "--batchWorkers", "0")
+		assert.EqualError(t, err, "invalid number of batch workers 0, must be at least 1")
+
+		_, err = runBatch(mockCF, "-n", "example02", "--batch", batch, "--dedupKey", "transfer-1")
+		assert.EqualError(t, err, "the --dedupKey flag may not be combined with the --batch flag")
+	})
+}
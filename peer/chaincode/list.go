@@ -15,6 +15,7 @@ import (
 	"strings"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/peer/common"
 	cb "github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -111,6 +112,22 @@ func getChaincodes(cmd *cobra.Command, cf *ChaincodeCmdFactory) error {
 		return err
 	}
 
+	if common.StructuredOutput() {
+		result := &chaincodeListResult{Chaincodes: []*chaincodeListEntry{}}
+		for _, chaincode := range cqr.Chaincodes {
+			result.Chaincodes = append(result.Chaincodes, &chaincodeListEntry{
+				Name:    chaincode.Name,
+				Version: chaincode.Version,
+				Path:    chaincode.Path,
+				Input:   chaincode.Input,
+				Escc:    chaincode.Escc,
+				Vscc:    chaincode.Vscc,
+				ID:      hex.EncodeToString(chaincode.Id),
+			})
+		}
+		return common.PrintOutput(result)
+	}
+
 	if getInstalledChaincodes {
 		fmt.Println("Get installed chaincodes on peer:")
 	} else {
@@ -122,6 +139,23 @@ func getChaincodes(cmd *cobra.Command, cf *ChaincodeCmdFactory) error {
 	return nil
 }
 
+// chaincodeListResult is the machine readable output of the list command
+type chaincodeListResult struct {
+	Chaincodes []*chaincodeListEntry `json:"chaincodes" yaml:"chaincodes"`
+}
+
+// chaincodeListEntry describes an installed or instantiated chaincode, the
+// instantiation fields being blank for the installed ones
+type chaincodeListEntry struct {
+	Name    string `json:"name" yaml:"name"`
+	Version string `json:"version" yaml:"version"`
+	Path    string `json:"path,omitempty" yaml:"path,omitempty"`
+	Input   string `json:"input,omitempty" yaml:"input,omitempty"`
+	Escc    string `json:"escc,omitempty" yaml:"escc,omitempty"`
+	Vscc    string `json:"vscc,omitempty" yaml:"vscc,omitempty"`
+	ID      string `json:"id" yaml:"id"`
+}
+
 type ccInfo struct {
 	*pb.ChaincodeInfo
 }
@@ -7,7 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package chaincode
 
 import (
+	"bytes"
 	"fmt"
+	"os"
 	"testing"
 
 	"encoding/hex"
@@ -15,6 +17,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/peer/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -59,6 +62,28 @@ func TestChaincodeListCmd(t *testing.T) {
 		t.Errorf("Run chaincode list cmd to get installed chaincodes error:%v", err)
 	}
 
+	// Get installed chaincodes as yaml
+	buf := &bytes.Buffer{}
+	common.OutputWriter = buf
+	defer func() { common.OutputWriter = os.Stdout }()
+	viper.Set(common.OutputFormatKey, common.OutputYAML)
+	installedChaincodesCmd = listCmd(mockCF)
+	installedChaincodesCmd.SetArgs(args)
+	assert.NoError(t, installedChaincodesCmd.Execute())
+	assert.Equal(t, "chaincodes:\n"+
+		"- name: mycc1\n  version: \"1.0\"\n  path: codePath1\n  input: input\n  escc: escc\n  vscc: vscc\n  id: \"010203\"\n"+
+		"- name: mycc2\n  version: \"1.0\"\n  path: codePath2\n  input: input\n  escc: escc\n  vscc: vscc\n  id: \"\"\n",
+		buf.String())
+
+	// Unsupported output format
+	buf.Reset()
+	viper.Set(common.OutputFormatKey, "xml")
+	installedChaincodesCmd = listCmd(mockCF)
+	installedChaincodesCmd.SetArgs(args)
+	assert.EqualError(t, installedChaincodesCmd.Execute(), "unsupported output format xml, must be one of text, json or yaml")
+	assert.Empty(t, buf.String())
+	viper.Set(common.OutputFormatKey, "")
+
 	resetFlags()
 
 	// Get instantiated chaincodes
@@ -7,11 +7,15 @@ SPDX-License-Identifier: Apache-2.0
 package chaincode
 
 import (
+	"bytes"
 	"fmt"
+	"os"
 	"testing"
 
 	"github.com/hyperledger/fabric/peer/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -46,6 +50,38 @@ func TestQueryCmd(t *testing.T) {
 	err = cmd.Execute()
 	assert.NoError(t, err, "Run chaincode query cmd error")
 
+	// Success case: run query command with json output, with and without -x option
+	buf := &bytes.Buffer{}
+	common.OutputWriter = buf
+	defer func() { common.OutputWriter = os.Stdout }()
+	payloadCF, err := getMockChaincodeCmdFactory()
+	assert.NoError(t, err, "Error getting mock chaincode command factory")
+	payloadResponse := &pb.ProposalResponse{
+		Response:    &pb.Response{Status: 200, Payload: []byte("100")},
+		Endorsement: &pb.Endorsement{},
+	}
+	payloadCF.EndorserClients = []pb.EndorserClient{common.GetMockEndorserClient(payloadResponse, nil)}
+	viper.Set(common.OutputFormatKey, common.OutputJSON)
+	args = []string{"-C", "mychannel", "-n", "example02", "-c", "{\"Args\": [\"query\",\"a\"]}"}
+	cmd = newQueryCmdForTest(payloadCF, args)
+	assert.NoError(t, cmd.Execute())
+	assert.Equal(t, "{\n  \"payload\": \"100\"\n}\n", buf.String())
+	buf.Reset()
+	args = []string{"-x", "-C", "mychannel", "-n", "example02", "-c", "{\"Args\": [\"query\",\"a\"]}"}
+	cmd = newQueryCmdForTest(payloadCF, args)
+	assert.NoError(t, cmd.Execute())
+	assert.Equal(t, "{\n  \"payload\": \"313030\"\n}\n", buf.String())
+	chaincodeQueryHex = false
+
+	// Failure case: run query command with an unsupported output format
+	buf.Reset()
+	viper.Set(common.OutputFormatKey, "xml")
+	args = []string{"-C", "mychannel", "-n", "example02", "-c", "{\"Args\": [\"query\",\"a\"]}"}
+	cmd = newQueryCmdForTest(payloadCF, args)
+	assert.EqualError(t, cmd.Execute(), "unsupported output format xml, must be one of text, json or yaml")
+	assert.Empty(t, buf.String())
+	viper.Set(common.OutputFormatKey, "")
+
 	// Failure case: run query command with both -x and -r options
 	args = []string{"-r", "-x", "-C", "mychannel", "-n", "example02", "-c", "{\"Args\": [\"query\",\"a\"]}"}
 	cmd = newQueryCmdForTest(mockCF, args)
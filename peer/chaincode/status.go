@@ -0,0 +1,159 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// statusCmdFactory holds the clients used by the status command
+type statusCmdFactory struct {
+	adminClient      pb.AdminClient
+	wrapWithEnvelope func(msg proto.Message) (*cb.Envelope, error)
+}
+
+// newStatusCmdFactory returns the factory of the admin client of the local
+// peer and of the request envelopes signed by the local MSP
+func newStatusCmdFactory() (*statusCmdFactory, error) {
+	adminClient, err := common.GetAdminClient()
+	if err != nil {
+		return nil, err
+	}
+	signer, err := common.GetDefaultSignerFnc()
+	if err != nil {
+		return nil, errors.Errorf("failed obtaining default signer: %v", err)
+	}
+	localSigner := crypto.NewSignatureHeaderCreator(signer)
+	return &statusCmdFactory{
+		adminClient: adminClient,
+		wrapWithEnvelope: func(msg proto.Message) (*cb.Envelope, error) {
+			return utils.CreateSignedEnvelope(cb.HeaderType_PEER_ADMIN_OPERATION, "", localSigner, msg, 0, 0)
+		},
+	}, nil
+}
+
+// statusCmd returns the cobra command for the status of the chaincodes
+func statusCmd(cf *statusCmdFactory) *cobra.Command {
+	chaincodeStatusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Get the status of the chaincodes running on the peer.",
+		Long:  "Get the last heartbeat and the runtime stats reported by the chaincode containers running on the peer, or by the instances of the chaincode with the given name",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getChaincodeStatus(cmd, args, cf)
+		},
+	}
+	attachFlags(chaincodeStatusCmd, []string{"name"})
+
+	return chaincodeStatusCmd
+}
+
+func getChaincodeStatus(cmd *cobra.Command, args []string, cf *statusCmdFactory) error {
+	if len(args) != 0 {
+		return fmt.Errorf("trailing args detected: %s", args)
+	}
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	var err error
+	if cf == nil {
+		if cf, err = newStatusCmdFactory(); err != nil {
+			return err
+		}
+	}
+	env, err := cf.wrapWithEnvelope(&pb.AdminOperation{
+		Content: &pb.AdminOperation_ChaincodeStatusReq{
+			ChaincodeStatusReq: &pb.ChaincodeStatusRequest{Chaincode: chaincodeName},
+		},
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed signing chaincode status request")
+	}
+	resp, err := cf.adminClient.GetChaincodeStatus(context.Background(), env)
+	if err != nil {
+		return errors.WithMessage(err, "failed getting the status of the chaincodes")
+	}
+	return printChaincodeStatus(resp)
+}
+
+// chaincodeStatus is the machine readable output of the status command
+type chaincodeStatus struct {
+	Name                   string `json:"name" yaml:"name"`
+	Responsive             bool   `json:"responsive" yaml:"responsive"`
+	LastHeartbeat          string `json:"last_heartbeat,omitempty" yaml:"last_heartbeat,omitempty"`
+	TransactionsInFlight   int32  `json:"transactions_in_flight" yaml:"transactions_in_flight"`
+	TransactionErrors      uint64 `json:"transaction_errors" yaml:"transaction_errors"`
+	OldestTransactionStart string `json:"oldest_transaction_start,omitempty" yaml:"oldest_transaction_start,omitempty"`
+	MemoryBytes            uint64 `json:"memory_bytes" yaml:"memory_bytes"`
+	Goroutines             int32  `json:"goroutines" yaml:"goroutines"`
+}
+
+// printChaincodeStatus prints the status of the chaincodes in the requested
+// output format
+func printChaincodeStatus(resp *pb.ChaincodeStatusResponse) error {
+	statuses := []*chaincodeStatus{}
+	for _, cc := range resp.Chaincodes {
+		status := &chaincodeStatus{
+			Name:          cc.Name,
+			Responsive:    cc.Responsive,
+			LastHeartbeat: formatTimestamp(cc.LastHeartbeat),
+		}
+		if stats := cc.Stats; stats != nil {
+			status.TransactionsInFlight = stats.TransactionsInFlight
+			status.TransactionErrors = stats.TransactionErrors
+			status.OldestTransactionStart = formatTimestamp(stats.OldestTransactionStart)
+			status.MemoryBytes = stats.MemoryBytes
+			status.Goroutines = stats.Goroutines
+		}
+		statuses = append(statuses, status)
+	}
+	if common.StructuredOutput() {
+		return common.PrintOutput(statuses)
+	}
+	if len(statuses) == 0 {
+		fmt.Println("No chaincode running")
+		return nil
+	}
+	for _, status := range statuses {
+		state := "responsive"
+		if !status.Responsive {
+			state = "unresponsive"
+		}
+		if status.LastHeartbeat == "" {
+			fmt.Printf("%s: %s, no heartbeat received\n", status.Name, state)
+			continue
+		}
+		fmt.Printf("%s: %s, last heartbeat: %s, transactions in flight: %d, transaction errors: %d, memory: %d bytes, goroutines: %d\n",
+			status.Name, state, status.LastHeartbeat, status.TransactionsInFlight, status.TransactionErrors, status.MemoryBytes, status.Goroutines)
+		if status.OldestTransactionStart != "" {
+			fmt.Printf("%s: oldest transaction in flight started at %s\n", status.Name, status.OldestTransactionStart)
+		}
+	}
+	return nil
+}
+
+// formatTimestamp returns the timestamp in RFC 3339 format, or an empty
+// string if it is not set
+func formatTimestamp(ts *timestamp.Timestamp) string {
+	t, err := ptypes.Timestamp(ts)
+	if err != nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
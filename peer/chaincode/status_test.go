@@ -0,0 +1,71 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStatusCmdFactory(err error) *statusCmdFactory {
+	return &statusCmdFactory{
+		adminClient: common.GetMockAdminClient(err),
+		wrapWithEnvelope: func(msg proto.Message) (*cb.Envelope, error) {
+			return &cb.Envelope{Payload: utils.MarshalOrPanic(&cb.Payload{Data: utils.MarshalOrPanic(msg)})}, nil
+		},
+	}
+}
+
+func TestStatusCmd(t *testing.T) {
+	defer viper.Reset()
+	defer resetFlags()
+
+	resetFlags()
+	cmd := statusCmd(newTestStatusCmdFactory(nil))
+	cmd.SetArgs([]string{})
+	assert.NoError(t, cmd.Execute())
+
+	resetFlags()
+	cmd = statusCmd(newTestStatusCmdFactory(nil))
+	cmd.SetArgs([]string{"-n", "marbles"})
+	assert.NoError(t, cmd.Execute())
+
+	viper.Set(common.OutputFormatKey, common.OutputJSON)
+	resetFlags()
+	cmd = statusCmd(newTestStatusCmdFactory(nil))
+	cmd.SetArgs([]string{"-n", "marbles"})
+	assert.NoError(t, cmd.Execute())
+}
+
+func TestStatusCmdErrors(t *testing.T) {
+	defer resetFlags()
+
+	resetFlags()
+	cmd := statusCmd(newTestStatusCmdFactory(nil))
+	cmd.SetArgs([]string{"extra"})
+	assert.EqualError(t, cmd.Execute(), "trailing args detected: [extra]")
+
+	resetFlags()
+	cmd = statusCmd(newTestStatusCmdFactory(errors.New("connection refused")))
+	cmd.SetArgs([]string{})
+	assert.EqualError(t, cmd.Execute(), "failed getting the status of the chaincodes: connection refused")
+}
+
+func TestPrintChaincodeStatus(t *testing.T) {
+	assert.NoError(t, printChaincodeStatus(&pb.ChaincodeStatusResponse{}))
+	assert.NoError(t, printChaincodeStatus(&pb.ChaincodeStatusResponse{Chaincodes: []*pb.ChaincodeStatus{
+		{Name: "mycc:1.0", Responsive: false},
+	}}))
+}
@@ -11,6 +11,8 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
 	protcommon "github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -46,6 +48,10 @@ func upgradeCmd(cf *ChaincodeCmdFactory) *cobra.Command {
 		"tlsRootCertFiles",
 		"connectionProfile",
 		"collections-config",
+		"teeMeasurements",
+		"migrate",
+		"waitForEvent",
+		"waitForEventTimeout",
 	}
 	attachFlags(chaincodeUpgradeCmd, flagList)
 
@@ -125,6 +131,9 @@ func chaincodeUpgrade(cmd *cobra.Command, args []string, cf *ChaincodeCmdFactory
 	}
 
 	if env != nil {
+		if migrate {
+			return upgradeAndMigrate(env, cf)
+		}
 		logger.Debug("Send signed envelope to orderer")
 		err = cf.BroadcastClient.Send(env)
 		return err
@@ -132,3 +141,49 @@ func chaincodeUpgrade(cmd *cobra.Command, args []string, cf *ChaincodeCmdFactory
 
 	return nil
 }
+
+// upgradeAndMigrate sends the upgrade transaction to the orderer and waits for
+// the peers to commit it, then invokes the Migrate function of the new chaincode
+// on them, so that it transforms its existing state under the endorsement
+// policy of the new definition
+func upgradeAndMigrate(env *protcommon.Envelope, cf *ChaincodeCmdFactory) error {
+	chdr, err := utils.ChannelHeader(env)
+	if err != nil {
+		return fmt.Errorf("error getting the channel header of the upgrade transaction: %s", err)
+	}
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), waitForEventTimeout)
+	defer cancelFunc()
+	dg := newDeliverGroup(cf.DeliverClients, peerAddresses, cf.Certificate, channelID, chdr.TxId)
+	if err = dg.Connect(ctx); err != nil {
+		return err
+	}
+
+	logger.Debug("Send signed envelope to orderer")
+	if err = cf.BroadcastClient.Send(env); err != nil {
+		return err
+	}
+	if err = dg.Wait(ctx); err != nil {
+		return err
+	}
+	for _, dc := range dg.Clients {
+		if dc.TxValidationCode != pb.TxValidationCode_VALID {
+			return fmt.Errorf("upgrade transaction %s committed with status (%s) at %s", chdr.TxId, dc.TxValidationCode, dc.Address)
+		}
+	}
+
+	spec := &pb.ChaincodeSpec{
+		Type:        pb.ChaincodeSpec_Type(pb.ChaincodeSpec_Type_value[chaincodeLang]),
+		ChaincodeId: &pb.ChaincodeID{Name: chaincodeName},
+		Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte(ccprovider.MigrateFunction)}},
+	}
+	proposalResp, err := ChaincodeInvokeOrQuery(spec, channelID, "", true, cf.Signer, cf.Certificate, cf.EndorserClients, cf.DeliverClients, cf.BroadcastClient)
+	if err != nil {
+		return fmt.Errorf("error migrating chaincode %s: %s - proposal response: %v", chaincodeName, err, proposalResp)
+	}
+	if proposalResp.Response.Status >= shim.ERRORTHRESHOLD || proposalResp.Endorsement == nil {
+		return fmt.Errorf("migration failure of chaincode %s. response: %v", chaincodeName, proposalResp.Response)
+	}
+	logger.Infof("Chaincode %s migrated to version %s", chaincodeName, chaincodeVersion)
+	return nil
+}
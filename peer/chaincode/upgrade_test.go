@@ -7,15 +7,24 @@ SPDX-License-Identifier: Apache-2.0
 package chaincode
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
+	ccapi "github.com/hyperledger/fabric/peer/chaincode/api"
+	"github.com/hyperledger/fabric/peer/chaincode/mock"
 	"github.com/hyperledger/fabric/peer/common"
+	"github.com/hyperledger/fabric/peer/common/api"
+	cmock "github.com/hyperledger/fabric/peer/common/mock"
+	cb "github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
 )
 
 func TestUpgradeCmd(t *testing.T) {
@@ -152,3 +161,116 @@ func TestUpgradeCmdWithNilCF(t *testing.T) {
 	err := cmd.Execute()
 	assert.Error(t, err, "'peer chaincode upgrade' command should have failed without a panic")
 }
+
+// recordingBroadcastClient records the envelopes sent to the orderer and
+// notifies them to the deliver clients
+type recordingBroadcastClient struct {
+	envelopes []*cb.Envelope
+	sent      chan *cb.Envelope
+}
+
+func (bc *recordingBroadcastClient) Send(env *cb.Envelope) error {
+	bc.envelopes = append(bc.envelopes, env)
+	bc.sent <- env
+	return nil
+}
+
+func (bc *recordingBroadcastClient) Close() error {
+	return nil
+}
+
+// getMockDeliverClientCommittingSent returns a mock deliver client which
+// commits the transactions sent through the broadcast client with the code
+func getMockDeliverClientCommittingSent(bc *recordingBroadcastClient, code pb.TxValidationCode) *cmock.PeerDeliverClient {
+	mockDC := &cmock.PeerDeliverClient{}
+	mockDC.DeliverFilteredStub = func(ctx context.Context, opts ...grpc.CallOption) (ccapi.Deliver, error) {
+		mockDF := &mock.Deliver{}
+		mockDF.RecvStub = func() (*pb.DeliverResponse, error) {
+			chdr, err := utils.ChannelHeader(<-bc.sent)
+			if err != nil {
+				return nil, err
+			}
+			fb := createFilteredBlock(chdr.TxId)
+			fb.FilteredTransactions[0].TxValidationCode = code
+			return &pb.DeliverResponse{Type: &pb.DeliverResponse_FilteredBlock{FilteredBlock: fb}}, nil
+		}
+		return mockDF, nil
+	}
+	return mockDC
+}
+
+func TestUpgradeCmdMigrate(t *testing.T) {
+	defer resetFlags()
+
+	signer, err := common.GetDefaultSigner()
+	assert.NoError(t, err)
+	mockResponse := &pb.ProposalResponse{
+		Response:    &pb.Response{Status: 200},
+		Endorsement: &pb.Endorsement{},
+	}
+	args := []string{"-C", "mychannel", "-n", "example02", "-p", "github.com/hyperledger/fabric/examples/chaincode/go/example02/cmd",
+		"-v", "anotherversion", "-c", "{\"Function\":\"init\",\"Args\": [\"param\",\"1\"]}", "--migrate", "--waitForEventTimeout", "1s"}
+
+	// the Migrate function of the chaincode is invoked once the upgrade is committed
+	bc := &recordingBroadcastClient{sent: make(chan *cb.Envelope, 2)}
+	mockCF := &ChaincodeCmdFactory{
+		EndorserClients: []pb.EndorserClient{common.GetMockEndorserClient(mockResponse, nil)},
+		DeliverClients:  []api.PeerDeliverClient{getMockDeliverClientCommittingSent(bc, pb.TxValidationCode_VALID)},
+		Signer:          signer,
+		BroadcastClient: bc,
+	}
+	cmd := upgradeCmd(mockCF)
+	addFlags(cmd)
+	cmd.SetArgs(args)
+	assert.NoError(t, cmd.Execute())
+	assert.Len(t, bc.envelopes, 2)
+	payload, err := utils.UnmarshalPayload(bc.envelopes[1].Payload)
+	assert.NoError(t, err)
+	tx, err := utils.GetTransaction(payload.Data)
+	assert.NoError(t, err)
+	cap, err := utils.GetChaincodeActionPayload(tx.Actions[0].Payload)
+	assert.NoError(t, err)
+	cpp, err := utils.GetChaincodeProposalPayload(cap.ChaincodeProposalPayload)
+	assert.NoError(t, err)
+	cis := &pb.ChaincodeInvocationSpec{}
+	assert.NoError(t, proto.Unmarshal(cpp.Input, cis))
+	assert.Equal(t, "example02", cis.ChaincodeSpec.ChaincodeId.Name)
+	assert.Equal(t, [][]byte{[]byte("Migrate")}, cis.ChaincodeSpec.Input.Args)
+
+	// the chaincode is not migrated if the upgrade is invalid
+	bc = &recordingBroadcastClient{sent: make(chan *cb.Envelope, 2)}
+	mockCF.DeliverClients = []api.PeerDeliverClient{getMockDeliverClientCommittingSent(bc, pb.TxValidationCode_MVCC_READ_CONFLICT)}
+	mockCF.BroadcastClient = bc
+	cmd = upgradeCmd(mockCF)
+	addFlags(cmd)
+	cmd.SetArgs(args)
+	err = cmd.Execute()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "committed with status (MVCC_READ_CONFLICT)")
+	assert.Len(t, bc.envelopes, 1)
+
+	// the migration may fail
+	bc = &recordingBroadcastClient{sent: make(chan *cb.Envelope, 2)}
+	failingResponse := &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: "unknown function Migrate"}}
+	mockCF.EndorserClients = []pb.EndorserClient{&sequenceEndorserClient{responses: []*pb.ProposalResponse{mockResponse, failingResponse}}}
+	mockCF.DeliverClients = []api.PeerDeliverClient{getMockDeliverClientCommittingSent(bc, pb.TxValidationCode_VALID)}
+	mockCF.BroadcastClient = bc
+	cmd = upgradeCmd(mockCF)
+	addFlags(cmd)
+	cmd.SetArgs(args)
+	err = cmd.Execute()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "migration failure of chaincode example02")
+	assert.Len(t, bc.envelopes, 1)
+}
+
+// sequenceEndorserClient returns its responses in sequence
+type sequenceEndorserClient struct {
+	responses []*pb.ProposalResponse
+}
+
+func (ec *sequenceEndorserClient) ProcessProposal(ctx context.Context, in *pb.SignedProposal, opts ...grpc.CallOption) (*pb.ProposalResponse, error) {
+	resp := ec.responses[0]
+	ec.responses = ec.responses[1:]
+	return resp, nil
+}
@@ -7,7 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package channel
 
 import (
-	"strings"
+	"net"
 	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
@@ -34,12 +34,17 @@ const (
 var (
 	// join related variables.
 	genesisBlockPath string
+	snapshotPath     string
 
 	// create related variables
 	channelID     string
 	channelTxFile string
 	outputBlock   string
 	timeout       time.Duration
+
+	// fetchconfig related variables
+	decodeConfig bool
+	configPath   string
 )
 
 // Cmd returns the cobra command for Node
@@ -48,6 +53,7 @@ func Cmd(cf *ChannelCmdFactory) *cobra.Command {
 
 	channelCmd.AddCommand(createCmd(cf))
 	channelCmd.AddCommand(fetchCmd(cf))
+	channelCmd.AddCommand(fetchconfigCmd(cf))
 	channelCmd.AddCommand(joinCmd(cf))
 	channelCmd.AddCommand(listCmd(cf))
 	channelCmd.AddCommand(updateCmd(cf))
@@ -73,10 +79,13 @@ func resetFlags() {
 	flags = &pflag.FlagSet{}
 
 	flags.StringVarP(&genesisBlockPath, "blockpath", "b", common.UndefinedParamValue, "Path to file containing genesis block")
+	flags.StringVarP(&snapshotPath, "snapshotpath", "", common.UndefinedParamValue, "Path to the gzip compressed tar archive of a state snapshot of the channel to join from instead of the genesis block")
 	flags.StringVarP(&channelID, "channelID", "c", common.UndefinedParamValue, "In case of a newChain command, the channel ID to create. It must be all lower case, less than 250 characters long and match the regular expression: [a-z][a-z0-9.-]*")
 	flags.StringVarP(&channelTxFile, "file", "f", "", "Configuration transaction file generated by a tool such as configtxgen for submitting to orderer")
 	flags.StringVarP(&outputBlock, "outputBlock", "", common.UndefinedParamValue, `The path to write the genesis block for the channel. (default ./<channelID>.block)`)
 	flags.DurationVarP(&timeout, "timeout", "t", 5*time.Second, "Channel creation timeout")
+	flags.BoolVarP(&decodeConfig, "decode", "", false, "Write the channel configuration as JSON instead of the configuration block")
+	flags.StringVarP(&configPath, "path", "", "", "The slash separated path of the group, value or policy of the decoded channel configuration to write, such as Application/Org1MSP/AnchorPeers")
 }
 
 func attachFlags(cmd *cobra.Command, names []string) {
@@ -92,8 +101,8 @@ func attachFlags(cmd *cobra.Command, names []string) {
 
 var channelCmd = &cobra.Command{
 	Use:   "channel",
-	Short: "Operate a channel: create|fetch|join|list|update|signconfigtx|getinfo.",
-	Long:  "Operate a channel: create|fetch|join|list|update|signconfigtx|getinfo.",
+	Short: "Operate a channel: create|fetch|fetchconfig|join|list|update|signconfigtx|getinfo.",
+	Long:  "Operate a channel: create|fetch|fetchconfig|join|list|update|signconfigtx|getinfo.",
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		common.InitCmd(cmd, args)
 		common.SetOrdererEnv(cmd, args)
@@ -158,7 +167,7 @@ func InitCmdFactory(isEndorserRequired, isPeerDeliverRequired, isOrdererRequired
 
 	// for create and fetch, we need the orderer as well
 	if isOrdererRequired {
-		if len(strings.Split(common.OrderingEndpoint, ":")) != 2 {
+		if _, _, err := net.SplitHostPort(common.OrderingEndpoint); err != nil {
 			return nil, errors.Errorf("ordering service endpoint %s is not valid or missing", common.OrderingEndpoint)
 		}
 		cf.DeliverClient, err = common.NewDeliverClientForOrderer(channelID)
@@ -10,12 +10,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"strconv"
-	"strings"
 
 	"github.com/golang/protobuf/proto"
-	"github.com/hyperledger/fabric/peer/common"
 	cb "github.com/hyperledger/fabric/protos/common"
-	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -46,17 +43,9 @@ func fetch(cmd *cobra.Command, args []string, cf *ChannelCmdFactory) error {
 	// Parsing of the command line is done so silence cmd usage
 	cmd.SilenceUsage = true
 
-	// default to fetching from orderer
-	ordererRequired := OrdererRequired
-	peerDeliverRequired := PeerDeliverNotRequired
-	if len(strings.Split(common.OrderingEndpoint, ":")) != 2 {
-		// if no orderer endpoint supplied, connect to peer's deliver service
-		ordererRequired = OrdererNotRequired
-		peerDeliverRequired = PeerDeliverRequired
-	}
 	var err error
 	if cf == nil {
-		cf, err = InitCmdFactory(EndorserNotRequired, peerDeliverRequired, ordererRequired)
+		cf, err = initDeliverCmdFactory()
 		if err != nil {
 			return err
 		}
@@ -70,15 +59,7 @@ func fetch(cmd *cobra.Command, args []string, cf *ChannelCmdFactory) error {
 	case "newest":
 		block, err = cf.DeliverClient.GetNewestBlock()
 	case "config":
-		iBlock, err2 := cf.DeliverClient.GetNewestBlock()
-		if err2 != nil {
-			return err2
-		}
-		lc, err2 := utils.GetLastConfigIndexFromBlock(iBlock)
-		if err2 != nil {
-			return err2
-		}
-		block, err = cf.DeliverClient.GetSpecifiedBlock(lc)
+		block, err = fetchConfigBlock(cf)
 	default:
 		num, err2 := strconv.Atoi(args[0])
 		if err2 != nil {
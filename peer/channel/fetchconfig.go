@@ -0,0 +1,193 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/tools/protolator"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func fetchconfigCmd(cf *ChannelCmdFactory) *cobra.Command {
+	fetchconfigCmd := &cobra.Command{
+		Use:   "fetchconfig [outputfile]",
+		Short: "Fetch the configuration of a channel",
+		Long: `Fetch the latest configuration block of a channel, writing it to a file, or to stdout if the file is '-'.
+With --decode, the channel configuration of the block is written as JSON instead, and --path restricts it to
+the group, value or policy at a slash separated path of group names, such as Application/Org1MSP/AnchorPeers.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return fetchconfig(cmd, args, cf)
+		},
+	}
+	flagList := []string{
+		"channelID",
+		"decode",
+		"path",
+	}
+	attachFlags(fetchconfigCmd, flagList)
+
+	return fetchconfigCmd
+}
+
+func fetchconfig(cmd *cobra.Command, args []string, cf *ChannelCmdFactory) error {
+	if len(args) > 1 {
+		return fmt.Errorf("trailing args detected")
+	}
+	if configPath != "" && !decodeConfig {
+		return fmt.Errorf("a configuration path can only be extracted when decoding, specify --decode")
+	}
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	var err error
+	if cf == nil {
+		cf, err = initDeliverCmdFactory()
+		if err != nil {
+			return err
+		}
+	}
+
+	block, err := fetchConfigBlock(cf)
+	if err != nil {
+		return err
+	}
+
+	var out []byte
+	var file string
+	if decodeConfig {
+		out, err = decodeChannelConfig(block, configPath)
+		file = channelID + "_config.json"
+	} else {
+		out, err = proto.Marshal(block)
+		file = channelID + "_config.block"
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		file = args[0]
+	}
+	if file == "-" {
+		_, err = os.Stdout.Write(out)
+		return err
+	}
+	return ioutil.WriteFile(file, out, 0644)
+}
+
+// initDeliverCmdFactory creates the factory of the commands fetching blocks, which
+// fetch from the orderer if one is specified and from the peer otherwise
+func initDeliverCmdFactory() (*ChannelCmdFactory, error) {
+	if _, _, err := net.SplitHostPort(common.OrderingEndpoint); err != nil {
+		return InitCmdFactory(EndorserNotRequired, PeerDeliverRequired, OrdererNotRequired)
+	}
+	return InitCmdFactory(EndorserNotRequired, PeerDeliverNotRequired, OrdererRequired)
+}
+
+// fetchConfigBlock fetches the block of the latest configuration of the channel
+func fetchConfigBlock(cf *ChannelCmdFactory) (*cb.Block, error) {
+	iBlock, err := cf.DeliverClient.GetNewestBlock()
+	if err != nil {
+		return nil, err
+	}
+	lc, err := utils.GetLastConfigIndexFromBlock(iBlock)
+	if err != nil {
+		return nil, err
+	}
+	return cf.DeliverClient.GetSpecifiedBlock(lc)
+}
+
+// decodeChannelConfig returns the JSON representation of the channel configuration of the given
+// configuration block, or of its element at the given path if the path is not empty
+func decodeChannelConfig(block *cb.Block, path string) ([]byte, error) {
+	env, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return nil, errors.WithMessage(err, "malformed configuration block")
+	}
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, errors.WithMessage(err, "malformed configuration block")
+	}
+	configEnv, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	if err != nil {
+		return nil, errors.WithMessage(err, "malformed configuration block")
+	}
+
+	buf := &bytes.Buffer{}
+	if err := protolator.DeepMarshalJSON(buf, configEnv.Config); err != nil {
+		return nil, errors.WithMessage(err, "failed decoding channel configuration")
+	}
+	if path == "" {
+		return buf.Bytes(), nil
+	}
+
+	decoder := json.NewDecoder(buf)
+	decoder.UseNumber()
+	var config map[string]interface{}
+	if err := decoder.Decode(&config); err != nil {
+		return nil, errors.Wrap(err, "failed decoding channel configuration")
+	}
+	element, err := configElement(config["channel_group"], path)
+	if err != nil {
+		return nil, err
+	}
+	out, err := json.MarshalIndent(element, "", "\t")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed encoding configuration element")
+	}
+	return append(out, '\n'), nil
+}
+
+// configElement returns the element of the given JSON config group at the given path, whose
+// elements name sub-groups, but for the last one, which names a sub-group, value or policy
+func configElement(group interface{}, path string) (interface{}, error) {
+	names := strings.Split(strings.Trim(path, "/"), "/")
+	element := group
+	for i, name := range names {
+		current, ok := element.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("%s is not a group", strings.Join(names[:i], "/"))
+		}
+		kinds := []string{"groups"}
+		if i == len(names)-1 {
+			kinds = append(kinds, "values", "policies")
+		}
+		element = nil
+		for _, kind := range kinds {
+			if found, exists := childOf(current, kind, name); exists {
+				element = found
+				break
+			}
+		}
+		if element == nil {
+			return nil, errors.Errorf("%s not found in the channel configuration", strings.Join(names[:i+1], "/"))
+		}
+	}
+	return element, nil
+}
+
+func childOf(group map[string]interface{}, kind string, name string) (interface{}, bool) {
+	children, ok := group[kind].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	child, exists := children[name]
+	return child, exists
+}
@@ -0,0 +1,118 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/tools/configtxgen/configtxgentest"
+	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
+	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	"github.com/hyperledger/fabric/core/config/configtest"
+	"github.com/hyperledger/fabric/peer/common"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchConfig(t *testing.T) {
+	defer resetFlags()
+	InitMSP()
+	resetFlags()
+	cleanup := configtest.SetDevFabricConfigPath(t)
+	defer cleanup()
+
+	mockchain := "mockchain"
+
+	signer, err := common.GetDefaultSigner()
+	require.NoError(t, err)
+
+	configBlock := encoder.New(configtxgentest.Load(genesisconfig.SampleSingleMSPSoloProfile)).GenesisBlockForChannel(mockchain)
+
+	tempDir, err := ioutil.TempDir("", "fetchconfig-output")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	run := func(args ...string) error {
+		resetFlags()
+		mockCF := &ChannelCmdFactory{
+			BroadcastFactory: mockBroadcastClientFactory,
+			Signer:           signer,
+			DeliverClient:    getMockDeliverClientWithBlock(mockchain, configBlock),
+		}
+		cmd := fetchconfigCmd(mockCF)
+		AddFlags(cmd)
+		cmd.SetArgs(append([]string{"-c", mockchain}, args...))
+		return cmd.Execute()
+	}
+
+	t.Run("Block", func(t *testing.T) {
+		output := filepath.Join(tempDir, "config.block")
+		require.NoError(t, run(output))
+
+		data, err := ioutil.ReadFile(output)
+		require.NoError(t, err)
+		assert.Equal(t, putils.MarshalOrPanic(configBlock), data)
+	})
+
+	t.Run("Decode", func(t *testing.T) {
+		output := filepath.Join(tempDir, "config.json")
+		require.NoError(t, run("--decode", output))
+
+		data, err := ioutil.ReadFile(output)
+		require.NoError(t, err)
+		config := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(data, &config))
+		assert.Contains(t, config, "channel_group")
+		assert.Contains(t, string(data), `"type": "solo"`)
+	})
+
+	t.Run("DecodePath", func(t *testing.T) {
+		output := filepath.Join(tempDir, "consensustype.json")
+		require.NoError(t, run("--decode", "--path", "Orderer/ConsensusType", output))
+
+		data, err := ioutil.ReadFile(output)
+		require.NoError(t, err)
+		value := &struct {
+			ModPolicy string `json:"mod_policy"`
+			Value     struct {
+				Type string `json:"type"`
+			} `json:"value"`
+		}{}
+		require.NoError(t, json.Unmarshal(data, value))
+		assert.Equal(t, "Admins", value.ModPolicy)
+		assert.Equal(t, "solo", value.Value.Type)
+
+		output = filepath.Join(tempDir, "org.json")
+		require.NoError(t, run("--decode", "--path", "Orderer/SampleOrg", output))
+		data, err = ioutil.ReadFile(output)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"MSP"`)
+	})
+
+	t.Run("DecodeMissingPath", func(t *testing.T) {
+		err := run("--decode", "--path", "Orderer/NotAnOrg/MSP", filepath.Join(tempDir, "missing.json"))
+		assert.EqualError(t, err, "Orderer/NotAnOrg not found in the channel configuration")
+
+		err = run("--decode", "--path", "Orderer/BatchSize/MaxMessageCount", filepath.Join(tempDir, "missing.json"))
+		assert.EqualError(t, err, "Orderer/BatchSize not found in the channel configuration")
+	})
+
+	t.Run("PathWithoutDecode", func(t *testing.T) {
+		err := run("--path", "Orderer", filepath.Join(tempDir, "nodecode.json"))
+		assert.EqualError(t, err, "a configuration path can only be extracted when decoding, specify --decode")
+	})
+
+	t.Run("TrailingArgs", func(t *testing.T) {
+		err := run("a", "b")
+		assert.EqualError(t, err, "trailing args detected")
+	})
+}
@@ -8,6 +8,7 @@ package channel
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 
@@ -21,6 +22,13 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// blockchainInfoResult is the machine readable output of the getinfo command
+type blockchainInfoResult struct {
+	Height            uint64 `json:"height" yaml:"height"`
+	CurrentBlockHash  string `json:"currentBlockHash" yaml:"currentBlockHash"`
+	PreviousBlockHash string `json:"previousBlockHash" yaml:"previousBlockHash"`
+}
+
 func getinfoCmd(cf *ChannelCmdFactory) *cobra.Command {
 	getinfoCmd := &cobra.Command{
 		Use:   "getinfo",
@@ -102,6 +110,13 @@ func getinfo(cmd *cobra.Command, cf *ChannelCmdFactory) error {
 	if err != nil {
 		return err
 	}
+	if common.StructuredOutput() {
+		return common.PrintOutput(&blockchainInfoResult{
+			Height:            blockChainInfo.Height,
+			CurrentBlockHash:  hex.EncodeToString(blockChainInfo.CurrentBlockHash),
+			PreviousBlockHash: hex.EncodeToString(blockChainInfo.PreviousBlockHash),
+		})
+	}
 	jsonBytes, err := json.Marshal(blockChainInfo)
 	if err != nil {
 		return err
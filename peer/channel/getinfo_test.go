@@ -7,12 +7,15 @@ SPDX-License-Identifier: Apache-2.0
 package channel
 
 import (
+	"bytes"
+	"os"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/peer/common"
 	cb "github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -52,6 +55,34 @@ func TestGetChannelInfo(t *testing.T) {
 	cmd.SetArgs(args)
 
 	assert.NoError(t, cmd.Execute())
+
+	buf := &bytes.Buffer{}
+	common.OutputWriter = buf
+	defer func() { common.OutputWriter = os.Stdout }()
+	defer viper.Set(common.OutputFormatKey, "")
+	for _, test := range []struct {
+		format   string
+		expected string
+	}{
+		{
+			format:   common.OutputJSON,
+			expected: "{\n  \"height\": 1,\n  \"currentBlockHash\": \"43757272656e74426c6f636b48617368\",\n  \"previousBlockHash\": \"50726576696f7573426c6f636b48617368\"\n}\n",
+		},
+		{
+			format:   common.OutputYAML,
+			expected: "height: 1\ncurrentBlockHash: 43757272656e74426c6f636b48617368\npreviousBlockHash: 50726576696f7573426c6f636b48617368\n",
+		},
+	} {
+		buf.Reset()
+		viper.Set(common.OutputFormatKey, test.format)
+		assert.NoError(t, cmd.Execute())
+		assert.Equal(t, test.expected, buf.String())
+	}
+
+	buf.Reset()
+	viper.Set(common.OutputFormatKey, "xml")
+	assert.EqualError(t, cmd.Execute(), "unsupported output format xml, must be one of text, json or yaml")
+	assert.Empty(t, buf.String())
 }
 
 func TestGetChannelInfoMissingChannelID(t *testing.T) {
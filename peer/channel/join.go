@@ -44,6 +44,7 @@ func joinCmd(cf *ChannelCmdFactory) *cobra.Command {
 	}
 	flagList := []string{
 		"blockpath",
+		"snapshotpath",
 	}
 	attachFlags(joinCmd, flagList)
 
@@ -65,16 +66,25 @@ func (e ProposalFailedErr) Error() string {
 }
 
 func getJoinCCSpec() (*pb.ChaincodeSpec, error) {
-	if genesisBlockPath == common.UndefinedParamValue {
-		return nil, errors.New("Must supply genesis block file")
-	}
+	var input *pb.ChaincodeInput
+	if snapshotPath != common.UndefinedParamValue {
+		archive, err := ioutil.ReadFile(snapshotPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the snapshot archive: %s", err)
+		}
+		input = &pb.ChaincodeInput{Args: [][]byte{[]byte(cscc.JoinChainBySnapshot), archive}}
+	} else {
+		if genesisBlockPath == common.UndefinedParamValue {
+			return nil, errors.New("Must supply genesis block file")
+		}
 
-	gb, err := ioutil.ReadFile(genesisBlockPath)
-	if err != nil {
-		return nil, GBFileNotFoundErr(err.Error())
+		gb, err := ioutil.ReadFile(genesisBlockPath)
+		if err != nil {
+			return nil, GBFileNotFoundErr(err.Error())
+		}
+		input = &pb.ChaincodeInput{Args: [][]byte{[]byte(cscc.JoinChain), gb}}
 	}
 	// Build the spec
-	input := &pb.ChaincodeInput{Args: [][]byte{[]byte(cscc.JoinChain), gb}}
 
 	spec := &pb.ChaincodeSpec{
 		Type:        pb.ChaincodeSpec_Type(pb.ChaincodeSpec_Type_value["GOLANG"]),
@@ -129,7 +139,10 @@ func executeJoin(cf *ChannelCmdFactory) (err error) {
 }
 
 func join(cmd *cobra.Command, args []string, cf *ChannelCmdFactory) error {
-	if genesisBlockPath == common.UndefinedParamValue {
+	if genesisBlockPath != common.UndefinedParamValue && snapshotPath != common.UndefinedParamValue {
+		return errors.New("Must supply either the genesis block path or the snapshot path, not both")
+	}
+	if genesisBlockPath == common.UndefinedParamValue && snapshotPath == common.UndefinedParamValue {
 		return errors.New("Must supply genesis block path")
 	}
 	// Parsing of the command line is done so silence cmd usage
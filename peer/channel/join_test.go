@@ -23,6 +23,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hyperledger/fabric/core/scc/cscc"
 	"github.com/hyperledger/fabric/peer/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/spf13/viper"
@@ -79,6 +80,65 @@ func TestJoin(t *testing.T) {
 	assert.NoError(t, cmd.Execute(), "expected join command to succeed")
 }
 
+func TestJoinBySnapshot(t *testing.T) {
+	defer resetFlags()
+
+	InitMSP()
+	resetFlags()
+
+	signer, err := common.GetDefaultSigner()
+	assert.NoError(t, err, "Get default signer error: %v", err)
+
+	mockResponse := &pb.ProposalResponse{
+		Response:    &pb.Response{Status: 200},
+		Endorsement: &pb.Endorsement{},
+	}
+
+	mockEndorserClient := common.GetMockEndorserClient(mockResponse, nil)
+
+	mockCF := &ChannelCmdFactory{
+		EndorserClient:   mockEndorserClient,
+		BroadcastFactory: mockBroadcastClientFactory,
+		Signer:           signer,
+	}
+
+	cmd := joinCmd(mockCF)
+	AddFlags(cmd)
+
+	dir, err := ioutil.TempDir("", "snapshot")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	archivePath := filepath.Join(dir, "mychannel-1000.tar.gz")
+	assert.NoError(t, ioutil.WriteFile(archivePath, []byte("archive"), 0644))
+
+	// the archive of the snapshot is sent to the peer
+	args := []string{"--snapshotpath", archivePath}
+	cmd.SetArgs(args)
+
+	assert.NoError(t, cmd.Execute(), "expected join command to succeed")
+	spec, err := getJoinCCSpec()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte(cscc.JoinChainBySnapshot), []byte("archive")}, spec.Input.Args)
+
+	resetFlags()
+	cmd = joinCmd(mockCF)
+	AddFlags(cmd)
+	args = []string{"--snapshotpath", filepath.Join(dir, "missing.tar.gz")}
+	cmd.SetArgs(args)
+
+	err = cmd.Execute()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error reading the snapshot archive")
+
+	resetFlags()
+	cmd = joinCmd(mockCF)
+	AddFlags(cmd)
+	args = []string{"-b", "mychannel.block", "--snapshotpath", archivePath}
+	cmd.SetArgs(args)
+
+	assert.EqualError(t, cmd.Execute(), "Must supply either the genesis block path or the snapshot path, not both")
+}
+
 func TestJoinNonExistentBlock(t *testing.T) {
 	defer resetFlags()
 
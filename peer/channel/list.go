@@ -23,12 +23,18 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/core/scc/cscc"
+	"github.com/hyperledger/fabric/peer/common"
 	common2 "github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/spf13/cobra"
 )
 
+// channelListResult is the machine readable output of the list command
+type channelListResult struct {
+	Channels []string `json:"channels" yaml:"channels"`
+}
+
 type endorserClient struct {
 	cf *ChannelCmdFactory
 }
@@ -105,6 +111,12 @@ func list(cf *ChannelCmdFactory) error {
 
 	if channels, err := client.getChannels(); err != nil {
 		return err
+	} else if common.StructuredOutput() {
+		result := &channelListResult{Channels: []string{}}
+		for _, channel := range channels {
+			result.Channels = append(result.Channels, channel.ChannelId)
+		}
+		return common.PrintOutput(result)
 	} else {
 		fmt.Println("Channels peers has joined: ")
 
@@ -7,13 +7,16 @@ SPDX-License-Identifier: Apache-2.0
 package channel
 
 import (
+	"bytes"
 	"errors"
+	"os"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/peer/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -53,6 +56,23 @@ func TestListChannels(t *testing.T) {
 		t.Error(err)
 	}
 
+	buf := &bytes.Buffer{}
+	common.OutputWriter = buf
+	defer func() { common.OutputWriter = os.Stdout }()
+	defer viper.Set(common.OutputFormatKey, "")
+	viper.Set(common.OutputFormatKey, common.OutputJSON)
+	assert.NoError(t, listCmd(mockCF).Execute())
+	assert.Equal(t, "{\n  \"channels\": [\n    \"TEST_LIST_CHANNELS\"\n  ]\n}\n", buf.String())
+	buf.Reset()
+	viper.Set(common.OutputFormatKey, common.OutputYAML)
+	assert.NoError(t, listCmd(mockCF).Execute())
+	assert.Equal(t, "channels:\n- TEST_LIST_CHANNELS\n", buf.String())
+	buf.Reset()
+	viper.Set(common.OutputFormatKey, "xml")
+	assert.EqualError(t, listCmd(mockCF).Execute(), "unsupported output format xml, must be one of text, json or yaml")
+	assert.Empty(t, buf.String())
+	viper.Set(common.OutputFormatKey, "")
+
 	testListChannelsEmptyCF(t, mockCF)
 }
 
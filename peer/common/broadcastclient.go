@@ -17,9 +17,14 @@ limitations under the License.
 package common
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/pkg/errors"
+	"github.com/spf13/viper"
 )
 
 type BroadcastClient interface {
@@ -32,18 +37,38 @@ type broadcastClient struct {
 	client ab.AtomicBroadcast_BroadcastClient
 }
 
-// GetBroadcastClient creates a simple instance of the BroadcastClient interface
+// GetBroadcastClient creates an instance of the BroadcastClient interface
+// which submits to the orderer at orderer.address, failing over to the other
+// orderer.addresses and retrying as configured by orderer.client.retries,
+// orderer.client.retryBackoff and orderer.client.retryMaxBackoff
 func GetBroadcastClient() (BroadcastClient, error) {
 	oc, err := NewOrdererClientFromEnv()
 	if err != nil {
 		return nil, err
 	}
-	bc, err := oc.Broadcast()
-	if err != nil {
-		return nil, err
+
+	connect := func(endpoint string) (BroadcastClient, error) {
+		endpointClient := *oc
+		endpointClient.address = endpoint
+		bc, err := endpointClient.Broadcast()
+		if err != nil {
+			return nil, err
+		}
+		return &broadcastClient{client: bc}, nil
 	}
 
-	return &broadcastClient{client: bc}, nil
+	return newRetryingBroadcastClient(ordererEndpointsFromEnv(), connect, retryPolicyFromEnv())
+}
+
+// BroadcastRejection is returned when the ordering service does not accept a
+// transaction
+type BroadcastRejection struct {
+	Status cb.Status
+	Info   string
+}
+
+func (r *BroadcastRejection) Error() string {
+	return fmt.Sprintf("got unexpected status: %v -- %s", r.Status, r.Info)
 }
 
 func (s *broadcastClient) getAck() error {
@@ -52,7 +77,7 @@ func (s *broadcastClient) getAck() error {
 		return err
 	}
 	if msg.Status != cb.Status_SUCCESS {
-		return errors.Errorf("got unexpected status: %v -- %s", msg.Status, msg.Info)
+		return &BroadcastRejection{Status: msg.Status, Info: msg.Info}
 	}
 	return nil
 }
@@ -71,3 +96,201 @@ func (s *broadcastClient) Send(env *cb.Envelope) error {
 func (s *broadcastClient) Close() error {
 	return s.client.CloseSend()
 }
+
+// BroadcastRetryPolicy controls the resubmission of transactions which the
+// ordering service failed to accept
+type BroadcastRetryPolicy struct {
+	// Retries is the number of times a submission is retried after the first attempt
+	Retries int
+	// Backoff is the delay before the first retry, doubled before every further retry
+	Backoff time.Duration
+	// MaxBackoff caps the delay between two retries
+	MaxBackoff time.Duration
+}
+
+// delay returns the time to wait before the given retry, counted from zero
+func (p BroadcastRetryPolicy) delay(retry int) time.Duration {
+	delay := p.Backoff
+	for i := 0; i < retry && (p.MaxBackoff <= 0 || delay < p.MaxBackoff); i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	return delay
+}
+
+// broadcastFailure classifies why a submission to an orderer failed
+type broadcastFailure int
+
+const (
+	// failurePermanent is a rejection of the transaction itself, which no
+	// orderer will accept when resubmitted
+	failurePermanent broadcastFailure = iota
+	// failureTransient is a connection error or a temporary unavailability of
+	// the orderer, which is retried
+	failureTransient
+	// failureEndpoint is an orderer which stopped serving the channel, which is
+	// retried on the other endpoints only
+	failureEndpoint
+)
+
+// haltedConsenterInfos are the infos of the SERVICE_UNAVAILABLE responses
+// sent by orderers whose consenter for the channel stopped for good
+var haltedConsenterInfos = []string{
+	"consenter for this channel has been halted", // kafka
+	"chain is stopped",                           // etcdraft
+	"Exiting",                                    // solo
+}
+
+// classifyBroadcastError tells whether a failed submission may be retried
+func classifyBroadcastError(err error) broadcastFailure {
+	rejection, ok := errors.Cause(err).(*BroadcastRejection)
+	if !ok {
+		return failureTransient
+	}
+	if rejection.Status != cb.Status_SERVICE_UNAVAILABLE {
+		return failurePermanent
+	}
+	for _, info := range haltedConsenterInfos {
+		if strings.Contains(rejection.Info, info) {
+			return failureEndpoint
+		}
+	}
+	return failureTransient
+}
+
+// retryingBroadcastClient is a BroadcastClient which resubmits transactions
+// on transient failures, moving on to the next ordering endpoint with an
+// exponential backoff. As the response of a submission may be lost after the
+// orderer accepted it, a retried transaction may be ordered twice, in which
+// case the duplicate is invalidated by the committing peers.
+type retryingBroadcastClient struct {
+	endpoints []string
+	connect   func(endpoint string) (BroadcastClient, error)
+	policy    BroadcastRetryPolicy
+	sleep     func(time.Duration)
+
+	current BroadcastClient
+	next    int
+}
+
+func newRetryingBroadcastClient(endpoints []string, connect func(string) (BroadcastClient, error), policy BroadcastRetryPolicy) (*retryingBroadcastClient, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("no ordering endpoint provided")
+	}
+	rc := &retryingBroadcastClient{
+		endpoints: endpoints,
+		connect:   connect,
+		policy:    policy,
+		sleep:     time.Sleep,
+	}
+	if err := rc.retry(func() error { return nil }); err != nil {
+		return nil, err
+	}
+	return rc, nil
+}
+
+// Send submits the envelope, retrying according to the retry policy
+func (rc *retryingBroadcastClient) Send(env *cb.Envelope) error {
+	return rc.retry(func() error {
+		return rc.current.Send(env)
+	})
+}
+
+// Close closes the stream to the current orderer, if any
+func (rc *retryingBroadcastClient) Close() error {
+	if rc.current == nil {
+		return nil
+	}
+	err := rc.current.Close()
+	rc.current = nil
+	return err
+}
+
+// retry invokes op on a client connected to an orderer until it succeeds,
+// fails permanently, or the retries or endpoints are exhausted
+func (rc *retryingBroadcastClient) retry(op func() error) error {
+	for retry := 0; ; retry++ {
+		endpoint, err := rc.attempt(op)
+		if err == nil {
+			return nil
+		}
+
+		switch classifyBroadcastError(err) {
+		case failurePermanent:
+			return err
+		case failureEndpoint:
+			rc.dropEndpoint(endpoint)
+			if len(rc.endpoints) == 0 {
+				return errors.WithMessage(err, "no ordering endpoint left")
+			}
+		}
+		if retry >= rc.policy.Retries {
+			return err
+		}
+
+		delay := rc.policy.delay(retry)
+		logger.Warningf("Failed submitting to orderer %s, retrying in %s: %s", endpoint, delay, err)
+		rc.sleep(delay)
+	}
+}
+
+// attempt invokes op once, connecting to the next endpoint first if needed,
+// and returns the endpoint it was submitted to. The stream is dropped on
+// failure so that the next attempt moves on to the next endpoint.
+func (rc *retryingBroadcastClient) attempt(op func() error) (string, error) {
+	endpoint := rc.endpoints[rc.next%len(rc.endpoints)]
+	if rc.current == nil {
+		client, err := rc.connect(endpoint)
+		if err != nil {
+			rc.next++
+			return endpoint, err
+		}
+		rc.current = client
+	}
+
+	err := op()
+	if err != nil && classifyBroadcastError(err) != failurePermanent {
+		rc.Close()
+		rc.next++
+	}
+	return endpoint, err
+}
+
+// dropEndpoint removes an endpoint which will not serve the channel anymore
+func (rc *retryingBroadcastClient) dropEndpoint(endpoint string) {
+	for i, e := range rc.endpoints {
+		if e == endpoint {
+			rc.endpoints = append(rc.endpoints[:i:i], rc.endpoints[i+1:]...)
+			if rc.next > i {
+				rc.next--
+			}
+			return
+		}
+	}
+}
+
+// ordererEndpointsFromEnv returns orderer.address followed by the other
+// orderer.addresses, without duplicates
+func ordererEndpointsFromEnv() []string {
+	endpoints := []string{}
+	seen := map[string]struct{}{}
+	for _, endpoint := range append([]string{viper.GetString("orderer.address")}, viper.GetStringSlice("orderer.addresses")...) {
+		if _, exists := seen[endpoint]; exists || endpoint == "" {
+			continue
+		}
+		seen[endpoint] = struct{}{}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints
+}
+
+// retryPolicyFromEnv returns the retry policy of the global Viper instance
+func retryPolicyFromEnv() BroadcastRetryPolicy {
+	return BroadcastRetryPolicy{
+		Retries:    viper.GetInt("orderer.client.retries"),
+		Backoff:    viper.GetDuration("orderer.client.retryBackoff"),
+		MaxBackoff: viper.GetDuration("orderer.client.retryMaxBackoff"),
+	}
+}
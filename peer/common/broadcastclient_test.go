@@ -0,0 +1,163 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// scriptedBroadcastClient fails the submissions with the scripted errors, in order
+type scriptedBroadcastClient struct {
+	endpoint string
+	errs     *[]error
+	sent     *[]string
+}
+
+func (s *scriptedBroadcastClient) Send(env *cb.Envelope) error {
+	*s.sent = append(*s.sent, s.endpoint)
+	if len(*s.errs) == 0 {
+		return nil
+	}
+	err := (*s.errs)[0]
+	*s.errs = (*s.errs)[1:]
+	return err
+}
+
+func (s *scriptedBroadcastClient) Close() error {
+	return nil
+}
+
+func newScriptedRetryingClient(t *testing.T, endpoints []string, retries int, errs ...error) (*retryingBroadcastClient, *[]string, *[]time.Duration) {
+	sent := &[]string{}
+	connect := func(endpoint string) (BroadcastClient, error) {
+		return &scriptedBroadcastClient{endpoint: endpoint, errs: &errs, sent: sent}, nil
+	}
+	rc, err := newRetryingBroadcastClient(endpoints, connect, BroadcastRetryPolicy{
+		Retries:    retries,
+		Backoff:    100 * time.Millisecond,
+		MaxBackoff: 300 * time.Millisecond,
+	})
+	assert.NoError(t, err)
+	delays := &[]time.Duration{}
+	rc.sleep = func(d time.Duration) { *delays = append(*delays, d) }
+	return rc, sent, delays
+}
+
+func TestBroadcastRetryPolicyDelay(t *testing.T) {
+	policy := BroadcastRetryPolicy{Backoff: 100 * time.Millisecond, MaxBackoff: 500 * time.Millisecond}
+	assert.Equal(t, 100*time.Millisecond, policy.delay(0))
+	assert.Equal(t, 200*time.Millisecond, policy.delay(1))
+	assert.Equal(t, 400*time.Millisecond, policy.delay(2))
+	assert.Equal(t, 500*time.Millisecond, policy.delay(3))
+	assert.Equal(t, 500*time.Millisecond, policy.delay(100))
+
+	policy.MaxBackoff = 0
+	assert.Equal(t, 800*time.Millisecond, policy.delay(3))
+}
+
+func TestClassifyBroadcastError(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected broadcastFailure
+	}{
+		{errors.New("transport is closing"), failureTransient},
+		{errors.WithMessage(errors.New("EOF"), "could not send"), failureTransient},
+		{&BroadcastRejection{Status: cb.Status_SERVICE_UNAVAILABLE, Info: "no Raft leader"}, failureTransient},
+		{&BroadcastRejection{Status: cb.Status_SERVICE_UNAVAILABLE, Info: "will not enqueue, consenter for this channel hasn't started yet"}, failureTransient},
+		{&BroadcastRejection{Status: cb.Status_SERVICE_UNAVAILABLE, Info: "will not enqueue, consenter for this channel has been halted"}, failureEndpoint},
+		{&BroadcastRejection{Status: cb.Status_SERVICE_UNAVAILABLE, Info: "chain is stopped"}, failureEndpoint},
+		{&BroadcastRejection{Status: cb.Status_SERVICE_UNAVAILABLE, Info: "Exiting"}, failureEndpoint},
+		{&BroadcastRejection{Status: cb.Status_BAD_REQUEST, Info: "duplicate transaction"}, failurePermanent},
+		{&BroadcastRejection{Status: cb.Status_FORBIDDEN, Info: "access denied"}, failurePermanent},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, classifyBroadcastError(test.err), test.err.Error())
+	}
+}
+
+func TestRetryingBroadcastClient(t *testing.T) {
+	unavailable := &BroadcastRejection{Status: cb.Status_SERVICE_UNAVAILABLE, Info: "no Raft leader"}
+	halted := &BroadcastRejection{Status: cb.Status_SERVICE_UNAVAILABLE, Info: "chain is stopped"}
+	forbidden := &BroadcastRejection{Status: cb.Status_FORBIDDEN, Info: "access denied"}
+
+	t.Run("Success", func(t *testing.T) {
+		rc, sent, delays := newScriptedRetryingClient(t, []string{"o1", "o2"}, 3)
+		assert.NoError(t, rc.Send(&cb.Envelope{}))
+		assert.NoError(t, rc.Send(&cb.Envelope{}))
+		assert.Equal(t, []string{"o1", "o1"}, *sent)
+		assert.Empty(t, *delays)
+	})
+
+	t.Run("TransientFailuresFailOver", func(t *testing.T) {
+		rc, sent, delays := newScriptedRetryingClient(t, []string{"o1", "o2"}, 3, unavailable, errors.New("EOF"))
+		assert.NoError(t, rc.Send(&cb.Envelope{}))
+		assert.Equal(t, []string{"o1", "o2", "o1"}, *sent)
+		assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, *delays)
+	})
+
+	t.Run("RetriesExhausted", func(t *testing.T) {
+		rc, sent, delays := newScriptedRetryingClient(t, []string{"o1"}, 3, unavailable, unavailable, unavailable, unavailable)
+		err := rc.Send(&cb.Envelope{})
+		assert.Equal(t, unavailable, err)
+		assert.Equal(t, []string{"o1", "o1", "o1", "o1"}, *sent)
+		assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}, *delays)
+	})
+
+	t.Run("NoRetries", func(t *testing.T) {
+		rc, sent, _ := newScriptedRetryingClient(t, []string{"o1", "o2"}, 0, unavailable)
+		assert.Equal(t, unavailable, rc.Send(&cb.Envelope{}))
+		assert.Equal(t, []string{"o1"}, *sent)
+	})
+
+	t.Run("PermanentFailure", func(t *testing.T) {
+		rc, sent, delays := newScriptedRetryingClient(t, []string{"o1", "o2"}, 3, forbidden)
+		assert.Equal(t, forbidden, rc.Send(&cb.Envelope{}))
+		assert.Equal(t, []string{"o1"}, *sent)
+		assert.Empty(t, *delays)
+	})
+
+	t.Run("HaltedEndpointDropped", func(t *testing.T) {
+		rc, sent, _ := newScriptedRetryingClient(t, []string{"o1", "o2"}, 3, halted)
+		assert.NoError(t, rc.Send(&cb.Envelope{}))
+		assert.NoError(t, rc.Send(&cb.Envelope{}))
+		assert.Equal(t, []string{"o1", "o2", "o2"}, *sent)
+		assert.Equal(t, []string{"o2"}, rc.endpoints)
+	})
+
+	t.Run("AllEndpointsHalted", func(t *testing.T) {
+		rc, sent, _ := newScriptedRetryingClient(t, []string{"o1", "o2"}, 3, halted, halted)
+		err := rc.Send(&cb.Envelope{})
+		assert.EqualError(t, err, "no ordering endpoint left: got unexpected status: SERVICE_UNAVAILABLE -- chain is stopped")
+		assert.Equal(t, []string{"o1", "o2"}, *sent)
+	})
+
+	t.Run("ConnectionFailures", func(t *testing.T) {
+		attempts := []string{}
+		connect := func(endpoint string) (BroadcastClient, error) {
+			attempts = append(attempts, endpoint)
+			if endpoint == "o1" {
+				return nil, errors.New("connection refused")
+			}
+			return &scriptedBroadcastClient{endpoint: endpoint, errs: &[]error{}, sent: &[]string{}}, nil
+		}
+		rc, err := newRetryingBroadcastClient([]string{"o1", "o2"}, connect, BroadcastRetryPolicy{Retries: 1})
+		assert.NoError(t, err)
+		assert.NoError(t, rc.Send(&cb.Envelope{}))
+		assert.Equal(t, []string{"o1", "o2"}, attempts)
+
+		_, err = newRetryingBroadcastClient([]string{"o1"}, connect, BroadcastRetryPolicy{Retries: 1})
+		assert.EqualError(t, err, "connection refused")
+
+		_, err = newRetryingBroadcastClient(nil, connect, BroadcastRetryPolicy{})
+		assert.EqualError(t, err, "no ordering endpoint provided")
+	})
+}
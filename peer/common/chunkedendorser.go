@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/comm"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// ProposalChunkSize is the size in bytes of the chunks a signed proposal too
+// large for a single gRPC message is split into
+var ProposalChunkSize = 4 * 1024 * 1024
+
+// chunkingEndorserClient is an endorser client which sends the signed
+// proposals exceeding maxMessageSize to the ChunkedEndorser service in chunks
+// of chunkSize bytes instead of failing on the gRPC message size limit
+type chunkingEndorserClient struct {
+	pb.EndorserClient
+	chunked        pb.ChunkedEndorserClient
+	maxMessageSize int
+	chunkSize      int
+}
+
+func newChunkingEndorserClient(conn *grpc.ClientConn) *chunkingEndorserClient {
+	return &chunkingEndorserClient{
+		EndorserClient: pb.NewEndorserClient(conn),
+		chunked:        pb.NewChunkedEndorserClient(conn),
+		maxMessageSize: comm.MaxSendMsgSize,
+		chunkSize:      ProposalChunkSize,
+	}
+}
+
+// ProcessProposal sends the signed proposal in a single message if it fits
+// in one, and in chunks otherwise
+func (c *chunkingEndorserClient) ProcessProposal(ctx context.Context, in *pb.SignedProposal, opts ...grpc.CallOption) (*pb.ProposalResponse, error) {
+	if proto.Size(in) <= c.maxMessageSize {
+		return c.EndorserClient.ProcessProposal(ctx, in, opts...)
+	}
+
+	signedPropBytes, err := proto.Marshal(in)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshaling signed proposal")
+	}
+
+	stream, err := c.chunked.ProcessProposal(ctx, opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed opening chunked proposal stream")
+	}
+	for offset := 0; offset < len(signedPropBytes); offset += c.chunkSize {
+		end := offset + c.chunkSize
+		if end > len(signedPropBytes) {
+			end = len(signedPropBytes)
+		}
+		if err := stream.Send(&pb.SignedProposalChunk{Content: signedPropBytes[offset:end]}); err != nil {
+			return nil, errors.WithMessage(err, "failed sending proposal chunk")
+		}
+	}
+	return stream.CloseAndRecv()
+}
@@ -0,0 +1,93 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+type countingEndorserServer struct {
+	proposals int
+	chunks    int
+	received  *pb.SignedProposal
+}
+
+func (s *countingEndorserServer) ProcessProposal(_ context.Context, signedProp *pb.SignedProposal) (*pb.ProposalResponse, error) {
+	s.proposals++
+	s.received = signedProp
+	return &pb.ProposalResponse{Response: &pb.Response{Status: 200, Message: "unary"}}, nil
+}
+
+type countingChunkedEndorserServer struct {
+	*countingEndorserServer
+}
+
+func (s *countingChunkedEndorserServer) ProcessProposal(stream pb.ChunkedEndorser_ProcessProposalServer) error {
+	var data []byte
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		s.chunks++
+		data = append(data, chunk.Content...)
+	}
+	s.received = &pb.SignedProposal{}
+	if err := proto.Unmarshal(data, s.received); err != nil {
+		return err
+	}
+	return stream.SendAndClose(&pb.ProposalResponse{Response: &pb.Response{Status: 200, Message: "chunked"}})
+}
+
+func TestChunkingEndorserClient(t *testing.T) {
+	server := &countingEndorserServer{}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	srv := grpc.NewServer()
+	pb.RegisterEndorserServer(srv, server)
+	pb.RegisterChunkedEndorserServer(srv, &countingChunkedEndorserServer{countingEndorserServer: server})
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure(), grpc.WithBlock())
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	client := newChunkingEndorserClient(conn)
+	client.maxMessageSize = 500
+	client.chunkSize = 300
+
+	t.Run("small proposal", func(t *testing.T) {
+		signedProp := &pb.SignedProposal{ProposalBytes: make([]byte, 100)}
+		resp, err := client.ProcessProposal(context.Background(), signedProp)
+		assert.NoError(t, err)
+		assert.Equal(t, "unary", resp.Response.Message)
+		assert.Equal(t, 1, server.proposals)
+		assert.Equal(t, 0, server.chunks)
+	})
+
+	t.Run("large proposal", func(t *testing.T) {
+		signedProp := &pb.SignedProposal{ProposalBytes: make([]byte, 1000), Signature: []byte("signature")}
+		resp, err := client.ProcessProposal(context.Background(), signedProp)
+		assert.NoError(t, err)
+		assert.Equal(t, "chunked", resp.Response.Message)
+		assert.Equal(t, 1, server.proposals)
+		assert.Equal(t, 4, server.chunks)
+		assert.True(t, proto.Equal(signedProp, server.received))
+	})
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	cb "github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	grpc "google.golang.org/grpc"
@@ -103,3 +104,77 @@ func (m *mockAdminClient) SetModuleLogLevel(ctx context.Context, env *cb.Envelop
 func (m *mockAdminClient) RevertLogLevels(ctx context.Context, in *cb.Envelope, opts ...grpc.CallOption) (*empty.Empty, error) {
 	return &empty.Empty{}, m.err
 }
+
+func (m *mockAdminClient) ReloadConfig(ctx context.Context, in *cb.Envelope, opts ...grpc.CallOption) (*pb.ReloadConfigResponse, error) {
+	return &pb.ReloadConfigResponse{}, m.err
+}
+
+func (m *mockAdminClient) ListStateIndexes(ctx context.Context, env *cb.Envelope, opts ...grpc.CallOption) (*pb.StateIndexesResponse, error) {
+	return mockStateIndexesResponse(env), m.err
+}
+
+func (m *mockAdminClient) CreateStateIndex(ctx context.Context, env *cb.Envelope, opts ...grpc.CallOption) (*pb.StateIndexesResponse, error) {
+	return mockStateIndexesResponse(env), m.err
+}
+
+func (m *mockAdminClient) RebuildStateIndexes(ctx context.Context, env *cb.Envelope, opts ...grpc.CallOption) (*pb.StateIndexesResponse, error) {
+	return mockStateIndexesResponse(env), m.err
+}
+
+func (m *mockAdminClient) GetChaincodeStatus(ctx context.Context, env *cb.Envelope, opts ...grpc.CallOption) (*pb.ChaincodeStatusResponse, error) {
+	op := &pb.AdminOperation{}
+	pl := &cb.Payload{}
+	proto.Unmarshal(env.Payload, pl)
+	proto.Unmarshal(pl.Data, op)
+	name := op.GetChaincodeStatusReq().Chaincode
+	if name == "" {
+		name = "mycc"
+	}
+	return &pb.ChaincodeStatusResponse{Chaincodes: []*pb.ChaincodeStatus{{
+		Name:          name + ":1.0",
+		LastHeartbeat: &timestamp.Timestamp{Seconds: 1500000000},
+		Stats:         &pb.ChaincodeRuntimeStats{TransactionsInFlight: 2, TransactionErrors: 1, MemoryBytes: 1048576, Goroutines: 12},
+		Responsive:    true,
+	}}}, m.err
+}
+
+func (m *mockAdminClient) ListSnapshots(ctx context.Context, env *cb.Envelope, opts ...grpc.CallOption) (*pb.SnapshotsResponse, error) {
+	return &pb.SnapshotsResponse{Snapshots: []*pb.Snapshot{{
+		Height:        1000,
+		Path:          "/var/hyperledger/production/snapshots/mychannel/1000",
+		LastBlockHash: []byte{0x01, 0x02, 0x03},
+		Timestamp:     &timestamp.Timestamp{Seconds: 1500000000},
+	}}}, m.err
+}
+
+func (m *mockAdminClient) ListExpiringCertificates(ctx context.Context, env *cb.Envelope, opts ...grpc.CallOption) (*pb.ExpiringCertificatesResponse, error) {
+	return &pb.ExpiringCertificatesResponse{Certificates: []*pb.ExpiringCertificate{{
+		MspId:        "SampleOrg",
+		Role:         "signer",
+		Subject:      "CN=peer0.org1.example.com",
+		SerialNumber: "1f",
+		NotAfter:     &timestamp.Timestamp{Seconds: 1500000000},
+		Expired:      true,
+	}, {
+		ChannelId:    "mychannel",
+		MspId:        "Org1MSP",
+		Role:         "root_cert",
+		Subject:      "CN=ca.org1.example.com",
+		SerialNumber: "2a",
+		NotAfter:     &timestamp.Timestamp{Seconds: 1600000000},
+	}}}, m.err
+}
+
+// mockStateIndexesResponse returns an index of the collection of the request
+func mockStateIndexesResponse(env *cb.Envelope) *pb.StateIndexesResponse {
+	op := &pb.AdminOperation{}
+	pl := &cb.Payload{}
+	proto.Unmarshal(env.Payload, pl)
+	proto.Unmarshal(pl.Data, op)
+	return &pb.StateIndexesResponse{Indexes: []*pb.StateIndex{{
+		Collection:     op.GetIndexReq().Collection,
+		DesignDocument: "indexOwnerDoc",
+		Name:           "indexOwner",
+		Definition:     `{"fields":[{"owner":"asc"}]}`,
+	}}}
+}
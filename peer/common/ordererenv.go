@@ -22,6 +22,9 @@ var (
 	certFile                   string
 	ordererTLSHostnameOverride string
 	connTimeout                time.Duration
+	retries                    int
+	retryBackoff               time.Duration
+	retryMaxBackoff            time.Duration
 )
 
 // SetOrdererEnv adds orderer-specific settings to the global Viper environment
@@ -39,6 +42,9 @@ func SetOrdererEnv(cmd *cobra.Command, args []string) {
 	viper.Set("orderer.tls.enabled", tlsEnabled)
 	viper.Set("orderer.tls.clientAuthRequired", clientAuth)
 	viper.Set("orderer.client.connTimeout", connTimeout)
+	viper.Set("orderer.client.retries", retries)
+	viper.Set("orderer.client.retryBackoff", retryBackoff)
+	viper.Set("orderer.client.retryMaxBackoff", retryMaxBackoff)
 }
 
 // AddOrdererFlags adds flags for orderer-related commands
@@ -61,4 +67,10 @@ func AddOrdererFlags(cmd *cobra.Command) {
 		"", "", "The hostname override to use when validating the TLS connection to the orderer.")
 	flags.DurationVarP(&connTimeout, "connTimeout",
 		"", 3*time.Second, "Timeout for client to connect")
+	flags.IntVarP(&retries, "ordererRetries", "", 3,
+		"Number of times a transaction is resubmitted to the ordering service after a transient failure")
+	flags.DurationVarP(&retryBackoff, "ordererRetryBackoff", "", 500*time.Millisecond,
+		"Delay before the first resubmission to the ordering service, doubled for every further one")
+	flags.DurationVarP(&retryMaxBackoff, "ordererRetryMaxBackoff", "", 5*time.Second,
+		"Maximum delay between two resubmissions to the ordering service")
 }
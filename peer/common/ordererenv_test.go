@@ -7,6 +7,7 @@ package common_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/hyperledger/fabric/peer/common"
 	"github.com/spf13/cobra"
@@ -35,6 +36,9 @@ func TestOrdererCmdEnv(t *testing.T) {
 			assert.Equal(t, sn, viper.GetString("orderer.tls.serverhostoverride"))
 			assert.Equal(t, true, viper.GetBool("orderer.tls.enabled"))
 			assert.Equal(t, true, viper.GetBool("orderer.tls.clientAuthRequired"))
+			assert.Equal(t, 5, viper.GetInt("orderer.client.retries"))
+			assert.Equal(t, time.Second, viper.GetDuration("orderer.client.retryBackoff"))
+			assert.Equal(t, 5*time.Second, viper.GetDuration("orderer.client.retryMaxBackoff"))
 		},
 		PersistentPreRun: common.SetOrdererEnv,
 	}
@@ -43,7 +47,8 @@ func TestOrdererCmdEnv(t *testing.T) {
 
 	runCmd.SetArgs([]string{"test", "--cafile", ca, "--keyfile", key,
 		"--certfile", cert, "--orderer", endpoint, "--tls", "--clientauth",
-		"--ordererTLSHostnameOverride", sn})
+		"--ordererTLSHostnameOverride", sn, "--ordererRetries", "5",
+		"--ordererRetryBackoff", "1s"})
 	err := runCmd.Execute()
 	assert.NoError(t, err)
 
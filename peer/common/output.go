@@ -0,0 +1,73 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// The formats of the results of the peer commands, selected with the
+// global --output flag
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+	OutputYAML = "yaml"
+)
+
+// OutputFormatKey is the Viper key the global --output flag is bound to
+const OutputFormatKey = "output_format"
+
+// OutputWriter is where PrintOutput writes the results of the commands
+var OutputWriter io.Writer = os.Stdout
+
+// OutputFormat returns the format requested for the results of the commands
+func OutputFormat() string {
+	format := viper.GetString(OutputFormatKey)
+	if format == "" {
+		return OutputText
+	}
+	return format
+}
+
+// StructuredOutput tells whether the results of the commands must be printed
+// in a machine readable format rather than as text
+func StructuredOutput() bool {
+	return OutputFormat() != OutputText
+}
+
+// PrintOutput prints the result of a command to OutputWriter in the requested
+// machine readable format
+func PrintOutput(result interface{}) error {
+	return WriteOutput(OutputWriter, OutputFormat(), result)
+}
+
+// WriteOutput writes the result of a command in the given machine readable format
+func WriteOutput(w io.Writer, format string, result interface{}) error {
+	var out []byte
+	var err error
+	switch format {
+	case OutputJSON:
+		out, err = json.MarshalIndent(result, "", "  ")
+		out = append(out, '\n')
+	case OutputYAML:
+		out, err = yaml.Marshal(result)
+	default:
+		return errors.Errorf("unsupported output format %s, must be one of %s, %s or %s", format, OutputText, OutputJSON, OutputYAML)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "error marshaling result as %s", format)
+	}
+	_, err = fmt.Fprint(w, string(out))
+	return err
+}
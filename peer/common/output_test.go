@@ -0,0 +1,52 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperledger/fabric/peer/common"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type outputTestResult struct {
+	Name   string   `json:"name" yaml:"name"`
+	Values []string `json:"values" yaml:"values"`
+}
+
+func TestOutputFormat(t *testing.T) {
+	defer viper.Reset()
+
+	assert.Equal(t, common.OutputText, common.OutputFormat())
+	assert.False(t, common.StructuredOutput())
+
+	viper.Set(common.OutputFormatKey, common.OutputText)
+	assert.False(t, common.StructuredOutput())
+
+	viper.Set(common.OutputFormatKey, common.OutputYAML)
+	assert.Equal(t, common.OutputYAML, common.OutputFormat())
+	assert.True(t, common.StructuredOutput())
+}
+
+func TestWriteOutput(t *testing.T) {
+	result := &outputTestResult{Name: "mychannel", Values: []string{"a", "b"}}
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, common.WriteOutput(buf, common.OutputJSON, result))
+	assert.Equal(t, "{\n  \"name\": \"mychannel\",\n  \"values\": [\n    \"a\",\n    \"b\"\n  ]\n}\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, common.WriteOutput(buf, common.OutputYAML, result))
+	assert.Equal(t, "name: mychannel\nvalues:\n- a\n- b\n", buf.String())
+
+	buf.Reset()
+	err := common.WriteOutput(buf, "xml", result)
+	assert.EqualError(t, err, "unsupported output format xml, must be one of text, json or yaml")
+	assert.Empty(t, buf.String())
+}
@@ -68,13 +68,14 @@ func newPeerClientForClientConfig(address, override string, clientConfig comm.Cl
 	return pClient, nil
 }
 
-// Endorser returns a client for the Endorser service
+// Endorser returns a client for the Endorser service, which sends the
+// proposals too large for a single message to the ChunkedEndorser service
 func (pc *PeerClient) Endorser() (pb.EndorserClient, error) {
 	conn, err := pc.commonClient.NewConnection(pc.address, pc.sn)
 	if err != nil {
 		return nil, errors.WithMessage(err, fmt.Sprintf("endorser client failed to connect to %s", pc.address))
 	}
-	return pb.NewEndorserClient(conn), nil
+	return newChunkingEndorserClient(conn), nil
 }
 
 // Deliver returns a client for the Deliver service
@@ -118,8 +118,9 @@ func (s *mspMessageCryptoService) GetPKIidOfCert(peerIdentity api.PeerIdentityTy
 // sequence number that the block's header contains.
 // else returns error
 func (s *mspMessageCryptoService) VerifyBlock(chainID common.ChainID, seqNum uint64, signedBlock []byte) error {
-	// - Convert signedBlock to common.Block.
-	block, err := utils.GetBlockFromBlockBytes(signedBlock)
+	// - Convert signedBlock to common.Block, without copying its transactions
+	// since the block is only read
+	block, err := utils.GetBlockFromBlockBytesNoCopy(signedBlock)
 	if err != nil {
 		return fmt.Errorf("Failed unmarshalling block bytes on channel [%s]: [%s]", chainID, err)
 	}
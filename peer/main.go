@@ -7,7 +7,6 @@ SPDX-License-Identifier: Apache-2.0
 package main
 
 import (
-	_ "net/http/pprof"
 	"os"
 	"strings"
 
@@ -40,6 +39,8 @@ func main() {
 
 	mainFlags.String("logging-level", "", "Default logging level and overrides, see core.yaml for full syntax")
 	viper.BindPFlag("logging_level", mainFlags.Lookup("logging-level"))
+	mainFlags.String("output", common.OutputText, "Format of the results of the commands supporting it: text, json or yaml")
+	viper.BindPFlag(common.OutputFormatKey, mainFlags.Lookup("output"))
 
 	mainCmd.AddCommand(version.Cmd())
 	mainCmd.AddCommand(node.Cmd())
@@ -0,0 +1,118 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hyperledger/fabric/peer/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var certificatesWithin time.Duration
+
+func certificatesCmd(cf *indexCmdFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "certificates",
+		Short: "Lists the certificates of the node which are about to expire.",
+		Long:  `Lists the certificates of the local MSP, the TLS certificates and the certificates of the MSPs of the channels of the running node which expire within the given duration, or within peer.certificateExpiration.warnBefore, earliest first.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCertificatesCmd(cmd, args, cf)
+		},
+	}
+	cmd.Flags().DurationVarP(&certificatesWithin, "within", "w", 0, "List the certificates which expire within this duration, such as 720h")
+	return cmd
+}
+
+func runCertificatesCmd(cmd *cobra.Command, args []string, cf *indexCmdFactory) error {
+	if len(args) != 0 {
+		return fmt.Errorf("trailing args detected: %s", args)
+	}
+	if certificatesWithin < 0 {
+		return errors.New("the duration must not be negative")
+	}
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	var err error
+	if cf == nil {
+		if cf, err = newIndexCmdFactory(); err != nil {
+			return err
+		}
+	}
+	request := &pb.ExpiringCertificatesRequest{}
+	if certificatesWithin > 0 {
+		request.Within = ptypes.DurationProto(certificatesWithin)
+	}
+	env, err := cf.wrapWithEnvelope(&pb.AdminOperation{
+		Content: &pb.AdminOperation_ExpiringCertsReq{ExpiringCertsReq: request},
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed signing certificates request")
+	}
+	resp, err := cf.adminClient.ListExpiringCertificates(context.Background(), env)
+	if err != nil {
+		return errors.WithMessage(err, "failed listing the expiring certificates")
+	}
+	return printCertificates(resp)
+}
+
+// certificate is the machine readable output of the certificates command
+type certificate struct {
+	Channel      string    `json:"channel,omitempty" yaml:"channel,omitempty"`
+	MSPID        string    `json:"msp_id" yaml:"msp_id"`
+	Role         string    `json:"role" yaml:"role"`
+	Subject      string    `json:"subject" yaml:"subject"`
+	SerialNumber string    `json:"serial_number" yaml:"serial_number"`
+	NotAfter     time.Time `json:"not_after" yaml:"not_after"`
+	Expired      bool      `json:"expired" yaml:"expired"`
+}
+
+// printCertificates prints the expiring certificates in the requested output
+// format
+func printCertificates(resp *pb.ExpiringCertificatesResponse) error {
+	certificates := []*certificate{}
+	for _, c := range resp.Certificates {
+		notAfter, err := ptypes.Timestamp(c.NotAfter)
+		if err != nil {
+			return errors.Wrapf(err, "invalid expiration of certificate %s", c.Subject)
+		}
+		certificates = append(certificates, &certificate{
+			Channel:      c.ChannelId,
+			MSPID:        c.MspId,
+			Role:         c.Role,
+			Subject:      c.Subject,
+			SerialNumber: c.SerialNumber,
+			NotAfter:     notAfter.UTC(),
+			Expired:      c.Expired,
+		})
+	}
+	if common.StructuredOutput() {
+		return common.PrintOutput(certificates)
+	}
+	if len(certificates) == 0 {
+		fmt.Println("No certificate expiring")
+		return nil
+	}
+	for _, c := range certificates {
+		state := "expires"
+		if c.Expired {
+			state = "expired"
+		}
+		owner := "node"
+		if c.Channel != "" {
+			owner = "channel " + c.Channel
+		}
+		fmt.Printf("%s %s: %s %s of MSP %s, %s, serial number %s\n", state, c.NotAfter.Format(time.RFC3339), owner, c.Role, c.MSPID, c.Subject, c.SerialNumber)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"testing"
+
+	common2 "github.com/hyperledger/fabric/peer/common"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCertificatesCmd(t *testing.T) {
+	defer viper.Reset()
+	defer func() { certificatesWithin = 0 }()
+
+	cmd := certificatesCmd(newTestIndexCmdFactory(nil))
+	cmd.SetArgs([]string{})
+	assert.NoError(t, cmd.Execute())
+
+	viper.Set(common2.OutputFormatKey, common2.OutputJSON)
+	cmd = certificatesCmd(newTestIndexCmdFactory(nil))
+	cmd.SetArgs([]string{"--within", "720h"})
+	assert.NoError(t, cmd.Execute())
+
+	cmd = certificatesCmd(newTestIndexCmdFactory(nil))
+	cmd.SetArgs([]string{"--within", "-1h"})
+	assert.EqualError(t, cmd.Execute(), "the duration must not be negative")
+
+	cmd = certificatesCmd(newTestIndexCmdFactory(errors.New("certificate monitoring is not enabled")))
+	cmd.SetArgs([]string{"--within", "1h"})
+	assert.EqualError(t, cmd.Execute(), "failed listing the expiring certificates: certificate monitoring is not enabled")
+}
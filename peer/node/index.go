@@ -0,0 +1,200 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/peer/common"
+	common2 "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+)
+
+var (
+	indexChannelID  string
+	indexChaincode  string
+	indexCollection string
+	indexFile       string
+)
+
+// indexCmdFactory holds the clients used by the index commands
+type indexCmdFactory struct {
+	adminClient      pb.AdminClient
+	wrapWithEnvelope func(msg proto.Message) (*common2.Envelope, error)
+}
+
+// newIndexCmdFactory returns the factory of the admin client of the local
+// peer and of the request envelopes signed by the local MSP
+func newIndexCmdFactory() (*indexCmdFactory, error) {
+	adminClient, err := common.GetAdminClient()
+	if err != nil {
+		return nil, err
+	}
+	signer, err := common.GetDefaultSignerFnc()
+	if err != nil {
+		return nil, errors.Errorf("failed obtaining default signer: %v", err)
+	}
+	localSigner := crypto.NewSignatureHeaderCreator(signer)
+	return &indexCmdFactory{
+		adminClient: adminClient,
+		wrapWithEnvelope: func(msg proto.Message) (*common2.Envelope, error) {
+			return utils.CreateSignedEnvelope(common2.HeaderType_PEER_ADMIN_OPERATION, "", localSigner, msg, 0, 0)
+		},
+	}, nil
+}
+
+// indexOperation is a call of the admin service managing the state indexes
+type indexOperation func(client pb.AdminClient, ctx context.Context, env *common2.Envelope, opts ...grpc.CallOption) (*pb.StateIndexesResponse, error)
+
+func indexCmd() *cobra.Command {
+	nodeIndexCmd.AddCommand(indexListCmd(nil))
+	nodeIndexCmd.AddCommand(indexCreateCmd(nil))
+	nodeIndexCmd.AddCommand(indexRebuildCmd(nil))
+	return nodeIndexCmd
+}
+
+var nodeIndexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "Manages the CouchDB indexes of a chaincode: list|create|rebuild.",
+	Long:  `Lists, creates or rebuilds the CouchDB indexes of the state databases of a chaincode, and of its private data collections, on the running node.`,
+}
+
+func indexListCmd(cf *indexCmdFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists the indexes of a chaincode.",
+		Long:  `Lists the indexes of the state databases of a chaincode, or of one of its collections.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runIndexCmd(cmd, args, cf, nil, pb.AdminClient.ListStateIndexes)
+		},
+	}
+	addIndexFlags(cmd.Flags())
+	return cmd
+}
+
+func indexCreateCmd(cf *indexCmdFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Creates an index for a chaincode.",
+		Long:  `Creates an index, defined in the same format as the index files of the chaincode packages, in the state database of a chaincode, or of one of its collections, and warms it.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if indexFile == "" {
+				return errors.New("the index definition file must be specified")
+			}
+			definition, err := ioutil.ReadFile(indexFile)
+			if err != nil {
+				return errors.Wrapf(err, "failed reading the index definition file %s", indexFile)
+			}
+			return runIndexCmd(cmd, args, cf, definition, pb.AdminClient.CreateStateIndex)
+		},
+	}
+	flags := cmd.Flags()
+	addIndexFlags(flags)
+	flags.StringVarP(&indexFile, "file", "f", "", "The file holding the JSON definition of the index")
+	return cmd
+}
+
+func indexRebuildCmd(cf *indexCmdFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rebuild",
+		Short: "Rebuilds the indexes of a chaincode.",
+		Long:  `Drops, re-creates and warms the indexes of the state databases of a chaincode, or of one of its collections.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runIndexCmd(cmd, args, cf, nil, pb.AdminClient.RebuildStateIndexes)
+		},
+	}
+	addIndexFlags(cmd.Flags())
+	return cmd
+}
+
+func addIndexFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&indexChannelID, "channelID", "C", "", "The channel on which the chaincode is instantiated")
+	flags.StringVarP(&indexChaincode, "name", "n", "", "The name of the chaincode")
+	flags.StringVarP(&indexCollection, "collection", "", "", "The private data collection whose database is managed, instead of all the databases of the chaincode")
+}
+
+func runIndexCmd(cmd *cobra.Command, args []string, cf *indexCmdFactory, definition []byte, op indexOperation) error {
+	if len(args) != 0 {
+		return fmt.Errorf("trailing args detected: %s", args)
+	}
+	if indexChannelID == "" || indexChaincode == "" {
+		return errors.New("the channel and the chaincode must be specified")
+	}
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	var err error
+	if cf == nil {
+		if cf, err = newIndexCmdFactory(); err != nil {
+			return err
+		}
+	}
+	env, err := cf.wrapWithEnvelope(&pb.AdminOperation{
+		Content: &pb.AdminOperation_IndexReq{
+			IndexReq: &pb.StateIndexRequest{
+				ChannelId:  indexChannelID,
+				Chaincode:  indexChaincode,
+				Collection: indexCollection,
+				Definition: definition,
+			},
+		},
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed signing index request")
+	}
+	resp, err := op(cf.adminClient, context.Background(), env)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed managing the indexes of chaincode %s on channel %s", indexChaincode, indexChannelID))
+	}
+	return printStateIndexes(resp)
+}
+
+// stateIndex is the machine readable output of the index commands
+type stateIndex struct {
+	Collection     string `json:"collection,omitempty" yaml:"collection,omitempty"`
+	DesignDocument string `json:"design_document" yaml:"design_document"`
+	Name           string `json:"name" yaml:"name"`
+	Definition     string `json:"definition" yaml:"definition"`
+}
+
+// printStateIndexes prints the indexes of the chaincode in the requested
+// output format
+func printStateIndexes(resp *pb.StateIndexesResponse) error {
+	indexes := []*stateIndex{}
+	for _, index := range resp.Indexes {
+		indexes = append(indexes, &stateIndex{
+			Collection:     index.Collection,
+			DesignDocument: index.DesignDocument,
+			Name:           index.Name,
+			Definition:     index.Definition,
+		})
+	}
+	if common.StructuredOutput() {
+		return common.PrintOutput(indexes)
+	}
+	if len(indexes) == 0 {
+		fmt.Println("No index defined")
+		return nil
+	}
+	for _, index := range indexes {
+		database := "public state"
+		if index.Collection != "" {
+			database = "collection " + index.Collection
+		}
+		fmt.Printf("%s/%s (%s): %s\n", index.DesignDocument, index.Name, database, index.Definition)
+	}
+	return nil
+}
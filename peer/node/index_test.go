@@ -0,0 +1,95 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	common2 "github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestIndexCmdFactory(err error) *indexCmdFactory {
+	return &indexCmdFactory{
+		adminClient: common2.GetMockAdminClient(err),
+		wrapWithEnvelope: func(msg proto.Message) (*cb.Envelope, error) {
+			return &cb.Envelope{Payload: utils.MarshalOrPanic(&cb.Payload{Data: utils.MarshalOrPanic(msg)})}, nil
+		},
+	}
+}
+
+func resetIndexFlags() {
+	indexChannelID, indexChaincode, indexCollection, indexFile = "", "", "", ""
+}
+
+func TestIndexCmds(t *testing.T) {
+	defer viper.Reset()
+	defer resetIndexFlags()
+
+	dir, err := ioutil.TempDir("", "index")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	definitionFile := filepath.Join(dir, "indexOwner.json")
+	require.NoError(t, ioutil.WriteFile(definitionFile, []byte(`{"index":{"fields":["owner"]},"name":"indexOwner","type":"json"}`), 0644))
+
+	cf := newTestIndexCmdFactory(nil)
+	for _, cmd := range []*cobra.Command{indexListCmd(cf), indexCreateCmd(cf), indexRebuildCmd(cf)} {
+		resetIndexFlags()
+		args := []string{"-C", "mychannel", "-n", "marbles", "--collection", "collectionMarbles"}
+		if cmd.Name() == "create" {
+			args = append(args, "-f", definitionFile)
+		}
+		cmd.SetArgs(args)
+		assert.NoError(t, cmd.Execute(), cmd.Name())
+	}
+
+	viper.Set(common2.OutputFormatKey, common2.OutputJSON)
+	cmd := indexListCmd(cf)
+	cmd.SetArgs([]string{"-C", "mychannel", "-n", "marbles"})
+	assert.NoError(t, cmd.Execute())
+}
+
+func TestIndexCmdsErrors(t *testing.T) {
+	defer resetIndexFlags()
+
+	resetIndexFlags()
+	cmd := indexListCmd(newTestIndexCmdFactory(nil))
+	cmd.SetArgs([]string{"-C", "mychannel"})
+	assert.EqualError(t, cmd.Execute(), "the channel and the chaincode must be specified")
+
+	resetIndexFlags()
+	cmd = indexListCmd(newTestIndexCmdFactory(nil))
+	cmd.SetArgs([]string{"-C", "mychannel", "-n", "marbles", "extra"})
+	assert.EqualError(t, cmd.Execute(), "trailing args detected: [extra]")
+
+	resetIndexFlags()
+	cmd = indexCreateCmd(newTestIndexCmdFactory(nil))
+	cmd.SetArgs([]string{"-C", "mychannel", "-n", "marbles"})
+	assert.EqualError(t, cmd.Execute(), "the index definition file must be specified")
+
+	resetIndexFlags()
+	cmd = indexCreateCmd(newTestIndexCmdFactory(nil))
+	cmd.SetArgs([]string{"-C", "mychannel", "-n", "marbles", "-f", "/does/not/exist.json"})
+	err := cmd.Execute()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed reading the index definition file /does/not/exist.json")
+
+	resetIndexFlags()
+	cmd = indexRebuildCmd(newTestIndexCmdFactory(errors.New("ledger not found")))
+	cmd.SetArgs([]string{"-C", "mychannel", "-n", "marbles"})
+	assert.EqualError(t, cmd.Execute(), "failed managing the indexes of chaincode marbles on channel mychannel: ledger not found")
+}
@@ -0,0 +1,79 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/spf13/viper"
+)
+
+// startIndexLagReporter reports every metrics.interval how many blocks the
+// block index and the history database of the ledger of every channel lag
+// behind the ledger, unless the blocks are neither indexed nor committed to
+// the history database asynchronously. The returned function stops it.
+func startIndexLagReporter() (stop func()) {
+	if !ledgerconfig.IsAsyncBlockIndexingEnabled() && !ledgerconfig.IsAsyncHistoryCommitEnabled() {
+		return func() {}
+	}
+	interval := viper.GetDuration("metrics.interval")
+	if interval <= 0 {
+		interval = time.Second
+	}
+	scope := metrics.RootScope.SubScope("ledger")
+	channelIDs := func() []string {
+		var channelIDs []string
+		for _, info := range peer.GetChannelsInfo() {
+			channelIDs = append(channelIDs, info.ChannelId)
+		}
+		return channelIDs
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				reportIndexLag(scope, channelIDs(), peer.GetLedger)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// reportIndexLag updates the block_index_lag and history_db_lag gauges, tagged
+// with the channel, of the ledgers of the given channels which report the lag
+// of their indexes
+func reportIndexLag(scope metrics.Scope, channelIDs []string, getLedger func(channelID string) ledger.PeerLedger) {
+	for _, channelID := range channelIDs {
+		l := getLedger(channelID)
+		if l == nil {
+			continue
+		}
+		reporter, ok := ledgermgmt.Unwrap(l).(ledger.IndexLagReporter)
+		if !ok {
+			continue
+		}
+		lag, err := reporter.GetIndexLag()
+		if err != nil {
+			logger.Warningf("Failed retrieving the index lag of channel %s: %s", channelID, err)
+			continue
+		}
+		tagged := scope.Tagged(map[string]string{"channel": channelID})
+		tagged.Gauge("block_index_lag").Update(float64(lag.Height - lag.BlockIndexHeight))
+		tagged.Gauge("history_db_lag").Update(float64(lag.Height - lag.HistoryDBHeight))
+	}
+}
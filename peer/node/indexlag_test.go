@@ -0,0 +1,66 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type indexLagLedger struct {
+	ledger.PeerLedger
+	lag *ledger.IndexLag
+	err error
+}
+
+func (l *indexLagLedger) GetIndexLag() (*ledger.IndexLag, error) {
+	return l.lag, l.err
+}
+
+type gaugeValue float64
+
+func (g *gaugeValue) Update(value float64) {
+	*g = gaugeValue(value)
+}
+
+// gaugeScope records the gauges by name, prefixed by the channel they are
+// tagged with
+type gaugeScope struct {
+	metrics.Scope
+	prefix string
+	gauges map[string]*gaugeValue
+}
+
+func (s *gaugeScope) Gauge(name string) metrics.Gauge {
+	g := new(gaugeValue)
+	s.gauges[s.prefix+name] = g
+	return g
+}
+
+func (s *gaugeScope) Tagged(tags map[string]string) metrics.Scope {
+	return &gaugeScope{Scope: s.Scope, prefix: tags["channel"] + "/", gauges: s.gauges}
+}
+
+func TestReportIndexLag(t *testing.T) {
+	ledgers := map[string]ledger.PeerLedger{
+		"lagging":  &indexLagLedger{lag: &ledger.IndexLag{Height: 10, BlockIndexHeight: 7, HistoryDBHeight: 9}},
+		"failing":  &indexLagLedger{err: errors.New("unreadable")},
+		"no-index": &struct{ ledger.PeerLedger }{},
+	}
+	scope := &gaugeScope{Scope: metrics.NewNoOpScope(), gauges: make(map[string]*gaugeValue)}
+
+	reportIndexLag(scope, []string{"lagging", "failing", "no-index", "missing"}, func(channelID string) ledger.PeerLedger {
+		return ledgers[channelID]
+	})
+	assert.Len(t, scope.gauges, 2)
+	assert.Equal(t, gaugeValue(3), *scope.gauges["lagging/block_index_lag"])
+	assert.Equal(t, gaugeValue(1), *scope.gauges["lagging/history_db_lag"])
+}
@@ -26,7 +26,7 @@ import (
 
 const (
 	nodeFuncName = "node"
-	nodeCmdDes   = "Operate a peer node: start|status."
+	nodeCmdDes   = "Operate a peer node: start|status|preflight|reload|index|snapshot|certificates."
 )
 
 var logger = flogging.MustGetLogger("nodeCmd")
@@ -35,6 +35,11 @@ var logger = flogging.MustGetLogger("nodeCmd")
 func Cmd() *cobra.Command {
 	nodeCmd.AddCommand(startCmd())
 	nodeCmd.AddCommand(statusCmd())
+	nodeCmd.AddCommand(preflightCmd())
+	nodeCmd.AddCommand(reloadCmd())
+	nodeCmd.AddCommand(indexCmd())
+	nodeCmd.AddCommand(snapshotCmd())
+	nodeCmd.AddCommand(certificatesCmd(nil))
 
 	return nodeCmd
 }
@@ -0,0 +1,402 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric/core/config"
+	"github.com/hyperledger/fabric/core/container/util"
+	"github.com/hyperledger/fabric/core/ledger/util/couchdb"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/msp/mgmt"
+	"github.com/hyperledger/fabric/peer/common"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// The statuses of the preflight checks
+const (
+	checkPass = "PASS"
+	checkWarn = "WARN"
+	checkFail = "FAIL"
+	checkSkip = "SKIP"
+)
+
+var preflightTimeout time.Duration
+
+func preflightCmd() *cobra.Command {
+	flags := nodePreflightCmd.Flags()
+	flags.DurationVarP(&preflightTimeout, "timeout", "t", 5*time.Second,
+		"Timeout of each connectivity check")
+
+	return nodePreflightCmd
+}
+
+var nodePreflightCmd = &cobra.Command{
+	Use:   "preflight",
+	Short: "Checks the configuration of the node before starting it.",
+	Long: `Checks the configuration of the node, its MSP and TLS material, the availability of its ports and its ` +
+		`connectivity to CouchDB, Docker and the other peers, then reports the result of every check. ` +
+		`Returns an error if any of the checks failed.`,
+	// The configuration and the MSP are loaded by the checks themselves, so
+	// that their errors are reported rather than aborting the command
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("trailing args detected: %s", args)
+		}
+		// Parsing of the command line is done so silence cmd usage
+		cmd.SilenceUsage = true
+		report := preflight()
+		if err := writePreflightReport(os.Stdout, common.OutputFormat(), report); err != nil {
+			return err
+		}
+		if !report.Passed {
+			return errors.New("preflight checks failed")
+		}
+		return nil
+	},
+}
+
+// preflightCheck is the result of one of the checks of the preflight command
+type preflightCheck struct {
+	Name    string `json:"name" yaml:"name"`
+	Status  string `json:"status" yaml:"status"`
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// preflightReport is the machine readable output of the preflight command
+type preflightReport struct {
+	Passed bool              `json:"passed" yaml:"passed"`
+	Checks []*preflightCheck `json:"checks" yaml:"checks"`
+}
+
+func (r *preflightReport) add(check *preflightCheck) {
+	if check.Status == checkFail {
+		r.Passed = false
+	}
+	r.Checks = append(r.Checks, check)
+}
+
+func newCheck(name, status, format string, args ...interface{}) *preflightCheck {
+	return &preflightCheck{Name: name, Status: status, Message: fmt.Sprintf(format, args...)}
+}
+
+// preflight runs all the checks, skipping them when the configuration can't
+// be loaded since they all depend on it
+func preflight() *preflightReport {
+	report := &preflightReport{Passed: true}
+	configCheck := checkConfig()
+	report.add(configCheck)
+	if configCheck.Status == checkFail {
+		return report
+	}
+
+	report.add(checkMSP())
+	report.add(checkTLS())
+	// The ports are checked before the gossip endpoint, which binds the
+	// listen address of the peer for a short time
+	report.add(checkPorts())
+	report.add(checkCouchDB())
+	report.add(checkDocker())
+	report.add(checkGossip())
+	return report
+}
+
+// writePreflightReport writes the report as a table, or in the requested
+// machine readable format
+func writePreflightReport(w io.Writer, format string, report *preflightReport) error {
+	if format != common.OutputText {
+		return common.WriteOutput(w, format, report)
+	}
+	for _, check := range report.Checks {
+		fmt.Fprintf(w, "%-4s  %-7s  %s\n", check.Status, check.Name, check.Message)
+	}
+	return nil
+}
+
+func checkConfig() *preflightCheck {
+	const name = "config"
+	if err := common.InitConfig(common.CmdRoot); err != nil {
+		return newCheck(name, checkFail, "failed loading the configuration: %s", err)
+	}
+
+	var problems []string
+	for _, key := range []string{"peer.id", "peer.localMspId", "peer.fileSystemPath"} {
+		if viper.GetString(key) == "" {
+			problems = append(problems, fmt.Sprintf("%s is not set", key))
+		}
+	}
+	for _, key := range []string{"peer.listenAddress", "peer.address"} {
+		if _, _, err := net.SplitHostPort(viper.GetString(key)); err != nil {
+			problems = append(problems, fmt.Sprintf("%s is invalid: %s", key, err))
+		}
+	}
+	if db := viper.GetString("ledger.state.stateDatabase"); db != "goleveldb" && db != "CouchDB" {
+		problems = append(problems, fmt.Sprintf("ledger.state.stateDatabase %q must be goleveldb or CouchDB", db))
+	}
+	if len(problems) != 0 {
+		return newCheck(name, checkFail, "%s", strings.Join(problems, "; "))
+	}
+	return newCheck(name, checkPass, "configuration loaded from %s", viper.ConfigFileUsed())
+}
+
+func checkMSP() *preflightCheck {
+	const name = "msp"
+	mspDir := config.GetPath("peer.mspConfigPath")
+	subDirs := []string{"signcerts", "cacerts"}
+	if viper.GetString("peer.BCCSP.Default") == "SW" {
+		subDirs = append(subDirs, "keystore")
+	}
+	for _, subDir := range subDirs {
+		files, err := ioutil.ReadDir(filepath.Join(mspDir, subDir))
+		if err != nil {
+			return newCheck(name, checkFail, "MSP folder %s is invalid: %s", mspDir, err)
+		}
+		if len(files) == 0 {
+			return newCheck(name, checkFail, "MSP folder %s is invalid: %s is empty", mspDir, subDir)
+		}
+	}
+
+	mspType := viper.GetString("peer.localMspType")
+	if mspType == "" {
+		mspType = msp.ProviderTypeToString(msp.FABRIC)
+	}
+	if mspType != msp.ProviderTypeToString(msp.FABRIC) {
+		return newCheck(name, checkFail, "MSP type %s is not supported by the peer", mspType)
+	}
+	if err := common.InitCrypto(mspDir, viper.GetString("peer.localMspId"), mspType); err != nil {
+		return newCheck(name, checkFail, "%s", err)
+	}
+	if _, err := mgmt.GetLocalMSP().GetDefaultSigningIdentity(); err != nil {
+		return newCheck(name, checkFail, "failed getting the signing identity of the MSP: %s", err)
+	}
+	return newCheck(name, checkPass, "MSP %s loaded from %s", viper.GetString("peer.localMspId"), mspDir)
+}
+
+func checkTLS() *preflightCheck {
+	const name = "tls"
+	if !viper.GetBool("peer.tls.enabled") {
+		return newCheck(name, checkSkip, "TLS is disabled")
+	}
+
+	serverConfig, err := peer.GetServerConfig()
+	if err != nil {
+		return newCheck(name, checkFail, "%s", err)
+	}
+	serverCert, err := tls.X509KeyPair(serverConfig.SecOpts.Certificate, serverConfig.SecOpts.Key)
+	if err != nil {
+		return newCheck(name, checkFail, "TLS certificate and key do not match: %s", err)
+	}
+	cert, err := x509.ParseCertificate(serverCert.Certificate[0])
+	if err != nil {
+		return newCheck(name, checkFail, "failed parsing the TLS certificate: %s", err)
+	}
+	if now := time.Now(); now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return newCheck(name, checkFail, "TLS certificate is only valid from %s to %s", cert.NotBefore, cert.NotAfter)
+	}
+
+	if serverConfig.SecOpts.RequireClientCert {
+		if _, err := peer.GetClientCertificate(); err != nil {
+			return newCheck(name, checkFail, "%s", err)
+		}
+	}
+	return newCheck(name, checkPass, "TLS certificate valid until %s", cert.NotAfter)
+}
+
+func checkPorts() *preflightCheck {
+	const name = "ports"
+	keys := []string{"peer.listenAddress", chaincodeListenAddrKey, "peer.adminService.listenAddress"}
+	if viper.GetBool("peer.eventsGateway.enabled") {
+		keys = append(keys, "peer.eventsGateway.listenAddress")
+	}
+	if viper.GetBool("peer.profile.enabled") {
+		keys = append(keys, "peer.profile.listenAddress")
+	}
+	if viper.GetBool("metrics.enabled") && viper.GetString("metrics.reporter") == "prom" {
+		keys = append(keys, "metrics.promReporter.listenAddress")
+	}
+
+	var problems, addresses []string
+	checked := map[string]bool{}
+	for _, key := range keys {
+		address := viper.GetString(key)
+		if address == "" || checked[address] {
+			continue
+		}
+		checked[address] = true
+		lis, err := net.Listen("tcp", address)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s %s is unavailable: %s", key, address, err))
+			continue
+		}
+		lis.Close()
+		addresses = append(addresses, address)
+	}
+	if len(problems) != 0 {
+		return newCheck(name, checkFail, "%s", strings.Join(problems, "; "))
+	}
+	return newCheck(name, checkPass, "addresses %s are available", strings.Join(addresses, ", "))
+}
+
+func checkCouchDB() *preflightCheck {
+	const name = "couchdb"
+	if viper.GetString("ledger.state.stateDatabase") != "CouchDB" {
+		return newCheck(name, checkSkip, "the state database is not CouchDB")
+	}
+
+	couchDBDef, err := couchdb.GetCouchDBDefinition()
+	if err != nil {
+		return newCheck(name, checkFail, "%s", err)
+	}
+	addresses := append([]string{couchDBDef.URL}, couchDBDef.ClusterAddresses...)
+	for _, address := range addresses {
+		if check := checkCouchDBNode(name, address, couchDBDef); check != nil {
+			return check
+		}
+	}
+	return newCheck(name, checkPass, "CouchDB reachable at %s", strings.Join(addresses, ", "))
+}
+
+// checkCouchDBNode returns a failed check if the CouchDB node at the supplied
+// address is unreachable or rejects the credentials, or nil
+func checkCouchDBNode(name, address string, couchDBDef *couchdb.CouchDBDef) *preflightCheck {
+	req, err := http.NewRequest(http.MethodGet, "http://"+address+"/", nil)
+	if err != nil {
+		return newCheck(name, checkFail, "CouchDB address %s is invalid: %s", address, err)
+	}
+	if couchDBDef.Username != "" {
+		req.SetBasicAuth(couchDBDef.Username, couchDBDef.Password)
+	}
+	resp, err := (&http.Client{Timeout: preflightTimeout}).Do(req)
+	if err != nil {
+		return newCheck(name, checkFail, "failed connecting to CouchDB at %s: %s", address, err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized:
+		return newCheck(name, checkFail, "CouchDB at %s rejected the configured credentials", address)
+	default:
+		return newCheck(name, checkFail, "CouchDB at %s returned status %s", address, resp.Status)
+	}
+}
+
+func checkDocker() *preflightCheck {
+	const name = "docker"
+	if viper.GetString("chaincode.mode") == "dev" {
+		return newCheck(name, checkSkip, "chaincodes are run by the user in development mode")
+	}
+
+	endpoint := viper.GetString("vm.endpoint")
+	client, err := util.NewDockerClient()
+	if err != nil {
+		return newCheck(name, checkFail, "failed creating the Docker client for %s: %s", endpoint, err)
+	}
+	client.SetTimeout(preflightTimeout)
+	if err := client.Ping(); err != nil {
+		return newCheck(name, checkFail, "failed connecting to Docker at %s: %s", endpoint, err)
+	}
+	return newCheck(name, checkPass, "Docker reachable at %s", endpoint)
+}
+
+func checkGossip() *preflightCheck {
+	const name = "gossip"
+	var problems []string
+	for _, bootstrap := range viper.GetStringSlice("peer.gossip.bootstrap") {
+		if isSelf(bootstrap) {
+			continue
+		}
+		conn, err := net.DialTimeout("tcp", bootstrap, preflightTimeout)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("bootstrap peer %s is unreachable: %s", bootstrap, err))
+			continue
+		}
+		conn.Close()
+	}
+
+	endpoint := viper.GetString("peer.gossip.externalEndpoint")
+	if endpoint == "" {
+		problems = append(problems, "peer.gossip.externalEndpoint is not set, the peer won't be known to other organizations")
+	} else if _, _, err := net.SplitHostPort(endpoint); err != nil {
+		return newCheck(name, checkFail, "peer.gossip.externalEndpoint is invalid: %s", err)
+	} else if err := checkEndpointReachable(endpoint, viper.GetString("peer.listenAddress")); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if len(problems) != 0 {
+		// The other peers may not be started yet, and the network may not
+		// route the external endpoint back to this host
+		return newCheck(name, checkWarn, "%s", strings.Join(problems, "; "))
+	}
+	return newCheck(name, checkPass, "external endpoint %s reachable", endpoint)
+}
+
+// isSelf tells whether the endpoint designates the peer itself, as the
+// bootstrap peer of the first peer of an organization usually does
+func isSelf(endpoint string) bool {
+	for _, key := range []string{"peer.address", "peer.gossip.endpoint", "peer.gossip.externalEndpoint"} {
+		if endpoint == viper.GetString(key) {
+			return true
+		}
+	}
+	host, port, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return false
+	}
+	_, listenPort, err := net.SplitHostPort(viper.GetString("peer.listenAddress"))
+	if err != nil || port != listenPort {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return host == "localhost" || (ip != nil && (ip.IsLoopback() || ip.IsUnspecified()))
+}
+
+// checkEndpointReachable verifies that a connection to the external endpoint
+// reaches a listener on the listen address of the peer
+func checkEndpointReachable(endpoint, listenAddress string) error {
+	lis, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return errors.Errorf("failed listening on %s to check the reachability of external endpoint %s: %s", listenAddress, endpoint, err)
+	}
+	defer lis.Close()
+
+	accepted := make(chan struct{})
+	go func() {
+		conn, err := lis.Accept()
+		if err == nil {
+			conn.Close()
+			close(accepted)
+		}
+	}()
+
+	conn, err := net.DialTimeout("tcp", endpoint, preflightTimeout)
+	if err != nil {
+		return errors.Errorf("external endpoint %s is unreachable: %s", endpoint, err)
+	}
+	defer conn.Close()
+	select {
+	case <-accepted:
+		return nil
+	case <-time.After(preflightTimeout):
+		return errors.Errorf("external endpoint %s does not lead to listen address %s", endpoint, listenAddress)
+	}
+}
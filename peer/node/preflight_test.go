@@ -0,0 +1,188 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/hyperledger/fabric/core/config/configtest"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreflightReport(t *testing.T) {
+	report := &preflightReport{Passed: true}
+	report.add(newCheck("config", checkPass, "configuration loaded from %s", "core.yaml"))
+	assert.True(t, report.Passed)
+	report.add(newCheck("docker", checkFail, "failed connecting to Docker"))
+	report.add(newCheck("gossip", checkWarn, "peer.gossip.externalEndpoint is not set"))
+	assert.False(t, report.Passed)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, writePreflightReport(buf, "text", report))
+	assert.Equal(t, "PASS  config   configuration loaded from core.yaml\n"+
+		"FAIL  docker   failed connecting to Docker\n"+
+		"WARN  gossip   peer.gossip.externalEndpoint is not set\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, writePreflightReport(buf, "json", report))
+	assert.Contains(t, buf.String(), `"passed": false`)
+	assert.Contains(t, buf.String(), `"status": "FAIL"`)
+}
+
+func TestCheckMSP(t *testing.T) {
+	defer viper.Reset()
+	mspDir, err := configtest.GetDevMspDir()
+	require.NoError(t, err)
+	viper.Set("peer.localMspId", "SampleOrg")
+	viper.Set("peer.BCCSP.Default", "SW")
+
+	viper.Set("peer.mspConfigPath", mspDir)
+	check := checkMSP()
+	assert.Equal(t, checkPass, check.Status, check.Message)
+
+	emptyDir, err := ioutil.TempDir("", "preflight")
+	require.NoError(t, err)
+	defer os.RemoveAll(emptyDir)
+	require.NoError(t, os.Mkdir(filepath.Join(emptyDir, "signcerts"), 0755))
+	viper.Set("peer.mspConfigPath", emptyDir)
+	check = checkMSP()
+	assert.Equal(t, checkFail, check.Status)
+	assert.Contains(t, check.Message, "signcerts is empty")
+}
+
+func TestCheckTLS(t *testing.T) {
+	defer viper.Reset()
+	check := checkTLS()
+	assert.Equal(t, checkSkip, check.Status)
+
+	dir, err := ioutil.TempDir("", "preflight")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+	writePair := func(name string) {
+		pair, err := ca.NewServerCertKeyPair("localhost")
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name+".crt"), pair.Cert, 0644))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name+".key"), pair.Key, 0600))
+	}
+	writePair("server")
+	writePair("other")
+
+	viper.Set("peer.tls.enabled", true)
+	viper.Set("peer.tls.cert.file", filepath.Join(dir, "server.crt"))
+	viper.Set("peer.tls.key.file", filepath.Join(dir, "server.key"))
+	check = checkTLS()
+	assert.Equal(t, checkPass, check.Status, check.Message)
+
+	viper.Set("peer.tls.key.file", filepath.Join(dir, "other.key"))
+	check = checkTLS()
+	assert.Equal(t, checkFail, check.Status)
+	assert.Contains(t, check.Message, "TLS certificate and key do not match")
+
+	viper.Set("peer.tls.key.file", filepath.Join(dir, "missing.key"))
+	check = checkTLS()
+	assert.Equal(t, checkFail, check.Status)
+	assert.Contains(t, check.Message, "error loading TLS key")
+}
+
+func TestCheckPorts(t *testing.T) {
+	defer viper.Reset()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := lis.Addr().String()
+
+	viper.Set("peer.listenAddress", address)
+	check := checkPorts()
+	assert.Equal(t, checkFail, check.Status)
+	assert.Contains(t, check.Message, "peer.listenAddress "+address+" is unavailable")
+
+	lis.Close()
+	check = checkPorts()
+	assert.Equal(t, checkPass, check.Status, check.Message)
+}
+
+func TestCheckCouchDB(t *testing.T) {
+	defer viper.Reset()
+	check := checkCouchDB()
+	assert.Equal(t, checkSkip, check.Status)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, _ := r.BasicAuth(); username != "admin" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+	preflightTimeout = time.Second
+	viper.Set("ledger.state.stateDatabase", "CouchDB")
+	viper.Set("ledger.state.couchDBConfig.couchDBAddress", strings.TrimPrefix(server.URL, "http://"))
+
+	viper.Set("ledger.state.couchDBConfig.username", "admin")
+	viper.Set("ledger.state.couchDBConfig.password", "secret")
+	check = checkCouchDB()
+	assert.Equal(t, checkPass, check.Status, check.Message)
+
+	viper.Set("ledger.state.couchDBConfig.password", "wrong")
+	check = checkCouchDB()
+	assert.Equal(t, checkFail, check.Status)
+	assert.Contains(t, check.Message, "rejected the configured credentials")
+
+	// every node of the cluster is checked
+	viper.Set("ledger.state.couchDBConfig.password", "secret")
+	viper.Set("ledger.state.couchDBConfig.clusterAddresses", []string{"127.0.0.1:1"})
+	check = checkCouchDB()
+	assert.Equal(t, checkFail, check.Status)
+	assert.Contains(t, check.Message, "failed connecting to CouchDB at 127.0.0.1:1")
+	viper.Set("ledger.state.couchDBConfig.clusterAddresses", []string{})
+
+	server.Close()
+	check = checkCouchDB()
+	assert.Equal(t, checkFail, check.Status)
+	assert.Contains(t, check.Message, "failed connecting to CouchDB")
+}
+
+func TestCheckGossip(t *testing.T) {
+	defer viper.Reset()
+	preflightTimeout = time.Second
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := lis.Addr().String()
+	lis.Close()
+	viper.Set("peer.listenAddress", address)
+	viper.Set("peer.gossip.bootstrap", []string{address})
+
+	check := checkGossip()
+	assert.Equal(t, checkWarn, check.Status)
+	assert.Contains(t, check.Message, "peer.gossip.externalEndpoint is not set")
+
+	viper.Set("peer.gossip.externalEndpoint", "peer0")
+	check = checkGossip()
+	assert.Equal(t, checkFail, check.Status)
+	assert.Contains(t, check.Message, "peer.gossip.externalEndpoint is invalid")
+
+	viper.Set("peer.gossip.externalEndpoint", address)
+	check = checkGossip()
+	assert.Equal(t, checkPass, check.Status, check.Message)
+
+	// the bootstrap peer is not started
+	viper.Set("peer.gossip.bootstrap", []string{"127.0.0.1:1"})
+	check = checkGossip()
+	assert.Equal(t, checkWarn, check.Status)
+	assert.Contains(t, check.Message, "bootstrap peer 127.0.0.1:1 is unreachable")
+}
@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/profiling"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/spf13/viper"
+)
+
+// profilingService runs the profiling server of the peer, which is started
+// once peer.profile.enabled is set, at boot or by a reload of the
+// configuration. Unsetting it turns the profiles off but keeps the listener,
+// so that they can be turned on again through the /profiling endpoint
+type profilingService struct {
+	lock   sync.Mutex
+	server *profiling.Server
+	// handlers are served by the server besides the profiles, by pattern
+	handlers map[string]http.Handler
+}
+
+// setEnabled turns the profiles on or off, starting the server if needed
+func (p *profilingService) setEnabled(enabled bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.server != nil {
+		p.server.SetEnabled(enabled)
+		return
+	}
+	if !enabled {
+		return
+	}
+
+	server, err := newProfilingServer()
+	if err != nil {
+		logger.Errorf("Failed starting profiling server: %s", err)
+		return
+	}
+	for pattern, handler := range p.handlers {
+		server.Handle(pattern, handler)
+	}
+	p.server = server
+	go func() {
+		if err := server.Start(); err != nil {
+			logger.Errorf("Profiling server exited with error: %s", err)
+		}
+	}()
+}
+
+// stop stops the profiling server if it was started
+func (p *profilingService) stop() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.server != nil {
+		p.server.Stop()
+	}
+}
+
+// newProfilingServer returns the profiling server configured by peer.profile
+func newProfilingServer() (*profiling.Server, error) {
+	options := profiling.Options{
+		ListenAddress:        viper.GetString("peer.profile.listenAddress"),
+		Enabled:              true,
+		BlockProfileRate:     viper.GetInt("peer.profile.blockProfileRate"),
+		MutexProfileFraction: viper.GetInt("peer.profile.mutexProfileFraction"),
+	}
+	secOpts, err := peer.GetProfilingSecureOptions()
+	if err != nil {
+		return nil, err
+	}
+	if secOpts.UseTLS {
+		options.TLS, err = profiling.NewTLSConfig(secOpts.Certificate, secOpts.Key, secOpts.ClientRootCAs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return profiling.NewServer(options)
+}
@@ -0,0 +1,64 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfilingService(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("peer.profile.listenAddress", "127.0.0.1:0")
+
+	p := &profilingService{handlers: map[string]http.Handler{
+		"/teapot": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		}),
+	}}
+	defer p.stop()
+
+	// the server isn't started until the profiles are enabled
+	p.setEnabled(false)
+	assert.Nil(t, p.server)
+
+	p.setEnabled(true)
+	require.NotNil(t, p.server)
+	resp, err := http.Get("http://" + p.server.Addr().String() + "/debug/pprof/heap")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// disabling the profiles keeps the listener
+	server := p.server
+	p.setEnabled(false)
+	assert.Equal(t, server, p.server)
+	assert.False(t, p.server.Enabled())
+	resp, err = http.Get("http://" + p.server.Addr().String() + "/debug/pprof/heap")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	// the handlers are served whether the profiles are enabled or not
+	resp, err = http.Get("http://" + p.server.Addr().String() + "/teapot")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusTeapot, resp.StatusCode)
+}
+
+func TestProfilingServiceRequiresTLS(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("peer.profile.listenAddress", "0.0.0.0:0")
+
+	p := &profilingService{}
+	p.setEnabled(true)
+	assert.Nil(t, p.server)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/core/peer"
+)
+
+// channelReadiness records the startup of the channels at peer boot. It
+// reports it as the channel_ready gauge and the channel_startup_duration
+// histogram, tagged with the channel, and serves it on the operations
+// endpoint as a health check.
+type channelReadiness struct {
+	scope    metrics.Scope
+	mutex    sync.Mutex
+	channels map[string]peer.ChannelReadiness
+}
+
+// channelStatus is the readiness of a channel served on the operations endpoint
+type channelStatus struct {
+	ChannelID string `json:"channel_id"`
+	Ready     bool   `json:"ready"`
+	Error     string `json:"error,omitempty"`
+	Duration  string `json:"duration"`
+}
+
+func newChannelReadiness(scope metrics.Scope) *channelReadiness {
+	return &channelReadiness{scope: scope, channels: make(map[string]peer.ChannelReadiness)}
+}
+
+// record is the listener of the readiness of the channels
+func (r *channelReadiness) record(readiness peer.ChannelReadiness) {
+	ready := 0.0
+	if readiness.Err == nil {
+		ready = 1
+	}
+	tagged := r.scope.Tagged(map[string]string{"channel": readiness.ChannelID})
+	tagged.Gauge("channel_ready").Update(ready)
+	tagged.Histogram("channel_startup_duration").RecordDuration(readiness.Duration)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.channels[readiness.ChannelID] = readiness
+}
+
+// ServeHTTP serves the readiness of the channels started at boot, encoded as
+// JSON, with the status 503 if any of them failed to start
+func (r *channelReadiness) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.mutex.Lock()
+	statuses := []channelStatus{}
+	healthy := true
+	for _, readiness := range r.channels {
+		status := channelStatus{
+			ChannelID: readiness.ChannelID,
+			Ready:     readiness.Err == nil,
+			Duration:  readiness.Duration.String(),
+		}
+		if readiness.Err != nil {
+			status.Error = readiness.Err.Error()
+			healthy = false
+		}
+		statuses = append(statuses, status)
+	}
+	r.mutex.Unlock()
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].ChannelID < statuses[j].ChannelID })
+
+	w.Header().Set("Content-Type", "application/json")
+	if !healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(statuses)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelReadiness(t *testing.T) {
+	scope := &gaugeScope{Scope: metrics.NewNoOpScope(), gauges: make(map[string]*gaugeValue)}
+	readiness := newChannelReadiness(scope)
+
+	get := func() (int, []channelStatus) {
+		rec := httptest.NewRecorder()
+		readiness.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/channels/readiness", nil))
+		var statuses []channelStatus
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &statuses))
+		return rec.Code, statuses
+	}
+
+	code, statuses := get()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, statuses)
+
+	readiness.record(peer.ChannelReadiness{ChannelID: "ready", Duration: time.Second})
+	code, statuses = get()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []channelStatus{{ChannelID: "ready", Ready: true, Duration: "1s"}}, statuses)
+	assert.Equal(t, gaugeValue(1), *scope.gauges["ready/channel_ready"])
+
+	readiness.record(peer.ChannelReadiness{ChannelID: "failed", Err: errors.New("corrupted ledger"), Duration: time.Millisecond})
+	code, statuses = get()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, []channelStatus{
+		{ChannelID: "failed", Ready: false, Error: "corrupted ledger", Duration: "1ms"},
+		{ChannelID: "ready", Ready: true, Duration: "1s"},
+	}, statuses)
+	assert.Equal(t, gaugeValue(0), *scope.gauges["failed/channel_ready"])
+
+	rec := httptest.NewRecorder()
+	readiness.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/channels/readiness", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
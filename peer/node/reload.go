@@ -0,0 +1,80 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/peer/common"
+	common2 "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func reloadCmd() *cobra.Command {
+	return nodeReloadCmd
+}
+
+var nodeReloadCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "Reloads the configuration of the node.",
+	Long:  `Makes the running node read again the reloadable settings of core.yaml and of its environment.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("trailing args detected: %s", args)
+		}
+		// Parsing of the command line is done so silence cmd usage
+		cmd.SilenceUsage = true
+		return reloadConfig()
+	},
+}
+
+func reloadConfig() error {
+	adminClient, err := common.GetAdminClient()
+	if err != nil {
+		return err
+	}
+	signer, err := common.GetDefaultSignerFnc()
+	if err != nil {
+		return errors.Errorf("failed obtaining default signer: %v", err)
+	}
+
+	env, err := utils.CreateSignedEnvelope(common2.HeaderType_PEER_ADMIN_OPERATION, "", crypto.NewSignatureHeaderCreator(signer), &pb.AdminOperation{}, 0, 0)
+	if err != nil {
+		return errors.WithMessage(err, "failed signing reload request")
+	}
+	resp, err := adminClient.ReloadConfig(context.Background(), env)
+	if err != nil {
+		return errors.WithMessage(err, "failed reloading the configuration of the local peer")
+	}
+	return printReloadResult(resp)
+}
+
+// reloadResult is the machine readable output of the reload command
+type reloadResult struct {
+	ChangedKeys []string `json:"changed_keys" yaml:"changed_keys"`
+}
+
+// printReloadResult prints the keys changed by the reload in the requested
+// output format
+func printReloadResult(resp *pb.ReloadConfigResponse) error {
+	if common.StructuredOutput() {
+		return common.PrintOutput(&reloadResult{ChangedKeys: resp.ChangedKeys})
+	}
+	if len(resp.ChangedKeys) == 0 {
+		fmt.Println("No configuration key changed")
+		return nil
+	}
+	for _, key := range resp.ChangedKeys {
+		fmt.Println(key)
+	}
+	return nil
+}
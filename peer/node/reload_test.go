@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/admin"
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/msp"
+	common2 "github.com/hyperledger/fabric/peer/common"
+	"github.com/hyperledger/fabric/peer/mocks"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloadConfig(t *testing.T) {
+	defer viper.Reset()
+
+	signer := &mocks.Signer{}
+	common2.GetDefaultSignerFnc = func() (msp.SigningIdentity, error) {
+		return signer, nil
+	}
+
+	dir, err := ioutil.TempDir("", "reload")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	configFile := filepath.Join(dir, "core.yaml")
+	require.NoError(t, ioutil.WriteFile(configFile, []byte("peer:\n  gossip:\n    deepHistoryDepth: 42\n"), 0644))
+
+	viper.Set("peer.address", "localhost:7074")
+	viper.Set("peer.client.connTimeout", 10*time.Millisecond)
+	peerServer, err := peer.NewPeerServer("localhost:7074", comm.ServerConfig{})
+	require.NoError(t, err)
+	pb.RegisterAdminServer(peerServer.Server(), admin.NewAdminServer(&mockEvaluator{}))
+	go peerServer.Start()
+	defer peerServer.Stop()
+
+	// the peer does not know its configuration file
+	assert.Error(t, reloadConfig())
+
+	viper.SetConfigFile(configFile)
+	assert.NoError(t, reloadConfig())
+	viper.Set(common2.OutputFormatKey, common2.OutputJSON)
+	assert.NoError(t, reloadConfig())
+
+	viper.Set("peer.address", "")
+	assert.Error(t, reloadConfig())
+}
+
+func TestPrintReloadResult(t *testing.T) {
+	defer viper.Reset()
+	assert.NoError(t, printReloadResult(&pb.ReloadConfigResponse{}))
+	assert.NoError(t, printReloadResult(&pb.ReloadConfigResponse{ChangedKeys: []string{"logging.level"}}))
+	viper.Set(common2.OutputFormatKey, common2.OutputYAML)
+	assert.NoError(t, printReloadResult(&pb.ReloadConfigResponse{ChangedKeys: []string{"logging.level"}}))
+}
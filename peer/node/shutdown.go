@@ -0,0 +1,114 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/core/handlers/auth/filter"
+	"github.com/hyperledger/fabric/core/peer"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
+)
+
+const defaultShutdownGracePeriod = 20 * time.Second
+
+// gracefulShutdown stops the services of the peer in an order which lets the
+// work in progress complete within the grace period
+type gracefulShutdown struct {
+	gracePeriod time.Duration
+	// endorser drains the proposals being endorsed
+	endorser *filter.DrainFilter
+	// deliverServers are drained if they implement peer.Drainer
+	deliverServers []pb.DeliverServer
+	// stopGossip stops the channels, which waits for their block commits
+	stopGossip  func()
+	grpcServers []*comm.GRPCServer
+	// closeClients close the connections opened to the other nodes
+	closeClients []func()
+	// closeLedgers flushes and closes the ledgers and their databases
+	closeLedgers func()
+	// flushCaches export the metrics and the traces not yet reported
+	flushCaches []func()
+}
+
+// shutdownGracePeriod returns the time the peer has to shut down gracefully
+func shutdownGracePeriod() time.Duration {
+	gracePeriod := viper.GetDuration("peer.shutdownGracePeriod")
+	if gracePeriod <= 0 {
+		logger.Warningf("`peer.shutdownGracePeriod` not set; defaulting to %s", defaultShutdownGracePeriod)
+		gracePeriod = defaultShutdownGracePeriod
+	}
+	return gracePeriod
+}
+
+// run stops accepting new proposals and drains those being endorsed, ends the
+// deliver streams telling the clients to retry against another peer, waits for
+// the block commits in progress, stops the gRPC servers, closes the connections
+// to the other nodes, closes the ledgers and finally flushes the caches
+func (s *gracefulShutdown) run() {
+	deadline := time.Now().Add(s.gracePeriod)
+	logger.Infof("Shutting down the peer, with a grace period of %s", s.gracePeriod)
+
+	if s.endorser != nil {
+		if err := s.endorser.Drain(time.Until(deadline)); err != nil {
+			logger.Warningf("Failed draining the endorsements: %s", err)
+		} else {
+			logger.Info("Drained the endorsements")
+		}
+	}
+
+	for _, deliverServer := range s.deliverServers {
+		if drainer, ok := deliverServer.(peer.Drainer); ok {
+			drainer.Drain()
+		}
+	}
+
+	if s.stopGossip != nil {
+		s.stopGossip()
+		logger.Info("Stopped the channels")
+	}
+
+	for _, grpcServer := range s.grpcServers {
+		if !grpcServer.GracefulStop(time.Until(deadline)) {
+			logger.Warningf("Cancelled the requests still served by %s at the end of the grace period", grpcServer.Address())
+		}
+	}
+
+	for _, closeClient := range s.closeClients {
+		closeClient()
+	}
+
+	if s.closeLedgers != nil {
+		s.closeLedgers()
+	}
+
+	for _, flushCache := range s.flushCaches {
+		flushCache()
+	}
+	logger.Info("Peer shut down")
+}
+
+// handleShutdownSignals shuts the peer down gracefully once it receives
+// SIGTERM or SIGINT. The returned channel is closed once the peer is shut
+// down, which the gRPC servers stopping by the shutdown must wait for.
+func handleShutdownSignals(shutdown *gracefulShutdown) <-chan struct{} {
+	halted := make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		sig := <-signals
+		logger.Infof("Received %s, shutting down", sig)
+		shutdown.run()
+		close(halted)
+	}()
+	return halted
+}
@@ -0,0 +1,99 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"context"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/core/handlers/auth/filter"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type drainingDeliverServer struct {
+	pb.DeliverServer
+	drain func()
+}
+
+func (ds *drainingDeliverServer) Drain() {
+	ds.drain()
+}
+
+func TestGracefulShutdown(t *testing.T) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	grpcServer, err := comm.NewGRPCServerFromListener(lis, comm.ServerConfig{SecOpts: &comm.SecureOptions{}})
+	require.NoError(t, err)
+	served := make(chan error)
+	go func() {
+		served <- grpcServer.Start()
+	}()
+	// wait for the server to serve
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, grpcServer.Address(), grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	conn.Close()
+
+	var steps []string
+	shutdown := &gracefulShutdown{
+		gracePeriod: time.Minute,
+		endorser:    filter.NewDrainFilter(),
+		deliverServers: []pb.DeliverServer{
+			&drainingDeliverServer{drain: func() { steps = append(steps, "deliver") }},
+			// servers which can't be drained are left to the gRPC server
+			&struct{ pb.DeliverServer }{},
+		},
+		stopGossip:   func() { steps = append(steps, "gossip") },
+		grpcServers:  []*comm.GRPCServer{grpcServer},
+		closeClients: []func(){func() { steps = append(steps, "clients") }},
+		closeLedgers: func() { steps = append(steps, "ledgers") },
+		flushCaches:  []func(){func() { steps = append(steps, "caches") }},
+	}
+	shutdown.run()
+
+	assert.Equal(t, []string{"deliver", "gossip", "clients", "ledgers", "caches"}, steps)
+	assert.NoError(t, <-served)
+	_, err = shutdown.endorser.ProcessProposal(context.Background(), nil)
+	assert.Error(t, err)
+}
+
+func TestHandleShutdownSignals(t *testing.T) {
+	closed := make(chan struct{})
+	halted := handleShutdownSignals(&gracefulShutdown{
+		closeLedgers: func() { close(closed) },
+	})
+
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGTERM))
+	select {
+	case <-halted:
+	case <-time.After(10 * time.Second):
+		t.Fatal("the peer was not shut down")
+	}
+	// the peer is halted once the ledgers are closed
+	select {
+	case <-closed:
+	default:
+		t.Fatal("the ledgers were not closed")
+	}
+}
+
+func TestShutdownGracePeriod(t *testing.T) {
+	defer viper.Set("peer.shutdownGracePeriod", nil)
+	assert.Equal(t, defaultShutdownGracePeriod, shutdownGracePeriod())
+	viper.Set("peer.shutdownGracePeriod", "5s")
+	assert.Equal(t, 5*time.Second, shutdownGracePeriod())
+}
@@ -0,0 +1,114 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hyperledger/fabric/peer/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var snapshotChannelID string
+
+func snapshotCmd() *cobra.Command {
+	nodeSnapshotCmd.AddCommand(snapshotListCmd(nil))
+	return nodeSnapshotCmd
+}
+
+var nodeSnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Manages the state snapshots of a channel: list.",
+	Long:  `Lists the state snapshots generated by the running node every ledger.snapshots.interval blocks of a channel.`,
+}
+
+func snapshotListCmd(cf *indexCmdFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists the state snapshots of a channel.",
+		Long:  `Lists the state snapshots of a channel retained by the node, oldest first.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSnapshotListCmd(cmd, args, cf)
+		},
+	}
+	cmd.Flags().StringVarP(&snapshotChannelID, "channelID", "C", "", "The channel whose snapshots are listed")
+	return cmd
+}
+
+func runSnapshotListCmd(cmd *cobra.Command, args []string, cf *indexCmdFactory) error {
+	if len(args) != 0 {
+		return fmt.Errorf("trailing args detected: %s", args)
+	}
+	if snapshotChannelID == "" {
+		return errors.New("the channel must be specified")
+	}
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	var err error
+	if cf == nil {
+		if cf, err = newIndexCmdFactory(); err != nil {
+			return err
+		}
+	}
+	env, err := cf.wrapWithEnvelope(&pb.AdminOperation{
+		Content: &pb.AdminOperation_SnapshotsReq{
+			SnapshotsReq: &pb.SnapshotsRequest{ChannelId: snapshotChannelID},
+		},
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed signing snapshots request")
+	}
+	resp, err := cf.adminClient.ListSnapshots(context.Background(), env)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed listing the snapshots of channel %s", snapshotChannelID))
+	}
+	return printSnapshots(resp)
+}
+
+// snapshot is the machine readable output of the snapshot commands
+type snapshot struct {
+	Height        uint64    `json:"height" yaml:"height"`
+	Path          string    `json:"path" yaml:"path"`
+	LastBlockHash string    `json:"last_block_hash" yaml:"last_block_hash"`
+	Timestamp     time.Time `json:"timestamp" yaml:"timestamp"`
+}
+
+// printSnapshots prints the snapshots of the channel in the requested output
+// format
+func printSnapshots(resp *pb.SnapshotsResponse) error {
+	snapshots := []*snapshot{}
+	for _, s := range resp.Snapshots {
+		timestamp, err := ptypes.Timestamp(s.Timestamp)
+		if err != nil {
+			return errors.Wrapf(err, "invalid timestamp of the snapshot at height %d", s.Height)
+		}
+		snapshots = append(snapshots, &snapshot{
+			Height:        s.Height,
+			Path:          s.Path,
+			LastBlockHash: hex.EncodeToString(s.LastBlockHash),
+			Timestamp:     timestamp.UTC(),
+		})
+	}
+	if common.StructuredOutput() {
+		return common.PrintOutput(snapshots)
+	}
+	if len(snapshots) == 0 {
+		fmt.Println("No snapshot available")
+		return nil
+	}
+	for _, s := range snapshots {
+		fmt.Printf("height %d, block hash %s, generated %s: %s\n", s.Height, s.LastBlockHash, s.Timestamp.Format(time.RFC3339), s.Path)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"testing"
+
+	common2 "github.com/hyperledger/fabric/peer/common"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotListCmd(t *testing.T) {
+	defer viper.Reset()
+	defer func() { snapshotChannelID = "" }()
+
+	cmd := snapshotListCmd(newTestIndexCmdFactory(nil))
+	cmd.SetArgs([]string{"-C", "mychannel"})
+	assert.NoError(t, cmd.Execute())
+
+	viper.Set(common2.OutputFormatKey, common2.OutputJSON)
+	cmd = snapshotListCmd(newTestIndexCmdFactory(nil))
+	cmd.SetArgs([]string{"-C", "mychannel"})
+	assert.NoError(t, cmd.Execute())
+
+	snapshotChannelID = ""
+	cmd = snapshotListCmd(newTestIndexCmdFactory(nil))
+	cmd.SetArgs([]string{})
+	assert.EqualError(t, cmd.Execute(), "the channel must be specified")
+
+	cmd = snapshotListCmd(newTestIndexCmdFactory(errors.New("ledger not found")))
+	cmd.SetArgs([]string{"-C", "mychannel"})
+	assert.EqualError(t, cmd.Execute(), "failed listing the snapshots of channel mychannel: ledger not found")
+}
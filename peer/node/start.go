@@ -7,28 +7,36 @@ SPDX-License-Identifier: Apache-2.0
 package node
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/certmonitor"
 	ccdef "github.com/hyperledger/fabric/common/chaincode"
 	"github.com/hyperledger/fabric/common/crypto/tlsgen"
 	"github.com/hyperledger/fabric/common/deliver"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/localmsp"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/tracing"
 	"github.com/hyperledger/fabric/common/viperutil"
 	"github.com/hyperledger/fabric/core/aclmgmt"
 	"github.com/hyperledger/fabric/core/admin"
 	"github.com/hyperledger/fabric/core/cclifecycle"
 	"github.com/hyperledger/fabric/core/chaincode"
 	"github.com/hyperledger/fabric/core/chaincode/accesscontrol"
+	"github.com/hyperledger/fabric/core/chaincode/analysis"
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
 	"github.com/hyperledger/fabric/core/chaincode/persistence"
 	"github.com/hyperledger/fabric/core/chaincode/platforms"
@@ -39,22 +47,32 @@ import (
 	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/core/committer/txvalidator"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
+	coreconfig "github.com/hyperledger/fabric/core/config"
+	"github.com/hyperledger/fabric/core/config/reload"
 	"github.com/hyperledger/fabric/core/container"
 	"github.com/hyperledger/fabric/core/container/dockercontroller"
 	"github.com/hyperledger/fabric/core/container/inproccontroller"
 	"github.com/hyperledger/fabric/core/endorser"
+	"github.com/hyperledger/fabric/core/eventsgateway"
+	"github.com/hyperledger/fabric/core/gateway"
 	authHandler "github.com/hyperledger/fabric/core/handlers/auth"
+	"github.com/hyperledger/fabric/core/handlers/auth/filter"
 	endorsement2 "github.com/hyperledger/fabric/core/handlers/endorsement/api"
 	endorsement3 "github.com/hyperledger/fabric/core/handlers/endorsement/api/identities"
 	"github.com/hyperledger/fabric/core/handlers/library"
 	"github.com/hyperledger/fabric/core/handlers/validation/api"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
 	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/core/quota"
 	"github.com/hyperledger/fabric/core/scc"
+	"github.com/hyperledger/fabric/core/scc/crlscc"
 	"github.com/hyperledger/fabric/core/scc/cscc"
 	"github.com/hyperledger/fabric/core/scc/lscc"
 	"github.com/hyperledger/fabric/core/scc/qscc"
+	"github.com/hyperledger/fabric/core/scc/xcc"
 	"github.com/hyperledger/fabric/discovery"
 	"github.com/hyperledger/fabric/discovery/endorsement"
 	discsupport "github.com/hyperledger/fabric/discovery/support"
@@ -62,6 +80,7 @@ import (
 	ccsupport "github.com/hyperledger/fabric/discovery/support/chaincode"
 	"github.com/hyperledger/fabric/discovery/support/config"
 	"github.com/hyperledger/fabric/discovery/support/gossip"
+	"github.com/hyperledger/fabric/gossip/api"
 	gossipcommon "github.com/hyperledger/fabric/gossip/common"
 	"github.com/hyperledger/fabric/gossip/service"
 	"github.com/hyperledger/fabric/msp"
@@ -71,10 +90,12 @@ import (
 	"github.com/hyperledger/fabric/peer/version"
 	cb "github.com/hyperledger/fabric/protos/common"
 	discprotos "github.com/hyperledger/fabric/protos/discovery"
+	gatewayprotos "github.com/hyperledger/fabric/protos/gateway"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/transientstore"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
+	"github.com/spf13/cast"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"google.golang.org/grpc"
@@ -135,6 +156,21 @@ func serve(args []string) error {
 		}
 	}
 
+	// the logging spec of core.yaml is merged into the active one when the
+	// configuration is reloaded, unless it was overridden by the
+	// --logging-level flag, so that the module overrides above and the levels
+	// set for the chaincodes are kept
+	if viper.GetString("logging_level") == "" {
+		_, err := reload.Subscribe("logging.level", func(value interface{}) {
+			if err := flogging.Global.MergeSpec(cast.ToString(value)); err != nil {
+				logger.Errorf("Failed activating reloaded logging spec: %s", err)
+			}
+		})
+		if err != nil {
+			logger.Panicf("Failed subscribing to logging level changes: %s", err)
+		}
+	}
+
 	// Trace RPCs with the golang.org/x/net/trace package. This was moved out of
 	// the deliver service connection factory as it has process wide implications
 	// and was racy with respect to initialization of gRPC clients and servers.
@@ -142,6 +178,18 @@ func serve(args []string) error {
 
 	logger.Infof("Starting %s", version.GetInfo())
 
+	// the metrics are discarded unless metrics.enabled is set
+	if err := metrics.Init(metrics.NewOpts()); err != nil {
+		logger.Panicf("Failed initializing metrics: %s", err)
+	}
+	if viper.GetBool("metrics.enabled") {
+		go func() {
+			if err := metrics.Start(); err != nil {
+				logger.Errorf("Error starting metrics server: %s", err)
+			}
+		}()
+	}
+
 	//startup aclmgmt with default ACL providers (resource based and default 1.0 policies based).
 	//Users can pass in their own ACLProvider to RegisterACLProvider (currently unit tests do this)
 	aclProvider := aclmgmt.NewACLProvider(
@@ -157,12 +205,34 @@ func serve(args []string) error {
 
 	deployedCCInfoProvider := &lscc.DeployedCCInfoProvider{}
 
+	if err := ledgerconfig.CheckBlockfileSyncConfig(); err != nil {
+		return err
+	}
+	// read replicas share the state database of their committer
+	if err := ledgerconfig.CheckReplicationConfig(); err != nil {
+		return err
+	}
+	if ledgerconfig.IsReadReplica() {
+		logger.Infof("Running as a read replica of the committer at %s", ledgerconfig.GetReplicationCommitterAddress())
+	}
+
+	// the handlers are loaded before the ledgers, which process the
+	// transactions of the custom types with the processors of the handlers
+	libConf := library.Config{}
+	if err := viperutil.EnhancedExactUnmarshalKey("peer.handlers", &libConf); err != nil {
+		return errors.WithMessage(err, "could not load YAML config")
+	}
+	reg := library.InitRegistry(libConf)
+
 	//initialize resource management exit
 	ledgermgmt.Initialize(
 		&ledgermgmt.Initializer{
 			CustomTxProcessors:            peer.ConfigTxProcessors,
 			PlatformRegistry:              pr,
 			DeployedChaincodeInfoProvider: deployedCCInfoProvider,
+			Metrics:                       metrics.RootScope.SubScope("ledger"),
+			StateListeners:                []ledger.StateListener{newCRLStateListener()},
+			SnapshotSigner:                localmsp.NewSigner(),
 		})
 
 	// Parameter overrides must be processed before any parameters are
@@ -192,11 +262,21 @@ func serve(args []string) error {
 
 	listenAddr := viper.GetString("peer.listenAddress")
 
+	// the requests are traced if tracing.enabled is set
+	if err := tracing.Init(tracing.NewConfig(peerEndpoint.Id.Name)); err != nil {
+		logger.Fatalf("Failed initializing tracing: %s", err)
+	}
+	defer tracing.Shutdown()
+
 	serverConfig, err := peer.GetServerConfig()
 	if err != nil {
 		logger.Fatalf("Error loading secure config for peer (%s)", err)
 	}
 	serverConfig.Logger = flogging.MustGetLogger("core/comm").With("server", "PeerServer")
+	if tracer := tracing.GlobalTracer(); tracer != nil {
+		serverConfig.UnaryInterceptors = append(serverConfig.UnaryInterceptors, tracing.UnaryServerInterceptor(tracer))
+		serverConfig.StreamInterceptors = append(serverConfig.StreamInterceptors, tracing.StreamServerInterceptor(tracer))
+	}
 	peerServer, err := peer.NewPeerServer(listenAddr, serverConfig)
 	if err != nil {
 		logger.Fatalf("Failed to create peer server (%s)", err)
@@ -223,18 +303,56 @@ func serve(args []string) error {
 		}
 	}
 
-	abServer := peer.NewDeliverEventsServer(mutualTLS, policyCheckerProvider, &peer.DeliverChainManager{})
+	// the per channel quotas on the resources of the peer
+	quotaManager := quota.NewManager(quota.GlobalConfig(), metrics.RootScope.SubScope("quota"))
+	go quotaManager.MonitorStateDBSizes(channelLedgers, nil)
+
+	abServer := peer.NewDeliverEventsServer(mutualTLS, policyCheckerProvider, &peer.DeliverChainManager{}, quotaManager)
 	pb.RegisterDeliverServer(peerServer.Server(), abServer)
+	shutdown := &gracefulShutdown{
+		gracePeriod:    shutdownGracePeriod(),
+		deliverServers: []pb.DeliverServer{abServer},
+		grpcServers:    []*comm.GRPCServer{peerServer},
+		closeLedgers:   ledgermgmt.Close,
+		// the metrics reported and the spans exported periodically
+		flushCaches: []func(){tracing.Shutdown, func() { metrics.Shutdown() }},
+	}
+
+	if viper.GetBool("peer.eventsGateway.enabled") {
+		gatewaySecOpts, err := peer.GetEventsGatewaySecureOptions()
+		if err != nil {
+			logger.Fatalf("Failed to load events gateway TLS settings (%s)", err)
+		}
+		// the deliver requests of the clients must be bound to their TLS
+		// certificate when the gateway verifies it
+		gatewayMutualTLS := gatewaySecOpts.UseTLS && gatewaySecOpts.RequireClientCert
+		gatewayDeliverServer := peer.NewDeliverEventsServer(gatewayMutualTLS, policyCheckerProvider, &peer.DeliverChainManager{}, quotaManager)
+		shutdown.deliverServers = append(shutdown.deliverServers, gatewayDeliverServer)
+		go startEventsGateway(eventsgateway.NewGateway(gatewayDeliverServer), gatewaySecOpts)
+	}
 
 	// Initialize chaincode service
 	chaincodeSupport, ccp, sccp, packageProvider := startChaincodeServer(peerHost, aclProvider, pr)
+	chaincodeSupport.ContainerQuota = quotaManager
 
 	logger.Debugf("Running peer")
 
-	// Start the Admin server
-	startAdminServer(listenAddr, peerServer.Server())
+	// Create the Admin server, which is attached to the peer server unless
+	// it has a separate listener
+	adminServer := newAdminServer(listenAddr)
+	adminGRPCServer := peerServer.Server()
+	if adminServer != nil {
+		adminGRPCServer = adminServer.Server()
+	}
+	certMonitor := startCertificateMonitor()
+	if certMonitor != nil {
+		defer certMonitor.Stop()
+	}
+	registerAdminServer(adminGRPCServer, chaincodeSupport, certMonitor)
+	stopIndexLagReporter := startIndexLagReporter()
+	defer stopIndexLagReporter()
 
-	privDataDist := func(channel string, txID string, privateData *transientstore.TxPvtReadWriteSetWithConfigInfo, blkHt uint64) error {
+	privDataDist := func(channel string, txID string, privateData *transientstore.TxPvtReadWriteSetWithConfigInfo, blkHt uint64) (*pb.PrivateDataDissemination, error) {
 		return service.GetGossipService().DistributePrivateData(channel, txID, privateData, blkHt)
 	}
 
@@ -244,12 +362,6 @@ func serve(args []string) error {
 		logger.Panicf("Failed serializing self identity: %v", err)
 	}
 
-	libConf := library.Config{}
-	if err = viperutil.EnhancedExactUnmarshalKey("peer.handlers", &libConf); err != nil {
-		return errors.WithMessage(err, "could not load YAML config")
-	}
-	reg := library.InitRegistry(libConf)
-
 	authFilters := reg.Lookup(library.Auth).([]authHandler.Filter)
 	endorserSupport := &endorser.SupportImpl{
 		SignerSupport:    signingIdentity,
@@ -272,9 +384,22 @@ func serve(args []string) error {
 	})
 	endorserSupport.PluginEndorser = pluginEndorser
 	serverEndorser := endorser.NewEndorserServer(privDataDist, endorserSupport, pr)
-	auth := authHandler.ChainFilters(serverEndorser, authFilters...)
-	// Register the Endorser server
-	pb.RegisterEndorserServer(peerServer.Server(), auth)
+	serverEndorser.TransientMapMaxSize = viper.GetInt("peer.limits.transientMapMaxSize")
+	serverEndorser.Metrics = metrics.RootScope.SubScope("endorser")
+	serverEndorser.ProposalQuota = quotaManager
+	// the proposals are drained by the outermost filter when the peer shuts down
+	shutdown.endorser = filter.NewDrainFilter()
+	auth := authHandler.ChainFilters(serverEndorser, append([]authHandler.Filter{shutdown.endorser}, authFilters...)...)
+	if adminServer != nil {
+		shutdown.grpcServers = append(shutdown.grpcServers, adminServer)
+		// Chaincode installation is an admin operation, hence it is only
+		// served by the separate listener of the admin service, which serves
+		// no other proposal
+		registerEndorserServers(adminServer.Server(), authHandler.ChainFilters(auth, filter.NewInstallOnlyFilter()))
+		auth = authHandler.ChainFilters(auth, filter.NewInstallRejectionFilter())
+		go adminServer.Start()
+	}
+	registerEndorserServers(peerServer.Server(), auth)
 
 	policyMgr := peer.NewChannelPolicyManagerGetter()
 
@@ -283,7 +408,11 @@ func serve(args []string) error {
 	if err != nil {
 		return err
 	}
-	defer service.GetGossipService().Stop()
+	var stopGossipOnce sync.Once
+	shutdown.stopGossip = func() {
+		stopGossipOnce.Do(service.GetGossipService().Stop)
+	}
+	defer shutdown.stopGossip()
 
 	// initialize system chaincodes
 
@@ -303,6 +432,10 @@ func serve(args []string) error {
 	})
 	lifecycle.AddListener(onUpdate)
 
+	// the readiness of the channels is recorded as they are brought up
+	readiness := newChannelReadiness(metrics.RootScope.SubScope("peer"))
+	peer.AddChannelReadinessListener(readiness.record)
+
 	// this brings up all the channels
 	peer.Initialize(func(cid string) {
 		logger.Debugf("Deploying system CC, for channel <%s>", cid)
@@ -320,6 +453,11 @@ func serve(args []string) error {
 		registerDiscoveryService(peerServer, policyMgr, lifecycle)
 	}
 
+	if viper.GetBool("peer.gateway.enabled") {
+		endorsers := registerGatewayService(peerServer, policyMgr, lifecycle, auth, peerEndpoint.Address, aclProvider)
+		shutdown.closeClients = append(shutdown.closeClients, endorsers.Close)
+	}
+
 	logger.Infof("Starting peer with ID=[%s], network ID=[%s], address=[%s]",
 		peerEndpoint.Id, viper.GetString("peer.networkId"), peerEndpoint.Address)
 
@@ -327,12 +465,20 @@ func serve(args []string) error {
 	// genesis block if needed.
 	serve := make(chan error)
 
-	sigs := make(chan os.Signal, 1)
-	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	halted := handleShutdownSignals(shutdown)
+
+	// SIGHUP reloads the reloadable settings of core.yaml
+	reloadSigs := make(chan os.Signal, 1)
+	signal.Notify(reloadSigs, syscall.SIGHUP)
 	go func() {
-		sig := <-sigs
-		logger.Debugf("sig: %s", sig)
-		serve <- nil
+		for range reloadSigs {
+			changed, err := reload.Reload()
+			if err != nil {
+				logger.Errorf("Failed reloading configuration: %s", err)
+				continue
+			}
+			logger.Infof("Configuration reloaded, changed keys: %v", changed)
+		}
 	}()
 
 	go func() {
@@ -345,22 +491,45 @@ func serve(args []string) error {
 		serve <- grpcErr
 	}()
 
-	// Start profiling http endpoint if enabled
-	if viper.GetBool("peer.profile.enabled") {
-		go func() {
-			profileListenAddress := viper.GetString("peer.profile.listenAddress")
-			logger.Infof("Starting profiling server with listenAddress = %s", profileListenAddress)
-			if profileErr := http.ListenAndServe(profileListenAddress, nil); profileErr != nil {
-				logger.Errorf("Error starting profiler: %s", profileErr)
-			}
-		}()
+	// Start the profiling server if enabled, it can also be enabled by a reload
+	profiler := &profilingService{handlers: map[string]http.Handler{
+		// the health check of the channels started at boot
+		"/channels/readiness": readiness,
+	}}
+	if chaincodeSupport.Restarter != nil {
+		// the events of the restarts of the chaincode containers
+		profiler.handlers["/chaincode/restarts"] = chaincodeSupport.Restarter
+	}
+	profiler.setEnabled(viper.GetBool("peer.profile.enabled"))
+	if _, err := reload.Subscribe("peer.profile.enabled", func(value interface{}) {
+		profiler.setEnabled(cast.ToBool(value))
+	}); err != nil {
+		logger.Panicf("Failed subscribing to profiling changes: %s", err)
 	}
+	defer profiler.stop()
 
 	logger.Infof("Started peer with ID=[%s], network ID=[%s], address=[%s]",
 		peerEndpoint.Id, viper.GetString("peer.networkId"), peerEndpoint.Address)
 
 	// Block until grpc server exits
-	return <-serve
+	if err := <-serve; err != nil {
+		return err
+	}
+	// The grpc server was stopped by the shutdown, which closes the ledgers
+	<-halted
+	return nil
+}
+
+// channelLedgers returns the ledgers of the channels the peer joined
+func channelLedgers() map[string]ledger.PeerLedger {
+	ledgers := map[string]ledger.PeerLedger{}
+	for _, info := range peer.GetChannelsInfo() {
+		if l := peer.GetLedger(info.ChannelId); l != nil {
+			// the sizes are reported by the ledgers wrapped by ledgermgmt
+			ledgers[info.ChannelId] = ledgermgmt.Unwrap(l)
+		}
+	}
+	return ledgers
 }
 
 func localPolicy(policyObject proto.Message) policies.Policy {
@@ -396,6 +565,132 @@ func registerDiscoveryService(peerServer *comm.GRPCServer, polMgr policies.Chann
 	discprotos.RegisterDiscoveryServer(peerServer.Server(), svc)
 }
 
+// registerGatewayService registers the gateway service and returns the registry
+// of the endorsers it connects to
+func registerGatewayService(peerServer *comm.GRPCServer, polMgr policies.ChannelPolicyManagerGetter, lc *cc.Lifecycle, localEndorser pb.EndorserServer, localEndpoint string, aclProvider aclmgmt.ACLProvider) *gateway.EndorserRegistry {
+	channelVerifier := discacl.NewChannelVerifier(policies.ChannelApplicationWriters, polMgr)
+	acl := discacl.NewDiscoverySupport(channelVerifier, nil, discacl.ChannelConfigGetterFunc(peer.GetStableChannelConfig))
+	gSup := gossip.NewDiscoverySupport(service.GetGossipService())
+	ccSup := ccsupport.NewDiscoverySupport(lc)
+	ea := endorsement.NewEndorsementAnalyzer(gSup, ccSup, acl, lc)
+
+	tlsEnabled := viper.GetBool("peer.tls.enabled")
+	endorsers := gateway.NewEndorserRegistry(localEndpoint, localEndorser, func(endpoint string) (*grpc.ClientConn, error) {
+		return comm.NewClientConnectionWithAddress(endpoint, true, tlsEnabled, comm.GetCredentialSupport().GetPeerCredentials(), nil)
+	})
+	broadcaster := &gateway.OrdererBroadcaster{
+		OrdererAddresses: func(channelID string) []string {
+			res := peer.GetStableChannelConfig(channelID)
+			if res == nil {
+				return nil
+			}
+			return res.ChannelConfig().OrdererAddresses()
+		},
+		Dial: func(channelID string, endpoint string) (*grpc.ClientConn, error) {
+			creds, err := comm.GetCredentialSupport().GetDeliverServiceCredentials(channelID)
+			if tlsEnabled && err != nil {
+				return nil, err
+			}
+			return comm.NewClientConnectionWithAddress(endpoint, true, tlsEnabled, creds, nil)
+		},
+	}
+	commitFinder := &gateway.LedgerCommitFinder{GetLedger: peer.GetLedger}
+	// the read sets are checked by the ledgers wrapped by ledgermgmt
+	conflictChecker := &gateway.LedgerConflictChecker{GetLedger: func(channelID string) ledger.PeerLedger {
+		return ledgermgmt.Unwrap(peer.GetLedger(channelID))
+	}}
+
+	svc := gateway.NewServer(gateway.Config{
+		EndorsementTimeout: gatewayTimeout("peer.gateway.endorsementTimeout", gateway.DefaultEndorsementTimeout),
+		BroadcastTimeout:   gatewayTimeout("peer.gateway.broadcastTimeout", gateway.DefaultBroadcastTimeout),
+		CommitTimeout:      gatewayTimeout("peer.gateway.commitTimeout", gateway.DefaultCommitTimeout),
+	}, ea, endorsers, broadcaster, commitFinder, conflictChecker, aclProvider)
+	logger.Info("Gateway service activated")
+	gatewayprotos.RegisterGatewayServer(peerServer.Server(), svc)
+	return endorsers
+}
+
+// startCertificateMonitor starts watching the expiration of the certificates
+// of the local MSP, of the TLS certificates and of the certificates of the
+// MSPs of the channels of the peer, unless peer.certificateExpiration.enabled
+// is unset
+func startCertificateMonitor() *certmonitor.Monitor {
+	if !viper.GetBool("peer.certificateExpiration.enabled") {
+		return nil
+	}
+	options := certmonitor.Options{
+		Interval:   viper.GetDuration("peer.certificateExpiration.checkInterval"),
+		WarnBefore: viper.GetDuration("peer.certificateExpiration.warnBefore"),
+	}
+	if options.Interval <= 0 {
+		options.Interval = time.Hour
+	}
+	if options.WarnBefore <= 0 {
+		options.WarnBefore = 30 * 24 * time.Hour
+	}
+
+	mspID := viper.GetString("peer.localMspId")
+	mspType := viper.GetString("peer.localMspType")
+	if mspType == "" {
+		mspType = msp.ProviderTypeToString(msp.FABRIC)
+	}
+	sources := []certmonitor.Source{
+		certmonitor.LocalMSP(coreconfig.GetPath("peer.mspConfigPath"), mspID, mspType),
+		certmonitor.Channels(
+			func() []string {
+				var channelIDs []string
+				for _, info := range peer.GetChannelsInfo() {
+					channelIDs = append(channelIDs, info.ChannelId)
+				}
+				return channelIDs
+			},
+			func(channelID string) *cb.Config {
+				if res := peer.GetChannelConfig(channelID); res != nil {
+					return res.ConfigtxValidator().ConfigProto()
+				}
+				return nil
+			},
+		),
+	}
+	if viper.GetBool("peer.tls.enabled") {
+		sources = append(sources, certmonitor.PEMFiles(mspID, "tls_server", coreconfig.GetPath("peer.tls.cert.file")))
+		if viper.GetString("peer.tls.clientCert.file") != "" {
+			sources = append(sources, certmonitor.PEMFiles(mspID, "tls_client", coreconfig.GetPath("peer.tls.clientCert.file")))
+		}
+	}
+
+	logger.Infof("Monitoring certificate expiration every %s, warning %s before", options.Interval, options.WarnBefore)
+	monitor := certmonitor.NewMonitor(options, metrics.RootScope.SubScope("certificates"), sources...)
+	monitor.Start()
+	return monitor
+}
+
+// gatewayTimeout returns the gateway timeout set at the given key, or the
+// given default if it isn't set to a positive duration
+func gatewayTimeout(key string, defaultTimeout time.Duration) time.Duration {
+	timeout := viper.GetDuration(key)
+	if timeout <= 0 {
+		logger.Warningf("`%s` not set; defaulting to %s", key, defaultTimeout)
+		return defaultTimeout
+	}
+	return timeout
+}
+
+// newCRLStateListener returns the listener which applies the CRLs published
+// through crlscc to the MSPs of the channels as the blocks are committed.
+// Since the identities of the peers may have been revoked, gossip validates
+// them again.
+func newCRLStateListener() ledger.StateListener {
+	return crlscc.NewStateListener(
+		peer.GetChannelConfig,
+		func(channelID string) {
+			service.GetGossipService().SuspectPeers(func(identity api.PeerIdentityType) bool {
+				return true
+			})
+		},
+	)
+}
+
 //create a CC listener using peer.chaincodeListenAddress (and if that's not set use peer.peerAddress)
 func createChaincodeServer(ca tlsgen.CA, peerHostname string) (srv *comm.GRPCServer, ccEndpoint string, err error) {
 	// before potentially setting chaincodeListenAddress, compute chaincode endpoint at first
@@ -403,7 +698,7 @@ func createChaincodeServer(ca tlsgen.CA, peerHostname string) (srv *comm.GRPCSer
 	if err != nil {
 		if chaincode.IsDevMode() {
 			// if any error for dev mode, we use 0.0.0.0:7052
-			ccEndpoint = fmt.Sprintf("%s:%d", "0.0.0.0", defaultChaincodePort)
+			ccEndpoint = net.JoinHostPort("0.0.0.0", strconv.Itoa(defaultChaincodePort))
 			logger.Warningf("use %s as chaincode endpoint because of error in computeChaincodeEndpoint: %s", ccEndpoint, err)
 		} else {
 			// for non-dev mode, we have to return error
@@ -419,7 +714,7 @@ func createChaincodeServer(ca tlsgen.CA, peerHostname string) (srv *comm.GRPCSer
 
 	cclistenAddress := viper.GetString(chaincodeListenAddrKey)
 	if cclistenAddress == "" {
-		cclistenAddress = fmt.Sprintf("%s:%d", peerHostname, defaultChaincodePort)
+		cclistenAddress = net.JoinHostPort(peerHostname, strconv.Itoa(defaultChaincodePort))
 		logger.Warningf("%s is not set, using %s", chaincodeListenAddrKey, cclistenAddress)
 		viper.Set(chaincodeListenAddrKey, cclistenAddress)
 	}
@@ -499,7 +794,7 @@ func computeChaincodeEndpoint(peerHostname string) (ccEndpoint string, err error
 			}
 
 			// use peerAddress:defaultChaincodePort
-			ccEndpoint = fmt.Sprintf("%s:%d", peerHostname, defaultChaincodePort)
+			ccEndpoint = net.JoinHostPort(peerHostname, strconv.Itoa(defaultChaincodePort))
 
 		} else {
 			// Case B: chaincodeListenAddrKey is set
@@ -520,7 +815,7 @@ func computeChaincodeEndpoint(peerHostname string) (ccEndpoint string, err error
 					logger.Error("ChaincodeAddress is nil while both chaincodeListenAddressIP and peerIP are 0.0.0.0")
 					return "", errors.New("invalid endpoint for chaincode to connect")
 				}
-				ccEndpoint = fmt.Sprintf("%s:%s", peerHostname, port)
+				ccEndpoint = net.JoinHostPort(peerHostname, port)
 			}
 
 		}
@@ -556,6 +851,11 @@ func registerChaincodeSupport(grpcServer *comm.GRPCServer, ccEndpoint string, ca
 
 	sccp := scc.NewProvider(peer.Default, peer.DefaultSupport, ipRegistry)
 	lsccInst := lscc.New(sccp, aclProvider, pr)
+	analyzer, err := analysis.NewAnalyzer(analysis.GlobalConfig())
+	if err != nil {
+		logger.Panicf("Failed creating the chaincode analyzer: %s", err)
+	}
+	lsccInst.Analyzer = analyzer
 	lifecycleSCC := &lifecycle.SCC{}
 
 	chaincodeSupport := chaincode.NewChaincodeSupport(
@@ -577,6 +877,7 @@ func registerChaincodeSupport(grpcServer *comm.GRPCServer, ccEndpoint string, ca
 		sccp,
 		pr,
 		peer.DefaultSupport,
+		metrics.RootScope.SubScope("chaincode"),
 	)
 	ipRegistry.ChaincodeSupport = chaincodeSupport
 	ccp := chaincode.NewProvider(chaincodeSupport)
@@ -588,10 +889,12 @@ func registerChaincodeSupport(grpcServer *comm.GRPCServer, ccEndpoint string, ca
 
 	csccInst := cscc.New(ccp, sccp, aclProvider)
 	qsccInst := qscc.New(aclProvider)
+	xccInst := xcc.New(sccp)
+	crlsccInst := crlscc.New(sccp)
 
 	//Now that chaincode is initialized, register all system chaincodes.
 	sccs := scc.CreatePluginSysCCs(sccp)
-	for _, cc := range append([]scc.SelfDescribingSysCC{lsccInst, csccInst, qsccInst, lifecycleSCC}, sccs...) {
+	for _, cc := range append([]scc.SelfDescribingSysCC{lsccInst, csccInst, qsccInst, xccInst, crlsccInst, lifecycleSCC}, sccs...) {
 		sccp.RegisterSysCC(cc)
 	}
 	pb.RegisterChaincodeSupportServer(grpcServer.Server(), ccSrv)
@@ -637,6 +940,54 @@ func startChaincodeServer(peerHost string, aclProvider aclmgmt.ACLProvider, pr *
 	return chaincodeSupport, ccp, sccp, packageProvider
 }
 
+// startEventsGateway serves the given events gateway on peer.eventsGateway.listenAddress
+func startEventsGateway(gateway *eventsgateway.Gateway, secOpts *comm.SecureOptions) {
+	listenAddress := viper.GetString("peer.eventsGateway.listenAddress")
+	logger.Infof("Starting events gateway with listenAddress = %s", listenAddress)
+	server := &http.Server{
+		Addr:    listenAddress,
+		Handler: gateway,
+		// WebSockets are upgraded from HTTP/1.1 connections only
+		TLSNextProto: map[string]func(*http.Server, *tls.Conn, http.Handler){},
+	}
+	var err error
+	if secOpts.UseTLS {
+		server.TLSConfig, err = eventsGatewayTLSConfig(secOpts)
+		if err == nil {
+			err = server.ListenAndServeTLS("", "")
+		}
+	} else {
+		err = server.ListenAndServe()
+	}
+	logger.Errorf("Events gateway exited with error: %s", err)
+}
+
+// eventsGatewayTLSConfig returns the TLS configuration of the events gateway
+// listener, which verifies the certificates of the clients against the client
+// root CAs if client authentication is required
+func eventsGatewayTLSConfig(secOpts *comm.SecureOptions) (*tls.Config, error) {
+	cert, err := tls.X509KeyPair(secOpts.Certificate, secOpts.Key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed loading events gateway TLS key pair")
+	}
+	tlsConfig := &tls.Config{
+		Certificates:           []tls.Certificate{cert},
+		CipherSuites:           comm.DefaultTLSCipherSuites,
+		SessionTicketsDisabled: true,
+		ClientAuth:             tls.RequestClientCert,
+	}
+	if secOpts.RequireClientCert {
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		tlsConfig.ClientCAs = x509.NewCertPool()
+		for _, clientRootCA := range secOpts.ClientRootCAs {
+			if !tlsConfig.ClientCAs.AppendCertsFromPEM(clientRootCA) {
+				return nil, errors.New("failed adding events gateway client root CA")
+			}
+		}
+	}
+	return tlsConfig, nil
+}
+
 func adminHasSeparateListener(peerListenAddr string, adminListenAddress string) bool {
 	// By default, admin listens on the same port as the peer data service
 	if adminListenAddress == "" {
@@ -656,30 +1007,45 @@ func adminHasSeparateListener(peerListenAddr string, adminListenAddress string)
 	return adminPort != peerPort
 }
 
-func startAdminServer(peerListenAddr string, peerServer *grpc.Server) {
+// newAdminServer creates the gRPC server of the admin service if it has a
+// separate listener, or returns nil otherwise
+func newAdminServer(peerListenAddr string) *comm.GRPCServer {
 	adminListenAddress := viper.GetString("peer.adminService.listenAddress")
-	separateLsnrForAdmin := adminHasSeparateListener(peerListenAddr, adminListenAddress)
+	if !adminHasSeparateListener(peerListenAddr, adminListenAddress) {
+		return nil
+	}
+	logger.Info("Creating gRPC server for admin service on", adminListenAddress)
+	serverConfig, err := peer.GetAdminServerConfig()
+	if err != nil {
+		logger.Fatalf("Error loading secure config for admin service (%s)", err)
+	}
+	serverConfig.Logger = flogging.MustGetLogger("core/comm").With("server", "AdminServer")
+	adminServer, err := peer.NewPeerServer(adminListenAddress, serverConfig)
+	if err != nil {
+		logger.Fatalf("Failed to create admin server (%s)", err)
+	}
+	return adminServer
+}
+
+func registerAdminServer(gRPCService *grpc.Server, chaincodeStatus admin.ChaincodeStatusProvider, certMonitor *certmonitor.Monitor) {
 	mspID := viper.GetString("peer.localMspId")
 	adminPolicy := localPolicy(cauthdsl.SignedByAnyAdmin([]string{mspID}))
-	gRPCService := peerServer
-	if separateLsnrForAdmin {
-		logger.Info("Creating gRPC server for admin service on", adminListenAddress)
-		serverConfig, err := peer.GetServerConfig()
-		serverConfig.Logger = flogging.MustGetLogger("core/comm").With("server", "AdminServer")
-		if err != nil {
-			logger.Fatalf("Error loading secure config for admin service (%s)", err)
-		}
-		adminServer, err := peer.NewPeerServer(adminListenAddress, serverConfig)
-		if err != nil {
-			logger.Fatalf("Failed to create admin server (%s)", err)
-		}
-		gRPCService = adminServer.Server()
-		defer func() {
-			go adminServer.Start()
-		}()
+	adminServer := admin.NewAdminServer(adminPolicy)
+	adminServer.ChaincodeStatusProvider = chaincodeStatus
+	if certMonitor != nil {
+		adminServer.ExpiringCertificatesProvider = certMonitor
 	}
+	pb.RegisterAdminServer(gRPCService, adminServer)
+}
 
-	pb.RegisterAdminServer(gRPCService, admin.NewAdminServer(adminPolicy))
+// registerEndorserServers registers the Endorser server, and the
+// ChunkedEndorser server which goes through the same filters
+func registerEndorserServers(gRPCService *grpc.Server, auth pb.EndorserServer) {
+	pb.RegisterEndorserServer(gRPCService, auth)
+	pb.RegisterChunkedEndorserServer(gRPCService, &endorser.ChunkedEndorser{
+		Endorser:        auth,
+		MaxProposalSize: viper.GetInt("peer.limits.chunkedProposalMaxSize"),
+	})
 }
 
 // secureDialOpts is the callback function for secure dial options for gossip service
@@ -11,6 +11,7 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/fabric/common/viperutil"
 	"github.com/hyperledger/fabric/core/handlers/library"
@@ -168,6 +169,23 @@ func TestComputeChaincodeEndpoint(t *testing.T) {
 
 	/*** Scenario 4: set up both chaincodeAddress and chaincodeListenAddress ***/
 	// This scenario will be the same to scenarios 3: set up chaincodeAddress only.
+
+	/*** Scenario 5: IPv6 addresses are enclosed in brackets ***/
+	// Scenario 5.1: peer address is an IPv6 address
+	viper.Set(chaincodeListenAddrKey, nil)
+	viper.Set(chaincodeAddrKey, nil)
+	ccEndpoint, err = computeChaincodeEndpoint("2001:db8::1")
+	assert.NoError(t, err)
+	assert.Equal(t, "[2001:db8::1]:7052", ccEndpoint)
+	// Scenario 5.2: chaincodeListenAddress is "::"
+	viper.Set(chaincodeListenAddrKey, "[::]:"+chaincodeListenPort)
+	ccEndpoint, err = computeChaincodeEndpoint("2001:db8::1")
+	assert.NoError(t, err)
+	assert.Equal(t, "[2001:db8::1]:"+chaincodeListenPort, ccEndpoint)
+	ccEndpoint, err = computeChaincodeEndpoint("::")
+	assert.Error(t, err)
+	assert.Equal(t, "", ccEndpoint)
+	viper.Set(chaincodeListenAddrKey, nil)
 }
 
 func grpcProbe(addr string) bool {
@@ -178,3 +196,15 @@ func grpcProbe(addr string) bool {
 	}
 	return false
 }
+
+func TestGatewayTimeout(t *testing.T) {
+	defer viper.Reset()
+
+	assert.Equal(t, 30*time.Second, gatewayTimeout("peer.gateway.endorsementTimeout", 30*time.Second))
+	viper.Set("peer.gateway.endorsementTimeout", "0s")
+	assert.Equal(t, 30*time.Second, gatewayTimeout("peer.gateway.endorsementTimeout", 30*time.Second))
+	viper.Set("peer.gateway.endorsementTimeout", "-1s")
+	assert.Equal(t, 30*time.Second, gatewayTimeout("peer.gateway.endorsementTimeout", 30*time.Second))
+	viper.Set("peer.gateway.endorsementTimeout", "10s")
+	assert.Equal(t, 10*time.Second, gatewayTimeout("peer.gateway.endorsementTimeout", 30*time.Second))
+}
@@ -42,12 +42,12 @@ func status() (err error) {
 	adminClient, err := common.GetAdminClient()
 	if err != nil {
 		logger.Warningf("%s", err)
-		fmt.Println(&pb.ServerStatus{Status: pb.ServerStatus_UNKNOWN})
+		printStatus(&pb.ServerStatus{Status: pb.ServerStatus_UNKNOWN})
 		return err
 	}
 	signer, err := common.GetDefaultSignerFnc()
 	if err != nil {
-		fmt.Println(&pb.ServerStatus{Status: pb.ServerStatus_UNKNOWN})
+		printStatus(&pb.ServerStatus{Status: pb.ServerStatus_UNKNOWN})
 		return errors.Errorf("failed obtaining default signer: %v", err)
 	}
 
@@ -64,9 +64,22 @@ func status() (err error) {
 	if err != nil {
 		logger.Infof("Error trying to get status from local peer: %s", err)
 		err = fmt.Errorf("Error trying to connect to local peer: %s", err)
-		fmt.Println(&pb.ServerStatus{Status: pb.ServerStatus_UNKNOWN})
+		printStatus(&pb.ServerStatus{Status: pb.ServerStatus_UNKNOWN})
 		return err
 	}
+	return printStatus(status)
+}
+
+// statusResult is the machine readable output of the status command
+type statusResult struct {
+	Status string `json:"status" yaml:"status"`
+}
+
+// printStatus prints the status of the node in the requested output format
+func printStatus(status *pb.ServerStatus) error {
+	if common.StructuredOutput() {
+		return common.PrintOutput(&statusResult{Status: status.Status.String()})
+	}
 	fmt.Println(status)
 	return nil
 }
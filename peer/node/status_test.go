@@ -17,7 +17,9 @@ limitations under the License.
 package node
 
 import (
+	"bytes"
 	"context"
+	"os"
 	"testing"
 	"time"
 
@@ -77,10 +79,12 @@ func TestStatus(t *testing.T) {
 		return signer, nil
 	}
 	var tests = []struct {
-		name          string
-		peerAddress   string
-		listenAddress string
-		shouldSucceed bool
+		name           string
+		peerAddress    string
+		listenAddress  string
+		outputFormat   string
+		expectedOutput string
+		shouldSucceed  bool
 	}{
 		{
 			name:          "status function to success",
@@ -88,6 +92,29 @@ func TestStatus(t *testing.T) {
 			listenAddress: "localhost:7071",
 			shouldSucceed: true,
 		},
+		{
+			name:           "status function to success with json output",
+			peerAddress:    "localhost:7073",
+			listenAddress:  "localhost:7073",
+			outputFormat:   common2.OutputJSON,
+			expectedOutput: "{\n  \"status\": \"STARTED\"\n}\n",
+			shouldSucceed:  true,
+		},
+		{
+			name:           "admin client error with yaml output",
+			peerAddress:    "",
+			listenAddress:  "localhost:7075",
+			outputFormat:   common2.OutputYAML,
+			expectedOutput: "status: UNKNOWN\n",
+			shouldSucceed:  false,
+		},
+		{
+			name:          "unsupported output format",
+			peerAddress:   "localhost:7076",
+			listenAddress: "localhost:7076",
+			outputFormat:  "xml",
+			shouldSucceed: false,
+		},
 		{
 			name:          "admin client error",
 			peerAddress:   "",
@@ -101,6 +128,10 @@ func TestStatus(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			t.Logf("Running test: %s", test.name)
 			viper.Set("peer.address", test.peerAddress)
+			viper.Set(common2.OutputFormatKey, test.outputFormat)
+			buf := &bytes.Buffer{}
+			common2.OutputWriter = buf
+			defer func() { common2.OutputWriter = os.Stdout }()
 			viper.Set("peer.client.connTimeout", 10*time.Millisecond)
 			peerServer, err := peer.NewPeerServer(test.listenAddress, comm.ServerConfig{})
 			if err != nil {
@@ -114,6 +145,9 @@ func TestStatus(t *testing.T) {
 				} else {
 					assert.Error(t, status())
 				}
+				if test.outputFormat != "" {
+					assert.Equal(t, test.expectedOutput, buf.String())
+				}
 			}
 		})
 	}
@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"math"
 
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/factory"
 	"github.com/hyperledger/fabric/common/util"
 )
 
@@ -83,6 +85,15 @@ func (b *BlockData) Bytes() []byte {
 }
 
 // Hash returns the hash of the marshaled representation of the block data.
+// The entries are hashed in turn rather than concatenated, so that hashing a
+// large block doesn't copy it
 func (b *BlockData) Hash() []byte {
-	return util.ComputeSHA256(b.Bytes())
+	h, err := factory.GetDefault().GetHash(&bccsp.SHA256Opts{})
+	if err != nil {
+		panic(fmt.Errorf("Failed getting SHA256 hash function: %s", err))
+	}
+	for _, d := range b.Data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
 }
@@ -77,3 +77,16 @@ func TestBadBlockHeaderBytes(t *testing.T) {
 
 	_ = badBlockHeader.Bytes() // Should panic
 }
+
+func BenchmarkBlockDataHash(b *testing.B) {
+	// a block of 50MB
+	data := &BlockData{}
+	for i := 0; i < 500; i++ {
+		data.Data = append(data.Data, make([]byte, 100*1024))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data.Hash()
+	}
+}
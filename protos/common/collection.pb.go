@@ -32,7 +32,7 @@ func (m *CollectionConfigPackage) Reset()         { *m = CollectionConfigPackage
 func (m *CollectionConfigPackage) String() string { return proto.CompactTextString(m) }
 func (*CollectionConfigPackage) ProtoMessage()    {}
 func (*CollectionConfigPackage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_collection_b741380d1d025da1, []int{0}
+	return fileDescriptor_collection_20c491d2a9f0eb0d, []int{0}
 }
 func (m *CollectionConfigPackage) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CollectionConfigPackage.Unmarshal(m, b)
@@ -75,7 +75,7 @@ func (m *CollectionConfig) Reset()         { *m = CollectionConfig{} }
 func (m *CollectionConfig) String() string { return proto.CompactTextString(m) }
 func (*CollectionConfig) ProtoMessage()    {}
 func (*CollectionConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_collection_b741380d1d025da1, []int{1}
+	return fileDescriptor_collection_20c491d2a9f0eb0d, []int{1}
 }
 func (m *CollectionConfig) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CollectionConfig.Unmarshal(m, b)
@@ -194,7 +194,23 @@ type StaticCollectionConfig struct {
 	// The number of blocks after which the collection data expires.
 	// For instance if the value is set to 10, a key last modified by block number 100
 	// will be purged at block number 111. A zero value is treated same as MaxUint64
-	BlockToLive          uint64   `protobuf:"varint,5,opt,name=block_to_live,json=blockToLive" json:"block_to_live,omitempty"`
+	BlockToLive uint64 `protobuf:"varint,5,opt,name=block_to_live,json=blockToLive" json:"block_to_live,omitempty"`
+	// The state database storing the private data of the collection on the
+	// peers maintaining both a goleveldb and a CouchDB state database, either
+	// "goleveldb" or "CouchDB". An empty value, or a database the peer does not
+	// maintain, stores the private data along with the public state. It cannot
+	// be modified once the collection is defined.
+	StateDatabase string `protobuf:"bytes,6,opt,name=state_database,json=stateDatabase" json:"state_database,omitempty"`
+	// Whether the private data of the collection is only shared with the
+	// endorsers and the peers of the collection through their transient store
+	// until the transaction commits: it is never persisted in the private data
+	// store nor in the state database of the peers, only its hash is committed.
+	Ephemeral bool `protobuf:"varint,7,opt,name=ephemeral" json:"ephemeral,omitempty"`
+	// The scheme committing on-chain to the private values of the collection,
+	// either empty to hash them, or "Pedersen" for Pedersen commitments, about
+	// which later transactions may prove properties in zero knowledge. It
+	// cannot be modified once the collection is defined.
+	ValueCommitment      string   `protobuf:"bytes,8,opt,name=value_commitment,json=valueCommitment" json:"value_commitment,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -204,7 +220,7 @@ func (m *StaticCollectionConfig) Reset()         { *m = StaticCollectionConfig{}
 func (m *StaticCollectionConfig) String() string { return proto.CompactTextString(m) }
 func (*StaticCollectionConfig) ProtoMessage()    {}
 func (*StaticCollectionConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_collection_b741380d1d025da1, []int{2}
+	return fileDescriptor_collection_20c491d2a9f0eb0d, []int{2}
 }
 func (m *StaticCollectionConfig) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StaticCollectionConfig.Unmarshal(m, b)
@@ -259,6 +275,27 @@ func (m *StaticCollectionConfig) GetBlockToLive() uint64 {
 	return 0
 }
 
+func (m *StaticCollectionConfig) GetStateDatabase() string {
+	if m != nil {
+		return m.StateDatabase
+	}
+	return ""
+}
+
+func (m *StaticCollectionConfig) GetEphemeral() bool {
+	if m != nil {
+		return m.Ephemeral
+	}
+	return false
+}
+
+func (m *StaticCollectionConfig) GetValueCommitment() string {
+	if m != nil {
+		return m.ValueCommitment
+	}
+	return ""
+}
+
 // Collection policy configuration. Initially, the configuration can only
 // contain a SignaturePolicy. In the future, the SignaturePolicy may be a
 // more general Policy. Instead of containing the actual policy, the
@@ -276,7 +313,7 @@ func (m *CollectionPolicyConfig) Reset()         { *m = CollectionPolicyConfig{}
 func (m *CollectionPolicyConfig) String() string { return proto.CompactTextString(m) }
 func (*CollectionPolicyConfig) ProtoMessage()    {}
 func (*CollectionPolicyConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_collection_b741380d1d025da1, []int{3}
+	return fileDescriptor_collection_20c491d2a9f0eb0d, []int{3}
 }
 func (m *CollectionPolicyConfig) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CollectionPolicyConfig.Unmarshal(m, b)
@@ -391,7 +428,7 @@ func (m *CollectionCriteria) Reset()         { *m = CollectionCriteria{} }
 func (m *CollectionCriteria) String() string { return proto.CompactTextString(m) }
 func (*CollectionCriteria) ProtoMessage()    {}
 func (*CollectionCriteria) Descriptor() ([]byte, []int) {
-	return fileDescriptor_collection_b741380d1d025da1, []int{4}
+	return fileDescriptor_collection_20c491d2a9f0eb0d, []int{4}
 }
 func (m *CollectionCriteria) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CollectionCriteria.Unmarshal(m, b)
@@ -447,37 +484,42 @@ func init() {
 	proto.RegisterType((*CollectionCriteria)(nil), "common.CollectionCriteria")
 }
 
-func init() { proto.RegisterFile("common/collection.proto", fileDescriptor_collection_b741380d1d025da1) }
-
-var fileDescriptor_collection_b741380d1d025da1 = []byte{
-	// 453 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0x41, 0x6b, 0xdb, 0x40,
-	0x10, 0x85, 0xa3, 0xc6, 0x76, 0xd0, 0x98, 0x52, 0x77, 0x43, 0x1d, 0x51, 0x4a, 0x6a, 0x44, 0x0f,
-	0x86, 0x16, 0xa9, 0xa4, 0xff, 0x20, 0xa6, 0x90, 0x52, 0x43, 0x8d, 0xd2, 0x53, 0x2e, 0x62, 0xb5,
-	0x9a, 0xc8, 0x4b, 0x24, 0xad, 0xb2, 0xbb, 0x32, 0xf6, 0xb1, 0xff, 0xbb, 0x87, 0xe0, 0x5d, 0xc9,
-	0x52, 0x8c, 0x6f, 0x9e, 0x79, 0xdf, 0x3c, 0xcf, 0x3c, 0x2d, 0x5c, 0x31, 0x51, 0x14, 0xa2, 0x0c,
-	0x99, 0xc8, 0x73, 0x64, 0x9a, 0x8b, 0x32, 0xa8, 0xa4, 0xd0, 0x82, 0x8c, 0xac, 0xf0, 0xf1, 0x43,
-	0x03, 0x54, 0x22, 0xe7, 0x8c, 0xa3, 0xb2, 0xb2, 0xff, 0x1b, 0xae, 0x16, 0x87, 0x91, 0x85, 0x28,
-	0x1f, 0x79, 0xb6, 0xa2, 0xec, 0x89, 0x66, 0x48, 0xbe, 0xc3, 0x88, 0x99, 0x86, 0xe7, 0xcc, 0xce,
-	0xe7, 0xe3, 0x1b, 0x2f, 0xb0, 0x16, 0xc1, 0xf1, 0x40, 0xd4, 0x70, 0xfe, 0x0e, 0x26, 0xc7, 0x1a,
-	0x79, 0x00, 0x4f, 0x69, 0xaa, 0x39, 0x8b, 0xbb, 0xd5, 0xe2, 0x83, 0xaf, 0x33, 0x1f, 0xdf, 0x5c,
-	0xb7, 0xbe, 0xf7, 0x86, 0x3b, 0x76, 0xb8, 0x3b, 0x8b, 0xa6, 0xea, 0xa4, 0x72, 0xeb, 0xc2, 0x45,
-	0x45, 0x77, 0xb9, 0xa0, 0xa9, 0xff, 0xdf, 0x81, 0xe9, 0xe9, 0x79, 0x42, 0x60, 0x50, 0xd2, 0x02,
-	0xcd, 0xbf, 0xb9, 0x91, 0xf9, 0x4d, 0x96, 0x40, 0x0a, 0x2c, 0x12, 0x94, 0xb1, 0x90, 0x99, 0x8a,
-	0x4d, 0x28, 0x3b, 0xef, 0xcd, 0xeb, 0x7d, 0x3a, 0xa7, 0x95, 0xd1, 0x9b, 0x6b, 0x27, 0x76, 0xf2,
-	0x8f, 0xcc, 0x94, 0xed, 0x93, 0x00, 0x2e, 0x25, 0x3e, 0xd7, 0x5c, 0x62, 0x1a, 0x57, 0x88, 0x32,
-	0x66, 0xa2, 0x2e, 0xb5, 0x77, 0x3e, 0x73, 0xe6, 0xc3, 0xe8, 0x7d, 0x2b, 0xad, 0x10, 0xe5, 0x62,
-	0x2f, 0x90, 0x6f, 0x40, 0x0a, 0xba, 0xe5, 0x45, 0x5d, 0xf4, 0xf1, 0x81, 0xc1, 0x27, 0x8d, 0xd2,
-	0xd1, 0x3e, 0xbc, 0x4d, 0x72, 0xc1, 0x9e, 0x62, 0x2d, 0xe2, 0x9c, 0x6f, 0xd0, 0x1b, 0xce, 0x9c,
-	0xf9, 0x20, 0x1a, 0x9b, 0xe6, 0x5f, 0xb1, 0xe4, 0x1b, 0xf4, 0x9f, 0x61, 0x7a, 0x7a, 0x5b, 0xb2,
-	0x84, 0x89, 0xe2, 0x59, 0x49, 0x75, 0x2d, 0xb1, 0xbd, 0xd3, 0xe6, 0xfe, 0xf9, 0x90, 0x7b, 0xab,
-	0xdb, 0xc1, 0x9f, 0xe5, 0x06, 0x73, 0x51, 0xe1, 0xdd, 0x59, 0xf4, 0x4e, 0xbd, 0x96, 0xfa, 0x89,
-	0xff, 0x73, 0x80, 0xf4, 0xb2, 0x96, 0x5c, 0xa3, 0xe4, 0x94, 0x78, 0x70, 0xc1, 0xd6, 0xb4, 0x2c,
-	0x31, 0x6f, 0x02, 0x6f, 0x4b, 0x72, 0x09, 0x43, 0xbd, 0x8d, 0x79, 0x6a, 0x62, 0x76, 0xa3, 0x81,
-	0xde, 0xfe, 0x4a, 0xc9, 0x35, 0x40, 0xf7, 0x2e, 0x4c, 0x62, 0x6e, 0xd4, 0xeb, 0x90, 0x4f, 0xe0,
-	0xee, 0x3f, 0x98, 0xaa, 0x28, 0x43, 0x93, 0x90, 0x1b, 0x75, 0x8d, 0xdb, 0x7b, 0xf8, 0x22, 0x64,
-	0x16, 0xac, 0x77, 0x15, 0xca, 0x1c, 0xd3, 0x0c, 0x65, 0xf0, 0x48, 0x13, 0xc9, 0x99, 0x7d, 0xdd,
-	0xaa, 0xb9, 0xf0, 0xe1, 0x6b, 0xc6, 0xf5, 0xba, 0x4e, 0xf6, 0x65, 0xd8, 0x83, 0x43, 0x0b, 0x87,
-	0x16, 0x0e, 0x2d, 0x9c, 0x8c, 0x4c, 0xf9, 0xe3, 0x25, 0x00, 0x00, 0xff, 0xff, 0x04, 0x6f, 0x60,
-	0x95, 0x53, 0x03, 0x00, 0x00,
+func init() {
+	proto.RegisterFile("common/collection.proto", fileDescriptor_collection_20c491d2a9f0eb0d)
+}
+
+var fileDescriptor_collection_20c491d2a9f0eb0d = []byte{
+	// 510 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x53, 0xd1, 0x6a, 0xdb, 0x30,
+	0x14, 0xad, 0xdb, 0x34, 0xad, 0x6f, 0xe9, 0x9a, 0xa9, 0x2c, 0x35, 0x63, 0x74, 0x21, 0x6c, 0x90,
+	0xb1, 0xe1, 0x8c, 0xee, 0x0f, 0x9a, 0x0d, 0x3a, 0x16, 0x58, 0x70, 0xf7, 0xd4, 0x17, 0x21, 0xcb,
+	0xb7, 0x8e, 0xa8, 0x6c, 0xb9, 0xb2, 0x1c, 0x92, 0xc7, 0xfd, 0xef, 0x3e, 0x62, 0x58, 0x72, 0xe2,
+	0x34, 0xe4, 0xcd, 0xf7, 0x9c, 0x73, 0x8f, 0x75, 0xef, 0x91, 0xe0, 0x8a, 0xab, 0x2c, 0x53, 0xf9,
+	0x98, 0x2b, 0x29, 0x91, 0x1b, 0xa1, 0xf2, 0xb0, 0xd0, 0xca, 0x28, 0xd2, 0x75, 0xc4, 0xdb, 0x37,
+	0x8d, 0xa0, 0x50, 0x52, 0x70, 0x81, 0xa5, 0xa3, 0x87, 0xbf, 0xe0, 0x6a, 0xb2, 0x69, 0x99, 0xa8,
+	0xfc, 0x51, 0xa4, 0x33, 0xc6, 0x9f, 0x58, 0x8a, 0xe4, 0x2b, 0x74, 0xb9, 0x05, 0x02, 0x6f, 0x70,
+	0x34, 0x3a, 0xbb, 0x09, 0x42, 0x67, 0x11, 0xee, 0x36, 0x44, 0x8d, 0x6e, 0xb8, 0x82, 0xde, 0x2e,
+	0x47, 0x1e, 0x20, 0x28, 0x0d, 0x33, 0x82, 0xd3, 0xf6, 0x68, 0x74, 0xe3, 0xeb, 0x8d, 0xce, 0x6e,
+	0xae, 0xd7, 0xbe, 0xf7, 0x56, 0xb7, 0xeb, 0x70, 0x77, 0x10, 0xf5, 0xcb, 0xbd, 0xcc, 0xad, 0x0f,
+	0x27, 0x05, 0x5b, 0x49, 0xc5, 0x92, 0xe1, 0xbf, 0x43, 0xe8, 0xef, 0xef, 0x27, 0x04, 0x3a, 0x39,
+	0xcb, 0xd0, 0xfe, 0xcd, 0x8f, 0xec, 0x37, 0x99, 0x02, 0xc9, 0x30, 0x8b, 0x51, 0x53, 0xa5, 0xd3,
+	0x92, 0xda, 0xa5, 0xac, 0x82, 0xc3, 0x97, 0xe7, 0x69, 0x9d, 0x66, 0x96, 0x6f, 0xa6, 0xed, 0xb9,
+	0xce, 0xdf, 0x3a, 0x2d, 0x1d, 0x4e, 0x42, 0xb8, 0xd4, 0xf8, 0x5c, 0x09, 0x8d, 0x09, 0x2d, 0x10,
+	0x35, 0xe5, 0xaa, 0xca, 0x4d, 0x70, 0x34, 0xf0, 0x46, 0xc7, 0xd1, 0xeb, 0x35, 0x35, 0x43, 0xd4,
+	0x93, 0x9a, 0x20, 0x5f, 0x80, 0x64, 0x6c, 0x29, 0xb2, 0x2a, 0xdb, 0x96, 0x77, 0xac, 0xbc, 0xd7,
+	0x30, 0xad, 0x7a, 0x08, 0xe7, 0xb1, 0x54, 0xfc, 0x89, 0x1a, 0x45, 0xa5, 0x58, 0x60, 0x70, 0x3c,
+	0xf0, 0x46, 0x9d, 0xe8, 0xcc, 0x82, 0x7f, 0xd4, 0x54, 0x2c, 0x90, 0x7c, 0x84, 0x57, 0xf5, 0x8e,
+	0x90, 0x26, 0xcc, 0xb0, 0x98, 0x95, 0x18, 0x74, 0xed, 0xb4, 0xe7, 0x16, 0xfd, 0xde, 0x80, 0xe4,
+	0x1d, 0xf8, 0x58, 0xcc, 0x31, 0x43, 0xcd, 0x64, 0x70, 0x32, 0xf0, 0x46, 0xa7, 0x51, 0x0b, 0x90,
+	0x4f, 0xd0, 0x5b, 0x30, 0x59, 0x21, 0xad, 0xe7, 0x17, 0x26, 0xc3, 0xdc, 0x04, 0xa7, 0xd6, 0xe6,
+	0xc2, 0xe2, 0x93, 0x0d, 0x3c, 0x7c, 0x86, 0xfe, 0xfe, 0xed, 0x90, 0x29, 0xf4, 0x4a, 0x91, 0xe6,
+	0xcc, 0x54, 0x1a, 0xd7, 0x7b, 0x75, 0x39, 0xbf, 0xdf, 0xe4, 0xbc, 0xe6, 0x5d, 0xe3, 0x8f, 0x7c,
+	0x81, 0x52, 0x15, 0x78, 0x77, 0x10, 0x5d, 0x94, 0x2f, 0xa9, 0xed, 0x84, 0xff, 0x7a, 0x40, 0xb6,
+	0xb2, 0xd5, 0xc2, 0xa0, 0x16, 0x8c, 0x04, 0x70, 0xc2, 0xe7, 0x2c, 0xcf, 0x51, 0x36, 0x01, 0xaf,
+	0x4b, 0x72, 0x09, 0xc7, 0x66, 0x49, 0x45, 0x62, 0x63, 0xf5, 0xa3, 0x8e, 0x59, 0xfe, 0x4c, 0xc8,
+	0x35, 0x40, 0x7b, 0x0f, 0x6d, 0x42, 0x7e, 0xb4, 0x85, 0xd4, 0x1b, 0xaa, 0x2f, 0x48, 0x59, 0x30,
+	0x8e, 0x36, 0x11, 0x3f, 0x6a, 0x81, 0xdb, 0x7b, 0xf8, 0xa0, 0x74, 0x1a, 0xce, 0x57, 0x05, 0x6a,
+	0x89, 0x49, 0x8a, 0x3a, 0x7c, 0x64, 0xb1, 0x16, 0xdc, 0xbd, 0xa6, 0xb2, 0x99, 0xf0, 0xe1, 0x73,
+	0x2a, 0xcc, 0xbc, 0x8a, 0xeb, 0x72, 0xbc, 0x25, 0x1e, 0x3b, 0xf1, 0xd8, 0x89, 0xc7, 0x4e, 0x1c,
+	0x77, 0x6d, 0xf9, 0xed, 0xff, 0x00, 0xf4, 0xd0, 0xa2, 0x70, 0xc3, 0x03, 0x00, 0x00,
 }
@@ -61,7 +61,7 @@ func (x Status) String() string {
 	return proto.EnumName(Status_name, int32(x))
 }
 func (Status) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_common_b374fafc5e1c956e, []int{0}
+	return fileDescriptor_common_43e9c81b2bc570be, []int{0}
 }
 
 type HeaderType int32
@@ -105,7 +105,7 @@ func (x HeaderType) String() string {
 	return proto.EnumName(HeaderType_name, int32(x))
 }
 func (HeaderType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_common_b374fafc5e1c956e, []int{1}
+	return fileDescriptor_common_43e9c81b2bc570be, []int{1}
 }
 
 // This enum enlists indexes of the block metadata array
@@ -116,6 +116,8 @@ const (
 	BlockMetadataIndex_LAST_CONFIG         BlockMetadataIndex = 1
 	BlockMetadataIndex_TRANSACTIONS_FILTER BlockMetadataIndex = 2
 	BlockMetadataIndex_ORDERER             BlockMetadataIndex = 3
+	// e.g. For Kafka, this is where we store the last offset written to the local ledger.
+	BlockMetadataIndex_CHECKPOINT BlockMetadataIndex = 4
 )
 
 var BlockMetadataIndex_name = map[int32]string{
@@ -123,19 +125,21 @@ var BlockMetadataIndex_name = map[int32]string{
 	1: "LAST_CONFIG",
 	2: "TRANSACTIONS_FILTER",
 	3: "ORDERER",
+	4: "CHECKPOINT",
 }
 var BlockMetadataIndex_value = map[string]int32{
 	"SIGNATURES":          0,
 	"LAST_CONFIG":         1,
 	"TRANSACTIONS_FILTER": 2,
 	"ORDERER":             3,
+	"CHECKPOINT":          4,
 }
 
 func (x BlockMetadataIndex) String() string {
 	return proto.EnumName(BlockMetadataIndex_name, int32(x))
 }
 func (BlockMetadataIndex) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_common_b374fafc5e1c956e, []int{2}
+	return fileDescriptor_common_43e9c81b2bc570be, []int{2}
 }
 
 // LastConfig is the encoded value for the Metadata message which is encoded in the LAST_CONFIGURATION block metadata index
@@ -150,7 +154,7 @@ func (m *LastConfig) Reset()         { *m = LastConfig{} }
 func (m *LastConfig) String() string { return proto.CompactTextString(m) }
 func (*LastConfig) ProtoMessage()    {}
 func (*LastConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_b374fafc5e1c956e, []int{0}
+	return fileDescriptor_common_43e9c81b2bc570be, []int{0}
 }
 func (m *LastConfig) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_LastConfig.Unmarshal(m, b)
@@ -177,6 +181,71 @@ func (m *LastConfig) GetIndex() uint64 {
 	return 0
 }
 
+// Checkpoint is the encoded value for the Metadata message which is encoded in the CHECKPOINT block metadata index.
+// Checkpoints link to each other by header hash, which lets a node trust a recent block by verifying the chain of
+// checkpoints leading to it rather than every block since the genesis block
+type Checkpoint struct {
+	LastConfig             uint64   `protobuf:"varint,1,opt,name=last_config,json=lastConfig" json:"last_config,omitempty"`
+	PreviousCheckpoint     uint64   `protobuf:"varint,2,opt,name=previous_checkpoint,json=previousCheckpoint" json:"previous_checkpoint,omitempty"`
+	PreviousCheckpointHash []byte   `protobuf:"bytes,3,opt,name=previous_checkpoint_hash,json=previousCheckpointHash,proto3" json:"previous_checkpoint_hash,omitempty"`
+	StateHash              []byte   `protobuf:"bytes,4,opt,name=state_hash,json=stateHash,proto3" json:"state_hash,omitempty"`
+	XXX_NoUnkeyedLiteral   struct{} `json:"-"`
+	XXX_unrecognized       []byte   `json:"-"`
+	XXX_sizecache          int32    `json:"-"`
+}
+
+func (m *Checkpoint) Reset()         { *m = Checkpoint{} }
+func (m *Checkpoint) String() string { return proto.CompactTextString(m) }
+func (*Checkpoint) ProtoMessage()    {}
+func (*Checkpoint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_common_43e9c81b2bc570be, []int{1}
+}
+func (m *Checkpoint) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Checkpoint.Unmarshal(m, b)
+}
+func (m *Checkpoint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Checkpoint.Marshal(b, m, deterministic)
+}
+func (dst *Checkpoint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Checkpoint.Merge(dst, src)
+}
+func (m *Checkpoint) XXX_Size() int {
+	return xxx_messageInfo_Checkpoint.Size(m)
+}
+func (m *Checkpoint) XXX_DiscardUnknown() {
+	xxx_messageInfo_Checkpoint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Checkpoint proto.InternalMessageInfo
+
+func (m *Checkpoint) GetLastConfig() uint64 {
+	if m != nil {
+		return m.LastConfig
+	}
+	return 0
+}
+
+func (m *Checkpoint) GetPreviousCheckpoint() uint64 {
+	if m != nil {
+		return m.PreviousCheckpoint
+	}
+	return 0
+}
+
+func (m *Checkpoint) GetPreviousCheckpointHash() []byte {
+	if m != nil {
+		return m.PreviousCheckpointHash
+	}
+	return nil
+}
+
+func (m *Checkpoint) GetStateHash() []byte {
+	if m != nil {
+		return m.StateHash
+	}
+	return nil
+}
+
 // Metadata is a common structure to be used to encode block metadata
 type Metadata struct {
 	Value                []byte               `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
@@ -190,7 +259,7 @@ func (m *Metadata) Reset()         { *m = Metadata{} }
 func (m *Metadata) String() string { return proto.CompactTextString(m) }
 func (*Metadata) ProtoMessage()    {}
 func (*Metadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_b374fafc5e1c956e, []int{1}
+	return fileDescriptor_common_43e9c81b2bc570be, []int{2}
 }
 func (m *Metadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Metadata.Unmarshal(m, b)
@@ -236,7 +305,7 @@ func (m *MetadataSignature) Reset()         { *m = MetadataSignature{} }
 func (m *MetadataSignature) String() string { return proto.CompactTextString(m) }
 func (*MetadataSignature) ProtoMessage()    {}
 func (*MetadataSignature) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_b374fafc5e1c956e, []int{2}
+	return fileDescriptor_common_43e9c81b2bc570be, []int{3}
 }
 func (m *MetadataSignature) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MetadataSignature.Unmarshal(m, b)
@@ -282,7 +351,7 @@ func (m *Header) Reset()         { *m = Header{} }
 func (m *Header) String() string { return proto.CompactTextString(m) }
 func (*Header) ProtoMessage()    {}
 func (*Header) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_b374fafc5e1c956e, []int{3}
+	return fileDescriptor_common_43e9c81b2bc570be, []int{4}
 }
 func (m *Header) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Header.Unmarshal(m, b)
@@ -355,7 +424,7 @@ func (m *ChannelHeader) Reset()         { *m = ChannelHeader{} }
 func (m *ChannelHeader) String() string { return proto.CompactTextString(m) }
 func (*ChannelHeader) ProtoMessage()    {}
 func (*ChannelHeader) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_b374fafc5e1c956e, []int{4}
+	return fileDescriptor_common_43e9c81b2bc570be, []int{5}
 }
 func (m *ChannelHeader) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChannelHeader.Unmarshal(m, b)
@@ -445,7 +514,7 @@ func (m *SignatureHeader) Reset()         { *m = SignatureHeader{} }
 func (m *SignatureHeader) String() string { return proto.CompactTextString(m) }
 func (*SignatureHeader) ProtoMessage()    {}
 func (*SignatureHeader) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_b374fafc5e1c956e, []int{5}
+	return fileDescriptor_common_43e9c81b2bc570be, []int{6}
 }
 func (m *SignatureHeader) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SignatureHeader.Unmarshal(m, b)
@@ -494,7 +563,7 @@ func (m *Payload) Reset()         { *m = Payload{} }
 func (m *Payload) String() string { return proto.CompactTextString(m) }
 func (*Payload) ProtoMessage()    {}
 func (*Payload) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_b374fafc5e1c956e, []int{6}
+	return fileDescriptor_common_43e9c81b2bc570be, []int{7}
 }
 func (m *Payload) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Payload.Unmarshal(m, b)
@@ -543,7 +612,7 @@ func (m *Envelope) Reset()         { *m = Envelope{} }
 func (m *Envelope) String() string { return proto.CompactTextString(m) }
 func (*Envelope) ProtoMessage()    {}
 func (*Envelope) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_b374fafc5e1c956e, []int{7}
+	return fileDescriptor_common_43e9c81b2bc570be, []int{8}
 }
 func (m *Envelope) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Envelope.Unmarshal(m, b)
@@ -594,7 +663,7 @@ func (m *Block) Reset()         { *m = Block{} }
 func (m *Block) String() string { return proto.CompactTextString(m) }
 func (*Block) ProtoMessage()    {}
 func (*Block) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_b374fafc5e1c956e, []int{8}
+	return fileDescriptor_common_43e9c81b2bc570be, []int{9}
 }
 func (m *Block) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Block.Unmarshal(m, b)
@@ -651,7 +720,7 @@ func (m *BlockHeader) Reset()         { *m = BlockHeader{} }
 func (m *BlockHeader) String() string { return proto.CompactTextString(m) }
 func (*BlockHeader) ProtoMessage()    {}
 func (*BlockHeader) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_b374fafc5e1c956e, []int{9}
+	return fileDescriptor_common_43e9c81b2bc570be, []int{10}
 }
 func (m *BlockHeader) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BlockHeader.Unmarshal(m, b)
@@ -703,7 +772,7 @@ func (m *BlockData) Reset()         { *m = BlockData{} }
 func (m *BlockData) String() string { return proto.CompactTextString(m) }
 func (*BlockData) ProtoMessage()    {}
 func (*BlockData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_b374fafc5e1c956e, []int{10}
+	return fileDescriptor_common_43e9c81b2bc570be, []int{11}
 }
 func (m *BlockData) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BlockData.Unmarshal(m, b)
@@ -741,7 +810,7 @@ func (m *BlockMetadata) Reset()         { *m = BlockMetadata{} }
 func (m *BlockMetadata) String() string { return proto.CompactTextString(m) }
 func (*BlockMetadata) ProtoMessage()    {}
 func (*BlockMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_common_b374fafc5e1c956e, []int{11}
+	return fileDescriptor_common_43e9c81b2bc570be, []int{12}
 }
 func (m *BlockMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BlockMetadata.Unmarshal(m, b)
@@ -770,6 +839,7 @@ func (m *BlockMetadata) GetMetadata() [][]byte {
 
 func init() {
 	proto.RegisterType((*LastConfig)(nil), "common.LastConfig")
+	proto.RegisterType((*Checkpoint)(nil), "common.Checkpoint")
 	proto.RegisterType((*Metadata)(nil), "common.Metadata")
 	proto.RegisterType((*MetadataSignature)(nil), "common.MetadataSignature")
 	proto.RegisterType((*Header)(nil), "common.Header")
@@ -786,68 +856,73 @@ func init() {
 	proto.RegisterEnum("common.BlockMetadataIndex", BlockMetadataIndex_name, BlockMetadataIndex_value)
 }
 
-func init() { proto.RegisterFile("common/common.proto", fileDescriptor_common_b374fafc5e1c956e) }
+func init() { proto.RegisterFile("common/common.proto", fileDescriptor_common_43e9c81b2bc570be) }
 
-var fileDescriptor_common_b374fafc5e1c956e = []byte{
-	// 959 bytes of a gzipped FileDescriptorProto
+var fileDescriptor_common_43e9c81b2bc570be = []byte{
+	// 1038 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x55, 0xcf, 0x6f, 0xe3, 0x44,
-	0x18, 0xdd, 0xc4, 0xf9, 0xf9, 0xa5, 0x69, 0xdd, 0x49, 0xcb, 0x9a, 0xc2, 0x6a, 0x2b, 0xc3, 0xa2,
-	0xd2, 0x4a, 0xa9, 0x28, 0x17, 0x38, 0x3a, 0xf6, 0xb4, 0xb5, 0x9a, 0x8e, 0xc3, 0xd8, 0x59, 0xc4,
-	0x2e, 0x92, 0xe5, 0x24, 0xd3, 0x24, 0x22, 0xb1, 0x23, 0x7b, 0x52, 0xb5, 0x67, 0xee, 0x08, 0x09,
-	0xae, 0xfc, 0x2f, 0x1c, 0x11, 0x7f, 0x0f, 0x88, 0x2b, 0x1a, 0x8f, 0xed, 0x4d, 0xca, 0x4a, 0x9c,
-	0xe2, 0xf7, 0xe6, 0xcd, 0xf7, 0xbd, 0xf9, 0xde, 0xc4, 0x86, 0xce, 0x38, 0x5a, 0x2e, 0xa3, 0xf0,
-	0x5c, 0xfe, 0x74, 0x57, 0x71, 0xc4, 0x23, 0x54, 0x93, 0xe8, 0xe8, 0xe5, 0x34, 0x8a, 0xa6, 0x0b,
-	0x76, 0x9e, 0xb2, 0xa3, 0xf5, 0xdd, 0x39, 0x9f, 0x2f, 0x59, 0xc2, 0x83, 0xe5, 0x4a, 0x0a, 0x75,
-	0x1d, 0xa0, 0x1f, 0x24, 0xdc, 0x8c, 0xc2, 0xbb, 0xf9, 0x14, 0x1d, 0x40, 0x75, 0x1e, 0x4e, 0xd8,
-	0x83, 0x56, 0x3a, 0x2e, 0x9d, 0x54, 0xa8, 0x04, 0xfa, 0x5b, 0x68, 0xdc, 0x32, 0x1e, 0x4c, 0x02,
-	0x1e, 0x08, 0xc5, 0x7d, 0xb0, 0x58, 0xb3, 0x54, 0xb1, 0x43, 0x25, 0x40, 0x5f, 0x03, 0x24, 0xf3,
-	0x69, 0x18, 0xf0, 0x75, 0xcc, 0x12, 0xad, 0x7c, 0xac, 0x9c, 0xb4, 0x2e, 0x3e, 0xec, 0x66, 0x8e,
-	0xf2, 0xbd, 0x6e, 0xae, 0xa0, 0x1b, 0x62, 0xfd, 0x7b, 0xd8, 0xff, 0x8f, 0x00, 0x7d, 0x0e, 0x6a,
-	0x21, 0xf1, 0x67, 0x2c, 0x98, 0xb0, 0x38, 0x6b, 0xb8, 0x57, 0xf0, 0xd7, 0x29, 0x8d, 0x3e, 0x86,
-	0x66, 0x41, 0x69, 0xe5, 0x54, 0xf3, 0x8e, 0xd0, 0xdf, 0x40, 0x2d, 0xd3, 0xbd, 0x82, 0xdd, 0xf1,
-	0x2c, 0x08, 0x43, 0xb6, 0xd8, 0x2e, 0xd8, 0xce, 0xd8, 0x4c, 0xf6, 0xbe, 0xce, 0xe5, 0xf7, 0x76,
-	0xd6, 0x7f, 0x2c, 0x43, 0xdb, 0xdc, 0xda, 0x8c, 0xa0, 0xc2, 0x1f, 0x57, 0x72, 0x36, 0x55, 0x9a,
-	0x3e, 0x23, 0x0d, 0xea, 0xf7, 0x2c, 0x4e, 0xe6, 0x51, 0x98, 0xd6, 0xa9, 0xd2, 0x1c, 0xa2, 0xaf,
-	0xa0, 0x59, 0xa4, 0xa1, 0x29, 0xc7, 0xa5, 0x93, 0xd6, 0xc5, 0x51, 0x57, 0xe6, 0xd5, 0xcd, 0xf3,
-	0xea, 0x7a, 0xb9, 0x82, 0xbe, 0x13, 0xa3, 0x17, 0x00, 0xf9, 0x59, 0xe6, 0x13, 0xad, 0x72, 0x5c,
-	0x3a, 0x69, 0xd2, 0x66, 0xc6, 0xd8, 0x13, 0xd4, 0x81, 0x2a, 0x7f, 0x10, 0x2b, 0xd5, 0x74, 0xa5,
-	0xc2, 0x1f, 0xec, 0x89, 0x08, 0x8e, 0xad, 0xa2, 0xf1, 0x4c, 0xab, 0xc9, 0x68, 0x53, 0x20, 0xa6,
-	0xc7, 0x1e, 0x38, 0x0b, 0x53, 0x7f, 0x75, 0x39, 0xbd, 0x82, 0x40, 0x3a, 0xb4, 0xf9, 0x22, 0xf1,
-	0xc7, 0x2c, 0xe6, 0xfe, 0x2c, 0x48, 0x66, 0x5a, 0x23, 0x55, 0xb4, 0xf8, 0x22, 0x31, 0x59, 0xcc,
-	0xaf, 0x83, 0x64, 0xa6, 0x1b, 0xb0, 0xe7, 0x3e, 0x89, 0x44, 0x83, 0xfa, 0x38, 0x66, 0x01, 0x8f,
-	0xf2, 0x19, 0xe7, 0x50, 0x98, 0x08, 0xa3, 0x70, 0x9c, 0x07, 0x25, 0x81, 0x8e, 0xa1, 0x3e, 0x08,
-	0x1e, 0x17, 0x51, 0x30, 0x41, 0x9f, 0x41, 0x6d, 0x23, 0x9d, 0xd6, 0xc5, 0x6e, 0x7e, 0x89, 0x64,
-	0x69, 0x9a, 0xad, 0x8a, 0x49, 0x8b, 0x1b, 0x93, 0xd5, 0x49, 0x9f, 0xf5, 0x1e, 0x34, 0x70, 0x78,
-	0xcf, 0x16, 0x91, 0x9c, 0xfa, 0x4a, 0x96, 0xcc, 0x2d, 0x64, 0xf0, 0x7f, 0xee, 0xcb, 0x4f, 0x25,
-	0xa8, 0xf6, 0x16, 0xd1, 0xf8, 0x07, 0x74, 0xf6, 0xc4, 0x49, 0x27, 0x77, 0x92, 0x2e, 0x3f, 0xb1,
-	0xf3, 0x6a, 0xc3, 0x4e, 0xeb, 0x62, 0x7f, 0x4b, 0x6a, 0x05, 0x3c, 0x90, 0x0e, 0xd1, 0x17, 0xd0,
-	0x58, 0x66, 0x77, 0x3d, 0x0b, 0xfc, 0x70, 0x4b, 0x9a, 0xff, 0x11, 0x68, 0x21, 0xd3, 0xa7, 0xd0,
-	0xda, 0x68, 0x88, 0x3e, 0x80, 0x5a, 0xb8, 0x5e, 0x8e, 0x32, 0x57, 0x15, 0x9a, 0x21, 0xf4, 0x09,
-	0xb4, 0x57, 0x31, 0xbb, 0x9f, 0x47, 0xeb, 0x44, 0x26, 0x25, 0x4f, 0xb6, 0x93, 0x93, 0x22, 0x2a,
-	0xf4, 0x11, 0x34, 0x45, 0x4d, 0x29, 0x50, 0x52, 0x41, 0x43, 0x10, 0x69, 0x8e, 0x2f, 0xa1, 0x59,
-	0xd8, 0x2d, 0xc6, 0x5b, 0x3a, 0x56, 0x8a, 0xf1, 0x9e, 0x41, 0x7b, 0xcb, 0x24, 0x3a, 0xda, 0x38,
-	0x8d, 0x14, 0x16, 0xf8, 0xf4, 0xf7, 0x12, 0xd4, 0x5c, 0x1e, 0xf0, 0x75, 0x82, 0x5a, 0x50, 0x1f,
-	0x92, 0x1b, 0xe2, 0x7c, 0x4b, 0xd4, 0x67, 0x68, 0x07, 0xea, 0xee, 0xd0, 0x34, 0xb1, 0xeb, 0xaa,
-	0x7f, 0x94, 0x90, 0x0a, 0xad, 0x9e, 0x61, 0xf9, 0x14, 0x7f, 0x33, 0xc4, 0xae, 0xa7, 0xfe, 0xac,
-	0xa0, 0x5d, 0x68, 0x5e, 0x3a, 0xb4, 0x67, 0x5b, 0x16, 0x26, 0xea, 0x2f, 0x29, 0x26, 0x8e, 0xe7,
-	0x5f, 0x3a, 0x43, 0x62, 0xa9, 0xbf, 0x2a, 0xe8, 0x05, 0x68, 0x99, 0xda, 0xc7, 0xc4, 0xb3, 0xbd,
-	0xef, 0x7c, 0xcf, 0x71, 0xfc, 0xbe, 0x41, 0xaf, 0xb0, 0xfa, 0x9b, 0x82, 0x8e, 0xe0, 0xd0, 0x26,
-	0x1e, 0xa6, 0xc4, 0xe8, 0xfb, 0x2e, 0xa6, 0xaf, 0x31, 0xf5, 0x31, 0xa5, 0x0e, 0x55, 0xff, 0x52,
-	0xd0, 0x01, 0xec, 0x89, 0x52, 0xf6, 0xed, 0xa0, 0x8f, 0x6f, 0x31, 0xf1, 0xb0, 0xa5, 0xfe, 0xad,
-	0x20, 0x0d, 0x3a, 0x42, 0x68, 0x9b, 0xd8, 0x1f, 0x12, 0xe3, 0xb5, 0x61, 0xf7, 0x8d, 0x5e, 0x1f,
-	0xab, 0xff, 0x28, 0xa7, 0x7f, 0x96, 0x00, 0xe4, 0xd4, 0x3d, 0xf1, 0x3f, 0x6e, 0x41, 0xfd, 0x16,
-	0xbb, 0xae, 0x71, 0x85, 0xd5, 0x67, 0x08, 0xa0, 0x66, 0x3a, 0xe4, 0xd2, 0xbe, 0x52, 0x4b, 0x68,
-	0x1f, 0xda, 0xf2, 0xd9, 0x1f, 0x0e, 0x2c, 0xc3, 0xc3, 0x6a, 0x19, 0x69, 0x70, 0x80, 0x89, 0xe5,
-	0x50, 0x17, 0x53, 0xdf, 0xa3, 0x06, 0x71, 0x0d, 0xd3, 0xb3, 0x1d, 0xa2, 0x2a, 0xe8, 0x39, 0x74,
-	0x1c, 0x6a, 0x61, 0xfa, 0x64, 0xa1, 0x82, 0x0e, 0x61, 0xdf, 0xc2, 0x7d, 0x5b, 0x38, 0x76, 0x31,
-	0xbe, 0xf1, 0x6d, 0x72, 0xe9, 0xa8, 0x55, 0x41, 0x9b, 0xd7, 0x86, 0x4d, 0x4c, 0xc7, 0xc2, 0xfe,
-	0xc0, 0x30, 0x6f, 0x44, 0xff, 0x9a, 0x68, 0x30, 0xc0, 0x98, 0xfa, 0x86, 0x75, 0x6b, 0x13, 0xdf,
-	0x19, 0x60, 0x6a, 0xa4, 0x75, 0x1a, 0x62, 0x83, 0xe7, 0xdc, 0x60, 0xb2, 0x55, 0xbe, 0x79, 0xfa,
-	0x16, 0xd0, 0x56, 0x78, 0xb6, 0x78, 0xb1, 0xa3, 0x5d, 0x00, 0xd7, 0xbe, 0x22, 0x86, 0x37, 0xa4,
-	0xd8, 0x55, 0x9f, 0xa1, 0x3d, 0x68, 0xf5, 0x0d, 0xd7, 0xf3, 0x8b, 0xb3, 0x3d, 0x87, 0xce, 0x46,
-	0x1d, 0xd7, 0xbf, 0xb4, 0xfb, 0x1e, 0xa6, 0x6a, 0x59, 0x4c, 0x23, 0x3b, 0x87, 0xaa, 0xf4, 0x5c,
-	0xf8, 0x34, 0x8a, 0xa7, 0xdd, 0xd9, 0xe3, 0x8a, 0xc5, 0x0b, 0x36, 0x99, 0xb2, 0xb8, 0x7b, 0x17,
-	0x8c, 0xe2, 0xf9, 0x58, 0xbe, 0xc6, 0x92, 0xec, 0x8e, 0xbf, 0x39, 0x9b, 0xce, 0xf9, 0x6c, 0x3d,
-	0x12, 0xf0, 0x7c, 0x43, 0x7c, 0x2e, 0xc5, 0xf2, 0x1b, 0x95, 0x64, 0xdf, 0xb1, 0x51, 0x2d, 0x85,
-	0x5f, 0xfe, 0x1b, 0x00, 0x00, 0xff, 0xff, 0x63, 0x9b, 0x9f, 0x8b, 0xdf, 0x06, 0x00, 0x00,
+	0x14, 0xde, 0xfc, 0x4e, 0x5e, 0x9a, 0xd6, 0x9d, 0x6c, 0x77, 0x4d, 0x61, 0xd5, 0xca, 0xb0, 0xa8,
+	0xb4, 0x52, 0x22, 0xca, 0x65, 0x39, 0x3a, 0xf6, 0xb4, 0xb5, 0x9a, 0xda, 0x61, 0xec, 0x2c, 0x62,
+	0x41, 0xb2, 0xdc, 0x64, 0x9a, 0x44, 0x4d, 0xec, 0xc8, 0x9e, 0x54, 0xed, 0x99, 0x3b, 0x42, 0x82,
+	0x2b, 0x7f, 0x07, 0x57, 0x8e, 0x88, 0xbf, 0x07, 0xc4, 0x15, 0xcd, 0x8c, 0xed, 0x4d, 0xba, 0x95,
+	0x38, 0xc5, 0xdf, 0x9b, 0xef, 0xbd, 0xf9, 0xe6, 0x7d, 0x6f, 0x32, 0xd0, 0x1e, 0x45, 0x8b, 0x45,
+	0x14, 0x76, 0xe5, 0x4f, 0x67, 0x19, 0x47, 0x2c, 0x42, 0x55, 0x89, 0xf6, 0x0f, 0x26, 0x51, 0x34,
+	0x99, 0xd3, 0xae, 0x88, 0x5e, 0xaf, 0x6e, 0xba, 0x6c, 0xb6, 0xa0, 0x09, 0x0b, 0x16, 0x4b, 0x49,
+	0xd4, 0x34, 0x80, 0x7e, 0x90, 0x30, 0x23, 0x0a, 0x6f, 0x66, 0x13, 0xf4, 0x1c, 0x2a, 0xb3, 0x70,
+	0x4c, 0xef, 0xd5, 0xc2, 0x61, 0xe1, 0xa8, 0x4c, 0x24, 0xd0, 0x7e, 0x2f, 0x00, 0x18, 0x53, 0x3a,
+	0xba, 0x5d, 0x46, 0xb3, 0x90, 0xa1, 0x03, 0x68, 0xce, 0x83, 0x84, 0xf9, 0x23, 0x91, 0x93, 0x52,
+	0x61, 0xfe, 0xbe, 0x4a, 0x17, 0xda, 0xcb, 0x98, 0xde, 0xcd, 0xa2, 0x55, 0xe2, 0x8f, 0xf2, 0x3c,
+	0xb5, 0x28, 0x88, 0x28, 0x5b, 0x5a, 0xab, 0xf8, 0x06, 0xd4, 0x27, 0x12, 0xfc, 0x69, 0x90, 0x4c,
+	0xd5, 0xd2, 0x61, 0xe1, 0x68, 0x8b, 0xbc, 0xf8, 0x30, 0xeb, 0x22, 0x48, 0xa6, 0xe8, 0x15, 0x40,
+	0xc2, 0x02, 0x46, 0x25, 0xb7, 0x2c, 0xb8, 0x0d, 0x11, 0xe1, 0xcb, 0xda, 0xf7, 0x50, 0xbf, 0xa2,
+	0x2c, 0x18, 0x07, 0x2c, 0xe0, 0x67, 0xbb, 0x0b, 0xe6, 0x2b, 0x2a, 0x04, 0x6f, 0x11, 0x09, 0xd0,
+	0xd7, 0x00, 0xc9, 0x6c, 0x12, 0x06, 0x6c, 0x15, 0xd3, 0x44, 0x2d, 0x1e, 0x96, 0x8e, 0x9a, 0xa7,
+	0x1f, 0x75, 0xd2, 0x5e, 0x66, 0xb9, 0x6e, 0xc6, 0x20, 0x6b, 0x64, 0xed, 0x07, 0xd8, 0xfd, 0x80,
+	0x80, 0xbe, 0x00, 0x25, 0xa7, 0xf8, 0x53, 0x1a, 0x8c, 0x69, 0x9c, 0x6e, 0xb8, 0x93, 0xc7, 0x2f,
+	0x44, 0x18, 0x7d, 0x02, 0x8d, 0x3c, 0xa4, 0x16, 0x53, 0xe9, 0x59, 0x40, 0x7b, 0x07, 0xd5, 0x94,
+	0xf7, 0x1a, 0xb6, 0x47, 0xd3, 0x20, 0x0c, 0xe9, 0x7c, 0xb3, 0x60, 0x2b, 0x8d, 0xa6, 0xb4, 0xa7,
+	0x76, 0x2e, 0x3e, 0xb9, 0xb3, 0xf6, 0x63, 0x11, 0x5a, 0xc6, 0x46, 0x32, 0x82, 0x32, 0x7b, 0x58,
+	0xca, 0xde, 0x54, 0x88, 0xf8, 0x46, 0x2a, 0xd4, 0xee, 0x68, 0x9c, 0xcc, 0xa2, 0x50, 0xd4, 0xa9,
+	0x90, 0x0c, 0xa2, 0x37, 0xd0, 0xc8, 0xe7, 0x48, 0x18, 0xd4, 0x3c, 0xdd, 0xef, 0xc8, 0x49, 0xeb,
+	0x64, 0x93, 0xd6, 0xf1, 0x32, 0x06, 0x79, 0x4f, 0xe6, 0x7e, 0x65, 0x67, 0x99, 0x8d, 0x85, 0x5f,
+	0x0d, 0xd2, 0x48, 0x23, 0xd6, 0x18, 0xb5, 0xa1, 0xc2, 0xee, 0xf9, 0x4a, 0x45, 0xac, 0x94, 0xd9,
+	0xbd, 0x35, 0xe6, 0xc6, 0xd1, 0x65, 0x34, 0x9a, 0xaa, 0x55, 0x39, 0x94, 0x02, 0xf0, 0xee, 0xd1,
+	0x7b, 0x46, 0x43, 0xa1, 0xaf, 0x26, 0xbb, 0x97, 0x07, 0x90, 0x06, 0x2d, 0x36, 0x4f, 0xfc, 0x11,
+	0x8d, 0xd3, 0x31, 0xaa, 0x0b, 0x46, 0x93, 0xcd, 0x13, 0x83, 0xc6, 0x62, 0x76, 0x34, 0x1d, 0x76,
+	0xdc, 0x47, 0x96, 0xa8, 0x50, 0x1b, 0xc5, 0x34, 0x60, 0x51, 0xd6, 0xe3, 0x0c, 0x72, 0x11, 0x61,
+	0x14, 0x8e, 0x32, 0xa3, 0x24, 0xd0, 0x30, 0xd4, 0x06, 0xc1, 0xc3, 0x3c, 0x0a, 0xc6, 0xe8, 0x73,
+	0xa8, 0xae, 0xb9, 0xd3, 0x3c, 0xdd, 0xce, 0x86, 0x48, 0x96, 0x26, 0xd5, 0x69, 0xde, 0x69, 0x3e,
+	0x31, 0x69, 0x1d, 0xf1, 0xad, 0xf5, 0xa0, 0x8e, 0xc3, 0x3b, 0x3a, 0x8f, 0x64, 0xd7, 0x97, 0xb2,
+	0x64, 0x26, 0x21, 0x85, 0xff, 0x33, 0x2f, 0x3f, 0x15, 0xa0, 0xd2, 0x9b, 0x47, 0xa3, 0x5b, 0x74,
+	0xf2, 0x48, 0x49, 0x3b, 0x53, 0x22, 0x96, 0x1f, 0xc9, 0x79, 0xbd, 0x26, 0xa7, 0x79, 0xba, 0xbb,
+	0x41, 0x35, 0x03, 0x16, 0x48, 0x85, 0xe8, 0x4b, 0xa8, 0x2f, 0xd2, 0x59, 0x4f, 0x0d, 0xdf, 0xdb,
+	0xa0, 0x66, 0x17, 0x81, 0xe4, 0x34, 0x6d, 0x02, 0xcd, 0xb5, 0x0d, 0xd1, 0x0b, 0xa8, 0x86, 0xab,
+	0xc5, 0x75, 0xaa, 0xaa, 0x4c, 0x52, 0x84, 0x3e, 0x85, 0x56, 0x7e, 0xf7, 0x85, 0x53, 0xf2, 0x64,
+	0x5b, 0x59, 0x50, 0x5c, 0xf3, 0x8f, 0xa1, 0xc1, 0x6b, 0xae, 0xff, 0x23, 0xd4, 0x79, 0x40, 0xf8,
+	0x78, 0x00, 0x8d, 0x5c, 0x6e, 0xde, 0xde, 0xc2, 0x61, 0x29, 0x6f, 0xef, 0x09, 0xb4, 0x36, 0x44,
+	0xa2, 0xfd, 0xb5, 0xd3, 0x48, 0x62, 0x8e, 0x8f, 0xff, 0x28, 0x40, 0xd5, 0x65, 0x01, 0x5b, 0x25,
+	0xa8, 0x09, 0xb5, 0xa1, 0x7d, 0x69, 0x3b, 0xdf, 0xda, 0xca, 0x33, 0xb4, 0x05, 0x35, 0x77, 0x68,
+	0x18, 0xd8, 0x75, 0x95, 0x3f, 0x0b, 0x48, 0x81, 0x66, 0x4f, 0x37, 0x7d, 0x82, 0xbf, 0x19, 0x62,
+	0xd7, 0x53, 0x7e, 0x2e, 0xa1, 0x6d, 0x68, 0x9c, 0x39, 0xa4, 0x67, 0x99, 0x26, 0xb6, 0x95, 0x5f,
+	0x04, 0xb6, 0x1d, 0xcf, 0x3f, 0x73, 0x86, 0xb6, 0xa9, 0xfc, 0x5a, 0x42, 0xaf, 0x40, 0x4d, 0xd9,
+	0x3e, 0xb6, 0x3d, 0xcb, 0xfb, 0xce, 0xf7, 0x1c, 0xc7, 0xef, 0xeb, 0xe4, 0x1c, 0x2b, 0xbf, 0x95,
+	0xd0, 0x3e, 0xec, 0x59, 0xb6, 0x87, 0x89, 0xad, 0xf7, 0x7d, 0x17, 0x93, 0xb7, 0x98, 0xf8, 0x98,
+	0x10, 0x87, 0x28, 0x7f, 0x97, 0xd0, 0x73, 0xd8, 0xe1, 0xa5, 0xac, 0xab, 0x41, 0x1f, 0x5f, 0x61,
+	0xdb, 0xc3, 0xa6, 0xf2, 0x4f, 0x09, 0xa9, 0xd0, 0xe6, 0x44, 0xcb, 0xc0, 0xfe, 0xd0, 0xd6, 0xdf,
+	0xea, 0x56, 0x5f, 0xef, 0xf5, 0xb1, 0xf2, 0x6f, 0xe9, 0xf8, 0xaf, 0x02, 0x80, 0xec, 0xba, 0xc7,
+	0xef, 0x71, 0x13, 0x6a, 0x57, 0xd8, 0x75, 0xf5, 0x73, 0xac, 0x3c, 0x43, 0x00, 0x55, 0xc3, 0xb1,
+	0xcf, 0xac, 0x73, 0xa5, 0x80, 0x76, 0xa1, 0x25, 0xbf, 0xfd, 0xe1, 0xc0, 0xd4, 0x3d, 0xac, 0x14,
+	0x91, 0x0a, 0xcf, 0xb1, 0x6d, 0x3a, 0xc4, 0xc5, 0xc4, 0xf7, 0x88, 0x6e, 0xbb, 0xba, 0xe1, 0x59,
+	0x8e, 0xad, 0x94, 0xd0, 0x4b, 0x68, 0x3b, 0xc4, 0xc4, 0xe4, 0xd1, 0x42, 0x19, 0xed, 0xc1, 0xae,
+	0x89, 0xfb, 0x16, 0x57, 0xec, 0x62, 0x7c, 0xe9, 0x5b, 0xf6, 0x99, 0xa3, 0x54, 0x78, 0xd8, 0xb8,
+	0xd0, 0x2d, 0xdb, 0x70, 0x4c, 0xec, 0x0f, 0x74, 0xe3, 0x92, 0xef, 0x5f, 0xe5, 0x1b, 0x0c, 0x30,
+	0x26, 0xbe, 0x6e, 0x5e, 0x59, 0xb6, 0xef, 0x0c, 0x30, 0xd1, 0x45, 0x9d, 0x3a, 0x4f, 0xf0, 0x9c,
+	0x4b, 0x6c, 0x6f, 0x94, 0x6f, 0x1c, 0xdf, 0x02, 0xda, 0x30, 0xcf, 0xe2, 0x4f, 0x12, 0xda, 0x06,
+	0x70, 0xad, 0x73, 0x5b, 0xf7, 0x86, 0x04, 0xbb, 0xca, 0x33, 0xb4, 0x03, 0xcd, 0xbe, 0xee, 0x7a,
+	0x7e, 0x7e, 0xb6, 0x97, 0xd0, 0x5e, 0xab, 0xe3, 0xfa, 0x67, 0x56, 0xdf, 0xc3, 0x44, 0x29, 0xf2,
+	0x6e, 0xa4, 0xe7, 0x50, 0xb8, 0x49, 0x60, 0x5c, 0x60, 0xe3, 0x72, 0xe0, 0x58, 0xb6, 0xa7, 0x94,
+	0x7b, 0x2e, 0x7c, 0x16, 0xc5, 0x93, 0xce, 0xf4, 0x61, 0x49, 0xe3, 0x39, 0x1d, 0x4f, 0x68, 0xdc,
+	0xb9, 0x09, 0xae, 0xe3, 0xd9, 0x48, 0xfe, 0xad, 0x25, 0xe9, 0xcc, 0xbf, 0x3b, 0x99, 0xcc, 0xd8,
+	0x74, 0x75, 0xcd, 0x61, 0x77, 0x8d, 0xdc, 0x95, 0x64, 0xf9, 0xda, 0x26, 0xe9, 0x8b, 0x7c, 0x5d,
+	0x15, 0xf0, 0xab, 0xff, 0x06, 0x00, 0x4b, 0x0c, 0x7d, 0x50, 0xa9, 0x07, 0x00, 0x00,
 }
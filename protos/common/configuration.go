@@ -87,6 +87,8 @@ func (dccv *DynamicChannelConfigValue) VariablyOpaqueFieldProto(name string) (pr
 		return &Consortium{}, nil
 	case "Capabilities":
 		return &Capabilities{}, nil
+	case "EndorsementMaxSkew":
+		return &EndorsementMaxSkew{}, nil
 	default:
 		return nil, fmt.Errorf("unknown Channel ConfigValue name: %s", dccv.name)
 	}
@@ -32,7 +32,7 @@ func (m *HashingAlgorithm) Reset()         { *m = HashingAlgorithm{} }
 func (m *HashingAlgorithm) String() string { return proto.CompactTextString(m) }
 func (*HashingAlgorithm) ProtoMessage()    {}
 func (*HashingAlgorithm) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_c60fbe5ebb3de531, []int{0}
+	return fileDescriptor_configuration_1149a3231c26efcd, []int{0}
 }
 func (m *HashingAlgorithm) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_HashingAlgorithm.Unmarshal(m, b)
@@ -74,7 +74,7 @@ func (m *BlockDataHashingStructure) Reset()         { *m = BlockDataHashingStruc
 func (m *BlockDataHashingStructure) String() string { return proto.CompactTextString(m) }
 func (*BlockDataHashingStructure) ProtoMessage()    {}
 func (*BlockDataHashingStructure) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_c60fbe5ebb3de531, []int{1}
+	return fileDescriptor_configuration_1149a3231c26efcd, []int{1}
 }
 func (m *BlockDataHashingStructure) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BlockDataHashingStructure.Unmarshal(m, b)
@@ -114,7 +114,7 @@ func (m *OrdererAddresses) Reset()         { *m = OrdererAddresses{} }
 func (m *OrdererAddresses) String() string { return proto.CompactTextString(m) }
 func (*OrdererAddresses) ProtoMessage()    {}
 func (*OrdererAddresses) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_c60fbe5ebb3de531, []int{2}
+	return fileDescriptor_configuration_1149a3231c26efcd, []int{2}
 }
 func (m *OrdererAddresses) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_OrdererAddresses.Unmarshal(m, b)
@@ -153,7 +153,7 @@ func (m *Consortium) Reset()         { *m = Consortium{} }
 func (m *Consortium) String() string { return proto.CompactTextString(m) }
 func (*Consortium) ProtoMessage()    {}
 func (*Consortium) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_c60fbe5ebb3de531, []int{3}
+	return fileDescriptor_configuration_1149a3231c26efcd, []int{3}
 }
 func (m *Consortium) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Consortium.Unmarshal(m, b)
@@ -221,7 +221,7 @@ func (m *Capabilities) Reset()         { *m = Capabilities{} }
 func (m *Capabilities) String() string { return proto.CompactTextString(m) }
 func (*Capabilities) ProtoMessage()    {}
 func (*Capabilities) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_c60fbe5ebb3de531, []int{4}
+	return fileDescriptor_configuration_1149a3231c26efcd, []int{4}
 }
 func (m *Capabilities) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Capabilities.Unmarshal(m, b)
@@ -262,7 +262,7 @@ func (m *Capability) Reset()         { *m = Capability{} }
 func (m *Capability) String() string { return proto.CompactTextString(m) }
 func (*Capability) ProtoMessage()    {}
 func (*Capability) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_c60fbe5ebb3de531, []int{5}
+	return fileDescriptor_configuration_1149a3231c26efcd, []int{5}
 }
 func (m *Capability) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Capability.Unmarshal(m, b)
@@ -282,6 +282,49 @@ func (m *Capability) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_Capability proto.InternalMessageInfo
 
+// EndorsementMaxSkew is encoded into the configuration transaction as a configuration item of type Chain
+// with a Key of "EndorsementMaxSkew" and a Value of EndorsementMaxSkew as marshaled protobuf bytes.
+// It is the maximum difference allowed between the timestamp of a transaction and the time at which
+// it is ordered, when the expiration of the identities is checked against the endorsement time.
+type EndorsementMaxSkew struct {
+	// Any duration string parseable by ParseDuration()
+	Duration             string   `protobuf:"bytes,1,opt,name=duration" json:"duration,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EndorsementMaxSkew) Reset()         { *m = EndorsementMaxSkew{} }
+func (m *EndorsementMaxSkew) String() string { return proto.CompactTextString(m) }
+func (*EndorsementMaxSkew) ProtoMessage()    {}
+func (*EndorsementMaxSkew) Descriptor() ([]byte, []int) {
+	return fileDescriptor_configuration_1149a3231c26efcd, []int{6}
+}
+func (m *EndorsementMaxSkew) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EndorsementMaxSkew.Unmarshal(m, b)
+}
+func (m *EndorsementMaxSkew) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EndorsementMaxSkew.Marshal(b, m, deterministic)
+}
+func (dst *EndorsementMaxSkew) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EndorsementMaxSkew.Merge(dst, src)
+}
+func (m *EndorsementMaxSkew) XXX_Size() int {
+	return xxx_messageInfo_EndorsementMaxSkew.Size(m)
+}
+func (m *EndorsementMaxSkew) XXX_DiscardUnknown() {
+	xxx_messageInfo_EndorsementMaxSkew.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EndorsementMaxSkew proto.InternalMessageInfo
+
+func (m *EndorsementMaxSkew) GetDuration() string {
+	if m != nil {
+		return m.Duration
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*HashingAlgorithm)(nil), "common.HashingAlgorithm")
 	proto.RegisterType((*BlockDataHashingStructure)(nil), "common.BlockDataHashingStructure")
@@ -290,32 +333,34 @@ func init() {
 	proto.RegisterType((*Capabilities)(nil), "common.Capabilities")
 	proto.RegisterMapType((map[string]*Capability)(nil), "common.Capabilities.CapabilitiesEntry")
 	proto.RegisterType((*Capability)(nil), "common.Capability")
+	proto.RegisterType((*EndorsementMaxSkew)(nil), "common.EndorsementMaxSkew")
 }
 
 func init() {
-	proto.RegisterFile("common/configuration.proto", fileDescriptor_configuration_c60fbe5ebb3de531)
-}
-
-var fileDescriptor_configuration_c60fbe5ebb3de531 = []byte{
-	// 314 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0x41, 0x6b, 0xf2, 0x40,
-	0x10, 0x86, 0x89, 0x7e, 0x0a, 0x8e, 0x7e, 0x60, 0x97, 0x1e, 0xac, 0xf4, 0x10, 0x42, 0x91, 0x40,
-	0x21, 0x69, 0xed, 0xa5, 0xf4, 0xa6, 0xb6, 0x50, 0x7a, 0x29, 0xc4, 0x5b, 0x6f, 0x9b, 0x64, 0x4c,
-	0x16, 0x93, 0x5d, 0x99, 0xdd, 0xb4, 0xe4, 0x57, 0xf5, 0x2f, 0x16, 0xb3, 0x16, 0x23, 0xf6, 0x36,
-	0xcf, 0xce, 0xf3, 0xce, 0xce, 0xb2, 0x30, 0x4d, 0x54, 0x59, 0x2a, 0x19, 0x26, 0x4a, 0x6e, 0x44,
-	0x56, 0x11, 0x37, 0x42, 0xc9, 0x60, 0x47, 0xca, 0x28, 0xd6, 0xb7, 0x3d, 0x6f, 0x06, 0xe3, 0x57,
-	0xae, 0x73, 0x21, 0xb3, 0x45, 0x91, 0x29, 0x12, 0x26, 0x2f, 0x19, 0x83, 0x7f, 0x92, 0x97, 0x38,
-	0x71, 0x5c, 0xc7, 0x1f, 0x44, 0x4d, 0xed, 0xdd, 0xc3, 0xd5, 0xb2, 0x50, 0xc9, 0xf6, 0x99, 0x1b,
-	0x7e, 0x08, 0xac, 0x0d, 0x55, 0x89, 0xa9, 0x08, 0xd9, 0x25, 0xf4, 0xbe, 0x44, 0x6a, 0xf2, 0x26,
-	0xf1, 0x3f, 0xb2, 0xe0, 0xdd, 0xc1, 0xf8, 0x9d, 0x52, 0x24, 0xa4, 0x45, 0x9a, 0x12, 0x6a, 0x8d,
-	0x9a, 0x5d, 0xc3, 0x80, 0xff, 0xc2, 0xc4, 0x71, 0xbb, 0xfe, 0x20, 0x3a, 0x1e, 0x78, 0x2e, 0xc0,
-	0x4a, 0x49, 0xad, 0xc8, 0x88, 0xea, 0xef, 0x35, 0xbe, 0x1d, 0x18, 0xad, 0xf8, 0x8e, 0xc7, 0xa2,
-	0x10, 0x46, 0xa0, 0x66, 0x6f, 0x30, 0x4a, 0x5a, 0xdc, 0xcc, 0x1c, 0xce, 0x67, 0x81, 0x7d, 0x5e,
-	0xd0, 0x76, 0x4f, 0xe0, 0x45, 0x1a, 0xaa, 0xa3, 0x93, 0xec, 0x74, 0x0d, 0x17, 0x67, 0x0a, 0x1b,
-	0x43, 0x77, 0x8b, 0xf5, 0x61, 0x89, 0x7d, 0xc9, 0x7c, 0xe8, 0x7d, 0xf2, 0xa2, 0xc2, 0x49, 0xc7,
-	0x75, 0xfc, 0xe1, 0x9c, 0x9d, 0xdd, 0x55, 0x47, 0x56, 0x78, 0xea, 0x3c, 0x3a, 0xde, 0x08, 0xe0,
-	0xd8, 0x58, 0xae, 0xe1, 0x46, 0x51, 0x16, 0xe4, 0xf5, 0x0e, 0xa9, 0xc0, 0x34, 0x43, 0x0a, 0x36,
-	0x3c, 0x26, 0x91, 0xd8, 0x6f, 0xd1, 0x87, 0x59, 0x1f, 0xb7, 0x99, 0x30, 0x79, 0x15, 0xef, 0x31,
-	0x6c, 0xc9, 0xa1, 0x95, 0x43, 0x2b, 0x87, 0x56, 0x8e, 0xfb, 0x0d, 0x3e, 0xfc, 0x04, 0x00, 0x00,
-	0xff, 0xff, 0xd6, 0x7e, 0xb4, 0x89, 0xf0, 0x01, 0x00, 0x00,
+	proto.RegisterFile("common/configuration.proto", fileDescriptor_configuration_1149a3231c26efcd)
+}
+
+var fileDescriptor_configuration_1149a3231c26efcd = []byte{
+	// 336 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0x41, 0x6b, 0xea, 0x40,
+	0x10, 0xc7, 0x89, 0x3e, 0xe5, 0x39, 0xfa, 0x20, 0x6f, 0xe9, 0xc1, 0x4a, 0x0f, 0x21, 0x14, 0x09,
+	0x14, 0x92, 0xd6, 0x5e, 0x4a, 0x6f, 0x6a, 0x85, 0x52, 0x28, 0x85, 0xe4, 0xd6, 0xdb, 0x26, 0x59,
+	0x93, 0xc5, 0x64, 0x57, 0x66, 0x37, 0xb5, 0xf9, 0x54, 0xfd, 0x8a, 0xc5, 0x6c, 0x8a, 0x8a, 0xbd,
+	0xcd, 0x2f, 0xf3, 0x9b, 0xfc, 0x67, 0x19, 0x98, 0x24, 0xb2, 0x2c, 0xa5, 0x08, 0x12, 0x29, 0xd6,
+	0x3c, 0xab, 0x90, 0x6a, 0x2e, 0x85, 0xbf, 0x45, 0xa9, 0x25, 0xe9, 0x9b, 0x9e, 0x3b, 0x05, 0xfb,
+	0x99, 0xaa, 0x9c, 0x8b, 0x6c, 0x5e, 0x64, 0x12, 0xb9, 0xce, 0x4b, 0x42, 0xe0, 0x8f, 0xa0, 0x25,
+	0x1b, 0x5b, 0x8e, 0xe5, 0x0d, 0xc2, 0xa6, 0x76, 0xef, 0xe0, 0x72, 0x51, 0xc8, 0x64, 0xf3, 0x44,
+	0x35, 0x6d, 0x07, 0x22, 0x8d, 0x55, 0xa2, 0x2b, 0x64, 0xe4, 0x02, 0x7a, 0x3b, 0x9e, 0xea, 0xbc,
+	0x99, 0xf8, 0x17, 0x1a, 0x70, 0x6f, 0xc1, 0x7e, 0xc3, 0x94, 0x21, 0xc3, 0x79, 0x9a, 0x22, 0x53,
+	0x8a, 0x29, 0x72, 0x05, 0x03, 0xfa, 0x03, 0x63, 0xcb, 0xe9, 0x7a, 0x83, 0xf0, 0xf0, 0xc1, 0x75,
+	0x00, 0x96, 0x52, 0x28, 0x89, 0x9a, 0x57, 0xbf, 0xaf, 0xf1, 0x65, 0xc1, 0x68, 0x49, 0xb7, 0x34,
+	0xe6, 0x05, 0xd7, 0x9c, 0x29, 0xf2, 0x02, 0xa3, 0xe4, 0x88, 0x9b, 0x7f, 0x0e, 0x67, 0x53, 0xdf,
+	0x3c, 0xcf, 0x3f, 0x76, 0x4f, 0x60, 0x25, 0x34, 0xd6, 0xe1, 0xc9, 0xec, 0x24, 0x82, 0xff, 0x67,
+	0x0a, 0xb1, 0xa1, 0xbb, 0x61, 0x75, 0xbb, 0xc4, 0xbe, 0x24, 0x1e, 0xf4, 0x3e, 0x68, 0x51, 0xb1,
+	0x71, 0xc7, 0xb1, 0xbc, 0xe1, 0x8c, 0x9c, 0x65, 0xd5, 0xa1, 0x11, 0x1e, 0x3b, 0x0f, 0x96, 0x3b,
+	0x02, 0x38, 0x34, 0xdc, 0x29, 0x90, 0x95, 0x48, 0x25, 0x2a, 0x56, 0x32, 0xa1, 0x5f, 0xe9, 0x67,
+	0xb4, 0x61, 0x3b, 0x62, 0xc3, 0xdf, 0xb4, 0x3d, 0x8f, 0x09, 0x5a, 0x44, 0x70, 0x2d, 0x31, 0xf3,
+	0xf3, 0x7a, 0xcb, 0xb0, 0x60, 0x69, 0xc6, 0xd0, 0x5f, 0xd3, 0x18, 0x79, 0x62, 0xce, 0xa7, 0xda,
+	0xcc, 0xf7, 0x9b, 0x8c, 0xeb, 0xbc, 0x8a, 0xf7, 0x18, 0x1c, 0xc9, 0x81, 0x91, 0x03, 0x23, 0x07,
+	0x46, 0x8e, 0xfb, 0x0d, 0xde, 0x7f, 0x0f, 0x00, 0x4f, 0x3f, 0x7c, 0xf0, 0x18, 0x02, 0x00, 0x00,
 }
@@ -47,7 +47,7 @@ func (x PullMsgType) String() string {
 	return proto.EnumName(PullMsgType_name, int32(x))
 }
 func (PullMsgType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{0}
+	return fileDescriptor_message_981e4d545985bf5a, []int{0}
 }
 
 type GossipMessage_Tag int32
@@ -82,7 +82,7 @@ func (x GossipMessage_Tag) String() string {
 	return proto.EnumName(GossipMessage_Tag_name, int32(x))
 }
 func (GossipMessage_Tag) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{3, 0}
+	return fileDescriptor_message_981e4d545985bf5a, []int{3, 0}
 }
 
 // Envelope contains a marshalled
@@ -102,7 +102,7 @@ func (m *Envelope) Reset()         { *m = Envelope{} }
 func (m *Envelope) String() string { return proto.CompactTextString(m) }
 func (*Envelope) ProtoMessage()    {}
 func (*Envelope) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{0}
+	return fileDescriptor_message_981e4d545985bf5a, []int{0}
 }
 func (m *Envelope) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Envelope.Unmarshal(m, b)
@@ -160,7 +160,7 @@ func (m *SecretEnvelope) Reset()         { *m = SecretEnvelope{} }
 func (m *SecretEnvelope) String() string { return proto.CompactTextString(m) }
 func (*SecretEnvelope) ProtoMessage()    {}
 func (*SecretEnvelope) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{1}
+	return fileDescriptor_message_981e4d545985bf5a, []int{1}
 }
 func (m *SecretEnvelope) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SecretEnvelope.Unmarshal(m, b)
@@ -210,7 +210,7 @@ func (m *Secret) Reset()         { *m = Secret{} }
 func (m *Secret) String() string { return proto.CompactTextString(m) }
 func (*Secret) ProtoMessage()    {}
 func (*Secret) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{2}
+	return fileDescriptor_message_981e4d545985bf5a, []int{2}
 }
 func (m *Secret) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Secret.Unmarshal(m, b)
@@ -339,6 +339,7 @@ type GossipMessage struct {
 	//	*GossipMessage_PrivateReq
 	//	*GossipMessage_PrivateRes
 	//	*GossipMessage_PrivateData
+	//	*GossipMessage_PvtDataSummary
 	Content              isGossipMessage_Content `protobuf_oneof:"content"`
 	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
 	XXX_unrecognized     []byte                  `json:"-"`
@@ -349,7 +350,7 @@ func (m *GossipMessage) Reset()         { *m = GossipMessage{} }
 func (m *GossipMessage) String() string { return proto.CompactTextString(m) }
 func (*GossipMessage) ProtoMessage()    {}
 func (*GossipMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{3}
+	return fileDescriptor_message_981e4d545985bf5a, []int{3}
 }
 func (m *GossipMessage) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GossipMessage.Unmarshal(m, b)
@@ -436,6 +437,9 @@ type GossipMessage_PrivateRes struct {
 type GossipMessage_PrivateData struct {
 	PrivateData *PrivateDataMessage `protobuf:"bytes,25,opt,name=private_data,json=privateData,oneof"`
 }
+type GossipMessage_PvtDataSummary struct {
+	PvtDataSummary *PvtDataSummary `protobuf:"bytes,26,opt,name=pvt_data_summary,json=pvtDataSummary,oneof"`
+}
 
 func (*GossipMessage_AliveMsg) isGossipMessage_Content()         {}
 func (*GossipMessage_MemReq) isGossipMessage_Content()           {}
@@ -458,6 +462,7 @@ func (*GossipMessage_Ack) isGossipMessage_Content()              {}
 func (*GossipMessage_PrivateReq) isGossipMessage_Content()       {}
 func (*GossipMessage_PrivateRes) isGossipMessage_Content()       {}
 func (*GossipMessage_PrivateData) isGossipMessage_Content()      {}
+func (*GossipMessage_PvtDataSummary) isGossipMessage_Content()   {}
 
 func (m *GossipMessage) GetContent() isGossipMessage_Content {
 	if m != nil {
@@ -634,6 +639,13 @@ func (m *GossipMessage) GetPrivateData() *PrivateDataMessage {
 	return nil
 }
 
+func (m *GossipMessage) GetPvtDataSummary() *PvtDataSummary {
+	if x, ok := m.GetContent().(*GossipMessage_PvtDataSummary); ok {
+		return x.PvtDataSummary
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*GossipMessage) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _GossipMessage_OneofMarshaler, _GossipMessage_OneofUnmarshaler, _GossipMessage_OneofSizer, []interface{}{
@@ -658,6 +670,7 @@ func (*GossipMessage) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer)
 		(*GossipMessage_PrivateReq)(nil),
 		(*GossipMessage_PrivateRes)(nil),
 		(*GossipMessage_PrivateData)(nil),
+		(*GossipMessage_PvtDataSummary)(nil),
 	}
 }
 
@@ -770,6 +783,11 @@ func _GossipMessage_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.PrivateData); err != nil {
 			return err
 		}
+	case *GossipMessage_PvtDataSummary:
+		b.EncodeVarint(26<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.PvtDataSummary); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("GossipMessage.Content has unexpected type %T", x)
@@ -948,6 +966,14 @@ func _GossipMessage_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.
 		err := b.DecodeMessage(msg)
 		m.Content = &GossipMessage_PrivateData{msg}
 		return true, err
+	case 26: // content.pvt_data_summary
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(PvtDataSummary)
+		err := b.DecodeMessage(msg)
+		m.Content = &GossipMessage_PvtDataSummary{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -1062,6 +1088,11 @@ func _GossipMessage_OneofSizer(msg proto.Message) (n int) {
 		n += 2 // tag and wire
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *GossipMessage_PvtDataSummary:
+		s := proto.Size(x.PvtDataSummary)
+		n += 2 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -1088,7 +1119,7 @@ func (m *StateInfo) Reset()         { *m = StateInfo{} }
 func (m *StateInfo) String() string { return proto.CompactTextString(m) }
 func (*StateInfo) ProtoMessage()    {}
 func (*StateInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{4}
+	return fileDescriptor_message_981e4d545985bf5a, []int{4}
 }
 func (m *StateInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StateInfo.Unmarshal(m, b)
@@ -1137,19 +1168,22 @@ func (m *StateInfo) GetProperties() *Properties {
 }
 
 type Properties struct {
-	LedgerHeight         uint64       `protobuf:"varint,1,opt,name=ledger_height,json=ledgerHeight" json:"ledger_height,omitempty"`
-	LeftChannel          bool         `protobuf:"varint,2,opt,name=left_channel,json=leftChannel" json:"left_channel,omitempty"`
-	Chaincodes           []*Chaincode `protobuf:"bytes,3,rep,name=chaincodes" json:"chaincodes,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	LedgerHeight uint64       `protobuf:"varint,1,opt,name=ledger_height,json=ledgerHeight" json:"ledger_height,omitempty"`
+	LeftChannel  bool         `protobuf:"varint,2,opt,name=left_channel,json=leftChannel" json:"left_channel,omitempty"`
+	Chaincodes   []*Chaincode `protobuf:"bytes,3,rep,name=chaincodes" json:"chaincodes,omitempty"`
+	// archive is set by peers retaining the full history of their ledgers,
+	// which serve the state transfer requests for old blocks
+	Archive              bool     `protobuf:"varint,4,opt,name=archive" json:"archive,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Properties) Reset()         { *m = Properties{} }
 func (m *Properties) String() string { return proto.CompactTextString(m) }
 func (*Properties) ProtoMessage()    {}
 func (*Properties) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{5}
+	return fileDescriptor_message_981e4d545985bf5a, []int{5}
 }
 func (m *Properties) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Properties.Unmarshal(m, b)
@@ -1190,6 +1224,13 @@ func (m *Properties) GetChaincodes() []*Chaincode {
 	return nil
 }
 
+func (m *Properties) GetArchive() bool {
+	if m != nil {
+		return m.Archive
+	}
+	return false
+}
+
 // StateInfoSnapshot is an aggregation of StateInfo messages
 type StateInfoSnapshot struct {
 	Elements             []*Envelope `protobuf:"bytes,1,rep,name=elements" json:"elements,omitempty"`
@@ -1202,7 +1243,7 @@ func (m *StateInfoSnapshot) Reset()         { *m = StateInfoSnapshot{} }
 func (m *StateInfoSnapshot) String() string { return proto.CompactTextString(m) }
 func (*StateInfoSnapshot) ProtoMessage()    {}
 func (*StateInfoSnapshot) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{6}
+	return fileDescriptor_message_981e4d545985bf5a, []int{6}
 }
 func (m *StateInfoSnapshot) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StateInfoSnapshot.Unmarshal(m, b)
@@ -1245,7 +1286,7 @@ func (m *StateInfoPullRequest) Reset()         { *m = StateInfoPullRequest{} }
 func (m *StateInfoPullRequest) String() string { return proto.CompactTextString(m) }
 func (*StateInfoPullRequest) ProtoMessage()    {}
 func (*StateInfoPullRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{7}
+	return fileDescriptor_message_981e4d545985bf5a, []int{7}
 }
 func (m *StateInfoPullRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StateInfoPullRequest.Unmarshal(m, b)
@@ -1288,7 +1329,7 @@ func (m *ConnEstablish) Reset()         { *m = ConnEstablish{} }
 func (m *ConnEstablish) String() string { return proto.CompactTextString(m) }
 func (*ConnEstablish) ProtoMessage()    {}
 func (*ConnEstablish) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{8}
+	return fileDescriptor_message_981e4d545985bf5a, []int{8}
 }
 func (m *ConnEstablish) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ConnEstablish.Unmarshal(m, b)
@@ -1345,7 +1386,7 @@ func (m *PeerIdentity) Reset()         { *m = PeerIdentity{} }
 func (m *PeerIdentity) String() string { return proto.CompactTextString(m) }
 func (*PeerIdentity) ProtoMessage()    {}
 func (*PeerIdentity) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{9}
+	return fileDescriptor_message_981e4d545985bf5a, []int{9}
 }
 func (m *PeerIdentity) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PeerIdentity.Unmarshal(m, b)
@@ -1401,7 +1442,7 @@ func (m *DataRequest) Reset()         { *m = DataRequest{} }
 func (m *DataRequest) String() string { return proto.CompactTextString(m) }
 func (*DataRequest) ProtoMessage()    {}
 func (*DataRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{10}
+	return fileDescriptor_message_981e4d545985bf5a, []int{10}
 }
 func (m *DataRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DataRequest.Unmarshal(m, b)
@@ -1457,7 +1498,7 @@ func (m *GossipHello) Reset()         { *m = GossipHello{} }
 func (m *GossipHello) String() string { return proto.CompactTextString(m) }
 func (*GossipHello) ProtoMessage()    {}
 func (*GossipHello) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{11}
+	return fileDescriptor_message_981e4d545985bf5a, []int{11}
 }
 func (m *GossipHello) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GossipHello.Unmarshal(m, b)
@@ -1513,7 +1554,7 @@ func (m *DataUpdate) Reset()         { *m = DataUpdate{} }
 func (m *DataUpdate) String() string { return proto.CompactTextString(m) }
 func (*DataUpdate) ProtoMessage()    {}
 func (*DataUpdate) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{12}
+	return fileDescriptor_message_981e4d545985bf5a, []int{12}
 }
 func (m *DataUpdate) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DataUpdate.Unmarshal(m, b)
@@ -1569,7 +1610,7 @@ func (m *DataDigest) Reset()         { *m = DataDigest{} }
 func (m *DataDigest) String() string { return proto.CompactTextString(m) }
 func (*DataDigest) ProtoMessage()    {}
 func (*DataDigest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{13}
+	return fileDescriptor_message_981e4d545985bf5a, []int{13}
 }
 func (m *DataDigest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DataDigest.Unmarshal(m, b)
@@ -1622,7 +1663,7 @@ func (m *DataMessage) Reset()         { *m = DataMessage{} }
 func (m *DataMessage) String() string { return proto.CompactTextString(m) }
 func (*DataMessage) ProtoMessage()    {}
 func (*DataMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{14}
+	return fileDescriptor_message_981e4d545985bf5a, []int{14}
 }
 func (m *DataMessage) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DataMessage.Unmarshal(m, b)
@@ -1663,7 +1704,7 @@ func (m *PrivateDataMessage) Reset()         { *m = PrivateDataMessage{} }
 func (m *PrivateDataMessage) String() string { return proto.CompactTextString(m) }
 func (*PrivateDataMessage) ProtoMessage()    {}
 func (*PrivateDataMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{15}
+	return fileDescriptor_message_981e4d545985bf5a, []int{15}
 }
 func (m *PrivateDataMessage) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PrivateDataMessage.Unmarshal(m, b)
@@ -1704,7 +1745,7 @@ func (m *Payload) Reset()         { *m = Payload{} }
 func (m *Payload) String() string { return proto.CompactTextString(m) }
 func (*Payload) ProtoMessage()    {}
 func (*Payload) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{16}
+	return fileDescriptor_message_981e4d545985bf5a, []int{16}
 }
 func (m *Payload) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Payload.Unmarshal(m, b)
@@ -1764,7 +1805,7 @@ func (m *PrivatePayload) Reset()         { *m = PrivatePayload{} }
 func (m *PrivatePayload) String() string { return proto.CompactTextString(m) }
 func (*PrivatePayload) ProtoMessage()    {}
 func (*PrivatePayload) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{17}
+	return fileDescriptor_message_981e4d545985bf5a, []int{17}
 }
 func (m *PrivatePayload) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PrivatePayload.Unmarshal(m, b)
@@ -1841,7 +1882,7 @@ func (m *AliveMessage) Reset()         { *m = AliveMessage{} }
 func (m *AliveMessage) String() string { return proto.CompactTextString(m) }
 func (*AliveMessage) ProtoMessage()    {}
 func (*AliveMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{18}
+	return fileDescriptor_message_981e4d545985bf5a, []int{18}
 }
 func (m *AliveMessage) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AliveMessage.Unmarshal(m, b)
@@ -1897,7 +1938,7 @@ func (m *LeadershipMessage) Reset()         { *m = LeadershipMessage{} }
 func (m *LeadershipMessage) String() string { return proto.CompactTextString(m) }
 func (*LeadershipMessage) ProtoMessage()    {}
 func (*LeadershipMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{19}
+	return fileDescriptor_message_981e4d545985bf5a, []int{19}
 }
 func (m *LeadershipMessage) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_LeadershipMessage.Unmarshal(m, b)
@@ -1951,7 +1992,7 @@ func (m *PeerTime) Reset()         { *m = PeerTime{} }
 func (m *PeerTime) String() string { return proto.CompactTextString(m) }
 func (*PeerTime) ProtoMessage()    {}
 func (*PeerTime) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{20}
+	return fileDescriptor_message_981e4d545985bf5a, []int{20}
 }
 func (m *PeerTime) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PeerTime.Unmarshal(m, b)
@@ -1999,7 +2040,7 @@ func (m *MembershipRequest) Reset()         { *m = MembershipRequest{} }
 func (m *MembershipRequest) String() string { return proto.CompactTextString(m) }
 func (*MembershipRequest) ProtoMessage()    {}
 func (*MembershipRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{21}
+	return fileDescriptor_message_981e4d545985bf5a, []int{21}
 }
 func (m *MembershipRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MembershipRequest.Unmarshal(m, b)
@@ -2046,7 +2087,7 @@ func (m *MembershipResponse) Reset()         { *m = MembershipResponse{} }
 func (m *MembershipResponse) String() string { return proto.CompactTextString(m) }
 func (*MembershipResponse) ProtoMessage()    {}
 func (*MembershipResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{22}
+	return fileDescriptor_message_981e4d545985bf5a, []int{22}
 }
 func (m *MembershipResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MembershipResponse.Unmarshal(m, b)
@@ -2095,7 +2136,7 @@ func (m *Member) Reset()         { *m = Member{} }
 func (m *Member) String() string { return proto.CompactTextString(m) }
 func (*Member) ProtoMessage()    {}
 func (*Member) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{23}
+	return fileDescriptor_message_981e4d545985bf5a, []int{23}
 }
 func (m *Member) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Member.Unmarshal(m, b)
@@ -2147,7 +2188,7 @@ func (m *Empty) Reset()         { *m = Empty{} }
 func (m *Empty) String() string { return proto.CompactTextString(m) }
 func (*Empty) ProtoMessage()    {}
 func (*Empty) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{24}
+	return fileDescriptor_message_981e4d545985bf5a, []int{24}
 }
 func (m *Empty) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Empty.Unmarshal(m, b)
@@ -2181,7 +2222,7 @@ func (m *RemoteStateRequest) Reset()         { *m = RemoteStateRequest{} }
 func (m *RemoteStateRequest) String() string { return proto.CompactTextString(m) }
 func (*RemoteStateRequest) ProtoMessage()    {}
 func (*RemoteStateRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{25}
+	return fileDescriptor_message_981e4d545985bf5a, []int{25}
 }
 func (m *RemoteStateRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RemoteStateRequest.Unmarshal(m, b)
@@ -2228,7 +2269,7 @@ func (m *RemoteStateResponse) Reset()         { *m = RemoteStateResponse{} }
 func (m *RemoteStateResponse) String() string { return proto.CompactTextString(m) }
 func (*RemoteStateResponse) ProtoMessage()    {}
 func (*RemoteStateResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{26}
+	return fileDescriptor_message_981e4d545985bf5a, []int{26}
 }
 func (m *RemoteStateResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RemoteStateResponse.Unmarshal(m, b)
@@ -2268,7 +2309,7 @@ func (m *RemotePvtDataRequest) Reset()         { *m = RemotePvtDataRequest{} }
 func (m *RemotePvtDataRequest) String() string { return proto.CompactTextString(m) }
 func (*RemotePvtDataRequest) ProtoMessage()    {}
 func (*RemotePvtDataRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{27}
+	return fileDescriptor_message_981e4d545985bf5a, []int{27}
 }
 func (m *RemotePvtDataRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RemotePvtDataRequest.Unmarshal(m, b)
@@ -2311,7 +2352,7 @@ func (m *PvtDataDigest) Reset()         { *m = PvtDataDigest{} }
 func (m *PvtDataDigest) String() string { return proto.CompactTextString(m) }
 func (*PvtDataDigest) ProtoMessage()    {}
 func (*PvtDataDigest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{28}
+	return fileDescriptor_message_981e4d545985bf5a, []int{28}
 }
 func (m *PvtDataDigest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PvtDataDigest.Unmarshal(m, b)
@@ -2379,7 +2420,7 @@ func (m *RemotePvtDataResponse) Reset()         { *m = RemotePvtDataResponse{} }
 func (m *RemotePvtDataResponse) String() string { return proto.CompactTextString(m) }
 func (*RemotePvtDataResponse) ProtoMessage()    {}
 func (*RemotePvtDataResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{29}
+	return fileDescriptor_message_981e4d545985bf5a, []int{29}
 }
 func (m *RemotePvtDataResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RemotePvtDataResponse.Unmarshal(m, b)
@@ -2419,7 +2460,7 @@ func (m *PvtDataElement) Reset()         { *m = PvtDataElement{} }
 func (m *PvtDataElement) String() string { return proto.CompactTextString(m) }
 func (*PvtDataElement) ProtoMessage()    {}
 func (*PvtDataElement) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{30}
+	return fileDescriptor_message_981e4d545985bf5a, []int{30}
 }
 func (m *PvtDataElement) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PvtDataElement.Unmarshal(m, b)
@@ -2453,6 +2494,73 @@ func (m *PvtDataElement) GetPayload() [][]byte {
 	return nil
 }
 
+// PvtDataSummary summarizes the private data a peer holds
+// for a range of blocks, so that the other peers of the channel
+// detect the private data they miss. The bloom filter holds the
+// block sequence, the sequence in the block, the namespace and
+// the collection of each private write set held by the peer
+type PvtDataSummary struct {
+	StartBlock           uint64   `protobuf:"varint,1,opt,name=start_block,json=startBlock" json:"start_block,omitempty"`
+	EndBlock             uint64   `protobuf:"varint,2,opt,name=end_block,json=endBlock" json:"end_block,omitempty"`
+	BloomFilter          []byte   `protobuf:"bytes,3,opt,name=bloom_filter,json=bloomFilter,proto3" json:"bloom_filter,omitempty"`
+	HashFunctions        uint32   `protobuf:"varint,4,opt,name=hash_functions,json=hashFunctions" json:"hash_functions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PvtDataSummary) Reset()         { *m = PvtDataSummary{} }
+func (m *PvtDataSummary) String() string { return proto.CompactTextString(m) }
+func (*PvtDataSummary) ProtoMessage()    {}
+func (*PvtDataSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_message_981e4d545985bf5a, []int{31}
+}
+func (m *PvtDataSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PvtDataSummary.Unmarshal(m, b)
+}
+func (m *PvtDataSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PvtDataSummary.Marshal(b, m, deterministic)
+}
+func (dst *PvtDataSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PvtDataSummary.Merge(dst, src)
+}
+func (m *PvtDataSummary) XXX_Size() int {
+	return xxx_messageInfo_PvtDataSummary.Size(m)
+}
+func (m *PvtDataSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_PvtDataSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PvtDataSummary proto.InternalMessageInfo
+
+func (m *PvtDataSummary) GetStartBlock() uint64 {
+	if m != nil {
+		return m.StartBlock
+	}
+	return 0
+}
+
+func (m *PvtDataSummary) GetEndBlock() uint64 {
+	if m != nil {
+		return m.EndBlock
+	}
+	return 0
+}
+
+func (m *PvtDataSummary) GetBloomFilter() []byte {
+	if m != nil {
+		return m.BloomFilter
+	}
+	return nil
+}
+
+func (m *PvtDataSummary) GetHashFunctions() uint32 {
+	if m != nil {
+		return m.HashFunctions
+	}
+	return 0
+}
+
 // PvtPayload augments private rwset data and tx index
 // inside the block
 type PvtDataPayload struct {
@@ -2469,7 +2577,7 @@ func (m *PvtDataPayload) Reset()         { *m = PvtDataPayload{} }
 func (m *PvtDataPayload) String() string { return proto.CompactTextString(m) }
 func (*PvtDataPayload) ProtoMessage()    {}
 func (*PvtDataPayload) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{31}
+	return fileDescriptor_message_981e4d545985bf5a, []int{32}
 }
 func (m *PvtDataPayload) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PvtDataPayload.Unmarshal(m, b)
@@ -2514,7 +2622,7 @@ func (m *Acknowledgement) Reset()         { *m = Acknowledgement{} }
 func (m *Acknowledgement) String() string { return proto.CompactTextString(m) }
 func (*Acknowledgement) ProtoMessage()    {}
 func (*Acknowledgement) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{32}
+	return fileDescriptor_message_981e4d545985bf5a, []int{33}
 }
 func (m *Acknowledgement) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Acknowledgement.Unmarshal(m, b)
@@ -2556,7 +2664,7 @@ func (m *Chaincode) Reset()         { *m = Chaincode{} }
 func (m *Chaincode) String() string { return proto.CompactTextString(m) }
 func (*Chaincode) ProtoMessage()    {}
 func (*Chaincode) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_7c42328ef5ef9997, []int{33}
+	return fileDescriptor_message_981e4d545985bf5a, []int{34}
 }
 func (m *Chaincode) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Chaincode.Unmarshal(m, b)
@@ -2629,6 +2737,7 @@ func init() {
 	proto.RegisterType((*PvtDataDigest)(nil), "gossip.PvtDataDigest")
 	proto.RegisterType((*RemotePvtDataResponse)(nil), "gossip.RemotePvtDataResponse")
 	proto.RegisterType((*PvtDataElement)(nil), "gossip.PvtDataElement")
+	proto.RegisterType((*PvtDataSummary)(nil), "gossip.PvtDataSummary")
 	proto.RegisterType((*PvtDataPayload)(nil), "gossip.PvtDataPayload")
 	proto.RegisterType((*Acknowledgement)(nil), "gossip.Acknowledgement")
 	proto.RegisterType((*Chaincode)(nil), "gossip.Chaincode")
@@ -2778,126 +2887,132 @@ var _Gossip_serviceDesc = grpc.ServiceDesc{
 	Metadata: "gossip/message.proto",
 }
 
-func init() { proto.RegisterFile("gossip/message.proto", fileDescriptor_message_7c42328ef5ef9997) }
-
-var fileDescriptor_message_7c42328ef5ef9997 = []byte{
-	// 1874 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x58, 0x5b, 0x6f, 0xe3, 0xc6,
-	0x15, 0x16, 0x6d, 0x5d, 0x8f, 0x2e, 0x96, 0xc7, 0xde, 0x5d, 0xc6, 0x49, 0x13, 0x87, 0xed, 0x26,
-	0xdb, 0x7a, 0x23, 0x6f, 0x9d, 0x16, 0x0d, 0x90, 0xb6, 0x0b, 0x5b, 0x52, 0x2c, 0x21, 0x2b, 0xad,
-	0x4b, 0x7b, 0xd1, 0xba, 0x2f, 0xc4, 0x98, 0x1c, 0x53, 0xac, 0xc9, 0x21, 0xcd, 0x19, 0x3b, 0xf6,
-	0x63, 0xd1, 0x87, 0x00, 0x7d, 0xe9, 0x6f, 0xe8, 0x53, 0xff, 0x66, 0x31, 0x33, 0xbc, 0x4a, 0xf6,
-	0x02, 0x1b, 0x20, 0x6f, 0x3c, 0xf7, 0x99, 0x33, 0x67, 0xbe, 0x73, 0x86, 0xb0, 0xed, 0x86, 0x8c,
-	0x79, 0xd1, 0x7e, 0x40, 0x18, 0xc3, 0x2e, 0x19, 0x44, 0x71, 0xc8, 0x43, 0x54, 0x57, 0xdc, 0x9d,
-	0x67, 0x76, 0x18, 0x04, 0x21, 0xdd, 0xb7, 0x43, 0xdf, 0x27, 0x36, 0xf7, 0x42, 0xaa, 0x14, 0x8c,
-	0x7f, 0x69, 0xd0, 0x1c, 0xd3, 0x5b, 0xe2, 0x87, 0x11, 0x41, 0x3a, 0x34, 0x22, 0x7c, 0xef, 0x87,
-	0xd8, 0xd1, 0xb5, 0x5d, 0xed, 0x45, 0xc7, 0x4c, 0x49, 0xf4, 0x09, 0xb4, 0x98, 0xe7, 0x52, 0xcc,
-	0x6f, 0x62, 0xa2, 0xaf, 0x49, 0x59, 0xce, 0x40, 0xaf, 0x61, 0x83, 0x11, 0x3b, 0x26, 0xdc, 0x22,
-	0x89, 0x2b, 0x7d, 0x7d, 0x57, 0x7b, 0xd1, 0x3e, 0x78, 0x3a, 0x50, 0xf1, 0x07, 0xa7, 0x52, 0x9c,
-	0x06, 0x32, 0x7b, 0xac, 0x44, 0x1b, 0x13, 0xe8, 0x95, 0x35, 0x7e, 0xea, 0x52, 0x8c, 0x43, 0xa8,
-	0x2b, 0x4f, 0xe8, 0x25, 0xf4, 0x3d, 0xca, 0x49, 0x4c, 0xb1, 0x3f, 0xa6, 0x4e, 0x14, 0x7a, 0x94,
-	0x4b, 0x57, 0xad, 0x49, 0xc5, 0x5c, 0x91, 0x1c, 0xb5, 0xa0, 0x61, 0x87, 0x94, 0x13, 0xca, 0x8d,
-	0x1f, 0xdb, 0xd0, 0x3d, 0x96, 0xcb, 0x9e, 0xa9, 0x5c, 0xa2, 0x6d, 0xa8, 0xd1, 0x90, 0xda, 0x44,
-	0xda, 0x57, 0x4d, 0x45, 0x88, 0x25, 0xda, 0x0b, 0x4c, 0x29, 0xf1, 0x93, 0x65, 0xa4, 0x24, 0xda,
-	0x83, 0x75, 0x8e, 0x5d, 0x99, 0x83, 0xde, 0xc1, 0x47, 0x69, 0x0e, 0x4a, 0x3e, 0x07, 0x67, 0xd8,
-	0x35, 0x85, 0x16, 0xfa, 0x1a, 0x5a, 0xd8, 0xf7, 0x6e, 0x89, 0x15, 0x30, 0x57, 0xaf, 0xc9, 0xb4,
-	0x6d, 0xa7, 0x26, 0x87, 0x42, 0x90, 0x58, 0x4c, 0x2a, 0x66, 0x53, 0x2a, 0xce, 0x98, 0x8b, 0x7e,
-	0x07, 0x8d, 0x80, 0x04, 0x56, 0x4c, 0xae, 0xf5, 0xba, 0x34, 0xc9, 0xa2, 0xcc, 0x48, 0x70, 0x41,
-	0x62, 0xb6, 0xf0, 0x22, 0x93, 0x5c, 0xdf, 0x10, 0xc6, 0x27, 0x15, 0xb3, 0x1e, 0x90, 0xc0, 0x24,
-	0xd7, 0xe8, 0xf7, 0xa9, 0x15, 0xd3, 0x1b, 0xd2, 0x6a, 0xe7, 0x21, 0x2b, 0x16, 0x85, 0x94, 0x91,
-	0xcc, 0x8c, 0xa1, 0x57, 0xd0, 0x74, 0x30, 0xc7, 0x72, 0x81, 0x4d, 0x69, 0xb7, 0x95, 0xda, 0x8d,
-	0x30, 0xc7, 0xf9, 0xfa, 0x1a, 0x42, 0x4d, 0x2c, 0x6f, 0x0f, 0x6a, 0x0b, 0xe2, 0xfb, 0xa1, 0xde,
-	0x2a, 0xab, 0xab, 0x14, 0x4c, 0x84, 0x68, 0x52, 0x31, 0x95, 0x0e, 0xda, 0x4f, 0xdc, 0x3b, 0x9e,
-	0xab, 0x83, 0xd4, 0x47, 0x45, 0xf7, 0x23, 0xcf, 0x55, 0xbb, 0x90, 0xde, 0x47, 0x9e, 0x9b, 0xad,
-	0x47, 0xec, 0xbe, 0xbd, 0xba, 0x9e, 0x7c, 0xdf, 0xd2, 0x42, 0x6d, 0xbc, 0x2d, 0x2d, 0x6e, 0x22,
-	0x07, 0x73, 0xa2, 0x77, 0x56, 0xa3, 0xbc, 0x93, 0x92, 0x49, 0xc5, 0x04, 0x27, 0xa3, 0xd0, 0x73,
-	0xa8, 0x91, 0x20, 0xe2, 0xf7, 0x7a, 0x57, 0x1a, 0x74, 0x53, 0x83, 0xb1, 0x60, 0x8a, 0x0d, 0x48,
-	0x29, 0xda, 0x83, 0xaa, 0x1d, 0x52, 0xaa, 0xf7, 0xa4, 0xd6, 0x93, 0x54, 0x6b, 0x18, 0x52, 0x3a,
-	0x66, 0x1c, 0x5f, 0xf8, 0x1e, 0x5b, 0x4c, 0x2a, 0xa6, 0x54, 0x42, 0x07, 0x00, 0x8c, 0x63, 0x4e,
-	0x2c, 0x8f, 0x5e, 0x86, 0xfa, 0x86, 0x34, 0xd9, 0xcc, 0xae, 0x89, 0x90, 0x4c, 0xe9, 0xa5, 0xc8,
-	0x4e, 0x8b, 0xa5, 0x04, 0x3a, 0x82, 0x9e, 0xb2, 0x61, 0x14, 0x47, 0x6c, 0x11, 0x72, 0xbd, 0x5f,
-	0x3e, 0xf4, 0xcc, 0xee, 0x34, 0x51, 0x98, 0x54, 0xcc, 0xae, 0x34, 0x49, 0x19, 0x68, 0x06, 0x5b,
-	0x79, 0x5c, 0x2b, 0xba, 0xf1, 0x7d, 0x99, 0xbf, 0x4d, 0xe9, 0xe8, 0x93, 0x15, 0x47, 0x27, 0x37,
-	0xbe, 0x9f, 0x27, 0xb2, 0xcf, 0x96, 0xf8, 0xe8, 0x10, 0x94, 0x7f, 0xe1, 0x44, 0x28, 0xe9, 0xa8,
-	0x5c, 0x50, 0x26, 0x09, 0x42, 0x4e, 0xa4, 0xbb, 0xdc, 0x4d, 0x87, 0x15, 0x68, 0x34, 0x4a, 0x77,
-	0x15, 0x27, 0x25, 0xa7, 0x6f, 0x49, 0x1f, 0x1f, 0x3f, 0xe8, 0x23, 0xab, 0xca, 0x2e, 0x2b, 0x32,
-	0x44, 0x6e, 0x7c, 0x82, 0x1d, 0x55, 0xbc, 0xb2, 0x44, 0xb7, 0xcb, 0xb9, 0x79, 0x93, 0x49, 0xf3,
-	0x42, 0xed, 0xe6, 0x26, 0xa2, 0x5c, 0xbf, 0x85, 0x6e, 0x44, 0x48, 0x6c, 0x79, 0x0e, 0xa1, 0xdc,
-	0xe3, 0xf7, 0xfa, 0x93, 0xf2, 0x35, 0x3c, 0x21, 0x24, 0x9e, 0x26, 0x32, 0xb1, 0x8d, 0xa8, 0x40,
-	0x8b, 0xcb, 0x8e, 0xed, 0x2b, 0xfd, 0xa9, 0x34, 0x79, 0x96, 0xdd, 0x5c, 0xfb, 0x8a, 0x86, 0x3f,
-	0xf8, 0xc4, 0x71, 0x49, 0x40, 0xa8, 0xd8, 0xbc, 0xd0, 0x42, 0x7f, 0x06, 0x88, 0x62, 0xef, 0x56,
-	0x65, 0x41, 0x7f, 0x56, 0x4e, 0xbe, 0xda, 0xef, 0xc9, 0x2d, 0x2f, 0x57, 0x71, 0xc1, 0x02, 0xbd,
-	0x2e, 0xd8, 0x33, 0x5d, 0x97, 0xf6, 0xbf, 0x78, 0xc4, 0x3e, 0xcb, 0x58, 0xc1, 0x04, 0xbd, 0x86,
-	0x4e, 0x42, 0x59, 0xa2, 0xd0, 0xf5, 0x8f, 0xca, 0xc7, 0x76, 0xa2, 0x64, 0xe5, 0x6b, 0xdd, 0x8e,
-	0x72, 0xae, 0x61, 0xc1, 0xfa, 0x19, 0x76, 0x51, 0x17, 0x5a, 0xef, 0xe6, 0xa3, 0xf1, 0x77, 0xd3,
-	0xf9, 0x78, 0xd4, 0xaf, 0xa0, 0x16, 0xd4, 0xc6, 0xb3, 0x93, 0xb3, 0xf3, 0xbe, 0x86, 0x3a, 0xd0,
-	0x7c, 0x6b, 0x1e, 0x5b, 0x6f, 0xe7, 0x6f, 0xce, 0xfb, 0x6b, 0x42, 0x6f, 0x38, 0x39, 0x9c, 0x2b,
-	0x72, 0x1d, 0xf5, 0xa1, 0x23, 0xc9, 0xc3, 0xf9, 0xc8, 0x7a, 0x6b, 0x1e, 0xf7, 0xab, 0x68, 0x03,
-	0xda, 0x4a, 0xc1, 0x94, 0x8c, 0x5a, 0x11, 0x89, 0xff, 0xa7, 0x41, 0x2b, 0xab, 0x48, 0x34, 0x80,
-	0x16, 0xf7, 0x02, 0xc2, 0x38, 0x0e, 0x22, 0x89, 0xb8, 0xed, 0x83, 0x7e, 0xf1, 0x84, 0xce, 0xbc,
-	0x80, 0x98, 0xb9, 0x0a, 0x7a, 0x02, 0xf5, 0xe8, 0xca, 0xb3, 0x3c, 0x47, 0x02, 0x71, 0xc7, 0xac,
-	0x45, 0x57, 0xde, 0xd4, 0x41, 0x9f, 0x41, 0x3b, 0xc1, 0x69, 0x6b, 0x76, 0x38, 0xd4, 0xab, 0x52,
-	0x06, 0x09, 0x6b, 0x76, 0x38, 0x14, 0x37, 0x34, 0x8a, 0xc3, 0x88, 0xc4, 0xdc, 0x23, 0x2c, 0x41,
-	0x64, 0x94, 0x27, 0x28, 0x95, 0x98, 0x05, 0x2d, 0xe3, 0x47, 0x0d, 0x20, 0x17, 0xa1, 0x5f, 0x42,
-	0x57, 0x1e, 0x7d, 0x6c, 0x2d, 0x88, 0xe7, 0x2e, 0x78, 0xd2, 0x38, 0x3a, 0x8a, 0x39, 0x91, 0x3c,
-	0xf4, 0x39, 0x74, 0x7c, 0x72, 0xc9, 0xad, 0x62, 0x13, 0x69, 0x9a, 0x6d, 0xc1, 0x1b, 0x26, 0x8d,
-	0xe4, 0xb7, 0x20, 0x16, 0xe6, 0x51, 0x3b, 0x74, 0x08, 0xd3, 0xd7, 0x77, 0xd7, 0x8b, 0x60, 0x31,
-	0x4c, 0x25, 0x66, 0x41, 0xc9, 0x38, 0x84, 0xcd, 0x15, 0x34, 0x40, 0x2f, 0xa1, 0x49, 0x7c, 0x59,
-	0x88, 0x4c, 0xd7, 0xa4, 0x97, 0x2c, 0x73, 0x59, 0x4f, 0xce, 0x34, 0x8c, 0x3f, 0xc0, 0xf6, 0x43,
-	0x38, 0xb0, 0x9c, 0x39, 0x6d, 0x39, 0x73, 0xc6, 0x25, 0x74, 0x4b, 0xa0, 0x57, 0x38, 0x02, 0xad,
-	0x78, 0x04, 0x3b, 0xd0, 0xcc, 0xae, 0x9a, 0x6a, 0x9d, 0x19, 0x8d, 0x0c, 0xe8, 0x72, 0x9f, 0x59,
-	0x36, 0x89, 0xb9, 0xb5, 0xc0, 0x6c, 0x91, 0x1c, 0x5e, 0x9b, 0xfb, 0x6c, 0x48, 0x62, 0x3e, 0xc1,
-	0x6c, 0x61, 0xbc, 0x83, 0x4e, 0xf1, 0x4a, 0x3e, 0x16, 0x06, 0x41, 0x55, 0xb8, 0x49, 0x42, 0xc8,
-	0x6f, 0x11, 0x3a, 0x20, 0x1c, 0xcb, 0xda, 0x57, 0x9e, 0x33, 0xda, 0x08, 0xa0, 0x5d, 0xb8, 0x79,
-	0x8f, 0x77, 0x7d, 0x47, 0x76, 0x24, 0xa6, 0xaf, 0xed, 0xae, 0x8b, 0xae, 0x9f, 0x90, 0x68, 0x00,
-	0xcd, 0x80, 0xb9, 0x16, 0xbf, 0x4f, 0xc6, 0x9f, 0x5e, 0xde, 0x96, 0x44, 0x16, 0x67, 0xcc, 0x3d,
-	0xbb, 0x8f, 0x88, 0xd9, 0x08, 0xd4, 0x87, 0x11, 0x42, 0xbb, 0xd0, 0x0f, 0x1f, 0x09, 0x57, 0x5c,
-	0xef, 0x5a, 0x79, 0xbd, 0x1f, 0x1c, 0xf0, 0x0e, 0x20, 0x6f, 0x75, 0x8f, 0xc4, 0xfb, 0x15, 0x54,
-	0x93, 0x58, 0x0f, 0x57, 0x49, 0xf5, 0x27, 0x45, 0xf6, 0x55, 0x64, 0xd5, 0xca, 0x7f, 0xf6, 0xc4,
-	0x7e, 0xa3, 0xce, 0x31, 0x9d, 0xde, 0x7e, 0x5d, 0x1e, 0x25, 0xdb, 0x07, 0x1b, 0x99, 0xb5, 0x62,
-	0x67, 0xb3, 0xa5, 0xf1, 0x1d, 0xa0, 0x55, 0x04, 0x44, 0xaf, 0x96, 0x1d, 0x3c, 0x5d, 0x82, 0xcb,
-	0x15, 0x3f, 0xe7, 0xd0, 0x48, 0x78, 0xe8, 0x19, 0x34, 0x18, 0xb9, 0xb6, 0xe8, 0x4d, 0x90, 0x6c,
-	0xb7, 0xce, 0xc8, 0xf5, 0xfc, 0x26, 0x10, 0xd5, 0x59, 0x38, 0x55, 0x95, 0xd7, 0xcf, 0x97, 0xd0,
-	0x79, 0x5d, 0x26, 0xa2, 0x84, 0xbf, 0xff, 0x59, 0x83, 0x5e, 0x39, 0x2c, 0xfa, 0x12, 0x36, 0xf2,
-	0xb9, 0xde, 0xa2, 0x38, 0x50, 0x99, 0x6d, 0x99, 0xbd, 0x9c, 0x3d, 0xc7, 0x01, 0x11, 0xa3, 0xb3,
-	0x90, 0xb2, 0x08, 0xdb, 0x6a, 0x74, 0x6e, 0x99, 0x39, 0x03, 0x6d, 0x41, 0x8d, 0xdf, 0xa5, 0x70,
-	0xd9, 0x32, 0xab, 0xfc, 0x6e, 0xea, 0x08, 0x24, 0x4b, 0x57, 0x14, 0xff, 0xc0, 0x08, 0x4f, 0xf0,
-	0x32, 0x5d, 0xa6, 0x29, 0x78, 0xe8, 0x25, 0xa0, 0x54, 0x89, 0x79, 0x41, 0x8a, 0x79, 0x35, 0xb9,
-	0xdd, 0x7e, 0x22, 0x39, 0xf5, 0x82, 0x04, 0xf7, 0xe6, 0x80, 0x0a, 0xcb, 0xb5, 0x43, 0x7a, 0xe9,
-	0xb9, 0x2c, 0x19, 0x63, 0x3f, 0x1b, 0xa8, 0x87, 0xca, 0x60, 0x98, 0x69, 0x0c, 0xa5, 0xc2, 0x09,
-	0xb6, 0xaf, 0xb0, 0x4b, 0xcc, 0x4d, 0x7b, 0x49, 0xc0, 0x8c, 0x7f, 0x6b, 0xd0, 0x29, 0x0e, 0xca,
-	0x68, 0x00, 0x10, 0x64, 0xf3, 0x6c, 0x72, 0x64, 0xbd, 0xf2, 0xa4, 0x6b, 0x16, 0x34, 0x3e, 0xb8,
-	0xb1, 0x14, 0xe1, 0xab, 0x5a, 0x86, 0x2f, 0xe3, 0x9f, 0x1a, 0x6c, 0xae, 0x4c, 0x1c, 0x8f, 0x01,
-	0xd4, 0x87, 0x06, 0x7e, 0x0e, 0x3d, 0x8f, 0x59, 0x0e, 0xb1, 0x7d, 0x1c, 0x63, 0x91, 0x02, 0x79,
-	0x54, 0x4d, 0xb3, 0xeb, 0xb1, 0x51, 0xce, 0x34, 0xfe, 0x08, 0xcd, 0xd4, 0x5a, 0x94, 0x9f, 0x47,
-	0xed, 0x62, 0xf9, 0x79, 0xd4, 0x16, 0xe5, 0x57, 0xa8, 0xcb, 0xb5, 0x62, 0x5d, 0x1a, 0x97, 0xb0,
-	0xb9, 0xf2, 0x86, 0x40, 0xdf, 0x42, 0x9f, 0x11, 0xff, 0x52, 0x0e, 0x8f, 0x71, 0xa0, 0x62, 0x6b,
-	0xe5, 0x05, 0x67, 0x10, 0xb1, 0x21, 0x34, 0xa7, 0xb9, 0xa2, 0xb8, 0xef, 0x62, 0x18, 0xa2, 0xc9,
-	0xbd, 0x56, 0x84, 0x71, 0x01, 0x68, 0xf5, 0xd5, 0x81, 0xbe, 0x80, 0x9a, 0x7c, 0xe4, 0x3c, 0xda,
-	0xa6, 0x94, 0x58, 0xe2, 0x14, 0xc1, 0xce, 0x7b, 0x70, 0x8a, 0x60, 0xc7, 0xf8, 0x2b, 0xd4, 0x55,
-	0x0c, 0x71, 0x66, 0xa4, 0xf4, 0x0a, 0x34, 0x33, 0xfa, 0xbd, 0x18, 0xfb, 0xf0, 0x10, 0x61, 0x34,
-	0xa0, 0x26, 0x1f, 0x01, 0xc6, 0xdf, 0x00, 0xad, 0x8e, 0xba, 0xa2, 0x89, 0x31, 0x8e, 0x63, 0x6e,
-	0x95, 0xaf, 0x7e, 0x5b, 0x32, 0x4f, 0xd5, 0xfd, 0xff, 0x14, 0xda, 0x84, 0x3a, 0x56, 0xf9, 0x10,
-	0x5a, 0x84, 0x3a, 0x4a, 0x6e, 0x1c, 0xc1, 0xd6, 0x03, 0x03, 0x30, 0xda, 0x83, 0x66, 0x82, 0x32,
-	0x69, 0x2b, 0x5f, 0x81, 0xb3, 0x4c, 0xc1, 0x38, 0x86, 0xed, 0x87, 0x86, 0x4a, 0xb4, 0x9f, 0x63,
-	0xad, 0xf2, 0x91, 0x3d, 0x5a, 0x12, 0x45, 0x85, 0xd4, 0x19, 0x04, 0x1b, 0xff, 0xd5, 0xa0, 0x5b,
-	0x12, 0xe5, 0x68, 0xa1, 0x15, 0xd0, 0xe2, 0xfd, 0x00, 0xf3, 0x29, 0x40, 0x7e, 0x7b, 0x13, 0x94,
-	0x29, 0x70, 0xd0, 0xc7, 0xd0, 0xba, 0xf0, 0x43, 0xfb, 0x4a, 0xe4, 0x44, 0x5e, 0xac, 0xaa, 0xd9,
-	0x94, 0x8c, 0x53, 0x72, 0x8d, 0x76, 0xa1, 0x23, 0x52, 0xe5, 0x51, 0x4b, 0xb2, 0x12, 0x74, 0x01,
-	0x46, 0xae, 0xa7, 0xf4, 0x48, 0x70, 0x8c, 0xef, 0xe1, 0xc9, 0x83, 0x13, 0x30, 0x3a, 0x58, 0x99,
-	0x7e, 0x9e, 0x2e, 0x6d, 0x77, 0xac, 0xc4, 0x85, 0x19, 0xe8, 0x1c, 0x7a, 0x65, 0x19, 0xfa, 0x0a,
-	0xea, 0x2a, 0x1b, 0x49, 0xe1, 0x3f, 0x92, 0xb2, 0x44, 0xa9, 0xf8, 0x03, 0x23, 0x69, 0x67, 0x69,
-	0x73, 0xf8, 0x4b, 0xe6, 0x3a, 0x05, 0xf0, 0xe7, 0xb0, 0xc1, 0xef, 0xac, 0xd2, 0xf6, 0x92, 0x81,
-	0x91, 0xdf, 0x9d, 0x66, 0x1b, 0x2c, 0xbb, 0x2c, 0xfe, 0x13, 0x31, 0xbe, 0x84, 0x8d, 0xa5, 0x07,
-	0x87, 0xb8, 0x74, 0x24, 0x8e, 0xc3, 0x38, 0x39, 0x1f, 0x45, 0x18, 0xef, 0xa0, 0x95, 0x8d, 0x8d,
-	0xa2, 0x03, 0x15, 0x9a, 0x85, 0xfc, 0x16, 0x31, 0x6e, 0x49, 0xcc, 0xc4, 0x01, 0xa9, 0xf3, 0x4b,
-	0xc9, 0xf7, 0x4d, 0x4e, 0xbf, 0xf9, 0x13, 0xb4, 0x0b, 0x9d, 0x78, 0xf9, 0x71, 0xd0, 0x85, 0xd6,
-	0xd1, 0x9b, 0xb7, 0xc3, 0xef, 0xad, 0xd9, 0xe9, 0x71, 0x5f, 0x13, 0x6f, 0x80, 0xe9, 0x68, 0x3c,
-	0x3f, 0x9b, 0x9e, 0x9d, 0x4b, 0xce, 0xda, 0xc1, 0x3f, 0xa0, 0xae, 0x26, 0x21, 0xf4, 0x0d, 0x74,
-	0xd4, 0xd7, 0x29, 0x8f, 0x09, 0x0e, 0xd0, 0xca, 0xc5, 0xde, 0x59, 0xe1, 0x18, 0x95, 0x17, 0xda,
-	0x2b, 0x0d, 0x7d, 0x01, 0xd5, 0x13, 0x8f, 0xba, 0xa8, 0xfc, 0x48, 0xdf, 0x29, 0x93, 0x46, 0xe5,
-	0xe8, 0xab, 0xbf, 0xef, 0xb9, 0x1e, 0x5f, 0xdc, 0x5c, 0x88, 0x4e, 0xb3, 0xbf, 0xb8, 0x8f, 0x48,
-	0xac, 0xa6, 0xf2, 0xfd, 0x4b, 0x7c, 0x11, 0x7b, 0xf6, 0xbe, 0xfc, 0x2f, 0xc6, 0xf6, 0x95, 0xd9,
-	0x45, 0x5d, 0x92, 0x5f, 0xff, 0x3f, 0x00, 0x00, 0xff, 0xff, 0xdd, 0x1d, 0xb3, 0x7e, 0x5f, 0x13,
-	0x00, 0x00,
+func init() { proto.RegisterFile("gossip/message.proto", fileDescriptor_message_981e4d545985bf5a) }
+
+var fileDescriptor_message_981e4d545985bf5a = []byte{
+	// 1983 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x58, 0xcd, 0x53, 0xe4, 0xc6,
+	0x15, 0x1f, 0xc1, 0xcc, 0x30, 0xf3, 0xe6, 0x83, 0xa1, 0x81, 0x5d, 0x19, 0x3b, 0x36, 0x56, 0xb2,
+	0xf6, 0x26, 0xac, 0x61, 0x83, 0x93, 0x8a, 0xab, 0x9c, 0x64, 0x0b, 0x06, 0x96, 0xa1, 0xbc, 0xb0,
+	0x44, 0xb0, 0x95, 0x90, 0x8b, 0xaa, 0x91, 0x7a, 0x34, 0x0a, 0x52, 0x4b, 0xa8, 0x7b, 0x30, 0x1c,
+	0x53, 0xb9, 0xe5, 0x92, 0x6b, 0xae, 0x39, 0xe5, 0x8f, 0xc9, 0x3f, 0x95, 0xea, 0x6e, 0x7d, 0xb4,
+	0x66, 0x60, 0xab, 0x76, 0xab, 0x7c, 0xd3, 0xfb, 0xee, 0x7e, 0xfd, 0xfa, 0xf7, 0x5e, 0x0b, 0xd6,
+	0xfc, 0x98, 0xb1, 0x20, 0xd9, 0x89, 0x08, 0x63, 0xd8, 0x27, 0xdb, 0x49, 0x1a, 0xf3, 0x18, 0x35,
+	0x15, 0x77, 0xe3, 0xa9, 0x1b, 0x47, 0x51, 0x4c, 0x77, 0xdc, 0x38, 0x0c, 0x89, 0xcb, 0x83, 0x98,
+	0x2a, 0x05, 0xeb, 0x1f, 0x06, 0xb4, 0x0e, 0xe9, 0x2d, 0x09, 0xe3, 0x84, 0x20, 0x13, 0x96, 0x12,
+	0x7c, 0x1f, 0xc6, 0xd8, 0x33, 0x8d, 0x4d, 0xe3, 0x79, 0xd7, 0xce, 0x49, 0xf4, 0x19, 0xb4, 0x59,
+	0xe0, 0x53, 0xcc, 0xa7, 0x29, 0x31, 0x17, 0xa4, 0xac, 0x64, 0xa0, 0x57, 0xb0, 0xcc, 0x88, 0x9b,
+	0x12, 0xee, 0x90, 0xcc, 0x95, 0xb9, 0xb8, 0x69, 0x3c, 0xef, 0xec, 0x3e, 0xd9, 0x56, 0xf1, 0xb7,
+	0xcf, 0xa5, 0x38, 0x0f, 0x64, 0xf7, 0x59, 0x85, 0xb6, 0x46, 0xd0, 0xaf, 0x6a, 0x7c, 0xec, 0x52,
+	0xac, 0x3d, 0x68, 0x2a, 0x4f, 0xe8, 0x05, 0x0c, 0x02, 0xca, 0x49, 0x4a, 0x71, 0x78, 0x48, 0xbd,
+	0x24, 0x0e, 0x28, 0x97, 0xae, 0xda, 0xa3, 0x9a, 0x3d, 0x27, 0xd9, 0x6f, 0xc3, 0x92, 0x1b, 0x53,
+	0x4e, 0x28, 0xb7, 0xfe, 0xd7, 0x81, 0xde, 0x91, 0x5c, 0xf6, 0x89, 0xca, 0x25, 0x5a, 0x83, 0x06,
+	0x8d, 0xa9, 0x4b, 0xa4, 0x7d, 0xdd, 0x56, 0x84, 0x58, 0xa2, 0x3b, 0xc1, 0x94, 0x92, 0x30, 0x5b,
+	0x46, 0x4e, 0xa2, 0x2d, 0x58, 0xe4, 0xd8, 0x97, 0x39, 0xe8, 0xef, 0x7e, 0x92, 0xe7, 0xa0, 0xe2,
+	0x73, 0xfb, 0x02, 0xfb, 0xb6, 0xd0, 0x42, 0xdf, 0x42, 0x1b, 0x87, 0xc1, 0x2d, 0x71, 0x22, 0xe6,
+	0x9b, 0x0d, 0x99, 0xb6, 0xb5, 0xdc, 0x64, 0x4f, 0x08, 0x32, 0x8b, 0x51, 0xcd, 0x6e, 0x49, 0xc5,
+	0x13, 0xe6, 0xa3, 0xdf, 0xc0, 0x52, 0x44, 0x22, 0x27, 0x25, 0x37, 0x66, 0x53, 0x9a, 0x14, 0x51,
+	0x4e, 0x48, 0x74, 0x45, 0x52, 0x36, 0x09, 0x12, 0x9b, 0xdc, 0x4c, 0x09, 0xe3, 0xa3, 0x9a, 0xdd,
+	0x8c, 0x48, 0x64, 0x93, 0x1b, 0xf4, 0xdb, 0xdc, 0x8a, 0x99, 0x4b, 0xd2, 0x6a, 0xe3, 0x21, 0x2b,
+	0x96, 0xc4, 0x94, 0x91, 0xc2, 0x8c, 0xa1, 0x97, 0xd0, 0xf2, 0x30, 0xc7, 0x72, 0x81, 0x2d, 0x69,
+	0xb7, 0x9a, 0xdb, 0x1d, 0x60, 0x8e, 0xcb, 0xf5, 0x2d, 0x09, 0x35, 0xb1, 0xbc, 0x2d, 0x68, 0x4c,
+	0x48, 0x18, 0xc6, 0x66, 0xbb, 0xaa, 0xae, 0x52, 0x30, 0x12, 0xa2, 0x51, 0xcd, 0x56, 0x3a, 0x68,
+	0x27, 0x73, 0xef, 0x05, 0xbe, 0x09, 0x52, 0x1f, 0xe9, 0xee, 0x0f, 0x02, 0x5f, 0xed, 0x42, 0x7a,
+	0x3f, 0x08, 0xfc, 0x62, 0x3d, 0x62, 0xf7, 0x9d, 0xf9, 0xf5, 0x94, 0xfb, 0x96, 0x16, 0x6a, 0xe3,
+	0x1d, 0x69, 0x31, 0x4d, 0x3c, 0xcc, 0x89, 0xd9, 0x9d, 0x8f, 0xf2, 0x4e, 0x4a, 0x46, 0x35, 0x1b,
+	0xbc, 0x82, 0x42, 0xcf, 0xa0, 0x41, 0xa2, 0x84, 0xdf, 0x9b, 0x3d, 0x69, 0xd0, 0xcb, 0x0d, 0x0e,
+	0x05, 0x53, 0x6c, 0x40, 0x4a, 0xd1, 0x16, 0xd4, 0xdd, 0x98, 0x52, 0xb3, 0x2f, 0xb5, 0xd6, 0x73,
+	0xad, 0x61, 0x4c, 0xe9, 0x21, 0xe3, 0xf8, 0x2a, 0x0c, 0xd8, 0x64, 0x54, 0xb3, 0xa5, 0x12, 0xda,
+	0x05, 0x60, 0x1c, 0x73, 0xe2, 0x04, 0x74, 0x1c, 0x9b, 0xcb, 0xd2, 0x64, 0xa5, 0xb8, 0x26, 0x42,
+	0x72, 0x4c, 0xc7, 0x22, 0x3b, 0x6d, 0x96, 0x13, 0x68, 0x1f, 0xfa, 0xca, 0x86, 0x51, 0x9c, 0xb0,
+	0x49, 0xcc, 0xcd, 0x41, 0xf5, 0xd0, 0x0b, 0xbb, 0xf3, 0x4c, 0x61, 0x54, 0xb3, 0x7b, 0xd2, 0x24,
+	0x67, 0xa0, 0x13, 0x58, 0x2d, 0xe3, 0x3a, 0xc9, 0x34, 0x0c, 0x65, 0xfe, 0x56, 0xa4, 0xa3, 0xcf,
+	0xe6, 0x1c, 0x9d, 0x4d, 0xc3, 0xb0, 0x4c, 0xe4, 0x80, 0xcd, 0xf0, 0xd1, 0x1e, 0x28, 0xff, 0x4e,
+	0xaa, 0x94, 0x4c, 0x54, 0x2d, 0x28, 0x9b, 0x44, 0x31, 0x27, 0xd2, 0x5d, 0xe9, 0xa6, 0xcb, 0x34,
+	0x1a, 0x1d, 0xe4, 0xbb, 0x4a, 0xb3, 0x92, 0x33, 0x57, 0xa5, 0x8f, 0x4f, 0x1f, 0xf4, 0x51, 0x54,
+	0x65, 0x8f, 0xe9, 0x0c, 0x91, 0x9b, 0x90, 0x60, 0x4f, 0x15, 0xaf, 0x2c, 0xd1, 0xb5, 0x6a, 0x6e,
+	0xde, 0x14, 0xd2, 0xb2, 0x50, 0x7b, 0xa5, 0x89, 0x28, 0xd7, 0xef, 0xa1, 0x97, 0x10, 0x92, 0x3a,
+	0x81, 0x47, 0x28, 0x0f, 0xf8, 0xbd, 0xb9, 0x5e, 0xbd, 0x86, 0x67, 0x84, 0xa4, 0xc7, 0x99, 0x4c,
+	0x6c, 0x23, 0xd1, 0x68, 0x71, 0xd9, 0xb1, 0x7b, 0x6d, 0x3e, 0x91, 0x26, 0x4f, 0x8b, 0x9b, 0xeb,
+	0x5e, 0xd3, 0xf8, 0xc7, 0x90, 0x78, 0x3e, 0x89, 0x08, 0x15, 0x9b, 0x17, 0x5a, 0xe8, 0x8f, 0x00,
+	0x49, 0x1a, 0xdc, 0xaa, 0x2c, 0x98, 0x4f, 0xab, 0xc9, 0x57, 0xfb, 0x3d, 0xbb, 0xe5, 0xd5, 0x2a,
+	0xd6, 0x2c, 0xd0, 0x2b, 0xcd, 0x9e, 0x99, 0xa6, 0xb4, 0xff, 0xd9, 0x23, 0xf6, 0x45, 0xc6, 0x34,
+	0x13, 0xf4, 0x0a, 0xba, 0x19, 0xe5, 0x88, 0x42, 0x37, 0x3f, 0xa9, 0x1e, 0xdb, 0x99, 0x92, 0x55,
+	0xaf, 0x75, 0x27, 0x29, 0xb9, 0x68, 0x1f, 0x06, 0xc9, 0x2d, 0x97, 0xc6, 0x0e, 0x9b, 0x46, 0x11,
+	0x4e, 0xef, 0xcd, 0x8d, 0x2a, 0xd8, 0x67, 0x2b, 0x38, 0x57, 0xd2, 0x51, 0xcd, 0xee, 0x27, 0x15,
+	0x8e, 0xe5, 0xc0, 0xe2, 0x05, 0xf6, 0x51, 0x0f, 0xda, 0xef, 0x4e, 0x0f, 0x0e, 0x5f, 0x1f, 0x9f,
+	0x1e, 0x1e, 0x0c, 0x6a, 0xa8, 0x0d, 0x8d, 0xc3, 0x93, 0xb3, 0x8b, 0xcb, 0x81, 0x81, 0xba, 0xd0,
+	0x7a, 0x6b, 0x1f, 0x39, 0x6f, 0x4f, 0xdf, 0x5c, 0x0e, 0x16, 0x84, 0xde, 0x70, 0xb4, 0x77, 0xaa,
+	0xc8, 0x45, 0x34, 0x80, 0xae, 0x24, 0xf7, 0x4e, 0x0f, 0x9c, 0xb7, 0xf6, 0xd1, 0xa0, 0x8e, 0x96,
+	0xa1, 0xa3, 0x14, 0x6c, 0xc9, 0x68, 0xe8, 0x68, 0xfe, 0x5f, 0x03, 0xda, 0x45, 0x55, 0xa3, 0x6d,
+	0x68, 0xf3, 0x20, 0x22, 0x8c, 0xe3, 0x28, 0x91, 0xa8, 0xdd, 0xd9, 0x1d, 0xe8, 0xa7, 0x7c, 0x11,
+	0x44, 0xc4, 0x2e, 0x55, 0xd0, 0x3a, 0x34, 0x93, 0xeb, 0xc0, 0x09, 0x3c, 0x09, 0xe6, 0x5d, 0xbb,
+	0x91, 0x5c, 0x07, 0xc7, 0x1e, 0xfa, 0x02, 0x3a, 0x19, 0xd6, 0x3b, 0x27, 0x7b, 0x43, 0xb3, 0x2e,
+	0x65, 0x90, 0xb1, 0x4e, 0xf6, 0x86, 0xe2, 0x96, 0x27, 0x69, 0x9c, 0x90, 0x94, 0x07, 0x84, 0x99,
+	0x8d, 0x2a, 0xde, 0x9c, 0x15, 0x12, 0x5b, 0xd3, 0xb2, 0xfe, 0x63, 0x00, 0x94, 0x22, 0xf4, 0x73,
+	0xe8, 0xc9, 0xf2, 0x49, 0x9d, 0x09, 0x09, 0xfc, 0x09, 0xcf, 0x9a, 0x4f, 0x57, 0x31, 0x47, 0x92,
+	0x87, 0xbe, 0x84, 0x6e, 0x48, 0xc6, 0xdc, 0xd1, 0x1b, 0x51, 0xcb, 0xee, 0x08, 0xde, 0x50, 0xb1,
+	0xd0, 0xaf, 0x41, 0x2c, 0x2c, 0xa0, 0x6e, 0xec, 0x11, 0x66, 0x2e, 0x6e, 0x2e, 0xea, 0x80, 0x33,
+	0xcc, 0x25, 0xb6, 0xa6, 0x24, 0x3a, 0x1b, 0x4e, 0xdd, 0x49, 0x70, 0x4b, 0xe4, 0xd6, 0x5a, 0x76,
+	0x4e, 0x5a, 0x7b, 0xb0, 0x32, 0x87, 0x35, 0xe8, 0x05, 0xb4, 0x48, 0x28, 0xcb, 0x9c, 0x99, 0xc6,
+	0xe6, 0xa2, 0x9e, 0xd3, 0xa2, 0xe3, 0x17, 0x1a, 0xd6, 0xef, 0x60, 0xed, 0x21, 0x94, 0x99, 0xcd,
+	0xa9, 0x31, 0x9b, 0x53, 0x6b, 0x0c, 0xbd, 0x0a, 0xa4, 0x6a, 0x87, 0x63, 0xe8, 0x87, 0xb3, 0x01,
+	0xad, 0xe2, 0x22, 0xab, 0xc6, 0x5c, 0xd0, 0xc8, 0x82, 0x1e, 0x0f, 0x99, 0xe3, 0x92, 0x94, 0x3b,
+	0x13, 0xcc, 0x26, 0xd9, 0xb1, 0x76, 0x78, 0xc8, 0x86, 0x24, 0xe5, 0x23, 0xcc, 0x26, 0xd6, 0x3b,
+	0xe8, 0xea, 0x17, 0xfe, 0xb1, 0x30, 0x08, 0xea, 0xc2, 0x4d, 0x16, 0x42, 0x7e, 0x8b, 0xd0, 0x11,
+	0xe1, 0x58, 0xde, 0x2c, 0xe5, 0xb9, 0xa0, 0xad, 0x08, 0x3a, 0xda, 0xbd, 0x7e, 0x7c, 0xa6, 0xf0,
+	0x64, 0xbf, 0x63, 0xe6, 0xc2, 0xe6, 0xa2, 0x98, 0x29, 0x32, 0x12, 0x6d, 0x43, 0x2b, 0x62, 0xbe,
+	0xc3, 0xef, 0xb3, 0xe1, 0xaa, 0x5f, 0x36, 0x3d, 0x91, 0xc5, 0x13, 0xe6, 0x5f, 0xdc, 0x27, 0xc4,
+	0x5e, 0x8a, 0xd4, 0x87, 0x15, 0x43, 0x47, 0xeb, 0xb6, 0x8f, 0x84, 0xd3, 0xd7, 0xbb, 0x50, 0x5d,
+	0xef, 0x07, 0x07, 0xbc, 0x03, 0x28, 0x1b, 0xe9, 0x23, 0xf1, 0x7e, 0x01, 0xf5, 0x2c, 0xd6, 0xc3,
+	0x55, 0x52, 0xff, 0xa8, 0xc8, 0x21, 0x40, 0x39, 0x28, 0xfc, 0xe4, 0x89, 0xfd, 0x0e, 0x3a, 0x1a,
+	0x3c, 0xa2, 0x5f, 0x56, 0x07, 0xd5, 0xce, 0xee, 0x72, 0x61, 0xad, 0xd8, 0xc5, 0xe4, 0x6a, 0xbd,
+	0x06, 0x34, 0x8f, 0xaf, 0xe8, 0xe5, 0xac, 0x83, 0x27, 0x33, 0x60, 0x3c, 0xe7, 0xe7, 0x12, 0x96,
+	0x32, 0x1e, 0x7a, 0x0a, 0x4b, 0x8c, 0xdc, 0x38, 0x74, 0x1a, 0x65, 0xdb, 0x6d, 0x32, 0x72, 0x73,
+	0x3a, 0x8d, 0x44, 0x75, 0x6a, 0xa7, 0x2a, 0xbf, 0x05, 0x58, 0x54, 0xb0, 0x7f, 0x51, 0x26, 0x42,
+	0x47, 0x77, 0xeb, 0x5f, 0x0b, 0xd0, 0xaf, 0x86, 0x45, 0x5f, 0xc3, 0x72, 0xf9, 0x6a, 0x70, 0x28,
+	0x8e, 0x54, 0x66, 0xdb, 0x76, 0xbf, 0x64, 0x9f, 0xe2, 0x88, 0x88, 0xc1, 0x5c, 0x48, 0x59, 0x82,
+	0x5d, 0x35, 0x98, 0xb7, 0xed, 0x92, 0x81, 0x56, 0xa1, 0xc1, 0xef, 0x72, 0x20, 0x6d, 0xdb, 0x75,
+	0x7e, 0x77, 0xec, 0x09, 0x8c, 0xcb, 0x57, 0x94, 0xfe, 0xc8, 0x08, 0xcf, 0x90, 0x34, 0x5f, 0xa6,
+	0x2d, 0x78, 0xe8, 0x05, 0xa0, 0x5c, 0x89, 0x05, 0x51, 0x8e, 0x86, 0x0d, 0xb9, 0xdd, 0x41, 0x26,
+	0x39, 0x0f, 0xa2, 0x0c, 0x11, 0x4f, 0x01, 0x69, 0xcb, 0x75, 0x63, 0x3a, 0x0e, 0x7c, 0x96, 0x0d,
+	0xc9, 0x5f, 0x6c, 0xab, 0x67, 0xd0, 0xf6, 0xb0, 0xd0, 0x18, 0x4a, 0x85, 0x33, 0xec, 0x5e, 0x63,
+	0x9f, 0xd8, 0x2b, 0xee, 0x8c, 0x80, 0x59, 0xff, 0x34, 0xa0, 0xab, 0x8f, 0xe1, 0x68, 0x1b, 0x20,
+	0x2a, 0xa6, 0xe5, 0xec, 0xc8, 0xfa, 0xd5, 0x39, 0xda, 0xd6, 0x34, 0x3e, 0xb8, 0xe5, 0xe8, 0xf0,
+	0x55, 0xaf, 0xc2, 0x97, 0xf5, 0x77, 0x03, 0x56, 0xe6, 0xe6, 0x99, 0xc7, 0x00, 0xea, 0x43, 0x03,
+	0x3f, 0x83, 0x7e, 0xc0, 0x1c, 0x8f, 0xb8, 0x21, 0x4e, 0xb1, 0x48, 0x81, 0x3c, 0xaa, 0x96, 0xdd,
+	0x0b, 0xd8, 0x41, 0xc9, 0xb4, 0x7e, 0x0f, 0xad, 0xdc, 0x5a, 0x94, 0x5f, 0x40, 0x5d, 0xbd, 0xfc,
+	0x02, 0xea, 0x8a, 0xf2, 0xd3, 0xea, 0x72, 0x41, 0xaf, 0x4b, 0x6b, 0x0c, 0x2b, 0x73, 0x2f, 0x14,
+	0xf4, 0x3d, 0x0c, 0x18, 0x09, 0xc7, 0x72, 0x34, 0x4d, 0x23, 0x15, 0xdb, 0xd8, 0x34, 0x1e, 0x84,
+	0x88, 0x65, 0xa1, 0x79, 0x5c, 0x2a, 0x8a, 0xfb, 0x2e, 0x46, 0x2d, 0x9a, 0xdd, 0x6b, 0x45, 0x58,
+	0x57, 0x80, 0xe6, 0xdf, 0x34, 0xe8, 0x2b, 0x68, 0xc8, 0x27, 0xd4, 0xa3, 0x6d, 0x4a, 0x89, 0x25,
+	0x4e, 0x11, 0xec, 0xbd, 0x07, 0xa7, 0x08, 0xf6, 0xac, 0x3f, 0x43, 0x53, 0xc5, 0x10, 0x67, 0x46,
+	0x2a, 0x6f, 0x4c, 0xbb, 0xa0, 0xdf, 0x8b, 0xb1, 0x0f, 0x8f, 0x17, 0xd6, 0x12, 0x34, 0xe4, 0x13,
+	0xc3, 0xfa, 0x0b, 0xa0, 0xf9, 0x41, 0x5a, 0x34, 0x31, 0xc6, 0x71, 0xca, 0x9d, 0xea, 0xd5, 0xef,
+	0x48, 0xe6, 0xb9, 0xba, 0xff, 0x9f, 0x43, 0x87, 0x50, 0xcf, 0xa9, 0x1e, 0x42, 0x9b, 0x50, 0x4f,
+	0xc9, 0xad, 0x7d, 0x58, 0x7d, 0x60, 0xbc, 0x46, 0x5b, 0xd0, 0xca, 0x50, 0x26, 0x6f, 0xe5, 0x73,
+	0x70, 0x56, 0x28, 0x58, 0x47, 0xb0, 0xf6, 0xd0, 0xc8, 0x8a, 0x76, 0x4a, 0xac, 0x55, 0x3e, 0xd6,
+	0x67, 0x26, 0x43, 0x85, 0xd4, 0x05, 0x04, 0x8b, 0xc9, 0xa7, 0x57, 0x11, 0x95, 0x68, 0x61, 0x68,
+	0x68, 0xf1, 0x7e, 0x80, 0xf9, 0x1c, 0xa0, 0xbc, 0xbd, 0x19, 0xca, 0x68, 0x1c, 0xf4, 0x29, 0xb4,
+	0xaf, 0xc2, 0xd8, 0xbd, 0x16, 0x39, 0x91, 0x17, 0xab, 0x6e, 0xb7, 0x24, 0xe3, 0x9c, 0xdc, 0xa0,
+	0x4d, 0xe8, 0x8a, 0x54, 0x05, 0xd4, 0x91, 0xac, 0x0c, 0x5d, 0x80, 0x91, 0x9b, 0x63, 0xba, 0x2f,
+	0x38, 0xd6, 0x0f, 0xb0, 0xfe, 0xe0, 0x7c, 0x8d, 0x76, 0xe7, 0xa6, 0x9f, 0xd9, 0x41, 0xf8, 0x50,
+	0x89, 0xb5, 0x19, 0xe8, 0x12, 0xfa, 0x55, 0x19, 0xfa, 0x06, 0x9a, 0x2a, 0x1b, 0x59, 0xe1, 0x3f,
+	0x92, 0xb2, 0x4c, 0x49, 0xff, 0x3d, 0x92, 0xb5, 0xb3, 0x8c, 0xb4, 0xfe, 0x6d, 0x14, 0xbe, 0xb3,
+	0x71, 0x5b, 0x4c, 0x56, 0xaa, 0x5e, 0xd4, 0xde, 0x8c, 0x6c, 0x6f, 0x82, 0x25, 0xf7, 0x26, 0x52,
+	0x23, 0x8a, 0x45, 0x89, 0x55, 0xa9, 0x88, 0xfa, 0x55, 0xc2, 0x2f, 0xa1, 0x7b, 0x15, 0xc6, 0x71,
+	0xe4, 0x8c, 0x83, 0x90, 0x93, 0x34, 0x9f, 0x98, 0x24, 0xef, 0xb5, 0x64, 0x09, 0xe4, 0x10, 0xc3,
+	0x94, 0x33, 0x9e, 0x52, 0x99, 0x6b, 0x26, 0xf3, 0xdb, 0xb3, 0x7b, 0x82, 0xfb, 0x3a, 0x67, 0x5a,
+	0x7f, 0x2a, 0x56, 0x96, 0xf7, 0x96, 0x67, 0xb0, 0xcc, 0xef, 0x9c, 0x4a, 0xe6, 0xb3, 0x29, 0x97,
+	0xdf, 0x9d, 0x17, 0xb9, 0xaf, 0xee, 0x56, 0xff, 0x19, 0x64, 0x7d, 0x0d, 0xcb, 0x33, 0x2f, 0x2d,
+	0x81, 0x07, 0x24, 0x4d, 0xe3, 0x34, 0x2b, 0x1d, 0x45, 0x58, 0xef, 0xa0, 0x5d, 0xcc, 0xba, 0xa2,
+	0x39, 0x6a, 0x7d, 0x4c, 0x7e, 0x8b, 0x18, 0xb7, 0x24, 0x65, 0xa2, 0x76, 0x54, 0x69, 0xe5, 0xe4,
+	0xfb, 0x86, 0xba, 0x5f, 0xfd, 0x01, 0x3a, 0xda, 0x90, 0x30, 0xfb, 0xa2, 0xe9, 0x41, 0x7b, 0xff,
+	0xcd, 0xdb, 0xe1, 0x0f, 0xce, 0xc9, 0xf9, 0xd1, 0xc0, 0x10, 0x0f, 0x97, 0xe3, 0x83, 0xc3, 0xd3,
+	0x8b, 0xe3, 0x8b, 0x4b, 0xc9, 0x59, 0xd8, 0xfd, 0x1b, 0x34, 0xd5, 0x90, 0x86, 0xbe, 0x83, 0xae,
+	0xfa, 0x3a, 0xe7, 0x29, 0xc1, 0x11, 0x9a, 0xc3, 0x9c, 0x8d, 0x39, 0x8e, 0x55, 0x7b, 0x6e, 0xbc,
+	0x34, 0xd0, 0x57, 0x50, 0x3f, 0x0b, 0xa8, 0x8f, 0xaa, 0x7f, 0x27, 0x36, 0xaa, 0xa4, 0x55, 0xdb,
+	0xff, 0xe6, 0xaf, 0x5b, 0x7e, 0xc0, 0x27, 0xd3, 0x2b, 0xd1, 0x04, 0x77, 0x26, 0xf7, 0x09, 0x49,
+	0xd5, 0x53, 0x62, 0x67, 0x8c, 0xaf, 0xd2, 0xc0, 0xdd, 0x91, 0x3f, 0x04, 0xd9, 0x8e, 0x32, 0xbb,
+	0x6a, 0x4a, 0xf2, 0xdb, 0xff, 0x0f, 0x00, 0x58, 0x40, 0xd4, 0x1a, 0x58, 0x14, 0x00, 0x00,
 }
@@ -44,7 +44,7 @@ func (x SeekInfo_SeekBehavior) String() string {
 	return proto.EnumName(SeekInfo_SeekBehavior_name, int32(x))
 }
 func (SeekInfo_SeekBehavior) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_ab_86effae0ebc2388c, []int{5, 0}
+	return fileDescriptor_ab_c00ad9ee50780233, []int{7, 0}
 }
 
 type BroadcastResponse struct {
@@ -61,7 +61,7 @@ func (m *BroadcastResponse) Reset()         { *m = BroadcastResponse{} }
 func (m *BroadcastResponse) String() string { return proto.CompactTextString(m) }
 func (*BroadcastResponse) ProtoMessage()    {}
 func (*BroadcastResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ab_86effae0ebc2388c, []int{0}
+	return fileDescriptor_ab_c00ad9ee50780233, []int{0}
 }
 func (m *BroadcastResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BroadcastResponse.Unmarshal(m, b)
@@ -105,7 +105,7 @@ func (m *SeekNewest) Reset()         { *m = SeekNewest{} }
 func (m *SeekNewest) String() string { return proto.CompactTextString(m) }
 func (*SeekNewest) ProtoMessage()    {}
 func (*SeekNewest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ab_86effae0ebc2388c, []int{1}
+	return fileDescriptor_ab_c00ad9ee50780233, []int{1}
 }
 func (m *SeekNewest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SeekNewest.Unmarshal(m, b)
@@ -135,7 +135,7 @@ func (m *SeekOldest) Reset()         { *m = SeekOldest{} }
 func (m *SeekOldest) String() string { return proto.CompactTextString(m) }
 func (*SeekOldest) ProtoMessage()    {}
 func (*SeekOldest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ab_86effae0ebc2388c, []int{2}
+	return fileDescriptor_ab_c00ad9ee50780233, []int{2}
 }
 func (m *SeekOldest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SeekOldest.Unmarshal(m, b)
@@ -166,7 +166,7 @@ func (m *SeekSpecified) Reset()         { *m = SeekSpecified{} }
 func (m *SeekSpecified) String() string { return proto.CompactTextString(m) }
 func (*SeekSpecified) ProtoMessage()    {}
 func (*SeekSpecified) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ab_86effae0ebc2388c, []int{3}
+	return fileDescriptor_ab_c00ad9ee50780233, []int{3}
 }
 func (m *SeekSpecified) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SeekSpecified.Unmarshal(m, b)
@@ -193,11 +193,52 @@ func (m *SeekSpecified) GetNumber() uint64 {
 	return 0
 }
 
+// SeekCheckpoint resumes a deliver from the checkpoint token
+// returned along with a previously delivered block
+type SeekCheckpoint struct {
+	Token                []byte   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SeekCheckpoint) Reset()         { *m = SeekCheckpoint{} }
+func (m *SeekCheckpoint) String() string { return proto.CompactTextString(m) }
+func (*SeekCheckpoint) ProtoMessage()    {}
+func (*SeekCheckpoint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ab_c00ad9ee50780233, []int{4}
+}
+func (m *SeekCheckpoint) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SeekCheckpoint.Unmarshal(m, b)
+}
+func (m *SeekCheckpoint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SeekCheckpoint.Marshal(b, m, deterministic)
+}
+func (dst *SeekCheckpoint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SeekCheckpoint.Merge(dst, src)
+}
+func (m *SeekCheckpoint) XXX_Size() int {
+	return xxx_messageInfo_SeekCheckpoint.Size(m)
+}
+func (m *SeekCheckpoint) XXX_DiscardUnknown() {
+	xxx_messageInfo_SeekCheckpoint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SeekCheckpoint proto.InternalMessageInfo
+
+func (m *SeekCheckpoint) GetToken() []byte {
+	if m != nil {
+		return m.Token
+	}
+	return nil
+}
+
 type SeekPosition struct {
 	// Types that are valid to be assigned to Type:
 	//	*SeekPosition_Newest
 	//	*SeekPosition_Oldest
 	//	*SeekPosition_Specified
+	//	*SeekPosition_Checkpoint
 	Type                 isSeekPosition_Type `protobuf_oneof:"Type"`
 	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
 	XXX_unrecognized     []byte              `json:"-"`
@@ -208,7 +249,7 @@ func (m *SeekPosition) Reset()         { *m = SeekPosition{} }
 func (m *SeekPosition) String() string { return proto.CompactTextString(m) }
 func (*SeekPosition) ProtoMessage()    {}
 func (*SeekPosition) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ab_86effae0ebc2388c, []int{4}
+	return fileDescriptor_ab_c00ad9ee50780233, []int{5}
 }
 func (m *SeekPosition) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SeekPosition.Unmarshal(m, b)
@@ -241,10 +282,14 @@ type SeekPosition_Oldest struct {
 type SeekPosition_Specified struct {
 	Specified *SeekSpecified `protobuf:"bytes,3,opt,name=specified,oneof"`
 }
+type SeekPosition_Checkpoint struct {
+	Checkpoint *SeekCheckpoint `protobuf:"bytes,4,opt,name=checkpoint,oneof"`
+}
 
-func (*SeekPosition_Newest) isSeekPosition_Type()    {}
-func (*SeekPosition_Oldest) isSeekPosition_Type()    {}
-func (*SeekPosition_Specified) isSeekPosition_Type() {}
+func (*SeekPosition_Newest) isSeekPosition_Type()     {}
+func (*SeekPosition_Oldest) isSeekPosition_Type()     {}
+func (*SeekPosition_Specified) isSeekPosition_Type()  {}
+func (*SeekPosition_Checkpoint) isSeekPosition_Type() {}
 
 func (m *SeekPosition) GetType() isSeekPosition_Type {
 	if m != nil {
@@ -274,12 +319,20 @@ func (m *SeekPosition) GetSpecified() *SeekSpecified {
 	return nil
 }
 
+func (m *SeekPosition) GetCheckpoint() *SeekCheckpoint {
+	if x, ok := m.GetType().(*SeekPosition_Checkpoint); ok {
+		return x.Checkpoint
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*SeekPosition) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _SeekPosition_OneofMarshaler, _SeekPosition_OneofUnmarshaler, _SeekPosition_OneofSizer, []interface{}{
 		(*SeekPosition_Newest)(nil),
 		(*SeekPosition_Oldest)(nil),
 		(*SeekPosition_Specified)(nil),
+		(*SeekPosition_Checkpoint)(nil),
 	}
 }
 
@@ -302,6 +355,11 @@ func _SeekPosition_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.Specified); err != nil {
 			return err
 		}
+	case *SeekPosition_Checkpoint:
+		b.EncodeVarint(4<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Checkpoint); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("SeekPosition.Type has unexpected type %T", x)
@@ -336,6 +394,14 @@ func _SeekPosition_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.B
 		err := b.DecodeMessage(msg)
 		m.Type = &SeekPosition_Specified{msg}
 		return true, err
+	case 4: // Type.checkpoint
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(SeekCheckpoint)
+		err := b.DecodeMessage(msg)
+		m.Type = &SeekPosition_Checkpoint{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -360,6 +426,11 @@ func _SeekPosition_OneofSizer(msg proto.Message) (n int) {
 		n += 1 // tag and wire
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *SeekPosition_Checkpoint:
+		s := proto.Size(x.Checkpoint)
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -367,6 +438,54 @@ func _SeekPosition_OneofSizer(msg proto.Message) (n int) {
 	return n
 }
 
+// DeliverCheckpoint is the content of an opaque checkpoint token, it records
+// the position a deliver should resume from on the given channel
+type DeliverCheckpoint struct {
+	ChannelId            string   `protobuf:"bytes,1,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+	BlockNumber          uint64   `protobuf:"varint,2,opt,name=block_number,json=blockNumber" json:"block_number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeliverCheckpoint) Reset()         { *m = DeliverCheckpoint{} }
+func (m *DeliverCheckpoint) String() string { return proto.CompactTextString(m) }
+func (*DeliverCheckpoint) ProtoMessage()    {}
+func (*DeliverCheckpoint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ab_c00ad9ee50780233, []int{6}
+}
+func (m *DeliverCheckpoint) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeliverCheckpoint.Unmarshal(m, b)
+}
+func (m *DeliverCheckpoint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeliverCheckpoint.Marshal(b, m, deterministic)
+}
+func (dst *DeliverCheckpoint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeliverCheckpoint.Merge(dst, src)
+}
+func (m *DeliverCheckpoint) XXX_Size() int {
+	return xxx_messageInfo_DeliverCheckpoint.Size(m)
+}
+func (m *DeliverCheckpoint) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeliverCheckpoint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeliverCheckpoint proto.InternalMessageInfo
+
+func (m *DeliverCheckpoint) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *DeliverCheckpoint) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
 // SeekInfo specifies the range of requested blocks to return
 // If the start position is not found, an error is immediately returned
 // Otherwise, blocks are returned until a missing block is encountered, then behavior is dictated
@@ -388,7 +507,7 @@ func (m *SeekInfo) Reset()         { *m = SeekInfo{} }
 func (m *SeekInfo) String() string { return proto.CompactTextString(m) }
 func (*SeekInfo) ProtoMessage()    {}
 func (*SeekInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ab_86effae0ebc2388c, []int{5}
+	return fileDescriptor_ab_c00ad9ee50780233, []int{7}
 }
 func (m *SeekInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SeekInfo.Unmarshal(m, b)
@@ -443,7 +562,7 @@ func (m *DeliverResponse) Reset()         { *m = DeliverResponse{} }
 func (m *DeliverResponse) String() string { return proto.CompactTextString(m) }
 func (*DeliverResponse) ProtoMessage()    {}
 func (*DeliverResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ab_86effae0ebc2388c, []int{6}
+	return fileDescriptor_ab_c00ad9ee50780233, []int{8}
 }
 func (m *DeliverResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeliverResponse.Unmarshal(m, b)
@@ -572,7 +691,9 @@ func init() {
 	proto.RegisterType((*SeekNewest)(nil), "orderer.SeekNewest")
 	proto.RegisterType((*SeekOldest)(nil), "orderer.SeekOldest")
 	proto.RegisterType((*SeekSpecified)(nil), "orderer.SeekSpecified")
+	proto.RegisterType((*SeekCheckpoint)(nil), "orderer.SeekCheckpoint")
 	proto.RegisterType((*SeekPosition)(nil), "orderer.SeekPosition")
+	proto.RegisterType((*DeliverCheckpoint)(nil), "orderer.DeliverCheckpoint")
 	proto.RegisterType((*SeekInfo)(nil), "orderer.SeekInfo")
 	proto.RegisterType((*DeliverResponse)(nil), "orderer.DeliverResponse")
 	proto.RegisterEnum("orderer.SeekInfo_SeekBehavior", SeekInfo_SeekBehavior_name, SeekInfo_SeekBehavior_value)
@@ -751,40 +872,45 @@ var _AtomicBroadcast_serviceDesc = grpc.ServiceDesc{
 	Metadata: "orderer/ab.proto",
 }
 
-func init() { proto.RegisterFile("orderer/ab.proto", fileDescriptor_ab_86effae0ebc2388c) }
-
-var fileDescriptor_ab_86effae0ebc2388c = []byte{
-	// 505 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x93, 0xdf, 0x6e, 0x12, 0x51,
-	0x10, 0xc6, 0x59, 0xa5, 0xb4, 0x8c, 0x2d, 0xa5, 0xa7, 0x69, 0x43, 0xb8, 0x30, 0xcd, 0x26, 0x55,
-	0x8c, 0xba, 0x6b, 0x30, 0xf1, 0x42, 0x4d, 0x0c, 0x6b, 0xdb, 0x40, 0x24, 0x60, 0x16, 0x7a, 0xa1,
-	0x37, 0x64, 0x77, 0x19, 0x60, 0xed, 0xb2, 0x67, 0x73, 0xce, 0x01, 0xd3, 0xa7, 0xf0, 0x45, 0x7c,
-	0x24, 0x1f, 0xc6, 0x9c, 0x3f, 0xbb, 0x48, 0xd5, 0x5e, 0xb1, 0x33, 0xf3, 0xfb, 0xce, 0x7c, 0x33,
-	0x19, 0xa0, 0x4e, 0xd9, 0x14, 0x19, 0x32, 0x37, 0x08, 0x9d, 0x8c, 0x51, 0x41, 0xc9, 0xae, 0xc9,
-	0x34, 0x8f, 0x23, 0xba, 0x5c, 0xd2, 0xd4, 0xd5, 0x3f, 0xba, 0x6a, 0x0f, 0xe1, 0xc8, 0x63, 0x34,
-	0x98, 0x46, 0x01, 0x17, 0x3e, 0xf2, 0x8c, 0xa6, 0x1c, 0xc9, 0x13, 0xa8, 0x70, 0x11, 0x88, 0x15,
-	0x6f, 0x58, 0x67, 0x56, 0xab, 0xd6, 0xae, 0x39, 0x46, 0x33, 0x52, 0x59, 0xdf, 0x54, 0x09, 0x81,
-	0x72, 0x9c, 0xce, 0x68, 0xe3, 0xc1, 0x99, 0xd5, 0xaa, 0xfa, 0xea, 0xdb, 0xde, 0x07, 0x18, 0x21,
-	0xde, 0x0c, 0xf0, 0x3b, 0x72, 0x91, 0x47, 0xc3, 0x64, 0x2a, 0xa3, 0xa7, 0x70, 0x20, 0xa3, 0x51,
-	0x86, 0x51, 0x3c, 0x8b, 0x71, 0x4a, 0x4e, 0xa1, 0x92, 0xae, 0x96, 0x21, 0x32, 0xd5, 0xa8, 0xec,
-	0x9b, 0xc8, 0xfe, 0x69, 0xc1, 0xbe, 0x24, 0x3f, 0x53, 0x1e, 0x8b, 0x98, 0xa6, 0xe4, 0x25, 0x54,
-	0x52, 0xf5, 0xa2, 0x02, 0x1f, 0xb5, 0x8f, 0x1d, 0x33, 0x95, 0xb3, 0x69, 0xd6, 0x2d, 0xf9, 0x06,
-	0x92, 0x38, 0x55, 0x2d, 0x95, 0xb5, 0xbb, 0xb8, 0x76, 0x23, 0x71, 0x0d, 0x91, 0x37, 0x50, 0xe5,
-	0xb9, 0xa7, 0xc6, 0x43, 0xa5, 0x38, 0xdd, 0x52, 0x14, 0x8e, 0xbb, 0x25, 0x7f, 0x83, 0x7a, 0x15,
-	0x28, 0x8f, 0x6f, 0x33, 0xb4, 0x7f, 0x59, 0xb0, 0x27, 0xb1, 0x5e, 0x3a, 0xa3, 0xe4, 0x39, 0xec,
-	0x70, 0x11, 0xb0, 0xdc, 0xe9, 0xc9, 0xd6, 0x43, 0xf9, 0x40, 0xbe, 0x66, 0xc8, 0x33, 0x28, 0x73,
-	0x41, 0x33, 0x63, 0xf3, 0x3f, 0xac, 0x42, 0xc8, 0x5b, 0xd8, 0x0b, 0x71, 0x11, 0xac, 0x63, 0xca,
-	0x94, 0xc7, 0x5a, 0xfb, 0xf1, 0x16, 0x2e, 0x9b, 0xab, 0x0f, 0xcf, 0x50, 0x7e, 0xc1, 0xdb, 0xef,
-	0xf5, 0x3a, 0xf3, 0x0a, 0x39, 0x81, 0x23, 0xaf, 0x3f, 0xfc, 0xf8, 0x69, 0x72, 0x3d, 0x18, 0xf7,
-	0xfa, 0x13, 0xff, 0xb2, 0x73, 0xf1, 0xa5, 0x5e, 0x92, 0xe9, 0xab, 0x4e, 0xaf, 0x3f, 0xe9, 0x5d,
-	0x4d, 0x06, 0xc3, 0xb1, 0x49, 0x5b, 0xf6, 0x37, 0x38, 0xbc, 0xc0, 0x24, 0x5e, 0x23, 0x2b, 0x2e,
-	0xa4, 0x75, 0xff, 0x85, 0xc8, 0xdd, 0x9a, 0x1b, 0x39, 0x87, 0x9d, 0x30, 0xa1, 0xd1, 0x8d, 0x19,
-	0xf1, 0x20, 0x07, 0x3d, 0x99, 0xec, 0x96, 0x7c, 0x5d, 0xcd, 0x57, 0xd9, 0xfe, 0x61, 0xc1, 0x61,
-	0x47, 0xd0, 0x65, 0x1c, 0x15, 0x67, 0x49, 0x3e, 0x40, 0x75, 0x13, 0xd4, 0xf3, 0x07, 0x2e, 0xd3,
-	0x35, 0x26, 0x34, 0xc3, 0x66, 0xb3, 0x58, 0xc3, 0x5f, 0x97, 0x6c, 0x97, 0x5a, 0xd6, 0x2b, 0x8b,
-	0xbc, 0x83, 0x5d, 0x33, 0xc0, 0x3f, 0xe4, 0x8d, 0x42, 0x7e, 0x67, 0x48, 0x2d, 0xf6, 0xae, 0xe1,
-	0x9c, 0xb2, 0xb9, 0xb3, 0xb8, 0xcd, 0x90, 0x25, 0x38, 0x9d, 0x23, 0x73, 0x66, 0x41, 0xc8, 0xe2,
-	0x48, 0xff, 0x83, 0x78, 0x2e, 0xff, 0xfa, 0x62, 0x1e, 0x8b, 0xc5, 0x2a, 0x94, 0x0d, 0xdc, 0x3f,
-	0x68, 0x57, 0xd3, 0xae, 0xa6, 0x5d, 0x43, 0x87, 0x15, 0x15, 0xbf, 0xfe, 0x1d, 0x00, 0x00, 0xff,
-	0xff, 0x4b, 0x88, 0xa4, 0x39, 0xb1, 0x03, 0x00, 0x00,
+func init() { proto.RegisterFile("orderer/ab.proto", fileDescriptor_ab_c00ad9ee50780233) }
+
+var fileDescriptor_ab_c00ad9ee50780233 = []byte{
+	// 587 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x93, 0xdb, 0x6e, 0xd3, 0x4c,
+	0x10, 0xc7, 0xe3, 0x7c, 0x69, 0xda, 0x4c, 0xd3, 0xb4, 0xdd, 0x7e, 0x2d, 0x56, 0x25, 0x50, 0xb1,
+	0xd4, 0x12, 0x04, 0x24, 0x28, 0x48, 0x48, 0x1c, 0x24, 0xd4, 0xf4, 0xa0, 0x44, 0x54, 0x09, 0xda,
+	0xb6, 0x17, 0x70, 0x63, 0xf9, 0xb0, 0x69, 0x96, 0x38, 0xbb, 0xd6, 0x7a, 0x1b, 0xd4, 0xa7, 0xe0,
+	0x05, 0xb9, 0xe7, 0x35, 0xd0, 0x1e, 0xec, 0x24, 0x50, 0xf5, 0xca, 0x9e, 0x99, 0xdf, 0xdf, 0x33,
+	0xf3, 0xf7, 0x2e, 0x6c, 0x71, 0x11, 0x13, 0x41, 0x44, 0x3b, 0x08, 0x5b, 0xa9, 0xe0, 0x92, 0xa3,
+	0x55, 0x9b, 0xd9, 0xdf, 0x89, 0xf8, 0x74, 0xca, 0x59, 0xdb, 0x3c, 0x4c, 0xd5, 0x1b, 0xc2, 0x76,
+	0x57, 0xf0, 0x20, 0x8e, 0x82, 0x4c, 0x62, 0x92, 0xa5, 0x9c, 0x65, 0x04, 0x1d, 0x41, 0x35, 0x93,
+	0x81, 0xbc, 0xcd, 0x5c, 0xe7, 0xc0, 0x69, 0x36, 0x3a, 0x8d, 0x96, 0xd5, 0x5c, 0xea, 0x2c, 0xb6,
+	0x55, 0x84, 0xa0, 0x42, 0xd9, 0x88, 0xbb, 0xe5, 0x03, 0xa7, 0x59, 0xc3, 0xfa, 0xdd, 0xab, 0x03,
+	0x5c, 0x12, 0x32, 0x19, 0x90, 0x1f, 0x24, 0x93, 0x79, 0x34, 0x4c, 0x62, 0x15, 0x3d, 0x83, 0x0d,
+	0x15, 0x5d, 0xa6, 0x24, 0xa2, 0x23, 0x4a, 0x62, 0xb4, 0x07, 0x55, 0x76, 0x3b, 0x0d, 0x89, 0xd0,
+	0x8d, 0x2a, 0xd8, 0x46, 0xde, 0x11, 0x34, 0x14, 0x78, 0x32, 0x26, 0xd1, 0x24, 0xe5, 0x94, 0x49,
+	0xf4, 0x3f, 0xac, 0x48, 0x3e, 0x21, 0x4c, 0x83, 0x75, 0x6c, 0x02, 0xef, 0xb7, 0x03, 0x75, 0x05,
+	0x7e, 0xe1, 0x19, 0x95, 0x94, 0x33, 0xf4, 0x0a, 0xaa, 0x4c, 0x77, 0xd6, 0xdc, 0x7a, 0x67, 0xa7,
+	0x65, 0xb7, 0x6f, 0xcd, 0x87, 0xea, 0x95, 0xb0, 0x85, 0x14, 0xce, 0xf5, 0x68, 0x6e, 0xf9, 0x1e,
+	0xdc, 0x4c, 0xad, 0x70, 0x03, 0xa1, 0xb7, 0x50, 0xcb, 0xf2, 0xd9, 0xdd, 0xff, 0xb4, 0x62, 0x6f,
+	0x49, 0x51, 0x6c, 0xd6, 0x2b, 0xe1, 0x39, 0x8a, 0xde, 0x01, 0x44, 0xc5, 0x2a, 0x6e, 0x45, 0x0b,
+	0x1f, 0x2d, 0x09, 0xe7, 0x9b, 0xf6, 0x4a, 0x78, 0x01, 0xee, 0x56, 0xa1, 0x72, 0x75, 0x97, 0x12,
+	0xef, 0x1a, 0xb6, 0x4f, 0x49, 0x42, 0x67, 0x44, 0x2c, 0x98, 0xf2, 0x58, 0x7d, 0x37, 0x60, 0x8c,
+	0x24, 0x3e, 0x8d, 0xf5, 0xc6, 0x35, 0x5c, 0xb3, 0x99, 0x7e, 0x8c, 0x9e, 0x42, 0x3d, 0x4c, 0x78,
+	0x34, 0xf1, 0xad, 0xc7, 0x65, 0xed, 0xf1, 0xba, 0xce, 0x0d, 0x8c, 0xd1, 0xbf, 0x1c, 0x58, 0x53,
+	0xfd, 0xfb, 0x6c, 0xc4, 0xd1, 0x0b, 0x58, 0xc9, 0x64, 0x20, 0x72, 0xef, 0x76, 0x97, 0x26, 0xcc,
+	0x2d, 0xc6, 0x86, 0x41, 0xcf, 0xa1, 0x92, 0x49, 0x9e, 0xba, 0xe5, 0x87, 0x58, 0x8d, 0xa0, 0xf7,
+	0xb0, 0x16, 0x92, 0x71, 0x30, 0xa3, 0x5c, 0x68, 0xd7, 0x1a, 0x9d, 0x27, 0x4b, 0xb8, 0x6a, 0xae,
+	0x5f, 0xba, 0x96, 0xc2, 0x05, 0xef, 0x7d, 0x84, 0xfa, 0x62, 0x05, 0xed, 0xc2, 0x76, 0xf7, 0x62,
+	0x78, 0xf2, 0xd9, 0xbf, 0x1e, 0x5c, 0xf5, 0x2f, 0x7c, 0x7c, 0x76, 0x7c, 0xfa, 0x75, 0xab, 0xa4,
+	0xd2, 0xe7, 0xc7, 0xfd, 0x0b, 0xbf, 0x7f, 0xee, 0x0f, 0x86, 0x57, 0x36, 0xed, 0x78, 0xdf, 0x61,
+	0xd3, 0xba, 0x56, 0x9c, 0xed, 0xe6, 0xc3, 0x67, 0x5b, 0xfd, 0x6d, 0x7b, 0xba, 0x0f, 0x61, 0x45,
+	0x5b, 0x65, 0x57, 0xdc, 0xc8, 0xc1, 0xae, 0x4a, 0xf6, 0x4a, 0xd8, 0x54, 0xf3, 0x3f, 0xd4, 0xf9,
+	0xe9, 0xc0, 0xe6, 0xb1, 0xe4, 0x53, 0x1a, 0x15, 0x17, 0x0a, 0x7d, 0x82, 0xda, 0x3c, 0xd8, 0xca,
+	0x3f, 0x70, 0xc6, 0x66, 0x24, 0xe1, 0x29, 0xd9, 0xdf, 0x2f, 0x6c, 0xf8, 0xe7, 0x0e, 0x7a, 0xa5,
+	0xa6, 0xf3, 0xda, 0x41, 0x1f, 0x60, 0xd5, 0x2e, 0x70, 0x8f, 0xdc, 0x2d, 0xe4, 0x7f, 0x2d, 0x69,
+	0xc4, 0xdd, 0x6b, 0x38, 0xe4, 0xe2, 0xa6, 0x35, 0xbe, 0x4b, 0x89, 0x48, 0x48, 0x7c, 0x43, 0x44,
+	0x6b, 0x14, 0x84, 0x82, 0x46, 0xe6, 0xee, 0x67, 0xb9, 0xfc, 0xdb, 0xcb, 0x1b, 0x2a, 0xc7, 0xb7,
+	0xa1, 0x6a, 0xd0, 0x5e, 0xa0, 0xdb, 0x86, 0x6e, 0x1b, 0xba, 0x6d, 0xe9, 0xb0, 0xaa, 0xe3, 0x37,
+	0x7f, 0x06, 0x00, 0x8a, 0x02, 0xa1, 0xeb, 0x6b, 0x04, 0x00, 0x00,
 }
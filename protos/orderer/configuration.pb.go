@@ -31,7 +31,7 @@ func (m *ConsensusType) Reset()         { *m = ConsensusType{} }
 func (m *ConsensusType) String() string { return proto.CompactTextString(m) }
 func (*ConsensusType) ProtoMessage()    {}
 func (*ConsensusType) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_3289caae61166f4d, []int{0}
+	return fileDescriptor_configuration_10efb1836d5be499, []int{0}
 }
 func (m *ConsensusType) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ConsensusType.Unmarshal(m, b)
@@ -74,7 +74,11 @@ type BatchSize struct {
 	AbsoluteMaxBytes uint32 `protobuf:"varint,2,opt,name=absolute_max_bytes,json=absoluteMaxBytes" json:"absolute_max_bytes,omitempty"`
 	// The byte count of the serialized messages in a batch should not
 	// exceed this value.
-	PreferredMaxBytes    uint32   `protobuf:"varint,3,opt,name=preferred_max_bytes,json=preferredMaxBytes" json:"preferred_max_bytes,omitempty"`
+	PreferredMaxBytes uint32 `protobuf:"varint,3,opt,name=preferred_max_bytes,json=preferredMaxBytes" json:"preferred_max_bytes,omitempty"`
+	// The byte count of a serialized message cannot exceed this value, a
+	// value of 0 limits the messages to absolute_max_bytes. It is only
+	// enforced with the V1_4_2 orderer capability.
+	MaxTransactionBytes  uint32   `protobuf:"varint,4,opt,name=max_transaction_bytes,json=maxTransactionBytes" json:"max_transaction_bytes,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -84,7 +88,7 @@ func (m *BatchSize) Reset()         { *m = BatchSize{} }
 func (m *BatchSize) String() string { return proto.CompactTextString(m) }
 func (*BatchSize) ProtoMessage()    {}
 func (*BatchSize) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_3289caae61166f4d, []int{1}
+	return fileDescriptor_configuration_10efb1836d5be499, []int{1}
 }
 func (m *BatchSize) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BatchSize.Unmarshal(m, b)
@@ -125,6 +129,13 @@ func (m *BatchSize) GetPreferredMaxBytes() uint32 {
 	return 0
 }
 
+func (m *BatchSize) GetMaxTransactionBytes() uint32 {
+	if m != nil {
+		return m.MaxTransactionBytes
+	}
+	return 0
+}
+
 type BatchTimeout struct {
 	// Any duration string parseable by ParseDuration():
 	// https://golang.org/pkg/time/#ParseDuration
@@ -138,7 +149,7 @@ func (m *BatchTimeout) Reset()         { *m = BatchTimeout{} }
 func (m *BatchTimeout) String() string { return proto.CompactTextString(m) }
 func (*BatchTimeout) ProtoMessage()    {}
 func (*BatchTimeout) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_3289caae61166f4d, []int{2}
+	return fileDescriptor_configuration_10efb1836d5be499, []int{2}
 }
 func (m *BatchTimeout) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BatchTimeout.Unmarshal(m, b)
@@ -180,7 +191,7 @@ func (m *KafkaBrokers) Reset()         { *m = KafkaBrokers{} }
 func (m *KafkaBrokers) String() string { return proto.CompactTextString(m) }
 func (*KafkaBrokers) ProtoMessage()    {}
 func (*KafkaBrokers) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_3289caae61166f4d, []int{3}
+	return fileDescriptor_configuration_10efb1836d5be499, []int{3}
 }
 func (m *KafkaBrokers) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_KafkaBrokers.Unmarshal(m, b)
@@ -219,7 +230,7 @@ func (m *ChannelRestrictions) Reset()         { *m = ChannelRestrictions{} }
 func (m *ChannelRestrictions) String() string { return proto.CompactTextString(m) }
 func (*ChannelRestrictions) ProtoMessage()    {}
 func (*ChannelRestrictions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_3289caae61166f4d, []int{4}
+	return fileDescriptor_configuration_10efb1836d5be499, []int{4}
 }
 func (m *ChannelRestrictions) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChannelRestrictions.Unmarshal(m, b)
@@ -255,30 +266,31 @@ func init() {
 }
 
 func init() {
-	proto.RegisterFile("orderer/configuration.proto", fileDescriptor_configuration_3289caae61166f4d)
+	proto.RegisterFile("orderer/configuration.proto", fileDescriptor_configuration_10efb1836d5be499)
 }
 
-var fileDescriptor_configuration_3289caae61166f4d = []byte{
-	// 333 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x91, 0x4f, 0x6b, 0xf2, 0x40,
-	0x10, 0xc6, 0xc9, 0xab, 0xbc, 0xea, 0xa2, 0xbc, 0xaf, 0xeb, 0x25, 0xd4, 0x8b, 0x04, 0x0a, 0x52,
-	0x24, 0x81, 0xf6, 0x03, 0x14, 0xe2, 0xb1, 0x78, 0x49, 0xed, 0xa5, 0x17, 0x99, 0x24, 0x93, 0x3f,
-	0x68, 0x76, 0xc3, 0xec, 0x06, 0x92, 0x7e, 0x8f, 0x7e, 0xdf, 0xb2, 0x9b, 0x68, 0xbd, 0xcd, 0x33,
-	0xcf, 0x6f, 0x87, 0x79, 0x76, 0xd8, 0x5a, 0x52, 0x8a, 0x84, 0x14, 0x24, 0x52, 0x64, 0x65, 0xde,
-	0x10, 0xe8, 0x52, 0x0a, 0xbf, 0x26, 0xa9, 0x25, 0x9f, 0x0c, 0xa6, 0xf7, 0xca, 0x16, 0x7b, 0x29,
-	0x14, 0x0a, 0xd5, 0xa8, 0x63, 0x57, 0x23, 0xe7, 0x6c, 0xac, 0xbb, 0x1a, 0x5d, 0x67, 0xe3, 0x6c,
-	0x67, 0x91, 0xad, 0xf9, 0x03, 0x9b, 0x56, 0xa8, 0x21, 0x05, 0x0d, 0xee, 0x9f, 0x8d, 0xb3, 0x9d,
-	0x47, 0x37, 0xed, 0x7d, 0x3b, 0x6c, 0x16, 0x82, 0x4e, 0x8a, 0xf7, 0xf2, 0x0b, 0xf9, 0x13, 0x5b,
-	0x56, 0xd0, 0x9e, 0x2a, 0x54, 0x0a, 0x72, 0x3c, 0x25, 0xb2, 0x11, 0xda, 0x8e, 0x5a, 0x44, 0xff,
-	0x2a, 0x68, 0x0f, 0x7d, 0x7f, 0x6f, 0xda, 0x7c, 0xc7, 0x38, 0xc4, 0x4a, 0x5e, 0x1a, 0x8d, 0x27,
-	0xf3, 0x28, 0xee, 0x34, 0x2a, 0x3b, 0x7f, 0x11, 0xfd, 0xbf, 0x3a, 0x07, 0x68, 0x43, 0xd3, 0xe7,
-	0x3e, 0x5b, 0xd5, 0x84, 0x19, 0x12, 0x61, 0x7a, 0x87, 0x8f, 0x2c, 0xbe, 0xbc, 0x59, 0x57, 0xde,
-	0xdb, 0xb2, 0xb9, 0x5d, 0xeb, 0x58, 0x56, 0x28, 0x1b, 0xcd, 0x5d, 0x36, 0xd1, 0x7d, 0x39, 0x44,
-	0xbb, 0x4a, 0x43, 0xbe, 0x41, 0x76, 0x86, 0x90, 0xe4, 0x19, 0x49, 0x19, 0x32, 0xee, 0x4b, 0xd7,
-	0xd9, 0x8c, 0x0c, 0x39, 0x48, 0xef, 0x99, 0xad, 0xf6, 0x05, 0x08, 0x81, 0x97, 0x08, 0x95, 0xa6,
-	0x32, 0x31, 0x3f, 0xaa, 0xf8, 0x9a, 0xcd, 0xcc, 0x42, 0xbf, 0x61, 0xc7, 0xd1, 0xb4, 0x82, 0xd6,
-	0xa6, 0x0c, 0x3f, 0xd8, 0xa3, 0xa4, 0xdc, 0x2f, 0xba, 0x1a, 0xe9, 0x82, 0x69, 0x8e, 0xe4, 0x67,
-	0x10, 0x53, 0x99, 0xf4, 0x97, 0x50, 0xfe, 0x70, 0x89, 0xcf, 0x5d, 0x5e, 0xea, 0xa2, 0x89, 0xfd,
-	0x44, 0x56, 0xc1, 0x1d, 0x1d, 0xf4, 0x74, 0xd0, 0xd3, 0xc1, 0x40, 0xc7, 0x7f, 0xad, 0x7e, 0xf9,
-	0x09, 0x00, 0x00, 0xff, 0xff, 0xb5, 0x9c, 0xb6, 0xa5, 0xe6, 0x01, 0x00, 0x00,
+var fileDescriptor_configuration_10efb1836d5be499 = []byte{
+	// 351 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x91, 0xcb, 0xca, 0xdb, 0x30,
+	0x10, 0x85, 0x71, 0xff, 0xd0, 0x24, 0x22, 0xa1, 0x8d, 0x42, 0xc1, 0x34, 0x9b, 0x60, 0x28, 0x84,
+	0x12, 0x6c, 0x48, 0x1f, 0xa0, 0xe0, 0x2c, 0x4b, 0x36, 0x6e, 0xba, 0xe9, 0x26, 0x8c, 0xed, 0xf1,
+	0x85, 0x44, 0x92, 0x19, 0xc9, 0x60, 0xf7, 0x0d, 0xfb, 0x56, 0x3f, 0x92, 0x9d, 0xcb, 0x6e, 0xce,
+	0x9c, 0xef, 0xc8, 0x9e, 0x19, 0xb6, 0x51, 0x94, 0x23, 0x21, 0x45, 0x99, 0x92, 0x45, 0x5d, 0xb6,
+	0x04, 0xa6, 0x56, 0x32, 0x6c, 0x48, 0x19, 0xc5, 0xa7, 0xa3, 0x19, 0xfc, 0x64, 0xcb, 0xa3, 0x92,
+	0x1a, 0xa5, 0x6e, 0xf5, 0xb9, 0x6f, 0x90, 0x73, 0x36, 0x31, 0x7d, 0x83, 0xbe, 0xb7, 0xf5, 0x76,
+	0xf3, 0xc4, 0xd5, 0xfc, 0x2b, 0x9b, 0x09, 0x34, 0x90, 0x83, 0x01, 0xff, 0xc3, 0xd6, 0xdb, 0x2d,
+	0x92, 0x87, 0x0e, 0xfe, 0x7b, 0x6c, 0x1e, 0x83, 0xc9, 0xaa, 0xdf, 0xf5, 0x3f, 0xe4, 0xdf, 0xd9,
+	0x4a, 0x40, 0x77, 0x11, 0xa8, 0x35, 0x94, 0x78, 0xc9, 0x54, 0x2b, 0x8d, 0x7b, 0x6a, 0x99, 0x7c,
+	0x12, 0xd0, 0x9d, 0x86, 0xfe, 0xd1, 0xb6, 0xf9, 0x9e, 0x71, 0x48, 0xb5, 0xba, 0xb5, 0x06, 0x2f,
+	0x36, 0x94, 0xf6, 0x06, 0xb5, 0x7b, 0x7f, 0x99, 0x7c, 0xbe, 0x3b, 0x27, 0xe8, 0x62, 0xdb, 0xe7,
+	0x21, 0x5b, 0x37, 0x84, 0x05, 0x12, 0x61, 0xfe, 0x82, 0xbf, 0x39, 0x7c, 0xf5, 0xb0, 0x1e, 0xfc,
+	0x81, 0x7d, 0xb1, 0x94, 0x21, 0x90, 0x1a, 0x32, 0x3b, 0xfa, 0x98, 0x98, 0xb8, 0xc4, 0x5a, 0x40,
+	0x77, 0x7e, 0x7a, 0x2e, 0x13, 0xec, 0xd8, 0xc2, 0x8d, 0x72, 0xae, 0x05, 0xaa, 0xd6, 0x70, 0x9f,
+	0x4d, 0xcd, 0x50, 0x8e, 0xeb, 0xb8, 0x4b, 0x4b, 0xfe, 0x82, 0xe2, 0x0a, 0x31, 0xa9, 0x2b, 0x92,
+	0xb6, 0x64, 0x3a, 0x94, 0xbe, 0xb7, 0x7d, 0xb3, 0xe4, 0x28, 0x83, 0x03, 0x5b, 0x1f, 0x2b, 0x90,
+	0x12, 0x6f, 0x09, 0x6a, 0x43, 0xb5, 0xfb, 0x9c, 0xe6, 0x1b, 0x36, 0xb7, 0xbf, 0xf7, 0x5c, 0xd0,
+	0x24, 0x99, 0x09, 0xe8, 0xdc, 0x66, 0xe2, 0x3f, 0xec, 0x9b, 0xa2, 0x32, 0xac, 0xfa, 0x06, 0xe9,
+	0x86, 0x79, 0x89, 0x14, 0x16, 0x90, 0x52, 0x9d, 0x0d, 0xd7, 0xd3, 0xe1, 0x78, 0xbd, 0xbf, 0xfb,
+	0xb2, 0x36, 0x55, 0x9b, 0x86, 0x99, 0x12, 0xd1, 0x0b, 0x1d, 0x0d, 0x74, 0x34, 0xd0, 0xd1, 0x48,
+	0xa7, 0x1f, 0x9d, 0xfe, 0xf1, 0x3e, 0x00, 0x66, 0xa2, 0x5a, 0x8f, 0x1a, 0x02, 0x00, 0x00,
 }
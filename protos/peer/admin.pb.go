@@ -6,7 +6,9 @@ package peer // import "github.com/hyperledger/fabric/protos/peer"
 import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
+import duration "github.com/golang/protobuf/ptypes/duration"
 import empty "github.com/golang/protobuf/ptypes/empty"
+import timestamp "github.com/golang/protobuf/ptypes/timestamp"
 import common "github.com/hyperledger/fabric/protos/common"
 
 import (
@@ -57,7 +59,7 @@ func (x ServerStatus_StatusCode) String() string {
 	return proto.EnumName(ServerStatus_StatusCode_name, int32(x))
 }
 func (ServerStatus_StatusCode) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_admin_6c8e47987088ed1f, []int{0, 0}
+	return fileDescriptor_admin_1e2ccbeee30ad42e, []int{0, 0}
 }
 
 type ServerStatus struct {
@@ -71,7 +73,7 @@ func (m *ServerStatus) Reset()         { *m = ServerStatus{} }
 func (m *ServerStatus) String() string { return proto.CompactTextString(m) }
 func (*ServerStatus) ProtoMessage()    {}
 func (*ServerStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_admin_6c8e47987088ed1f, []int{0}
+	return fileDescriptor_admin_1e2ccbeee30ad42e, []int{0}
 }
 func (m *ServerStatus) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ServerStatus.Unmarshal(m, b)
@@ -110,7 +112,7 @@ func (m *LogLevelRequest) Reset()         { *m = LogLevelRequest{} }
 func (m *LogLevelRequest) String() string { return proto.CompactTextString(m) }
 func (*LogLevelRequest) ProtoMessage()    {}
 func (*LogLevelRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_admin_6c8e47987088ed1f, []int{1}
+	return fileDescriptor_admin_1e2ccbeee30ad42e, []int{1}
 }
 func (m *LogLevelRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_LogLevelRequest.Unmarshal(m, b)
@@ -156,7 +158,7 @@ func (m *LogLevelResponse) Reset()         { *m = LogLevelResponse{} }
 func (m *LogLevelResponse) String() string { return proto.CompactTextString(m) }
 func (*LogLevelResponse) ProtoMessage()    {}
 func (*LogLevelResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_admin_6c8e47987088ed1f, []int{2}
+	return fileDescriptor_admin_1e2ccbeee30ad42e, []int{2}
 }
 func (m *LogLevelResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_LogLevelResponse.Unmarshal(m, b)
@@ -190,9 +192,682 @@ func (m *LogLevelResponse) GetLogLevel() string {
 	return ""
 }
 
+// ReloadConfigResponse lists the configuration keys whose value was changed
+// by reloading the configuration of the peer
+type ReloadConfigResponse struct {
+	ChangedKeys          []string `protobuf:"bytes,1,rep,name=changed_keys,json=changedKeys" json:"changed_keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReloadConfigResponse) Reset()         { *m = ReloadConfigResponse{} }
+func (m *ReloadConfigResponse) String() string { return proto.CompactTextString(m) }
+func (*ReloadConfigResponse) ProtoMessage()    {}
+func (*ReloadConfigResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_admin_1e2ccbeee30ad42e, []int{3}
+}
+func (m *ReloadConfigResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReloadConfigResponse.Unmarshal(m, b)
+}
+func (m *ReloadConfigResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReloadConfigResponse.Marshal(b, m, deterministic)
+}
+func (dst *ReloadConfigResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReloadConfigResponse.Merge(dst, src)
+}
+func (m *ReloadConfigResponse) XXX_Size() int {
+	return xxx_messageInfo_ReloadConfigResponse.Size(m)
+}
+func (m *ReloadConfigResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReloadConfigResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReloadConfigResponse proto.InternalMessageInfo
+
+func (m *ReloadConfigResponse) GetChangedKeys() []string {
+	if m != nil {
+		return m.ChangedKeys
+	}
+	return nil
+}
+
+// StateIndexRequest designates the state databases of a chaincode on a
+// channel, restricted to the one of a private data collection if set, and
+// carries the definition of the index to create
+type StateIndexRequest struct {
+	ChannelId            string   `protobuf:"bytes,1,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+	Chaincode            string   `protobuf:"bytes,2,opt,name=chaincode" json:"chaincode,omitempty"`
+	Collection           string   `protobuf:"bytes,3,opt,name=collection" json:"collection,omitempty"`
+	Definition           []byte   `protobuf:"bytes,4,opt,name=definition,proto3" json:"definition,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StateIndexRequest) Reset()         { *m = StateIndexRequest{} }
+func (m *StateIndexRequest) String() string { return proto.CompactTextString(m) }
+func (*StateIndexRequest) ProtoMessage()    {}
+func (*StateIndexRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_admin_1e2ccbeee30ad42e, []int{4}
+}
+func (m *StateIndexRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateIndexRequest.Unmarshal(m, b)
+}
+func (m *StateIndexRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateIndexRequest.Marshal(b, m, deterministic)
+}
+func (dst *StateIndexRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateIndexRequest.Merge(dst, src)
+}
+func (m *StateIndexRequest) XXX_Size() int {
+	return xxx_messageInfo_StateIndexRequest.Size(m)
+}
+func (m *StateIndexRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateIndexRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateIndexRequest proto.InternalMessageInfo
+
+func (m *StateIndexRequest) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *StateIndexRequest) GetChaincode() string {
+	if m != nil {
+		return m.Chaincode
+	}
+	return ""
+}
+
+func (m *StateIndexRequest) GetCollection() string {
+	if m != nil {
+		return m.Collection
+	}
+	return ""
+}
+
+func (m *StateIndexRequest) GetDefinition() []byte {
+	if m != nil {
+		return m.Definition
+	}
+	return nil
+}
+
+// StateIndex is an index of a state database of a chaincode
+type StateIndex struct {
+	Collection           string   `protobuf:"bytes,1,opt,name=collection" json:"collection,omitempty"`
+	DesignDocument       string   `protobuf:"bytes,2,opt,name=design_document,json=designDocument" json:"design_document,omitempty"`
+	Name                 string   `protobuf:"bytes,3,opt,name=name" json:"name,omitempty"`
+	Definition           string   `protobuf:"bytes,4,opt,name=definition" json:"definition,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StateIndex) Reset()         { *m = StateIndex{} }
+func (m *StateIndex) String() string { return proto.CompactTextString(m) }
+func (*StateIndex) ProtoMessage()    {}
+func (*StateIndex) Descriptor() ([]byte, []int) {
+	return fileDescriptor_admin_1e2ccbeee30ad42e, []int{5}
+}
+func (m *StateIndex) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateIndex.Unmarshal(m, b)
+}
+func (m *StateIndex) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateIndex.Marshal(b, m, deterministic)
+}
+func (dst *StateIndex) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateIndex.Merge(dst, src)
+}
+func (m *StateIndex) XXX_Size() int {
+	return xxx_messageInfo_StateIndex.Size(m)
+}
+func (m *StateIndex) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateIndex.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateIndex proto.InternalMessageInfo
+
+func (m *StateIndex) GetCollection() string {
+	if m != nil {
+		return m.Collection
+	}
+	return ""
+}
+
+func (m *StateIndex) GetDesignDocument() string {
+	if m != nil {
+		return m.DesignDocument
+	}
+	return ""
+}
+
+func (m *StateIndex) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *StateIndex) GetDefinition() string {
+	if m != nil {
+		return m.Definition
+	}
+	return ""
+}
+
+// StateIndexesResponse lists the indexes of the state databases of a
+// chaincode after the requested operation
+type StateIndexesResponse struct {
+	Indexes              []*StateIndex `protobuf:"bytes,1,rep,name=indexes" json:"indexes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *StateIndexesResponse) Reset()         { *m = StateIndexesResponse{} }
+func (m *StateIndexesResponse) String() string { return proto.CompactTextString(m) }
+func (*StateIndexesResponse) ProtoMessage()    {}
+func (*StateIndexesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_admin_1e2ccbeee30ad42e, []int{6}
+}
+func (m *StateIndexesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateIndexesResponse.Unmarshal(m, b)
+}
+func (m *StateIndexesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateIndexesResponse.Marshal(b, m, deterministic)
+}
+func (dst *StateIndexesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateIndexesResponse.Merge(dst, src)
+}
+func (m *StateIndexesResponse) XXX_Size() int {
+	return xxx_messageInfo_StateIndexesResponse.Size(m)
+}
+func (m *StateIndexesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateIndexesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateIndexesResponse proto.InternalMessageInfo
+
+func (m *StateIndexesResponse) GetIndexes() []*StateIndex {
+	if m != nil {
+		return m.Indexes
+	}
+	return nil
+}
+
+// ChaincodeStatusRequest restricts the chaincodes whose status is returned
+// to the one with the given name, if set
+type ChaincodeStatusRequest struct {
+	Chaincode            string   `protobuf:"bytes,1,opt,name=chaincode" json:"chaincode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChaincodeStatusRequest) Reset()         { *m = ChaincodeStatusRequest{} }
+func (m *ChaincodeStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*ChaincodeStatusRequest) ProtoMessage()    {}
+func (*ChaincodeStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_admin_1e2ccbeee30ad42e, []int{7}
+}
+func (m *ChaincodeStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChaincodeStatusRequest.Unmarshal(m, b)
+}
+func (m *ChaincodeStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChaincodeStatusRequest.Marshal(b, m, deterministic)
+}
+func (dst *ChaincodeStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChaincodeStatusRequest.Merge(dst, src)
+}
+func (m *ChaincodeStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_ChaincodeStatusRequest.Size(m)
+}
+func (m *ChaincodeStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChaincodeStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChaincodeStatusRequest proto.InternalMessageInfo
+
+func (m *ChaincodeStatusRequest) GetChaincode() string {
+	if m != nil {
+		return m.Chaincode
+	}
+	return ""
+}
+
+// ChaincodeStatus is the status of a chaincode running on the peer, as last
+// reported by its heartbeats
+type ChaincodeStatus struct {
+	// name is the name and version of the chaincode
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// last_heartbeat is unset if the chaincode sent no heartbeat
+	LastHeartbeat *timestamp.Timestamp   `protobuf:"bytes,2,opt,name=last_heartbeat,json=lastHeartbeat" json:"last_heartbeat,omitempty"`
+	Stats         *ChaincodeRuntimeStats `protobuf:"bytes,3,opt,name=stats" json:"stats,omitempty"`
+	// responsive is false if the heartbeats of the chaincode are overdue
+	Responsive           bool     `protobuf:"varint,4,opt,name=responsive" json:"responsive,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChaincodeStatus) Reset()         { *m = ChaincodeStatus{} }
+func (m *ChaincodeStatus) String() string { return proto.CompactTextString(m) }
+func (*ChaincodeStatus) ProtoMessage()    {}
+func (*ChaincodeStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_admin_1e2ccbeee30ad42e, []int{8}
+}
+func (m *ChaincodeStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChaincodeStatus.Unmarshal(m, b)
+}
+func (m *ChaincodeStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChaincodeStatus.Marshal(b, m, deterministic)
+}
+func (dst *ChaincodeStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChaincodeStatus.Merge(dst, src)
+}
+func (m *ChaincodeStatus) XXX_Size() int {
+	return xxx_messageInfo_ChaincodeStatus.Size(m)
+}
+func (m *ChaincodeStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChaincodeStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChaincodeStatus proto.InternalMessageInfo
+
+func (m *ChaincodeStatus) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ChaincodeStatus) GetLastHeartbeat() *timestamp.Timestamp {
+	if m != nil {
+		return m.LastHeartbeat
+	}
+	return nil
+}
+
+func (m *ChaincodeStatus) GetStats() *ChaincodeRuntimeStats {
+	if m != nil {
+		return m.Stats
+	}
+	return nil
+}
+
+func (m *ChaincodeStatus) GetResponsive() bool {
+	if m != nil {
+		return m.Responsive
+	}
+	return false
+}
+
+// ChaincodeStatusResponse lists the status of the chaincodes running on the
+// peer
+type ChaincodeStatusResponse struct {
+	Chaincodes           []*ChaincodeStatus `protobuf:"bytes,1,rep,name=chaincodes" json:"chaincodes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ChaincodeStatusResponse) Reset()         { *m = ChaincodeStatusResponse{} }
+func (m *ChaincodeStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*ChaincodeStatusResponse) ProtoMessage()    {}
+func (*ChaincodeStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_admin_1e2ccbeee30ad42e, []int{9}
+}
+func (m *ChaincodeStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChaincodeStatusResponse.Unmarshal(m, b)
+}
+func (m *ChaincodeStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChaincodeStatusResponse.Marshal(b, m, deterministic)
+}
+func (dst *ChaincodeStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChaincodeStatusResponse.Merge(dst, src)
+}
+func (m *ChaincodeStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_ChaincodeStatusResponse.Size(m)
+}
+func (m *ChaincodeStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChaincodeStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChaincodeStatusResponse proto.InternalMessageInfo
+
+func (m *ChaincodeStatusResponse) GetChaincodes() []*ChaincodeStatus {
+	if m != nil {
+		return m.Chaincodes
+	}
+	return nil
+}
+
+// SnapshotsRequest designates the channel whose state snapshots are listed
+type SnapshotsRequest struct {
+	ChannelId            string   `protobuf:"bytes,1,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotsRequest) Reset()         { *m = SnapshotsRequest{} }
+func (m *SnapshotsRequest) String() string { return proto.CompactTextString(m) }
+func (*SnapshotsRequest) ProtoMessage()    {}
+func (*SnapshotsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_admin_1e2ccbeee30ad42e, []int{10}
+}
+func (m *SnapshotsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotsRequest.Unmarshal(m, b)
+}
+func (m *SnapshotsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotsRequest.Marshal(b, m, deterministic)
+}
+func (dst *SnapshotsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotsRequest.Merge(dst, src)
+}
+func (m *SnapshotsRequest) XXX_Size() int {
+	return xxx_messageInfo_SnapshotsRequest.Size(m)
+}
+func (m *SnapshotsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotsRequest proto.InternalMessageInfo
+
+func (m *SnapshotsRequest) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+// Snapshot is a snapshot of the state of a channel, from which a peer joins
+// the channel at the height of the snapshot
+type Snapshot struct {
+	Height uint64 `protobuf:"varint,1,opt,name=height" json:"height,omitempty"`
+	// path is the directory of the snapshot on the file system of the peer
+	Path                 string               `protobuf:"bytes,2,opt,name=path" json:"path,omitempty"`
+	LastBlockHash        []byte               `protobuf:"bytes,3,opt,name=last_block_hash,json=lastBlockHash,proto3" json:"last_block_hash,omitempty"`
+	Timestamp            *timestamp.Timestamp `protobuf:"bytes,4,opt,name=timestamp" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Snapshot) Reset()         { *m = Snapshot{} }
+func (m *Snapshot) String() string { return proto.CompactTextString(m) }
+func (*Snapshot) ProtoMessage()    {}
+func (*Snapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_admin_1e2ccbeee30ad42e, []int{11}
+}
+func (m *Snapshot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Snapshot.Unmarshal(m, b)
+}
+func (m *Snapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Snapshot.Marshal(b, m, deterministic)
+}
+func (dst *Snapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Snapshot.Merge(dst, src)
+}
+func (m *Snapshot) XXX_Size() int {
+	return xxx_messageInfo_Snapshot.Size(m)
+}
+func (m *Snapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_Snapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Snapshot proto.InternalMessageInfo
+
+func (m *Snapshot) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *Snapshot) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *Snapshot) GetLastBlockHash() []byte {
+	if m != nil {
+		return m.LastBlockHash
+	}
+	return nil
+}
+
+func (m *Snapshot) GetTimestamp() *timestamp.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+// SnapshotsResponse lists the snapshots of the state of a channel by
+// increasing height
+type SnapshotsResponse struct {
+	Snapshots            []*Snapshot `protobuf:"bytes,1,rep,name=snapshots" json:"snapshots,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *SnapshotsResponse) Reset()         { *m = SnapshotsResponse{} }
+func (m *SnapshotsResponse) String() string { return proto.CompactTextString(m) }
+func (*SnapshotsResponse) ProtoMessage()    {}
+func (*SnapshotsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_admin_1e2ccbeee30ad42e, []int{12}
+}
+func (m *SnapshotsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotsResponse.Unmarshal(m, b)
+}
+func (m *SnapshotsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotsResponse.Marshal(b, m, deterministic)
+}
+func (dst *SnapshotsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotsResponse.Merge(dst, src)
+}
+func (m *SnapshotsResponse) XXX_Size() int {
+	return xxx_messageInfo_SnapshotsResponse.Size(m)
+}
+func (m *SnapshotsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotsResponse proto.InternalMessageInfo
+
+func (m *SnapshotsResponse) GetSnapshots() []*Snapshot {
+	if m != nil {
+		return m.Snapshots
+	}
+	return nil
+}
+
+// ExpiringCertificatesRequest restricts the listed certificates to those
+// which expire within the given duration, within the warning period of the
+// peer if unset
+type ExpiringCertificatesRequest struct {
+	Within               *duration.Duration `protobuf:"bytes,1,opt,name=within" json:"within,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ExpiringCertificatesRequest) Reset()         { *m = ExpiringCertificatesRequest{} }
+func (m *ExpiringCertificatesRequest) String() string { return proto.CompactTextString(m) }
+func (*ExpiringCertificatesRequest) ProtoMessage()    {}
+func (*ExpiringCertificatesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_admin_1e2ccbeee30ad42e, []int{13}
+}
+func (m *ExpiringCertificatesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExpiringCertificatesRequest.Unmarshal(m, b)
+}
+func (m *ExpiringCertificatesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExpiringCertificatesRequest.Marshal(b, m, deterministic)
+}
+func (dst *ExpiringCertificatesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExpiringCertificatesRequest.Merge(dst, src)
+}
+func (m *ExpiringCertificatesRequest) XXX_Size() int {
+	return xxx_messageInfo_ExpiringCertificatesRequest.Size(m)
+}
+func (m *ExpiringCertificatesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExpiringCertificatesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExpiringCertificatesRequest proto.InternalMessageInfo
+
+func (m *ExpiringCertificatesRequest) GetWithin() *duration.Duration {
+	if m != nil {
+		return m.Within
+	}
+	return nil
+}
+
+// ExpiringCertificate is a certificate of the local MSP, a TLS certificate of
+// the peer, or a certificate of an MSP of the config of a channel
+type ExpiringCertificate struct {
+	// channel_id is unset for the certificates of the peer itself
+	ChannelId            string               `protobuf:"bytes,1,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+	MspId                string               `protobuf:"bytes,2,opt,name=msp_id,json=mspId" json:"msp_id,omitempty"`
+	Role                 string               `protobuf:"bytes,3,opt,name=role" json:"role,omitempty"`
+	Subject              string               `protobuf:"bytes,4,opt,name=subject" json:"subject,omitempty"`
+	SerialNumber         string               `protobuf:"bytes,5,opt,name=serial_number,json=serialNumber" json:"serial_number,omitempty"`
+	NotAfter             *timestamp.Timestamp `protobuf:"bytes,6,opt,name=not_after,json=notAfter" json:"not_after,omitempty"`
+	Expired              bool                 `protobuf:"varint,7,opt,name=expired" json:"expired,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ExpiringCertificate) Reset()         { *m = ExpiringCertificate{} }
+func (m *ExpiringCertificate) String() string { return proto.CompactTextString(m) }
+func (*ExpiringCertificate) ProtoMessage()    {}
+func (*ExpiringCertificate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_admin_1e2ccbeee30ad42e, []int{14}
+}
+func (m *ExpiringCertificate) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExpiringCertificate.Unmarshal(m, b)
+}
+func (m *ExpiringCertificate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExpiringCertificate.Marshal(b, m, deterministic)
+}
+func (dst *ExpiringCertificate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExpiringCertificate.Merge(dst, src)
+}
+func (m *ExpiringCertificate) XXX_Size() int {
+	return xxx_messageInfo_ExpiringCertificate.Size(m)
+}
+func (m *ExpiringCertificate) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExpiringCertificate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExpiringCertificate proto.InternalMessageInfo
+
+func (m *ExpiringCertificate) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *ExpiringCertificate) GetMspId() string {
+	if m != nil {
+		return m.MspId
+	}
+	return ""
+}
+
+func (m *ExpiringCertificate) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+func (m *ExpiringCertificate) GetSubject() string {
+	if m != nil {
+		return m.Subject
+	}
+	return ""
+}
+
+func (m *ExpiringCertificate) GetSerialNumber() string {
+	if m != nil {
+		return m.SerialNumber
+	}
+	return ""
+}
+
+func (m *ExpiringCertificate) GetNotAfter() *timestamp.Timestamp {
+	if m != nil {
+		return m.NotAfter
+	}
+	return nil
+}
+
+func (m *ExpiringCertificate) GetExpired() bool {
+	if m != nil {
+		return m.Expired
+	}
+	return false
+}
+
+// ExpiringCertificatesResponse lists the expiring certificates, the earliest
+// expiring first
+type ExpiringCertificatesResponse struct {
+	Certificates         []*ExpiringCertificate `protobuf:"bytes,1,rep,name=certificates" json:"certificates,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *ExpiringCertificatesResponse) Reset()         { *m = ExpiringCertificatesResponse{} }
+func (m *ExpiringCertificatesResponse) String() string { return proto.CompactTextString(m) }
+func (*ExpiringCertificatesResponse) ProtoMessage()    {}
+func (*ExpiringCertificatesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_admin_1e2ccbeee30ad42e, []int{15}
+}
+func (m *ExpiringCertificatesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExpiringCertificatesResponse.Unmarshal(m, b)
+}
+func (m *ExpiringCertificatesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExpiringCertificatesResponse.Marshal(b, m, deterministic)
+}
+func (dst *ExpiringCertificatesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExpiringCertificatesResponse.Merge(dst, src)
+}
+func (m *ExpiringCertificatesResponse) XXX_Size() int {
+	return xxx_messageInfo_ExpiringCertificatesResponse.Size(m)
+}
+func (m *ExpiringCertificatesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExpiringCertificatesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExpiringCertificatesResponse proto.InternalMessageInfo
+
+func (m *ExpiringCertificatesResponse) GetCertificates() []*ExpiringCertificate {
+	if m != nil {
+		return m.Certificates
+	}
+	return nil
+}
+
 type AdminOperation struct {
 	// Types that are valid to be assigned to Content:
 	//	*AdminOperation_LogReq
+	//	*AdminOperation_IndexReq
+	//	*AdminOperation_ChaincodeStatusReq
+	//	*AdminOperation_SnapshotsReq
+	//	*AdminOperation_ExpiringCertsReq
 	Content              isAdminOperation_Content `protobuf_oneof:"content"`
 	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
 	XXX_unrecognized     []byte                   `json:"-"`
@@ -203,7 +878,7 @@ func (m *AdminOperation) Reset()         { *m = AdminOperation{} }
 func (m *AdminOperation) String() string { return proto.CompactTextString(m) }
 func (*AdminOperation) ProtoMessage()    {}
 func (*AdminOperation) Descriptor() ([]byte, []int) {
-	return fileDescriptor_admin_6c8e47987088ed1f, []int{3}
+	return fileDescriptor_admin_1e2ccbeee30ad42e, []int{16}
 }
 func (m *AdminOperation) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AdminOperation.Unmarshal(m, b)
@@ -230,8 +905,24 @@ type isAdminOperation_Content interface {
 type AdminOperation_LogReq struct {
 	LogReq *LogLevelRequest `protobuf:"bytes,1,opt,name=logReq,oneof"`
 }
+type AdminOperation_IndexReq struct {
+	IndexReq *StateIndexRequest `protobuf:"bytes,2,opt,name=indexReq,oneof"`
+}
+type AdminOperation_ChaincodeStatusReq struct {
+	ChaincodeStatusReq *ChaincodeStatusRequest `protobuf:"bytes,3,opt,name=chaincodeStatusReq,oneof"`
+}
+type AdminOperation_SnapshotsReq struct {
+	SnapshotsReq *SnapshotsRequest `protobuf:"bytes,4,opt,name=snapshotsReq,oneof"`
+}
+type AdminOperation_ExpiringCertsReq struct {
+	ExpiringCertsReq *ExpiringCertificatesRequest `protobuf:"bytes,5,opt,name=expiringCertsReq,oneof"`
+}
 
-func (*AdminOperation_LogReq) isAdminOperation_Content() {}
+func (*AdminOperation_LogReq) isAdminOperation_Content()             {}
+func (*AdminOperation_IndexReq) isAdminOperation_Content()           {}
+func (*AdminOperation_ChaincodeStatusReq) isAdminOperation_Content() {}
+func (*AdminOperation_SnapshotsReq) isAdminOperation_Content()       {}
+func (*AdminOperation_ExpiringCertsReq) isAdminOperation_Content()   {}
 
 func (m *AdminOperation) GetContent() isAdminOperation_Content {
 	if m != nil {
@@ -247,10 +938,42 @@ func (m *AdminOperation) GetLogReq() *LogLevelRequest {
 	return nil
 }
 
+func (m *AdminOperation) GetIndexReq() *StateIndexRequest {
+	if x, ok := m.GetContent().(*AdminOperation_IndexReq); ok {
+		return x.IndexReq
+	}
+	return nil
+}
+
+func (m *AdminOperation) GetChaincodeStatusReq() *ChaincodeStatusRequest {
+	if x, ok := m.GetContent().(*AdminOperation_ChaincodeStatusReq); ok {
+		return x.ChaincodeStatusReq
+	}
+	return nil
+}
+
+func (m *AdminOperation) GetSnapshotsReq() *SnapshotsRequest {
+	if x, ok := m.GetContent().(*AdminOperation_SnapshotsReq); ok {
+		return x.SnapshotsReq
+	}
+	return nil
+}
+
+func (m *AdminOperation) GetExpiringCertsReq() *ExpiringCertificatesRequest {
+	if x, ok := m.GetContent().(*AdminOperation_ExpiringCertsReq); ok {
+		return x.ExpiringCertsReq
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*AdminOperation) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _AdminOperation_OneofMarshaler, _AdminOperation_OneofUnmarshaler, _AdminOperation_OneofSizer, []interface{}{
 		(*AdminOperation_LogReq)(nil),
+		(*AdminOperation_IndexReq)(nil),
+		(*AdminOperation_ChaincodeStatusReq)(nil),
+		(*AdminOperation_SnapshotsReq)(nil),
+		(*AdminOperation_ExpiringCertsReq)(nil),
 	}
 }
 
@@ -263,6 +986,26 @@ func _AdminOperation_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.LogReq); err != nil {
 			return err
 		}
+	case *AdminOperation_IndexReq:
+		b.EncodeVarint(2<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.IndexReq); err != nil {
+			return err
+		}
+	case *AdminOperation_ChaincodeStatusReq:
+		b.EncodeVarint(3<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.ChaincodeStatusReq); err != nil {
+			return err
+		}
+	case *AdminOperation_SnapshotsReq:
+		b.EncodeVarint(4<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.SnapshotsReq); err != nil {
+			return err
+		}
+	case *AdminOperation_ExpiringCertsReq:
+		b.EncodeVarint(5<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.ExpiringCertsReq); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("AdminOperation.Content has unexpected type %T", x)
@@ -281,6 +1024,38 @@ func _AdminOperation_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto
 		err := b.DecodeMessage(msg)
 		m.Content = &AdminOperation_LogReq{msg}
 		return true, err
+	case 2: // content.indexReq
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(StateIndexRequest)
+		err := b.DecodeMessage(msg)
+		m.Content = &AdminOperation_IndexReq{msg}
+		return true, err
+	case 3: // content.chaincodeStatusReq
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(ChaincodeStatusRequest)
+		err := b.DecodeMessage(msg)
+		m.Content = &AdminOperation_ChaincodeStatusReq{msg}
+		return true, err
+	case 4: // content.snapshotsReq
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(SnapshotsRequest)
+		err := b.DecodeMessage(msg)
+		m.Content = &AdminOperation_SnapshotsReq{msg}
+		return true, err
+	case 5: // content.expiringCertsReq
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(ExpiringCertificatesRequest)
+		err := b.DecodeMessage(msg)
+		m.Content = &AdminOperation_ExpiringCertsReq{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -295,6 +1070,26 @@ func _AdminOperation_OneofSizer(msg proto.Message) (n int) {
 		n += 1 // tag and wire
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *AdminOperation_IndexReq:
+		s := proto.Size(x.IndexReq)
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *AdminOperation_ChaincodeStatusReq:
+		s := proto.Size(x.ChaincodeStatusReq)
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *AdminOperation_SnapshotsReq:
+		s := proto.Size(x.SnapshotsReq)
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *AdminOperation_ExpiringCertsReq:
+		s := proto.Size(x.ExpiringCertsReq)
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -306,6 +1101,19 @@ func init() {
 	proto.RegisterType((*ServerStatus)(nil), "protos.ServerStatus")
 	proto.RegisterType((*LogLevelRequest)(nil), "protos.LogLevelRequest")
 	proto.RegisterType((*LogLevelResponse)(nil), "protos.LogLevelResponse")
+	proto.RegisterType((*ReloadConfigResponse)(nil), "protos.ReloadConfigResponse")
+	proto.RegisterType((*StateIndexRequest)(nil), "protos.StateIndexRequest")
+	proto.RegisterType((*StateIndex)(nil), "protos.StateIndex")
+	proto.RegisterType((*StateIndexesResponse)(nil), "protos.StateIndexesResponse")
+	proto.RegisterType((*ChaincodeStatusRequest)(nil), "protos.ChaincodeStatusRequest")
+	proto.RegisterType((*ChaincodeStatus)(nil), "protos.ChaincodeStatus")
+	proto.RegisterType((*ChaincodeStatusResponse)(nil), "protos.ChaincodeStatusResponse")
+	proto.RegisterType((*SnapshotsRequest)(nil), "protos.SnapshotsRequest")
+	proto.RegisterType((*Snapshot)(nil), "protos.Snapshot")
+	proto.RegisterType((*SnapshotsResponse)(nil), "protos.SnapshotsResponse")
+	proto.RegisterType((*ExpiringCertificatesRequest)(nil), "protos.ExpiringCertificatesRequest")
+	proto.RegisterType((*ExpiringCertificate)(nil), "protos.ExpiringCertificate")
+	proto.RegisterType((*ExpiringCertificatesResponse)(nil), "protos.ExpiringCertificatesResponse")
 	proto.RegisterType((*AdminOperation)(nil), "protos.AdminOperation")
 	proto.RegisterEnum("protos.ServerStatus_StatusCode", ServerStatus_StatusCode_name, ServerStatus_StatusCode_value)
 }
@@ -326,6 +1134,13 @@ type AdminClient interface {
 	GetModuleLogLevel(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (*LogLevelResponse, error)
 	SetModuleLogLevel(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (*LogLevelResponse, error)
 	RevertLogLevels(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (*empty.Empty, error)
+	ReloadConfig(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (*ReloadConfigResponse, error)
+	ListStateIndexes(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (*StateIndexesResponse, error)
+	CreateStateIndex(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (*StateIndexesResponse, error)
+	RebuildStateIndexes(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (*StateIndexesResponse, error)
+	GetChaincodeStatus(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (*ChaincodeStatusResponse, error)
+	ListSnapshots(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (*SnapshotsResponse, error)
+	ListExpiringCertificates(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (*ExpiringCertificatesResponse, error)
 }
 
 type adminClient struct {
@@ -381,6 +1196,69 @@ func (c *adminClient) RevertLogLevels(ctx context.Context, in *common.Envelope,
 	return out, nil
 }
 
+func (c *adminClient) ReloadConfig(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (*ReloadConfigResponse, error) {
+	out := new(ReloadConfigResponse)
+	err := grpc.Invoke(ctx, "/protos.Admin/ReloadConfig", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListStateIndexes(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (*StateIndexesResponse, error) {
+	out := new(StateIndexesResponse)
+	err := grpc.Invoke(ctx, "/protos.Admin/ListStateIndexes", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) CreateStateIndex(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (*StateIndexesResponse, error) {
+	out := new(StateIndexesResponse)
+	err := grpc.Invoke(ctx, "/protos.Admin/CreateStateIndex", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) RebuildStateIndexes(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (*StateIndexesResponse, error) {
+	out := new(StateIndexesResponse)
+	err := grpc.Invoke(ctx, "/protos.Admin/RebuildStateIndexes", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) GetChaincodeStatus(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (*ChaincodeStatusResponse, error) {
+	out := new(ChaincodeStatusResponse)
+	err := grpc.Invoke(ctx, "/protos.Admin/GetChaincodeStatus", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListSnapshots(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (*SnapshotsResponse, error) {
+	out := new(SnapshotsResponse)
+	err := grpc.Invoke(ctx, "/protos.Admin/ListSnapshots", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListExpiringCertificates(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (*ExpiringCertificatesResponse, error) {
+	out := new(ExpiringCertificatesResponse)
+	err := grpc.Invoke(ctx, "/protos.Admin/ListExpiringCertificates", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Admin service
 
 type AdminServer interface {
@@ -389,6 +1267,13 @@ type AdminServer interface {
 	GetModuleLogLevel(context.Context, *common.Envelope) (*LogLevelResponse, error)
 	SetModuleLogLevel(context.Context, *common.Envelope) (*LogLevelResponse, error)
 	RevertLogLevels(context.Context, *common.Envelope) (*empty.Empty, error)
+	ReloadConfig(context.Context, *common.Envelope) (*ReloadConfigResponse, error)
+	ListStateIndexes(context.Context, *common.Envelope) (*StateIndexesResponse, error)
+	CreateStateIndex(context.Context, *common.Envelope) (*StateIndexesResponse, error)
+	RebuildStateIndexes(context.Context, *common.Envelope) (*StateIndexesResponse, error)
+	GetChaincodeStatus(context.Context, *common.Envelope) (*ChaincodeStatusResponse, error)
+	ListSnapshots(context.Context, *common.Envelope) (*SnapshotsResponse, error)
+	ListExpiringCertificates(context.Context, *common.Envelope) (*ExpiringCertificatesResponse, error)
 }
 
 func RegisterAdminServer(s *grpc.Server, srv AdminServer) {
@@ -485,6 +1370,132 @@ func _Admin_RevertLogLevels_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Admin_ReloadConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(common.Envelope)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ReloadConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.Admin/ReloadConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ReloadConfig(ctx, req.(*common.Envelope))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListStateIndexes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(common.Envelope)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListStateIndexes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.Admin/ListStateIndexes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListStateIndexes(ctx, req.(*common.Envelope))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_CreateStateIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(common.Envelope)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).CreateStateIndex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.Admin/CreateStateIndex",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).CreateStateIndex(ctx, req.(*common.Envelope))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_RebuildStateIndexes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(common.Envelope)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).RebuildStateIndexes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.Admin/RebuildStateIndexes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).RebuildStateIndexes(ctx, req.(*common.Envelope))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_GetChaincodeStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(common.Envelope)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetChaincodeStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.Admin/GetChaincodeStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetChaincodeStatus(ctx, req.(*common.Envelope))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListSnapshots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(common.Envelope)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListSnapshots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.Admin/ListSnapshots",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListSnapshots(ctx, req.(*common.Envelope))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListExpiringCertificates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(common.Envelope)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListExpiringCertificates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.Admin/ListExpiringCertificates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListExpiringCertificates(ctx, req.(*common.Envelope))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Admin_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "protos.Admin",
 	HandlerType: (*AdminServer)(nil),
@@ -509,43 +1520,118 @@ var _Admin_serviceDesc = grpc.ServiceDesc{
 			MethodName: "RevertLogLevels",
 			Handler:    _Admin_RevertLogLevels_Handler,
 		},
+		{
+			MethodName: "ReloadConfig",
+			Handler:    _Admin_ReloadConfig_Handler,
+		},
+		{
+			MethodName: "ListStateIndexes",
+			Handler:    _Admin_ListStateIndexes_Handler,
+		},
+		{
+			MethodName: "CreateStateIndex",
+			Handler:    _Admin_CreateStateIndex_Handler,
+		},
+		{
+			MethodName: "RebuildStateIndexes",
+			Handler:    _Admin_RebuildStateIndexes_Handler,
+		},
+		{
+			MethodName: "GetChaincodeStatus",
+			Handler:    _Admin_GetChaincodeStatus_Handler,
+		},
+		{
+			MethodName: "ListSnapshots",
+			Handler:    _Admin_ListSnapshots_Handler,
+		},
+		{
+			MethodName: "ListExpiringCertificates",
+			Handler:    _Admin_ListExpiringCertificates_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "peer/admin.proto",
 }
 
-func init() { proto.RegisterFile("peer/admin.proto", fileDescriptor_admin_6c8e47987088ed1f) }
-
-var fileDescriptor_admin_6c8e47987088ed1f = []byte{
-	// 466 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x53, 0xdd, 0x6e, 0xd3, 0x30,
-	0x14, 0x6e, 0x0b, 0xed, 0xc8, 0xe9, 0xd8, 0x82, 0x41, 0x50, 0x75, 0x42, 0xa0, 0x5c, 0xc1, 0x8d,
-	0x23, 0x8a, 0xd0, 0xae, 0xb8, 0x68, 0x49, 0x18, 0x88, 0x2d, 0xad, 0x9c, 0x55, 0x08, 0x24, 0x34,
-	0xa5, 0xed, 0x99, 0x57, 0xe1, 0xc4, 0x99, 0xe3, 0x54, 0xda, 0xeb, 0xf0, 0x1c, 0x3c, 0x1c, 0x8a,
-	0x9d, 0x68, 0x13, 0xf4, 0x06, 0xf5, 0xea, 0xf8, 0x1c, 0x7f, 0xdf, 0x67, 0x9f, 0x3f, 0x70, 0x73,
-	0x44, 0xe5, 0x27, 0xab, 0x74, 0x9d, 0xd1, 0x5c, 0x49, 0x2d, 0x49, 0xcf, 0x98, 0x62, 0x78, 0xc4,
-	0xa5, 0xe4, 0x02, 0x7d, 0xe3, 0x2e, 0xca, 0x4b, 0x1f, 0xd3, 0x5c, 0xdf, 0x58, 0xd0, 0xf0, 0xf1,
-	0x52, 0xa6, 0xa9, 0xcc, 0x7c, 0x6b, 0x6c, 0xd0, 0xfb, 0xd5, 0x86, 0xfd, 0x18, 0xd5, 0x06, 0x55,
-	0xac, 0x13, 0x5d, 0x16, 0xe4, 0x18, 0x7a, 0x85, 0x39, 0x0d, 0xda, 0x2f, 0xdb, 0xaf, 0x0e, 0x46,
-	0x2f, 0x2c, 0xb0, 0xa0, 0x77, 0x51, 0xd4, 0x9a, 0x0f, 0x72, 0x85, 0xac, 0x86, 0x7b, 0xdf, 0x00,
-	0x6e, 0xa3, 0xe4, 0x21, 0x38, 0xf3, 0x28, 0x08, 0x3f, 0x7e, 0x8e, 0xc2, 0xc0, 0x6d, 0x91, 0x3e,
-	0xec, 0xc5, 0xe7, 0x63, 0x76, 0x1e, 0x06, 0x6e, 0xdb, 0x3a, 0xd3, 0xd9, 0x2c, 0x0c, 0xdc, 0x0e,
-	0x01, 0xe8, 0xcd, 0xc6, 0xf3, 0x38, 0x0c, 0xdc, 0x7b, 0xc4, 0x81, 0x6e, 0xc8, 0xd8, 0x94, 0xb9,
-	0xf7, 0x2b, 0xcc, 0x3c, 0xfa, 0x12, 0x4d, 0xbf, 0x46, 0x6e, 0xd7, 0x3b, 0x83, 0xc3, 0x53, 0xc9,
-	0x4f, 0x71, 0x83, 0x82, 0xe1, 0x75, 0x89, 0x85, 0x26, 0xcf, 0x01, 0x84, 0xe4, 0x17, 0xa9, 0x5c,
-	0x95, 0x02, 0xcd, 0x57, 0x1d, 0xe6, 0x08, 0xc9, 0xcf, 0x4c, 0x80, 0x1c, 0x41, 0xe5, 0x5c, 0x88,
-	0x8a, 0x32, 0xe8, 0x98, 0xdb, 0x07, 0xa2, 0x96, 0xf0, 0x22, 0x70, 0x6f, 0xe5, 0x8a, 0x5c, 0x66,
-	0x05, 0xee, 0xa8, 0x77, 0x30, 0xae, 0x9a, 0x31, 0xcd, 0x51, 0x25, 0x7a, 0x2d, 0x33, 0xf2, 0x06,
-	0x7a, 0x42, 0x72, 0x86, 0xd7, 0x46, 0xa9, 0x3f, 0x7a, 0xd6, 0x14, 0xf1, 0xaf, 0x34, 0x3e, 0xb5,
-	0x58, 0x0d, 0x9c, 0x38, 0xb0, 0xb7, 0x94, 0x99, 0xc6, 0x4c, 0x8f, 0x7e, 0x77, 0xa0, 0x6b, 0x04,
-	0xc9, 0x3b, 0x70, 0x4e, 0x50, 0xd7, 0x9d, 0x71, 0x69, 0xdd, 0xb9, 0x30, 0xdb, 0xa0, 0x90, 0x39,
-	0x0e, 0x9f, 0x6c, 0xeb, 0x8d, 0xd7, 0x22, 0xc7, 0xd0, 0x8f, 0x75, 0xa2, 0xb4, 0x0d, 0xff, 0x07,
-	0x71, 0x0c, 0x8f, 0x4e, 0x50, 0xdb, 0x9c, 0x9b, 0xaf, 0x6e, 0xa1, 0x0f, 0xfe, 0x4d, 0xc7, 0x96,
-	0xd1, 0x4a, 0xc4, 0x3b, 0x4a, 0xbc, 0x87, 0x43, 0x86, 0x1b, 0x54, 0xba, 0xb9, 0xdb, 0x96, 0xfb,
-	0x53, 0x6a, 0x67, 0x9d, 0x36, 0xb3, 0x4e, 0xc3, 0x6a, 0xd6, 0xbd, 0xd6, 0xe4, 0x07, 0x78, 0x52,
-	0x71, 0x7a, 0x75, 0x93, 0xa3, 0x12, 0xb8, 0xe2, 0xa8, 0xe8, 0x65, 0xb2, 0x50, 0xeb, 0x65, 0xf3,
-	0x64, 0xb5, 0x3e, 0x93, 0x7d, 0x53, 0xe1, 0x59, 0xb2, 0xfc, 0x99, 0x70, 0xfc, 0xfe, 0x9a, 0xaf,
-	0xf5, 0x55, 0xb9, 0xa8, 0x5e, 0xf1, 0xef, 0x10, 0x7d, 0x4b, 0xb4, 0xfb, 0x54, 0xf8, 0x15, 0x71,
-	0x61, 0x77, 0xed, 0xed, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xaf, 0x20, 0x26, 0x0b, 0x86, 0x03,
-	0x00, 0x00,
+func init() { proto.RegisterFile("peer/admin.proto", fileDescriptor_admin_1e2ccbeee30ad42e) }
+
+var fileDescriptor_admin_1e2ccbeee30ad42e = []byte{
+	// 1219 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x57, 0xdd, 0x72, 0xdb, 0x44,
+	0x14, 0x8e, 0xda, 0xd8, 0x89, 0x8f, 0x9d, 0x44, 0xdd, 0x96, 0xd6, 0x49, 0xfa, 0x87, 0xca, 0x40,
+	0x99, 0x61, 0xec, 0x69, 0x3a, 0x10, 0xb8, 0xa0, 0x90, 0xc4, 0x26, 0xcd, 0xb4, 0x75, 0x82, 0x9c,
+	0x0e, 0x03, 0x33, 0x8c, 0x47, 0x96, 0x8e, 0x25, 0x51, 0x49, 0xab, 0x6a, 0xd7, 0xa1, 0x79, 0x04,
+	0xee, 0xb8, 0xe4, 0x9a, 0x67, 0xe0, 0x96, 0x37, 0xe2, 0x86, 0x37, 0x60, 0xf6, 0x47, 0xb2, 0x62,
+	0xab, 0x2d, 0xd0, 0x2b, 0x6b, 0xcf, 0xcf, 0xb7, 0x67, 0xbf, 0x3d, 0xdf, 0xee, 0x1a, 0xcc, 0x14,
+	0x31, 0xeb, 0x3a, 0x5e, 0x1c, 0x26, 0x9d, 0x34, 0xa3, 0x9c, 0x92, 0xba, 0xfc, 0x61, 0x5b, 0xb7,
+	0x7d, 0x4a, 0xfd, 0x08, 0xbb, 0x72, 0x38, 0x9e, 0x4e, 0xba, 0xde, 0x34, 0x73, 0x78, 0x48, 0x75,
+	0xdc, 0xd6, 0xf6, 0xbc, 0x1f, 0xe3, 0x94, 0x9f, 0x6b, 0xe7, 0x9d, 0x79, 0x27, 0x0f, 0x63, 0x64,
+	0xdc, 0x89, 0x53, 0x1d, 0x70, 0xd5, 0xa5, 0x71, 0x4c, 0x93, 0xae, 0xfa, 0xd1, 0xc6, 0x4d, 0x59,
+	0x8c, 0x1b, 0x38, 0x61, 0xe2, 0x52, 0x0f, 0x47, 0x2c, 0x08, 0x63, 0xe5, 0xb2, 0x7e, 0x37, 0xa0,
+	0x35, 0xc4, 0xec, 0x0c, 0xb3, 0x21, 0x77, 0xf8, 0x94, 0x91, 0x5d, 0xa8, 0x33, 0xf9, 0xd5, 0x36,
+	0xee, 0x1a, 0xf7, 0xd7, 0x77, 0xee, 0xa8, 0x40, 0xd6, 0x29, 0x47, 0x75, 0xd4, 0xcf, 0x01, 0xf5,
+	0xd0, 0xd6, 0xe1, 0xd6, 0xf7, 0x00, 0x33, 0x2b, 0x59, 0x83, 0xc6, 0xf3, 0x41, 0xaf, 0xff, 0xcd,
+	0xd1, 0xa0, 0xdf, 0x33, 0x97, 0x48, 0x13, 0x56, 0x86, 0xa7, 0x7b, 0xf6, 0x69, 0xbf, 0x67, 0x1a,
+	0x6a, 0x70, 0x7c, 0x72, 0xd2, 0xef, 0x99, 0x97, 0x08, 0x40, 0xfd, 0x64, 0xef, 0xf9, 0xb0, 0xdf,
+	0x33, 0x2f, 0x93, 0x06, 0xd4, 0xfa, 0xb6, 0x7d, 0x6c, 0x9b, 0xcb, 0x22, 0xe6, 0xf9, 0xe0, 0xc9,
+	0xe0, 0xf8, 0xbb, 0x81, 0x59, 0xb3, 0x9e, 0xc1, 0xc6, 0x53, 0xea, 0x3f, 0xc5, 0x33, 0x8c, 0x6c,
+	0x7c, 0x39, 0x45, 0xc6, 0xc9, 0x2d, 0x80, 0x88, 0xfa, 0xa3, 0x98, 0x7a, 0xd3, 0x08, 0x65, 0xa9,
+	0x0d, 0xbb, 0x11, 0x51, 0xff, 0x99, 0x34, 0x90, 0x6d, 0x10, 0x83, 0x51, 0x24, 0x52, 0xda, 0x97,
+	0xa4, 0x77, 0x35, 0xd2, 0x10, 0xd6, 0x00, 0xcc, 0x19, 0x1c, 0x4b, 0x69, 0xc2, 0xf0, 0x9d, 0xf0,
+	0xbe, 0x80, 0x6b, 0x36, 0x46, 0xd4, 0xf1, 0x0e, 0x68, 0x32, 0x09, 0xfd, 0x02, 0xf3, 0x7d, 0x68,
+	0xb9, 0x81, 0x93, 0xf8, 0xe8, 0x8d, 0x5e, 0xe0, 0xb9, 0x20, 0xf4, 0xf2, 0xfd, 0x86, 0xdd, 0xd4,
+	0xb6, 0x27, 0x78, 0xce, 0xac, 0x5f, 0x0d, 0xb8, 0x22, 0x58, 0xc3, 0xa3, 0xc4, 0xc3, 0x57, 0xa5,
+	0xc5, 0x89, 0xa0, 0x04, 0xa3, 0x51, 0xe8, 0xe5, 0xc5, 0x68, 0xcb, 0x91, 0x47, 0x6e, 0x42, 0xa3,
+	0xd8, 0x4b, 0x5d, 0xcc, 0xcc, 0x40, 0x6e, 0x03, 0xb8, 0x34, 0x8a, 0xd0, 0x15, 0x3d, 0xd5, 0xbe,
+	0x2c, 0xdd, 0x25, 0x8b, 0xf0, 0x7b, 0x38, 0x09, 0x93, 0x50, 0xfa, 0x97, 0xef, 0x1a, 0xf7, 0x5b,
+	0x76, 0xc9, 0x62, 0xfd, 0x62, 0x00, 0xcc, 0x4a, 0x9a, 0x83, 0x33, 0x16, 0xe0, 0x3e, 0x82, 0x0d,
+	0x0f, 0x59, 0xe8, 0x27, 0x23, 0x8f, 0xba, 0xd3, 0x18, 0x13, 0xae, 0x4b, 0x5a, 0x57, 0xe6, 0x9e,
+	0xb6, 0x12, 0x02, 0xcb, 0x89, 0x13, 0xa3, 0xae, 0x48, 0x7e, 0x57, 0xd4, 0xd2, 0xb8, 0x50, 0x4b,
+	0x0f, 0xae, 0xcd, 0x4a, 0x41, 0x56, 0x30, 0xfb, 0x09, 0xac, 0x84, 0xca, 0x24, 0x49, 0x6d, 0xee,
+	0x90, 0xa2, 0x4b, 0x67, 0x64, 0xe6, 0x21, 0xd6, 0x67, 0x70, 0xfd, 0x20, 0xa7, 0x47, 0xb5, 0x68,
+	0x4e, 0xf4, 0x05, 0x26, 0x8d, 0x39, 0x26, 0xad, 0x3f, 0x0d, 0xd8, 0x98, 0x4b, 0x2c, 0x56, 0x61,
+	0x94, 0x56, 0xb1, 0x07, 0xeb, 0x91, 0xc3, 0xf8, 0x28, 0x40, 0x27, 0xe3, 0x63, 0x74, 0x14, 0x03,
+	0xcd, 0x9d, 0xad, 0x8e, 0x52, 0x6b, 0x27, 0x57, 0x6b, 0xe7, 0x34, 0x57, 0xab, 0xbd, 0x26, 0x32,
+	0x1e, 0xe7, 0x09, 0xe4, 0x21, 0xd4, 0x84, 0x8c, 0x98, 0x64, 0xa7, 0xb9, 0x73, 0x2b, 0x5f, 0x4e,
+	0x31, 0xbd, 0x3d, 0x4d, 0x84, 0xd4, 0x45, 0x15, 0xcc, 0x56, 0xb1, 0x82, 0xbd, 0x4c, 0x31, 0x12,
+	0x9e, 0xa1, 0x64, 0x6f, 0xd5, 0x2e, 0x59, 0x2c, 0x1b, 0x6e, 0x2c, 0xac, 0x5b, 0x13, 0xb8, 0x2b,
+	0x3b, 0x4c, 0xb9, 0x72, 0x0e, 0x6f, 0x2c, 0x4c, 0xaa, 0x93, 0x4a, 0xa1, 0xd6, 0x03, 0x30, 0x87,
+	0x89, 0x93, 0xb2, 0x80, 0x72, 0xf6, 0xef, 0xda, 0xd5, 0xfa, 0xcd, 0x80, 0xd5, 0x3c, 0x87, 0x5c,
+	0x87, 0x7a, 0x80, 0xa1, 0x1f, 0x70, 0x19, 0xb7, 0x6c, 0xeb, 0x91, 0xe0, 0x35, 0x75, 0x78, 0xa0,
+	0x7b, 0x47, 0x7e, 0x93, 0x0f, 0x61, 0x43, 0xf2, 0x3a, 0x8e, 0xa8, 0xfb, 0x62, 0x14, 0x38, 0x2c,
+	0x90, 0xf4, 0xb4, 0x14, 0x79, 0xfb, 0xc2, 0xfa, 0xd8, 0x61, 0x01, 0xf9, 0x1c, 0x1a, 0xc5, 0x31,
+	0xd8, 0x5e, 0x7e, 0x2b, 0xf5, 0xb3, 0x60, 0xeb, 0x00, 0xae, 0x94, 0x56, 0xa3, 0xb9, 0xe9, 0x40,
+	0x83, 0xe5, 0x46, 0x4d, 0x8d, 0x59, 0xb4, 0x97, 0x76, 0xd8, 0xb3, 0x10, 0xeb, 0x04, 0xb6, 0xfb,
+	0xaf, 0xd2, 0x30, 0x0b, 0x13, 0xff, 0x00, 0x33, 0x1e, 0x4e, 0x42, 0xd7, 0xe1, 0x58, 0xb0, 0xf3,
+	0x00, 0xea, 0x3f, 0x87, 0x3c, 0x08, 0x95, 0x78, 0x9a, 0x3b, 0x9b, 0x0b, 0xa5, 0xf5, 0xf4, 0x05,
+	0x60, 0xeb, 0x40, 0xeb, 0x6f, 0x03, 0xae, 0x56, 0x40, 0xbe, 0xed, 0x5c, 0x78, 0x0f, 0xea, 0x31,
+	0x4b, 0x85, 0x4b, 0xb1, 0x58, 0x8b, 0x59, 0x7a, 0xe4, 0x09, 0x6a, 0x33, 0x1a, 0x15, 0xc2, 0x13,
+	0xdf, 0xa4, 0x0d, 0x2b, 0x6c, 0x3a, 0xfe, 0x09, 0x5d, 0xae, 0x55, 0x97, 0x0f, 0xc9, 0x3d, 0x58,
+	0x63, 0x98, 0x85, 0x4e, 0x34, 0x4a, 0xa6, 0xf1, 0x18, 0xb3, 0x76, 0x4d, 0xfa, 0x5b, 0xca, 0x38,
+	0x90, 0x36, 0xb2, 0x0b, 0x8d, 0x84, 0xf2, 0x91, 0x33, 0xe1, 0x98, 0xb5, 0xeb, 0x6f, 0x65, 0x7c,
+	0x35, 0xa1, 0x7c, 0x4f, 0xc4, 0x8a, 0x79, 0x51, 0x2c, 0x0c, 0xbd, 0xf6, 0x8a, 0xec, 0xd7, 0x7c,
+	0x68, 0x8d, 0xe0, 0x66, 0x35, 0x8b, 0x7a, 0x57, 0xbe, 0x82, 0x96, 0x5b, 0xb2, 0xeb, 0x8d, 0xd9,
+	0xce, 0x37, 0xa6, 0x22, 0xd7, 0xbe, 0x90, 0x60, 0xfd, 0x75, 0x09, 0xd6, 0xf7, 0xc4, 0x7d, 0x7c,
+	0x9c, 0xa2, 0xe2, 0x5b, 0x6c, 0x4d, 0x44, 0x7d, 0x1b, 0x5f, 0xea, 0xad, 0x29, 0x14, 0x30, 0x77,
+	0xdb, 0x3c, 0x5e, 0xb2, 0x75, 0x20, 0xd9, 0x85, 0xd5, 0x50, 0x1f, 0xd5, 0x5a, 0xe5, 0x9b, 0x15,
+	0x47, 0x4f, 0x91, 0x56, 0x04, 0x93, 0x13, 0x20, 0xee, 0xc2, 0x21, 0xa4, 0xe5, 0x7e, 0xfb, 0x75,
+	0xca, 0x2b, 0x70, 0x2a, 0x72, 0xc9, 0x23, 0x68, 0xb1, 0x92, 0x14, 0x75, 0xe7, 0xb7, 0xe7, 0x5b,
+	0xb5, 0x84, 0x72, 0x21, 0x9e, 0x7c, 0x0b, 0x26, 0x96, 0x58, 0x93, 0x18, 0x35, 0x89, 0x71, 0xef,
+	0x0d, 0xac, 0x96, 0xe0, 0x16, 0xd2, 0xf7, 0x1b, 0xb0, 0xe2, 0xd2, 0x84, 0x63, 0xc2, 0x77, 0xfe,
+	0xa8, 0x43, 0x4d, 0xd2, 0x4d, 0x3e, 0x85, 0xc6, 0x21, 0x72, 0x7d, 0x7e, 0x9a, 0x1d, 0xfd, 0x32,
+	0xe9, 0x27, 0x67, 0x18, 0xd1, 0x14, 0xb7, 0xae, 0x55, 0x3d, 0x30, 0xac, 0x25, 0xb2, 0x0b, 0xcd,
+	0x21, 0x77, 0x32, 0xae, 0xcc, 0xff, 0x21, 0x71, 0x0f, 0xae, 0x1c, 0x22, 0x57, 0x17, 0x77, 0xbe,
+	0x91, 0x15, 0xe9, 0xed, 0xc5, 0xcd, 0x56, 0xad, 0xa6, 0x20, 0x86, 0xef, 0x08, 0xf1, 0x25, 0x6c,
+	0xd8, 0x78, 0x86, 0x19, 0xcf, 0x7d, 0x55, 0x6b, 0xbf, 0xbe, 0x20, 0x9a, 0xbe, 0x78, 0xec, 0x59,
+	0x4b, 0xe4, 0x6b, 0x68, 0x95, 0xdf, 0x14, 0x15, 0xb9, 0x37, 0xf3, 0xc9, 0xab, 0xde, 0x1e, 0xd6,
+	0x12, 0xe9, 0x81, 0xf9, 0x34, 0x64, 0xbc, 0x7c, 0x7f, 0xbe, 0x09, 0xa5, 0xea, 0x9e, 0x55, 0x28,
+	0x07, 0x19, 0x3a, 0x1c, 0x67, 0xfe, 0xff, 0x81, 0x72, 0x08, 0x57, 0x6d, 0x1c, 0x4f, 0xc3, 0xc8,
+	0x7b, 0xc7, 0x72, 0x8e, 0x80, 0x1c, 0x22, 0x9f, 0xbf, 0x94, 0x17, 0x71, 0xee, 0xbc, 0x56, 0x51,
+	0x05, 0xd4, 0x23, 0x58, 0x93, 0xfc, 0xe4, 0x92, 0xa8, 0x40, 0xd9, 0xac, 0xd0, 0x52, 0x91, 0x7f,
+	0x0a, 0x6d, 0x91, 0x5f, 0x25, 0x91, 0x0a, 0xa8, 0x0f, 0xde, 0x2c, 0xa9, 0x1c, 0x75, 0xff, 0x47,
+	0xb0, 0x68, 0xe6, 0x77, 0x82, 0xf3, 0x14, 0xb3, 0x08, 0x3d, 0x1f, 0xb3, 0xce, 0xc4, 0x19, 0x67,
+	0xa1, 0x9b, 0xe7, 0x8b, 0xa7, 0xfc, 0x7e, 0x4b, 0x2a, 0xeb, 0xc4, 0x71, 0x5f, 0x38, 0x3e, 0xfe,
+	0xf0, 0xb1, 0x1f, 0xf2, 0x60, 0x3a, 0x16, 0x73, 0x76, 0x4b, 0x89, 0x5d, 0x95, 0xa8, 0xfe, 0x2b,
+	0xb0, 0xae, 0x48, 0x1c, 0xab, 0x3f, 0x21, 0x0f, 0xff, 0x19, 0x00, 0xc1, 0xe0, 0x4e, 0x23, 0x9f,
+	0x0c, 0x00, 0x00,
 }
@@ -27,27 +27,31 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 type ChaincodeMessage_Type int32
 
 const (
-	ChaincodeMessage_UNDEFINED           ChaincodeMessage_Type = 0
-	ChaincodeMessage_REGISTER            ChaincodeMessage_Type = 1
-	ChaincodeMessage_REGISTERED          ChaincodeMessage_Type = 2
-	ChaincodeMessage_INIT                ChaincodeMessage_Type = 3
-	ChaincodeMessage_READY               ChaincodeMessage_Type = 4
-	ChaincodeMessage_TRANSACTION         ChaincodeMessage_Type = 5
-	ChaincodeMessage_COMPLETED           ChaincodeMessage_Type = 6
-	ChaincodeMessage_ERROR               ChaincodeMessage_Type = 7
-	ChaincodeMessage_GET_STATE           ChaincodeMessage_Type = 8
-	ChaincodeMessage_PUT_STATE           ChaincodeMessage_Type = 9
-	ChaincodeMessage_DEL_STATE           ChaincodeMessage_Type = 10
-	ChaincodeMessage_INVOKE_CHAINCODE    ChaincodeMessage_Type = 11
-	ChaincodeMessage_RESPONSE            ChaincodeMessage_Type = 13
-	ChaincodeMessage_GET_STATE_BY_RANGE  ChaincodeMessage_Type = 14
-	ChaincodeMessage_GET_QUERY_RESULT    ChaincodeMessage_Type = 15
-	ChaincodeMessage_QUERY_STATE_NEXT    ChaincodeMessage_Type = 16
-	ChaincodeMessage_QUERY_STATE_CLOSE   ChaincodeMessage_Type = 17
-	ChaincodeMessage_KEEPALIVE           ChaincodeMessage_Type = 18
-	ChaincodeMessage_GET_HISTORY_FOR_KEY ChaincodeMessage_Type = 19
-	ChaincodeMessage_GET_STATE_METADATA  ChaincodeMessage_Type = 20
-	ChaincodeMessage_PUT_STATE_METADATA  ChaincodeMessage_Type = 21
+	ChaincodeMessage_UNDEFINED                ChaincodeMessage_Type = 0
+	ChaincodeMessage_REGISTER                 ChaincodeMessage_Type = 1
+	ChaincodeMessage_REGISTERED               ChaincodeMessage_Type = 2
+	ChaincodeMessage_INIT                     ChaincodeMessage_Type = 3
+	ChaincodeMessage_READY                    ChaincodeMessage_Type = 4
+	ChaincodeMessage_TRANSACTION              ChaincodeMessage_Type = 5
+	ChaincodeMessage_COMPLETED                ChaincodeMessage_Type = 6
+	ChaincodeMessage_ERROR                    ChaincodeMessage_Type = 7
+	ChaincodeMessage_GET_STATE                ChaincodeMessage_Type = 8
+	ChaincodeMessage_PUT_STATE                ChaincodeMessage_Type = 9
+	ChaincodeMessage_DEL_STATE                ChaincodeMessage_Type = 10
+	ChaincodeMessage_INVOKE_CHAINCODE         ChaincodeMessage_Type = 11
+	ChaincodeMessage_RESPONSE                 ChaincodeMessage_Type = 13
+	ChaincodeMessage_GET_STATE_BY_RANGE       ChaincodeMessage_Type = 14
+	ChaincodeMessage_GET_QUERY_RESULT         ChaincodeMessage_Type = 15
+	ChaincodeMessage_QUERY_STATE_NEXT         ChaincodeMessage_Type = 16
+	ChaincodeMessage_QUERY_STATE_CLOSE        ChaincodeMessage_Type = 17
+	ChaincodeMessage_KEEPALIVE                ChaincodeMessage_Type = 18
+	ChaincodeMessage_GET_HISTORY_FOR_KEY      ChaincodeMessage_Type = 19
+	ChaincodeMessage_GET_STATE_METADATA       ChaincodeMessage_Type = 20
+	ChaincodeMessage_PUT_STATE_METADATA       ChaincodeMessage_Type = 21
+	ChaincodeMessage_GET_PRIVATE_DATA_HASH    ChaincodeMessage_Type = 22
+	ChaincodeMessage_GET_STATE_MULTIPLE       ChaincodeMessage_Type = 23
+	ChaincodeMessage_HEARTBEAT                ChaincodeMessage_Type = 24
+	ChaincodeMessage_VERIFY_PRIVATE_DATA_HASH ChaincodeMessage_Type = 25
 )
 
 var ChaincodeMessage_Type_name = map[int32]string{
@@ -72,36 +76,44 @@ var ChaincodeMessage_Type_name = map[int32]string{
 	19: "GET_HISTORY_FOR_KEY",
 	20: "GET_STATE_METADATA",
 	21: "PUT_STATE_METADATA",
+	22: "GET_PRIVATE_DATA_HASH",
+	23: "GET_STATE_MULTIPLE",
+	24: "HEARTBEAT",
+	25: "VERIFY_PRIVATE_DATA_HASH",
 }
 var ChaincodeMessage_Type_value = map[string]int32{
-	"UNDEFINED":           0,
-	"REGISTER":            1,
-	"REGISTERED":          2,
-	"INIT":                3,
-	"READY":               4,
-	"TRANSACTION":         5,
-	"COMPLETED":           6,
-	"ERROR":               7,
-	"GET_STATE":           8,
-	"PUT_STATE":           9,
-	"DEL_STATE":           10,
-	"INVOKE_CHAINCODE":    11,
-	"RESPONSE":            13,
-	"GET_STATE_BY_RANGE":  14,
-	"GET_QUERY_RESULT":    15,
-	"QUERY_STATE_NEXT":    16,
-	"QUERY_STATE_CLOSE":   17,
-	"KEEPALIVE":           18,
-	"GET_HISTORY_FOR_KEY": 19,
-	"GET_STATE_METADATA":  20,
-	"PUT_STATE_METADATA":  21,
+	"UNDEFINED":                0,
+	"REGISTER":                 1,
+	"REGISTERED":               2,
+	"INIT":                     3,
+	"READY":                    4,
+	"TRANSACTION":              5,
+	"COMPLETED":                6,
+	"ERROR":                    7,
+	"GET_STATE":                8,
+	"PUT_STATE":                9,
+	"DEL_STATE":                10,
+	"INVOKE_CHAINCODE":         11,
+	"RESPONSE":                 13,
+	"GET_STATE_BY_RANGE":       14,
+	"GET_QUERY_RESULT":         15,
+	"QUERY_STATE_NEXT":         16,
+	"QUERY_STATE_CLOSE":        17,
+	"KEEPALIVE":                18,
+	"GET_HISTORY_FOR_KEY":      19,
+	"GET_STATE_METADATA":       20,
+	"PUT_STATE_METADATA":       21,
+	"GET_PRIVATE_DATA_HASH":    22,
+	"GET_STATE_MULTIPLE":       23,
+	"HEARTBEAT":                24,
+	"VERIFY_PRIVATE_DATA_HASH": 25,
 }
 
 func (x ChaincodeMessage_Type) String() string {
 	return proto.EnumName(ChaincodeMessage_Type_name, int32(x))
 }
 func (ChaincodeMessage_Type) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{0, 0}
+	return fileDescriptor_chaincode_shim_70027e04f99f8756, []int{0, 0}
 }
 
 type ChaincodeMessage struct {
@@ -115,17 +127,20 @@ type ChaincodeMessage struct {
 	// with Block.NonHashData.TransactionResult
 	ChaincodeEvent *ChaincodeEvent `protobuf:"bytes,6,opt,name=chaincode_event,json=chaincodeEvent" json:"chaincode_event,omitempty"`
 	// channel id
-	ChannelId            string   `protobuf:"bytes,7,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	ChannelId string `protobuf:"bytes,7,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+	// deadline of the execution of the transaction, set on the INIT and
+	// TRANSACTION messages sent to the chaincode
+	Deadline             *timestamp.Timestamp `protobuf:"bytes,8,opt,name=deadline" json:"deadline,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
 func (m *ChaincodeMessage) Reset()         { *m = ChaincodeMessage{} }
 func (m *ChaincodeMessage) String() string { return proto.CompactTextString(m) }
 func (*ChaincodeMessage) ProtoMessage()    {}
 func (*ChaincodeMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{0}
+	return fileDescriptor_chaincode_shim_70027e04f99f8756, []int{0}
 }
 func (m *ChaincodeMessage) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChaincodeMessage.Unmarshal(m, b)
@@ -194,12 +209,22 @@ func (m *ChaincodeMessage) GetChannelId() string {
 	return ""
 }
 
+func (m *ChaincodeMessage) GetDeadline() *timestamp.Timestamp {
+	if m != nil {
+		return m.Deadline
+	}
+	return nil
+}
+
 // GetState is the payload of a ChaincodeMessage. It contains a key which
 // is to be fetched from the ledger. If the collection is specified, the key
-// would be fetched from the collection (i.e., private state)
+// would be fetched from the collection (i.e., private state). An unvalidated
+// read of a collection is not recorded in the read set, so the version of the
+// value is not checked at validation time.
 type GetState struct {
 	Key                  string   `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
 	Collection           string   `protobuf:"bytes,2,opt,name=collection" json:"collection,omitempty"`
+	Unvalidated          bool     `protobuf:"varint,3,opt,name=unvalidated" json:"unvalidated,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -209,7 +234,7 @@ func (m *GetState) Reset()         { *m = GetState{} }
 func (m *GetState) String() string { return proto.CompactTextString(m) }
 func (*GetState) ProtoMessage()    {}
 func (*GetState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{1}
+	return fileDescriptor_chaincode_shim_70027e04f99f8756, []int{1}
 }
 func (m *GetState) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetState.Unmarshal(m, b)
@@ -243,6 +268,103 @@ func (m *GetState) GetCollection() string {
 	return ""
 }
 
+func (m *GetState) GetUnvalidated() bool {
+	if m != nil {
+		return m.Unvalidated
+	}
+	return false
+}
+
+// GetStateMultiple is the payload of a ChaincodeMessage. It contains the keys
+// which are to be fetched from the ledger in a single request. If the collection
+// is specified, the keys would be fetched from the collection (i.e., private state)
+type GetStateMultiple struct {
+	Keys                 []string `protobuf:"bytes,1,rep,name=keys" json:"keys,omitempty"`
+	Collection           string   `protobuf:"bytes,2,opt,name=collection" json:"collection,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStateMultiple) Reset()         { *m = GetStateMultiple{} }
+func (m *GetStateMultiple) String() string { return proto.CompactTextString(m) }
+func (*GetStateMultiple) ProtoMessage()    {}
+func (*GetStateMultiple) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chaincode_shim_70027e04f99f8756, []int{2}
+}
+func (m *GetStateMultiple) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStateMultiple.Unmarshal(m, b)
+}
+func (m *GetStateMultiple) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStateMultiple.Marshal(b, m, deterministic)
+}
+func (dst *GetStateMultiple) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStateMultiple.Merge(dst, src)
+}
+func (m *GetStateMultiple) XXX_Size() int {
+	return xxx_messageInfo_GetStateMultiple.Size(m)
+}
+func (m *GetStateMultiple) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStateMultiple.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStateMultiple proto.InternalMessageInfo
+
+func (m *GetStateMultiple) GetKeys() []string {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+func (m *GetStateMultiple) GetCollection() string {
+	if m != nil {
+		return m.Collection
+	}
+	return ""
+}
+
+// GetStateMultipleResult is returned by the peer as a result of a GetStateMultiple.
+// It holds the values of the requested keys in the same order, a non existing key
+// having an empty value.
+type GetStateMultipleResult struct {
+	Values               [][]byte `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStateMultipleResult) Reset()         { *m = GetStateMultipleResult{} }
+func (m *GetStateMultipleResult) String() string { return proto.CompactTextString(m) }
+func (*GetStateMultipleResult) ProtoMessage()    {}
+func (*GetStateMultipleResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chaincode_shim_70027e04f99f8756, []int{3}
+}
+func (m *GetStateMultipleResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStateMultipleResult.Unmarshal(m, b)
+}
+func (m *GetStateMultipleResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStateMultipleResult.Marshal(b, m, deterministic)
+}
+func (dst *GetStateMultipleResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStateMultipleResult.Merge(dst, src)
+}
+func (m *GetStateMultipleResult) XXX_Size() int {
+	return xxx_messageInfo_GetStateMultipleResult.Size(m)
+}
+func (m *GetStateMultipleResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStateMultipleResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStateMultipleResult proto.InternalMessageInfo
+
+func (m *GetStateMultipleResult) GetValues() [][]byte {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
 type GetStateMetadata struct {
 	Key                  string   `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
 	Collection           string   `protobuf:"bytes,2,opt,name=collection" json:"collection,omitempty"`
@@ -255,7 +377,7 @@ func (m *GetStateMetadata) Reset()         { *m = GetStateMetadata{} }
 func (m *GetStateMetadata) String() string { return proto.CompactTextString(m) }
 func (*GetStateMetadata) ProtoMessage()    {}
 func (*GetStateMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{2}
+	return fileDescriptor_chaincode_shim_70027e04f99f8756, []int{4}
 }
 func (m *GetStateMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetStateMetadata.Unmarshal(m, b)
@@ -306,7 +428,7 @@ func (m *PutState) Reset()         { *m = PutState{} }
 func (m *PutState) String() string { return proto.CompactTextString(m) }
 func (*PutState) ProtoMessage()    {}
 func (*PutState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{3}
+	return fileDescriptor_chaincode_shim_70027e04f99f8756, []int{5}
 }
 func (m *PutState) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PutState.Unmarshal(m, b)
@@ -360,7 +482,7 @@ func (m *PutStateMetadata) Reset()         { *m = PutStateMetadata{} }
 func (m *PutStateMetadata) String() string { return proto.CompactTextString(m) }
 func (*PutStateMetadata) ProtoMessage()    {}
 func (*PutStateMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{4}
+	return fileDescriptor_chaincode_shim_70027e04f99f8756, []int{6}
 }
 func (m *PutStateMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PutStateMetadata.Unmarshal(m, b)
@@ -417,7 +539,7 @@ func (m *DelState) Reset()         { *m = DelState{} }
 func (m *DelState) String() string { return proto.CompactTextString(m) }
 func (*DelState) ProtoMessage()    {}
 func (*DelState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{5}
+	return fileDescriptor_chaincode_shim_70027e04f99f8756, []int{7}
 }
 func (m *DelState) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DelState.Unmarshal(m, b)
@@ -469,7 +591,7 @@ func (m *GetStateByRange) Reset()         { *m = GetStateByRange{} }
 func (m *GetStateByRange) String() string { return proto.CompactTextString(m) }
 func (*GetStateByRange) ProtoMessage()    {}
 func (*GetStateByRange) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{6}
+	return fileDescriptor_chaincode_shim_70027e04f99f8756, []int{8}
 }
 func (m *GetStateByRange) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetStateByRange.Unmarshal(m, b)
@@ -534,7 +656,7 @@ func (m *GetQueryResult) Reset()         { *m = GetQueryResult{} }
 func (m *GetQueryResult) String() string { return proto.CompactTextString(m) }
 func (*GetQueryResult) ProtoMessage()    {}
 func (*GetQueryResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{7}
+	return fileDescriptor_chaincode_shim_70027e04f99f8756, []int{9}
 }
 func (m *GetQueryResult) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetQueryResult.Unmarshal(m, b)
@@ -590,7 +712,7 @@ func (m *QueryMetadata) Reset()         { *m = QueryMetadata{} }
 func (m *QueryMetadata) String() string { return proto.CompactTextString(m) }
 func (*QueryMetadata) ProtoMessage()    {}
 func (*QueryMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{8}
+	return fileDescriptor_chaincode_shim_70027e04f99f8756, []int{10}
 }
 func (m *QueryMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryMetadata.Unmarshal(m, b)
@@ -637,7 +759,7 @@ func (m *GetHistoryForKey) Reset()         { *m = GetHistoryForKey{} }
 func (m *GetHistoryForKey) String() string { return proto.CompactTextString(m) }
 func (*GetHistoryForKey) ProtoMessage()    {}
 func (*GetHistoryForKey) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{9}
+	return fileDescriptor_chaincode_shim_70027e04f99f8756, []int{11}
 }
 func (m *GetHistoryForKey) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetHistoryForKey.Unmarshal(m, b)
@@ -675,7 +797,7 @@ func (m *QueryStateNext) Reset()         { *m = QueryStateNext{} }
 func (m *QueryStateNext) String() string { return proto.CompactTextString(m) }
 func (*QueryStateNext) ProtoMessage()    {}
 func (*QueryStateNext) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{10}
+	return fileDescriptor_chaincode_shim_70027e04f99f8756, []int{12}
 }
 func (m *QueryStateNext) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryStateNext.Unmarshal(m, b)
@@ -713,7 +835,7 @@ func (m *QueryStateClose) Reset()         { *m = QueryStateClose{} }
 func (m *QueryStateClose) String() string { return proto.CompactTextString(m) }
 func (*QueryStateClose) ProtoMessage()    {}
 func (*QueryStateClose) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{11}
+	return fileDescriptor_chaincode_shim_70027e04f99f8756, []int{13}
 }
 func (m *QueryStateClose) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryStateClose.Unmarshal(m, b)
@@ -752,7 +874,7 @@ func (m *QueryResultBytes) Reset()         { *m = QueryResultBytes{} }
 func (m *QueryResultBytes) String() string { return proto.CompactTextString(m) }
 func (*QueryResultBytes) ProtoMessage()    {}
 func (*QueryResultBytes) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{12}
+	return fileDescriptor_chaincode_shim_70027e04f99f8756, []int{14}
 }
 func (m *QueryResultBytes) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryResultBytes.Unmarshal(m, b)
@@ -798,7 +920,7 @@ func (m *QueryResponse) Reset()         { *m = QueryResponse{} }
 func (m *QueryResponse) String() string { return proto.CompactTextString(m) }
 func (*QueryResponse) ProtoMessage()    {}
 func (*QueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{13}
+	return fileDescriptor_chaincode_shim_70027e04f99f8756, []int{15}
 }
 func (m *QueryResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryResponse.Unmarshal(m, b)
@@ -847,10 +969,13 @@ func (m *QueryResponse) GetMetadata() []byte {
 }
 
 // QueryResponseMetadata is the metadata of a QueryResponse. It contains a count
-// which denotes the number of records fetched from the ledger and a bookmark.
+// which denotes the number of records fetched from the ledger, a bookmark and
+// the warning of the state database about the execution of the query, such as
+// the lack of an index matching the selector of a rich query.
 type QueryResponseMetadata struct {
 	FetchedRecordsCount  int32    `protobuf:"varint,1,opt,name=fetched_records_count,json=fetchedRecordsCount" json:"fetched_records_count,omitempty"`
 	Bookmark             string   `protobuf:"bytes,2,opt,name=bookmark" json:"bookmark,omitempty"`
+	Warning              string   `protobuf:"bytes,3,opt,name=warning" json:"warning,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -860,7 +985,7 @@ func (m *QueryResponseMetadata) Reset()         { *m = QueryResponseMetadata{} }
 func (m *QueryResponseMetadata) String() string { return proto.CompactTextString(m) }
 func (*QueryResponseMetadata) ProtoMessage()    {}
 func (*QueryResponseMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{14}
+	return fileDescriptor_chaincode_shim_70027e04f99f8756, []int{16}
 }
 func (m *QueryResponseMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryResponseMetadata.Unmarshal(m, b)
@@ -894,6 +1019,13 @@ func (m *QueryResponseMetadata) GetBookmark() string {
 	return ""
 }
 
+func (m *QueryResponseMetadata) GetWarning() string {
+	if m != nil {
+		return m.Warning
+	}
+	return ""
+}
+
 type StateMetadata struct {
 	Metakey              string   `protobuf:"bytes,1,opt,name=metakey" json:"metakey,omitempty"`
 	Value                []byte   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
@@ -906,7 +1038,7 @@ func (m *StateMetadata) Reset()         { *m = StateMetadata{} }
 func (m *StateMetadata) String() string { return proto.CompactTextString(m) }
 func (*StateMetadata) ProtoMessage()    {}
 func (*StateMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{15}
+	return fileDescriptor_chaincode_shim_70027e04f99f8756, []int{17}
 }
 func (m *StateMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StateMetadata.Unmarshal(m, b)
@@ -951,7 +1083,7 @@ func (m *StateMetadataResult) Reset()         { *m = StateMetadataResult{} }
 func (m *StateMetadataResult) String() string { return proto.CompactTextString(m) }
 func (*StateMetadataResult) ProtoMessage()    {}
 func (*StateMetadataResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{16}
+	return fileDescriptor_chaincode_shim_70027e04f99f8756, []int{18}
 }
 func (m *StateMetadataResult) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StateMetadataResult.Unmarshal(m, b)
@@ -978,9 +1110,147 @@ func (m *StateMetadataResult) GetEntries() []*StateMetadata {
 	return nil
 }
 
+// ChaincodeRuntimeStats is the payload of the HEARTBEAT messages sent
+// periodically by the chaincode to the peer. It describes the transactions
+// being executed by the chaincode and the resources used by its process.
+type ChaincodeRuntimeStats struct {
+	// transactions_in_flight is the number of transactions being executed
+	TransactionsInFlight int32 `protobuf:"varint,1,opt,name=transactions_in_flight,json=transactionsInFlight" json:"transactions_in_flight,omitempty"`
+	// transaction_errors is the number of transactions which failed since
+	// the chaincode started
+	TransactionErrors uint64 `protobuf:"varint,2,opt,name=transaction_errors,json=transactionErrors" json:"transaction_errors,omitempty"`
+	// oldest_transaction_start is the start time of the oldest transaction
+	// being executed, unset if none is
+	OldestTransactionStart *timestamp.Timestamp `protobuf:"bytes,3,opt,name=oldest_transaction_start,json=oldestTransactionStart" json:"oldest_transaction_start,omitempty"`
+	// memory_bytes is the memory obtained from the system by the process
+	MemoryBytes          uint64   `protobuf:"varint,4,opt,name=memory_bytes,json=memoryBytes" json:"memory_bytes,omitempty"`
+	Goroutines           int32    `protobuf:"varint,5,opt,name=goroutines" json:"goroutines,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChaincodeRuntimeStats) Reset()         { *m = ChaincodeRuntimeStats{} }
+func (m *ChaincodeRuntimeStats) String() string { return proto.CompactTextString(m) }
+func (*ChaincodeRuntimeStats) ProtoMessage()    {}
+func (*ChaincodeRuntimeStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chaincode_shim_70027e04f99f8756, []int{19}
+}
+func (m *ChaincodeRuntimeStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChaincodeRuntimeStats.Unmarshal(m, b)
+}
+func (m *ChaincodeRuntimeStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChaincodeRuntimeStats.Marshal(b, m, deterministic)
+}
+func (dst *ChaincodeRuntimeStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChaincodeRuntimeStats.Merge(dst, src)
+}
+func (m *ChaincodeRuntimeStats) XXX_Size() int {
+	return xxx_messageInfo_ChaincodeRuntimeStats.Size(m)
+}
+func (m *ChaincodeRuntimeStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChaincodeRuntimeStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChaincodeRuntimeStats proto.InternalMessageInfo
+
+func (m *ChaincodeRuntimeStats) GetTransactionsInFlight() int32 {
+	if m != nil {
+		return m.TransactionsInFlight
+	}
+	return 0
+}
+
+func (m *ChaincodeRuntimeStats) GetTransactionErrors() uint64 {
+	if m != nil {
+		return m.TransactionErrors
+	}
+	return 0
+}
+
+func (m *ChaincodeRuntimeStats) GetOldestTransactionStart() *timestamp.Timestamp {
+	if m != nil {
+		return m.OldestTransactionStart
+	}
+	return nil
+}
+
+func (m *ChaincodeRuntimeStats) GetMemoryBytes() uint64 {
+	if m != nil {
+		return m.MemoryBytes
+	}
+	return 0
+}
+
+func (m *ChaincodeRuntimeStats) GetGoroutines() int32 {
+	if m != nil {
+		return m.Goroutines
+	}
+	return 0
+}
+
+// VerifyPrivateDataHash is the payload of a ChaincodeMessage. It asks the peer
+// to verify that the preimage is the value of the key in the collection,
+// against the hash of the value committed to the ledger.
+type VerifyPrivateDataHash struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Collection           string   `protobuf:"bytes,2,opt,name=collection" json:"collection,omitempty"`
+	Preimage             []byte   `protobuf:"bytes,3,opt,name=preimage,proto3" json:"preimage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VerifyPrivateDataHash) Reset()         { *m = VerifyPrivateDataHash{} }
+func (m *VerifyPrivateDataHash) String() string { return proto.CompactTextString(m) }
+func (*VerifyPrivateDataHash) ProtoMessage()    {}
+func (*VerifyPrivateDataHash) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chaincode_shim_70027e04f99f8756, []int{20}
+}
+func (m *VerifyPrivateDataHash) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifyPrivateDataHash.Unmarshal(m, b)
+}
+func (m *VerifyPrivateDataHash) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifyPrivateDataHash.Marshal(b, m, deterministic)
+}
+func (dst *VerifyPrivateDataHash) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyPrivateDataHash.Merge(dst, src)
+}
+func (m *VerifyPrivateDataHash) XXX_Size() int {
+	return xxx_messageInfo_VerifyPrivateDataHash.Size(m)
+}
+func (m *VerifyPrivateDataHash) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifyPrivateDataHash.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerifyPrivateDataHash proto.InternalMessageInfo
+
+func (m *VerifyPrivateDataHash) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *VerifyPrivateDataHash) GetCollection() string {
+	if m != nil {
+		return m.Collection
+	}
+	return ""
+}
+
+func (m *VerifyPrivateDataHash) GetPreimage() []byte {
+	if m != nil {
+		return m.Preimage
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*ChaincodeMessage)(nil), "protos.ChaincodeMessage")
 	proto.RegisterType((*GetState)(nil), "protos.GetState")
+	proto.RegisterType((*GetStateMultiple)(nil), "protos.GetStateMultiple")
+	proto.RegisterType((*GetStateMultipleResult)(nil), "protos.GetStateMultipleResult")
 	proto.RegisterType((*GetStateMetadata)(nil), "protos.GetStateMetadata")
 	proto.RegisterType((*PutState)(nil), "protos.PutState")
 	proto.RegisterType((*PutStateMetadata)(nil), "protos.PutStateMetadata")
@@ -996,6 +1266,8 @@ func init() {
 	proto.RegisterType((*QueryResponseMetadata)(nil), "protos.QueryResponseMetadata")
 	proto.RegisterType((*StateMetadata)(nil), "protos.StateMetadata")
 	proto.RegisterType((*StateMetadataResult)(nil), "protos.StateMetadataResult")
+	proto.RegisterType((*ChaincodeRuntimeStats)(nil), "protos.ChaincodeRuntimeStats")
+	proto.RegisterType((*VerifyPrivateDataHash)(nil), "protos.VerifyPrivateDataHash")
 	proto.RegisterEnum("protos.ChaincodeMessage_Type", ChaincodeMessage_Type_name, ChaincodeMessage_Type_value)
 }
 
@@ -1104,73 +1376,91 @@ var _ChaincodeSupport_serviceDesc = grpc.ServiceDesc{
 }
 
 func init() {
-	proto.RegisterFile("peer/chaincode_shim.proto", fileDescriptor_chaincode_shim_3c6cdf1ba834f673)
-}
-
-var fileDescriptor_chaincode_shim_3c6cdf1ba834f673 = []byte{
-	// 1010 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0xcf, 0x73, 0xda, 0x46,
-	0x14, 0x0e, 0x06, 0x8c, 0x78, 0xd8, 0x78, 0xb3, 0x8e, 0x5d, 0xc2, 0x4c, 0x5a, 0xca, 0xf4, 0x40,
-	0x2f, 0xd0, 0xd0, 0x1e, 0x7a, 0xe8, 0x4c, 0x06, 0xc3, 0x1a, 0x33, 0xb6, 0x05, 0x59, 0xc9, 0x99,
-	0xb8, 0x17, 0x8d, 0x90, 0xd6, 0x42, 0x63, 0xa1, 0x55, 0xa5, 0x25, 0x0d, 0xbd, 0xf5, 0xda, 0x7f,
-	0xa9, 0x7f, 0x58, 0xaf, 0x9d, 0xd5, 0x2f, 0x03, 0xae, 0x93, 0x4e, 0x4e, 0xe8, 0x7b, 0xef, 0xdb,
-	0xef, 0xfd, 0xda, 0x87, 0x04, 0x2f, 0x03, 0xc6, 0xc2, 0x9e, 0xb5, 0x30, 0x5d, 0xdf, 0xe2, 0x36,
-	0x33, 0xa2, 0x85, 0xbb, 0xec, 0x06, 0x21, 0x17, 0x1c, 0xef, 0xc7, 0x3f, 0x51, 0xb3, 0xb9, 0x43,
-	0x61, 0x1f, 0x98, 0x2f, 0x12, 0x4e, 0xf3, 0x38, 0xf6, 0x05, 0x21, 0x0f, 0x78, 0x64, 0x7a, 0xa9,
-	0xf1, 0x1b, 0x87, 0x73, 0xc7, 0x63, 0xbd, 0x18, 0xcd, 0x57, 0x77, 0x3d, 0xe1, 0x2e, 0x59, 0x24,
-	0xcc, 0x65, 0x90, 0x10, 0xda, 0x7f, 0x97, 0x01, 0x0d, 0x33, 0xbd, 0x6b, 0x16, 0x45, 0xa6, 0xc3,
-	0xf0, 0x6b, 0x28, 0x89, 0x75, 0xc0, 0x1a, 0x85, 0x56, 0xa1, 0x53, 0xef, 0xbf, 0x4a, 0xa8, 0x51,
-	0x77, 0x97, 0xd7, 0xd5, 0xd7, 0x01, 0xa3, 0x31, 0x15, 0xff, 0x0c, 0xd5, 0x5c, 0xba, 0xb1, 0xd7,
-	0x2a, 0x74, 0x6a, 0xfd, 0x66, 0x37, 0x09, 0xde, 0xcd, 0x82, 0x77, 0xf5, 0x8c, 0x41, 0x1f, 0xc8,
-	0xb8, 0x01, 0x95, 0xc0, 0x5c, 0x7b, 0xdc, 0xb4, 0x1b, 0xc5, 0x56, 0xa1, 0x73, 0x40, 0x33, 0x88,
-	0x31, 0x94, 0xc4, 0x47, 0xd7, 0x6e, 0x94, 0x5a, 0x85, 0x4e, 0x95, 0xc6, 0xcf, 0xb8, 0x0f, 0x4a,
-	0x56, 0x62, 0xa3, 0x1c, 0x87, 0x39, 0xcd, 0xd2, 0xd3, 0x5c, 0xc7, 0x67, 0xf6, 0x2c, 0xf5, 0xd2,
-	0x9c, 0x87, 0xdf, 0xc0, 0xd1, 0x4e, 0xcb, 0x1a, 0xfb, 0xdb, 0x47, 0xf3, 0xca, 0x88, 0xf4, 0xd2,
-	0xba, 0xb5, 0x85, 0xf1, 0x2b, 0x00, 0x6b, 0x61, 0xfa, 0x3e, 0xf3, 0x0c, 0xd7, 0x6e, 0x54, 0xe2,
-	0x74, 0xaa, 0xa9, 0x65, 0x62, 0xb7, 0xff, 0xd9, 0x83, 0x92, 0x6c, 0x05, 0x3e, 0x84, 0xea, 0x8d,
-	0x3a, 0x22, 0xe7, 0x13, 0x95, 0x8c, 0xd0, 0x33, 0x7c, 0x00, 0x0a, 0x25, 0xe3, 0x89, 0xa6, 0x13,
-	0x8a, 0x0a, 0xb8, 0x0e, 0x90, 0x21, 0x32, 0x42, 0x7b, 0x58, 0x81, 0xd2, 0x44, 0x9d, 0xe8, 0xa8,
-	0x88, 0xab, 0x50, 0xa6, 0x64, 0x30, 0xba, 0x45, 0x25, 0x7c, 0x04, 0x35, 0x9d, 0x0e, 0x54, 0x6d,
-	0x30, 0xd4, 0x27, 0x53, 0x15, 0x95, 0xa5, 0xe4, 0x70, 0x7a, 0x3d, 0xbb, 0x22, 0x3a, 0x19, 0xa1,
-	0x7d, 0x49, 0x25, 0x94, 0x4e, 0x29, 0xaa, 0x48, 0xcf, 0x98, 0xe8, 0x86, 0xa6, 0x0f, 0x74, 0x82,
-	0x14, 0x09, 0x67, 0x37, 0x19, 0xac, 0x4a, 0x38, 0x22, 0x57, 0x29, 0x04, 0xfc, 0x02, 0xd0, 0x44,
-	0x7d, 0x37, 0xbd, 0x24, 0xc6, 0xf0, 0x62, 0x30, 0x51, 0x87, 0xd3, 0x11, 0x41, 0xb5, 0x24, 0x41,
-	0x6d, 0x36, 0x55, 0x35, 0x82, 0x0e, 0xf1, 0x29, 0xe0, 0x5c, 0xd0, 0x38, 0xbb, 0x35, 0xe8, 0x40,
-	0x1d, 0x13, 0x54, 0x97, 0x67, 0xa5, 0xfd, 0xed, 0x0d, 0xa1, 0xb7, 0x06, 0x25, 0xda, 0xcd, 0x95,
-	0x8e, 0x8e, 0xa4, 0x35, 0xb1, 0x24, 0x7c, 0x95, 0xbc, 0xd7, 0x11, 0xc2, 0x27, 0xf0, 0x7c, 0xd3,
-	0x3a, 0xbc, 0x9a, 0x6a, 0x04, 0x3d, 0x97, 0xd9, 0x5c, 0x12, 0x32, 0x1b, 0x5c, 0x4d, 0xde, 0x11,
-	0x84, 0xf1, 0x57, 0x70, 0x2c, 0x15, 0x2f, 0x26, 0x9a, 0x3e, 0xa5, 0xb7, 0xc6, 0xf9, 0x94, 0x1a,
-	0x97, 0xe4, 0x16, 0x1d, 0x6f, 0xa7, 0x70, 0x4d, 0xf4, 0xc1, 0x68, 0xa0, 0x0f, 0xd0, 0x0b, 0x69,
-	0xcf, 0x8b, 0x7b, 0xb0, 0x9f, 0xb4, 0x7f, 0x01, 0x65, 0xcc, 0x84, 0x26, 0x4c, 0xc1, 0x30, 0x82,
-	0xe2, 0x3d, 0x5b, 0xc7, 0x77, 0xb6, 0x4a, 0xe5, 0x23, 0xfe, 0x1a, 0xc0, 0xe2, 0x9e, 0xc7, 0x2c,
-	0xe1, 0x72, 0x3f, 0xbe, 0x94, 0x55, 0xba, 0x61, 0x69, 0x8f, 0x00, 0x65, 0xa7, 0xaf, 0x99, 0x30,
-	0x6d, 0x53, 0x98, 0x5f, 0xa0, 0x42, 0x41, 0x99, 0xad, 0x9e, 0xcc, 0xe1, 0x05, 0x94, 0x3f, 0x98,
-	0xde, 0x8a, 0xc5, 0x07, 0x0f, 0x68, 0x02, 0x76, 0x34, 0x8b, 0x8f, 0x34, 0x7f, 0x07, 0x94, 0x69,
-	0xfe, 0xef, 0xcc, 0x1e, 0xa9, 0xe0, 0xd7, 0xa0, 0x2c, 0xd3, 0xd3, 0xf1, 0x0e, 0xd5, 0xfa, 0x27,
-	0xf9, 0xae, 0x6c, 0x4a, 0xd3, 0x9c, 0x26, 0x1b, 0x3a, 0x62, 0xde, 0x97, 0x36, 0xf4, 0xcf, 0x02,
-	0x1c, 0x65, 0x1d, 0x3d, 0x5b, 0x53, 0xd3, 0x77, 0x18, 0x6e, 0x82, 0x12, 0x09, 0x33, 0x14, 0x97,
-	0xb9, 0x54, 0x8e, 0xf1, 0x29, 0xec, 0x33, 0xdf, 0x96, 0x9e, 0x44, 0x2b, 0x45, 0x9f, 0x2d, 0xac,
-	0xb9, 0x53, 0xd8, 0xc1, 0x46, 0x05, 0x73, 0xa8, 0x8f, 0x99, 0x78, 0xbb, 0x62, 0xe1, 0x9a, 0xb2,
-	0x68, 0xe5, 0x09, 0x39, 0x82, 0xdf, 0x24, 0x4c, 0xc3, 0x27, 0xe0, 0x73, 0xb5, 0x6c, 0xc5, 0x28,
-	0xee, 0xc4, 0x18, 0xc3, 0x61, 0x1c, 0x20, 0x9f, 0x4d, 0x13, 0x94, 0xc0, 0x74, 0x98, 0xe6, 0xfe,
-	0x91, 0xfc, 0x69, 0x96, 0x69, 0x8e, 0xa5, 0x6f, 0xce, 0xf9, 0xfd, 0xd2, 0x0c, 0xef, 0xd3, 0x30,
-	0x39, 0x6e, 0x7f, 0x17, 0xdf, 0xc0, 0x0b, 0x37, 0x12, 0x3c, 0x5c, 0x9f, 0xf3, 0x50, 0x16, 0xff,
-	0xa8, 0xed, 0xed, 0x16, 0xd4, 0xe3, 0x70, 0x71, 0x5f, 0x55, 0xf6, 0x51, 0xe0, 0x3a, 0xec, 0xb9,
-	0x76, 0x4a, 0xd9, 0x73, 0xed, 0xf6, 0xb7, 0x70, 0xf4, 0xc0, 0x18, 0x7a, 0x3c, 0x62, 0x8f, 0x28,
-	0x3f, 0x01, 0xda, 0x68, 0xca, 0xd9, 0x5a, 0xb0, 0x08, 0xb7, 0xa0, 0x16, 0x3e, 0xc0, 0x98, 0x7c,
-	0x40, 0x37, 0x4d, 0xed, 0xbf, 0x0a, 0x69, 0xa9, 0x94, 0x45, 0x01, 0xf7, 0x23, 0x86, 0xfb, 0x50,
-	0x49, 0x08, 0x92, 0x5f, 0xec, 0xd4, 0xfa, 0x8d, 0xec, 0x4e, 0xed, 0xca, 0xd3, 0x8c, 0x88, 0x5f,
-	0x82, 0xb2, 0x30, 0x23, 0x63, 0xc9, 0xc3, 0x64, 0x0f, 0x14, 0x5a, 0x59, 0x98, 0xd1, 0x35, 0x0f,
-	0xb3, 0x34, 0x8b, 0x59, 0x9a, 0x9f, 0x1c, 0xad, 0x03, 0x27, 0x5b, 0xb9, 0xe4, 0xed, 0xef, 0xc3,
-	0xc9, 0x1d, 0x13, 0xd6, 0x82, 0xd9, 0x46, 0xc8, 0x2c, 0x1e, 0xda, 0x91, 0x61, 0xf1, 0x95, 0x2f,
-	0xd2, 0x59, 0x1c, 0xa7, 0x4e, 0x9a, 0xf8, 0x86, 0xd2, 0xf5, 0xc9, 0xb1, 0xbc, 0x81, 0xc3, 0xed,
-	0xdd, 0x6b, 0x40, 0x45, 0x66, 0xf1, 0x30, 0x97, 0x0c, 0xfe, 0xf7, 0x7e, 0xb7, 0xcf, 0xe1, 0x78,
-	0x7b, 0xc3, 0x92, 0x9b, 0xd8, 0x83, 0x0a, 0xf3, 0x45, 0xe8, 0xb2, 0xac, 0x77, 0x4f, 0xec, 0x63,
-	0xc6, 0xea, 0xbf, 0xdf, 0x78, 0x39, 0x6b, 0xab, 0x20, 0xe0, 0xa1, 0xc0, 0x23, 0x50, 0x28, 0x73,
-	0xdc, 0x48, 0xb0, 0x10, 0x37, 0x9e, 0x7a, 0x35, 0x37, 0x9f, 0xf4, 0xb4, 0x9f, 0x75, 0x0a, 0x3f,
-	0x14, 0xce, 0xa6, 0xd0, 0xe6, 0xa1, 0xd3, 0x5d, 0xac, 0x03, 0x16, 0x7a, 0xcc, 0x76, 0x58, 0xd8,
-	0xbd, 0x33, 0xe7, 0xa1, 0x6b, 0x65, 0xe7, 0xe4, 0xd7, 0xc4, 0xaf, 0xdf, 0x3b, 0xae, 0x58, 0xac,
-	0xe6, 0x5d, 0x8b, 0x2f, 0x7b, 0x1b, 0xd4, 0x5e, 0x42, 0x4d, 0xbe, 0x2a, 0xa2, 0x9e, 0xa4, 0xce,
-	0x93, 0x4f, 0x94, 0x1f, 0xff, 0x0d, 0x00, 0x00, 0xff, 0xff, 0x41, 0xbd, 0x1a, 0x01, 0xc6, 0x08,
-	0x00, 0x00,
+	proto.RegisterFile("peer/chaincode_shim.proto", fileDescriptor_chaincode_shim_70027e04f99f8756)
+}
+
+var fileDescriptor_chaincode_shim_70027e04f99f8756 = []byte{
+	// 1301 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0xcd, 0x53, 0x1b, 0x37,
+	0x14, 0x8f, 0xb1, 0x81, 0xf5, 0xe3, 0x4b, 0x11, 0x98, 0x2c, 0x9e, 0x26, 0x25, 0x3b, 0x3d, 0xd0,
+	0x43, 0x4d, 0xe2, 0x66, 0x3a, 0x3d, 0x74, 0x26, 0x63, 0x6c, 0x01, 0x1e, 0xc0, 0x76, 0xe4, 0x85,
+	0x09, 0x3d, 0x74, 0x47, 0xf6, 0x0a, 0x7b, 0x87, 0xf5, 0xca, 0xd5, 0xca, 0x24, 0xee, 0x2d, 0xd7,
+	0xde, 0xfb, 0x97, 0xf5, 0xd0, 0x7f, 0xa7, 0xa3, 0xfd, 0x30, 0x8b, 0x09, 0x61, 0x92, 0x93, 0xfd,
+	0x7b, 0xef, 0xa7, 0xdf, 0xfb, 0x90, 0xde, 0x4a, 0xb0, 0x33, 0xe6, 0x5c, 0xee, 0xf7, 0x87, 0xcc,
+	0x0b, 0xfa, 0xc2, 0xe5, 0x4e, 0x38, 0xf4, 0x46, 0x95, 0xb1, 0x14, 0x4a, 0xe0, 0xa5, 0xe8, 0x27,
+	0x2c, 0x97, 0xe7, 0x28, 0xfc, 0x86, 0x07, 0x2a, 0xe6, 0x94, 0x37, 0x23, 0xdf, 0x58, 0x8a, 0xb1,
+	0x08, 0x99, 0x9f, 0x18, 0xbf, 0x1f, 0x08, 0x31, 0xf0, 0xf9, 0x7e, 0x84, 0x7a, 0x93, 0xab, 0x7d,
+	0xe5, 0x8d, 0x78, 0xa8, 0xd8, 0x68, 0x1c, 0x13, 0xac, 0x7f, 0x97, 0x00, 0xd5, 0x53, 0xbd, 0x33,
+	0x1e, 0x86, 0x6c, 0xc0, 0xf1, 0x6b, 0x28, 0xa8, 0xe9, 0x98, 0x9b, 0xb9, 0xdd, 0xdc, 0xde, 0x7a,
+	0xf5, 0x79, 0x4c, 0x0d, 0x2b, 0xf3, 0xbc, 0x8a, 0x3d, 0x1d, 0x73, 0x1a, 0x51, 0xf1, 0xaf, 0x50,
+	0x9c, 0x49, 0x9b, 0x0b, 0xbb, 0xb9, 0xbd, 0x95, 0x6a, 0xb9, 0x12, 0x07, 0xaf, 0xa4, 0xc1, 0x2b,
+	0x76, 0xca, 0xa0, 0xb7, 0x64, 0x6c, 0xc2, 0xf2, 0x98, 0x4d, 0x7d, 0xc1, 0x5c, 0x33, 0xbf, 0x9b,
+	0xdb, 0x5b, 0xa5, 0x29, 0xc4, 0x18, 0x0a, 0xea, 0xa3, 0xe7, 0x9a, 0x85, 0xdd, 0xdc, 0x5e, 0x91,
+	0x46, 0xff, 0x71, 0x15, 0x8c, 0xb4, 0x44, 0x73, 0x31, 0x0a, 0xb3, 0x9d, 0xa6, 0xd7, 0xf5, 0x06,
+	0x01, 0x77, 0x3b, 0x89, 0x97, 0xce, 0x78, 0xf8, 0x2d, 0x6c, 0xcc, 0xb5, 0xcc, 0x5c, 0xba, 0xbb,
+	0x74, 0x56, 0x19, 0xd1, 0x5e, 0xba, 0xde, 0xbf, 0x83, 0xf1, 0x73, 0x80, 0xfe, 0x90, 0x05, 0x01,
+	0xf7, 0x1d, 0xcf, 0x35, 0x97, 0xa3, 0x74, 0x8a, 0x89, 0xa5, 0xe9, 0xe2, 0x5f, 0xc0, 0x70, 0x39,
+	0x73, 0x7d, 0x2f, 0xe0, 0xa6, 0xf1, 0x68, 0xe9, 0x33, 0xae, 0xf5, 0x5f, 0x1e, 0x0a, 0xba, 0x85,
+	0x78, 0x0d, 0x8a, 0xe7, 0xad, 0x06, 0x39, 0x6c, 0xb6, 0x48, 0x03, 0x3d, 0xc1, 0xab, 0x60, 0x50,
+	0x72, 0xd4, 0xec, 0xda, 0x84, 0xa2, 0x1c, 0x5e, 0x07, 0x48, 0x11, 0x69, 0xa0, 0x05, 0x6c, 0x40,
+	0xa1, 0xd9, 0x6a, 0xda, 0x28, 0x8f, 0x8b, 0xb0, 0x48, 0x49, 0xad, 0x71, 0x89, 0x0a, 0x78, 0x03,
+	0x56, 0x6c, 0x5a, 0x6b, 0x75, 0x6b, 0x75, 0xbb, 0xd9, 0x6e, 0xa1, 0x45, 0x2d, 0x59, 0x6f, 0x9f,
+	0x75, 0x4e, 0x89, 0x4d, 0x1a, 0x68, 0x49, 0x53, 0x09, 0xa5, 0x6d, 0x8a, 0x96, 0xb5, 0xe7, 0x88,
+	0xd8, 0x4e, 0xd7, 0xae, 0xd9, 0x04, 0x19, 0x1a, 0x76, 0xce, 0x53, 0x58, 0xd4, 0xb0, 0x41, 0x4e,
+	0x13, 0x08, 0x78, 0x0b, 0x50, 0xb3, 0x75, 0xd1, 0x3e, 0x21, 0x4e, 0xfd, 0xb8, 0xd6, 0x6c, 0xd5,
+	0xdb, 0x0d, 0x82, 0x56, 0xe2, 0x04, 0xbb, 0x9d, 0x76, 0xab, 0x4b, 0xd0, 0x1a, 0xde, 0x06, 0x3c,
+	0x13, 0x74, 0x0e, 0x2e, 0x1d, 0x5a, 0x6b, 0x1d, 0x11, 0xb4, 0xae, 0xd7, 0x6a, 0xfb, 0xbb, 0x73,
+	0x42, 0x2f, 0x1d, 0x4a, 0xba, 0xe7, 0xa7, 0x36, 0xda, 0xd0, 0xd6, 0xd8, 0x12, 0xf3, 0x5b, 0xe4,
+	0xbd, 0x8d, 0x10, 0x2e, 0xc1, 0xd3, 0xac, 0xb5, 0x7e, 0xda, 0xee, 0x12, 0xf4, 0x54, 0x67, 0x73,
+	0x42, 0x48, 0xa7, 0x76, 0xda, 0xbc, 0x20, 0x08, 0xe3, 0x67, 0xb0, 0xa9, 0x15, 0x8f, 0x9b, 0x5d,
+	0xbb, 0x4d, 0x2f, 0x9d, 0xc3, 0x36, 0x75, 0x4e, 0xc8, 0x25, 0xda, 0xbc, 0x9b, 0xc2, 0x19, 0xb1,
+	0x6b, 0x8d, 0x9a, 0x5d, 0x43, 0x5b, 0xda, 0xde, 0x39, 0xbf, 0x67, 0x2f, 0xe1, 0x1d, 0x28, 0x69,
+	0x7e, 0x87, 0x36, 0x2f, 0xb4, 0x47, 0x5b, 0x9d, 0xe3, 0x5a, 0xf7, 0x18, 0x6d, 0xcf, 0x49, 0x9d,
+	0x9f, 0xda, 0xcd, 0xce, 0x29, 0x41, 0xcf, 0x74, 0x2a, 0xc7, 0xa4, 0x46, 0xed, 0x03, 0x52, 0xb3,
+	0x91, 0x89, 0xbf, 0x03, 0xf3, 0x82, 0xd0, 0xe6, 0xe1, 0xe5, 0x67, 0x44, 0x76, 0xac, 0x3f, 0xc0,
+	0x38, 0xe2, 0xaa, 0xab, 0x98, 0xe2, 0x18, 0x41, 0xfe, 0x9a, 0x4f, 0xa3, 0x59, 0x2a, 0x52, 0xfd,
+	0x17, 0xbf, 0x00, 0xe8, 0x0b, 0xdf, 0xe7, 0x7d, 0xe5, 0x89, 0x20, 0x1a, 0x96, 0x22, 0xcd, 0x58,
+	0xf0, 0x2e, 0xac, 0x4c, 0x82, 0x1b, 0xe6, 0x7b, 0x2e, 0x53, 0x3c, 0x9e, 0x0a, 0x83, 0x66, 0x4d,
+	0xd6, 0x21, 0xa0, 0x54, 0xff, 0x6c, 0xe2, 0x2b, 0x6f, 0xec, 0x73, 0x3d, 0x2d, 0xd7, 0x7c, 0x1a,
+	0x9a, 0xb9, 0xdd, 0xbc, 0x9e, 0x16, 0xfd, 0xff, 0xb1, 0x48, 0xd6, 0x2b, 0xd8, 0x9e, 0xd7, 0xa1,
+	0x3c, 0x9c, 0xf8, 0x0a, 0x6f, 0xc3, 0xd2, 0x0d, 0xf3, 0x27, 0x3c, 0xd6, 0x5b, 0xa5, 0x09, 0xb2,
+	0x1a, 0x99, 0xc8, 0x5c, 0x31, 0x97, 0x29, 0xf6, 0xf5, 0x15, 0x5a, 0x14, 0x8c, 0xce, 0xe4, 0xc1,
+	0xfe, 0x6c, 0xc1, 0x62, 0x14, 0x2d, 0x5a, 0xb8, 0x4a, 0x63, 0x30, 0xa7, 0x99, 0xbf, 0xa7, 0xf9,
+	0x01, 0x50, 0x67, 0xf2, 0x95, 0x99, 0xdd, 0x53, 0xc1, 0xaf, 0xc1, 0x18, 0x25, 0xab, 0xa3, 0xef,
+	0xce, 0x4a, 0xb5, 0x34, 0xfb, 0xbe, 0x64, 0xa5, 0xe9, 0x8c, 0x66, 0xfd, 0x06, 0x46, 0x83, 0xfb,
+	0xdf, 0xb8, 0xd9, 0xd6, 0xa7, 0x1c, 0x6c, 0xa4, 0x1d, 0x3d, 0x98, 0x52, 0x16, 0x0c, 0x38, 0x2e,
+	0x83, 0x11, 0x2a, 0x26, 0xd5, 0xc9, 0x4c, 0x6a, 0x86, 0xf5, 0xc6, 0xf0, 0xc0, 0xd5, 0x9e, 0x58,
+	0x2b, 0x41, 0x8f, 0x16, 0x56, 0x9e, 0x2b, 0x6c, 0x35, 0x53, 0x41, 0x0f, 0xd6, 0x8f, 0xb8, 0x7a,
+	0x37, 0xe1, 0x72, 0x9a, 0x6c, 0xff, 0x16, 0x2c, 0xfe, 0xa9, 0x61, 0x12, 0x3e, 0x06, 0x8f, 0x1e,
+	0xdc, 0x6c, 0x8c, 0xfc, 0x5c, 0x8c, 0x23, 0x58, 0x8b, 0x02, 0xcc, 0xf6, 0xa6, 0x0c, 0xc6, 0x98,
+	0x0d, 0x78, 0xd7, 0xfb, 0x2b, 0xbe, 0x68, 0x16, 0xe9, 0x0c, 0x6b, 0x5f, 0x4f, 0x88, 0xeb, 0x11,
+	0x93, 0xd7, 0x49, 0x98, 0x19, 0xb6, 0x7e, 0x88, 0x4e, 0xe0, 0xb1, 0x17, 0x2a, 0x21, 0xa7, 0x87,
+	0x42, 0xea, 0xe2, 0xef, 0xb5, 0xdd, 0xda, 0x85, 0xf5, 0x28, 0x5c, 0xd4, 0xd7, 0x16, 0xff, 0xa8,
+	0xf0, 0x3a, 0x2c, 0x78, 0x6e, 0x42, 0x59, 0xf0, 0x5c, 0xeb, 0x25, 0x6c, 0xdc, 0x32, 0xea, 0xbe,
+	0x08, 0xf9, 0x3d, 0xca, 0x1b, 0x40, 0x99, 0xa6, 0x1c, 0x4c, 0x15, 0x0f, 0xf5, 0x70, 0xca, 0x5b,
+	0x18, 0x91, 0x57, 0x69, 0xd6, 0x64, 0xfd, 0x9d, 0x4b, 0x4a, 0xa5, 0x3c, 0x1c, 0x8b, 0x20, 0xe4,
+	0xb8, 0x0a, 0xcb, 0x31, 0x21, 0x9e, 0xa6, 0x95, 0xaa, 0x99, 0x9e, 0xa9, 0x79, 0x79, 0x9a, 0x12,
+	0xf1, 0x0e, 0x18, 0x43, 0x16, 0x3a, 0x23, 0x21, 0xe3, 0x39, 0x30, 0xe8, 0xf2, 0x90, 0x85, 0x67,
+	0x42, 0xa6, 0x69, 0xe6, 0xd3, 0x34, 0xbf, 0xb8, 0xb5, 0x9f, 0x72, 0x50, 0xba, 0x93, 0xcc, 0xac,
+	0xff, 0x55, 0x28, 0x5d, 0x71, 0xd5, 0x1f, 0x72, 0xd7, 0x91, 0xbc, 0x2f, 0xa4, 0x1b, 0x3a, 0x7d,
+	0x31, 0x09, 0x54, 0xb2, 0x19, 0x9b, 0x89, 0x93, 0xc6, 0xbe, 0xba, 0x76, 0x7d, 0x69, 0x5f, 0xf4,
+	0x3d, 0xfe, 0x81, 0xc9, 0xc0, 0x0b, 0x06, 0x49, 0x6a, 0x29, 0xb4, 0xde, 0xc2, 0xda, 0xdd, 0xb1,
+	0x34, 0x61, 0x59, 0x27, 0x78, 0xbb, 0x65, 0x29, 0xfc, 0xfc, 0xe8, 0x5b, 0x87, 0xb0, 0x79, 0x77,
+	0xf8, 0xe2, 0x43, 0xba, 0x0f, 0xcb, 0x3c, 0x50, 0xd2, 0xe3, 0x69, 0x5b, 0x1f, 0x18, 0xd5, 0x94,
+	0x65, 0xfd, 0xb3, 0x00, 0xa5, 0xd9, 0x55, 0x4f, 0x27, 0x81, 0x7e, 0x84, 0x68, 0x6a, 0x88, 0xdf,
+	0xc0, 0xb6, 0x92, 0x2c, 0x08, 0x59, 0x74, 0x90, 0x43, 0xc7, 0x0b, 0x9c, 0x2b, 0xdf, 0x1b, 0x0c,
+	0xd3, 0x6e, 0x6c, 0x65, 0xbd, 0xcd, 0xe0, 0x30, 0xf2, 0xe1, 0x9f, 0x00, 0x67, 0xec, 0x0e, 0x97,
+	0x52, 0xc8, 0x30, 0x4a, 0xbd, 0x40, 0x9f, 0x66, 0x3c, 0x24, 0x72, 0x60, 0x1b, 0x4c, 0xe1, 0xbb,
+	0x3c, 0x54, 0x4e, 0x76, 0x55, 0x34, 0xd9, 0x66, 0xfe, 0xd1, 0x77, 0xc3, 0x76, 0xbc, 0xd6, 0xbe,
+	0x5d, 0xda, 0xd5, 0x2b, 0xf1, 0x4b, 0x58, 0x1d, 0xf1, 0x91, 0x90, 0x53, 0xa7, 0x17, 0x9d, 0xc8,
+	0x42, 0x14, 0x7e, 0x25, 0xb6, 0xc5, 0x67, 0xf6, 0x05, 0xc0, 0x40, 0x48, 0x31, 0x51, 0x5e, 0xc0,
+	0xc3, 0xe8, 0xd9, 0xb4, 0x48, 0x33, 0x16, 0x8b, 0x43, 0xe9, 0x82, 0x4b, 0xef, 0x6a, 0xda, 0x91,
+	0xde, 0x0d, 0x53, 0xbc, 0xc1, 0x14, 0x3b, 0x66, 0xe1, 0xf0, 0x1b, 0xee, 0x2e, 0x3d, 0xd5, 0x92,
+	0x7b, 0x23, 0x36, 0xe0, 0xe9, 0x27, 0x20, 0xc5, 0xd5, 0xf7, 0x99, 0xa7, 0x66, 0x77, 0x32, 0x1e,
+	0x0b, 0xa9, 0x70, 0x03, 0x0c, 0xca, 0x07, 0x5e, 0xa8, 0xb8, 0xc4, 0xe6, 0x43, 0x0f, 0xcd, 0xf2,
+	0x83, 0x1e, 0xeb, 0xc9, 0x5e, 0xee, 0x55, 0xee, 0xa0, 0x0d, 0x96, 0x90, 0x83, 0xca, 0x70, 0x3a,
+	0xe6, 0xd2, 0xe7, 0xee, 0x80, 0xcb, 0xca, 0x15, 0xeb, 0x49, 0xaf, 0x9f, 0xae, 0xd3, 0x6f, 0xe3,
+	0xdf, 0x7f, 0x1c, 0x78, 0x6a, 0x38, 0xe9, 0x55, 0xfa, 0x62, 0xb4, 0x9f, 0xa1, 0xee, 0xc7, 0xd4,
+	0xf8, 0x8d, 0x1c, 0xee, 0x6b, 0x6a, 0x2f, 0x7e, 0x70, 0xff, 0xfc, 0xff, 0x00, 0x9b, 0x6e, 0xc2,
+	0xbe, 0x94, 0x0b, 0x00, 0x00,
 }
@@ -30,7 +30,7 @@ func (m *AnchorPeers) Reset()         { *m = AnchorPeers{} }
 func (m *AnchorPeers) String() string { return proto.CompactTextString(m) }
 func (*AnchorPeers) ProtoMessage()    {}
 func (*AnchorPeers) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_e51d0015b9cb595d, []int{0}
+	return fileDescriptor_configuration_c964006c026a3e8a, []int{0}
 }
 func (m *AnchorPeers) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AnchorPeers.Unmarshal(m, b)
@@ -73,7 +73,7 @@ func (m *AnchorPeer) Reset()         { *m = AnchorPeer{} }
 func (m *AnchorPeer) String() string { return proto.CompactTextString(m) }
 func (*AnchorPeer) ProtoMessage()    {}
 func (*AnchorPeer) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_e51d0015b9cb595d, []int{1}
+	return fileDescriptor_configuration_c964006c026a3e8a, []int{1}
 }
 func (m *AnchorPeer) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AnchorPeer.Unmarshal(m, b)
@@ -120,7 +120,7 @@ func (m *APIResource) Reset()         { *m = APIResource{} }
 func (m *APIResource) String() string { return proto.CompactTextString(m) }
 func (*APIResource) ProtoMessage()    {}
 func (*APIResource) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_e51d0015b9cb595d, []int{2}
+	return fileDescriptor_configuration_c964006c026a3e8a, []int{2}
 }
 func (m *APIResource) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_APIResource.Unmarshal(m, b)
@@ -160,7 +160,7 @@ func (m *ACLs) Reset()         { *m = ACLs{} }
 func (m *ACLs) String() string { return proto.CompactTextString(m) }
 func (*ACLs) ProtoMessage()    {}
 func (*ACLs) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_e51d0015b9cb595d, []int{3}
+	return fileDescriptor_configuration_c964006c026a3e8a, []int{3}
 }
 func (m *ACLs) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ACLs.Unmarshal(m, b)
@@ -187,37 +187,80 @@ func (m *ACLs) GetAcls() map[string]*APIResource {
 	return nil
 }
 
+// TxDedupWindow is the number of blocks within which the transactions of a
+// channel carrying the same dedup key for the same chaincode are duplicates.
+// Transactions are not deduplicated when it is 0.
+type TxDedupWindow struct {
+	Blocks               uint64   `protobuf:"varint,1,opt,name=blocks" json:"blocks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TxDedupWindow) Reset()         { *m = TxDedupWindow{} }
+func (m *TxDedupWindow) String() string { return proto.CompactTextString(m) }
+func (*TxDedupWindow) ProtoMessage()    {}
+func (*TxDedupWindow) Descriptor() ([]byte, []int) {
+	return fileDescriptor_configuration_c964006c026a3e8a, []int{4}
+}
+func (m *TxDedupWindow) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxDedupWindow.Unmarshal(m, b)
+}
+func (m *TxDedupWindow) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxDedupWindow.Marshal(b, m, deterministic)
+}
+func (dst *TxDedupWindow) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxDedupWindow.Merge(dst, src)
+}
+func (m *TxDedupWindow) XXX_Size() int {
+	return xxx_messageInfo_TxDedupWindow.Size(m)
+}
+func (m *TxDedupWindow) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxDedupWindow.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxDedupWindow proto.InternalMessageInfo
+
+func (m *TxDedupWindow) GetBlocks() uint64 {
+	if m != nil {
+		return m.Blocks
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*AnchorPeers)(nil), "protos.AnchorPeers")
 	proto.RegisterType((*AnchorPeer)(nil), "protos.AnchorPeer")
 	proto.RegisterType((*APIResource)(nil), "protos.APIResource")
 	proto.RegisterType((*ACLs)(nil), "protos.ACLs")
 	proto.RegisterMapType((map[string]*APIResource)(nil), "protos.ACLs.AclsEntry")
+	proto.RegisterType((*TxDedupWindow)(nil), "protos.TxDedupWindow")
 }
 
 func init() {
-	proto.RegisterFile("peer/configuration.proto", fileDescriptor_configuration_e51d0015b9cb595d)
-}
-
-var fileDescriptor_configuration_e51d0015b9cb595d = []byte{
-	// 295 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x91, 0xdf, 0x4b, 0xfb, 0x30,
-	0x14, 0xc5, 0xe9, 0x7e, 0x7c, 0x61, 0xb7, 0xdf, 0x07, 0x89, 0x20, 0x45, 0x10, 0x46, 0x9f, 0x36,
-	0x91, 0x14, 0xa6, 0x82, 0xf8, 0x56, 0xa7, 0x0f, 0xc2, 0xc0, 0x91, 0x47, 0x5f, 0x46, 0x16, 0x6f,
-	0x7f, 0x60, 0x6d, 0xca, 0x4d, 0x2a, 0xf4, 0xcd, 0x3f, 0x5d, 0x9a, 0x6c, 0xab, 0x4f, 0x39, 0x39,
-	0xf9, 0x9c, 0xcb, 0x21, 0x17, 0xa2, 0x06, 0x91, 0x12, 0xa5, 0xeb, 0xac, 0xcc, 0x5b, 0x92, 0xb6,
-	0xd4, 0x35, 0x6f, 0x48, 0x5b, 0xcd, 0xfe, 0xb9, 0xc3, 0xc4, 0xcf, 0x10, 0xa6, 0xb5, 0x2a, 0x34,
-	0x6d, 0x11, 0xc9, 0xb0, 0x7b, 0xf8, 0x2f, 0xdd, 0x75, 0xd7, 0x27, 0x4d, 0x14, 0xcc, 0xc7, 0x8b,
-	0x70, 0xc5, 0x7c, 0xc8, 0xf0, 0x01, 0x15, 0xa1, 0x1c, 0x62, 0xf1, 0x1d, 0xc0, 0xf0, 0xc4, 0x18,
-	0x4c, 0x0a, 0x6d, 0x6c, 0x14, 0xcc, 0x83, 0xc5, 0x4c, 0x38, 0xdd, 0x7b, 0x8d, 0x26, 0x1b, 0x8d,
-	0xe6, 0xc1, 0x62, 0x2a, 0x9c, 0x8e, 0x6f, 0x20, 0x4c, 0xb7, 0xaf, 0x02, 0x8d, 0x6e, 0x49, 0x21,
-	0xbb, 0x02, 0x68, 0x74, 0x55, 0xaa, 0x6e, 0x47, 0x98, 0x1d, 0xc2, 0x33, 0xef, 0x08, 0xcc, 0xe2,
-	0x9f, 0x00, 0x26, 0xe9, 0x7a, 0x63, 0xd8, 0x35, 0x4c, 0xa4, 0xaa, 0x8e, 0xdd, 0x2e, 0x4e, 0xdd,
-	0xd6, 0x1b, 0xc3, 0x53, 0x55, 0x99, 0x97, 0xda, 0x52, 0x27, 0x1c, 0x73, 0xb9, 0x81, 0xd9, 0xc9,
-	0x62, 0x67, 0x30, 0xfe, 0xc4, 0xee, 0x30, 0xb9, 0x97, 0x6c, 0x09, 0xd3, 0x6f, 0x59, 0xb5, 0xe8,
-	0x6a, 0x85, 0xab, 0xf3, 0xd3, 0xac, 0xa1, 0x96, 0xf0, 0xc4, 0xe3, 0xe8, 0x21, 0x78, 0x7a, 0x83,
-	0x58, 0x53, 0xce, 0x8b, 0xae, 0x41, 0xaa, 0xf0, 0x23, 0x47, 0xe2, 0x99, 0xdc, 0x53, 0xa9, 0x8e,
-	0xb9, 0xfe, 0xd3, 0xde, 0x97, 0x79, 0x69, 0x8b, 0x76, 0xcf, 0x95, 0xfe, 0x4a, 0xfe, 0xa0, 0x89,
-	0x47, 0x13, 0x8f, 0x26, 0x3d, 0xba, 0xf7, 0x5b, 0xb8, 0xfd, 0x0d, 0x00, 0x00, 0xff, 0xff, 0xae,
-	0x0a, 0x1d, 0x41, 0xa8, 0x01, 0x00, 0x00,
+	proto.RegisterFile("peer/configuration.proto", fileDescriptor_configuration_c964006c026a3e8a)
+}
+
+var fileDescriptor_configuration_c964006c026a3e8a = []byte{
+	// 320 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x91, 0x4f, 0x4b, 0xc3, 0x40,
+	0x10, 0xc5, 0x49, 0x9b, 0x16, 0x3a, 0x51, 0x90, 0x15, 0x4a, 0x10, 0x84, 0x92, 0x8b, 0xad, 0x48,
+	0x02, 0x55, 0x41, 0xbc, 0xc5, 0xd6, 0x83, 0x50, 0xb0, 0x2c, 0x82, 0xe0, 0xa5, 0x24, 0xdb, 0xc9,
+	0x1f, 0x1a, 0xb3, 0x61, 0x36, 0x51, 0x73, 0xf3, 0xa3, 0x4b, 0x36, 0x6d, 0xe3, 0x29, 0x33, 0x2f,
+	0xbf, 0xf7, 0xf6, 0xb1, 0x0b, 0x76, 0x81, 0x48, 0x9e, 0x90, 0x79, 0x94, 0xc6, 0x15, 0x05, 0x65,
+	0x2a, 0x73, 0xb7, 0x20, 0x59, 0x4a, 0x36, 0xd4, 0x1f, 0xe5, 0x2c, 0xc1, 0xf2, 0x73, 0x91, 0x48,
+	0x5a, 0x23, 0x92, 0x62, 0xf7, 0x70, 0x12, 0xe8, 0x75, 0xd3, 0x38, 0x95, 0x6d, 0x4c, 0xfa, 0x53,
+	0x6b, 0xce, 0x5a, 0x93, 0x72, 0x3b, 0x94, 0x5b, 0x41, 0x67, 0x73, 0xee, 0x00, 0xba, 0x5f, 0x8c,
+	0x81, 0x99, 0x48, 0x55, 0xda, 0xc6, 0xc4, 0x98, 0x8e, 0xb8, 0x9e, 0x1b, 0xad, 0x90, 0x54, 0xda,
+	0xbd, 0x89, 0x31, 0x1d, 0x70, 0x3d, 0x3b, 0x37, 0x60, 0xf9, 0xeb, 0x17, 0x8e, 0x4a, 0x56, 0x24,
+	0x90, 0x5d, 0x02, 0x14, 0x32, 0x4b, 0x45, 0xbd, 0x21, 0x8c, 0xf6, 0xe6, 0x51, 0xab, 0x70, 0x8c,
+	0x9c, 0x5f, 0x03, 0x4c, 0x7f, 0xb1, 0x52, 0xec, 0x1a, 0xcc, 0x40, 0x64, 0x87, 0x6e, 0xe3, 0x63,
+	0xb7, 0xc5, 0x4a, 0xb9, 0xbe, 0xc8, 0xd4, 0x73, 0x5e, 0x52, 0xcd, 0x35, 0x73, 0xb1, 0x82, 0xd1,
+	0x51, 0x62, 0x67, 0xd0, 0xdf, 0x61, 0xbd, 0x4f, 0x6e, 0x46, 0x36, 0x83, 0xc1, 0x57, 0x90, 0x55,
+	0xa8, 0x6b, 0x59, 0xf3, 0xf3, 0x63, 0x56, 0x57, 0x8b, 0xb7, 0xc4, 0x63, 0xef, 0xc1, 0x70, 0xae,
+	0xe0, 0xf4, 0xed, 0x67, 0x89, 0xdb, 0xaa, 0x78, 0x4f, 0xf3, 0xad, 0xfc, 0x66, 0x63, 0x18, 0x86,
+	0x99, 0x14, 0x3b, 0xa5, 0x43, 0x4d, 0xbe, 0xdf, 0x9e, 0x5e, 0xc1, 0x91, 0x14, 0xbb, 0x49, 0x5d,
+	0x20, 0x65, 0xb8, 0x8d, 0x91, 0xdc, 0x28, 0x08, 0x29, 0x15, 0x87, 0x03, 0x9a, 0xdb, 0xfd, 0x98,
+	0xc5, 0x69, 0x99, 0x54, 0xa1, 0x2b, 0xe4, 0xa7, 0xf7, 0x0f, 0xf5, 0x5a, 0xd4, 0x6b, 0x51, 0xaf,
+	0x41, 0xc3, 0xf6, 0xb9, 0x6e, 0xff, 0x06, 0x00, 0x22, 0x0e, 0x03, 0x0e, 0xd1, 0x01, 0x00, 0x00,
 }
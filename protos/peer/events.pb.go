@@ -8,6 +8,7 @@ import fmt "fmt"
 import math "math"
 import _ "github.com/golang/protobuf/ptypes/timestamp"
 import common "github.com/hyperledger/fabric/protos/common"
+import rwset "github.com/hyperledger/fabric/protos/ledger/rwset"
 
 import (
 	context "golang.org/x/net/context"
@@ -39,7 +40,7 @@ func (m *FilteredBlock) Reset()         { *m = FilteredBlock{} }
 func (m *FilteredBlock) String() string { return proto.CompactTextString(m) }
 func (*FilteredBlock) ProtoMessage()    {}
 func (*FilteredBlock) Descriptor() ([]byte, []int) {
-	return fileDescriptor_events_8af932975aef5a3c, []int{0}
+	return fileDescriptor_events_07e58e8b9c8e58a5, []int{0}
 }
 func (m *FilteredBlock) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FilteredBlock.Unmarshal(m, b)
@@ -98,7 +99,7 @@ func (m *FilteredTransaction) Reset()         { *m = FilteredTransaction{} }
 func (m *FilteredTransaction) String() string { return proto.CompactTextString(m) }
 func (*FilteredTransaction) ProtoMessage()    {}
 func (*FilteredTransaction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_events_8af932975aef5a3c, []int{1}
+	return fileDescriptor_events_07e58e8b9c8e58a5, []int{1}
 }
 func (m *FilteredTransaction) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FilteredTransaction.Unmarshal(m, b)
@@ -231,7 +232,7 @@ func (m *FilteredTransactionActions) Reset()         { *m = FilteredTransactionA
 func (m *FilteredTransactionActions) String() string { return proto.CompactTextString(m) }
 func (*FilteredTransactionActions) ProtoMessage()    {}
 func (*FilteredTransactionActions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_events_8af932975aef5a3c, []int{2}
+	return fileDescriptor_events_07e58e8b9c8e58a5, []int{2}
 }
 func (m *FilteredTransactionActions) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FilteredTransactionActions.Unmarshal(m, b)
@@ -271,7 +272,7 @@ func (m *FilteredChaincodeAction) Reset()         { *m = FilteredChaincodeAction
 func (m *FilteredChaincodeAction) String() string { return proto.CompactTextString(m) }
 func (*FilteredChaincodeAction) ProtoMessage()    {}
 func (*FilteredChaincodeAction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_events_8af932975aef5a3c, []int{3}
+	return fileDescriptor_events_07e58e8b9c8e58a5, []int{3}
 }
 func (m *FilteredChaincodeAction) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FilteredChaincodeAction.Unmarshal(m, b)
@@ -298,23 +299,197 @@ func (m *FilteredChaincodeAction) GetChaincodeEvent() *ChaincodeEvent {
 	return nil
 }
 
+// ChaincodeEventFilter may be attached as the extension of the channel header
+// of a DeliverFiltered request, in order to receive only the chaincode events
+// that match it. Both fields are regular expressions which must match the
+// whole chaincode ID or event name, and an empty field matches everything
+type ChaincodeEventFilter struct {
+	ChaincodeId          string   `protobuf:"bytes,1,opt,name=chaincode_id,json=chaincodeId" json:"chaincode_id,omitempty"`
+	EventName            string   `protobuf:"bytes,2,opt,name=event_name,json=eventName" json:"event_name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChaincodeEventFilter) Reset()         { *m = ChaincodeEventFilter{} }
+func (m *ChaincodeEventFilter) String() string { return proto.CompactTextString(m) }
+func (*ChaincodeEventFilter) ProtoMessage()    {}
+func (*ChaincodeEventFilter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_events_07e58e8b9c8e58a5, []int{4}
+}
+func (m *ChaincodeEventFilter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChaincodeEventFilter.Unmarshal(m, b)
+}
+func (m *ChaincodeEventFilter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChaincodeEventFilter.Marshal(b, m, deterministic)
+}
+func (dst *ChaincodeEventFilter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChaincodeEventFilter.Merge(dst, src)
+}
+func (m *ChaincodeEventFilter) XXX_Size() int {
+	return xxx_messageInfo_ChaincodeEventFilter.Size(m)
+}
+func (m *ChaincodeEventFilter) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChaincodeEventFilter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChaincodeEventFilter proto.InternalMessageInfo
+
+func (m *ChaincodeEventFilter) GetChaincodeId() string {
+	if m != nil {
+		return m.ChaincodeId
+	}
+	return ""
+}
+
+func (m *ChaincodeEventFilter) GetEventName() string {
+	if m != nil {
+		return m.EventName
+	}
+	return ""
+}
+
+// BlockSlimming may be attached as the extension of the channel header of a
+// Deliver request, in order to receive the blocks without the parts of their
+// endorser transactions that the client does not need. The data hash of a
+// slimmed block no longer matches its data, and the signatures which are kept
+// can no longer be verified
+type BlockSlimming struct {
+	// strip_signatures removes the envelope signatures and the endorsements
+	StripSignatures bool `protobuf:"varint,1,opt,name=strip_signatures,json=stripSignatures" json:"strip_signatures,omitempty"`
+	// strip_creators removes the certificates of the transaction creators
+	StripCreators bool `protobuf:"varint,2,opt,name=strip_creators,json=stripCreators" json:"strip_creators,omitempty"`
+	// strip_rwsets removes the read-write sets of the chaincode actions
+	StripRwsets bool `protobuf:"varint,3,opt,name=strip_rwsets,json=stripRwsets" json:"strip_rwsets,omitempty"`
+	// strip_events removes the chaincode events of the chaincode actions
+	StripEvents          bool     `protobuf:"varint,4,opt,name=strip_events,json=stripEvents" json:"strip_events,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockSlimming) Reset()         { *m = BlockSlimming{} }
+func (m *BlockSlimming) String() string { return proto.CompactTextString(m) }
+func (*BlockSlimming) ProtoMessage()    {}
+func (*BlockSlimming) Descriptor() ([]byte, []int) {
+	return fileDescriptor_events_07e58e8b9c8e58a5, []int{5}
+}
+func (m *BlockSlimming) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockSlimming.Unmarshal(m, b)
+}
+func (m *BlockSlimming) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockSlimming.Marshal(b, m, deterministic)
+}
+func (dst *BlockSlimming) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockSlimming.Merge(dst, src)
+}
+func (m *BlockSlimming) XXX_Size() int {
+	return xxx_messageInfo_BlockSlimming.Size(m)
+}
+func (m *BlockSlimming) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockSlimming.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockSlimming proto.InternalMessageInfo
+
+func (m *BlockSlimming) GetStripSignatures() bool {
+	if m != nil {
+		return m.StripSignatures
+	}
+	return false
+}
+
+func (m *BlockSlimming) GetStripCreators() bool {
+	if m != nil {
+		return m.StripCreators
+	}
+	return false
+}
+
+func (m *BlockSlimming) GetStripRwsets() bool {
+	if m != nil {
+		return m.StripRwsets
+	}
+	return false
+}
+
+func (m *BlockSlimming) GetStripEvents() bool {
+	if m != nil {
+		return m.StripEvents
+	}
+	return false
+}
+
+// BlockAndPrivateData contains Block and a map from tx_seq_in_block to rwset.TxPvtReadWriteSet
+type BlockAndPrivateData struct {
+	Block *common.Block `protobuf:"bytes,1,opt,name=block" json:"block,omitempty"`
+	// map from tx_seq_in_block to rwset.TxPvtReadWriteSet
+	PrivateDataMap       map[uint64]*rwset.TxPvtReadWriteSet `protobuf:"bytes,2,rep,name=private_data_map,json=privateDataMap" json:"private_data_map,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	XXX_NoUnkeyedLiteral struct{}                            `json:"-"`
+	XXX_unrecognized     []byte                              `json:"-"`
+	XXX_sizecache        int32                               `json:"-"`
+}
+
+func (m *BlockAndPrivateData) Reset()         { *m = BlockAndPrivateData{} }
+func (m *BlockAndPrivateData) String() string { return proto.CompactTextString(m) }
+func (*BlockAndPrivateData) ProtoMessage()    {}
+func (*BlockAndPrivateData) Descriptor() ([]byte, []int) {
+	return fileDescriptor_events_07e58e8b9c8e58a5, []int{6}
+}
+func (m *BlockAndPrivateData) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockAndPrivateData.Unmarshal(m, b)
+}
+func (m *BlockAndPrivateData) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockAndPrivateData.Marshal(b, m, deterministic)
+}
+func (dst *BlockAndPrivateData) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockAndPrivateData.Merge(dst, src)
+}
+func (m *BlockAndPrivateData) XXX_Size() int {
+	return xxx_messageInfo_BlockAndPrivateData.Size(m)
+}
+func (m *BlockAndPrivateData) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockAndPrivateData.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockAndPrivateData proto.InternalMessageInfo
+
+func (m *BlockAndPrivateData) GetBlock() *common.Block {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *BlockAndPrivateData) GetPrivateDataMap() map[uint64]*rwset.TxPvtReadWriteSet {
+	if m != nil {
+		return m.PrivateDataMap
+	}
+	return nil
+}
+
 // DeliverResponse
 type DeliverResponse struct {
 	// Types that are valid to be assigned to Type:
 	//	*DeliverResponse_Status
 	//	*DeliverResponse_Block
 	//	*DeliverResponse_FilteredBlock
-	Type                 isDeliverResponse_Type `protobuf_oneof:"Type"`
-	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
-	XXX_unrecognized     []byte                 `json:"-"`
-	XXX_sizecache        int32                  `json:"-"`
+	//	*DeliverResponse_BlockAndPrivateData
+	Type isDeliverResponse_Type `protobuf_oneof:"Type"`
+	// An opaque token sent along with every delivered block, which may be
+	// used as the orderer.SeekCheckpoint start position of a later deliver
+	// request in order to resume right after that block
+	Checkpoint           []byte   `protobuf:"bytes,5,opt,name=checkpoint,proto3" json:"checkpoint,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *DeliverResponse) Reset()         { *m = DeliverResponse{} }
 func (m *DeliverResponse) String() string { return proto.CompactTextString(m) }
 func (*DeliverResponse) ProtoMessage()    {}
 func (*DeliverResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_events_8af932975aef5a3c, []int{4}
+	return fileDescriptor_events_07e58e8b9c8e58a5, []int{7}
 }
 func (m *DeliverResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeliverResponse.Unmarshal(m, b)
@@ -347,10 +522,14 @@ type DeliverResponse_Block struct {
 type DeliverResponse_FilteredBlock struct {
 	FilteredBlock *FilteredBlock `protobuf:"bytes,3,opt,name=filtered_block,json=filteredBlock,oneof"`
 }
+type DeliverResponse_BlockAndPrivateData struct {
+	BlockAndPrivateData *BlockAndPrivateData `protobuf:"bytes,4,opt,name=block_and_private_data,json=blockAndPrivateData,oneof"`
+}
 
-func (*DeliverResponse_Status) isDeliverResponse_Type()        {}
-func (*DeliverResponse_Block) isDeliverResponse_Type()         {}
-func (*DeliverResponse_FilteredBlock) isDeliverResponse_Type() {}
+func (*DeliverResponse_Status) isDeliverResponse_Type()              {}
+func (*DeliverResponse_Block) isDeliverResponse_Type()               {}
+func (*DeliverResponse_FilteredBlock) isDeliverResponse_Type()       {}
+func (*DeliverResponse_BlockAndPrivateData) isDeliverResponse_Type() {}
 
 func (m *DeliverResponse) GetType() isDeliverResponse_Type {
 	if m != nil {
@@ -380,12 +559,27 @@ func (m *DeliverResponse) GetFilteredBlock() *FilteredBlock {
 	return nil
 }
 
+func (m *DeliverResponse) GetBlockAndPrivateData() *BlockAndPrivateData {
+	if x, ok := m.GetType().(*DeliverResponse_BlockAndPrivateData); ok {
+		return x.BlockAndPrivateData
+	}
+	return nil
+}
+
+func (m *DeliverResponse) GetCheckpoint() []byte {
+	if m != nil {
+		return m.Checkpoint
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*DeliverResponse) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _DeliverResponse_OneofMarshaler, _DeliverResponse_OneofUnmarshaler, _DeliverResponse_OneofSizer, []interface{}{
 		(*DeliverResponse_Status)(nil),
 		(*DeliverResponse_Block)(nil),
 		(*DeliverResponse_FilteredBlock)(nil),
+		(*DeliverResponse_BlockAndPrivateData)(nil),
 	}
 }
 
@@ -406,6 +600,11 @@ func _DeliverResponse_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.FilteredBlock); err != nil {
 			return err
 		}
+	case *DeliverResponse_BlockAndPrivateData:
+		b.EncodeVarint(4<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.BlockAndPrivateData); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("DeliverResponse.Type has unexpected type %T", x)
@@ -439,6 +638,14 @@ func _DeliverResponse_OneofUnmarshaler(msg proto.Message, tag, wire int, b *prot
 		err := b.DecodeMessage(msg)
 		m.Type = &DeliverResponse_FilteredBlock{msg}
 		return true, err
+	case 4: // Type.block_and_private_data
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(BlockAndPrivateData)
+		err := b.DecodeMessage(msg)
+		m.Type = &DeliverResponse_BlockAndPrivateData{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -461,6 +668,11 @@ func _DeliverResponse_OneofSizer(msg proto.Message) (n int) {
 		n += 1 // tag and wire
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *DeliverResponse_BlockAndPrivateData:
+		s := proto.Size(x.BlockAndPrivateData)
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -473,6 +685,10 @@ func init() {
 	proto.RegisterType((*FilteredTransaction)(nil), "protos.FilteredTransaction")
 	proto.RegisterType((*FilteredTransactionActions)(nil), "protos.FilteredTransactionActions")
 	proto.RegisterType((*FilteredChaincodeAction)(nil), "protos.FilteredChaincodeAction")
+	proto.RegisterType((*ChaincodeEventFilter)(nil), "protos.ChaincodeEventFilter")
+	proto.RegisterType((*BlockSlimming)(nil), "protos.BlockSlimming")
+	proto.RegisterType((*BlockAndPrivateData)(nil), "protos.BlockAndPrivateData")
+	proto.RegisterMapType((map[uint64]*rwset.TxPvtReadWriteSet)(nil), "protos.BlockAndPrivateData.PrivateDataMapEntry")
 	proto.RegisterType((*DeliverResponse)(nil), "protos.DeliverResponse")
 }
 
@@ -495,6 +711,10 @@ type DeliverClient interface {
 	// Payload data as a marshaled orderer.SeekInfo message,
 	// then a stream of **filtered** block replies is received
 	DeliverFiltered(ctx context.Context, opts ...grpc.CallOption) (Deliver_DeliverFilteredClient, error)
+	// deliver first requires an Envelope of type ab.DELIVER_SEEK_INFO with
+	// Payload data as a marshaled orderer.SeekInfo message,
+	// then a stream of block and private data replies is received
+	DeliverWithPrivateData(ctx context.Context, opts ...grpc.CallOption) (Deliver_DeliverWithPrivateDataClient, error)
 }
 
 type deliverClient struct {
@@ -567,6 +787,37 @@ func (x *deliverDeliverFilteredClient) Recv() (*DeliverResponse, error) {
 	return m, nil
 }
 
+func (c *deliverClient) DeliverWithPrivateData(ctx context.Context, opts ...grpc.CallOption) (Deliver_DeliverWithPrivateDataClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Deliver_serviceDesc.Streams[2], c.cc, "/protos.Deliver/DeliverWithPrivateData", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &deliverDeliverWithPrivateDataClient{stream}
+	return x, nil
+}
+
+type Deliver_DeliverWithPrivateDataClient interface {
+	Send(*common.Envelope) error
+	Recv() (*DeliverResponse, error)
+	grpc.ClientStream
+}
+
+type deliverDeliverWithPrivateDataClient struct {
+	grpc.ClientStream
+}
+
+func (x *deliverDeliverWithPrivateDataClient) Send(m *common.Envelope) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *deliverDeliverWithPrivateDataClient) Recv() (*DeliverResponse, error) {
+	m := new(DeliverResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for Deliver service
 
 type DeliverServer interface {
@@ -578,6 +829,10 @@ type DeliverServer interface {
 	// Payload data as a marshaled orderer.SeekInfo message,
 	// then a stream of **filtered** block replies is received
 	DeliverFiltered(Deliver_DeliverFilteredServer) error
+	// deliver first requires an Envelope of type ab.DELIVER_SEEK_INFO with
+	// Payload data as a marshaled orderer.SeekInfo message,
+	// then a stream of block and private data replies is received
+	DeliverWithPrivateData(Deliver_DeliverWithPrivateDataServer) error
 }
 
 func RegisterDeliverServer(s *grpc.Server, srv DeliverServer) {
@@ -636,6 +891,32 @@ func (x *deliverDeliverFilteredServer) Recv() (*common.Envelope, error) {
 	return m, nil
 }
 
+func _Deliver_DeliverWithPrivateData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DeliverServer).DeliverWithPrivateData(&deliverDeliverWithPrivateDataServer{stream})
+}
+
+type Deliver_DeliverWithPrivateDataServer interface {
+	Send(*DeliverResponse) error
+	Recv() (*common.Envelope, error)
+	grpc.ServerStream
+}
+
+type deliverDeliverWithPrivateDataServer struct {
+	grpc.ServerStream
+}
+
+func (x *deliverDeliverWithPrivateDataServer) Send(m *DeliverResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *deliverDeliverWithPrivateDataServer) Recv() (*common.Envelope, error) {
+	m := new(common.Envelope)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _Deliver_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "protos.Deliver",
 	HandlerType: (*DeliverServer)(nil),
@@ -653,47 +934,72 @@ var _Deliver_serviceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "DeliverWithPrivateData",
+			Handler:       _Deliver_DeliverWithPrivateData_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "peer/events.proto",
 }
 
-func init() { proto.RegisterFile("peer/events.proto", fileDescriptor_events_8af932975aef5a3c) }
-
-var fileDescriptor_events_8af932975aef5a3c = []byte{
-	// 560 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0xcd, 0x6e, 0xd3, 0x40,
-	0x10, 0x8e, 0x69, 0x08, 0xea, 0x44, 0x49, 0xdb, 0x2d, 0x4d, 0xa3, 0x20, 0xd4, 0xc8, 0x12, 0xc8,
-	0x5c, 0x62, 0x64, 0x6e, 0x1c, 0x40, 0xa4, 0x3f, 0x0a, 0x12, 0x87, 0x6a, 0x09, 0x1c, 0x7a, 0xc0,
-	0x5a, 0xdb, 0x13, 0xc7, 0xd4, 0xf1, 0x5a, 0xbb, 0x9b, 0x28, 0x79, 0x04, 0xde, 0x80, 0x67, 0xe0,
-	0x09, 0x39, 0x22, 0xaf, 0xbd, 0x49, 0x9a, 0x52, 0x24, 0x4e, 0xf6, 0xce, 0x7c, 0x3f, 0x33, 0xb3,
-	0x63, 0xc3, 0x51, 0x8e, 0x28, 0x5c, 0x5c, 0x60, 0xa6, 0xe4, 0x20, 0x17, 0x5c, 0x71, 0xd2, 0xd0,
-	0x0f, 0xd9, 0x3b, 0x0e, 0xf9, 0x6c, 0xc6, 0x33, 0xb7, 0x7c, 0x94, 0xc9, 0xde, 0x59, 0xcc, 0x79,
-	0x9c, 0xa2, 0xab, 0x4f, 0xc1, 0x7c, 0xe2, 0xaa, 0x64, 0x86, 0x52, 0xb1, 0x59, 0x5e, 0x01, 0x7a,
-	0x5a, 0x30, 0x9c, 0xb2, 0x24, 0x0b, 0x79, 0x84, 0xbe, 0x96, 0xae, 0x72, 0x1d, 0x9d, 0x53, 0x82,
-	0x65, 0x92, 0x85, 0x2a, 0x31, 0xa2, 0xf6, 0x4f, 0x0b, 0x5a, 0x57, 0x49, 0xaa, 0x50, 0x60, 0x34,
-	0x4c, 0x79, 0x78, 0x4b, 0x9e, 0x03, 0x84, 0x53, 0x96, 0x65, 0x98, 0xfa, 0x49, 0xd4, 0xb5, 0xfa,
-	0x96, 0xb3, 0x4f, 0xf7, 0xab, 0xc8, 0xc7, 0x88, 0x74, 0xa0, 0x91, 0xcd, 0x67, 0x01, 0x8a, 0xee,
-	0xa3, 0xbe, 0xe5, 0xd4, 0x69, 0x75, 0x22, 0xd7, 0x70, 0x32, 0xa9, 0x74, 0xfc, 0x2d, 0x1b, 0xd9,
-	0xad, 0xf7, 0xf7, 0x9c, 0xa6, 0xf7, 0xac, 0xf4, 0x93, 0x03, 0x63, 0x36, 0xde, 0x60, 0xe8, 0xd3,
-	0xc9, 0xfd, 0xa0, 0xb4, 0x7f, 0x5b, 0x70, 0xfc, 0x17, 0x34, 0x21, 0x50, 0x57, 0xcb, 0x75, 0x69,
-	0xfa, 0x9d, 0xbc, 0x84, 0xba, 0x5a, 0xe5, 0xa8, 0x6b, 0x6a, 0x7b, 0x64, 0x50, 0x0d, 0x6e, 0x84,
-	0x2c, 0x42, 0x31, 0x5e, 0xe5, 0x48, 0x75, 0x9e, 0x5c, 0x01, 0x51, 0x4b, 0x7f, 0xc1, 0xd2, 0x24,
-	0x62, 0x85, 0x98, 0x5f, 0x0c, 0xaa, 0xbb, 0xa7, 0x59, 0x5d, 0x53, 0xe2, 0x78, 0xf9, 0x75, 0x0d,
-	0x38, 0xe7, 0x11, 0xd2, 0x43, 0xb5, 0x13, 0x21, 0x5f, 0xe0, 0x78, 0xab, 0x49, 0x7f, 0xd3, 0xab,
-	0xe5, 0x34, 0x3d, 0xfb, 0x1f, 0xbd, 0x7e, 0x28, 0x91, 0xa3, 0x1a, 0x25, 0xea, 0x5e, 0x74, 0xd8,
-	0x80, 0xfa, 0x05, 0x53, 0xcc, 0xfe, 0x0e, 0xbd, 0x87, 0xb9, 0xe4, 0x13, 0x1c, 0x6d, 0x2e, 0xd9,
-	0x58, 0x5b, 0x7a, 0xcc, 0x67, 0xbb, 0xd6, 0xe7, 0x06, 0x58, 0x92, 0xe9, 0x61, 0x78, 0x37, 0x20,
-	0xed, 0x1b, 0x38, 0x7d, 0x00, 0x4c, 0xde, 0xc3, 0xc1, 0xce, 0x36, 0xe9, 0xa1, 0x37, 0xbd, 0x8e,
-	0xb1, 0x59, 0x33, 0x2e, 0x8b, 0x2c, 0x6d, 0x87, 0x77, 0xce, 0xf6, 0x2f, 0x0b, 0x0e, 0x2e, 0x30,
-	0x4d, 0x16, 0x28, 0x28, 0xca, 0x9c, 0x67, 0x12, 0x89, 0x03, 0x0d, 0xa9, 0x98, 0x9a, 0x4b, 0xad,
-	0xd5, 0xf6, 0xda, 0xe6, 0xb2, 0x3e, 0xeb, 0xe8, 0xa8, 0x46, 0xab, 0x3c, 0x79, 0x01, 0x8f, 0x83,
-	0x62, 0x25, 0xf5, 0xad, 0x36, 0xbd, 0x96, 0x01, 0xea, 0x3d, 0x1d, 0xd5, 0x68, 0x99, 0x25, 0xef,
-	0xa0, 0xbd, 0xde, 0xbc, 0x12, 0xbf, 0xa7, 0xf1, 0x27, 0xbb, 0xb3, 0x30, 0xbc, 0xd6, 0x64, 0x3b,
-	0x50, 0x0c, 0xbd, 0xd8, 0x10, 0xef, 0x87, 0x05, 0x4f, 0xaa, 0x62, 0xc9, 0xdb, 0xcd, 0xeb, 0xa1,
-	0xb1, 0xbd, 0xcc, 0x16, 0x98, 0xf2, 0x1c, 0x7b, 0xa7, 0x46, 0x78, 0xa7, 0x35, 0xbb, 0xe6, 0x58,
-	0xaf, 0x2d, 0x32, 0x5c, 0xf7, 0x6c, 0x8c, 0xff, 0x5b, 0x63, 0xf8, 0x0d, 0x6c, 0x2e, 0xe2, 0xc1,
-	0x74, 0x95, 0xa3, 0x48, 0x31, 0x8a, 0x51, 0x0c, 0x26, 0x2c, 0x10, 0x49, 0x68, 0x68, 0xc5, 0xe7,
-	0x3c, 0x6c, 0xe9, 0x29, 0xcb, 0x6b, 0x16, 0xde, 0xb2, 0x18, 0x6f, 0x5e, 0xc5, 0x89, 0x9a, 0xce,
-	0x83, 0xc2, 0xcb, 0xdd, 0x62, 0xba, 0x25, 0xb3, 0xfc, 0x6f, 0x48, 0xb7, 0x60, 0x06, 0xe5, 0x8f,
-	0xe6, 0xcd, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xd3, 0x19, 0xd1, 0xa9, 0x84, 0x04, 0x00, 0x00,
+func init() { proto.RegisterFile("peer/events.proto", fileDescriptor_events_07e58e8b9c8e58a5) }
+
+var fileDescriptor_events_07e58e8b9c8e58a5 = []byte{
+	// 850 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x55, 0x4d, 0x6f, 0x1b, 0x45,
+	0x18, 0xf6, 0x3a, 0x4e, 0x48, 0x5e, 0xd7, 0x8e, 0x3b, 0x6e, 0xdd, 0x95, 0x2b, 0x68, 0x58, 0x54,
+	0xe4, 0x5e, 0xbc, 0xc8, 0x5c, 0x50, 0x0f, 0xa0, 0x3a, 0x4d, 0xe5, 0x48, 0x80, 0xa2, 0x49, 0xa0,
+	0x50, 0x24, 0x56, 0xe3, 0xdd, 0xd7, 0xf6, 0x90, 0xfd, 0xd2, 0xcc, 0xd8, 0xc4, 0xff, 0x84, 0x13,
+	0x57, 0xfe, 0x10, 0xbf, 0x84, 0x13, 0xc7, 0x6a, 0x67, 0x76, 0xd7, 0x6b, 0xd7, 0xad, 0xd4, 0x8b,
+	0x3d, 0xfb, 0xbc, 0xcf, 0xfb, 0xf5, 0xbc, 0xf3, 0x01, 0xf7, 0x53, 0x44, 0xe1, 0xe2, 0x0a, 0x63,
+	0x25, 0x87, 0xa9, 0x48, 0x54, 0x42, 0x8e, 0xf4, 0x9f, 0xec, 0x77, 0xfd, 0x24, 0x8a, 0x92, 0xd8,
+	0x35, 0x7f, 0xc6, 0xd8, 0x7f, 0x32, 0x4f, 0x92, 0x79, 0x88, 0xae, 0xfe, 0x9a, 0x2e, 0x67, 0xae,
+	0xe2, 0x11, 0x4a, 0xc5, 0xa2, 0x34, 0x27, 0xd8, 0x21, 0x06, 0x73, 0x14, 0xae, 0xf8, 0x53, 0xa2,
+	0x32, 0xbf, 0xb9, 0xa5, 0xaf, 0x53, 0xf9, 0x0b, 0xc6, 0x63, 0x3f, 0x09, 0xd0, 0xd3, 0x49, 0x73,
+	0x5b, 0x4f, 0xdb, 0x94, 0x60, 0xb1, 0x64, 0xbe, 0xe2, 0x45, 0x3a, 0xe7, 0x2f, 0x0b, 0x5a, 0xaf,
+	0x78, 0xa8, 0x50, 0x60, 0x30, 0x0e, 0x13, 0xff, 0x96, 0x7c, 0x0a, 0xe0, 0x2f, 0x58, 0x1c, 0x63,
+	0xe8, 0xf1, 0xc0, 0xb6, 0xce, 0xac, 0xc1, 0x09, 0x3d, 0xc9, 0x91, 0xcb, 0x80, 0xf4, 0xe0, 0x28,
+	0x5e, 0x46, 0x53, 0x14, 0x76, 0xfd, 0xcc, 0x1a, 0x34, 0x68, 0xfe, 0x45, 0xae, 0xe0, 0xe1, 0x2c,
+	0x8f, 0xe3, 0x55, 0xd2, 0x48, 0xbb, 0x71, 0x76, 0x30, 0x68, 0x8e, 0x1e, 0x9b, 0x7c, 0x72, 0x58,
+	0x24, 0xbb, 0xd9, 0x70, 0xe8, 0x83, 0xd9, 0xbb, 0xa0, 0x74, 0xfe, 0xb7, 0xa0, 0xbb, 0x87, 0x4d,
+	0x08, 0x34, 0xd4, 0x5d, 0x59, 0x9a, 0x5e, 0x93, 0x2f, 0xa1, 0xa1, 0xd6, 0x29, 0xea, 0x9a, 0xda,
+	0x23, 0x32, 0xcc, 0x25, 0x9d, 0x20, 0x0b, 0x50, 0xdc, 0xac, 0x53, 0xa4, 0xda, 0x4e, 0x5e, 0x01,
+	0x51, 0x77, 0xde, 0x8a, 0x85, 0x3c, 0x60, 0x59, 0x30, 0x2f, 0x13, 0xca, 0x3e, 0xd0, 0x5e, 0x76,
+	0x51, 0xe2, 0xcd, 0xdd, 0xcf, 0x25, 0xe1, 0x3c, 0x09, 0x90, 0x76, 0xd4, 0x0e, 0x42, 0x7e, 0x82,
+	0x6e, 0xa5, 0x49, 0x6f, 0xd3, 0xab, 0x35, 0x68, 0x8e, 0x9c, 0x0f, 0xf4, 0xfa, 0xc2, 0x30, 0x27,
+	0x35, 0x4a, 0xd4, 0x3b, 0xe8, 0xf8, 0x08, 0x1a, 0x2f, 0x99, 0x62, 0xce, 0x1f, 0xd0, 0x7f, 0xbf,
+	0x2f, 0xf9, 0x1e, 0xee, 0x6f, 0x86, 0x5c, 0xa4, 0xb6, 0xb4, 0xcc, 0x4f, 0x76, 0x53, 0x9f, 0x17,
+	0x44, 0xe3, 0x4c, 0x3b, 0xfe, 0x36, 0x20, 0x9d, 0x37, 0xf0, 0xe8, 0x3d, 0x64, 0xf2, 0x1d, 0x9c,
+	0xee, 0xec, 0x26, 0x2d, 0x7a, 0x73, 0xd4, 0x2b, 0xd2, 0x94, 0x1e, 0x17, 0x99, 0x95, 0xb6, 0xfd,
+	0xad, 0x6f, 0xe7, 0x17, 0x78, 0xb0, 0xcd, 0x30, 0x99, 0xc8, 0xe7, 0x70, 0x6f, 0x13, 0xb8, 0x1c,
+	0x65, 0xb3, 0xc4, 0x2e, 0x83, 0x6c, 0x1b, 0xea, 0x8c, 0x5e, 0xcc, 0x22, 0x33, 0xd7, 0x13, 0x7a,
+	0xa2, 0x91, 0x1f, 0x59, 0x84, 0xce, 0x3f, 0x16, 0xb4, 0xf4, 0x7e, 0xbd, 0x0e, 0x79, 0x14, 0xf1,
+	0x78, 0x4e, 0x9e, 0x41, 0x47, 0x2a, 0xc1, 0x53, 0x4f, 0xf2, 0x79, 0xcc, 0xd4, 0x52, 0xa0, 0xd4,
+	0x71, 0x8f, 0xe9, 0xa9, 0xc6, 0xaf, 0x4b, 0x98, 0x3c, 0x85, 0xb6, 0xa1, 0xfa, 0x02, 0x99, 0x4a,
+	0x84, 0xd4, 0xf1, 0x8f, 0x69, 0x4b, 0xa3, 0xe7, 0x39, 0x98, 0x55, 0x69, 0x68, 0xfa, 0x90, 0x49,
+	0xbd, 0x4d, 0x8e, 0x69, 0x53, 0x63, 0x54, 0x43, 0x1b, 0x8a, 0x39, 0xe0, 0x76, 0xa3, 0x42, 0xd1,
+	0x0d, 0x4b, 0xe7, 0x3f, 0x0b, 0xba, 0xba, 0xd2, 0x17, 0x71, 0x70, 0x25, 0xf8, 0x8a, 0x29, 0xcc,
+	0x66, 0x4c, 0xbe, 0x80, 0xc3, 0x69, 0x06, 0xe7, 0x92, 0xb6, 0x8a, 0x3d, 0xab, 0xb9, 0xd4, 0xd8,
+	0xc8, 0xaf, 0xd0, 0x49, 0x8d, 0x8f, 0x17, 0x30, 0xc5, 0xbc, 0x88, 0xa5, 0x76, 0x5d, 0x4f, 0xda,
+	0x2d, 0x46, 0xb0, 0x27, 0xf6, 0xb0, 0xb2, 0xfe, 0x81, 0xa5, 0x17, 0xb1, 0x12, 0x6b, 0xda, 0x4e,
+	0xb7, 0xc0, 0xfe, 0x6f, 0xd0, 0xdd, 0x43, 0x23, 0x1d, 0x38, 0xb8, 0xc5, 0xb5, 0x2e, 0xaa, 0x41,
+	0xb3, 0x25, 0x19, 0xc2, 0xe1, 0x8a, 0x85, 0x4b, 0x33, 0x84, 0xe6, 0xc8, 0x1e, 0x9a, 0x3b, 0xe7,
+	0xe6, 0xee, 0x6a, 0xa5, 0x28, 0xb2, 0xe0, 0xb5, 0xe0, 0x0a, 0xaf, 0x51, 0x51, 0x43, 0x7b, 0x5e,
+	0xff, 0xc6, 0x72, 0xfe, 0xae, 0xc3, 0xe9, 0x4b, 0x0c, 0xf9, 0x0a, 0x05, 0x45, 0x99, 0x26, 0xb1,
+	0x44, 0x32, 0x80, 0x23, 0xa9, 0x98, 0x5a, 0x9a, 0xb1, 0xb4, 0x47, 0xed, 0xa2, 0xe3, 0x6b, 0x8d,
+	0x4e, 0x6a, 0x34, 0xb7, 0x93, 0xa7, 0x85, 0x34, 0xf5, 0x3d, 0xd2, 0x4c, 0x6a, 0x85, 0x38, 0xdf,
+	0x42, 0xbb, 0xbc, 0x72, 0x0c, 0xff, 0x40, 0xf3, 0x1f, 0xee, 0x1e, 0x82, 0xc2, 0xaf, 0x35, 0xab,
+	0x02, 0x84, 0x42, 0x4f, 0xbb, 0x79, 0x2c, 0x0e, 0xbc, 0xaa, 0xcc, 0xf9, 0x39, 0x7e, 0xfc, 0x01,
+	0x89, 0x27, 0x35, 0xda, 0x9d, 0xee, 0x99, 0xea, 0x67, 0xd9, 0xed, 0x89, 0xfe, 0x6d, 0x9a, 0xf0,
+	0x58, 0xd9, 0x87, 0x67, 0xd6, 0xe0, 0x1e, 0xad, 0x20, 0xd9, 0x09, 0xcf, 0xae, 0xa3, 0xd1, 0xbf,
+	0x16, 0x7c, 0x92, 0x0b, 0x44, 0x9e, 0x6f, 0x96, 0x9d, 0xa2, 0xd5, 0x8b, 0x78, 0x85, 0x61, 0x92,
+	0x62, 0xff, 0x51, 0x51, 0xc4, 0x8e, 0x9c, 0x4e, 0x6d, 0x60, 0x7d, 0x65, 0x91, 0x71, 0xa9, 0x73,
+	0xd1, 0xec, 0xc7, 0xc7, 0xb8, 0x84, 0x5e, 0x6e, 0x78, 0xcd, 0xd5, 0xa2, 0xda, 0xcd, 0xc7, 0x86,
+	0x1a, 0xff, 0x0e, 0x4e, 0x22, 0xe6, 0xc3, 0xc5, 0x3a, 0x45, 0x61, 0xde, 0xa9, 0xe1, 0x8c, 0x4d,
+	0x05, 0xf7, 0x0b, 0xb7, 0xec, 0x19, 0x1a, 0xb7, 0xcc, 0xd1, 0xb8, 0x62, 0xfe, 0x2d, 0x9b, 0xe3,
+	0x9b, 0x67, 0x73, 0xae, 0x16, 0xcb, 0x69, 0x96, 0xcb, 0xad, 0x78, 0xba, 0xc6, 0xd3, 0xbc, 0x84,
+	0xd2, 0xcd, 0x3c, 0xa7, 0xe6, 0xe9, 0xfc, 0xfa, 0xed, 0x00, 0xb5, 0x56, 0xd7, 0x5a, 0x56, 0x07,
+	0x00, 0x00,
 }
@@ -34,7 +34,7 @@ func (m *PeerID) Reset()         { *m = PeerID{} }
 func (m *PeerID) String() string { return proto.CompactTextString(m) }
 func (*PeerID) ProtoMessage()    {}
 func (*PeerID) Descriptor() ([]byte, []int) {
-	return fileDescriptor_peer_f225b6e437338532, []int{0}
+	return fileDescriptor_peer_9d96413776a000e6, []int{0}
 }
 func (m *PeerID) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PeerID.Unmarshal(m, b)
@@ -73,7 +73,7 @@ func (m *PeerEndpoint) Reset()         { *m = PeerEndpoint{} }
 func (m *PeerEndpoint) String() string { return proto.CompactTextString(m) }
 func (*PeerEndpoint) ProtoMessage()    {}
 func (*PeerEndpoint) Descriptor() ([]byte, []int) {
-	return fileDescriptor_peer_f225b6e437338532, []int{1}
+	return fileDescriptor_peer_9d96413776a000e6, []int{1}
 }
 func (m *PeerEndpoint) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PeerEndpoint.Unmarshal(m, b)
@@ -107,9 +107,50 @@ func (m *PeerEndpoint) GetAddress() string {
 	return ""
 }
 
+// SignedProposalChunk carries a part of a marshaled SignedProposal which is
+// too large to be sent to the peer in a single message
+type SignedProposalChunk struct {
+	Content              []byte   `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SignedProposalChunk) Reset()         { *m = SignedProposalChunk{} }
+func (m *SignedProposalChunk) String() string { return proto.CompactTextString(m) }
+func (*SignedProposalChunk) ProtoMessage()    {}
+func (*SignedProposalChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_peer_9d96413776a000e6, []int{2}
+}
+func (m *SignedProposalChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignedProposalChunk.Unmarshal(m, b)
+}
+func (m *SignedProposalChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignedProposalChunk.Marshal(b, m, deterministic)
+}
+func (dst *SignedProposalChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignedProposalChunk.Merge(dst, src)
+}
+func (m *SignedProposalChunk) XXX_Size() int {
+	return xxx_messageInfo_SignedProposalChunk.Size(m)
+}
+func (m *SignedProposalChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignedProposalChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignedProposalChunk proto.InternalMessageInfo
+
+func (m *SignedProposalChunk) GetContent() []byte {
+	if m != nil {
+		return m.Content
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*PeerID)(nil), "protos.PeerID")
 	proto.RegisterType((*PeerEndpoint)(nil), "protos.PeerEndpoint")
+	proto.RegisterType((*SignedProposalChunk)(nil), "protos.SignedProposalChunk")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -184,24 +225,124 @@ var _Endorser_serviceDesc = grpc.ServiceDesc{
 	Metadata: "peer/peer.proto",
 }
 
-func init() { proto.RegisterFile("peer/peer.proto", fileDescriptor_peer_f225b6e437338532) }
-
-var fileDescriptor_peer_f225b6e437338532 = []byte{
-	// 246 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x90, 0x4f, 0x4b, 0xc4, 0x30,
-	0x10, 0xc5, 0xdd, 0x22, 0xab, 0x8e, 0xe2, 0x42, 0x04, 0x29, 0x65, 0x11, 0xe9, 0x49, 0x2f, 0x29,
-	0xac, 0xdf, 0x40, 0x2c, 0xe8, 0xc9, 0x5a, 0x6f, 0x5e, 0xa4, 0x6d, 0xc6, 0x6e, 0x60, 0x37, 0x13,
-	0x66, 0xea, 0xc1, 0x6f, 0x2f, 0x4d, 0x5a, 0x71, 0x2f, 0xf9, 0xf3, 0xde, 0x6f, 0x5e, 0x26, 0x03,
-	0x2b, 0x8f, 0xc8, 0xc5, 0xb8, 0x68, 0xcf, 0x34, 0x90, 0x5a, 0x86, 0x4d, 0xb2, 0xab, 0x68, 0x30,
-	0x79, 0x92, 0x66, 0x17, 0xcd, 0x6c, 0x7d, 0x20, 0x7e, 0x32, 0x8a, 0x27, 0x27, 0x18, 0xdd, 0x7c,
-	0x0d, 0xcb, 0x0a, 0x91, 0x5f, 0x9e, 0x94, 0x82, 0x63, 0xd7, 0xec, 0x31, 0x5d, 0xdc, 0x2e, 0xee,
-	0xce, 0xea, 0x70, 0xce, 0x9f, 0xe1, 0x62, 0x74, 0x4b, 0x67, 0x3c, 0x59, 0x37, 0xa8, 0x1b, 0x48,
-	0xac, 0x09, 0xc4, 0xf9, 0xe6, 0x32, 0x26, 0x88, 0x8e, 0xf5, 0x75, 0x62, 0x8d, 0x4a, 0xe1, 0xa4,
-	0x31, 0x86, 0x51, 0x24, 0x4d, 0x42, 0xcc, 0x7c, 0xdd, 0xbc, 0xc1, 0x69, 0xe9, 0x0c, 0xb1, 0x20,
-	0xab, 0x12, 0x56, 0x15, 0x53, 0x87, 0x22, 0xd5, 0xd4, 0x95, 0xba, 0x9e, 0xc3, 0xde, 0x6d, 0xef,
-	0xd0, 0xcc, 0x7a, 0x96, 0xfe, 0x3d, 0x32, 0x29, 0xf5, 0xd4, 0x7e, 0x7e, 0xf4, 0xf8, 0x0a, 0x39,
-	0x71, 0xaf, 0xb7, 0x3f, 0x1e, 0x79, 0x87, 0xa6, 0x47, 0xd6, 0x5f, 0x4d, 0xcb, 0xb6, 0x9b, 0x6b,
-	0xc6, 0x8f, 0x7f, 0xdc, 0xf7, 0x76, 0xd8, 0x7e, 0xb7, 0xba, 0xa3, 0x7d, 0xf1, 0x0f, 0x2d, 0x22,
-	0x5a, 0x44, 0x34, 0x0c, 0xb3, 0x8d, 0x63, 0x7c, 0xf8, 0x0d, 0x00, 0x00, 0xff, 0xff, 0xef, 0x32,
-	0xf2, 0x1f, 0x60, 0x01, 0x00, 0x00,
+// Client API for ChunkedEndorser service
+
+type ChunkedEndorserClient interface {
+	ProcessProposal(ctx context.Context, opts ...grpc.CallOption) (ChunkedEndorser_ProcessProposalClient, error)
+}
+
+type chunkedEndorserClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewChunkedEndorserClient(cc *grpc.ClientConn) ChunkedEndorserClient {
+	return &chunkedEndorserClient{cc}
+}
+
+func (c *chunkedEndorserClient) ProcessProposal(ctx context.Context, opts ...grpc.CallOption) (ChunkedEndorser_ProcessProposalClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_ChunkedEndorser_serviceDesc.Streams[0], c.cc, "/protos.ChunkedEndorser/ProcessProposal", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &chunkedEndorserProcessProposalClient{stream}
+	return x, nil
+}
+
+type ChunkedEndorser_ProcessProposalClient interface {
+	Send(*SignedProposalChunk) error
+	CloseAndRecv() (*ProposalResponse, error)
+	grpc.ClientStream
+}
+
+type chunkedEndorserProcessProposalClient struct {
+	grpc.ClientStream
+}
+
+func (x *chunkedEndorserProcessProposalClient) Send(m *SignedProposalChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *chunkedEndorserProcessProposalClient) CloseAndRecv() (*ProposalResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ProposalResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for ChunkedEndorser service
+
+type ChunkedEndorserServer interface {
+	ProcessProposal(ChunkedEndorser_ProcessProposalServer) error
+}
+
+func RegisterChunkedEndorserServer(s *grpc.Server, srv ChunkedEndorserServer) {
+	s.RegisterService(&_ChunkedEndorser_serviceDesc, srv)
+}
+
+func _ChunkedEndorser_ProcessProposal_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ChunkedEndorserServer).ProcessProposal(&chunkedEndorserProcessProposalServer{stream})
+}
+
+type ChunkedEndorser_ProcessProposalServer interface {
+	SendAndClose(*ProposalResponse) error
+	Recv() (*SignedProposalChunk, error)
+	grpc.ServerStream
+}
+
+type chunkedEndorserProcessProposalServer struct {
+	grpc.ServerStream
+}
+
+func (x *chunkedEndorserProcessProposalServer) SendAndClose(m *ProposalResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *chunkedEndorserProcessProposalServer) Recv() (*SignedProposalChunk, error) {
+	m := new(SignedProposalChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _ChunkedEndorser_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "protos.ChunkedEndorser",
+	HandlerType: (*ChunkedEndorserServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ProcessProposal",
+			Handler:       _ChunkedEndorser_ProcessProposal_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "peer/peer.proto",
+}
+
+func init() { proto.RegisterFile("peer/peer.proto", fileDescriptor_peer_9d96413776a000e6) }
+
+var fileDescriptor_peer_9d96413776a000e6 = []byte{
+	// 288 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x91, 0xd1, 0x4b, 0xbc, 0x40,
+	0x10, 0xc7, 0x7f, 0xca, 0x8f, 0xab, 0xb6, 0x23, 0x61, 0x0f, 0x42, 0xec, 0x88, 0xf0, 0xe9, 0x7a,
+	0x51, 0xb0, 0xff, 0xa0, 0x12, 0x0a, 0x82, 0xcc, 0xde, 0x7a, 0x39, 0xd4, 0x9d, 0x74, 0xe9, 0x6e,
+	0x67, 0x99, 0xf5, 0x1e, 0xfa, 0xef, 0xc3, 0x5d, 0x3d, 0x3a, 0x8a, 0x5e, 0xd4, 0x99, 0xef, 0xf7,
+	0xfb, 0x99, 0x91, 0x61, 0x81, 0x06, 0xa0, 0x74, 0x78, 0x24, 0x9a, 0xb0, 0x47, 0x3e, 0xb3, 0x2f,
+	0x13, 0x2d, 0x9c, 0x40, 0xa8, 0xd1, 0x54, 0x1b, 0x27, 0x46, 0xcb, 0x83, 0xe6, 0x9a, 0xc0, 0x68,
+	0x54, 0x06, 0x9c, 0x1a, 0x2f, 0xd9, 0xac, 0x00, 0xa0, 0xc7, 0x7b, 0xce, 0xd9, 0x7f, 0x55, 0x6d,
+	0x21, 0xf4, 0xae, 0xbc, 0xd5, 0x49, 0x69, 0xbf, 0xe3, 0x07, 0x36, 0x1f, 0xd4, 0x5c, 0x09, 0x8d,
+	0x52, 0xf5, 0xfc, 0x92, 0xf9, 0x52, 0x58, 0xc7, 0x69, 0x76, 0xe6, 0x08, 0x26, 0x71, 0xf9, 0xd2,
+	0x97, 0x82, 0x87, 0xec, 0xa8, 0x12, 0x82, 0xc0, 0x98, 0xd0, 0xb7, 0x98, 0xa9, 0x8c, 0x53, 0xb6,
+	0x78, 0x95, 0xad, 0x02, 0x51, 0x8c, 0x8b, 0xdc, 0x75, 0x3b, 0xf5, 0x31, 0x04, 0x1a, 0x54, 0x3d,
+	0xa8, 0xde, 0x52, 0xe7, 0xe5, 0x54, 0x66, 0x2f, 0xec, 0x38, 0x57, 0x02, 0xc9, 0x00, 0xf1, 0x9c,
+	0x05, 0x05, 0x61, 0x03, 0xc6, 0x4c, 0x69, 0x7e, 0x3e, 0x4d, 0x3f, 0xa4, 0x46, 0xe1, 0x7e, 0xab,
+	0xb1, 0x53, 0x8e, 0xff, 0x1b, 0xff, 0xcb, 0xd6, 0x2c, 0xb0, 0x53, 0x41, 0xec, 0xc9, 0x4f, 0x3f,
+	0xc9, 0x17, 0xbf, 0x93, 0x6d, 0xf2, 0x2f, 0xfc, 0xca, 0xbb, 0x7d, 0x66, 0x31, 0x52, 0x9b, 0x74,
+	0x9f, 0x1a, 0x68, 0x03, 0xa2, 0x05, 0x4a, 0xde, 0xab, 0x9a, 0x64, 0x33, 0xa5, 0x86, 0x53, 0xbc,
+	0x5d, 0xb7, 0xb2, 0xef, 0x76, 0x75, 0xd2, 0xe0, 0x36, 0xfd, 0x66, 0x4d, 0x9d, 0x35, 0x75, 0x56,
+	0x7b, 0xde, 0xda, 0x1d, 0xf6, 0xe6, 0x6b, 0x00, 0x22, 0xd8, 0xcc, 0x99, 0xf2, 0x01, 0x00, 0x00,
 }
@@ -26,22 +26,22 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 // When an endorser receives a SignedProposal message, it should verify the
 // signature over the proposal bytes. This verification requires the following
 // steps:
-// 1. Verification of the validity of the certificate that was used to produce
-//    the signature.  The certificate will be available once proposalBytes has
-//    been unmarshalled to a Proposal message, and Proposal.header has been
-//    unmarshalled to a Header message. While this unmarshalling-before-verifying
-//    might not be ideal, it is unavoidable because i) the signature needs to also
-//    protect the signing certificate; ii) it is desirable that Header is created
-//    once by the client and never changed (for the sake of accountability and
-//    non-repudiation). Note also that it is actually impossible to conclusively
-//    verify the validity of the certificate included in a Proposal, because the
-//    proposal needs to first be endorsed and ordered with respect to certificate
-//    expiration transactions. Still, it is useful to pre-filter expired
-//    certificates at this stage.
-// 2. Verification that the certificate is trusted (signed by a trusted CA) and
-//    that it is allowed to transact with us (with respect to some ACLs);
-// 3. Verification that the signature on proposalBytes is valid;
-// 4. Detect replay attacks;
+//  1. Verification of the validity of the certificate that was used to produce
+//     the signature.  The certificate will be available once proposalBytes has
+//     been unmarshalled to a Proposal message, and Proposal.header has been
+//     unmarshalled to a Header message. While this unmarshalling-before-verifying
+//     might not be ideal, it is unavoidable because i) the signature needs to also
+//     protect the signing certificate; ii) it is desirable that Header is created
+//     once by the client and never changed (for the sake of accountability and
+//     non-repudiation). Note also that it is actually impossible to conclusively
+//     verify the validity of the certificate included in a Proposal, because the
+//     proposal needs to first be endorsed and ordered with respect to certificate
+//     expiration transactions. Still, it is useful to pre-filter expired
+//     certificates at this stage.
+//  2. Verification that the certificate is trusted (signed by a trusted CA) and
+//     that it is allowed to transact with us (with respect to some ACLs);
+//  3. Verification that the signature on proposalBytes is valid;
+//  4. Detect replay attacks;
 type SignedProposal struct {
 	// The bytes of Proposal
 	ProposalBytes []byte `protobuf:"bytes,1,opt,name=proposal_bytes,json=proposalBytes,proto3" json:"proposal_bytes,omitempty"`
@@ -58,7 +58,7 @@ func (m *SignedProposal) Reset()         { *m = SignedProposal{} }
 func (m *SignedProposal) String() string { return proto.CompactTextString(m) }
 func (*SignedProposal) ProtoMessage()    {}
 func (*SignedProposal) Descriptor() ([]byte, []int) {
-	return fileDescriptor_proposal_8e93dae9c9555ac0, []int{0}
+	return fileDescriptor_proposal_0fbc62283e84a027, []int{0}
 }
 func (m *SignedProposal) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SignedProposal.Unmarshal(m, b)
@@ -93,24 +93,24 @@ func (m *SignedProposal) GetSignature() []byte {
 }
 
 // A Proposal is sent to an endorser for endorsement.  The proposal contains:
-// 1. A header which should be unmarshaled to a Header message.  Note that
-//    Header is both the header of a Proposal and of a Transaction, in that i)
-//    both headers should be unmarshaled to this message; and ii) it is used to
-//    compute cryptographic hashes and signatures.  The header has fields common
-//    to all proposals/transactions.  In addition it has a type field for
-//    additional customization. An example of this is the ChaincodeHeaderExtension
-//    message used to extend the Header for type CHAINCODE.
-// 2. A payload whose type depends on the header's type field.
-// 3. An extension whose type depends on the header's type field.
+//  1. A header which should be unmarshaled to a Header message.  Note that
+//     Header is both the header of a Proposal and of a Transaction, in that i)
+//     both headers should be unmarshaled to this message; and ii) it is used to
+//     compute cryptographic hashes and signatures.  The header has fields common
+//     to all proposals/transactions.  In addition it has a type field for
+//     additional customization. An example of this is the ChaincodeHeaderExtension
+//     message used to extend the Header for type CHAINCODE.
+//  2. A payload whose type depends on the header's type field.
+//  3. An extension whose type depends on the header's type field.
 //
 // Let us see an example. For type CHAINCODE (see the Header message),
 // we have the following:
-// 1. The header is a Header message whose extensions field is a
-//    ChaincodeHeaderExtension message.
-// 2. The payload is a ChaincodeProposalPayload message.
-// 3. The extension is a ChaincodeAction that might be used to ask the
-//    endorsers to endorse a specific ChaincodeAction, thus emulating the
-//    submitting peer model.
+//  1. The header is a Header message whose extensions field is a
+//     ChaincodeHeaderExtension message.
+//  2. The payload is a ChaincodeProposalPayload message.
+//  3. The extension is a ChaincodeAction that might be used to ask the
+//     endorsers to endorse a specific ChaincodeAction, thus emulating the
+//     submitting peer model.
 type Proposal struct {
 	// The header of the proposal. It is the bytes of the Header
 	Header []byte `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
@@ -130,7 +130,7 @@ func (m *Proposal) Reset()         { *m = Proposal{} }
 func (m *Proposal) String() string { return proto.CompactTextString(m) }
 func (*Proposal) ProtoMessage()    {}
 func (*Proposal) Descriptor() ([]byte, []int) {
-	return fileDescriptor_proposal_8e93dae9c9555ac0, []int{1}
+	return fileDescriptor_proposal_0fbc62283e84a027, []int{1}
 }
 func (m *Proposal) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Proposal.Unmarshal(m, b)
@@ -188,17 +188,23 @@ type ChaincodeHeaderExtension struct {
 	// this field impacts the content of ProposalResponsePayload.proposalHash.
 	PayloadVisibility []byte `protobuf:"bytes,1,opt,name=payload_visibility,json=payloadVisibility,proto3" json:"payload_visibility,omitempty"`
 	// The ID of the chaincode to target.
-	ChaincodeId          *ChaincodeID `protobuf:"bytes,2,opt,name=chaincode_id,json=chaincodeId" json:"chaincode_id,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	ChaincodeId *ChaincodeID `protobuf:"bytes,2,opt,name=chaincode_id,json=chaincodeId" json:"chaincode_id,omitempty"`
+	// An optional key chosen by the application to identify the business
+	// operation of the transaction. On channels with a transaction dedup
+	// window, a transaction is invalidated with DUPLICATE_DEDUP_KEY when a
+	// valid transaction with the same key for the same chaincode was committed
+	// within the window, so that retried submissions are applied only once.
+	DedupKey             string   `protobuf:"bytes,3,opt,name=dedup_key,json=dedupKey" json:"dedup_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ChaincodeHeaderExtension) Reset()         { *m = ChaincodeHeaderExtension{} }
 func (m *ChaincodeHeaderExtension) String() string { return proto.CompactTextString(m) }
 func (*ChaincodeHeaderExtension) ProtoMessage()    {}
 func (*ChaincodeHeaderExtension) Descriptor() ([]byte, []int) {
-	return fileDescriptor_proposal_8e93dae9c9555ac0, []int{2}
+	return fileDescriptor_proposal_0fbc62283e84a027, []int{2}
 }
 func (m *ChaincodeHeaderExtension) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChaincodeHeaderExtension.Unmarshal(m, b)
@@ -232,6 +238,13 @@ func (m *ChaincodeHeaderExtension) GetChaincodeId() *ChaincodeID {
 	return nil
 }
 
+func (m *ChaincodeHeaderExtension) GetDedupKey() string {
+	if m != nil {
+		return m.DedupKey
+	}
+	return ""
+}
+
 // ChaincodeProposalPayload is the Proposal's payload message to be used when
 // the Header's type is CHAINCODE.  It contains the arguments for this
 // invocation.
@@ -254,7 +267,7 @@ func (m *ChaincodeProposalPayload) Reset()         { *m = ChaincodeProposalPaylo
 func (m *ChaincodeProposalPayload) String() string { return proto.CompactTextString(m) }
 func (*ChaincodeProposalPayload) ProtoMessage()    {}
 func (*ChaincodeProposalPayload) Descriptor() ([]byte, []int) {
-	return fileDescriptor_proposal_8e93dae9c9555ac0, []int{3}
+	return fileDescriptor_proposal_0fbc62283e84a027, []int{3}
 }
 func (m *ChaincodeProposalPayload) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChaincodeProposalPayload.Unmarshal(m, b)
@@ -314,7 +327,7 @@ func (m *ChaincodeAction) Reset()         { *m = ChaincodeAction{} }
 func (m *ChaincodeAction) String() string { return proto.CompactTextString(m) }
 func (*ChaincodeAction) ProtoMessage()    {}
 func (*ChaincodeAction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_proposal_8e93dae9c9555ac0, []int{4}
+	return fileDescriptor_proposal_0fbc62283e84a027, []int{4}
 }
 func (m *ChaincodeAction) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChaincodeAction.Unmarshal(m, b)
@@ -371,37 +384,38 @@ func init() {
 	proto.RegisterType((*ChaincodeAction)(nil), "protos.ChaincodeAction")
 }
 
-func init() { proto.RegisterFile("peer/proposal.proto", fileDescriptor_proposal_8e93dae9c9555ac0) }
-
-var fileDescriptor_proposal_8e93dae9c9555ac0 = []byte{
-	// 452 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x53, 0x4d, 0x6f, 0xd3, 0x4c,
-	0x10, 0x96, 0x93, 0xf7, 0xed, 0xc7, 0x24, 0xf4, 0x63, 0x5b, 0x21, 0x2b, 0xea, 0xa1, 0xb2, 0x84,
-	0x54, 0x24, 0xb0, 0xa5, 0x20, 0x21, 0xc4, 0x05, 0x11, 0xa8, 0x44, 0x0f, 0x48, 0x95, 0x81, 0x1e,
-	0x7a, 0x09, 0x6b, 0x7b, 0x70, 0x56, 0x35, 0xbb, 0xab, 0xdd, 0x75, 0x84, 0x8f, 0xfc, 0x1c, 0x7e,
-	0x0a, 0xff, 0x0a, 0xd9, 0xfb, 0xd1, 0x94, 0x5c, 0x38, 0x25, 0x33, 0xf3, 0x3c, 0xcf, 0xcc, 0x3c,
-	0xb3, 0x86, 0x13, 0x89, 0xa8, 0x32, 0xa9, 0x84, 0x14, 0x9a, 0x36, 0xa9, 0x54, 0xc2, 0x08, 0xb2,
-	0x33, 0xfc, 0xe8, 0xd9, 0xe9, 0x50, 0x2c, 0x57, 0x94, 0xf1, 0x52, 0x54, 0x68, 0xab, 0xb3, 0xb3,
-	0x07, 0x94, 0xa5, 0x42, 0x2d, 0x05, 0xd7, 0xae, 0x9a, 0x7c, 0x81, 0x83, 0x4f, 0xac, 0xe6, 0x58,
-	0x5d, 0x3b, 0x00, 0x79, 0x02, 0x07, 0x01, 0x5c, 0x74, 0x06, 0x75, 0x1c, 0x9d, 0x47, 0x17, 0xd3,
-	0xfc, 0x91, 0xcf, 0x2e, 0xfa, 0x24, 0x39, 0x83, 0x7d, 0xcd, 0x6a, 0x4e, 0x4d, 0xab, 0x30, 0x1e,
-	0x0d, 0x88, 0xfb, 0x44, 0x72, 0x0b, 0x7b, 0x41, 0xf0, 0x31, 0xec, 0xac, 0x90, 0x56, 0xa8, 0x9c,
-	0x90, 0x8b, 0x48, 0x0c, 0xbb, 0x92, 0x76, 0x8d, 0xa0, 0x95, 0xe3, 0xfb, 0xb0, 0xd7, 0xc6, 0x1f,
-	0x06, 0xb9, 0x66, 0x82, 0xc7, 0x63, 0xab, 0x1d, 0x12, 0xc9, 0xcf, 0x08, 0xe2, 0x77, 0x7e, 0xc9,
-	0x0f, 0x83, 0xd6, 0xa5, 0x2f, 0x92, 0xe7, 0x40, 0x9c, 0xca, 0x72, 0xcd, 0x34, 0x2b, 0x58, 0xc3,
-	0x4c, 0xe7, 0x1a, 0x1f, 0xbb, 0xca, 0x4d, 0x28, 0x90, 0x97, 0x30, 0x0d, 0x7e, 0x2d, 0x99, 0x1d,
-	0x64, 0x32, 0x3f, 0xb1, 0xe6, 0xe8, 0x34, 0xb4, 0xb9, 0x7a, 0x9f, 0x4f, 0x02, 0xf0, 0xaa, 0x4a,
-	0x7e, 0x6f, 0xce, 0xe0, 0x37, 0xbd, 0x76, 0xe3, 0x9f, 0xc2, 0xff, 0x8c, 0xcb, 0xd6, 0xb8, 0xb6,
-	0x36, 0x20, 0x37, 0x30, 0xfd, 0xac, 0x28, 0xd7, 0x0c, 0xb9, 0xf9, 0x48, 0x65, 0x3c, 0x3a, 0x1f,
-	0x5f, 0x4c, 0xe6, 0xf3, 0xad, 0x56, 0x7f, 0xa9, 0xa5, 0x9b, 0xa4, 0x4b, 0x6e, 0x54, 0x97, 0x3f,
-	0xd0, 0x99, 0xbd, 0x81, 0xe3, 0x2d, 0x08, 0x39, 0x82, 0xf1, 0x1d, 0xda, 0xbd, 0xf7, 0xf3, 0xfe,
-	0x6f, 0x3f, 0xd4, 0x9a, 0x36, 0xad, 0xbf, 0x95, 0x0d, 0x5e, 0x8f, 0x5e, 0x45, 0xc9, 0xaf, 0x08,
-	0x0e, 0x43, 0xf7, 0xb7, 0xa5, 0xe9, 0x6d, 0x8c, 0x61, 0x57, 0xa1, 0x6e, 0x1b, 0xe3, 0xaf, 0xef,
-	0xc3, 0xfe, 0x9a, 0xb8, 0x46, 0x6e, 0xb4, 0x13, 0x72, 0x11, 0x79, 0x06, 0x7b, 0xfe, 0x69, 0x0d,
-	0x27, 0x9b, 0xcc, 0x8f, 0xfc, 0x6a, 0xb9, 0xcb, 0xe7, 0x01, 0xb1, 0xe5, 0xfb, 0x7f, 0xff, 0xe6,
-	0xfb, 0xe2, 0x2b, 0x24, 0x42, 0xd5, 0xe9, 0xaa, 0x93, 0xa8, 0x1a, 0xac, 0x6a, 0x54, 0xe9, 0x37,
-	0x5a, 0x28, 0x56, 0x7a, 0x66, 0xff, 0xd8, 0x17, 0x87, 0xf7, 0x1e, 0x96, 0x77, 0xb4, 0xc6, 0xdb,
-	0xa7, 0x35, 0x33, 0xab, 0xb6, 0x48, 0x4b, 0xf1, 0x3d, 0xdb, 0xe0, 0x66, 0x96, 0x9b, 0x59, 0x6e,
-	0xd6, 0x73, 0x0b, 0xfb, 0x31, 0xbd, 0xf8, 0x13, 0x00, 0x00, 0xff, 0xff, 0x12, 0x75, 0xb6, 0xaf,
-	0x6a, 0x03, 0x00, 0x00,
+func init() { proto.RegisterFile("peer/proposal.proto", fileDescriptor_proposal_0fbc62283e84a027) }
+
+var fileDescriptor_proposal_0fbc62283e84a027 = []byte{
+	// 466 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x53, 0x5d, 0x6b, 0xd4, 0x40,
+	0x14, 0x25, 0xbb, 0xda, 0xee, 0xde, 0x5d, 0xfb, 0x31, 0x2d, 0x12, 0xd6, 0x3e, 0x94, 0x80, 0x50,
+	0x41, 0x13, 0x58, 0x41, 0xc4, 0x17, 0x71, 0xb5, 0x60, 0x11, 0xa1, 0x44, 0xed, 0x43, 0x5f, 0xd6,
+	0x49, 0x72, 0xcd, 0x0e, 0x8d, 0x33, 0xc3, 0xcc, 0x64, 0x31, 0x7f, 0xc6, 0x77, 0x7f, 0x8a, 0xff,
+	0x4a, 0x92, 0xcc, 0x4c, 0xb7, 0xee, 0x4b, 0x9f, 0x92, 0xfb, 0x71, 0xce, 0x9c, 0x7b, 0xee, 0x0c,
+	0x1c, 0x49, 0x44, 0x95, 0x48, 0x25, 0xa4, 0xd0, 0xb4, 0x8a, 0xa5, 0x12, 0x46, 0x90, 0x9d, 0xee,
+	0xa3, 0x67, 0xc7, 0x5d, 0x31, 0x5f, 0x51, 0xc6, 0x73, 0x51, 0x60, 0x5f, 0x9d, 0x9d, 0xdc, 0x81,
+	0x2c, 0x15, 0x6a, 0x29, 0xb8, 0xb6, 0xd5, 0xe8, 0x1b, 0xec, 0x7d, 0x61, 0x25, 0xc7, 0xe2, 0xd2,
+	0x36, 0x90, 0xa7, 0xb0, 0xe7, 0x9b, 0xb3, 0xc6, 0xa0, 0x0e, 0x83, 0xd3, 0xe0, 0x6c, 0x9a, 0x3e,
+	0x72, 0xd9, 0x45, 0x9b, 0x24, 0x27, 0x30, 0xd6, 0xac, 0xe4, 0xd4, 0xd4, 0x0a, 0xc3, 0x41, 0xd7,
+	0x71, 0x9b, 0x88, 0xae, 0x61, 0xe4, 0x09, 0x1f, 0xc3, 0xce, 0x0a, 0x69, 0x81, 0xca, 0x12, 0xd9,
+	0x88, 0x84, 0xb0, 0x2b, 0x69, 0x53, 0x09, 0x5a, 0x58, 0xbc, 0x0b, 0x5b, 0x6e, 0xfc, 0x65, 0x90,
+	0x6b, 0x26, 0x78, 0x38, 0xec, 0xb9, 0x7d, 0x22, 0xfa, 0x1d, 0x40, 0xf8, 0xde, 0x0d, 0xf9, 0xb1,
+	0xe3, 0x3a, 0x77, 0x45, 0xf2, 0x02, 0x88, 0x65, 0x59, 0xae, 0x99, 0x66, 0x19, 0xab, 0x98, 0x69,
+	0xec, 0xc1, 0x87, 0xb6, 0x72, 0xe5, 0x0b, 0xe4, 0x15, 0x4c, 0xbd, 0x5f, 0x4b, 0xd6, 0x0b, 0x99,
+	0xcc, 0x8f, 0x7a, 0x73, 0x74, 0xec, 0x8f, 0xb9, 0xf8, 0x90, 0x4e, 0x7c, 0xe3, 0x45, 0x41, 0x9e,
+	0xc0, 0xb8, 0xc0, 0xa2, 0x96, 0xcb, 0x1b, 0x6c, 0x3a, 0x85, 0xe3, 0x74, 0xd4, 0x25, 0x3e, 0x61,
+	0x13, 0xfd, 0xdd, 0x14, 0xe8, 0x6c, 0xb8, 0xb4, 0xb3, 0x1d, 0xc3, 0x43, 0xc6, 0x65, 0x6d, 0xac,
+	0xa6, 0x3e, 0x20, 0x57, 0x30, 0xfd, 0xaa, 0x28, 0xd7, 0x0c, 0xb9, 0xf9, 0x4c, 0x65, 0x38, 0x38,
+	0x1d, 0x9e, 0x4d, 0xe6, 0xf3, 0x2d, 0x1d, 0xff, 0xb1, 0xc5, 0x9b, 0xa0, 0x73, 0x6e, 0x54, 0x93,
+	0xde, 0xe1, 0x99, 0xbd, 0x85, 0xc3, 0xad, 0x16, 0x72, 0x00, 0xc3, 0x56, 0x76, 0xd0, 0xc9, 0x6e,
+	0x7f, 0x5b, 0x51, 0x6b, 0x5a, 0xd5, 0x6e, 0x91, 0x7d, 0xf0, 0x66, 0xf0, 0x3a, 0x88, 0xfe, 0x04,
+	0xb0, 0xef, 0x4f, 0x7f, 0x97, 0x9b, 0xd6, 0xe3, 0x10, 0x76, 0x15, 0xea, 0xba, 0x32, 0xee, 0x6a,
+	0xb8, 0xb0, 0x5d, 0x35, 0xae, 0x91, 0x1b, 0x6d, 0x89, 0x6c, 0x44, 0x9e, 0xc3, 0xc8, 0xdd, 0xbb,
+	0xce, 0xad, 0xc9, 0xfc, 0xc0, 0x8d, 0x96, 0xda, 0x7c, 0xea, 0x3b, 0xb6, 0x96, 0xf2, 0xe0, 0x7e,
+	0x4b, 0x59, 0x7c, 0x87, 0x48, 0xa8, 0x32, 0x5e, 0x35, 0x12, 0x55, 0x85, 0x45, 0x89, 0x2a, 0xfe,
+	0x41, 0x33, 0xc5, 0x72, 0x87, 0x6c, 0x5f, 0xc2, 0x62, 0xff, 0xd6, 0xc3, 0xfc, 0x86, 0x96, 0x78,
+	0xfd, 0xac, 0x64, 0x66, 0x55, 0x67, 0x71, 0x2e, 0x7e, 0x26, 0x1b, 0xd8, 0xa4, 0xc7, 0x26, 0x3d,
+	0x36, 0x69, 0xb1, 0x59, 0xff, 0xd2, 0x5e, 0xfe, 0x1b, 0x00, 0x6b, 0xf6, 0x4d, 0xa5, 0x87, 0x03,
+	0x00, 0x00,
 }
@@ -40,17 +40,21 @@ type ProposalResponse struct {
 	Payload []byte `protobuf:"bytes,5,opt,name=payload,proto3" json:"payload,omitempty"`
 	// The endorsement of the proposal, basically
 	// the endorser's signature over the payload
-	Endorsement          *Endorsement `protobuf:"bytes,6,opt,name=endorsement" json:"endorsement,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	Endorsement *Endorsement `protobuf:"bytes,6,opt,name=endorsement" json:"endorsement,omitempty"`
+	// The dissemination of the private data written by the proposal, which
+	// is not covered by the endorsement and is only informative, letting the
+	// client decide whether the private data is durable enough to proceed
+	PrivateDataDissemination *PrivateDataDissemination `protobuf:"bytes,7,opt,name=private_data_dissemination,json=privateDataDissemination" json:"private_data_dissemination,omitempty"`
+	XXX_NoUnkeyedLiteral     struct{}                  `json:"-"`
+	XXX_unrecognized         []byte                    `json:"-"`
+	XXX_sizecache            int32                     `json:"-"`
 }
 
 func (m *ProposalResponse) Reset()         { *m = ProposalResponse{} }
 func (m *ProposalResponse) String() string { return proto.CompactTextString(m) }
 func (*ProposalResponse) ProtoMessage()    {}
 func (*ProposalResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_proposal_response_22a755721b685f40, []int{0}
+	return fileDescriptor_proposal_response_4c02d74d8c4eb938, []int{0}
 }
 func (m *ProposalResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ProposalResponse.Unmarshal(m, b)
@@ -105,6 +109,177 @@ func (m *ProposalResponse) GetEndorsement() *Endorsement {
 	return nil
 }
 
+func (m *ProposalResponse) GetPrivateDataDissemination() *PrivateDataDissemination {
+	if m != nil {
+		return m.PrivateDataDissemination
+	}
+	return nil
+}
+
+// PrivateDataDissemination describes to which peers the endorser disseminated
+// the private data written by a proposal
+type PrivateDataDissemination struct {
+	Collections          []*CollectionDissemination `protobuf:"bytes,1,rep,name=collections" json:"collections,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
+}
+
+func (m *PrivateDataDissemination) Reset()         { *m = PrivateDataDissemination{} }
+func (m *PrivateDataDissemination) String() string { return proto.CompactTextString(m) }
+func (*PrivateDataDissemination) ProtoMessage()    {}
+func (*PrivateDataDissemination) Descriptor() ([]byte, []int) {
+	return fileDescriptor_proposal_response_4c02d74d8c4eb938, []int{1}
+}
+func (m *PrivateDataDissemination) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrivateDataDissemination.Unmarshal(m, b)
+}
+func (m *PrivateDataDissemination) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrivateDataDissemination.Marshal(b, m, deterministic)
+}
+func (dst *PrivateDataDissemination) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrivateDataDissemination.Merge(dst, src)
+}
+func (m *PrivateDataDissemination) XXX_Size() int {
+	return xxx_messageInfo_PrivateDataDissemination.Size(m)
+}
+func (m *PrivateDataDissemination) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrivateDataDissemination.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrivateDataDissemination proto.InternalMessageInfo
+
+func (m *PrivateDataDissemination) GetCollections() []*CollectionDissemination {
+	if m != nil {
+		return m.Collections
+	}
+	return nil
+}
+
+// CollectionDissemination describes to which peers the endorser disseminated
+// the private data of a collection
+type CollectionDissemination struct {
+	Namespace  string `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+	Collection string `protobuf:"bytes,2,opt,name=collection" json:"collection,omitempty"`
+	// The required_peer_count of the collection, the endorsement failing if
+	// fewer peers acknowledged the receipt of the private data
+	RequiredPeerCount int32 `protobuf:"varint,3,opt,name=required_peer_count,json=requiredPeerCount" json:"required_peer_count,omitempty"`
+	// The maximum_peer_count of the collection
+	MaximumPeerCount int32 `protobuf:"varint,4,opt,name=maximum_peer_count,json=maximumPeerCount" json:"maximum_peer_count,omitempty"`
+	// The peers which acknowledged the receipt of the private data by the
+	// time the endorser stopped waiting for acknowledgements
+	AcknowledgedBy       []*PrivateDataRecipient `protobuf:"bytes,5,rep,name=acknowledged_by,json=acknowledgedBy" json:"acknowledged_by,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *CollectionDissemination) Reset()         { *m = CollectionDissemination{} }
+func (m *CollectionDissemination) String() string { return proto.CompactTextString(m) }
+func (*CollectionDissemination) ProtoMessage()    {}
+func (*CollectionDissemination) Descriptor() ([]byte, []int) {
+	return fileDescriptor_proposal_response_4c02d74d8c4eb938, []int{2}
+}
+func (m *CollectionDissemination) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CollectionDissemination.Unmarshal(m, b)
+}
+func (m *CollectionDissemination) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CollectionDissemination.Marshal(b, m, deterministic)
+}
+func (dst *CollectionDissemination) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CollectionDissemination.Merge(dst, src)
+}
+func (m *CollectionDissemination) XXX_Size() int {
+	return xxx_messageInfo_CollectionDissemination.Size(m)
+}
+func (m *CollectionDissemination) XXX_DiscardUnknown() {
+	xxx_messageInfo_CollectionDissemination.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CollectionDissemination proto.InternalMessageInfo
+
+func (m *CollectionDissemination) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *CollectionDissemination) GetCollection() string {
+	if m != nil {
+		return m.Collection
+	}
+	return ""
+}
+
+func (m *CollectionDissemination) GetRequiredPeerCount() int32 {
+	if m != nil {
+		return m.RequiredPeerCount
+	}
+	return 0
+}
+
+func (m *CollectionDissemination) GetMaximumPeerCount() int32 {
+	if m != nil {
+		return m.MaximumPeerCount
+	}
+	return 0
+}
+
+func (m *CollectionDissemination) GetAcknowledgedBy() []*PrivateDataRecipient {
+	if m != nil {
+		return m.AcknowledgedBy
+	}
+	return nil
+}
+
+// PrivateDataRecipient is a peer which received private data
+type PrivateDataRecipient struct {
+	Endpoint             string   `protobuf:"bytes,1,opt,name=endpoint" json:"endpoint,omitempty"`
+	Mspid                string   `protobuf:"bytes,2,opt,name=mspid" json:"mspid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PrivateDataRecipient) Reset()         { *m = PrivateDataRecipient{} }
+func (m *PrivateDataRecipient) String() string { return proto.CompactTextString(m) }
+func (*PrivateDataRecipient) ProtoMessage()    {}
+func (*PrivateDataRecipient) Descriptor() ([]byte, []int) {
+	return fileDescriptor_proposal_response_4c02d74d8c4eb938, []int{3}
+}
+func (m *PrivateDataRecipient) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrivateDataRecipient.Unmarshal(m, b)
+}
+func (m *PrivateDataRecipient) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrivateDataRecipient.Marshal(b, m, deterministic)
+}
+func (dst *PrivateDataRecipient) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrivateDataRecipient.Merge(dst, src)
+}
+func (m *PrivateDataRecipient) XXX_Size() int {
+	return xxx_messageInfo_PrivateDataRecipient.Size(m)
+}
+func (m *PrivateDataRecipient) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrivateDataRecipient.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrivateDataRecipient proto.InternalMessageInfo
+
+func (m *PrivateDataRecipient) GetEndpoint() string {
+	if m != nil {
+		return m.Endpoint
+	}
+	return ""
+}
+
+func (m *PrivateDataRecipient) GetMspid() string {
+	if m != nil {
+		return m.Mspid
+	}
+	return ""
+}
+
 // A response with a representation similar to an HTTP response that can
 // be used within another message.
 type Response struct {
@@ -123,7 +298,7 @@ func (m *Response) Reset()         { *m = Response{} }
 func (m *Response) String() string { return proto.CompactTextString(m) }
 func (*Response) ProtoMessage()    {}
 func (*Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_proposal_response_22a755721b685f40, []int{1}
+	return fileDescriptor_proposal_response_4c02d74d8c4eb938, []int{4}
 }
 func (m *Response) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Response.Unmarshal(m, b)
@@ -201,7 +376,7 @@ func (m *ProposalResponsePayload) Reset()         { *m = ProposalResponsePayload
 func (m *ProposalResponsePayload) String() string { return proto.CompactTextString(m) }
 func (*ProposalResponsePayload) ProtoMessage()    {}
 func (*ProposalResponsePayload) Descriptor() ([]byte, []int) {
-	return fileDescriptor_proposal_response_22a755721b685f40, []int{2}
+	return fileDescriptor_proposal_response_4c02d74d8c4eb938, []int{5}
 }
 func (m *ProposalResponsePayload) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ProposalResponsePayload.Unmarshal(m, b)
@@ -249,17 +424,21 @@ type Endorsement struct {
 	Endorser []byte `protobuf:"bytes,1,opt,name=endorser,proto3" json:"endorser,omitempty"`
 	// Signature of the payload included in ProposalResponse concatenated with
 	// the endorser's certificate; ie, sign(ProposalResponse.payload + endorser)
-	Signature            []byte   `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Signature []byte `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	// Evidence that the chaincode producing the payload executed inside a
+	// trusted execution environment, set by the endorsers executing the
+	// chaincode inside an enclave
+	Attestation          *AttestationEvidence `protobuf:"bytes,3,opt,name=attestation" json:"attestation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
 func (m *Endorsement) Reset()         { *m = Endorsement{} }
 func (m *Endorsement) String() string { return proto.CompactTextString(m) }
 func (*Endorsement) ProtoMessage()    {}
 func (*Endorsement) Descriptor() ([]byte, []int) {
-	return fileDescriptor_proposal_response_22a755721b685f40, []int{3}
+	return fileDescriptor_proposal_response_4c02d74d8c4eb938, []int{6}
 }
 func (m *Endorsement) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Endorsement.Unmarshal(m, b)
@@ -293,40 +472,198 @@ func (m *Endorsement) GetSignature() []byte {
 	return nil
 }
 
+func (m *Endorsement) GetAttestation() *AttestationEvidence {
+	if m != nil {
+		return m.Attestation
+	}
+	return nil
+}
+
+// AttestationEvidence proves that a proposal response payload was produced by
+// a chaincode executing inside the enclave of a trusted execution environment.
+// The quote binds the public key of the enclave to the measurement of the
+// enclave, and the enclave signs the payload with the corresponding key
+type AttestationEvidence struct {
+	// Type of the trusted execution environment producing the quote (e.g. SGX)
+	Type string `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
+	// Quote of the enclave, whose report data starts with the SHA256 hash of
+	// the public key of the enclave
+	Quote []byte `protobuf:"bytes,2,opt,name=quote,proto3" json:"quote,omitempty"`
+	// DER encoded public key of the enclave
+	EnclavePublicKey []byte `protobuf:"bytes,3,opt,name=enclave_public_key,json=enclavePublicKey,proto3" json:"enclave_public_key,omitempty"`
+	// Signature of the enclave over the proposal response payload
+	Signature            []byte   `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AttestationEvidence) Reset()         { *m = AttestationEvidence{} }
+func (m *AttestationEvidence) String() string { return proto.CompactTextString(m) }
+func (*AttestationEvidence) ProtoMessage()    {}
+func (*AttestationEvidence) Descriptor() ([]byte, []int) {
+	return fileDescriptor_proposal_response_4c02d74d8c4eb938, []int{7}
+}
+func (m *AttestationEvidence) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AttestationEvidence.Unmarshal(m, b)
+}
+func (m *AttestationEvidence) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AttestationEvidence.Marshal(b, m, deterministic)
+}
+func (dst *AttestationEvidence) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AttestationEvidence.Merge(dst, src)
+}
+func (m *AttestationEvidence) XXX_Size() int {
+	return xxx_messageInfo_AttestationEvidence.Size(m)
+}
+func (m *AttestationEvidence) XXX_DiscardUnknown() {
+	xxx_messageInfo_AttestationEvidence.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AttestationEvidence proto.InternalMessageInfo
+
+func (m *AttestationEvidence) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *AttestationEvidence) GetQuote() []byte {
+	if m != nil {
+		return m.Quote
+	}
+	return nil
+}
+
+func (m *AttestationEvidence) GetEnclavePublicKey() []byte {
+	if m != nil {
+		return m.EnclavePublicKey
+	}
+	return nil
+}
+
+func (m *AttestationEvidence) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+// TEEValidationParameter is the validation parameter of the chaincodes validated
+// by the TEEValidation plugin, recorded in their chaincode definition. It carries
+// the endorsement policy of the chaincode along with the measurements of the
+// enclaves trusted to execute it
+type TEEValidationParameter struct {
+	// Endorsement policy of the chaincode, a marshaled SignaturePolicyEnvelope
+	EndorsementPolicy []byte `protobuf:"bytes,1,opt,name=endorsement_policy,json=endorsementPolicy,proto3" json:"endorsement_policy,omitempty"`
+	// Measurements of the enclaves trusted to execute the chaincode
+	TrustedMeasurements  [][]byte `protobuf:"bytes,2,rep,name=trusted_measurements,json=trustedMeasurements,proto3" json:"trusted_measurements,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TEEValidationParameter) Reset()         { *m = TEEValidationParameter{} }
+func (m *TEEValidationParameter) String() string { return proto.CompactTextString(m) }
+func (*TEEValidationParameter) ProtoMessage()    {}
+func (*TEEValidationParameter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_proposal_response_4c02d74d8c4eb938, []int{8}
+}
+func (m *TEEValidationParameter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TEEValidationParameter.Unmarshal(m, b)
+}
+func (m *TEEValidationParameter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TEEValidationParameter.Marshal(b, m, deterministic)
+}
+func (dst *TEEValidationParameter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TEEValidationParameter.Merge(dst, src)
+}
+func (m *TEEValidationParameter) XXX_Size() int {
+	return xxx_messageInfo_TEEValidationParameter.Size(m)
+}
+func (m *TEEValidationParameter) XXX_DiscardUnknown() {
+	xxx_messageInfo_TEEValidationParameter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TEEValidationParameter proto.InternalMessageInfo
+
+func (m *TEEValidationParameter) GetEndorsementPolicy() []byte {
+	if m != nil {
+		return m.EndorsementPolicy
+	}
+	return nil
+}
+
+func (m *TEEValidationParameter) GetTrustedMeasurements() [][]byte {
+	if m != nil {
+		return m.TrustedMeasurements
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*ProposalResponse)(nil), "protos.ProposalResponse")
+	proto.RegisterType((*PrivateDataDissemination)(nil), "protos.PrivateDataDissemination")
+	proto.RegisterType((*CollectionDissemination)(nil), "protos.CollectionDissemination")
+	proto.RegisterType((*PrivateDataRecipient)(nil), "protos.PrivateDataRecipient")
 	proto.RegisterType((*Response)(nil), "protos.Response")
 	proto.RegisterType((*ProposalResponsePayload)(nil), "protos.ProposalResponsePayload")
 	proto.RegisterType((*Endorsement)(nil), "protos.Endorsement")
+	proto.RegisterType((*AttestationEvidence)(nil), "protos.AttestationEvidence")
+	proto.RegisterType((*TEEValidationParameter)(nil), "protos.TEEValidationParameter")
 }
 
 func init() {
-	proto.RegisterFile("peer/proposal_response.proto", fileDescriptor_proposal_response_22a755721b685f40)
-}
-
-var fileDescriptor_proposal_response_22a755721b685f40 = []byte{
-	// 367 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0x51, 0x4b, 0xfb, 0x30,
-	0x14, 0xc5, 0xe9, 0xfe, 0xff, 0xcd, 0x2d, 0x9b, 0x30, 0x2a, 0x68, 0x19, 0x03, 0x47, 0x7d, 0x99,
-	0x20, 0x29, 0x28, 0x82, 0xcf, 0x03, 0xd1, 0xc7, 0x11, 0xc4, 0x07, 0x11, 0x24, 0xdd, 0xee, 0xd2,
-	0x62, 0xdb, 0x84, 0xdc, 0x54, 0xdc, 0x07, 0xf6, 0x7b, 0x48, 0xd3, 0xa6, 0xab, 0xe2, 0xd3, 0x38,
-	0x77, 0x27, 0xbf, 0x7b, 0xcf, 0xed, 0x25, 0x73, 0x05, 0xa0, 0x23, 0xa5, 0xa5, 0x92, 0xc8, 0xb3,
-	0x37, 0x0d, 0xa8, 0x64, 0x81, 0x40, 0x95, 0x96, 0x46, 0xfa, 0x03, 0xfb, 0x83, 0xb3, 0x73, 0x21,
-	0xa5, 0xc8, 0x20, 0xb2, 0x32, 0x2e, 0x77, 0x91, 0x49, 0x73, 0x40, 0xc3, 0x73, 0x55, 0x1b, 0xc3,
-	0x2f, 0x8f, 0x4c, 0xd7, 0x0d, 0x84, 0x35, 0x0c, 0x3f, 0x20, 0x47, 0x1f, 0xa0, 0x31, 0x95, 0x45,
-	0xe0, 0x2d, 0xbc, 0x65, 0x9f, 0x39, 0xe9, 0xdf, 0x91, 0x51, 0x4b, 0x08, 0x7a, 0x0b, 0x6f, 0x39,
-	0xbe, 0x9e, 0xd1, 0xba, 0x07, 0x75, 0x3d, 0xe8, 0x93, 0x73, 0xb0, 0x83, 0xd9, 0xbf, 0x22, 0x43,
-	0x37, 0x63, 0xf0, 0xdf, 0x3e, 0x9c, 0xd6, 0x2f, 0x90, 0xba, 0xbe, 0xac, 0x75, 0x54, 0x13, 0x28,
-	0xbe, 0xcf, 0x24, 0xdf, 0x06, 0xfd, 0x85, 0xb7, 0x9c, 0x30, 0x27, 0xfd, 0x5b, 0x32, 0x86, 0x62,
-	0x2b, 0x35, 0x42, 0x0e, 0x85, 0x09, 0x06, 0x16, 0x75, 0xe2, 0x50, 0xf7, 0x87, 0xbf, 0x58, 0xd7,
-	0x17, 0x3e, 0x93, 0x61, 0x1b, 0xef, 0x94, 0x0c, 0xd0, 0x70, 0x53, 0x62, 0x93, 0xae, 0x51, 0x55,
-	0xd3, 0x1c, 0x10, 0xb9, 0x00, 0x1b, 0x6d, 0xc4, 0x9c, 0xec, 0x8e, 0xf3, 0xef, 0xc7, 0x38, 0xe1,
-	0x2b, 0x39, 0xfb, 0xbd, 0xbe, 0x75, 0x33, 0xe9, 0x05, 0x39, 0x6e, 0x3f, 0x4f, 0xc2, 0x31, 0xb1,
-	0xdd, 0x26, 0x6c, 0xe2, 0x8a, 0x8f, 0x1c, 0x13, 0x7f, 0x4e, 0x46, 0xf0, 0x69, 0xa0, 0xb0, 0xcb,
-	0xee, 0x59, 0xc3, 0xa1, 0x10, 0x3e, 0x90, 0x71, 0x27, 0x91, 0x3f, 0x23, 0xc3, 0x26, 0x93, 0x6e,
-	0x60, 0xad, 0xae, 0x40, 0x98, 0x8a, 0x82, 0x9b, 0x52, 0x83, 0x03, 0xb5, 0x85, 0x55, 0x42, 0x42,
-	0xa9, 0x05, 0x4d, 0xf6, 0x0a, 0x74, 0x06, 0x5b, 0x01, 0x9a, 0xee, 0x78, 0xac, 0xd3, 0x8d, 0x5b,
-	0x5c, 0x75, 0x4d, 0xab, 0x3f, 0xa2, 0x6c, 0xde, 0xb9, 0x80, 0x97, 0x4b, 0x91, 0x9a, 0xa4, 0x8c,
-	0xe9, 0x46, 0xe6, 0x51, 0x87, 0x11, 0xd5, 0x8c, 0xfa, 0xba, 0x30, 0xaa, 0x18, 0x71, 0x7d, 0x79,
-	0x37, 0xdf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x0e, 0x52, 0x0b, 0x35, 0xa0, 0x02, 0x00, 0x00,
+	proto.RegisterFile("peer/proposal_response.proto", fileDescriptor_proposal_response_4c02d74d8c4eb938)
+}
+
+var fileDescriptor_proposal_response_4c02d74d8c4eb938 = []byte{
+	// 727 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x54, 0x5f, 0x6f, 0xfb, 0x34,
+	0x14, 0x55, 0xd6, 0x76, 0xbf, 0xd6, 0x2d, 0xd0, 0x9f, 0x3b, 0xfd, 0x16, 0x95, 0x89, 0x55, 0xe1,
+	0xa5, 0x48, 0x23, 0x15, 0x43, 0x48, 0xbc, 0xf0, 0xb0, 0x3f, 0x95, 0x26, 0x21, 0xa4, 0x2a, 0x9a,
+	0xf6, 0x80, 0x80, 0xc8, 0x4d, 0xee, 0x52, 0x6b, 0x49, 0xec, 0xd9, 0x4e, 0x59, 0xf8, 0x00, 0xbc,
+	0xf0, 0xb9, 0xf8, 0x56, 0x3c, 0x20, 0x3b, 0x76, 0x9b, 0x4d, 0xdd, 0x53, 0x7b, 0x7d, 0xce, 0x3d,
+	0xd7, 0xf7, 0xde, 0xe3, 0xa0, 0x33, 0x0e, 0x20, 0x16, 0x5c, 0x30, 0xce, 0x24, 0xc9, 0x63, 0x01,
+	0x92, 0xb3, 0x52, 0x42, 0xc8, 0x05, 0x53, 0x0c, 0x1f, 0x9b, 0x1f, 0x39, 0x3d, 0xcf, 0x18, 0xcb,
+	0x72, 0x58, 0x98, 0x70, 0x5d, 0x3d, 0x2e, 0x14, 0x2d, 0x40, 0x2a, 0x52, 0xf0, 0x86, 0x18, 0xfc,
+	0x7b, 0x84, 0xc6, 0x2b, 0x2b, 0x12, 0x59, 0x0d, 0xec, 0xa3, 0x0f, 0x5b, 0x10, 0x92, 0xb2, 0xd2,
+	0xf7, 0x66, 0xde, 0xbc, 0x17, 0xb9, 0x10, 0xff, 0x88, 0x06, 0x3b, 0x05, 0xff, 0x68, 0xe6, 0xcd,
+	0x87, 0x97, 0xd3, 0xb0, 0xa9, 0x11, 0xba, 0x1a, 0xe1, 0xbd, 0x63, 0x44, 0x7b, 0x32, 0xbe, 0x40,
+	0x7d, 0x77, 0x47, 0xbf, 0x6b, 0x12, 0xc7, 0x4d, 0x86, 0x0c, 0x5d, 0xdd, 0xa8, 0x2f, 0x5a, 0x37,
+	0xe0, 0xa4, 0xce, 0x19, 0x49, 0xfd, 0xde, 0xcc, 0x9b, 0x8f, 0x22, 0x17, 0xe2, 0x1f, 0xd0, 0x10,
+	0xca, 0x94, 0x09, 0x09, 0x05, 0x94, 0xca, 0x3f, 0x36, 0x52, 0x13, 0x27, 0xb5, 0xdc, 0x43, 0x51,
+	0x9b, 0x87, 0xff, 0x40, 0x53, 0x2e, 0xe8, 0x96, 0x28, 0x88, 0x53, 0xa2, 0x48, 0x9c, 0x52, 0x29,
+	0xa1, 0xa0, 0x25, 0x51, 0xba, 0xcb, 0x0f, 0x46, 0x65, 0xe6, 0x54, 0x56, 0x0d, 0xf3, 0x96, 0x28,
+	0x72, 0xdb, 0xe6, 0x45, 0x3e, 0x7f, 0x07, 0x09, 0x7e, 0x47, 0xfe, 0x7b, 0x59, 0xf8, 0x0a, 0x0d,
+	0x13, 0x96, 0xe7, 0x90, 0xe8, 0x48, 0xfa, 0xde, 0xac, 0x33, 0x1f, 0x5e, 0x9e, 0xbb, 0x62, 0x37,
+	0x3b, 0xe8, 0x75, 0xad, 0x76, 0x4e, 0xf0, 0x9f, 0x87, 0x4e, 0xdf, 0x21, 0xe2, 0x33, 0x34, 0x28,
+	0x49, 0x01, 0x92, 0x93, 0x04, 0xcc, 0xbe, 0x06, 0xd1, 0xfe, 0x00, 0x7f, 0x85, 0xd0, 0x5e, 0xc8,
+	0xac, 0x6c, 0x10, 0xb5, 0x4e, 0x70, 0x88, 0x26, 0x02, 0x9e, 0x2b, 0x2a, 0x20, 0x8d, 0xb5, 0xa5,
+	0xe2, 0x84, 0x55, 0xa5, 0xf2, 0x3b, 0x66, 0xef, 0x1f, 0x1d, 0xb4, 0x02, 0x10, 0x37, 0x1a, 0xc0,
+	0x17, 0x08, 0x17, 0xe4, 0x85, 0x16, 0x55, 0xd1, 0xa6, 0x77, 0x0d, 0x7d, 0x6c, 0x91, 0x3d, 0x7b,
+	0x89, 0xbe, 0x20, 0xc9, 0x53, 0xc9, 0xfe, 0xcc, 0x21, 0xcd, 0x20, 0x8d, 0xd7, 0xb5, 0xdf, 0x33,
+	0xed, 0x9f, 0x1d, 0x98, 0x75, 0x04, 0x09, 0xe5, 0x54, 0xaf, 0xee, 0xf3, 0x76, 0xd2, 0x75, 0x1d,
+	0xdc, 0xa1, 0x93, 0x43, 0x3c, 0x3c, 0x45, 0x7d, 0x28, 0x53, 0xce, 0x68, 0xa9, 0x6c, 0xe7, 0xbb,
+	0x18, 0x9f, 0xa0, 0x5e, 0x21, 0x39, 0x4d, 0x6d, 0xcf, 0x4d, 0x10, 0x3c, 0xa0, 0xfe, 0xce, 0xe6,
+	0x9f, 0xd0, 0xb1, 0x54, 0x44, 0x55, 0xd2, 0xba, 0xdc, 0x46, 0xda, 0x7c, 0x05, 0x48, 0x49, 0x32,
+	0xb0, 0xb9, 0x2e, 0x6c, 0xdb, 0xb2, 0xf3, 0xca, 0x96, 0xc1, 0x6f, 0xe8, 0xf4, 0xed, 0x33, 0x5a,
+	0x59, 0xc7, 0x7e, 0x8d, 0x3e, 0xdb, 0x3d, 0xd3, 0x0d, 0x91, 0x1b, 0x53, 0x6d, 0x14, 0x8d, 0xdc,
+	0xe1, 0x1d, 0x91, 0x1b, 0xbd, 0x44, 0x78, 0x51, 0x50, 0x4a, 0xb7, 0xa5, 0x51, 0xb4, 0x3f, 0x08,
+	0xfe, 0xf6, 0xd0, 0xb0, 0x65, 0x6d, 0xdb, 0xb7, 0x0e, 0x85, 0x55, 0xdb, 0xc5, 0x5a, 0x49, 0xd2,
+	0xac, 0x24, 0xaa, 0x12, 0xe0, 0x94, 0x76, 0x07, 0xf8, 0x27, 0x34, 0x24, 0x4a, 0x81, 0xee, 0x54,
+	0x57, 0xea, 0x18, 0xe3, 0x7f, 0xe9, 0x96, 0x71, 0xb5, 0x87, 0x96, 0x5b, 0x9a, 0x42, 0x99, 0x40,
+	0xd4, 0xe6, 0x07, 0xff, 0x78, 0x68, 0x72, 0x80, 0x84, 0x31, 0xea, 0xaa, 0x9a, 0x3b, 0xfb, 0x99,
+	0xff, 0x7a, 0x01, 0xcf, 0x15, 0x53, 0xee, 0x12, 0x4d, 0xa0, 0xfd, 0x03, 0x65, 0x92, 0x93, 0x2d,
+	0xc4, 0xbc, 0x5a, 0xe7, 0x34, 0x89, 0x9f, 0xa0, 0xb6, 0xd3, 0x1c, 0x5b, 0x64, 0x65, 0x80, 0x9f,
+	0xa1, 0x7e, 0xdd, 0x4c, 0xf7, 0x4d, 0x33, 0xc1, 0x5f, 0xe8, 0xd3, 0xfd, 0x72, 0xf9, 0x40, 0x72,
+	0x9a, 0x9a, 0xeb, 0xac, 0x88, 0x20, 0x05, 0x28, 0x10, 0xf8, 0x5b, 0x84, 0xed, 0x40, 0xf4, 0xbc,
+	0x62, 0xce, 0x72, 0x9a, 0xd4, 0x76, 0x54, 0x1f, 0x5b, 0xc8, 0xca, 0x00, 0xf8, 0x3b, 0x74, 0xa2,
+	0x44, 0x25, 0x15, 0xa4, 0x71, 0x01, 0x44, 0x56, 0xc2, 0x80, 0xd2, 0x3f, 0x9a, 0x75, 0xe6, 0xa3,
+	0x68, 0x62, 0xb1, 0x5f, 0x5a, 0xd0, 0xf5, 0x06, 0x05, 0x4c, 0x64, 0xe1, 0xa6, 0xe6, 0x20, 0x8c,
+	0x4f, 0x45, 0xf8, 0x48, 0xd6, 0x82, 0x26, 0x6e, 0x96, 0xfa, 0x75, 0x5c, 0x1f, 0x30, 0x45, 0xf2,
+	0x44, 0x32, 0xf8, 0xf5, 0x9b, 0x8c, 0xaa, 0x4d, 0xb5, 0x0e, 0x13, 0x56, 0x2c, 0x5a, 0x1a, 0x8b,
+	0x46, 0xa3, 0xf9, 0x5e, 0xcb, 0x85, 0xd6, 0x58, 0x37, 0xdf, 0xf2, 0xef, 0xff, 0x1f, 0x00, 0x7f,
+	0xc7, 0x24, 0x60, 0xf2, 0x05, 0x00, 0x00,
 }
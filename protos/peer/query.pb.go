@@ -6,6 +6,7 @@ package peer // import "github.com/hyperledger/fabric/protos/peer"
 import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
+import common "github.com/hyperledger/fabric/protos/common"
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
@@ -18,6 +19,32 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type PrivateDataEntry_Status int32
+
+const (
+	PrivateDataEntry_PRESENT PrivateDataEntry_Status = 0
+	PrivateDataEntry_MISSING PrivateDataEntry_Status = 1
+	PrivateDataEntry_PURGED  PrivateDataEntry_Status = 2
+)
+
+var PrivateDataEntry_Status_name = map[int32]string{
+	0: "PRESENT",
+	1: "MISSING",
+	2: "PURGED",
+}
+var PrivateDataEntry_Status_value = map[string]int32{
+	"PRESENT": 0,
+	"MISSING": 1,
+	"PURGED":  2,
+}
+
+func (x PrivateDataEntry_Status) String() string {
+	return proto.EnumName(PrivateDataEntry_Status_name, int32(x))
+}
+func (PrivateDataEntry_Status) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_query_bd74fef3a4d562af, []int{9, 0}
+}
+
 // ChaincodeQueryResponse returns information about each chaincode that pertains
 // to a query in lscc.go, such as GetChaincodes (returns all chaincodes
 // instantiated on a channel), and GetInstalledChaincodes (returns all chaincodes
@@ -33,7 +60,7 @@ func (m *ChaincodeQueryResponse) Reset()         { *m = ChaincodeQueryResponse{}
 func (m *ChaincodeQueryResponse) String() string { return proto.CompactTextString(m) }
 func (*ChaincodeQueryResponse) ProtoMessage()    {}
 func (*ChaincodeQueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_query_37e5d407118e73f5, []int{0}
+	return fileDescriptor_query_bd74fef3a4d562af, []int{0}
 }
 func (m *ChaincodeQueryResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChaincodeQueryResponse.Unmarshal(m, b)
@@ -91,7 +118,7 @@ func (m *ChaincodeInfo) Reset()         { *m = ChaincodeInfo{} }
 func (m *ChaincodeInfo) String() string { return proto.CompactTextString(m) }
 func (*ChaincodeInfo) ProtoMessage()    {}
 func (*ChaincodeInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_query_37e5d407118e73f5, []int{1}
+	return fileDescriptor_query_bd74fef3a4d562af, []int{1}
 }
 func (m *ChaincodeInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChaincodeInfo.Unmarshal(m, b)
@@ -174,7 +201,7 @@ func (m *ChannelQueryResponse) Reset()         { *m = ChannelQueryResponse{} }
 func (m *ChannelQueryResponse) String() string { return proto.CompactTextString(m) }
 func (*ChannelQueryResponse) ProtoMessage()    {}
 func (*ChannelQueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_query_37e5d407118e73f5, []int{2}
+	return fileDescriptor_query_bd74fef3a4d562af, []int{2}
 }
 func (m *ChannelQueryResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChannelQueryResponse.Unmarshal(m, b)
@@ -213,7 +240,7 @@ func (m *ChannelInfo) Reset()         { *m = ChannelInfo{} }
 func (m *ChannelInfo) String() string { return proto.CompactTextString(m) }
 func (*ChannelInfo) ProtoMessage()    {}
 func (*ChannelInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_query_37e5d407118e73f5, []int{3}
+	return fileDescriptor_query_bd74fef3a4d562af, []int{3}
 }
 func (m *ChannelInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChannelInfo.Unmarshal(m, b)
@@ -240,34 +267,405 @@ func (m *ChannelInfo) GetChannelId() string {
 	return ""
 }
 
+// LedgerBlocks returns the blocks of a ledger matching a query in qscc.go,
+// such as GetBlockRange, GetBlockByTxIDRange and GetBlocksByTimeRange
+type LedgerBlocks struct {
+	Blocks               []*common.Block `protobuf:"bytes,1,rep,name=blocks" json:"blocks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *LedgerBlocks) Reset()         { *m = LedgerBlocks{} }
+func (m *LedgerBlocks) String() string { return proto.CompactTextString(m) }
+func (*LedgerBlocks) ProtoMessage()    {}
+func (*LedgerBlocks) Descriptor() ([]byte, []int) {
+	return fileDescriptor_query_bd74fef3a4d562af, []int{4}
+}
+func (m *LedgerBlocks) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LedgerBlocks.Unmarshal(m, b)
+}
+func (m *LedgerBlocks) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LedgerBlocks.Marshal(b, m, deterministic)
+}
+func (dst *LedgerBlocks) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LedgerBlocks.Merge(dst, src)
+}
+func (m *LedgerBlocks) XXX_Size() int {
+	return xxx_messageInfo_LedgerBlocks.Size(m)
+}
+func (m *LedgerBlocks) XXX_DiscardUnknown() {
+	xxx_messageInfo_LedgerBlocks.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LedgerBlocks proto.InternalMessageInfo
+
+func (m *LedgerBlocks) GetBlocks() []*common.Block {
+	if m != nil {
+		return m.Blocks
+	}
+	return nil
+}
+
+// LedgerTransactions returns the transactions of a ledger matching a query in
+// qscc.go, such as GetTransactionsByCreator
+type LedgerTransactions struct {
+	Transactions         []*LedgerTransaction `protobuf:"bytes,1,rep,name=transactions" json:"transactions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *LedgerTransactions) Reset()         { *m = LedgerTransactions{} }
+func (m *LedgerTransactions) String() string { return proto.CompactTextString(m) }
+func (*LedgerTransactions) ProtoMessage()    {}
+func (*LedgerTransactions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_query_bd74fef3a4d562af, []int{5}
+}
+func (m *LedgerTransactions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LedgerTransactions.Unmarshal(m, b)
+}
+func (m *LedgerTransactions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LedgerTransactions.Marshal(b, m, deterministic)
+}
+func (dst *LedgerTransactions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LedgerTransactions.Merge(dst, src)
+}
+func (m *LedgerTransactions) XXX_Size() int {
+	return xxx_messageInfo_LedgerTransactions.Size(m)
+}
+func (m *LedgerTransactions) XXX_DiscardUnknown() {
+	xxx_messageInfo_LedgerTransactions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LedgerTransactions proto.InternalMessageInfo
+
+func (m *LedgerTransactions) GetTransactions() []*LedgerTransaction {
+	if m != nil {
+		return m.Transactions
+	}
+	return nil
+}
+
+// LedgerTransaction contains a committed transaction along with its position
+// in the ledger
+type LedgerTransaction struct {
+	BlockNumber          uint64                `protobuf:"varint,1,opt,name=block_number,json=blockNumber" json:"block_number,omitempty"`
+	TxNumber             uint64                `protobuf:"varint,2,opt,name=tx_number,json=txNumber" json:"tx_number,omitempty"`
+	Transaction          *ProcessedTransaction `protobuf:"bytes,3,opt,name=transaction" json:"transaction,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *LedgerTransaction) Reset()         { *m = LedgerTransaction{} }
+func (m *LedgerTransaction) String() string { return proto.CompactTextString(m) }
+func (*LedgerTransaction) ProtoMessage()    {}
+func (*LedgerTransaction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_query_bd74fef3a4d562af, []int{6}
+}
+func (m *LedgerTransaction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LedgerTransaction.Unmarshal(m, b)
+}
+func (m *LedgerTransaction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LedgerTransaction.Marshal(b, m, deterministic)
+}
+func (dst *LedgerTransaction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LedgerTransaction.Merge(dst, src)
+}
+func (m *LedgerTransaction) XXX_Size() int {
+	return xxx_messageInfo_LedgerTransaction.Size(m)
+}
+func (m *LedgerTransaction) XXX_DiscardUnknown() {
+	xxx_messageInfo_LedgerTransaction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LedgerTransaction proto.InternalMessageInfo
+
+func (m *LedgerTransaction) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func (m *LedgerTransaction) GetTxNumber() uint64 {
+	if m != nil {
+		return m.TxNumber
+	}
+	return 0
+}
+
+func (m *LedgerTransaction) GetTransaction() *ProcessedTransaction {
+	if m != nil {
+		return m.Transaction
+	}
+	return nil
+}
+
+// TransactionValidationCode returns the validation code of a committed
+// transaction, as queried by GetTxValidationCode in qscc.go
+type TransactionValidationCode struct {
+	TxId                 string           `protobuf:"bytes,1,opt,name=tx_id,json=txId" json:"tx_id,omitempty"`
+	ValidationCode       TxValidationCode `protobuf:"varint,2,opt,name=validation_code,json=validationCode,enum=protos.TxValidationCode" json:"validation_code,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *TransactionValidationCode) Reset()         { *m = TransactionValidationCode{} }
+func (m *TransactionValidationCode) String() string { return proto.CompactTextString(m) }
+func (*TransactionValidationCode) ProtoMessage()    {}
+func (*TransactionValidationCode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_query_bd74fef3a4d562af, []int{7}
+}
+func (m *TransactionValidationCode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransactionValidationCode.Unmarshal(m, b)
+}
+func (m *TransactionValidationCode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransactionValidationCode.Marshal(b, m, deterministic)
+}
+func (dst *TransactionValidationCode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransactionValidationCode.Merge(dst, src)
+}
+func (m *TransactionValidationCode) XXX_Size() int {
+	return xxx_messageInfo_TransactionValidationCode.Size(m)
+}
+func (m *TransactionValidationCode) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransactionValidationCode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransactionValidationCode proto.InternalMessageInfo
+
+func (m *TransactionValidationCode) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+func (m *TransactionValidationCode) GetValidationCode() TxValidationCode {
+	if m != nil {
+		return m.ValidationCode
+	}
+	return TxValidationCode_VALID
+}
+
+// PrivateDataAvailability returns, for each valid transaction of a range of
+// blocks which wrote to a private data collection, whether the peer stores the
+// private data of the transaction, as queried by GetPrivateDataAvailability in
+// qscc.go
+type PrivateDataAvailability struct {
+	Entries              []*PrivateDataEntry `protobuf:"bytes,1,rep,name=entries" json:"entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *PrivateDataAvailability) Reset()         { *m = PrivateDataAvailability{} }
+func (m *PrivateDataAvailability) String() string { return proto.CompactTextString(m) }
+func (*PrivateDataAvailability) ProtoMessage()    {}
+func (*PrivateDataAvailability) Descriptor() ([]byte, []int) {
+	return fileDescriptor_query_bd74fef3a4d562af, []int{8}
+}
+func (m *PrivateDataAvailability) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrivateDataAvailability.Unmarshal(m, b)
+}
+func (m *PrivateDataAvailability) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrivateDataAvailability.Marshal(b, m, deterministic)
+}
+func (dst *PrivateDataAvailability) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrivateDataAvailability.Merge(dst, src)
+}
+func (m *PrivateDataAvailability) XXX_Size() int {
+	return xxx_messageInfo_PrivateDataAvailability.Size(m)
+}
+func (m *PrivateDataAvailability) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrivateDataAvailability.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrivateDataAvailability proto.InternalMessageInfo
+
+func (m *PrivateDataAvailability) GetEntries() []*PrivateDataEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+// PrivateDataEntry tells whether the private data written by a transaction to
+// a collection is present, missing, or purged after the block to live of the
+// collection
+type PrivateDataEntry struct {
+	BlockNumber          uint64                  `protobuf:"varint,1,opt,name=block_number,json=blockNumber" json:"block_number,omitempty"`
+	TxNumber             uint64                  `protobuf:"varint,2,opt,name=tx_number,json=txNumber" json:"tx_number,omitempty"`
+	TxId                 string                  `protobuf:"bytes,3,opt,name=tx_id,json=txId" json:"tx_id,omitempty"`
+	Status               PrivateDataEntry_Status `protobuf:"varint,4,opt,name=status,enum=protos.PrivateDataEntry_Status" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *PrivateDataEntry) Reset()         { *m = PrivateDataEntry{} }
+func (m *PrivateDataEntry) String() string { return proto.CompactTextString(m) }
+func (*PrivateDataEntry) ProtoMessage()    {}
+func (*PrivateDataEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_query_bd74fef3a4d562af, []int{9}
+}
+func (m *PrivateDataEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrivateDataEntry.Unmarshal(m, b)
+}
+func (m *PrivateDataEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrivateDataEntry.Marshal(b, m, deterministic)
+}
+func (dst *PrivateDataEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrivateDataEntry.Merge(dst, src)
+}
+func (m *PrivateDataEntry) XXX_Size() int {
+	return xxx_messageInfo_PrivateDataEntry.Size(m)
+}
+func (m *PrivateDataEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrivateDataEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrivateDataEntry proto.InternalMessageInfo
+
+func (m *PrivateDataEntry) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func (m *PrivateDataEntry) GetTxNumber() uint64 {
+	if m != nil {
+		return m.TxNumber
+	}
+	return 0
+}
+
+func (m *PrivateDataEntry) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+func (m *PrivateDataEntry) GetStatus() PrivateDataEntry_Status {
+	if m != nil {
+		return m.Status
+	}
+	return PrivateDataEntry_PRESENT
+}
+
+// PolicyEvaluation returns whether a set of identities would satisfy an
+// endorsement policy, as evaluated by EvaluatePolicy and EvaluateKeyPolicy in
+// qscc.go. The explanation details the evaluation of the policy when it is not
+// satisfied.
+type PolicyEvaluation struct {
+	Satisfied            bool     `protobuf:"varint,1,opt,name=satisfied" json:"satisfied,omitempty"`
+	Explanation          string   `protobuf:"bytes,2,opt,name=explanation" json:"explanation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PolicyEvaluation) Reset()         { *m = PolicyEvaluation{} }
+func (m *PolicyEvaluation) String() string { return proto.CompactTextString(m) }
+func (*PolicyEvaluation) ProtoMessage()    {}
+func (*PolicyEvaluation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_query_bd74fef3a4d562af, []int{10}
+}
+func (m *PolicyEvaluation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PolicyEvaluation.Unmarshal(m, b)
+}
+func (m *PolicyEvaluation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PolicyEvaluation.Marshal(b, m, deterministic)
+}
+func (dst *PolicyEvaluation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PolicyEvaluation.Merge(dst, src)
+}
+func (m *PolicyEvaluation) XXX_Size() int {
+	return xxx_messageInfo_PolicyEvaluation.Size(m)
+}
+func (m *PolicyEvaluation) XXX_DiscardUnknown() {
+	xxx_messageInfo_PolicyEvaluation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PolicyEvaluation proto.InternalMessageInfo
+
+func (m *PolicyEvaluation) GetSatisfied() bool {
+	if m != nil {
+		return m.Satisfied
+	}
+	return false
+}
+
+func (m *PolicyEvaluation) GetExplanation() string {
+	if m != nil {
+		return m.Explanation
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*ChaincodeQueryResponse)(nil), "protos.ChaincodeQueryResponse")
 	proto.RegisterType((*ChaincodeInfo)(nil), "protos.ChaincodeInfo")
 	proto.RegisterType((*ChannelQueryResponse)(nil), "protos.ChannelQueryResponse")
 	proto.RegisterType((*ChannelInfo)(nil), "protos.ChannelInfo")
+	proto.RegisterType((*LedgerBlocks)(nil), "protos.LedgerBlocks")
+	proto.RegisterType((*LedgerTransactions)(nil), "protos.LedgerTransactions")
+	proto.RegisterType((*LedgerTransaction)(nil), "protos.LedgerTransaction")
+	proto.RegisterType((*TransactionValidationCode)(nil), "protos.TransactionValidationCode")
+	proto.RegisterType((*PrivateDataAvailability)(nil), "protos.PrivateDataAvailability")
+	proto.RegisterType((*PrivateDataEntry)(nil), "protos.PrivateDataEntry")
+	proto.RegisterType((*PolicyEvaluation)(nil), "protos.PolicyEvaluation")
+	proto.RegisterEnum("protos.PrivateDataEntry_Status", PrivateDataEntry_Status_name, PrivateDataEntry_Status_value)
 }
 
-func init() { proto.RegisterFile("peer/query.proto", fileDescriptor_query_37e5d407118e73f5) }
-
-var fileDescriptor_query_37e5d407118e73f5 = []byte{
-	// 296 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x91, 0xdf, 0x4a, 0xc3, 0x30,
-	0x14, 0xc6, 0xe9, 0xfe, 0xba, 0x33, 0x15, 0x89, 0x53, 0x72, 0x23, 0x8c, 0x5e, 0x4d, 0x90, 0x16,
-	0x14, 0x5f, 0xc0, 0x5d, 0xc8, 0xae, 0x86, 0xbd, 0xf4, 0x46, 0xba, 0xe4, 0x6c, 0x0d, 0x6c, 0x49,
-	0x4c, 0xba, 0xc1, 0x9e, 0xc6, 0x57, 0x95, 0x93, 0xac, 0xa3, 0xbb, 0xea, 0x39, 0xbf, 0xef, 0x17,
-	0xca, 0x97, 0xc0, 0x9d, 0x45, 0x74, 0xf9, 0xef, 0x1e, 0xdd, 0x31, 0xb3, 0xce, 0xd4, 0x86, 0x0d,
-	0xc2, 0xc7, 0xa7, 0x4b, 0x78, 0x9c, 0x57, 0xa5, 0xd2, 0xc2, 0x48, 0xfc, 0xa2, 0xbc, 0x40, 0x6f,
-	0x8d, 0xf6, 0xc8, 0xde, 0x01, 0x44, 0x93, 0x78, 0x9e, 0x4c, 0xbb, 0xb3, 0xf1, 0xeb, 0x43, 0x3c,
-	0xed, 0xb3, 0xf3, 0x99, 0x85, 0x5e, 0x9b, 0xa2, 0x25, 0xa6, 0x7f, 0x09, 0xdc, 0x5c, 0xa4, 0x8c,
-	0x41, 0x4f, 0x97, 0x3b, 0xe4, 0xc9, 0x34, 0x99, 0x8d, 0x8a, 0x30, 0x33, 0x0e, 0xc3, 0x03, 0x3a,
-	0xaf, 0x8c, 0xe6, 0x9d, 0x80, 0x9b, 0x95, 0x6c, 0x5b, 0xd6, 0x15, 0xef, 0x46, 0x9b, 0x66, 0x36,
-	0x81, 0xbe, 0xd2, 0x76, 0x5f, 0xf3, 0x5e, 0x80, 0x71, 0x21, 0x13, 0xbd, 0x10, 0xbc, 0x1f, 0x4d,
-	0x9a, 0x89, 0x1d, 0x88, 0x0d, 0x22, 0xa3, 0x99, 0xdd, 0x42, 0x47, 0x49, 0x3e, 0x9c, 0x26, 0xb3,
-	0xeb, 0xa2, 0xa3, 0x64, 0xfa, 0x09, 0x93, 0x79, 0x55, 0x6a, 0x8d, 0xdb, 0xcb, 0xc2, 0x39, 0x5c,
-	0x89, 0xc8, 0x9b, 0xba, 0xf7, 0xad, 0xba, 0xc4, 0x43, 0xd9, 0xb3, 0x94, 0xbe, 0xc0, 0xb8, 0x15,
-	0xb0, 0xa7, 0x70, 0x61, 0xb4, 0xfe, 0x28, 0x79, 0x6a, 0x3b, 0x3a, 0x91, 0x85, 0xfc, 0x58, 0x42,
-	0x6a, 0xdc, 0x26, 0xab, 0x8e, 0x16, 0xdd, 0x16, 0xe5, 0x06, 0x5d, 0xb6, 0x2e, 0x57, 0x4e, 0x89,
-	0xe6, 0x27, 0xf4, 0x46, 0xdf, 0xcf, 0x1b, 0x55, 0x57, 0xfb, 0x55, 0x26, 0xcc, 0x2e, 0x6f, 0xa9,
-	0x79, 0x54, 0xf3, 0xa8, 0xe6, 0xa4, 0xae, 0xe2, 0x13, 0xbe, 0xfd, 0x07, 0x00, 0x00, 0xff, 0xff,
-	0x79, 0x94, 0xb3, 0xbd, 0xdd, 0x01, 0x00, 0x00,
+func init() { proto.RegisterFile("peer/query.proto", fileDescriptor_query_bd74fef3a4d562af) }
+
+var fileDescriptor_query_bd74fef3a4d562af = []byte{
+	// 643 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0xdd, 0x6e, 0xd3, 0x30,
+	0x14, 0x26, 0x5d, 0xd7, 0xae, 0xa7, 0x5d, 0x29, 0xde, 0x18, 0xd9, 0x18, 0xa2, 0x44, 0x42, 0x1a,
+	0x12, 0x4a, 0xa4, 0xa2, 0x89, 0x2b, 0x90, 0xf6, 0x53, 0x4d, 0x95, 0xd8, 0x56, 0xdc, 0xc1, 0x05,
+	0x37, 0x93, 0x9b, 0x78, 0xab, 0x45, 0xea, 0x04, 0xdb, 0x8d, 0xda, 0xe7, 0xe0, 0x01, 0x78, 0x25,
+	0x1e, 0x09, 0xd9, 0x4e, 0xda, 0x94, 0x89, 0x2b, 0xae, 0x72, 0xce, 0xf7, 0x7d, 0xc7, 0x3e, 0x9f,
+	0x8f, 0x1d, 0xe8, 0xa4, 0x94, 0x8a, 0xe0, 0xc7, 0x8c, 0x8a, 0x85, 0x9f, 0x8a, 0x44, 0x25, 0xa8,
+	0x66, 0x3e, 0xf2, 0x60, 0x27, 0x4c, 0xa6, 0xd3, 0x84, 0x07, 0xf6, 0x63, 0xc9, 0x83, 0x3d, 0x23,
+	0x57, 0x82, 0x70, 0x49, 0x42, 0xc5, 0x0a, 0xdc, 0xbb, 0x86, 0xbd, 0xb3, 0x09, 0x61, 0x3c, 0x4c,
+	0x22, 0xfa, 0x59, 0x2f, 0x86, 0xa9, 0x4c, 0x13, 0x2e, 0x29, 0x3a, 0x06, 0x08, 0x0b, 0x46, 0xba,
+	0x4e, 0x77, 0xe3, 0xa8, 0xd9, 0x7b, 0x6a, 0xab, 0xa4, 0xbf, 0xac, 0x19, 0xf0, 0xbb, 0x04, 0x97,
+	0x84, 0xde, 0x2f, 0x07, 0xb6, 0xd7, 0x58, 0x84, 0xa0, 0xca, 0xc9, 0x94, 0xba, 0x4e, 0xd7, 0x39,
+	0x6a, 0x60, 0x13, 0x23, 0x17, 0xea, 0x19, 0x15, 0x92, 0x25, 0xdc, 0xad, 0x18, 0xb8, 0x48, 0xb5,
+	0x3a, 0x25, 0x6a, 0xe2, 0x6e, 0x58, 0xb5, 0x8e, 0xd1, 0x2e, 0x6c, 0x32, 0x9e, 0xce, 0x94, 0x5b,
+	0x35, 0xa0, 0x4d, 0xb4, 0x92, 0xca, 0x30, 0x74, 0x37, 0xad, 0x52, 0xc7, 0x1a, 0xcb, 0x34, 0x56,
+	0xb3, 0x98, 0x8e, 0x51, 0x1b, 0x2a, 0x2c, 0x72, 0xeb, 0x5d, 0xe7, 0xa8, 0x85, 0x2b, 0x2c, 0xf2,
+	0x2e, 0x60, 0xf7, 0x6c, 0x42, 0x38, 0xa7, 0xf1, 0xba, 0xe1, 0x00, 0xb6, 0x42, 0x8b, 0x17, 0x76,
+	0x77, 0x4a, 0x76, 0x35, 0x6e, 0xcc, 0x2e, 0x45, 0xde, 0x5b, 0x68, 0x96, 0x08, 0xf4, 0xc2, 0x1c,
+	0x98, 0x4e, 0x6f, 0x59, 0x94, 0xbb, 0x6d, 0xe4, 0xc8, 0x20, 0xf2, 0x8e, 0xa1, 0xf5, 0x89, 0x46,
+	0xf7, 0x54, 0x9c, 0xc6, 0x49, 0xf8, 0x5d, 0xa2, 0xd7, 0x50, 0x1b, 0x9b, 0x28, 0xdf, 0x6c, 0xdb,
+	0xcf, 0x07, 0x66, 0x78, 0x9c, 0x93, 0xde, 0x08, 0x90, 0x2d, 0xbb, 0x59, 0xcd, 0x4e, 0xa2, 0x0f,
+	0xd0, 0x2a, 0xcd, 0xb2, 0x58, 0x62, 0xbf, 0xe8, 0xf7, 0x41, 0x05, 0x5e, 0x93, 0x7b, 0x3f, 0x1d,
+	0x78, 0xf2, 0x40, 0x83, 0x5e, 0x41, 0xcb, 0x6c, 0x7a, 0xcb, 0x67, 0xd3, 0x31, 0x15, 0xc6, 0x42,
+	0x15, 0x37, 0x0d, 0x76, 0x65, 0x20, 0xf4, 0x1c, 0x1a, 0x6a, 0x5e, 0xf0, 0x15, 0xc3, 0x6f, 0xa9,
+	0x79, 0x4e, 0x7e, 0x84, 0x66, 0x69, 0x17, 0x33, 0xc1, 0x66, 0xef, 0xb0, 0xe8, 0x69, 0x28, 0x92,
+	0x90, 0x4a, 0x49, 0xa3, 0x72, 0x5b, 0xe5, 0x02, 0x4f, 0xc2, 0x7e, 0x89, 0xfb, 0x4a, 0x62, 0x16,
+	0x11, 0x1d, 0x9d, 0x25, 0x11, 0x45, 0x3b, 0xb0, 0xa9, 0xe6, 0xab, 0x83, 0xad, 0xaa, 0xf9, 0x20,
+	0x42, 0x27, 0xf0, 0x38, 0x5b, 0xca, 0x6e, 0xf5, 0x8d, 0x33, 0x4d, 0xb5, 0x7b, 0x6e, 0xb1, 0xeb,
+	0xcd, 0x7c, 0x7d, 0x1d, 0xdc, 0xce, 0xd6, 0x72, 0xef, 0x12, 0x9e, 0x0d, 0x05, 0xcb, 0x88, 0xa2,
+	0xe7, 0x44, 0x91, 0x93, 0x8c, 0xb0, 0x98, 0x8c, 0x59, 0xcc, 0xd4, 0x02, 0xf5, 0xa0, 0x4e, 0xb9,
+	0x12, 0x6c, 0x79, 0xfd, 0xdd, 0x95, 0x97, 0x65, 0x45, 0x9f, 0x2b, 0xb1, 0xc0, 0x85, 0xd0, 0xfb,
+	0xed, 0x40, 0xe7, 0x6f, 0xf6, 0xbf, 0x0f, 0x76, 0xe9, 0x7d, 0xa3, 0xe4, 0xfd, 0x3d, 0xd4, 0xa4,
+	0x22, 0x6a, 0x26, 0xcd, 0xab, 0x68, 0xf7, 0x5e, 0xfe, 0xab, 0x39, 0x7f, 0x64, 0x64, 0x38, 0x97,
+	0x7b, 0x3e, 0xd4, 0x2c, 0x82, 0x9a, 0x50, 0x1f, 0xe2, 0xfe, 0xa8, 0x7f, 0x75, 0xd3, 0x79, 0xa4,
+	0x93, 0xcb, 0xc1, 0x68, 0x34, 0xb8, 0xba, 0xe8, 0x38, 0x08, 0xa0, 0x36, 0xfc, 0x82, 0x2f, 0xfa,
+	0xe7, 0x9d, 0x8a, 0x87, 0xa1, 0x33, 0x4c, 0x62, 0x16, 0x2e, 0xfa, 0x19, 0x89, 0x67, 0xe6, 0xe4,
+	0xd0, 0x21, 0x34, 0x24, 0x51, 0x4c, 0xde, 0x31, 0x6a, 0x27, 0xb2, 0x85, 0x57, 0x00, 0xea, 0x42,
+	0x93, 0xce, 0xd3, 0x98, 0x70, 0x23, 0xce, 0x5f, 0x78, 0x19, 0x3a, 0xbd, 0x06, 0x2f, 0x11, 0xf7,
+	0xfe, 0x64, 0x91, 0x52, 0x11, 0x9b, 0x8b, 0xe8, 0xdf, 0x91, 0xb1, 0x60, 0x61, 0x61, 0x42, 0xff,
+	0xae, 0xbe, 0xbd, 0xb9, 0x67, 0x6a, 0x32, 0x1b, 0xeb, 0x87, 0x11, 0x94, 0xa4, 0x81, 0x95, 0x06,
+	0x56, 0x1a, 0x68, 0xe9, 0xd8, 0xfe, 0xfc, 0xde, 0xfd, 0x19, 0x00, 0xed, 0x6f, 0xa9, 0xbd, 0x17,
+	0x05, 0x00, 0x00,
 }
@@ -48,6 +48,8 @@ const (
 	TxValidationCode_BAD_RWSET                    TxValidationCode = 22
 	TxValidationCode_ILLEGAL_WRITESET             TxValidationCode = 23
 	TxValidationCode_INVALID_WRITESET             TxValidationCode = 24
+	TxValidationCode_DUPLICATE_DEDUP_KEY          TxValidationCode = 25
+	TxValidationCode_EXPIRED_IDENTITY             TxValidationCode = 26
 	TxValidationCode_NOT_VALIDATED                TxValidationCode = 254
 	TxValidationCode_INVALID_OTHER_REASON         TxValidationCode = 255
 )
@@ -78,6 +80,8 @@ var TxValidationCode_name = map[int32]string{
 	22:  "BAD_RWSET",
 	23:  "ILLEGAL_WRITESET",
 	24:  "INVALID_WRITESET",
+	25:  "DUPLICATE_DEDUP_KEY",
+	26:  "EXPIRED_IDENTITY",
 	254: "NOT_VALIDATED",
 	255: "INVALID_OTHER_REASON",
 }
@@ -107,6 +111,8 @@ var TxValidationCode_value = map[string]int32{
 	"BAD_RWSET":                    22,
 	"ILLEGAL_WRITESET":             23,
 	"INVALID_WRITESET":             24,
+	"DUPLICATE_DEDUP_KEY":          25,
+	"EXPIRED_IDENTITY":             26,
 	"NOT_VALIDATED":                254,
 	"INVALID_OTHER_REASON":         255,
 }
@@ -115,7 +121,7 @@ func (x TxValidationCode) String() string {
 	return proto.EnumName(TxValidationCode_name, int32(x))
 }
 func (TxValidationCode) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_transaction_4fbd1a0e1a50cfab, []int{0}
+	return fileDescriptor_transaction_9429a2b961138753, []int{0}
 }
 
 // Reserved entries in the key-level metadata map
@@ -136,7 +142,7 @@ func (x MetaDataKeys) String() string {
 	return proto.EnumName(MetaDataKeys_name, int32(x))
 }
 func (MetaDataKeys) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_transaction_4fbd1a0e1a50cfab, []int{1}
+	return fileDescriptor_transaction_9429a2b961138753, []int{1}
 }
 
 // This message is necessary to facilitate the verification of the signature
@@ -159,7 +165,7 @@ func (m *SignedTransaction) Reset()         { *m = SignedTransaction{} }
 func (m *SignedTransaction) String() string { return proto.CompactTextString(m) }
 func (*SignedTransaction) ProtoMessage()    {}
 func (*SignedTransaction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_transaction_4fbd1a0e1a50cfab, []int{0}
+	return fileDescriptor_transaction_9429a2b961138753, []int{0}
 }
 func (m *SignedTransaction) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SignedTransaction.Unmarshal(m, b)
@@ -213,7 +219,7 @@ func (m *ProcessedTransaction) Reset()         { *m = ProcessedTransaction{} }
 func (m *ProcessedTransaction) String() string { return proto.CompactTextString(m) }
 func (*ProcessedTransaction) ProtoMessage()    {}
 func (*ProcessedTransaction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_transaction_4fbd1a0e1a50cfab, []int{1}
+	return fileDescriptor_transaction_9429a2b961138753, []int{1}
 }
 func (m *ProcessedTransaction) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ProcessedTransaction.Unmarshal(m, b)
@@ -272,7 +278,7 @@ func (m *Transaction) Reset()         { *m = Transaction{} }
 func (m *Transaction) String() string { return proto.CompactTextString(m) }
 func (*Transaction) ProtoMessage()    {}
 func (*Transaction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_transaction_4fbd1a0e1a50cfab, []int{2}
+	return fileDescriptor_transaction_9429a2b961138753, []int{2}
 }
 func (m *Transaction) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Transaction.Unmarshal(m, b)
@@ -316,7 +322,7 @@ func (m *TransactionAction) Reset()         { *m = TransactionAction{} }
 func (m *TransactionAction) String() string { return proto.CompactTextString(m) }
 func (*TransactionAction) ProtoMessage()    {}
 func (*TransactionAction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_transaction_4fbd1a0e1a50cfab, []int{3}
+	return fileDescriptor_transaction_9429a2b961138753, []int{3}
 }
 func (m *TransactionAction) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TransactionAction.Unmarshal(m, b)
@@ -375,7 +381,7 @@ func (m *ChaincodeActionPayload) Reset()         { *m = ChaincodeActionPayload{}
 func (m *ChaincodeActionPayload) String() string { return proto.CompactTextString(m) }
 func (*ChaincodeActionPayload) ProtoMessage()    {}
 func (*ChaincodeActionPayload) Descriptor() ([]byte, []int) {
-	return fileDescriptor_transaction_4fbd1a0e1a50cfab, []int{4}
+	return fileDescriptor_transaction_9429a2b961138753, []int{4}
 }
 func (m *ChaincodeActionPayload) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChaincodeActionPayload.Unmarshal(m, b)
@@ -428,7 +434,7 @@ func (m *ChaincodeEndorsedAction) Reset()         { *m = ChaincodeEndorsedAction
 func (m *ChaincodeEndorsedAction) String() string { return proto.CompactTextString(m) }
 func (*ChaincodeEndorsedAction) ProtoMessage()    {}
 func (*ChaincodeEndorsedAction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_transaction_4fbd1a0e1a50cfab, []int{5}
+	return fileDescriptor_transaction_9429a2b961138753, []int{5}
 }
 func (m *ChaincodeEndorsedAction) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChaincodeEndorsedAction.Unmarshal(m, b)
@@ -473,63 +479,67 @@ func init() {
 	proto.RegisterEnum("protos.MetaDataKeys", MetaDataKeys_name, MetaDataKeys_value)
 }
 
-func init() { proto.RegisterFile("peer/transaction.proto", fileDescriptor_transaction_4fbd1a0e1a50cfab) }
-
-var fileDescriptor_transaction_4fbd1a0e1a50cfab = []byte{
-	// 877 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x55, 0x41, 0x6f, 0xe2, 0x46,
-	0x14, 0x5e, 0xb2, 0x4d, 0xd2, 0x0c, 0x24, 0x19, 0x06, 0x42, 0x08, 0x8a, 0xba, 0x2b, 0x0e, 0x55,
-	0xba, 0x95, 0x40, 0xca, 0x1e, 0x2a, 0x55, 0xbd, 0x0c, 0xf6, 0x24, 0x58, 0x6b, 0x66, 0xac, 0xf1,
-	0x40, 0x48, 0x0f, 0x1d, 0x19, 0x98, 0x25, 0xa8, 0x60, 0x23, 0xdb, 0x59, 0x35, 0xd7, 0xfe, 0x80,
-	0xf6, 0xd2, 0xdf, 0xdb, 0x56, 0xe3, 0xb1, 0x81, 0x24, 0xed, 0x05, 0x33, 0xdf, 0xfb, 0xde, 0x7b,
-	0xdf, 0xf7, 0x9e, 0x35, 0x06, 0x8d, 0xb5, 0x52, 0x71, 0x37, 0x8d, 0x83, 0x30, 0x09, 0xa6, 0xe9,
-	0x22, 0x0a, 0x3b, 0xeb, 0x38, 0x4a, 0x23, 0x74, 0x90, 0x3d, 0x92, 0xd6, 0xbb, 0x79, 0x14, 0xcd,
-	0x97, 0xaa, 0x9b, 0x1d, 0x27, 0x8f, 0x9f, 0xbb, 0xe9, 0x62, 0xa5, 0x92, 0x34, 0x58, 0xad, 0x0d,
-	0xb1, 0x75, 0x99, 0x15, 0x58, 0xc7, 0xd1, 0x3a, 0x4a, 0x82, 0xa5, 0x8c, 0x55, 0xb2, 0x8e, 0xc2,
-	0x44, 0xe5, 0xd1, 0xda, 0x34, 0x5a, 0xad, 0xa2, 0xb0, 0x6b, 0x1e, 0x06, 0x6c, 0xff, 0x02, 0xaa,
-	0xfe, 0x62, 0x1e, 0xaa, 0x99, 0xd8, 0xb6, 0x45, 0xdf, 0x83, 0xea, 0x8e, 0x0a, 0x39, 0x79, 0x4a,
-	0x55, 0xd2, 0x2c, 0xbd, 0x2f, 0x5d, 0x55, 0x38, 0xdc, 0x09, 0xf4, 0x34, 0x8e, 0x2e, 0xc1, 0x51,
-	0xb2, 0x98, 0x87, 0x41, 0xfa, 0x18, 0xab, 0xe6, 0x5e, 0x46, 0xda, 0x02, 0xed, 0xdf, 0x4b, 0xa0,
-	0xee, 0xc5, 0xd1, 0x54, 0x25, 0xc9, 0xf3, 0x1e, 0x3d, 0x50, 0xdb, 0x29, 0x45, 0xc2, 0x2f, 0x6a,
-	0x19, 0xad, 0x55, 0xd6, 0xa5, 0x7c, 0x0d, 0x3b, 0xb9, 0xc8, 0x02, 0xe7, 0xff, 0x45, 0x46, 0xdf,
-	0x82, 0x93, 0x2f, 0xc1, 0x72, 0x31, 0x0b, 0x34, 0x6a, 0x45, 0x33, 0xd3, 0x7f, 0x9f, 0xbf, 0x40,
-	0xdb, 0x3d, 0x50, 0xde, 0x6d, 0xfd, 0x11, 0x1c, 0x9a, 0x7f, 0xda, 0xd4, 0xdb, 0xab, 0xf2, 0xf5,
-	0x85, 0x19, 0x46, 0xd2, 0xd9, 0x61, 0xe1, 0xec, 0x97, 0x17, 0xcc, 0x36, 0x01, 0xd5, 0x57, 0x51,
-	0xd4, 0x00, 0x07, 0x0f, 0x2a, 0x98, 0xa9, 0x38, 0x9f, 0x4e, 0x7e, 0x42, 0x4d, 0x70, 0xb8, 0x0e,
-	0x9e, 0x96, 0x51, 0x30, 0xcb, 0x27, 0x52, 0x1c, 0xdb, 0x7f, 0x96, 0x40, 0xc3, 0x7a, 0x08, 0x16,
-	0xe1, 0x34, 0x9a, 0x29, 0x53, 0xc5, 0x33, 0x21, 0xf4, 0x13, 0x68, 0x4d, 0x8b, 0x88, 0xdc, 0x2c,
-	0xb1, 0xa8, 0x63, 0x1a, 0x34, 0x37, 0x0c, 0x2f, 0x27, 0x14, 0xd9, 0x3f, 0x80, 0x03, 0x23, 0x2d,
-	0xeb, 0x58, 0xbe, 0x7e, 0x57, 0x78, 0xda, 0x74, 0x23, 0xe1, 0x2c, 0x8a, 0x13, 0x35, 0xcb, 0x9d,
-	0xe5, 0xf4, 0xf6, 0x1f, 0x25, 0x70, 0xfe, 0x3f, 0x1c, 0xf4, 0x23, 0xb8, 0x78, 0xf5, 0x36, 0xbd,
-	0x50, 0x74, 0x5e, 0x10, 0x78, 0x1e, 0xdf, 0x0a, 0xaa, 0x28, 0x53, 0x6d, 0xa5, 0xc2, 0x34, 0x69,
-	0xee, 0x65, 0xa3, 0xae, 0x15, 0xb2, 0xc8, 0x36, 0xc6, 0x9f, 0x11, 0x3f, 0xfc, 0xb5, 0x0f, 0xa0,
-	0xf8, 0x6d, 0xf4, 0x6c, 0x85, 0xe8, 0x08, 0xec, 0x8f, 0xb0, 0xeb, 0xd8, 0xf0, 0x0d, 0x82, 0xa0,
-	0x42, 0x1d, 0x57, 0x12, 0x3a, 0x22, 0x2e, 0xf3, 0x08, 0x2c, 0xa1, 0x53, 0x50, 0xee, 0x61, 0x5b,
-	0x7a, 0xf8, 0xde, 0x65, 0xd8, 0x86, 0x7b, 0xe8, 0x0c, 0x54, 0x35, 0x60, 0xb1, 0xc1, 0x80, 0x51,
-	0xd9, 0x27, 0xd8, 0x26, 0x1c, 0xbe, 0x45, 0x17, 0xe0, 0x2c, 0x83, 0x39, 0xc1, 0x82, 0x71, 0xe9,
-	0x3b, 0xb7, 0x14, 0x8b, 0x21, 0x27, 0xf0, 0x2b, 0xf4, 0x1e, 0x5c, 0x3a, 0x34, 0xeb, 0x20, 0x09,
-	0xb5, 0x19, 0xf7, 0x09, 0x97, 0x82, 0x63, 0xea, 0x63, 0x4b, 0x38, 0x8c, 0xc2, 0x7d, 0xf4, 0x0d,
-	0x68, 0x15, 0x0c, 0x8b, 0xd1, 0x1b, 0xe7, 0xf6, 0x59, 0xfc, 0x00, 0xb5, 0x40, 0x63, 0x48, 0xfd,
-	0xa1, 0xe7, 0x31, 0x2e, 0x88, 0x2d, 0xc5, 0x78, 0xa3, 0xe7, 0xb0, 0xd0, 0xe3, 0x71, 0xe6, 0x31,
-	0x1f, 0xbb, 0x52, 0x8c, 0x1d, 0x1b, 0x7e, 0x8d, 0x10, 0x38, 0xb1, 0x87, 0x9e, 0xeb, 0x58, 0x58,
-	0x10, 0x83, 0x1d, 0xe9, 0x36, 0xb9, 0x80, 0x01, 0xa1, 0x42, 0x7a, 0xcc, 0x75, 0xac, 0x7b, 0x79,
-	0x83, 0x1d, 0x57, 0x0b, 0x05, 0xa8, 0x01, 0xd0, 0x60, 0x64, 0x59, 0x92, 0x13, 0x6c, 0x84, 0xb8,
-	0x8e, 0x25, 0x60, 0x59, 0x7b, 0xf3, 0xfa, 0x98, 0x0a, 0x36, 0x78, 0x11, 0xaa, 0xa0, 0x1a, 0x38,
-	0x1d, 0xd2, 0x4f, 0x94, 0xdd, 0x51, 0xad, 0x4a, 0xdc, 0x7b, 0x04, 0x1e, 0x6b, 0xb9, 0x02, 0xf3,
-	0x5b, 0x22, 0xa4, 0xd5, 0xc7, 0x0e, 0x95, 0x94, 0x09, 0x79, 0xc3, 0x86, 0xd4, 0x86, 0x27, 0xa8,
-	0x0e, 0xe0, 0x00, 0x73, 0xbf, 0x9f, 0x29, 0x95, 0x84, 0x73, 0xc6, 0xe1, 0x69, 0x31, 0x77, 0x31,
-	0xce, 0x2d, 0x43, 0x6d, 0x8b, 0x8c, 0x3d, 0x87, 0x13, 0xdb, 0x14, 0xb1, 0x98, 0x4d, 0x60, 0x55,
-	0x5b, 0xd8, 0x1c, 0xe5, 0x88, 0x70, 0xdf, 0x61, 0x74, 0xab, 0x07, 0xa1, 0x26, 0xa8, 0xeb, 0x69,
-	0x98, 0xb5, 0x48, 0x32, 0x16, 0x84, 0x6a, 0x0a, 0xac, 0x69, 0x73, 0xd9, 0x82, 0xfa, 0x98, 0x52,
-	0xe2, 0x16, 0x8b, 0xab, 0x17, 0x19, 0x9c, 0xf8, 0x1e, 0xa3, 0x3e, 0xd9, 0x4c, 0xf6, 0x0c, 0x1d,
-	0x83, 0xa3, 0x2c, 0x72, 0xe7, 0x13, 0x01, 0x1b, 0x5a, 0xb9, 0xe3, 0xba, 0xe4, 0x16, 0xbb, 0xf2,
-	0x8e, 0x3b, 0x82, 0x68, 0xf4, 0x3c, 0x43, 0xf3, 0xd5, 0x6d, 0xd0, 0x26, 0x42, 0xe0, 0x58, 0x9b,
-	0xce, 0x70, 0x2c, 0x88, 0x0d, 0xff, 0x2e, 0xa1, 0x0b, 0x50, 0x2f, 0x98, 0x4c, 0xf4, 0x09, 0xd7,
-	0xb3, 0xf4, 0x19, 0x85, 0xff, 0x94, 0x3e, 0x5c, 0x81, 0xca, 0x40, 0xa5, 0x81, 0x1d, 0xa4, 0xc1,
-	0x27, 0xf5, 0x94, 0x68, 0x4d, 0x79, 0xaa, 0xb6, 0xe7, 0x61, 0x8e, 0x07, 0x44, 0x10, 0x0e, 0xdf,
-	0xf4, 0xa6, 0xa0, 0x1d, 0xc5, 0xf3, 0xce, 0xc3, 0xd3, 0x5a, 0xc5, 0x4b, 0x35, 0x9b, 0xab, 0xb8,
-	0xf3, 0x39, 0x98, 0xc4, 0x8b, 0x69, 0xf1, 0xee, 0xeb, 0x6b, 0xba, 0x87, 0x76, 0xae, 0x13, 0x2f,
-	0x98, 0xfe, 0x1a, 0xcc, 0xd5, 0xcf, 0xdf, 0xcd, 0x17, 0xe9, 0xc3, 0xe3, 0x44, 0xdf, 0x7e, 0xdd,
-	0x9d, 0xf4, 0xae, 0x49, 0x37, 0x17, 0x7f, 0xd2, 0xd5, 0xe9, 0x13, 0xf3, 0x51, 0xf8, 0xf8, 0x6f,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0xba, 0xd3, 0xb2, 0x32, 0x35, 0x06, 0x00, 0x00,
+func init() {
+	proto.RegisterFile("peer/transaction.proto", fileDescriptor_transaction_9429a2b961138753)
+}
+
+var fileDescriptor_transaction_9429a2b961138753 = []byte{
+	// 900 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x55, 0x5d, 0x6f, 0xe2, 0x46,
+	0x14, 0x5d, 0xb2, 0x4d, 0xd2, 0x0c, 0xf9, 0x98, 0x0c, 0x09, 0x21, 0x28, 0xea, 0xae, 0x78, 0xa8,
+	0xd2, 0xad, 0x14, 0xa4, 0xec, 0x43, 0xa5, 0xaa, 0x2f, 0x83, 0x7d, 0x13, 0xac, 0x98, 0x19, 0x6b,
+	0x3c, 0x24, 0xd0, 0x87, 0x8e, 0x0c, 0xcc, 0x12, 0x54, 0xb0, 0x91, 0xed, 0xac, 0x9a, 0xd7, 0xfe,
+	0x80, 0xf6, 0x47, 0xf5, 0x7f, 0xb5, 0xd5, 0xf8, 0x03, 0x48, 0xb6, 0x7d, 0xc1, 0xcc, 0xb9, 0xe7,
+	0xde, 0x7b, 0xee, 0xb9, 0x30, 0x46, 0xf5, 0xa5, 0xd6, 0x71, 0x3b, 0x8d, 0x83, 0x30, 0x09, 0xc6,
+	0xe9, 0x2c, 0x0a, 0xaf, 0x96, 0x71, 0x94, 0x46, 0x64, 0x27, 0x7b, 0x24, 0xcd, 0x77, 0xd3, 0x28,
+	0x9a, 0xce, 0x75, 0x3b, 0x3b, 0x8e, 0x9e, 0x3e, 0xb5, 0xd3, 0xd9, 0x42, 0x27, 0x69, 0xb0, 0x58,
+	0xe6, 0xc4, 0xe6, 0x45, 0x56, 0x60, 0x19, 0x47, 0xcb, 0x28, 0x09, 0xe6, 0x2a, 0xd6, 0xc9, 0x32,
+	0x0a, 0x13, 0x5d, 0x44, 0x6b, 0xe3, 0x68, 0xb1, 0x88, 0xc2, 0x76, 0xfe, 0xc8, 0xc1, 0xd6, 0x2f,
+	0xe8, 0xd8, 0x9f, 0x4d, 0x43, 0x3d, 0x91, 0xeb, 0xb6, 0xe4, 0x7b, 0x74, 0xbc, 0xa1, 0x42, 0x8d,
+	0x9e, 0x53, 0x9d, 0x34, 0x2a, 0xef, 0x2b, 0x97, 0xfb, 0x02, 0x6f, 0x04, 0x3a, 0x06, 0x27, 0x17,
+	0x68, 0x2f, 0x99, 0x4d, 0xc3, 0x20, 0x7d, 0x8a, 0x75, 0x63, 0x2b, 0x23, 0xad, 0x81, 0xd6, 0xef,
+	0x15, 0x74, 0xe2, 0xc5, 0xd1, 0x58, 0x27, 0xc9, 0xcb, 0x1e, 0x1d, 0x54, 0xdb, 0x28, 0x05, 0xe1,
+	0x67, 0x3d, 0x8f, 0x96, 0x3a, 0xeb, 0x52, 0xbd, 0xc6, 0x57, 0x85, 0xc8, 0x12, 0x17, 0xff, 0x45,
+	0x26, 0xdf, 0xa2, 0xc3, 0xcf, 0xc1, 0x7c, 0x36, 0x09, 0x0c, 0x6a, 0x45, 0x93, 0xbc, 0xff, 0xb6,
+	0x78, 0x85, 0xb6, 0x3a, 0xa8, 0xba, 0xd9, 0xfa, 0x23, 0xda, 0xcd, 0xbf, 0x99, 0xa1, 0xde, 0x5e,
+	0x56, 0xaf, 0xcf, 0x73, 0x33, 0x92, 0xab, 0x0d, 0x16, 0xcd, 0x3e, 0x45, 0xc9, 0x6c, 0x01, 0x3a,
+	0xfe, 0x22, 0x4a, 0xea, 0x68, 0xe7, 0x51, 0x07, 0x13, 0x1d, 0x17, 0xee, 0x14, 0x27, 0xd2, 0x40,
+	0xbb, 0xcb, 0xe0, 0x79, 0x1e, 0x05, 0x93, 0xc2, 0x91, 0xf2, 0xd8, 0xfa, 0xb3, 0x82, 0xea, 0xd6,
+	0x63, 0x30, 0x0b, 0xc7, 0xd1, 0x44, 0xe7, 0x55, 0xbc, 0x3c, 0x44, 0x7e, 0x42, 0xcd, 0x71, 0x19,
+	0x51, 0xab, 0x25, 0x96, 0x75, 0xf2, 0x06, 0x8d, 0x15, 0xc3, 0x2b, 0x08, 0x65, 0xf6, 0x0f, 0x68,
+	0x27, 0x97, 0x96, 0x75, 0xac, 0x5e, 0xbf, 0x2b, 0x67, 0x5a, 0x75, 0x83, 0x70, 0x12, 0xc5, 0x89,
+	0x9e, 0x14, 0x93, 0x15, 0xf4, 0xd6, 0x1f, 0x15, 0x74, 0xf6, 0x3f, 0x1c, 0xf2, 0x23, 0x3a, 0xff,
+	0xe2, 0xd7, 0xf4, 0x4a, 0xd1, 0x59, 0x49, 0x10, 0x45, 0x7c, 0x2d, 0x68, 0x5f, 0xe7, 0xd5, 0x16,
+	0x3a, 0x4c, 0x93, 0xc6, 0x56, 0x66, 0x75, 0xad, 0x94, 0x05, 0xeb, 0x98, 0x78, 0x41, 0xfc, 0xf0,
+	0xd7, 0x36, 0xc2, 0xf2, 0xb7, 0xfb, 0x17, 0x2b, 0x24, 0x7b, 0x68, 0xfb, 0x9e, 0xba, 0x8e, 0x8d,
+	0xdf, 0x10, 0x8c, 0xf6, 0x99, 0xe3, 0x2a, 0x60, 0xf7, 0xe0, 0x72, 0x0f, 0x70, 0x85, 0x1c, 0xa1,
+	0x6a, 0x87, 0xda, 0xca, 0xa3, 0x43, 0x97, 0x53, 0x1b, 0x6f, 0x91, 0x53, 0x74, 0x6c, 0x00, 0x8b,
+	0xf7, 0x7a, 0x9c, 0xa9, 0x2e, 0x50, 0x1b, 0x04, 0x7e, 0x4b, 0xce, 0xd1, 0x69, 0x06, 0x0b, 0xa0,
+	0x92, 0x0b, 0xe5, 0x3b, 0xb7, 0x8c, 0xca, 0xbe, 0x00, 0xfc, 0x15, 0x79, 0x8f, 0x2e, 0x1c, 0x96,
+	0x75, 0x50, 0xc0, 0x6c, 0x2e, 0x7c, 0x10, 0x4a, 0x0a, 0xca, 0x7c, 0x6a, 0x49, 0x87, 0x33, 0xbc,
+	0x4d, 0xbe, 0x41, 0xcd, 0x92, 0x61, 0x71, 0x76, 0xe3, 0xdc, 0xbe, 0x88, 0xef, 0x90, 0x26, 0xaa,
+	0xf7, 0x99, 0xdf, 0xf7, 0x3c, 0x2e, 0x24, 0xd8, 0x4a, 0x0e, 0x56, 0x7a, 0x76, 0x4b, 0x3d, 0x9e,
+	0xe0, 0x1e, 0xf7, 0xa9, 0xab, 0xe4, 0xc0, 0xb1, 0xf1, 0xd7, 0x84, 0xa0, 0x43, 0xbb, 0xef, 0xb9,
+	0x8e, 0x45, 0x25, 0xe4, 0xd8, 0x9e, 0x69, 0x53, 0x08, 0xe8, 0x01, 0x93, 0xca, 0xe3, 0xae, 0x63,
+	0x0d, 0xd5, 0x0d, 0x75, 0x5c, 0x23, 0x14, 0x91, 0x3a, 0x22, 0xbd, 0x7b, 0xcb, 0x52, 0x02, 0x68,
+	0x2e, 0xc4, 0x75, 0x2c, 0x89, 0xab, 0x66, 0x36, 0xaf, 0x4b, 0x99, 0xe4, 0xbd, 0x57, 0xa1, 0x7d,
+	0x52, 0x43, 0x47, 0x7d, 0x76, 0xc7, 0xf8, 0x03, 0x33, 0xaa, 0xe4, 0xd0, 0x03, 0x7c, 0x60, 0xe4,
+	0x4a, 0x2a, 0x6e, 0x41, 0x2a, 0xab, 0x4b, 0x1d, 0xa6, 0x18, 0x97, 0xea, 0x86, 0xf7, 0x99, 0x8d,
+	0x0f, 0xc9, 0x09, 0xc2, 0x3d, 0x2a, 0xfc, 0x6e, 0xa6, 0x54, 0x81, 0x10, 0x5c, 0xe0, 0xa3, 0xd2,
+	0x77, 0x39, 0x28, 0x46, 0xc6, 0x66, 0x2c, 0x18, 0x78, 0x8e, 0x00, 0x3b, 0x2f, 0x62, 0x71, 0x1b,
+	0xf0, 0xb1, 0x19, 0x61, 0x75, 0x54, 0xf7, 0x20, 0x7c, 0x87, 0xb3, 0xb5, 0x1e, 0x42, 0x1a, 0xe8,
+	0xc4, 0xb8, 0x91, 0xaf, 0x45, 0xc1, 0x40, 0x02, 0x33, 0x14, 0x5c, 0x33, 0xc3, 0x65, 0x0b, 0xea,
+	0x52, 0xc6, 0xc0, 0x2d, 0x17, 0x77, 0x52, 0x66, 0x08, 0xf0, 0x3d, 0xce, 0x7c, 0x58, 0x39, 0x7b,
+	0x4a, 0x0e, 0xd0, 0x5e, 0x16, 0x79, 0xf0, 0x41, 0xe2, 0xba, 0x51, 0xee, 0xb8, 0x2e, 0xdc, 0x52,
+	0x57, 0x3d, 0x08, 0x47, 0x82, 0x41, 0xcf, 0x32, 0xb4, 0x58, 0xdd, 0x0a, 0x6d, 0x90, 0x33, 0x54,
+	0x5b, 0xbb, 0x6f, 0x83, 0xdd, 0xf7, 0xd4, 0x1d, 0x0c, 0xf1, 0xb9, 0xa1, 0x97, 0x63, 0x39, 0x36,
+	0x30, 0xe9, 0xc8, 0x21, 0x6e, 0x12, 0x82, 0x0e, 0x8c, 0x47, 0x59, 0x19, 0x2a, 0xc1, 0xc6, 0x7f,
+	0x57, 0xc8, 0x39, 0x3a, 0x29, 0x0b, 0x73, 0xd9, 0x05, 0x61, 0xac, 0xf7, 0x39, 0xc3, 0xff, 0x54,
+	0x3e, 0x5c, 0xa2, 0xfd, 0x9e, 0x4e, 0x03, 0x3b, 0x48, 0x83, 0x3b, 0xfd, 0x9c, 0x98, 0x11, 0x8a,
+	0x54, 0xe3, 0x86, 0x47, 0x05, 0xed, 0x81, 0x04, 0x81, 0xdf, 0x74, 0xc6, 0xa8, 0x15, 0xc5, 0xd3,
+	0xab, 0xc7, 0xe7, 0xa5, 0x8e, 0xe7, 0x7a, 0x32, 0xd5, 0xf1, 0xd5, 0xa7, 0x60, 0x14, 0xcf, 0xc6,
+	0xe5, 0x5f, 0xc5, 0xdc, 0xea, 0x1d, 0xb2, 0x71, 0xfb, 0x78, 0xc1, 0xf8, 0xd7, 0x60, 0xaa, 0x7f,
+	0xfe, 0x6e, 0x3a, 0x4b, 0x1f, 0x9f, 0x46, 0xe6, 0xb2, 0x6c, 0x6f, 0xa4, 0xb7, 0xf3, 0xf4, 0xfc,
+	0x3d, 0x91, 0xb4, 0x4d, 0xfa, 0x28, 0x7f, 0x87, 0x7c, 0xfc, 0x77, 0x00, 0x70, 0x1a, 0x81, 0x3c,
+	0x64, 0x06, 0x00, 0x00,
 }
@@ -15,7 +15,7 @@ import (
 // GetChainIDFromBlockBytes returns chain ID given byte array which represents
 // the block
 func GetChainIDFromBlockBytes(bytes []byte) (string, error) {
-	block, err := GetBlockFromBlockBytes(bytes)
+	block, err := GetBlockFromBlockBytesNoCopy(bytes)
 	if err != nil {
 		return "", err
 	}
@@ -94,6 +94,27 @@ func GetLastConfigIndexFromBlockOrPanic(block *cb.Block) uint64 {
 	return index
 }
 
+// GetCheckpointFromBlock retrieves the checkpoint encoded in the block
+// metadata, or nil if the block does not carry one
+func GetCheckpointFromBlock(block *cb.Block) (*cb.Checkpoint, error) {
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(cb.BlockMetadataIndex_CHECKPOINT) {
+		return nil, nil
+	}
+	md, err := GetMetadataFromBlock(block, cb.BlockMetadataIndex_CHECKPOINT)
+	if err != nil {
+		return nil, err
+	}
+	if len(md.Value) == 0 {
+		return nil, nil
+	}
+	cp := &cb.Checkpoint{}
+	err = proto.Unmarshal(md.Value, cp)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling Checkpoint")
+	}
+	return cp, nil
+}
+
 // GetBlockFromBlockBytes marshals the bytes into Block
 func GetBlockFromBlockBytes(blockBytes []byte) (*cb.Block, error) {
 	block := &cb.Block{}
@@ -104,6 +125,137 @@ func GetBlockFromBlockBytes(blockBytes []byte) (*cb.Block, error) {
 	return block, nil
 }
 
+// GetBlockFromBlockBytesNoCopy unmarshals the bytes into Block without copying
+// its transactions: the data entries of the block refer to the given bytes,
+// which must not be modified while the block is in use. The header and the
+// metadata of the block, which are small, are copied
+func GetBlockFromBlockBytesNoCopy(blockBytes []byte) (*cb.Block, error) {
+	block := &cb.Block{}
+	err := forEachBytesField(blockBytes, func(fieldNum uint64, value []byte) error {
+		switch fieldNum {
+		case blockHeaderField:
+			if block.Header == nil {
+				block.Header = &cb.BlockHeader{}
+			}
+			return proto.UnmarshalMerge(value, block.Header)
+		case blockDataField:
+			if block.Data == nil {
+				block.Data = &cb.BlockData{}
+			}
+			return forEachBytesField(value, func(fieldNum uint64, value []byte) error {
+				if fieldNum == blockDataDataField {
+					block.Data.Data = append(block.Data.Data, value)
+				}
+				return nil
+			})
+		case blockMetadataField:
+			if block.Metadata == nil {
+				block.Metadata = &cb.BlockMetadata{}
+			}
+			return proto.UnmarshalMerge(value, block.Metadata)
+		}
+		return nil
+	})
+	if err != nil {
+		return block, errors.Wrap(err, "error unmarshaling block")
+	}
+	return block, nil
+}
+
+// UnmarshalEnvelopeNoCopy unmarshals the bytes into Envelope without copying
+// them: the payload and the signature of the envelope refer to the given
+// bytes, which must not be modified while the envelope is in use
+func UnmarshalEnvelopeNoCopy(envBytes []byte) (*cb.Envelope, error) {
+	env := &cb.Envelope{}
+	err := forEachBytesField(envBytes, func(fieldNum uint64, value []byte) error {
+		switch fieldNum {
+		case envelopePayloadField:
+			env.Payload = value
+		case envelopeSignatureField:
+			env.Signature = value
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling Envelope")
+	}
+	return env, nil
+}
+
+// UnmarshalPayloadHeader unmarshals the header of the marshaled payload,
+// without unmarshaling nor copying its data
+func UnmarshalPayloadHeader(payloadBytes []byte) (*cb.Header, error) {
+	header := &cb.Header{}
+	err := forEachBytesField(payloadBytes, func(fieldNum uint64, value []byte) error {
+		if fieldNum == payloadHeaderField {
+			return proto.UnmarshalMerge(value, header)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling Payload")
+	}
+	return header, nil
+}
+
+// The numbers of the fields of the Block, BlockData, Envelope and Payload
+// messages
+const (
+	blockHeaderField       = 1
+	blockDataField         = 2
+	blockMetadataField     = 3
+	blockDataDataField     = 1
+	envelopePayloadField   = 1
+	envelopeSignatureField = 2
+	payloadHeaderField     = 1
+)
+
+// forEachBytesField calls f with the number and the value of each
+// length-delimited field of the marshaled message, in order, skipping the
+// other fields. The values refer to the marshaled message
+func forEachBytesField(b []byte, f func(fieldNum uint64, value []byte) error) error {
+	for len(b) > 0 {
+		key, n := proto.DecodeVarint(b)
+		if n == 0 {
+			return errors.New("invalid field key")
+		}
+		b = b[n:]
+		switch key & 7 {
+		case proto.WireVarint:
+			if _, n = proto.DecodeVarint(b); n == 0 {
+				return errors.New("invalid varint field")
+			}
+			b = b[n:]
+		case proto.WireFixed64:
+			if len(b) < 8 {
+				return errors.New("invalid fixed64 field")
+			}
+			b = b[8:]
+		case proto.WireFixed32:
+			if len(b) < 4 {
+				return errors.New("invalid fixed32 field")
+			}
+			b = b[4:]
+		case proto.WireBytes:
+			length, n := proto.DecodeVarint(b)
+			if n == 0 || length > uint64(len(b)-n) {
+				return errors.New("invalid length-delimited field")
+			}
+			end := n + int(length)
+			// the capacity of the value is limited to its length, so that
+			// appending to it doesn't overwrite the next fields
+			value := b[n:end:end]
+			b = b[end:]
+			if err := f(key>>3, value); err != nil {
+				return err
+			}
+		default:
+			return errors.Errorf("unsupported wire type %d", key&7)
+		}
+	}
+	return nil
+}
+
 // CopyBlockMetadata copies metadata from one block into another
 func CopyBlockMetadata(src *cb.Block, dst *cb.Block) {
 	dst.Metadata = src.Metadata
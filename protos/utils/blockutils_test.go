@@ -120,6 +120,107 @@ func TestGetBlockFromBlockBytes(t *testing.T) {
 	assert.Error(t, err, "Expected error for malformed block bytes")
 }
 
+func TestGetBlockFromBlockBytesNoCopy(t *testing.T) {
+	gb, err := configtxtest.MakeGenesisBlock(testChainID)
+	assert.NoError(t, err, "Failed to create test configuration block")
+	for _, block := range []*cb.Block{gb, {}, {Data: &cb.BlockData{}}, newBenchmarkBlock(3, 10)} {
+		blockBytes, err := utils.Marshal(block)
+		assert.NoError(t, err, "Failed to marshal block")
+		expected, err := utils.GetBlockFromBlockBytes(blockBytes)
+		assert.NoError(t, err)
+		noCopy, err := utils.GetBlockFromBlockBytesNoCopy(blockBytes)
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(expected, noCopy))
+		assert.Equal(t, expected.Data, noCopy.Data)
+	}
+
+	// the data entries refer to the block bytes
+	blockBytes, err := utils.Marshal(newBenchmarkBlock(1, 10))
+	assert.NoError(t, err)
+	block, err := utils.GetBlockFromBlockBytesNoCopy(blockBytes)
+	assert.NoError(t, err)
+	block.Data.Data[0][0] = 42
+	copied, err := utils.GetBlockFromBlockBytes(blockBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, byte(42), copied.Data.Data[0][0])
+	assert.Equal(t, len(block.Data.Data[0]), cap(block.Data.Data[0]))
+
+	// bad block bytes
+	_, err = utils.GetBlockFromBlockBytesNoCopy([]byte("bad block"))
+	assert.EqualError(t, err, "error unmarshaling block: invalid length-delimited field")
+	_, err = utils.GetBlockFromBlockBytesNoCopy(blockBytes[:len(blockBytes)-1])
+	assert.Error(t, err, "Expected error for truncated block bytes")
+	_, err = utils.GetBlockFromBlockBytesNoCopy([]byte{0x0f})
+	assert.EqualError(t, err, "error unmarshaling block: unsupported wire type 7")
+}
+
+func TestUnmarshalEnvelopeNoCopy(t *testing.T) {
+	chdr := utils.MakeChannelHeader(cb.HeaderType_ENDORSER_TRANSACTION, 0, testChainID, 0)
+	chdr.TxId = "txid"
+	payload := &cb.Payload{
+		Header: utils.MakePayloadHeader(chdr, &cb.SignatureHeader{Creator: []byte("creator")}),
+		Data:   []byte("data"),
+	}
+	env := &cb.Envelope{Payload: utils.MarshalOrPanic(payload), Signature: []byte("signature")}
+	envBytes := utils.MarshalOrPanic(env)
+
+	noCopy, err := utils.UnmarshalEnvelopeNoCopy(envBytes)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(env, noCopy))
+	header, err := utils.UnmarshalPayloadHeader(noCopy.Payload)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(payload.Header, header))
+
+	_, err = utils.UnmarshalEnvelopeNoCopy([]byte("bad envelope"))
+	assert.EqualError(t, err, "error unmarshaling Envelope: invalid length-delimited field")
+	_, err = utils.UnmarshalPayloadHeader([]byte("bad payload"))
+	assert.EqualError(t, err, "error unmarshaling Payload: invalid length-delimited field")
+}
+
+// newBenchmarkBlock returns a block of the given number of transactions of
+// the given size
+func newBenchmarkBlock(txCount int, txSize int) *cb.Block {
+	block := common.NewBlock(1, []byte("previous hash"))
+	for i := 0; i < txCount; i++ {
+		tx := make([]byte, txSize)
+		tx[0] = byte(i)
+		block.Data.Data = append(block.Data.Data, tx)
+	}
+	block.Header.DataHash = block.Data.Hash()
+	block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = []byte("signatures")
+	return block
+}
+
+// a block of 50MB
+const (
+	benchmarkTxCount = 500
+	benchmarkTxSize  = 100 * 1024
+)
+
+func BenchmarkGetBlockFromBlockBytes(b *testing.B) {
+	blockBytes, err := utils.Marshal(newBenchmarkBlock(benchmarkTxCount, benchmarkTxSize))
+	assert.NoError(b, err)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := utils.GetBlockFromBlockBytes(blockBytes); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetBlockFromBlockBytesNoCopy(b *testing.B) {
+	blockBytes, err := utils.Marshal(newBenchmarkBlock(benchmarkTxCount, benchmarkTxSize))
+	assert.NoError(b, err)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := utils.GetBlockFromBlockBytesNoCopy(blockBytes); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func TestGetMetadataFromNewBlock(t *testing.T) {
 	block := common.NewBlock(0, nil)
 	md, err := utils.GetMetadataFromBlock(block, cb.BlockMetadataIndex_ORDERER)
@@ -204,3 +305,43 @@ func TestGetLastConfigIndexFromBlock(t *testing.T) {
 		_ = utils.GetLastConfigIndexFromBlockOrPanic(block)
 	}, "Expected panic with malformed last config metadata")
 }
+
+func TestGetCheckpointFromBlock(t *testing.T) {
+	block := common.NewBlock(0, nil)
+	cp, err := utils.GetCheckpointFromBlock(block)
+	assert.NoError(t, err, "Unexpected error with no checkpoint")
+	assert.Nil(t, cp, "Expected no checkpoint in new block")
+
+	// block written before checkpoints were introduced
+	block.Metadata.Metadata = block.Metadata.Metadata[:cb.BlockMetadataIndex_CHECKPOINT]
+	cp, err = utils.GetCheckpointFromBlock(block)
+	assert.NoError(t, err, "Unexpected error with short metadata")
+	assert.Nil(t, cp, "Expected no checkpoint with short metadata")
+
+	block = common.NewBlock(10, nil)
+	checkpoint := &cb.Checkpoint{
+		LastConfig:             3,
+		PreviousCheckpoint:     5,
+		PreviousCheckpointHash: []byte("hash"),
+	}
+	metadata, _ := proto.Marshal(&cb.Metadata{
+		Value: utils.MarshalOrPanic(checkpoint),
+	})
+	block.Metadata.Metadata[cb.BlockMetadataIndex_CHECKPOINT] = metadata
+	cp, err = utils.GetCheckpointFromBlock(block)
+	assert.NoError(t, err, "Unexpected error returning checkpoint")
+	assert.True(t, proto.Equal(checkpoint, cp), "Unexpected checkpoint returned from block")
+
+	// malformed metadata
+	block.Metadata.Metadata[cb.BlockMetadataIndex_CHECKPOINT] = []byte("bad metadata")
+	_, err = utils.GetCheckpointFromBlock(block)
+	assert.Error(t, err, "Expected error with malformed metadata")
+
+	// malformed checkpoint
+	metadata, _ = proto.Marshal(&cb.Metadata{
+		Value: []byte("bad checkpoint"),
+	})
+	block.Metadata.Metadata[cb.BlockMetadataIndex_CHECKPOINT] = metadata
+	_, err = utils.GetCheckpointFromBlock(block)
+	assert.Error(t, err, "Expected error with malformed checkpoint metadata")
+}
@@ -335,6 +335,35 @@ func CreateChaincodeProposalWithTxIDNonceAndTransient(txid string, typ common.He
 	return prop, txid, nil
 }
 
+// SetProposalDedupKey sets the dedup key of the chaincode header extension of
+// the given proposal, the transaction of the proposal is then invalidated when
+// a transaction of the same chaincode with the same dedup key was already
+// committed within the dedup window of the channel
+func SetProposalDedupKey(prop *peer.Proposal, dedupKey string) error {
+	hdr, err := GetHeader(prop.Header)
+	if err != nil {
+		return err
+	}
+	chdr, err := UnmarshalChannelHeader(hdr.ChannelHeader)
+	if err != nil {
+		return err
+	}
+	ccHdrExt, err := GetChaincodeHeaderExtension(hdr)
+	if err != nil {
+		return err
+	}
+
+	ccHdrExt.DedupKey = dedupKey
+	if chdr.Extension, err = proto.Marshal(ccHdrExt); err != nil {
+		return errors.Wrap(err, "error marshaling ChaincodeHeaderExtension")
+	}
+	if hdr.ChannelHeader, err = proto.Marshal(chdr); err != nil {
+		return errors.Wrap(err, "error marshaling ChannelHeader")
+	}
+	prop.Header, err = proto.Marshal(hdr)
+	return errors.Wrap(err, "error marshaling Header")
+}
+
 // GetBytesProposalResponsePayload gets proposal response payload
 func GetBytesProposalResponsePayload(hash []byte, response *peer.Response, result []byte, event []byte, ccid *peer.ChaincodeID) ([]byte, error) {
 	cAct := &peer.ChaincodeAction{
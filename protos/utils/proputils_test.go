@@ -359,6 +359,27 @@ func TestProposal(t *testing.T) {
 	}
 }
 
+func TestSetProposalDedupKey(t *testing.T) {
+	prop, txid, err := utils.CreateChaincodeProposal(common.HeaderType_ENDORSER_TRANSACTION, util.GetTestChainID(), createCIS(), []byte("creator"))
+	assert.NoError(t, err)
+
+	err = utils.SetProposalDedupKey(prop, "order-42")
+	assert.NoError(t, err)
+
+	hdr, err := utils.GetHeader(prop.Header)
+	assert.NoError(t, err)
+	chdr, err := utils.UnmarshalChannelHeader(hdr.ChannelHeader)
+	assert.NoError(t, err)
+	assert.Equal(t, txid, chdr.TxId)
+	ccHdrExt, err := utils.GetChaincodeHeaderExtension(hdr)
+	assert.NoError(t, err)
+	assert.Equal(t, "order-42", ccHdrExt.DedupKey)
+	assert.Equal(t, createCIS().ChaincodeSpec.ChaincodeId, ccHdrExt.ChaincodeId)
+
+	err = utils.SetProposalDedupKey(&pb.Proposal{Header: []byte("garbage")}, "order-42")
+	assert.Error(t, err)
+}
+
 func TestProposalWithTxID(t *testing.T) {
 	// create a proposal from a ChaincodeInvocationSpec
 	prop, txid, err := utils.CreateChaincodeProposalWithTxIDAndTransient(
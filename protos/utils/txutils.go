@@ -71,9 +71,22 @@ func CreateSignedEnvelope(txType common.HeaderType, channelID string, signer cry
 // type, with marshaled dataMsg and signs it. It also includes a TLS cert hash
 // into the channel header
 func CreateSignedEnvelopeWithTLSBinding(txType common.HeaderType, channelID string, signer crypto.LocalSigner, dataMsg proto.Message, msgVersion int32, epoch uint64, tlsCertHash []byte) (*common.Envelope, error) {
+	return CreateSignedEnvelopeWithExtension(txType, channelID, signer, dataMsg, msgVersion, epoch, tlsCertHash, nil)
+}
+
+// CreateSignedEnvelopeWithExtension creates a signed envelope of the desired
+// type, with marshaled dataMsg and signs it. It also includes a TLS cert hash
+// and, if not nil, the marshaled extension into the channel header
+func CreateSignedEnvelopeWithExtension(txType common.HeaderType, channelID string, signer crypto.LocalSigner, dataMsg proto.Message, msgVersion int32, epoch uint64, tlsCertHash []byte, extension proto.Message) (*common.Envelope, error) {
 	payloadChannelHeader := MakeChannelHeader(txType, msgVersion, channelID, epoch)
 	payloadChannelHeader.TlsCertHash = tlsCertHash
 	var err error
+	if extension != nil {
+		payloadChannelHeader.Extension, err = proto.Marshal(extension)
+		if err != nil {
+			return nil, errors.Wrap(err, "error marshaling extension")
+		}
+	}
 	payloadSignatureHeader := &common.SignatureHeader{}
 
 	if signer != nil {
@@ -148,6 +161,52 @@ func CreateSignedTx(proposal *peer.Proposal, signer msp.SigningIdentity, resps .
 		return nil, errors.New("signer must be the same as the one referenced in the header")
 	}
 
+	paylBytes, err := createTxPayload(hdr, pPayl, resps)
+	if err != nil {
+		return nil, err
+	}
+
+	// sign the payload
+	sig, err := signer.Sign(paylBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// here's the envelope
+	return &common.Envelope{Payload: paylBytes, Signature: sig}, nil
+}
+
+// CreateUnsignedTx assembles an Envelope message from proposal and endorsements,
+// leaving it to the creator of the proposal to sign its payload. This function
+// is meant for components collecting endorsements on behalf of a client
+func CreateUnsignedTx(proposal *peer.Proposal, resps ...*peer.ProposalResponse) (*common.Envelope, error) {
+	if len(resps) == 0 {
+		return nil, errors.New("at least one proposal response is required")
+	}
+
+	// the original header
+	hdr, err := GetHeader(proposal.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	// the original payload
+	pPayl, err := GetChaincodeProposalPayload(proposal.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	paylBytes, err := createTxPayload(hdr, pPayl, resps)
+	if err != nil {
+		return nil, err
+	}
+
+	return &common.Envelope{Payload: paylBytes}, nil
+}
+
+// createTxPayload returns the marshaled transaction payload
+// of the given proposal header, payload and endorsements
+func createTxPayload(hdr *common.Header, pPayl *peer.ChaincodeProposalPayload, resps []*peer.ProposalResponse) ([]byte, error) {
 	// get header extensions so we have the visibility field
 	hdrExt, err := GetChaincodeHeaderExtension(hdr)
 	if err != nil {
@@ -206,19 +265,7 @@ func CreateSignedTx(proposal *peer.Proposal, signer msp.SigningIdentity, resps .
 
 	// create the payload
 	payl := &common.Payload{Header: hdr, Data: txBytes}
-	paylBytes, err := GetBytesPayload(payl)
-	if err != nil {
-		return nil, err
-	}
-
-	// sign the payload
-	sig, err := signer.Sign(paylBytes)
-	if err != nil {
-		return nil, err
-	}
-
-	// here's the envelope
-	return &common.Envelope{Payload: paylBytes, Signature: sig}, nil
+	return GetBytesPayload(payl)
 }
 
 // CreateProposalResponse creates a proposal response.
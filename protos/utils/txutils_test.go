@@ -223,6 +223,54 @@ func TestCreateSignedTx(t *testing.T) {
 
 }
 
+func TestCreateUnsignedTx(t *testing.T) {
+	signID, err := mockmsp.NewNoopMsp().GetDefaultSigningIdentity()
+	assert.NoError(t, err, "Unexpected error getting signing identity")
+	signerBytes, err := signID.Serialize()
+	assert.NoError(t, err, "Unexpected error serializing signing identity")
+
+	ccHeaderExtensionBytes, _ := proto.Marshal(&pb.ChaincodeHeaderExtension{})
+	chdrBytes, _ := proto.Marshal(&cb.ChannelHeader{
+		Extension: ccHeaderExtensionBytes,
+	})
+	shdrBytes, _ := proto.Marshal(&cb.SignatureHeader{
+		Creator: signerBytes,
+	})
+	headerBytes, _ := proto.Marshal(&cb.Header{
+		ChannelHeader:   chdrBytes,
+		SignatureHeader: shdrBytes,
+	})
+	prop := &pb.Proposal{Header: headerBytes}
+	responses := []*pb.ProposalResponse{{
+		Payload:     []byte("payload"),
+		Endorsement: &pb.Endorsement{Endorser: []byte("endorser")},
+		Response: &pb.Response{
+			Status: int32(200),
+		},
+	}}
+
+	env, err := utils.CreateUnsignedTx(prop, responses...)
+	assert.NoError(t, err, "Unexpected error creating unsigned transaction")
+	assert.Empty(t, env.Signature, "Signature should have been empty")
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	assert.NoError(t, err, "Failed to unmarshal payload")
+	tx, err := utils.GetTransaction(payload.Data)
+	assert.NoError(t, err, "Expected payload data to be a transaction")
+	assert.Equal(t, shdrBytes, tx.Actions[0].Header)
+
+	// the transaction is the one a client would have signed
+	signedEnv, err := utils.CreateSignedTx(prop, signID, responses...)
+	assert.NoError(t, err, "Unexpected error creating signed transaction")
+	assert.Equal(t, signedEnv.Payload, env.Payload)
+
+	_, err = utils.CreateUnsignedTx(prop)
+	assert.Error(t, err, "Expected error with no proposal responses")
+
+	prop.Header = []byte("bad header")
+	_, err = utils.CreateUnsignedTx(prop, responses...)
+	assert.Error(t, err, "Expected error with malformed proposal header")
+}
+
 func TestCreateSignedTxStatus(t *testing.T) {
 	serializedExtension, err := proto.Marshal(&pb.ChaincodeHeaderExtension{})
 	assert.NoError(t, err)
@@ -327,6 +375,33 @@ func TestCreateSignedEnvelopeNilSigner(t *testing.T) {
 	assert.Equal(t, msg, data, "Payload data does not match expected value")
 }
 
+func TestCreateSignedEnvelopeWithExtension(t *testing.T) {
+	channelID := "mychannelID"
+	filter := &pb.ChaincodeEventFilter{ChaincodeId: "mycc", EventName: "event.*"}
+
+	env, err := utils.CreateSignedEnvelopeWithExtension(cb.HeaderType_DELIVER_SEEK_INFO, channelID,
+		goodSigner, &cb.ConfigEnvelope{}, int32(1), uint64(1), []byte("hash"), filter)
+	assert.NoError(t, err, "Unexpected error creating signed envelope")
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	assert.NoError(t, err, "Failed to unmarshal payload")
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	assert.NoError(t, err, "Failed to unmarshal channel header")
+	assert.Equal(t, []byte("hash"), chdr.TlsCertHash)
+	extension := &pb.ChaincodeEventFilter{}
+	err = proto.Unmarshal(chdr.Extension, extension)
+	assert.NoError(t, err, "Expected extension to be a chaincode event filter")
+	assert.True(t, proto.Equal(filter, extension), "Extension does not match expected value")
+
+	env, err = utils.CreateSignedEnvelopeWithExtension(cb.HeaderType_DELIVER_SEEK_INFO, channelID,
+		goodSigner, &cb.ConfigEnvelope{}, int32(1), uint64(1), nil, nil)
+	assert.NoError(t, err, "Unexpected error creating signed envelope")
+	payload, err = utils.UnmarshalPayload(env.Payload)
+	assert.NoError(t, err, "Failed to unmarshal payload")
+	chdr, err = utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	assert.NoError(t, err, "Failed to unmarshal channel header")
+	assert.Empty(t, chdr.Extension, "Extension should have been empty")
+}
+
 func TestGetSignedProposal(t *testing.T) {
 	var signedProp *pb.SignedProposal
 	var err error
@@ -21,7 +21,7 @@ cat docs/wrappers/license_postscript.md >> $DOC
 DOC=docs/source/commands/peerchaincode.md
 cat docs/wrappers/peer_chaincode_preamble.md > $DOC
 
-for x in "peer chaincode install" "peer chaincode instantiate" "peer chaincode invoke" "peer chaincode list" "peer chaincode package" "peer chaincode query" "peer chaincode signpackage" "peer chaincode upgrade"; do
+for x in "peer chaincode install" "peer chaincode instantiate" "peer chaincode invoke" "peer chaincode list" "peer chaincode package" "peer chaincode query" "peer chaincode signpackage" "peer chaincode status" "peer chaincode upgrade"; do
   echo "" >> $DOC
   echo "##" $x >> $DOC
   echo "\`\`\`" >> $DOC
@@ -34,7 +34,7 @@ cat docs/wrappers/peer_chaincode_postscript.md >> $DOC
 DOC=docs/source/commands/peerchannel.md
 cat docs/wrappers/peer_channel_preamble.md > $DOC
 
-for x in "peer channel" "peer channel create" "peer channel fetch" "peer channel getinfo" "peer channel join" "peer channel list" "peer channel signconfigtx" "peer channel update"; do
+for x in "peer channel" "peer channel create" "peer channel fetch" "peer channel fetchconfig" "peer channel getinfo" "peer channel join" "peer channel list" "peer channel signconfigtx" "peer channel update"; do
   echo "" >> $DOC
   echo "##" $x >> $DOC
   echo "\`\`\`" >> $DOC
@@ -60,7 +60,7 @@ cat docs/wrappers/peer_logging_postscript.md >> $DOC
 DOC=docs/source/commands/peernode.md
 cat docs/wrappers/peer_node_preamble.md > $DOC
 
-for x in "peer node start" "peer node status"; do
+for x in "peer node start" "peer node status" "peer node preflight" "peer node reload" "peer node index list" "peer node index create" "peer node index rebuild" "peer node snapshot list" "peer node certificates"; do
   echo "" >> $DOC
   echo "##" $x >> $DOC
   echo "\`\`\`" >> $DOC
@@ -90,7 +90,7 @@ cat docs/wrappers/cryptogen_preamble.md > $DOC
 
 echo "" >> $DOC
 
-for x in "cryptogen help" "cryptogen generate" "cryptogen showtemplate" "cryptogen extend" "cryptogen version"; do
+for x in "cryptogen help" "cryptogen generate" "cryptogen showtemplate" "cryptogen extend" "cryptogen renew" "cryptogen version"; do
   echo "" >> $DOC
   echo "##" $x >> $DOC
   echo "\`\`\`" >> $DOC
@@ -104,7 +104,7 @@ DOC=docs/source/commands/configtxlator.md
 
 cat docs/wrappers/configtxlator_preamble.md > $DOC
 
-for x in "configtxlator start" "configtxlator proto_encode" "configtxlator proto_decode" "configtxlator compute_update" "configtxlator version"; do
+for x in "configtxlator start" "configtxlator proto_encode" "configtxlator proto_decode" "configtxlator compute_update" "configtxlator explain_policy" "configtxlator version"; do
   echo "" >> $DOC
   echo "##" $x >> $DOC
   echo "\`\`\`" >> $DOC